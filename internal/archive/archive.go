@@ -0,0 +1,178 @@
+// Package archive moves a town's state between hosts. An export is a tar
+// of everything gt keeps in the town (configs, beads databases, merge
+// queue state, mail) without the rigs' repositories, which are cloned
+// again on import. It opens with a manifest recording the versions that
+// wrote it, so a gt too old to read the state refuses to restore it.
+//
+// Archives are compressed by file extension: .tar.zst (with the zstd
+// command), .tar.gz or .tgz, or plain .tar.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// FormatVersion is the archive layout this gt writes and the newest it reads.
+const FormatVersion = 1
+
+// ManifestName is the archive's first entry.
+const ManifestName = "gastown-export.json"
+
+// ErrIncompatible means the archive holds state this gt can't read.
+var ErrIncompatible = errors.New("archive is not compatible with this gt")
+
+// Manifest describes an export.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	GTVersion     string    `json:"gt_version"`
+	TownVersion   int       `json:"town_version"` // mayor/town.json schema
+	RigsVersion   int       `json:"rigs_version"` // mayor/rigs.json schema
+	TownName      string    `json:"town_name"`
+	CreatedAt     time.Time `json:"created_at"`
+	Rigs          []Rig     `json:"rigs"`
+	Files         int       `json:"files"`
+	Bytes         int64     `json:"bytes"`
+}
+
+// Rig is a rig in an export, with what import has to re-create.
+type Rig struct {
+	Name   string `json:"name"`
+	GitURL string `json:"git_url"`
+
+	// Crew lists the rig's crew workspaces, which are clones and so left
+	// out; gt crew add makes them again.
+	Crew []string `json:"crew,omitempty"`
+}
+
+// Check returns ErrIncompatible if the archive is in a newer format than
+// this gt reads, or holds town config from a newer gt. A different gt
+// version alone is fine.
+func (m *Manifest) Check() error {
+	if m.FormatVersion > FormatVersion {
+		return fmt.Errorf("%w: archive format %d, this gt reads up to %d", ErrIncompatible, m.FormatVersion, FormatVersion)
+	}
+	if m.TownVersion > config.CurrentTownVersion {
+		return fmt.Errorf("%w: town config version %d, this gt reads up to %d (exported by gt %s)",
+			ErrIncompatible, m.TownVersion, config.CurrentTownVersion, m.GTVersion)
+	}
+	if m.RigsVersion > config.CurrentRigsVersion {
+		return fmt.Errorf("%w: rig registry version %d, this gt reads up to %d (exported by gt %s)",
+			ErrIncompatible, m.RigsVersion, config.CurrentRigsVersion, m.GTVersion)
+	}
+	return nil
+}
+
+// Compression formats.
+const (
+	compressNone = "tar"
+	compressGzip = "gzip"
+	compressZstd = "zstd"
+)
+
+// compressionFor picks the compression for an archive path by extension.
+func compressionFor(path string) (string, error) {
+	switch lower := strings.ToLower(path); {
+	case strings.HasSuffix(lower, ".tar.zst"), strings.HasSuffix(lower, ".tzst"):
+		return compressZstd, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return compressGzip, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return compressNone, nil
+	default:
+		return "", fmt.Errorf("unknown archive type %q: use .tar.zst, .tar.gz or .tar", path)
+	}
+}
+
+// sniffCompression identifies an archive's compression by its magic bytes,
+// so a renamed archive still imports.
+func sniffCompression(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return compressZstd
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return compressGzip
+	default:
+		return compressNone
+	}
+}
+
+// compress wraps w in the given compression. Closing the writer flushes
+// it; w itself is left open.
+func compress(format string, w io.Writer) (io.WriteCloser, error) {
+	switch format {
+	case compressGzip:
+		return gzip.NewWriter(w), nil
+	case compressZstd:
+		cmd := exec.Command("zstd", "-q", "-c")
+		cmd.Stdout = w
+		return startZstd(cmd, func() (io.Closer, error) { return cmd.StdinPipe() })
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// decompress wraps r in the given decompression.
+func decompress(format string, r io.Reader) (io.ReadCloser, error) {
+	switch format {
+	case compressGzip:
+		return gzip.NewReader(r)
+	case compressZstd:
+		cmd := exec.Command("zstd", "-d", "-q", "-c")
+		cmd.Stdin = r
+		return startZstd(cmd, func() (io.Closer, error) { return cmd.StdoutPipe() })
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// startZstd starts a zstd command and returns its pipe, whose Close also
+// waits for zstd to finish.
+func startZstd(cmd *exec.Cmd, pipe func() (io.Closer, error)) (*zstdPipe, error) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return nil, fmt.Errorf("zstd not found: install it, or use a .tar.gz archive")
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	p, err := pipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting zstd: %w", err)
+	}
+	return &zstdPipe{Closer: p, cmd: cmd, stderr: &stderr}, nil
+}
+
+// zstdPipe is the write end of zstd's stdin or the read end of its stdout.
+type zstdPipe struct {
+	io.Closer
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func (p *zstdPipe) Write(b []byte) (int, error) { return p.Closer.(io.Writer).Write(b) }
+func (p *zstdPipe) Read(b []byte) (int, error)  { return p.Closer.(io.Reader).Read(b) }
+
+func (p *zstdPipe) Close() error {
+	closeErr := p.Closer.Close()
+	if err := p.cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(p.stderr.String()); msg != "" {
+			return fmt.Errorf("zstd: %s", msg)
+		}
+		return fmt.Errorf("zstd: %w", err)
+	}
+	return closeErr
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,299 @@
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// makeTown lays out a small town with one rig, its clones and a crew
+// workspace.
+func makeTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := config.SaveTownConfig(filepath.Join(townRoot, "mayor", "town.json"), &config.TownConfig{
+		Type: "town", Version: config.CurrentTownVersion, Name: "hq", CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs:    map[string]config.RigEntry{"greenplace": {GitURL: "https://example.com/gp.git"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		".beads/beads.db":                          "town beads",
+		".git/HEAD":                                "town repo",
+		"daemon/daemon.pid":                        "123",
+		"greenplace/config.json":                   `{"git_url": "https://example.com/gp.git"}`,
+		"greenplace/.beads/redirect":               "mayor/rig/.beads",
+		"greenplace/.runtime/check-history.json":   "{}",
+		"greenplace/.repo.git/HEAD":                "bare",
+		"greenplace/mayor/rig/.git/HEAD":           "clone",
+		"greenplace/mayor/rig/main.go":             "package main",
+		"greenplace/mayor/rig/.beads/beads.db":     "rig beads",
+		"greenplace/refinery/rig/.git":             "gitdir: ../../.repo.git/worktrees/rig",
+		"greenplace/refinery/rig/main.go":          "package main",
+		"greenplace/refinery/rig/.beads/redirect":  "../../.beads",
+		"greenplace/crew/README.md":                "crew",
+		"greenplace/crew/max/.git/HEAD":            "clone",
+		"greenplace/crew/max/notes.txt":            "work in progress",
+		"greenplace/polecats/nux/greenplace/.git":  "gitdir: elsewhere",
+		"greenplace/polecats/nux/greenplace/a.txt": "a",
+	}
+	for rel, content := range files {
+		path := filepath.Join(townRoot, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func TestExportImport(t *testing.T) {
+	exts := []string{".tar", ".tar.gz"}
+	if _, err := exec.LookPath("zstd"); err == nil {
+		exts = append(exts, ".tar.zst")
+	}
+	for _, ext := range exts {
+		t.Run(ext, func(t *testing.T) {
+			townRoot := makeTown(t)
+			// Written inside the town, so it must leave itself out
+			out := filepath.Join(townRoot, "backup"+ext)
+			m, err := Export(townRoot, out, "1.2.3")
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			if m.TownName != "hq" || m.GTVersion != "1.2.3" || len(m.Rigs) != 1 {
+				t.Fatalf("manifest = %+v", m)
+			}
+			if crew := m.Rigs[0].Crew; len(crew) != 1 || crew[0] != "max" {
+				t.Errorf("crew = %v, want [max]", crew)
+			}
+
+			read, err := ReadManifest(out)
+			if err != nil || read.Files != m.Files {
+				t.Fatalf("ReadManifest = %+v, %v", read, err)
+			}
+
+			dest := filepath.Join(t.TempDir(), "town")
+			if _, err := Import(out, dest); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			for _, rel := range []string{
+				"mayor/town.json",
+				".beads/beads.db",
+				"greenplace/.beads/redirect",
+				"greenplace/.runtime/check-history.json",
+				"greenplace/mayor/rig/.beads/beads.db",
+				"greenplace/crew/README.md",
+				"greenplace/polecats",
+			} {
+				if _, err := os.Stat(filepath.Join(dest, rel)); err != nil {
+					t.Errorf("%s not restored: %v", rel, err)
+				}
+			}
+			for _, rel := range []string{
+				".git",
+				"daemon/daemon.pid",
+				"greenplace/.repo.git",
+				"greenplace/mayor/rig/.git",
+				"greenplace/mayor/rig/main.go",
+				"greenplace/refinery/rig",
+				"greenplace/crew/max",
+				"greenplace/polecats/nux",
+				"backup" + ext,
+			} {
+				if _, err := os.Stat(filepath.Join(dest, rel)); err == nil {
+					t.Errorf("%s should not be in the export", rel)
+				}
+			}
+		})
+	}
+}
+
+func TestManifestCheck(t *testing.T) {
+	ok := Manifest{FormatVersion: FormatVersion, TownVersion: config.CurrentTownVersion, RigsVersion: config.CurrentRigsVersion}
+	if err := ok.Check(); err != nil {
+		t.Errorf("current versions: %v", err)
+	}
+	older := Manifest{FormatVersion: FormatVersion, TownVersion: 1, RigsVersion: 1}
+	if err := older.Check(); err != nil {
+		t.Errorf("older versions: %v", err)
+	}
+	for _, m := range []Manifest{
+		{FormatVersion: FormatVersion + 1},
+		{FormatVersion: FormatVersion, TownVersion: config.CurrentTownVersion + 1},
+		{FormatVersion: FormatVersion, RigsVersion: config.CurrentRigsVersion + 1},
+	} {
+		if err := m.Check(); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("%+v: err = %v, want ErrIncompatible", m, err)
+		}
+	}
+}
+
+func TestImportRefusesIncompatible(t *testing.T) {
+	newer := filepath.Join(t.TempDir(), "newer.tar")
+	writeTar(t, newer, map[string]string{
+		ManifestName:      `{"format_version": 99, "gt_version": "9.0.0"}`,
+		"mayor/town.json": "{}",
+	})
+
+	dest := filepath.Join(t.TempDir(), "town")
+	if _, err := Import(newer, dest); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("err = %v, want ErrIncompatible", err)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		t.Error("nothing should be written for an incompatible archive")
+	}
+}
+
+func TestImportRejectsEscapingEntries(t *testing.T) {
+	evil := filepath.Join(t.TempDir(), "evil.tar")
+	writeTar(t, evil, map[string]string{
+		ManifestName:     `{"format_version": 1}`,
+		"../escaped.txt": "x",
+	})
+	if _, err := Import(evil, filepath.Join(t.TempDir(), "town")); err == nil {
+		t.Error("entry outside the town should be refused")
+	}
+}
+
+// writeEntries writes a tar of the manifest then hdrs in order, with
+// content for the regular files.
+func writeEntries(t *testing.T, path string, hdrs ...*tar.Header) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	manifest := `{"format_version": 1}`
+	hdrs = append([]*tar.Header{{Name: ManifestName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(manifest))}}, hdrs...)
+	for _, hdr := range hdrs {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			content := "x"
+			if hdr.Name == ManifestName {
+				content = manifest
+			}
+			if _, err := tw.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportRejectsEscapingSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	tests := []struct {
+		name string
+		hdrs []*tar.Header
+	}{
+		{"absolute link", []*tar.Header{
+			{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside},
+		}},
+		{"relative link out", []*tar.Header{
+			{Name: "mayor/link", Typeflag: tar.TypeSymlink, Linkname: "../../escaped"},
+		}},
+		{"write through a link", []*tar.Header{
+			{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "mayor"},
+			{Name: "mayor/", Typeflag: tar.TypeDir, Mode: 0755},
+			{Name: "link/owned.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evil := filepath.Join(t.TempDir(), "evil.tar")
+			writeEntries(t, evil, tt.hdrs...)
+			if _, err := Import(evil, filepath.Join(t.TempDir(), "town")); err == nil {
+				t.Error("escaping symlink should be refused")
+			}
+		})
+	}
+
+	// An in-town relative link is kept
+	ok := filepath.Join(t.TempDir(), "ok.tar")
+	writeEntries(t, ok,
+		&tar.Header{Name: "mayor/rig/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "mayor/current", Typeflag: tar.TypeSymlink, Linkname: "rig"})
+	dest := filepath.Join(t.TempDir(), "town")
+	if _, err := Import(ok, dest); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(dest, "mayor", "current")); err != nil || link != "rig" {
+		t.Errorf("mayor/current -> %q, %v", link, err)
+	}
+}
+
+func TestImportRejectsNonExport(t *testing.T) {
+	plain := filepath.Join(t.TempDir(), "plain.tar")
+	writeTar(t, plain, map[string]string{"README.md": "hi"})
+	if _, err := ReadManifest(plain); err == nil {
+		t.Error("tar without a manifest should be refused")
+	}
+}
+
+func TestCompressionFor(t *testing.T) {
+	for path, want := range map[string]string{
+		"town.tar.zst": compressZstd,
+		"town.TGZ":     compressGzip,
+		"town.tar.gz":  compressGzip,
+		"town.tar":     compressNone,
+	} {
+		if got, err := compressionFor(path); err != nil || got != want {
+			t.Errorf("compressionFor(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := compressionFor("town.zip"); err == nil {
+		t.Error("unknown extension should be an error")
+	}
+}
+
+// writeTar writes an uncompressed tar with the manifest entry first.
+func writeTar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	names := make([]string, 0, len(files))
+	if _, ok := files[ManifestName]; ok {
+		names = append(names, ManifestName)
+	}
+	for name := range files {
+		if name != ManifestName {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,294 @@
+package archive
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// entry is a file, directory or symlink going into an export.
+type entry struct {
+	path string // Absolute
+	rel  string // Slash-separated, relative to the town root
+	info fs.FileInfo
+	link string
+}
+
+// Export writes the state of the town at townRoot to an archive at out,
+// compressed by out's extension, and returns its manifest.
+//
+// Everything under the town root goes in except git repositories: .git
+// and .repo.git directories are skipped, as is every checkout inside the
+// town (mayor and refinery clones, crew workspaces, polecat worktrees),
+// apart from a checkout's .beads directory when it holds a database
+// rather than a redirect. Pid, lock and socket files are skipped too.
+func Export(townRoot, out, gtVersion string) (*Manifest, error) {
+	format, err := compressionFor(out)
+	if err != nil {
+		return nil, err
+	}
+	townConfig, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading town config: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	m := &Manifest{
+		FormatVersion: FormatVersion,
+		GTVersion:     gtVersion,
+		TownVersion:   townConfig.Version,
+		RigsVersion:   rigsConfig.Version,
+		TownName:      townConfig.Name,
+		CreatedAt:     time.Now().UTC(),
+	}
+	rigs := make(map[string]*Rig, len(rigsConfig.Rigs))
+	for name, r := range rigsConfig.Rigs {
+		m.Rigs = append(m.Rigs, Rig{Name: name, GitURL: r.GitURL})
+	}
+	sort.Slice(m.Rigs, func(i, j int) bool { return m.Rigs[i].Name < m.Rigs[j].Name })
+	for i := range m.Rigs {
+		rigs[m.Rigs[i].Name] = &m.Rigs[i]
+	}
+
+	outAbs, err := filepath.Abs(out)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := collect(townRoot, rigs, outAbs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.info.Mode().IsRegular() {
+			m.Files++
+			m.Bytes += e.info.Size()
+		}
+	}
+
+	tmp := out + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: path is from the user
+	if err != nil {
+		return nil, fmt.Errorf("creating archive: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp) }()
+	if err := write(f, format, m, entries); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		return nil, fmt.Errorf("writing archive: %w", err)
+	}
+	return m, nil
+}
+
+// write writes the manifest and entries to f as a compressed tar.
+func write(f io.Writer, format string, m *Manifest, entries []entry) error {
+	cw, err := compress(format, f)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(cw)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    ManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: m.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	for _, e := range entries {
+		if err := writeEntry(tw, e); err != nil {
+			return fmt.Errorf("archiving %s: %w", e.rel, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if err := cw.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, e entry) error {
+	hdr, err := tar.FileInfoHeader(e.info, e.link)
+	if err != nil {
+		return err
+	}
+	// Ownership means nothing on the host it's restored to
+	hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+	hdr.Name = e.rel
+	if e.info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !e.info.Mode().IsRegular() {
+		return nil
+	}
+	src, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	// Copy only the size in the header, in case the file grew meanwhile
+	_, err = io.CopyN(tw, src, hdr.Size)
+	return err
+}
+
+// collect lists what goes into an export of townRoot, recording each
+// rig's crew workspaces as it skips them. skip is the archive being
+// written, if it's inside the town.
+func collect(townRoot string, rigs map[string]*Rig, skip string) ([]entry, error) {
+	root, err := filepath.Abs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	emptied := make(map[string]bool) // Directories a skipped checkout was in
+	var visit fs.WalkDirFunc
+	visit = func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		name := d.Name()
+
+		if d.IsDir() {
+			if name == ".git" || name == ".repo.git" {
+				return filepath.SkipDir
+			}
+			if _, isRig := rigs[rel]; !isRig && isCheckout(path) {
+				if parent := strings.Split(rel, "/"); len(parent) == 3 && parent[1] == "crew" {
+					if r, ok := rigs[parent[0]]; ok {
+						r.Crew = append(r.Crew, parent[2])
+					}
+				}
+				for dir := pathpkg.Dir(rel); strings.Count(dir, "/") >= 2; dir = pathpkg.Dir(dir) {
+					emptied[dir] = true
+				}
+				if err := keepCheckoutBeads(path, visit, &entries, root); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+		} else if path == skip || path == skip+".tmp" || isRuntimeFile(name) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		e := entry{path: path, rel: rel, info: info}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if e.link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			return nil // Sockets, pipes, devices
+		}
+		entries = append(entries, e)
+		return nil
+	}
+	if err := filepath.WalkDir(root, visit); err != nil {
+		return nil, fmt.Errorf("reading town: %w", err)
+	}
+	return pruneEmptied(entries, emptied), nil
+}
+
+// pruneEmptied drops the directories that held nothing but skipped
+// checkouts (a polecat's directory around its worktree), so a restored
+// town doesn't show workers that no longer have a workspace.
+func pruneEmptied(entries []entry, emptied map[string]bool) []entry {
+	for _, e := range entries {
+		for dir := pathpkg.Dir(e.rel); dir != "."; dir = pathpkg.Dir(dir) {
+			delete(emptied, dir)
+		}
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if !emptied[e.rel] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// keepCheckoutBeads adds a checkout's .beads directory to the export when
+// it holds a database (the mayor clone's, for a rig whose beads are
+// tracked in its repo). A .beads that only redirects elsewhere is made
+// again with the checkout.
+func keepCheckoutBeads(checkout string, visit fs.WalkDirFunc, entries *[]entry, root string) error {
+	beadsDir := filepath.Join(checkout, ".beads")
+	if _, err := os.Stat(beadsDir); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "redirect")); err == nil {
+		return nil
+	}
+	// The checkout's own directory goes in so .beads restores inside it
+	info, err := os.Lstat(checkout)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(root, checkout)
+	if err != nil {
+		return err
+	}
+	*entries = append(*entries, entry{path: checkout, rel: filepath.ToSlash(rel), info: info})
+	return filepath.WalkDir(beadsDir, visit)
+}
+
+// isCheckout reports whether dir is a git checkout (a clone, or a
+// worktree whose .git is a file).
+func isCheckout(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// isRuntimeFile reports files that only mean something to processes
+// running on this host.
+func isRuntimeFile(name string) bool {
+	for _, suffix := range []string{".pid", ".lock", ".sock"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
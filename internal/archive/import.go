@@ -0,0 +1,198 @@
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadManifest returns an archive's manifest without restoring anything.
+func ReadManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is from the user
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	r, err := open(f)
+	if err != nil {
+		return nil, err
+	}
+	// Stopping early makes zstd complain about the closed pipe; that's fine
+	defer func() { _ = r.Close() }()
+	return readManifest(tar.NewReader(r))
+}
+
+// Import restores an archive into dest and returns its manifest. It
+// refuses, with ErrIncompatible, an archive this gt can't read, before
+// writing anything. Files already in dest are overwritten.
+func Import(path, dest string) (*Manifest, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is from the user
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	r, err := open(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(r)
+	m, err := readManifest(tr)
+	if err == nil {
+		err = m.Check()
+	}
+	if err != nil {
+		_ = r.Close()
+		return m, err
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		_ = r.Close()
+		return m, fmt.Errorf("creating %s: %w", dest, err)
+	}
+	if err := extract(tr, dest); err != nil {
+		_ = r.Close()
+		return m, err
+	}
+	if err := r.Close(); err != nil {
+		return m, fmt.Errorf("reading archive: %w", err)
+	}
+	return m, nil
+}
+
+// open returns the archive's tar stream, decompressing as its magic
+// bytes say.
+func open(f io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(4)
+	r, err := decompress(sniffCompression(head), br)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	return r, nil
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != ManifestName {
+		return nil, fmt.Errorf("not a gt export: no %s", ManifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ManifestName, err)
+	}
+	return &m, nil
+}
+
+// extract writes the rest of the archive's entries under dest.
+func extract(tr *tar.Reader, dest string) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		target, err := entryPath(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkParents(dest, target); err != nil {
+			return fmt.Errorf("archive entry %q: %w", hdr.Name, err)
+		}
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return fmt.Errorf("restoring %s: %w", hdr.Name, err)
+			}
+		case tar.TypeReg:
+			if err := restoreFile(tr, target, mode); err != nil {
+				return fmt.Errorf("restoring %s: %w", hdr.Name, err)
+			}
+		case tar.TypeSymlink:
+			if err := checkLink(dest, target, hdr.Linkname); err != nil {
+				return fmt.Errorf("archive entry %q: %w", hdr.Name, err)
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return fmt.Errorf("restoring %s: %w", hdr.Name, err)
+			}
+		}
+	}
+}
+
+// entryPath returns where an archive entry goes under dest, refusing
+// names that would land outside it.
+func entryPath(dest, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q is outside the town", name)
+	}
+	return filepath.Join(dest, clean), nil
+}
+
+// checkLink refuses a symlink at target whose link would point outside
+// dest: absolute links, and relative ones that climb out of it.
+func checkLink(dest, target, link string) error {
+	if filepath.IsAbs(link) || !within(dest, filepath.Join(filepath.Dir(target), link)) {
+		return fmt.Errorf("symlink to %q is outside the town", link)
+	}
+	return nil
+}
+
+// checkParents refuses a target whose parent directories under dest pass
+// through a symlink, such as one restored earlier from the same archive:
+// writing through it could land outside dest.
+func checkParents(dest, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+	dir := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil // Nothing below here exists yet to pass through
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("path passes through symlink %s", dir)
+		}
+	}
+	return nil
+}
+
+// within reports whether path is dest or under it.
+func within(dest, path string) bool {
+	rel, err := filepath.Rel(dest, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func restoreFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	_ = os.Remove(target) // Replace, rather than write through, a symlink
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec // G110: size comes from our own export
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
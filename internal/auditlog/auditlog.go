@@ -0,0 +1,238 @@
+// Package auditlog records state-changing gt invocations in an append-only,
+// hash-chained log per town.
+//
+// Each record carries the SHA-256 hash of its predecessor, so editing or
+// deleting a record breaks the chain from that point on; Verify reports the
+// first broken record. The log lives at <town>/logs/audit.jsonl, next to the
+// town log.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// File is the audit log's name within the town logs directory.
+const File = "audit.jsonl"
+
+// lockTimeout bounds how long Append waits for concurrent writers.
+const lockTimeout = 5 * time.Second
+
+// tailChunk is how much of the file Append reads to find the last record.
+const tailChunk = 64 * 1024
+
+// Record is one audited invocation.
+type Record struct {
+	Seq        int       `json:"seq"`
+	Timestamp  time.Time `json:"timestamp"`
+	Actor      string    `json:"actor"`          // e.g., "greenplace/polecats/Toast" or "human"
+	Role       string    `json:"role,omitempty"` // GT_ROLE-derived role, empty for humans
+	User       string    `json:"user,omitempty"` // OS user that ran the command
+	Command    string    `json:"command"`        // e.g., "mq reject"
+	Args       []string  `json:"args"`
+	ExitCode   int       `json:"exit_code"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// ChainError reports the first record whose hash chain does not verify.
+type ChainError struct {
+	Seq    int
+	Line   int
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit log tampered at record %d (line %d): %s", e.Seq, e.Line, e.Reason)
+}
+
+// Path returns the audit log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "logs", File)
+}
+
+// Append chains r onto the town's audit log and writes it. Seq, PrevHash,
+// and Hash are filled in; the completed record is returned. Concurrent
+// writers are serialized with a lock file.
+func Append(townRoot string, r Record) (Record, error) {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return r, fmt.Errorf("creating logs directory: %w", err)
+	}
+
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return r, fmt.Errorf("locking audit log: %w", err)
+	}
+	if !locked {
+		return r, fmt.Errorf("timeout waiting for audit log lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	last, err := lastRecord(path)
+	if err != nil {
+		return r, err
+	}
+	r.Seq = 1
+	r.PrevHash = ""
+	if last != nil {
+		r.Seq = last.Seq + 1
+		r.PrevHash = last.Hash
+	}
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+	r.Timestamp = r.Timestamp.UTC()
+	if r.Args == nil {
+		r.Args = []string{}
+	}
+	r.Hash = hashRecord(r)
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return r, fmt.Errorf("encoding audit record: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: audit log is shared by town agents
+	if err != nil {
+		return r, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return r, fmt.Errorf("writing audit log: %w", err)
+	}
+	return r, nil
+}
+
+// ReadAll returns every record in the town's audit log, oldest first. A
+// missing log is empty.
+func ReadAll(townRoot string) ([]Record, error) {
+	f, err := os.Open(Path(townRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	return decode(f)
+}
+
+// Verify checks the hash chain of records as read from the log. It returns
+// a *ChainError for the first record that was altered, removed, or
+// reordered.
+func Verify(records []Record) error {
+	prev := ""
+	for i, r := range records {
+		switch {
+		case r.Seq != i+1:
+			return &ChainError{Seq: r.Seq, Line: i + 1, Reason: fmt.Sprintf("expected sequence %d", i+1)}
+		case r.PrevHash != prev:
+			return &ChainError{Seq: r.Seq, Line: i + 1, Reason: "previous hash does not match"}
+		case r.Hash != hashRecord(r):
+			return &ChainError{Seq: r.Seq, Line: i + 1, Reason: "record hash does not match its contents"}
+		}
+		prev = r.Hash
+	}
+	return nil
+}
+
+// hashRecord returns the hex SHA-256 of r's JSON encoding with Hash cleared.
+// PrevHash is part of the encoding, which is what chains the records.
+func hashRecord(r Record) string {
+	r.Hash = ""
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func decode(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return records, &ChainError{Seq: len(records) + 1, Line: line, Reason: "malformed record"}
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("reading audit log: %w", err)
+	}
+	return records, nil
+}
+
+// lastRecord returns the final record of the log, or nil when the log is
+// missing or empty. Only the tail of the file is read unless the last record
+// is longer than tailChunk.
+func lastRecord(path string) (*Record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	offset := info.Size() - tailChunk
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	buf = bytes.TrimRight(buf, "\n")
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	start := bytes.LastIndexByte(buf, '\n')
+	if start < 0 && offset > 0 {
+		// The last record is longer than the chunk; fall back to a full read.
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("reading audit log: %w", err)
+		}
+		records, err := decode(f)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		return &records[len(records)-1], nil
+	}
+
+	var rec Record
+	if err := json.Unmarshal(buf[start+1:], &rec); err != nil {
+		return nil, fmt.Errorf("audit log has a malformed last record: %w", err)
+	}
+	return &rec, nil
+}
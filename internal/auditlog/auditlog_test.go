@@ -0,0 +1,138 @@
+package auditlog
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func appendN(t *testing.T, townRoot string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := Append(townRoot, Record{Actor: "human", Command: "mq reject", Args: []string{"mq", "reject", "greenplace", "gp-1"}}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+}
+
+func TestAppendChainsRecords(t *testing.T) {
+	townRoot := t.TempDir()
+	appendN(t, townRoot, 3)
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if records[0].PrevHash != "" || records[1].PrevHash != records[0].Hash || records[2].PrevHash != records[1].Hash {
+		t.Error("records are not chained by hash")
+	}
+	if records[2].Seq != 3 {
+		t.Errorf("Seq = %d, want 3", records[2].Seq)
+	}
+	if err := Verify(records); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestReadAllMissingLog(t *testing.T) {
+	records, err := ReadAll(t.TempDir())
+	if err != nil || len(records) != 0 {
+		t.Errorf("ReadAll on missing log = %v, %v; want empty", records, err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func([]Record) []Record
+		seq    int
+	}{
+		{"edited", func(r []Record) []Record { r[1].Actor = "mayor"; return r }, 2},
+		{"deleted", func(r []Record) []Record { return append(r[:1], r[2:]...) }, 3},
+		{"reordered", func(r []Record) []Record { r[1], r[2] = r[2], r[1]; return r }, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			appendN(t, townRoot, 4)
+			records, _ := ReadAll(townRoot)
+
+			err := Verify(tt.tamper(records))
+			var chainErr *ChainError
+			if !errors.As(err, &chainErr) {
+				t.Fatalf("Verify = %v, want ChainError", err)
+			}
+			if chainErr.Seq != tt.seq {
+				t.Errorf("ChainError.Seq = %d, want %d", chainErr.Seq, tt.seq)
+			}
+		})
+	}
+}
+
+func TestVerifyDetectsEditedFile(t *testing.T) {
+	townRoot := t.TempDir()
+	appendN(t, townRoot, 2)
+
+	data, _ := os.ReadFile(Path(townRoot))
+	edited := strings.Replace(string(data), `"exit_code":0`, `"exit_code":1`, 1)
+	if err := os.WriteFile(Path(townRoot), []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if err := Verify(records); err == nil {
+		t.Error("Verify should fail after the file was edited")
+	}
+}
+
+func TestAppendConcurrent(t *testing.T) {
+	townRoot := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Append(townRoot, Record{Actor: "human", Command: "sling"}); err != nil {
+				t.Errorf("Append: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 8 {
+		t.Fatalf("got %d records, want 8", len(records))
+	}
+	if err := Verify(records); err != nil {
+		t.Errorf("Verify after concurrent appends: %v", err)
+	}
+}
+
+func TestAppendLongLastRecord(t *testing.T) {
+	townRoot := t.TempDir()
+	appendN(t, townRoot, 1)
+	long := strings.Repeat("x", tailChunk+10)
+	if _, err := Append(townRoot, Record{Actor: "human", Command: "mail send", Args: []string{long}}); err != nil {
+		t.Fatalf("Append long: %v", err)
+	}
+	appendN(t, townRoot, 1)
+
+	records, _ := ReadAll(townRoot)
+	if err := Verify(records); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if records[2].Seq != 3 {
+		t.Errorf("Seq after long record = %d, want 3", records[2].Seq)
+	}
+}
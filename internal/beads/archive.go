@@ -0,0 +1,183 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// MRArchiveFile is the file in a beads directory that holds its archived
+// merge requests, one bead per line, as bd list --json writes them.
+const MRArchiveFile = "mr-archive.jsonl"
+
+// MRArchivePath returns where the beads directory keeps archived MRs.
+func (b *Beads) MRArchivePath() string {
+	return filepath.Join(b.getResolvedBeadsDir(), MRArchiveFile)
+}
+
+// ArchiveMRs moves closed merge-request beads out of the database into the
+// archive, so queue queries no longer see them. Each bead is written to the
+// archive before it is deleted, so a failure part way leaves it in both
+// places rather than neither; ListArchivedMRs keeps the last copy of each.
+// Returns the IDs archived before any error.
+func (b *Beads) ArchiveMRs(issues []*Issue) ([]string, error) {
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	if err := appendMRArchive(b.MRArchivePath(), issues); err != nil {
+		return nil, fmt.Errorf("writing MR archive: %w", err)
+	}
+	var archived []string
+	for _, issue := range issues {
+		if _, err := b.run("delete", issue.ID, "--hard", "--force"); err != nil {
+			return archived, fmt.Errorf("deleting %s: %w", issue.ID, err)
+		}
+		archived = append(archived, issue.ID)
+	}
+	return archived, nil
+}
+
+// ListArchivedMRs returns the archived merge requests, oldest archived
+// first. A bead archived more than once appears once, as last written.
+func (b *Beads) ListArchivedMRs() ([]*Issue, error) {
+	return readMRArchive(b.MRArchivePath())
+}
+
+// CompactMRArchive rewrites the archive with one copy of each bead, the
+// last written, dropping the older copies a bead archived more than once
+// (or a part-failed archive) left, and lines that don't parse. With dryRun nothing is written. Returns
+// how many copies were, or would be, dropped and the bytes reclaimed.
+func (b *Beads) CompactMRArchive(dryRun bool) (dropped int, reclaimed int64, err error) {
+	path := b.MRArchivePath()
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	unlock, err := lockMRArchive(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	lines, err := countLines(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	issues, err := readMRArchive(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var buf bytes.Buffer
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return 0, 0, err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	dropped = lines - len(issues)
+	if dropped == 0 {
+		return 0, 0, nil
+	}
+	reclaimed = info.Size() - int64(buf.Len())
+	if dryRun {
+		return dropped, reclaimed, nil
+	}
+	if err := util.AtomicWriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return 0, 0, fmt.Errorf("writing MR archive: %w", err)
+	}
+	return dropped, reclaimed, nil
+}
+
+// lockMRArchive takes the archive's lock, so compacting it can't drop MRs
+// archived meanwhile. The returned func releases it.
+func lockMRArchive(path string) (func(), error) {
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking MR archive: %w", err)
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// countLines counts the non-empty lines of a file.
+func countLines(path string) (int, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func appendMRArchive(path string, issues []*Issue) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockMRArchive(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: archive is non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return file.Sync()
+}
+
+func readMRArchive(path string) ([]*Issue, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var issues []*Issue
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var issue Issue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil || issue.ID == "" {
+			continue // Skip malformed lines
+		}
+		if i, ok := index[issue.ID]; ok {
+			issues[i] = &issue
+			continue
+		}
+		index[issue.ID] = len(issues)
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
@@ -0,0 +1,80 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMRArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	b := NewWithBeadsDir(dir, filepath.Join(dir, ".beads"))
+
+	if got, err := b.ListArchivedMRs(); err != nil || got != nil {
+		t.Fatalf("empty archive = %v, %v; want nil, nil", got, err)
+	}
+
+	path := b.MRArchivePath()
+	if err := appendMRArchive(path, []*Issue{
+		{ID: "gp-mr-1", Title: "Merge: gp-1", Status: "closed"},
+		{ID: "gp-mr-2", Title: "Merge: gp-2", Status: "closed"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A bead archived again (after a failed delete) keeps its latest copy
+	if err := appendMRArchive(path, []*Issue{{ID: "gp-mr-1", Title: "Merge: gp-1 (retitled)", Status: "closed"}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+
+	got, err := b.ListArchivedMRs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "gp-mr-1" || got[1].ID != "gp-mr-2" {
+		t.Fatalf("archived = %+v, want gp-mr-1, gp-mr-2", got)
+	}
+	if got[0].Title != "Merge: gp-1 (retitled)" {
+		t.Errorf("gp-mr-1 title = %q, want the latest copy", got[0].Title)
+	}
+}
+
+func TestCompactMRArchive(t *testing.T) {
+	dir := t.TempDir()
+	b := NewWithBeadsDir(dir, filepath.Join(dir, ".beads"))
+	if dropped, _, err := b.CompactMRArchive(false); err != nil || dropped != 0 {
+		t.Fatalf("compacting no archive = %d, %v", dropped, err)
+	}
+
+	path := b.MRArchivePath()
+	for _, title := range []string{"Merge: gp-1", "Merge: gp-1 (again)"} {
+		if err := appendMRArchive(path, []*Issue{{ID: "gp-mr-1", Title: title}, {ID: "gp-mr-2", Title: "Merge: gp-2"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, _ := os.Stat(path)
+
+	dropped, reclaimed, err := b.CompactMRArchive(true)
+	if err != nil || dropped != 2 || reclaimed <= 0 {
+		t.Fatalf("dry run = %d, %d, %v; want 2 dropped", dropped, reclaimed, err)
+	}
+	if after, _ := os.Stat(path); after.Size() != before.Size() {
+		t.Error("dry run rewrote the archive")
+	}
+
+	if dropped, _, err = b.CompactMRArchive(false); err != nil || dropped != 2 {
+		t.Fatalf("compact = %d, %v", dropped, err)
+	}
+	got, err := b.ListArchivedMRs()
+	if err != nil || len(got) != 2 || got[0].Title != "Merge: gp-1 (again)" {
+		t.Fatalf("archived after compacting = %+v, %v", got, err)
+	}
+	if dropped, _, _ := b.CompactMRArchive(false); dropped != 0 {
+		t.Errorf("compacting again dropped %d", dropped)
+	}
+}
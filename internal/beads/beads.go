@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/helper"
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
@@ -41,6 +42,10 @@ type Issue struct {
 	Blocks      []string `json:"blocks,omitempty"`
 	BlockedBy   []string `json:"blocked_by,omitempty"`
 	Labels      []string `json:"labels,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
+
+	// Comments, oldest first (show output only)
+	Comments []IssueComment `json:"comments,omitempty"`
 
 	// Agent bead slots (type=agent only)
 	HookBead   string `json:"hook_bead,omitempty"`   // Current work attached to agent's hook
@@ -57,6 +62,13 @@ type Issue struct {
 	Dependents   []IssueDep `json:"dependents,omitempty"`
 }
 
+// IssueComment is a comment on an issue.
+type IssueComment struct {
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
 // IssueDep represents a dependency or dependent issue with its relation.
 type IssueDep struct {
 	ID             string `json:"id"`
@@ -218,7 +230,16 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	// Read-only queries go to the helper when one is running (not in
+	// isolated mode, where tests control bd directly)
+	var err error
+	if res, ok := b.runHelper(fullArgs, cmd.Env); ok {
+		stdout.Write(res.Stdout)
+		stderr.Write(res.Stderr)
+		err = res.Err
+	} else {
+		err = cmd.Run()
+	}
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -233,6 +254,14 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	return stdout.Bytes(), nil
 }
 
+// runHelper sends a read-only bd query to the helper, if one is running.
+func (b *Beads) runHelper(args, env []string) (*helper.Result, bool) {
+	if b.isolated {
+		return nil, false
+	}
+	return helper.Run("bd", args, b.workDir, env)
+}
+
 // Run executes a bd command and returns stdout.
 // This is a public wrapper around the internal run method for cases where
 // callers need to run arbitrary bd commands.
@@ -595,6 +624,12 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 	return err
 }
 
+// Comment adds a comment to an issue.
+func (b *Beads) Comment(id, text string) error {
+	_, err := b.run("comment", id, text)
+	return err
+}
+
 // Close closes one or more issues.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
@@ -655,6 +690,18 @@ func (b *Beads) ReleaseWithReason(id, reason string) error {
 	return err
 }
 
+// ReassignWithReason reopens an issue and assigns it to assignee, adding
+// the reason as a note. It is used to hand work back to a worker.
+func (b *Beads) ReassignWithReason(id, assignee, reason string) error {
+	args := []string{"update", id, "--status=open", "--assignee=" + assignee}
+	if reason != "" {
+		args = append(args, "--notes="+reason)
+	}
+
+	_, err := b.run(args...)
+	return err
+}
+
 // AddDependency adds a dependency: issue depends on dependsOn.
 func (b *Beads) AddDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "add", issue, dependsOn)
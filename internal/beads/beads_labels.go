@@ -0,0 +1,64 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+)
+
+// systemLabelPrefixes mark labels Gas Town manages itself: bead kinds
+// (gt:merge-request) and claim leases (claimed-by:, claim-expires:).
+var systemLabelPrefixes = []string{"gt:", "claimed-by:", "claimed-at:", "claim-expires:"}
+
+// IsSystemLabel reports whether label is one Gas Town manages itself,
+// rather than one people and agents put on issues to categorize them.
+func IsSystemLabel(label string) bool {
+	for _, prefix := range systemLabelPrefixes {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayLabels returns issue's labels other than system ones, in order.
+func DisplayLabels(issue *Issue) []string {
+	var labels []string
+	for _, label := range issue.Labels {
+		if !IsSystemLabel(label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// HasAllLabels reports whether issue has every one of labels.
+func HasAllLabels(issue *Issue, labels []string) bool {
+	for _, label := range labels {
+		if !HasLabel(issue, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeLabels trims labels given on the command line, drops repeats,
+// and rejects ones that are empty, contain commas or whitespace, or are
+// system labels.
+func NormalizeLabels(labels []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || strings.ContainsAny(label, ", \t\n") {
+			return nil, fmt.Errorf("bad label %q: labels are non-empty, without commas or spaces", label)
+		}
+		if IsSystemLabel(label) {
+			return nil, fmt.Errorf("label %q is reserved for Gas Town", label)
+		}
+		if !seen[label] {
+			seen[label] = true
+			normalized = append(normalized, label)
+		}
+	}
+	return normalized, nil
+}
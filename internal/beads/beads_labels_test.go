@@ -0,0 +1,34 @@
+package beads
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDisplayLabels(t *testing.T) {
+	issue := &Issue{Labels: []string{"gt:merge-request", "urgent", "claimed-by:greenplace/crew/max", "infra", "claim-expires:2026-03-01T10:00:00Z"}}
+	if got := DisplayLabels(issue); !slices.Equal(got, []string{"urgent", "infra"}) {
+		t.Errorf("DisplayLabels = %q", got)
+	}
+	if !HasAllLabels(issue, []string{"infra", "urgent"}) || HasAllLabels(issue, []string{"infra", "docs"}) {
+		t.Error("HasAllLabels wrong")
+	}
+	if !HasAllLabels(issue, nil) {
+		t.Error("HasAllLabels(nil) should match everything")
+	}
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	got, err := NormalizeLabels([]string{" urgent", "infra", "urgent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"urgent", "infra"}) {
+		t.Errorf("NormalizeLabels = %q", got)
+	}
+	for _, bad := range []string{"", "a,b", "two words", "gt:merge-request", "claimed-by:me"} {
+		if _, err := NormalizeLabels([]string{bad}); err == nil {
+			t.Errorf("NormalizeLabels(%q) should fail", bad)
+		}
+	}
+}
@@ -31,6 +31,32 @@ func (b *Beads) FindMRForBranch(branch string) (*Issue, error) {
 	return nil, nil
 }
 
+// FindOpenMR returns the unmerged (open or in progress) merge-request bead
+// for branch into target, or nil if there is none. An MR that doesn't
+// record its target matches any.
+func (b *Beads) FindOpenMR(branch, target string) (*Issue, error) {
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := b.List(ListOptions{
+			Status:   status,
+			Label:    "gt:merge-request",
+			Priority: -1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			fields := ParseMRFields(issue)
+			if fields == nil || fields.Branch != branch {
+				continue
+			}
+			if fields.Target == "" || fields.Target == target {
+				return issue, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
 // AddGateWaiter registers an agent as a waiter on a gate bead.
 // When the gate closes, the waiter will receive a wake notification via gt gate wake.
 // The waiter is typically the polecat's address (e.g., "gastown/polecats/Toast").
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindOpenMR(t *testing.T) {
+	binDir, dataDir := t.TempDir(), t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+  *--status=in_progress*) cat "` + filepath.Join(dataDir, "in_progress.json") + `" ;;
+  *) cat "` + filepath.Join(dataDir, "open.json") + `" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	write := func(name, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("open.json", `[
+		{"id":"gp-mr-1","status":"open","priority":2,"labels":["gt:merge-request"],"description":"branch: polecat/nux/gp-1\ntarget: release/1.2\nsource_issue: gp-1"},
+		{"id":"gp-mr-2","status":"open","priority":2,"labels":["gt:merge-request"],"description":"branch: polecat/nux/gp-1-more\ntarget: main\nsource_issue: gp-1"}
+	]`)
+	write("in_progress.json", `[
+		{"id":"gp-mr-3","status":"in_progress","priority":2,"labels":["gt:merge-request"],"description":"branch: polecat/nux/gp-1\ntarget: main\nsource_issue: gp-1"}
+	]`)
+	b := New(t.TempDir())
+
+	for _, tt := range []struct{ branch, target, want string }{
+		{"polecat/nux/gp-1", "main", "gp-mr-3"}, // The refinery is merging it
+		{"polecat/nux/gp-1", "release/1.2", "gp-mr-1"},
+		{"polecat/nux/gp-1", "release/1.3", ""},
+		{"polecat/nux/gp-2", "main", ""},
+	} {
+		mr, err := b.FindOpenMR(tt.branch, tt.target)
+		if err != nil {
+			t.Fatalf("FindOpenMR(%s, %s): %v", tt.branch, tt.target, err)
+		}
+		got := ""
+		if mr != nil {
+			got = mr.ID
+		}
+		if got != tt.want {
+			t.Errorf("FindOpenMR(%s, %s) = %q, want %q", tt.branch, tt.target, got, tt.want)
+		}
+	}
+}
@@ -648,6 +648,38 @@ Also see http://localhost:8080/api`,
 	}
 }
 
+func TestMRBody(t *testing.T) {
+	issue := &Issue{Description: `branch: polecat/Nux/gt-xyz
+target: main
+
+## Summary
+
+Moves the deploy target.
+Target: production
+
+## Testing
+
+make test`}
+
+	// Body text is neither parsed as fields nor dropped when they change
+	if fields := ParseMRFields(issue); fields.Target != "main" {
+		t.Errorf("Target = %q, want main (body lines aren't fields)", fields.Target)
+	}
+	issue.Description = SetMRFields(issue, &MRFields{Branch: "polecat/Nux/gt-xyz", Target: "main", State: "queued"})
+	body := MRBody(issue)
+	if !strings.HasPrefix(body, "## Summary") || !strings.Contains(body, "Target: production") ||
+		!strings.HasSuffix(body, "make test") {
+		t.Errorf("MRBody after SetMRFields = %q", body)
+	}
+	if fields := ParseMRFields(issue); fields.State != "queued" || fields.Target != "main" {
+		t.Errorf("fields after SetMRFields = %+v", fields)
+	}
+
+	if got := MRBody(&Issue{Description: "branch: b\nNote without a heading"}); got != "" {
+		t.Errorf("MRBody without a heading = %q, want empty", got)
+	}
+}
+
 // TestParseAttachmentFields tests parsing attachment fields from issue descriptions.
 func TestParseAttachmentFields(t *testing.T) {
 	tests := []struct {
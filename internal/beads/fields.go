@@ -185,7 +185,11 @@ type MRFields struct {
 	SourceIssue string // The work item being merged (e.g., "gt-xyz")
 	Worker      string // Who did the work
 	Rig         string // Which rig
+	Project     string // Monorepo sub-project its changes fall in (see merge_queue.projects)
+	Locks       string // Path locks it merges under, comma-separated (see merge_queue.locks)
+	State       string // Lifecycle state: queued, rebasing, ..., merged (see refinery.MRState)
 	MergeCommit string // SHA of merge commit (set on close)
+	MergeHead   string // SHA of the source branch tip that was merged (set on close)
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
 
@@ -197,6 +201,55 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Branch protection and review state (comma-separated lists)
+	ChecksPassed       string // Names of checks that passed (e.g., "tests,lint")
+	ApprovedBy         string // Addresses that approved the MR
+	ChangesRequestedBy string // Addresses that requested changes (blocks merging)
+	Reviewers          string // Reviewers assigned at submit time
+	ChangesTasks       string // Follow-up tasks for the worker from requests for changes
+
+	// Latest test run (see refinery.TestResults)
+	TestResults  string // Summary, e.g. "failed suite=go passed=41 failed=2 duration=12.5s"
+	FailingTests string // Comma-separated names of failing tests
+	FlakyChecks  string // Checks that passed only after a retry (e.g., "tests")
+	Artifacts    string // Directory holding the check runs' logs and reports
+	Coverage     string // Coverage delta, e.g. "81.62% -> 81.20% (-0.42)"
+	LintFindings string // JSON list of lint findings (see refinery.LintFinding)
+
+	// Revert tracking
+	Reverts    string // MR this revert MR backs out
+	RevertedBy string // Revert MR that backs this (merged) MR out
+
+	// Backport tracking
+	BackportOf string // MR this cherry-pick MR backports
+	Backports  string // Comma-separated backport MRs submitted for this (merged) MR
+
+	// Attestation of the merge, as JSON (see refinery.Attestation)
+	Attestation string
+
+	// Discussion thread, as JSON (see refinery.MRComment)
+	Comments string
+}
+
+// MRBodyHeading starts the free-form body of an MR description: the
+// sections written at submit time (e.g., "## Summary"). Fields come before
+// the body; from its first heading on, "key: value" lines are body text.
+const MRBodyHeading = "## "
+
+// MRBody returns the body of an MR's description, from its first heading
+// on, or "" if it has none.
+func MRBody(issue *Issue) string {
+	if issue == nil {
+		return ""
+	}
+	lines := strings.Split(issue.Description, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), MRBodyHeading) {
+			return strings.TrimSpace(strings.Join(lines[i:], "\n"))
+		}
+	}
+	return ""
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -215,6 +268,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		if line == "" {
 			continue
 		}
+		if strings.HasPrefix(line, MRBodyHeading) {
+			break // The body's "key: value" text isn't fields
+		}
 
 		// Look for "key: value" pattern
 		colonIdx := strings.Index(line, ":")
@@ -245,9 +301,21 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "rig":
 			fields.Rig = value
 			hasFields = true
+		case "project":
+			fields.Project = value
+			hasFields = true
+		case "locks":
+			fields.Locks = value
+			hasFields = true
+		case "state":
+			fields.State = value
+			hasFields = true
 		case "merge_commit", "merge-commit", "mergecommit":
 			fields.MergeCommit = value
 			hasFields = true
+		case "merge_head", "merge-head", "mergehead":
+			fields.MergeHead = value
+			hasFields = true
 		case "close_reason", "close-reason", "closereason":
 			fields.CloseReason = value
 			hasFields = true
@@ -271,6 +339,57 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "checks_passed", "checks-passed", "checkspassed":
+			fields.ChecksPassed = value
+			hasFields = true
+		case "approved_by", "approved-by", "approvedby":
+			fields.ApprovedBy = value
+			hasFields = true
+		case "changes_requested_by", "changes-requested-by", "changesrequestedby":
+			fields.ChangesRequestedBy = value
+			hasFields = true
+		case "reviewers":
+			fields.Reviewers = value
+			hasFields = true
+		case "changes_tasks", "changes-tasks", "changestasks":
+			fields.ChangesTasks = value
+			hasFields = true
+		case "test_results", "test-results", "testresults":
+			fields.TestResults = value
+			hasFields = true
+		case "failing_tests", "failing-tests", "failingtests":
+			fields.FailingTests = value
+			hasFields = true
+		case "flaky_checks", "flaky-checks", "flakychecks":
+			fields.FlakyChecks = value
+			hasFields = true
+		case "artifacts":
+			fields.Artifacts = value
+			hasFields = true
+		case "coverage":
+			fields.Coverage = value
+			hasFields = true
+		case "lint_findings", "lint-findings", "lintfindings":
+			fields.LintFindings = value
+			hasFields = true
+		case "reverts":
+			fields.Reverts = value
+			hasFields = true
+		case "reverted_by", "reverted-by", "revertedby":
+			fields.RevertedBy = value
+			hasFields = true
+		case "backport_of", "backport-of", "backportof":
+			fields.BackportOf = value
+			hasFields = true
+		case "backports":
+			fields.Backports = value
+			hasFields = true
+		case "attestation":
+			fields.Attestation = value
+			hasFields = true
+		case "comments":
+			fields.Comments = value
+			hasFields = true
 		}
 	}
 
@@ -311,9 +430,21 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.Rig != "" {
 		lines = append(lines, "rig: "+fields.Rig)
 	}
+	if fields.Project != "" {
+		lines = append(lines, "project: "+fields.Project)
+	}
+	if fields.Locks != "" {
+		lines = append(lines, "locks: "+fields.Locks)
+	}
+	if fields.State != "" {
+		lines = append(lines, "state: "+fields.State)
+	}
 	if fields.MergeCommit != "" {
 		lines = append(lines, "merge_commit: "+fields.MergeCommit)
 	}
+	if fields.MergeHead != "" {
+		lines = append(lines, "merge_head: "+fields.MergeHead)
+	}
 	if fields.CloseReason != "" {
 		lines = append(lines, "close_reason: "+fields.CloseReason)
 	}
@@ -335,6 +466,57 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.ChecksPassed != "" {
+		lines = append(lines, "checks_passed: "+fields.ChecksPassed)
+	}
+	if fields.ApprovedBy != "" {
+		lines = append(lines, "approved_by: "+fields.ApprovedBy)
+	}
+	if fields.ChangesRequestedBy != "" {
+		lines = append(lines, "changes_requested_by: "+fields.ChangesRequestedBy)
+	}
+	if fields.Reviewers != "" {
+		lines = append(lines, "reviewers: "+fields.Reviewers)
+	}
+	if fields.ChangesTasks != "" {
+		lines = append(lines, "changes_tasks: "+fields.ChangesTasks)
+	}
+	if fields.TestResults != "" {
+		lines = append(lines, "test_results: "+fields.TestResults)
+	}
+	if fields.FailingTests != "" {
+		lines = append(lines, "failing_tests: "+fields.FailingTests)
+	}
+	if fields.FlakyChecks != "" {
+		lines = append(lines, "flaky_checks: "+fields.FlakyChecks)
+	}
+	if fields.Artifacts != "" {
+		lines = append(lines, "artifacts: "+fields.Artifacts)
+	}
+	if fields.Coverage != "" {
+		lines = append(lines, "coverage: "+fields.Coverage)
+	}
+	if fields.LintFindings != "" {
+		lines = append(lines, "lint_findings: "+fields.LintFindings)
+	}
+	if fields.Reverts != "" {
+		lines = append(lines, "reverts: "+fields.Reverts)
+	}
+	if fields.RevertedBy != "" {
+		lines = append(lines, "reverted_by: "+fields.RevertedBy)
+	}
+	if fields.BackportOf != "" {
+		lines = append(lines, "backport_of: "+fields.BackportOf)
+	}
+	if fields.Backports != "" {
+		lines = append(lines, "backports: "+fields.Backports)
+	}
+	if fields.Attestation != "" {
+		lines = append(lines, "attestation: "+fields.Attestation)
+	}
+	if fields.Comments != "" {
+		lines = append(lines, "comments: "+fields.Comments)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -356,9 +538,15 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"sourceissue":        true,
 		"worker":             true,
 		"rig":                true,
+		"project":            true,
+		"locks":              true,
+		"state":              true,
 		"merge_commit":       true,
 		"merge-commit":       true,
 		"mergecommit":        true,
+		"merge_head":         true,
+		"merge-head":         true,
+		"mergehead":          true,
 		"close_reason":       true,
 		"close-reason":       true,
 		"closereason":        true,
@@ -381,12 +569,50 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"checks_passed":      true,
+		"checks-passed":      true,
+		"checkspassed":       true,
+		"approved_by":        true,
+		"approved-by":        true,
+		"approvedby":         true,
+		"changes_requested_by": true,
+		"changes-requested-by": true,
+		"changesrequestedby":   true,
+		"reviewers":            true,
+		"changes_tasks":        true,
+		"changes-tasks":        true,
+		"changestasks":         true,
+		"test_results":         true,
+		"test-results":         true,
+		"testresults":          true,
+		"failing_tests":        true,
+		"failing-tests":        true,
+		"failingtests":         true,
+		"flaky_checks":         true,
+		"flaky-checks":         true,
+		"flakychecks":          true,
+		"artifacts":            true,
+		"coverage":             true,
+		"lint_findings":        true,
+		"lint-findings":        true,
+		"lintfindings":         true,
+		"reverts":              true,
+		"reverted_by":          true,
+		"reverted-by":          true,
+		"revertedby":           true,
+		"backport_of":          true,
+		"backport-of":          true,
+		"backportof":           true,
+		"backports":            true,
+		"attestation":          true,
+		"comments":             true,
 	}
 
 	// Collect non-MR lines from existing description
 	var otherLines []string
 	if issue.Description != "" {
-		for _, line := range strings.Split(issue.Description, "\n") {
+		lines := strings.Split(issue.Description, "\n")
+		for i, line := range lines {
 			trimmed := strings.TrimSpace(line)
 			if trimmed == "" {
 				// Preserve blank lines in content
@@ -394,6 +620,12 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 				continue
 			}
 
+			// The body is kept as written
+			if strings.HasPrefix(trimmed, MRBodyHeading) {
+				otherLines = append(otherLines, lines[i:]...)
+				break
+			}
+
 			// Check if this is an MR field line
 			colonIdx := strings.Index(trimmed, ":")
 			if colonIdx == -1 {
@@ -0,0 +1,223 @@
+package beads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// IndexEnv disables the list index when set to "0", so every ListIndexed
+// call runs bd.
+const IndexEnv = "GT_BEADS_INDEX"
+
+const indexVersion = 1
+
+// indexRebuildAge bounds how long an index is kept up to date incrementally.
+// Beads deleted outside gt never show up as updated, so a periodic full
+// rebuild drops them.
+const indexRebuildAge = 15 * time.Minute
+
+// indexOverlap re-fetches beads updated shortly before the last refresh, to
+// cover clock skew and second-granularity timestamps.
+const indexOverlap = time.Minute
+
+var errNoDatabase = errors.New("no local beads database to index")
+
+// listIndex is a local snapshot of every bead in a beads directory, tagged
+// with the database fingerprint it was taken at.
+type listIndex struct {
+	Version     int               `json:"version"`
+	BeadsDir    string            `json:"beads_dir"`
+	Fingerprint string            `json:"fingerprint"`
+	RefreshedAt time.Time         `json:"refreshed_at"`
+	RebuiltAt   time.Time         `json:"rebuilt_at"`
+	Issues      map[string]*Issue `json:"issues"`
+}
+
+// IndexPath returns where the list index for beadsDir is cached. The index
+// is local state, like beads.db, so it lives outside the synced directory.
+func IndexPath(beadsDir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(beadsDir)))
+	return filepath.Join(state.CacheDir(), "beads-index", hex.EncodeToString(sum[:8])+".json")
+}
+
+// ListIndexed is List answered from a local index of the beads directory.
+// While the beads database is unchanged, no bd process runs. When it has
+// changed (a write by gt, bd, or a sync), the index first catches up by
+// asking bd for beads updated since its last refresh, or by listing
+// everything. If the index can't be used, it falls back to List.
+//
+// Status "" matches every status except closed, as bd list does.
+func (b *Beads) ListIndexed(opts ListOptions) ([]*Issue, error) {
+	if opts.Parent != "" || b.isolated || os.Getenv(IndexEnv) == "0" {
+		return b.List(opts)
+	}
+	idx, err := b.loadIndex()
+	if err != nil {
+		return b.List(opts)
+	}
+	return idx.filter(opts), nil
+}
+
+// loadIndex returns an index that is current with the beads database,
+// refreshing or rebuilding the cached one as needed.
+func (b *Beads) loadIndex() (*listIndex, error) {
+	dir := b.getResolvedBeadsDir()
+	// Fingerprint before querying: a write that lands mid-query changes
+	// the fingerprint and is caught by the next call.
+	fingerprint := databaseFingerprint(dir)
+	if fingerprint == "" {
+		return nil, errNoDatabase
+	}
+	path := IndexPath(dir)
+	now := time.Now()
+
+	idx := readIndex(path, dir)
+	if idx != nil && idx.Fingerprint == fingerprint {
+		return idx, nil
+	}
+
+	if idx != nil && now.Sub(idx.RebuiltAt) < indexRebuildAge {
+		since := idx.RefreshedAt.Add(-indexOverlap).UTC().Format(time.RFC3339)
+		if changed, err := b.listAll("--updated-after=" + since); err == nil {
+			for _, issue := range changed {
+				idx.Issues[issue.ID] = issue
+			}
+			idx.Fingerprint = fingerprint
+			idx.RefreshedAt = now
+			writeIndex(path, idx)
+			return idx, nil
+		}
+		// bd without --updated-after: fall through to a full rebuild
+	}
+
+	all, err := b.listAll()
+	if err != nil {
+		return nil, err
+	}
+	idx = &listIndex{
+		Version:     indexVersion,
+		BeadsDir:    dir,
+		Fingerprint: fingerprint,
+		RefreshedAt: now,
+		RebuiltAt:   now,
+		Issues:      make(map[string]*Issue, len(all)),
+	}
+	for _, issue := range all {
+		idx.Issues[issue.ID] = issue
+	}
+	writeIndex(path, idx)
+	return idx, nil
+}
+
+// listAll lists beads of every status, without bd's default result limit.
+func (b *Beads) listAll(extra ...string) ([]*Issue, error) {
+	out, err := b.run(append([]string{"list", "--all", "--json", "--limit=0"}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+	return issues, nil
+}
+
+// readIndex returns the cached index for dir, or nil if there is none or
+// it was written by a different format version.
+func readIndex(path, dir string) *listIndex {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var idx listIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil
+	}
+	if idx.Version != indexVersion || idx.BeadsDir != dir || idx.Issues == nil {
+		return nil
+	}
+	return &idx
+}
+
+// writeIndex caches idx. Best-effort: a failed write only costs the next
+// query a refresh.
+func writeIndex(path string, idx *listIndex) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = util.AtomicWriteJSON(path, idx)
+}
+
+// databaseFingerprint identifies the state of the beads database by the
+// size and modification time of its files. Empty if there are none.
+func databaseFingerprint(dir string) string {
+	var parts []string
+	for _, name := range []string{"beads.db", "beads.db-wal", "issues.jsonl"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", name, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// filter applies opts the way bd list does, ordered by priority, then
+// oldest first.
+func (idx *listIndex) filter(opts ListOptions) []*Issue {
+	label := opts.Label
+	if label == "" && opts.Type != "" {
+		label = "gt:" + opts.Type
+	}
+
+	var issues []*Issue
+	for _, issue := range idx.Issues {
+		switch opts.Status {
+		case "":
+			if issue.Status == "closed" {
+				continue
+			}
+		case "all":
+		default:
+			if issue.Status != opts.Status {
+				continue
+			}
+		}
+		if label != "" && !HasLabel(issue, label) {
+			continue
+		}
+		if opts.Priority >= 0 && issue.Priority != opts.Priority {
+			continue
+		}
+		if opts.Assignee != "" && issue.Assignee != opts.Assignee {
+			continue
+		}
+		if opts.NoAssignee && issue.Assignee != "" {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+		return a.ID < b.ID
+	})
+	return issues
+}
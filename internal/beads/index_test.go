@@ -0,0 +1,135 @@
+//go:build !windows
+
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupIndexBd puts a fake bd on PATH that logs its arguments and prints the
+// contents of full.json, or updated.json for --updated-after queries.
+func setupIndexBd(t *testing.T) (beadsDir, dataDir, logPath string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	binDir := t.TempDir()
+	dataDir = t.TempDir()
+	logPath = filepath.Join(dataDir, "calls.log")
+	script := `#!/bin/sh
+echo "$*" >> "` + logPath + `"
+case "$*" in
+  *--updated-after*) cat "` + filepath.Join(dataDir, "updated.json") + `" ;;
+  *) cat "` + filepath.Join(dataDir, "full.json") + `" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	beadsDir = filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.db"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return beadsDir, dataDir, logPath
+}
+
+func bdCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func ids(issues []*Issue) string {
+	var out []string
+	for _, issue := range issues {
+		out = append(out, issue.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestListIndexed(t *testing.T) {
+	beadsDir, dataDir, logPath := setupIndexBd(t)
+	write := func(name, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("full.json", `[
+		{"id":"gp-1","status":"open","priority":2,"labels":["gt:merge-request"],"created_at":"2026-01-01T00:00:00Z"},
+		{"id":"gp-2","status":"open","priority":1,"labels":["gt:merge-request"],"created_at":"2026-01-02T00:00:00Z"},
+		{"id":"gp-3","status":"closed","priority":1,"labels":["gt:merge-request"]},
+		{"id":"gp-4","status":"open","priority":1,"labels":["gt:task"],"assignee":"greenplace/Toast","created_at":"2026-01-03T00:00:00Z"}
+	]`)
+	b := NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+	mrs := ListOptions{Type: "merge-request", Status: "open", Priority: -1}
+
+	got, err := b.ListIndexed(mrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(got) != "gp-2,gp-1" {
+		t.Errorf("open MRs = %s, want gp-2,gp-1 (priority order)", ids(got))
+	}
+	if calls := bdCalls(t, logPath); len(calls) != 1 || !strings.Contains(calls[0], "list --all --json --limit=0") {
+		t.Fatalf("first query should build the index with a full listing, got %q", calls)
+	}
+
+	// Unchanged database: answered without running bd
+	if got, _ := b.ListIndexed(ListOptions{Priority: -1}); ids(got) != "gp-2,gp-4,gp-1" {
+		t.Errorf("non-closed = %s, want gp-2,gp-4,gp-1", ids(got))
+	}
+	if got, _ := b.ListIndexed(ListOptions{Status: "all", Assignee: "greenplace/Toast", Priority: -1}); ids(got) != "gp-4" {
+		t.Errorf("by assignee = %s, want gp-4", ids(got))
+	}
+	if calls := bdCalls(t, logPath); len(calls) != 1 {
+		t.Fatalf("fresh index should not run bd, got %q", calls)
+	}
+
+	// A write catches the index up with only the changed beads
+	write("updated.json", `[{"id":"gp-1","status":"closed","priority":2,"labels":["gt:merge-request"]}]`)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(beadsDir, "beads.db"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := b.ListIndexed(mrs); ids(got) != "gp-2" {
+		t.Errorf("open MRs after close = %s, want gp-2", ids(got))
+	}
+	calls := bdCalls(t, logPath)
+	if len(calls) != 2 || !strings.Contains(calls[1], "--updated-after=") {
+		t.Errorf("stale index should refresh incrementally, got %q", calls)
+	}
+}
+
+func TestListIndexedFallsBack(t *testing.T) {
+	beadsDir, dataDir, logPath := setupIndexBd(t)
+	if err := os.WriteFile(filepath.Join(dataDir, "full.json"), []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b := NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+
+	t.Setenv(IndexEnv, "0")
+	if _, err := b.ListIndexed(ListOptions{Status: "open", Priority: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if calls := bdCalls(t, logPath); len(calls) != 1 || strings.Contains(calls[0], "list --all") {
+		t.Errorf("disabled index should run a plain bd list, got %q", calls)
+	}
+	if _, err := os.Stat(IndexPath(beadsDir)); !os.IsNotExist(err) {
+		t.Error("disabled index should not be written")
+	}
+}
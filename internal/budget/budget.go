@@ -0,0 +1,301 @@
+// Package budget estimates agent session time from the town event log and
+// enforces per-role session budgets.
+//
+// Session time is derived, not metered: a session runs from its
+// session_start event (emitted by gt prime) until the agent's next
+// session_end, session_death, crash_report, handoff, done, kill, or
+// session_start event, or until now if none has happened yet. Sling, hook,
+// and unhook events split a session so time is attributed to the issue on
+// the agent's hook.
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Agent roles. The mayor, deacon, and boot are town-level and have no rig.
+const (
+	RoleMayor    = "mayor"
+	RoleDeacon   = "deacon"
+	RoleBoot     = "boot"
+	RoleWitness  = "witness"
+	RoleRefinery = "refinery"
+	RolePolecat  = "polecat"
+	RoleCrew     = "crew"
+)
+
+// Agent identifies the owner of a session.
+type Agent struct {
+	Rig  string `json:"rig,omitempty"`
+	Role string `json:"role"`
+	Name string `json:"name,omitempty"` // polecat or crew name
+}
+
+// ParseAgent parses an agent address such as "greenplace/polecats/Toast",
+// "greenplace/crew/max", "greenplace/witness", or "mayor". The short
+// "greenplace/Toast" form used by session hooks is read as a polecat.
+func ParseAgent(addr string) (Agent, bool) {
+	parts := strings.Split(strings.Trim(addr, "/"), "/")
+	switch len(parts) {
+	case 1:
+		switch parts[0] {
+		case RoleMayor, RoleDeacon, RoleBoot:
+			return Agent{Role: parts[0]}, true
+		}
+	case 2:
+		switch parts[1] {
+		case RoleWitness, RoleRefinery:
+			return Agent{Rig: parts[0], Role: parts[1]}, true
+		case "":
+			return Agent{}, false
+		}
+		return Agent{Rig: parts[0], Role: RolePolecat, Name: parts[1]}, true
+	case 3:
+		switch parts[1] {
+		case "polecats":
+			return Agent{Rig: parts[0], Role: RolePolecat, Name: parts[2]}, true
+		case RoleCrew:
+			return Agent{Rig: parts[0], Role: RoleCrew, Name: parts[2]}, true
+		}
+	}
+	return Agent{}, false
+}
+
+// Address returns the canonical address, e.g. "greenplace/polecats/Toast".
+func (a Agent) Address() string {
+	switch a.Role {
+	case RolePolecat:
+		return a.Rig + "/polecats/" + a.Name
+	case RoleCrew:
+		return a.Rig + "/crew/" + a.Name
+	case RoleWitness, RoleRefinery:
+		return a.Rig + "/" + a.Role
+	}
+	return a.Role
+}
+
+// Span is a stretch of one agent's session time.
+type Span struct {
+	Agent Agent     `json:"agent"`
+	Issue string    `json:"issue,omitempty"` // bead on the agent's hook, if known
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Continued is true when the span began at an issue change within a
+	// running session rather than at a session start.
+	Continued bool `json:"continued,omitempty"`
+}
+
+// Duration returns the span's length.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Spans derives session spans from events, closing sessions still open at
+// now. Events after now are ignored.
+func Spans(evts []events.Event, now time.Time) []Span {
+	var spans []Span
+	open := map[string]*Span{}
+	issues := map[string]string{}
+
+	closeSpan := func(addr string, at time.Time) {
+		if s := open[addr]; s != nil {
+			s.End = at
+			if s.End.After(s.Start) {
+				spans = append(spans, *s)
+			}
+			delete(open, addr)
+		}
+	}
+
+	// Writers append concurrently, so order by timestamp first
+	type timed struct {
+		at time.Time
+		e  events.Event
+	}
+	ordered := make([]timed, 0, len(evts))
+	for _, e := range evts {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || at.After(now) {
+			continue
+		}
+		ordered = append(ordered, timed{at, e})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].at.Before(ordered[j].at) })
+
+	for _, te := range ordered {
+		at, e := te.at, te.e
+		agent, ok := eventAgent(e)
+		if !ok {
+			continue
+		}
+		addr := agent.Address()
+
+		switch e.Type {
+		case events.TypeSessionStart:
+			closeSpan(addr, at)
+			open[addr] = &Span{Agent: agent, Issue: issues[addr], Start: at}
+
+		case events.TypeSessionEnd, events.TypeSessionDeath, events.TypeCrashReport,
+			events.TypeHandoff, events.TypeKill:
+			closeSpan(addr, at)
+
+		case events.TypeDone:
+			closeSpan(addr, at)
+			delete(issues, addr)
+
+		case events.TypeSling, events.TypeHook, events.TypeUnhook:
+			issue := payloadString(e, "bead")
+			if e.Type == events.TypeUnhook {
+				issue = ""
+			}
+			issues[addr] = issue
+			if s := open[addr]; s != nil && s.Issue != issue {
+				closeSpan(addr, at)
+				open[addr] = &Span{Agent: agent, Issue: issue, Start: at, Continued: true}
+			}
+		}
+	}
+
+	addrs := make([]string, 0, len(open))
+	for addr := range open {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		closeSpan(addr, now)
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans
+}
+
+// eventAgent returns the agent an event is about: the sling target, the
+// killed polecat, the dead session's agent, or else the actor.
+func eventAgent(e events.Event) (Agent, bool) {
+	switch e.Type {
+	case events.TypeSling:
+		return ParseAgent(payloadString(e, "target"))
+	case events.TypeKill:
+		rig, target := payloadString(e, "rig"), payloadString(e, "target")
+		if rig == "" || target == "" {
+			return Agent{}, false
+		}
+		return Agent{Rig: rig, Role: RolePolecat, Name: target}, true
+	case events.TypeSessionDeath:
+		if agent, ok := ParseAgent(payloadString(e, "agent")); ok {
+			return agent, true
+		}
+	}
+	return ParseAgent(e.Actor)
+}
+
+func payloadString(e events.Event, key string) string {
+	if v, ok := e.Payload[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Clip returns the part of each span inside [from, to), dropping spans
+// entirely outside it.
+func Clip(spans []Span, from, to time.Time) []Span {
+	var clipped []Span
+	for _, s := range spans {
+		if !from.IsZero() && s.Start.Before(from) {
+			s.Start = from
+		}
+		if !to.IsZero() && s.End.After(to) {
+			s.End = to
+		}
+		if s.End.After(s.Start) {
+			clipped = append(clipped, s)
+		}
+	}
+	return clipped
+}
+
+// Usage is a role's consumption against its budget.
+type Usage struct {
+	SessionHoursToday float64 `json:"session_hours_today"`
+	RespawnsLastHour  int     `json:"respawns_last_hour"`
+}
+
+// UsageFor sums the session time since local midnight and the session
+// starts in the last hour for role in rig.
+func UsageFor(spans []Span, rig, role string, now time.Time) Usage {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	hourAgo := now.Add(-time.Hour)
+
+	var u Usage
+	var total time.Duration
+	for _, s := range spans {
+		if s.Agent.Rig != rig || s.Agent.Role != role {
+			continue
+		}
+		if !s.Continued && !s.Start.Before(hourAgo) {
+			u.RespawnsLastHour++
+		}
+		for _, c := range Clip([]Span{s}, midnight, now) {
+			total += c.Duration()
+		}
+	}
+	u.SessionHoursToday = total.Hours()
+	return u
+}
+
+// For returns the budget for role in a rig: rig settings override town
+// settings field by field. Either settings value may be nil.
+func For(town *config.TownSettings, rig *config.RigSettings, role string) config.SessionBudget {
+	var b config.SessionBudget
+	if town != nil && town.Budgets[role] != nil {
+		b = *town.Budgets[role]
+	}
+	if rig != nil && rig.Budgets[role] != nil {
+		r := rig.Budgets[role]
+		if r.MaxSessionHoursPerDay != 0 {
+			b.MaxSessionHoursPerDay = r.MaxSessionHoursPerDay
+		}
+		if r.MaxRespawnsPerHour != 0 {
+			b.MaxRespawnsPerHour = r.MaxRespawnsPerHour
+		}
+	}
+	return b
+}
+
+// ExceededError reports a budget that does not allow another session.
+type ExceededError struct {
+	Rig   string
+	Role  string
+	Limit string // "max_session_hours_per_day" or "max_respawns_per_hour"
+	Used  float64
+	Max   float64
+}
+
+func (e *ExceededError) Error() string {
+	scope := e.Role
+	if e.Rig != "" {
+		scope = e.Rig + "/" + e.Role
+	}
+	return fmt.Sprintf("%s budget exceeded: %s used %.4g of %.4g", scope, e.Limit, e.Used, e.Max)
+}
+
+// Check reports whether b allows starting another session for role in rig
+// given usage u. It returns an *ExceededError when it does not.
+func Check(b config.SessionBudget, u Usage, rig, role string) error {
+	if b.MaxSessionHoursPerDay > 0 && u.SessionHoursToday >= b.MaxSessionHoursPerDay {
+		return &ExceededError{Rig: rig, Role: role, Limit: "max_session_hours_per_day",
+			Used: u.SessionHoursToday, Max: b.MaxSessionHoursPerDay}
+	}
+	if b.MaxRespawnsPerHour > 0 && u.RespawnsLastHour >= b.MaxRespawnsPerHour {
+		return &ExceededError{Rig: rig, Role: role, Limit: "max_respawns_per_hour",
+			Used: float64(u.RespawnsLastHour), Max: float64(b.MaxRespawnsPerHour)}
+	}
+	return nil
+}
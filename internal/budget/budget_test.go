@@ -0,0 +1,163 @@
+package budget
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+var t0 = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func ev(minutes int, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{
+		Timestamp: t0.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339),
+		Type:      typ,
+		Actor:     actor,
+		Payload:   payload,
+	}
+}
+
+func TestParseAgent(t *testing.T) {
+	tests := map[string]Agent{
+		"greenplace/polecats/Toast": {Rig: "greenplace", Role: RolePolecat, Name: "Toast"},
+		"greenplace/Toast":          {Rig: "greenplace", Role: RolePolecat, Name: "Toast"},
+		"greenplace/crew/max":       {Rig: "greenplace", Role: RoleCrew, Name: "max"},
+		"greenplace/witness":        {Rig: "greenplace", Role: RoleWitness},
+		"mayor":                     {Role: RoleMayor},
+	}
+	for addr, want := range tests {
+		got, ok := ParseAgent(addr)
+		if !ok || got != want {
+			t.Errorf("ParseAgent(%q) = %+v, %v; want %+v", addr, got, ok, want)
+		}
+	}
+	for _, addr := range []string{"", "gt", "human", "a/b/c/d"} {
+		if _, ok := ParseAgent(addr); ok {
+			t.Errorf("ParseAgent(%q) should fail", addr)
+		}
+	}
+	if got := (Agent{Rig: "greenplace", Role: RolePolecat, Name: "Toast"}).Address(); got != "greenplace/polecats/Toast" {
+		t.Errorf("Address = %q", got)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	toast := "greenplace/polecats/Toast"
+	evts := []events.Event{
+		ev(0, events.TypeSling, "mayor", events.SlingPayload("gp-1", toast)),
+		ev(1, events.TypeSessionStart, toast, nil),
+		ev(31, events.TypeHook, toast, events.HookPayload("gp-2")),
+		ev(61, events.TypeDone, toast, events.DonePayload("gp-2", "polecat/Toast")),
+		ev(0, events.TypeSessionStart, "greenplace/witness", nil),
+		ev(30, events.TypeHandoff, "greenplace/witness", nil),
+		ev(40, events.TypeSessionStart, "greenplace/witness", nil),
+	}
+	spans := Spans(evts, t0.Add(100*time.Minute))
+
+	var toastSpans []Span
+	var witness time.Duration
+	for _, s := range spans {
+		switch s.Agent.Address() {
+		case toast:
+			toastSpans = append(toastSpans, s)
+		case "greenplace/witness":
+			witness += s.Duration()
+		}
+	}
+	if len(toastSpans) != 2 {
+		t.Fatalf("toast spans = %+v, want 2", toastSpans)
+	}
+	if toastSpans[0].Issue != "gp-1" || toastSpans[0].Duration() != 30*time.Minute || toastSpans[0].Continued {
+		t.Errorf("first span = %+v", toastSpans[0])
+	}
+	if toastSpans[1].Issue != "gp-2" || toastSpans[1].Duration() != 30*time.Minute || !toastSpans[1].Continued {
+		t.Errorf("second span = %+v", toastSpans[1])
+	}
+	// 30m before handoff, then 60m still running at now
+	if witness != 90*time.Minute {
+		t.Errorf("witness time = %v, want 90m", witness)
+	}
+}
+
+func TestSpansEndOnDeathAndKill(t *testing.T) {
+	evts := []events.Event{
+		ev(0, events.TypeSessionStart, "greenplace/polecats/Nux", nil),
+		ev(10, events.TypeSessionDeath, "gt-greenplace-Nux", events.SessionDeathPayload("gt-greenplace-Nux", "greenplace/polecats/Nux", "zombie", "doctor")),
+		ev(20, events.TypeSessionStart, "greenplace/polecats/Nux", nil),
+		ev(25, events.TypeKill, "gt", events.KillPayload("greenplace", "Nux", "nuked")),
+	}
+	spans := Spans(evts, t0.Add(time.Hour))
+	if len(spans) != 2 || spans[0].Duration() != 10*time.Minute || spans[1].Duration() != 5*time.Minute {
+		t.Errorf("spans = %+v", spans)
+	}
+}
+
+func TestUsageAndCheck(t *testing.T) {
+	now := t0.Add(3 * time.Hour)
+	var evts []events.Event
+	for i, p := range []string{"Toast", "Nux", "Slit"} {
+		evts = append(evts, ev(150+i*5, events.TypeSessionStart, "greenplace/polecats/"+p, nil))
+	}
+	evts = append(evts, ev(0, events.TypeSessionStart, "otherrig/polecats/Max", nil))
+	spans := Spans(evts, now)
+
+	u := UsageFor(spans, "greenplace", RolePolecat, now)
+	if u.RespawnsLastHour != 3 {
+		t.Errorf("RespawnsLastHour = %d, want 3", u.RespawnsLastHour)
+	}
+	// 30m + 25m + 20m
+	if got := u.SessionHoursToday; got < 1.24 || got > 1.26 {
+		t.Errorf("SessionHoursToday = %v, want 1.25", got)
+	}
+
+	if err := Check(config.SessionBudget{}, u, "greenplace", RolePolecat); err != nil {
+		t.Errorf("empty budget should allow: %v", err)
+	}
+	if err := Check(config.SessionBudget{MaxSessionHoursPerDay: 2, MaxRespawnsPerHour: 5}, u, "greenplace", RolePolecat); err != nil {
+		t.Errorf("budget should allow: %v", err)
+	}
+
+	err := Check(config.SessionBudget{MaxRespawnsPerHour: 3}, u, "greenplace", RolePolecat)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Limit != "max_respawns_per_hour" {
+		t.Errorf("Check respawns = %v", err)
+	}
+	err = Check(config.SessionBudget{MaxSessionHoursPerDay: 1}, u, "greenplace", RolePolecat)
+	if !errors.As(err, &exceeded) || exceeded.Limit != "max_session_hours_per_day" {
+		t.Errorf("Check hours = %v", err)
+	}
+}
+
+func TestUsageCountsSinceMidnight(t *testing.T) {
+	start := time.Date(2026, 3, 1, 22, 0, 0, 0, time.Local)
+	now := time.Date(2026, 3, 2, 1, 0, 0, 0, time.Local)
+	spans := []Span{{Agent: Agent{Rig: "greenplace", Role: RoleWitness}, Start: start, End: now}}
+
+	u := UsageFor(spans, "greenplace", RoleWitness, now)
+	if u.SessionHoursToday != 1 {
+		t.Errorf("SessionHoursToday = %v, want 1 (time before midnight is yesterday's)", u.SessionHoursToday)
+	}
+	if u.RespawnsLastHour != 0 {
+		t.Errorf("RespawnsLastHour = %d, want 0", u.RespawnsLastHour)
+	}
+}
+
+func TestFor(t *testing.T) {
+	town := &config.TownSettings{Budgets: map[string]*config.SessionBudget{
+		RolePolecat: {MaxSessionHoursPerDay: 40, MaxRespawnsPerHour: 10},
+	}}
+	rig := &config.RigSettings{Budgets: map[string]*config.SessionBudget{
+		RolePolecat: {MaxSessionHoursPerDay: 8},
+	}}
+
+	got := For(town, rig, RolePolecat)
+	if got.MaxSessionHoursPerDay != 8 || got.MaxRespawnsPerHour != 10 {
+		t.Errorf("For = %+v, want rig hours and town respawns", got)
+	}
+	if got := For(nil, nil, RoleWitness); got != (config.SessionBudget{}) {
+		t.Errorf("For without settings = %+v, want unlimited", got)
+	}
+}
@@ -0,0 +1,259 @@
+// Package claim leases issues to workers so two of them don't pick up the
+// same bead. A claim assigns the issue and records the lease in its labels
+// (claimed-by:<agent>, claim-expires:<RFC3339>); it lapses at expiry, so a
+// worker that dies mid-task doesn't hold its issue forever.
+package claim
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Label prefixes recording a lease on an issue.
+const (
+	LabelHolder  = "claimed-by:"
+	LabelExpires = "claim-expires:"
+)
+
+// DefaultTTL is how long a claim lasts unless renewed.
+const DefaultTTL = 2 * time.Hour
+
+// lockTimeout bounds the wait for another claim in progress.
+const lockTimeout = 10 * time.Second
+
+// Lease is a worker's claim on an issue.
+type Lease struct {
+	Issue   string    `json:"issue"`
+	Holder  string    `json:"holder"`  // Agent address, e.g. "greenplace/polecats/toast"
+	Expires time.Time `json:"expires"` // Zero for work assigned without a claim
+}
+
+// Live reports whether the lease is in force at now. Assignments without
+// a lease (hooked or in-progress work) don't expire.
+func (l *Lease) Live(now time.Time) bool {
+	return l != nil && (l.Expires.IsZero() || now.Before(l.Expires))
+}
+
+// HeldBy reports whether agent holds the lease. agent may be a full
+// address or a bare worker name (as parsed from a branch).
+func (l *Lease) HeldBy(agent string) bool {
+	if l == nil || agent == "" {
+		return false
+	}
+	agent = strings.TrimSuffix(agent, "/")
+	return l.Holder == agent || path.Base(l.Holder) == agent
+}
+
+// FromIssue returns the lease an issue is under: its claim labels if it has
+// them, otherwise its assignee if the work is hooked or in progress. Returns
+// nil for unclaimed and closed issues. The lease may have expired.
+func FromIssue(issue *beads.Issue) *Lease {
+	if issue == nil || issue.Status == "closed" {
+		return nil
+	}
+	lease := &Lease{Issue: issue.ID}
+	for _, label := range issue.Labels {
+		if holder, ok := strings.CutPrefix(label, LabelHolder); ok {
+			lease.Holder = holder
+		} else if ts, ok := strings.CutPrefix(label, LabelExpires); ok {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				lease.Expires = t
+			}
+		}
+	}
+	if lease.Holder != "" {
+		return lease
+	}
+	if issue.Assignee != "" && (issue.Status == beads.StatusHooked || issue.Status == "in_progress") {
+		return &Lease{Issue: issue.ID, Holder: issue.Assignee}
+	}
+	return nil
+}
+
+// HeldError reports an issue under someone else's live lease.
+type HeldError struct {
+	Lease *Lease
+}
+
+func (e *HeldError) Error() string {
+	if e.Lease.Expires.IsZero() {
+		return fmt.Sprintf("%s is assigned to %s", e.Lease.Issue, e.Lease.Holder)
+	}
+	return fmt.Sprintf("%s is claimed by %s until %s", e.Lease.Issue, e.Lease.Holder,
+		e.Lease.Expires.Local().Format("15:04 Jan 2"))
+}
+
+// Check returns a *HeldError if issue is under a live lease that agent
+// doesn't hold.
+func Check(issue *beads.Issue, agent string, now time.Time) error {
+	if lease := FromIssue(issue); lease.Live(now) && !lease.HeldBy(agent) {
+		return &HeldError{Lease: lease}
+	}
+	return nil
+}
+
+// Store is the beads access claims need. *beads.Beads implements it.
+type Store interface {
+	Show(id string) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+}
+
+// Options control a claim.
+type Options struct {
+	TTL   time.Duration // Lease length (0 = DefaultTTL)
+	Force bool          // Take the issue even from a live lease
+	Now   time.Time     // Defaults to time.Now()
+}
+
+// Claim leases issueID to agent: it becomes the assignee, open work moves to
+// in_progress, and the lease labels are written. Claiming an issue you
+// already hold renews the lease. Claims in a town are serialized with a
+// lock file, so of two workers claiming the same issue one gets a
+// *HeldError.
+func Claim(townRoot string, store Store, issueID, agent string, opts Options) (*Lease, error) {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	var lease *Lease
+	err := withLock(townRoot, func() error {
+		issue, err := store.Show(issueID)
+		if err != nil {
+			return fmt.Errorf("getting issue %s: %w", issueID, err)
+		}
+		if issue.Status == "closed" {
+			return fmt.Errorf("%s is closed", issueID)
+		}
+		if !opts.Force {
+			if err := Check(issue, agent, opts.Now); err != nil {
+				return err
+			}
+		}
+
+		lease = &Lease{Issue: issue.ID, Holder: agent, Expires: opts.Now.Add(opts.TTL).UTC().Truncate(time.Second)}
+		update := beads.UpdateOptions{
+			Assignee:  &agent,
+			AddLabels: []string{LabelHolder + lease.Holder, LabelExpires + lease.Expires.Format(time.RFC3339)},
+		}
+		update.RemoveLabels = staleLabels(issue.Labels, update.AddLabels)
+		if issue.Status == "open" {
+			status := "in_progress"
+			update.Status = &status
+		}
+		return store.Update(issue.ID, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// Release ends agent's claim on issueID. The issue stays assigned; only the
+// lease labels go. Releasing someone else's live claim needs force.
+func Release(townRoot string, store Store, issueID, agent string, force bool) error {
+	return withLock(townRoot, func() error {
+		issue, err := store.Show(issueID)
+		if err != nil {
+			return fmt.Errorf("getting issue %s: %w", issueID, err)
+		}
+		lease := FromIssue(issue)
+		if lease == nil || lease.Expires.IsZero() {
+			return fmt.Errorf("%s is not claimed", issueID)
+		}
+		if !force && lease.Live(time.Now()) && !lease.HeldBy(agent) {
+			return &HeldError{Lease: lease}
+		}
+		return store.Update(issue.ID, beads.UpdateOptions{RemoveLabels: staleLabels(issue.Labels, nil)})
+	})
+}
+
+// Transfer hands issueID from its holder to agent: agent becomes the
+// assignee and the issue keeps its status. A lease moves with it, renewed
+// for opts.TTL; work held without one stays without one. Unlike a claim,
+// a transfer takes the issue from a live lease, so opts.Force is ignored.
+func Transfer(townRoot string, store Store, issueID, agent string, opts Options) (*Lease, error) {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	var lease *Lease
+	err := withLock(townRoot, func() error {
+		issue, err := store.Show(issueID)
+		if err != nil {
+			return fmt.Errorf("getting issue %s: %w", issueID, err)
+		}
+		held := FromIssue(issue)
+		if held == nil {
+			return fmt.Errorf("%s is not claimed, hooked or in progress", issueID)
+		}
+		if held.HeldBy(agent) {
+			return fmt.Errorf("%s is already held by %s", issueID, held.Holder)
+		}
+
+		lease = &Lease{Issue: issue.ID, Holder: agent}
+		update := beads.UpdateOptions{Assignee: &agent}
+		if !held.Expires.IsZero() {
+			lease.Expires = opts.Now.Add(opts.TTL).UTC().Truncate(time.Second)
+			update.AddLabels = []string{LabelHolder + lease.Holder, LabelExpires + lease.Expires.Format(time.RFC3339)}
+			update.RemoveLabels = staleLabels(issue.Labels, update.AddLabels)
+		}
+		return store.Update(issue.ID, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// staleLabels returns the claim labels in labels that aren't in keep.
+func staleLabels(labels, keep []string) []string {
+	var stale []string
+	for _, label := range labels {
+		if !strings.HasPrefix(label, LabelHolder) && !strings.HasPrefix(label, LabelExpires) {
+			continue
+		}
+		kept := false
+		for _, k := range keep {
+			kept = kept || k == label
+		}
+		if !kept {
+			stale = append(stale, label)
+		}
+	}
+	return stale
+}
+
+// withLock runs fn holding the town's claim lock.
+func withLock(townRoot string, fn func() error) error {
+	dir := constants.TownRuntimePath(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, "claims.lock"))
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("locking claims: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("timeout waiting for claims lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
@@ -0,0 +1,239 @@
+package claim
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeStore applies updates to in-memory issues.
+type fakeStore struct {
+	mu     sync.Mutex
+	issues map[string]*beads.Issue
+}
+
+func (f *fakeStore) Show(id string) (*beads.Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue, ok := f.issues[id]
+	if !ok {
+		return nil, beads.ErrNotFound
+	}
+	copied := *issue
+	copied.Labels = slices.Clone(issue.Labels)
+	return &copied, nil
+}
+
+func (f *fakeStore) Update(id string, opts beads.UpdateOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if opts.Assignee != nil {
+		issue.Assignee = *opts.Assignee
+	}
+	if opts.Status != nil {
+		issue.Status = *opts.Status
+	}
+	issue.Labels = slices.DeleteFunc(issue.Labels, func(l string) bool { return slices.Contains(opts.RemoveLabels, l) })
+	for _, l := range opts.AddLabels {
+		if !slices.Contains(issue.Labels, l) {
+			issue.Labels = append(issue.Labels, l)
+		}
+	}
+	return nil
+}
+
+func newStore(issues ...*beads.Issue) *fakeStore {
+	f := &fakeStore{issues: make(map[string]*beads.Issue)}
+	for _, issue := range issues {
+		f.issues[issue.ID] = issue
+	}
+	return f
+}
+
+func TestClaim(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	store := newStore(&beads.Issue{ID: "gp-1", Status: "open", Labels: []string{"bug"}})
+
+	lease, err := Claim(town, store, "gp-1", "greenplace/polecats/toast", Options{TTL: time.Hour, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lease.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expires = %v, want %v", lease.Expires, now.Add(time.Hour))
+	}
+	issue, _ := store.Show("gp-1")
+	if issue.Assignee != "greenplace/polecats/toast" || issue.Status != "in_progress" {
+		t.Errorf("issue = %s, %s; want in_progress for toast", issue.Assignee, issue.Status)
+	}
+
+	// Someone else is refused while the lease is live
+	_, err = Claim(town, store, "gp-1", "greenplace/polecats/nux", Options{Now: now.Add(30 * time.Minute)})
+	var held *HeldError
+	if !errors.As(err, &held) || held.Lease.Holder != "greenplace/polecats/toast" {
+		t.Fatalf("second claim = %v, want HeldError for toast", err)
+	}
+
+	// The holder renews; the old expiry label is replaced
+	if _, err := Claim(town, store, "gp-1", "greenplace/polecats/toast", Options{TTL: time.Hour, Now: now.Add(30 * time.Minute)}); err != nil {
+		t.Fatalf("renewal: %v", err)
+	}
+	issue, _ = store.Show("gp-1")
+	want := []string{"bug", "claimed-by:greenplace/polecats/toast", "claim-expires:2026-03-01T10:30:00Z"}
+	if !slices.Equal(issue.Labels, want) {
+		t.Errorf("labels after renewal = %v, want %v", issue.Labels, want)
+	}
+
+	// Once it lapses, anyone may claim it
+	if _, err := Claim(town, store, "gp-1", "greenplace/polecats/nux", Options{Now: now.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("claim after expiry: %v", err)
+	}
+	if lease := FromIssue(store.issues["gp-1"]); lease.Holder != "greenplace/polecats/nux" {
+		t.Errorf("holder = %q, want nux", lease.Holder)
+	}
+}
+
+func TestClaimRefusesAssignedWork(t *testing.T) {
+	store := newStore(
+		&beads.Issue{ID: "gp-1", Status: beads.StatusHooked, Assignee: "greenplace/polecats/toast"},
+		&beads.Issue{ID: "gp-2", Status: "closed"},
+	)
+	town := t.TempDir()
+
+	if _, err := Claim(town, store, "gp-1", "greenplace/crew/max", Options{}); err == nil {
+		t.Error("claimed work hooked to someone else")
+	}
+	if _, err := Claim(town, store, "gp-1", "greenplace/crew/max", Options{Force: true}); err != nil {
+		t.Errorf("forced claim: %v", err)
+	}
+	if _, err := Claim(town, store, "gp-2", "greenplace/crew/max", Options{}); err == nil {
+		t.Error("claimed a closed issue")
+	}
+}
+
+func TestClaimConcurrent(t *testing.T) {
+	town := t.TempDir()
+	store := newStore(&beads.Issue{ID: "gp-1", Status: "open"})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = Claim(town, store, "gp-1", "greenplace/polecats/p"+string(rune('a'+i)), Options{})
+		}(i)
+	}
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		var held *HeldError
+		switch {
+		case err == nil:
+			won++
+		case !errors.As(err, &held):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d claims won, want exactly 1", won)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	town := t.TempDir()
+	store := newStore(&beads.Issue{ID: "gp-1", Status: "open"})
+	if _, err := Claim(town, store, "gp-1", "greenplace/polecats/toast", Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var held *HeldError
+	if err := Release(town, store, "gp-1", "greenplace/polecats/nux", false); !errors.As(err, &held) {
+		t.Errorf("release by another worker = %v, want HeldError", err)
+	}
+	if err := Release(town, store, "gp-1", "toast", false); err != nil {
+		t.Fatalf("release by holder: %v", err)
+	}
+	issue, _ := store.Show("gp-1")
+	if len(issue.Labels) != 0 || issue.Assignee != "greenplace/polecats/toast" {
+		t.Errorf("after release: labels %v, assignee %q; want no labels, still assigned", issue.Labels, issue.Assignee)
+	}
+	if err := Release(town, store, "gp-1", "toast", false); err == nil {
+		t.Error("released an unclaimed issue")
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	store := newStore(
+		&beads.Issue{ID: "gp-1", Status: "in_progress", Assignee: "greenplace/polecats/toast",
+			Labels: []string{"bug", "claimed-by:greenplace/polecats/toast", "claim-expires:2026-03-01T10:00:00Z"}},
+		&beads.Issue{ID: "gp-2", Status: beads.StatusHooked, Assignee: "greenplace/polecats/toast"},
+		&beads.Issue{ID: "gp-3", Status: "open"},
+	)
+
+	// The lease moves, renewed, even though toast's is live
+	lease, err := Transfer(town, store, "gp-1", "greenplace/polecats/nux", Options{TTL: 2 * time.Hour, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue, _ := store.Show("gp-1")
+	want := []string{"bug", "claimed-by:greenplace/polecats/nux", "claim-expires:2026-03-01T11:00:00Z"}
+	if issue.Assignee != "greenplace/polecats/nux" || issue.Status != "in_progress" || !slices.Equal(issue.Labels, want) {
+		t.Errorf("issue = %s, %s, %v; want in_progress for nux with labels %v", issue.Assignee, issue.Status, issue.Labels, want)
+	}
+	if lease.Holder != "greenplace/polecats/nux" || !lease.Expires.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("lease = %+v", lease)
+	}
+
+	// Hooked work moves without gaining a lease
+	lease, err = Transfer(town, store, "gp-2", "overseer", Options{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue, _ = store.Show("gp-2")
+	if issue.Assignee != "overseer" || issue.Status != beads.StatusHooked || len(issue.Labels) != 0 || !lease.Expires.IsZero() {
+		t.Errorf("issue = %s, %s, %v; lease = %+v", issue.Assignee, issue.Status, issue.Labels, lease)
+	}
+
+	if _, err := Transfer(town, store, "gp-2", "overseer", Options{}); err == nil {
+		t.Error("transferred an issue to its holder")
+	}
+	if _, err := Transfer(town, store, "gp-3", "overseer", Options{}); err == nil {
+		t.Error("transferred unclaimed work")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	claimed := &beads.Issue{ID: "gp-1", Status: "in_progress", Assignee: "greenplace/polecats/toast",
+		Labels: []string{"claimed-by:greenplace/polecats/toast", "claim-expires:2026-03-01T10:00:00Z"}}
+
+	tests := []struct {
+		name    string
+		issue   *beads.Issue
+		agent   string
+		at      time.Time
+		wantErr bool
+	}{
+		{"holder", claimed, "greenplace/polecats/toast", now, false},
+		{"holder by worker name", claimed, "toast", now, false},
+		{"other worker", claimed, "nux", now, true},
+		{"expired", claimed, "nux", now.Add(2 * time.Hour), false},
+		{"unclaimed", &beads.Issue{ID: "gp-2", Status: "open"}, "nux", now, false},
+		{"in progress without claim", &beads.Issue{ID: "gp-3", Status: "in_progress", Assignee: "greenplace/crew/max"}, "nux", now, true},
+		{"closed", &beads.Issue{ID: "gp-4", Status: "closed", Labels: claimed.Labels}, "nux", now, false},
+	}
+	for _, tt := range tests {
+		if err := Check(tt.issue, tt.agent, tt.at); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Check = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -172,40 +172,35 @@ func runAccountAdd(cmd *cobra.Command, args []string) error {
 
 	accountsPath := constants.MayorAccountsPath(townRoot)
 
-	// Load existing config or create new
-	cfg, err := config.LoadAccountsConfig(accountsPath)
-	if err != nil {
-		cfg = config.NewAccountsConfig()
-	}
-
-	// Check if account already exists
-	if _, exists := cfg.Accounts[handle]; exists {
-		return fmt.Errorf("account '%s' already exists", handle)
-	}
-
 	// Build config directory path
 	configDir := config.DefaultAccountsConfigDir() + "/" + handle
 
-	// Create the config directory
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("creating config directory: %w", err)
-	}
+	_, err = config.AccountsStore(accountsPath).Update(func(cfg *config.AccountsConfig) error {
+		// Check if account already exists
+		if _, exists := cfg.Accounts[handle]; exists {
+			return fmt.Errorf("account '%s' already exists", handle)
+		}
 
-	// Add account
-	cfg.Accounts[handle] = config.Account{
-		Email:       accountEmail,
-		Description: accountDescription,
-		ConfigDir:   configDir,
-	}
+		// Create the config directory
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return fmt.Errorf("creating config directory: %w", err)
+		}
 
-	// If this is the first account, make it default
-	if cfg.Default == "" {
-		cfg.Default = handle
-	}
+		// Add account
+		cfg.Accounts[handle] = config.Account{
+			Email:       accountEmail,
+			Description: accountDescription,
+			ConfigDir:   configDir,
+		}
 
-	// Save config
-	if err := config.SaveAccountsConfig(accountsPath, cfg); err != nil {
-		return fmt.Errorf("saving accounts config: %w", err)
+		// If this is the first account, make it default
+		if cfg.Default == "" {
+			cfg.Default = handle
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Added account '%s'\n", handle)
@@ -227,22 +222,22 @@ func runAccountDefault(cmd *cobra.Command, args []string) error {
 	}
 
 	accountsPath := constants.MayorAccountsPath(townRoot)
-	cfg, err := config.LoadAccountsConfig(accountsPath)
-	if err != nil {
+	if _, err := config.LoadAccountsConfig(accountsPath); err != nil {
 		return fmt.Errorf("loading accounts config: %w", err)
 	}
 
-	// Check if account exists
-	if _, exists := cfg.Accounts[handle]; !exists {
-		return fmt.Errorf("account '%s' not found", handle)
-	}
-
-	// Update default
-	cfg.Default = handle
+	_, err = config.AccountsStore(accountsPath).Update(func(cfg *config.AccountsConfig) error {
+		// Check if account exists
+		if _, exists := cfg.Accounts[handle]; !exists {
+			return fmt.Errorf("account '%s' not found", handle)
+		}
 
-	// Save config
-	if err := config.SaveAccountsConfig(accountsPath, cfg); err != nil {
-		return fmt.Errorf("saving accounts config: %w", err)
+		// Update default
+		cfg.Default = handle
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Default account set to '%s'\n", handle)
@@ -440,8 +435,11 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Update default account
-	cfg.Default = targetHandle
-	if err := config.SaveAccountsConfig(accountsPath, cfg); err != nil {
+	_, err = config.AccountsStore(accountsPath).Update(func(cfg *config.AccountsConfig) error {
+		cfg.Default = targetHandle
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving accounts config: %w", err)
 	}
 
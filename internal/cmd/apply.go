@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/manifest"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Apply command flags
+var applyDryRun bool
+
+var applyCmd = &cobra.Command{
+	Use:     "apply <manifest>",
+	GroupID: GroupWorkspace,
+	Short:   "Reconcile the town with a declarative manifest",
+	Long: `Bring the town in line with a manifest (town.yaml) declaring its rigs,
+their refinery settings and polecat counts, and escalation routing.
+
+  templates:
+    go-service:
+      polecats: 4
+      merge_queue:
+        test_command: go test ./...
+  rigs:
+    greenplace:
+      git_url: https://github.com/acme/greenplace
+      prefix: gp
+      template: go-service
+      merge_queue:
+        protection:
+          min_approvals: 1
+  escalation:
+    contacts:
+      slack_webhook: secret:slack
+
+A rig takes its template's settings, overridden key by key by its own.
+merge_queue and escalation use the keys of the rig's settings/config.json
+and settings/escalation.json; anything left out gets the default.
+
+Missing rigs are cloned as by 'gt rig add'. A rig's merge_queue settings,
+max_polecats (set in the wisp layer, as by 'gt rig config set'), and the
+town's escalation config are rewritten where they differ. Drift apply
+won't fix is reported and left alone: a rig cloned from a different URL,
+and rigs the manifest doesn't list. Running it again changes nothing.
+
+With --dry-run, nothing changes: the command lists the differences and
+exits with code 8 if there are any.
+
+Examples:
+  gt apply town.yaml
+  gt apply town.yaml --dry-run
+  gt apply town.yaml --dry-run -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Report differences without changing anything")
+
+	rootCmd.AddCommand(applyCmd)
+}
+
+// ApplyOutput is the structured output for gt apply.
+type ApplyOutput struct {
+	Manifest string            `json:"manifest"`
+	DryRun   bool              `json:"dry_run"`
+	Changes  []manifest.Change `json:"changes"`
+	Applied  int               `json:"applied"`
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	m, err := manifest.Load(args[0])
+	if err != nil {
+		return err
+	}
+	changes, err := m.Plan(townRoot)
+	if err != nil {
+		return err
+	}
+	out := ApplyOutput{Manifest: args[0], DryRun: applyDryRun, Changes: changes}
+	if out.Changes == nil {
+		out.Changes = []manifest.Change{}
+	}
+
+	if !structuredOutput(false) {
+		printApplyPlan(out)
+	}
+	if !applyDryRun {
+		if out.Applied, err = applyChanges(townRoot, m, changes); err != nil {
+			return err
+		}
+	}
+
+	if structuredOutput(false) {
+		if err := renderStructured(out); err != nil {
+			return err
+		}
+	} else if !applyDryRun && out.Applied > 0 {
+		fmt.Printf("\n%s Applied %d change(s)\n", style.Success.Render("✓"), out.Applied)
+	}
+	if applyDryRun && len(changes) > 0 {
+		return NewSilentExit(ExitCheckFailed)
+	}
+	return nil
+}
+
+// applyChanges makes the plan's creates and updates, in order, and returns
+// how many it made. Drift is left alone.
+func applyChanges(townRoot string, m *manifest.Manifest, changes []manifest.Change) (int, error) {
+	// Cloning narrates on stdout, which structured output owns
+	if structuredOutput(false) {
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
+	for _, c := range changes {
+		if c.Action == manifest.ActionCreate {
+			if err := deps.EnsureBeads(true); err != nil {
+				return 0, fmt.Errorf("beads dependency check failed: %w", err)
+			}
+			break
+		}
+	}
+
+	applied := 0
+	for _, c := range changes {
+		switch c.Action {
+		case manifest.ActionCreate:
+			spec := m.Rig(c.Rig)
+			fmt.Printf("\nCreating rig %s...\n", style.Bold.Render(spec.Name))
+			if _, err := addRigToTown(townRoot, rig.AddRigOptions{
+				Name:          spec.Name,
+				GitURL:        spec.GitURL,
+				BeadsPrefix:   spec.Prefix,
+				DefaultBranch: spec.Branch,
+			}); err != nil {
+				return applied, fmt.Errorf("rig %s: %w", spec.Name, err)
+			}
+		case manifest.ActionUpdate:
+			if err := m.Apply(townRoot, c); err != nil {
+				return applied, err
+			}
+		default:
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func printApplyPlan(out ApplyOutput) {
+	verb := "Applying"
+	if out.DryRun {
+		verb = "Checking"
+	}
+	fmt.Printf("%s %s %s:\n\n", style.Bold.Render("🏗"), verb, out.Manifest)
+	if len(out.Changes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(town matches the manifest)"))
+		return
+	}
+	for _, c := range out.Changes {
+		var mark string
+		switch c.Action {
+		case manifest.ActionCreate:
+			mark = style.Success.Render("+")
+		case manifest.ActionUpdate:
+			mark = style.Warning.Render("~")
+		default:
+			mark = style.Dim.Render("!")
+		}
+		target := c.Subject
+		if c.Rig != "" && c.Subject != manifest.SubjectRig {
+			target = c.Rig + " " + c.Subject
+		} else if c.Rig != "" {
+			target = c.Rig
+		}
+		fmt.Printf("  %s %-28s %s\n", mark, target, style.Dim.Render(c.Detail))
+	}
+}
@@ -40,6 +40,9 @@ Shows a unified timeline of work performed by an actor including:
   - Town log events (spawn, done, handoff, etc.)
   - Activity feed events
 
+For the tamper-evident record of state-changing commands, see
+'gt audit list' and 'gt audit verify'.
+
 Examples:
   gt audit --actor=greenplace/crew/joe       # Show all work by joe
   gt audit --actor=greenplace/polecats/toast # Show polecat toast's work
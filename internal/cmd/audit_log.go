@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Audit log subcommand flags
+var (
+	auditListSince   string
+	auditListActor   string
+	auditListCommand string
+	auditListLimit   int
+)
+
+// auditReadOnlyCommands are commands that never change town state and are
+// not recorded in the audit log. A command is skipped if it or any parent
+// is listed.
+var auditReadOnlyCommands = map[string]bool{
+	"activity":     true,
+	"audit":        true,
+	"blocked":      true,
+	"capture":      true,
+	"cat":          true,
+	"changelog":    true,
+	"check":        true,
+	"commits":      true,
+	"completion":   true,
+	"conflicts":    true,
+	"current":      true,
+	"dag":          true,
+	"dashboard":    true,
+	"diff":         true,
+	"env":          true,
+	"feed":         true,
+	"git-state":    true,
+	"guard":        true, // Runs on every agent tool call
+	"health-state": true,
+	"heartbeat":    true, // Periodic liveness touch
+	"help":         true,
+	"history":      true,
+	"inbox":        true,
+	"info":         true,
+	"list":         true,
+	"logs":         true,
+	"peek":         true,
+	"prime":        true,
+	"procs":        true,
+	"read":         true,
+	"ready":        true,
+	"schema":       true,
+	"search":       true,
+	"show":         true,
+	"stale":        true,
+	"stats":        true,
+	"status":       true,
+	"status-line":  true, // Polled by the tmux status bar
+	"stranded":     true,
+	"subscribers":  true,
+	"tail":         true,
+	"themes":       true,
+	"time":         true,
+	"trail":        true,
+	"unclaimed":    true,
+	"version":      true,
+	"whoami":       true,
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded state-changing commands",
+	Long: `List the town's audit log: every state-changing gt command, who ran
+it (agent address, or "human" plus the OS user), its arguments, exit code,
+and error.
+
+Read-only commands (list, show, status, ...) and --dry-run invocations are
+not recorded. The log is at ~/gt/logs/audit.jsonl; see 'gt audit verify'.
+
+Examples:
+  gt audit list --since 24h
+  gt audit list --command "mq reject"       # Who rejected my MR?
+  gt audit list --actor greenplace/polecats/Toast
+  gt audit list --since 7d -o json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditList,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the audit log's hash chain for tampering",
+	Long: `Verify the audit log's hash chain.
+
+Each record stores the SHA-256 hash of the record before it, so editing,
+deleting, or reordering records breaks the chain. Exits non-zero and names
+the first broken record if the log was altered.
+
+Examples:
+  gt audit verify`,
+	Args: cobra.NoArgs,
+	RunE: runAuditVerify,
+}
+
+func init() {
+	auditListCmd.Flags().StringVar(&auditListSince, "since", "", "Show records since duration (e.g., 1h, 24h, 7d)")
+	auditListCmd.Flags().StringVar(&auditListActor, "actor", "", "Filter by actor (agent address or partial match)")
+	auditListCmd.Flags().StringVar(&auditListCommand, "command", "", "Filter by command (e.g., \"mq reject\")")
+	auditListCmd.Flags().IntVarP(&auditListLimit, "limit", "n", 50, "Maximum number of records to show (0 for all)")
+
+	auditCmd.AddCommand(auditListCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+}
+
+// recordInvocation appends a finished command to the town audit log unless
+// it is read-only, a help request, a dry run, or outside a town. Failures
+// are warnings: auditing never changes a command's outcome.
+func recordInvocation(cmd *cobra.Command, args []string, started time.Time, exitCode int, runErr error) {
+	if cmd == nil || !auditable(cmd) {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	info, actor, err := callerIdentity(townRoot)
+	if err != nil {
+		actor = os.Getenv(EnvGTRole)
+	}
+	rec := auditlog.Record{
+		Timestamp:  started,
+		Actor:      actor,
+		Role:       string(info.Role),
+		Command:    strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Args:       args,
+		ExitCode:   exitCode,
+		DurationMS: time.Since(started).Milliseconds(),
+	}
+	if u, err := user.Current(); err == nil {
+		rec.User = u.Username
+	}
+	var silent *SilentExitError
+	if runErr != nil && !errors.As(runErr, &silent) {
+		rec.Error = runErr.Error()
+	}
+
+	if _, err := auditlog.Append(townRoot, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log: %v\n", err)
+	}
+}
+
+// auditable reports whether an executed command should be recorded.
+func auditable(cmd *cobra.Command) bool {
+	if !cmd.Runnable() {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if auditReadOnlyCommands[c.Name()] {
+			return false
+		}
+	}
+	for _, name := range []string{"help", "dry-run"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed && f.Value.String() == "true" {
+			return false
+		}
+	}
+	return true
+}
+
+func runAuditList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if auditListSince != "" {
+		d, err := parseDuration(auditListSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	records, err := auditlog.ReadAll(townRoot)
+	if err != nil {
+		return err
+	}
+
+	filtered := []auditlog.Record{}
+	for _, r := range records {
+		if !since.IsZero() && r.Timestamp.Before(since) {
+			continue
+		}
+		if auditListActor != "" && !strings.Contains(r.Actor, auditListActor) && r.User != auditListActor {
+			continue
+		}
+		if auditListCommand != "" && r.Command != auditListCommand && !strings.HasPrefix(r.Command, auditListCommand+" ") {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	if auditListLimit > 0 && len(filtered) > auditListLimit {
+		filtered = filtered[len(filtered)-auditListLimit:]
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(filtered)
+	}
+
+	if len(filtered) == 0 {
+		fmt.Printf("%s No audit records found\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range filtered {
+		result := style.Success.Render("ok")
+		if r.ExitCode != 0 {
+			result = style.Error.Render(fmt.Sprintf("exit %d", r.ExitCode))
+		}
+		who := r.Actor
+		if r.User != "" {
+			who += style.Dim.Render(" (" + r.User + ")")
+		}
+		fmt.Printf("%s %s %s  gt %s\n",
+			style.Dim.Render(r.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			result, who, strings.Join(r.Args, " "))
+		if r.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(r.Error))
+		}
+	}
+	return nil
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	records, err := auditlog.ReadAll(townRoot)
+	if err == nil {
+		err = auditlog.Verify(records)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", auditlog.Path(townRoot), err)
+	}
+
+	fmt.Printf("%s Audit log intact (%d records)\n", style.Success.Render("✓"), len(records))
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+)
+
+// auditTestTree builds "gt mq <leaf>" and returns the leaf command.
+func auditTestTree(leaf string) *cobra.Command {
+	root := &cobra.Command{Use: "gt"}
+	mq := &cobra.Command{Use: "mq"}
+	cmd := &cobra.Command{Use: leaf, RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().Bool("dry-run", false, "")
+	root.AddCommand(mq)
+	mq.AddCommand(cmd)
+	return cmd
+}
+
+func TestAuditable(t *testing.T) {
+	if !auditable(auditTestTree("reject")) {
+		t.Error("mq reject should be audited")
+	}
+	if auditable(auditTestTree("list")) {
+		t.Error("mq list is read-only and should not be audited")
+	}
+
+	dryRun := auditTestTree("reject")
+	_ = dryRun.Flags().Set("dry-run", "true")
+	if auditable(dryRun) {
+		t.Error("--dry-run should not be audited")
+	}
+
+	parent := auditTestTree("reject").Parent()
+	if auditable(parent) {
+		t.Error("a parent without Run should not be audited")
+	}
+}
+
+func TestRecordInvocation(t *testing.T) {
+	t.Setenv(EnvGTRole, "")
+	townRoot := setupPermissionTown(t, nil)
+
+	runErr := errors.New("rejecting MR: not found")
+	recordInvocation(auditTestTree("reject"), []string{"mq", "reject", "greenplace", "gp-1"}, time.Now(), ExitMRNotFound, runErr)
+	recordInvocation(auditTestTree("list"), []string{"mq", "list", "greenplace"}, time.Now(), ExitOK, nil)
+
+	records, err := auditlog.ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1 (read-only commands are skipped)", len(records))
+	}
+	r := records[0]
+	if r.Command != "mq reject" || r.Actor != "human" || r.ExitCode != ExitMRNotFound || r.Error != runErr.Error() {
+		t.Errorf("record = %+v", r)
+	}
+	if len(r.Args) != 4 || r.Args[3] != "gp-1" {
+		t.Errorf("Args = %v", r.Args)
+	}
+}
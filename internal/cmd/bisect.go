@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bisect command flags
+var (
+	bisectBad     string
+	bisectGood    string
+	bisectCommand string
+	bisectFileBug bool
+)
+
+var bisectCmd = &cobra.Command{
+	Use:     "bisect <rig>",
+	GroupID: GroupWork,
+	Short:   "Find the MR that introduced a regression",
+	Long: `Run git bisect in a rig and report the culprit as a merge request.
+
+Every change lands through the merge queue, so the first bad commit is
+traced back to the MR that merged it, along with the worker and the source
+issue. The bisect runs in a temporary worktree of the refinery's clone;
+nothing in the rig is checked out or changed.
+
+--cmd is run with sh -c at each step, from the repo root. Exit 0 marks the
+commit good, 125 skips it, and any other code marks it bad (as with
+'git bisect run').
+
+With --file-bug, a bug bead describing the regression is created and
+assigned to the worker whose MR introduced it, and the worker is mailed.
+
+Examples:
+  gt bisect greenplace --good v1.4.0 --bad origin/main --cmd "make test"
+  gt bisect greenplace --good 1a2b3c4 --bad 5d6e7f8 --cmd "go test ./internal/auth/..."
+  gt bisect greenplace --good v1.4.0 --bad origin/main --cmd "./repro.sh" --file-bug
+  gt bisect greenplace --good v1.4.0 --bad origin/main --cmd "make test" -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBisect,
+}
+
+func init() {
+	bisectCmd.Flags().StringVar(&bisectBad, "bad", "", "A commit or ref where the regression is present (required)")
+	bisectCmd.Flags().StringVar(&bisectGood, "good", "", "An earlier commit or ref where it is not (required)")
+	bisectCmd.Flags().StringVar(&bisectCommand, "cmd", "", "Command that fails on bad commits, run via sh -c (required)")
+	bisectCmd.Flags().BoolVar(&bisectFileBug, "file-bug", false, "Open a bug bead assigned to the culprit MR's worker")
+	_ = bisectCmd.MarkFlagRequired("bad")
+	_ = bisectCmd.MarkFlagRequired("good")
+	_ = bisectCmd.MarkFlagRequired("cmd")
+
+	rootCmd.AddCommand(bisectCmd)
+}
+
+// BisectOutput is the structured output for gt bisect.
+type BisectOutput struct {
+	Rig         string `json:"rig"`
+	Good        string `json:"good"`
+	Bad         string `json:"bad"`
+	Command     string `json:"command"`
+	Culprit     string `json:"culprit"`
+	Subject     string `json:"subject"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	MR          string `json:"mr,omitempty"`
+	SourceIssue string `json:"source_issue,omitempty"`
+	SourceTitle string `json:"source_title,omitempty"`
+	Worker      string `json:"worker,omitempty"`
+	Bug         string `json:"bug,omitempty"`
+}
+
+func runBisect(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	bd := beads.New(r.BeadsPath())
+
+	if !structuredOutput(false) {
+		fmt.Printf("Bisecting %s..%s in %s with %q...\n", bisectGood, bisectBad, rigName, bisectCommand)
+	}
+	result, err := eng.Bisect(bisectGood, bisectBad, bisectCommand)
+	if err != nil {
+		return err
+	}
+
+	out := BisectOutput{
+		Rig:         rigName,
+		Good:        bisectGood,
+		Bad:         bisectBad,
+		Command:     bisectCommand,
+		Culprit:     result.Culprit,
+		Subject:     result.Subject,
+		MergeCommit: result.MergeCommit,
+	}
+
+	// Trace the merge commit back to the MR, worker, and source issue
+	var fields *beads.MRFields
+	if result.MergeCommit != "" {
+		mr, err := findMRByMergeCommit(bd, result.MergeCommit)
+		if err != nil {
+			return err
+		}
+		if mr != nil {
+			fields = beads.ParseMRFields(mr)
+			out.MR = mr.ID
+			out.SourceIssue = fields.SourceIssue
+			out.Worker = fields.Worker
+			if fields.SourceIssue != "" {
+				if src, err := bd.Show(fields.SourceIssue); err == nil {
+					out.SourceTitle = src.Title
+				}
+			}
+		}
+	}
+
+	if bisectFileBug {
+		bug, err := fileRegressionBug(bd, rigName, out)
+		if err != nil {
+			return err
+		}
+		out.Bug = bug
+		if fields != nil {
+			notifyMRWorker(fields, detectSender(), fmt.Sprintf("Regression from %s: %s", out.MR, bug),
+				fmt.Sprintf("Bisect traced a regression to your merge %s (%s).\n\nCommand: %s\nCulprit: %s %s\n\nBug: %s",
+					out.MR, out.SourceIssue, out.Command, shortSHA(out.Culprit), out.Subject, bug))
+		}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printBisectResult(out)
+	return nil
+}
+
+// fileRegressionBug creates a bug bead for a bisected regression, assigned
+// to the culprit MR's worker if known. Returns the bug's ID.
+func fileRegressionBug(bd *beads.Beads, rigName string, out BisectOutput) (string, error) {
+	what := out.SourceTitle
+	if what == "" {
+		what = out.Subject
+	}
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "Regression found by gt bisect: %s fails from %s.\n\n", out.Command, shortSHA(out.Culprit))
+	fmt.Fprintf(&desc, "Culprit: %s %s\n", out.Culprit, out.Subject)
+	if out.MR != "" {
+		fmt.Fprintf(&desc, "MR: %s\n", out.MR)
+	}
+	if out.SourceIssue != "" {
+		fmt.Fprintf(&desc, "Source issue: %s\n", out.SourceIssue)
+	}
+	fmt.Fprintf(&desc, "Good: %s\nBad: %s\n", out.Good, out.Bad)
+
+	bug, err := bd.Create(beads.CreateOptions{
+		Title:       "Regression: " + what,
+		Type:        "bug",
+		Priority:    1,
+		Description: desc.String(),
+		Actor:       detectSender(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating bug bead: %w", err)
+	}
+	if out.Worker != "" {
+		assignee := fmt.Sprintf("%s/polecats/%s", rigName, out.Worker)
+		if err := bd.Update(bug.ID, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+			style.PrintWarning("could not assign %s to %s: %v", bug.ID, assignee, err)
+		}
+	}
+	return bug.ID, nil
+}
+
+func printBisectResult(out BisectOutput) {
+	fmt.Printf("\n%s First bad commit: %s %s\n", style.Bold.Render("✗"), shortSHA(out.Culprit), out.Subject)
+	if out.MR == "" {
+		if out.MergeCommit != "" && out.MergeCommit != out.Culprit {
+			fmt.Printf("  Merged in: %s\n", shortSHA(out.MergeCommit))
+		}
+		fmt.Printf("  %s\n", style.Dim.Render("(no merge request found for this commit; it may predate the queue)"))
+	} else {
+		fmt.Printf("  MR:     %s (merged in %s)\n", style.Bold.Render(out.MR), shortSHA(out.MergeCommit))
+		if out.SourceIssue != "" {
+			if out.SourceTitle != "" {
+				fmt.Printf("  Issue:  %s %s\n", out.SourceIssue, style.Dim.Render(out.SourceTitle))
+			} else {
+				fmt.Printf("  Issue:  %s\n", out.SourceIssue)
+			}
+		}
+		if out.Worker != "" {
+			fmt.Printf("  Worker: %s\n", out.Worker)
+		}
+		fmt.Printf("\n  Revert it with: gt mq revert %s --rig %s\n", out.MR, out.Rig)
+	}
+	if out.Bug != "" {
+		fmt.Printf("\n%s Filed %s\n", style.Success.Render("✓"), style.Bold.Render(out.Bug))
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Branch command flags
+var (
+	branchNewName   string
+	branchNewFrom   string
+	branchNewDryRun bool
+)
+
+var branchCmd = &cobra.Command{
+	Use:     "branch",
+	GroupID: GroupWork,
+	Short:   "Work branches named by the rig's branch policy",
+	RunE:    requireSubcommand,
+	Long: `Create work branches named by the rig's branch policy.
+
+A rig's work branches are named from its polecat_branch_template (see
+'gt rig config'), or polecat/<worker>/<issue>@<timestamp> if none is set.
+gt done and gt mq submit read the issue and worker back out of the branch
+name with the same template, so a branch made here always parses.`,
+}
+
+var branchNewCmd = &cobra.Command{
+	Use:   "new <issue>",
+	Short: "Create and check out a correctly named work branch for an issue",
+	Long: `Create a work branch for an issue, named by the rig's branch policy,
+and check it out.
+
+The name is checked against the policy before anything is created: if the
+rig's template can't carry the issue and worker back to gt done and
+gt mq submit, the command fails rather than make a branch they can't read.
+
+The worker defaults to $GT_POLECAT or $GT_CREW, otherwise the last part of
+your agent identity.
+
+Examples:
+  gt branch new gt-abc
+  gt branch new gt-abc --from origin/main
+  gt branch new gt-abc --name max --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBranchNew,
+}
+
+func init() {
+	branchNewCmd.Flags().StringVar(&branchNewName, "name", "", "Worker name for the branch (default: current agent)")
+	branchNewCmd.Flags().StringVar(&branchNewFrom, "from", "HEAD", "Ref to branch from")
+	branchNewCmd.Flags().BoolVarP(&branchNewDryRun, "dry-run", "n", false, "Print the branch name without creating it")
+
+	branchCmd.AddCommand(branchNewCmd)
+	rootCmd.AddCommand(branchCmd)
+}
+
+func runBranchNew(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := findCurrentRig(townRoot)
+	if err != nil {
+		return err
+	}
+	policy, err := r.BranchPolicy()
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	g := git.NewGit(cwd)
+
+	issue, err := beads.New(cwd).Show(issueID)
+	if err != nil {
+		return fmt.Errorf("getting issue %s: %w", issueID, err)
+	}
+
+	worker := branchNewName
+	if worker == "" {
+		worker = branchWorker()
+	}
+	if worker == "" && policy.Uses("name") {
+		return fmt.Errorf("cannot determine worker name; use --name to specify")
+	}
+
+	vars := rig.BranchVars{Name: worker, Issue: issue.ID, Title: issue.Title, Now: time.Now()}
+	if policy.Uses("user") {
+		vars.User, _ = g.ConfigGet("user.name")
+	}
+	branch := policy.Name(vars)
+
+	// Refuse a name gt done and gt mq submit would read differently
+	parsed, ok := policy.Parse(branch)
+	if !ok || parsed.Issue != issue.ID || (policy.Uses("name") && parsed.Worker != worker) {
+		return fmt.Errorf("branch template %q names this branch %q, which parses as issue %q, worker %q; "+
+			"the template needs {id} (or {issue}) and {name} set apart by separators",
+			policy.Template(), branch, parsed.Issue, parsed.Worker)
+	}
+
+	if branchNewDryRun {
+		fmt.Println(branch)
+		return nil
+	}
+
+	exists, err := g.BranchExists(branch)
+	if err != nil {
+		return fmt.Errorf("checking branch %s: %w", branch, err)
+	}
+	if exists {
+		return fmt.Errorf("branch %s already exists", branch)
+	}
+	if err := g.CreateBranchFrom(branch, branchNewFrom); err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	if err := g.Checkout(branch); err != nil {
+		return fmt.Errorf("checking out %s: %w", branch, err)
+	}
+
+	fmt.Printf("%s Created %s\n", style.Bold.Render("✓"), branch)
+	return nil
+}
+
+// branchWorker returns the current agent's worker name: the polecat or crew
+// member from the environment, else the last part of its identity.
+func branchWorker() string {
+	if name := os.Getenv("GT_POLECAT"); name != "" {
+		return name
+	}
+	if name := os.Getenv("GT_CREW"); name != "" {
+		return name
+	}
+	if sender := detectSender(); sender != "" {
+		return path.Base(sender)
+	}
+	return ""
+}
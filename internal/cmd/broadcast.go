@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,14 +13,17 @@ import (
 
 var (
 	broadcastRig    string
+	broadcastRole   string
 	broadcastAll    bool
 	broadcastDryRun bool
 )
 
 func init() {
 	broadcastCmd.Flags().StringVar(&broadcastRig, "rig", "", "Only broadcast to workers in this rig")
-	broadcastCmd.Flags().BoolVar(&broadcastAll, "all", false, "Include all agents (mayor, witness, etc.), not just workers")
+	broadcastCmd.Flags().StringVar(&broadcastRole, "role", "worker", "Roles to broadcast to: polecat, crew, worker, witness, refinery, mayor, deacon, or all (comma-separated)")
+	broadcastCmd.Flags().BoolVar(&broadcastAll, "all", false, "Include all agents (mayor, witness, etc.), not just workers (same as --role all)")
 	broadcastCmd.Flags().BoolVar(&broadcastDryRun, "dry-run", false, "Show what would be sent without sending")
+	broadcastCmd.MarkFlagsMutuallyExclusive("role", "all")
 	rootCmd.AddCommand(broadcastCmd)
 }
 
@@ -30,13 +34,17 @@ var broadcastCmd = &cobra.Command{
 	Long: `Broadcasts a message to all active workers (polecats and crew).
 
 By default, only workers (polecats and crew) receive the message.
-Use --all to include infrastructure agents (mayor, deacon, witness, refinery).
+Use --role to pick roles: polecat, crew, worker (polecats and crew),
+witness, refinery, mayor, deacon, or all. Separate several with commas.
+--all is shorthand for --role all.
 
 The message is sent as a nudge to each worker's Claude Code session.
 
 Examples:
   gt broadcast "Check your mail"
+  gt broadcast --role polecat "Merge freeze at 5pm, land your work"
   gt broadcast --rig greenplace "New priority work available"
+  gt broadcast --role witness,refinery "Restarting the daemon"
   gt broadcast --all "System maintenance in 5 minutes"
   gt broadcast --dry-run "Test message"`,
 	Args: cobra.ExactArgs(1),
@@ -50,6 +58,15 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("message cannot be empty")
 	}
 
+	role := broadcastRole
+	if broadcastAll {
+		role = "all"
+	}
+	roles, err := parseBroadcastRoles(role)
+	if err != nil {
+		return err
+	}
+
 	// Get all agent sessions (including polecats)
 	agents, err := getAgentSessions(true)
 	if err != nil {
@@ -67,11 +84,9 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Unless --all, only include workers (crew + polecats)
-		if !broadcastAll {
-			if agent.Type != AgentCrew && agent.Type != AgentPolecat {
-				continue
-			}
+		// Filter by role (workers only by default)
+		if !roles[agent.Type] {
+			continue
 		}
 
 		// Skip self to avoid interrupting own session
@@ -87,6 +102,9 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 		if broadcastRig != "" {
 			fmt.Printf("  (filtered by rig: %s)\n", broadcastRig)
 		}
+		if role != "worker" {
+			fmt.Printf("  (filtered by role: %s)\n", role)
+		}
 		return nil
 	}
 
@@ -139,6 +157,41 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// broadcastRoleTypes maps --role values to the agent types they select.
+var broadcastRoleTypes = map[string][]AgentType{
+	"mayor":    {AgentMayor},
+	"deacon":   {AgentDeacon},
+	"witness":  {AgentWitness},
+	"refinery": {AgentRefinery},
+	"crew":     {AgentCrew},
+	"polecat":  {AgentPolecat},
+	"worker":   {AgentCrew, AgentPolecat},
+	"all":      {AgentMayor, AgentDeacon, AgentWitness, AgentRefinery, AgentCrew, AgentPolecat},
+}
+
+// parseBroadcastRoles parses a comma-separated --role value into the set of
+// agent types to broadcast to.
+func parseBroadcastRoles(value string) (map[AgentType]bool, error) {
+	roles := make(map[AgentType]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		types, ok := broadcastRoleTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %q (valid: polecat, crew, worker, witness, refinery, mayor, deacon, all)", name)
+		}
+		for _, t := range types {
+			roles[t] = true
+		}
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("--role cannot be empty")
+	}
+	return roles, nil
+}
+
 // formatAgentName returns a display name for an agent.
 func formatAgentName(agent *AgentSession) string {
 	switch agent.Type {
@@ -0,0 +1,36 @@
+package cmd
+
+import "testing"
+
+func TestParseBroadcastRoles(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []AgentType
+	}{
+		{"worker", []AgentType{AgentCrew, AgentPolecat}},
+		{"polecat", []AgentType{AgentPolecat}},
+		{"Witness, refinery", []AgentType{AgentWitness, AgentRefinery}},
+		{"all", []AgentType{AgentMayor, AgentDeacon, AgentWitness, AgentRefinery, AgentCrew, AgentPolecat}},
+	}
+	for _, tt := range tests {
+		roles, err := parseBroadcastRoles(tt.value)
+		if err != nil {
+			t.Errorf("parseBroadcastRoles(%q): %v", tt.value, err)
+			continue
+		}
+		if len(roles) != len(tt.want) {
+			t.Errorf("parseBroadcastRoles(%q) = %v, want %v", tt.value, roles, tt.want)
+		}
+		for _, at := range tt.want {
+			if !roles[at] {
+				t.Errorf("parseBroadcastRoles(%q) missing agent type %d", tt.value, at)
+			}
+		}
+	}
+
+	for _, bad := range []string{"", " , ", "janitor"} {
+		if _, err := parseBroadcastRoles(bad); err == nil {
+			t.Errorf("parseBroadcastRoles(%q) succeeded, want error", bad)
+		}
+	}
+}
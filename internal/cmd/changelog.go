@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Changelog command flags
+var (
+	changelogSince  string
+	changelogAppend string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog <rig>",
+	GroupID: GroupWork,
+	Short:   "Generate a changelog from merged MRs",
+	Long: `Assemble a changelog from a rig's merged merge requests.
+
+Each entry shows the source issue's title, the issue ID, the worker, and the
+merge commit. Entries are grouped by epic (the source issue's parent, or the
+integration branch), then by issue type.
+
+--since limits the changelog to MRs merged after a date (YYYY-MM-DD or
+RFC3339) or after the commit a tag or ref points to.
+
+With --append, the entry for one MR is added to CHANGELOG.md in the
+refinery's clone and committed on the checked-out branch. The Refinery runs
+this after each merge when merge_queue.changelog is enabled; otherwise it
+does nothing.
+
+Examples:
+  gt changelog greenplace
+  gt changelog greenplace --since v1.4.0
+  gt changelog greenplace --since 2026-01-01 -o json
+  gt changelog greenplace --append gp-mr-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Only MRs merged after a tag, ref, or date")
+	changelogCmd.Flags().StringVar(&changelogAppend, "append", "", "Add this MR to CHANGELOG.md and commit (if merge_queue.changelog is enabled)")
+	changelogCmd.MarkFlagsMutuallyExclusive("since", "append")
+
+	rootCmd.AddCommand(changelogCmd)
+}
+
+// ChangelogOutput is the structured output for gt changelog.
+type ChangelogOutput struct {
+	Rig    string                    `json:"rig"`
+	Since  string                    `json:"since,omitempty"`
+	Groups []refinery.ChangelogGroup `json:"groups"`
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	bd := beads.New(r.BeadsPath())
+
+	if changelogAppend != "" {
+		return appendChangelog(r.Path, eng, bd, changelogAppend)
+	}
+
+	var since time.Time
+	if changelogSince != "" {
+		since, err = parseChangelogSince(eng, changelogSince)
+		if err != nil {
+			return err
+		}
+	}
+
+	groups, err := buildChangelog(bd, since)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		if groups == nil {
+			groups = []refinery.ChangelogGroup{}
+		}
+		return renderStructured(ChangelogOutput{Rig: rigName, Since: changelogSince, Groups: groups})
+	}
+
+	heading := "Unreleased"
+	if changelogSince != "" {
+		heading = "Changes since " + changelogSince
+	}
+	fmt.Print(refinery.FormatChangelogMarkdown(heading, groups))
+	return nil
+}
+
+// buildChangelog groups the rig's merged MRs (merged after since, if set)
+// into changelog sections.
+func buildChangelog(bd *beads.Beads, since time.Time) ([]refinery.ChangelogGroup, error) {
+	issues, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "closed", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("querying merged MRs: %w", err)
+	}
+
+	var merged []*beads.Issue
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil || (fields.CloseReason != "merged" && fields.MergeCommit == "") {
+			continue
+		}
+		if !since.IsZero() {
+			closed, err := time.Parse(time.RFC3339, issue.ClosedAt)
+			if err != nil || !closed.After(since) {
+				continue
+			}
+		}
+		merged = append(merged, issue)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].ClosedAt < merged[j].ClosedAt })
+
+	entries, err := changelogEntries(bd, merged)
+	if err != nil {
+		return nil, err
+	}
+	epicTitles := make(map[string]string)
+	var epicIDs []string
+	for _, e := range entries {
+		if e.Epic != "" && !slices.Contains(epicIDs, e.Epic) {
+			epicIDs = append(epicIDs, e.Epic)
+		}
+	}
+	if epics, err := bd.ShowMultiple(epicIDs); err == nil {
+		for id, epic := range epics {
+			epicTitles[id] = epic.Title
+		}
+	}
+	return refinery.GroupChangelog(entries, epicTitles), nil
+}
+
+// appendChangelog adds one MR's entry to CHANGELOG.md if the rig has
+// merge_queue.changelog enabled.
+func appendChangelog(rigPath string, eng *refinery.Engineer, bd *beads.Beads, mrID string) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings == nil || settings.MergeQueue == nil || !settings.MergeQueue.Changelog {
+		fmt.Printf("%s\n", style.Dim.Render("Changelog disabled for this rig (merge_queue.changelog); nothing to do"))
+		return nil
+	}
+
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found", mrID))
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	entries, err := changelogEntries(bd, []*beads.Issue{issue})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", mrID)
+	}
+
+	if err := eng.AppendChangelog(entries[0]); err != nil {
+		return fmt.Errorf("updating changelog: %w", err)
+	}
+	fmt.Printf("%s Added %s to %s\n", style.Success.Render("✓"), mrID, refinery.ChangelogFile)
+	return nil
+}
+
+// changelogEntries builds changelog entries for MR beads, taking titles,
+// types, and epics from their source issues. Beads without MR fields are
+// skipped.
+func changelogEntries(bd *beads.Beads, mrs []*beads.Issue) ([]refinery.ChangelogEntry, error) {
+	var sourceIDs []string
+	for _, mr := range mrs {
+		if fields := beads.ParseMRFields(mr); fields != nil && fields.SourceIssue != "" {
+			sourceIDs = append(sourceIDs, fields.SourceIssue)
+		}
+	}
+	sources, err := bd.ShowMultiple(sourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetching source issues: %w", err)
+	}
+
+	var entries []refinery.ChangelogEntry
+	for _, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil {
+			continue
+		}
+		entry := refinery.ChangelogEntry{
+			MR:          mr.ID,
+			Title:       mr.Title,
+			SourceIssue: fields.SourceIssue,
+			Worker:      fields.Worker,
+			MergeCommit: fields.MergeCommit,
+			MergedAt:    mr.ClosedAt,
+		}
+		if epic, ok := strings.CutPrefix(fields.Target, "integration/"); ok {
+			entry.Epic = epic
+		}
+		if src := sources[fields.SourceIssue]; src != nil {
+			entry.Title = src.Title
+			entry.Type = src.Type
+			if src.Parent != "" {
+				entry.Epic = src.Parent
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseChangelogSince parses --since as a date, an RFC3339 time, or a git
+// tag/ref in the refinery's clone.
+func parseChangelogSince(eng *refinery.Engineer, since string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", since, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	t, err := eng.CommitTime(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since %q is not a date or a known tag/ref: %w", since, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Check command flags
+var (
+	checkRig string
+	checkDir string
+)
+
+var checkCmd = &cobra.Command{
+	Use:     "check",
+	GroupID: GroupWork,
+	Short:   "Run the refinery's checks in your worktree before submitting",
+	Long: `Run the checks the refinery runs on merge requests, in your worktree,
+so failures turn up before you submit instead of in the merge queue.
+
+The checks are the rig's merge_queue.test_command ("tests") and, with
+transactional merges, its verify command ("verify"), in the order the
+refinery runs them. Each runs at the top of your worktree, as the
+refinery runs it:
+
+  - through the shell, or the docker or nix runner merge_queue.checks
+    sets for it, within the same CPU, memory and time limits
+  - with the refinery session's environment: GT_ROLE, BD_ACTOR and the
+    rest are the rig's refinery's, not yours
+  - with the same retries for checks that have a history of flaking
+
+The subject lines of your branch's commits are also checked against the
+rig's merge_queue.commit_messages convention, as gt mq submit and gt done
+check them. gt commit writes messages that follow it.
+
+Results are reported as gt mq test reports them: pass, fail and skip
+counts, and the names of failing tests. Local runs are not recorded in the
+rig's check history or on any MR. gt done runs the same checks before
+submitting.
+
+Exits with code 8 if a check fails (the checks after it are not run) or a
+commit message breaks the convention.
+
+Examples:
+  gt check
+  gt check --rig greenplace --dir ~/gt/greenplace/crew/max
+  gt check -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&checkRig, "rig", "", "Rig whose checks to run (default: the current rig)")
+	checkCmd.Flags().StringVar(&checkDir, "dir", "", "Worktree to run the checks in (default: the top of the current one)")
+
+	rootCmd.AddCommand(checkCmd)
+}
+
+// CheckOutput is the structured output for gt check.
+type CheckOutput struct {
+	Rig              string                     `json:"rig"`
+	Dir              string                     `json:"dir"`
+	Passed           bool                       `json:"passed"`
+	Checks           []CheckRunOutput           `json:"checks"` // Those run, in order
+	CommitViolations []refinery.CommitViolation `json:"commit_violations,omitempty"`
+}
+
+// CheckRunOutput is one check's run.
+type CheckRunOutput struct {
+	Name    string                `json:"name"`
+	Command string                `json:"command"`
+	Runner  string                `json:"runner"`  // shell, docker:<image> or nix
+	Outcome string                `json:"outcome"` // passed, failed or flaky
+	Tests   *refinery.TestResults `json:"tests"`
+	Error   string                `json:"error,omitempty"` // Why it failed
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := checkRig
+	var r *rig.Rig
+	if rigName == "" {
+		if rigName, r, err = findCurrentRig(townRoot); err != nil {
+			return fmt.Errorf("%w (use --rig)", err)
+		}
+	} else if _, r, err = getRig(rigName); err != nil {
+		return err
+	}
+	dir := checkDir
+	if dir == "" {
+		if dir, err = detectCloneRoot(); err != nil {
+			return fmt.Errorf("finding your worktree: %w (use --dir)", err)
+		}
+	}
+
+	// Structured output owns stdout, so the checks' goes to stderr
+	var passthrough io.Writer = os.Stdout
+	if structuredOutput(false) {
+		passthrough = os.Stderr
+	}
+	policy, violations, err := checkBranchCommits(r.Path, dir, r.DefaultBranch())
+	if err != nil {
+		return err
+	}
+	results, passed, err := runRigChecks(townRoot, rigName, dir, passthrough)
+	if err != nil {
+		return err
+	}
+	passed = passed && len(violations) == 0
+
+	if structuredOutput(false) {
+		if err := renderStructured(CheckOutput{Rig: rigName, Dir: dir, Passed: passed, Checks: results, CommitViolations: violations}); err != nil {
+			return err
+		}
+	} else {
+		if len(results) == 0 {
+			fmt.Printf("Rig '%s' has no checks %s\n", rigName, style.Dim.Render("(merge_queue.test_command)"))
+		} else {
+			fmt.Println()
+			for _, r := range results {
+				printCheckRun(r)
+			}
+		}
+		switch {
+		case len(violations) > 0:
+			fmt.Printf("%s %s\n", style.Error.Render("✗"), policy.FormatCommitViolations(violations))
+		case policy != nil:
+			fmt.Printf("%s commit messages follow %q\n", style.Success.Render("✓"), policy.Template())
+		}
+	}
+	if !passed {
+		return NewSilentExit(ExitCheckFailed)
+	}
+	return nil
+}
+
+// checkBranchCommits checks the subjects of the commits in dir's branch
+// that aren't on the rig's target branch against the rig's commit message
+// convention. A nil policy means the rig has none.
+func checkBranchCommits(rigPath, dir, target string) (*refinery.CommitPolicy, []refinery.CommitViolation, error) {
+	policy, err := refinery.LoadCommitPolicy(rigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading commit message policy: %w", err)
+	}
+	if policy == nil {
+		return nil, nil, nil
+	}
+	g := git.NewGit(dir)
+	violations, err := policy.CheckCommits(g, submitDiffBase(g, target), "HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, violations, nil
+}
+
+// runRigChecks runs a rig's refinery checks in dir, in order, until one
+// fails. The checks' output, and what is being run, go to passthrough.
+// Runs are not recorded in the rig's check history.
+func runRigChecks(townRoot, rigName, dir string, passthrough io.Writer) ([]CheckRunOutput, bool, error) {
+	rigPath := filepath.Join(townRoot, rigName)
+	checks, err := refinery.LoadChecks(rigPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("loading checks: %w", err)
+	}
+	history, err := refinery.LoadFlakeHistory(rigPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("loading flaky check settings: %w", err)
+	}
+	env := refinery.CheckEnv(rigName, townRoot)
+
+	results := []CheckRunOutput{}
+	for _, check := range checks {
+		running := fmt.Sprintf("Running %s: %s", check.Name, check.Command)
+		if check.Runner != nil {
+			running += " (" + check.Runner.String() + ")"
+		}
+		_, _ = fmt.Fprintf(passthrough, "%s\n", style.Bold.Render(running))
+		result := runRigCheck(check, dir, history, env, passthrough)
+		results = append(results, result)
+		if result.Error != "" {
+			return results, false, nil
+		}
+	}
+	return results, true, nil
+}
+
+// runRigCheck runs one check the way the refinery does.
+func runRigCheck(check refinery.RigCheck, dir string, history *refinery.FlakeHistory, env []string, passthrough io.Writer) CheckRunOutput {
+	ctx := context.Background()
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.Timeout)
+		defer cancel()
+	}
+
+	command := check.CheckCommand(dir, history)
+	command.Env = env
+	command.Output = passthrough
+	command.Retrying = func(attempt, attempts int) {
+		fmt.Fprintf(os.Stderr, "\n%s\n", style.Dim.Render(fmt.Sprintf("Retrying %s (attempt %d/%d)...", check.Name, attempt, attempts)))
+	}
+	run := command.Run(ctx)
+
+	result := CheckRunOutput{Name: check.Name, Command: check.Command, Runner: check.Runner.String(), Outcome: run.Outcome(), Tests: run.Tests}
+	switch {
+	case run.Err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Error = fmt.Sprintf("timed out after %v", check.Timeout)
+	default:
+		result.Error = run.Err.Error()
+	}
+	return result
+}
+
+func printCheckRun(r CheckRunOutput) {
+	if r.Error == "" {
+		fmt.Printf("%s %s passed: %s\n", style.Success.Render("✓"), r.Name, r.Tests)
+		return
+	}
+	fmt.Printf("%s %s failed: %s %s\n", style.Error.Render("✗"), r.Name, r.Tests, style.Dim.Render("("+r.Error+")"))
+	printFailingTests(r.Tests)
+}
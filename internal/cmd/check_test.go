@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRunRigChecks(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "greenplace")
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(mergeQueue string) {
+		data := `{"type":"rig-settings","version":1,"merge_queue":` + mergeQueue + `}`
+		if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := t.TempDir()
+
+	// Tests pass, then verification runs as the refinery and fails
+	write(`{"test_command":"true","flaky":{"max_retries":-1},"transactional":{"verify_command":"test \"$GT_ROLE\" = greenplace/refinery && exit 3"}}`)
+	results, passed, err := runRigChecks(townRoot, "greenplace", dir, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passed || len(results) != 2 {
+		t.Fatalf("runRigChecks = %+v, passed %v; want tests then a failed verify", results, passed)
+	}
+	if results[0].Name != "tests" || results[0].Error != "" || results[0].Outcome != "passed" {
+		t.Errorf("tests = %+v, want passed", results[0])
+	}
+	if results[1].Name != "verify" || results[1].Error == "" || results[1].Outcome != "failed" {
+		t.Errorf("verify = %+v, want failed", results[1])
+	}
+
+	// A failure stops the checks after it
+	write(`{"test_command":"false","flaky":{"max_retries":-1},"transactional":{"verify_command":"true"}}`)
+	results, passed, err = runRigChecks(townRoot, "greenplace", dir, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passed || len(results) != 1 {
+		t.Errorf("runRigChecks = %+v, passed %v; want only the failed tests", results, passed)
+	}
+
+	// Verification timeouts are reported as such
+	write(`{"transactional":{"verify_command":"exec sleep 5","timeout":"100ms"}}`)
+	results, _, err = runRigChecks(townRoot, "greenplace", dir, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Error != "timed out after 100ms" {
+		t.Errorf("runRigChecks = %+v, want a timed out verify", results)
+	}
+}
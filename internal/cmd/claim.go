@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Claim command flags
+var (
+	claimTTL     time.Duration
+	claimForce   bool
+	claimRelease bool
+)
+
+var claimCmd = &cobra.Command{
+	Use:     "claim <issue>",
+	GroupID: GroupWork,
+	Short:   "Lease an issue to yourself before starting work on it",
+	Long: `Atomically assign an issue to yourself with a lease.
+
+A claim makes you the assignee, moves open work to in_progress, and
+records a lease that lasts --ttl (default 2h). Claims are serialized
+town-wide, so when two workers claim the same issue exactly one wins;
+the other gets exit code 11 and should pick something else.
+
+Claiming an issue you already hold renews the lease - do so on long tasks.
+A lease that runs out lets someone else claim the issue; the assignment
+stays until they do.
+
+gt sling and gt mq submit verify claims: slinging an issue claimed (or
+hooked, or in progress) by someone else needs --force, and submitting work
+for it needs --ignore-claim.
+
+Examples:
+  gt claim gt-abc                 # Claim for 2h
+  gt claim gt-abc --ttl 6h        # Long task (or renew)
+  gt claim gt-abc --release       # Give it up
+  gt claim gt-abc --force         # Take it from a stuck worker`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClaim,
+}
+
+func init() {
+	claimCmd.Flags().DurationVar(&claimTTL, "ttl", claim.DefaultTTL, "How long the lease lasts")
+	claimCmd.Flags().BoolVar(&claimForce, "force", false, "Claim (or release) even if someone else holds a live lease")
+	claimCmd.Flags().BoolVar(&claimRelease, "release", false, "Release your claim instead")
+
+	rootCmd.AddCommand(claimCmd)
+}
+
+func runClaim(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(cwd)
+	agent := strings.TrimSuffix(detectSender(), "/")
+
+	if claimRelease {
+		if err := claim.Release(townRoot, bd, issueID, agent, claimForce); err != nil {
+			return err
+		}
+		if !structuredOutput(false) {
+			fmt.Printf("%s Released %s\n", style.Bold.Render("✓"), issueID)
+		}
+		return nil
+	}
+
+	lease, err := claim.Claim(townRoot, bd, issueID, agent, claim.Options{TTL: claimTTL, Force: claimForce})
+	if err != nil {
+		var held *claim.HeldError
+		if errors.As(err, &held) {
+			return fmt.Errorf("%w\nPick other work, or use --force if the holder is stuck", err)
+		}
+		return err
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(lease)
+	}
+	fmt.Printf("%s Claimed %s for %s until %s\n", style.Bold.Render("✓"), lease.Issue, lease.Holder,
+		lease.Expires.Local().Format("15:04 Jan 2"))
+	return nil
+}
+
+// checkClaim returns a *claim.HeldError if issueID is under a live lease
+// that agent doesn't hold. Problems reading the issue are left to the
+// caller's own checks.
+func checkClaim(issueID, agent string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	issue, err := beads.New(townRoot).Show(issueID)
+	if err != nil {
+		return nil
+	}
+	return claim.Check(issue, agent, time.Now())
+}
@@ -73,7 +73,7 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 
 	// Confirm unless --force
 	if !cleanupForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(zombies))
+		promptf("Kill these %d process(es)? [y/N] ", len(zombies))
 		var response string
 		_, _ = fmt.Scanln(&response)
 		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -15,13 +21,27 @@ const DefaultAgentEmailDomain = "gastown.local"
 
 var commitCmd = &cobra.Command{
 	Use:   "commit [flags] [-- git-commit-args...]",
-	Short: "Git commit with automatic agent identity",
-	Long: `Git commit wrapper that automatically sets git author identity for agents.
+	Short: "Git commit with automatic agent identity and message convention",
+	Long: `Git commit wrapper that automatically sets git author identity for agents
+and writes messages in the rig's commit message convention.
 
 When run by an agent (GT_ROLE set), this command:
 1. Detects the agent identity from environment variables
 2. Converts it to a git-friendly name and email
-3. Runs 'git commit' with the correct identity
+3. Composes the commit message (see below)
+4. Runs 'git commit' with the correct identity
+
+Commit messages:
+  A -m message whose subject breaks the rig's merge_queue.commit_messages
+  convention is taken as the summary, and the subject is composed from
+  the template: {summary}, {issue} (the issue you're working on, from
+  your branch name or hook) and {type} (the one suiting the issue's type:
+  fix for a bug, feat for a feature, ...). Without a message, agents (and
+  anyone giving --issue) get the issue's title as the summary. gt check,
+  gt mq submit and gt done reject commits that break the convention.
+
+  --issue <id>    The issue the commit is for
+  --type <type>   The commit type, for templates with {type}
 
 The email domain is configurable in town settings (agent_email_domain).
 Default: gastown.local
@@ -29,13 +49,16 @@ Default: gastown.local
 Examples:
   gt commit -m "Fix bug"              # Commit as current agent
   gt commit -am "Quick fix"           # Stage all and commit
+  gt commit -a                        # The issue's title as the message
+  gt commit --type fix -m "Handle expired tokens"
   gt commit -- --amend                # Amend last commit
 
 Identity mapping:
   Agent: gastown/crew/jack  →  Name: gastown/crew/jack
                                 Email: gastown.crew.jack@gastown.local
 
-When run without GT_ROLE (human), passes through to git commit with no changes.`,
+When run without GT_ROLE (human), passes through to git commit with no
+identity changes.`,
 	RunE:               runCommit,
 	DisableFlagParsing: true, // We'll parse flags ourselves to pass them to git
 }
@@ -51,9 +74,21 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	commitType, commitIssue, args, err := parseCommitFlags(args)
+	if err != nil {
+		return err
+	}
+
 	// Detect agent identity
 	identity := detectSender()
 
+	townRoot, err := workspace.FindFromCwd()
+	if err == nil && townRoot != "" {
+		if args, err = applyCommitPolicy(townRoot, args, commitType, commitIssue, identity != "overseer"); err != nil {
+			return err
+		}
+	}
+
 	// If overseer (human), just pass through to git commit
 	if identity == "overseer" {
 		return runGitCommit(args, "", "")
@@ -61,8 +96,7 @@ func runCommit(cmd *cobra.Command, args []string) error {
 
 	// Load agent email domain from town settings
 	domain := DefaultAgentEmailDomain
-	townRoot, err := workspace.FindFromCwd()
-	if err == nil && townRoot != "" {
+	if townRoot != "" {
 		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
 		if err == nil && settings.AgentEmailDomain != "" {
 			domain = settings.AgentEmailDomain
@@ -79,6 +113,197 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	return runGitCommit(args, name, email)
 }
 
+// parseCommitFlags takes gt commit's own flags (--type, --issue) out of
+// args, leaving git commit's.
+func parseCommitFlags(args []string) (commitType, issue string, gitArgs []string, err error) {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		var target *string
+		switch {
+		case a == "--type" || a == "--issue":
+			if i+1 >= len(args) {
+				return "", "", nil, fmt.Errorf("%s needs a value", a)
+			}
+			target = &commitType
+			if a == "--issue" {
+				target = &issue
+			}
+			i++
+			*target = args[i]
+		case strings.HasPrefix(a, "--type="):
+			commitType = strings.TrimPrefix(a, "--type=")
+		case strings.HasPrefix(a, "--issue="):
+			issue = strings.TrimPrefix(a, "--issue=")
+		default:
+			gitArgs = append(gitArgs, a)
+		}
+	}
+	return commitType, issue, gitArgs, nil
+}
+
+// applyCommitPolicy rewrites git commit args so the message follows the
+// current rig's commit message convention: a -m message that breaks it
+// becomes the summary of a composed one, and with compose set, a commit
+// without any message gets one from the issue's title.
+func applyCommitPolicy(townRoot string, args []string, commitType, issueID string, compose bool) ([]string, error) {
+	idx, prefix := commitMessageArg(args)
+	if idx < 0 && (!(compose || issueID != "") || commitMessageFromElsewhere(args)) {
+		return args, nil
+	}
+
+	rigName, _, err := findCurrentRig(townRoot)
+	if err != nil {
+		if idx >= 0 {
+			return args, nil // No rig, no convention
+		}
+		rigName = ""
+	}
+	var policy *refinery.CommitPolicy
+	if rigName != "" {
+		if policy, err = refinery.LoadCommitPolicy(filepath.Join(townRoot, rigName)); err != nil {
+			return nil, fmt.Errorf("loading commit message policy: %w", err)
+		}
+	}
+
+	var summary, rest string
+	if idx >= 0 {
+		summary, rest, _ = strings.Cut(strings.TrimPrefix(args[idx], prefix), "\n")
+		if policy.Check(summary) == nil {
+			return args, nil
+		}
+	}
+
+	workDir, err := detectCloneRoot()
+	if err != nil {
+		return nil, fmt.Errorf("finding your workspace: %w", err)
+	}
+	bd := beads.New(beads.ResolveBeadsDir(workDir))
+	if issueID == "" && (idx < 0 || policy.Uses("issue")) {
+		issueID = currentWorkIssue(townRoot, rigName, workDir, git.NewGit(workDir), bd)
+	}
+	var issue *beads.Issue
+	if issueID != "" {
+		if issue, err = bd.Show(issueID); err != nil && idx < 0 {
+			return nil, fmt.Errorf("getting issue %s: %w", issueID, err)
+		}
+	}
+	if idx < 0 && issue == nil {
+		return args, nil // Nothing to compose from: git asks for a message
+	}
+
+	subject, err := composeCommitMessage(policy, commitType, issueID, issue, summary)
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return append([]string{"-m", subject}, args...), nil
+	}
+	if rest != "" {
+		subject += "\n" + rest
+	}
+	rewritten := append([]string(nil), args...)
+	rewritten[idx] = prefix + subject
+	return rewritten, nil
+}
+
+// commitMessageArg finds the first -m/--message value in git commit args.
+// It returns the value's index and the flag text before it in that
+// argument ("-m" for "-mFix"), or -1 if there is no message.
+func commitMessageArg(args []string) (int, string) {
+	for i, a := range args {
+		switch {
+		case a == "--message":
+			if i+1 < len(args) {
+				return i + 1, ""
+			}
+		case strings.HasPrefix(a, "--message="):
+			return i, "--message="
+		case strings.HasPrefix(a, "--") || !strings.HasPrefix(a, "-"):
+		default:
+			// A short flag cluster: -m, -am, -mFix, -amFix
+			if j := strings.IndexByte(a, 'm'); j > 0 {
+				if j == len(a)-1 {
+					if i+1 < len(args) {
+						return i + 1, ""
+					}
+					return -1, ""
+				}
+				return i, a[:j+1]
+			}
+		}
+	}
+	return -1, ""
+}
+
+// commitMessageFromElsewhere reports whether git commit args take the
+// message from somewhere other than -m (a file, another commit, --amend).
+func commitMessageFromElsewhere(args []string) bool {
+	for _, a := range args {
+		for _, flag := range []string{"-F", "--file", "-C", "--reuse-message", "-c", "--reedit-message", "--amend", "--fixup", "--squash", "--no-edit", "-t", "--template"} {
+			if a == flag || strings.HasPrefix(a, flag+"=") || (len(flag) == 2 && strings.HasPrefix(a, flag)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// currentWorkIssue returns the issue a workspace is working on: the one
+// its branch is named for, else its agent's hooked issue, else "".
+func currentWorkIssue(townRoot, rigName, workDir string, g *git.Git, bd *beads.Beads) string {
+	if branch, err := g.CurrentBranch(); err == nil {
+		var policy *rig.BranchPolicy
+		if rigName != "" {
+			policy = rigBranchPolicy(rigName)
+		}
+		if issue := parseRigBranchName(policy, branch).Issue; issue != "" {
+			return issue
+		}
+	}
+	roleInfo, err := GetRoleWithContext(workDir, townRoot)
+	if err != nil {
+		return ""
+	}
+	agentBeadID := getAgentBeadID(RoleContext{
+		Role:     roleInfo.Role,
+		Rig:      roleInfo.Rig,
+		Polecat:  roleInfo.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  workDir,
+	})
+	if agentBeadID == "" {
+		return ""
+	}
+	return getIssueFromAgentHook(bd, agentBeadID)
+}
+
+// composeCommitMessage writes a commit subject in policy's convention. The
+// summary defaults to the issue's title and the type to the one suiting
+// the issue's type.
+func composeCommitMessage(policy *refinery.CommitPolicy, commitType, issueID string, issue *beads.Issue, summary string) (string, error) {
+	if issue != nil {
+		if strings.TrimSpace(summary) == "" {
+			summary = issue.Title
+		}
+		if commitType == "" {
+			commitType = policy.TypeFor(issue.Type)
+		}
+	}
+	if strings.TrimSpace(summary) == "" {
+		return "", fmt.Errorf("no commit message: use -m, or --issue for an issue with a title")
+	}
+	if commitType == "" && policy.Uses("type") {
+		if len(policy.Types()) > 0 {
+			return "", fmt.Errorf("no commit type for the rig's convention %q: use --type (one of %s)", policy.Template(), strings.Join(policy.Types(), ", "))
+		}
+		return "", fmt.Errorf("no commit type for the rig's convention %q: use --type", policy.Template())
+	}
+	if issueID == "" && policy.Uses("issue") {
+		return "", fmt.Errorf("no issue for the rig's convention %q: use --issue", policy.Template())
+	}
+	return policy.Compose(commitType, issueID, summary)
+}
+
 // identityToEmail converts a Gas Town identity to a git email address.
 // "gastown/crew/jack" → "gastown.crew.jack@domain"
 // "mayor/" → "mayor@domain"
@@ -1,6 +1,13 @@
 package cmd
 
-import "testing"
+import (
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
 
 func TestIdentityToEmail(t *testing.T) {
 	tests := []struct {
@@ -69,3 +76,86 @@ func TestIdentityToEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCommitFlags(t *testing.T) {
+	commitType, issue, gitArgs, err := parseCommitFlags([]string{"-a", "--type", "fix", "--issue=gt-abc", "-m", "Fix it"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitType != "fix" || issue != "gt-abc" || !slices.Equal(gitArgs, []string{"-a", "-m", "Fix it"}) {
+		t.Errorf("parseCommitFlags = %q, %q, %q", commitType, issue, gitArgs)
+	}
+	if _, _, _, err := parseCommitFlags([]string{"--type"}); err == nil {
+		t.Error("parseCommitFlags(--type) without a value should fail")
+	}
+}
+
+func TestCommitMessageArg(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantIdx    int
+		wantPrefix string
+	}{
+		{[]string{"-m", "Fix"}, 1, ""},
+		{[]string{"-am", "Fix"}, 1, ""},
+		{[]string{"-mFix"}, 0, "-m"},
+		{[]string{"-amFix"}, 0, "-am"},
+		{[]string{"--all", "--message", "Fix"}, 2, ""},
+		{[]string{"--message=Fix"}, 0, "--message="},
+		{[]string{"-a"}, -1, ""},
+		{[]string{"-m"}, -1, ""},
+		{[]string{"--amend"}, -1, ""},
+	}
+	for _, tt := range tests {
+		idx, prefix := commitMessageArg(tt.args)
+		if idx != tt.wantIdx || prefix != tt.wantPrefix {
+			t.Errorf("commitMessageArg(%q) = %d, %q; want %d, %q", tt.args, idx, prefix, tt.wantIdx, tt.wantPrefix)
+		}
+	}
+
+	if !commitMessageFromElsewhere([]string{"-a", "--amend"}) || !commitMessageFromElsewhere([]string{"-Fmsg.txt"}) {
+		t.Error("commitMessageFromElsewhere missed --amend or -F")
+	}
+	if commitMessageFromElsewhere([]string{"-a", "-m", "Fix"}) {
+		t.Error("commitMessageFromElsewhere(-m) = true")
+	}
+}
+
+func TestComposeCommitMessage(t *testing.T) {
+	policy, err := refinery.NewCommitPolicy(&config.CommitMessageConfig{
+		Template: "{type}: {summary} ({issue})",
+		Types:    []string{"feat", "fix"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bug := &beads.Issue{ID: "gt-abc", Title: "Login fails", Type: "bug"}
+
+	tests := []struct {
+		name                string
+		policy              *refinery.CommitPolicy
+		commitType, issueID string
+		issue               *beads.Issue
+		summary, want       string
+		wantErr             bool
+	}{
+		{name: "title and type from issue", policy: policy, issueID: "gt-abc", issue: bug, want: "fix: Login fails (gt-abc)"},
+		{name: "summary given", policy: policy, issueID: "gt-abc", issue: bug, summary: "handle expired tokens", want: "fix: handle expired tokens (gt-abc)"},
+		{name: "type given", policy: policy, commitType: "feat", issueID: "gt-abc", issue: bug, want: "feat: Login fails (gt-abc)"},
+		{name: "no type", policy: policy, issueID: "gt-abc", summary: "x", wantErr: true},
+		{name: "no issue", policy: policy, commitType: "fix", summary: "x", wantErr: true},
+		{name: "no summary", policy: policy, commitType: "fix", issueID: "gt-abc", wantErr: true},
+		{name: "no convention", issueID: "gt-abc", issue: bug, want: "Login fails (gt-abc)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := composeCommitMessage(tt.policy, tt.commitType, tt.issueID, tt.issue, tt.summary)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("composeCommitMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("composeCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
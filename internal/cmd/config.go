@@ -319,32 +319,24 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	// Load town settings
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
-	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
-	}
-
 	// Parse command line into command and args
 	parts := strings.Fields(commandLine)
 	if len(parts) == 0 {
 		return fmt.Errorf("command cannot be empty")
 	}
 
-	// Initialize agents map if needed
-	if townSettings.Agents == nil {
-		townSettings.Agents = make(map[string]*config.RuntimeConfig)
-	}
-
 	// Create or update the agent
-	townSettings.Agents[name] = &config.RuntimeConfig{
-		Command: parts[0],
-		Args:    parts[1:],
-	}
-
-	// Save settings
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+	_, err = config.TownSettingsStore(config.TownSettingsPath(townRoot)).Update(func(townSettings *config.TownSettings) error {
+		if townSettings.Agents == nil {
+			townSettings.Agents = make(map[string]*config.RuntimeConfig)
+		}
+		townSettings.Agents[name] = &config.RuntimeConfig{
+			Command: parts[0],
+			Args:    parts[1:],
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
 
@@ -378,24 +370,16 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Load town settings
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
-	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
-	}
-
-	// Check if agent exists
-	if townSettings.Agents == nil || townSettings.Agents[name] == nil {
-		return fmt.Errorf("custom agent '%s' not found", name)
-	}
-
 	// Remove the agent
-	delete(townSettings.Agents, name)
-
-	// Save settings
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
-		return fmt.Errorf("saving town settings: %w", err)
+	_, err = config.TownSettingsStore(config.TownSettingsPath(townRoot)).Update(func(townSettings *config.TownSettings) error {
+		if townSettings.Agents == nil || townSettings.Agents[name] == nil {
+			return fmt.Errorf("custom agent '%s' not found", name)
+		}
+		delete(townSettings.Agents, name)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Removed custom agent '%s'\n", style.Bold.Render(name))
@@ -454,10 +438,11 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 	}
 
 	// Set default
-	townSettings.DefaultAgent = name
-
-	// Save settings
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+	_, err = config.TownSettingsStore(settingsPath).Update(func(townSettings *config.TownSettings) error {
+		townSettings.DefaultAgent = name
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
 
@@ -501,10 +486,11 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	}
 
 	// Set domain
-	townSettings.AgentEmailDomain = domain
-
-	// Save settings
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+	_, err = config.TownSettingsStore(settingsPath).Update(func(townSettings *config.TownSettings) error {
+		townSettings.AgentEmailDomain = domain
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
 
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Context command flags
+var (
+	contextRig      string
+	contextMaxChars int
+	contextCommits  int
+)
+
+var contextCmd = &cobra.Command{
+	Use:     "context <issue-id>",
+	GroupID: GroupWork,
+	Short:   "Print a briefing for an agent picking up an issue",
+	Long: `Assemble what an agent needs to pick up an issue, as markdown:
+
+  - the issue: status, priority, assignee and description
+  - owner notes: the issue's notes and comments (e.g., handoff notes)
+  - prior merge attempts: the issue's merge requests, with what made
+    failed ones fail and their latest review comments
+  - related issues: parent, children, dependencies and dependents
+  - recent commits on the rig's default branch that mention the issue
+
+The briefing is cut to --max-chars so it fits in a prompt: the sections
+at the end of the list go first. The rig is worked out from the issue's
+prefix; use --rig for issues whose prefix isn't routed.
+
+Examples:
+  gt context gp-42
+  gt context gp-42 --max-chars 4000
+  gt sling gp-42 greenplace -a "$(gt context gp-42)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContext,
+}
+
+func init() {
+	contextCmd.Flags().StringVar(&contextRig, "rig", "", "Rig the issue belongs to (default: from its prefix)")
+	contextCmd.Flags().IntVar(&contextMaxChars, "max-chars", 16000, "Longest briefing to print (about 4 characters per token)")
+	contextCmd.Flags().IntVar(&contextCommits, "commits", 10, "Most commits to list")
+
+	rootCmd.AddCommand(contextCmd)
+}
+
+// ContextOutput is the structured output for gt context.
+type ContextOutput struct {
+	Issue    string   `json:"issue"`
+	Rig      string   `json:"rig,omitempty"`
+	Markdown string   `json:"markdown"`
+	Cut      []string `json:"cut,omitempty"` // Sections shortened or left out to fit
+}
+
+// contextSection is one part of a briefing.
+type contextSection struct {
+	Title string
+	Body  string
+}
+
+func (s contextSection) String() string {
+	return fmt.Sprintf("## %s\n\n%s\n\n", s.Title, strings.TrimRight(s.Body, "\n"))
+}
+
+func runContext(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	issue, err := beads.New(townRoot).Show(issueID)
+	if err != nil {
+		return fmt.Errorf("getting issue %s: %w", issueID, err)
+	}
+
+	rigName := contextRig
+	if rigName == "" {
+		rigName = rigOfIssue(townRoot, issue.ID)
+	}
+	var r *rig.Rig
+	if rigName != "" {
+		if _, r, err = getRig(rigName); err != nil {
+			return err
+		}
+	}
+
+	header := fmt.Sprintf("# %s: %s\n\n%s\n\n", issue.ID, issue.Title, contextIssueLine(issue))
+	sections := []contextSection{{Title: "Description", Body: issue.Description}}
+	if notes := contextNotes(issue); notes != "" {
+		sections = append(sections, contextSection{Title: "Owner notes", Body: notes})
+	}
+	if r != nil {
+		attempts, err := contextMergeAttempts(r, issue.ID)
+		if err != nil {
+			return err
+		}
+		if attempts != "" {
+			sections = append(sections, contextSection{Title: "Prior merge attempts", Body: attempts})
+		}
+	}
+	if related := contextRelated(issue); related != "" {
+		sections = append(sections, contextSection{Title: "Related issues", Body: related})
+	}
+	if r != nil {
+		if commits := contextCommitList(r, issue.ID, contextCommits); commits != "" {
+			sections = append(sections, contextSection{Title: "Recent commits", Body: commits})
+		}
+	}
+	if strings.TrimSpace(issue.Description) == "" {
+		sections[0].Body = "(none)"
+	}
+
+	markdown, cut := fitContextSections(header, sections, contextMaxChars)
+	if structuredOutput(false) {
+		return renderStructured(ContextOutput{Issue: issue.ID, Rig: rigName, Markdown: markdown, Cut: cut})
+	}
+	fmt.Print(markdown)
+	return nil
+}
+
+// rigOfIssue returns the rig an issue's prefix routes to, or "" for
+// town-level and unrouted issues.
+func rigOfIssue(townRoot, issueID string) string {
+	path := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(issueID))
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0]
+}
+
+// contextIssueLine summarizes an issue's state on one line.
+func contextIssueLine(issue *beads.Issue) string {
+	parts := []string{
+		"**Status:** " + issue.Status,
+		fmt.Sprintf("**Priority:** P%d", issue.Priority),
+	}
+	if issue.Type != "" {
+		parts = append(parts, "**Type:** "+issue.Type)
+	}
+	if issue.Assignee != "" {
+		parts = append(parts, "**Assignee:** "+issue.Assignee)
+	}
+	if labels := strings.Join(issue.Labels, ", "); labels != "" {
+		parts = append(parts, "**Labels:** "+labels)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// contextNotes renders an issue's notes and comments, oldest first.
+func contextNotes(issue *beads.Issue) string {
+	var b strings.Builder
+	if notes := strings.TrimSpace(issue.Notes); notes != "" {
+		b.WriteString(notes + "\n\n")
+	}
+	for _, c := range issue.Comments {
+		fmt.Fprintf(&b, "**%s** (%s):\n%s\n\n", c.Author, c.CreatedAt, strings.TrimSpace(c.Text))
+	}
+	return b.String()
+}
+
+// contextRelated lists an issue's parent, children and dependencies.
+func contextRelated(issue *beads.Issue) string {
+	var lines []string
+	if issue.Parent != "" {
+		lines = append(lines, "- Parent: "+issue.Parent)
+	}
+	for _, child := range issue.Children {
+		lines = append(lines, "- Child: "+child)
+	}
+	for _, d := range issue.Dependencies {
+		lines = append(lines, fmt.Sprintf("- Depends on (%s): %s %s [%s]", depType(d), d.ID, d.Title, d.Status))
+	}
+	for _, d := range issue.Dependents {
+		lines = append(lines, fmt.Sprintf("- Dependent (%s): %s %s [%s]", depType(d), d.ID, d.Title, d.Status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func depType(d beads.IssueDep) string {
+	if d.DependencyType == "" {
+		return "blocks"
+	}
+	return d.DependencyType
+}
+
+// contextMergeAttempts describes the merge requests submitted for an
+// issue: what state each is in, why failed ones failed, and their latest
+// review comments.
+func contextMergeAttempts(r *rig.Rig, issueID string) (string, error) {
+	mrs, err := beads.New(r.BeadsPath()).ListIndexed(beads.ListOptions{Type: "merge-request", Status: "all", Priority: -1})
+	if err != nil {
+		return "", fmt.Errorf("listing merge requests: %w", err)
+	}
+	var b strings.Builder
+	for _, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.SourceIssue != issueID {
+			continue
+		}
+		if fields.Target == "" {
+			fields.Target = r.DefaultBranch()
+		}
+		fmt.Fprintf(&b, "### %s (%s)\n\nBranch %s into %s", mr.ID, refinery.StateOf(mr), fields.Branch, fields.Target)
+		if fields.Worker != "" {
+			fmt.Fprintf(&b, ", by %s", fields.Worker)
+		}
+		b.WriteString(".\n")
+		if failure, ok := failureOf(mr, fields); ok {
+			fmt.Fprintf(&b, "\nFailed (%s): %s\n", failure.Kind, failure.Detail)
+		}
+		if fields.ChangesRequestedBy != "" {
+			fmt.Fprintf(&b, "\nChanges requested by %s\n", fields.ChangesRequestedBy)
+		}
+		if comments, err := refinery.ParseMRComments(fields.Comments); err == nil && len(comments) > 0 {
+			if len(comments) > 3 {
+				comments = comments[len(comments)-3:]
+			}
+			b.WriteString("\nLatest comments:\n")
+			for _, c := range comments {
+				line := fmt.Sprintf("- %s: %s", c.Author, c.Body)
+				if loc := c.Location(); loc != "" {
+					line = fmt.Sprintf("- %s on %s: %s", c.Author, loc, c.Body)
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// contextCommitList lists recent commits on the rig's default branch
+// that mention the issue. Rigs without a mayor clone have none to list.
+func contextCommitList(r *rig.Rig, issueID string, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	g := git.NewGit(filepath.Join(r.Path, "mayor", "rig"))
+	commits, err := g.CommitsMentioning("origin/"+r.DefaultBranch(), issueID, limit)
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, c := range commits {
+		lines = append(lines, fmt.Sprintf("- %s %s", shortSHA(c.SHA), c.Subject))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// contextCutMarker ends a section shortened to fit.
+const contextCutMarker = "\n\n_(cut to fit)_"
+
+// fitContextSections renders a briefing within budget characters. When it
+// doesn't fit, sections are cut from the last up: shortened at a line
+// break, or left out if too little of them would be left. The header is
+// always kept. Returns the markdown and the titles of the sections cut.
+func fitContextSections(header string, sections []contextSection, budget int) (string, []string) {
+	total := len(header)
+	for _, s := range sections {
+		total += len(s.String())
+	}
+	var cut []string
+	for i := len(sections) - 1; i >= 0 && total > budget; i-- {
+		over := total - budget
+		title := sections[i].Title
+		size := len(sections[i].String())
+		keep := len(sections[i].Body) - over - len(contextCutMarker)
+		if nl := strings.LastIndex(sections[i].Body[:max(keep, 0)], "\n"); nl > 0 {
+			keep = nl
+		}
+		if keep < 200 {
+			sections = append(sections[:i], sections[i+1:]...)
+			total -= size
+		} else {
+			sections[i].Body = sections[i].Body[:keep] + contextCutMarker
+			total += len(sections[i].String()) - size
+		}
+		cut = append(cut, title)
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	for _, s := range sections {
+		b.WriteString(s.String())
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n", cut
+}
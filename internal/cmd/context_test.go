@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestFitContextSections(t *testing.T) {
+	header := "# gp-42: Retry\n\n**Status:** open\n\n"
+	long := strings.Repeat("a line of commit history\n", 40)
+	sections := func() []contextSection {
+		return []contextSection{
+			{Title: "Description", Body: "Add retries."},
+			{Title: "Related issues", Body: long},
+			{Title: "Recent commits", Body: long},
+		}
+	}
+
+	all, cut := fitContextSections(header, sections(), 100000)
+	if len(cut) != 0 || !strings.Contains(all, "## Recent commits") {
+		t.Fatalf("everything fits, cut %v", cut)
+	}
+
+	// A little too long: the last section is shortened at a line break
+	got, cut := fitContextSections(header, sections(), len(all)-100)
+	if len(got) > len(all)-100 || !slices.Equal(cut, []string{"Recent commits"}) {
+		t.Errorf("len %d, cut %v; want <= %d with Recent commits cut", len(got), cut, len(all)-100)
+	}
+	if !strings.Contains(got, "history\n\n_(cut to fit)_") {
+		t.Errorf("shortened section should end at a line break with the marker:\n%s", got)
+	}
+
+	// Much too long: the last section goes, the one before is shortened
+	got, cut = fitContextSections(header, sections(), len(all)/2)
+	if len(got) > len(all)/2 || !slices.Equal(cut, []string{"Recent commits", "Related issues"}) {
+		t.Errorf("len %d, cut %v", len(got), cut)
+	}
+	if strings.Contains(got, "## Recent commits") || !strings.HasPrefix(got, header+"## Description") {
+		t.Errorf("briefing:\n%s", got)
+	}
+}
+
+func TestContextRelated(t *testing.T) {
+	issue := &beads.Issue{
+		Parent:       "gp-40",
+		Children:     []string{"gp-43"},
+		Dependencies: []beads.IssueDep{{ID: "gp-41", Title: "Client backoff", Status: "closed"}},
+		Dependents:   []beads.IssueDep{{ID: "gp-44", Title: "Docs", Status: "open", DependencyType: "related"}},
+	}
+	want := `- Parent: gp-40
+- Child: gp-43
+- Depends on (blocks): gp-41 Client backoff [closed]
+- Dependent (related): gp-44 Docs [open]`
+	if got := contextRelated(issue); got != want {
+		t.Errorf("contextRelated =\n%s\nwant\n%s", got, want)
+	}
+	if got := contextRelated(&beads.Issue{}); got != "" {
+		t.Errorf("no relations = %q", got)
+	}
+}
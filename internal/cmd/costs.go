@@ -17,6 +17,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -59,11 +60,13 @@ Examples:
   gt costs --by-role    # Breakdown by role (polecat, witness, etc.)
   gt costs --by-rig     # Breakdown by rig
   gt costs --json       # Output as JSON
+  gt costs --week --by-rig -o csv  # Totals as CSV, for spreadsheets
   gt costs -v           # Show debug output for failures
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs time         # Agent session time per rig, worker, or issue`,
 	RunE: runCosts,
 }
 
@@ -174,6 +177,15 @@ type CostEntry struct {
 	WorkItem  string    `json:"work_item,omitempty"`
 }
 
+// CostsRow is a line of gt costs' CSV/TSV output from the ledger: the
+// total, or the total for a role or rig.
+type CostsRow struct {
+	Period  string  `json:"period"`
+	By      string  `json:"by"` // "total", "role" or "rig"
+	Name    string  `json:"name"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
 // CostsOutput is the JSON output structure.
 type CostsOutput struct {
 	Sessions []SessionCost      `json:"sessions,omitempty"`
@@ -305,6 +317,9 @@ func runLiveCosts() error {
 		return costs[i].Session < costs[j].Session
 	})
 
+	if tabularOutput() {
+		return renderStructured(costs)
+	}
 	if costsJSON {
 		return outputCostsJSON(CostsOutput{
 			Sessions: costs,
@@ -388,6 +403,9 @@ func runCostsFromLedger() error {
 		output.Period = "this week"
 	}
 
+	if tabularOutput() {
+		return renderStructured(costsRows(output))
+	}
 	if costsJSON {
 		return outputCostsJSON(output)
 	}
@@ -450,12 +468,21 @@ func querySessionEvents() []CostEntry {
 		}
 	}
 
-	// Query each beads location and merge results
+	// Query all beads locations in parallel, then merge results in order
+	type locationEntries struct {
+		entries []CostEntry
+		err     error
+	}
+	results := rig.ForEach(beadsLocations, func(location string) locationEntries {
+		entries, err := querySessionEventsFromLocation(location)
+		return locationEntries{entries: entries, err: err}
+	})
+
 	var allEntries []CostEntry
 	seenIDs := make(map[string]bool)
 
-	for _, location := range beadsLocations {
-		entries, err := querySessionEventsFromLocation(location)
+	for i, location := range beadsLocations {
+		entries, err := results[i].entries, results[i].err
 		if err != nil {
 			// Log but continue with other locations
 			if costsVerbose {
@@ -857,6 +884,30 @@ func getTmuxSessionWorkDir(session string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// costsRows turns ledger totals into rows: the total, then each role's and
+// each rig's, by name.
+func costsRows(output CostsOutput) []CostsRow {
+	period := output.Period
+	if period == "" {
+		period = "all"
+	}
+	rows := []CostsRow{{Period: period, By: "total", CostUSD: output.Total}}
+	for _, group := range []struct {
+		by     string
+		totals map[string]float64
+	}{{"role", output.ByRole}, {"rig", output.ByRig}} {
+		names := make([]string, 0, len(group.totals))
+		for name := range group.totals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rows = append(rows, CostsRow{Period: period, By: group.by, Name: name, CostUSD: group.totals[name]})
+		}
+	}
+	return rows
+}
+
 func outputCostsJSON(output CostsOutput) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
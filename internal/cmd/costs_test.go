@@ -112,3 +112,24 @@ func TestDeriveSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestCostsRows(t *testing.T) {
+	rows := costsRows(CostsOutput{
+		Total:  12.5,
+		ByRole: map[string]float64{"witness": 2.5, "polecat": 10},
+		Period: "today",
+	})
+	want := []CostsRow{
+		{Period: "today", By: "total", CostUSD: 12.5},
+		{Period: "today", By: "role", Name: "polecat", CostUSD: 10},
+		{Period: "today", By: "role", Name: "witness", CostUSD: 2.5},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("costsRows = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
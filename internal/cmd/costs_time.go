@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	costsTimeSince string
+	costsTimeBy    string
+)
+
+var costsTimeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Show estimated agent session time per rig, worker, or issue",
+	Long: `Show agent time consumed, estimated from session uptime in the event log.
+
+A session counts from its session_start event until the agent's next
+session end, death, crash, handoff, done, or kill, or until now if it is
+still running. Time is attributed to the issue on the agent's hook.
+
+When session budgets are configured (the "budgets" key in town or rig
+settings), today's usage against each limit is shown as well.
+
+Examples:
+  gt costs time                   # Last 24h by rig
+  gt costs time --by worker       # Per agent
+  gt costs time --by issue --since 7d
+  gt costs time -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCostsTime,
+}
+
+func init() {
+	costsCmd.AddCommand(costsTimeCmd)
+	costsTimeCmd.Flags().StringVar(&costsTimeSince, "since", "24h", "Report time since duration (e.g., 1h, 24h, 7d)")
+	costsTimeCmd.Flags().StringVar(&costsTimeBy, "by", "rig", "Group by: rig, worker, or issue")
+}
+
+// AgentTimeRow is agent time for one rig, worker, or issue.
+type AgentTimeRow struct {
+	Key      string  `json:"key"`
+	Hours    float64 `json:"hours"`
+	Sessions int     `json:"sessions"`
+}
+
+// BudgetUsageRow is today's usage for a role with a configured budget.
+type BudgetUsageRow struct {
+	Rig    string               `json:"rig,omitempty"`
+	Role   string               `json:"role"`
+	Budget config.SessionBudget `json:"budget"`
+	Usage  budget.Usage         `json:"usage"`
+}
+
+// CostsTimeOutput is the structured output of gt costs time.
+type CostsTimeOutput struct {
+	Since      time.Time        `json:"since"`
+	By         string           `json:"by"`
+	Rows       []AgentTimeRow   `json:"rows"`
+	TotalHours float64          `json:"total_hours"`
+	Budgets    []BudgetUsageRow `json:"budgets,omitempty"`
+}
+
+func runCostsTime(cmd *cobra.Command, args []string) error {
+	switch costsTimeBy {
+	case "rig", "worker", "issue":
+	default:
+		return fmt.Errorf("invalid --by %q: must be rig, worker, or issue", costsTimeBy)
+	}
+	d, err := parseDuration(costsTimeSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since duration: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	evts, _, err := events.ReadAll(filepath.Join(townRoot, events.EventsFile), nil)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	now := time.Now()
+	all := budget.Spans(evts, now)
+	out := CostsTimeOutput{
+		Since:   now.Add(-d),
+		By:      costsTimeBy,
+		Rows:    agentTimeRows(budget.Clip(all, now.Add(-d), now), costsTimeBy),
+		Budgets: budgetUsageRows(townRoot, all, now),
+	}
+	for _, r := range out.Rows {
+		out.TotalHours += r.Hours
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	return outputCostsTimeHuman(out)
+}
+
+// agentTimeRows sums span time by rig, worker address, or issue, largest
+// first. Town-level agents group under "(town)" and unhooked time under
+// "(no issue)".
+func agentTimeRows(spans []budget.Span, by string) []AgentTimeRow {
+	totals := map[string]*AgentTimeRow{}
+	for _, s := range spans {
+		var key string
+		switch by {
+		case "worker":
+			key = s.Agent.Address()
+		case "issue":
+			key = s.Issue
+			if key == "" {
+				key = "(no issue)"
+			}
+		default:
+			key = s.Agent.Rig
+			if key == "" {
+				key = "(town)"
+			}
+		}
+		row := totals[key]
+		if row == nil {
+			row = &AgentTimeRow{Key: key}
+			totals[key] = row
+		}
+		row.Hours += s.Duration().Hours()
+		if !s.Continued {
+			row.Sessions++
+		}
+	}
+
+	rows := make([]AgentTimeRow, 0, len(totals))
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Hours != rows[j].Hours {
+			return rows[i].Hours > rows[j].Hours
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
+
+// budgetUsageRows reports usage for each rig and role seen in spans that has
+// a budget configured.
+func budgetUsageRows(townRoot string, spans []budget.Span, now time.Time) []BudgetUsageRow {
+	townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+
+	type rigRole struct{ rig, role string }
+	seen := map[rigRole]bool{}
+	var keys []rigRole
+	for _, s := range spans {
+		k := rigRole{s.Agent.Rig, s.Agent.Role}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rig != keys[j].rig {
+			return keys[i].rig < keys[j].rig
+		}
+		return keys[i].role < keys[j].role
+	})
+
+	rigSettings := map[string]*config.RigSettings{}
+	var rows []BudgetUsageRow
+	for _, k := range keys {
+		if k.rig != "" {
+			if _, ok := rigSettings[k.rig]; !ok {
+				rigSettings[k.rig], _ = config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, k.rig)))
+			}
+		}
+		b := budget.For(townSettings, rigSettings[k.rig], k.role)
+		if b == (config.SessionBudget{}) {
+			continue
+		}
+		rows = append(rows, BudgetUsageRow{
+			Rig:    k.rig,
+			Role:   k.role,
+			Budget: b,
+			Usage:  budget.UsageFor(spans, k.rig, k.role, now),
+		})
+	}
+	return rows
+}
+
+func outputCostsTimeHuman(out CostsTimeOutput) error {
+	fmt.Printf("%s Agent time since %s\n\n", style.Bold.Render("⏱"), out.Since.Local().Format("2006-01-02 15:04"))
+	if len(out.Rows) == 0 {
+		fmt.Println(style.Dim.Render("No agent sessions recorded in this period."))
+	} else {
+		fmt.Printf("  %-36s %9s %9s\n", out.By, "Hours", "Sessions")
+		for _, r := range out.Rows {
+			fmt.Printf("  %-36s %9.2f %9d\n", r.Key, r.Hours, r.Sessions)
+		}
+		fmt.Printf("\n  %-36s %9.2f\n", "Total", out.TotalHours)
+	}
+
+	if len(out.Budgets) == 0 {
+		return nil
+	}
+	fmt.Printf("\n%s Budgets (today)\n\n", style.Bold.Render("◆"))
+	for _, b := range out.Budgets {
+		scope := b.Role
+		if b.Rig != "" {
+			scope = b.Rig + "/" + b.Role
+		}
+		fmt.Printf("  %-24s", scope)
+		if b.Budget.MaxSessionHoursPerDay > 0 {
+			fmt.Printf("  %.2f/%.4gh per day", b.Usage.SessionHoursToday, b.Budget.MaxSessionHoursPerDay)
+		}
+		if b.Budget.MaxRespawnsPerHour > 0 {
+			fmt.Printf("  %d/%d respawns per hour", b.Usage.RespawnsLastHour, b.Budget.MaxRespawnsPerHour)
+		}
+		if budget.Check(b.Budget, b.Usage, b.Rig, b.Role) != nil {
+			fmt.Printf("  %s", style.Warning.Render("exceeded"))
+		}
+		fmt.Println()
+	}
+	return nil
+}
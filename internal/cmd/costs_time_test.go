@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+)
+
+func TestAgentTimeRows(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	toast := budget.Agent{Rig: "greenplace", Role: budget.RolePolecat, Name: "Toast"}
+	spans := []budget.Span{
+		{Agent: toast, Issue: "gp-1", Start: t0, End: t0.Add(30 * time.Minute)},
+		{Agent: toast, Issue: "gp-2", Start: t0.Add(30 * time.Minute), End: t0.Add(2 * time.Hour), Continued: true},
+		{Agent: budget.Agent{Role: budget.RoleMayor}, Start: t0, End: t0.Add(time.Hour)},
+	}
+
+	rows := agentTimeRows(spans, "rig")
+	if len(rows) != 2 || rows[0].Key != "greenplace" || rows[0].Hours != 2 || rows[0].Sessions != 1 {
+		t.Errorf("by rig = %+v", rows)
+	}
+	if rows[1].Key != "(town)" {
+		t.Errorf("town-level agents should group under (town), got %q", rows[1].Key)
+	}
+
+	rows = agentTimeRows(spans, "issue")
+	if len(rows) != 3 || rows[0].Key != "gp-2" || rows[0].Hours != 1.5 || rows[0].Sessions != 0 {
+		t.Errorf("by issue = %+v", rows)
+	}
+
+	rows = agentTimeRows(spans, "worker")
+	if len(rows) != 2 || rows[0].Key != "greenplace/polecats/Toast" {
+		t.Errorf("by worker = %+v", rows)
+	}
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crash"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Crashes command flags
+var (
+	crashesListAll bool
+	crashesAckAll  bool
+)
+
+var crashesCmd = &cobra.Command{
+	Use:     "crashes",
+	GroupID: GroupDiag,
+	Short:   "Review crash reports for dead agent sessions",
+	Long: `Review crash report bundles captured when agent sessions die.
+
+When a polecat or Mayor session exits with a non-zero status, the tmux
+pane-died hook captures a bundle under ~/gt/.runtime/crashes/<id>/:
+
+  report.json      Agent, session, exit code, and what was captured
+  pane.txt         The last lines of the tmux pane
+  git-status.txt   git status of the session's worktree
+  events.jsonl     The most recent town events
+
+Reports stay pending until acknowledged. Pending reports are flagged by
+'gt doctor' and 'gt status'.
+
+Subcommands:
+  list    List crash reports (pending only, unless --all)
+  show    Print a crash report bundle
+  ack     Mark crash reports as reviewed`,
+	RunE: requireSubcommand,
+}
+
+var crashesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List crash reports",
+	Long: `List crash reports, newest first. Only pending reports are shown unless
+--all is given.
+
+Examples:
+  gt crashes list
+  gt crashes list --all -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCrashesList,
+}
+
+var crashesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Print a crash report bundle",
+	Long: `Print a crash report and the files in its bundle.
+
+Examples:
+  gt crashes show 20261015T120304-greenplace-Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCrashesShow,
+}
+
+var crashesAckCmd = &cobra.Command{
+	Use:   "ack [id...]",
+	Short: "Mark crash reports as reviewed",
+	Long: `Mark crash reports as reviewed so they are no longer pending. The
+bundles are kept.
+
+Examples:
+  gt crashes ack 20261015T120304-greenplace-Toast
+  gt crashes ack --all`,
+	RunE: runCrashesAck,
+}
+
+func init() {
+	crashesListCmd.Flags().BoolVarP(&crashesListAll, "all", "a", false, "Include acknowledged reports")
+	crashesAckCmd.Flags().BoolVarP(&crashesAckAll, "all", "a", false, "Acknowledge every pending report")
+
+	crashesCmd.AddCommand(crashesListCmd)
+	crashesCmd.AddCommand(crashesShowCmd)
+	crashesCmd.AddCommand(crashesAckCmd)
+	rootCmd.AddCommand(crashesCmd)
+}
+
+// CrashShowOutput is the structured output for gt crashes show.
+type CrashShowOutput struct {
+	Report   *crash.Report     `json:"report"`
+	Path     string            `json:"path"`
+	Contents map[string]string `json:"contents"`
+}
+
+func runCrashesList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var reports []*crash.Report
+	if crashesListAll {
+		reports, err = crash.List(townRoot)
+	} else {
+		reports, err = crash.Pending(townRoot)
+	}
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		if reports == nil {
+			reports = []*crash.Report{}
+		}
+		return renderStructured(reports)
+	}
+
+	if len(reports) == 0 {
+		if crashesListAll {
+			fmt.Println(style.Dim.Render("No crash reports"))
+		} else {
+			fmt.Println(style.Dim.Render("No pending crash reports"))
+		}
+		return nil
+	}
+
+	for _, r := range reports {
+		age := time.Since(r.CapturedAt).Round(time.Minute)
+		marker := style.Warning.Render("●")
+		if r.Reviewed {
+			marker = style.Dim.Render("○")
+		}
+		fmt.Printf("%s %s  %s exit %d  %s\n", marker, style.Bold.Render(r.ID), r.Agent, r.ExitCode,
+			style.Dim.Render(fmt.Sprintf("%s ago", age)))
+	}
+	return nil
+}
+
+func runCrashesShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	r, err := crash.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	dir := crash.BundlePath(townRoot, r.ID)
+
+	out := CrashShowOutput{Report: r, Path: dir, Contents: map[string]string{}}
+	for _, name := range r.Files {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: file listed in the crash report
+		if err != nil {
+			continue
+		}
+		out.Contents[name] = string(data)
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Crash:"), r.ID)
+	fmt.Printf("  Agent:    %s\n", r.Agent)
+	if r.Session != "" {
+		fmt.Printf("  Session:  %s\n", r.Session)
+	}
+	fmt.Printf("  Exit:     %d\n", r.ExitCode)
+	fmt.Printf("  Captured: %s\n", r.CapturedAt.Local().Format("2006-01-02 15:04:05"))
+	if r.WorkDir != "" {
+		fmt.Printf("  Workdir:  %s\n", r.WorkDir)
+	}
+	if r.Reviewed {
+		fmt.Printf("  Status:   %s\n", style.Dim.Render("reviewed"))
+	} else {
+		fmt.Printf("  Status:   %s\n", style.Warning.Render("pending"))
+	}
+	fmt.Printf("  Bundle:   %s\n", style.Dim.Render(dir))
+	for _, e := range r.Errors {
+		fmt.Printf("  %s %s\n", style.Warning.Render("not captured:"), e)
+	}
+
+	for _, name := range []string{crash.PaneFile, crash.GitStatusFile, crash.EventsFile} {
+		content, ok := out.Contents[name]
+		if !ok {
+			continue
+		}
+		fmt.Printf("\n%s\n%s", style.Bold.Render("── "+name+" ──"), content)
+	}
+	return nil
+}
+
+func runCrashesAck(cmd *cobra.Command, args []string) error {
+	if crashesAckAll == (len(args) > 0) {
+		return fmt.Errorf("specify crash report IDs or --all")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	ids := args
+	if crashesAckAll {
+		pending, err := crash.Pending(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, r := range pending {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	for _, id := range ids {
+		if _, err := crash.Acknowledge(townRoot, id); err != nil {
+			return err
+		}
+		fmt.Printf("%s Acknowledged %s\n", style.Success.Render("✓"), id)
+	}
+	if len(ids) == 0 {
+		fmt.Println(style.Dim.Render("No pending crash reports"))
+	}
+	return nil
+}
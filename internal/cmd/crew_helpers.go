@@ -6,7 +6,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -24,17 +23,13 @@ func inferRigFromCwd(townRoot string) (string, error) {
 		return "", err
 	}
 
-	// Check if cwd is within a rig
-	rel, err := filepath.Rel(townRoot, cwd)
+	// Check if cwd is within a rig - first component is the rig name
+	parts, err := workspace.RelParts(townRoot, cwd)
 	if err != nil {
 		return "", fmt.Errorf("not in workspace")
 	}
 
-	// Normalize and split path - first component is the rig name
-	rel = filepath.ToSlash(rel)
-	parts := strings.Split(rel, "/")
-
-	if len(parts) > 0 && parts[0] != "" && parts[0] != "." {
+	if len(parts) > 0 {
 		return parts[0], nil
 	}
 
@@ -109,16 +104,12 @@ func detectCrewFromCwd() (*crewDetection, error) {
 		return nil, fmt.Errorf("not in Gas Town workspace")
 	}
 
-	// Get relative path from town root
-	relPath, err := filepath.Rel(townRoot, cwd)
+	// Get path components relative to town root
+	parts, err := workspace.RelParts(townRoot, cwd)
 	if err != nil {
 		return nil, fmt.Errorf("getting relative path: %w", err)
 	}
 
-	// Normalize and split path
-	relPath = filepath.ToSlash(relPath)
-	parts := strings.Split(relPath, "/")
-
 	// Look for pattern: <rig>/crew/<name>/...
 	// Minimum: rig, crew, name = 3 parts
 	if len(parts) < 3 {
@@ -167,7 +158,7 @@ func execAgent(cfg *config.RuntimeConfig, prompt string) error {
 	if prompt != "" {
 		args = append(args, prompt)
 	}
-	return syscall.Exec(agentPath, args, os.Environ())
+	return execReplace(agentPath, args, os.Environ())
 }
 
 // execRuntime execs the runtime CLI, replacing the current process.
@@ -190,7 +181,7 @@ func execRuntime(prompt, rigPath, configDir string) error {
 		env = append(env, fmt.Sprintf("%s=%s", runtimeConfig.Session.ConfigDirEnv, configDir))
 	}
 
-	return syscall.Exec(binPath, args, env)
+	return execReplace(binPath, args, env)
 }
 
 // isInTmuxSession checks if we're currently inside the target tmux session.
@@ -236,6 +227,30 @@ func attachToTmuxSession(sessionID string) error {
 	return cmd.Run()
 }
 
+// attachToTmuxSessionReadOnly attaches to a tmux session as a read-only
+// client, so keystrokes are not sent to the session.
+// If already inside tmux, opens a new window holding a read-only client,
+// since switch-client would leave the current client read-only.
+func attachToTmuxSessionReadOnly(sessionID string) error {
+	tmuxPath, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux not found: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if os.Getenv("TMUX") != "" {
+		// Inside tmux: nested clients need TMUX unset
+		cmd = exec.Command(tmuxPath, "new-window", "-n", sessionID,
+			fmt.Sprintf("env -u TMUX %s attach-session -r -t '%s'", tmuxPath, sessionID))
+	} else {
+		cmd = exec.Command(tmuxPath, "attach-session", "-r", "-t", sessionID)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // ensureDefaultBranch checks if a git directory is on the default branch.
 // If not, warns the user and offers to switch.
 // Returns true if on default branch (or switched to it), false if user declined.
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/permission"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -47,6 +48,9 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			lastErr = err
 			continue
 		}
+		if err := requirePermission(filepath.Dir(r.Path), permission.ActionCrewRemove, r.Name+"/crew/"+name, nil); err != nil {
+			return err
+		}
 
 		// Check for running session (unless forced)
 		if !forceRemove {
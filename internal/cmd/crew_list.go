@@ -43,20 +43,22 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 		rigs = []*rig.Rig{r}
 	}
 
-	// Check session and git status for each worker
+	// Check session and git status for each worker, a rig at a time in parallel
 	t := tmux.NewTmux()
-	var items []CrewListItem
-
-	for _, r := range rigs {
+	type rigCrew struct {
+		items []CrewListItem
+		err   error
+	}
+	results := rig.ForEach(rigs, func(r *rig.Rig) rigCrew {
 		crewGit := git.NewGit(r.Path)
 		crewMgr := crew.NewManager(r, crewGit)
 
 		workers, err := crewMgr.List()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to list crew workers in %s: %v\n", r.Name, err)
-			continue
+			return rigCrew{err: err}
 		}
 
+		var items []CrewListItem
 		for _, w := range workers {
 			sessionID := crewSessionName(r.Name, w.Name)
 			hasSession, _ := t.HasSession(sessionID)
@@ -76,6 +78,16 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 				GitClean:   gitClean,
 			})
 		}
+		return rigCrew{items: items}
+	})
+
+	var items []CrewListItem
+	for i, res := range results {
+		if res.err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to list crew workers in %s: %v\n", rigs[i].Name, res.err)
+			continue
+		}
+		items = append(items, res.items...)
 	}
 
 	if len(items) == 0 {
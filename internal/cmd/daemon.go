@@ -20,10 +20,20 @@ var daemonCmd = &cobra.Command{
 	RunE:    requireSubcommand,
 	Long: `Manage the Gas Town background daemon.
 
-The daemon is a simple Go process that:
-- Pokes agents periodically (heartbeat)
+The daemon is a simple Go process that runs the town's periodic tasks,
+each on its own interval:
+- Keeps patrol agents running (Deacon, Witnesses, Refineries)
 - Processes lifecycle requests (cycle, restart, shutdown)
-- Restarts sessions when agents request cycling
+- Checks for stuck workers and orphaned work
+- Reports MRs past their merge SLA, sends the Mayor's digest, archives
+  closed MRs and syncs rig clones
+
+'gt daemon status' lists the tasks. Tune them in mayor/daemon.json:
+
+  "tasks": {
+    "sla": {"interval": "1m"},
+    "orphan-processes": {"enabled": false}
+  }
 
 The daemon is a "dumb scheduler" - all intelligence is in agents.`,
 }
@@ -47,15 +57,20 @@ var daemonStopCmd = &cobra.Command{
 var daemonStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show daemon status",
-	Long:  `Show the current status of the Gas Town daemon.`,
-	RunE:  runDaemonStatus,
+	Long: `Show the current status of the Gas Town daemon and its tasks: how often
+each runs, and when it last did.`,
+	RunE: runDaemonStatus,
 }
 
 var daemonLogsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "View daemon logs",
-	Long:  `View the daemon log file.`,
-	RunE:  runDaemonLogs,
+	Long: `View the daemon log file.
+
+With --task, show the structured task log instead: each run of the task
+(or of every task, with --task all), what triggered it, how long it took
+and whether it panicked.`,
+	RunE: runDaemonLogs,
 }
 
 var daemonRunCmd = &cobra.Command{
@@ -68,6 +83,7 @@ var daemonRunCmd = &cobra.Command{
 var (
 	daemonLogLines int
 	daemonLogFollow bool
+	daemonLogTask string
 )
 
 func init() {
@@ -79,6 +95,7 @@ func init() {
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonLogsCmd.Flags().StringVar(&daemonLogTask, "task", "", "Show a task's runs from the task log (all = every task)")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -174,6 +191,19 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
+	state, stateErr := daemon.LoadState(townRoot)
+	if stateErr != nil || !running {
+		state = &daemon.State{} // A stopped daemon's schedule is stale
+	}
+	tasks := daemonTaskStatuses(townRoot, state, running)
+
+	if structuredOutput(false) {
+		out := DaemonStatusOutput{Running: running, Tasks: tasks}
+		if running {
+			out.PID, out.StartedAt, out.LastHeartbeat = pid, state.StartedAt, state.LastHeartbeat
+		}
+		return renderStructured(out)
+	}
 
 	if running {
 		fmt.Printf("%s Daemon is %s (PID %d)\n",
@@ -181,9 +211,8 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("running"),
 			pid)
 
-		// Load state for more details
-		state, err := daemon.LoadState(townRoot)
-		if err == nil && !state.StartedAt.IsZero() {
+		// Use state for more details
+		if stateErr == nil && !state.StartedAt.IsZero() {
 			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
 			if !state.LastHeartbeat.IsZero() {
 				fmt.Printf("  Last heartbeat: %s (#%d)\n",
@@ -201,10 +230,12 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+		printDaemonTasks(tasks)
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
 			"not running")
+		printDaemonTasks(tasks)
 		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt daemon start"))
 	}
 
@@ -230,6 +261,10 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if daemonLogTask != "" {
+		return printDaemonTaskLog(townRoot, daemonLogTask, daemonLogLines)
+	}
+
 	logFile := filepath.Join(townRoot, "daemon", "daemon.log")
 
 	if _, err := os.Stat(logFile); os.IsNotExist(err) {
@@ -245,10 +280,12 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 	}
 
 	// Use tail -n for last N lines
-	tailCmd := exec.Command("tail", "-n", fmt.Sprintf("%d", daemonLogLines), logFile)
-	tailCmd.Stdout = os.Stdout
-	tailCmd.Stderr = os.Stderr
-	return tailCmd.Run()
+	return withPager(func() error {
+		tailCmd := exec.Command("tail", "-n", fmt.Sprintf("%d", daemonLogLines), logFile)
+		tailCmd.Stdout = os.Stdout
+		tailCmd.Stderr = os.Stderr
+		return tailCmd.Run()
+	})
 }
 
 func runDaemonRun(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var daemonRunOnceCmd = &cobra.Command{
+	Use:   "run-once <task>",
+	Short: "Run one of the daemon's tasks now",
+	Long: `Run one of the daemon's periodic tasks in the foreground, now, whether
+or not it is enabled or due. The run is logged with the daemon's (see
+'gt daemon logs --task'). 'gt daemon status' lists the tasks.
+
+Examples:
+  gt daemon run-once sla
+  gt daemon run-once rig-sync`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDaemonRunOnce,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonRunOnceCmd)
+}
+
+// DaemonTaskStatus is a task in gt daemon status's structured output.
+type DaemonTaskStatus struct {
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	daemon.TaskState
+	NextRun *time.Time `json:"next_run,omitempty"` // While the daemon runs
+}
+
+// DaemonStatusOutput is the structured output for gt daemon status.
+type DaemonStatusOutput struct {
+	Running       bool               `json:"running"`
+	PID           int                `json:"pid,omitempty"`
+	StartedAt     time.Time          `json:"started_at,omitempty"`
+	LastHeartbeat time.Time          `json:"last_heartbeat,omitempty"`
+	Tasks         []DaemonTaskStatus `json:"tasks"`
+}
+
+// daemonTaskStatuses describes every task: its schedule and last run from
+// the daemon's state if it has run, otherwise from mayor/daemon.json.
+func daemonTaskStatuses(townRoot string, state *daemon.State, running bool) []DaemonTaskStatus {
+	cfg := daemon.LoadPatrolConfig(townRoot)
+	statuses := []DaemonTaskStatus{}
+	for _, t := range daemon.Tasks() {
+		ts := DaemonTaskStatus{Name: t.Name, Summary: t.Summary}
+		if s := state.Tasks[t.Name]; s != nil {
+			ts.TaskState = *s
+		} else {
+			ts.Enabled = daemon.TaskEnabled(cfg, t)
+			ts.Interval, _ = daemon.TaskInterval(cfg, t)
+		}
+		if running && ts.Enabled && !ts.LastRun.IsZero() {
+			next := ts.LastRun.Add(ts.Interval)
+			ts.NextRun = &next
+		}
+		statuses = append(statuses, ts)
+	}
+	return statuses
+}
+
+// printDaemonTasks prints the tasks' schedules and last runs.
+func printDaemonTasks(tasks []DaemonTaskStatus) {
+	fmt.Printf("\n  Tasks:\n")
+	for _, t := range tasks {
+		if !t.Enabled {
+			fmt.Printf("    %-17s %s\n", t.Name, style.Dim.Render("disabled"))
+			continue
+		}
+		line := fmt.Sprintf("    %-17s every %-6s", t.Name, formatTaskInterval(t.Interval))
+		if !t.LastRun.IsZero() {
+			line += fmt.Sprintf("  last %s (%s)", formatAge(t.LastRun), t.LastDuration.Round(time.Millisecond))
+		}
+		if t.LastStatus == daemon.TaskPanic {
+			line += "  " + style.Error.Render("panicked")
+		}
+		fmt.Println(line)
+	}
+}
+
+// formatTaskInterval prints an interval without trailing zero units.
+func formatTaskInterval(d time.Duration) string {
+	s := d.String()
+	if d >= time.Minute && d%time.Minute == 0 {
+		s = s[:len(s)-2] // "3m0s" → "3m"
+	}
+	if d >= time.Hour && d%time.Hour == 0 {
+		s = s[:len(s)-2] // "1h0m" → "1h"
+	}
+	return s
+}
+
+// printDaemonTaskLog prints runs from the task log.
+func printDaemonTaskLog(townRoot, task string, n int) error {
+	if task == "all" {
+		task = ""
+	} else if _, ok := daemon.FindTask(task); !ok {
+		return fmt.Errorf("unknown task %q (see 'gt daemon status')", task)
+	}
+	runs, err := daemon.ReadTaskLog(townRoot, task, n)
+	if err != nil {
+		return fmt.Errorf("reading task log: %w", err)
+	}
+	if runs == nil {
+		runs = []daemon.TaskRun{}
+	}
+	if structuredOutput(false) {
+		return renderStructured(runs)
+	}
+	if len(runs) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No task runs logged"))
+		return nil
+	}
+	for _, r := range runs {
+		status := style.Success.Render(r.Status)
+		if r.Status != daemon.TaskOK {
+			status = style.Error.Render(r.Status)
+		}
+		fmt.Printf("%s  %-17s %-8s %-8s %s\n", r.Started.Format("2006-01-02 15:04:05"), r.Task, r.Trigger,
+			r.Duration.Round(time.Millisecond), status)
+		if r.Error != "" {
+			fmt.Printf("  %s\n", r.Error)
+		}
+	}
+	return nil
+}
+
+func runDaemonRunOnce(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, ok := daemon.FindTask(args[0]); !ok {
+		return fmt.Errorf("unknown task %q (see 'gt daemon status')", args[0])
+	}
+	d, err := daemon.New(daemon.DefaultConfig(townRoot))
+	if err != nil {
+		return fmt.Errorf("creating daemon: %w", err)
+	}
+	run, err := d.RunOnce(args[0])
+	if err != nil {
+		return err
+	}
+	if run.Status != daemon.TaskOK {
+		return fmt.Errorf("task %s %s: %s", run.Task, run.Status, run.Error)
+	}
+	fmt.Printf("%s Ran %s in %s %s\n", style.Success.Render("✓"), run.Task, run.Duration.Round(time.Millisecond),
+		style.Dim.Render("(details in 'gt daemon logs')"))
+	return nil
+}
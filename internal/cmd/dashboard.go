@@ -29,6 +29,10 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+POST /api/chatops lets chat bots and slash commands check status, retry or
+approve MRs, and pause queues, with the tokens and scopes in
+settings/chatops.json.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
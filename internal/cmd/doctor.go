@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -130,6 +131,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewTownRootBranchCheck())
 	d.Register(doctor.NewPreCheckoutHookCheck())
 	d.Register(doctor.NewDaemonCheck())
+	d.Register(doctor.NewSessionBackendCheck())
 	d.Register(doctor.NewRepoFingerprintCheck())
 	d.Register(doctor.NewBootHealthCheck())
 	d.Register(doctor.NewBeadsDatabaseCheck())
@@ -151,10 +153,12 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewBeadsSyncOrphanCheck())
 	d.Register(doctor.NewBeadsSyncWorktreeCheck())
 	d.Register(doctor.NewCloneDivergenceCheck())
+	d.Register(doctor.NewRefineryJournalCheck())
 	d.Register(doctor.NewIdentityCollisionCheck())
 	d.Register(doctor.NewLinkedPaneCheck())
 	d.Register(doctor.NewThemeCheck())
 	d.Register(doctor.NewCrashReportCheck())
+	d.Register(doctor.NewPendingCrashCheck())
 	d.Register(doctor.NewEnvVarsCheck())
 
 	// Patrol system checks
@@ -208,6 +212,22 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if structuredOutput(false) {
+		var report *doctor.Report
+		if doctorFix {
+			report = d.Fix(ctx)
+		} else {
+			report = d.Run(ctx)
+		}
+		if err := renderStructured(newDoctorOutput(report)); err != nil {
+			return err
+		}
+		if report.HasErrors() {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
 	// Run checks with streaming output
 	fmt.Println() // Initial blank line
 	var report *doctor.Report
@@ -227,3 +247,48 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// DoctorOutput is the structured output for gt doctor.
+type DoctorOutput struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Total     int                 `json:"total"`
+	OK        int                 `json:"ok"`
+	Warnings  int                 `json:"warnings"`
+	Errors    int                 `json:"errors"`
+	Checks    []DoctorCheckOutput `json:"checks"`
+}
+
+// DoctorCheckOutput is a single check result in DoctorOutput.
+type DoctorCheckOutput struct {
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	Category  string   `json:"category,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Details   []string `json:"details,omitempty"`
+	FixHint   string   `json:"fix_hint,omitempty"`
+	ElapsedMs int64    `json:"elapsed_ms"`
+}
+
+// newDoctorOutput converts a doctor report into its structured output form.
+func newDoctorOutput(report *doctor.Report) DoctorOutput {
+	out := DoctorOutput{
+		Timestamp: report.Timestamp,
+		Total:     report.Summary.Total,
+		OK:        report.Summary.OK,
+		Warnings:  report.Summary.Warnings,
+		Errors:    report.Summary.Errors,
+		Checks:    make([]DoctorCheckOutput, 0, len(report.Checks)),
+	}
+	for _, c := range report.Checks {
+		out.Checks = append(out.Checks, DoctorCheckOutput{
+			Name:      c.Name,
+			Status:    strings.ToLower(c.Status.String()),
+			Category:  c.Category,
+			Message:   c.Message,
+			Details:   c.Details,
+			FixHint:   c.FixHint,
+			ElapsedMs: c.Elapsed.Milliseconds(),
+		})
+	}
+	return out
+}
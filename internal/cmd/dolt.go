@@ -247,10 +247,12 @@ func runDoltLogs(cmd *cobra.Command, args []string) error {
 	}
 
 	// Use tail -n for last N lines
-	tailCmd := exec.Command("tail", "-n", strconv.Itoa(doltLogLines), config.LogFile)
-	tailCmd.Stdout = os.Stdout
-	tailCmd.Stderr = os.Stderr
-	return tailCmd.Run()
+	return withPager(func() error {
+		tailCmd := exec.Command("tail", "-n", strconv.Itoa(doltLogLines), config.LogFile)
+		tailCmd.Stdout = os.Stdout
+		tailCmd.Stderr = os.Stderr
+		return tailCmd.Run()
+	})
 }
 
 func runDoltSQL(cmd *cobra.Command, args []string) error {
@@ -9,10 +9,12 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claim"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -26,11 +28,21 @@ var doneCmd = &cobra.Command{
 	Short:   "Signal work ready for merge queue",
 	Long: `Signal that your work is complete and ready for the merge queue.
 
-This is a convenience command for polecats that:
-1. Submits the current branch to the merge queue
-2. Auto-detects issue ID from branch name
-3. Notifies the Witness with the exit outcome
-4. Exits the Claude session (polecats don't stay alive after completion)
+This is a convenience command for polecats that, in order:
+1. Runs the rig's checks in your workspace, as gt check does
+2. Commits any uncommitted changes (message: -m, or the issue's title,
+   in the rig's commit message convention)
+3. Pushes the branch and submits it to the merge queue, with the issue
+   (auto-detected from the branch name), target, priority, worker and
+   reviewers filled in
+4. Marks the issue in_progress until the Refinery merges it, and releases
+   your claim on it
+5. Notifies the Witness with the exit outcome
+6. Exits the Claude session (polecats don't stay alive after completion)
+
+Steps 1-4 are for COMPLETED only. If one fails, gt done stops there and
+says which step failed: nothing after it has happened, so fix the problem
+and run gt done again. Failing checks exit with code 8.
 
 Exit statuses:
   COMPLETED      - Work done, MR submitted (default)
@@ -47,6 +59,8 @@ Phase handoff workflow:
 Examples:
   gt done                              # Submit branch, notify COMPLETED, exit session
   gt done --issue gt-abc               # Explicit issue ID
+  gt done -m "Fix login redirect"      # Commit message for uncommitted work
+  gt done --skip-checks                # Leave the checks to the Refinery
   gt done --status ESCALATED           # Signal blocker, skip MR
   gt done --status DEFERRED            # Pause work, skip MR
   gt done --phase-complete --gate g-x  # Phase done, waiting on gate g-x`,
@@ -60,6 +74,9 @@ var (
 	donePhaseComplete bool
 	doneGate          string
 	doneCleanupStatus string
+	doneSkipChecks    bool
+	doneMessage       string
+	doneNoCommit      bool
 )
 
 // Valid exit types for gt done
@@ -77,6 +94,9 @@ func init() {
 	doneCmd.Flags().BoolVar(&donePhaseComplete, "phase-complete", false, "Signal phase complete - await gate before continuing")
 	doneCmd.Flags().StringVar(&doneGate, "gate", "", "Gate bead ID to wait on (with --phase-complete)")
 	doneCmd.Flags().StringVar(&doneCleanupStatus, "cleanup-status", "", "Git cleanup status: clean, uncommitted, unpushed, stash, unknown (ZFC: agent-observed)")
+	doneCmd.Flags().BoolVar(&doneSkipChecks, "skip-checks", false, "Don't run the rig's checks before submitting")
+	doneCmd.Flags().StringVarP(&doneMessage, "message", "m", "", "Commit message for uncommitted changes (default: the issue's title and ID)")
+	doneCmd.Flags().BoolVar(&doneNoCommit, "no-commit", false, "Refuse to complete with uncommitted changes instead of committing them")
 
 	rootCmd.AddCommand(doneCmd)
 }
@@ -161,6 +181,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 
 	// Auto-detect cleanup status if not explicitly provided
 	// This prevents premature polecat cleanup by ensuring witness knows git state
+	cleanupDetected := doneCleanupStatus == ""
 	if doneCleanupStatus == "" {
 		if !cwdAvailable {
 			// Can't detect git state without working directory, default to unknown
@@ -195,7 +216,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse branch info
-	info := parseBranchName(branch)
+	info := parseRigBranchName(rigBranchPolicy(rigName), branch)
 
 	// Override with explicit flags
 	issueID := doneIssue
@@ -259,13 +280,29 @@ func runDone(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("cannot complete: working directory not available (worktree deleted?)\nUse --status DEFERRED to exit without completing")
 		}
 
-		// Block if there are uncommitted changes (would be lost on completion)
+		// Step 1: the rig's checks, against the work as it stands
+		if !doneSkipChecks {
+			checkDir := cwd
+			if root, err := detectCloneRoot(); err == nil {
+				checkDir = root
+			}
+			if err := runDoneChecks(townRoot, rigName, checkDir); err != nil {
+				return err
+			}
+		}
+
+		// Step 2: commit uncommitted changes (they would be lost on completion)
 		workStatus, err := g.CheckUncommittedWork()
 		if err != nil {
 			return fmt.Errorf("checking git status: %w", err)
 		}
 		if workStatus.HasUncommittedChanges {
-			return fmt.Errorf("cannot complete: uncommitted changes would be lost\nCommit your changes first, or use --status DEFERRED to exit without completing\nUncommitted: %s", workStatus.String())
+			if doneNoCommit {
+				return fmt.Errorf("cannot complete: uncommitted changes would be lost\nCommit your changes first, or use --status DEFERRED to exit without completing\nUncommitted: %s", workStatus.String())
+			}
+			if err := commitDoneWork(filepath.Join(townRoot, rigName), g, beads.New(beads.ResolveBeadsDir(cwd)), issueID); err != nil {
+				return err
+			}
 		}
 
 		// Check if branch has commits ahead of origin/default
@@ -293,16 +330,6 @@ func runDone(cmd *cobra.Command, args []string) error {
 			goto notifyWitness
 		}
 
-		// CRITICAL: Push branch BEFORE creating MR bead (hq-6dk53, hq-a4ksk)
-		// The MR bead triggers Refinery to process this branch. If the branch
-		// isn't pushed yet, Refinery finds nothing to merge. The worktree gets
-		// nuked at the end of gt done, so the commits are lost forever.
-		fmt.Printf("Pushing branch to remote...\n")
-		if err := g.Push("origin", branch, false); err != nil {
-			return fmt.Errorf("pushing branch '%s' to origin: %w\nCommits exist locally but failed to push. Fix the issue and retry.", branch, err)
-		}
-		fmt.Printf("%s Branch pushed to origin\n", style.Bold.Render("✓"))
-
 		if issueID == "" {
 			return fmt.Errorf("cannot determine source issue from branch '%s'; use --issue to specify", branch)
 		}
@@ -311,36 +338,11 @@ func runDone(cmd *cobra.Command, args []string) error {
 		bd := beads.New(beads.ResolveBeadsDir(cwd))
 
 		// Check for no_merge flag - if set, skip merge queue and notify for review
-		sourceIssueForNoMerge, err := bd.Show(issueID)
-		if err == nil {
-			attachmentFields := beads.ParseAttachmentFields(sourceIssueForNoMerge)
-			if attachmentFields != nil && attachmentFields.NoMerge {
-				fmt.Printf("%s No-merge mode: skipping merge queue\n", style.Bold.Render("→"))
-				fmt.Printf("  Branch: %s\n", branch)
-				fmt.Printf("  Issue: %s\n", issueID)
-				fmt.Println()
-				fmt.Printf("%s\n", style.Dim.Render("Work stays on feature branch for human review."))
-
-				// Mail dispatcher with READY_FOR_REVIEW
-				if dispatcher := attachmentFields.DispatchedBy; dispatcher != "" {
-					townRouter := mail.NewRouter(townRoot)
-					reviewMsg := &mail.Message{
-						To:      dispatcher,
-						From:    detectSender(),
-						Subject: fmt.Sprintf("READY_FOR_REVIEW: %s", issueID),
-						Body:    fmt.Sprintf("Branch: %s\nIssue: %s\nReady for review.", branch, issueID),
-					}
-					if err := townRouter.Send(reviewMsg); err != nil {
-						style.PrintWarning("could not notify dispatcher: %v", err)
-					} else {
-						fmt.Printf("%s Dispatcher notified: READY_FOR_REVIEW\n", style.Bold.Render("✓"))
-					}
-				}
-
-				// Skip MR creation, go to witness notification
-				goto notifyWitness
-			}
+		var attachmentFields *beads.AttachmentFields
+		if sourceIssueForNoMerge, err := bd.Show(issueID); err == nil {
+			attachmentFields = beads.ParseAttachmentFields(sourceIssueForNoMerge)
 		}
+		noMerge := attachmentFields != nil && attachmentFields.NoMerge
 
 		// Determine target branch (auto-detect integration branch if applicable)
 		target := defaultBranch
@@ -349,6 +351,57 @@ func runDone(cmd *cobra.Command, args []string) error {
 			target = autoTarget
 		}
 
+		// Reject before pushing anything the refinery's branch protection would reject
+		if !noMerge {
+			if err := checkSubmitProtection(filepath.Join(townRoot, rigName), g, branch, target); err != nil {
+				return err
+			}
+			if err := checkSubmitCommits(filepath.Join(townRoot, rigName), g, branch, target); err != nil {
+				return err
+			}
+		}
+
+		// Step 3: push, then submit.
+		// CRITICAL: Push branch BEFORE creating MR bead (hq-6dk53, hq-a4ksk)
+		// The MR bead triggers Refinery to process this branch. If the branch
+		// isn't pushed yet, Refinery finds nothing to merge. The worktree gets
+		// nuked at the end of gt done, so the commits are lost forever.
+		fmt.Printf("Pushing branch to remote...\n")
+		if err := g.Push("origin", branch, false); err != nil {
+			return fmt.Errorf("pushing branch '%s' to origin: %w\nCommits exist locally but failed to push. Fix the issue and retry.", branch, err)
+		}
+		fmt.Printf("%s Branch pushed to origin\n", style.Bold.Render("✓"))
+		if cleanupDetected && (doneCleanupStatus == "uncommitted" || doneCleanupStatus == "unpushed") {
+			doneCleanupStatus = "clean" // Committed and pushed above
+		}
+
+		if noMerge {
+			fmt.Printf("%s No-merge mode: skipping merge queue\n", style.Bold.Render("→"))
+			fmt.Printf("  Branch: %s\n", branch)
+			fmt.Printf("  Issue: %s\n", issueID)
+			fmt.Println()
+			fmt.Printf("%s\n", style.Dim.Render("Work stays on feature branch for human review."))
+
+			// Mail dispatcher with READY_FOR_REVIEW
+			if dispatcher := attachmentFields.DispatchedBy; dispatcher != "" {
+				townRouter := mail.NewRouter(townRoot)
+				reviewMsg := &mail.Message{
+					To:      dispatcher,
+					From:    detectSender(),
+					Subject: fmt.Sprintf("READY_FOR_REVIEW: %s", issueID),
+					Body:    fmt.Sprintf("Branch: %s\nIssue: %s\nReady for review.", branch, issueID),
+				}
+				if err := townRouter.Send(reviewMsg); err != nil {
+					style.PrintWarning("could not notify dispatcher: %v", err)
+				} else {
+					fmt.Printf("%s Dispatcher notified: READY_FOR_REVIEW\n", style.Bold.Render("✓"))
+				}
+			}
+
+			// Skip MR creation, go to witness notification
+			goto notifyWitness
+		}
+
 		// Get source issue for priority inheritance
 		var priority int
 		if donePriority >= 0 {
@@ -363,8 +416,20 @@ func runDone(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Check if MR bead already exists for this branch (idempotency)
-		existingMR, err := bd.FindMRForBranch(branch)
+		// The MR carries the source issue's labels
+		var labels []string
+		if sourceIssue, err := bd.Show(issueID); err == nil {
+			labels = mrLabels(sourceIssue, nil)
+		}
+
+		rigPath := filepath.Join(townRoot, rigName)
+		owners := submitMROwners(rigPath, g, branch, target)
+		project := submitMRProject(rigPath, g, branch, target)
+		locks := submitMRLocks(rigPath, g, branch, target, nil)
+
+		// Check if MR bead already exists for this branch and target (idempotency)
+		var reviewers []string
+		existingMR, err := bd.FindOpenMR(branch, target)
 		if err != nil {
 			style.PrintWarning("could not check for existing MR: %v", err)
 			// Continue with creation attempt - Create will fail if duplicate
@@ -373,25 +438,43 @@ func runDone(cmd *cobra.Command, args []string) error {
 		if existingMR != nil {
 			// MR already exists - use it instead of creating a new one
 			mrID = existingMR.ID
-			fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
+			if refinery.StateOf(existingMR) == refinery.StateChangesRequested {
+				if err := resubmitMR(townRoot, bd, existingMR); err != nil {
+					return fmt.Errorf("resubmitting %s: %w\nThe branch is pushed; run gt done again to resubmit it.", mrID, err)
+				}
+			} else {
+				fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
+			}
 			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
 		} else {
 			// Build MR bead title and description
 			title := fmt.Sprintf("Merge: %s", issueID)
-			description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
-				branch, target, issueID, rigName)
+			description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s\nstate: %s",
+				branch, target, issueID, rigName, refinery.StateQueued)
 			if worker != "" {
 				description += fmt.Sprintf("\nworker: %s", worker)
 			}
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if project != "" {
+				description += "\nproject: " + project
+			}
+			if len(locks) > 0 {
+				description += "\nlocks: " + strings.Join(locks, ",")
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
 			description += "\nlast_conflict_sha: null"
 			description += "\nconflict_task_id: null"
 
+			// Assign reviewers when the rig requires approvals
+			reviewers = assignMRReviewers(bd, rigPath, rigName, worker)
+			if len(reviewers) > 0 {
+				description += "\nreviewers: " + strings.Join(reviewers, ",")
+			}
+
 			// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 			mrIssue, err := bd.Create(beads.CreateOptions{
 				Title:       title,
@@ -401,9 +484,11 @@ func runDone(cmd *cobra.Command, args []string) error {
 				Ephemeral:   true,
 			})
 			if err != nil {
-				return fmt.Errorf("creating merge request bead: %w", err)
+				return fmt.Errorf("creating merge request bead: %w\nThe branch is pushed; run gt done again to submit it.", err)
 			}
 			mrID = mrIssue.ID
+			_ = events.LogFeed(events.TypeMRSubmitted, sender,
+				events.MRPayload(rigName, mrID, issueID, branch, ""))
 
 			// Update agent bead with active_mr reference (for traceability)
 			if agentBeadID != "" {
@@ -416,6 +501,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s Work submitted to merge queue\n", style.Bold.Render("✓"))
 			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
 		}
+		labelMR(bd, mrID, labels)
 		fmt.Printf("  Source: %s\n", branch)
 		fmt.Printf("  Target: %s\n", target)
 		fmt.Printf("  Issue: %s\n", issueID)
@@ -423,6 +509,24 @@ func runDone(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  Worker: %s\n", worker)
 		}
 		fmt.Printf("  Priority: P%d\n", priority)
+		if len(labels) > 0 {
+			fmt.Printf("  Labels: %s\n", strings.Join(labels, ", "))
+		}
+		if len(reviewers) > 0 {
+			fmt.Printf("  Reviewers: %s\n", strings.Join(reviewers, ", "))
+			notifyMRReviewers(townRoot, reviewers, mrID, branch, issueID)
+		}
+		if len(owners) > 0 {
+			fmt.Println("  Owners (sign-off required):")
+			for _, a := range owners {
+				fmt.Printf("    %s: %s %s\n", a.Path, strings.Join(a.Owners, " or "),
+					style.Dim.Render(fmt.Sprintf("(%d file(s))", len(a.Files))))
+			}
+		}
+
+		// Step 4: the issue stays in progress until the Refinery merges it
+		markDoneIssue(townRoot, bd, issueID, sender)
+
 		fmt.Println()
 		fmt.Printf("%s\n", style.Dim.Render("The Refinery will process your merge request."))
 	} else if exitType == ExitPhaseComplete {
@@ -550,6 +654,102 @@ notifyWitness:
 	return nil // unreachable, but keeps compiler happy
 }
 
+// runDoneChecks runs the rig's refinery checks in dir, as gt check does.
+func runDoneChecks(townRoot, rigName, dir string) error {
+	results, passed, err := runRigChecks(townRoot, rigName, dir, os.Stdout)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		printCheckRun(r)
+	}
+	if !passed {
+		failed := results[len(results)-1]
+		return withExitCode(ExitCheckFailed, fmt.Errorf("%s check failed: %s\nNothing was committed, pushed or submitted. Fix the failures (gt check reruns the checks) and run gt done again, or use --skip-checks to leave them to the Refinery", failed.Name, failed.Error))
+	}
+	return nil
+}
+
+// commitDoneWork commits every uncommitted change in the workspace, with
+// --message or a message made from the issue, following the rig's commit
+// message convention.
+func commitDoneWork(rigPath string, g *git.Git, bd *beads.Beads, issueID string) error {
+	policy, err := refinery.LoadCommitPolicy(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading commit message policy: %w\nNothing was committed or pushed.", err)
+	}
+	var title, issueType string
+	if issueID != "" {
+		if issue, err := bd.Show(issueID); err == nil {
+			title, issueType = issue.Title, issue.Type
+		}
+	}
+	message, err := doneCommitMessage(policy, doneMessage, issueID, issueType, title)
+	if err != nil {
+		return fmt.Errorf("%w\nNothing was committed or pushed. Commit by hand (gt commit) and run gt done again.", err)
+	}
+
+	if err := g.Add("-A"); err != nil {
+		return fmt.Errorf("staging changes: %w\nNothing was committed or pushed. Commit by hand and run gt done again.", err)
+	}
+	if err := g.Commit(message); err != nil {
+		return fmt.Errorf("committing changes: %w\nNothing was pushed. Commit by hand and run gt done again.", err)
+	}
+	fmt.Printf("%s Committed uncommitted changes: %s\n", style.Bold.Render("✓"), message)
+	return nil
+}
+
+// doneCommitMessage returns the message for committing leftover work:
+// message if it follows policy, else one composed by policy from the issue
+// with message (or the issue's title) as the summary.
+func doneCommitMessage(policy *refinery.CommitPolicy, message, issueID, issueType, title string) (string, error) {
+	if message != "" && policy.Check(message) == nil {
+		return message, nil
+	}
+	summary := message
+	if summary == "" {
+		switch {
+		case issueID == "":
+			return "", fmt.Errorf("cannot commit uncommitted changes: no issue to describe them; use -m to give a commit message")
+		case title == "" && policy == nil:
+			return issueID, nil
+		case title == "":
+			return "", fmt.Errorf("cannot commit uncommitted changes: %s has no title; use -m to give a commit message", issueID)
+		}
+		summary = title
+	}
+	subject, err := policy.Compose(policy.TypeFor(issueType), issueID, summary)
+	if err != nil {
+		return "", fmt.Errorf("cannot commit uncommitted changes: %w", err)
+	}
+	return subject, nil
+}
+
+// markDoneIssue updates the source issue once its work is submitted: it is
+// in progress until the Refinery merges it (and closes it), and agent's
+// claim on it is released. Best-effort: the MR is already in the queue.
+func markDoneIssue(townRoot string, bd *beads.Beads, issueID, agent string) {
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		style.PrintWarning("could not update issue %s: %v", issueID, err)
+		return
+	}
+	if issue.Status == "open" {
+		status := "in_progress"
+		if err := bd.Update(issueID, beads.UpdateOptions{Status: &status}); err != nil {
+			style.PrintWarning("could not mark %s in_progress: %v", issueID, err)
+			return
+		}
+	}
+	if lease := claim.FromIssue(issue); lease != nil && !lease.Expires.IsZero() && lease.HeldBy(agent) {
+		if err := claim.Release(townRoot, bd, issueID, agent, false); err != nil {
+			style.PrintWarning("could not release claim on %s: %v", issueID, err)
+			return
+		}
+	}
+	fmt.Printf("%s Issue %s in progress, pending merge\n", style.Bold.Render("✓"), issueID)
+}
+
 // updateAgentStateOnDone clears the agent's hook and reports cleanup status.
 // Per gt-zecmc: observable states ("done", "idle") removed - use tmux to discover.
 // Non-observable states ("stuck", "awaiting-gate") are still set since they represent
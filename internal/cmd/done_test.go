@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
 )
 
 // TestDoneUsesResolveBeadsDir verifies that the done command correctly uses
@@ -377,3 +379,43 @@ func TestIsPolecatActor(t *testing.T) {
 		})
 	}
 }
+
+// TestDoneCommitMessage verifies the message gt done commits leftover work with.
+func TestDoneCommitMessage(t *testing.T) {
+	conventional, err := refinery.NewCommitPolicy(&config.CommitMessageConfig{
+		Template: "{type}({issue}): {summary}",
+		Types:    []string{"feat", "fix", "chore"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                                     string
+		policy                                   *refinery.CommitPolicy
+		message, issueID, issueType, title, want string
+		wantErr                                  bool
+	}{
+		{name: "explicit message", message: "Fix redirect", issueID: "gt-abc", title: "Login bug", want: "Fix redirect"},
+		{name: "issue title", issueID: "gt-abc", title: "Login bug", want: "Login bug (gt-abc)"},
+		{name: "issue without title", issueID: "gt-abc", want: "gt-abc"},
+		{name: "no issue", wantErr: true},
+		{name: "policy from issue", policy: conventional, issueID: "gt-abc", issueType: "bug", title: "Login bug", want: "fix(gt-abc): Login bug"},
+		{name: "policy message as summary", policy: conventional, message: "handle redirect", issueID: "gt-abc", issueType: "task", want: "chore(gt-abc): handle redirect"},
+		{name: "policy message already compliant", policy: conventional, message: "feat(gt-xyz): add it", issueID: "gt-abc", issueType: "bug", want: "feat(gt-xyz): add it"},
+		{name: "policy without type", policy: conventional, issueID: "gt-abc", issueType: "epic", title: "Login", wantErr: true},
+		{name: "policy without title", policy: conventional, issueID: "gt-abc", issueType: "bug", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doneCommitMessage(tt.policy, tt.message, tt.issueID, tt.issueType, tt.title)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doneCommitMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("doneCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -68,7 +68,8 @@ var (
 )
 
 func init() {
-	downCmd.Flags().BoolVarP(&downQuiet, "quiet", "q", false, "Only show errors")
+	legacyQuietFlag(downCmd, &downQuiet, "Only show errors",
+		"it will hide all normal output, not just stopped services, in a future release")
 	downCmd.Flags().BoolVarP(&downForce, "force", "f", false, "Force kill without graceful shutdown")
 	downCmd.Flags().BoolVarP(&downPolecats, "polecats", "p", false, "Also stop all polecat sessions")
 	downCmd.Flags().BoolVarP(&downAll, "all", "a", false, "Stop bd daemons/activity and verify shutdown")
@@ -328,7 +329,7 @@ func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force boo
 				stopped++
 				fmt.Printf("  %s [%s] %s stopped\n", style.SuccessPrefix, rigName, info.Polecat)
 			} else {
-				fmt.Printf("  %s [%s] %s: %s\n", style.ErrorPrefix, rigName, info.Polecat, err.Error())
+				fmt.Fprintf(os.Stderr, "  %s [%s] %s: %s\n", style.ErrorPrefix, rigName, info.Polecat, err.Error())
 			}
 		}
 	}
@@ -336,6 +337,8 @@ func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force boo
 	return stopped
 }
 
+// printDownStatus reports a service's shutdown. Failures go to stderr, so
+// --quiet still shows them.
 func printDownStatus(name string, ok bool, detail string) {
 	if downQuiet && ok {
 		return
@@ -343,7 +346,7 @@ func printDownStatus(name string, ok bool, detail string) {
 	if ok {
 		fmt.Printf("%s %s: %s\n", style.SuccessPrefix, name, style.Dim.Render(detail))
 	} else {
-		fmt.Printf("%s %s: %s\n", style.ErrorPrefix, name, detail)
+		fmt.Fprintf(os.Stderr, "%s %s: %s\n", style.ErrorPrefix, name, detail)
 	}
 }
 
@@ -3,6 +3,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/wip"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // SilentExitError signals that the command should exit with a specific code
@@ -34,3 +41,80 @@ func IsSilentExit(err error) (int, bool) {
 	}
 	return 0, false
 }
+
+// Exit codes returned by gt. These are part of the scripting contract: CI
+// jobs and agents branch on them, so existing values must never change.
+const (
+	ExitOK               = 0
+	ExitError            = 1  // Unclassified failure
+	ExitNotInWorkspace   = 3  // Not inside a Gas Town workspace
+	ExitRigNotFound      = 4  // Named rig is not registered
+	ExitMRNotFound       = 5  // Merge request does not exist
+	ExitQueuePaused      = 6  // Merge queue is parked, docked, frozen, or outside its schedule
+	ExitMergeConflict    = 7  // Merge could not be completed due to conflicts
+	ExitCheckFailed      = 8  // Tests or other merge checks failed
+	ExitPermissionDenied = 9  // Caller's role may not run this destructive command
+	ExitWIPLimit         = 10 // Worker is at a work-in-progress limit
+	ExitClaimHeld        = 11 // Issue is claimed by another worker
+)
+
+// ExitCodeError attaches a specific exit code to an error. Unlike
+// SilentExitError, the wrapped error message is still printed.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode wraps err so the process exits with code.
+// Returns nil if err is nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitCodeError{Code: code, Err: err}
+}
+
+// exitCodeFor maps an error returned by a command to a process exit code.
+// Explicit codes win; otherwise well-known sentinel errors are classified,
+// and anything else exits with ExitError.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if code, ok := IsSilentExit(err); ok {
+		return code
+	}
+	var ce *ExitCodeError
+	if errors.As(err, &ce) {
+		return ce.Code
+	}
+	var denied *permission.DeniedError
+	if errors.As(err, &denied) {
+		return ExitPermissionDenied
+	}
+	var overWIP *wip.ExceededError
+	if errors.As(err, &overWIP) {
+		return ExitWIPLimit
+	}
+	var held *claim.HeldError
+	if errors.As(err, &held) {
+		return ExitClaimHeld
+	}
+	switch {
+	case errors.Is(err, workspace.ErrNotFound):
+		return ExitNotInWorkspace
+	case errors.Is(err, rig.ErrRigNotFound):
+		return ExitRigNotFound
+	case errors.Is(err, refinery.ErrMRNotFound):
+		return ExitMRNotFound
+	}
+	return ExitError
+}
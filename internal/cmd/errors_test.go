@@ -4,6 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/wip"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 func TestSilentExitError_Error(t *testing.T) {
@@ -90,3 +97,45 @@ func TestSilentExitError_Is(t *testing.T) {
 		t.Errorf("errors.As extracted code = %d, want 1", target.Code)
 	}
 }
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"silent exit", NewSilentExit(2), 2},
+		{"explicit code", withExitCode(ExitCheckFailed, errors.New("tests failed")), ExitCheckFailed},
+		{"wrapped explicit code", fmt.Errorf("landing: %w", withExitCode(ExitMergeConflict, errors.New("conflict"))), ExitMergeConflict},
+		{"not in workspace", fmt.Errorf("not in a Gas Town workspace: %w", workspace.ErrNotFound), ExitNotInWorkspace},
+		{"rig not found", fmt.Errorf("loading: %w", rig.ErrRigNotFound), ExitRigNotFound},
+		{"mr not found", fmt.Errorf("rejecting MR: %w", refinery.ErrMRNotFound), ExitMRNotFound},
+		{"permission denied", &permission.DeniedError{Actor: "gp/polecats/Toast", Level: permission.LevelPolecat, Action: permission.ActionRigRemove}, ExitPermissionDenied},
+		{"wip limit", fmt.Errorf("submitting: %w", &wip.ExceededError{Worker: "Toast", Kind: "open MRs", Count: 3, Limit: 3}), ExitWIPLimit},
+		{"claim held", fmt.Errorf("slinging: %w", &claim.HeldError{Lease: &claim.Lease{Issue: "gt-abc", Holder: "greenplace/polecats/nux"}}), ExitClaimHeld},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodeError_PreservesMessage(t *testing.T) {
+	inner := errors.New("merge failed")
+	err := withExitCode(ExitMergeConflict, inner)
+	if err.Error() != "merge failed" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "merge failed")
+	}
+	if !errors.Is(err, inner) {
+		t.Error("withExitCode should wrap the original error")
+	}
+	if withExitCode(ExitMergeConflict, nil) != nil {
+		t.Error("withExitCode(nil) should return nil")
+	}
+}
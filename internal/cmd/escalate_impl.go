@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -86,36 +87,37 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	actions := escalationConfig.GetRouteForSeverity(severity)
 	targets := extractMailTargetsFromActions(actions)
 
+	escalation := &mail.Message{
+		From:    agentID,
+		Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
+		Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+		Type:    mail.TypeTask,
+	}
+
+	// Set priority based on severity
+	switch severity {
+	case config.SeverityCritical:
+		escalation.Priority = mail.PriorityUrgent
+	case config.SeverityHigh:
+		escalation.Priority = mail.PriorityHigh
+	case config.SeverityMedium:
+		escalation.Priority = mail.PriorityNormal
+	default:
+		escalation.Priority = mail.PriorityLow
+	}
+
 	// Send mail to each target (actions with "mail:" prefix)
 	router := mail.NewRouter(townRoot)
 	for _, target := range targets {
-		msg := &mail.Message{
-			From:    agentID,
-			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
-			Type:    mail.TypeTask,
-		}
-
-		// Set priority based on severity
-		switch severity {
-		case config.SeverityCritical:
-			msg.Priority = mail.PriorityUrgent
-		case config.SeverityHigh:
-			msg.Priority = mail.PriorityHigh
-		case config.SeverityMedium:
-			msg.Priority = mail.PriorityNormal
-		default:
-			msg.Priority = mail.PriorityLow
-		}
-
-		if err := router.Send(msg); err != nil {
+		msg := *escalation
+		msg.To = target
+		if err := router.Send(&msg); err != nil {
 			style.PrintWarning("failed to send to %s: %v", target, err)
 		}
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	executeExternalActions(townRoot, actions, escalationConfig, router, escalation)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -539,16 +541,25 @@ func extractMailTargetsFromActions(actions []string) []string {
 }
 
 // executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// Contacts may be "secret:<name>" references, resolved here from the town's secret store.
+// Email goes out through the SMTP server in config/messaging.json; the rest only
+// log warnings if contacts aren't configured - actual sending is future work.
+func executeExternalActions(townRoot string, actions []string, cfg *config.EscalationConfig, router *mail.Router, escalation *mail.Message) {
 	for _, action := range actions {
 		switch {
 		case strings.HasPrefix(action, "email:"):
 			if cfg.Contacts.HumanEmail == "" {
 				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
+			} else if addr, err := secrets.Resolve(townRoot, cfg.Contacts.HumanEmail); err != nil {
+				style.PrintWarning("email action '%s' skipped: contacts.human_email: %v", action, err)
 			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
+				msg := *escalation
+				msg.To = addr
+				if err := router.Send(&msg); err != nil {
+					style.PrintWarning("email action '%s' failed: %v", action, err)
+				} else {
+					fmt.Printf("  📧 Emailed %s\n", addr)
+				}
 			}
 
 		case strings.HasPrefix(action, "sms:"):
@@ -562,6 +573,8 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 		case action == "slack":
 			if cfg.Contacts.SlackWebhook == "" {
 				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
+			} else if _, err := secrets.Resolve(townRoot, cfg.Contacts.SlackWebhook); err != nil {
+				style.PrintWarning("slack action skipped: contacts.slack_webhook: %v", err)
 			} else {
 				// TODO: Implement actual Slack webhook posting
 				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
@@ -616,6 +629,9 @@ func formatRelativeTime(timestamp string) string {
 	if err != nil {
 		return timestamp
 	}
+	if timeFormat != TimeRelative {
+		return formatAbsoluteTime(t)
+	}
 
 	duration := time.Since(t)
 	if duration < time.Minute {
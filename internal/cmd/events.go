@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsTailFilters []string
+	eventsTailLines   int
+	eventsTailFollow  bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Read the town event bus",
+	Long: `Read the town event bus.
+
+Every subsystem publishes events to an append-only log (~/gt/.events.jsonl):
+merge request transitions (mr_submitted, mr_approved, mr_changes_requested,
+mr_rejected, mr_reverted, mr_assigned, mr_backported, mr_commented,
+mr_sla_breached, merge_started, merged, merge_failed), polecat spawn and kill, mail,
+escalations, hooks, slings, sessions, and patrols.
+'gt feed' shows a curated view; 'gt events tail' gives consumers the raw
+stream.
+
+Subcommands:
+  tail    Print recent events and optionally follow new ones`,
+	RunE: requireSubcommand,
+}
+
+var eventsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Print and follow events",
+	Long: `Print the most recent events, then optionally follow new ones.
+
+--filter selects events by field: type, actor, source, visibility, or any
+payload key (rig, mr, polecat, ...). Values may list alternatives separated
+by commas and may use * globs. Repeat --filter to require several fields.
+
+With -o json, each event is printed as one JSON line (JSONL), suitable for
+piping into other tools.
+
+Examples:
+  gt events tail
+  gt events tail -n 100 --filter type=merge_*
+  gt events tail -f --filter type=merged,merge_failed --filter rig=greenplace
+  gt events tail -f --filter type=spawn,kill -o json`,
+	Args: cobra.NoArgs,
+	RunE: runEventsTail,
+}
+
+func init() {
+	eventsTailCmd.Flags().StringArrayVar(&eventsTailFilters, "filter", nil, "Only events matching key=value (repeatable)")
+	eventsTailCmd.Flags().IntVarP(&eventsTailLines, "lines", "n", 20, "Number of recent events to print (0 for none)")
+	eventsTailCmd.Flags().BoolVarP(&eventsTailFollow, "follow", "f", false, "Keep printing new events as they are published")
+
+	eventsCmd.AddCommand(eventsTailCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsTail(cmd *cobra.Command, args []string) error {
+	filter, err := events.ParseFilter(eventsTailFilters)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := events.Path(townRoot)
+
+	recent, offset, err := events.ReadAll(path, filter)
+	if err != nil {
+		return err
+	}
+	if eventsTailLines >= 0 && len(recent) > eventsTailLines {
+		recent = recent[len(recent)-eventsTailLines:]
+	}
+	for _, e := range recent {
+		if err := printBusEvent(e); err != nil {
+			return err
+		}
+	}
+	if !eventsTailFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return events.Follow(ctx, path, offset, filter, events.DefaultPollInterval, printBusEvent)
+}
+
+// printBusEvent prints one event as a JSON line, a YAML document, or a
+// human-readable line, per --output.
+func printBusEvent(e events.Event) error {
+	switch outputFormat {
+	case OutputJSON:
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encoding event: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case OutputYAML:
+		fmt.Println("---")
+		return renderStructured(e)
+	}
+
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%s %s %s%s\n", style.Dim.Render(ts), style.Bold.Render(e.Type), e.Actor, formatBusPayload(e.Payload))
+	return nil
+}
+
+// formatBusPayload renders a payload as " key=value ..." in key order.
+func formatBusPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		v := fmt.Sprint(payload[k])
+		if strings.ContainsAny(v, " \t\n") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&sb, " %s=%s", k, v)
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Export command flags
+var exportOut string
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupWorkspace,
+	Short:   "Export the town's state to an archive for another host",
+	Long: `Write the town's state to one archive, to restore with 'gt import' on
+another host: town and rig configs, beads databases (with mail, and merge
+requests and their queue state), and each rig's runtime state.
+
+Repository contents stay out. Rig clones, crew workspaces and polecat
+worktrees are skipped (all but a mayor clone's beads database), as are
+pid, lock and socket files. 'gt import' clones the rigs' repositories again.
+
+The archive is compressed by its extension: .tar.zst (needs the zstd
+command), .tar.gz, or .tar. It records the gt and config versions that
+wrote it, so an older gt won't restore state it can't read.
+
+Beads databases must be still while they're copied: export refuses to run
+while the Dolt server is up, so stop it first with 'gt dolt stop' (and
+ideally stop the agents with 'gt down').
+
+Examples:
+  gt export --out town-backup.tar.zst
+  gt export --out town-backup.tar.gz -o json`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "Archive to write (.tar.zst, .tar.gz or .tar)")
+	_ = exportCmd.MarkFlagRequired("out")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+// ExportOutput is the structured output for gt export.
+type ExportOutput struct {
+	Archive string `json:"archive"`
+	archive.Manifest
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); running {
+		return fmt.Errorf("the Dolt server is running; stop it with 'gt dolt stop' before exporting")
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); running && !structuredOutput(false) {
+		fmt.Printf("%s The daemon is running; agents may change state mid-export (stop them with 'gt down')\n\n",
+			style.Warning.Render("⚠"))
+	}
+
+	m, err := archive.Export(townRoot, exportOut, Version)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(ExportOutput{Archive: exportOut, Manifest: *m})
+	}
+	fmt.Printf("%s Exported %s to %s\n", style.Success.Render("✓"), style.Bold.Render(m.TownName), exportOut)
+	fmt.Printf("  %d files, %s\n", m.Files, formatBytes(m.Bytes))
+	for _, r := range m.Rigs {
+		fmt.Printf("  %s %s\n", r.Name, style.Dim.Render(r.GitURL))
+	}
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -24,7 +23,6 @@ var (
 	feedRig      string
 	feedNoFollow bool
 	feedWindow   bool
-	feedPlain    bool
 )
 
 func init() {
@@ -38,7 +36,6 @@ func init() {
 	feedCmd.Flags().StringVar(&feedType, "type", "", "Filter by event type (create, update, delete, comment)")
 	feedCmd.Flags().StringVar(&feedRig, "rig", "", "Run from specific rig's beads directory")
 	feedCmd.Flags().BoolVarP(&feedWindow, "window", "w", false, "Open in dedicated tmux window (creates 'feed' window)")
-	feedCmd.Flags().BoolVar(&feedPlain, "plain", false, "Use plain text output (bd activity) instead of TUI")
 }
 
 var feedCmd = &cobra.Command{
@@ -133,8 +130,9 @@ func runFeed(cmd *cobra.Command, args []string) error {
 		return runFeedInWindow(workDir, bdArgs)
 	}
 
-	// Use TUI by default if running in a terminal and not --plain
-	useTUI := !feedPlain && term.IsTerminal(int(os.Stdout.Fd()))
+	// Use TUI by default if running in a terminal and not --plain (the
+	// global flag, which also drops colors and icons)
+	useTUI := !plainOutput && term.IsTerminal(int(os.Stdout.Fd()))
 
 	if useTUI {
 		return runFeedTUI(workDir)
@@ -192,7 +190,7 @@ func runFeedDirect(workDir string, bdArgs []string) error {
 		return fmt.Errorf("changing to directory %s: %w", workDir, err)
 	}
 
-	return syscall.Exec(bdPath, fullArgs, os.Environ())
+	return execReplace(bdPath, fullArgs, os.Environ())
 }
 
 // runFeedTUI runs the interactive TUI feed.
@@ -1189,7 +1189,7 @@ Perform the patrol inspection.
 
 // promptYesNo asks the user a yes/no question
 func promptYesNo(question string) bool {
-	fmt.Printf("%s [y/N]: ", question)
+	promptf("%s [y/N]: ", question)
 	reader := bufio.NewReader(os.Stdin)
 	answer, _ := reader.ReadString('\n')
 	answer = strings.TrimSpace(strings.ToLower(answer))
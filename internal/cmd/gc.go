@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/gc"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	gcDryRun     bool
+	gcPushRemote bool
+	gcOnly       []string
+	gcOlderThan  time.Duration
+	gcRig        string
+)
+
+var gcCmd = &cobra.Command{
+	Use:     "gc",
+	GroupID: GroupDiag,
+	Short:   "Remove merged branches, dead worktrees and other cruft",
+	Long: `Remove what a town accumulates and no longer needs:
+
+  worktrees        Worktree entries whose checkouts were deleted
+  branches         Polecat branches in a rig's shared repo whose work merged
+  remote-branches  The same branches on the rig's push remote (with --push-remote)
+  sessions         tmux sessions of polecats that no longer exist
+  artifacts        MR check artifacts past the rig's retention
+  logs             Rotated daemon and town logs older than --older-than
+  crashes          Reviewed crash reports older than --older-than
+  archives         Superseded copies of MRs in MR archives
+
+A branch has merged when the refinery merged an MR from its current tip
+(the refinery squash-merges, so merged branches aren't ancestors of the
+target) or when its tip is on the rig's default branch. A branch with
+commits pushed after its MR merged is kept, as are branches with an open
+MR or checked out in a worktree. Crew sessions are never touched.
+
+Removing anything is gated by the caller's permission level (action
+"gc"); --dry-run is not.
+
+Examples:
+  gt gc --dry-run                  # Show what would be removed
+  gt gc                            # Remove it
+  gt gc --push-remote              # Also delete merged branches on the remote
+  gt gc --only logs,crashes --older-than 168h
+  gt gc --rig greenplace`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Show what would be removed without removing it")
+	gcCmd.Flags().BoolVar(&gcPushRemote, "push-remote", false, "Also delete merged branches on each rig's push remote")
+	gcCmd.Flags().StringSliceVar(&gcOnly, "only", nil, "Collect only these kinds (comma-separated)")
+	gcCmd.Flags().DurationVar(&gcOlderThan, "older-than", gc.DefaultMaxAge, "Age past which rotated logs and reviewed crash reports are removed")
+	gcCmd.Flags().StringVar(&gcRig, "rig", "", "Collect in one rig only")
+
+	rootCmd.AddCommand(gcCmd)
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	var townRoot string
+	if gcRig != "" {
+		root, r, err := getRig(gcRig)
+		if err != nil {
+			return err
+		}
+		townRoot, rigs = root, []*rig.Rig{r}
+	} else {
+		var err error
+		if rigs, townRoot, err = getAllRigs(); err != nil {
+			return err
+		}
+	}
+
+	kinds := gcOnly
+	if len(kinds) == 0 {
+		kinds = gc.DefaultKinds
+		if gcPushRemote {
+			kinds = gc.Kinds
+		}
+	}
+	if !gcDryRun {
+		if err := requirePermission(townRoot, permission.ActionGC, gcRig, nil); err != nil {
+			return err
+		}
+	}
+	report, err := gc.Run(townRoot, rigs, gc.Options{Kinds: kinds, MaxAge: gcOlderThan, DryRun: gcDryRun})
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(report)
+	}
+	printGCReport(report)
+	if report.Failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printGCReport prints what was removed, or would be, by kind.
+func printGCReport(report *gc.Report) {
+	if len(report.Items) == 0 {
+		fmt.Printf("%s Nothing to collect\n", style.Success.Render("✓"))
+		return
+	}
+	for _, kind := range gc.Kinds {
+		var items []gc.Item
+		for _, item := range report.Items {
+			if item.Kind == kind {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		fmt.Printf("%s\n", style.Bold.Render(kind))
+		for _, item := range items {
+			target := item.Target
+			if item.Rig != "" {
+				target = item.Rig + ": " + target
+			}
+			line := "  " + target
+			if item.Reason != "" {
+				line += "  " + style.Dim.Render(item.Reason)
+			}
+			if item.Bytes > 0 {
+				line += "  " + style.Dim.Render(formatBytes(item.Bytes))
+			}
+			if item.Error != "" {
+				line += "  " + style.Error.Render(item.Error)
+			}
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println()
+	removed := len(report.Items) - report.Failed
+	if report.DryRun {
+		fmt.Printf("%s Would remove %d item(s), reclaiming %s %s\n", style.Dim.Render("ℹ"), removed,
+			formatBytes(report.Bytes), style.Dim.Render("(dry run)"))
+	} else {
+		fmt.Printf("%s Removed %d item(s), reclaimed %s\n", style.Success.Render("✓"), removed, formatBytes(report.Bytes))
+	}
+	if report.Failed > 0 {
+		fmt.Printf("%s %d item(s) could not be removed\n", style.Warning.Render("⚠"), report.Failed)
+	}
+}
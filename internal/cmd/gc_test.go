@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestGCPushRemoteThroughExecute runs gt gc through Execute, which strips
+// the global --remote before cobra parses: --push-remote must survive it in
+// any position.
+func TestGCPushRemoteThroughExecute(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, nil)
+	t.Chdir(townRoot)
+	t.Setenv(EnvGTRole, "")
+	binDir := t.TempDir()
+	bd := "#!/bin/sh\nif [ \"$1\" = version ]; then echo 'bd version 1.0.0'; else echo '[]'; fi\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(bd), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	versionCheckOnce = sync.Once{}
+	origArgs, origBuilt := os.Args, BuiltProperly
+	BuiltProperly = "1"
+	t.Cleanup(func() {
+		os.Args, BuiltProperly = origArgs, origBuilt
+		gcPushRemote, gcDryRun, gcOnly = false, false, nil
+	})
+
+	for _, args := range [][]string{
+		{"gc", "--dry-run", "--push-remote", "--only", "logs"},
+		{"gc", "--push-remote", "--dry-run", "--only", "logs"},
+	} {
+		gcPushRemote, gcDryRun, gcOnly = false, false, nil
+		os.Args = append([]string{"gt"}, args...)
+		if code := Execute(); code != ExitOK {
+			t.Errorf("gt %v exited %d", args, code)
+		}
+		if !gcPushRemote || !gcDryRun {
+			t.Errorf("gt %v: --push-remote = %v, --dry-run = %v", args, gcPushRemote, gcDryRun)
+		}
+	}
+}
@@ -47,7 +47,21 @@ in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
 Any molecule on the hook will be auto-continued by the new session.
-The SessionStart hook runs 'gt prime' to restore context.`,
+The SessionStart hook runs 'gt prime' to restore context.
+
+With --to, hands an issue over to another worker instead, e.g. when a
+polecat's session died mid-task:
+
+  gt handoff gt-abc --to Nux -m "Retry logic done; tests still flaky"
+
+The issue (and its claim, if it has one) is reassigned, and the holder's
+work branch moves with it: uncommitted changes are committed as WIP, and
+the branch is checked out in the receiving polecat's worktree. Crew, the
+mayor and the overseer (the human operator) get the branch pushed to
+origin instead. A note on the state of the work - branch, commits,
+uncommitted files, and -m - is added to the issue as a comment and
+mailed to both workers. A holder whose session is still running, or a
+polecat that already has work, needs --force.`,
 	RunE: runHandoff,
 }
 
@@ -57,6 +71,8 @@ var (
 	handoffSubject string
 	handoffMessage string
 	handoffCollect bool
+	handoffTo      string
+	handoffForce   bool
 )
 
 func init() {
@@ -65,10 +81,19 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().StringVar(&handoffTo, "to", "", "Hand the given issue to this worker (polecat, crew address, mayor or overseer)")
+	handoffCmd.Flags().BoolVarP(&handoffForce, "force", "f", false, "With --to: stop the holder's session, or hand work to a busy polecat")
 	rootCmd.AddCommand(handoffCmd)
 }
 
 func runHandoff(cmd *cobra.Command, args []string) error {
+	if handoffTo != "" {
+		if len(args) != 1 || !looksLikeBeadID(args[0]) {
+			return fmt.Errorf("--to needs the issue to hand over: gt handoff <issue> --to <worker>")
+		}
+		return runHandoffTransfer(args[0])
+	}
+
 	// Check if we're a polecat - polecats use gt done instead
 	// GT_POLECAT is set by the session manager when starting polecat sessions
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
@@ -3,8 +3,11 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -177,3 +180,51 @@ func TestDetectTownRootFromCwd_EnvFallback(t *testing.T) {
 		}
 	})
 }
+
+func TestHandoffNote(t *testing.T) {
+	note := &handoffNote{
+		Issue:       "gp-42",
+		From:        "greenplace/polecats/Toast",
+		To:          "greenplace/polecats/Nux",
+		By:          "overseer",
+		At:          time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		Message:     "Retry logic done; tests still flaky",
+		Branch:      "polecat/Toast/gp-42",
+		Base:        "origin/main",
+		Commits:     []git.CommitSummary{{SHA: "0123456789abcdef", Subject: "Add retry"}},
+		Uncommitted: []string{" M retry.go", "?? retry_test.go"},
+		WorkDir:     "/gt/greenplace/polecats/Nux/greenplace",
+	}
+	got := note.String()
+	for _, want := range []string{
+		"Handoff of gp-42 from greenplace/polecats/Toast to greenplace/polecats/Nux (by overseer, 2026-03-01 09:30)",
+		"Retry logic done; tests still flaky",
+		"Branch: polecat/Toast/gp-42 (1 commit(s) ahead of origin/main)",
+		"  01234567 Add retry",
+		"  ?? retry_test.go",
+		"Checked out in /gt/greenplace/polecats/Nux/greenplace",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("note missing %q:\n%s", want, got)
+		}
+	}
+
+	note = &handoffNote{Issue: "gp-42", From: "greenplace/polecats/Toast", To: "overseer", By: "mayor", At: note.At}
+	if got := note.String(); !strings.Contains(got, "No work branch to hand over.") {
+		t.Errorf("note without a branch:\n%s", got)
+	}
+}
+
+func TestHandoffHolder(t *testing.T) {
+	w := handoffHolder("greenplace/polecats/Toast")
+	if w.Rig != "greenplace" || w.Polecat != "Toast" {
+		t.Errorf("polecat holder = %+v", w)
+	}
+	w = handoffHolder("greenplace/crew/max")
+	if w.Rig != "greenplace" || w.Polecat != "" {
+		t.Errorf("crew holder = %+v", w)
+	}
+	if w = handoffHolder("overseer"); w.Rig != "" || w.Polecat != "" {
+		t.Errorf("overseer holder = %+v", w)
+	}
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// handoffWorker is one side of an issue transfer. Polecats have a
+// worktree the work moves out of or into; crew, the mayor and the
+// overseer pick it up from the pushed branch.
+type handoffWorker struct {
+	Address string
+	Rig     string
+	Polecat string // Empty unless the worker is a polecat
+}
+
+// handoffNote is the state of transferred work, as recorded on the issue
+// and mailed to both workers.
+type handoffNote struct {
+	Issue       string
+	From        string
+	To          string
+	By          string
+	At          time.Time
+	Message     string
+	Branch      string
+	Base        string
+	Commits     []git.CommitSummary
+	Uncommitted []string // git status --short lines, committed as WIP
+	Pushed      string   // Remote the branch was pushed to, if any
+	WorkDir     string   // The receiving polecat's worktree, if any
+}
+
+// String renders the note as plain text.
+func (n *handoffNote) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Handoff of %s from %s to %s (by %s, %s)\n", n.Issue, n.From, n.To, n.By, n.At.Format("2006-01-02 15:04"))
+	if n.Message != "" {
+		fmt.Fprintf(&b, "\n%s\n", n.Message)
+	}
+	if n.Branch == "" {
+		b.WriteString("\nNo work branch to hand over.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nBranch: %s (%d commit(s) ahead of %s)\n", n.Branch, len(n.Commits), n.Base)
+	for _, c := range n.Commits {
+		fmt.Fprintf(&b, "  %s %s\n", shortSHA(c.SHA), c.Subject)
+	}
+	if len(n.Uncommitted) > 0 {
+		b.WriteString("Uncommitted changes, now in a WIP commit:\n")
+		for _, line := range n.Uncommitted {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	if n.Pushed != "" {
+		fmt.Fprintf(&b, "Pushed to %s\n", n.Pushed)
+	}
+	if n.WorkDir != "" {
+		fmt.Fprintf(&b, "Checked out in %s\n", n.WorkDir)
+	}
+	return b.String()
+}
+
+// resolveHandoffWorker resolves a --to target: a polecat by name or
+// address, a crew address, mayor, or overseer (the human operator).
+func resolveHandoffWorker(target string) (*handoffWorker, error) {
+	target = strings.TrimSuffix(target, "/")
+	switch target {
+	case "mayor", "overseer":
+		return &handoffWorker{Address: target}, nil
+	}
+	addr := target
+	if strings.Contains(target, "/") {
+		id, err := session.ParseAddress(target)
+		if err != nil {
+			return nil, err
+		}
+		switch id.Role {
+		case session.RoleCrew:
+			if _, _, err := getRig(id.Rig); err != nil {
+				return nil, err
+			}
+			return &handoffWorker{Address: target, Rig: id.Rig}, nil
+		case session.RolePolecat:
+			addr = id.Rig + "/" + id.Name
+		default:
+			return nil, fmt.Errorf("%s can't take over work (want a polecat, crew member, mayor or overseer)", target)
+		}
+	}
+	rigName, polecatName, err := resolvePolecatAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &handoffWorker{Address: rigName + "/polecats/" + polecatName, Rig: rigName, Polecat: polecatName}, nil
+}
+
+// handoffHolder describes an issue's current holder.
+func handoffHolder(address string) *handoffWorker {
+	w := &handoffWorker{Address: address}
+	if id, err := session.ParseAddress(address); err == nil {
+		w.Rig = id.Rig
+		if id.Role == session.RolePolecat {
+			w.Polecat = id.Name
+		}
+	}
+	return w
+}
+
+// runHandoffTransfer hands an issue, and its branch, from its holder to
+// another worker (gt handoff <issue> --to).
+func runHandoffTransfer(issueID string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bd := beads.New(townRoot)
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return fmt.Errorf("getting issue %s: %w", issueID, err)
+	}
+	lease := claim.FromIssue(issue)
+	if lease == nil {
+		return fmt.Errorf("%s isn't claimed, hooked or in progress; use gt sling to assign it", issueID)
+	}
+	from := handoffHolder(lease.Holder)
+	to, err := resolveHandoffWorker(handoffTo)
+	if err != nil {
+		return err
+	}
+	if lease.HeldBy(to.Address) {
+		return fmt.Errorf("%s is already held by %s", issueID, lease.Holder)
+	}
+
+	note := &handoffNote{
+		Issue:   issue.ID,
+		From:    from.Address,
+		To:      to.Address,
+		By:      strings.TrimSuffix(detectSender(), "/"),
+		At:      time.Now(),
+		Message: handoffMessage,
+	}
+
+	// The holder's worktree, if it is a polecat that still has one
+	var fromGit *git.Git
+	if from.Polecat != "" {
+		if mgr, _, err := getPolecatManager(from.Rig); err == nil {
+			if p, err := mgr.Get(from.Polecat); err == nil {
+				fromGit = git.NewGit(p.ClonePath)
+			}
+		}
+	}
+
+	// The receiving polecat's worktree, which must be free to switch
+	var toGit *git.Git
+	if to.Polecat != "" {
+		mgr, _, err := getPolecatManager(to.Rig)
+		if err != nil {
+			return err
+		}
+		p, err := mgr.Get(to.Polecat)
+		if err != nil {
+			return fmt.Errorf("getting %s: %w", to.Address, err)
+		}
+		if to.Rig != from.Rig && fromGit != nil {
+			return fmt.Errorf("%s and %s are in different rigs; a branch only moves between polecats of one rig", from.Address, to.Address)
+		}
+		toGit = git.NewGit(p.ClonePath)
+		if dirty, err := toGit.HasUncommittedChanges(); err != nil {
+			return fmt.Errorf("checking %s for uncommitted changes: %w", to.Address, err)
+		} else if dirty {
+			return fmt.Errorf("%s has uncommitted changes; it can't take over %s", to.Address, issueID)
+		}
+		if !handoffForce {
+			for _, status := range []string{beads.StatusHooked, "in_progress"} {
+				held, err := bd.List(beads.ListOptions{Status: status, Assignee: to.Address, Priority: -1})
+				if err == nil && len(held) > 0 {
+					return fmt.Errorf("%s is working on %s; use --force to hand it %s anyway", to.Address, held[0].ID, issueID)
+				}
+			}
+		}
+		note.WorkDir = p.ClonePath
+	}
+
+	// Stop a holder that's still at it, now the transfer can go ahead
+	if from.Polecat != "" {
+		sessMgr, _, err := getSessionManager(from.Rig)
+		if err != nil {
+			return err
+		}
+		running, err := sessMgr.IsRunning(from.Polecat)
+		if err != nil {
+			return fmt.Errorf("checking %s's session: %w", from.Address, err)
+		}
+		if running {
+			if !handoffForce {
+				return fmt.Errorf("%s's session is still running; use --force to stop it and take the work", from.Address)
+			}
+			if handoffDryRun {
+				fmt.Printf("Would stop %s's session\n", from.Address)
+			} else if err := sessMgr.Stop(from.Polecat, true); err != nil {
+				return fmt.Errorf("stopping %s's session: %w", from.Address, err)
+			}
+		}
+	}
+
+	if fromGit != nil {
+		if err := collectHandoffWork(note, fromGit, from); err != nil {
+			return err
+		}
+	}
+
+	if handoffDryRun {
+		fmt.Printf("Would hand %s from %s to %s with note:\n\n%s", issueID, from.Address, to.Address, note)
+		return nil
+	}
+
+	// Move the branch: commit what was in flight, then free the branch
+	// for the receiving worktree, or publish it for a human
+	if note.Branch != "" {
+		if len(note.Uncommitted) > 0 {
+			if err := fromGit.Add("-A"); err != nil {
+				return fmt.Errorf("staging %s's changes: %w", from.Address, err)
+			}
+			if err := fromGit.Commit(fmt.Sprintf("WIP: %s, handed off to %s", issueID, to.Address)); err != nil {
+				return fmt.Errorf("committing %s's changes: %w", from.Address, err)
+			}
+		}
+		if toGit != nil {
+			head, err := fromGit.Rev("HEAD")
+			if err != nil {
+				return fmt.Errorf("reading %s's branch: %w", from.Address, err)
+			}
+			if err := fromGit.Checkout(head); err != nil {
+				return fmt.Errorf("releasing %s from %s's worktree: %w", note.Branch, from.Address, err)
+			}
+			if err := toGit.Checkout(note.Branch); err != nil {
+				return fmt.Errorf("checking out %s for %s: %w", note.Branch, to.Address, err)
+			}
+		} else if err := fromGit.Push("origin", note.Branch, false); err != nil {
+			style.PrintWarning("could not push %s: %v", note.Branch, err)
+		} else {
+			note.Pushed = "origin"
+		}
+	}
+
+	if _, err := claim.Transfer(townRoot, bd, issueID, to.Address, claim.Options{}); err != nil {
+		return err
+	}
+
+	// Agent beads track their current work (hook_bead)
+	if beadID := agentIDToBeadID(from.Address, townRoot); beadID != "" {
+		agentBeads := beads.New(beads.ResolveHookDir(townRoot, beadID, townRoot))
+		if err := agentBeads.ClearHookBead(beadID); err != nil && !errors.Is(err, beads.ErrNotFound) {
+			style.PrintWarning("could not clear %s's hook: %v", from.Address, err)
+		}
+	}
+	if to.Polecat != "" {
+		updateAgentHookBead(to.Address, issueID, note.WorkDir, "")
+	}
+
+	text := note.String()
+	if err := bd.Comment(issueID, text); err != nil {
+		style.PrintWarning("could not add the handoff note to %s: %v", issueID, err)
+	}
+	notifyHandoff(townRoot, note, text)
+	_ = events.LogFeed(events.TypeHandoff, note.By, events.HandoffPayload(fmt.Sprintf("%s to %s", issueID, to.Address), false))
+
+	fmt.Printf("%s Handed %s from %s to %s\n", style.Success.Render("✓"), issueID, from.Address, to.Address)
+	if note.Branch != "" {
+		fmt.Printf("  Branch: %s\n", note.Branch)
+	}
+	if note.WorkDir != "" {
+		fmt.Printf("  Work dir: %s\n", note.WorkDir)
+	}
+	if to.Polecat != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Start it with: gt session start %s/%s", to.Rig, to.Polecat)))
+	}
+	return nil
+}
+
+// collectHandoffWork records the holder's branch and its state on note.
+// A worktree on its rig's default branch (or detached) has nothing to hand
+// over.
+func collectHandoffWork(note *handoffNote, g *git.Git, from *handoffWorker) error {
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("reading %s's branch: %w", from.Address, err)
+	}
+	base := "main"
+	if _, r, err := getRig(from.Rig); err == nil {
+		base = r.DefaultBranch()
+	}
+	if branch == "HEAD" || branch == base {
+		return nil
+	}
+	note.Branch = branch
+	note.Base = "origin/" + base
+	if note.Commits, err = g.CommitSummaries(note.Base, branch); err != nil {
+		style.PrintWarning("could not list %s's commits: %v", branch, err)
+	}
+	status, err := g.StatusShort()
+	if err != nil {
+		return fmt.Errorf("reading %s's changes: %w", from.Address, err)
+	}
+	for _, line := range strings.Split(status, "\n") {
+		if line != "" && !strings.HasPrefix(line, "##") {
+			note.Uncommitted = append(note.Uncommitted, line)
+		}
+	}
+	return nil
+}
+
+// notifyHandoff mails the handoff note to both workers, and nudges a
+// receiving polecat whose session is up.
+func notifyHandoff(townRoot string, note *handoffNote, text string) {
+	router := mail.NewRouter(townRoot)
+	for _, msg := range []*mail.Message{
+		{To: note.To, Subject: fmt.Sprintf("HANDOFF: %s is yours (from %s)", note.Issue, note.From), Type: mail.TypeTask},
+		{To: note.From, Subject: fmt.Sprintf("HANDOFF: %s went to %s", note.Issue, note.To), Type: mail.TypeNotification},
+	} {
+		msg.From = note.By
+		msg.Body = text
+		msg.Priority = mail.PriorityNormal
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("could not notify %s: %v", msg.To, err)
+		}
+	}
+
+	to := handoffHolder(note.To)
+	if to.Polecat == "" {
+		return
+	}
+	if sessMgr, _, err := getSessionManager(to.Rig); err == nil {
+		if err := sessMgr.Inject(to.Polecat, fmt.Sprintf("%s was handed to you; check your mail (gt mail inbox)", note.Issue)); err != nil && !errors.Is(err, polecat.ErrSessionNotFound) {
+			style.PrintWarning("could not nudge %s: %v", note.To, err)
+		}
+	}
+}
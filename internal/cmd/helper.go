@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/helper"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	helperServeIdle   time.Duration
+	helperServeMaxAge time.Duration
+)
+
+var helperCmd = &cobra.Command{
+	Use:     "helper",
+	GroupID: GroupServices,
+	Short:   "Manage the bd/git query helper",
+	RunE:    requireSubcommand,
+	Long: `Manage the optional bd/git query helper.
+
+Most gt latency is spawning bd and git. The helper is a long-lived process
+that answers gt's read-only queries (bd list/ready/blocked/search/count,
+git rev-parse/rev-list/merge-base/show-ref/for-each-ref) over a unix socket,
+serving repeated queries from memory until the beads database or the repo's
+refs change. Writes always run bd and git directly.
+
+When no helper is running, gt runs every command itself, so the helper can
+be started and stopped at any time. Set GT_HELPER=0 to bypass a running
+helper.
+
+Commands:
+  serve   Run the helper in the foreground
+  status  Show whether a helper is running
+  stop    Stop the running helper`,
+}
+
+var helperServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the helper in the foreground",
+	Long: `Run the helper in the foreground, listening on the helper socket
+(~/.local/state/gastown/helper.sock, or $GT_HELPER_SOCKET).
+
+Supports systemd socket activation: started by a .socket unit, it serves the
+inherited socket. Pair it with --idle-exit so it exits when unused and is
+started again on the next query.
+
+Examples:
+  gt helper serve &
+  gt helper serve --idle-exit 10m   # Under a systemd .socket unit`,
+	Args: cobra.NoArgs,
+	RunE: runHelperServe,
+}
+
+var helperStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether a helper is running",
+	Long: `Show whether a helper is running, and its request and cache hit counts.
+
+Examples:
+  gt helper status
+  gt helper status --output json`,
+	Args: cobra.NoArgs,
+	RunE: runHelperStatus,
+}
+
+var helperStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running helper",
+	Long: `Stop the running helper. gt goes back to running bd and git directly.
+
+Examples:
+  gt helper stop`,
+	Args: cobra.NoArgs,
+	RunE: runHelperStop,
+}
+
+func init() {
+	helperServeCmd.Flags().DurationVar(&helperServeIdle, "idle-exit", 0, "Exit after this long without a query (0 = never)")
+	helperServeCmd.Flags().DurationVar(&helperServeMaxAge, "max-age", helper.DefaultMaxAge, "Longest a cached result is served (0 disables caching)")
+
+	helperCmd.AddCommand(helperServeCmd)
+	helperCmd.AddCommand(helperStatusCmd)
+	helperCmd.AddCommand(helperStopCmd)
+	rootCmd.AddCommand(helperCmd)
+}
+
+func runHelperServe(cmd *cobra.Command, args []string) error {
+	socket := helper.SocketPath()
+	ln, err := helper.Listen(socket)
+	if err != nil {
+		return err
+	}
+
+	s := helper.NewServer(socket)
+	s.MaxAge = helperServeMaxAge
+	s.IdleTimeout = helperServeIdle
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		s.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Helper listening on %s\n", socket)
+	return s.Serve(ln)
+}
+
+func runHelperStatus(cmd *cobra.Command, args []string) error {
+	socket := helper.SocketPath()
+	stats, err := helper.GetStats(socket)
+	if structuredOutput(false) {
+		if err != nil {
+			stats = &helper.Stats{Socket: socket}
+		}
+		return renderStructured(stats)
+	}
+
+	if err != nil {
+		fmt.Printf("%s Helper not running %s\n", style.Dim.Render("○"), style.Dim.Render("("+socket+")"))
+		return nil
+	}
+	hitRate := 0.0
+	if stats.Requests > 0 {
+		hitRate = 100 * float64(stats.Hits) / float64(stats.Requests)
+	}
+	fmt.Printf("%s Helper running (PID %d)\n", style.Bold.Render("●"), stats.PID)
+	fmt.Printf("  Socket:  %s\n", stats.Socket)
+	fmt.Printf("  Uptime:  %s\n", time.Since(stats.StartedAt).Round(time.Second))
+	fmt.Printf("  Queries: %d (%.0f%% from cache)\n", stats.Requests, hitRate)
+	fmt.Printf("  Cached:  %d result(s)\n", stats.Entries)
+	return nil
+}
+
+func runHelperStop(cmd *cobra.Command, args []string) error {
+	socket := helper.SocketPath()
+	stats, err := helper.GetStats(socket)
+	if err != nil {
+		return fmt.Errorf("helper not running")
+	}
+	if err := helper.Shutdown(socket); err != nil {
+		return fmt.Errorf("stopping helper: %w", err)
+	}
+	fmt.Printf("%s Helper stopped (was PID %d)\n", style.Bold.Render("✓"), stats.PID)
+	return nil
+}
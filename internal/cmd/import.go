@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Import command flags
+var (
+	importForce   bool
+	importNoClone bool
+)
+
+var importCmd = &cobra.Command{
+	Use:     "import <archive> [path]",
+	GroupID: GroupWorkspace,
+	Short:   "Restore a town exported with gt export",
+	Long: `Restore a town's state from a 'gt export' archive into path (the
+current directory if omitted), then clone each rig's repositories again:
+the shared bare repo, the mayor clone and the refinery worktree.
+
+The archive's versions are checked first. An archive from a newer gt
+whose archive format or town config this gt can't read is refused before
+anything is written; a different gt version otherwise is only a warning.
+
+Import won't restore over an existing town unless --force is given, in
+which case the archive's files overwrite the town's. With --no-clone, the
+rigs' repositories are left out, to clone by hand.
+
+Crew workspaces aren't in the archive; import lists the 'gt crew add'
+commands that make them again.
+
+To bring a GitHub backlog into a rig's beads, see 'gt import github-issues'.
+
+Examples:
+  gt import town-backup.tar.zst ~/gt
+  gt import town-backup.tar.zst . --no-clone
+  gt import town-backup.tar.gz ~/gt -o json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Restore over an existing town")
+	importCmd.Flags().BoolVar(&importNoClone, "no-clone", false, "Don't clone the rigs' repositories")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+// ImportOutput is the structured output for gt import.
+type ImportOutput struct {
+	Archive  string           `json:"archive"`
+	Path     string           `json:"path"`
+	Manifest archive.Manifest `json:"manifest"`
+	Warnings []string         `json:"warnings,omitempty"`
+	Cloned   []string         `json:"cloned,omitempty"` // Rigs whose repositories were cloned
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 1 {
+		targetPath = args[1]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+
+	m, err := archive.ReadManifest(args[0])
+	if err != nil {
+		return err
+	}
+	if err := m.Check(); err != nil {
+		return err
+	}
+	if isWS, _ := workspace.IsWorkspace(absPath); isWS && !importForce {
+		return fmt.Errorf("%s is already a Gas Town HQ (use --force to restore over it)", absPath)
+	}
+
+	out := ImportOutput{Archive: args[0], Path: absPath}
+	if m.GTVersion != Version {
+		out.Warnings = append(out.Warnings,
+			fmt.Sprintf("archive was exported by gt %s, this is gt %s", m.GTVersion, Version))
+	}
+
+	restored, err := archive.Import(args[0], absPath)
+	if err != nil {
+		return err
+	}
+	out.Manifest = *restored
+
+	if !structuredOutput(false) {
+		for _, w := range out.Warnings {
+			fmt.Printf("%s %s\n", style.Warning.Render("⚠"), w)
+		}
+		fmt.Printf("%s Restored %s to %s (%d files, %s)\n", style.Success.Render("✓"),
+			style.Bold.Render(restored.TownName), absPath, restored.Files, formatBytes(restored.Bytes))
+	}
+
+	if !importNoClone {
+		cloned, warnings := restoreRigClones(absPath, restored.Rigs)
+		out.Cloned = cloned
+		out.Warnings = append(out.Warnings, warnings...)
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printImportNextSteps(out)
+	return nil
+}
+
+// restoreRigClones clones the imported rigs' repositories again. A rig
+// that fails is reported and skipped, so one unreachable remote doesn't
+// hold up the rest.
+func restoreRigClones(townRoot string, rigs []archive.Rig) (cloned, warnings []string) {
+	// Cloning narrates on stdout, which structured output owns
+	if structuredOutput(false) {
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, []string{fmt.Sprintf("loading rigs config: %v", err)}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	for _, r := range rigs {
+		fmt.Printf("\nCloning %s...\n", style.Bold.Render(r.Name))
+		if err := mgr.RestoreClones(r.Name); err != nil {
+			warning := fmt.Sprintf("rig %s: %v", r.Name, err)
+			warnings = append(warnings, warning)
+			fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), warning)
+			continue
+		}
+		cloned = append(cloned, r.Name)
+	}
+	return cloned, warnings
+}
+
+func printImportNextSteps(out ImportOutput) {
+	var crew []string
+	for _, r := range out.Manifest.Rigs {
+		for _, name := range r.Crew {
+			crew = append(crew, fmt.Sprintf("gt crew add %s --rig %s", name, r.Name))
+		}
+	}
+
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  cd %s\n", out.Path)
+	if len(crew) > 0 {
+		fmt.Printf("  %s\n", strings.Join(crew, "\n  "))
+	}
+	fmt.Printf("  gt doctor\n")
+	fmt.Printf("  gt up\n")
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracker"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Import github-issues command flags
+var (
+	importGitHubRepo     string
+	importGitHubLabels   []string
+	importGitHubState    string
+	importGitHubLimit    int
+	importGitHubPriority int
+	importGitHubRig      string
+	importGitHubDryRun   bool
+)
+
+var importGitHubIssuesCmd = &cobra.Command{
+	Use:   "github-issues",
+	Short: "Import GitHub issues into a rig's beads",
+	Long: `Create a bead for each selected issue in a GitHub repository, so a team
+adopting Gas Town keeps its backlog instead of re-entering it.
+
+Each bead gets the issue's title and body, its labels, and a link back to
+the issue. Issues labeled bug become bugs, enhancement or feature become
+features, and the rest tasks. A github:<owner>/<name>#<number> label
+records where the bead came from, so importing again skips issues already
+imported. Closed issues (with --state closed or all) are imported closed.
+
+Issues are read with the GitHub CLI (gh), which must be installed and
+logged in.
+
+Examples:
+  gt import github-issues --repo acme/widgets --dry-run
+  gt import github-issues --repo acme/widgets --label agent-ok
+  gt import github-issues --repo acme/widgets --rig greenplace --state all --limit 500`,
+	Args: cobra.NoArgs,
+	RunE: runImportGitHubIssues,
+}
+
+func init() {
+	importGitHubIssuesCmd.Flags().StringVar(&importGitHubRepo, "repo", "", "GitHub repository as owner/name (required)")
+	importGitHubIssuesCmd.Flags().StringSliceVarP(&importGitHubLabels, "label", "l", nil, "Import only issues with these labels (all of them)")
+	importGitHubIssuesCmd.Flags().StringVar(&importGitHubState, "state", "open", "Issues to import: open, closed or all")
+	importGitHubIssuesCmd.Flags().IntVar(&importGitHubLimit, "limit", 100, "Import at most this many issues")
+	importGitHubIssuesCmd.Flags().IntVarP(&importGitHubPriority, "priority", "p", 2, "Priority 0-4 for the imported beads")
+	importGitHubIssuesCmd.Flags().StringVar(&importGitHubRig, "rig", "", "Rig to import into (default: the current rig)")
+	importGitHubIssuesCmd.Flags().BoolVarP(&importGitHubDryRun, "dry-run", "n", false, "Show what would be imported without creating beads")
+	_ = importGitHubIssuesCmd.MarkFlagRequired("repo")
+
+	importCmd.AddCommand(importGitHubIssuesCmd)
+}
+
+// githubIssue is an issue as gh issue list --json reports it.
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"` // OPEN or CLOSED
+	URL    string `json:"url"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// ImportedGitHubIssue is an issue in gt import github-issues's structured
+// output.
+type ImportedGitHubIssue struct {
+	Number  int      `json:"number"`
+	URL     string   `json:"url"`
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	Labels  []string `json:"labels"`
+	Closed  bool     `json:"closed,omitempty"`
+	ID      string   `json:"id,omitempty"`      // The bead; empty for --dry-run
+	Skipped string   `json:"skipped,omitempty"` // Bead already imported from the issue
+}
+
+// ImportGitHubIssuesOutput is the structured output for gt import
+// github-issues.
+type ImportGitHubIssuesOutput struct {
+	Repo     string                `json:"repo"`
+	Rig      string                `json:"rig"`
+	DryRun   bool                  `json:"dry_run"`
+	Issues   []ImportedGitHubIssue `json:"issues"`
+	Imported int                   `json:"imported"`
+	Skipped  int                   `json:"skipped"`
+}
+
+func runImportGitHubIssues(cmd *cobra.Command, args []string) error {
+	if owner, name, ok := strings.Cut(importGitHubRepo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid --repo %q (expected owner/name)", importGitHubRepo)
+	}
+	switch importGitHubState {
+	case "open", "closed", "all":
+	default:
+		return fmt.Errorf("--state must be open, closed or all")
+	}
+	if importGitHubPriority < 0 || importGitHubPriority > 4 {
+		return fmt.Errorf("--priority must be 0-4")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := importGitHubRig
+	var r *rig.Rig
+	if rigName == "" {
+		if rigName, r, err = findCurrentRig(townRoot); err != nil {
+			return fmt.Errorf("%w (use --rig)", err)
+		}
+	} else if _, r, err = getRig(rigName); err != nil {
+		return err
+	}
+
+	issues, err := listGitHubIssues(importGitHubRepo, importGitHubState, importGitHubLabels, importGitHubLimit)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+	imported, err := importedGitHubIssues(bd, importGitHubRepo)
+	if err != nil {
+		return fmt.Errorf("listing imported issues: %w", err)
+	}
+
+	out := ImportGitHubIssuesOutput{Repo: importGitHubRepo, Rig: rigName, DryRun: importGitHubDryRun, Issues: []ImportedGitHubIssue{}}
+	for _, issue := range issues {
+		item := ImportedGitHubIssue{
+			Number: issue.Number,
+			URL:    issue.URL,
+			Title:  issue.Title,
+			Type:   githubIssueType(issue),
+			Labels: githubIssueLabels(importGitHubRepo, issue),
+			Closed: strings.EqualFold(issue.State, "closed"),
+		}
+		if id := imported[issue.Number]; id != "" {
+			item.Skipped = id
+			out.Skipped++
+		} else if !importGitHubDryRun {
+			if item.ID, err = createGitHubIssueBead(bd, importGitHubRepo, issue, item, importGitHubPriority); err != nil {
+				return fmt.Errorf("importing #%d: %w", issue.Number, err)
+			}
+			out.Imported++
+		} else {
+			out.Imported++
+		}
+		out.Issues = append(out.Issues, item)
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printImportGitHubIssues(out)
+	return nil
+}
+
+// listGitHubIssues reads a repository's issues with gh.
+func listGitHubIssues(repo, state string, labels []string, limit int) ([]githubIssue, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("GitHub CLI (gh) not found. Install it with: brew install gh")
+	}
+	args := []string{"issue", "list", "--repo", repo, "--state", state,
+		"--limit", strconv.Itoa(limit), "--json", "number,title,body,state,url,author,labels"}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	out, err := exec.Command("gh", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("gh issue list: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("gh issue list: %w", err)
+	}
+	var issues []githubIssue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing gh issue list output: %w", err)
+	}
+	return issues, nil
+}
+
+// importedGitHubIssues maps the numbers of a repository's issues already
+// imported into a rig to their beads.
+func importedGitHubIssues(bd *beads.Beads, repo string) (map[int]string, error) {
+	all, err := bd.ListIndexed(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	prefix := tracker.GitHubSourceLabelPrefix + repo + "#"
+	imported := make(map[int]string)
+	for _, issue := range all {
+		for _, label := range issue.Labels {
+			if rest, ok := strings.CutPrefix(label, prefix); ok {
+				if n, err := strconv.Atoi(rest); err == nil {
+					imported[n] = issue.ID
+				}
+			}
+		}
+	}
+	return imported, nil
+}
+
+// githubIssueType picks the bead type for an issue from its labels.
+func githubIssueType(issue githubIssue) string {
+	for _, label := range issue.Labels {
+		switch strings.ToLower(label.Name) {
+		case "bug":
+			return "bug"
+		case "enhancement", "feature":
+			return "feature"
+		}
+	}
+	return "task"
+}
+
+// githubIssueLabels returns the labels for an issue's bead: its own,
+// then the one recording where it came from.
+func githubIssueLabels(repo string, issue githubIssue) []string {
+	labels := []string{}
+	for _, label := range issue.Labels {
+		if name := strings.TrimSpace(label.Name); name != "" {
+			labels = append(labels, name)
+		}
+	}
+	return append(labels, fmt.Sprintf("%s%s#%d", tracker.GitHubSourceLabelPrefix, repo, issue.Number))
+}
+
+// githubIssueDescription returns an issue's body with a link back to it.
+func githubIssueDescription(repo string, issue githubIssue) string {
+	source := fmt.Sprintf("Imported from GitHub: %s#%d", repo, issue.Number)
+	if issue.Author.Login != "" {
+		source += " by @" + issue.Author.Login
+	}
+	if issue.URL != "" {
+		source += "\n" + issue.URL
+	}
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		return body + "\n\n---\n" + source
+	}
+	return source
+}
+
+// createGitHubIssueBead creates the bead for an issue, returning its ID.
+func createGitHubIssueBead(bd *beads.Beads, repo string, issue githubIssue, item ImportedGitHubIssue, priority int) (string, error) {
+	created, err := bd.Create(beads.CreateOptions{
+		Title:       issue.Title,
+		Type:        item.Type,
+		Priority:    priority,
+		Description: githubIssueDescription(repo, issue),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := bd.Update(created.ID, beads.UpdateOptions{AddLabels: item.Labels}); err != nil {
+		return created.ID, fmt.Errorf("labeling %s: %w", created.ID, err)
+	}
+	if item.Closed {
+		if err := bd.CloseWithReason("closed on GitHub", created.ID); err != nil {
+			return created.ID, fmt.Errorf("closing %s: %w", created.ID, err)
+		}
+	}
+	return created.ID, nil
+}
+
+func printImportGitHubIssues(out ImportGitHubIssuesOutput) {
+	if len(out.Issues) == 0 {
+		fmt.Printf("%s No matching issues in %s\n", style.Dim.Render("ℹ"), out.Repo)
+		return
+	}
+	for _, item := range out.Issues {
+		var status string
+		switch {
+		case item.Skipped != "":
+			status = style.Dim.Render("already " + item.Skipped)
+		case item.ID != "":
+			status = style.Success.Render("→ " + item.ID)
+		default:
+			status = style.Dim.Render("would import as " + item.Type)
+		}
+		fmt.Printf("  #%-5d %s  %s\n", item.Number, item.Title, status)
+	}
+	fmt.Println()
+	if out.DryRun {
+		fmt.Printf("%s Would import %d issue(s) from %s into %s, %d already imported %s\n", style.Dim.Render("ℹ"),
+			out.Imported, out.Repo, out.Rig, out.Skipped, style.Dim.Render("(dry run)"))
+		return
+	}
+	fmt.Printf("%s Imported %d issue(s) from %s into %s, %d already imported\n", style.Success.Render("✓"),
+		out.Imported, out.Repo, out.Rig, out.Skipped)
+}
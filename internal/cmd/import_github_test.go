@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGitHubIssueConversion(t *testing.T) {
+	var issue githubIssue
+	if err := json.Unmarshal([]byte(`{
+		"number": 42,
+		"title": "Login fails on Safari",
+		"body": "Steps: open /login\n",
+		"state": "OPEN",
+		"url": "https://github.com/acme/widgets/issues/42",
+		"author": {"login": "octocat"},
+		"labels": [{"name": "agent-ok"}, {"name": "Bug"}]
+	}`), &issue); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := githubIssueType(issue); got != "bug" {
+		t.Errorf("type = %q, want bug", got)
+	}
+	if got, want := strings.Join(githubIssueLabels("acme/widgets", issue), ","), "agent-ok,Bug,github:acme/widgets#42"; got != want {
+		t.Errorf("labels = %s, want %s", got, want)
+	}
+	desc := githubIssueDescription("acme/widgets", issue)
+	for _, want := range []string{"Steps: open /login\n\n---\n", "acme/widgets#42 by @octocat", issue.URL} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description %q missing %q", desc, want)
+		}
+	}
+
+	issue.Labels, issue.Body = nil, ""
+	if got := githubIssueType(issue); got != "task" {
+		t.Errorf("unlabeled type = %q, want task", got)
+	}
+	if desc := githubIssueDescription("acme/widgets", issue); !strings.HasPrefix(desc, "Imported from GitHub") {
+		t.Errorf("empty body description = %q", desc)
+	}
+}
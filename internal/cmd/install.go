@@ -20,6 +20,7 @@ import (
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/upgrade"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"github.com/steveyegge/gastown/internal/wrappers"
 )
@@ -192,6 +193,11 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("   ✓ Created mayor/rigs.json\n")
 
+	// A new town is already in the current format
+	if err := upgrade.MarkApplied(absPath, Version); err != nil {
+		return fmt.Errorf("writing migrations.json: %w", err)
+	}
+
 	// Create Mayor CLAUDE.md at mayor/ (Mayor's canonical home)
 	// NOTE: Role-specific CLAUDE.md stays in mayor/, but a generic identity anchor
 	// is also created at the town root (see createTownRootCLAUDEmd below).
@@ -352,6 +358,21 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Register the town so it can be selected with 'gt town use' from anywhere.
+	// An existing registration under the same name is left alone.
+	if reg, err := workspace.LoadRegistry(); err == nil {
+		if _, taken := reg.Towns[townName]; !taken && reg.NameFor(absPath) == "" {
+			if err := reg.Add(townName, absPath); err == nil {
+				if reg.Current == "" {
+					reg.Current = townName
+				}
+				if err := reg.Save(); err == nil {
+					fmt.Printf("   ✓ Registered town context %q (gt town list)\n", townName)
+				}
+			}
+		}
+	}
+
 	fmt.Printf("\n%s HQ created successfully!\n", style.Bold.Render("✓"))
 	fmt.Println()
 	fmt.Println("Next steps:")
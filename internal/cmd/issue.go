@@ -11,7 +11,7 @@ import (
 var issueCmd = &cobra.Command{
 	Use:     "issue",
 	GroupID: GroupConfig,
-	Short:   "Manage current issue for status line display",
+	Short:   "Create and start issues, manage the current issue for status line display, and split issues",
 }
 
 var issueSetCmd = &cobra.Command{
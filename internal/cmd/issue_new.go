@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/issuetmpl"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Issue new command flags
+var (
+	issueNewType     string
+	issueNewFields   []string
+	issueNewPriority int
+	issueNewLabels   []string
+	issueNewRig      string
+	issueNewNoEdit   bool
+	issueNewDryRun   bool
+)
+
+var issueNewCmd = &cobra.Command{
+	Use:   "new [title]",
+	Short: "Create an issue from the rig's template for its type",
+	Long: `Create an issue written in the rig's template for its type, so every
+bug, feature or chore carries the same fields and sections instead of free
+text.
+
+A template has one-line fields ("Component: auth") and markdown sections
+("## Steps to Reproduce"), some of them required, plus a default priority
+and labels. Rigs set them per type under issue_templates in
+settings/config.json; bug, feature and chore have built-in ones.
+
+Run in a terminal, the template opens in $VISUAL/$EDITOR, with the title
+and any --field values filled in. Otherwise (or with --no-edit) the issue
+is made from the title and --field flags alone. Either way, creation fails
+if a required field or section is empty.
+
+--field Name=value sets a field or section by name (case-insensitive); a
+section's value may span lines.
+
+Examples:
+  gt issue new --type bug "Login fails on Safari"
+  gt issue new --type bug "Login fails on Safari" \
+    --field "Steps to Reproduce=Open /login in Safari and submit" \
+    --field "Expected=Signed in" --field "Actual=Blank page"
+  gt issue new --type feature --rig greenplace -p 1
+  gt issue new --type chore "Bump deps" --field "Summary=Monthly update" --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIssueNew,
+}
+
+func init() {
+	issueNewCmd.Flags().StringVarP(&issueNewType, "type", "t", "", "Issue type: bug, feature, chore, or one of the rig's templates (required)")
+	issueNewCmd.Flags().StringArrayVarP(&issueNewFields, "field", "f", nil, "Fill in a field or section: Name=value (repeatable)")
+	issueNewCmd.Flags().IntVarP(&issueNewPriority, "priority", "p", -1, "Priority 0-4 (default: the template's)")
+	issueNewCmd.Flags().StringSliceVarP(&issueNewLabels, "label", "l", nil, "Labels to add, besides the template's")
+	issueNewCmd.Flags().StringVar(&issueNewRig, "rig", "", "Rig to create the issue in (default: the current rig)")
+	issueNewCmd.Flags().BoolVar(&issueNewNoEdit, "no-edit", false, "Don't open the template in $EDITOR")
+	issueNewCmd.Flags().BoolVarP(&issueNewDryRun, "dry-run", "n", false, "Show the issue without creating it")
+	_ = issueNewCmd.MarkFlagRequired("type")
+
+	issueCmd.AddCommand(issueNewCmd)
+}
+
+// IssueNewOutput is the structured output for gt issue new.
+type IssueNewOutput struct {
+	ID          string   `json:"id,omitempty"` // Empty for --dry-run
+	Rig         string   `json:"rig"`
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels"`
+	Description string   `json:"description"`
+}
+
+func runIssueNew(cmd *cobra.Command, args []string) error {
+	if issueNewPriority > 4 {
+		return fmt.Errorf("--priority must be 0-4")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := issueNewRig
+	var r *rig.Rig
+	if rigName == "" {
+		if rigName, r, err = findCurrentRig(townRoot); err != nil {
+			return fmt.Errorf("%w (use --rig)", err)
+		}
+	} else if _, r, err = getRig(rigName); err != nil {
+		return err
+	}
+
+	templates, err := issuetmpl.Load(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading issue templates: %w", err)
+	}
+	tmpl, err := templates.Get(issueNewType)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	if len(args) > 0 {
+		values[issuetmpl.Title] = args[0]
+	}
+	if err := applyIssueFields(tmpl, values, issueNewFields); err != nil {
+		return err
+	}
+
+	editPath := ""
+	if !issueNewNoEdit && term.IsTerminal(int(os.Stdin.Fd())) && ui.IsTerminal() {
+		var text string
+		editPath, text, err = editDescription("gt-issue-*.md", tmpl.Render(values))
+		if err != nil {
+			return err
+		}
+		values = tmpl.Parse(text)
+	}
+	description, err := tmpl.Description(values)
+	var missing *issuetmpl.MissingError
+	if errors.As(err, &missing) {
+		if editPath != "" {
+			return fmt.Errorf("%w (your edits are in %s)", err, editPath)
+		}
+		return fmt.Errorf("%w: fill them in with --field Name=value", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := IssueNewOutput{
+		Rig:         rigName,
+		Type:        tmpl.Type(),
+		Title:       strings.TrimSpace(values[issuetmpl.Title]),
+		Priority:    tmpl.Priority(),
+		Labels:      issueNewLabelSet(tmpl.Labels(), issueNewLabels),
+		Description: description,
+	}
+	if issueNewPriority >= 0 {
+		out.Priority = issueNewPriority
+	}
+
+	if !issueNewDryRun {
+		bd := beads.New(r.BeadsPath())
+		issue, err := bd.Create(beads.CreateOptions{
+			Title:       out.Title,
+			Type:        out.Type,
+			Priority:    out.Priority,
+			Description: out.Description,
+		})
+		if err != nil {
+			return fmt.Errorf("creating issue: %w", err)
+		}
+		out.ID = issue.ID
+		if len(out.Labels) > 0 {
+			if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: out.Labels}); err != nil {
+				return fmt.Errorf("labeling %s: %w", issue.ID, err)
+			}
+		}
+	}
+	if editPath != "" {
+		_ = os.Remove(editPath)
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	if out.ID == "" {
+		fmt.Printf("Would create %s in %s (P%d", out.Type, out.Rig, out.Priority)
+		if len(out.Labels) > 0 {
+			fmt.Printf(", labels: %s", strings.Join(out.Labels, ", "))
+		}
+		fmt.Printf("): %s\n\n%s\n", out.Title, out.Description)
+		return nil
+	}
+	fmt.Printf("%s Created %s: %s\n", style.Bold.Render("✓"), out.ID, out.Title)
+	return nil
+}
+
+// applyIssueFields sets values from --field Name=value flags, by the
+// template's spelling of each name.
+func applyIssueFields(tmpl *issuetmpl.Template, values map[string]string, fields []string) error {
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("--field %q: want Name=value", field)
+		}
+		canonical, ok := tmpl.Canonical(name)
+		if !ok {
+			return fmt.Errorf("--field %q: the %s template has no %q (have: %s)", field, tmpl.Type(), name, strings.Join(tmpl.Names(), ", "))
+		}
+		values[canonical] = value
+	}
+	return nil
+}
+
+// issueNewLabelSet returns the template's labels followed by the extra
+// ones, without repeats.
+func issueNewLabelSet(template, extra []string) []string {
+	labels := []string{}
+	seen := make(map[string]bool)
+	for _, label := range append(append([]string(nil), template...), extra...) {
+		if label = strings.TrimSpace(label); label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
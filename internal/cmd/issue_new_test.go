@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/issuetmpl"
+)
+
+func TestApplyIssueFields(t *testing.T) {
+	tmpl, err := issuetmpl.New("bug", &config.IssueTemplateConfig{
+		Fields:   []string{"Component"},
+		Sections: []string{"Steps to Reproduce"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{}
+	if err := applyIssueFields(tmpl, values, []string{"component=auth", "steps to reproduce=Open /login\nSubmit", "title=a=b"}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Component": "auth", "Steps to Reproduce": "Open /login\nSubmit", "Title": "a=b"}
+	for k, v := range want {
+		if values[k] != v {
+			t.Errorf("values[%q] = %q, want %q", k, values[k], v)
+		}
+	}
+
+	for _, bad := range []string{"Component", "Severity=high"} {
+		if err := applyIssueFields(tmpl, values, []string{bad}); err == nil {
+			t.Errorf("applyIssueFields(%q) should fail", bad)
+		}
+	}
+}
+
+func TestIssueNewLabelSet(t *testing.T) {
+	got := issueNewLabelSet([]string{"triage", "bug-bash"}, []string{"auth", "triage", " "})
+	if !slices.Equal(got, []string{"triage", "bug-bash", "auth"}) {
+		t.Errorf("issueNewLabelSet = %q", got)
+	}
+	if got := issueNewLabelSet(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("issueNewLabelSet(nil, nil) = %#v, want empty", got)
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Split command flags
+var (
+	issueSplitInto   int
+	issueSplitSling  string
+	issueSplitDryRun bool
+)
+
+var issueSplitCmd = &cobra.Command{
+	Use:   "split <issue>",
+	Short: "Split an issue into subtasks",
+	Long: `Split an issue into child subtasks numbered <issue>.1, <issue>.2, ...
+
+Each subtask is created as a child of the issue, at its priority, and the
+issue is made to depend on every subtask, so it stays blocked until they
+are all closed. Numbering continues after any subtasks the issue already
+has.
+
+With --into N, the subtasks are titled "<title> (part i/N)". Otherwise
+their titles are read from stdin, one per line; blank lines and lines
+starting with # are skipped.
+
+With --sling <rig>, the subtasks are slung to the rig, each to its own
+fresh polecat. Subtask branches parse like any other issue's, so gt done
+and gt mq submit work unchanged.
+
+Examples:
+  gt issue split gt-abc --into 3
+  gt issue split gt-abc --into 3 --sling greenplace
+  printf 'Schema migration\nAPI endpoints\nUI\n' | gt issue split gt-abc
+  gt issue split gt-abc --into 2 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueSplit,
+}
+
+func init() {
+	issueSplitCmd.Flags().IntVar(&issueSplitInto, "into", 0, "Number of subtasks (default: one per line of stdin)")
+	issueSplitCmd.Flags().StringVar(&issueSplitSling, "sling", "", "Sling the subtasks to this rig, a fresh polecat each")
+	issueSplitCmd.Flags().BoolVarP(&issueSplitDryRun, "dry-run", "n", false, "Show the subtasks without creating them")
+
+	issueCmd.AddCommand(issueSplitCmd)
+}
+
+// IssueSplitOutput is the structured output for gt issue split.
+type IssueSplitOutput struct {
+	Parent   string            `json:"parent"`
+	Subtasks []IssueSplitChild `json:"subtasks"`
+	Slung    string            `json:"slung,omitempty"` // Rig the subtasks were slung to
+}
+
+// IssueSplitChild is one subtask created by gt issue split.
+type IssueSplitChild struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func runIssueSplit(cmd *cobra.Command, args []string) error {
+	parentID := args[0]
+	if issueSplitInto < 0 {
+		return fmt.Errorf("--into must be positive")
+	}
+	if issueSplitSling != "" {
+		if _, isRig := IsRigName(issueSplitSling); !isRig {
+			return fmt.Errorf("--sling: '%s' is not a rig", issueSplitSling)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(cwd)
+
+	parent, err := bd.Show(parentID)
+	if err != nil {
+		return fmt.Errorf("getting issue %s: %w", parentID, err)
+	}
+	if parent.Status == "closed" {
+		return fmt.Errorf("%s is closed", parentID)
+	}
+
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok && issueSplitInto == 0 {
+		if stat, err := f.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			return fmt.Errorf("no subtasks: use --into N or pipe titles on stdin")
+		}
+	}
+	titles, err := subtaskTitles(parent.Title, issueSplitInto, in)
+	if err != nil {
+		return err
+	}
+
+	// Continue numbering after existing subtasks
+	existing := parent.Children
+	if children, err := bd.List(beads.ListOptions{Parent: parent.ID, Status: "all", Priority: -1}); err == nil {
+		for _, child := range children {
+			existing = append(existing, child.ID)
+		}
+	}
+	next := nextSubtaskIndex(parent.ID, existing)
+
+	out := IssueSplitOutput{Parent: parent.ID}
+	for i, title := range titles {
+		out.Subtasks = append(out.Subtasks, IssueSplitChild{ID: fmt.Sprintf("%s.%d", parent.ID, next+i), Title: title})
+	}
+
+	if issueSplitDryRun {
+		fmt.Printf("Would split %s into %d subtasks:\n", parent.ID, len(out.Subtasks))
+		for _, child := range out.Subtasks {
+			fmt.Printf("  %s  %s\n", child.ID, child.Title)
+		}
+		if issueSplitSling != "" {
+			fmt.Printf("Would sling them to %s\n", issueSplitSling)
+		}
+		return nil
+	}
+
+	for i, child := range out.Subtasks {
+		description := fmt.Sprintf("Subtask %d of %d of %s: %s", i+1, len(out.Subtasks), parent.ID, parent.Title)
+		if _, err := bd.CreateWithID(child.ID, beads.CreateOptions{
+			Title:       child.Title,
+			Priority:    parent.Priority,
+			Description: description,
+			Parent:      parent.ID,
+		}); err != nil {
+			return fmt.Errorf("creating %s: %w", child.ID, err)
+		}
+		// The parent can't close until every subtask has
+		if err := bd.AddDependency(parent.ID, child.ID); err != nil {
+			return fmt.Errorf("making %s depend on %s: %w", parent.ID, child.ID, err)
+		}
+		if !structuredOutput(false) {
+			fmt.Printf("%s Created %s: %s\n", style.Bold.Render("✓"), child.ID, child.Title)
+		}
+	}
+
+	if issueSplitSling != "" {
+		if err := slingSubtasks(out.Subtasks, issueSplitSling); err != nil {
+			return err
+		}
+		out.Slung = issueSplitSling
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	return nil
+}
+
+// subtaskTitles returns the titles of the subtasks to create: into numbered
+// parts of title, or if into is 0, one per line of r.
+func subtaskTitles(title string, into int, r io.Reader) ([]string, error) {
+	if into > 0 {
+		titles := make([]string, into)
+		for i := range titles {
+			titles[i] = fmt.Sprintf("%s (part %d/%d)", title, i+1, into)
+		}
+		return titles, nil
+	}
+
+	var titles []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		titles = append(titles, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading subtasks: %w", err)
+	}
+	if len(titles) == 0 {
+		return nil, fmt.Errorf("no subtasks: use --into N or list titles on stdin")
+	}
+	return titles, nil
+}
+
+// nextSubtaskIndex returns the number of the next subtask of parentID,
+// after the highest <parentID>.<n> among existing.
+func nextSubtaskIndex(parentID string, existing []string) int {
+	highest := 0
+	for _, id := range existing {
+		suffix, ok := strings.CutPrefix(id, parentID+".")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(suffix); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest + 1
+}
+
+// slingSubtasks slings subtasks to rigName, each to a fresh polecat.
+func slingSubtasks(subtasks []IssueSplitChild, rigName string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	slingArgs := []string{"sling"}
+	for _, child := range subtasks {
+		slingArgs = append(slingArgs, child.ID)
+	}
+	slingArgs = append(slingArgs, rigName)
+
+	slingCmd := exec.Command("gt", slingArgs...)
+	slingCmd.Dir = townRoot
+	slingCmd.Stdout = os.Stdout
+	if structuredOutput(false) {
+		slingCmd.Stdout = os.Stderr
+	}
+	slingCmd.Stderr = os.Stderr
+	if err := slingCmd.Run(); err != nil {
+		return fmt.Errorf("slinging subtasks to %s: %w", rigName, err)
+	}
+	return nil
+}
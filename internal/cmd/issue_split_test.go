@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubtaskTitles(t *testing.T) {
+	got, err := subtaskTitles("Add OAuth", 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "|") != "Add OAuth (part 1/3)|Add OAuth (part 2/3)|Add OAuth (part 3/3)" {
+		t.Errorf("--into 3 titles = %q", got)
+	}
+
+	got, err = subtaskTitles("Add OAuth", 0, strings.NewReader("# plan\nSchema migration\n\n  API endpoints  \nUI\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "|") != "Schema migration|API endpoints|UI" {
+		t.Errorf("stdin titles = %q", got)
+	}
+
+	if _, err := subtaskTitles("Add OAuth", 0, strings.NewReader("\n# nothing\n")); err == nil {
+		t.Error("empty stdin should be an error")
+	}
+}
+
+func TestNextSubtaskIndex(t *testing.T) {
+	tests := []struct {
+		existing []string
+		want     int
+	}{
+		{nil, 1},
+		{[]string{"gt-abc.1", "gt-abc.2"}, 3},
+		{[]string{"gt-abc.2", "gt-abc.10", "gt-abc.2"}, 11},
+		{[]string{"gt-abcd.7", "gt-abc.x", "gt-abc.1.3"}, 1}, // Not gt-abc's direct subtasks
+	}
+	for _, tt := range tests {
+		if got := nextSubtaskIndex("gt-abc", tt.existing); got != tt.want {
+			t.Errorf("nextSubtaskIndex(%v) = %d, want %d", tt.existing, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Issue start command flags
+var (
+	issueStartTTL   time.Duration
+	issueStartForce bool
+	issueStartNoCD  bool
+)
+
+var issueStartCmd = &cobra.Command{
+	Use:   "start <issue-id>",
+	Short: "Claim an issue and switch to a work branch for it",
+	Long: `Do everything a worker needs to begin an issue, in one step:
+
+  1. Claim the issue (as gt claim: assign it to you with a lease)
+  2. Mark it in_progress
+  3. Create the work branch, named by the rig's branch template, from
+     origin's default branch, and check it out in your workspace
+  4. Set it as your current issue (tmux status line)
+  5. Print the work directory
+
+If your workspace is already on a work branch for the issue (e.g., the one
+your polecat was spawned on), that branch is kept, so running it again is
+safe; it also renews the claim. Otherwise the workspace must have no
+uncommitted changes.
+
+Run from your polecat or crew workspace. The last line printed is a cd
+command for the work directory; with --no-cd only the path is printed:
+
+  cd "$(gt issue start gt-abc --no-cd)"
+
+An issue claimed by someone else exits with code 11, as gt claim does;
+pick other work, or use --force if the holder is stuck.
+
+Examples:
+  gt issue start gt-abc
+  gt issue start gt-abc --ttl 6h
+  gt issue start gt-abc --no-cd`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueStart,
+}
+
+func init() {
+	issueStartCmd.Flags().DurationVar(&issueStartTTL, "ttl", claim.DefaultTTL, "How long the claim lasts")
+	issueStartCmd.Flags().BoolVar(&issueStartForce, "force", false, "Claim the issue even if someone else holds a live lease")
+	issueStartCmd.Flags().BoolVar(&issueStartNoCD, "no-cd", false, "Just print the work directory")
+
+	issueCmd.AddCommand(issueStartCmd)
+}
+
+// IssueStartOutput is the structured output for gt issue start.
+type IssueStartOutput struct {
+	Issue   string    `json:"issue"`
+	Title   string    `json:"title"`
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+	Branch  string    `json:"branch"`
+	Created bool      `json:"created"` // False if the workspace was already on the branch
+	WorkDir string    `json:"work_dir"`
+}
+
+func runIssueStart(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	info, err := GetRole()
+	if err != nil {
+		return fmt.Errorf("detecting role: %w", err)
+	}
+	if (info.Role != RolePolecat && info.Role != RoleCrew) || info.Rig == "" || info.Polecat == "" {
+		return fmt.Errorf("gt issue start runs in a polecat or crew workspace (role: %s)", info.Role)
+	}
+	_, r, err := getRig(info.Rig)
+	if err != nil {
+		return err
+	}
+	workDir, err := detectCloneRoot()
+	if err != nil {
+		return fmt.Errorf("finding your workspace: %w", err)
+	}
+
+	bd := beads.New(workDir)
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return fmt.Errorf("getting issue %s: %w", issueID, err)
+	}
+
+	// Work out the branch before claiming, so a workspace we can't switch
+	// doesn't leave a claim behind
+	g := git.NewGit(workDir)
+	policy, err := r.BranchPolicy()
+	if err != nil {
+		style.PrintWarning("%v; using default branch names", err)
+		policy = nil
+	}
+	current, _ := g.CurrentBranch()
+	branch, reuse := issueStartBranch(policy, current, issue.ID)
+	if !reuse {
+		if dirty, err := g.HasUncommittedChanges(); err != nil {
+			return fmt.Errorf("checking %s for uncommitted changes: %w", workDir, err)
+		} else if dirty {
+			return fmt.Errorf("%s has uncommitted changes; commit or stash them before starting %s", workDir, issue.ID)
+		}
+		vars := rig.BranchVars{Name: info.Polecat, Issue: issue.ID, Title: issue.Title, Now: time.Now()}
+		if policy.Uses("user") {
+			vars.User, _ = g.ConfigGet("user.name")
+		}
+		branch = policy.Name(vars)
+	}
+
+	agent := info.ActorString()
+	lease, err := claim.Claim(townRoot, bd, issue.ID, agent, claim.Options{TTL: issueStartTTL, Force: issueStartForce})
+	if err != nil {
+		var held *claim.HeldError
+		if errors.As(err, &held) {
+			return fmt.Errorf("%w\nPick other work, or use --force if the holder is stuck", err)
+		}
+		return err
+	}
+	// A claim only moves open work; hooked work starts here too
+	if issue.Status == beads.StatusHooked {
+		status := "in_progress"
+		if err := bd.Update(issue.ID, beads.UpdateOptions{Status: &status}); err != nil {
+			return fmt.Errorf("marking %s in_progress: %w", issue.ID, err)
+		}
+	}
+
+	if !reuse {
+		base := r.DefaultBranch()
+		if err := g.FetchBranch("origin", base); err != nil {
+			return fmt.Errorf("fetching origin/%s: %w", base, err)
+		}
+		if err := g.CreateBranchFrom(branch, "origin/"+base); err != nil {
+			return fmt.Errorf("creating branch %s: %w", branch, err)
+		}
+		if err := g.Checkout(branch); err != nil {
+			return fmt.Errorf("checking out %s: %w", branch, err)
+		}
+	}
+
+	// Best-effort: show the issue in the tmux status line
+	session := os.Getenv("TMUX_PANE")
+	if session == "" {
+		session = detectCurrentSession()
+	}
+	if session != "" {
+		_ = tmux.NewTmux().SetEnvironment(session, "GT_ISSUE", issue.ID)
+	}
+
+	out := IssueStartOutput{
+		Issue:   issue.ID,
+		Title:   issue.Title,
+		Holder:  lease.Holder,
+		Expires: lease.Expires,
+		Branch:  branch,
+		Created: !reuse,
+		WorkDir: workDir,
+	}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	if issueStartNoCD {
+		fmt.Println(workDir)
+		return nil
+	}
+
+	fmt.Printf("%s Started %s: %s\n", style.Success.Render("✓"), out.Issue, out.Title)
+	fmt.Printf("  Claimed for %s until %s\n", out.Holder, out.Expires.Local().Format("15:04 Jan 2"))
+	if out.Created {
+		fmt.Printf("  Branch: %s %s\n", out.Branch, style.Dim.Render("(new, from origin/"+r.DefaultBranch()+")"))
+	} else {
+		fmt.Printf("  Branch: %s %s\n", out.Branch, style.Dim.Render("(already checked out)"))
+	}
+	fmt.Printf("cd %s\n", out.WorkDir)
+	return nil
+}
+
+// issueStartBranch decides the work branch for issueID. If the current
+// branch is already a work branch for it, that is reused.
+func issueStartBranch(policy *rig.BranchPolicy, current, issueID string) (branch string, reuse bool) {
+	if parsed, ok := policy.Parse(current); ok && parsed.Issue == issueID {
+		return current, true
+	}
+	return "", false
+}
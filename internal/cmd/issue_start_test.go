@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestIssueStartBranch(t *testing.T) {
+	if branch, reuse := issueStartBranch(nil, "polecat/toast/gt-abc@mk3x9", "gt-abc"); !reuse || branch != "polecat/toast/gt-abc@mk3x9" {
+		t.Errorf("own work branch: got %q, %v; want reuse", branch, reuse)
+	}
+	if _, reuse := issueStartBranch(nil, "polecat/toast/gt-abc@mk3x9", "gt-def"); reuse {
+		t.Error("another issue's branch should not be reused")
+	}
+	if _, reuse := issueStartBranch(nil, "main", "gt-abc"); reuse {
+		t.Error("main should not be reused")
+	}
+
+	policy, err := rig.NewBranchPolicy("{user}/{issue}", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, reuse := issueStartBranch(policy, "alice/gt-abc", "gt-abc"); !reuse {
+		t.Error("templated work branch for the issue should be reused")
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// KB command flags
+var (
+	kbRig      string
+	kbLimit    int
+	kbMinScore float64
+	kbOpen     bool
+	kbHow      string
+	kbNote     string
+)
+
+var kbCmd = &cobra.Command{
+	Use:     "kb",
+	GroupID: GroupWork,
+	Short:   "Search past merge failures and how they were resolved",
+	RunE:    requireSubcommand,
+	Long: `Search the knowledge base of merge failures.
+
+Every failure the refinery hits (a conflict, failing tests, lint, a
+coverage drop, a build error) is kept in its rig's knowledge base, in
+.runtime/merge-failures.json. When the MR later merges, its failures are
+marked resolved by how it got through:
+
+  rebased   a conflict, rebased past
+  retried   merged without the branch changing: a flake, or fixed on the target
+  fixed     merged after the branch changed
+
+Failures fixed another way (a change on the target reverted, the rig's
+config or environment fixed) can be marked with 'gt kb resolve'.
+
+Before spending a work cycle on a failure, search for it: a known
+resolution is often the fix.`,
+}
+
+var kbSearchCmd = &cobra.Command{
+	Use:   "search <error text>...",
+	Short: "Find past failures like an error",
+	Long: `Find past merge failures whose error, failing tests or conflicting
+files contain the words of an error, in every rig or just --rig.
+
+SHAs, numbers and short words are ignored, so an error can be pasted as
+is. Failures containing at least --min-score of the error's words are
+listed, best match first, resolved failures ahead of unresolved ones.
+
+Examples:
+  gt kb search "undefined: config.AgentWindow"
+  gt kb search TestParallelReadySteps --rig greenplace
+  gt kb search "$(tail -5 test.log)" -o json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runKBSearch,
+}
+
+var kbListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded merge failures",
+	Long: `List the merge failures in every rig's knowledge base, or just --rig's,
+newest first.
+
+Examples:
+  gt kb list
+  gt kb list --rig greenplace --open`,
+	Args: cobra.NoArgs,
+	RunE: runKBList,
+}
+
+var kbResolveCmd = &cobra.Command{
+	Use:   "resolve <rig> <id>",
+	Short: "Record how a merge failure was resolved",
+	Long: `Record how a merge failure was resolved, replacing the resolution the
+refinery inferred, if any.
+
+--how is one of: retried, rebased, fixed, reverted, config, other. Say
+what the fix was with --message, so the next agent to hit the failure
+can apply it.
+
+Examples:
+  gt kb resolve greenplace kb-12 --how config -m "CI image lacked protoc; added to setup"
+  gt kb resolve greenplace kb-15 --how reverted -m "Reverted gp-88's migration on main"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runKBResolve,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{kbSearchCmd, kbListCmd} {
+		c.Flags().StringVar(&kbRig, "rig", "", "Only this rig's failures (default: every rig)")
+		c.Flags().IntVarP(&kbLimit, "limit", "n", 10, "Most failures to list (0 = all)")
+	}
+	kbSearchCmd.Flags().Float64Var(&kbMinScore, "min-score", refinery.DefaultKBMinScore, "Share of the error's words a failure must contain")
+	kbListCmd.Flags().BoolVar(&kbOpen, "open", false, "Only failures without a resolution")
+	kbResolveCmd.Flags().StringVar(&kbHow, "how", "", "How it was resolved (required)")
+	kbResolveCmd.Flags().StringVarP(&kbNote, "message", "m", "", "What the fix was")
+	_ = kbResolveCmd.MarkFlagRequired("how")
+
+	kbCmd.AddCommand(kbSearchCmd)
+	kbCmd.AddCommand(kbListCmd)
+	kbCmd.AddCommand(kbResolveCmd)
+	rootCmd.AddCommand(kbCmd)
+}
+
+// kbRigs returns --rig, or every rig.
+func kbRigs() ([]*rig.Rig, error) {
+	if kbRig != "" {
+		_, r, err := getRig(kbRig)
+		if err != nil {
+			return nil, err
+		}
+		return []*rig.Rig{r}, nil
+	}
+	rigs, _, err := getAllRigs()
+	return rigs, err
+}
+
+func runKBSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	rigs, err := kbRigs()
+	if err != nil {
+		return err
+	}
+	matches := []refinery.KBMatch{}
+	for _, r := range rigs {
+		for _, m := range refinery.NewFailureKB(r.Path).Search(query, kbMinScore) {
+			m.Rig = r.Name
+			matches = append(matches, m)
+		}
+	}
+	refinery.RankMatches(matches)
+	if kbLimit > 0 && len(matches) > kbLimit {
+		matches = matches[:kbLimit]
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(matches)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No past failures like this one"))
+		return nil
+	}
+	for _, m := range matches {
+		printKBRecord(m.Rig, m.FailureRecord, fmt.Sprintf("%.0f%% match", m.Score*100))
+	}
+	return nil
+}
+
+// KBListItem is a failure in gt kb list's structured output.
+type KBListItem struct {
+	refinery.FailureRecord
+	Rig string `json:"rig"`
+}
+
+func runKBList(cmd *cobra.Command, args []string) error {
+	rigs, err := kbRigs()
+	if err != nil {
+		return err
+	}
+	items := []KBListItem{}
+	for _, r := range rigs {
+		for _, f := range refinery.NewFailureKB(r.Path).List() {
+			if kbOpen && f.Resolved() {
+				continue
+			}
+			items = append(items, KBListItem{FailureRecord: f, Rig: r.Name})
+		}
+	}
+	slices.SortStableFunc(items, func(a, b KBListItem) int { return b.At.Compare(a.At) })
+	if kbLimit > 0 && len(items) > kbLimit {
+		items = items[:kbLimit]
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(items)
+	}
+	if len(items) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No merge failures recorded"))
+		return nil
+	}
+	for _, item := range items {
+		printKBRecord(item.Rig, item.FailureRecord, formatAge(item.At))
+	}
+	return nil
+}
+
+func runKBResolve(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(refinery.Resolutions, kbHow) {
+		return fmt.Errorf("--how %q: must be one of %s", kbHow, strings.Join(refinery.Resolutions, ", "))
+	}
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := refinery.NewFailureKB(r.Path).Resolve(args[1], refinery.FailureResolution{How: kbHow, Note: kbNote, By: detectActor()})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Resolved %s (%s failure of %s): %s\n", style.Success.Render("✓"), f.ID, f.Kind, f.MR, kbHow)
+	return nil
+}
+
+// printKBRecord prints a failure and its resolution.
+func printKBRecord(rigName string, f refinery.FailureRecord, note string) {
+	fmt.Printf("%s %s %s %s\n", style.Bold.Render(rigName+"/"+f.ID), f.Kind, f.MR, style.Dim.Render("("+note+")"))
+	errText := strings.TrimSpace(f.Error)
+	if i := strings.IndexByte(errText, '\n'); i >= 0 {
+		errText = errText[:i] + " …"
+	}
+	fmt.Printf("  Error: %s\n", errText)
+	if len(f.Tests) > 0 {
+		fmt.Printf("  Tests: %s\n", strings.Join(f.Tests, ", "))
+	}
+	if len(f.Files) > 0 {
+		fmt.Printf("  Files: %s\n", strings.Join(f.Files, ", "))
+	}
+	if res := f.Resolution; res != nil {
+		line := res.How
+		if res.Note != "" {
+			line += ": " + res.Note
+		}
+		if res.MergeCommit != "" {
+			line += style.Dim.Render(" (merged in " + shortSHA(res.MergeCommit) + ")")
+		}
+		fmt.Printf("  %s %s\n", style.Success.Render("Resolved:"), line)
+	} else {
+		fmt.Printf("  %s\n", style.Warning.Render("Unresolved"))
+	}
+	fmt.Println()
+}
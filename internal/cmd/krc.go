@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -132,13 +131,8 @@ func runKrcStats(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting stats: %w", err)
 	}
 
-	if krcStatsJSON {
-		data, err := json.MarshalIndent(stats, "", "  ")
-		if err != nil {
-			return err
-		}
-		fmt.Println(string(data))
-		return nil
+	if structuredOutput(krcStatsJSON) {
+		return renderStructured(stats)
 	}
 
 	// Human-readable output
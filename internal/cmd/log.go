@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crash"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -25,6 +27,7 @@ var (
 	crashAgent    string
 	crashSession  string
 	crashExitCode int
+	crashWorkDir  string
 )
 
 var logCmd = &cobra.Command{
@@ -64,6 +67,10 @@ The exit code determines if this was a crash or expected exit:
   - Exit code 0: Expected exit (logged as 'done' if no other done was recorded)
   - Exit code non-zero: Crash (logged as 'crash')
 
+For a crash, a report bundle is also captured under ~/gt/.runtime/crashes/
+with the pane's last lines, the worktree's git status, and recent events.
+Review bundles with 'gt crashes'.
+
 Examples:
   gt log crash --agent greenplace/Toast --session gt-greenplace-Toast --exit-code 1`,
 	RunE: runLogCrash,
@@ -80,6 +87,7 @@ func init() {
 	logCrashCmd.Flags().StringVar(&crashAgent, "agent", "", "Agent ID (e.g., greenplace/Toast)")
 	logCrashCmd.Flags().StringVar(&crashSession, "session", "", "Tmux session name")
 	logCrashCmd.Flags().IntVar(&crashExitCode, "exit-code", -1, "Exit code from pane")
+	logCrashCmd.Flags().StringVar(&crashWorkDir, "workdir", "", "Pane working directory (for the crash report's git status)")
 	_ = logCrashCmd.MarkFlagRequired("agent")
 
 	logCmd.AddCommand(logCrashCmd)
@@ -98,7 +106,11 @@ func runLog(cmd *cobra.Command, args []string) error {
 	if logFollow {
 		return followLog(logPath)
 	}
+	return withPager(func() error { return showLog(townRoot, logPath) })
+}
 
+// showLog prints the town log's events that match the filter flags.
+func showLog(townRoot, logPath string) error {
 	// Check if log file exists
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		fmt.Printf("%s No log file yet (no events recorded)\n", style.Dim.Render("○"))
@@ -348,6 +360,23 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("logging event: %w", err)
 	}
 
+	// For a real crash, bundle the pane, worktree status, and recent events
+	// while the dead pane is still around (pane-died fires with remain-on-exit)
+	if eventType == townlog.EventCrash {
+		report, err := crash.Capture(townRoot, crash.Options{
+			Agent:    crashAgent,
+			Session:  crashSession,
+			ExitCode: crashExitCode,
+			Reason:   context,
+			WorkDir:  crashWorkDir,
+		})
+		if err != nil {
+			return fmt.Errorf("capturing crash report: %w", err)
+		}
+		_ = events.LogFeed(events.TypeCrashReport, crashAgent,
+			events.CrashReportPayload(crashSession, crashAgent, report.ID, crashExitCode))
+	}
+
 	return nil
 }
 
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Digest command flags
+var mailDigestSend bool
+
+var mailDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Show or send the Mayor's held notification digest",
+	Long: `Show the mail held for the Mayor's next digest, or send it now.
+
+With mayor_digest set in the town's settings/config.json, routine mail to
+the Mayor is held instead of delivered one message at a time:
+  interval    How long mail is held before the digest goes out (default "1h")
+  categories  Which mail to hold: mr, escalation, stuck (default: all)
+  inject      Also type a one-line briefing into the Mayor's session
+
+Categories are recognised by subject: merge queue outcomes (MERGED,
+MR_SLA_BREACH, "Merge request rejected"), escalations ("[HIGH] ...",
+"Escalation: ...", RECOVERY_NEEDED), and stuck-worker alerts ("Agent
+killed: ...", FORCE_KILL, "ALERT: ..."). Urgent mail is never held.
+
+The daemon sends the digest once the oldest held mail has waited an
+interval: one message from "daemon" with a section per category.
+
+Examples:
+  gt mail digest            # What the next digest holds
+  gt mail digest --send     # Deliver it now
+  gt mail digest -o json`,
+	Args: cobra.NoArgs,
+	RunE: runMailDigest,
+}
+
+func init() {
+	mailDigestCmd.Flags().BoolVar(&mailDigestSend, "send", false, "Deliver the held mail to the Mayor now")
+
+	mailCmd.AddCommand(mailDigestCmd)
+}
+
+// MailDigestOutput is the structured output for gt mail digest.
+type MailDigestOutput struct {
+	Enabled  bool               `json:"enabled"`
+	Interval string             `json:"interval,omitempty"`
+	Sent     int                `json:"sent,omitempty"`
+	Held     []mail.DigestEntry `json:"held"`
+}
+
+func runMailDigest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	digest, err := mail.LoadDigest(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading mayor_digest settings: %w", err)
+	}
+
+	out := MailDigestOutput{Enabled: digest != nil}
+	if digest != nil {
+		out.Interval = digest.Interval().String()
+	}
+
+	if mailDigestSend {
+		sent, err := mail.NewRouterWithTownRoot(townRoot, townRoot).SendDigest(digest.Inject())
+		if err != nil {
+			return fmt.Errorf("sending digest: %w", err)
+		}
+		out.Sent = sent
+	}
+
+	out.Held, err = mail.PendingDigest(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading held mail: %w", err)
+	}
+	if out.Held == nil {
+		out.Held = []mail.DigestEntry{}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printMailDigest(out, digest.Interval())
+	return nil
+}
+
+func printMailDigest(out MailDigestOutput, interval time.Duration) {
+	if mailDigestSend {
+		if out.Sent == 0 {
+			fmt.Println("No mail held; nothing sent")
+		} else {
+			fmt.Printf("%s Sent the Mayor a digest of %d notification(s)\n", style.Bold.Render("✓"), out.Sent)
+		}
+		return
+	}
+
+	if !out.Enabled {
+		fmt.Println(style.Dim.Render("Mayor digest is off (set mayor_digest in settings/config.json); mail is delivered as sent"))
+	}
+	if len(out.Held) == 0 {
+		fmt.Println("No mail held for the Mayor's digest")
+		return
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Held for the Mayor's digest (%d):", len(out.Held))))
+	for _, e := range out.Held {
+		fmt.Printf("  %s %-10s %s %s\n",
+			style.Dim.Render(e.HeldAt.Local().Format("15:04")), e.Category, e.Subject,
+			style.Dim.Render("from "+e.From))
+	}
+	if out.Enabled {
+		due := out.Held[0].HeldAt.Add(interval)
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Goes out around %s (every %s)", due.Local().Format("15:04"), interval)))
+	}
+}
@@ -199,6 +199,14 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return fmt.Errorf("building startup command: %w", err)
 			}
+			startupCmd, _, err = config.ApplySessionTemplate(townRoot, config.SessionTemplateVars{
+				Role:     "mayor",
+				TownRoot: townRoot,
+				Command:  startupCmd,
+			})
+			if err != nil {
+				return err
+			}
 
 			// Set remain-on-exit so the pane survives process death during respawn.
 			// Without this, killing processes causes tmux to destroy the pane.
@@ -233,6 +241,18 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	info, err := mgr.Status()
+	if structuredOutput(false) {
+		if err != nil && err != mayor.ErrNotRunning {
+			return fmt.Errorf("checking status: %w", err)
+		}
+		out := MayorStatusOutput{Session: mgr.SessionName()}
+		if info != nil {
+			out.Running = true
+			out.Attached = info.Attached
+			out.Created = info.Created
+		}
+		return renderStructured(out)
+	}
 	if err != nil {
 		if err == mayor.ErrNotRunning {
 			fmt.Printf("%s Mayor session is %s\n",
@@ -258,6 +278,14 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// MayorStatusOutput is the structured output for gt mayor status.
+type MayorStatusOutput struct {
+	Session  string `json:"session"`
+	Running  bool   `json:"running"`
+	Attached bool   `json:"attached"`
+	Created  string `json:"created,omitempty"`
+}
+
 func runMayorRestart(cmd *cobra.Command, args []string) error {
 	mgr, err := getMayorManager()
 	if err != nil {
@@ -85,8 +85,8 @@ func init() {
 		"Maximum interval cap for backoff (e.g., 10m)")
 	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalAgentBead, "agent-bead", "",
 		"Agent bead ID for tracking idle cycles (reads/writes idle:N label)")
-	moleculeAwaitSignalCmd.Flags().BoolVar(&awaitSignalQuiet, "quiet", false,
-		"Suppress output (for scripting)")
+	legacyQuietFlag(moleculeAwaitSignalCmd, &awaitSignalQuiet, "Suppress output (for scripting)",
+		"it will hide all output but --json in a future release")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&moleculeJSON, "json", false,
 		"Output as JSON")
 
@@ -4,24 +4,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/permission"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wisp"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // MQ command flags
 var (
 	// Submit flags
-	mqSubmitBranch    string
-	mqSubmitIssue     string
-	mqSubmitEpic      string
-	mqSubmitPriority  int
-	mqSubmitNoCleanup bool
+	mqSubmitBranch      string
+	mqSubmitIssue       string
+	mqSubmitEpic        string
+	mqSubmitTarget      string
+	mqSubmitPriority    int
+	mqSubmitNoCleanup   bool
+	mqSubmitWatch       bool
+	mqSubmitTimeout     time.Duration
+	mqSubmitIgnoreWIP   bool
+	mqSubmitIgnoreClaim bool
+	mqSubmitBodyFile    string
+	mqSubmitNoEdit      bool
+	mqSubmitLabels      []string
+	mqSubmitLocks       []string
+	mqSubmitForceNew    bool
 
 	// Retry flags
 	mqRetryNow bool
@@ -31,11 +44,19 @@ var (
 	mqRejectNotify bool
 
 	// List command flags
-	mqListReady  bool
-	mqListStatus string
-	mqListWorker string
-	mqListEpic   string
-	mqListJSON   bool
+	mqListReady       bool
+	mqListStatus      string
+	mqListWorker      string
+	mqListEpic        string
+	mqListTarget      string
+	mqListLabels      []string
+	mqListOlderThan   string
+	mqListView        string
+	mqListColumnsFlag string
+	mqListSort        string
+	mqListReverse     bool
+	mqListPage        listPage
+	mqListJSON        bool
 
 	// Status command flags
 	mqStatusJSON bool
@@ -82,12 +103,24 @@ Auto-detection:
   - Priority: inherited from source issue
 
 Target branch auto-detection:
-  1. If --epic is specified: target integration/<epic>
-  2. If source issue has a parent epic with integration/<epic> branch: target it
-  3. Otherwise: target main
+  1. If --target is specified: target that branch
+  2. If --epic is specified: target integration/<epic>
+  3. If source issue has a parent epic with integration/<epic> branch: target it
+  4. Otherwise: target main
 
 This ensures batch work on epics automatically flows to integration branches.
 
+Release branches:
+  --target must be the rig's default branch or one of the extra targets in
+  merge_queue.targets (e.g., release/1.2). Each target has its own
+  sub-queue in the Refinery. Targets with "backport": true get a
+  cherry-pick MR for every MR merged into the default branch.
+
+Path locks:
+  An MR touching one of the rig's hot paths (merge_queue.locks) takes its
+  lock; --lock declares one more. The Refinery merges MRs sharing a lock
+  one at a time.
+
 Polecat auto-cleanup:
   When run from a polecat work branch (polecat/<worker>/<issue>), this command
   automatically triggers polecat shutdown after submitting the MR. The polecat
@@ -96,79 +129,181 @@ Polecat auto-cleanup:
   Use --no-cleanup to disable this behavior (e.g., if you want to submit
   multiple MRs or continue working).
 
+Watching:
+  With --watch, the command blocks after submitting and prints each state
+  change (queued → processing → merged) until the MR lands or fails. The
+  exit code reflects the outcome: 0 merged, 7 merge conflict, 8 checks
+  failed, 1 otherwise. --watch skips polecat auto-cleanup.
+
+WIP limits:
+  If the rig sets wip.max_open_mrs_per_worker and the worker already has
+  that many open MRs, nothing is submitted and the command exits with
+  code 10. Land or close some first, or pass --ignore-wip.
+
+Claims:
+  If the source issue is claimed (see gt claim) by, or assigned in progress
+  to, a different worker, nothing is submitted and the command exits with
+  code 11. Pass --ignore-claim to submit anyway.
+
+Description:
+  If the rig sets merge_queue.description, a new MR's description is
+  written in its sections (e.g. Summary, Testing, Risk): the template is
+  opened in $EDITOR, with the summary filled in from the source issue.
+  Without a terminal, or with --no-edit, the template is used as rendered,
+  and fails if a required section is left empty. --body-file reads the
+  description from a file instead ("-" for stdin), with or without a
+  template. The description is stored on the MR bead after its fields.
+
+Resubmitting:
+  A branch has one unmerged MR per target. Submitting it again updates
+  that MR (worker, project, locks, labels, and --priority if given)
+  instead of queueing a duplicate, and resubmits it if changes were
+  requested. It is refused if the MR is for another source issue or the
+  Refinery is merging it. --force-new queues a new MR regardless.
+
+Labels:
+  The MR gets the source issue's labels, plus any given with --label
+  (repeatable). gt mq list and gt mq next --label filter by them.
+
+Owners:
+  If the rig sets merge_queue.owners, the owners of the paths the branch
+  changes are listed. The Refinery won't merge the MR until one owner of
+  each approves it (gt mq approve); gt mq status shows who is outstanding.
+
 Examples:
   gt mq submit                           # Auto-detect everything + auto-cleanup
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
+  gt mq submit --target release/1.2      # Queue against a release branch
   gt mq submit --priority 0              # Override priority (P0)
-  gt mq submit --no-cleanup              # Submit without auto-cleanup`,
+  gt mq submit --no-cleanup              # Submit without auto-cleanup
+  gt mq submit --watch --timeout 30m     # Block until merged or failed
+  gt mq submit --ignore-wip              # Submit past the rig's WIP limit
+  gt mq submit --ignore-claim            # Submit for an issue claimed by someone else
+  gt mq submit --body-file mr.md         # Description from a file
+  gt mq submit --label urgent            # Label the MR`,
 	RunE: runMqSubmit,
 }
 
 var mqRetryCmd = &cobra.Command{
-	Use:   "retry <rig> <mr-id>",
+	Use:   "retry [rig] [mr-id]",
 	Short: "Retry a failed merge request",
 	Long: `Retry a failed merge request.
 
 Resets a failed MR so it can be processed again by the refinery.
 The MR must be in a failed state (open with an error).
 
+At a terminal, leave out the MR (or the rig too) to pick from the failed
+MRs.
+
 Examples:
   gt mq retry greenplace gp-mr-abc123
-  gt mq retry greenplace gp-mr-abc123 --now`,
-	Args: cobra.ExactArgs(2),
+  gt mq retry greenplace gp-mr-abc123 --now
+  gt mq retry greenplace                 # Pick from the failed MRs`,
+	Args: pickableArgs(2),
 	RunE: runMQRetry,
 }
 
 var mqListCmd = &cobra.Command{
-	Use:   "list <rig>",
+	Use:   "list [rig]",
 	Short: "Show the merge queue",
 	Long: `Show the merge queue for a rig.
 
-Lists all pending merge requests waiting to be processed.
+Lists all unmerged merge requests: those waiting to be processed and those
+the refinery is working on.
 
 Output format:
   ID          STATUS       PRIORITY  BRANCH                    WORKER  AGE
   gt-mr-001   ready        P0        polecat/Nux/gp-xyz        Nux     5m
-  gt-mr-002   in_progress  P1        polecat/Toast/gt-abc      Toast   12m
+  gt-mr-002   checking     P1        polecat/Toast/gt-abc      Toast   12m
   gt-mr-003   blocked      P1        polecat/Capable/gt-def    Capable 8m
               (waiting on gt-mr-001)
 
+Queued MRs show whether they can merge (ready, blocked, review, changes,
+held, locked); the rest show their state (rebasing, checking, merging, or with
+--status=closed: merged, failed, rejected, cancelled, stale).
+
+If the rig sets merge SLA targets (merge_queue.sla), the age of MRs past
+their target is shown in red and marked with "!".
+
+If the rig has extra merge targets (merge_queue.targets, e.g. release
+branches), each target's sub-queue is listed separately, in its own merge
+order. --target shows just one. Monorepo projects with their own queue
+(merge_queue.projects) get a sub-queue of their own within each target,
+listed after its main one, e.g. "main (docs)".
+
+If the rig has path locks (merge_queue.locks), an MR wanting a lock that a
+claimed MR, or one ahead of it, holds is "locked", with the lock and the
+MR holding it shown below the table.
+
+MRs' labels (other than Gas Town's own) are shown; --label shows only MRs
+with the label (repeat it to require several).
+
+MRs are listed in the order the refinery will work through them, so the
+first ready one is what merges next: MRs being processed, then ready ones,
+then those held back, awaiting review, or blocked. --sort orders each
+sub-queue by priority, age (oldest first), worker, risk (highest first) or
+score instead; --reverse reverses the order.
+
+--columns picks the table's columns (id, score, risk, pri, convoy, branch,
+worker, target, status, age, labels).
+
+Big queues are shown a page at a time: --limit rows (default 100, 0 for
+all), starting --offset rows in. JSON output shows every MR unless --limit
+is given.
+
+--view applies a saved view: filters, columns and order the rig names under
+merge_queue.views in settings/config.json. Flags given with it override the
+view's settings. The built-in "mine" view shows your own MRs.
+
+At a terminal, leave out the rig to pick it.
+
 Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
   gt mq list greenplace --status=open
-  gt mq list greenplace --worker=Nux`,
-	Args: cobra.ExactArgs(1),
+  gt mq list greenplace --worker=Nux
+  gt mq list greenplace --target release/1.2
+  gt mq list greenplace --label infra
+  gt mq list greenplace --view mine
+  gt mq list greenplace --view stuck --columns id,branch,age,risk
+  gt mq list greenplace --older-than 2d --sort age
+  gt mq list greenplace --sort risk --reverse
+  gt mq list greenplace --limit 50 --offset 50   # The second page of 50`,
+	Args: pickableArgs(1),
 	RunE: runMQList,
 }
 
 var mqRejectCmd = &cobra.Command{
-	Use:   "reject <rig> <mr-id-or-branch>",
+	Use:   "reject [rig] [mr-id-or-branch]",
 	Short: "Reject a merge request",
 	Long: `Manually reject a merge request.
 
 This closes the MR with a 'rejected' status without merging.
 The source issue is NOT closed (work is not done).
 
+At a terminal, leave out the MR (or the rig too) to pick it.
+
 Examples:
   gt mq reject greenplace polecat/Nux/gp-xyz --reason "Does not meet requirements"
   gt mq reject greenplace mr-Nux-12345 --reason "Superseded by other work" --notify`,
-	Args: cobra.ExactArgs(2),
+	Args: pickableArgs(2),
 	RunE: runMQReject,
 }
 
 var mqStatusCmd = &cobra.Command{
-	Use:   "status <id>",
+	Use:   "status [id]",
 	Short: "Show detailed merge request status",
 	Long: `Display detailed information about a merge request.
 
 Shows all MR fields, current status with timestamps, dependencies,
 blockers, and processing history.
 
+At a terminal, leave out the ID to pick a rig and one of its MRs.
+
 Example:
   gt mq status gp-mr-abc123`,
-	Args: cobra.ExactArgs(1),
+	Args: pickableArgs(1),
 	RunE: runMqStatus,
 }
 
@@ -274,8 +409,18 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitBranch, "branch", "", "Source branch (default: current branch)")
 	mqSubmitCmd.Flags().StringVar(&mqSubmitIssue, "issue", "", "Source issue ID (default: parse from branch name)")
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitTarget, "target", "", "Target branch: the default branch or one of the rig's merge_queue.targets")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitWatch, "watch", false, "Block and stream MR state changes until merged or failed")
+	mqSubmitCmd.Flags().DurationVar(&mqSubmitTimeout, "timeout", 0, "Give up watching after this long (with --watch; 0 = no limit)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitIgnoreWIP, "ignore-wip", false, "Submit even if the worker is at the rig's open MR limit")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitIgnoreClaim, "ignore-claim", false, "Submit even if another worker has claimed the source issue")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitBodyFile, "body-file", "", "Read the MR description from this file (- for stdin)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoEdit, "no-edit", false, "Don't open the description template in $EDITOR")
+	mqSubmitCmd.Flags().StringSliceVarP(&mqSubmitLabels, "label", "l", nil, "Label the MR (repeatable; added to the source issue's labels)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitForceNew, "force-new", false, "Queue a new MR even if one is open for the branch and target")
+	mqSubmitCmd.Flags().StringSliceVar(&mqSubmitLocks, "lock", nil, "Merge under this path lock (repeatable; added to those detected from merge_queue.locks)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -285,6 +430,14 @@ func init() {
 	mqListCmd.Flags().StringVar(&mqListStatus, "status", "", "Filter by status (open, in_progress, closed)")
 	mqListCmd.Flags().StringVar(&mqListWorker, "worker", "", "Filter by worker name")
 	mqListCmd.Flags().StringVar(&mqListEpic, "epic", "", "Show MRs targeting integration/<epic>")
+	mqListCmd.Flags().StringVar(&mqListTarget, "target", "", "Show only the sub-queue for this target branch")
+	mqListCmd.Flags().StringSliceVarP(&mqListLabels, "label", "l", nil, "Show only MRs with this label (repeatable: all must match)")
+	mqListCmd.Flags().StringVar(&mqListOlderThan, "older-than", "", "Show only MRs submitted longer ago than this (e.g. 4h, 2d)")
+	mqListCmd.Flags().StringVar(&mqListView, "view", "", "Apply a saved view (merge_queue.views, or the built-in mine)")
+	mqListCmd.Flags().StringVar(&mqListColumnsFlag, "columns", "", "Comma-separated columns to show (e.g. id,branch,age,risk)")
+	mqListCmd.Flags().StringVar(&mqListSort, "sort", "", "Order each sub-queue by priority, age, worker, risk or score (default: refinery order)")
+	mqListCmd.Flags().BoolVar(&mqListReverse, "reverse", false, "Reverse the order")
+	addPageFlags(mqListCmd, &mqListPage)
 	mqListCmd.Flags().BoolVar(&mqListJSON, "json", false, "Output as JSON")
 
 	// Reject flags
@@ -329,15 +482,9 @@ func findCurrentRig(townRoot string) (string, *rig.Rig, error) {
 		return "", nil, fmt.Errorf("getting current directory: %w", err)
 	}
 
-	// Get relative path from town root to cwd
-	relPath, err := filepath.Rel(townRoot, cwd)
-	if err != nil {
-		return "", nil, fmt.Errorf("computing relative path: %w", err)
-	}
-
-	// The first component of the relative path should be the rig name
-	parts := strings.Split(relPath, string(filepath.Separator))
-	if len(parts) == 0 || parts[0] == "" || parts[0] == "." {
+	// The first component of the path relative to the town root is the rig name
+	parts, err := workspace.RelParts(townRoot, cwd)
+	if err != nil || len(parts) == 0 {
 		return "", nil, fmt.Errorf("not inside a rig directory")
 	}
 
@@ -361,6 +508,10 @@ func findCurrentRig(townRoot string) (string, *rig.Rig, error) {
 }
 
 func runMQRetry(cmd *cobra.Command, args []string) error {
+	args, err := pickArgs(cmd, args, pickRig, pickMR("failed merge requests", isFailedMR))
+	if err != nil {
+		return err
+	}
 	rigName := args[0]
 	mrID := args[1]
 
@@ -373,7 +524,7 @@ func runMQRetry(cmd *cobra.Command, args []string) error {
 	mr, err := mgr.GetMR(mrID)
 	if err != nil {
 		if err == refinery.ErrMRNotFound {
-			return fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName)
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName))
 		}
 		return fmt.Errorf("getting merge request: %w", err)
 	}
@@ -405,11 +556,27 @@ func runMQRetry(cmd *cobra.Command, args []string) error {
 }
 
 func runMQReject(cmd *cobra.Command, args []string) error {
+	args, err := pickArgs(cmd, args, pickRig, pickMR("merge requests", nil))
+	if err != nil {
+		return err
+	}
 	rigName := args[0]
 	mrIDOrBranch := args[1]
 
-	mgr, _, _, err := getRefineryManager(rigName)
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	// Polecats may only withdraw their own MRs
+	mr, err := mgr.FindMR(mrIDOrBranch)
 	if err != nil {
+		return fmt.Errorf("rejecting MR: %w", err)
+	}
+	ownMR := func(info RoleInfo) bool {
+		return info.Role == RolePolecat && info.Rig == r.Name && info.Polecat == mr.Worker
+	}
+	if err := requirePermission(filepath.Dir(r.Path), permission.ActionMQReject, mr.ID, ownMR); err != nil {
 		return err
 	}
 
@@ -432,3 +599,25 @@ func runMQReject(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// MergeQueueFreezeKey is the wisp config key holding why a rig's merge
+// queue is frozen (e.g., "release v1.2.0"). Unset means not frozen.
+const MergeQueueFreezeKey = "mq_freeze"
+
+// mergeQueuePausedReason returns why the rig's merge queue is not being
+// processed, or "" if the queue is live. A parked or docked rig has its
+// refinery stopped, so nothing in the queue will merge. A frozen queue
+// keeps the refinery running but holds merges.
+func mergeQueuePausedReason(r *rig.Rig) string {
+	townRoot := filepath.Dir(r.Path)
+	if IsRigParked(townRoot, r.Name) {
+		return "rig parked"
+	}
+	if r.Config != nil && r.Config.Prefix != "" && IsRigDocked(townRoot, r.Name, r.Config.Prefix) {
+		return "rig docked"
+	}
+	if reason := wisp.NewConfig(townRoot, r.Name).GetString(MergeQueueFreezeKey); reason != "" {
+		return "frozen for " + reason
+	}
+	return ""
+}
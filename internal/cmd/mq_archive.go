@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Archive command flags
+var (
+	mqArchiveOlderThan string
+	mqArchiveDryRun    bool
+)
+
+var mqArchiveCmd = &cobra.Command{
+	Use:   "archive <rig>",
+	Short: "Move old closed MRs out of the beads database",
+	Long: `Move closed merge requests out of the rig's beads database into its MR
+archive, so queue queries stay fast as merged work piles up.
+
+MRs closed longer ago than --older-than (a Go duration or whole days, e.g.
+30d) are archived; without the flag, the rig's merge_queue.archive_after
+policy applies. With that policy set, the daemon archives old MRs on its
+own as well.
+
+Archived MRs are kept in mr-archive.jsonl in the rig's .beads directory,
+and gt search --archived still finds them.
+
+Examples:
+  gt mq archive greenplace --older-than 30d
+  gt mq archive greenplace --older-than 30d --dry-run
+  gt mq archive greenplace                 # Use merge_queue.archive_after`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQArchive,
+}
+
+func init() {
+	mqArchiveCmd.Flags().StringVar(&mqArchiveOlderThan, "older-than", "", "Archive MRs closed longer ago than this (e.g., 30d; default: merge_queue.archive_after)")
+	mqArchiveCmd.Flags().BoolVar(&mqArchiveDryRun, "dry-run", false, "List the MRs that would be archived without moving them")
+
+	mqCmd.AddCommand(mqArchiveCmd)
+}
+
+// MQArchiveOutput is the structured output for gt mq archive.
+type MQArchiveOutput struct {
+	Rig       string   `json:"rig"`
+	OlderThan string   `json:"older_than"`
+	DryRun    bool     `json:"dry_run,omitempty"`
+	Archived  []string `json:"archived"` // MR IDs (to be) archived
+	Archive   string   `json:"archive"`  // Path of the archive file
+}
+
+func runMQArchive(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	var olderThan time.Duration
+	if mqArchiveOlderThan != "" {
+		if olderThan, err = refinery.ParseArchiveAge(mqArchiveOlderThan); err != nil {
+			return err
+		}
+	} else {
+		if olderThan, err = refinery.LoadArchiveAfter(r.Path); err != nil {
+			return fmt.Errorf("loading archive policy: %w", err)
+		}
+		if olderThan == 0 {
+			return fmt.Errorf("rig '%s' has no merge_queue.archive_after policy; pass --older-than (e.g., 30d)", rigName)
+		}
+	}
+
+	bd := beads.New(r.BeadsPath())
+	closed, err := bd.ListIndexed(beads.ListOptions{Type: "merge-request", Status: "closed", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("querying closed merge requests: %w", err)
+	}
+	old := refinery.ArchivableMRs(closed, time.Now().Add(-olderThan))
+
+	out := MQArchiveOutput{
+		Rig:       rigName,
+		OlderThan: formatArchiveAge(olderThan),
+		DryRun:    mqArchiveDryRun,
+		Archived:  []string{},
+		Archive:   bd.MRArchivePath(),
+	}
+	if mqArchiveDryRun {
+		for _, issue := range old {
+			out.Archived = append(out.Archived, issue.ID)
+		}
+	} else {
+		archived, archiveErr := bd.ArchiveMRs(old)
+		out.Archived = append(out.Archived, archived...)
+		if archiveErr != nil {
+			if len(archived) > 0 {
+				style.PrintWarning("archived %d MR(s) before failing", len(archived))
+			}
+			return archiveErr
+		}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+
+	switch {
+	case len(out.Archived) == 0:
+		fmt.Printf("%s No closed MRs in '%s' older than %s\n", style.Dim.Render("ℹ"), rigName, out.OlderThan)
+	case mqArchiveDryRun:
+		fmt.Printf("%s Would archive %d closed MR(s) older than %s:\n", style.Bold.Render("🗄"), len(out.Archived), out.OlderThan)
+		for _, id := range out.Archived {
+			fmt.Printf("  %s\n", id)
+		}
+	default:
+		fmt.Printf("%s Archived %d closed MR(s) older than %s\n", style.Bold.Render("✓"), len(out.Archived), out.OlderThan)
+		fmt.Printf("  %s\n", style.Dim.Render("Find them with: gt search --archived <query>"))
+	}
+	return nil
+}
+
+// formatArchiveAge renders an archive age as whole days ("30d") when it is
+// one, and as a Go duration otherwise.
+func formatArchiveAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatArchiveAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * 24 * time.Hour: "30d",
+		36 * time.Hour:      "36h0m0s",
+	}
+	for d, want := range tests {
+		if got := formatArchiveAge(d); got != want {
+			t.Errorf("formatArchiveAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Artifacts command flags
+var (
+	mqArtifactsOpen string
+	mqArtifactsPath bool
+)
+
+var mqArtifactsCmd = &cobra.Command{
+	Use:   "artifacts <mr-id>",
+	Short: "List or open the check logs and artifacts kept for a merge request",
+	Long: `List the check logs and artifacts the refinery kept from its latest runs
+of each check on a merge request.
+
+Each check's output is kept as <check>.log, and the files its run left in
+the checkout matching the rig's merge_queue.artifacts.paths (junit XML,
+coverage reports, build outputs) under <check>/. They live in the rig's
+.runtime/artifacts/<mr-id>/, and are pruned by the daemon once the MR
+hasn't been checked for merge_queue.artifacts.retention (default 14d).
+
+Use --open to open one with the system's default application, or --path to
+print the artifact directory (or, with --open, the artifact's path).
+
+Examples:
+  gt mq artifacts gp-mr-abc123
+  gt mq artifacts gp-mr-abc123 --open tests.log
+  gt mq artifacts gp-mr-abc123 --open tests/coverage.html --path
+  less $(gt mq artifacts gp-mr-abc123 --path)/tests.log`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQArtifacts,
+}
+
+func init() {
+	mqArtifactsCmd.Flags().StringVar(&mqArtifactsOpen, "open", "", "Open the named artifact (as listed)")
+	mqArtifactsCmd.Flags().BoolVar(&mqArtifactsPath, "path", false, "Print the path instead of listing or opening")
+
+	mqCmd.AddCommand(mqArtifactsCmd)
+}
+
+// MQArtifactsOutput is the structured output for gt mq artifacts.
+type MQArtifactsOutput struct {
+	ID        string              `json:"id"`
+	Dir       string              `json:"dir,omitempty"` // "" = none kept
+	Artifacts []refinery.Artifact `json:"artifacts"`
+}
+
+func runMQArtifacts(cmd *cobra.Command, args []string) error {
+	_, issue, fields, err := loadMRBead(args[0])
+	if err != nil {
+		return err
+	}
+	dir := fields.Artifacts
+	var artifacts []refinery.Artifact
+	if dir != "" {
+		if artifacts, err = refinery.ListArtifacts(dir); err != nil {
+			return err
+		}
+	}
+
+	if mqArtifactsOpen != "" {
+		var found *refinery.Artifact
+		for i := range artifacts {
+			if artifacts[i].Name == filepath.ToSlash(mqArtifactsOpen) {
+				found = &artifacts[i]
+				break
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("%s has no artifact %q (see gt mq artifacts %s)", issue.ID, mqArtifactsOpen, issue.ID)
+		}
+		if mqArtifactsPath {
+			fmt.Println(found.Path)
+			return nil
+		}
+		openBrowser(found.Path)
+		fmt.Printf("%s Opened %s\n", style.Bold.Render("✓"), found.Path)
+		return nil
+	}
+
+	if mqArtifactsPath {
+		if dir == "" {
+			return fmt.Errorf("no artifacts kept for %s", issue.ID)
+		}
+		fmt.Println(dir)
+		return nil
+	}
+
+	if structuredOutput(false) {
+		out := MQArtifactsOutput{ID: issue.ID, Artifacts: artifacts}
+		if len(artifacts) > 0 {
+			out.Dir = dir
+		}
+		if out.Artifacts == nil {
+			out.Artifacts = []refinery.Artifact{}
+		}
+		return renderStructured(out)
+	}
+
+	if len(artifacts) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("No artifacts kept for %s (not checked yet, or pruned)", issue.ID)))
+		return nil
+	}
+	fmt.Printf("%s Artifacts for %s\n", style.Bold.Render("📦"), issue.ID)
+	fmt.Printf("   %s\n\n", style.Dim.Render(dir))
+	for _, a := range artifacts {
+		fmt.Printf("   %-40s %10s  %s\n", a.Name, formatBytes(a.Size), style.Dim.Render(formatAge(a.Modified)))
+	}
+	return nil
+}
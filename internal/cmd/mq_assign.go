@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Assign command flags
+var (
+	mqAssignTo   string
+	mqAssignNote string
+)
+
+var mqAssignCmd = &cobra.Command{
+	Use:   "assign [rig] [mr-id]",
+	Short: "Hand a failed MR back to a worker with context",
+	Long: `Hand a failed merge request back to a worker to fix.
+
+For an MR that failed with conflicts or failing tests, gt mq assign:
+  1. Reopens the source issue and assigns it to the MR's worker (or --to)
+  2. Adds a note to the issue with what failed
+  3. Mails the worker the branch, the failure, and the next step: rebase
+     for conflicts, fix the failing tests for test failures
+
+If the MR is still in the queue, the Refinery retries it once the worker
+pushes; if it was closed, the worker resubmits with gt mq submit.
+
+At a terminal, the rig and MR can be left off to pick them from a list of
+the rig's failed MRs.
+
+Examples:
+  gt mq assign greenplace gp-mr-abc123
+  gt mq assign greenplace gp-mr-abc123 --to Toast
+  gt mq assign greenplace gp-mr-abc123 --note "The flake in auth_test is real, see gp-456"`,
+	Args: pickableArgs(2),
+	RunE: runMQAssign,
+}
+
+func init() {
+	mqAssignCmd.Flags().StringVar(&mqAssignTo, "to", "", "Polecat to assign the work to (default: the MR's worker)")
+	mqAssignCmd.Flags().StringVarP(&mqAssignNote, "note", "m", "", "Extra context for the worker (added to the issue and the mail)")
+
+	mqCmd.AddCommand(mqAssignCmd)
+}
+
+// MRAssignOutput is the structured output for gt mq assign.
+type MRAssignOutput struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	Target      string `json:"target"`
+	SourceIssue string `json:"source_issue"`
+	Worker      string `json:"worker"`
+	Assignee    string `json:"assignee"`
+	Failure     string `json:"failure"`
+	Detail      string `json:"detail"`
+	NextStep    string `json:"next_step"`
+}
+
+// mrFailure is why a merge request failed and what its worker should do
+// about it.
+type mrFailure struct {
+	Kind   string // "conflict", "tests" or "failed"
+	Detail string
+	Next   string
+}
+
+// failureOf reports why an MR failed: its latest test run failed, it
+// conflicted with its target, or it was closed as failed. ok is false for
+// an MR that hasn't failed. fields.Target must be set.
+func failureOf(issue *beads.Issue, fields *beads.MRFields) (mrFailure, bool) {
+	if fields == nil {
+		return mrFailure{}, false
+	}
+	state := refinery.StateOf(issue)
+
+	var f mrFailure
+	switch tests := refinery.TestResultsFromFields(fields); {
+	case tests != nil && !tests.OK:
+		f.Kind = "tests"
+		f.Detail = "Tests " + tests.String()
+		if len(tests.Failing) > 0 {
+			f.Detail += "\nFailing: " + strings.Join(tests.Failing, ", ")
+		}
+		f.Next = fmt.Sprintf("Fix the failing tests on %s, run them locally, and push.", fields.Branch)
+	case fields.LastConflictSHA != "" || refinery.CloseReason(fields.CloseReason) == refinery.CloseReasonConflict:
+		f.Kind = "conflict"
+		f.Detail = "Conflicts with " + fields.Target
+		if fields.LastConflictSHA != "" {
+			f.Detail += " at " + shortSHA(fields.LastConflictSHA)
+		}
+		f.Next = fmt.Sprintf(`Rebase onto %s, resolve the conflicts, and push:
+  git fetch origin
+  git rebase origin/%s
+  git push -f`, fields.Target, fields.Target)
+	case state == refinery.StateFailed:
+		f.Kind = "failed"
+		f.Detail = "Closed as failed"
+		f.Next = fmt.Sprintf("Find out why %s can't merge, fix it, and push.", fields.Branch)
+	default:
+		return mrFailure{}, false
+	}
+
+	if state.Terminal() {
+		f.Next += fmt.Sprintf("\nThen resubmit: gt mq submit --branch %s --issue %s", fields.Branch, fields.SourceIssue)
+	} else {
+		f.Next += "\nThe Refinery retries the merge once you push."
+	}
+	return f, true
+}
+
+func runMQAssign(cmd *cobra.Command, args []string) error {
+	args, err := pickArgs(cmd, args, pickRig, pickFailedMR)
+	if err != nil {
+		return err
+	}
+	rigName := args[0]
+	mrID := args[1]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName))
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", mrID)
+	}
+	if fields.Target == "" {
+		fields.Target = r.DefaultBranch()
+	}
+	failure, ok := failureOf(issue, fields)
+	if !ok {
+		return fmt.Errorf("merge request %s has not failed (state: %s)", mrID, refinery.StateOf(issue))
+	}
+	if fields.SourceIssue == "" {
+		return fmt.Errorf("merge request %s has no source issue to assign", mrID)
+	}
+
+	worker := fields.Worker
+	if mqAssignTo != "" {
+		worker = mqAssignTo
+	}
+	if worker == "" {
+		return fmt.Errorf("merge request %s has no worker; name one with --to", mrID)
+	}
+	if err := requirePermission(filepath.Dir(r.Path), permission.ActionMQAssign, fields.SourceIssue, nil); err != nil {
+		return err
+	}
+
+	assignee := fmt.Sprintf("%s/polecats/%s", rigName, worker)
+	note := fmt.Sprintf("Reassigned: %s failed. %s", mrID, strings.ReplaceAll(failure.Detail, "\n", ". "))
+	if mqAssignNote != "" {
+		note += " (" + mqAssignNote + ")"
+	}
+	if err := bd.ReassignWithReason(fields.SourceIssue, assignee, note); err != nil {
+		return fmt.Errorf("assigning %s to %s: %w", fields.SourceIssue, assignee, err)
+	}
+
+	payload := events.MRPayload(rigName, mrID, fields.SourceIssue, fields.Branch, failure.Kind)
+	payload["assignee"] = assignee
+	_ = events.LogFeed(events.TypeMRAssigned, detectSender(), payload)
+
+	to := *fields
+	to.Rig, to.Worker = rigName, worker
+	notifyMRWorker(&to, detectSender(), fmt.Sprintf("Merge failed: %s is back with you", fields.SourceIssue),
+		assignMailBody(mrID, fields, failure, mqAssignNote))
+
+	if structuredOutput(false) {
+		return renderStructured(MRAssignOutput{
+			ID:          mrID,
+			Branch:      fields.Branch,
+			Target:      fields.Target,
+			SourceIssue: fields.SourceIssue,
+			Worker:      fields.Worker,
+			Assignee:    assignee,
+			Failure:     failure.Kind,
+			Detail:      failure.Detail,
+			NextStep:    failure.Next,
+		})
+	}
+
+	fmt.Printf("%s Assigned %s back to %s\n", style.Bold.Render("✓"), fields.SourceIssue, assignee)
+	fmt.Printf("  MR:     %s\n", mrID)
+	fmt.Printf("  Branch: %s\n", fields.Branch)
+	fmt.Printf("  Failed: %s\n", style.Dim.Render(strings.ReplaceAll(failure.Detail, "\n", "; ")))
+	fmt.Printf("  %s\n", style.Dim.Render("Worker notified via mail"))
+	return nil
+}
+
+// assignMailBody is the mail handing a failed MR back to its worker.
+func assignMailBody(mrID string, fields *beads.MRFields, failure mrFailure, note string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Merge request %s failed, and %s is assigned to you to fix.\n\n", mrID, fields.SourceIssue)
+	fmt.Fprintf(&b, "Branch: %s\n", fields.Branch)
+	fmt.Fprintf(&b, "Issue: %s\n", fields.SourceIssue)
+	fmt.Fprintf(&b, "Error: %s\n", failure.Detail)
+	if note != "" {
+		fmt.Fprintf(&b, "Note: %s\n", note)
+	}
+	fmt.Fprintf(&b, "\nNext step:\n%s", failure.Next)
+	return b.String()
+}
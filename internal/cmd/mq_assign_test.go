@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestFailureOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		fields   beads.MRFields
+		wantKind string
+		wantIn   []string // substrings of Detail and Next
+	}{
+		{
+			name:     "failing tests, still queued",
+			status:   "open",
+			fields:   beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", State: "queued", TestResults: "failed suite=go passed=41 failed=2 duration=12.5s", FailingTests: "auth.TestLogin,auth.TestLogout"},
+			wantKind: "tests",
+			wantIn:   []string{"41 passed, 2 failed", "auth.TestLogin, auth.TestLogout", "Fix the failing tests on polecat/Nux/gp-1", "retries the merge"},
+		},
+		{
+			name:     "conflict, still queued",
+			status:   "open",
+			fields:   beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", State: "queued", LastConflictSHA: "1a2b3c4d5e6f"},
+			wantKind: "conflict",
+			wantIn:   []string{"Conflicts with main at 1a2b3c4", "git rebase origin/main", "retries the merge"},
+		},
+		{
+			name:     "legacy conflict close",
+			status:   "closed",
+			fields:   beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", SourceIssue: "gp-1", CloseReason: "conflict"},
+			wantKind: "conflict",
+			wantIn:   []string{"Conflicts with main", "gt mq submit --branch polecat/Nux/gp-1 --issue gp-1"},
+		},
+		{
+			name:     "closed as failed",
+			status:   "closed",
+			fields:   beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", State: "failed"},
+			wantKind: "failed",
+			wantIn:   []string{"Closed as failed", "gt mq submit"},
+		},
+		{
+			name:   "passing tests",
+			status: "open",
+			fields: beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", State: "queued", TestResults: "ok suite=go passed=41 failed=0 duration=12.5s"},
+		},
+		{
+			name:   "merged",
+			status: "closed",
+			fields: beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", State: "merged", MergeCommit: "abc1234"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &beads.Issue{ID: "gp-mr-1", Status: tt.status}
+			issue.Description = beads.SetMRFields(issue, &tt.fields)
+
+			f, ok := failureOf(issue, beads.ParseMRFields(issue))
+			if ok != (tt.wantKind != "") {
+				t.Fatalf("failureOf ok = %v, want %v", ok, tt.wantKind != "")
+			}
+			if f.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", f.Kind, tt.wantKind)
+			}
+			for _, want := range tt.wantIn {
+				if !strings.Contains(f.Detail+"\n"+f.Next, want) {
+					t.Errorf("failure %+v does not mention %q", f, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAssignMailBody(t *testing.T) {
+	fields := &beads.MRFields{Branch: "polecat/Nux/gp-1", SourceIssue: "gp-1"}
+	failure := mrFailure{Kind: "tests", Detail: "Tests failed (3s)", Next: "Fix the failing tests on polecat/Nux/gp-1, run them locally, and push."}
+
+	body := assignMailBody("gp-mr-1", fields, failure, "see gp-456")
+	for _, want := range []string{"gp-mr-1", "Branch: polecat/Nux/gp-1", "Issue: gp-1", "Error: Tests failed (3s)", "Note: see gp-456", "Next step:\nFix the failing tests"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("mail body missing %q:\n%s", want, body)
+		}
+	}
+	if body := assignMailBody("gp-mr-1", fields, failure, ""); strings.Contains(body, "Note:") {
+		t.Errorf("mail body without a note has a Note line:\n%s", body)
+	}
+}
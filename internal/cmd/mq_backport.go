@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Backport command flags
+var (
+	mqBackportRig string
+	mqBackportTo  string
+)
+
+var mqBackportCmd = &cobra.Command{
+	Use:   "backport <merge-commit|mr-id> --to <branch>",
+	Short: "Cherry-pick a merged MR onto a release branch through the queue",
+	Long: `Backport a merged merge request onto another merge target.
+
+The MR can be named by its ID or by its merge commit (a SHA prefix of at
+least 7 characters). gt mq backport then:
+  1. Creates branch backport/<branch>/<mr-id> from origin's --to branch and
+     cherry-picks the merge commit onto it (in the refinery's clone)
+  2. Submits the branch to the merge queue against --to, at the original's
+     priority, as an MR for the same source issue with "backport_of: <mr-id>"
+  3. Adds the backport MR to "backports" on the original MR
+
+--to must be one of the rig's merge targets (merge_queue.targets). The
+backport goes through the target's sub-queue like any other MR. If the
+cherry-pick doesn't apply cleanly, nothing is created; backport by hand
+instead.
+
+Targets with "backport": true get this automatically for every MR merged
+into the default branch (see gt mq land).
+
+Examples:
+  gt mq backport gp-mr-abc123 --to release/1.2
+  gt mq backport 1a2b3c4d --to release/1.1 --rig greenplace -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQBackport,
+}
+
+func init() {
+	mqBackportCmd.Flags().StringVar(&mqBackportRig, "rig", "", "Rig the MR belongs to (default: infer from cwd)")
+	mqBackportCmd.Flags().StringVar(&mqBackportTo, "to", "", "Merge target to backport onto (e.g., release/1.2)")
+	_ = mqBackportCmd.MarkFlagRequired("to")
+
+	mqCmd.AddCommand(mqBackportCmd)
+}
+
+// MRBackportOutput is the structured output for gt mq backport.
+type MRBackportOutput struct {
+	ID          string `json:"id"`
+	BackportOf  string `json:"backport_of"`
+	MergeCommit string `json:"merge_commit"`
+	Branch      string `json:"branch"`
+	Target      string `json:"target"`
+	SourceIssue string `json:"source_issue,omitempty"`
+}
+
+func runMQBackport(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(mqBackportRig)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+
+	original, err := findMergedMR(bd, args[0])
+	if err != nil {
+		return err
+	}
+	fields := beads.ParseMRFields(original)
+
+	eng := refinery.NewEngineer(r)
+	if structuredOutput(false) {
+		eng.SetOutput(io.Discard)
+	}
+	bp, err := eng.SubmitBackport(original.ID, mqBackportTo)
+	if err != nil {
+		if errors.Is(err, refinery.ErrBranchExists) {
+			return fmt.Errorf("%s already exists on origin; a backport of %s onto %s may already be queued",
+				refinery.BackportBranch(mqBackportTo, original.ID), original.ID, mqBackportTo)
+		}
+		if bp.MRID == "" {
+			return fmt.Errorf("backporting %s onto %s: %w", original.ID, mqBackportTo, err)
+		}
+		// The backport MR exists; only linking it back failed
+		style.PrintWarning("%v", err)
+	}
+
+	out := MRBackportOutput{
+		ID:          bp.MRID,
+		BackportOf:  original.ID,
+		MergeCommit: fields.MergeCommit,
+		Branch:      bp.Branch,
+		Target:      bp.Target,
+		SourceIssue: fields.SourceIssue,
+	}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+
+	fmt.Printf("%s Submitted backport of %s to merge queue\n", style.Bold.Render("✓"), original.ID)
+	fmt.Printf("  MR ID: %s\n", style.Bold.Render(out.ID))
+	fmt.Printf("  Backports: %s (%s)\n", original.ID, shortSHA(out.MergeCommit))
+	fmt.Printf("  Source: %s\n", out.Branch)
+	fmt.Printf("  Target: %s\n", out.Target)
+	if out.SourceIssue != "" {
+		fmt.Printf("  Issue: %s\n", out.SourceIssue)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Comment command flags
+var (
+	mqCommentMessage string
+	mqCommentReplyTo int
+	mqCommentFile    string
+	mqCommentLine    int
+)
+
+var mqCommentCmd = &cobra.Command{
+	Use:   "comment <mr-id>",
+	Short: "Comment on a merge request",
+	Long: `Add a comment to a merge request's discussion thread.
+
+Comments are stored on the MR bead with your address, role (mayor, human,
+crew, witness, polecat, ...) and the time, so review conversations stay
+with the MR. 'gt mq status' shows the thread oldest first, and includes
+it in JSON. Use --reply-to to answer an earlier comment by its number.
+
+With --file (and --line), the comment is anchored to a file, or a line of
+the MR's version of it, for precise revision instructions. 'gt mq diff'
+shows anchored comments under the lines they're on. Replies to an
+anchored comment share its anchor.
+
+The MR's worker is notified of comments by anyone else. Merged and
+closed MRs can still be commented on.
+
+Examples:
+  gt mq comment gp-mr-abc123 -m "Why retry here rather than in the client?"
+  gt mq comment gp-mr-abc123 --reply-to 1 -m "The client has no backoff"
+  gt mq comment gp-mr-abc123 --file internal/retry.go --line 42 -m "Cap this at 5 attempts"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQComment,
+}
+
+func init() {
+	mqCommentCmd.Flags().StringVarP(&mqCommentMessage, "message", "m", "", "The comment (required)")
+	mqCommentCmd.Flags().IntVar(&mqCommentReplyTo, "reply-to", 0, "Number of the comment this replies to")
+	mqCommentCmd.Flags().StringVar(&mqCommentFile, "file", "", "Anchor the comment to this file (path from the repository root)")
+	mqCommentCmd.Flags().IntVar(&mqCommentLine, "line", 0, "Anchor the comment to this line of --file, in the MR's version")
+	mqCommentCmd.MarkFlagsRequiredTogether("line", "file")
+	_ = mqCommentCmd.MarkFlagRequired("message")
+
+	mqCmd.AddCommand(mqCommentCmd)
+}
+
+func runMQComment(cmd *cobra.Command, args []string) error {
+	author := strings.TrimSuffix(detectSender(), "/")
+
+	bd, issue, fields, err := loadMRBead(args[0])
+	if err != nil {
+		return err
+	}
+
+	thread, comment, err := refinery.AddMRComment(fields.Comments, refinery.MRComment{
+		Author:  author,
+		Body:    mqCommentMessage,
+		ReplyTo: mqCommentReplyTo,
+		File:    mqCommentFile,
+		Line:    mqCommentLine,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	fields.Comments = thread
+	desc := beads.SetMRFields(issue, fields)
+	if err := bd.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording comment: %w", err)
+	}
+
+	_ = events.LogFeed(events.TypeMRCommented, author,
+		events.MRPayload(fields.Rig, issue.ID, fields.SourceIssue, fields.Branch, comment.Body))
+
+	where := ""
+	if loc := comment.Location(); loc != "" {
+		where = " at " + loc
+	}
+	fmt.Printf("%s Commented on %s%s as %s (#%d)\n", style.Bold.Render("✓"), issue.ID, where, author, comment.ID)
+	if !isOwnMR(author, fields) {
+		subject := "Comment on merge request"
+		if comment.ReplyTo != 0 {
+			subject = "Reply on merge request"
+		}
+		notifyMRWorker(fields, author, subject,
+			fmt.Sprintf("%s commented on %s (%s)%s:\n\n%s\n\nReply with: gt mq comment %s --reply-to %d -m \"...\"",
+				author, issue.ID, fields.Branch, where, comment.Body, issue.ID, comment.ID))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqConflictsCmd = &cobra.Command{
+	Use:   "conflicts <rig>",
+	Short: "Show which queued MRs are likely to conflict",
+	Long: `Pairwise-compare the files each queued MR modifies and show which MRs
+overlap and are likely to conflict.
+
+Only MRs that target the same branch are compared. Each MR's changes are
+taken from the rig's refinery clone (branch...target), so the comparison is
+against the merge base rather than the current tip.
+
+The matrix shows the number of shared files for each pair; the list below
+it names the files. Use it to sequence or consolidate overlapping work
+before the Refinery hits merge failures.
+
+Examples:
+  gt mq conflicts greenplace
+  gt mq conflicts greenplace -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQConflicts,
+}
+
+func init() {
+	mqCmd.AddCommand(mqConflictsCmd)
+}
+
+// MQConflictsOutput is the structured output for gt mq conflicts.
+type MQConflictsOutput struct {
+	Rig     string                  `json:"rig"`
+	MRs     []MQConflictsMR         `json:"mrs"`
+	Pairs   []refinery.ConflictPair `json:"pairs"`
+	Skipped map[string]string       `json:"skipped,omitempty"` // MR ID -> why its diff is unavailable
+}
+
+// MQConflictsMR is one queued MR in gt mq conflicts output.
+type MQConflictsMR struct {
+	ID     string `json:"id"`
+	Branch string `json:"branch"`
+	Target string `json:"target"`
+	Files  int    `json:"files"`
+}
+
+func runMQConflicts(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	queue, err := mgr.Queue()
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	out := MQConflictsOutput{Rig: rigName, MRs: []MQConflictsMR{}, Pairs: []refinery.ConflictPair{}}
+	var sets []refinery.MRFileSet
+	for _, item := range queue {
+		mr := item.MR
+		if mr.Branch == "" {
+			continue
+		}
+		stats, err := eng.DiffStatMR(mr.Branch, mr.TargetBranch)
+		if err != nil {
+			if out.Skipped == nil {
+				out.Skipped = make(map[string]string)
+			}
+			out.Skipped[mr.ID] = err.Error()
+			continue
+		}
+		set := refinery.MRFileSet{ID: mr.ID, Target: mr.TargetBranch}
+		for _, s := range stats {
+			set.Files = append(set.Files, s.Path)
+		}
+		sets = append(sets, set)
+		out.MRs = append(out.MRs, MQConflictsMR{ID: mr.ID, Branch: mr.Branch, Target: mr.TargetBranch, Files: len(stats)})
+	}
+	if pairs := refinery.FindOverlaps(sets); pairs != nil {
+		out.Pairs = pairs
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printMQConflicts(out)
+	return nil
+}
+
+func printMQConflicts(out MQConflictsOutput) {
+	fmt.Printf("%s Conflict matrix for '%s':\n\n", style.Bold.Render("🔀"), out.Rig)
+
+	if len(out.MRs) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no queued MRs with diffs)"))
+		printMQConflictsSkipped(out.Skipped)
+		return
+	}
+
+	// Shared file counts, keyed by "a|b" in both orders
+	shared := make(map[string]int, 2*len(out.Pairs))
+	for _, p := range out.Pairs {
+		shared[p.A+"|"+p.B] = len(p.Files)
+		shared[p.B+"|"+p.A] = len(p.Files)
+	}
+
+	// Columns are numbered to keep the matrix narrow; rows name the MR
+	columns := []style.Column{{Name: "#", Width: 3, Align: style.AlignRight}, {Name: "MR", Width: 14}}
+	for i := range out.MRs {
+		columns = append(columns, style.Column{Name: fmt.Sprintf("%d", i+1), Width: 3, Align: style.AlignRight})
+	}
+	table := style.NewTable(columns...)
+	for i, a := range out.MRs {
+		row := []string{fmt.Sprintf("%d", i+1), a.ID}
+		for j, b := range out.MRs {
+			switch n := shared[a.ID+"|"+b.ID]; {
+			case i == j:
+				row = append(row, style.Dim.Render("-"))
+			case n > 0:
+				row = append(row, style.Warning.Render(fmt.Sprintf("%d", n)))
+			default:
+				row = append(row, style.Dim.Render("·"))
+			}
+		}
+		table.AddRow(row...)
+	}
+	fmt.Print(table.Render())
+
+	fmt.Println()
+	if len(out.Pairs) == 0 {
+		fmt.Printf("%s No overlapping MRs\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("%s\n", style.Bold.Render("Likely conflicts"))
+		for _, p := range out.Pairs {
+			fmt.Printf("  %s ↔ %s %s\n", p.A, p.B,
+				style.Dim.Render(fmt.Sprintf("(%d shared: %s)", len(p.Files), strings.Join(p.Files, ", "))))
+		}
+	}
+	printMQConflictsSkipped(out.Skipped)
+}
+
+func printMQConflictsSkipped(skipped map[string]string) {
+	ids := make([]string, 0, len(skipped))
+	for id := range skipped {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		reason := skipped[id]
+		fmt.Printf("  %s %s\n", style.Dim.Render(id+": skipped,"), style.Dim.Render(reason))
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Diff command flags
+var (
+	mqDiffRig        string
+	mqDiffPatch      bool
+	mqDiffNameOnly   bool
+	mqDiffNoComments bool
+)
+
+var mqDiffCmd = &cobra.Command{
+	Use:   "diff <mr-id>",
+	Short: "Show the diff of a merge request against its target",
+	Long: `Show what a merge request would change in its target branch.
+
+The diff is computed in the rig's refinery clone (branch...target, i.e.
+against the merge base), so there's no need to find the polecat's worktree.
+
+By default a --stat summary is shown. Use --patch for the full diff or
+--name-only for just the changed files.
+
+Review comments anchored to files and lines (gt mq comment --file --line)
+are shown in the patch under the lines they're on, and listed after the
+--stat summary. Use --no-comments for a plain patch, e.g. to apply it.
+
+Examples:
+  gt mq diff gp-mr-abc123
+  gt mq diff gp-mr-abc123 --patch
+  gt mq diff gp-mr-abc123 --name-only
+  gt mq diff gp-mr-abc123 --patch --no-comments | git apply
+  gt mq diff gp-mr-abc123 --rig greenplace -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQDiff,
+}
+
+func init() {
+	mqDiffCmd.Flags().StringVar(&mqDiffRig, "rig", "", "Rig the MR belongs to (default: from the MR, then cwd)")
+	mqDiffCmd.Flags().BoolVar(&mqDiffPatch, "patch", false, "Show the full patch")
+	mqDiffCmd.Flags().BoolVar(&mqDiffNameOnly, "name-only", false, "Show only changed file names")
+	mqDiffCmd.Flags().BoolVar(&mqDiffNoComments, "no-comments", false, "Leave out review comments")
+	mqDiffCmd.MarkFlagsMutuallyExclusive("patch", "name-only")
+
+	mqCmd.AddCommand(mqDiffCmd)
+}
+
+// MRDiffOutput is the structured output for gt mq diff.
+type MRDiffOutput struct {
+	ID      string             `json:"id"`
+	Branch  string             `json:"branch"`
+	Target  string             `json:"target"`
+	Files   []git.FileDiffStat `json:"files"`
+	Added   int                `json:"added"`
+	Deleted int                `json:"deleted"`
+
+	// Review comments anchored to files and lines, oldest first
+	Comments []refinery.MRComment `json:"comments,omitempty"`
+}
+
+func runMQDiff(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	// Look the MR up in the named rig, or via the current directory's beads
+	beadsDir := ""
+	if mqDiffRig != "" {
+		_, r, err := getRig(mqDiffRig)
+		if err != nil {
+			return err
+		}
+		beadsDir = r.BeadsPath()
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		beadsDir = cwd
+	}
+
+	issue, err := beads.New(beadsDir).Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found", mrID))
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Branch == "" || fields.Target == "" {
+		return fmt.Errorf("%s is not a merge request (no branch/target fields)", mrID)
+	}
+
+	rigName := mqDiffRig
+	if rigName == "" {
+		rigName = fields.Rig
+	}
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+
+	var inline []refinery.MRComment
+	if !mqDiffNoComments {
+		comments, _ := refinery.ParseMRComments(fields.Comments)
+		for _, c := range comments {
+			if c.File != "" {
+				inline = append(inline, c)
+			}
+		}
+	}
+
+	if structuredOutput(false) {
+		stats, err := eng.DiffStatMR(fields.Branch, fields.Target)
+		if err != nil {
+			return fmt.Errorf("diffing %s: %w", mrID, err)
+		}
+		out := MRDiffOutput{ID: issue.ID, Branch: fields.Branch, Target: fields.Target, Files: stats, Comments: inline}
+		if out.Files == nil {
+			out.Files = []git.FileDiffStat{}
+		}
+		for _, s := range stats {
+			out.Added += s.Added
+			out.Deleted += s.Deleted
+		}
+		return renderStructured(out)
+	}
+
+	flag := "--stat"
+	if mqDiffPatch {
+		flag = "--patch"
+	} else if mqDiffNameOnly {
+		flag = "--name-only"
+	}
+	diff, err := eng.DiffMR(fields.Branch, fields.Target, flag)
+	if err != nil {
+		return fmt.Errorf("diffing %s: %w", mrID, err)
+	}
+
+	if !mqDiffPatch && !mqDiffNameOnly {
+		fmt.Printf("%s %s: %s → %s\n\n", style.Bold.Render("📄"), issue.ID, fields.Branch, fields.Target)
+	}
+	if diff == "" {
+		fmt.Printf("%s\n", style.Dim.Render("(no changes)"))
+		return nil
+	}
+
+	switch {
+	case mqDiffNameOnly || len(inline) == 0:
+		fmt.Println(diff)
+	case mqDiffPatch:
+		annotated, unplaced := refinery.AnnotatePatch(diff, inline, func(c refinery.MRComment) []string {
+			lines := formatMRComment(c)
+			for i, line := range lines {
+				lines[i] = style.Warning.Render("┃ ") + line
+			}
+			return lines
+		})
+		fmt.Println(annotated)
+		printMRDiffComments("Comments outside the diff", unplaced)
+	default:
+		fmt.Println(diff)
+		printMRDiffComments("Review comments", inline)
+	}
+	return nil
+}
+
+// printMRDiffComments lists inline comments under a heading.
+func printMRDiffComments(heading string, comments []refinery.MRComment) {
+	if len(comments) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(heading))
+	for _, c := range comments {
+		for _, line := range formatMRComment(c) {
+			fmt.Printf("   %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// orderByFairness orders MRs waiting to be claimed by the rig's fairness
+// policy, and splits off those held by the per-worker in-flight limit.
+// fairness must be non-nil; without a policy callers keep score order.
+func orderByFairness[T any](r *rig.Rig, fairness *refinery.Fairness, items []T, entry func(T) refinery.QueueEntry, now time.Time) (ordered, held []T, err error) {
+	inFlight, err := mergeQueueInFlight(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]refinery.QueueEntry, len(items))
+	for i, item := range items {
+		entries[i] = entry(item)
+	}
+	order, heldIdx := fairness.Order(entries, inFlight, now)
+	for _, i := range order {
+		ordered = append(ordered, items[i])
+	}
+	for _, i := range heldIdx {
+		held = append(held, items[i])
+	}
+	return ordered, held, nil
+}
+
+// mergeQueueInFlight counts each worker's MRs the refinery has claimed.
+func mergeQueueInFlight(r *rig.Rig) (map[string]int, error) {
+	claimed, err := unmergedMRs(r)
+	if err != nil {
+		return nil, err
+	}
+	return refinery.InFlightByWorker(claimed), nil
+}
+
+// mergeQueueLockHolders returns the claimed MR holding each path lock.
+func mergeQueueLockHolders(r *rig.Rig) (map[string]string, error) {
+	claimed, err := unmergedMRs(r)
+	if err != nil {
+		return nil, err
+	}
+	return refinery.LockHolders(claimed), nil
+}
+
+// unmergedMRs lists the rig's open and in-progress MRs, among them those
+// the refinery has claimed.
+func unmergedMRs(r *rig.Rig) ([]*beads.Issue, error) {
+	b := beads.New(r.BeadsPath())
+	var claimed []*beads.Issue
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := b.ListIndexed(beads.ListOptions{Type: "merge-request", Status: status, Priority: -1})
+		if err != nil {
+			return nil, fmt.Errorf("querying claimed MRs: %w", err)
+		}
+		claimed = append(claimed, issues...)
+	}
+	return claimed, nil
+}
+
+// mrQueueEntry describes a queued MR bead for fairness ordering.
+func mrQueueEntry(issue *beads.Issue, fields *beads.MRFields, score float64) refinery.QueueEntry {
+	e := refinery.QueueEntry{Priority: issue.Priority, Score: score}
+	if fields != nil {
+		e.Worker = fields.Worker
+	}
+	if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+		e.CreatedAt = t
+	}
+	return e
+}
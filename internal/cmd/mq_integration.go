@@ -447,7 +447,7 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 	if err := g.MergeNoFF("origin/"+branchName, mergeMsg); err != nil {
 		// Abort merge on failure (best-effort cleanup)
 		_ = g.AbortMerge()
-		return fmt.Errorf("merge failed: %w", err)
+		return withExitCode(ExitMergeConflict, fmt.Errorf("merge failed: %w", err))
 	}
 	fmt.Printf("  %s Merged successfully\n", style.Bold.Render("✓"))
 
@@ -462,9 +462,9 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 				_ = g.Checkout("main") // best-effort: need to be on main to reset
 				resetErr := resetHard(g, "HEAD~1")
 				if resetErr != nil {
-					return fmt.Errorf("tests failed and could not reset: %w (test error: %v)", resetErr, err)
+					return withExitCode(ExitCheckFailed, fmt.Errorf("tests failed and could not reset: %w (test error: %v)", resetErr, err))
 				}
-				return fmt.Errorf("tests failed: %w", err)
+				return withExitCode(ExitCheckFailed, fmt.Errorf("tests failed: %w", err))
 			}
 			fmt.Printf("  %s Tests passed\n", style.Bold.Render("✓"))
 		} else {
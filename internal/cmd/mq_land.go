@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Land command flags
+var mqLandRev string
+
+var mqLandCmd = &cobra.Command{
+	Use:   "land <rig> <mr-id-or-branch>",
+	Short: "Push a merged MR to its target, verifying it first if transactional",
+	Long: `Push the refinery's merge of an MR to origin's target branch.
+
+Run this from the refinery's checkout after merging the MR locally (e.g.,
+git merge --ff-only temp on main). --rev names the merged result; it
+defaults to the MR's target branch. The push is a fast-forward only: if
+origin's target has moved since the merge, nothing lands.
+
+With transactional merges enabled (merge_queue.transactional in the rig's
+settings/config.json) the merged result is first staged on
+refs/gastown/staging/<mr-id> and verified in a temporary worktree:
+  verify_command  Command the merged result must pass (default: test_command)
+  timeout         Limit on a verification run (e.g., "20m")
+
+Only if verification passes does the target move. Otherwise nothing lands:
+the refinery's local target is reset to origin's, the MR is marked failed,
+and the worker is notified. The command exits with code 8.
+
+If the push fails for any other reason (e.g., the target moved), the local
+merge is discarded the same way and the MR goes back to the queue to be
+rebased.
+
+With merge_queue.signing set, the merge commit is replaced by a copy signed
+with the rig's key (format "gpg" or "ssh", key) before it is verified and
+pushed, and the local target is moved to it. If it can't be signed, nothing
+lands. With merge_queue.attestation set, an attestation of the merge (MR,
+source issue, commit, signature, checks passed, test results, approvers,
+who merged it and when) is stored on the MR bead as "attestation: <json>",
+and with git_notes also as a note on the merge commit, pushed to origin
+(refs/notes/gastown/attestations by default).
+
+Once an MR lands on the rig's default branch, a cherry-pick MR is submitted
+onto each merge target with "backport": true (merge_queue.targets), on a
+backport/<target>/<mr-id> branch. A cherry-pick that conflicts is reported
+and skipped; backport that one by hand.
+
+While the queue is paused (rig parked or docked, or frozen by 'gt release')
+the command exits with code 6 and nothing lands.
+
+Examples:
+  gt mq land greenplace gp-mr-abc123
+  gt mq land greenplace gp-mr-abc123 --rev temp`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQLand,
+}
+
+func init() {
+	mqLandCmd.Flags().StringVar(&mqLandRev, "rev", "", "Merged result to land (default: the MR's target branch)")
+
+	mqCmd.AddCommand(mqLandCmd)
+}
+
+// MRLandOutput is the structured output for gt mq land.
+type MRLandOutput struct {
+	ID            string                  `json:"id"`
+	Target        string                  `json:"target"`
+	Landed        bool                    `json:"landed"`
+	Commit        string                  `json:"commit,omitempty"`
+	VerifyCommand string                  `json:"verify_command,omitempty"`
+	Tests         *refinery.TestResults   `json:"tests,omitempty"`    // Verification results
+	Coverage      *refinery.CoverageDelta `json:"coverage,omitempty"` // Coverage change, if the rig gates on it
+	Lint          *refinery.LintReport    `json:"lint,omitempty"`
+	LintTask      string                  `json:"lint_task,omitempty"` // Fix-it task for lint findings
+	Signed        string                  `json:"signed,omitempty"`    // Signature format, if the merge commit was signed
+	Attestation   *refinery.Attestation   `json:"attestation,omitempty"`
+	State         refinery.MRState        `json:"state"`
+	Backports     []refinery.Backport     `json:"backports,omitempty"` // Cherry-pick MRs onto backport targets
+	Error         string                  `json:"error,omitempty"`
+}
+
+func runMQLand(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	if reason := mergeQueuePausedReason(r); reason != "" {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is paused (%s)", rigName, reason))
+	}
+
+	mr, err := mgr.FindMR(args[1])
+	if err != nil {
+		if errors.Is(err, refinery.ErrMRNotFound) {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", args[1], rigName))
+		}
+		return fmt.Errorf("finding merge request: %w", err)
+	}
+
+	bd := beads.New(r.BeadsPath())
+	issue, err := bd.Show(mr.ID)
+	if err != nil {
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("merge request %s has no MR fields", mr.ID)
+	}
+	target := fields.Target
+	if target == "" {
+		target = r.DefaultBranch()
+	}
+	rev := mqLandRev
+	if rev == "" {
+		rev = target
+	}
+
+	tx, err := refinery.LoadTransaction(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading transactional merge settings: %w", err)
+	}
+	signing, err := refinery.LoadSigning(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge signing settings: %w", err)
+	}
+	attestations, err := refinery.LoadAttestations(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge attestation settings: %w", err)
+	}
+
+	eng := refinery.NewEngineer(r)
+	if structuredOutput(false) {
+		eng.SetOutput(io.Discard)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	result := eng.Land(ctx, tx, signing, mr.ID, rev, target)
+
+	out := MRLandOutput{
+		ID:            mr.ID,
+		Target:        target,
+		Landed:        result.Success,
+		Commit:        result.MergeCommit,
+		VerifyCommand: tx.VerifyCommand(),
+		Tests:         result.Tests,
+		Coverage:      result.Coverage,
+		Lint:          result.Lint,
+		Signed:        result.Signed,
+		State:         refinery.StateOf(issue),
+		Error:         result.Error,
+	}
+
+	if result.Success {
+		fields.MergeCommit = result.MergeCommit
+		result.Apply(fields, refinery.CheckVerify)
+		desc := beads.SetMRFields(issue, fields)
+		if err := bd.Update(mr.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			style.PrintWarning("could not record merge commit on %s: %v", mr.ID, err)
+		}
+
+		// Record what landed, and on whose say-so, for the audit trail
+		out.Attestation, err = eng.Attest(attestations, mr.ID, result.Signed)
+		if err != nil {
+			style.PrintWarning("%v", err)
+		}
+
+		// Queue cherry-picks onto the rig's backport targets
+		out.Backports, err = eng.SubmitBackports(mr.ID)
+		if err != nil {
+			style.PrintWarning("%v", err)
+		}
+	} else {
+		// Nothing landed: a merge that failed verification is done for;
+		// anything else (e.g., the target moved) gets rebased and retried.
+		if err := refinery.RecordCheckRun(bd, issue, refinery.CheckVerify, result); err != nil {
+			style.PrintWarning("%v", err)
+		}
+		// Lint findings go back to the worker as a fix-it task
+		from := refinery.StateOf(issue)
+		if result.Lint.Failed() {
+			out.LintTask, err = eng.SendBackForLint(issue, result.Lint)
+			if err != nil {
+				style.PrintWarning("could not send %s back for lint: %v", mr.ID, err)
+			}
+		}
+		if out.LintTask != "" {
+			out.State = refinery.StateChangesRequested
+			logMRStateEvent(rigName, mr.ID, fields, from, out.State, "lint: "+out.LintTask)
+		} else {
+			to, reason := refinery.StateQueued, ""
+			if result.TestsFailed {
+				to, reason = refinery.StateFailed, firstLine(result.Error)
+			}
+			if from, err = refinery.RecordState(bd, issue, to, reason); err != nil {
+				return fmt.Errorf("recording MR state: %w", err)
+			}
+			logMRStateEvent(rigName, mr.ID, fields, from, to, reason)
+			out.State = to
+		}
+		// Land rolled the local merge back; there's nothing left to recover
+		if err := refinery.OpenJournal(r.Path).Finish(mr.ID); err != nil {
+			style.PrintWarning("%v", err)
+		}
+
+		switch {
+		case out.LintTask != "":
+			notifyMRWorker(fields, detectSender(), fmt.Sprintf("Merge failed lint: %s", mr.ID),
+				fmt.Sprintf("Your merge %s (%s) has %d lint finding(s), so nothing landed on %s.\n\nTask: %s (lists each finding)\n\nFix them, push to the same branch, and resubmit with gt mq submit.",
+					mr.ID, fields.SourceIssue, result.Lint.Total, target, out.LintTask))
+		case result.Coverage.Blocked():
+			notifyMRWorker(fields, detectSender(), fmt.Sprintf("Merge failed coverage gate: %s", mr.ID),
+				fmt.Sprintf("Your merge %s (%s) would drop test coverage on %s, so nothing landed.\n\n%s\n\nAdd tests and resubmit.",
+					mr.ID, fields.SourceIssue, target, result.Error))
+		case result.TestsFailed:
+			notifyMRWorker(fields, detectSender(), fmt.Sprintf("Merge failed verification: %s", mr.ID),
+				fmt.Sprintf("Your merge %s (%s) failed post-merge verification, so nothing landed on %s.\n\nCommand: %s\n\n%s\n\nFix the branch and resubmit.",
+					mr.ID, fields.SourceIssue, target, out.VerifyCommand, result.Error))
+		}
+	}
+
+	if structuredOutput(false) {
+		if err := renderStructured(out); err != nil {
+			return err
+		}
+	} else {
+		printMQLand(out)
+	}
+
+	switch {
+	case result.Success:
+		return nil
+	case result.TestsFailed:
+		return NewSilentExit(ExitCheckFailed)
+	default:
+		return NewSilentExit(ExitError)
+	}
+}
+
+func printMQLand(out MRLandOutput) {
+	if out.Landed {
+		verified := "unverified"
+		if out.VerifyCommand != "" {
+			verified = "verified with " + out.VerifyCommand
+		}
+		if out.Signed != "" {
+			verified += ", signed with " + out.Signed
+		}
+		fmt.Printf("%s Landed %s on %s at %s %s\n", style.Success.Render("✓"), out.ID, out.Target,
+			shortSHA(out.Commit), style.Dim.Render("("+verified+")"))
+		if out.Attestation != nil {
+			fmt.Printf("  Attestation: recorded on %s\n", out.ID)
+		}
+		if out.Tests != nil {
+			fmt.Printf("  Tests: %s\n", out.Tests)
+		}
+		if out.Coverage != nil {
+			fmt.Printf("  Coverage: %s\n", out.Coverage)
+		}
+		for _, bp := range out.Backports {
+			if bp.Error != "" {
+				fmt.Printf("  %s Backport onto %s failed: %s\n", style.Warning.Render("⚠"), bp.Target, firstLine(bp.Error))
+				continue
+			}
+			fmt.Printf("  Backport onto %s: %s\n", bp.Target, bp.MRID)
+		}
+		return
+	}
+
+	fmt.Printf("%s %s did not land on %s\n", style.Error.Render("✗"), out.ID, out.Target)
+	if out.Tests != nil {
+		fmt.Printf("  Tests: %s\n", out.Tests)
+	}
+	if out.Lint.Failed() {
+		printLintFindings("  ", out.Lint.Findings, out.Lint.Total)
+	}
+	for _, line := range strings.Split(out.Error, "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+	if out.LintTask != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Nothing landed; MR sent back to the worker with fix-it task "+out.LintTask))
+	} else if out.State == refinery.StateFailed {
+		fmt.Printf("  %s\n", style.Dim.Render("Nothing landed; MR failed and worker notified"))
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render("Nothing landed; MR returned to the queue"))
+	}
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
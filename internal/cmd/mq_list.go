@@ -4,24 +4,68 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
+// MQListItem is an MR bead in gt mq list's structured output, with its
+// lifecycle state.
+type MQListItem struct {
+	SchemaVersion int `json:"schema_version"` // published schema: gt schema mq.list
+	beads.Issue
+	State refinery.MRState `json:"state"`
+}
+
+// mqListRow is an MR as gt mq list shows it.
+type mqListRow struct {
+	issue  *beads.Issue
+	fields *beads.MRFields
+	score  float64
+	risk   *refinery.RiskAssessment // nil if not scored
+	status string                   // ready, blocked, review, changes, held, locked, or the MR's state
+}
+
 func runMQList(cmd *cobra.Command, args []string) error {
-	rigName := args[0]
+	args, err := pickArgs(cmd, args, pickRig)
+	if err != nil {
+		return err
+	}
+	if structuredOutput(mqListJSON) {
+		return listMQ(args[0])
+	}
+	return withPager(func() error { return listMQ(args[0]) })
+}
 
+// listMQ prints a rig's merge queue.
+func listMQ(rigName string) error {
 	_, r, _, err := getRefineryManager(rigName)
 	if err != nil {
 		return err
 	}
 
+	var view *config.MQListViewConfig
+	if mqListView != "" {
+		if view, err = loadMQListView(r.Path, mqListView); err != nil {
+			return err
+		}
+	}
+	q, err := newMQListQuery(view, mqListFlags(), strings.TrimSuffix(detectSender(), "/"))
+	if err != nil {
+		if mqListView != "" {
+			return fmt.Errorf("view %s: %w", mqListView, err)
+		}
+		return err
+	}
+
 	// Create beads wrapper for the rig - use BeadsPath() to get the git-synced location
 	b := beads.New(r.BeadsPath())
 
@@ -32,17 +76,16 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		Priority: -1,
 	}
 
-	// Apply status filter if specified
-	if mqListStatus != "" {
-		opts.Status = mqListStatus
-	} else if !mqListReady {
-		// Default to open if not showing ready
-		opts.Status = "open"
+	// Apply status filter if specified. By default show every unmerged MR:
+	// queued ones are open, those the refinery is working on in progress.
+	statuses := []string{q.Status}
+	if q.Status == "" {
+		statuses = []string{"open", "in_progress"}
 	}
 
 	var issues []*beads.Issue
 
-	if mqListReady {
+	if q.Ready {
 		// Use ready query which filters by no blockers
 		allReady, err := b.Ready()
 		if err != nil {
@@ -55,35 +98,63 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else {
-		issues, err = b.List(opts)
-		if err != nil {
-			return fmt.Errorf("querying merge queue: %w", err)
+		for _, status := range statuses {
+			opts.Status = status
+			listed, err := b.ListIndexed(opts)
+			if err != nil {
+				return fmt.Errorf("querying merge queue: %w", err)
+			}
+			issues = append(issues, listed...)
 		}
 	}
 
+	protection, err := refinery.LoadBranchProtection(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading branch protection: %w", err)
+	}
+	scorer, err := refinery.LoadRiskScorer(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading risk settings: %w", err)
+	}
+	ownership, err := refinery.LoadOwnership(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading owners: %w", err)
+	}
+	fairness, err := refinery.LoadFairness(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge fairness: %w", err)
+	}
+	sla, err := refinery.LoadSLA(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge SLA: %w", err)
+	}
+	targets, err := refinery.LoadTargets(r.Path, r.DefaultBranch())
+	if err != nil {
+		return fmt.Errorf("loading merge targets: %w", err)
+	}
+	projects, err := refinery.LoadProjects(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge projects: %w", err)
+	}
+
 	// Apply additional filters and calculate scores
 	now := time.Now()
-	type scoredIssue struct {
-		issue  *beads.Issue
-		fields *beads.MRFields
-		score  float64
-	}
-	var scored []scoredIssue
+	var scored []mqListRow
 
 	for _, issue := range issues {
 		// Manual status filtering as workaround for bd list not respecting --status filter
-		if mqListReady {
+		if q.Ready {
 			// Ready view should only show open MRs
 			if issue.Status != "open" {
 				continue
 			}
-		} else if mqListStatus != "" && !strings.EqualFold(mqListStatus, "all") {
+		} else if q.Status != "" && !strings.EqualFold(q.Status, "all") {
 			// Explicit status filter should match exactly
-			if !strings.EqualFold(issue.Status, mqListStatus) {
+			if !strings.EqualFold(issue.Status, q.Status) {
 				continue
 			}
-		} else if mqListStatus == "" && issue.Status != "open" {
-			// Default case (no status specified) should only show open
+		} else if q.Status == "" && issue.Status == "closed" {
+			// Default case (no status specified) should only show unmerged
 			continue
 		}
 
@@ -91,31 +162,48 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		fields := beads.ParseMRFields(issue)
 
 		// Filter by worker
-		if mqListWorker != "" {
+		if q.Worker != "" {
 			worker := ""
 			if fields != nil {
 				worker = fields.Worker
 			}
-			if !strings.EqualFold(worker, mqListWorker) {
+			if !q.matchesWorker(worker) {
 				continue
 			}
 		}
 
 		// Filter by epic (target branch)
-		if mqListEpic != "" {
+		if q.Epic != "" {
 			target := ""
 			if fields != nil {
 				target = fields.Target
 			}
-			expectedTarget := "integration/" + mqListEpic
+			expectedTarget := "integration/" + q.Epic
 			if target != expectedTarget {
 				continue
 			}
 		}
 
+		// Filter by target sub-queue
+		if q.Target != "" && mrTarget(fields, targets) != q.Target {
+			continue
+		}
+
+		if !beads.HasAllLabels(issue, q.Labels) {
+			continue
+		}
+
+		// Filter by age
+		if q.OlderThan > 0 {
+			createdAt, ok := parseTimestamp(issue.CreatedAt)
+			if !ok || now.Sub(createdAt) < q.OlderThan {
+				continue
+			}
+		}
+
 		// Calculate priority score
 		score := calculateMRScore(issue, fields, now)
-		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score})
+		scored = append(scored, mqListRow{issue: issue, fields: fields, score: score})
 	}
 
 	// Sort by score descending (highest priority first)
@@ -123,136 +211,480 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		return scored[i].score > scored[j].score
 	})
 
-	// Extract filtered issues for JSON output compatibility
-	var filtered []*beads.Issue
-	for _, s := range scored {
-		filtered = append(filtered, s.issue)
+	// Each target branch has its own sub-queue, as do monorepo projects
+	// with their own queue. Within each, the rig's fairness policy orders
+	// MRs still waiting to be claimed; claimed and closed MRs stay ahead of
+	// them
+	queues := refinery.SplitByTarget(targets, scored, func(s mqListRow) string {
+		return mrTarget(s.fields, targets)
+	})
+	queues = refinery.SplitByProject(projects, queues, func(s mqListRow) string {
+		return mrProject(s.fields)
+	})
+	held := make(map[string]bool)
+	if fairness != nil {
+		for i, queue := range queues {
+			var waiting, claimed []mqListRow
+			for _, s := range queue.Items {
+				if s.issue.Status == "open" && s.issue.Assignee == "" {
+					waiting = append(waiting, s)
+				} else {
+					claimed = append(claimed, s)
+				}
+			}
+			ordered, heldItems, err := orderByFairness(r, fairness, waiting, func(s mqListRow) refinery.QueueEntry {
+				return mrQueueEntry(s.issue, s.fields, s.score)
+			}, now)
+			if err != nil {
+				return err
+			}
+			for _, s := range heldItems {
+				held[s.issue.ID] = true
+			}
+			queues[i].Items = append(append(claimed, ordered...), heldItems...)
+		}
+	}
+
+	// Queued MRs wanting a path lock that a claimed MR, or one ahead of
+	// them, holds wait on it
+	lockWaits, err := mqListLockWaits(r, queues, protection)
+	if err != nil {
+		return err
 	}
 
-	// JSON output
-	if mqListJSON {
-		return outputJSON(filtered)
+	// Work out whether an MR can merge: queued MRs show why they are or
+	// aren't ready, the rest their lifecycle state. Risk is scored from
+	// each open MR's diff in the refinery clone
+	eng := refinery.NewEngineer(r)
+	assess := func(item *mqListRow) {
+		if item.status != "" {
+			return
+		}
+		issue := item.issue
+		review := refinery.ReviewFromFields(item.fields)
+
+		var ownerWait *refinery.ProtectionViolation
+		if issue.Status == "open" && item.fields != nil && item.fields.Branch != "" && item.fields.Target != "" {
+			if stats, err := eng.DiffStatMR(item.fields.Branch, item.fields.Target); err == nil {
+				a := scorer.Score(stats)
+				ownerWait = ownership.CheckApproval(refinery.DiffStatFiles(stats), review)
+				item.risk = &a
+			}
+		}
+
+		state := refinery.StateOf(issue)
+		item.status = string(state)
+		if state == refinery.StateChangesRequested {
+			item.status = "changes"
+		} else if state == refinery.StateQueued {
+			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
+				item.status = "blocked"
+			} else if len(review.ChangesRequestedBy) > 0 {
+				item.status = "changes"
+			} else if protection.AwaitingReview(review) != "" {
+				item.status = "review"
+			} else if item.risk != nil && scorer.CheckApproval(*item.risk, protection, review) != nil {
+				item.status = "review"
+			} else if ownerWait != nil {
+				item.status = "review"
+			} else if held[issue.ID] {
+				item.status = "held"
+			} else if _, ok := lockWaits[issue.ID]; ok {
+				item.status = "locked"
+			} else {
+				item.status = "ready"
+			}
+		}
+	}
+
+	// Queue and risk order need every MR assessed; otherwise only those
+	// on the page shown are
+	for _, queue := range queues {
+		if q.Sort == "queue" || q.Sort == "risk" {
+			for i := range queue.Items {
+				assess(&queue.Items[i])
+			}
+		}
+		sortMQListRows(queue.Items, q.Sort, q.Reverse)
+	}
+	scored = nil
+	for _, queue := range queues {
+		scored = append(scored, queue.Items...)
+	}
+
+	structured := structuredOutput(mqListJSON)
+	start, end, err := mqListPage.bounds(len(scored), structured)
+	if err != nil {
+		return err
+	}
+	footer := mqListPage.footer(start, end, len(scored))
+
+	// Extract the page's issues, with their lifecycle state, for JSON output
+	var filtered []MQListItem
+	for _, s := range scored[start:end] {
+		filtered = append(filtered, MQListItem{SchemaVersion: mqListSchemaVersion, Issue: utcIssueTimes(*s.issue), State: refinery.StateOf(s.issue)})
+	}
+
+	// JSON output, or CSV/TSV of the listing's columns
+	if structured {
+		if tabularOutput() {
+			page := scored[start:end]
+			for i := range page {
+				assess(&page[i])
+			}
+			return renderStructured(mqListTabular(q.Columns, page, targets))
+		}
+		return renderStructured(filtered)
 	}
 
 	// Human-readable output
 	fmt.Printf("%s Merge queue for '%s':\n\n", style.Bold.Render("📋"), rigName)
 
-	if len(filtered) == 0 {
+	if len(scored) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(empty)"))
 		return nil
 	}
 
-	// Create styled table with SCORE column
-	table := style.NewTable(
-		style.Column{Name: "ID", Width: 12},
-		style.Column{Name: "SCORE", Width: 7, Align: style.AlignRight},
-		style.Column{Name: "PRI", Width: 4},
-		style.Column{Name: "CONVOY", Width: 12},
-		style.Column{Name: "BRANCH", Width: 24},
-		style.Column{Name: "STATUS", Width: 10},
-		style.Column{Name: "AGE", Width: 6, Align: style.AlignRight},
-	)
+	// Absolute times need a wider column than ages do
+	ageColumn := style.Column{Name: "AGE", Width: 6, Align: style.AlignRight}
+	if timeFormat != TimeRelative {
+		ageColumn = style.Column{Name: "CREATED", Width: 21}
+	}
 
-	// Add rows using scored items (already sorted by score)
-	for _, item := range scored {
-		issue := item.issue
-		fields := item.fields
+	// Print the page's rows as they are made, in the query's order, one
+	// table per target sub-queue
+	breached := 0
+	locked := make(map[string]bool)
+	columns := map[string]style.Column{
+		"id":     {Name: "ID", Width: 12},
+		"score":  {Name: "SCORE", Width: 7, Align: style.AlignRight},
+		"risk":   {Name: "RISK", Width: 9},
+		"pri":    {Name: "PRI", Width: 4},
+		"convoy": {Name: "CONVOY", Width: 12},
+		"branch": {Name: "BRANCH", Width: 24},
+		"worker": {Name: "WORKER", Width: 12},
+		"target": {Name: "TARGET", Width: 14},
+		"status": {Name: "STATUS", Width: 10},
+		"age":    ageColumn,
+		"labels": {Name: "LABELS", Width: 16},
+	}
+	var tableColumns []style.Column
+	for _, c := range q.Columns {
+		tableColumns = append(tableColumns, columns[c])
+	}
+	table := style.NewTable(tableColumns...)
+	queueStart := 0
+	for _, queue := range queues {
+		// The part of this sub-queue on the page
+		lo := min(max(start-queueStart, 0), len(queue.Items))
+		hi := min(max(end-queueStart, 0), len(queue.Items))
+		items := queue.Items[lo:hi]
+		queueStart += len(queue.Items)
+		if len(items) == 0 {
+			continue
+		}
+		if len(queues) > 1 {
+			fmt.Printf("  %s\n", style.Bold.Render("→ "+queue.Name()))
+		}
 
-		// Determine display status
-		displayStatus := issue.Status
-		if issue.Status == "open" {
-			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
-				displayStatus = "blocked"
-			} else {
-				displayStatus = "ready"
+		fmt.Print(table.RenderHeader())
+		for i := range items {
+			item := &items[i]
+			assess(item)
+			issue := item.issue
+			fields := item.fields
+			state := refinery.StateOf(issue)
+			if item.status == "locked" {
+				locked[issue.ID] = true
 			}
-		}
 
-		// Format status with styling
-		styledStatus := displayStatus
-		switch displayStatus {
-		case "ready":
-			styledStatus = style.Success.Render("ready")
-		case "in_progress":
-			styledStatus = style.Warning.Render("active")
-		case "blocked":
-			styledStatus = style.Dim.Render("blocked")
-		case "closed":
-			styledStatus = style.Dim.Render("closed")
-		}
-
-		// Get MR fields
-		branch := ""
-		convoyID := ""
-		if fields != nil {
-			branch = fields.Branch
-			convoyID = fields.ConvoyID
-		}
-
-		// Format convoy column
-		convoyDisplay := style.Dim.Render("(none)")
-		if convoyID != "" {
-			// Truncate convoy ID for display
-			if len(convoyID) > 12 {
-				convoyID = convoyID[:12]
+			riskStr := style.Dim.Render("-")
+			if item.risk != nil {
+				riskStr = formatRisk(*item.risk)
 			}
-			convoyDisplay = convoyID
-		}
 
-		// Format priority with color
-		priority := fmt.Sprintf("P%d", issue.Priority)
-		if issue.Priority <= 1 {
-			priority = style.Error.Render(priority)
-		} else if issue.Priority == 2 {
-			priority = style.Warning.Render(priority)
-		}
+			// Format status with styling
+			var styledStatus string
+			switch item.status {
+			case "ready":
+				styledStatus = style.Success.Render("ready")
+			case "blocked", "held", "locked":
+				styledStatus = style.Dim.Render(item.status)
+			case "review", "changes":
+				styledStatus = style.Warning.Render(item.status)
+			default:
+				styledStatus = formatMRState(state)
+			}
 
-		// Format score
-		scoreStr := fmt.Sprintf("%.1f", item.score)
+			// Get MR fields
+			branch := ""
+			convoyID := ""
+			worker := style.Dim.Render("-")
+			if fields != nil {
+				branch = fields.Branch
+				convoyID = fields.ConvoyID
+				if fields.Worker != "" {
+					worker = fields.Worker
+				}
+			}
 
-		// Calculate age
-		age := formatMRAge(issue.CreatedAt)
+			// Format convoy column
+			convoyDisplay := style.Dim.Render("(none)")
+			if convoyID != "" {
+				// Truncate convoy ID for display
+				if len(convoyID) > 12 {
+					convoyID = convoyID[:12]
+				}
+				convoyDisplay = convoyID
+			}
 
-		// Truncate ID if needed
-		displayID := issue.ID
-		if len(displayID) > 12 {
-			displayID = displayID[:12]
-		}
+			// Format priority with color
+			priority := fmt.Sprintf("P%d", issue.Priority)
+			if issue.Priority <= 1 {
+				priority = style.Error.Render(priority)
+			} else if issue.Priority == 2 {
+				priority = style.Warning.Render(priority)
+			}
+
+			// Format score
+			scoreStr := fmt.Sprintf("%.1f", item.score)
+
+			// Calculate age, highlighting unmerged MRs past their SLA
+			age := style.Dim.Render(formatMRAge(issue.CreatedAt))
+			if !state.Terminal() {
+				if createdAt, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil && sla.For(mrProject(fields)).Overdue(issue.Priority, createdAt, now) > 0 {
+					age = style.Error.Render(formatMRAge(issue.CreatedAt) + "!")
+					breached++
+				}
+			}
+
+			// Truncate ID if needed
+			displayID := issue.ID
+			if len(displayID) > 12 {
+				displayID = displayID[:12]
+			}
 
-		table.AddRow(displayID, scoreStr, priority, convoyDisplay, branch, styledStatus, style.Dim.Render(age))
+			labelsDisplay := style.Dim.Render("-")
+			if shown := beads.DisplayLabels(issue); len(shown) > 0 {
+				labelsDisplay = strings.Join(shown, ",")
+			}
+
+			cells := map[string]string{
+				"id":     displayID,
+				"score":  scoreStr,
+				"risk":   riskStr,
+				"pri":    priority,
+				"convoy": convoyDisplay,
+				"branch": branch,
+				"worker": worker,
+				"target": mrTarget(fields, targets),
+				"status": styledStatus,
+				"age":    age,
+				"labels": labelsDisplay,
+			}
+			var row []string
+			for _, c := range q.Columns {
+				row = append(row, cells[c])
+			}
+			fmt.Print(table.RenderRow(row...))
+		}
+	}
+	if footer != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(footer))
 	}
 
-	fmt.Print(table.Render())
+	if breached > 0 {
+		fmt.Printf("  %s\n", style.Error.Render(fmt.Sprintf("%d MR(s) past merge SLA (marked !)", breached)))
+	}
 
 	// Show blocking details below table
-	for _, item := range scored {
+	for _, item := range scored[start:end] {
 		issue := item.issue
 		displayStatus := issue.Status
 		if issue.Status == "open" && (len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0) {
 			displayStatus = "blocked"
 		}
+		displayID := issue.ID
+		if len(displayID) > 12 {
+			displayID = displayID[:12]
+		}
 		if displayStatus == "blocked" && len(issue.BlockedBy) > 0 {
-			displayID := issue.ID
-			if len(displayID) > 12 {
-				displayID = displayID[:12]
-			}
 			fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
 				style.Dim.Render(fmt.Sprintf("waiting on %s", issue.BlockedBy[0])))
+		} else if locked[issue.ID] {
+			wait := lockWaits[issue.ID]
+			fmt.Printf("  %s %s\n", style.Dim.Render(displayID+":"),
+				style.Dim.Render(fmt.Sprintf("waiting on lock %s (held by %s)", wait.Lock, wait.Holder)))
 		}
 	}
 
 	return nil
 }
 
-// formatMRAge formats the age of an MR from its created_at timestamp.
-func formatMRAge(createdAt string) string {
-	t, err := time.Parse(time.RFC3339, createdAt)
+// mqListLockWaits returns the queued MRs that must wait for another to
+// release a path lock, by ID. MRs not yet up for merging (blocked, or
+// awaiting changes or sign-off) take no locks.
+func mqListLockWaits(r *rig.Rig, queues []refinery.SubQueue[mqListRow], protection *refinery.BranchProtection) (map[string]refinery.LockWait, error) {
+	var waiting []mqListRow
+	for _, queue := range queues {
+		for _, s := range queue.Items {
+			if s.fields == nil || s.fields.Locks == "" || s.issue.Status != "open" || s.issue.Assignee != "" {
+				continue
+			}
+			review := refinery.ReviewFromFields(s.fields)
+			if len(s.issue.BlockedBy) > 0 || s.issue.BlockedByCount > 0 || len(review.ChangesRequestedBy) > 0 ||
+				refinery.StateOf(s.issue) != refinery.StateQueued || protection.AwaitingReview(review) != "" {
+				continue
+			}
+			waiting = append(waiting, s)
+		}
+	}
+	if len(waiting) == 0 {
+		return nil, nil
+	}
+	holders, err := mergeQueueLockHolders(r)
 	if err != nil {
-		// Try other formats
-		t, err = time.Parse("2006-01-02T15:04:05Z", createdAt)
-		if err != nil {
-			return "?"
+		return nil, err
+	}
+	_, waits := refinery.SerializeLocks(holders, waiting,
+		func(s mqListRow) string { return s.issue.ID },
+		func(s mqListRow) []string { return refinery.SplitMRList(s.fields.Locks) })
+	return waits, nil
+}
+
+// mqListTabular lays out rows for --output csv/tsv, in the given columns,
+// with plain values: the full ID, numeric priority and risk, and the
+// creation time in place of the age.
+func mqListTabular(columns []string, rows []mqListRow, targets *refinery.Targets) tabularData {
+	headers := map[string]string{"pri": "priority", "age": "created_at"}
+	var table tabularData
+	for _, c := range columns {
+		if h, ok := headers[c]; ok {
+			c = h
+		}
+		table.Header = append(table.Header, c)
+	}
+	for _, row := range rows {
+		fields := row.fields
+		if fields == nil {
+			fields = &beads.MRFields{}
+		}
+		risk := ""
+		if row.risk != nil {
+			risk = fmt.Sprintf("%d", row.risk.Score)
+		}
+		cells := map[string]string{
+			"id":     row.issue.ID,
+			"score":  fmt.Sprintf("%.1f", row.score),
+			"risk":   risk,
+			"pri":    fmt.Sprintf("%d", row.issue.Priority),
+			"convoy": fields.ConvoyID,
+			"branch": fields.Branch,
+			"worker": fields.Worker,
+			"target": mrTarget(row.fields, targets),
+			"status": row.status,
+			"age":    utcTimestamp(row.issue.CreatedAt),
+			"labels": strings.Join(beads.DisplayLabels(row.issue), ";"),
 		}
+		var out []string
+		for _, c := range columns {
+			out = append(out, cells[c])
+		}
+		table.Rows = append(table.Rows, out)
+	}
+	return table
+}
+
+// sortMQListRows orders a sub-queue's rows for --sort, keeping merge order
+// among equals. The default, "queue", is the order the refinery works in:
+// MRs it has claimed, then the ready ones, then those held back by
+// fairness, awaiting review, and blocked, then closed ones.
+func sortMQListRows(rows []mqListRow, by string, reverse bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch by {
+		case "score":
+			return a.score > b.score
+		case "priority":
+			return a.issue.Priority < b.issue.Priority
+		case "age":
+			ta, _ := parseTimestamp(a.issue.CreatedAt)
+			tb, _ := parseTimestamp(b.issue.CreatedAt)
+			return ta.Before(tb)
+		case "worker":
+			return mqListRowWorker(a) < mqListRowWorker(b)
+		case "risk":
+			return mqListRowRisk(a) > mqListRowRisk(b)
+		}
+		return mqListQueueRank(a) < mqListQueueRank(b)
+	})
+	if reverse {
+		slices.Reverse(rows)
+	}
+}
+
+// mqListQueueRank is a row's place in queue order (lower comes first).
+func mqListQueueRank(row mqListRow) int {
+	state := refinery.StateOf(row.issue)
+	switch {
+	case state.Terminal():
+		return 5
+	case (state != refinery.StateQueued && state != refinery.StateChangesRequested) || row.issue.Assignee != "":
+		return 0
+	}
+	switch row.status {
+	case "ready":
+		return 1
+	case "held", "locked":
+		return 2
+	case "review", "changes":
+		return 3
+	}
+	return 4
+}
+
+// mqListRowWorker returns a row's worker, for sorting.
+func mqListRowWorker(row mqListRow) string {
+	if row.fields == nil {
+		return ""
+	}
+	return strings.ToLower(row.fields.Worker)
+}
+
+// mqListRowRisk returns a row's risk score, -1 if it has none.
+func mqListRowRisk(row mqListRow) int {
+	if row.risk == nil {
+		return -1
+	}
+	return row.risk.Score
+}
+
+// mrTarget returns the target branch an MR merges into: its target field,
+// or the rig's default branch.
+func mrTarget(fields *beads.MRFields, targets *refinery.Targets) string {
+	if fields != nil && fields.Target != "" {
+		return fields.Target
+	}
+	return targets.Default()
+}
+
+// mrProject returns the monorepo project an MR was submitted in, or "".
+func mrProject(fields *beads.MRFields) string {
+	if fields == nil {
+		return ""
+	}
+	return fields.Project
+}
+
+// formatMRAge formats the age of an MR from its created_at timestamp, or
+// the timestamp itself when --time-format asks for absolute times.
+func formatMRAge(createdAt string) string {
+	t, ok := parseTimestamp(createdAt)
+	if !ok {
+		return "?"
+	}
+	if timeFormat != TimeRelative {
+		return formatAbsoluteTime(t)
 	}
 
 	d := time.Since(t)
@@ -269,6 +701,28 @@ func formatMRAge(createdAt string) string {
 	return fmt.Sprintf("%dd", int(d.Hours()/24))
 }
 
+// utcIssueTimes returns a copy of issue with its timestamps in UTC, for
+// structured output.
+func utcIssueTimes(issue beads.Issue) beads.Issue {
+	issue.CreatedAt = utcTimestamp(issue.CreatedAt)
+	issue.UpdatedAt = utcTimestamp(issue.UpdatedAt)
+	issue.ClosedAt = utcTimestamp(issue.ClosedAt)
+	return issue
+}
+
+// formatRisk renders a risk assessment as "<score> <level>", colored by level.
+func formatRisk(a refinery.RiskAssessment) string {
+	s := fmt.Sprintf("%d %s", a.Score, a.Level)
+	switch a.Level {
+	case refinery.RiskHigh:
+		return style.Error.Render(s)
+	case refinery.RiskMedium:
+		return style.Warning.Render(s)
+	default:
+		return style.Dim.Render(s)
+	}
+}
+
 // outputJSON outputs data as JSON.
 func outputJSON(data interface{}) error {
 	enc := json.NewEncoder(os.Stdout)
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// mqListColumns are the columns gt mq list can show, in their default
+// order. The default view shows all but worker and target.
+var mqListColumns = []string{"id", "score", "risk", "pri", "convoy", "branch", "worker", "target", "status", "age", "labels"}
+
+// mqListDefaultColumns are the columns shown without --columns or a view
+// that sets them.
+var mqListDefaultColumns = []string{"id", "score", "risk", "pri", "convoy", "branch", "status", "age", "labels"}
+
+// mqListSorts are the orders gt mq list can show each sub-queue in.
+var mqListSorts = []string{"queue", "score", "priority", "age", "worker", "risk"}
+
+// mqListMe stands for whoever runs gt mq list in a view's worker filter.
+const mqListMe = "@me"
+
+// builtinMQListViews are the views every rig has, unless it defines a
+// view of the same name.
+var builtinMQListViews = map[string]*config.MQListViewConfig{
+	"mine": {Worker: mqListMe},
+}
+
+// mqListQuery is what gt mq list shows: the filters, columns and order of
+// its view, overridden by the flags given.
+type mqListQuery struct {
+	Ready     bool
+	Status    string
+	Worker    string
+	Epic      string
+	Target    string
+	Labels    []string
+	OlderThan time.Duration
+	Columns   []string
+	Sort      string
+	Reverse   bool
+}
+
+// mqListFlags returns the query the gt mq list flags ask for, without a view.
+func mqListFlags() *config.MQListViewConfig {
+	flags := &config.MQListViewConfig{
+		Ready:     mqListReady,
+		Status:    mqListStatus,
+		Worker:    mqListWorker,
+		Epic:      mqListEpic,
+		Target:    mqListTarget,
+		Labels:    mqListLabels,
+		OlderThan: mqListOlderThan,
+		Sort:      mqListSort,
+		Reverse:   mqListReverse,
+	}
+	if mqListColumnsFlag != "" {
+		flags.Columns = strings.Split(mqListColumnsFlag, ",")
+	}
+	return flags
+}
+
+// loadMQListView returns the rig's view called name, or the built-in one.
+func loadMQListView(rigPath, name string) (*config.MQListViewConfig, error) {
+	views := make(map[string]*config.MQListViewConfig)
+	for n, v := range builtinMQListViews {
+		views[n] = v
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("loading rig settings: %w", err)
+	}
+	if err == nil && settings.MergeQueue != nil {
+		for n, v := range settings.MergeQueue.Views {
+			if v != nil {
+				views[n] = v
+			}
+		}
+	}
+
+	if view, ok := views[name]; ok {
+		return view, nil
+	}
+	names := make([]string, 0, len(views))
+	for n := range views {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown view %q (have: %s)", name, strings.Join(names, ", "))
+}
+
+// newMQListQuery combines a view (nil for none) with flags, which win
+// where set, and validates the result. me is who "@me" stands for.
+func newMQListQuery(view, flags *config.MQListViewConfig, me string) (*mqListQuery, error) {
+	if view == nil {
+		view = &config.MQListViewConfig{}
+	}
+	pick := func(flag, fromView string) string {
+		if flag != "" {
+			return flag
+		}
+		return fromView
+	}
+	q := &mqListQuery{
+		Ready:   flags.Ready || view.Ready,
+		Reverse: flags.Reverse || view.Reverse,
+		Status:  pick(flags.Status, view.Status),
+		Worker:  pick(flags.Worker, view.Worker),
+		Epic:    pick(flags.Epic, view.Epic),
+		Target:  pick(flags.Target, view.Target),
+		Sort:    pick(flags.Sort, view.Sort),
+	}
+
+	labels := view.Labels
+	if len(flags.Labels) > 0 {
+		labels = flags.Labels
+	}
+	var err error
+	if q.Labels, err = beads.NormalizeLabels(labels); err != nil {
+		return nil, fmt.Errorf("label: %w", err)
+	}
+
+	if olderThan := pick(flags.OlderThan, view.OlderThan); olderThan != "" {
+		if q.OlderThan, err = parseDuration(olderThan); err != nil || q.OlderThan <= 0 {
+			return nil, fmt.Errorf("invalid age %q (want e.g. 4h or 2d)", olderThan)
+		}
+	}
+
+	if q.Worker == mqListMe {
+		if q.Worker = me; q.Worker == "" {
+			return nil, fmt.Errorf("can't tell who you are for %s", mqListMe)
+		}
+	}
+
+	if q.Sort == "" {
+		q.Sort = "queue"
+	} else if !slices.Contains(mqListSorts, q.Sort) {
+		return nil, fmt.Errorf("unknown sort %q (have: %s)", q.Sort, strings.Join(mqListSorts, ", "))
+	}
+
+	columns := view.Columns
+	if len(flags.Columns) > 0 {
+		columns = flags.Columns
+	}
+	for _, c := range columns {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !slices.Contains(mqListColumns, c) {
+			return nil, fmt.Errorf("unknown column %q (have: %s)", c, strings.Join(mqListColumns, ", "))
+		}
+		if !slices.Contains(q.Columns, c) {
+			q.Columns = append(q.Columns, c)
+		}
+	}
+	if len(q.Columns) == 0 {
+		q.Columns = mqListDefaultColumns
+	}
+	return q, nil
+}
+
+// matchesWorker reports whether an MR by worker passes the worker filter.
+// A worker may be recorded by name ("Nux") or address
+// ("greenplace/polecats/Nux"); a name matches an address ending in it.
+func (q *mqListQuery) matchesWorker(worker string) bool {
+	switch {
+	case q.Worker == "" || strings.EqualFold(worker, q.Worker):
+		return true
+	case !strings.Contains(q.Worker, "/"):
+		return strings.EqualFold(path.Base(worker), q.Worker)
+	case !strings.Contains(worker, "/"):
+		return worker != "" && strings.EqualFold(worker, path.Base(q.Worker))
+	}
+	return false
+}
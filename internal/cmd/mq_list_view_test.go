@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestNewMQListQuery(t *testing.T) {
+	view := &config.MQListViewConfig{
+		Status:    "in_progress",
+		Worker:    mqListMe,
+		Labels:    []string{"infra"},
+		OlderThan: "2d",
+		Columns:   []string{"id", "branch", "age"},
+		Sort:      "age",
+	}
+
+	q, err := newMQListQuery(view, &config.MQListViewConfig{}, "greenplace/polecats/Nux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Status != "in_progress" || q.Worker != "greenplace/polecats/Nux" || q.OlderThan != 48*time.Hour || q.Sort != "age" {
+		t.Errorf("query from view = %+v", q)
+	}
+	if !slices.Equal(q.Columns, []string{"id", "branch", "age"}) || !slices.Equal(q.Labels, []string{"infra"}) {
+		t.Errorf("columns %q, labels %q", q.Columns, q.Labels)
+	}
+
+	// Flags override the view
+	flags := &config.MQListViewConfig{Worker: "Toast", Columns: []string{"ID", " risk"}, Labels: []string{"urgent"}}
+	if q, err = newMQListQuery(view, flags, "greenplace/polecats/Nux"); err != nil {
+		t.Fatal(err)
+	}
+	if q.Worker != "Toast" || !slices.Equal(q.Columns, []string{"id", "risk"}) || !slices.Equal(q.Labels, []string{"urgent"}) {
+		t.Errorf("query with flags = %+v", q)
+	}
+
+	// No view, no flags: the defaults
+	if q, err = newMQListQuery(nil, &config.MQListViewConfig{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if q.Sort != "queue" || q.Reverse || !slices.Equal(q.Columns, mqListDefaultColumns) {
+		t.Errorf("default query = %+v", q)
+	}
+
+	bad := []*config.MQListViewConfig{
+		{Columns: []string{"id", "owner"}},
+		{Sort: "name"},
+		{OlderThan: "soon"},
+		{Labels: []string{"gt:merge-request"}},
+		{Worker: mqListMe},
+	}
+	for _, flags := range bad {
+		if _, err := newMQListQuery(nil, flags, ""); err == nil {
+			t.Errorf("newMQListQuery(%+v) should fail", flags)
+		}
+	}
+}
+
+func TestLoadMQListView(t *testing.T) {
+	rigPath := t.TempDir()
+	if view, err := loadMQListView(rigPath, "mine"); err != nil || view.Worker != mqListMe {
+		t.Fatalf("built-in mine = %+v, %v", view, err)
+	}
+
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"rig-settings","version":1,"merge_queue":{"enabled":true,"views":{"stuck":{"status":"in_progress","older_than":"4h"}}}}`
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	view, err := loadMQListView(rigPath, "stuck")
+	if err != nil || view.Status != "in_progress" || view.OlderThan != "4h" {
+		t.Fatalf("stuck = %+v, %v", view, err)
+	}
+	if _, err := loadMQListView(rigPath, "nope"); err == nil || err.Error() != `unknown view "nope" (have: mine, stuck)` {
+		t.Errorf("loadMQListView(nope) = %v", err)
+	}
+}
+
+func TestMQListQueryMatchesWorker(t *testing.T) {
+	tests := []struct {
+		filter, worker string
+		want           bool
+	}{
+		{"", "Nux", true},
+		{"nux", "Nux", true},
+		{"Nux", "greenplace/polecats/Nux", true},
+		{"greenplace/polecats/Nux", "Nux", true},
+		{"greenplace/polecats/Nux", "greenplace/polecats/Nux/", false},
+		{"greenplace/crew/joe", "greenplace/polecats/Nux", false},
+		{"Nux", "", false},
+		{"greenplace/polecats/Nux", "", false},
+	}
+	for _, tt := range tests {
+		q := &mqListQuery{Worker: tt.filter}
+		if got := q.matchesWorker(tt.worker); got != tt.want {
+			t.Errorf("matchesWorker(%q) with filter %q = %v, want %v", tt.worker, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestSortMQListRows(t *testing.T) {
+	row := func(id, status string, priority int, created, worker string, risk int) mqListRow {
+		r := mqListRow{
+			issue:  &beads.Issue{ID: id, Status: "open", Priority: priority, CreatedAt: created},
+			fields: &beads.MRFields{Worker: worker},
+			status: status,
+		}
+		if risk >= 0 {
+			r.risk = &refinery.RiskAssessment{Score: risk}
+		}
+		return r
+	}
+	merging := row("merging", "merging", 3, "2026-01-05T00:00:00Z", "Toast", 10)
+	merging.issue.Status = "in_progress"
+	rows := []mqListRow{
+		row("blocked", "blocked", 0, "2026-01-01T00:00:00Z", "Nux", 80),
+		row("review", "review", 1, "2026-01-02T00:00:00Z", "Toast", -1),
+		row("ready", "ready", 2, "2026-01-03T00:00:00Z", "ace", 40),
+		merging,
+		row("held", "held", 2, "2026-01-04T00:00:00Z", "Nux", 20),
+	}
+	ids := func() []string {
+		var out []string
+		for _, r := range rows {
+			out = append(out, r.issue.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		by      string
+		reverse bool
+		want    []string
+	}{
+		{"queue", false, []string{"merging", "ready", "held", "review", "blocked"}},
+		{"priority", false, []string{"blocked", "review", "ready", "held", "merging"}},
+		{"age", false, []string{"blocked", "review", "ready", "held", "merging"}},
+		{"age", true, []string{"merging", "held", "ready", "review", "blocked"}},
+		{"worker", false, []string{"ready", "held", "blocked", "merging", "review"}},
+		{"risk", false, []string{"blocked", "ready", "held", "merging", "review"}},
+	}
+	for _, tt := range tests {
+		sortMQListRows(rows, "queue", false)
+		sortMQListRows(rows, tt.by, tt.reverse)
+		if got := ids(); !slices.Equal(got, tt.want) {
+			t.Errorf("sort %s (reverse %v) = %q, want %q", tt.by, tt.reverse, got, tt.want)
+		}
+	}
+}
+
+func TestMQListTabular(t *testing.T) {
+	targets, err := refinery.NewTargets("main", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []mqListRow{{
+		issue:  &beads.Issue{ID: "gp-mr-abcdefghijkl", Priority: 1, CreatedAt: "2026-03-01T10:00:00+01:00", Labels: []string{"gt:merge-request", "infra", "urgent"}},
+		fields: &beads.MRFields{Branch: "polecat/Nux/gp-1", Worker: "Nux"},
+		score:  1234.56,
+		risk:   &refinery.RiskAssessment{Score: 42},
+		status: "ready",
+	}}
+	table := mqListTabular([]string{"id", "pri", "risk", "target", "status", "age", "labels"}, rows, targets)
+	if !slices.Equal(table.Header, []string{"id", "priority", "risk", "target", "status", "created_at", "labels"}) {
+		t.Errorf("header = %q", table.Header)
+	}
+	want := []string{"gp-mr-abcdefghijkl", "1", "42", "main", "ready", "2026-03-01T09:00:00Z", "infra;urgent"}
+	if len(table.Rows) != 1 || !slices.Equal(table.Rows[0], want) {
+		t.Errorf("rows = %q, want %q", table.Rows, want)
+	}
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -14,7 +16,8 @@ import (
 var (
 	mqNextStrategy string // "priority" (default) or "fifo"
 	mqNextJSON     bool
-	mqNextQuiet    bool
+	mqNextIDOnly   bool
+	mqNextLabels   []string
 )
 
 var mqNextCmd = &cobra.Command{
@@ -28,13 +31,20 @@ The priority scoring function considers:
   - Retry count: MRs that fail repeatedly get deprioritized
   - MR age: FIFO tiebreaker for same priority/convoy
 
+The rig's merge_queue.fairness settings can add round-robin across
+workers, priority aging, and a per-worker in-flight limit.
+
 Use --strategy=fifo for first-in-first-out ordering instead.
 
+--label considers only MRs with the label (repeat it to require several),
+so a processor can be dedicated to, say, infra MRs.
+
 Examples:
   gt mq next gastown                    # Show highest-priority MR
   gt mq next gastown --strategy=fifo    # Show oldest MR instead
-  gt mq next gastown --quiet            # Just print the MR ID
-  gt mq next gastown --json             # Output as JSON`,
+  gt mq next gastown --id-only          # Just print the MR ID
+  gt mq next gastown --json             # Output as JSON
+  gt mq next gastown --label infra      # Highest-priority infra MR`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMQNext,
 }
@@ -42,19 +52,30 @@ Examples:
 func init() {
 	mqNextCmd.Flags().StringVar(&mqNextStrategy, "strategy", "priority", "Ordering strategy: 'priority' or 'fifo'")
 	mqNextCmd.Flags().BoolVar(&mqNextJSON, "json", false, "Output as JSON")
-	mqNextCmd.Flags().BoolVarP(&mqNextQuiet, "quiet", "q", false, "Just print the MR ID")
+	mqNextCmd.Flags().BoolVar(&mqNextIDOnly, "id-only", false, "Just print the MR ID")
+	legacyQuietFlag(mqNextCmd, &mqNextIDOnly, "Just print the MR ID",
+		"use --id-only; -q will hide all output in a future release")
+	mqNextCmd.Flags().StringSliceVarP(&mqNextLabels, "label", "l", nil, "Only consider MRs with this label (repeatable: all must match)")
 
 	mqCmd.AddCommand(mqNextCmd)
 }
 
 func runMQNext(cmd *cobra.Command, args []string) error {
 	rigName := args[0]
+	labels, err := beads.NormalizeLabels(mqNextLabels)
+	if err != nil {
+		return fmt.Errorf("--label: %w", err)
+	}
 
 	_, r, _, err := getRefineryManager(rigName)
 	if err != nil {
 		return err
 	}
 
+	if reason := mergeQueuePausedReason(r); reason != "" {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is paused (%s)", rigName, reason))
+	}
+
 	// Create beads wrapper for the rig
 	b := beads.New(r.BeadsPath())
 
@@ -65,7 +86,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		Priority: -1, // No priority filter
 	}
 
-	issues, err := b.List(opts)
+	issues, err := b.ListIndexed(opts)
 	if err != nil {
 		return fmt.Errorf("querying merge queue: %w", err)
 	}
@@ -74,7 +95,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	var ready []*beads.Issue
 	for _, issue := range issues {
 		// Skip closed MRs (workaround for bd list not respecting --status filter)
-		if issue.Status != "open" {
+		if issue.Status != "open" || !beads.HasAllLabels(issue, labels) {
 			continue
 		}
 		if len(issue.BlockedBy) == 0 && issue.BlockedByCount == 0 {
@@ -82,8 +103,23 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// MRs awaiting sign-off are not ready for the refinery
+	ready, err = filterAwaitingReview(r.Path, ready)
+	if err != nil {
+		return err
+	}
+
+	// Honor the rig's merge schedule (quiet hours, merge windows)
+	ready, closedReason, err := filterBySchedule(r.Path, ready, func(i *beads.Issue) int { return i.Priority })
+	if err != nil {
+		return err
+	}
+	if closedReason != "" && len(ready) == 0 {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is paused (%s)", rigName, closedReason))
+	}
+
 	if len(ready) == 0 {
-		if mqNextQuiet {
+		if mqNextIDOnly {
 			return nil // Silent exit
 		}
 		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
@@ -91,6 +127,10 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	now := time.Now()
+	fairness, err := refinery.LoadFairness(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge fairness: %w", err)
+	}
 
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
@@ -100,6 +140,23 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 			tj, _ := time.Parse(time.RFC3339, ready[j].CreatedAt)
 			return ti.Before(tj)
 		})
+	} else if fairness != nil {
+		// Priority, with the rig's fairness policy across workers
+		ordered, _, err := orderByFairness(r, fairness, ready, func(issue *beads.Issue) refinery.QueueEntry {
+			fields := beads.ParseMRFields(issue)
+			return mrQueueEntry(issue, fields, calculateMRScore(issue, fields, now))
+		}, now)
+		if err != nil {
+			return err
+		}
+		if len(ordered) == 0 {
+			if mqNextIDOnly {
+				return nil
+			}
+			fmt.Printf("%s No claimable merge requests: every ready MR's worker is at its in-flight limit\n", style.Dim.Render("ℹ"))
+			return nil
+		}
+		ready = ordered
 	} else {
 		// Priority: highest score first
 		type scoredIssue struct {
@@ -128,7 +185,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	fields := beads.ParseMRFields(next)
 
 	// Output based on format flags
-	if mqNextQuiet {
+	if mqNextIDOnly {
 		fmt.Println(next.ID)
 		return nil
 	}
@@ -161,6 +218,9 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if shown := beads.DisplayLabels(next); len(shown) > 0 {
+		fmt.Printf("  Labels:   %s\n", strings.Join(shown, ", "))
+	}
 	fmt.Printf("  Age:      %s\n", formatMRAge(next.CreatedAt))
 
 	if len(ready) > 1 {
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Pause command flags
+var mqPauseReason string
+
+var mqPauseCmd = &cobra.Command{
+	Use:   "pause <rig>",
+	Short: "Hold merges in a rig's merge queue",
+	Long: `Freeze a rig's merge queue: the refinery keeps running but takes no MR
+until the queue is resumed. Submissions still queue up.
+
+Examples:
+  gt mq pause greenplace
+  gt mq pause greenplace --reason "main is red"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQPause,
+}
+
+var mqResumeCmd = &cobra.Command{
+	Use:   "resume <rig>",
+	Short: "Resume merges in a paused merge queue",
+	Long: `Unfreeze a rig's merge queue paused with 'gt mq pause'. A queue frozen
+by a release in progress is left to 'gt release' to unfreeze.
+
+Examples:
+  gt mq resume greenplace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQResume,
+}
+
+func init() {
+	mqPauseCmd.Flags().StringVarP(&mqPauseReason, "reason", "r", "", "Why the queue is paused")
+
+	mqCmd.AddCommand(mqPauseCmd)
+	mqCmd.AddCommand(mqResumeCmd)
+}
+
+func runMQPause(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	freeze := wisp.NewConfig(townRoot, r.Name)
+	if existing := freeze.GetString(MergeQueueFreezeKey); existing != "" {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is already frozen (%s)", r.Name, existing))
+	}
+	reason := "pause"
+	if mqPauseReason != "" {
+		reason = "pause: " + mqPauseReason
+	}
+	if err := freeze.Set(MergeQueueFreezeKey, reason); err != nil {
+		return fmt.Errorf("pausing merge queue: %w", err)
+	}
+	fmt.Printf("%s Paused merge queue for %s\n", style.Bold.Render("⏸"), r.Name)
+	return nil
+}
+
+func runMQResume(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	freeze := wisp.NewConfig(townRoot, r.Name)
+	existing := freeze.GetString(MergeQueueFreezeKey)
+	if existing == "" {
+		fmt.Printf("%s Merge queue for %s is not paused\n", style.Dim.Render("ℹ"), r.Name)
+		return nil
+	}
+	if strings.HasPrefix(existing, "release ") {
+		return fmt.Errorf("merge queue for rig '%s' is frozen for %s; it resumes when the release finishes", r.Name, existing)
+	}
+	if err := freeze.Unset(MergeQueueFreezeKey); err != nil {
+		return fmt.Errorf("resuming merge queue: %w", err)
+	}
+	fmt.Printf("%s Resumed merge queue for %s\n", style.Bold.Render("▶"), r.Name)
+	return nil
+}
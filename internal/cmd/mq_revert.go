@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Revert command flags
+var (
+	mqRevertRig      string
+	mqRevertReason   string
+	mqRevertNoReopen bool
+)
+
+var mqRevertCmd = &cobra.Command{
+	Use:   "revert <merge-commit|mr-id>",
+	Short: "Back out a merged MR through the queue",
+	Long: `Revert a previously merged merge request.
+
+The MR can be named by its ID or by its merge commit (a SHA prefix of at
+least 7 characters). gt mq revert then:
+  1. Creates branch revert/<mr-id> from origin's target branch and commits
+     a revert of the merge commit on it (in the refinery's clone)
+  2. Submits the branch to the merge queue at P0, as an MR for the original
+     source issue with "reverts: <mr-id>"
+  3. Records "reverted_by: <revert-mr>" on the original MR
+  4. Reopens the source issue so the work can be redone
+  5. Notifies the original worker
+
+The revert goes through the queue like any other MR, so it is tested and
+protected the same way. If the revert doesn't apply cleanly (later work
+depends on the change), nothing is created; revert by hand instead.
+
+Examples:
+  gt mq revert gp-mr-abc123
+  gt mq revert 1a2b3c4d --rig greenplace
+  gt mq revert gp-mr-abc123 --reason "broke login on Safari"
+  gt mq revert gp-mr-abc123 --no-reopen -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQRevert,
+}
+
+func init() {
+	mqRevertCmd.Flags().StringVar(&mqRevertRig, "rig", "", "Rig the MR belongs to (default: infer from cwd)")
+	mqRevertCmd.Flags().StringVarP(&mqRevertReason, "reason", "r", "", "Why the MR is being reverted (added to the revert MR and the reopened issue)")
+	mqRevertCmd.Flags().BoolVar(&mqRevertNoReopen, "no-reopen", false, "Leave the source issue closed")
+
+	mqCmd.AddCommand(mqRevertCmd)
+}
+
+// MRRevertOutput is the structured output for gt mq revert.
+type MRRevertOutput struct {
+	ID          string `json:"id"`
+	Reverts     string `json:"reverts"`
+	MergeCommit string `json:"merge_commit"`
+	Branch      string `json:"branch"`
+	Target      string `json:"target"`
+	SourceIssue string `json:"source_issue,omitempty"`
+	Reopened    bool   `json:"reopened"`
+}
+
+func runMQRevert(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(mqRevertRig)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+
+	original, err := findMergedMR(bd, args[0])
+	if err != nil {
+		return err
+	}
+	if err := requirePermission(filepath.Dir(r.Path), permission.ActionMQRevert, original.ID, nil); err != nil {
+		return err
+	}
+
+	fields := beads.ParseMRFields(original)
+	if fields.RevertedBy != "" {
+		return fmt.Errorf("%s was already reverted by %s", original.ID, fields.RevertedBy)
+	}
+
+	branch := "revert/" + original.ID
+	eng := refinery.NewEngineer(r)
+	if err := eng.CreateRevertBranch(branch, fields.MergeCommit, fields.Target); err != nil {
+		if errors.Is(err, refinery.ErrBranchExists) {
+			return fmt.Errorf("%s already exists on origin; a revert of %s may already be queued", branch, original.ID)
+		}
+		return fmt.Errorf("creating revert branch: %w", err)
+	}
+
+	// Submit the revert at P0, linked to the original MR and its source issue
+	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s\nstate: %s\nreverts: %s",
+		branch, fields.Target, fields.SourceIssue, rigName, refinery.StateQueued, original.ID)
+	reviewers := assignMRReviewers(bd, r.Path, rigName, "")
+	if len(reviewers) > 0 {
+		description += "\nreviewers: " + strings.Join(reviewers, ",")
+	}
+	if mqRevertReason != "" {
+		description += "\n\nReason: " + mqRevertReason
+	}
+	revertMR, err := bd.Create(beads.CreateOptions{
+		Title:       revertTitle(original, fields),
+		Type:        "merge-request",
+		Priority:    0,
+		Description: description,
+		Actor:       detectSender(),
+		Ephemeral:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("creating revert merge request bead: %w", err)
+	}
+
+	payload := events.MRPayload(rigName, original.ID, fields.SourceIssue, fields.Branch, mqRevertReason)
+	payload["revert_mr"] = revertMR.ID
+	_ = events.LogFeed(events.TypeMRReverted, detectSender(), payload)
+
+	// Link the original MR back to its revert
+	fields.RevertedBy = revertMR.ID
+	desc := beads.SetMRFields(original, fields)
+	if err := bd.Update(original.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		style.PrintWarning("could not record reverted_by on %s: %v", original.ID, err)
+	}
+
+	reopened := false
+	if fields.SourceIssue != "" && !mqRevertNoReopen {
+		note := fmt.Sprintf("Reopened: merge %s reverted by %s", original.ID, revertMR.ID)
+		if mqRevertReason != "" {
+			note += " (" + mqRevertReason + ")"
+		}
+		if err := bd.ReleaseWithReason(fields.SourceIssue, note); err != nil {
+			style.PrintWarning("could not reopen %s: %v", fields.SourceIssue, err)
+		} else {
+			reopened = true
+		}
+	}
+
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" && len(reviewers) > 0 {
+		notifyMRReviewers(townRoot, reviewers, revertMR.ID, branch, fields.SourceIssue)
+	}
+	body := fmt.Sprintf("Your merge %s (%s) is being reverted by %s.", original.ID, fields.SourceIssue, revertMR.ID)
+	if mqRevertReason != "" {
+		body += "\n\nReason: " + mqRevertReason
+	}
+	if reopened {
+		body += fmt.Sprintf("\n\n%s has been reopened.", fields.SourceIssue)
+	}
+	notifyMRWorker(fields, detectSender(), fmt.Sprintf("Reverted: %s", original.ID), body)
+
+	if structuredOutput(false) {
+		return renderStructured(MRRevertOutput{
+			ID:          revertMR.ID,
+			Reverts:     original.ID,
+			MergeCommit: fields.MergeCommit,
+			Branch:      branch,
+			Target:      fields.Target,
+			SourceIssue: fields.SourceIssue,
+			Reopened:    reopened,
+		})
+	}
+
+	fmt.Printf("%s Submitted revert of %s to merge queue\n", style.Bold.Render("✓"), original.ID)
+	fmt.Printf("  MR ID: %s\n", style.Bold.Render(revertMR.ID))
+	fmt.Printf("  Reverts: %s (%s)\n", original.ID, shortSHA(fields.MergeCommit))
+	fmt.Printf("  Source: %s\n", branch)
+	fmt.Printf("  Target: %s\n", fields.Target)
+	fmt.Printf("  Priority: P0\n")
+	if len(reviewers) > 0 {
+		fmt.Printf("  Reviewers: %s\n", strings.Join(reviewers, ", "))
+	}
+	if reopened {
+		fmt.Printf("  Reopened: %s\n", fields.SourceIssue)
+	}
+	return nil
+}
+
+// findMergedMR resolves ref to a merged MR, by bead ID or by a prefix of
+// its merge commit.
+func findMergedMR(bd *beads.Beads, ref string) (*beads.Issue, error) {
+	issue, err := bd.Show(ref)
+	if err != nil && err != beads.ErrNotFound {
+		return nil, fmt.Errorf("fetching merge request: %w", err)
+	}
+	if issue == nil && len(ref) >= 7 && isHexString(ref) {
+		if issue, err = findMRByMergeCommit(bd, ref); err != nil {
+			return nil, err
+		}
+	}
+	if issue == nil {
+		return nil, withExitCode(ExitMRNotFound, fmt.Errorf("no merge request or merged commit '%s' found", ref))
+	}
+
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil, fmt.Errorf("%s is not a merge request (no MR fields)", issue.ID)
+	}
+	if fields.MergeCommit == "" {
+		return nil, fmt.Errorf("%s has not been merged (no merge_commit)", issue.ID)
+	}
+	if fields.Target == "" {
+		return nil, fmt.Errorf("%s has no target branch", issue.ID)
+	}
+	return issue, nil
+}
+
+// findMRByMergeCommit returns the merged MR whose merge commit starts with
+// sha, or nil if there is none.
+func findMRByMergeCommit(bd *beads.Beads, sha string) (*beads.Issue, error) {
+	issues, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "closed", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("querying merged MRs: %w", err)
+	}
+	var found *beads.Issue
+	for _, candidate := range issues {
+		if f := beads.ParseMRFields(candidate); f != nil && f.MergeCommit != "" &&
+			(strings.HasPrefix(f.MergeCommit, sha) || strings.HasPrefix(sha, f.MergeCommit)) {
+			if found != nil {
+				return nil, fmt.Errorf("merge commit %s is ambiguous (%s, %s)", sha, found.ID, candidate.ID)
+			}
+			found = candidate
+		}
+	}
+	return found, nil
+}
+
+// revertTitle names a revert MR after what it reverts, e.g. "Revert: gt-abc".
+func revertTitle(original *beads.Issue, fields *beads.MRFields) string {
+	if fields.SourceIssue != "" {
+		return "Revert: " + fields.SourceIssue
+	}
+	return "Revert: " + strings.TrimPrefix(original.Title, "Merge: ")
+}
+
+// isHexString reports whether s is a non-empty hex string (a SHA prefix).
+func isHexString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestIsHexString(t *testing.T) {
+	for s, want := range map[string]bool{
+		"1a2b3c4d": true,
+		"ABCDEF0":  true,
+		"gp-mr-1a": false,
+		"":         false,
+	} {
+		if got := isHexString(s); got != want {
+			t.Errorf("isHexString(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestRevertTitle(t *testing.T) {
+	mr := &beads.Issue{ID: "gp-mr-1", Title: "Merge: gp-abc"}
+	if got := revertTitle(mr, &beads.MRFields{SourceIssue: "gp-xyz"}); got != "Revert: gp-xyz" {
+		t.Errorf("revertTitle with source issue = %q", got)
+	}
+	if got := revertTitle(mr, &beads.MRFields{}); got != "Revert: gp-abc" {
+		t.Errorf("revertTitle without source issue = %q", got)
+	}
+}
@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Review command flags
+var (
+	mqApproveComment        string
+	mqRequestChangesSummary string
+	mqRequestChangesFile    string
+	mqRequestChangesLine    int
+)
+
+var mqApproveCmd = &cobra.Command{
+	Use:   "approve <mr-id>",
+	Short: "Approve a merge request",
+	Long: `Record your approval on a merge request.
+
+The approval is stored on the MR bead under approved_by, using your
+address (mayor, overseer for a human, <rig>/crew/<name>, ...). Approving
+clears any earlier request for changes from you; an MR sent back for
+changes is queued again once no one's request is outstanding.
+
+Rigs that set merge_queue.protection.min_approvals hold MRs out of the
+refinery's queue until enough approvals from the allowed approval_roles
+are recorded. MRs touching paths in merge_queue.owners also need an
+approval from one owner of each. Workers cannot approve their own MRs,
+whatever their role.
+
+Your address comes from GT_ROLE (or BD_ACTOR, or the directory you run
+in), which anyone can set. Unless the town authenticates who runs gt,
+treat approvals as advisory: they record who says they approved, not
+proof of it.
+
+Examples:
+  gt mq approve gp-mr-abc123
+  gt mq approve gp-mr-abc123 --comment "LGTM"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQApprove,
+}
+
+var mqRequestChangesCmd = &cobra.Command{
+	Use:   "request-changes <mr-id>",
+	Short: "Send a merge request back to its worker for changes",
+	Long: `Send a merge request back to its worker with the changes to make.
+
+Records you under changes_requested_by on the MR bead, withdraws any
+approval you gave, and moves the MR to the changes_requested state, which
+the refinery skips. The summary goes on the MR's discussion thread,
+anchored to --file (and --line) if given, and into a follow-up task bead
+assigned to the worker. The worker is notified.
+
+When the worker has pushed fixes, resubmitting the branch (gt mq submit,
+or gt done) queues the MR again, closes the follow-up tasks, and asks the
+reviewers who requested changes to look again.
+
+Examples:
+  gt mq request-changes gp-mr-abc123 --summary "Missing tests for the retry path"
+  gt mq request-changes gp-mr-abc123 -s "Cap retries at 5" --file internal/retry.go --line 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQRequestChanges,
+}
+
+func init() {
+	mqApproveCmd.Flags().StringVarP(&mqApproveComment, "comment", "m", "", "Optional comment sent to the worker")
+	mqRequestChangesCmd.Flags().StringVarP(&mqRequestChangesSummary, "summary", "s", "", "What needs to change (required)")
+	mqRequestChangesCmd.Flags().StringVarP(&mqRequestChangesSummary, "reason", "r", "", "Same as --summary")
+	mqRequestChangesCmd.Flags().StringVar(&mqRequestChangesFile, "file", "", "File the changes are needed in (path from the repository root)")
+	mqRequestChangesCmd.Flags().IntVar(&mqRequestChangesLine, "line", 0, "Line of --file the changes are needed at, in the MR's version")
+	mqRequestChangesCmd.MarkFlagsOneRequired("summary", "reason")
+	mqRequestChangesCmd.MarkFlagsMutuallyExclusive("summary", "reason")
+	mqRequestChangesCmd.MarkFlagsRequiredTogether("line", "file")
+
+	mqCmd.AddCommand(mqApproveCmd)
+	mqCmd.AddCommand(mqRequestChangesCmd)
+}
+
+func runMQApprove(cmd *cobra.Command, args []string) error {
+	reviewer := strings.TrimSuffix(detectSender(), "/")
+
+	bd, issue, fields, err := loadMRForReview(args[0])
+	if err != nil {
+		return err
+	}
+	if isOwnMR(reviewer, fields) {
+		return fmt.Errorf("cannot approve your own merge request")
+	}
+
+	fields.ApprovedBy = mergeMRList(fields.ApprovedBy, []string{reviewer})
+	fields.ChangesRequestedBy = removeMRListItem(fields.ChangesRequestedBy, reviewer)
+	if refinery.StateOf(issue) == refinery.StateChangesRequested && fields.ChangesRequestedBy == "" {
+		// No one is waiting on changes any more
+		err = requeueMR(bd, issue, fields, "approved in "+issue.ID)
+	} else {
+		err = saveMRReview(bd, issue, fields)
+	}
+	if err != nil {
+		return err
+	}
+
+	_ = events.LogFeed(events.TypeMRApproved, reviewer,
+		events.MRPayload(fields.Rig, issue.ID, fields.SourceIssue, fields.Branch, mqApproveComment))
+
+	fmt.Printf("%s Approved %s as %s\n", style.Bold.Render("✓"), issue.ID, reviewer)
+	printMRReviewState(fields)
+	if mqApproveComment != "" {
+		notifyMRWorker(fields, reviewer, "Merge request approved",
+			fmt.Sprintf("%s approved %s (%s).\n\n%s", reviewer, issue.ID, fields.Branch, mqApproveComment))
+	}
+	return nil
+}
+
+func runMQRequestChanges(cmd *cobra.Command, args []string) error {
+	reviewer := strings.TrimSuffix(detectSender(), "/")
+
+	bd, issue, fields, err := loadMRForReview(args[0])
+	if err != nil {
+		return err
+	}
+	if err := refinery.ValidateStateTransition(refinery.StateOf(issue), refinery.StateChangesRequested); err != nil {
+		return err
+	}
+
+	thread, comment, err := refinery.AddMRComment(fields.Comments, refinery.MRComment{
+		Author: reviewer,
+		Body:   mqRequestChangesSummary,
+		File:   mqRequestChangesFile,
+		Line:   mqRequestChangesLine,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// The worker's follow-up task. Best-effort: the request for changes
+	// holds the MR either way.
+	task, err := createChangesTask(bd, issue, fields, reviewer, comment)
+	if err != nil {
+		style.PrintWarning("could not create follow-up task: %v", err)
+	}
+
+	fields.Comments = thread
+	fields.ChangesRequestedBy = mergeMRList(fields.ChangesRequestedBy, []string{reviewer})
+	fields.ApprovedBy = removeMRListItem(fields.ApprovedBy, reviewer)
+	if task != nil {
+		fields.ChangesTasks = mergeMRList(fields.ChangesTasks, []string{task.ID})
+	}
+	if err := saveMRReview(bd, issue, fields); err != nil {
+		return err
+	}
+	if _, err := refinery.RecordState(bd, issue, refinery.StateChangesRequested, ""); err != nil {
+		return err
+	}
+
+	payload := events.MRPayload(fields.Rig, issue.ID, fields.SourceIssue, fields.Branch, comment.Body)
+	if task != nil {
+		payload["task"] = task.ID
+	}
+	_ = events.LogFeed(events.TypeMRChangesRequested, reviewer, payload)
+
+	fmt.Printf("%s Requested changes on %s as %s\n", style.Bold.Render("✗"), issue.ID, reviewer)
+	fmt.Printf("  Summary: %s\n", comment.Body)
+	if loc := comment.Location(); loc != "" {
+		fmt.Printf("  At: %s\n", loc)
+	}
+	if task != nil {
+		fmt.Printf("  Follow-up task: %s\n", task.ID)
+	}
+	printMRReviewState(fields)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s requested changes on %s.\n\n", reviewer, issue.ID)
+	fmt.Fprintf(&b, "Branch: %s\nIssue: %s\n", fields.Branch, fields.SourceIssue)
+	if task != nil {
+		fmt.Fprintf(&b, "Task: %s\n", task.ID)
+	}
+	if loc := comment.Location(); loc != "" {
+		fmt.Fprintf(&b, "At: %s\n", loc)
+	}
+	fmt.Fprintf(&b, "\n%s\n\nThe refinery holds the MR until you push fixes to the same branch and\nresubmit it with gt mq submit.", comment.Body)
+	notifyMRWorker(fields, reviewer, "Changes requested on merge request", b.String())
+	return nil
+}
+
+// createChangesTask creates the worker's follow-up task for a request for
+// changes on an MR, assigned to the worker if the MR has one.
+func createChangesTask(bd *beads.Beads, issue *beads.Issue, fields *beads.MRFields, reviewer string, comment refinery.MRComment) (*beads.Issue, error) {
+	summary, _, _ := strings.Cut(comment.Body, "\n")
+	task, err := bd.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Changes requested on %s: %s", issue.ID, truncateString(summary, 60)),
+		Type:        "task",
+		Priority:    issue.Priority,
+		Description: changesTaskDescription(issue.ID, fields, reviewer, comment),
+		Actor:       reviewer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fields.Worker != "" && fields.Rig != "" {
+		assignee := fmt.Sprintf("%s/polecats/%s", fields.Rig, fields.Worker)
+		if err := bd.Update(task.ID, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+			return task, fmt.Errorf("assigning %s to %s: %w", task.ID, assignee, err)
+		}
+	}
+	return task, nil
+}
+
+// changesTaskDescription builds the description of a follow-up task: the
+// MR, who asked, where, what to change, and how to hand the MR back.
+func changesTaskDescription(mrID string, fields *beads.MRFields, reviewer string, comment refinery.MRComment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Make the changes %s requested on %s\n\n", reviewer, mrID)
+	b.WriteString("## Metadata\n")
+	fmt.Fprintf(&b, "- MR: %s\n", mrID)
+	fmt.Fprintf(&b, "- Branch: %s\n", fields.Branch)
+	fmt.Fprintf(&b, "- Original issue: %s\n", fields.SourceIssue)
+	fmt.Fprintf(&b, "- Requested by: %s\n", reviewer)
+	if loc := comment.Location(); loc != "" {
+		fmt.Fprintf(&b, "- At: %s\n", loc)
+	}
+	fmt.Fprintf(&b, "\n## Requested Changes\n%s\n", comment.Body)
+	fmt.Fprintf(&b, `
+## Instructions
+1. Check out the branch: git checkout %s
+2. Make the changes (see also: gt mq status %s, gt mq diff %s --patch)
+3. Push to the same branch
+4. Resubmit: gt mq submit (this closes this task and queues the MR again)
+`, fields.Branch, mrID, mrID)
+	return b.String()
+}
+
+// resubmitMR queues an MR sent back for changes again, once its worker
+// has pushed fixes: it clears the requests for changes and asks the
+// reviewers who made them to look again.
+func resubmitMR(townRoot string, bd *beads.Beads, issue *beads.Issue) error {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", issue.ID)
+	}
+	reviewers := refinery.SplitMRList(fields.ChangesRequestedBy)
+	fields.Reviewers = mergeMRList(fields.Reviewers, reviewers)
+	fields.ChangesRequestedBy = ""
+	if err := requeueMR(bd, issue, fields, "resubmitted in "+issue.ID); err != nil {
+		return err
+	}
+
+	_ = events.LogFeed(events.TypeMRSubmitted, detectSender(),
+		events.MRPayload(fields.Rig, issue.ID, fields.SourceIssue, fields.Branch, "resubmitted"))
+	fmt.Printf("%s Resubmitted %s after requested changes\n", style.Bold.Render("✓"), issue.ID)
+	if len(reviewers) > 0 {
+		fmt.Printf("  Re-review: %s\n", strings.Join(reviewers, ", "))
+		notifyMRReviewers(townRoot, reviewers, issue.ID, fields.Branch, fields.SourceIssue)
+	}
+	return nil
+}
+
+// requeueMR moves an MR sent back for changes to the queue again and
+// closes its follow-up tasks with reason.
+func requeueMR(bd *beads.Beads, issue *beads.Issue, fields *beads.MRFields, reason string) error {
+	tasks := refinery.SplitMRList(fields.ChangesTasks)
+	fields.ChangesTasks = ""
+	if err := saveMRReview(bd, issue, fields); err != nil {
+		return err
+	}
+	if _, err := refinery.RecordState(bd, issue, refinery.StateQueued, ""); err != nil {
+		return err
+	}
+	if len(tasks) > 0 {
+		if err := bd.CloseWithReason(reason, tasks...); err != nil {
+			style.PrintWarning("could not close follow-up tasks %s: %v", strings.Join(tasks, ", "), err)
+		}
+	}
+	return nil
+}
+
+// loadMRForReview fetches an open MR bead and its fields from the current
+// directory's beads.
+func loadMRForReview(mrID string) (*beads.Beads, *beads.Issue, *beads.MRFields, error) {
+	bd, issue, fields, err := loadMRBead(mrID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if issue.Status == "closed" {
+		return nil, nil, nil, fmt.Errorf("merge request %s is already closed", mrID)
+	}
+	return bd, issue, fields, nil
+}
+
+// loadMRBead fetches an MR bead, open or closed, and its fields from the
+// current directory's beads.
+func loadMRBead(mrID string) (*beads.Beads, *beads.Issue, *beads.MRFields, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(workDir)
+
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return nil, nil, nil, withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found", mrID))
+		}
+		return nil, nil, nil, fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil, nil, nil, fmt.Errorf("%s is not a merge request (no MR fields)", mrID)
+	}
+	return bd, issue, fields, nil
+}
+
+// saveMRReview writes fields to the MR bead, and to issue's description.
+func saveMRReview(bd *beads.Beads, issue *beads.Issue, fields *beads.MRFields) error {
+	desc := beads.SetMRFields(issue, fields)
+	if err := bd.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording review: %w", err)
+	}
+	issue.Description = desc
+	return nil
+}
+
+// isOwnMR reports whether reviewer is the worker that submitted the MR,
+// whatever its role: any address in the MR's rig ending in the worker's
+// name, or the worker's own address.
+func isOwnMR(reviewer string, fields *beads.MRFields) bool {
+	if fields.Worker == "" {
+		return false
+	}
+	reviewer = strings.TrimSuffix(reviewer, "/")
+	if reviewer == strings.TrimSuffix(fields.Worker, "/") {
+		return true
+	}
+	parts := strings.Split(reviewer, "/")
+	if len(parts) < 2 || (fields.Rig != "" && parts[0] != fields.Rig) {
+		return false
+	}
+	return parts[len(parts)-1] == fields.Worker
+}
+
+func printMRReviewState(fields *beads.MRFields) {
+	if fields.ApprovedBy != "" {
+		fmt.Printf("  Approved by: %s\n", fields.ApprovedBy)
+	}
+	if fields.ChangesRequestedBy != "" {
+		fmt.Printf("  Changes requested by: %s\n", fields.ChangesRequestedBy)
+	}
+}
+
+// notifyMRWorker mails the MR's worker. Best-effort: review is already recorded.
+func notifyMRWorker(fields *beads.MRFields, from, subject, body string) {
+	if fields.Worker == "" || fields.Rig == "" {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	router := mail.NewRouter(townRoot)
+	msg := &mail.Message{
+		From:     from,
+		To:       fmt.Sprintf("%s/%s", fields.Rig, fields.Worker),
+		Subject:  subject,
+		Body:     body,
+		Priority: mail.PriorityNormal,
+	}
+	if err := router.Send(msg); err != nil {
+		style.PrintWarning("could not notify %s: %v", msg.To, err)
+	}
+}
+
+// filterAwaitingReview drops MRs that still need approvals or have changes
+// requested, per the rig's branch protection rules.
+func filterAwaitingReview(rigPath string, issues []*beads.Issue) ([]*beads.Issue, error) {
+	protection, err := refinery.LoadBranchProtection(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading branch protection: %w", err)
+	}
+	var reviewed []*beads.Issue
+	for _, issue := range issues {
+		review := refinery.ReviewFromFields(beads.ParseMRFields(issue))
+		if protection.AwaitingReview(review) == "" {
+			reviewed = append(reviewed, issue)
+		}
+	}
+	return reviewed, nil
+}
+
+// removeMRListItem drops item from a comma-separated MR field.
+func removeMRListItem(list, item string) string {
+	var kept []string
+	for _, existing := range refinery.SplitMRList(list) {
+		if existing != item {
+			kept = append(kept, existing)
+		}
+	}
+	return strings.Join(kept, ",")
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqSimulateCmd = &cobra.Command{
+	Use:   "simulate <rig>",
+	Short: "Project the order, conflicts and timing of the merge queue",
+	Long: `Project how the Refinery would work through the rig's merge queue,
+without touching it.
+
+The simulation takes the MRs the Refinery has claimed first, then the
+ready MRs in the order it would claim them (by priority score and the
+rig's fairness policy). It merges them one at a time: an MR that changes
+a file an earlier MR in the run merged is projected to conflict, and
+fails without merging. MRs that are blocked or awaiting review are listed
+as held.
+
+Each MR is estimated to take as long as the rig's checks usually do (the
+median of each check's recent runs), or, without a check history, as long
+as the tests of recently merged MRs took. The merge schedule's quiet
+hours and windows delay MRs they hold. A paused queue has no timeline.
+
+Examples:
+  gt mq simulate greenplace
+  gt mq simulate greenplace --time-format local
+  gt mq simulate greenplace -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQSimulate,
+}
+
+func init() {
+	mqCmd.AddCommand(mqSimulateCmd)
+}
+
+// MQSimulateOutput is the structured output for gt mq simulate.
+type MQSimulateOutput struct {
+	Rig            string             `json:"rig"`
+	Start          time.Time          `json:"start"`
+	Paused         string             `json:"paused,omitempty"`          // Why nothing will merge
+	MRDuration     time.Duration      `json:"mr_duration"`               // Estimated time per MR; 0 if unknown
+	DurationSource string             `json:"duration_source,omitempty"` // "check history" or "merged MRs"
+	Steps          []refinery.SimStep `json:"steps"`
+	Finish         *time.Time         `json:"finish,omitempty"` // When the last MR is done
+	Held           []MQSimulateHeld   `json:"held,omitempty"`
+	Skipped        map[string]string  `json:"skipped,omitempty"` // MR ID -> why its diff is unavailable
+}
+
+// MQSimulateHeld is an open MR the Refinery won't take yet.
+type MQSimulateHeld struct {
+	ID     string `json:"id"`
+	Branch string `json:"branch"`
+	Worker string `json:"worker,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func runMQSimulate(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Truncate(time.Second)
+	out := MQSimulateOutput{Rig: rigName, Start: now.UTC(), Steps: []refinery.SimStep{}}
+	out.Paused = mergeQueuePausedReason(r)
+
+	order, held, err := simulationOrder(r, now)
+	if err != nil {
+		return err
+	}
+	out.Held = held
+
+	eng := refinery.NewEngineer(r)
+	mrs := make([]refinery.SimMR, 0, len(order))
+	for _, mr := range order {
+		sim := refinery.SimMR{ID: mr.ID, Branch: mr.Branch, Target: mr.Target, Worker: mr.Worker, Priority: mr.Priority}
+		if sim.Target == "" {
+			sim.Target = r.DefaultBranch()
+		}
+		// Without a diff an MR still takes its turn; it just can't conflict
+		if stats, err := eng.DiffStatMR(sim.Branch, sim.Target); err != nil {
+			if out.Skipped == nil {
+				out.Skipped = make(map[string]string)
+			}
+			out.Skipped[mr.ID] = err.Error()
+		} else {
+			for _, s := range stats {
+				sim.Files = append(sim.Files, s.Path)
+			}
+		}
+		mrs = append(mrs, sim)
+	}
+
+	sched, err := refinery.LoadSchedule(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge schedule: %w", err)
+	}
+	simulation := refinery.Simulation{Start: now, Schedule: sched}
+	if out.Paused == "" {
+		out.MRDuration, out.DurationSource, err = estimateMRDuration(r)
+		if err != nil {
+			return err
+		}
+		simulation.Duration = out.MRDuration
+	}
+	out.Steps = simulation.Run(mrs)
+	if n := len(out.Steps); n > 0 && out.Steps[n-1].Finish != nil {
+		out.Finish = out.Steps[n-1].Finish
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printMQSimulate(out)
+	return nil
+}
+
+// simulationOrder returns the rig's open MRs in the order the Refinery
+// would take them: those it has claimed, then the ready ones by priority
+// score and fairness policy. MRs it won't take yet are returned as held.
+func simulationOrder(r *rig.Rig, now time.Time) ([]*refinery.MRInfo, []MQSimulateHeld, error) {
+	ready, err := refinery.NewEngineer(r).ListReadyMRs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing ready MRs: %w", err)
+	}
+	sort.SliceStable(ready, func(i, j int) bool { return ready[i].ScoreAt(now) > ready[j].ScoreAt(now) })
+
+	fairness, err := refinery.LoadFairness(r.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading merge fairness: %w", err)
+	}
+	if fairness != nil {
+		// MRs held by the in-flight limit are claimed as their worker's
+		// earlier MRs finish, so they come last
+		ordered, limited, err := orderByFairness(r, fairness, ready, func(mr *refinery.MRInfo) refinery.QueueEntry {
+			return refinery.QueueEntry{Worker: mr.Worker, Priority: mr.Priority, CreatedAt: mr.CreatedAt, Score: mr.ScoreAt(now)}
+		}, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		ready = append(ordered, limited...)
+	}
+
+	isReady := make(map[string]bool, len(ready))
+	for _, mr := range ready {
+		isReady[mr.ID] = true
+	}
+	protection, err := refinery.LoadBranchProtection(r.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading branch protection: %w", err)
+	}
+
+	b := beads.New(r.BeadsPath())
+	var claimed []*refinery.MRInfo
+	var held []MQSimulateHeld
+	for _, status := range []string{"in_progress", "open"} {
+		issues, err := b.List(beads.ListOptions{Type: "merge-request", Status: status, Priority: -1})
+		if err != nil {
+			return nil, nil, fmt.Errorf("querying merge queue: %w", err)
+		}
+		for _, issue := range issues {
+			fields := beads.ParseMRFields(issue)
+			if issue.Status != status || fields == nil || isReady[issue.ID] {
+				continue
+			}
+			if issue.Status == "in_progress" || issue.Assignee != "" {
+				claimed = append(claimed, &refinery.MRInfo{
+					ID:       issue.ID,
+					Branch:   fields.Branch,
+					Target:   fields.Target,
+					Worker:   fields.Worker,
+					Priority: issue.Priority,
+				})
+				continue
+			}
+			held = append(held, MQSimulateHeld{
+				ID:     issue.ID,
+				Branch: fields.Branch,
+				Worker: fields.Worker,
+				Reason: heldReason(issue, protection.AwaitingReview(refinery.ReviewFromFields(fields))),
+			})
+		}
+	}
+	return append(claimed, ready...), held, nil
+}
+
+// heldReason says why an open, unclaimed MR isn't ready: awaiting is the
+// branch protection's reason, if any.
+func heldReason(issue *beads.Issue, awaiting string) string {
+	switch {
+	case awaiting != "":
+		return awaiting
+	case len(issue.BlockedBy) > 0:
+		return "blocked by " + strings.Join(issue.BlockedBy, ", ")
+	case issue.BlockedByCount > 0:
+		return "blocked"
+	default:
+		return "not ready"
+	}
+}
+
+// estimateMRDuration estimates how long the Refinery takes over one MR:
+// the sum of its checks' median durations, or without a check history,
+// the median test duration of merged MRs. Returns 0 if neither is known.
+func estimateMRDuration(r *rig.Rig) (time.Duration, string, error) {
+	history, err := refinery.LoadFlakeHistory(r.Path)
+	if err != nil {
+		return 0, "", fmt.Errorf("loading check history: %w", err)
+	}
+	var total time.Duration
+	for _, d := range history.Durations() {
+		total += d
+	}
+	if total > 0 {
+		return total, "check history", nil
+	}
+
+	merged, err := beads.New(r.BeadsPath()).List(beads.ListOptions{Type: "merge-request", Status: "closed", Priority: -1})
+	if err != nil {
+		return 0, "", fmt.Errorf("querying merged MRs: %w", err)
+	}
+	if d := mergedTestDuration(merged); d > 0 {
+		return d, "merged MRs", nil
+	}
+	return 0, "", nil
+}
+
+// mergedTestDuration returns the median test duration of the merged MRs
+// among issues, or 0 if none recorded one.
+func mergedTestDuration(issues []*beads.Issue) time.Duration {
+	var ds []time.Duration
+	for _, issue := range issues {
+		if refinery.StateOf(issue) != refinery.StateMerged {
+			continue
+		}
+		if tests := refinery.TestResultsFromFields(beads.ParseMRFields(issue)); tests != nil && tests.Duration > 0 {
+			ds = append(ds, tests.Duration)
+		}
+	}
+	return refinery.MedianDuration(ds)
+}
+
+func printMQSimulate(out MQSimulateOutput) {
+	fmt.Printf("%s Projected merge queue for '%s':\n\n", style.Bold.Render("🔮"), out.Rig)
+
+	switch {
+	case out.Paused != "":
+		fmt.Printf("  %s\n", style.Warning.Render(fmt.Sprintf("Queue paused (%s): order only, nothing will merge", out.Paused)))
+	case out.MRDuration == 0:
+		fmt.Printf("  %s\n", style.Dim.Render("(no check history or merged MRs to estimate times from)"))
+	default:
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(~%s per MR, from %s)", formatDuration(out.MRDuration), out.DurationSource)))
+	}
+
+	if len(out.Steps) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no MRs to process)"))
+	} else {
+		fmt.Println()
+		table := style.NewTable(
+			style.Column{Name: "#", Width: 3, Align: style.AlignRight},
+			style.Column{Name: "MR", Width: 14},
+			style.Column{Name: "WORKER", Width: 12},
+			style.Column{Name: "P", Width: 2},
+			style.Column{Name: "OUTCOME", Width: 8},
+			style.Column{Name: "START", Width: 20},
+			style.Column{Name: "FINISH", Width: 20},
+		)
+		for _, s := range out.Steps {
+			outcome := style.Success.Render(s.Outcome)
+			if s.Outcome == refinery.SimConflict {
+				outcome = style.Warning.Render(s.Outcome)
+			}
+			table.AddRow(fmt.Sprintf("%d", s.Position), s.ID, s.Worker, fmt.Sprintf("P%d", s.Priority), outcome,
+				formatSimTime(s.Start, out.Start), formatSimTime(s.Finish, out.Start))
+		}
+		fmt.Print(table.Render())
+	}
+
+	var conflicts []refinery.SimStep
+	for _, s := range out.Steps {
+		if s.Outcome == refinery.SimConflict {
+			conflicts = append(conflicts, s)
+		}
+	}
+	if len(conflicts) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Likely conflicts"))
+		for _, s := range conflicts {
+			fmt.Printf("  %s after %s %s\n", s.ID, strings.Join(s.ConflictsWith, ", "),
+				style.Dim.Render("("+strings.Join(s.ConflictFiles, ", ")+")"))
+		}
+	}
+
+	if len(out.Held) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Held"))
+		for _, h := range out.Held {
+			fmt.Printf("  %s %s\n", h.ID, style.Dim.Render("("+h.Reason+")"))
+		}
+	}
+	printMQConflictsSkipped(out.Skipped)
+
+	if out.Finish != nil {
+		fmt.Printf("\n%s Queue clear %s\n", style.Success.Render("✓"), formatSimTime(out.Finish, out.Start))
+	}
+}
+
+// formatSimTime renders a projected time: "now" or "in 12m 0s" relative
+// to start, or an absolute time per --time-format. Unknown times are "-".
+func formatSimTime(t *time.Time, start time.Time) string {
+	switch {
+	case t == nil:
+		return style.Dim.Render("-")
+	case timeFormat != TimeRelative:
+		return formatAbsoluteTime(*t)
+	case !t.After(start):
+		return "now"
+	default:
+		return "in " + formatDuration(t.Sub(start))
+	}
+}
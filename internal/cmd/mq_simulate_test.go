@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestMergedTestDuration(t *testing.T) {
+	mr := func(status string, fields beads.MRFields) *beads.Issue {
+		issue := &beads.Issue{ID: "gp-mr-1", Status: status}
+		issue.Description = beads.SetMRFields(issue, &fields)
+		return issue
+	}
+	issues := []*beads.Issue{
+		mr("closed", beads.MRFields{State: "merged", MergeCommit: "abc1234", TestResults: "ok suite=go passed=4 failed=0 duration=2m0s"}),
+		mr("closed", beads.MRFields{State: "merged", MergeCommit: "def5678", TestResults: "ok suite=go passed=4 failed=0 duration=4m0s"}),
+		mr("closed", beads.MRFields{State: "merged", MergeCommit: "0123abc", TestResults: "ok suite=go passed=4 failed=0 duration=3m0s"}),
+		mr("closed", beads.MRFields{State: "failed", TestResults: "failed suite=go passed=3 failed=1 duration=1h0m0s"}),
+		mr("closed", beads.MRFields{State: "merged", MergeCommit: "4567def"}), // No test results
+	}
+	if got := mergedTestDuration(issues); got != 3*time.Minute {
+		t.Errorf("mergedTestDuration = %v, want 3m", got)
+	}
+	if got := mergedTestDuration(nil); got != 0 {
+		t.Errorf("mergedTestDuration(nil) = %v, want 0", got)
+	}
+}
+
+func TestHeldReason(t *testing.T) {
+	tests := []struct {
+		issue    beads.Issue
+		awaiting string
+		want     string
+	}{
+		{beads.Issue{BlockedBy: []string{"gp-1"}}, "awaiting 1 approval", "awaiting 1 approval"},
+		{beads.Issue{BlockedBy: []string{"gp-1", "gp-2"}}, "", "blocked by gp-1, gp-2"},
+		{beads.Issue{BlockedByCount: 1}, "", "blocked"},
+		{beads.Issue{}, "", "not ready"},
+	}
+	for _, tt := range tests {
+		if got := heldReason(&tt.issue, tt.awaiting); got != tt.want {
+			t.Errorf("heldReason(%+v, %q) = %q, want %q", tt.issue, tt.awaiting, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSimTime(t *testing.T) {
+	saved := timeFormat
+	defer func() { timeFormat = saved }()
+
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	later := start.Add(90 * time.Minute)
+
+	timeFormat = TimeRelative
+	if got := formatSimTime(&start, start); got != "now" {
+		t.Errorf("start = %q, want now", got)
+	}
+	if got := formatSimTime(&later, start); got != "in 1h 30m" {
+		t.Errorf("later = %q, want in 1h 30m", got)
+	}
+
+	timeFormat = TimeISO
+	if got := formatSimTime(&later, start); got != "2026-10-14T10:30:00Z" {
+		t.Errorf("iso = %q", got)
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Snapshot command flags
+var (
+	mqSnapshotFile string
+	mqSnapshotList bool
+	mqDiffSnapRig  string
+)
+
+var mqSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <rig>",
+	Short: "Capture the merge queue's state for later comparison",
+	Long: `Capture every MR in the rig's merge queue (open and closed, but not
+archived) with its state, priority, branch, target, worker and retries.
+
+Snapshots are saved in the rig's .runtime/mq-snapshots/ directory, named
+for the time they were taken (UTC), unless --file says otherwise. Compare
+two with gt mq diff-snapshots, e.g. from a nightly job:
+
+  gt mq snapshot greenplace
+  gt mq diff-snapshots previous latest --rig greenplace
+
+Examples:
+  gt mq snapshot greenplace
+  gt mq snapshot greenplace --file /tmp/before.json
+  gt mq snapshot greenplace --list`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQSnapshot,
+}
+
+var mqDiffSnapshotsCmd = &cobra.Command{
+	Use:   "diff-snapshots <a> <b>",
+	Short: "Compare two merge queue snapshots",
+	Long: `Compare merge queue snapshot <a> with a later snapshot <b>.
+
+Each MR that changed is listed under what happened to it between them:
+  Merged    merged since <a>
+  Failed    regressed to failed or rejected, with the reason
+  Closed    cancelled or gone stale
+  Stuck     open in both and still in the same state
+  Moved     open in both, in a different state (or reopened)
+  Added     submitted since <a>
+  Removed   open in <a> but gone from <b>
+
+Snapshots are files written by gt mq snapshot. With --rig, they can also be
+named from the rig's snapshot directory: "latest", "previous", or a prefix
+of a snapshot's name such as a date (2026-10-14), which picks the latest
+snapshot that day.
+
+Examples:
+  gt mq diff-snapshots previous latest --rig greenplace
+  gt mq diff-snapshots 2026-10-14 2026-10-15 --rig greenplace
+  gt mq diff-snapshots /tmp/before.json /tmp/after.json -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQDiffSnapshots,
+}
+
+func init() {
+	mqSnapshotCmd.Flags().StringVar(&mqSnapshotFile, "file", "", "Write the snapshot to this file instead of the rig's snapshot directory")
+	mqSnapshotCmd.Flags().BoolVar(&mqSnapshotList, "list", false, "List the rig's saved snapshots instead of taking one")
+
+	mqDiffSnapshotsCmd.Flags().StringVar(&mqDiffSnapRig, "rig", "", "Resolve snapshot names in this rig's snapshot directory")
+
+	mqCmd.AddCommand(mqSnapshotCmd)
+	mqCmd.AddCommand(mqDiffSnapshotsCmd)
+}
+
+// MQSnapshotOutput is the structured output for gt mq snapshot.
+type MQSnapshotOutput struct {
+	Path     string                  `json:"path,omitempty"`
+	Snapshot *refinery.QueueSnapshot `json:"snapshot,omitempty"`
+	Saved    []string                `json:"saved,omitempty"` // With --list: snapshot paths, oldest first
+}
+
+// MQDiffSnapshotsOutput is the structured output for gt mq diff-snapshots.
+type MQDiffSnapshotsOutput struct {
+	Rig          string `json:"rig,omitempty"`
+	FromSnapshot string `json:"from_snapshot"` // Snapshot paths
+	ToSnapshot   string `json:"to_snapshot"`
+	refinery.SnapshotDiff
+}
+
+func runMQSnapshot(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	dir := refinery.SnapshotDir(r.Path)
+
+	if mqSnapshotList {
+		saved, err := refinery.ListSnapshots(dir)
+		if err != nil {
+			return fmt.Errorf("listing snapshots: %w", err)
+		}
+		if structuredOutput(false) {
+			return renderStructured(MQSnapshotOutput{Saved: saved})
+		}
+		if len(saved) == 0 {
+			fmt.Printf("No snapshots for '%s'\n", rigName)
+			return nil
+		}
+		for _, path := range saved {
+			fmt.Println(path)
+		}
+		return nil
+	}
+
+	bd := beads.New(r.BeadsPath())
+	issues, err := bd.ListIndexed(beads.ListOptions{Type: "merge-request", Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("querying merge queue: %w", err)
+	}
+	snapshot := refinery.NewQueueSnapshot(rigName, issues, time.Now())
+
+	var path string
+	if mqSnapshotFile != "" {
+		path = mqSnapshotFile
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating snapshot dir: %w", err)
+		}
+		if err := writeSnapshotFile(path, snapshot); err != nil {
+			return err
+		}
+	} else if path, err = snapshot.Save(dir); err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(MQSnapshotOutput{Path: path, Snapshot: snapshot})
+	}
+
+	open := 0
+	for _, mr := range snapshot.MRs {
+		if !mr.State.Terminal() {
+			open++
+		}
+	}
+	fmt.Printf("%s Snapshot of '%s': %d MR(s), %d open\n", style.Success.Render("✓"), rigName, len(snapshot.MRs), open)
+	fmt.Printf("  %s\n", path)
+	return nil
+}
+
+// writeSnapshotFile writes a snapshot to an explicit path.
+func writeSnapshotFile(path string, snapshot *refinery.QueueSnapshot) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := writeStructured(f, snapshot, OutputJSON); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+func runMQDiffSnapshots(cmd *cobra.Command, args []string) error {
+	var dir string
+	if mqDiffSnapRig != "" {
+		_, r, err := getRig(mqDiffSnapRig)
+		if err != nil {
+			return err
+		}
+		dir = refinery.SnapshotDir(r.Path)
+	}
+
+	paths := make([]string, 2)
+	snapshots := make([]*refinery.QueueSnapshot, 2)
+	for i, name := range args {
+		path, err := resolveSnapshot(dir, name)
+		if err != nil {
+			return err
+		}
+		if snapshots[i], err = refinery.LoadQueueSnapshot(path); err != nil {
+			return fmt.Errorf("loading snapshot: %w", err)
+		}
+		paths[i] = path
+	}
+	if snapshots[0].Rig != snapshots[1].Rig {
+		style.PrintWarning("comparing snapshots of different rigs (%s, %s)", snapshots[0].Rig, snapshots[1].Rig)
+	}
+	if snapshots[1].TakenAt.Before(snapshots[0].TakenAt) {
+		style.PrintWarning("%s was taken before %s; changes are shown backwards", args[1], args[0])
+	}
+
+	out := MQDiffSnapshotsOutput{
+		Rig:          snapshots[1].Rig,
+		FromSnapshot: paths[0],
+		ToSnapshot:   paths[1],
+		SnapshotDiff: refinery.DiffSnapshots(snapshots[0], snapshots[1]),
+	}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	printMQDiffSnapshots(out)
+	return nil
+}
+
+// resolveSnapshot finds a snapshot given as a file or, with a rig's
+// snapshot dir, by name.
+func resolveSnapshot(dir, name string) (string, error) {
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+	if dir == "" {
+		return "", fmt.Errorf("snapshot %s not found (use --rig to name a rig's snapshots)", name)
+	}
+	return refinery.FindSnapshot(dir, name)
+}
+
+func printMQDiffSnapshots(out MQDiffSnapshotsOutput) {
+	fmt.Printf("%s Merge queue '%s': %s → %s %s\n", style.Bold.Render("📸"), out.Rig,
+		out.From.Local().Format("2006-01-02 15:04"), out.To.Local().Format("2006-01-02 15:04"),
+		style.Dim.Render("("+formatDuration(out.To.Sub(out.From))+")"))
+
+	sections := []struct {
+		name    string
+		changes []refinery.SnapshotChange
+		render  func(...string) string
+	}{
+		{"Merged", out.Merged, style.Success.Render},
+		{"Failed", out.Failed, style.Error.Render},
+		{"Closed", out.Closed, style.Dim.Render},
+		{"Stuck", out.Stuck, style.Warning.Render},
+		{"Moved", out.Moved, style.Bold.Render},
+		{"Added", out.Added, style.Bold.Render},
+		{"Removed", out.Removed, style.Warning.Render},
+	}
+	empty := true
+	for _, s := range sections {
+		if len(s.changes) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Printf("\n%s\n", s.render(fmt.Sprintf("%s (%d)", s.name, len(s.changes))))
+		for _, c := range s.changes {
+			fmt.Printf("  %s  %s %s\n", c.ID, truncateString(c.Title, 50), style.Dim.Render(snapshotChangeDetail(c)))
+			if c.Reason != "" {
+				fmt.Printf("      %s\n", style.Dim.Render(c.Reason))
+			}
+		}
+	}
+	if empty {
+		fmt.Printf("\n  %s\n", style.Dim.Render("(no changes)"))
+	}
+}
+
+// snapshotChangeDetail describes an MR's worker and state change, e.g.
+// "(toast, queued → merged)".
+func snapshotChangeDetail(c refinery.SnapshotChange) string {
+	var states string
+	switch {
+	case c.From == "":
+		states = string(c.To)
+	case c.To == "" || c.From == c.To:
+		states = string(c.From)
+	default:
+		states = fmt.Sprintf("%s → %s", c.From, c.To)
+	}
+	if c.Worker != "" {
+		return "(" + c.Worker + ", " + states + ")"
+	}
+	return "(" + states + ")"
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// State command flags
+var mqStateReason string
+
+var mqStateCmd = &cobra.Command{
+	Use:   "state <rig> <mr-id> <state>",
+	Short: "Move a merge request to a new lifecycle state",
+	Long: `Move a merge request to a new lifecycle state.
+
+MRs move through these states, stored in the MR bead's state field:
+  queued             Waiting for the refinery (bead status open)
+  changes_requested  Sent back to its worker for changes (open)
+  rebasing           Being rebased onto its target (in_progress)
+  checking           Tests and other checks running (in_progress)
+  merging            Checks passed, merging and pushing (in_progress)
+  merged             Landed on its target (closed)
+  failed             Can't be merged and won't be retried (closed)
+  rejected           Refused by a reviewer or branch protection (closed)
+  cancelled          Withdrawn or superseded (closed)
+  stale              Branch gone or abandoned (closed)
+
+The last five are terminal: a closed MR never changes state again. Only an
+MR the refinery is working on (rebasing, checking, merging) can be marked
+merged. Moving back to queued releases the refinery's claim. A
+changes_requested MR (see gt mq request-changes) must be queued again,
+usually by its worker resubmitting, before the refinery takes it.
+
+Terminal states close the bead with "<state>: <reason>".
+
+Examples:
+  gt mq state greenplace gp-mr-abc123 checking
+  gt mq state greenplace gp-mr-abc123 merged
+  gt mq state greenplace gp-mr-abc123 stale --reason "branch no longer exists"`,
+	Args: cobra.ExactArgs(3),
+	RunE: runMQState,
+}
+
+func init() {
+	mqStateCmd.Flags().StringVarP(&mqStateReason, "reason", "r", "", "Why the MR changed state (recorded when closing)")
+
+	mqCmd.AddCommand(mqStateCmd)
+}
+
+// MRStateOutput is the structured output for gt mq state.
+type MRStateOutput struct {
+	ID     string           `json:"id"`
+	From   refinery.MRState `json:"from"`
+	State  refinery.MRState `json:"state"`
+	Status string           `json:"status"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+func runMQState(cmd *cobra.Command, args []string) error {
+	rigName, mrID := args[0], args[1]
+	to, err := refinery.ParseMRState(args[2])
+	if err != nil {
+		return fmt.Errorf("%w (valid: %s)", err, strings.Join(mrStateNames(), ", "))
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName))
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", mrID)
+	}
+
+	from, err := refinery.RecordState(bd, issue, to, mqStateReason)
+	if err != nil {
+		return err
+	}
+	logMRStateEvent(rigName, issue.ID, fields, from, to, mqStateReason)
+	journalMRState(r, issue.ID, fields, from, to)
+
+	out := MRStateOutput{ID: issue.ID, From: from, State: to, Status: string(to.Status()), Reason: mqStateReason}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	fmt.Printf("%s %s: %s → %s\n", style.Bold.Render("✓"), issue.ID, from, formatMRState(to))
+	return nil
+}
+
+// logMRStateEvent publishes the merge queue event that matches a state
+// change, if there is one.
+func logMRStateEvent(rigName, mrID string, fields *beads.MRFields, from, to refinery.MRState, reason string) {
+	if from == to {
+		return
+	}
+	actor := rigName + "/refinery"
+	switch to {
+	case refinery.StateMerging:
+		_ = events.LogFeed(events.TypeMergeStarted, actor, events.MergePayload(mrID, fields.Worker, fields.Branch, ""))
+	case refinery.StateMerged:
+		_ = events.LogFeed(events.TypeMerged, actor, events.MergePayload(mrID, fields.Worker, fields.Branch, ""))
+	case refinery.StateFailed:
+		_ = events.LogFeed(events.TypeMergeFailed, actor, events.MergePayload(mrID, fields.Worker, fields.Branch, reason))
+	case refinery.StateRejected:
+		_ = events.LogFeed(events.TypeMRRejected, actor,
+			events.MRPayload(rigName, mrID, fields.SourceIssue, fields.Branch, reason))
+	}
+}
+
+// journalMRState keeps the refinery journal in step with the patrol's
+// merge: moving to merging begins the entry, so a refinery that dies before
+// recording the outcome is recovered when it restarts, and leaving the
+// merge, landed or not, clears it.
+func journalMRState(r *rig.Rig, mrID string, fields *beads.MRFields, from, to refinery.MRState) {
+	var err error
+	switch {
+	case to == refinery.StateMerging && from != to:
+		target := fields.Target
+		if target == "" {
+			target = r.DefaultBranch()
+		}
+		session := refinery.NewManager(r).SessionName()
+		err = refinery.NewEngineer(r).BeginLanding(mrID, fields.Branch, target, fields.SourceIssue, session)
+	case !to.Active():
+		err = refinery.OpenJournal(r.Path).Finish(mrID)
+	}
+	if err != nil {
+		style.PrintWarning("%v", err)
+	}
+}
+
+// mrStateNames lists the MR state names for help and errors.
+func mrStateNames() []string {
+	names := make([]string, len(refinery.MRStates))
+	for i, s := range refinery.MRStates {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// formatMRState styles an MR state for display.
+func formatMRState(s refinery.MRState) string {
+	switch {
+	case s == refinery.StateMerged:
+		return style.Success.Render(string(s))
+	case s == refinery.StateFailed || s == refinery.StateRejected:
+		return style.Error.Render(string(s))
+	case s.Terminal():
+		return style.Dim.Render(string(s))
+	case s.Active():
+		return style.Warning.Render(string(s))
+	default:
+		return string(s)
+	}
+}
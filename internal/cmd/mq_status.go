@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -9,30 +8,78 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
 // MRStatusOutput is the JSON output structure for gt mq status.
 type MRStatusOutput struct {
+	// Version of this output's published schema (gt schema mq.status)
+	SchemaVersion int `json:"schema_version"`
+
 	// Core issue fields
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	Priority  int    `json:"priority"`
-	Type      string `json:"type"`
-	Assignee  string `json:"assignee,omitempty"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
-	ClosedAt  string `json:"closed_at,omitempty"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  int      `json:"priority"`
+	Type      string   `json:"type"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Labels    []string `json:"labels,omitempty"` // Other than Gas Town's own
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+	ClosedAt  string   `json:"closed_at,omitempty"`
+
+	// Lifecycle state (queued, checking, merged, ...)
+	State refinery.MRState `json:"state"`
 
 	// MR-specific fields
-	Branch      string `json:"branch,omitempty"`
-	Target      string `json:"target,omitempty"`
-	SourceIssue string `json:"source_issue,omitempty"`
-	Worker      string `json:"worker,omitempty"`
-	Rig         string `json:"rig,omitempty"`
-	MergeCommit string `json:"merge_commit,omitempty"`
-	CloseReason string `json:"close_reason,omitempty"`
+	Branch      string   `json:"branch,omitempty"`
+	Target      string   `json:"target,omitempty"`
+	SourceIssue string   `json:"source_issue,omitempty"`
+	Worker      string   `json:"worker,omitempty"`
+	Rig         string   `json:"rig,omitempty"`
+	MergeCommit string   `json:"merge_commit,omitempty"`
+	CloseReason string   `json:"close_reason,omitempty"`
+	Reverts     string   `json:"reverts,omitempty"`
+	RevertedBy  string   `json:"reverted_by,omitempty"`
+	BackportOf  string   `json:"backport_of,omitempty"`
+	Backports   []string `json:"backports,omitempty"`
+
+	Attestation *refinery.Attestation `json:"attestation,omitempty"`
+
+	// Branch protection review state
+	ChecksPassed       []string `json:"checks_passed,omitempty"`
+	ApprovedBy         []string `json:"approved_by,omitempty"`
+	ChangesRequestedBy []string `json:"changes_requested_by,omitempty"`
+	Reviewers          []string `json:"reviewers,omitempty"`
+
+	// Latest test run, and checks that passed only after a retry
+	Tests       *refinery.TestResults `json:"tests,omitempty"`
+	FlakyChecks []string              `json:"flaky_checks,omitempty"`
+
+	// Coverage change under the rig's coverage gate, at the last attempt
+	Coverage *refinery.CoverageDelta `json:"coverage,omitempty"`
+
+	// What the rig's linters found at the last attempt
+	LintFindings []refinery.LintFinding `json:"lint_findings,omitempty"`
+
+	// Where the latest check runs' logs and artifacts are kept (gt mq artifacts)
+	Artifacts string `json:"artifacts,omitempty"`
+
+	// Description body, in the rig's template sections
+	Description string `json:"description,omitempty"`
+
+	// Heuristic risk score for open MRs (omitted if the diff is unavailable)
+	Risk *refinery.RiskAssessment `json:"risk,omitempty"`
+
+	// Owner sign-offs an open MR needs under the rig's merge_queue.owners
+	OwnerApprovals []refinery.OwnerApproval `json:"owner_approvals,omitempty"`
+
+	// Merge policies (merge_queue.policies) that block an open MR, and why
+	PolicyViolations []refinery.ProtectionViolation `json:"policy_violations,omitempty"`
+
+	// Discussion thread (gt mq comment), oldest first
+	Comments []refinery.MRComment `json:"comments,omitempty"`
 
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
@@ -49,6 +96,10 @@ type DependencyInfo struct {
 }
 
 func runMqStatus(cmd *cobra.Command, args []string) error {
+	args, err := pickArgs(cmd, args, pickAnyMR)
+	if err != nil {
+		return err
+	}
 	mrID := args[0]
 
 	// Use current working directory for beads operations
@@ -65,7 +116,7 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	issue, err := bd.Show(mrID)
 	if err != nil {
 		if err == beads.ErrNotFound {
-			return fmt.Errorf("merge request '%s' not found", mrID)
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found", mrID))
 		}
 		return fmt.Errorf("fetching merge request: %w", err)
 	}
@@ -75,15 +126,18 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 
 	// Build output structure
 	output := MRStatusOutput{
-		ID:        issue.ID,
-		Title:     issue.Title,
-		Status:    issue.Status,
-		Priority:  issue.Priority,
-		Type:      issue.Type,
-		Assignee:  issue.Assignee,
-		CreatedAt: issue.CreatedAt,
-		UpdatedAt: issue.UpdatedAt,
-		ClosedAt:  issue.ClosedAt,
+		SchemaVersion: mqStatusSchemaVersion,
+		ID:            issue.ID,
+		Title:         issue.Title,
+		Status:        issue.Status,
+		Priority:      issue.Priority,
+		Type:          issue.Type,
+		Assignee:      issue.Assignee,
+		Labels:        beads.DisplayLabels(issue),
+		CreatedAt:     utcTimestamp(issue.CreatedAt),
+		UpdatedAt:     utcTimestamp(issue.UpdatedAt),
+		ClosedAt:      utcTimestamp(issue.ClosedAt),
+		State:         refinery.StateOf(issue),
 	}
 
 	// Add MR fields if present
@@ -95,6 +149,29 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.Reverts = mrFields.Reverts
+		output.RevertedBy = mrFields.RevertedBy
+		output.BackportOf = mrFields.BackportOf
+		output.Backports = refinery.SplitMRList(mrFields.Backports)
+		if mrFields.Attestation != "" {
+			output.Attestation, _ = refinery.ParseAttestation(mrFields.Attestation)
+		}
+		output.ChecksPassed = refinery.SplitMRList(mrFields.ChecksPassed)
+		output.ApprovedBy = refinery.SplitMRList(mrFields.ApprovedBy)
+		output.ChangesRequestedBy = refinery.SplitMRList(mrFields.ChangesRequestedBy)
+		output.Reviewers = refinery.SplitMRList(mrFields.Reviewers)
+		output.Tests = refinery.TestResultsFromFields(mrFields)
+		output.FlakyChecks = refinery.SplitMRList(mrFields.FlakyChecks)
+		output.Coverage = refinery.ParseCoverageDelta(mrFields.Coverage)
+		output.LintFindings, _ = refinery.ParseLintFindings(mrFields.LintFindings)
+		output.Artifacts = mrFields.Artifacts
+		output.Description = beads.MRBody(issue)
+		output.Comments, _ = refinery.ParseMRComments(mrFields.Comments)
+		if issue.Status != "closed" {
+			output.Risk = assessMRRisk(mrFields)
+			output.OwnerApprovals = mrOwnerApprovals(mrFields)
+			output.PolicyViolations = mrPolicyViolations(issue, mrFields)
+		}
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -120,18 +197,67 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// JSON output
-	if mqStatusJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(output)
+	if structuredOutput(mqStatusJSON) {
+		return renderStructured(output)
 	}
 
 	// Human-readable output
-	return printMqStatus(issue, mrFields)
+	return printMqStatus(issue, mrFields, output.Risk, output.OwnerApprovals, output.PolicyViolations)
+}
+
+// assessMRRisk scores an MR's diff in its rig's refinery clone. Returns nil
+// if the rig or branches can't be resolved.
+func assessMRRisk(fields *beads.MRFields) *refinery.RiskAssessment {
+	if fields.Rig == "" || fields.Branch == "" || fields.Target == "" {
+		return nil
+	}
+	_, r, err := getRig(fields.Rig)
+	if err != nil {
+		return nil
+	}
+	risk, err := refinery.NewEngineer(r).AssessRisk(fields.Branch, fields.Target)
+	if err != nil {
+		return nil
+	}
+	return &risk
+}
+
+// mrOwnerApprovals returns the owner sign-offs an MR's diff needs. Returns
+// nil if the rig has no owners or the rig or branches can't be resolved.
+func mrOwnerApprovals(fields *beads.MRFields) []refinery.OwnerApproval {
+	if fields.Rig == "" || fields.Branch == "" || fields.Target == "" {
+		return nil
+	}
+	_, r, err := getRig(fields.Rig)
+	if err != nil {
+		return nil
+	}
+	approvals, err := refinery.NewEngineer(r).OwnerApprovals(fields.Branch, fields.Target, refinery.SplitMRList(fields.ApprovedBy))
+	if err != nil {
+		return nil
+	}
+	return approvals
+}
+
+// mrPolicyViolations returns the merge policies that block an MR. Returns
+// nil if the rig has no policies or the rig or branches can't be resolved.
+func mrPolicyViolations(issue *beads.Issue, fields *beads.MRFields) []refinery.ProtectionViolation {
+	if fields.Rig == "" || fields.Branch == "" || fields.Target == "" {
+		return nil
+	}
+	_, r, err := getRig(fields.Rig)
+	if err != nil {
+		return nil
+	}
+	violations, err := refinery.NewEngineer(r).CheckPolicies(fields.Branch, fields.Target, refinery.ReviewFromMR(issue, fields))
+	if err != nil {
+		return nil
+	}
+	return violations
 }
 
 // printMqStatus prints detailed MR status in human-readable format.
-func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
+func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields, risk *refinery.RiskAssessment, owners []refinery.OwnerApproval, policies []refinery.ProtectionViolation) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("📋 Merge Request:"), issue.ID)
 	fmt.Printf("   %s\n\n", issue.Title)
@@ -139,7 +265,7 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 	// Status section
 	fmt.Printf("%s\n", style.Bold.Render("Status"))
 	statusDisplay := formatStatus(issue.Status)
-	fmt.Printf("   State:    %s\n", statusDisplay)
+	fmt.Printf("   State:    %s %s\n", formatMRState(refinery.StateOf(issue)), style.Dim.Render("("+statusDisplay+")"))
 	fmt.Printf("   Priority: P%d\n", issue.Priority)
 	if issue.Type != "" {
 		fmt.Printf("   Type:     %s\n", issue.Type)
@@ -147,17 +273,20 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 	if issue.Assignee != "" {
 		fmt.Printf("   Assignee: %s\n", issue.Assignee)
 	}
+	if labels := beads.DisplayLabels(issue); len(labels) > 0 {
+		fmt.Printf("   Labels:   %s\n", strings.Join(labels, ", "))
+	}
 
 	// Timestamps
 	fmt.Printf("\n%s\n", style.Bold.Render("Timeline"))
 	if issue.CreatedAt != "" {
-		fmt.Printf("   Created: %s %s\n", issue.CreatedAt, formatTimeAgo(issue.CreatedAt))
+		fmt.Printf("   Created: %s\n", formatTimestamp(issue.CreatedAt))
 	}
 	if issue.UpdatedAt != "" && issue.UpdatedAt != issue.CreatedAt {
-		fmt.Printf("   Updated: %s %s\n", issue.UpdatedAt, formatTimeAgo(issue.UpdatedAt))
+		fmt.Printf("   Updated: %s\n", formatTimestamp(issue.UpdatedAt))
 	}
 	if issue.ClosedAt != "" {
-		fmt.Printf("   Closed:  %s %s\n", issue.ClosedAt, formatTimeAgo(issue.ClosedAt))
+		fmt.Printf("   Closed:  %s\n", formatTimestamp(issue.ClosedAt))
 	}
 
 	// MR-specific fields
@@ -184,6 +313,86 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.CloseReason != "" {
 			fmt.Printf("   Close Reason: %s\n", mrFields.CloseReason)
 		}
+		if mrFields.Reverts != "" {
+			fmt.Printf("   Reverts:      %s\n", mrFields.Reverts)
+		}
+		if mrFields.RevertedBy != "" {
+			fmt.Printf("   Reverted By:  %s\n", mrFields.RevertedBy)
+		}
+		if mrFields.BackportOf != "" {
+			fmt.Printf("   Backport Of:  %s\n", mrFields.BackportOf)
+		}
+		if mrFields.Backports != "" {
+			fmt.Printf("   Backports:    %s\n", mrFields.Backports)
+		}
+		if att, err := refinery.ParseAttestation(mrFields.Attestation); mrFields.Attestation != "" && err == nil {
+			signed := "unsigned"
+			if att.Signed != "" {
+				signed = "signed with " + att.Signed
+			}
+			fmt.Printf("   Attestation:  %s by %s at %s (%s)\n", shortSHA(att.Commit), att.MergedBy,
+				att.MergedAt.Format("2006-01-02 15:04"), signed)
+		}
+		if mrFields.ChecksPassed != "" {
+			fmt.Printf("   Checks:       %s\n", mrFields.ChecksPassed)
+		}
+		if tests := refinery.TestResultsFromFields(mrFields); tests != nil {
+			icon := style.Success.Render("✓")
+			if !tests.OK {
+				icon = style.Error.Render("✗")
+			}
+			fmt.Printf("   Tests:        %s %s\n", icon, tests)
+			for _, name := range tests.Failing {
+				fmt.Printf("                   %s\n", name)
+			}
+		}
+		if mrFields.FlakyChecks != "" {
+			fmt.Printf("   Flaky:        %s %s\n", mrFields.FlakyChecks, style.Dim.Render("(passed after retry)"))
+		}
+		if mrFields.Coverage != "" {
+			fmt.Printf("   Coverage:     %s\n", mrFields.Coverage)
+		}
+		if findings, _ := refinery.ParseLintFindings(mrFields.LintFindings); len(findings) > 0 {
+			printLintFindings("   ", findings, len(findings))
+		}
+		if mrFields.Artifacts != "" {
+			fmt.Printf("   Artifacts:    %s %s\n", mrFields.Artifacts, style.Dim.Render("(gt mq artifacts "+issue.ID+")"))
+		}
+		if mrFields.Reviewers != "" {
+			fmt.Printf("   Reviewers:    %s\n", mrFields.Reviewers)
+		}
+		if mrFields.ApprovedBy != "" {
+			fmt.Printf("   Approved By:  %s\n", mrFields.ApprovedBy)
+		}
+		if mrFields.ChangesRequestedBy != "" {
+			fmt.Printf("   Changes Req.: %s\n", mrFields.ChangesRequestedBy)
+		}
+		for i, a := range owners {
+			label := "Owners:      "
+			if i > 0 {
+				label = "             "
+			}
+			status := style.Warning.Render("awaiting " + strings.Join(a.Owners, " or "))
+			if a.ApprovedBy != "" {
+				status = style.Success.Render("✓") + " " + a.ApprovedBy
+			}
+			fmt.Printf("   %s %s %s\n", label, a.Path, status)
+		}
+		if risk != nil {
+			fmt.Printf("   Risk:         %s", formatRisk(*risk))
+			if len(risk.Factors) > 0 {
+				fmt.Printf(" %s", style.Dim.Render("("+strings.Join(risk.Factors, "; ")+")"))
+			}
+			fmt.Println()
+		}
+	}
+
+	// Merge policies holding the MR
+	if len(policies) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Blocked By Policy"))
+		for _, v := range policies {
+			fmt.Printf("   %s %s\n", style.Error.Render("✗"), v.Reason)
+		}
 	}
 
 	// Dependencies (what this MR is waiting on)
@@ -222,9 +431,39 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		}
 	}
 
+	// Discussion thread, oldest first
+	if mrFields != nil {
+		comments, _ := refinery.ParseMRComments(mrFields.Comments)
+		if len(comments) > 0 {
+			fmt.Printf("\n%s\n", style.Bold.Render("Discussion"))
+		}
+		for _, c := range comments {
+			for _, line := range formatMRComment(c) {
+				fmt.Printf("   %s\n", line)
+			}
+		}
+	}
+
 	return nil
 }
 
+// formatMRComment renders a comment as a heading line (number, author, role,
+// where it's anchored, what it replies to, and when) then its body.
+func formatMRComment(c refinery.MRComment) []string {
+	head := fmt.Sprintf("#%d %s %s", c.ID, style.Bold.Render(c.Author), style.Dim.Render("("+c.Role+")"))
+	if loc := c.Location(); loc != "" {
+		head += " on " + loc
+	}
+	if c.ReplyTo != 0 {
+		head += fmt.Sprintf(" ↳ #%d", c.ReplyTo)
+	}
+	lines := []string{head + " " + style.Dim.Render(formatTimestamp(c.At.Format(time.RFC3339)))}
+	for _, line := range strings.Split(c.Body, "\n") {
+		lines = append(lines, "   "+line)
+	}
+	return lines
+}
+
 // formatStatus formats the status with appropriate styling.
 func formatStatus(status string) string {
 	switch status {
@@ -253,26 +492,23 @@ func getStatusIcon(status string) string {
 	}
 }
 
+// formatTimestamp formats a timestamp for the timeline: as written with its
+// relative time, or in the absolute format --time-format asks for.
+func formatTimestamp(timestamp string) string {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
+		return timestamp
+	}
+	if timeFormat != TimeRelative {
+		return formatAbsoluteTime(t)
+	}
+	return timestamp + " " + formatTimeAgo(timestamp)
+}
+
 // formatTimeAgo formats a timestamp as a relative time string.
 func formatTimeAgo(timestamp string) string {
-	// Try parsing common formats
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-	}
-
-	var t time.Time
-	var err error
-	for _, format := range formats {
-		t, err = time.Parse(format, timestamp)
-		if err == nil {
-			break
-		}
-	}
-	if err != nil {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
 		return "" // Can't parse, return empty
 	}
 
@@ -314,30 +550,49 @@ func getDescriptionWithoutMRFields(description string) string {
 
 	// Known MR field keys (lowercase)
 	mrKeys := map[string]bool{
-		"branch":       true,
-		"target":       true,
-		"source_issue": true,
-		"source-issue": true,
-		"sourceissue":  true,
-		"worker":       true,
-		"rig":          true,
-		"merge_commit": true,
-		"merge-commit": true,
-		"mergecommit":  true,
-		"close_reason": true,
-		"close-reason": true,
-		"closereason":  true,
-		"type":         true,
+		"branch":               true,
+		"target":               true,
+		"source_issue":         true,
+		"source-issue":         true,
+		"sourceissue":          true,
+		"worker":               true,
+		"rig":                  true,
+		"state":                true,
+		"merge_commit":         true,
+		"merge-commit":         true,
+		"mergecommit":          true,
+		"close_reason":         true,
+		"close-reason":         true,
+		"closereason":          true,
+		"checks_passed":        true,
+		"approved_by":          true,
+		"changes_requested_by": true,
+		"reviewers":            true,
+		"test_results":         true,
+		"failing_tests":        true,
+		"flaky_checks":         true,
+		"reverts":              true,
+		"reverted_by":          true,
+		"backport_of":          true,
+		"backports":            true,
+		"type":                 true,
 	}
 
 	var lines []string
-	for _, line := range strings.Split(description, "\n") {
+	all := strings.Split(description, "\n")
+	for i, line := range all {
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" {
 			lines = append(lines, line)
 			continue
 		}
 
+		// The description body (see beads.MRBody) is kept as written
+		if strings.HasPrefix(trimmed, beads.MRBodyHeading) {
+			lines = append(lines, all[i:]...)
+			break
+		}
+
 		// Check if this is an MR field line
 		colonIdx := strings.Index(trimmed, ":")
 		if colonIdx != -1 {
@@ -355,3 +610,20 @@ func getDescriptionWithoutMRFields(description string) string {
 	result = strings.TrimSpace(result)
 	return result
 }
+
+// maxLintFindingsShown caps the lint findings listed in human output.
+const maxLintFindingsShown = 10
+
+// printLintFindings lists lint findings under a "Lint:" heading, indented.
+func printLintFindings(indent string, findings []refinery.LintFinding, total int) {
+	fmt.Printf("%sLint:         %s %d finding(s)\n", indent, style.Error.Render("✗"), total)
+	for i, f := range findings {
+		if i == maxLintFindingsShown {
+			break
+		}
+		fmt.Printf("%s                %s %s\n", indent, f, style.Dim.Render("["+f.Linter+"]"))
+	}
+	if more := total - min(len(findings), maxLintFindingsShown); more > 0 {
+		fmt.Printf("%s                %s\n", indent, style.Dim.Render(fmt.Sprintf("... and %d more", more)))
+	}
+}
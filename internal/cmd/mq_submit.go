@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,11 +13,17 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/wip"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 // branchInfo holds parsed branch information.
@@ -28,41 +36,29 @@ type branchInfo struct {
 // issuePattern matches issue IDs in branch names (e.g., "gt-xyz" or "gt-abc.1")
 var issuePattern = regexp.MustCompile(`([a-z]+-[a-z0-9]+(?:\.[0-9]+)?)`)
 
-// parseBranchName extracts issue ID and worker from a branch name.
-// Supports formats:
+// parseBranchName extracts issue ID and worker from a branch name using
+// the default branch scheme. See parseRigBranchName.
+func parseBranchName(branch string) branchInfo {
+	return parseRigBranchName(nil, branch)
+}
+
+// parseRigBranchName extracts issue ID and worker from a branch name.
+// Branches named by the rig's policy (or the default scheme) parse exactly:
 //   - polecat/<worker>/<issue>  → issue=<issue>, worker=<worker>
 //   - polecat/<worker>-<timestamp>  → issue="", worker=<worker> (modern polecat branches)
+//   - a polecat_branch_template name → its {name} and {id}/{issue} captures
+//
+// Other branches fall back to the first thing that looks like an issue ID:
 //   - <issue>                   → issue=<issue>, worker=""
-func parseBranchName(branch string) branchInfo {
+func parseRigBranchName(policy *rig.BranchPolicy, branch string) branchInfo {
 	info := branchInfo{Branch: branch}
 
-	// Try polecat/<worker>/<issue> or polecat/<worker>/<issue>@<timestamp> format
-	if strings.HasPrefix(branch, constants.BranchPolecatPrefix) {
-		parts := strings.SplitN(branch, "/", 3)
-		if len(parts) == 3 {
-			info.Worker = parts[1]
-			// Strip @timestamp suffix if present (e.g., "gt-abc@mk123" -> "gt-abc")
-			issue := parts[2]
-			if atIdx := strings.Index(issue, "@"); atIdx > 0 {
-				issue = issue[:atIdx]
-			}
-			info.Issue = issue
-			return info
-		}
-		// Modern polecat branch format: polecat/<worker>-<timestamp>
-		// The second part is "worker-timestamp", not an issue ID.
-		// Don't try to extract an issue ID - gt done will use hook_bead fallback.
-		if len(parts) == 2 {
-			// Extract worker name from "worker-timestamp" format
-			workerPart := parts[1]
-			if dashIdx := strings.LastIndex(workerPart, "-"); dashIdx > 0 {
-				info.Worker = workerPart[:dashIdx]
-			} else {
-				info.Worker = workerPart
-			}
-			// Explicitly don't set info.Issue - let hook_bead fallback handle it
-			return info
-		}
+	if parsed, ok := policy.Parse(branch); ok {
+		// Modern polecat branches carry no issue - gt done uses the
+		// hook_bead fallback.
+		info.Issue = parsed.Issue
+		info.Worker = parsed.Worker
+		return info
 	}
 
 	// Try to find an issue ID pattern in the branch name
@@ -74,7 +70,27 @@ func parseBranchName(branch string) branchInfo {
 	return info
 }
 
+// rigBranchPolicy loads a rig's branch policy for parsing. Problems are
+// warned about and the default scheme is used.
+func rigBranchPolicy(rigName string) *rig.BranchPolicy {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil
+	}
+	policy, err := r.BranchPolicy()
+	if err != nil {
+		style.PrintWarning("%v; parsing branch with the default scheme", err)
+		return nil
+	}
+	return policy
+}
+
 func runMqSubmit(cmd *cobra.Command, args []string) error {
+	extraLabels, err := beads.NormalizeLabels(mqSubmitLabels)
+	if err != nil {
+		return fmt.Errorf("--label: %w", err)
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -114,7 +130,7 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse branch info
-	info := parseBranchName(branch)
+	info := parseRigBranchName(rigBranchPolicy(rigName), branch)
 
 	// Override with explicit flags
 	issueID := mqSubmitIssue
@@ -130,9 +146,29 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	// Initialize beads for looking up source issue
 	bd := beads.New(cwd)
 
+	// Refuse work for an issue another worker has claimed
+	if !mqSubmitIgnoreClaim {
+		if err := checkSubmitClaim(bd, issueID, worker); err != nil {
+			return err
+		}
+	}
+
 	// Determine target branch
 	target := defaultBranch
-	if mqSubmitEpic != "" {
+	if mqSubmitTarget != "" {
+		// Explicit --target must be one of the rig's merge targets
+		if mqSubmitEpic != "" {
+			return fmt.Errorf("--target and --epic are mutually exclusive")
+		}
+		targets, err := refinery.LoadTargets(filepath.Join(townRoot, rigName), defaultBranch)
+		if err != nil {
+			return fmt.Errorf("loading merge targets: %w", err)
+		}
+		if err := targets.Check(mqSubmitTarget); err != nil {
+			return err
+		}
+		target = mqSubmitTarget
+	} else if mqSubmitEpic != "" {
 		// Explicit --epic flag takes precedence
 		target = "integration/" + mqSubmitEpic
 	} else {
@@ -146,6 +182,22 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if branch == target {
+		return fmt.Errorf("cannot submit %s to merge queue targeting itself", branch)
+	}
+
+	// Reject up front anything the refinery's branch protection would reject
+	if err := checkSubmitProtection(filepath.Join(townRoot, rigName), g, branch, target); err != nil {
+		return err
+	}
+	if err := checkSubmitCommits(filepath.Join(townRoot, rigName), g, branch, target); err != nil {
+		return err
+	}
+
+	owners := submitMROwners(filepath.Join(townRoot, rigName), g, branch, target)
+	project := submitMRProject(filepath.Join(townRoot, rigName), g, branch, target)
+	locks := submitMRLocks(filepath.Join(townRoot, rigName), g, branch, target, mqSubmitLocks)
+
 	// Get source issue for priority inheritance
 	var priority int
 	if mqSubmitPriority >= 0 {
@@ -161,24 +213,66 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// The MR carries the source issue's labels, plus any given
+	labels := extraLabels
+	if sourceIssue, err := bd.Show(issueID); err == nil {
+		labels = mrLabels(sourceIssue, extraLabels)
+	}
+
 	// Build MR bead title and description
 	title := fmt.Sprintf("Merge: %s", issueID)
-	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
-		branch, target, issueID, rigName)
+	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s\nstate: %s",
+		branch, target, issueID, rigName, refinery.StateQueued)
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	if project != "" {
+		description += "\nproject: " + project
+	}
+	if len(locks) > 0 {
+		description += "\nlocks: " + strings.Join(locks, ",")
+	}
 
-	// Check if MR bead already exists for this branch (idempotency)
+	// One unmerged MR per branch and target: submitting again updates it
 	var mrIssue *beads.Issue
-	existingMR, err := bd.FindMRForBranch(branch)
+	var reviewers []string
+	existingMR, err := bd.FindOpenMR(branch, target)
 	if err != nil {
 		style.PrintWarning("could not check for existing MR: %v", err)
 		// Continue with creation attempt - Create will fail if duplicate
-	} else if existingMR != nil {
+	} else if existingMR != nil && mqSubmitForceNew {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(note: %s for %s → %s stays open)", existingMR.ID, branch, target)))
+		existingMR = nil
+	}
+	if existingMR != nil {
 		mrIssue = existingMR
-		fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
+		if err := updateSubmittedMR(townRoot, bd, existingMR, issueID, worker, project, locks, mqSubmitPriority); err != nil {
+			return err
+		}
+		priority = existingMR.Priority
 	} else {
+		// Refuse a new MR past the worker's WIP limit
+		if !mqSubmitIgnoreWIP {
+			if err := checkSubmitWIP(filepath.Join(townRoot, rigName), bd, worker); err != nil {
+				return err
+			}
+		}
+
+		// Assign reviewers when the rig requires approvals
+		reviewers = assignMRReviewers(bd, filepath.Join(townRoot, rigName), rigName, worker)
+		if len(reviewers) > 0 {
+			description += "\nreviewers: " + strings.Join(reviewers, ",")
+		}
+
+		// The description body follows the structured fields
+		body, err := submitMRBody(filepath.Join(townRoot, rigName), bd, issueID)
+		if err != nil {
+			return err
+		}
+		if body != "" {
+			description += "\n\n" + body
+		}
+
 		// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 		mrIssue, err = bd.Create(beads.CreateOptions{
 			Title:       title,
@@ -190,7 +284,10 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("creating merge request bead: %w", err)
 		}
+		_ = events.LogFeed(events.TypeMRSubmitted, detectSender(),
+			events.MRPayload(rigName, mrIssue.ID, issueID, branch, ""))
 	}
+	labelMR(bd, mrIssue.ID, labels)
 
 	// Success output
 	fmt.Printf("%s Submitted to merge queue\n", style.Bold.Render("✓"))
@@ -202,6 +299,30 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if project != "" {
+		fmt.Printf("  Project: %s\n", project)
+	}
+	if len(locks) > 0 {
+		fmt.Printf("  Locks: %s\n", strings.Join(locks, ", "))
+	}
+	if len(labels) > 0 {
+		fmt.Printf("  Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if len(reviewers) > 0 {
+		fmt.Printf("  Reviewers: %s\n", strings.Join(reviewers, ", "))
+		notifyMRReviewers(townRoot, reviewers, mrIssue.ID, branch, issueID)
+	}
+	if len(owners) > 0 {
+		fmt.Println("  Owners (sign-off required):")
+		for _, a := range owners {
+			fmt.Printf("    %s: %s %s\n", a.Path, strings.Join(a.Owners, " or "),
+				style.Dim.Render(fmt.Sprintf("(%d file(s))", len(a.Files))))
+		}
+	}
+
+	if mqSubmitWatch {
+		return watchMR(bd, mrIssue.ID, mqSubmitTimeout)
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
@@ -220,6 +341,421 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// updateSubmittedMR brings an MR already open for the branch and target up
+// to date with a new submit, rather than queueing a duplicate: its worker,
+// project and locks are refreshed, its priority set if given (>= 0), and
+// if it was sent back for changes it is resubmitted. An MR for another source issue, or one the
+// refinery is merging, is left alone and the submit refused.
+func updateSubmittedMR(townRoot string, bd *beads.Beads, mr *beads.Issue, issueID, worker, project string, locks []string, priority int) error {
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", mr.ID)
+	}
+	if fields.SourceIssue != "" && fields.SourceIssue != issueID {
+		return fmt.Errorf("%s already merges %s into %s, for %s (not %s); use --force-new to submit another MR",
+			mr.ID, fields.Branch, fields.Target, fields.SourceIssue, issueID)
+	}
+	state := refinery.StateOf(mr)
+	if state.Active() {
+		return fmt.Errorf("%s for %s is %s, so can't be updated; wait for it (gt mq status %s) or use --force-new",
+			mr.ID, fields.Branch, state, mr.ID)
+	}
+
+	if worker != "" {
+		fields.Worker = worker
+	}
+	if project != "" {
+		fields.Project = project
+	}
+	if len(locks) > 0 {
+		fields.Locks = strings.Join(locks, ",")
+	}
+	opts := beads.UpdateOptions{}
+	if desc := beads.SetMRFields(mr, fields); desc != mr.Description {
+		opts.Description = &desc
+	}
+	if priority >= 0 && priority != mr.Priority {
+		opts.Priority = &priority
+	}
+	if opts.Description != nil || opts.Priority != nil {
+		if err := bd.Update(mr.ID, opts); err != nil {
+			return fmt.Errorf("updating %s: %w", mr.ID, err)
+		}
+		if opts.Description != nil {
+			mr.Description = *opts.Description
+		}
+		if opts.Priority != nil {
+			mr.Priority = priority
+		}
+	}
+
+	if state == refinery.StateChangesRequested {
+		if err := resubmitMR(townRoot, bd, mr); err != nil {
+			return fmt.Errorf("resubmitting %s: %w", mr.ID, err)
+		}
+		return nil
+	}
+	fmt.Printf("%s Updated %s, already queued for this branch (--force-new submits another)\n", style.Bold.Render("✓"), mr.ID)
+	return nil
+}
+
+// mrLabels returns the labels an MR for source gets: source's own (other
+// than Gas Town's), then extra, without repeats.
+func mrLabels(source *beads.Issue, extra []string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, label := range append(beads.DisplayLabels(source), extra...) {
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// labelMR adds labels to an MR bead. Non-fatal: the MR is already queued.
+func labelMR(bd *beads.Beads, mrID string, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	if err := bd.Update(mrID, beads.UpdateOptions{AddLabels: labels}); err != nil {
+		style.PrintWarning("could not label %s: %v", mrID, err)
+	}
+}
+
+// checkSubmitProtection validates the rig's diff-based branch protection
+// rules (forbidden paths, diff size) against branch. Check and approval
+// rules can't be met before the MR exists, so the refinery enforces those.
+func checkSubmitProtection(rigPath string, g *git.Git, branch, target string) error {
+	protection, err := refinery.LoadBranchProtection(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading branch protection: %w", err)
+	}
+	if protection == nil {
+		return nil
+	}
+
+	change, err := refinery.DiffMRChange(g, submitDiffBase(g, target), branch)
+	if err != nil {
+		// Non-fatal: the refinery checks again before merging
+		style.PrintWarning("could not check branch protection: %v", err)
+		return nil
+	}
+	if violations := protection.CheckChange(change); len(violations) > 0 {
+		return withExitCode(ExitCheckFailed, fmt.Errorf("%s", refinery.FormatViolations(violations)))
+	}
+	return nil
+}
+
+// checkSubmitCommits validates the subjects of branch's commits against
+// the rig's merge_queue.commit_messages convention.
+func checkSubmitCommits(rigPath string, g *git.Git, branch, target string) error {
+	policy, err := refinery.LoadCommitPolicy(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading commit message policy: %w", err)
+	}
+	if policy == nil {
+		return nil
+	}
+
+	violations, err := policy.CheckCommits(g, submitDiffBase(g, target), branch)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return withExitCode(ExitCheckFailed, fmt.Errorf("%s\nReword them (git rebase -i %s) before submitting", policy.FormatCommitViolations(violations), submitDiffBase(g, target)))
+	}
+	return nil
+}
+
+// submitMROwners returns the owner sign-offs branch needs under the rig's
+// merge_queue.owners. Best-effort: the refinery checks again before merging.
+func submitMROwners(rigPath string, g *git.Git, branch, target string) []refinery.OwnerApproval {
+	ownership, err := refinery.LoadOwnership(rigPath)
+	if err != nil {
+		style.PrintWarning("could not load owners: %v", err)
+		return nil
+	}
+	if ownership == nil {
+		return nil
+	}
+	change, err := refinery.DiffMRChange(g, submitDiffBase(g, target), branch)
+	if err != nil {
+		style.PrintWarning("could not check owners: %v", err)
+		return nil
+	}
+	return ownership.Approvals(change.Files, nil)
+}
+
+// submitMRProject returns the monorepo project (merge_queue.projects)
+// branch's changes all fall in, or "". Best-effort: an MR without one is
+// tested and queued as the rig's.
+func submitMRProject(rigPath string, g *git.Git, branch, target string) string {
+	projects, err := refinery.LoadProjects(rigPath)
+	if err != nil {
+		style.PrintWarning("could not load merge projects: %v", err)
+		return ""
+	}
+	if projects == nil {
+		return ""
+	}
+	change, err := refinery.DiffMRChange(g, submitDiffBase(g, target), branch)
+	if err != nil {
+		style.PrintWarning("could not match a merge project: %v", err)
+		return ""
+	}
+	return projects.Match(change.Files)
+}
+
+// submitMRLocks returns the path locks (merge_queue.locks) an MR for
+// branch merges under: those declared, plus those its changes touch.
+// Detection is best-effort: problems are warned about and only the
+// declared locks are taken.
+func submitMRLocks(rigPath string, g *git.Git, branch, target string, declared []string) []string {
+	locks, err := refinery.LoadPathLocks(rigPath)
+	if err != nil {
+		style.PrintWarning("could not load path locks: %v", err)
+		return refinery.MergeLocks(declared, nil)
+	}
+	if locks == nil {
+		return refinery.MergeLocks(declared, nil)
+	}
+	change, err := refinery.DiffMRChange(g, submitDiffBase(g, target), branch)
+	if err != nil {
+		style.PrintWarning("could not detect path locks: %v", err)
+		return refinery.MergeLocks(declared, nil)
+	}
+	return refinery.MergeLocks(declared, locks.Detect(change.Files))
+}
+
+// submitDiffBase returns the ref to diff a branch for target against:
+// origin's target if fetched, else the local one.
+func submitDiffBase(g *git.Git, target string) string {
+	base := "origin/" + target
+	if _, err := g.Rev(base); err != nil {
+		base = target
+	}
+	return base
+}
+
+// checkSubmitWIP enforces the rig's per-worker open MR limit. MRs from
+// polecat branches count against the polecat; others against the submitter.
+func checkSubmitWIP(rigPath string, bd *beads.Beads, worker string) error {
+	limits, err := wip.Load(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading WIP limits: %w", err)
+	}
+	if limits.MaxOpenMRs() == 0 {
+		return nil
+	}
+	if worker == "" {
+		worker = strings.TrimSuffix(detectSender(), "/")
+	}
+
+	mrs, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "open", Priority: -1})
+	if err != nil {
+		// Non-fatal: a limit we can't count is no reason to lose the work
+		style.PrintWarning("could not check WIP limit: %v", err)
+		return nil
+	}
+	inProgress, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "in_progress", Priority: -1})
+	if err != nil {
+		style.PrintWarning("could not check WIP limit: %v", err)
+		return nil
+	}
+	if err := limits.CheckOpenMRs(worker, append(mrs, inProgress...)); err != nil {
+		return fmt.Errorf("%w\nLand or close some first, or use --ignore-wip", err)
+	}
+	return nil
+}
+
+// checkSubmitClaim refuses to submit work for an issue under another
+// worker's live claim. worker is from the branch name; if the branch
+// doesn't carry one, the caller's identity is used.
+func checkSubmitClaim(bd *beads.Beads, issueID, worker string) error {
+	if worker == "" {
+		worker = strings.TrimSuffix(detectSender(), "/")
+	}
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		// Missing issues are handled (or tolerated) by the rest of submit
+		return nil
+	}
+	if err := claim.Check(issue, worker, time.Now()); err != nil {
+		return fmt.Errorf("%w\nUse --ignore-claim to submit anyway", err)
+	}
+	return nil
+}
+
+// submitMRBody returns the MR description body: read from --body-file,
+// or the rig's description template, filled in with $EDITOR when run
+// interactively. Returns "" if there's neither.
+func submitMRBody(rigPath string, bd *beads.Beads, issueID string) (string, error) {
+	tmpl, err := refinery.LoadMRDescription(rigPath)
+	if err != nil {
+		return "", fmt.Errorf("loading MR description template: %w", err)
+	}
+
+	if mqSubmitBodyFile != "" {
+		var data []byte
+		if mqSubmitBodyFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(mqSubmitBodyFile)
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading --body-file: %w", err)
+		}
+		return tmpl.Parse(string(data))
+	}
+	if tmpl == nil {
+		return "", nil
+	}
+
+	summary := issueID
+	if source, err := bd.Show(issueID); err == nil {
+		summary = fmt.Sprintf("%s (%s)", source.Title, issueID)
+	}
+	text := tmpl.Render(map[string]string{tmpl.Sections()[0]: summary})
+
+	editPath := ""
+	if !mqSubmitNoEdit && term.IsTerminal(int(os.Stdin.Fd())) && ui.IsTerminal() {
+		editPath, text, err = editDescription("gt-mr-*.md", text)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	body, err := tmpl.Parse(text)
+	var missing *refinery.MissingSectionsError
+	if errors.As(err, &missing) {
+		if editPath != "" {
+			return "", fmt.Errorf("%w (your edits are in %s; fix them up and pass --body-file)", err, editPath)
+		}
+		return "", fmt.Errorf("%w: fill them in with --body-file", err)
+	}
+	if editPath != "" {
+		_ = os.Remove(editPath)
+	}
+	return body, err
+}
+
+// editDescription opens text in $VISUAL or $EDITOR (default vi), in a temp
+// file named by pattern, and returns the file and the edited text.
+func editDescription(pattern, text string) (string, string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", "", fmt.Errorf("creating description file: %w", err)
+	}
+	path := f.Name()
+	_, err = f.WriteString(text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("writing description file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	argv := append(strings.Fields(editor), path)
+	editCmd := exec.Command(argv[0], argv[1:]...) //nolint:gosec // G204: editor is chosen by the user
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return "", "", fmt.Errorf("running editor %s: %w (description left in %s)", argv[0], err, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("reading description file: %w", err)
+	}
+	return path, string(data), nil
+}
+
+// assignMRReviewers picks reviewers from the rig's reviewer pool for a new
+// MR. Failures are non-fatal: the MR is still submitted, just unassigned.
+func assignMRReviewers(bd *beads.Beads, rigPath, rigName, worker string) []string {
+	pool, err := refinery.LoadReviewerPool(rigPath)
+	if err != nil {
+		style.PrintWarning("could not load reviewer pool: %v", err)
+		return nil
+	}
+	if pool == nil {
+		return nil
+	}
+
+	author := strings.TrimSuffix(detectSender(), "/")
+	if worker != "" {
+		author = rigName + "/" + worker
+	}
+
+	var load map[string]int
+	if pool.Strategy() == refinery.ReviewAssignLoad {
+		load, err = openReviewLoad(bd)
+		if err != nil {
+			style.PrintWarning("could not compute reviewer load: %v", err)
+		}
+	}
+
+	reviewers, err := pool.Assign(author, load)
+	if err != nil {
+		style.PrintWarning("%v", err)
+	}
+	return reviewers
+}
+
+// openReviewLoad counts open MRs assigned to each reviewer.
+func openReviewLoad(bd *beads.Beads) (map[string]int, error) {
+	issues, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "open", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	load := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Status != "open" {
+			continue
+		}
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			for _, r := range refinery.SplitMRList(fields.Reviewers) {
+				load[r]++
+			}
+		}
+	}
+	return load, nil
+}
+
+// notifyMRReviewers mails each assigned reviewer. Best-effort.
+func notifyMRReviewers(townRoot string, reviewers []string, mrID, branch, issueID string) {
+	router := mail.NewRouter(townRoot)
+	for _, reviewer := range reviewers {
+		msg := &mail.Message{
+			From:    strings.TrimSuffix(detectSender(), "/"),
+			To:      reviewer,
+			Subject: fmt.Sprintf("Review requested: %s", mrID),
+			Body: fmt.Sprintf(`You have been assigned to review a merge request.
+
+MR: %s
+Branch: %s
+Issue: %s
+
+Approve:          gt mq approve %s
+Request changes:  gt mq request-changes %s --summary "..."`,
+				mrID, branch, issueID, mrID, mrID),
+			Priority: mail.PriorityNormal,
+		}
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("could not notify reviewer %s: %v", reviewer, err)
+		}
+	}
+}
+
 // detectIntegrationBranch checks if an issue is a descendant of an epic that has an integration branch.
 // Traverses up the parent chain until it finds an epic or runs out of parents.
 // Returns the integration branch target (e.g., "integration/gt-epic") if found, or "" if not.
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 )
 
 func TestAddIntegrationBranchField(t *testing.T) {
@@ -179,6 +181,11 @@ func TestGetDescriptionWithoutMRFields(t *testing.T) {
 			description: "Just a regular description\nWith multiple lines",
 			want:        "Just a regular description\nWith multiple lines",
 		},
+		{
+			name:        "description body kept as written",
+			description: "branch: polecat/Nux/gt-xyz\ntarget: main\n\n## Testing\n\ntarget: staging only",
+			want:        "## Testing\n\ntarget: staging only",
+		},
 	}
 
 	for _, tt := range tests {
@@ -801,3 +808,128 @@ func TestMRFilteringByLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveMRWatchState(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue *beads.Issue
+		want  string
+	}{
+		{"open unclaimed", &beads.Issue{Status: "open"}, mrWatchQueued},
+		{"open claimed", &beads.Issue{Status: "open", Assignee: "refinery-1"}, mrWatchProcessing},
+		{"in progress", &beads.Issue{Status: "in_progress"}, mrWatchProcessing},
+		{"blocked", &beads.Issue{Status: "open", BlockedBy: []string{"gt-task"}}, mrWatchBlocked},
+		{"changes requested", &beads.Issue{Status: "open", Description: "branch: b\nstate: changes_requested"}, mrWatchBlocked},
+		{"merged", &beads.Issue{Status: "closed", Description: "branch: b\nclose_reason: merged"}, mrWatchMerged},
+		{"rejected", &beads.Issue{Status: "closed", Description: "branch: b"}, mrWatchClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveMRWatchState(tt.issue); got != tt.want {
+				t.Errorf("deriveMRWatchState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMRWatchOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		issue    *beads.Issue
+		baseline string
+		wantDone bool
+		wantCode int
+	}{
+		{"queued", &beads.Issue{Status: "open", Description: "branch: b\nstate: queued"}, "", false, 0},
+		{"checking", &beads.Issue{Status: "in_progress", Description: "branch: b\nstate: checking"}, "", false, 0},
+		{"merged", &beads.Issue{Status: "closed", Description: "branch: b\nstate: merged"}, "", true, 0},
+		{"failed", &beads.Issue{Status: "closed", Description: "branch: b\nstate: failed\nclose_reason: failed"}, "", true, ExitCheckFailed},
+		{"legacy conflict close", &beads.Issue{Status: "closed", Description: "branch: b\nclose_reason: conflict"}, "", true, ExitMergeConflict},
+		{"rejected", &beads.Issue{Status: "closed", Description: "branch: b\nstate: rejected"}, "", true, ExitError},
+		{"changes requested", &beads.Issue{Status: "open", Description: "branch: b\nstate: changes_requested"}, "", true, ExitError},
+		{"conflict task", &beads.Issue{Status: "open", BlockedBy: []string{"gt-task"}, Description: "branch: b\nstate: queued\nconflict_task_id: gt-task"}, "", true, ExitMergeConflict},
+		{"blocked on something else", &beads.Issue{Status: "open", BlockedBy: []string{"gt-dep"}, Description: "branch: b\nstate: queued"}, "", false, 0},
+		{"tests failed since watch began", &beads.Issue{Status: "open", Description: "branch: b\nstate: queued\ntest_results: failed duration=3s"}, "", true, ExitCheckFailed},
+		{"earlier failed run", &beads.Issue{Status: "open", Description: "branch: b\nstate: queued\ntest_results: failed duration=3s"}, "failed duration=3s", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.issue.ID = "gt-mr-1"
+			done, err := mrWatchOutcome(tt.issue, tt.baseline)
+			if done != tt.wantDone {
+				t.Fatalf("done = %v (err %v), want %v", done, err, tt.wantDone)
+			}
+			if done && exitCodeFor(err) != tt.wantCode {
+				t.Errorf("exit code = %d (err %v), want %d", exitCodeFor(err), err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestMergeMRList(t *testing.T) {
+	tests := []struct {
+		list  string
+		items []string
+		want  string
+	}{
+		{"", []string{"tests"}, "tests"},
+		{"tests", []string{"lint", "tests"}, "tests,lint"},
+		{"tests, lint", []string{" ", "build"}, "tests,lint,build"},
+	}
+	for _, tt := range tests {
+		if got := mergeMRList(tt.list, tt.items); got != tt.want {
+			t.Errorf("mergeMRList(%q, %q) = %q, want %q", tt.list, tt.items, got, tt.want)
+		}
+	}
+}
+
+func TestMRReviewHelpers(t *testing.T) {
+	fields := &beads.MRFields{Worker: "Nux", Rig: "greenplace"}
+	if !isOwnMR("greenplace/Nux", fields) || !isOwnMR("greenplace/polecats/Nux", fields) || !isOwnMR("greenplace/crew/Nux", fields) {
+		t.Error("a worker should not be able to approve its own MR, whatever its role")
+	}
+	if isOwnMR("othertown/crew/Nux", fields) || isOwnMR("mayor", fields) || isOwnMR("greenplace/crew/max", fields) {
+		t.Error("other reviewers are not the MR's worker")
+	}
+	if !isOwnMR("mayor/", &beads.MRFields{Worker: "mayor"}) {
+		t.Error("the mayor should not be able to approve its own MR")
+	}
+
+	if got := removeMRListItem("mayor,overseer,greenplace/crew/max", "overseer"); got != "mayor,greenplace/crew/max" {
+		t.Errorf("removeMRListItem = %q", got)
+	}
+}
+
+func TestMRLabels(t *testing.T) {
+	source := &beads.Issue{Labels: []string{"gt:task", "infra", "claimed-by:greenplace/polecats/nux"}}
+	got := mrLabels(source, []string{"urgent", "infra"})
+	want := []string{"infra", "urgent"}
+	if len(got) != len(want) {
+		t.Fatalf("mrLabels = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mrLabels = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestChangesTaskDescription(t *testing.T) {
+	fields := &beads.MRFields{Branch: "polecat/Nux/gp-1", SourceIssue: "gp-1"}
+	comment := refinery.MRComment{Body: "Cap retries at 5", File: "internal/retry.go", Line: 42}
+	desc := changesTaskDescription("gp-mr-abc", fields, "mayor", comment)
+	for _, want := range []string{
+		"- MR: gp-mr-abc",
+		"- Branch: polecat/Nux/gp-1",
+		"- Requested by: mayor",
+		"- At: internal/retry.go:42",
+		"## Requested Changes\nCap retries at 5",
+		"git checkout polecat/Nux/gp-1",
+		"gt mq submit",
+	} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("changes task description missing %q:\n%s", want, desc)
+		}
+	}
+}
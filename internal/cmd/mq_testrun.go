@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Test command flags
+var (
+	mqTestDir   string
+	mqTestCheck string
+)
+
+var mqTestCmd = &cobra.Command{
+	Use:   "test <rig> <mr-id> [-- <command>...]",
+	Short: "Run a merge request's tests and record the results on it",
+	Long: `Run the rig's test command and record the results on the merge request.
+
+The command is merge_queue.test_command from the rig's settings (or the
+test_command of the monorepo project the MR was submitted in; see
+merge_queue.projects), or the one given after --. It runs through the shell in --dir (default: the current
+directory, e.g. the merge worktree), with its output passed through. If
+merge_queue.checks sets a runner for the check (--check), it runs there
+instead (in a docker container or nix shell), within its limits.
+
+The output of go test, pytest, cargo test and jest is parsed for pass,
+fail and skip counts and the names of failing tests (go test lists passing
+tests only with -v). Those, the outcome and the duration are stored on the
+MR bead, replacing any earlier run's, and shown by 'gt mq status'. Output
+from other runners records just the outcome and duration. A run of the
+configured command also marks the check passed or failed on the MR, for
+branch protection's required_checks; a command given after -- does not.
+
+A failure is retried if the check (--check, default "tests") has a
+history of flaking: see merge_queue.flaky and 'gt refinery flakes'. A run
+that passes on retry is annotated on the MR as flaky.
+
+Exits with code 8 if the tests fail.
+
+Examples:
+  gt mq test greenplace gp-mr-abc123 --dir "$WT"
+  gt mq test greenplace gp-mr-abc123 -- go test -v ./...`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMQTest,
+}
+
+func init() {
+	mqTestCmd.Flags().StringVar(&mqTestDir, "dir", "", "Directory to run the tests in (default: current directory)")
+	mqTestCmd.Flags().StringVar(&mqTestCheck, "check", refinery.CheckTests, "Check name the run is tracked under for flake detection")
+
+	mqCmd.AddCommand(mqTestCmd)
+}
+
+// MRTestOutput is the structured output for gt mq test.
+type MRTestOutput struct {
+	ID      string                `json:"id"`
+	Command string                `json:"command"`
+	Tests   *refinery.TestResults `json:"tests"`
+}
+
+func runMQTest(cmd *cobra.Command, args []string) error {
+	rigName, mrID := args[0], args[1]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.BeadsPath())
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName))
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request (no MR fields)", mrID)
+	}
+
+	// An MR in a monorepo project is tested with the project's command.
+	// Only that command's runs count toward required checks.
+	testCmd := strings.Join(args[2:], " ")
+	recordCheck := ""
+	if testCmd == "" {
+		projects, err := refinery.LoadProjects(r.Path)
+		if err != nil {
+			return fmt.Errorf("loading merge projects: %w", err)
+		}
+		if projects.SkipsTests(fields.Project) {
+			if structuredOutput(false) {
+				return renderStructured(MRTestOutput{ID: issue.ID})
+			}
+			fmt.Printf("%s %s is in project %s, which runs no tests\n", style.Dim.Render("○"), mrID, fields.Project)
+			return nil
+		}
+		testCmd = projects.TestCommand(fields.Project, getTestCommand(r.Path))
+		recordCheck = mqTestCheck
+	}
+	if testCmd == "" {
+		return fmt.Errorf("rig '%s' has no merge_queue.test_command; give the command after --", rigName)
+	}
+
+	history, err := refinery.LoadFlakeHistory(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading flaky check settings: %w", err)
+	}
+
+	// Pass the output through. Structured output owns stdout, so the
+	// tests' goes to stderr.
+	var passthrough io.Writer = os.Stdout
+	if structuredOutput(false) {
+		passthrough = os.Stderr
+	}
+	runner, err := refinery.LoadCheckRunner(r.Path, mqTestCheck)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if runner.TimeoutOr(0) > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runner.Timeout)
+		defer cancel()
+	}
+	run := refinery.CheckCommand{
+		Command:  testCmd,
+		Dir:      mqTestDir,
+		Attempts: 1 + history.Retries(mqTestCheck),
+		Output:   passthrough,
+		Runner:   runner,
+		Retrying: func(attempt, attempts int) {
+			fmt.Fprintf(os.Stderr, "\n%s\n", style.Dim.Render(fmt.Sprintf("Retrying %s (attempt %d/%d)...", mqTestCheck, attempt, attempts)))
+		},
+	}.Run(ctx)
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		style.PrintWarning("%s timed out after %v", mqTestCheck, runner.Timeout)
+	case run.Err != nil && !errors.As(run.Err, &exitErr):
+		return fmt.Errorf("running %s: %w", testCmd, run.Err)
+	}
+	if err := history.Record(mqTestCheck, issue.ID, run); err != nil {
+		style.PrintWarning("%v", err)
+	}
+	tests := run.Tests
+
+	if err := refinery.RecordTestResults(bd, issue, recordCheck, tests); err != nil {
+		return err
+	}
+
+	if structuredOutput(false) {
+		if err := renderStructured(MRTestOutput{ID: issue.ID, Command: testCmd, Tests: tests}); err != nil {
+			return err
+		}
+	} else {
+		printMQTest(issue.ID, tests)
+	}
+	if !tests.OK {
+		return NewSilentExit(ExitCheckFailed)
+	}
+	return nil
+}
+
+func printMQTest(mrID string, tests *refinery.TestResults) {
+	fmt.Println()
+	if tests.OK {
+		fmt.Printf("%s Tests passed for %s: %s\n", style.Success.Render("✓"), mrID, tests)
+		return
+	}
+	fmt.Printf("%s Tests failed for %s: %s\n", style.Error.Render("✗"), mrID, tests)
+	printFailingTests(tests)
+}
+
+// printFailingTests lists a failed run's failing tests.
+func printFailingTests(tests *refinery.TestResults) {
+	for _, name := range tests.Failing {
+		fmt.Printf("  %s\n", name)
+	}
+	if tests.Failed > len(tests.Failing) {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("... and %d more", tests.Failed-len(tests.Failing))))
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Verify command flags
+var (
+	mqVerifyChecks []string
+	mqVerifyDryRun bool
+)
+
+var mqVerifyCmd = &cobra.Command{
+	Use:   "verify <rig> <mr-id-or-branch>",
+	Short: "Check an MR against the rig's branch protection rules",
+	Long: `Check a merge request against the rig's branch protection rules.
+
+Rules live in the rig's settings/config.json under merge_queue.protection:
+  required_checks  Check names that must have passed
+  min_approvals    Number of distinct approvals needed
+  forbidden_paths  Globs no MR may touch ("dir/**", "*.pem")
+  max_diff_lines   Limit on added plus deleted lines
+
+An outstanding 'gt mq request-changes' also blocks the merge, as does a
+missing approval on a high-risk MR when merge_queue.risk.require_approval
+is set, or on owned paths (merge_queue.owners) from one of their owners.
+So does any of the rig's merge policies (merge_queue.policies) whose deny
+rule holds, e.g. "lines > 500 && approvals < 2"; the violation names the
+policy and the values its rule saw.
+
+The Refinery runs this before merging. Checks count as passed only once a
+run of them is recorded on the MR (gt mq test, gt mq land); with --dry-run,
+--check asks what the verdict would be if the named checks had passed.
+
+While the queue is paused (rig parked or docked, or frozen by 'gt release')
+the command exits with code 6 and nothing should merge.
+
+If the diff breaks a rule (forbidden path or size), the MR is rejected and
+the worker is notified. If only checks, approvals, requested changes, or
+policies are outstanding, the MR stays queued. Either way the command
+exits with code 8 and the MR must not be merged.
+
+Examples:
+  gt mq verify greenplace gp-mr-abc123
+  gt mq verify greenplace polecat/Nux/gp-xyz --dry-run
+  gt mq verify greenplace gp-mr-abc123 --dry-run --check tests --check lint`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQVerify,
+}
+
+func init() {
+	mqVerifyCmd.Flags().StringArrayVar(&mqVerifyChecks, "check", nil, "With --dry-run, treat a check as passed (repeatable)")
+	mqVerifyCmd.Flags().BoolVar(&mqVerifyDryRun, "dry-run", false, "Report violations without rejecting")
+
+	mqCmd.AddCommand(mqVerifyCmd)
+}
+
+// MRVerifyOutput is the structured output for gt mq verify.
+type MRVerifyOutput struct {
+	ID         string                         `json:"id"`
+	Branch     string                         `json:"branch"`
+	Target     string                         `json:"target"`
+	Passed     bool                           `json:"passed"`
+	Rejected   bool                           `json:"rejected"`
+	Violations []refinery.ProtectionViolation `json:"violations,omitempty"`
+}
+
+func runMQVerify(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	if len(mqVerifyChecks) > 0 && !mqVerifyDryRun {
+		return fmt.Errorf("--check only applies with --dry-run; checks are recorded by the runs that make them (gt mq test)")
+	}
+
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	if reason := mergeQueuePausedReason(r); reason != "" {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is paused (%s)", rigName, reason))
+	}
+
+	mr, err := mgr.FindMR(args[1])
+	if err != nil {
+		if errors.Is(err, refinery.ErrMRNotFound) {
+			return withExitCode(ExitMRNotFound, fmt.Errorf("merge request '%s' not found in rig '%s'", args[1], rigName))
+		}
+		return fmt.Errorf("finding merge request: %w", err)
+	}
+
+	bd := beads.New(r.BeadsPath())
+	issue, err := bd.Show(mr.ID)
+	if err != nil {
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("merge request %s has no MR fields", mr.ID)
+	}
+
+	review := refinery.ReviewFromMR(issue, fields)
+	review.ChecksPassed = append(review.ChecksPassed, mqVerifyChecks...) // Only with --dry-run
+	violations, err := refinery.NewEngineer(r).CheckProtection(fields.Branch, fields.Target, review)
+	if err != nil {
+		return err
+	}
+
+	out := MRVerifyOutput{
+		ID:         mr.ID,
+		Branch:     fields.Branch,
+		Target:     fields.Target,
+		Passed:     len(violations) == 0,
+		Rejected:   !mqVerifyDryRun && refinery.HasChangeViolation(violations),
+		Violations: violations,
+	}
+
+	if out.Rejected {
+		if _, err := mgr.RejectMR(mr.ID, refinery.FormatViolations(violations), true); err != nil {
+			return fmt.Errorf("rejecting MR: %w", err)
+		}
+	}
+
+	if structuredOutput(false) {
+		if err := renderStructured(out); err != nil {
+			return err
+		}
+	} else {
+		printMQVerify(out)
+	}
+
+	if !out.Passed {
+		return NewSilentExit(ExitCheckFailed)
+	}
+	return nil
+}
+
+func printMQVerify(out MRVerifyOutput) {
+	if out.Passed {
+		fmt.Printf("%s %s satisfies branch protection\n", style.Success.Render("✓"), out.ID)
+		return
+	}
+
+	fmt.Printf("%s %s violates branch protection\n", style.Error.Render("✗"), out.ID)
+	for _, v := range out.Violations {
+		fmt.Printf("  %s %s\n", style.Dim.Render(v.Rule+":"), v.Reason)
+	}
+	if out.Rejected {
+		fmt.Printf("  %s\n", style.Dim.Render("MR rejected and worker notified"))
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render("MR stays queued until checks, review, and policies are satisfied"))
+	}
+}
+
+// mergeMRList adds items to a comma-separated MR field, skipping duplicates.
+func mergeMRList(list string, items []string) string {
+	existing := refinery.SplitMRList(list)
+	seen := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		seen[item] = true
+	}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" && !seen[item] {
+			existing = append(existing, item)
+			seen[item] = true
+		}
+	}
+	return strings.Join(existing, ",")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MR lifecycle states reported by gt mq submit --watch.
+// These are coarser than the MR's stored state (see refinery.MRState).
+const (
+	mrWatchQueued     = "queued"     // open, unclaimed, waiting for the refinery
+	mrWatchProcessing = "processing" // claimed or in_progress: merging and running checks
+	mrWatchBlocked    = "blocked"    // open but blocked (e.g. on a conflict-resolution task, or sent back for changes)
+	mrWatchMerged     = "merged"     // closed in state merged
+	mrWatchClosed     = "closed"     // closed without merging (rejected, superseded, ...)
+)
+
+// mrWatchPollInterval is how often --watch re-reads the MR bead.
+const mrWatchPollInterval = 5 * time.Second
+
+// deriveMRWatchState maps an MR bead to a coarse lifecycle state.
+func deriveMRWatchState(issue *beads.Issue) string {
+	switch issue.Status {
+	case "closed":
+		if refinery.StateOf(issue) == refinery.StateMerged {
+			return mrWatchMerged
+		}
+		return mrWatchClosed
+	case "in_progress":
+		return mrWatchProcessing
+	}
+	if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 || refinery.StateOf(issue) == refinery.StateChangesRequested {
+		return mrWatchBlocked
+	}
+	if issue.Assignee != "" {
+		return mrWatchProcessing
+	}
+	return mrWatchQueued
+}
+
+// mrWatchOutcome decides from the MR's recorded state whether the watch is
+// over, so an outcome is seen however briefly the MR was processing.
+// Returns done=true with a nil error when the MR merged, or with an
+// exit-coded error when it didn't: closed without merging, blocked on a
+// conflict-resolution task, sent back for changes, or returned to the queue
+// with a test run that failed since the watch began (baseline is the MR's
+// test_results then).
+func mrWatchOutcome(issue *beads.Issue, baseline string) (bool, error) {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	switch state := refinery.StateOf(issue); state {
+	case refinery.StateMerged:
+		return true, nil
+	case refinery.StateFailed:
+		if fields.CloseReason == string(refinery.CloseReasonConflict) {
+			return true, withExitCode(ExitMergeConflict, fmt.Errorf("merge request %s closed: merge conflict", issue.ID))
+		}
+		return true, withExitCode(ExitCheckFailed, fmt.Errorf("merge request %s failed", issue.ID))
+	case refinery.StateChangesRequested:
+		return true, fmt.Errorf("merge request %s sent back to its worker for changes", issue.ID)
+	case refinery.StateQueued:
+		if fields.ConflictTaskID != "" && (len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0) {
+			return true, withExitCode(ExitMergeConflict, fmt.Errorf("merge request %s blocked on conflict resolution (%s)", issue.ID, fields.ConflictTaskID))
+		}
+		if tests := refinery.TestResultsFromFields(fields); tests != nil && !tests.OK && fields.TestResults != baseline {
+			return true, withExitCode(ExitCheckFailed, fmt.Errorf("merge request %s failed its tests and was returned to the queue", issue.ID))
+		}
+	default:
+		if state.Terminal() {
+			return true, fmt.Errorf("merge request %s closed: %s", issue.ID, state)
+		}
+	}
+	return false, nil
+}
+
+// watchMR polls an MR bead and prints each state change until the MR reaches
+// a terminal outcome or timeout elapses (0 means wait indefinitely). A failed
+// outcome is returned, not printed: the top level prints it once and exits
+// with its code.
+func watchMR(bd *beads.Beads, mrID string, timeout time.Duration) error {
+	fmt.Println()
+	fmt.Printf("%s Watching %s (Ctrl+C to stop)\n", style.Dim.Render("◌"), mrID)
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	prev, baseline := "", ""
+	if issue, err := bd.Show(mrID); err == nil {
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			baseline = fields.TestResults
+		}
+	}
+	for {
+		issue, err := bd.Show(mrID)
+		if err != nil {
+			return fmt.Errorf("reading merge request %s: %w", mrID, err)
+		}
+
+		cur := deriveMRWatchState(issue)
+		if cur != prev {
+			ts := style.Dim.Render(time.Now().Format("15:04:05"))
+			if prev == "" {
+				fmt.Printf("  %s %s\n", ts, cur)
+			} else {
+				fmt.Printf("  %s %s → %s\n", ts, prev, cur)
+			}
+		}
+
+		done, outcome := mrWatchOutcome(issue, baseline)
+		if done {
+			if outcome != nil {
+				return outcome
+			}
+			fmt.Printf("%s Merged", style.Bold.Render("✓"))
+			if fields := beads.ParseMRFields(issue); fields != nil && fields.MergeCommit != "" {
+				fmt.Printf(" (commit: %s)", fields.MergeCommit)
+			}
+			fmt.Println()
+			return nil
+		}
+		prev = cur
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for %s (last state: %s)", timeout, mrID, cur)
+		}
+		time.Sleep(mrWatchPollInterval)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -231,36 +232,26 @@ func runNamepoolAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a rig directory")
 	}
 
-	// Load existing rig settings to get current theme and custom names
+	// Append the name to the rig's custom names in settings/config.json
+	// (the source of truth for config)
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err != nil {
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
+	exists := false
+	_, err := config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		if settings.Namepool == nil {
+			settings.Namepool = config.DefaultNamepoolConfig()
 		}
-	}
-
-	// Initialize namepool config if needed
-	if settings.Namepool == nil {
-		settings.Namepool = config.DefaultNamepoolConfig()
-	}
-
-	// Check if name already exists
-	for _, n := range settings.Namepool.Names {
-		if n == name {
-			fmt.Printf("Name '%s' already in pool\n", name)
-			return nil
+		exists = slices.Contains(settings.Namepool.Names, name)
+		if !exists {
+			settings.Namepool.Names = append(settings.Namepool.Names, name)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-
-	// Append new name to existing custom names
-	settings.Namepool.Names = append(settings.Namepool.Names, name)
-
-	// Save to settings/config.json (the source of truth for config)
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
-		return fmt.Errorf("saving settings: %w", err)
+	if exists {
+		fmt.Printf("Name '%s' already in pool\n", name)
+		return nil
 	}
 
 	// Note: No need to update runtime pool state - the settings file is the source
@@ -325,28 +316,13 @@ func detectCurrentRigWithPath() (string, string) {
 func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 
-	// Load existing settings or create new
-	var settings *config.RigSettings
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err != nil {
-		// Create new settings if not found
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
+	// Set namepool, creating the settings if needed
+	_, err := config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		settings.Namepool = &config.NamepoolConfig{
+			Style: theme,
+			Names: customNames,
 		}
-	}
-
-	// Set namepool
-	settings.Namepool = &config.NamepoolConfig{
-		Style: theme,
-		Names: customNames,
-	}
-
-	// Save (creates directory if needed)
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
-		return fmt.Errorf("saving settings: %w", err)
-	}
-
-	return nil
+		return nil
+	})
+	return err
 }
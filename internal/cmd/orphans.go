@@ -442,7 +442,7 @@ func runOrphansKill(cmd *cobra.Command, args []string) error {
 	if !orphansKillForce {
 		fmt.Printf("%s\n", style.Warning.Render("WARNING: This operation is irreversible!"))
 		total := len(filteredCommits) + len(procOrphans)
-		fmt.Printf("Remove %d orphan(s)? [y/N] ", total)
+		promptf("Remove %d orphan(s)? [y/N] ", total)
 		var response string
 		_, _ = fmt.Scanln(&response)
 		if strings.ToLower(strings.TrimSpace(response)) != "y" {
@@ -707,7 +707,7 @@ func runOrphansKillProcesses(cmd *cobra.Command, args []string) error {
 
 	// Confirm unless --force
 	if !orphansProcsForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(orphans))
+		promptf("Kill these %d process(es)? [y/N] ", len(orphans))
 		var response string
 		_, _ = fmt.Scanln(&response)
 		response = strings.ToLower(strings.TrimSpace(response))
@@ -784,7 +784,7 @@ func runOrphansKillProcessesAggressive() error {
 
 	// Confirm unless --force
 	if !orphansProcsForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(zombies))
+		promptf("Kill these %d process(es)? [y/N] ", len(zombies))
 		var response string
 		_, _ = fmt.Scanln(&response)
 		response = strings.ToLower(strings.TrimSpace(response))
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output flag.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+	OutputTSV   = "tsv"
+)
+
+var (
+	// outputFormat is the value of the global --output flag.
+	outputFormat string
+
+	// quietOutput is the value of the global --quiet flag.
+	quietOutput bool
+
+	// structuredOutputChecked records that the running command asked
+	// whether to emit structured output, i.e. that it honours --output.
+	structuredOutputChecked bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", OutputTable,
+		"Output format: table, json, yaml, csv, tsv")
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false,
+		"Suppress normal output; report status via exit code only (errors and prompts still go to stderr)")
+}
+
+// applyQuietMode discards normal stdout output and usage text when --quiet
+// is set. Errors still go to stderr and exit codes are unchanged, so scripts
+// can branch on the result. Structured output is never suppressed.
+func applyQuietMode(cmd *cobra.Command) error {
+	if !quietOutput || (outputFormat != "" && outputFormat != OutputTable) {
+		return nil
+	}
+	cmd.SilenceUsage = true
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", os.DevNull, err)
+	}
+	os.Stdout = devNull
+	return nil
+}
+
+// legacyQuietFlag adds a command's own --quiet (-q) that keeps the meaning
+// it had before the global flag. It shadows the global --quiet on that
+// command, so it is deprecated with change, the message saying how --quiet
+// will behave once it is removed.
+func legacyQuietFlag(cmd *cobra.Command, p *bool, usage, change string) {
+	cmd.Flags().BoolVarP(p, "quiet", "q", false, usage)
+	_ = cmd.Flags().MarkDeprecated("quiet", change)
+}
+
+// promptf prints a question the command waits for an answer to. --quiet
+// discards stdout, so there it goes to stderr instead: a prompt the user
+// can't see would just hang.
+func promptf(format string, args ...interface{}) {
+	w := io.Writer(os.Stdout)
+	if quietOutput {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
+// validateOutputFormat checks the --output flag value.
+func validateOutputFormat() error {
+	switch strings.ToLower(outputFormat) {
+	case "", OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputTSV:
+		outputFormat = strings.ToLower(outputFormat)
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q (valid: table, json, yaml, csv, tsv)", outputFormat)
+	}
+}
+
+// structuredOutput reports whether the command should emit machine-readable
+// output instead of its human table. legacyJSON is the command's own --json
+// flag, which is kept working as a shorthand for --output json.
+func structuredOutput(legacyJSON bool) bool {
+	structuredOutputChecked = true
+	return legacyJSON || outputFormat == OutputJSON || outputFormat == OutputYAML || tabularOutput()
+}
+
+// tabularOutput reports whether --output asks for CSV or TSV, for commands
+// that shape their output into rows for spreadsheets.
+func tabularOutput() bool {
+	structuredOutputChecked = true
+	return outputFormat == OutputCSV || outputFormat == OutputTSV
+}
+
+// warnIgnoredOutputFormat tells the user on stderr when --output asked for
+// a structured format but the command that ran has none, so it printed its
+// normal output instead.
+func warnIgnoredOutputFormat(cmd *cobra.Command) {
+	if cmd == nil || !cmd.Runnable() || outputFormat == "" || outputFormat == OutputTable || structuredOutputChecked {
+		return
+	}
+	if _, ok := outputSchemas[strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")]; ok {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s %s has no %s output; --output was ignored (see 'gt schema' for commands that have it)\n",
+		style.WarningPrefix, cmd.CommandPath(), outputFormat)
+}
+
+// renderStructured writes v to stdout in the selected structured format.
+// JSON is used unless --output yaml, csv or tsv was requested.
+func renderStructured(v interface{}) error {
+	return writeStructured(os.Stdout, v, outputFormat)
+}
+
+// writeStructured encodes v to w as JSON, YAML, CSV or TSV. YAML and the
+// tabular formats are produced from the JSON encoding so all formats share
+// field names and ordering.
+func writeStructured(w io.Writer, v interface{}, format string) error {
+	if format == OutputCSV || format == OutputTSV {
+		return writeTabular(w, v, format)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+
+	if format != OutputYAML {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(buf.Bytes(), &node); err != nil {
+		return fmt.Errorf("converting output to yaml: %w", err)
+	}
+	clearYAMLStyle(&node)
+
+	ye := yaml.NewEncoder(w)
+	ye.SetIndent(2)
+	if err := ye.Encode(&node); err != nil {
+		return fmt.Errorf("encoding yaml: %w", err)
+	}
+	return ye.Close()
+}
+
+// clearYAMLStyle resets the flow and quoting styles inherited from the JSON
+// source so the encoder emits block-style YAML. Strings that would otherwise
+// be read back as another type are still quoted by the encoder.
+func clearYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearYAMLStyle(c)
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tabularData is output already shaped into a header and rows, for commands
+// whose CSV/TSV columns aren't simply their JSON fields.
+type tabularData struct {
+	Header []string
+	Rows   [][]string
+}
+
+// writeTabular writes v to w as CSV or TSV. A tabularData is written as it
+// is; anything else is laid out from its JSON encoding: a list becomes one
+// row per item and an object a single row, with nested objects' fields as
+// dotted columns ("queue.peak"), lists of values joined with ";", and lists
+// of objects as JSON.
+func writeTabular(w io.Writer, v interface{}, format string) error {
+	table, ok := v.(tabularData)
+	if !ok {
+		var err error
+		if table, err = tabulate(v); err != nil {
+			return err
+		}
+	}
+
+	if format == OutputTSV {
+		// TSV has no quoting, so tabs and line breaks in cells become spaces
+		clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+		var sb strings.Builder
+		for _, row := range append([][]string{table.Header}, table.Rows...) {
+			for i, cell := range row {
+				row[i] = clean.Replace(cell)
+			}
+			sb.WriteString(strings.Join(row, "\t"))
+			sb.WriteString("\n")
+		}
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(table.Header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(table.Rows); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+	return nil
+}
+
+// tabulate lays v out as rows, from its JSON encoding. Decoding the JSON
+// into a YAML node keeps its fields in order.
+func tabulate(v interface{}) (tabularData, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return tabularData{}, fmt.Errorf("encoding output: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return tabularData{}, fmt.Errorf("converting output to rows: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return tabularData{}, nil
+	}
+
+	items := []*yaml.Node{doc.Content[0]}
+	if doc.Content[0].Kind == yaml.SequenceNode {
+		items = doc.Content[0].Content
+	}
+
+	var table tabularData
+	column := make(map[string]int)
+	var rows []map[string]string
+	for _, item := range items {
+		row := make(map[string]string)
+		if err := flattenNode(item, "", row, func(key string) {
+			if _, ok := column[key]; !ok {
+				column[key] = len(table.Header)
+				table.Header = append(table.Header, key)
+			}
+		}); err != nil {
+			return tabularData{}, err
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		cells := make([]string, len(table.Header))
+		for key, value := range row {
+			cells[column[key]] = value
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table, nil
+}
+
+// flattenNode adds n's cells to row, under prefix. addColumn is told about
+// each column in the order they're met.
+func flattenNode(n *yaml.Node, prefix string, row map[string]string, addColumn func(string)) error {
+	set := func(value string) {
+		key := prefix
+		if key == "" {
+			key = "value"
+		}
+		addColumn(key)
+		row[key] = value
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if err := flattenNode(n.Content[i+1], key, row, addColumn); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		var values []string
+		for _, c := range n.Content {
+			if c.Kind != yaml.ScalarNode {
+				var v interface{}
+				if err := n.Decode(&v); err != nil {
+					return err
+				}
+				data, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				set(string(bytes.TrimSpace(data)))
+				return nil
+			}
+			values = append(values, c.Value)
+		}
+		set(strings.Join(values, ";"))
+	case yaml.ScalarNode:
+		if n.Tag == "!!null" {
+			set("")
+		} else {
+			set(n.Value)
+		}
+	}
+	return nil
+}
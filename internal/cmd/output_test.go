@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "rewrite the published output schemas for the current schema versions")
+
+func TestWriteStructured(t *testing.T) {
+	item := RigListItem{Name: "greenplace", Polecats: 2, Agents: []string{"witness"}}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, item, OutputJSON); err != nil {
+			t.Fatalf("writeStructured: %v", err)
+		}
+		if !strings.Contains(buf.String(), `"name": "greenplace"`) {
+			t.Errorf("json output missing name field:\n%s", buf.String())
+		}
+	})
+
+	t.Run("yaml keeps field order and json names", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, item, OutputYAML); err != nil {
+			t.Fatalf("writeStructured: %v", err)
+		}
+		want := "name: greenplace\npolecats: 2\ncrew: 0\nagents:\n  - witness\n"
+		if buf.String() != want {
+			t.Errorf("yaml output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("csv lays out a list as rows", func(t *testing.T) {
+		items := []RigListItem{item, {Name: "oldtown", Agents: []string{"witness", "refinery"}, Error: "no, \"config\""}}
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, items, OutputCSV); err != nil {
+			t.Fatalf("writeStructured: %v", err)
+		}
+		want := "name,polecats,crew,agents,error\ngreenplace,2,0,witness,\noldtown,0,0,witness;refinery,\"no, \"\"config\"\"\"\n"
+		if buf.String() != want {
+			t.Errorf("csv output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("tsv flattens nested objects", func(t *testing.T) {
+		v := map[string]interface{}{"rig": "greenplace", "queue": map[string]int{"now": 3, "peak": 5}, "note": "a\tb\nc"}
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, v, OutputTSV); err != nil {
+			t.Fatalf("writeStructured: %v", err)
+		}
+		want := "note\tqueue.now\tqueue.peak\trig\na b c\t3\t5\tgreenplace\n"
+		if buf.String() != want {
+			t.Errorf("tsv output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("yaml quotes ambiguous strings", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, map[string]string{"v": "true"}, OutputYAML); err != nil {
+			t.Fatalf("writeStructured: %v", err)
+		}
+		if buf.String() != "v: \"true\"\n" {
+			t.Errorf("yaml output = %q, want quoted string", buf.String())
+		}
+	})
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	defer func(prev string) { outputFormat = prev }(outputFormat)
+
+	for _, ok := range []string{"table", "json", "YAML", "csv", "tsv", ""} {
+		outputFormat = ok
+		if err := validateOutputFormat(); err != nil {
+			t.Errorf("validateOutputFormat(%q) = %v, want nil", ok, err)
+		}
+	}
+
+	outputFormat = "xml"
+	if err := validateOutputFormat(); err == nil {
+		t.Error("validateOutputFormat(\"xml\") = nil, want error")
+	}
+}
+
+func TestWarnIgnoredOutputFormat(t *testing.T) {
+	defer func(format string, checked bool) {
+		outputFormat, structuredOutputChecked = format, checked
+	}(outputFormat, structuredOutputChecked)
+
+	warning := func() string {
+		t.Helper()
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stderr := os.Stderr
+		os.Stderr = w
+		warnIgnoredOutputFormat(versionCmd)
+		os.Stderr = stderr
+		w.Close()
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r)
+		return buf.String()
+	}
+
+	outputFormat, structuredOutputChecked = OutputJSON, false
+	if got := warning(); !strings.Contains(got, "--output was ignored") {
+		t.Errorf("gt version -o json warned %q, want --output ignored", got)
+	}
+	structuredOutputChecked = true
+	if got := warning(); got != "" {
+		t.Errorf("command honouring --output warned %q", got)
+	}
+	outputFormat, structuredOutputChecked = OutputTable, false
+	if got := warning(); got != "" {
+		t.Errorf("table output warned %q", got)
+	}
+}
+
+func TestJSONSchemaFor(t *testing.T) {
+	schema := jsonSchemaFor(reflect.TypeOf(RigListItem{}), map[reflect.Type]bool{})
+
+	if schema["type"] != "object" {
+		t.Fatalf("type = %v, want object", schema["type"])
+	}
+	props := schema["properties"].(map[string]interface{})
+	if _, ok := props["polecats"]; !ok {
+		t.Error("schema missing polecats property")
+	}
+	required := schema["required"].([]string)
+	for _, r := range required {
+		if r == "error" {
+			t.Error("omitempty field 'error' should not be required")
+		}
+	}
+}
+
+func TestOutputSchemasResolve(t *testing.T) {
+	for name, sample := range outputSchemas {
+		if schema := jsonSchemaFor(reflect.TypeOf(sample), map[reflect.Type]bool{}); schema["type"] == nil {
+			t.Errorf("schema for %q has no type", name)
+		}
+	}
+}
+
+// TestPublishedSchemas fails when a versioned command's output no longer
+// matches its published schema. For an additive change, refresh the file
+// with: go test ./internal/cmd -run TestPublishedSchemas -update-schemas
+// For a breaking one, bump the command's schema version first.
+func TestPublishedSchemas(t *testing.T) {
+	for name, version := range schemaVersions {
+		if _, ok := outputSchemas[name]; !ok {
+			t.Errorf("%q has a schema version but no output schema", name)
+			continue
+		}
+		path := publishedSchemaFile(name, version)
+		var want bytes.Buffer
+		if err := writeStructured(&want, reflectedSchema(name), OutputJSON); err != nil {
+			t.Fatal(err)
+		}
+		if *updateSchemas {
+			if err := os.WriteFile(filepath.FromSlash(path), want.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		data, err := publishedSchemas.ReadFile(path)
+		if err != nil {
+			t.Errorf("gt %s: schema version %d is not published (%s): %v", name, version, path, err)
+			continue
+		}
+		var published, current interface{}
+		if err := json.Unmarshal(data, &published); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if err := json.Unmarshal(want.Bytes(), &current); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(published, current) {
+			t.Errorf("gt %s output no longer matches %s: bump its schema version if the change breaks consumers, then run with -update-schemas", name, path)
+		}
+	}
+}
+
+func TestOutputSchemaVersions(t *testing.T) {
+	schema, err := outputSchema("mq status", 0)
+	if err != nil {
+		t.Fatalf("outputSchema: %v", err)
+	}
+	props := schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if v := props["schema_version"].(map[string]interface{})["const"]; v != float64(mqStatusSchemaVersion) {
+		t.Errorf("schema_version const = %v, want %d", v, mqStatusSchemaVersion)
+	}
+	if _, err := outputSchema("mq status", mqStatusSchemaVersion+1); err == nil {
+		t.Error("unpublished version should be an error")
+	}
+	if _, err := outputSchema("rig list", 1); err == nil {
+		t.Error("unversioned command should refuse a version")
+	}
+}
+
+func TestRunSchemaIfRequested(t *testing.T) {
+	if _, ok := runSchemaIfRequested([]string{"mq", "status", "gp-1"}); ok {
+		t.Error("handled without --schema")
+	}
+	if _, ok := runSchemaIfRequested([]string{"mq", "status", "--", "--schema"}); ok {
+		t.Error("--schema after -- should be left alone")
+	}
+}
+
+func TestLegacyQuietFlags(t *testing.T) {
+	t.Cleanup(func() {
+		mqNextIDOnly, downQuiet, quickAddQuiet, awaitSignalQuiet, quietOutput = false, false, false, false, false
+	})
+	for _, tc := range []struct {
+		cmd  *cobra.Command
+		args []string
+		set  *bool
+	}{
+		{mqNextCmd, []string{"-q"}, &mqNextIDOnly},
+		{downCmd, []string{"--quiet"}, &downQuiet},
+		{rigQuickAddCmd, []string{"-q"}, &quickAddQuiet},
+		{moleculeAwaitSignalCmd, []string{"--quiet"}, &awaitSignalQuiet},
+	} {
+		if err := tc.cmd.ParseFlags(tc.args); err != nil {
+			t.Fatalf("%s %v: %v", tc.cmd.Name(), tc.args, err)
+		}
+		if !*tc.set || quietOutput {
+			t.Errorf("%s %v: own flag = %v, global --quiet = %v; want only the command's own set",
+				tc.cmd.Name(), tc.args, *tc.set, quietOutput)
+		}
+		if f := tc.cmd.Flags().Lookup("quiet"); f == nil || f.Deprecated == "" {
+			t.Errorf("%s --quiet is not deprecated", tc.cmd.Name())
+		}
+	}
+}
+
+func TestFeedPlainIsGlobal(t *testing.T) {
+	t.Cleanup(func() { plainOutput = false })
+	if err := feedCmd.ParseFlags([]string{"--plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if !plainOutput {
+		t.Error("gt feed --plain did not set the global --plain")
+	}
+}
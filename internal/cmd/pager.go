@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// noPager is the value of the global --no-pager flag.
+var noPager bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false,
+		"Don't page long output (env: GT_NO_PAGER)")
+}
+
+// withPager runs fn, a listing that can run long, and shows what it
+// prints on stdout through $GT_PAGER or $PAGER (default less) when it's
+// taller than the terminal, like git does. Output goes straight to stdout
+// with --no-pager or GT_NO_PAGER, when stdout isn't a terminal, and for
+// structured output.
+func withPager(fn func() error) error {
+	if noPager || structuredOutput(false) || !ui.IsTerminal() {
+		return fn()
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fn()
+	}
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&buf, r)
+		_ = r.Close()
+		close(done)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	err = fn()
+	os.Stdout = stdout
+	_ = w.Close()
+	<-done
+
+	if pageErr := ui.ToPager(buf.String(), ui.PagerOptions{NoPager: noPager}); errors.Is(pageErr, exec.ErrNotFound) {
+		// No such pager: show the output as is
+		_, _ = stdout.Write(buf.Bytes())
+	}
+	return err
+}
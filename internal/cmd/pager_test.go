@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithPagerNotATerminal(t *testing.T) {
+	// Test output isn't a terminal, so fn runs directly
+	errList := errors.New("rig not found")
+	ran := false
+	err := withPager(func() error {
+		ran = true
+		return errList
+	})
+	if !ran {
+		t.Fatal("fn not run")
+	}
+	if !errors.Is(err, errList) {
+		t.Errorf("err = %v, want fn's error", err)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPageLimit is how many rows a listing command shows unless --limit
+// says otherwise.
+const defaultPageLimit = 100
+
+// listPage is a listing command's --limit and --offset, for paging through
+// queues too big to print at once.
+type listPage struct {
+	cmd    *cobra.Command
+	limit  int
+	offset int
+}
+
+// addPageFlags adds --limit and --offset to a listing command.
+func addPageFlags(cmd *cobra.Command, p *listPage) {
+	p.cmd = cmd
+	cmd.Flags().IntVar(&p.limit, "limit", defaultPageLimit, "Maximum number of rows to show (0 for all; JSON shows all unless set)")
+	cmd.Flags().IntVar(&p.offset, "offset", 0, "Skip this many rows first (to page through with --limit)")
+}
+
+// bounds returns where the page starts and ends among n rows. Structured
+// output is only paged when --limit is given, so scripts reading it see
+// every row by default.
+func (p *listPage) bounds(n int, structured bool) (start, end int, err error) {
+	if p.limit < 0 || p.offset < 0 {
+		return 0, 0, fmt.Errorf("--limit and --offset must not be negative")
+	}
+	limit := p.limit
+	if structured && (p.cmd == nil || !p.cmd.Flags().Changed("limit")) {
+		limit = 0
+	}
+	start = min(p.offset, n)
+	end = n
+	if limit > 0 {
+		end = min(start+limit, n)
+	}
+	return start, end, nil
+}
+
+// footer describes a page of rows start to end, of n, with how to get the
+// next one. Empty if the page holds every row.
+func (p *listPage) footer(start, end, n int) string {
+	switch {
+	case start == 0 && end == n:
+		return ""
+	case start == end:
+		return fmt.Sprintf("Nothing at offset %d (%d in all)", start, n)
+	case end < n:
+		return fmt.Sprintf("Showing %d-%d of %d (--offset %d for more)", start+1, end, n, end)
+	}
+	return fmt.Sprintf("Showing %d-%d of %d", start+1, end, n)
+}
+
+// paginate returns the page of items p selects, and its footer.
+func paginate[T any](p *listPage, items []T, structured bool) ([]T, string, error) {
+	start, end, err := p.bounds(len(items), structured)
+	if err != nil {
+		return nil, "", err
+	}
+	return items[start:end], p.footer(start, end, len(items)), nil
+}
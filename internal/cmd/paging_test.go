@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestListPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		limit, offset int
+		structured    bool
+		want          []int
+		footer        string
+	}{
+		{defaultPageLimit, 0, false, items, ""},
+		{2, 0, false, []int{1, 2}, "Showing 1-2 of 5 (--offset 2 for more)"},
+		{2, 4, false, []int{5}, "Showing 5-5 of 5"},
+		{0, 3, false, []int{4, 5}, "Showing 4-5 of 5"},
+		{2, 9, false, []int{}, "Nothing at offset 5 (5 in all)"},
+	}
+	for _, tt := range tests {
+		p := &listPage{limit: tt.limit, offset: tt.offset}
+		got, footer, err := paginate(p, items, tt.structured)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, tt.want) || footer != tt.footer {
+			t.Errorf("limit %d offset %d: got %v %q, want %v %q", tt.limit, tt.offset, got, footer, tt.want, tt.footer)
+		}
+	}
+
+	if _, _, err := paginate(&listPage{limit: -1}, items, false); err == nil {
+		t.Error("negative --limit should fail")
+	}
+}
+
+func TestListPageStructured(t *testing.T) {
+	cmd := &cobra.Command{Use: "list"}
+	var p listPage
+	addPageFlags(cmd, &p)
+	items := make([]int, defaultPageLimit+1)
+
+	// Without --limit, structured output gets every row
+	if got, _, _ := paginate(&p, items, true); len(got) != len(items) {
+		t.Errorf("structured, no --limit: %d rows", len(got))
+	}
+	if got, _, _ := paginate(&p, items, false); len(got) != defaultPageLimit {
+		t.Errorf("human, no --limit: %d rows", len(got))
+	}
+	if err := cmd.Flags().Set("limit", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := paginate(&p, items, true); len(got) != 3 {
+		t.Errorf("structured, --limit 3: %d rows", len(got))
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/permission"
+)
+
+// requirePermission checks that the caller may perform a destructive action
+// on target. The caller is identified by GT_ROLE; without it the caller is a
+// human operator (the current directory alone never lowers permissions).
+// owns reports whether target belongs to the caller and may be nil.
+// Denials are logged to the event feed and return a *permission.DeniedError.
+func requirePermission(townRoot string, action permission.Action, target string, owns func(RoleInfo) bool) error {
+	info, actor, err := callerIdentity(townRoot)
+	if err != nil {
+		return err
+	}
+	role := string(info.Role)
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings for permissions: %w", err)
+	}
+	level, err := permission.Resolve(actor, role, settings.Permissions)
+	if err != nil {
+		return fmt.Errorf("settings permissions for %s: %w", actor, err)
+	}
+
+	own := owns != nil && owns(info)
+	if permission.Allowed(level, action, own) {
+		return nil
+	}
+
+	_ = events.LogFeed(events.TypePermissionDenied, actor,
+		events.PermissionDeniedPayload(string(action), string(level), target))
+	return &permission.DeniedError{Actor: actor, Level: level, Action: action, Target: target}
+}
+
+// callerIdentity returns the caller's role and actor address. Without
+// GT_ROLE the caller is a human (permission.HumanActor) with an empty role.
+func callerIdentity(townRoot string) (RoleInfo, string, error) {
+	if os.Getenv(EnvGTRole) == "" {
+		return RoleInfo{}, permission.HumanActor, nil
+	}
+	cwd, _ := os.Getwd()
+	info, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return RoleInfo{}, "", fmt.Errorf("detecting role: %w", err)
+	}
+	return info, info.ActorString(), nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/permission"
+)
+
+func setupPermissionTown(t *testing.T, overrides map[string]string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir mayor: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatalf("write town.json: %v", err)
+	}
+	if overrides != nil {
+		settings := config.NewTownSettings()
+		settings.Permissions = overrides
+		if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+			t.Fatalf("save settings: %v", err)
+		}
+	}
+
+	originalWd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalWd) })
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	return townRoot
+}
+
+func TestRequirePermissionHumanIsOperator(t *testing.T) {
+	t.Setenv(EnvGTRole, "")
+	townRoot := setupPermissionTown(t, nil)
+
+	if err := requirePermission(townRoot, permission.ActionRigRemove, "greenplace", nil); err != nil {
+		t.Errorf("human should be allowed to remove a rig: %v", err)
+	}
+}
+
+func TestRequirePermissionPolecatOwnMR(t *testing.T) {
+	t.Setenv(EnvGTRole, "greenplace/polecats/Toast")
+	townRoot := setupPermissionTown(t, nil)
+
+	owns := func(info RoleInfo) bool {
+		return info.Role == RolePolecat && info.Rig == "greenplace" && info.Polecat == "Toast"
+	}
+	if err := requirePermission(townRoot, permission.ActionMQReject, "gp-mr-1", owns); err != nil {
+		t.Errorf("polecat should be allowed to reject its own MR: %v", err)
+	}
+
+	notOwned := func(RoleInfo) bool { return false }
+	err := requirePermission(townRoot, permission.ActionMQReject, "gp-mr-2", notOwned)
+	var denied *permission.DeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("expected DeniedError for another worker's MR, got %v", err)
+	}
+	if denied.Actor != "greenplace/polecats/Toast" || denied.Level != permission.LevelPolecat {
+		t.Errorf("denied = %+v", denied)
+	}
+	if exitCodeFor(err) != ExitPermissionDenied {
+		t.Errorf("exit code = %d, want %d", exitCodeFor(err), ExitPermissionDenied)
+	}
+
+	// The denial is logged to the event feed
+	data, readErr := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if readErr != nil {
+		t.Fatalf("reading events: %v", readErr)
+	}
+	if !strings.Contains(string(data), events.TypePermissionDenied) || !strings.Contains(string(data), "gp-mr-2") {
+		t.Errorf("events file missing permission_denied entry:\n%s", data)
+	}
+}
+
+func TestRequirePermissionSettingsOverride(t *testing.T) {
+	t.Setenv(EnvGTRole, "")
+	townRoot := setupPermissionTown(t, map[string]string{permission.HumanActor: "readonly"})
+
+	err := requirePermission(townRoot, permission.ActionCrewRemove, "greenplace/crew/max", nil)
+	var denied *permission.DeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("expected DeniedError for readonly human, got %v", err)
+	}
+}
+
+func TestRequirePermissionInvalidLevelFailsClosed(t *testing.T) {
+	t.Setenv(EnvGTRole, "")
+	townRoot := setupPermissionTown(t, map[string]string{"*": "superuser"})
+
+	err := requirePermission(townRoot, permission.ActionRigRemove, "greenplace", nil)
+	if err == nil {
+		t.Fatal("invalid permission level should block the action")
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/picker"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// argPicker picks a missing positional argument interactively, given the
+// arguments before it.
+type argPicker func(args []string) (string, error)
+
+// pickableArgs is cobra.ExactArgs(n), except that at a terminal trailing
+// arguments may be left off, to be picked by pickArgs.
+func pickableArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < n && isTerminal() {
+			return nil
+		}
+		return cobra.ExactArgs(n)(cmd, args)
+	}
+}
+
+// pickArgs fills in the arguments left off the end of args with the
+// pickers for those positions, one picker per positional argument. Agents
+// and scripts pass exact IDs, so it picks only for a human at a terminal;
+// otherwise a missing argument is an error, as cobra.ExactArgs reports it.
+func pickArgs(cmd *cobra.Command, args []string, pickers ...argPicker) ([]string, error) {
+	if len(args) >= len(pickers) {
+		return args, nil
+	}
+	if !canPick() {
+		return nil, fmt.Errorf("accepts %d arg(s), received %d", len(pickers), len(args))
+	}
+	for i := len(args); i < len(pickers); i++ {
+		arg, err := pickers[i](args)
+		if errors.Is(err, picker.ErrCancelled) {
+			cmd.SilenceUsage = true
+			return nil, NewSilentExit(ExitError)
+		}
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// isTerminal reports whether both stdin and stdout are terminals.
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// canPick reports whether a missing argument can be picked interactively.
+func canPick() bool {
+	return isTerminal() && !structuredOutput(false) && !style.Plain() && !ui.IsAgentMode()
+}
+
+// pickRig picks one of the town's rigs.
+func pickRig(_ []string) (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return "", fmt.Errorf("loading rigs config: %w", err)
+	}
+	items := make([]picker.Item, 0, len(rigsConfig.Rigs))
+	for name, entry := range rigsConfig.Rigs {
+		items = append(items, picker.Item{ID: name, Title: entry.GitURL})
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("no rigs in this town (add one with 'gt rig add')")
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	item, err := picker.Run("Pick a rig", items)
+	return item.ID, err
+}
+
+// pickMR returns a picker for the merge requests in the queue of the rig
+// named by the first argument that match keep (all of them if nil). what
+// describes them, e.g. "failed merge requests".
+func pickMR(what string, keep func(*refinery.MergeRequest) bool) argPicker {
+	return func(args []string) (string, error) {
+		rigName := args[0]
+		mgr, _, _, err := getRefineryManager(rigName)
+		if err != nil {
+			return "", err
+		}
+		queue, err := mgr.Queue()
+		if err != nil {
+			return "", err
+		}
+		var items []picker.Item
+		for _, qi := range queue {
+			if keep != nil && !keep(qi.MR) {
+				continue
+			}
+			title := qi.MR.Branch
+			if qi.MR.Error != "" {
+				title += "  " + qi.MR.Error
+			}
+			items = append(items, picker.Item{ID: qi.MR.ID, Title: title})
+		}
+		if len(items) == 0 {
+			return "", fmt.Errorf("no %s in rig '%s'", what, rigName)
+		}
+
+		item, err := picker.Run(fmt.Sprintf("Pick from the %s in %s", what, rigName), items)
+		return item.ID, err
+	}
+}
+
+// pickAnyMR picks a rig, then one of the merge requests in its queue.
+func pickAnyMR(_ []string) (string, error) {
+	rigName, err := pickRig(nil)
+	if err != nil {
+		return "", err
+	}
+	return pickMR("merge requests", nil)([]string{rigName})
+}
+
+// pickFailedMR picks one of the failed merge requests, queued or closed,
+// in the rig named by the first argument.
+func pickFailedMR(args []string) (string, error) {
+	rigName := args[0]
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return "", err
+	}
+	issues, err := beads.New(r.BeadsPath()).List(beads.ListOptions{
+		Type:     "merge-request",
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("querying merge requests: %w", err)
+	}
+	var items []picker.Item
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if failure, ok := failureOf(issue, fields); ok {
+			items = append(items, picker.Item{ID: issue.ID, Title: fields.Branch + "  " + failure.Kind})
+		}
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("no failed merge requests in rig '%s'", rigName)
+	}
+
+	item, err := picker.Run("Pick from the failed merge requests in "+rigName, items)
+	return item.ID, err
+}
+
+// isFailedMR reports whether an MR in the queue failed and can be retried.
+func isFailedMR(mr *refinery.MergeRequest) bool {
+	return mr.Error != ""
+}
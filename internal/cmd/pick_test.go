@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPickArgsNotATerminal(t *testing.T) {
+	picked := func([]string) (string, error) { return "greenplace", nil }
+
+	// Complete arguments pass through untouched
+	args, err := pickArgs(&cobra.Command{}, []string{"greenplace"}, picked)
+	if err != nil || len(args) != 1 {
+		t.Fatalf("pickArgs = %v, %v", args, err)
+	}
+
+	// Test output isn't a terminal, so a missing argument is an error
+	if _, err := pickArgs(&cobra.Command{}, nil, picked); err == nil {
+		t.Error("missing argument without a terminal should be an error")
+	}
+	if err := pickableArgs(1)(&cobra.Command{}, nil); err == nil {
+		t.Error("pickableArgs should require the argument without a terminal")
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// plainOutput is the value of the global --plain flag.
+var plainOutput bool
+
+// plainPipes are the filters applyPlainMode put in front of stdout and
+// stderr; finishPlainMode drains them.
+var plainPipes struct {
+	writers        []*os.File
+	stdout, stderr *os.File // the originals, restored by finishPlainMode
+	wg             sync.WaitGroup
+}
+
+// plainDrainTimeout bounds how long finishPlainMode waits for the filters:
+// a background process the command started may hold a pipe open.
+const plainDrainTimeout = 2 * time.Second
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false,
+		"No colors, icons or emoji: ASCII output for logs (env: GT_PLAIN; on when CI is set)")
+}
+
+// applyPlainMode turns on plain output when --plain is given or the
+// environment asks for it (see style.PlainFromEnv): styling is dropped,
+// and stdout and stderr are filtered through style.PlainText so the
+// icons and emoji printed directly by commands are rewritten too.
+// Structured output is left as it is.
+func applyPlainMode() error {
+	if !plainOutput && !style.PlainFromEnv() {
+		return nil
+	}
+	style.SetPlain()
+	plainPipes.stdout, plainPipes.stderr = os.Stdout, os.Stderr
+
+	if !structuredOutput(false) && !quietOutput {
+		filtered, err := plainPipe(os.Stdout)
+		if err != nil {
+			return err
+		}
+		os.Stdout = filtered
+	}
+	filtered, err := plainPipe(os.Stderr)
+	if err != nil {
+		return err
+	}
+	os.Stderr = filtered
+	return nil
+}
+
+// plainPipe returns a file whose writes reach dst as plain text.
+func plainPipe(dst *os.File) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	plainPipes.writers = append(plainPipes.writers, w)
+	plainPipes.wg.Add(1)
+	go func() {
+		defer plainPipes.wg.Done()
+		pw := style.NewPlainWriter(dst)
+		_, _ = io.Copy(pw, r)
+		_ = pw.Flush()
+		_ = r.Close()
+	}()
+	return w, nil
+}
+
+// finishPlainMode closes the plain output filters and waits for them to
+// write out everything the command printed.
+func finishPlainMode() {
+	if len(plainPipes.writers) == 0 {
+		return
+	}
+	for _, w := range plainPipes.writers {
+		_ = w.Close()
+	}
+	done := make(chan struct{})
+	go func() {
+		plainPipes.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(plainDrainTimeout):
+	}
+	plainPipes.writers = nil
+	os.Stdout, os.Stderr = plainPipes.stdout, plainPipes.stderr
+}
@@ -12,7 +12,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/permission"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -372,24 +374,26 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 		rigs = []*rig.Rig{r}
 	}
 
-	// Collect polecats from all rigs
+	// Collect polecats from all rigs in parallel
 	t := tmux.NewTmux()
-	var allPolecats []PolecatListItem
-
-	for _, r := range rigs {
+	type rigPolecats struct {
+		items []PolecatListItem
+		err   error
+	}
+	results := rig.ForEach(rigs, func(r *rig.Rig) rigPolecats {
 		polecatGit := git.NewGit(r.Path)
 		mgr := polecat.NewManager(r, polecatGit, t)
 		polecatMgr := polecat.NewSessionManager(t, r)
 
 		polecats, err := mgr.List()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to list polecats in %s: %v\n", r.Name, err)
-			continue
+			return rigPolecats{err: err}
 		}
 
+		var items []PolecatListItem
 		for _, p := range polecats {
 			running, _ := polecatMgr.IsRunning(p.Name)
-			allPolecats = append(allPolecats, PolecatListItem{
+			items = append(items, PolecatListItem{
 				Rig:            r.Name,
 				Name:           p.Name,
 				State:          p.State,
@@ -397,13 +401,21 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 				SessionRunning: running,
 			})
 		}
+		return rigPolecats{items: items}
+	})
+
+	var allPolecats []PolecatListItem
+	for i, res := range results {
+		if res.err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to list polecats in %s: %v\n", rigs[i].Name, res.err)
+			continue
+		}
+		allPolecats = append(allPolecats, res.items...)
 	}
 
 	// Output
-	if polecatListJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(allPolecats)
+	if structuredOutput(polecatListJSON) {
+		return renderStructured(allPolecats)
 	}
 
 	if len(allPolecats) == 0 {
@@ -482,6 +494,9 @@ func runPolecatRemove(cmd *cobra.Command, args []string) error {
 		fmt.Println("No polecats to remove.")
 		return nil
 	}
+	if err := requirePolecatPermission(permission.ActionPolecatRemove, targets); err != nil {
+		return err
+	}
 
 	// Remove each polecat
 	t := tmux.NewTmux()
@@ -511,6 +526,7 @@ func runPolecatRemove(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("  %s removed\n", style.Success.Render("✓"))
+		_ = events.LogFeed(events.TypeKill, "gt", events.KillPayload(p.rigName, p.polecatName, "removed"))
 		removed++
 	}
 
@@ -1066,6 +1082,9 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		fmt.Println("No polecats to nuke.")
 		return nil
 	}
+	if err := requirePolecatPermission(permission.ActionPolecatNuke, targets); err != nil {
+		return err
+	}
 
 	// Safety checks: refuse to nuke polecats with active work unless --force is set
 	if !polecatNukeForce && !polecatNukeDryRun {
@@ -1172,6 +1191,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), agentBeadID)
 		}
 
+		_ = events.LogFeed(events.TypeKill, "gt", events.KillPayload(p.rigName, p.polecatName, "nuked"))
 		nuked++
 	}
 
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/suggest"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	polecatAttachReadOnly bool
+	polecatAttachWindow   string
+)
+
+var polecatAttachCmd = &cobra.Command{
+	Use:     "attach <name> | <rig>/<polecat>",
+	Aliases: []string{"at"},
+	Short:   "Attach to a polecat's session",
+	Long: `Attach to a polecat's tmux session.
+
+A bare name is looked up in the polecat registry: the rig is taken from the
+current directory when inside a rig, otherwise every rig is searched. Use
+<rig>/<polecat> when the same name exists in several rigs.
+
+If the session is not running, it is started first when the rig enables
+polecat.auto_start_on_attach in settings/config.json; otherwise the command
+fails with a hint to start it.
+
+--read-only attaches as a read-only client, so the Mayor (or a human) can
+observe a worker without typing into its pane. Inside tmux, the read-only
+client opens in a new window. Detach with Ctrl-B D.
+
+--window attaches to one of the windows opened by the polecat session
+template (e.g., tests) instead of the agent's.
+
+Examples:
+  gt polecat attach Toast
+  gt polecat attach greenplace/Toast
+  gt polecat attach Toast --read-only
+  gt polecat attach Toast --window tests`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatAttach,
+}
+
+func init() {
+	polecatAttachCmd.Flags().BoolVarP(&polecatAttachReadOnly, "read-only", "r", false, "Attach without sending keystrokes to the session")
+	polecatAttachCmd.Flags().StringVarP(&polecatAttachWindow, "window", "w", "", "Attach to this window of the session")
+
+	polecatCmd.AddCommand(polecatAttachCmd)
+}
+
+func runPolecatAttach(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := resolvePolecatAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	sessMgr, r, err := getSessionManager(rigName)
+	if err != nil {
+		return err
+	}
+	sessionID := sessMgr.SessionName(polecatName)
+
+	running, err := sessMgr.IsRunning(polecatName)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		if !polecatAutoStartOnAttach(r) {
+			return fmt.Errorf("%w: %s (start with: gt session start %s/%s)",
+				polecat.ErrSessionNotFound, sessionID, rigName, polecatName)
+		}
+
+		fmt.Printf("Session for %s/%s not running, starting...\n", rigName, polecatName)
+		if err := sessMgr.Start(polecatName, polecat.SessionStartOptions{}); err != nil {
+			return fmt.Errorf("starting session: %w", err)
+		}
+		fmt.Printf("%s Session started\n", style.Bold.Render("✓"))
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			agent := fmt.Sprintf("%s/%s", rigName, polecatName)
+			_ = townlog.NewLogger(townRoot).Log(townlog.EventWake, agent, "attach")
+		}
+	}
+
+	target := sessionID
+	if polecatAttachWindow != "" {
+		target = tmux.WindowTarget(sessionID, polecatAttachWindow)
+	}
+	if polecatAttachReadOnly {
+		return attachToTmuxSessionReadOnly(target)
+	}
+	return attachToTmuxSession(target)
+}
+
+// polecatAutoStartOnAttach reports whether the rig starts stopped polecat
+// sessions on attach (polecat.auto_start_on_attach).
+func polecatAutoStartOnAttach(r *rig.Rig) bool {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.Polecat == nil {
+		return false
+	}
+	return settings.Polecat.AutoStartOnAttach
+}
+
+// resolvePolecatAddress resolves "<rig>/<polecat>" or a bare polecat name to
+// a rig and polecat. A bare name uses the rig of the current directory if
+// there is one, otherwise the rig whose registry holds that name.
+func resolvePolecatAddress(addr string) (rigName, polecatName string, err error) {
+	if strings.Contains(addr, "/") {
+		rigName, polecatName, err = parseAddress(addr)
+		if err != nil {
+			return "", "", err
+		}
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return "", "", err
+		}
+		if err := checkPolecatRegistered(r, polecatName); err != nil {
+			return "", "", err
+		}
+		return rigName, polecatName, nil
+	}
+	if addr == "" {
+		return "", "", fmt.Errorf("polecat name required")
+	}
+
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if inferred, err := inferRigFromCwd(townRoot); err == nil && inferred != "" {
+			if _, r, err := getRig(inferred); err == nil && checkPolecatRegistered(r, addr) == nil {
+				return inferred, addr, nil
+			}
+		}
+	}
+
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return "", "", err
+	}
+	var matches, known []string
+	for _, r := range rigs {
+		for _, p := range r.Polecats {
+			if p == addr {
+				matches = append(matches, r.Name)
+			}
+			known = append(known, p)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], addr, nil
+	case 0:
+		suggestions := suggest.FindSimilar(addr, known, 3)
+		return "", "", errors.New(suggest.FormatSuggestion("Polecat", addr, suggestions, "List polecats with: gt polecat list --all"))
+	default:
+		sort.Strings(matches)
+		var options []string
+		for _, m := range matches {
+			options = append(options, m+"/"+addr)
+		}
+		return "", "", fmt.Errorf("polecat %q exists in several rigs; use one of: %s", addr, strings.Join(options, ", "))
+	}
+}
+
+// checkPolecatRegistered returns an error with suggestions if the rig has no
+// polecat by that name.
+func checkPolecatRegistered(r *rig.Rig, name string) error {
+	for _, p := range r.Polecats {
+		if p == name {
+			return nil
+		}
+	}
+	suggestions := suggest.FindSimilar(name, r.Polecats, 3)
+	hint := fmt.Sprintf("Create with: gt polecat add %s %s", r.Name, name)
+	return errors.New(suggest.FormatSuggestion("Polecat", name, suggestions, hint))
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestPolecatAutoStartOnAttach(t *testing.T) {
+	r := &rig.Rig{Name: "greenplace", Path: t.TempDir()}
+
+	if polecatAutoStartOnAttach(r) {
+		t.Error("auto-start enabled without rig settings")
+	}
+
+	settingsDir := filepath.Join(r.Path, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"type": "rig-settings", "version": 1}`)
+	if polecatAutoStartOnAttach(r) {
+		t.Error("auto-start enabled without a polecat section")
+	}
+
+	write(`{"type": "rig-settings", "version": 1, "polecat": {"auto_start_on_attach": true}}`)
+	if !polecatAutoStartOnAttach(r) {
+		t.Error("auto-start disabled with polecat.auto_start_on_attach set")
+	}
+}
+
+func TestCheckPolecatRegistered(t *testing.T) {
+	r := &rig.Rig{Name: "greenplace", Polecats: []string{"Toast", "Nux"}}
+
+	if err := checkPolecatRegistered(r, "Nux"); err != nil {
+		t.Errorf("checkPolecatRegistered(Nux) = %v", err)
+	}
+	if err := checkPolecatRegistered(r, "Tost"); err == nil {
+		t.Error("checkPolecatRegistered(Tost) succeeded for an unknown polecat")
+	}
+}
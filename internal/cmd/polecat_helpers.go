@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/permission"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -19,6 +20,18 @@ type polecatTarget struct {
 	r           *rig.Rig
 }
 
+// requirePolecatPermission gates a destructive action on every target before
+// any of them is touched, so a denial never leaves a batch half done.
+func requirePolecatPermission(action permission.Action, targets []polecatTarget) error {
+	for _, p := range targets {
+		target := p.rigName + "/" + p.polecatName
+		if err := requirePermission(filepath.Dir(p.r.Path), action, target, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // resolvePolecatTargets builds a list of polecats from command args.
 // If useAll is true, the first arg is treated as a rig name and all polecats in it are returned.
 // Otherwise, args are parsed as rig/polecat addresses.
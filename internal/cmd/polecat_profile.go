@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Profile command flags
+var (
+	polecatProfileModel            string
+	polecatProfileSystemPromptFile string
+	polecatProfileAllowedTools     []string
+	polecatProfileDisallowedTools  []string
+	polecatProfileContextStrategy  string
+)
+
+var polecatProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Show or change polecat profiles",
+	Long: `Show the town's polecat profiles, or change them with the subcommands.
+
+A profile is a polecat's persona, applied whenever one of its sessions is
+launched, on top of the agent chosen for the polecat role:
+
+  model               passed to the agent as --model (e.g., opus)
+  system_prompt_file  appended to the agent's system prompt, relative to the
+                      town root (claude only; read at each launch)
+  allowed_tools       tool permissions, in claude's --allowedTools syntax
+  disallowed_tools    (e.g., "Bash(git:*)", WebFetch; claude only)
+  context_strategy    what the polecat does as its context fills: compact
+                      (the default) or handoff to a fresh session
+
+Profiles live in the town's settings/config.json under polecat_profiles,
+keyed by polecat name. A polecat without a profile of its own uses the one
+named "default", if there is one.
+
+Examples:
+  gt polecat profile
+  gt polecat profile show nux
+  gt polecat profile edit default --model sonnet
+  gt polecat profile edit nux        # opens the profile in $EDITOR
+  gt polecat profile remove nux`,
+	Args: cobra.NoArgs,
+	RunE: runPolecatProfileList,
+}
+
+var polecatProfileShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a polecat profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolecatProfileShow,
+}
+
+var polecatProfileEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Create or change a polecat profile",
+	Long: `Create or change a polecat profile.
+
+With flags, sets those settings and keeps the rest. Without, opens the
+profile as JSON in $VISUAL or $EDITOR (default vi). The profile is checked
+before it is saved: a system prompt file must exist. Changes apply to the
+polecat's next session.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatProfileEdit,
+}
+
+var polecatProfileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a polecat profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolecatProfileRemove,
+}
+
+func init() {
+	flags := polecatProfileEditCmd.Flags()
+	flags.StringVar(&polecatProfileModel, "model", "", "Model passed to the agent as --model")
+	flags.StringVar(&polecatProfileSystemPromptFile, "system-prompt-file", "", "File appended to the agent's system prompt (relative to the town root)")
+	flags.StringSliceVar(&polecatProfileAllowedTools, "allowed-tools", nil, "Tools the agent may use (replaces the list)")
+	flags.StringSliceVar(&polecatProfileDisallowedTools, "disallowed-tools", nil, "Tools the agent may not use (replaces the list)")
+	flags.StringVar(&polecatProfileContextStrategy, "context-strategy", "", "What to do as context fills: compact or handoff")
+
+	polecatProfileCmd.AddCommand(polecatProfileShowCmd)
+	polecatProfileCmd.AddCommand(polecatProfileEditCmd)
+	polecatProfileCmd.AddCommand(polecatProfileRemoveCmd)
+	polecatCmd.AddCommand(polecatProfileCmd)
+}
+
+// PolecatProfileEntry is one profile in gt polecat profile output.
+type PolecatProfileEntry struct {
+	Name string `json:"name"`
+	config.PolecatProfile
+}
+
+// loadPolecatProfiles loads the town's settings for the profile commands.
+func loadPolecatProfiles() (string, *config.TownSettings, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return townRoot, settings, nil
+}
+
+func runPolecatProfileList(cmd *cobra.Command, args []string) error {
+	_, settings, err := loadPolecatProfiles()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(settings.PolecatProfiles))
+	for name, profile := range settings.PolecatProfiles {
+		if profile != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := []PolecatProfileEntry{}
+	for _, name := range names {
+		out = append(out, PolecatProfileEntry{Name: name, PolecatProfile: *settings.PolecatProfiles[name]})
+	}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+
+	if len(out) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No polecat profiles (polecats launch with the polecat role's agent as is)"))
+		return nil
+	}
+	for _, entry := range out {
+		fmt.Printf("  %-12s %s\n", style.Bold.Render(entry.Name), style.Dim.Render(describePolecatProfile(&entry.PolecatProfile)))
+	}
+	return nil
+}
+
+func runPolecatProfileShow(cmd *cobra.Command, args []string) error {
+	_, settings, err := loadPolecatProfiles()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	profile := settings.PolecatProfiles[name]
+	if profile == nil {
+		return fmt.Errorf("no polecat profile %q", name)
+	}
+	if structuredOutput(false) {
+		return renderStructured(PolecatProfileEntry{Name: name, PolecatProfile: *profile})
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(name))
+	show := func(label, value string) {
+		if value == "" {
+			value = style.Dim.Render("-")
+		}
+		fmt.Printf("  %-18s %s\n", label+":", value)
+	}
+	show("Model", profile.Model)
+	show("System prompt", profile.SystemPromptFile)
+	show("Allowed tools", strings.Join(profile.AllowedTools, ", "))
+	show("Disallowed tools", strings.Join(profile.DisallowedTools, ", "))
+	strategy := profile.ContextStrategy
+	if strategy == "" {
+		strategy = config.ContextStrategyCompact
+	}
+	show("Context strategy", strategy)
+	return nil
+}
+
+func runPolecatProfileEdit(cmd *cobra.Command, args []string) error {
+	townRoot, settings, err := loadPolecatProfiles()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	profile := &config.PolecatProfile{}
+	if existing := settings.PolecatProfiles[name]; existing != nil {
+		*profile = *existing
+	}
+
+	flags := cmd.Flags()
+	setByFlags := false
+	for _, flag := range []string{"model", "system-prompt-file", "allowed-tools", "disallowed-tools", "context-strategy"} {
+		setByFlags = setByFlags || flags.Changed(flag)
+	}
+	if setByFlags {
+		if flags.Changed("model") {
+			profile.Model = polecatProfileModel
+		}
+		if flags.Changed("system-prompt-file") {
+			profile.SystemPromptFile = polecatProfileSystemPromptFile
+		}
+		if flags.Changed("allowed-tools") {
+			profile.AllowedTools = polecatProfileAllowedTools
+		}
+		if flags.Changed("disallowed-tools") {
+			profile.DisallowedTools = polecatProfileDisallowedTools
+		}
+		if flags.Changed("context-strategy") {
+			profile.ContextStrategy = polecatProfileContextStrategy
+		}
+	} else {
+		if !term.IsTerminal(int(os.Stdin.Fd())) || !ui.IsTerminal() {
+			return fmt.Errorf("not a terminal: set the profile with flags (see --help)")
+		}
+		// Every setting is shown, set or not, so the file doubles as a template
+		editable := struct {
+			Model            string   `json:"model"`
+			SystemPromptFile string   `json:"system_prompt_file"`
+			AllowedTools     []string `json:"allowed_tools"`
+			DisallowedTools  []string `json:"disallowed_tools"`
+			ContextStrategy  string   `json:"context_strategy"`
+		}(*profile)
+		data, err := json.MarshalIndent(editable, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding profile: %w", err)
+		}
+		path, edited, err := editDescription("gt-profile-*.json", string(data)+"\n")
+		if err != nil {
+			return err
+		}
+		profile = &config.PolecatProfile{}
+		if err := json.Unmarshal([]byte(edited), profile); err != nil {
+			return fmt.Errorf("parsing profile: %w (edits left in %s)", err, path)
+		}
+		defer func() { _ = os.Remove(path) }()
+	}
+
+	if err := profile.Validate(townRoot); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	_, err = config.TownSettingsStore(config.TownSettingsPath(townRoot)).Update(func(settings *config.TownSettings) error {
+		if settings.PolecatProfiles == nil {
+			settings.PolecatProfiles = make(map[string]*config.PolecatProfile)
+		}
+		settings.PolecatProfiles[name] = profile
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("%s Saved polecat profile %s\n", style.Success.Render("✓"), style.Bold.Render(name))
+	fmt.Printf("  %s\n", style.Dim.Render("Applies from each polecat's next session"))
+	return nil
+}
+
+func runPolecatProfileRemove(cmd *cobra.Command, args []string) error {
+	townRoot, _, err := loadPolecatProfiles()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	_, err = config.TownSettingsStore(config.TownSettingsPath(townRoot)).Update(func(settings *config.TownSettings) error {
+		if settings.PolecatProfiles[name] == nil {
+			return fmt.Errorf("no polecat profile %q", name)
+		}
+		delete(settings.PolecatProfiles, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed polecat profile %s\n", style.Success.Render("✓"), name)
+	return nil
+}
+
+// describePolecatProfile summarizes a profile's settings on one line.
+func describePolecatProfile(p *config.PolecatProfile) string {
+	var parts []string
+	if p.Model != "" {
+		parts = append(parts, "model "+p.Model)
+	}
+	if p.SystemPromptFile != "" {
+		parts = append(parts, "prompt "+p.SystemPromptFile)
+	}
+	if n := len(p.AllowedTools); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d allowed tool(s)", n))
+	}
+	if n := len(p.DisallowedTools); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d disallowed tool(s)", n))
+	}
+	if p.ContextStrategy != "" {
+		parts = append(parts, p.ContextStrategy+" on full context")
+	}
+	if len(parts) == 0 {
+		return "(no settings)"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var polecatSendWindow string
+
+var polecatSendCmd = &cobra.Command{
+	Use:   "send <name> | <rig>/<polecat> <text>...",
+	Short: "Type a line into one of a polecat's session windows",
+	Long: `Type a line into a polecat's session and press Enter.
+
+By default the line goes to the agent, as a nudge. --window sends it to
+another window of the session instead, one opened by the polecat session
+template's windows (see 'sessions' in the town's settings/config.json),
+e.g. to rerun a test watcher. The polecat is resolved as for
+'gt polecat attach'.
+
+Examples:
+  gt polecat send Toast --window tests "go test ./internal/..."
+  gt polecat send greenplace/Toast --window logs "clear"
+  gt polecat send Toast "Check the failing test in the tests window"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPolecatSend,
+}
+
+func init() {
+	polecatSendCmd.Flags().StringVarP(&polecatSendWindow, "window", "w", config.AgentWindow, "Window to type into")
+
+	polecatCmd.AddCommand(polecatSendCmd)
+}
+
+func runPolecatSend(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := resolvePolecatAddress(args[0])
+	if err != nil {
+		return err
+	}
+	sessMgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return err
+	}
+	sessionID := sessMgr.SessionName(polecatName)
+	running, err := sessMgr.IsRunning(polecatName)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("%w: %s", polecat.ErrSessionNotFound, sessionID)
+	}
+
+	text := strings.Join(args[1:], " ")
+	t := tmux.NewTmux()
+	if polecatSendWindow == config.AgentWindow {
+		if err := t.NudgeSession(sessionID, text); err != nil {
+			return fmt.Errorf("sending to %s: %w", sessionID, err)
+		}
+	} else {
+		windows, err := t.ListWindows(sessionID)
+		if err != nil {
+			return fmt.Errorf("listing windows: %w", err)
+		}
+		if !slices.Contains(windows, polecatSendWindow) {
+			return fmt.Errorf("%s/%s has no window %q (windows: %s)", rigName, polecatName, polecatSendWindow, strings.Join(windows, ", "))
+		}
+		if err := t.SendKeys(tmux.WindowTarget(sessionID, polecatSendWindow), text); err != nil {
+			return fmt.Errorf("sending to %s: %w", polecatSendWindow, err)
+		}
+	}
+
+	fmt.Printf("%s Sent to %s/%s (%s)\n", style.Success.Render("✓"), rigName, polecatName, polecatSendWindow)
+	return nil
+}
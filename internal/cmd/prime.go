@@ -209,6 +209,10 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Output the polecat profile's context strategy
+	explain(ctx.Role == RolePolecat && os.Getenv("GT_CONTEXT_STRATEGY") != "", "Context strategy: set by the polecat's profile")
+	outputContextStrategy(ctx)
+
 	// Output handoff content if present
 	outputHandoffContent(ctx)
 
@@ -3,11 +3,13 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -187,6 +189,26 @@ func outputPolecatContext(ctx RoleContext) {
 		style.Dim.Render(ctx.Polecat), style.Dim.Render(ctx.Rig))
 }
 
+// outputContextStrategy tells a polecat what to do as its context fills,
+// when its profile sets a strategy (GT_CONTEXT_STRATEGY).
+func outputContextStrategy(ctx RoleContext) {
+	if ctx.Role != RolePolecat {
+		return
+	}
+	switch os.Getenv("GT_CONTEXT_STRATEGY") {
+	case config.ContextStrategyHandoff:
+		fmt.Println()
+		fmt.Println("## Context Strategy: handoff")
+		fmt.Println("When your context is getting full, don't let it be compacted: commit")
+		fmt.Println("and push your work, then run `gt handoff` to continue in a fresh session.")
+	case config.ContextStrategyCompact:
+		fmt.Println()
+		fmt.Println("## Context Strategy: compact")
+		fmt.Println("When your context fills it is compacted, and `gt prime` restores your")
+		fmt.Println("role context. Keep working.")
+	}
+}
+
 func outputCrewContext(ctx RoleContext) {
 	fmt.Printf("%s\n\n", style.Bold.Render("# Crew Worker Context"))
 	fmt.Printf("You are crew worker **%s** in rig: %s\n\n",
@@ -18,3 +18,9 @@ func isProcessRunning(pid int) bool {
 	// EPERM means process exists but we don't have permission to signal it.
 	return err == syscall.EPERM
 }
+
+// execReplace replaces the current process with binPath. argv[0] must be
+// the program name. It only returns on error.
+func execReplace(binPath string, argv, env []string) error {
+	return syscall.Exec(binPath, argv, env)
+}
@@ -2,7 +2,13 @@
 
 package cmd
 
-import "golang.org/x/sys/windows"
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/windows"
+)
 
 const processStillActive = 259
 
@@ -25,3 +31,24 @@ func isProcessRunning(pid int) bool {
 
 	return exitCode == processStillActive
 }
+
+// execReplace emulates exec on Windows, which cannot replace the running
+// process: it runs binPath attached to the console and exits with its exit
+// code. argv[0] is the program name and is not passed again. It only
+// returns on error.
+func execReplace(binPath string, argv, env []string) error {
+	c := exec.Command(binPath, argv[1:]...) //nolint:gosec // G204: callers resolve binPath via LookPath
+	c.Env = env
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 
 var readyJSON bool
 var readyRig string
+var readyPage listPage
 
 var readyCmd = &cobra.Command{
 	Use:     "ready",
@@ -36,16 +37,22 @@ Aggregates ready issues from:
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
 
+Long lists are shown a page at a time: --limit items (default 100, 0 for
+all), starting --offset items in. JSON output shows every item unless
+--limit is given; its summary always counts them all.
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
-  gt ready --rig=gastown  # Show only one rig`,
+  gt ready --rig=gastown  # Show only one rig
+  gt ready --limit 20 --offset 20  # The second page of 20`,
 	RunE: runReady,
 }
 
 func init() {
 	readyCmd.Flags().BoolVar(&readyJSON, "json", false, "Output as JSON")
 	readyCmd.Flags().StringVar(&readyRig, "rig", "", "Filter to a specific rig")
+	addPageFlags(readyCmd, &readyPage)
 	rootCmd.AddCommand(readyCmd)
 }
 
@@ -213,6 +220,20 @@ func runReady(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Keep the page of items asked for, in the order they are listed
+	start, end, err := readyPage.bounds(summary.Total, readyJSON)
+	if err != nil {
+		return err
+	}
+	pos := 0
+	for i := range sources {
+		n := len(sources[i].Issues)
+		lo := min(max(start-pos, 0), n)
+		hi := min(max(end-pos, 0), n)
+		sources[i].Issues = sources[i].Issues[lo:hi]
+		pos += n
+	}
+
 	result := ReadyResult{
 		Sources:  sources,
 		Summary:  summary,
@@ -226,10 +247,11 @@ func runReady(cmd *cobra.Command, args []string) error {
 		return enc.Encode(result)
 	}
 
-	return printReadyHuman(result)
+	return printReadyHuman(result, readyPage.footer(start, end, summary.Total))
 }
 
-func printReadyHuman(result ReadyResult) error {
+// printReadyHuman prints a page of ready work; footer describes the page.
+func printReadyHuman(result ReadyResult, footer string) error {
 	if result.Summary.Total == 0 {
 		fmt.Println("No ready work across town.")
 		return nil
@@ -243,11 +265,15 @@ func printReadyHuman(result ReadyResult) error {
 			continue
 		}
 
-		count := len(src.Issues)
+		count := result.Summary.BySource[src.Name]
 		if count == 0 {
 			fmt.Printf("%s %s\n", style.Dim.Render(src.Name+"/"), style.Dim.Render("(none)"))
 			continue
 		}
+		if len(src.Issues) == 0 {
+			// All of this source's items are on other pages
+			continue
+		}
 
 		fmt.Printf("%s (%d items)\n", style.Bold.Render(src.Name+"/"), count)
 		for _, issue := range src.Issues {
@@ -298,6 +324,9 @@ func printReadyHuman(result ReadyResult) error {
 	} else {
 		fmt.Printf("Total: %d items ready\n", result.Summary.Total)
 	}
+	if footer != "" {
+		fmt.Println(style.Dim.Render(footer))
+	}
 
 	return nil
 }
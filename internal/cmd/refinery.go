@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -19,6 +20,7 @@ var (
 	refineryForeground    bool
 	refineryStatusJSON    bool
 	refineryQueueJSON     bool
+	refineryQueuePage     listPage
 	refineryAgentOverride string
 )
 
@@ -92,7 +94,11 @@ var refineryQueueCmd = &cobra.Command{
 	Long: `Show the merge queue for a rig.
 
 Lists all pending merge requests waiting to be processed.
-If rig is not specified, infers it from the current directory.`,
+If rig is not specified, infers it from the current directory.
+
+Long queues are shown a page at a time: --limit MRs (default 100, 0 for
+all), starting --offset MRs in. JSON output shows every MR unless --limit
+is given.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRefineryQueue,
 }
@@ -188,17 +194,28 @@ var refineryReadyCmd = &cobra.Command{
 Shows MRs that are:
 - Not currently claimed by any worker (or claim is stale)
 - Not blocked by an open task (e.g., conflict resolution in progress)
+- Permitted by the rig's merge schedule (see 'gt refinery schedule')
+- Not waiting on a path lock (merge_queue.locks) that a claimed MR, or
+  one ahead of it in the queue, holds
 
 This is the preferred command for finding work to process.
 
+Each merge target (the default branch, plus any release branches in
+merge_queue.targets) has its own sub-queue, listed separately and ordered
+on its own. --target shows just one.
+
 Examples:
   gt refinery ready
+  gt refinery ready --target release/1.2
   gt refinery ready --json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRefineryReady,
 }
 
-var refineryReadyJSON bool
+var (
+	refineryReadyJSON   bool
+	refineryReadyTarget string
+)
 
 var refineryBlockedCmd = &cobra.Command{
 	Use:   "blocked [rig]",
@@ -233,12 +250,14 @@ func init() {
 
 	// Queue flags
 	refineryQueueCmd.Flags().BoolVar(&refineryQueueJSON, "json", false, "Output as JSON")
+	addPageFlags(refineryQueueCmd, &refineryQueuePage)
 
 	// Unclaimed flags
 	refineryUnclaimedCmd.Flags().BoolVar(&refineryUnclaimedJSON, "json", false, "Output as JSON")
 
 	// Ready flags
 	refineryReadyCmd.Flags().BoolVar(&refineryReadyJSON, "json", false, "Output as JSON")
+	refineryReadyCmd.Flags().StringVar(&refineryReadyTarget, "target", "", "Show only the sub-queue for this target branch")
 
 	// Blocked flags
 	refineryBlockedCmd.Flags().BoolVar(&refineryBlockedJSON, "json", false, "Output as JSON")
@@ -411,6 +430,11 @@ func runRefineryQueue(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting queue: %w", err)
 	}
+	total := len(queue)
+	queue, footer, err := paginate(&refineryQueuePage, queue, refineryQueueJSON)
+	if err != nil {
+		return err
+	}
 
 	// JSON output
 	if refineryQueueJSON {
@@ -422,7 +446,7 @@ func runRefineryQueue(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Merge queue for '%s':\n\n", style.Bold.Render("📋"), rigName)
 
-	if len(queue) == 0 {
+	if total == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(empty)"))
 		return nil
 	}
@@ -473,6 +497,9 @@ func runRefineryQueue(cmd *cobra.Command, args []string) error {
 			issueInfo,
 			style.Dim.Render(item.Age))
 	}
+	if footer != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(footer))
+	}
 
 	return nil
 }
@@ -688,6 +715,80 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing ready MRs: %w", err)
 	}
 
+	// Honor the rig's merge schedule (quiet hours, merge windows)
+	ready, closedReason, err := filterBySchedule(r.Path, ready, func(mr *refinery.MRInfo) int { return mr.Priority })
+	if err != nil {
+		return err
+	}
+
+	// Each target branch is its own sub-queue
+	targets, err := refinery.LoadTargets(r.Path, r.DefaultBranch())
+	if err != nil {
+		return fmt.Errorf("loading merge targets: %w", err)
+	}
+	queues := refinery.SplitByTarget(targets, ready, func(mr *refinery.MRInfo) string { return mr.Target })
+
+	// Monorepo projects with their own queue don't wait behind the rest
+	projects, err := refinery.LoadProjects(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge projects: %w", err)
+	}
+	queues = refinery.SplitByProject(projects, queues, func(mr *refinery.MRInfo) string { return mr.Project })
+	if refineryReadyTarget != "" {
+		var only []refinery.SubQueue[*refinery.MRInfo]
+		for _, q := range queues {
+			if q.Target == refineryReadyTarget {
+				only = append(only, q)
+			}
+		}
+		queues = only
+	}
+
+	// Order each sub-queue by the rig's fairness policy; MRs of workers at
+	// their in-flight limit are not claimable yet
+	fairness, err := refinery.LoadFairness(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading merge fairness: %w", err)
+	}
+	var held []*refinery.MRInfo
+	if fairness != nil {
+		now := time.Now()
+		for i, q := range queues {
+			ordered, qHeld, err := orderByFairness(r, fairness, q.Items, func(mr *refinery.MRInfo) refinery.QueueEntry {
+				return refinery.QueueEntry{Worker: mr.Worker, Priority: mr.Priority, CreatedAt: mr.CreatedAt, Score: mr.ScoreAt(now)}
+			}, now)
+			if err != nil {
+				return err
+			}
+			queues[i].Items = ordered
+			held = append(held, qHeld...)
+		}
+	}
+	ready = nil
+	for _, q := range queues {
+		ready = append(ready, q.Items...)
+	}
+
+	// MRs wanting a path lock that a claimed MR, or one ahead of them,
+	// wants merge after it
+	lockWaits, err := refineryLockWaits(r, ready)
+	if err != nil {
+		return err
+	}
+	if len(lockWaits) > 0 {
+		ready = nil
+		for i, q := range queues {
+			var free []*refinery.MRInfo
+			for _, mr := range q.Items {
+				if _, waiting := lockWaits[mr.ID]; !waiting {
+					free = append(free, mr)
+				}
+			}
+			queues[i].Items = free
+			ready = append(ready, free...)
+		}
+	}
+
 	// JSON output
 	if refineryReadyJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -697,21 +798,58 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 
 	// Human-readable output
 	fmt.Printf("%s Ready MRs for '%s':\n\n", style.Bold.Render("🚀"), rigName)
+	if closedReason != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(merge schedule closed: %s)", closedReason)))
+	}
+	if len(held) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(%d held: worker at in-flight limit)", len(held))))
+	}
+	if len(lockWaits) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(%d waiting on path locks)", len(lockWaits))))
+	}
 
 	if len(ready) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(none ready)"))
 		return nil
 	}
 
-	for i, mr := range ready {
-		priority := fmt.Sprintf("P%d", mr.Priority)
-		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
-		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+	for _, q := range queues {
+		if len(q.Items) == 0 {
+			continue
+		}
+		if len(queues) > 1 {
+			fmt.Printf("  %s\n", style.Bold.Render("→ "+q.Name()))
+		}
+		for i, mr := range q.Items {
+			priority := fmt.Sprintf("P%d", mr.Priority)
+			fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
+			fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		}
 	}
 
 	return nil
 }
 
+// refineryLockWaits returns the MRs among ready, in queue order, that must
+// wait for another to release a path lock, by ID.
+func refineryLockWaits(r *rig.Rig, ready []*refinery.MRInfo) (map[string]refinery.LockWait, error) {
+	locked := false
+	for _, mr := range ready {
+		locked = locked || len(mr.Locks) > 0
+	}
+	if !locked {
+		return nil, nil
+	}
+	holders, err := mergeQueueLockHolders(r)
+	if err != nil {
+		return nil, err
+	}
+	_, waits := refinery.SerializeLocks(holders, ready,
+		func(mr *refinery.MRInfo) string { return mr.ID },
+		func(mr *refinery.MRInfo) []string { return mr.Locks })
+	return waits, nil
+}
+
 func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 	rigName := ""
 	if len(args) > 0 {
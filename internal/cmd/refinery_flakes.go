@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Refinery flakes flags
+var refineryFlakesTop int
+
+var refineryFlakesCmd = &cobra.Command{
+	Use:   "flakes [rig]",
+	Short: "Report the flakiest checks and tests",
+	Long: `Report a rig's flakiest checks, from the refinery's check history.
+
+Every check the refinery runs (the test command, transactional
+verification, 'gt mq test') is recorded as passed, failed, or flaky:
+failed, then passed on retry. A check's flake rate is its flaky runs over
+its recent runs; once it reaches merge_queue.flaky.threshold, failures of
+the check are retried automatically. The tests that failed before a retry
+passed are listed under each check, flakiest first.
+
+Examples:
+  gt refinery flakes greenplace
+  gt refinery flakes greenplace --top 5
+  gt refinery flakes greenplace -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryFlakes,
+}
+
+func init() {
+	refineryFlakesCmd.Flags().IntVar(&refineryFlakesTop, "top", 10, "Flaky tests to list per check (0 = all)")
+
+	refineryCmd.AddCommand(refineryFlakesCmd)
+}
+
+// RefineryFlakesOutput is the structured output for gt refinery flakes.
+type RefineryFlakesOutput struct {
+	Rig    string                `json:"rig"`
+	Checks []refinery.CheckStats `json:"checks"`
+}
+
+func runRefineryFlakes(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	history, err := refinery.LoadFlakeHistory(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading flaky check settings: %w", err)
+	}
+
+	out := RefineryFlakesOutput{Rig: rigName, Checks: history.Stats()}
+	for i := range out.Checks {
+		if tests := out.Checks[i].Tests; refineryFlakesTop > 0 && len(tests) > refineryFlakesTop {
+			out.Checks[i].Tests = tests[:refineryFlakesTop]
+		}
+	}
+	if out.Checks == nil {
+		out.Checks = []refinery.CheckStats{}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+
+	fmt.Printf("%s Flaky checks for '%s':\n\n", style.Bold.Render("🎲"), rigName)
+	if len(out.Checks) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no check runs recorded)"))
+		return nil
+	}
+	for _, c := range out.Checks {
+		rate := fmt.Sprintf("%.0f%%", c.FlakeRate*100)
+		if c.Flaky > 0 {
+			rate = style.Warning.Render(rate)
+		}
+		fmt.Printf("  %-10s %s flaky  %s\n", c.Check, rate,
+			style.Dim.Render(fmt.Sprintf("(%d runs: %d flaky, %d failed)", c.Runs, c.Flaky, c.Failed)))
+		if c.LastFlake != nil {
+			fmt.Printf("    Last flake: %s\n", formatAge(*c.LastFlake))
+		}
+		for _, t := range c.Tests {
+			fmt.Printf("    %3d× %s\n", t.Flakes, t.Name)
+		}
+	}
+	return nil
+}
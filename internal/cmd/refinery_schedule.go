@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Refinery schedule flags
+var (
+	refineryScheduleWindows    []string
+	refineryScheduleQuietHours []string
+	refineryScheduleTimezone   string
+	refineryScheduleP0Override bool
+	refineryScheduleClear      bool
+)
+
+var refineryScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage merge windows and quiet hours",
+	RunE:  requireSubcommand,
+	Long: `Manage when the refinery is allowed to merge.
+
+A rig's schedule is stored in settings/config.json under merge_queue.schedule.
+Outside the schedule, 'gt mq next' exits with code 6 (queue paused) and
+'gt refinery ready' lists nothing, so the Refinery idles instead of merging.
+
+Window format: "[days] HH:MM-HH:MM"
+  days:  mon..sun, ranges (mon-fri), lists (mon,wed), weekdays, weekends, daily
+  times: 24h clock; an end before the start wraps past midnight
+
+Commands:
+  show  Show the schedule and whether merges are currently allowed
+  set   Change the schedule`,
+}
+
+var refineryScheduleShowCmd = &cobra.Command{
+	Use:   "show [rig]",
+	Short: "Show the merge schedule",
+	Long: `Show a rig's merge windows, quiet hours, and current state.
+
+Examples:
+  gt refinery schedule show greenplace`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryScheduleShow,
+}
+
+var refineryScheduleSetCmd = &cobra.Command{
+	Use:   "set <rig>",
+	Short: "Set the merge schedule",
+	Long: `Set a rig's merge windows and quiet hours.
+
+Only the flags given are changed. --window and --quiet-hours replace the
+existing lists and may be repeated.
+
+Examples:
+  gt refinery schedule set greenplace --window "weekdays 09:00-18:00"
+  gt refinery schedule set greenplace --quiet-hours "22:00-06:00" --timezone America/New_York
+  gt refinery schedule set greenplace --p0-override   # Let P0 MRs merge any time
+  gt refinery schedule set greenplace --clear         # Merge any time`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefineryScheduleSet,
+}
+
+func init() {
+	refineryScheduleSetCmd.Flags().StringArrayVar(&refineryScheduleWindows, "window", nil, "Allowed merge window (repeatable)")
+	refineryScheduleSetCmd.Flags().StringArrayVar(&refineryScheduleQuietHours, "quiet-hours", nil, "Window when merges are blocked (repeatable)")
+	refineryScheduleSetCmd.Flags().StringVar(&refineryScheduleTimezone, "timezone", "", "IANA timezone for windows (default: local)")
+	refineryScheduleSetCmd.Flags().BoolVar(&refineryScheduleP0Override, "p0-override", false, "Allow P0 MRs to merge outside the schedule")
+	refineryScheduleSetCmd.Flags().BoolVar(&refineryScheduleClear, "clear", false, "Remove the schedule (merge any time)")
+
+	refineryScheduleCmd.AddCommand(refineryScheduleShowCmd)
+	refineryScheduleCmd.AddCommand(refineryScheduleSetCmd)
+	refineryCmd.AddCommand(refineryScheduleCmd)
+}
+
+func runRefineryScheduleShow(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	var cfg *config.MergeScheduleConfig
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings != nil && settings.MergeQueue != nil {
+		cfg = settings.MergeQueue.Schedule
+	}
+
+	sched, err := refinery.NewSchedule(cfg)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	open, reason := sched.Check(now)
+
+	if structuredOutput(false) {
+		out := struct {
+			Rig      string                      `json:"rig"`
+			Schedule *config.MergeScheduleConfig `json:"schedule,omitempty"`
+			Open     bool                        `json:"open"`
+			Reason   string                      `json:"reason,omitempty"`
+			NextOpen *time.Time                  `json:"next_open,omitempty"`
+		}{Rig: rigName, Schedule: cfg, Open: open, Reason: reason}
+		if !open {
+			if next := sched.NextOpen(now); !next.IsZero() {
+				out.NextOpen = &next
+			}
+		}
+		return renderStructured(out)
+	}
+
+	fmt.Printf("%s Merge schedule for '%s':\n\n", style.Bold.Render("🕐"), rigName)
+	if sched == nil {
+		fmt.Printf("  %s\n", style.Dim.Render("(no schedule - merges allowed any time)"))
+		return nil
+	}
+
+	tz := cfg.Timezone
+	if tz == "" {
+		tz = "local"
+	}
+	fmt.Printf("  Timezone:    %s\n", tz)
+	for _, w := range cfg.Windows {
+		fmt.Printf("  Window:      %s\n", w)
+	}
+	for _, w := range cfg.QuietHours {
+		fmt.Printf("  Quiet hours: %s\n", w)
+	}
+	fmt.Printf("  P0 override: %v\n\n", cfg.P0Override)
+
+	if open {
+		fmt.Printf("  %s Merges allowed now\n", style.Success.Render("●"))
+	} else {
+		fmt.Printf("  %s Merges paused (%s)\n", style.Warning.Render("○"), reason)
+		if next := sched.NextOpen(now); !next.IsZero() {
+			fmt.Printf("    Next window: %s\n", next.Format("Mon 15:04 MST"))
+		}
+	}
+	return nil
+}
+
+func runRefineryScheduleSet(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	settingsPath := config.RigSettingsPath(r.Path)
+	_, err = config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		if settings.MergeQueue == nil {
+			settings.MergeQueue = config.DefaultMergeQueueConfig()
+		}
+
+		if refineryScheduleClear {
+			settings.MergeQueue.Schedule = nil
+		} else {
+			cfg := settings.MergeQueue.Schedule
+			if cfg == nil {
+				cfg = &config.MergeScheduleConfig{}
+			}
+			flags := cmd.Flags()
+			if flags.Changed("window") {
+				cfg.Windows = refineryScheduleWindows
+			}
+			if flags.Changed("quiet-hours") {
+				cfg.QuietHours = refineryScheduleQuietHours
+			}
+			if flags.Changed("timezone") {
+				cfg.Timezone = refineryScheduleTimezone
+			}
+			if flags.Changed("p0-override") {
+				cfg.P0Override = refineryScheduleP0Override
+			}
+			// Validate before saving so a bad spec never reaches the refinery
+			if _, err := refinery.NewSchedule(cfg); err != nil {
+				return err
+			}
+			settings.MergeQueue.Schedule = cfg
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Updated merge schedule for %s\n", style.Success.Render("✓"), rigName)
+	fmt.Printf("  View with: %s\n", style.Dim.Render("gt refinery schedule show "+rigName))
+	return nil
+}
+
+// filterBySchedule drops MRs the rig's merge schedule does not currently
+// permit. Returns the permitted items and, when the schedule is closed, the
+// reason it is closed.
+func filterBySchedule[T any](rigPath string, items []T, priority func(T) int) ([]T, string, error) {
+	sched, err := refinery.LoadSchedule(rigPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("loading merge schedule: %w", err)
+	}
+	now := time.Now()
+	open, reason := sched.Check(now)
+	if open {
+		return items, "", nil
+	}
+
+	var permitted []T
+	for _, item := range items {
+		if sched.Permits(now, priority(item)) {
+			permitted = append(permitted, item)
+		}
+	}
+	return permitted, reason, nil
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Refinery test-config flags
+var refineryTestConfigKeep bool
+
+var refineryTestConfigCmd = &cobra.Command{
+	Use:   "test-config [rig]",
+	Short: "Dry-run the merge pipeline on a fabricated MR in a throwaway clone",
+	Long: `Try a rig's refinery settings on a fabricated merge request before they're
+trusted with real work.
+
+The rig's repo is cloned into a temporary directory, where a trivial MR
+is made: a branch adding one file to the target branch as the refinery has
+it, while a scratch copy of the target moves on with a commit of its own.
+The refinery's pipeline then runs there, step by step:
+
+  settings  every merge_queue section parses
+  clone     the sandbox is cloned and its commits pass the repo's hooks
+  rebase    the MR rebases onto the scratch target
+  commits   its commit message follows merge_queue.commit_messages
+  merge     it squash-merges onto the scratch target
+  checks    the test and verify commands pass on the merged result, as
+            'gt check' runs them (refinery environment, retries, timeout)
+  signing   the merge commit can be signed with merge_queue.signing
+  conflict  merge_queue.on_conflict is a known strategy
+
+The fabricated commits follow the rig's commit message convention and run
+the repo's git hooks (.githooks), so broken hooks fail here too. Nothing
+is pushed, no MR bead is created, and check runs aren't recorded in the
+rig's check history. The clone is removed afterwards unless --keep is
+given.
+
+Exits with code 8 if any step fails.
+
+Examples:
+  gt refinery test-config greenplace
+  gt refinery test-config greenplace --keep
+  gt refinery test-config greenplace -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryTestConfig,
+}
+
+func init() {
+	refineryTestConfigCmd.Flags().BoolVar(&refineryTestConfigKeep, "keep", false, "Keep the throwaway clone to inspect it")
+
+	refineryCmd.AddCommand(refineryTestConfigCmd)
+}
+
+// Outcomes of a test-config step.
+const (
+	configStepPassed  = "passed"
+	configStepFailed  = "failed"
+	configStepSkipped = "skipped"
+)
+
+// RefineryTestConfigOutput is the structured output for gt refinery
+// test-config.
+type RefineryTestConfigOutput struct {
+	Rig    string             `json:"rig"`
+	Target string             `json:"target"`
+	Dir    string             `json:"dir,omitempty"` // The clone, if kept
+	Passed bool               `json:"passed"`
+	Steps  []ConfigStepOutput `json:"steps"`
+}
+
+// ConfigStepOutput is one step of a test-config run.
+type ConfigStepOutput struct {
+	Name     string        `json:"name"`
+	Outcome  string        `json:"outcome"` // passed, failed or skipped
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// configTestRun records the steps of a test-config run as they finish.
+type configTestRun struct {
+	out *RefineryTestConfigOutput
+}
+
+// step runs fn as the named step. fn returns a detail for the report, and
+// an error if the step failed; errConfigStepSkipped skips it instead.
+func (c *configTestRun) step(name string, fn func() (string, error)) bool {
+	start := time.Now()
+	detail, err := fn()
+	s := ConfigStepOutput{Name: name, Outcome: configStepPassed, Detail: detail, Duration: time.Since(start)}
+	switch {
+	case errors.Is(err, errConfigStepSkipped):
+		s.Outcome = configStepSkipped
+	case err != nil:
+		s.Outcome, s.Detail = configStepFailed, err.Error()
+		c.out.Passed = false
+	}
+	c.out.Steps = append(c.out.Steps, s)
+	if !structuredOutput(false) {
+		printConfigStep(s)
+	}
+	return s.Outcome != configStepFailed
+}
+
+// skip marks the named steps skipped because an earlier one failed.
+func (c *configTestRun) skip(reason string, names ...string) {
+	for _, name := range names {
+		c.step(name, func() (string, error) { return reason, errConfigStepSkipped })
+	}
+}
+
+var errConfigStepSkipped = errors.New("skipped")
+
+func runRefineryTestConfig(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	townRoot, _, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	eng.SetOutput(io.Discard)
+	target := r.DefaultBranch()
+	run := &configTestRun{out: &RefineryTestConfigOutput{Rig: rigName, Target: target, Passed: true, Steps: []ConfigStepOutput{}}}
+	if !structuredOutput(false) {
+		fmt.Printf("%s Testing refinery config for '%s' against %s\n\n", style.Bold.Render("🧪"), rigName, target)
+	}
+	later := []string{"clone", "rebase", "commits", "merge", "checks", "signing", "conflict"}
+
+	var settings *config.RigSettings
+	if !run.step("settings", func() (string, error) {
+		var err error
+		settings, err = loadRefinerySettings(r)
+		if settings == nil || settings.MergeQueue == nil {
+			return "no merge_queue settings; using defaults", err
+		}
+		return "all merge_queue sections parse", err
+	}) {
+		run.skip("settings don't load", later...)
+		return finishRefineryTestConfig(run.out)
+	}
+	var mq config.MergeQueueConfig
+	if settings != nil && settings.MergeQueue != nil {
+		mq = *settings.MergeQueue
+	}
+
+	// Loaded without error above
+	policy, _ := refinery.LoadCommitPolicy(r.Path)
+	signing, _ := refinery.LoadSigning(r.Path)
+
+	var sandbox *refinery.Sandbox
+	if !run.step("clone", func() (string, error) {
+		prefix := "gt"
+		if r.Config != nil && r.Config.Prefix != "" {
+			prefix = strings.TrimSuffix(r.Config.Prefix, "-")
+		}
+		message, err := policy.Compose(policy.TypeFor("chore"), prefix+"-sandbox", "Try the refinery's settings")
+		if err != nil {
+			return "", fmt.Errorf("composing a commit message: %w", err)
+		}
+		if sandbox, err = eng.NewSandbox(target, message); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("MR %s onto %s at %s", refinery.SandboxBranch, refinery.SandboxTarget, shortSHA(sandbox.Base)), nil
+	}) {
+		run.skip("no sandbox", later[1:]...)
+		return finishRefineryTestConfig(run.out)
+	}
+	if refineryTestConfigKeep {
+		run.out.Dir = sandbox.Dir
+	} else {
+		defer func() { _ = sandbox.Remove() }()
+	}
+
+	if !run.step("rebase", func() (string, error) {
+		return "", sandbox.Rebase()
+	}) {
+		run.skip("rebase failed", later[2:]...)
+		return finishRefineryTestConfig(run.out)
+	}
+
+	run.step("commits", func() (string, error) {
+		if policy == nil {
+			return "no merge_queue.commit_messages convention", errConfigStepSkipped
+		}
+		violations, err := policy.CheckCommits(sandbox.Git(), refinery.SandboxTarget, refinery.SandboxBranch)
+		if err != nil {
+			return "", err
+		}
+		if len(violations) > 0 {
+			return "", errors.New(policy.FormatCommitViolations(violations))
+		}
+		return fmt.Sprintf("follows %q", policy.Template()), nil
+	})
+
+	if !run.step("merge", func() (string, error) {
+		commit, err := sandbox.Merge()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("squashed onto %s as %s", refinery.SandboxTarget, shortSHA(commit)), nil
+	}) {
+		run.skip("merge failed", later[4:]...)
+		return finishRefineryTestConfig(run.out)
+	}
+
+	run.step("checks", func() (string, error) {
+		// Structured output owns stdout, so the checks' goes to stderr
+		var passthrough io.Writer = os.Stdout
+		if structuredOutput(false) {
+			passthrough = os.Stderr
+		}
+		results, passed, err := runRigChecks(townRoot, rigName, sandbox.Dir, passthrough)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "no checks (merge_queue.test_command)", errConfigStepSkipped
+		}
+		var summary []string
+		for _, res := range results {
+			summary = append(summary, fmt.Sprintf("%s %s: %s", res.Name, res.Outcome, res.Tests))
+			if res.Error != "" {
+				return "", fmt.Errorf("%s failed: %s", res.Name, res.Error)
+			}
+		}
+		if !passed {
+			return "", errors.New("checks failed")
+		}
+		return strings.Join(summary, "; "), nil
+	})
+
+	run.step("signing", func() (string, error) {
+		if signing == nil {
+			return "merges land unsigned", errConfigStepSkipped
+		}
+		commit, err := sandbox.Sign(signing)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("signed (%s) as %s", signing.Format(), shortSHA(commit)), nil
+	})
+
+	run.step("conflict", func() (string, error) {
+		switch mq.OnConflict {
+		case "":
+			return "on_conflict unset; conflicts are assigned back", nil
+		case config.OnConflictAssignBack, config.OnConflictAutoRebase:
+			return "on_conflict " + mq.OnConflict, nil
+		default:
+			return "", fmt.Errorf("unknown on_conflict %q (want %q or %q)", mq.OnConflict, config.OnConflictAssignBack, config.OnConflictAutoRebase)
+		}
+	})
+
+	return finishRefineryTestConfig(run.out)
+}
+
+// loadRefinerySettings loads a rig's settings and every merge queue
+// section the refinery parses, so a malformed one turns up as an error.
+// Returns nil settings, without error, for a rig that has none.
+func loadRefinerySettings(r *rig.Rig) (*config.RigSettings, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := []struct {
+		section string
+		load    func() error
+	}{
+		{"checks", func() error { _, err := refinery.LoadChecks(r.Path); return err }},
+		{"commit_messages", func() error { _, err := refinery.LoadCommitPolicy(r.Path); return err }},
+		{"signing", func() error { _, err := refinery.LoadSigning(r.Path); return err }},
+		{"transactional", func() error { _, err := refinery.LoadTransaction(r.Path); return err }},
+		{"protection", func() error { _, err := refinery.LoadBranchProtection(r.Path); return err }},
+		{"policies", func() error { _, err := refinery.LoadPolicies(r.Path); return err }},
+		{"owners", func() error { _, err := refinery.LoadOwnership(r.Path); return err }},
+		{"targets", func() error { _, err := refinery.LoadTargets(r.Path, r.DefaultBranch()); return err }},
+		{"schedule", func() error { _, err := refinery.LoadSchedule(r.Path); return err }},
+		{"fairness", func() error { _, err := refinery.LoadFairness(r.Path); return err }},
+		{"sla", func() error { _, err := refinery.LoadSLA(r.Path); return err }},
+		{"risk", func() error { _, err := refinery.LoadRiskScorer(r.Path); return err }},
+		{"reviewers", func() error { _, err := refinery.LoadReviewerPool(r.Path); return err }},
+		{"description", func() error { _, err := refinery.LoadMRDescription(r.Path); return err }},
+		{"flaky", func() error { _, err := refinery.LoadFlakeHistory(r.Path); return err }},
+	}
+	var errs []string
+	for _, l := range loaders {
+		if err := l.load(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", l.section, err))
+		}
+	}
+	if len(errs) > 0 {
+		return settings, errors.New(strings.Join(errs, "; "))
+	}
+	return settings, nil
+}
+
+func finishRefineryTestConfig(out *RefineryTestConfigOutput) error {
+	if structuredOutput(false) {
+		if err := renderStructured(*out); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		if out.Passed {
+			fmt.Printf("%s Refinery config for '%s' works\n", style.Success.Render("✓"), out.Rig)
+		} else {
+			fmt.Printf("%s Refinery config for '%s' failed\n", style.Error.Render("✗"), out.Rig)
+		}
+		if out.Dir != "" {
+			fmt.Printf("  Sandbox kept at %s\n", out.Dir)
+		}
+	}
+	if !out.Passed {
+		return NewSilentExit(ExitCheckFailed)
+	}
+	return nil
+}
+
+func printConfigStep(s ConfigStepOutput) {
+	mark := style.Success.Render("✓")
+	switch s.Outcome {
+	case configStepFailed:
+		mark = style.Error.Render("✗")
+	case configStepSkipped:
+		mark = style.Dim.Render("-")
+	}
+	detail := s.Detail
+	if s.Outcome != configStepFailed {
+		detail = style.Dim.Render(detail)
+	}
+	fmt.Printf("  %s %-9s %s\n", mark, s.Name, detail)
+}
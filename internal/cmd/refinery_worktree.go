@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var refineryWorktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Manage temporary merge checkouts from the rig mirror",
+	RunE:  requireSubcommand,
+	Long: `Manage temporary worktrees for merge and check runs.
+
+Each rig keeps a bare mirror of its repo (<rig>/.repo.git) that the refinery
+and polecat worktrees share, so polecat branches are already local. A merge
+worktree checks out a branch from the mirror in seconds, without cloning,
+and keeps rebases and test runs out of the refinery's own checkout.
+
+Commands:
+  add     Create a merge worktree and print its path
+  remove  Remove a merge worktree`,
+}
+
+var refineryWorktreeAddCmd = &cobra.Command{
+	Use:   "add <ref> [rig]",
+	Short: "Create a merge worktree and print its path",
+	Long: `Create a temporary worktree with ref checked out (detached HEAD) and print
+its path.
+
+The rig's default branch is fetched into the mirror first, so origin/main is
+current for a rebase. A ref that is not a local branch or known ref is read
+as origin/<ref>. Rigs with a push remote (gt rig remotes push) fetch and
+read from that remote instead.
+
+Examples:
+  WT=$(gt refinery worktree add polecat/Toast)
+  cd "$WT" && git rebase origin/main && go test ./...
+  gt refinery worktree remove "$WT"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRefineryWorktreeAdd,
+}
+
+var refineryWorktreeRemoveCmd = &cobra.Command{
+	Use:   "remove <path>",
+	Short: "Remove a merge worktree",
+	Long: `Remove a worktree created by 'gt refinery worktree add', discarding any
+uncommitted changes in it. Commits made there stay reachable in the mirror
+until garbage collected.
+
+Examples:
+  gt refinery worktree remove "$WT"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefineryWorktreeRemove,
+}
+
+func init() {
+	refineryWorktreeCmd.AddCommand(refineryWorktreeAddCmd)
+	refineryWorktreeCmd.AddCommand(refineryWorktreeRemoveCmd)
+	refineryCmd.AddCommand(refineryWorktreeCmd)
+}
+
+func runRefineryWorktreeAdd(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 1 {
+		rigName = args[1]
+	}
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return fmt.Errorf("could not determine rig: %w\nUsage: gt refinery worktree add <ref> <rig>", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	mirror, err := refinery.OpenMirror(r.Path)
+	if errors.Is(err, refinery.ErrNoMirror) {
+		return fmt.Errorf("rig %s predates the shared bare repo; merge in the refinery clone instead", rigName)
+	} else if err != nil {
+		return err
+	}
+	remote := r.PushRemote()
+	if err := mirror.Sync(remote, r.DefaultBranch()); err != nil {
+		return err
+	}
+
+	path, err := mirror.AddWorktree(mirror.ResolveRef(remote, args[0]))
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+func runRefineryWorktreeRemove(cmd *cobra.Command, args []string) error {
+	mirror, err := refinery.MirrorOf(args[0])
+	if err != nil {
+		return err
+	}
+	return mirror.RemoveWorktree(args[0])
+}
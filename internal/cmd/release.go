@@ -12,9 +12,9 @@ import (
 var releaseReason string
 
 var releaseCmd = &cobra.Command{
-	Use:     "release <issue-id>...",
+	Use:     "release <issue-id>... | release <rig> --version vX.Y.Z",
 	GroupID: GroupWork,
-	Short:   "Release stuck in_progress issues back to pending",
+	Short:   "Release stuck issues, or cut a release of a rig",
 	Long: `Release one or more in_progress issues back to open/pending status.
 
 This is used to recover stuck steps when a worker dies mid-task.
@@ -27,7 +27,29 @@ Examples:
   gt release gt-abc -r "worker died"  # Release with reason
 
 This implements nondeterministic idempotence - work can be safely
-retried by releasing and reclaiming stuck steps.`,
+retried by releasing and reclaiming stuck steps.
+
+With --version, cut a release of a rig's target branch instead, without
+racing the Refinery:
+  1. Freeze the merge queue (the Refinery holds merges; 'gt mq next' and
+     'gt mq verify' exit with code 6)
+  2. Refuse to continue if any MR is in flight (claimed or in progress)
+  3. Tag origin's target branch with an annotated tag and push it
+  4. Write the changelog since the previous tag to
+     <rig>/.runtime/releases/<version>.md
+  5. Run the release hook, if any (merge_queue.release_hook or --hook), in
+     the refinery's clone with GT_RIG, GT_RELEASE_VERSION, and
+     GT_RELEASE_NOTES set
+  6. Unfreeze the queue
+
+The queue is unfrozen even if a later step fails. If gt is killed
+mid-release, clear the freeze with: gt rig config unset <rig> mq_freeze
+
+Examples:
+  gt release greenplace --version v1.4.0
+  gt release greenplace --version v2.0.0-rc.1 --target integration/gp-epic
+  gt release greenplace --version v1.4.1 --hook "make publish"
+  gt release greenplace --version v1.4.1 --no-hook -o json`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runRelease,
 }
@@ -38,6 +60,16 @@ func init() {
 }
 
 func runRelease(cmd *cobra.Command, args []string) error {
+	if releaseVersion != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("--version takes exactly one rig, got %d arguments", len(args))
+		}
+		return runReleaseCut(args[0])
+	}
+	if releaseTarget != "" || releaseHook != "" || releaseNoHook {
+		return fmt.Errorf("--target, --hook, and --no-hook require --version")
+	}
+
 	// Get working directory for beads
 	cwd, err := os.Getwd()
 	if err != nil {
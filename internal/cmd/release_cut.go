@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Release cut flags (gt release <rig> --version)
+var (
+	releaseVersion string
+	releaseTarget  string
+	releaseHook    string
+	releaseNoHook  bool
+)
+
+// releaseVersionPattern matches vMAJOR.MINOR.PATCH with an optional
+// pre-release suffix (v1.4.0, v2.0.0-rc.1).
+var releaseVersionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseVersion, "version", "", "Cut a release of <rig> tagged with this version (vX.Y.Z)")
+	releaseCmd.Flags().StringVar(&releaseTarget, "target", "", "Branch to release (default: rig's default branch)")
+	releaseCmd.Flags().StringVar(&releaseHook, "hook", "", "Release hook command (overrides merge_queue.release_hook)")
+	releaseCmd.Flags().BoolVar(&releaseNoHook, "no-hook", false, "Skip the release hook")
+	releaseCmd.MarkFlagsMutuallyExclusive("hook", "no-hook")
+}
+
+// ReleaseOutput is the structured output for gt release.
+type ReleaseOutput struct {
+	Rig         string `json:"rig"`
+	Version     string `json:"version"`
+	Target      string `json:"target"`
+	Commit      string `json:"commit"`
+	PreviousTag string `json:"previous_tag,omitempty"`
+	Notes       string `json:"notes"` // path to the generated changelog
+	Hook        string `json:"hook,omitempty"`
+}
+
+// runReleaseCut freezes the rig's merge queue, tags the target branch,
+// writes the changelog, runs the release hook, and unfreezes.
+func runReleaseCut(rigName string) (err error) {
+	if !releaseVersionPattern.MatchString(releaseVersion) {
+		return fmt.Errorf("invalid --version %q: want vX.Y.Z (e.g., v1.4.0)", releaseVersion)
+	}
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	target := releaseTarget
+	if target == "" {
+		target = r.DefaultBranch()
+	}
+
+	hook := releaseHook
+	if hook == "" && !releaseNoHook {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+		if err != nil && !errors.Is(err, config.ErrNotFound) {
+			return fmt.Errorf("loading rig settings: %w", err)
+		}
+		if settings != nil && settings.MergeQueue != nil {
+			hook = settings.MergeQueue.ReleaseHook
+		}
+	}
+
+	// 1. Freeze the queue, and always unfreeze on the way out
+	freeze := wisp.NewConfig(townRoot, rigName)
+	if existing := freeze.GetString(MergeQueueFreezeKey); existing != "" {
+		return withExitCode(ExitQueuePaused, fmt.Errorf("merge queue for rig '%s' is already frozen (%s)", rigName, existing))
+	}
+	if err := freeze.Set(MergeQueueFreezeKey, "release "+releaseVersion); err != nil {
+		return fmt.Errorf("freezing merge queue: %w", err)
+	}
+	defer func() {
+		if unsetErr := freeze.Unset(MergeQueueFreezeKey); unsetErr != nil && err == nil {
+			err = fmt.Errorf("unfreezing merge queue: %w", unsetErr)
+		}
+	}()
+	printReleaseStep("Merge queue frozen")
+
+	// 2. Nothing may be mid-merge while we tag
+	bd := beads.New(r.BeadsPath())
+	inFlight, err := inFlightMRs(bd)
+	if err != nil {
+		return err
+	}
+	if len(inFlight) > 0 {
+		return fmt.Errorf("cannot release while MRs are in flight: %s (wait for the Refinery to finish)", strings.Join(inFlight, ", "))
+	}
+	printReleaseStep("No MRs in flight")
+
+	// 3. Tag the target branch
+	eng := refinery.NewEngineer(r)
+	commit, previous, err := eng.TagRelease(releaseVersion, target)
+	if err != nil {
+		return err
+	}
+	printReleaseStep(fmt.Sprintf("Tagged %s at %s (%s)", releaseVersion, shortSHA(commit), target))
+
+	// 4. Changelog since the previous release
+	out := ReleaseOutput{Rig: rigName, Version: releaseVersion, Target: target, Commit: commit, PreviousTag: previous}
+	notes, err := writeReleaseNotes(r.Path, eng, bd, releaseVersion, previous)
+	if err != nil {
+		return err
+	}
+	out.Notes = notes
+	printReleaseStep("Changelog written to " + notes)
+
+	// 5. Release hook
+	if hook != "" {
+		if err := runReleaseHook(eng.WorkDir(), hook, rigName, releaseVersion, notes); err != nil {
+			return err
+		}
+		out.Hook = hook
+		printReleaseStep("Release hook succeeded: " + hook)
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	fmt.Printf("%s Released %s %s\n", style.Bold.Render("🚀"), rigName, releaseVersion)
+	return nil
+}
+
+func printReleaseStep(msg string) {
+	if !structuredOutput(false) {
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), msg)
+	}
+}
+
+// inFlightMRs returns MRs the Refinery has claimed or is processing.
+func inFlightMRs(bd *beads.Beads) ([]string, error) {
+	var ids []string
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := bd.List(beads.ListOptions{Type: "merge-request", Status: status, Priority: -1})
+		if err != nil {
+			return nil, fmt.Errorf("querying merge queue: %w", err)
+		}
+		for _, issue := range issues {
+			if issue.Status == "in_progress" || (issue.Status == "open" && issue.Assignee != "") {
+				ids = append(ids, issue.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// writeReleaseNotes writes the changelog since previous to
+// <rig>/.runtime/releases/<version>.md and returns its path.
+func writeReleaseNotes(rigPath string, eng *refinery.Engineer, bd *beads.Beads, version, previous string) (string, error) {
+	var since time.Time
+	if previous != "" {
+		var err error
+		if since, err = eng.CommitTime(previous); err != nil {
+			return "", fmt.Errorf("dating previous tag %s: %w", previous, err)
+		}
+	}
+	groups, err := buildChangelog(bd, since)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(rigPath, ".runtime", "releases")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating releases directory: %w", err)
+	}
+	path := filepath.Join(dir, version+".md")
+	if err := os.WriteFile(path, []byte(refinery.FormatChangelogMarkdown(version, groups)), 0644); err != nil {
+		return "", fmt.Errorf("writing release notes: %w", err)
+	}
+	return path, nil
+}
+
+// runReleaseHook runs the release hook through the shell in dir.
+func runReleaseHook(dir, hook, rigName, version, notes string) error {
+	c := exec.Command("sh", "-c", hook)
+	c.Dir = dir
+	c.Env = append(os.Environ(),
+		"GT_RIG="+rigName,
+		"GT_RELEASE_VERSION="+version,
+		"GT_RELEASE_NOTES="+notes,
+	)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if structuredOutput(false) {
+		c.Stdout = os.Stderr // keep stdout clean for JSON/YAML
+	}
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("release hook %q failed (tag %s is already pushed): %w", hook, version, err)
+	}
+	return nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
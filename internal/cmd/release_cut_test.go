@@ -0,0 +1,18 @@
+package cmd
+
+import "testing"
+
+func TestReleaseVersionPattern(t *testing.T) {
+	for version, want := range map[string]bool{
+		"v1.4.0":      true,
+		"v2.0.0-rc.1": true,
+		"1.4.0":       false,
+		"v1.4":        false,
+		"v1.4.0 ":     false,
+		"release-1":   false,
+	} {
+		if got := releaseVersionPattern.MatchString(version); got != want {
+			t.Errorf("releaseVersionPattern(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"golang.org/x/term"
+)
+
+// Environment variables for remote mode.
+const (
+	// EnvRemote sets a default --remote target for every command.
+	EnvRemote = "GT_REMOTE"
+
+	// EnvRemoteBin names the gt binary on the remote host, for servers where
+	// it is not on the non-interactive ssh PATH.
+	EnvRemoteBin = "GT_REMOTE_BIN"
+)
+
+// defaultRemoteTown is where the remote town is assumed to live when the
+// target has no :path, matching 'gt install ~/gt'.
+const defaultRemoteTown = "~/gt"
+
+// remoteTarget is the value of the global --remote flag. Execute handles the
+// flag before cobra parses arguments; it is registered so help shows it.
+var remoteTarget string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&remoteTarget, "remote", "",
+		"Run the command on a remote town over SSH: [user@]host[:town-path] (env: GT_REMOTE)")
+}
+
+// remoteSpec is a parsed --remote target.
+type remoteSpec struct {
+	Host     string // ssh destination, [user@]host
+	TownPath string // town root on the remote host; empty means ~/gt
+}
+
+// parseRemote parses [user@]host[:town-path].
+func parseRemote(s string) (remoteSpec, error) {
+	host, path, _ := strings.Cut(strings.TrimSpace(s), ":")
+	if host == "" || strings.HasPrefix(host, "-") {
+		return remoteSpec{}, fmt.Errorf("invalid --remote %q: want [user@]host[:town-path]", s)
+	}
+	return remoteSpec{Host: host, TownPath: path}, nil
+}
+
+// splitRemoteArgs removes --remote from args and returns its value and the
+// remaining arguments. Arguments after "--" are left alone.
+func splitRemoteArgs(args []string) (target string, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return target, append(rest, args[i:]...), nil
+		case arg == "--remote":
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag needs an argument: --remote")
+			}
+			target = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--remote="):
+			target = strings.TrimPrefix(arg, "--remote=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return target, rest, nil
+}
+
+// remoteScript builds the shell command run on the remote host: change to
+// the town root, then exec gt with the original arguments.
+func remoteScript(spec remoteSpec, bin string, args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, config.ShellQuote(bin))
+	for _, a := range args {
+		if a == "" {
+			quoted = append(quoted, "''")
+			continue
+		}
+		quoted = append(quoted, config.ShellQuote(a))
+	}
+
+	// ~ must stay unquoted to expand on the remote side
+	town := defaultRemoteTown
+	if spec.TownPath != "" {
+		town = spec.TownPath
+	}
+	cd := town
+	if rest, ok := strings.CutPrefix(town, "~/"); ok {
+		cd = "~/" + config.ShellQuote(rest)
+	} else if town != "~" {
+		cd = config.ShellQuote(town)
+	}
+	return fmt.Sprintf("cd %s && exec %s", cd, strings.Join(quoted, " "))
+}
+
+// remoteSSHArgs returns the ssh arguments for running script on the host.
+// A terminal is allocated when the local side is interactive, so attach
+// commands and prompts work; otherwise output stays clean for scripts.
+func remoteSSHArgs(spec remoteSpec, script string, interactive bool) []string {
+	tty := "-T"
+	if interactive {
+		tty = "-t"
+	}
+	return []string{tty, spec.Host, script}
+}
+
+// runRemoteIfRequested proxies the command to a remote town when --remote or
+// GT_REMOTE is set. ok is false when the command should run locally.
+func runRemoteIfRequested(args []string) (code int, ok bool) {
+	target, rest, err := splitRemoteArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError, true
+	}
+	if target == "" {
+		target = os.Getenv(EnvRemote)
+	}
+	if target == "" {
+		return 0, false
+	}
+
+	spec, err := parseRemote(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError, true
+	}
+	bin := os.Getenv(EnvRemoteBin)
+	if bin == "" {
+		bin = "gt"
+	}
+
+	interactive := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	c := exec.Command("ssh", remoteSSHArgs(spec, remoteScript(spec, bin, rest), interactive)...) //nolint:gosec // G204: user-chosen remote
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// ssh exits with the remote command's status (255 for ssh errors)
+			return exitErr.ExitCode(), true
+		}
+		fmt.Fprintf(os.Stderr, "Error: running ssh: %v\n", err)
+		return ExitError, true
+	}
+	return ExitOK, true
+}
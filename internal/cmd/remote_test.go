@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitRemoteArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantTarget string
+		wantRest   string
+	}{
+		{[]string{"mq", "list", "gastown"}, "", "mq list gastown"},
+		{[]string{"--remote", "me@box", "mq", "list", "gastown"}, "me@box", "mq list gastown"},
+		{[]string{"mayor", "attach", "--remote=box:/srv/gt"}, "box:/srv/gt", "mayor attach"},
+		{[]string{"mail", "send", "--", "--remote", "x"}, "", "mail send -- --remote x"},
+	}
+	for _, tt := range tests {
+		target, rest, err := splitRemoteArgs(tt.args)
+		if err != nil {
+			t.Fatalf("splitRemoteArgs(%v): %v", tt.args, err)
+		}
+		if target != tt.wantTarget || strings.Join(rest, " ") != tt.wantRest {
+			t.Errorf("splitRemoteArgs(%v) = %q, %v; want %q, %q", tt.args, target, rest, tt.wantTarget, tt.wantRest)
+		}
+	}
+
+	if _, _, err := splitRemoteArgs([]string{"status", "--remote"}); err == nil {
+		t.Error("--remote without a value succeeded, want error")
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	spec, err := parseRemote("me@box:/srv/gt")
+	if err != nil || spec.Host != "me@box" || spec.TownPath != "/srv/gt" {
+		t.Errorf("parseRemote = %+v, %v", spec, err)
+	}
+	spec, err = parseRemote("box")
+	if err != nil || spec.Host != "box" || spec.TownPath != "" {
+		t.Errorf("parseRemote(box) = %+v, %v", spec, err)
+	}
+	for _, bad := range []string{"", ":/srv/gt", "-oProxyCommand=x"} {
+		if _, err := parseRemote(bad); err == nil {
+			t.Errorf("parseRemote(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRemoteScript(t *testing.T) {
+	args := []string{"mail", "send", "mayor/", "-m", "it's done", ""}
+	got := remoteScript(remoteSpec{Host: "box"}, "gt", args)
+	want := `cd ~/gt && exec gt mail send mayor/ -m 'it'\''s done' ''`
+	if got != want {
+		t.Errorf("remoteScript = %q, want %q", got, want)
+	}
+
+	got = remoteScript(remoteSpec{Host: "box", TownPath: "/srv/my town"}, "/opt/bin/gt", []string{"status"})
+	if got != `cd '/srv/my town' && exec /opt/bin/gt status` {
+		t.Errorf("remoteScript with path = %q", got)
+	}
+}
+
+func TestRemoteSSHArgs(t *testing.T) {
+	spec := remoteSpec{Host: "box"}
+	if got := remoteSSHArgs(spec, "cmd", true); strings.Join(got, " ") != "-t box cmd" {
+		t.Errorf("interactive ssh args = %v", got)
+	}
+	if got := remoteSSHArgs(spec, "cmd", false); got[0] != "-T" {
+		t.Errorf("non-interactive ssh args = %v", got)
+	}
+}
+
+func TestRunRemoteIfRequested(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake ssh")
+	}
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\nexit 5\n"
+	if err := os.WriteFile(filepath.Join(binDir, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(EnvRemote, "")
+
+	if _, ok := runRemoteIfRequested([]string{"status"}); ok {
+		t.Fatal("ran remotely without --remote")
+	}
+
+	t.Setenv(EnvRemote, "me@box")
+	code, ok := runRemoteIfRequested([]string{"mq", "list", "gastown"})
+	if !ok || code != 5 {
+		t.Fatalf("runRemoteIfRequested = %d, %v; want remote exit code 5", code, ok)
+	}
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[1] != "me@box" || lines[2] != "cd ~/gt && exec gt mq list gastown" {
+		t.Errorf("ssh args = %q", lines)
+	}
+}
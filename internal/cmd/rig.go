@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/deps"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/permission"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -65,9 +67,16 @@ Use --adopt to register an existing directory instead of creating new:
   - Auto-detects git URL from origin remote (git-url argument not required)
   - Adds entry to mayor/rigs.json
 
+For huge repos, --filter makes the rig's clones partial clones that fetch
+file contents (blobless) or directories too (treeless) on demand, and
+--sparse limits polecat worktrees to the given directories. Both are kept
+in config.json as "clone"; edit sparse_paths there to change what new
+polecats check out.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add mono git@github.com:corp/mono.git --filter blobless --sparse services/api,libs
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -265,6 +274,8 @@ var (
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
+	rigAddFilter       string
+	rigAddSparse       []string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -298,6 +309,8 @@ func init() {
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
+	rigAddCmd.Flags().StringVar(&rigAddFilter, "filter", "", "Partial clone: blobless, treeless or a git filter spec (for huge repos)")
+	rigAddCmd.Flags().StringSliceVar(&rigAddSparse, "sparse", nil, "Check out only these directories in polecat worktrees (repeatable)")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -342,6 +355,67 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	fmt.Printf("Creating rig %s...\n", style.Bold.Render(name))
+	fmt.Printf("  Repository: %s\n", gitURL)
+	if rigAddLocalRepo != "" {
+		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
+	}
+	var cloneCfg *rig.CloneConfig
+	if rigAddFilter != "" || len(rigAddSparse) > 0 {
+		if _, err := rig.PartialCloneFilter(rigAddFilter); err != nil {
+			return err
+		}
+		cloneCfg = &rig.CloneConfig{Filter: rigAddFilter, SparsePaths: rigAddSparse}
+	}
+
+	startTime := time.Now()
+
+	newRig, err := addRigToTown(townRoot, rig.AddRigOptions{
+		Name:          name,
+		GitURL:        gitURL,
+		BeadsPrefix:   rigAddPrefix,
+		LocalRepo:     rigAddLocalRepo,
+		DefaultBranch: rigAddBranch,
+		Clone:         cloneCfg,
+	})
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(startTime)
+
+	// Read default branch from rig config
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(filepath.Join(townRoot, name)); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+
+	fmt.Printf("\n%s Rig created in %.1fs\n", style.Success.Render("✓"), elapsed.Seconds())
+	fmt.Printf("\nStructure:\n")
+	fmt.Printf("  %s/\n", name)
+	fmt.Printf("  ├── config.json\n")
+	fmt.Printf("  ├── .repo.git/        (shared bare repo for refinery+polecats)\n")
+	fmt.Printf("  ├── .beads/           (prefix: %s)\n", newRig.Config.Prefix)
+	fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
+	fmt.Printf("  ├── mayor/rig/        (clone: %s)\n", defaultBranch)
+	fmt.Printf("  ├── refinery/rig/     (worktree: %s, sees polecat branches)\n", defaultBranch)
+	fmt.Printf("  ├── crew/             (empty - add crew with 'gt crew add')\n")
+	fmt.Printf("  ├── witness/\n")
+	fmt.Printf("  └── polecats/\n")
+
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  gt crew add <name> --rig %s   # Create your personal workspace\n", name)
+	fmt.Printf("  cd %s/crew/<name>              # Start working\n", filepath.Join(townRoot, name))
+
+	return nil
+}
+
+// addRigToTown clones a new rig into the town and registers it: in
+// mayor/rigs.json, in routes.jsonl for its beads prefix, and with a rig
+// identity bead. Shared by gt rig add and gt apply.
+func addRigToTown(townRoot string, opts rig.AddRigOptions) (*rig.Rig, error) {
+	name, gitURL := opts.Name, opts.GitURL
+
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
@@ -357,29 +431,15 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
-	fmt.Printf("Creating rig %s...\n", style.Bold.Render(name))
-	fmt.Printf("  Repository: %s\n", gitURL)
-	if rigAddLocalRepo != "" {
-		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
-	}
-
-	startTime := time.Now()
-
 	// Add the rig
-	newRig, err := mgr.AddRig(rig.AddRigOptions{
-		Name:          name,
-		GitURL:        gitURL,
-		BeadsPrefix:   rigAddPrefix,
-		LocalRepo:     rigAddLocalRepo,
-		DefaultBranch: rigAddBranch,
-	})
+	newRig, err := mgr.AddRig(opts)
 	if err != nil {
-		return fmt.Errorf("adding rig: %w", err)
+		return nil, fmt.Errorf("adding rig: %w", err)
 	}
 
 	// Save updated rigs config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
-		return fmt.Errorf("saving rigs config: %w", err)
+	if err := saveRigEntry(rigsPath, name, rigsConfig); err != nil {
+		return nil, fmt.Errorf("saving rigs config: %w", err)
 	}
 
 	// Add route to town-level routes.jsonl for prefix-based routing.
@@ -425,38 +485,12 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  Created rig identity bead: %s\n", rigBeadID)
 		}
 	}
-
-	elapsed := time.Since(startTime)
-
-	// Read default branch from rig config
-	defaultBranch := "main"
-	if rigCfg, err := rig.LoadRigConfig(filepath.Join(townRoot, name)); err == nil && rigCfg.DefaultBranch != "" {
-		defaultBranch = rigCfg.DefaultBranch
-	}
-
-	fmt.Printf("\n%s Rig created in %.1fs\n", style.Success.Render("✓"), elapsed.Seconds())
-	fmt.Printf("\nStructure:\n")
-	fmt.Printf("  %s/\n", name)
-	fmt.Printf("  ├── config.json\n")
-	fmt.Printf("  ├── .repo.git/        (shared bare repo for refinery+polecats)\n")
-	fmt.Printf("  ├── .beads/           (prefix: %s)\n", newRig.Config.Prefix)
-	fmt.Printf("  ├── plugins/          (rig-level plugins)\n")
-	fmt.Printf("  ├── mayor/rig/        (clone: %s)\n", defaultBranch)
-	fmt.Printf("  ├── refinery/rig/     (worktree: %s, sees polecat branches)\n", defaultBranch)
-	fmt.Printf("  ├── crew/             (empty - add crew with 'gt crew add')\n")
-	fmt.Printf("  ├── witness/\n")
-	fmt.Printf("  └── polecats/\n")
-
-	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  gt crew add <name> --rig %s   # Create your personal workspace\n", name)
-	fmt.Printf("  cd %s/crew/<name>              # Start working\n", filepath.Join(townRoot, name))
-
-	return nil
+	return newRig, nil
 }
 
 func runRigList(cmd *cobra.Command, args []string) error {
 	// Find workspace
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := workspace.FindFromCwdOrContext()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
@@ -465,11 +499,13 @@ func runRigList(cmd *cobra.Command, args []string) error {
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
 	if err != nil {
-		fmt.Println("No rigs configured.")
-		return nil
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
 
 	if len(rigsConfig.Rigs) == 0 {
+		if structuredOutput(false) {
+			return renderStructured([]RigListItem{})
+		}
 		fmt.Println("No rigs configured.")
 		fmt.Printf("\nAdd one with: %s\n", style.Dim.Render("gt rig add <name> <git-url>"))
 		return nil
@@ -479,19 +515,21 @@ func runRigList(cmd *cobra.Command, args []string) error {
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
-	fmt.Printf("Rigs in %s:\n\n", townRoot)
-
+	names := make([]string, 0, len(rigsConfig.Rigs))
 	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]RigListItem, 0, len(names))
+	for _, name := range names {
 		r, err := mgr.GetRig(name)
 		if err != nil {
-			fmt.Printf("  %s %s\n", style.Warning.Render("!"), name)
+			items = append(items, RigListItem{Name: name, Error: err.Error()})
 			continue
 		}
 
 		summary := r.Summary()
-		fmt.Printf("  %s\n", style.Bold.Render(name))
-		fmt.Printf("    Polecats: %d  Crew: %d\n", summary.PolecatCount, summary.CrewCount)
-
 		agents := []string{}
 		if summary.HasRefinery {
 			agents = append(agents, "refinery")
@@ -502,8 +540,30 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		if r.HasMayor {
 			agents = append(agents, "mayor")
 		}
-		if len(agents) > 0 {
-			fmt.Printf("    Agents: %v\n", agents)
+		items = append(items, RigListItem{
+			Name:     name,
+			Polecats: summary.PolecatCount,
+			Crew:     summary.CrewCount,
+			Agents:   agents,
+		})
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(items)
+	}
+
+	fmt.Printf("Rigs in %s:\n\n", townRoot)
+
+	for _, item := range items {
+		if item.Error != "" {
+			fmt.Printf("  %s %s\n", style.Warning.Render("!"), item.Name)
+			continue
+		}
+
+		fmt.Printf("  %s\n", style.Bold.Render(item.Name))
+		fmt.Printf("    Polecats: %d  Crew: %d\n", item.Polecats, item.Crew)
+		if len(item.Agents) > 0 {
+			fmt.Printf("    Agents: %v\n", item.Agents)
 		}
 		fmt.Println()
 	}
@@ -511,6 +571,31 @@ func runRigList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// RigListItem represents a rig in structured list output.
+type RigListItem struct {
+	Name     string   `json:"name"`
+	Polecats int      `json:"polecats"`
+	Crew     int      `json:"crew"`
+	Agents   []string `json:"agents"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// saveRigEntry writes a rig's entry in rigsConfig, as the rig manager left
+// it, to mayor/rigs.json: added or updated if present, removed if not.
+// Other rigs' entries are kept as on disk, so rigs added or removed by
+// another gt since rigsConfig was loaded aren't lost.
+func saveRigEntry(rigsPath, name string, rigsConfig *config.RigsConfig) error {
+	_, err := config.RigsStore(rigsPath).Update(func(c *config.RigsConfig) error {
+		if entry, ok := rigsConfig.Rigs[name]; ok {
+			c.Rigs[name] = entry
+		} else {
+			delete(c.Rigs, name)
+		}
+		return nil
+	})
+	return err
+}
+
 func runRigRemove(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -519,6 +604,9 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if err := requirePermission(townRoot, permission.ActionRigRemove, name, nil); err != nil {
+		return err
+	}
 
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
@@ -542,7 +630,7 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save updated config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+	if err := saveRigEntry(rigsPath, name, rigsConfig); err != nil {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
@@ -598,7 +686,7 @@ func runRigAdopt(_ *cobra.Command, args []string) error {
 	}
 
 	// Save updated config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+	if err := saveRigEntry(rigsPath, name, rigsConfig); err != nil {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
@@ -82,13 +82,8 @@ func runRigDetect(cmd *cobra.Command, args []string) error {
 }
 
 func detectRigFromPath(townRoot, absPath string) string {
-	rel, err := filepath.Rel(townRoot, absPath)
-	if err != nil || strings.HasPrefix(rel, "..") {
-		return ""
-	}
-
-	parts := strings.Split(rel, string(filepath.Separator))
-	if len(parts) == 0 || parts[0] == "." {
+	parts, err := workspace.RelParts(townRoot, absPath)
+	if err != nil || len(parts) == 0 {
 		return ""
 	}
 
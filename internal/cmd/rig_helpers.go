@@ -14,7 +14,7 @@ import (
 // This is the common boilerplate extracted from get*Manager functions.
 // Returns the town root path and rig instance.
 func getRig(rigName string) (string, *rig.Rig, error) {
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := workspace.FindFromCwdOrContext()
 	if err != nil {
 		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
@@ -29,7 +29,7 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	r, err := rigMgr.GetRig(rigName)
 	if err != nil {
-		return "", nil, fmt.Errorf("rig '%s' not found", rigName)
+		return "", nil, withExitCode(ExitRigNotFound, fmt.Errorf("rig '%s' not found", rigName))
 	}
 
 	return townRoot, r, nil
@@ -42,7 +42,8 @@ func init() {
 	rigCmd.AddCommand(rigQuickAddCmd)
 	rigQuickAddCmd.Flags().StringVar(&quickAddUser, "user", "", "Crew workspace name (default: $USER)")
 	rigQuickAddCmd.Flags().BoolVar(&quickAddYes, "yes", false, "Non-interactive, assume yes")
-	rigQuickAddCmd.Flags().BoolVar(&quickAddQuiet, "quiet", false, "Minimal output")
+	legacyQuietFlag(rigQuickAddCmd, &quickAddQuiet, "Minimal output",
+		"it will hide all normal output, as on other commands, in a future release")
 }
 
 func runRigQuickAdd(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Remotes command flags
+var rigRemotesSetMirror bool
+
+var rigRemotesCmd = &cobra.Command{
+	Use:   "remotes <rig>",
+	Short: "Show or change a rig's git remotes",
+	Long: `Show a rig's git remotes, or change them with the subcommands.
+
+Every rig clone has origin, at the rig's git_url, where workers fetch from
+and push their branches. A rig can carry more remotes, e.g. a fork or an
+internal mirror, recorded in the rig's config.json and configured in its
+shared bare repo and mayor clone:
+
+  - The push remote is where the refinery merges into and pushes: it
+    fetches the target branch from it, lands merges, release tags and
+    attestation notes on it, and gt rig sync fast-forwards to it.
+    Defaults to origin.
+  - Mirror remotes get each landed merge's target branch pushed to them
+    too. A mirror that can't take the push is only a warning.
+
+Examples:
+  gt rig remotes gastown
+  gt rig remotes set gastown fork git@github.com:me/gastown.git
+  gt rig remotes push gastown fork
+  gt rig remotes set gastown internal git@git.corp:gastown.git --mirror
+  gt rig remotes remove gastown internal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigRemotes,
+}
+
+var rigRemotesSetCmd = &cobra.Command{
+	Use:   "set <rig> <name> <url>",
+	Short: "Add a remote, or change its URL or mirror flag",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runRigRemotesSet,
+}
+
+var rigRemotesRemoveCmd = &cobra.Command{
+	Use:   "remove <rig> <name>",
+	Short: "Remove a remote (not the push remote)",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRigRemotesRemove,
+}
+
+var rigRemotesPushCmd = &cobra.Command{
+	Use:   "push <rig> <name>",
+	Short: "Set the remote the refinery merges into (origin to reset)",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRigRemotesPush,
+}
+
+func init() {
+	rigRemotesSetCmd.Flags().BoolVar(&rigRemotesSetMirror, "mirror", false, "Push each landed merge to this remote too")
+
+	rigRemotesCmd.AddCommand(rigRemotesSetCmd)
+	rigRemotesCmd.AddCommand(rigRemotesRemoveCmd)
+	rigRemotesCmd.AddCommand(rigRemotesPushCmd)
+	rigCmd.AddCommand(rigRemotesCmd)
+}
+
+// RigRemote is one remote in gt rig remotes output.
+type RigRemote struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Push   bool   `json:"push,omitempty"`
+	Mirror bool   `json:"mirror,omitempty"`
+}
+
+// RigRemotesOutput is the structured output for gt rig remotes.
+type RigRemotesOutput struct {
+	Rig     string      `json:"rig"`
+	Remotes []RigRemote `json:"remotes"`
+}
+
+func runRigRemotes(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := rig.LoadRigConfig(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+
+	push := r.PushRemote()
+	out := RigRemotesOutput{Rig: r.Name, Remotes: []RigRemote{{
+		Name: rig.OriginRemote,
+		URL:  cfg.GitURL,
+		Push: push == rig.OriginRemote,
+	}}}
+	for _, remote := range cfg.Remotes {
+		out.Remotes = append(out.Remotes, RigRemote{
+			Name:   remote.Name,
+			URL:    remote.URL,
+			Push:   push == remote.Name,
+			Mirror: remote.Mirror && push != remote.Name,
+		})
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	fmt.Printf("%s\n", style.Bold.Render(r.Name))
+	for _, remote := range out.Remotes {
+		var role string
+		switch {
+		case remote.Push:
+			role = style.Success.Render("push")
+		case remote.Mirror:
+			role = "mirror"
+		case remote.Name == rig.OriginRemote:
+			role = style.Dim.Render("fetch")
+		}
+		fmt.Printf("  %-12s %-6s %s\n", remote.Name, role, style.Dim.Render(remote.URL))
+	}
+	return nil
+}
+
+func runRigRemotesSet(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	remote := rig.RemoteConfig{Name: args[1], URL: args[2], Mirror: rigRemotesSetMirror}
+	if err := rig.SetRemote(r.Path, remote); err != nil {
+		return err
+	}
+	fmt.Printf("%s Set remote %s on %s\n", style.Success.Render("✓"), remote.Name, r.Name)
+	if remote.Mirror {
+		fmt.Printf("  The refinery will mirror landed merges to it\n")
+	}
+	return nil
+}
+
+func runRigRemotesRemove(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	if err := rig.RemoveRemote(r.Path, args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed remote %s from %s\n", style.Success.Render("✓"), args[1], r.Name)
+	return nil
+}
+
+func runRigRemotesPush(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	if err := rig.SetPushRemote(r.Path, args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s The %s refinery now merges into %s\n", style.Success.Render("✓"), r.Name, args[1])
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -132,31 +133,21 @@ func runRigSettingsSet(cmd *cobra.Command, args []string) error {
 
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
 
-	// Load existing settings or create new
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err != nil {
-		if errors.Is(err, config.ErrNotFound) {
-			// Create new settings with scaffold
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
-		}
-	}
-
 	// Parse the value
 	value, err := parseValue(valueStr)
 	if err != nil {
 		return fmt.Errorf("parsing value: %w", err)
 	}
 
-	// Set the value using dot notation
-	if err := setNestedValue(settings, keyPath, value); err != nil {
-		return fmt.Errorf("setting %s: %w", keyPath, err)
-	}
-
-	// Save the settings
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
-		return fmt.Errorf("saving settings: %w", err)
+	// Set the value using dot notation, creating the settings if needed
+	_, err = config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		if err := setNestedValue(settings, keyPath, value); err != nil {
+			return fmt.Errorf("setting %s: %w", keyPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("%s Set %s=%v in settings for rig %s\n",
@@ -175,23 +166,19 @@ func runRigSettingsUnset(cmd *cobra.Command, args []string) error {
 
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
 
-	// Load existing settings
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err != nil {
-		if errors.Is(err, config.ErrNotFound) {
-			return fmt.Errorf("settings file not found at %s", settingsPath)
-		}
-		return fmt.Errorf("loading settings: %w", err)
+	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+		return fmt.Errorf("settings file not found at %s", settingsPath)
 	}
 
 	// Unset the value using dot notation
-	if err := unsetNestedValue(settings, keyPath); err != nil {
-		return fmt.Errorf("unsetting %s: %w", keyPath, err)
-	}
-
-	// Save the settings
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
-		return fmt.Errorf("saving settings: %w", err)
+	_, err = config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		if err := unsetNestedValue(settings, keyPath); err != nil {
+			return fmt.Errorf("unsetting %s: %w", keyPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("%s Unset %s from settings for rig %s\n",
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var rigSyncCmd = &cobra.Command{
+	Use:   "sync [rig]...",
+	Short: "Fetch and fast-forward rig clones, reporting ones that drifted",
+	Long: `Bring rig clones up to date with their remote.
+
+For each rig (all rigs when none are named), sync:
+  - Fetches into the shared bare repo (.repo.git) and the mayor clone,
+    pruning branches deleted on the remote
+  - Fast-forwards the default branch in each, including the refinery's
+    checkout of it, to the rig's push remote (origin unless set with
+    gt rig remotes push)
+  - Reports a default branch that has diverged from the remote or can't be
+    fast-forwarded (uncommitted changes), and checkouts left on a
+    detached HEAD
+
+Nothing is reset or rebased: diverged branches are left for a human.
+Stale rig clones are a common source of spurious refinery conflicts; the
+daemon runs this on each heartbeat when patrols.rig_sync is enabled in
+mayor/daemon.json.
+
+Examples:
+  gt rig sync
+  gt rig sync gastown beads
+  gt rig sync --output json`,
+	RunE: runRigSync,
+}
+
+func init() {
+	rigCmd.AddCommand(rigSyncCmd)
+}
+
+func runRigSync(cmd *cobra.Command, args []string) error {
+	names := args
+	if len(names) == 0 {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		rigs, err := discoverAllRigs(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, r := range rigs {
+			names = append(names, r.Name)
+		}
+		sort.Strings(names)
+	}
+
+	results := make([]*rig.SyncResult, 0, len(names))
+	for _, name := range names {
+		_, r, err := getRig(name)
+		if err != nil {
+			return err
+		}
+		results = append(results, rig.Sync(r.Path))
+	}
+
+	failed := 0
+	for _, result := range results {
+		for _, repo := range result.Repos {
+			if repo.Error != "" {
+				failed++
+				break
+			}
+		}
+	}
+
+	if structuredOutput(false) {
+		if err := renderStructured(results); err != nil {
+			return err
+		}
+	} else {
+		if len(results) == 0 {
+			fmt.Println("No rigs configured.")
+		}
+		for _, result := range results {
+			printRigSync(result)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d rig(s)", failed)
+	}
+	return nil
+}
+
+// printRigSync prints one rig's sync result.
+func printRigSync(result *rig.SyncResult) {
+	icon := style.Success.Render("✓")
+	if result.NeedsAttention() {
+		icon = style.Warning.Render("!")
+	}
+	fmt.Printf("%s %s %s\n", icon, style.Bold.Render(result.Rig), style.Dim.Render("("+result.Remote+"/"+result.Branch+")"))
+
+	if len(result.Repos) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no clones"))
+	}
+	for _, repo := range result.Repos {
+		var notes []string
+		switch {
+		case repo.Error != "":
+			notes = append(notes, style.Error.Render(repo.Error))
+		case repo.Diverged():
+			notes = append(notes, style.Warning.Render(fmt.Sprintf("diverged: %d ahead, %d behind %s", repo.Ahead, repo.Behind, result.Remote)))
+		case repo.Skipped != "":
+			notes = append(notes, style.Warning.Render(fmt.Sprintf("%d behind %s, not fast-forwarded: %s", repo.Behind, result.Remote, repo.Skipped)))
+		case repo.Updated:
+			notes = append(notes, "fast-forwarded")
+		case repo.Ahead > 0:
+			notes = append(notes, fmt.Sprintf("%d ahead of %s", repo.Ahead, result.Remote))
+		default:
+			notes = append(notes, style.Dim.Render("up to date"))
+		}
+		if len(repo.Pruned) > 0 {
+			notes = append(notes, fmt.Sprintf("pruned %d branch(es)", len(repo.Pruned)))
+		}
+		fmt.Printf("  %-12s %s\n", repo.Path, strings.Join(notes, "; "))
+	}
+	for _, path := range result.Detached {
+		fmt.Printf("  %-12s %s\n", path, style.Warning.Render("detached HEAD"))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -22,7 +23,22 @@ var rootCmd = &cobra.Command{
 	Long: `Gas Town (gt) manages multi-agent workspaces called rigs.
 
 It coordinates agent spawning, work distribution, and communication
-across distributed teams of AI agents working on shared codebases.`,
+across distributed teams of AI agents working on shared codebases.
+
+Exit codes:
+  0  success
+  1  general error
+  3  not in a Gas Town workspace
+  4  rig not found
+  5  merge request not found
+  6  merge queue paused (rig parked/docked or outside merge schedule)
+  7  merge conflict
+  8  tests or merge checks failed
+  9  permission denied (role may not run this destructive command)
+  10 WIP limit reached (override with --ignore-wip)
+  11 issue claimed by another worker (see gt claim)
+
+Use --quiet to suppress normal output and rely on the exit code.`,
 	PersistentPreRunE: persistentPreRun,
 }
 
@@ -54,6 +70,10 @@ var beadsExemptCommands = map[string]bool{
 	"tap":        true,
 	"dnd":        true,
 	"krc":        true, // KRC doesn't require beads
+	"helper":     true,
+	"serve":      true, // gt helper serve: runs bd on behalf of callers, needs none itself
+	"schema":     true,
+	"upgrade":    true, // Updates gt itself; migrations run bd as they need it
 }
 
 // Commands exempt from the town root branch warning.
@@ -80,6 +100,22 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	if err := validateOutputFormat(); err != nil {
+		return err
+	}
+	if err := applyQuietMode(cmd); err != nil {
+		return err
+	}
+	if err := applyPlainMode(); err != nil {
+		return err
+	}
+	if err := applyTownContextFlag(); err != nil {
+		return err
+	}
+	if err := applyTimeFormat(); err != nil {
+		return err
+	}
+
 	// Initialize CLI theme (dark/light mode support)
 	initCLITheme()
 
@@ -217,15 +253,28 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
-		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
-		}
-		// Other errors already printed by cobra
-		return 1
+	// --remote proxies the whole command line over SSH before any local
+	// parsing or workspace checks.
+	if code, ok := runRemoteIfRequested(os.Args[1:]); ok {
+		return code
+	}
+	if code, ok := runSchemaIfRequested(os.Args[1:]); ok {
+		return code
+	}
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	finishPlainMode()
+	if err == nil {
+		warnIgnoredOutputFormat(cmd)
+	}
+	code := ExitOK
+	if err != nil {
+		// Errors are already printed by cobra (silent exits print nothing).
+		// Map the error to a documented exit code for scripting.
+		code = exitCodeFor(err)
 	}
-	return 0
+	recordInvocation(cmd, os.Args[1:], started, code, err)
+	return code
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/claim"
+	"github.com/steveyegge/gastown/internal/crash"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/gc"
+	"github.com/steveyegge/gastown/internal/helper"
+	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracker"
+)
+
+// outputSchemas maps a command path (without the leading "gt") to a value of
+// the type that command emits with --output json|yaml. Commands added here
+// have a stable, documented structured output.
+var outputSchemas = map[string]interface{}{
+	"apply":                ApplyOutput{},
+	"audit list":           []auditlog.Record{},
+	"bisect":               BisectOutput{},
+	"changelog":            ChangelogOutput{},
+	"check":                CheckOutput{},
+	"claim":                claim.Lease{},
+	"context":              ContextOutput{},
+	"costs time":           CostsTimeOutput{},
+	"crashes list":         []*crash.Report{},
+	"crashes show":         CrashShowOutput{},
+	"daemon status":        DaemonStatusOutput{},
+	"doctor":               DoctorOutput{},
+	"events tail":          events.Event{},
+	"export":               ExportOutput{},
+	"gc":                   gc.Report{},
+	"helper status":        helper.Stats{},
+	"import":               ImportOutput{},
+	"import github-issues": ImportGitHubIssuesOutput{},
+	"issue new":            IssueNewOutput{},
+	"issue start":          IssueStartOutput{},
+	"issue split":          IssueSplitOutput{},
+	"kb list":              []KBListItem{},
+	"kb search":            []refinery.KBMatch{},
+	"krc stats":            krc.Stats{},
+	"mail digest":          MailDigestOutput{},
+	"mayor status":         MayorStatusOutput{},
+	"mq archive":           MQArchiveOutput{},
+	"mq artifacts":         MQArtifactsOutput{},
+	"mq assign":            MRAssignOutput{},
+	"mq backport":          MRBackportOutput{},
+	"mq conflicts":         MQConflictsOutput{},
+	"mq diff":              MRDiffOutput{},
+	"mq diff-snapshots":    MQDiffSnapshotsOutput{},
+	"mq land":              MRLandOutput{},
+	"mq list":              []MQListItem{},
+	"mq revert":            MRRevertOutput{},
+	"mq simulate":          MQSimulateOutput{},
+	"mq snapshot":          MQSnapshotOutput{},
+	"mq state":             MRStateOutput{},
+	"mq status":            MRStatusOutput{},
+	"mq test":              MRTestOutput{},
+	"mq verify":            MRVerifyOutput{},
+	"refinery flakes":      RefineryFlakesOutput{},
+	"refinery test-config": RefineryTestConfigOutput{},
+	"polecat list":         []PolecatListItem{},
+	"polecat profile":      []PolecatProfileEntry{},
+	"polecat profile show": PolecatProfileEntry{},
+	"release":              ReleaseOutput{},
+	"rig list":             []RigListItem{},
+	"rig remotes":          RigRemotesOutput{},
+	"rig sync":             []*rig.SyncResult{},
+	"search":               []SearchResult{},
+	"secret list":          []secrets.Info{},
+	"standup":              StandupOutput{},
+	"status":               TownStatus{},
+	"town list":            []TownListItem{},
+	"tracker sync":         []*tracker.Result{},
+	"upgrade":              UpgradeOutput{},
+	"webhook list":         []WebhookListItem{},
+}
+
+// Schema versions of the commands whose structured output is published.
+// Bump a command's version when its output changes in a way that breaks
+// consumers (a field removed, renamed or retyped), and publish the new
+// schema alongside the old one; additive changes refresh the current
+// version's file in place.
+const (
+	mqListSchemaVersion   = 1
+	mqStatusSchemaVersion = 1
+)
+
+// schemaVersions maps a command path to the current version of its
+// published schema. Its output carries the version as schema_version, and
+// each version's schema is embedded from schemas/<command>.v<N>.json
+// (the path dotted: mq.status.v1.json).
+var schemaVersions = map[string]int{
+	"mq list":   mqListSchemaVersion,
+	"mq status": mqStatusSchemaVersion,
+}
+
+//go:embed schemas/*.json
+var publishedSchemas embed.FS
+
+// Schema command flags
+var schemaVersionFlag int
+
+// printSchema is the value of the global --schema flag. Execute handles the
+// flag before cobra parses arguments; it is registered so help shows it.
+var printSchema bool
+
+var schemaCmd = &cobra.Command{
+	Use:     "schema [command]",
+	GroupID: GroupDiag,
+	Short:   "Show the structured output schema for a command",
+	Long: `Show the JSON schema of a command's structured output.
+
+Commands listed here honor the global --output flag:
+
+  gt rig list --output json
+  gt mq list greenplace -o yaml
+
+Without arguments, lists the commands that have a documented schema.
+Commands may be named with spaces or dots (mq.status), and the global
+--schema flag prints the same schema from the command itself.
+
+Some commands publish versioned schemas: their output carries a
+schema_version field, bumped whenever the output changes in a way that
+breaks consumers. Older versions stay available with --schema-version.
+
+Examples:
+  gt schema
+  gt schema "mq list"
+  gt schema mq.status
+  gt schema mq.status --schema-version 1
+  gt mq status --schema
+  gt schema polecat list`,
+	RunE: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().IntVar(&schemaVersionFlag, "schema-version", 0, "Print this published version of the schema (default: current)")
+	rootCmd.PersistentFlags().BoolVar(&printSchema, "schema", false,
+		"Print the JSON schema of the command's structured output instead of running it")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(outputSchemas))
+		for name := range outputSchemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if structuredOutput(false) {
+			return renderStructured(names)
+		}
+
+		fmt.Printf("%s\n\n", style.Bold.Render("Commands with structured output:"))
+		for _, name := range names {
+			if v, ok := schemaVersions[name]; ok {
+				fmt.Printf("  gt %s %s\n", name, style.Dim.Render(fmt.Sprintf("(v%d)", v)))
+				continue
+			}
+			fmt.Printf("  gt %s\n", name)
+		}
+		fmt.Printf("\nShow a schema with: %s\n", style.Dim.Render("gt schema <command>"))
+		return nil
+	}
+
+	name := strings.ReplaceAll(strings.TrimPrefix(strings.Join(args, " "), "gt "), ".", " ")
+	schema, err := outputSchema(name, schemaVersionFlag)
+	if err != nil {
+		return err
+	}
+	return writeStructured(cmd.OutOrStdout(), schema, outputFormat)
+}
+
+// outputSchema returns the schema of a command's structured output: the
+// published one for a versioned command (version 0 meaning the current
+// one), otherwise the schema reflected from its output type.
+func outputSchema(name string, version int) (interface{}, error) {
+	if _, ok := outputSchemas[name]; !ok {
+		return nil, fmt.Errorf("no output schema for %q (run 'gt schema' to list commands)", name)
+	}
+	current, versioned := schemaVersions[name]
+	if !versioned {
+		if version != 0 {
+			return nil, fmt.Errorf("gt %s has no versioned schema", name)
+		}
+		return reflectedSchema(name), nil
+	}
+	if version == 0 {
+		version = current
+	}
+	data, err := publishedSchemas.ReadFile(publishedSchemaFile(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("gt %s has no schema version %d (current is %d)", name, version, current)
+	}
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parsing published schema for gt %s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// publishedSchemaFile is the embedded path of a published schema version.
+func publishedSchemaFile(name string, version int) string {
+	return "schemas/" + strings.ReplaceAll(name, " ", ".") + ".v" + strconv.Itoa(version) + ".json"
+}
+
+// reflectedSchema builds the schema of a command's output from its type.
+// For a versioned command, schema_version is pinned to the current version.
+func reflectedSchema(name string) map[string]interface{} {
+	schema := jsonSchemaFor(reflect.TypeOf(outputSchemas[name]), map[reflect.Type]bool{})
+	if version, ok := schemaVersions[name]; ok {
+		obj := schema
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			obj = items
+		}
+		if props, ok := obj["properties"].(map[string]interface{}); ok {
+			props["schema_version"] = map[string]interface{}{"type": "integer", "const": version}
+		}
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "gt " + name
+	return schema
+}
+
+// runSchemaIfRequested prints the output schema of the command named by
+// args if they include --schema, without running it. ok is false when
+// --schema wasn't given. Arguments after "--" are left alone.
+func runSchemaIfRequested(args []string) (code int, ok bool) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if arg == "--schema" || arg == "--schema=true" {
+			ok = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	if !ok {
+		return 0, false
+	}
+
+	target, _, err := rootCmd.Find(rest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError, true
+	}
+	name := strings.TrimPrefix(target.CommandPath(), rootCmd.Name()+" ")
+	schema, err := outputSchema(name, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError, true
+	}
+	if err := writeStructured(os.Stdout, schema, OutputJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError, true
+	}
+	return ExitOK, true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchemaFor builds a JSON schema for t from its encoding/json tags.
+// seen guards against infinite recursion on self-referencing types.
+func jsonSchemaFor(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonSchemaFor(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchemaFor(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		props := map[string]interface{}{}
+		var required []string
+		addStructFields(t, props, &required, seen)
+		schema := map[string]interface{}{"type": "object", "properties": props}
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// addStructFields adds t's exported fields to props, flattening embedded
+// structs the same way encoding/json does.
+func addStructFields(t reflect.Type, props map[string]interface{}, required *[]string, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addStructFields(f.Type, props, required, seen)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = jsonSchemaFor(f.Type, seen)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
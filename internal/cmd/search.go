@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Search command flags
+var (
+	searchRig      string
+	searchArchived bool
+	searchLimit    int
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	GroupID: GroupWork,
+	Short:   "Search the rigs' beads, including archived MRs",
+	Long: `Search the beads of every rig (or --rig) for a query.
+
+A bead matches when every word of the query appears in its ID, title or
+description, ignoring case. Open and closed beads are both searched.
+
+--archived also searches the merge requests moved out of the beads
+database by gt mq archive or the rig's merge_queue.archive_after policy.
+
+Examples:
+  gt search "login redirect"
+  gt search polecat/Toast --rig greenplace
+  gt search gp-1a2b --archived          # Find an MR's bead, even archived`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchRig, "rig", "", "Only search this rig")
+	searchCmd.Flags().BoolVar(&searchArchived, "archived", false, "Also search archived merge requests")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 50, "Maximum results to show (0 = all)")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+// SearchResult is one match in gt search output.
+type SearchResult struct {
+	Rig      string `json:"rig"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Type     string `json:"type,omitempty"`
+	Archived bool   `json:"archived,omitempty"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	terms := strings.Fields(strings.ToLower(args[0]))
+	if len(terms) == 0 {
+		return fmt.Errorf("empty search query")
+	}
+
+	var rigs []*rig.Rig
+	if searchRig != "" {
+		_, r, err := getRig(searchRig)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	} else {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	results := []SearchResult{}
+	for _, r := range rigs {
+		bd := beads.New(r.BeadsPath())
+		live, err := bd.ListIndexed(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			return fmt.Errorf("searching %s: %w", r.Name, err)
+		}
+		results = append(results, searchBeads(r.Name, live, terms, false)...)
+
+		if searchArchived {
+			archived, err := bd.ListArchivedMRs()
+			if err != nil {
+				return fmt.Errorf("reading %s MR archive: %w", r.Name, err)
+			}
+			results = append(results, searchBeads(r.Name, archived, terms, true)...)
+		}
+	}
+
+	total := len(results)
+	if searchLimit > 0 && total > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(results)
+	}
+
+	fmt.Printf("%s %d match(es) for %q\n\n", style.Bold.Render("🔍"), total, args[0])
+	if total == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
+		if !searchArchived {
+			fmt.Printf("  %s\n", style.Dim.Render("Archived MRs aren't searched without --archived"))
+		}
+		return nil
+	}
+	table := style.NewTable(
+		style.Column{Name: "RIG", Width: 12},
+		style.Column{Name: "ID", Width: 14},
+		style.Column{Name: "STATUS", Width: 11},
+		style.Column{Name: "TITLE", Width: 60},
+	)
+	for _, res := range results {
+		status := res.Status
+		if res.Archived {
+			status = style.Dim.Render("archived")
+		}
+		table.AddRow(res.Rig, res.ID, status, res.Title)
+	}
+	fmt.Print(table.Render())
+	if len(results) < total {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("(showing %d of %d; use --limit 0 for all)", len(results), total)))
+	}
+	return nil
+}
+
+// searchBeads returns the issues in which every term (lowercase) appears in
+// the ID, title or description, ordered by ID.
+func searchBeads(rigName string, issues []*beads.Issue, terms []string, archived bool) []SearchResult {
+	var results []SearchResult
+	for _, issue := range issues {
+		text := strings.ToLower(issue.ID + "\n" + issue.Title + "\n" + issue.Description)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, SearchResult{
+				Rig:      rigName,
+				ID:       issue.ID,
+				Title:    issue.Title,
+				Status:   issue.Status,
+				Type:     issue.Type,
+				Archived: archived,
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestSearchBeads(t *testing.T) {
+	issues := []*beads.Issue{
+		{ID: "gp-2", Title: "Fix login redirect", Status: "open"},
+		{ID: "gp-1", Title: "Login page", Description: "The redirect after login loops", Status: "closed"},
+		{ID: "gp-3", Title: "Logout", Status: "open"},
+	}
+
+	got := searchBeads("greenplace", issues, []string{"login", "redirect"}, false)
+	if len(got) != 2 || got[0].ID != "gp-1" || got[1].ID != "gp-2" {
+		t.Fatalf("searchBeads = %+v, want gp-1 then gp-2", got)
+	}
+	if got[0].Rig != "greenplace" || got[0].Status != "closed" || got[0].Archived {
+		t.Errorf("result = %+v", got[0])
+	}
+
+	if got := searchBeads("greenplace", issues, []string{"gp-3"}, true); len(got) != 1 || !got[0].Archived {
+		t.Errorf("archived search by ID = %+v", got)
+	}
+	if got := searchBeads("greenplace", issues, []string{"nothing"}, false); len(got) != 0 {
+		t.Errorf("unmatched search = %+v", got)
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Manage encrypted integration secrets",
+	RunE:    requireSubcommand,
+	Long: `Store tokens, webhook URLs, and other credentials encrypted at rest.
+
+Secrets are sealed into ~/gt/settings/secrets.json with a key kept outside
+the town (~/.config/gastown/secret.key, or GT_SECRET_KEY). Config files
+refer to a secret as "secret:<name>" instead of holding the plaintext:
+
+  settings/escalation.json:  "slack_webhook": "secret:slack-webhook"
+
+Reading and changing secrets requires the operator or mayor permission
+level; polecats cannot.
+
+Commands:
+  gt secret set <name>       Store a secret (value read from stdin)
+  gt secret get <name>       Print a secret
+  gt secret list             List secret names
+  gt secret remove <name>    Delete a secret`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret",
+	Long: `Store a secret under a name, replacing any existing value.
+
+The value is read from stdin (prompted without echo on a terminal) so it
+never appears in shell history, process listings, or the audit log.
+
+Examples:
+  gt secret set slack-webhook                 # Prompts for the value
+  gh auth token | gt secret set github-token`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretSet,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret",
+	Long: `Print the decrypted value of a secret to stdout.
+
+Examples:
+  gt secret get github-token
+  curl -H "Authorization: token $(gt secret get github-token)" ...`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretGet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secret names",
+	Long: `List stored secret names and when each was last set. Values are not
+shown.
+
+Examples:
+  gt secret list
+  gt secret list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runSecretList,
+}
+
+var secretRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a secret",
+	Long: `Delete a stored secret. Config references to it stop resolving.
+
+Examples:
+  gt secret remove slack-webhook`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretRemove,
+}
+
+func init() {
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretRemoveCmd)
+
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := requirePermission(townRoot, permission.ActionSecretSet, name, nil); err != nil {
+		return err
+	}
+
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+	if err := secrets.Set(townRoot, name, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Stored secret %s\n", style.Success.Render("✓"), name)
+	fmt.Printf("  Reference it in config as: %s%s\n", secrets.RefPrefix, name)
+	return nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := requirePermission(townRoot, permission.ActionSecretGet, args[0], nil); err != nil {
+		return err
+	}
+
+	value, err := secrets.Get(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	infos, err := secrets.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if structuredOutput(false) {
+		return renderStructured(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Println(style.Dim.Render("No secrets stored. Add one with: gt secret set <name>"))
+		return nil
+	}
+	for _, info := range infos {
+		fmt.Printf("  %-24s %s\n", info.Name,
+			style.Dim.Render("updated "+info.UpdatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runSecretRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := requirePermission(townRoot, permission.ActionSecretRemove, args[0], nil); err != nil {
+		return err
+	}
+
+	if err := secrets.Remove(townRoot, args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed secret %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+// readSecretValue reads a secret from stdin: a no-echo prompt on a terminal,
+// otherwise everything piped in, minus the trailing newline.
+func readSecretValue(name string) (string, error) {
+	var value string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading secret: %w", err)
+		}
+		value = string(data)
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading secret from stdin: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		return "", fmt.Errorf("empty secret value for %s", name)
+	}
+	return value, nil
+}
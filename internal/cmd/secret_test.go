@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/permission"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+func TestSecretGetDeniedForPolecat(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(secrets.EnvKey, "")
+	t.Setenv(EnvGTRole, "")
+	townRoot := setupPermissionTown(t, nil)
+	if err := secrets.Set(townRoot, "github-token", "ghp_example"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	t.Setenv(EnvGTRole, "greenplace/polecats/Toast")
+	err := runSecretGet(secretGetCmd, []string{"github-token"})
+	var denied *permission.DeniedError
+	if !errors.As(err, &denied) || denied.Action != permission.ActionSecretGet {
+		t.Errorf("runSecretGet as polecat = %v, want DeniedError", err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -58,5 +57,5 @@ func execBdShow(args []string) error {
 	fullArgs := append([]string{"bd", "show"}, args...)
 
 	// Replace process with bd show
-	return syscall.Exec(bdPath, fullArgs, os.Environ())
+	return execReplace(bdPath, fullArgs, os.Environ())
 }
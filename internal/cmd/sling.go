@@ -77,7 +77,20 @@ Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
   When multiple beads are provided with a rig target, each bead gets its own
-  polecat. This parallelizes work dispatch without running gt sling N times.`,
+  polecat. This parallelizes work dispatch without running gt sling N times.
+
+WIP Limits:
+  If the target's rig sets wip.max_in_progress_per_worker and an existing
+  crew member or polecat already has that many issues hooked or in progress,
+  nothing is slung and the command exits with code 10. Fresh polecats start
+  empty, so rig targets are never refused.
+
+  gt sling gt-abc greenplace/crew/max --ignore-wip   # Sling past the limit
+
+Claims:
+  An issue claimed by another worker (see gt claim), or in progress under
+  one, isn't slung to anyone else: the command exits with code 11, and no
+  polecat is spawned for it. --force re-slings it anyway.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -98,6 +111,8 @@ var (
 	slingAgent    string // --agent: override runtime agent for this sling/spawn
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
 	slingNoMerge  bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
+
+	slingIgnoreWIP bool // --ignore-wip: sling past the target rig's WIP limit
 )
 
 func init() {
@@ -116,6 +131,7 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
+	slingCmd.Flags().BoolVar(&slingIgnoreWIP, "ignore-wip", false, "Sling even if the target is at its rig's in-progress limit")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -202,6 +218,18 @@ func runSling(cmd *cobra.Command, args []string) error {
 	var delayedDogInfo *DogDispatchInfo     // For delayed dog session start after hook is set
 	var newPolecatInfo *SpawnedPolecatInfo  // Spawned polecat info (session started after bead setup)
 
+	// Refuse claimed work before spawning a polecat or dog for it; named
+	// agents are checked once resolved, below
+	if len(args) > 1 && !slingForce {
+		_, isDog := IsDogTarget(args[1])
+		_, isRig := IsRigName(args[1])
+		if isDog || isRig {
+			if err := checkClaim(beadID, ""); err != nil {
+				return fmt.Errorf("%w\nUse --force to re-sling", err)
+			}
+		}
+	}
+
 	if len(args) > 1 {
 		target := args[1]
 
@@ -324,6 +352,13 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Refuse more work for an agent already at its rig's WIP limit
+	if newPolecatInfo == nil && !slingIgnoreWIP {
+		if err := checkSlingWIP(targetAgent, beadID); err != nil {
+			return err
+		}
+	}
+
 	// Display what we're doing
 	if formulaName != "" {
 		fmt.Printf("%s Slinging formula %s on %s to %s...\n", style.Bold.Render("🎯"), formulaName, beadID, targetAgent)
@@ -344,6 +379,13 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("bead %s is already %s to %s\nUse --force to re-sling", beadID, info.Status, assignee)
 	}
 
+	// Refuse work another worker has claimed
+	if !slingForce {
+		if err := checkClaim(beadID, targetAgent); err != nil {
+			return fmt.Errorf("%w\nUse --force to re-sling", err)
+		}
+	}
+
 	// Handle --force when bead is already hooked: send shutdown to old polecat and unhook
 	if info.Status == "hooked" && slingForce && info.Assignee != "" {
 		fmt.Printf("%s Bead already hooked to %s, forcing reassignment...\n", style.Warning.Render("⚠"), info.Assignee)
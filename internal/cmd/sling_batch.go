@@ -64,6 +64,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  %s Already pinned (use --force to re-sling)\n", style.Dim.Render("✗"))
 			continue
 		}
+		if !slingForce {
+			if err := checkClaim(beadID, ""); err != nil {
+				results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+				fmt.Printf("  %s %v (use --force to re-sling)\n", style.Dim.Render("✗"), err)
+				continue
+			}
+		}
 
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
@@ -10,7 +10,10 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/wip"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -21,6 +24,43 @@ type beadInfo struct {
 	Assignee string `json:"assignee"`
 }
 
+// checkSlingWIP enforces the in-progress limit of targetAgent's rig. Only
+// crew and polecats have one; town agents, dogs, and the rig's own
+// witness and refinery are never refused.
+func checkSlingWIP(targetAgent, beadID string) error {
+	id, err := session.ParseAddress(targetAgent)
+	if err != nil || (id.Role != session.RoleCrew && id.Role != session.RolePolecat) {
+		return nil
+	}
+	_, r, err := getRig(id.Rig)
+	if err != nil {
+		return nil
+	}
+	limits, err := wip.Load(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading WIP limits: %w", err)
+	}
+	if limits.MaxInProgress() == 0 {
+		return nil
+	}
+
+	bd := beads.New(r.BeadsPath())
+	var assigned []*beads.Issue
+	for _, status := range []string{beads.StatusHooked, "in_progress"} {
+		issues, err := bd.List(beads.ListOptions{Status: status, Assignee: targetAgent, Priority: -1})
+		if err != nil {
+			// Non-fatal: a limit we can't count shouldn't block dispatch
+			style.PrintWarning("could not check WIP limit: %v", err)
+			return nil
+		}
+		assigned = append(assigned, issues...)
+	}
+	if err := limits.CheckInProgress(targetAgent, assigned, beadID); err != nil {
+		return fmt.Errorf("%w\nFinish or unhook some first, or use --ignore-wip", err)
+	}
+	return nil
+}
+
 // verifyBeadExists checks that the bead exists using bd show.
 // Uses bd's native prefix-based routing via routes.jsonl - do NOT set BEADS_DIR
 // as that overrides routing and breaks resolution of rig-level beads.
@@ -11,7 +11,6 @@ import (
 )
 
 var staleJSON bool
-var staleQuiet bool
 
 var staleCmd = &cobra.Command{
 	Use:     "stale",
@@ -36,7 +35,6 @@ Exit codes:
 
 func init() {
 	staleCmd.Flags().BoolVar(&staleJSON, "json", false, "Output as JSON")
-	staleCmd.Flags().BoolVarP(&staleQuiet, "quiet", "q", false, "Exit code only (0=stale, 1=fresh)")
 	rootCmd.AddCommand(staleCmd)
 }
 
@@ -53,7 +51,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 	// Find the gastown repo
 	repoRoot, err := version.GetRepoRoot()
 	if err != nil {
-		if staleQuiet {
+		if quietOutput {
 			os.Exit(2)
 		}
 		if staleJSON {
@@ -67,7 +65,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 
 	// Handle errors
 	if info.Error != nil {
-		if staleQuiet {
+		if quietOutput {
 			os.Exit(2)
 		}
 		if staleJSON {
@@ -77,7 +75,7 @@ func runStale(cmd *cobra.Command, args []string) error {
 	}
 
 	// Quiet mode: just exit with appropriate code
-	if staleQuiet {
+	if quietOutput {
 		if info.IsStale {
 			os.Exit(0)
 		}
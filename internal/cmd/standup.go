@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Standup command flags
+var standupSince time.Duration
+
+var standupCmd = &cobra.Command{
+	Use:     "standup [rig...]",
+	GroupID: GroupDiag,
+	Short:   "Summarize the last day's work per rig as markdown",
+	Long: `Compile what happened recently in each rig, as markdown suitable for
+pasting into Slack or for the Mayor to read at the start of a session.
+
+For each rig (all rigs unless some are named):
+  Completed         Issues closed in the window
+  Merged / Failed   MRs that landed, and MRs that failed or were rejected
+  Escalations       Open escalations raised from the rig
+  Idle polecats     Polecats with nothing on their hook
+  Queue             Merge queue depth at the start of the window, now, and
+                    its peak in between, with how many MRs were submitted
+
+Open escalations not raised from a rig are listed under Town.
+
+With -o csv or -o tsv, each rig is a row of counts, for spreadsheets.
+
+Examples:
+  gt standup                  # Last 24h, every rig
+  gt standup greenplace       # One rig
+  gt standup --since 72h      # Over a long weekend
+  gt standup -o json
+  gt standup -o csv > standup.csv`,
+	RunE: runStandup,
+}
+
+func init() {
+	standupCmd.Flags().DurationVar(&standupSince, "since", 24*time.Hour, "How far back to look")
+
+	rootCmd.AddCommand(standupCmd)
+}
+
+// StandupOutput is the structured output for gt standup.
+type StandupOutput struct {
+	Since       time.Time     `json:"since"`
+	Until       time.Time     `json:"until"`
+	Rigs        []StandupRig  `json:"rigs"`
+	Escalations []StandupItem `json:"escalations"` // Open escalations not raised from a rig
+}
+
+// StandupRig is one rig's section of the standup.
+type StandupRig struct {
+	Name         string            `json:"name"`
+	Completed    []StandupItem     `json:"completed"`
+	Merged       []StandupItem     `json:"merged"`
+	Failed       []StandupItem     `json:"failed"`
+	Escalations  []StandupItem     `json:"escalations"`
+	IdlePolecats []string          `json:"idle_polecats"`
+	Queue        StandupQueueTrend `json:"queue"`
+}
+
+// StandupRow is a rig's line in gt standup's CSV/TSV output.
+type StandupRow struct {
+	Rig            string `json:"rig"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	Completed      int    `json:"completed"`
+	Merged         int    `json:"merged"`
+	Failed         int    `json:"failed"`
+	Escalations    int    `json:"escalations"`
+	IdlePolecats   int    `json:"idle_polecats"`
+	QueueStart     int    `json:"queue_start"`
+	QueueNow       int    `json:"queue_now"`
+	QueuePeak      int    `json:"queue_peak"`
+	QueueSubmitted int    `json:"queue_submitted"`
+}
+
+// StandupItem is an issue, MR, or escalation in the standup.
+type StandupItem struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"` // Assignee, worker, or severity
+}
+
+// StandupQueueTrend is how a rig's merge queue depth moved over the window.
+type StandupQueueTrend struct {
+	Start     int `json:"start"`
+	Now       int `json:"now"`
+	Peak      int `json:"peak"`
+	Submitted int `json:"submitted"`
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	if standupSince <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+
+	var rigs []*rig.Rig
+	var townRoot string
+	if len(args) == 0 {
+		var err error
+		rigs, townRoot, err = getAllRigs()
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, name := range args {
+			root, r, err := getRig(name)
+			if err != nil {
+				return err
+			}
+			townRoot = root
+			rigs = append(rigs, r)
+		}
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	until := time.Now()
+	since := until.Add(-standupSince)
+	out := StandupOutput{Since: since, Until: until, Escalations: []StandupItem{}}
+
+	t := tmux.NewTmux()
+	results := rig.ForEach(rigs, func(r *rig.Rig) standupRigResult {
+		return collectStandupRig(r, t, since, until)
+	})
+	for i, res := range results {
+		for _, err := range res.errs {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v\n", rigs[i].Name, err)
+		}
+		out.Rigs = append(out.Rigs, res.rig)
+	}
+	if out.Rigs == nil {
+		out.Rigs = []StandupRig{}
+	}
+
+	escalations, err := beads.New(beads.ResolveBeadsDir(townRoot)).ListEscalations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: listing escalations: %v\n", err)
+	}
+	assignStandupEscalations(&out, escalations)
+
+	if tabularOutput() {
+		return renderStructured(standupRows(out))
+	}
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	fmt.Print(formatStandup(out))
+	return nil
+}
+
+// standupRigResult is one rig's section plus any queries that failed.
+type standupRigResult struct {
+	rig  StandupRig
+	errs []error
+}
+
+// collectStandupRig gathers one rig's section. Failures are returned
+// alongside whatever could be collected, so one bad query doesn't blank
+// the whole rig.
+func collectStandupRig(r *rig.Rig, t *tmux.Tmux, since, until time.Time) (res standupRigResult) {
+	res.rig = StandupRig{
+		Name:         r.Name,
+		Completed:    []StandupItem{},
+		Merged:       []StandupItem{},
+		Failed:       []StandupItem{},
+		Escalations:  []StandupItem{},
+		IdlePolecats: []string{},
+	}
+	bd := beads.New(r.BeadsPath())
+
+	if closed, err := bd.List(beads.ListOptions{Status: "closed", Priority: -1}); err != nil {
+		res.errs = append(res.errs, fmt.Errorf("listing closed issues: %w", err))
+	} else {
+		res.rig.Completed = standupCompleted(closed, since)
+	}
+
+	if mrs, err := bd.List(beads.ListOptions{Type: "merge-request", Status: "all", Priority: -1}); err != nil {
+		res.errs = append(res.errs, fmt.Errorf("listing merge requests: %w", err))
+	} else {
+		res.rig.Merged, res.rig.Failed = standupMROutcomes(mrs, since)
+		res.rig.Queue = standupQueueTrend(mrs, since, until)
+	}
+
+	polecats, err := polecat.NewManager(r, git.NewGit(r.Path), t).List()
+	if err != nil {
+		res.errs = append(res.errs, fmt.Errorf("listing polecats: %w", err))
+	}
+	for _, p := range polecats {
+		if p.State == polecat.StateDone {
+			res.rig.IdlePolecats = append(res.rig.IdlePolecats, p.Name)
+		}
+	}
+	return res
+}
+
+// standupTime parses a bead timestamp; ok is false if it is unset or bad.
+func standupTime(ts string) (time.Time, bool) {
+	if ts == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	return t, err == nil
+}
+
+// closedSince reports whether issue was closed after since.
+func closedSince(issue *beads.Issue, since time.Time) bool {
+	closed, ok := standupTime(issue.ClosedAt)
+	return ok && closed.After(since)
+}
+
+// standupCompleted returns the work issues (not MRs, mail, agents, or other
+// gt: bookkeeping beads) closed after since, oldest first.
+func standupCompleted(issues []*beads.Issue, since time.Time) []StandupItem {
+	var done []*beads.Issue
+	for _, issue := range issues {
+		if !closedSince(issue, since) || isBookkeepingBead(issue) {
+			continue
+		}
+		done = append(done, issue)
+	}
+	sort.SliceStable(done, func(i, j int) bool { return done[i].ClosedAt < done[j].ClosedAt })
+
+	items := []StandupItem{}
+	for _, issue := range done {
+		items = append(items, StandupItem{ID: issue.ID, Title: issue.Title, Detail: issue.Assignee})
+	}
+	return items
+}
+
+// isBookkeepingBead reports whether a bead records Gas Town's own
+// machinery (MRs, mail, agents, molecules) rather than work.
+func isBookkeepingBead(issue *beads.Issue) bool {
+	switch issue.Type {
+	case "merge-request", "message", "agent", "molecule", "event", "convoy":
+		return true
+	}
+	for _, label := range issue.Labels {
+		if strings.HasPrefix(label, "gt:") {
+			return true
+		}
+	}
+	return false
+}
+
+// standupMROutcomes splits the MRs closed after since into merged and
+// failed (failed or rejected), oldest first. Cancelled and stale MRs are
+// left out.
+func standupMROutcomes(mrs []*beads.Issue, since time.Time) (merged, failed []StandupItem) {
+	var closed []*beads.Issue
+	for _, mr := range mrs {
+		if closedSince(mr, since) {
+			closed = append(closed, mr)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return closed[i].ClosedAt < closed[j].ClosedAt })
+
+	merged, failed = []StandupItem{}, []StandupItem{}
+	for _, mr := range closed {
+		item := StandupItem{ID: mr.ID, Title: mr.Title}
+		if fields := beads.ParseMRFields(mr); fields != nil {
+			item.Detail = fields.Worker
+		}
+		switch state := refinery.StateOf(mr); state {
+		case refinery.StateMerged:
+			merged = append(merged, item)
+		case refinery.StateFailed, refinery.StateRejected:
+			if item.Detail != "" {
+				item.Detail += ", "
+			}
+			item.Detail += string(state)
+			failed = append(failed, item)
+		}
+	}
+	return merged, failed
+}
+
+// standupQueueTrend replays MR creation and closing to find the queue depth
+// at since, at until, and at its highest in between.
+func standupQueueTrend(mrs []*beads.Issue, since, until time.Time) StandupQueueTrend {
+	type change struct {
+		at    time.Time
+		delta int
+	}
+	var trend StandupQueueTrend
+	var changes []change
+	for _, mr := range mrs {
+		created, ok := standupTime(mr.CreatedAt)
+		if !ok || created.After(until) {
+			continue
+		}
+		closed, isClosed := standupTime(mr.ClosedAt)
+		if isClosed && !closed.After(since) {
+			continue // Closed before the window opened
+		}
+
+		if created.After(since) {
+			trend.Submitted++
+			changes = append(changes, change{created, 1})
+		} else {
+			trend.Start++
+		}
+		if isClosed && !closed.After(until) {
+			changes = append(changes, change{closed, -1})
+		}
+	}
+	// Close before open at the same instant, so a peak isn't overstated
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].at.Equal(changes[j].at) {
+			return changes[i].delta < changes[j].delta
+		}
+		return changes[i].at.Before(changes[j].at)
+	})
+
+	depth := trend.Start
+	trend.Peak = depth
+	for _, c := range changes {
+		depth += c.delta
+		if depth > trend.Peak {
+			trend.Peak = depth
+		}
+	}
+	trend.Now = depth
+	return trend
+}
+
+// assignStandupEscalations files each open escalation under the rig it was
+// raised from (by its escalated_by address), or under the town.
+func assignStandupEscalations(out *StandupOutput, escalations []*beads.Issue) {
+	byRig := make(map[string]int)
+	for i, r := range out.Rigs {
+		byRig[r.Name] = i
+	}
+	for _, issue := range escalations {
+		fields := beads.ParseEscalationFields(issue.Description)
+		var detail []string
+		if fields.Severity != "" {
+			detail = append(detail, fields.Severity)
+		}
+		if fields.EscalatedBy != "" {
+			detail = append(detail, "from "+fields.EscalatedBy)
+		}
+		item := StandupItem{ID: issue.ID, Title: issue.Title, Detail: strings.Join(detail, ", ")}
+
+		rigName, _, _ := strings.Cut(fields.EscalatedBy, "/")
+		if i, ok := byRig[rigName]; ok {
+			out.Rigs[i].Escalations = append(out.Rigs[i].Escalations, item)
+		} else {
+			out.Escalations = append(out.Escalations, item)
+		}
+	}
+}
+
+// formatStandup renders the standup as markdown.
+func formatStandup(out StandupOutput) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Standup: %s (last %s)\n", out.Until.Local().Format("Mon Jan 2 15:04"), formatStandupWindow(out.Until.Sub(out.Since)))
+
+	for _, r := range out.Rigs {
+		fmt.Fprintf(&sb, "\n## %s\n\n", r.Name)
+		writeStandupSection(&sb, "Completed", r.Completed)
+		writeStandupSection(&sb, "Merged", r.Merged)
+		writeStandupSection(&sb, "Failed", r.Failed)
+		writeStandupSection(&sb, "Open escalations", r.Escalations)
+
+		idle := "none"
+		if len(r.IdlePolecats) > 0 {
+			idle = strings.Join(r.IdlePolecats, ", ")
+		}
+		fmt.Fprintf(&sb, "**Idle polecats (%d):** %s\n", len(r.IdlePolecats), idle)
+
+		q := r.Queue
+		fmt.Fprintf(&sb, "**Queue depth:** %s %d → %d (peak %d, %d submitted)\n",
+			standupTrendArrow(q.Start, q.Now), q.Start, q.Now, q.Peak, q.Submitted)
+	}
+
+	if len(out.Escalations) > 0 {
+		sb.WriteString("\n## Town\n\n")
+		writeStandupSection(&sb, "Open escalations", out.Escalations)
+	}
+	return sb.String()
+}
+
+// writeStandupSection writes a bold count heading and a bullet per item;
+// empty sections get just the heading.
+func writeStandupSection(sb *strings.Builder, title string, items []StandupItem) {
+	if len(items) == 0 {
+		fmt.Fprintf(sb, "**%s (0):** none\n", title)
+		return
+	}
+	fmt.Fprintf(sb, "**%s (%d):**\n", title, len(items))
+	for _, item := range items {
+		fmt.Fprintf(sb, "- `%s` %s", item.ID, item.Title)
+		if item.Detail != "" {
+			fmt.Fprintf(sb, " (%s)", item.Detail)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// standupTrendArrow shows which way the queue moved.
+func standupTrendArrow(start, now int) string {
+	switch {
+	case now > start:
+		return "↑"
+	case now < start:
+		return "↓"
+	default:
+		return "="
+	}
+}
+
+// formatStandupWindow renders the window compactly: "24h", "3d", "90m".
+func formatStandupWindow(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+// standupRows turns a standup into a row of counts per rig.
+func standupRows(out StandupOutput) []StandupRow {
+	rows := []StandupRow{}
+	for _, r := range out.Rigs {
+		rows = append(rows, StandupRow{
+			Rig:            r.Name,
+			Since:          out.Since.UTC().Format(time.RFC3339),
+			Until:          out.Until.UTC().Format(time.RFC3339),
+			Completed:      len(r.Completed),
+			Merged:         len(r.Merged),
+			Failed:         len(r.Failed),
+			Escalations:    len(r.Escalations),
+			IdlePolecats:   len(r.IdlePolecats),
+			QueueStart:     r.Queue.Start,
+			QueueNow:       r.Queue.Now,
+			QueuePeak:      r.Queue.Peak,
+			QueueSubmitted: r.Queue.Submitted,
+		})
+	}
+	return rows
+}
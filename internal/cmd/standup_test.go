@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func standupMR(id string, created, closed time.Time, fields *beads.MRFields) *beads.Issue {
+	issue := &beads.Issue{ID: id, Title: "Merge " + id, Type: "merge-request", Status: "open",
+		CreatedAt: created.Format(time.RFC3339)}
+	if !closed.IsZero() {
+		issue.Status = "closed"
+		issue.ClosedAt = closed.Format(time.RFC3339)
+	}
+	if fields != nil {
+		issue.Description = beads.FormatMRFields(fields)
+	}
+	return issue
+}
+
+func TestStandupQueueTrend(t *testing.T) {
+	until := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	since := until.Add(-24 * time.Hour)
+	at := func(h int) time.Time { return since.Add(time.Duration(h) * time.Hour) }
+
+	mrs := []*beads.Issue{
+		standupMR("gp-mr-old", at(-10), at(-5), nil),   // closed before the window: ignored
+		standupMR("gp-mr-1", at(-2), at(3), nil),       // queued at start, merged
+		standupMR("gp-mr-2", at(-1), time.Time{}, nil), // queued at start, still open
+		standupMR("gp-mr-3", at(1), at(4), nil),        // submitted and merged
+		standupMR("gp-mr-4", at(2), time.Time{}, nil),  // submitted, still open
+		standupMR("gp-mr-5", at(3), time.Time{}, nil),  // submitted as gp-mr-1 closed
+	}
+
+	got := standupQueueTrend(mrs, since, until)
+	want := StandupQueueTrend{Start: 2, Now: 3, Peak: 4, Submitted: 3}
+	if got != want {
+		t.Errorf("standupQueueTrend = %+v, want %+v", got, want)
+	}
+}
+
+func TestStandupMROutcomes(t *testing.T) {
+	since := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	in := since.Add(time.Hour)
+
+	mrs := []*beads.Issue{
+		standupMR("gp-mr-1", since.Add(-time.Hour), in.Add(time.Hour), &beads.MRFields{Worker: "toast", State: "merged"}),
+		standupMR("gp-mr-2", since.Add(-time.Hour), in, &beads.MRFields{Worker: "nux", State: "failed"}),
+		standupMR("gp-mr-3", since.Add(-time.Hour), in, &beads.MRFields{State: "cancelled"}),
+		standupMR("gp-mr-4", since.Add(-48*time.Hour), since.Add(-time.Hour), &beads.MRFields{State: "merged"}),
+		standupMR("gp-mr-5", in, time.Time{}, &beads.MRFields{State: "queued"}),
+	}
+
+	merged, failed := standupMROutcomes(mrs, since)
+	if len(merged) != 1 || merged[0].ID != "gp-mr-1" || merged[0].Detail != "toast" {
+		t.Errorf("merged = %+v, want just gp-mr-1 by toast", merged)
+	}
+	if len(failed) != 1 || failed[0].ID != "gp-mr-2" || failed[0].Detail != "nux, failed" {
+		t.Errorf("failed = %+v, want just gp-mr-2 by nux", failed)
+	}
+}
+
+func TestStandupCompleted(t *testing.T) {
+	since := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	closed := func(d time.Duration) string { return since.Add(d).Format(time.RFC3339) }
+
+	issues := []*beads.Issue{
+		{ID: "gp-2", Title: "Second", Type: "bug", ClosedAt: closed(2 * time.Hour)},
+		{ID: "gp-1", Title: "First", Type: "task", ClosedAt: closed(time.Hour), Assignee: "greenplace/polecats/toast"},
+		{ID: "gp-old", Title: "Old", Type: "task", ClosedAt: closed(-time.Hour)},
+		{ID: "gp-mr-1", Title: "Merge", Type: "merge-request", ClosedAt: closed(time.Hour)},
+		{ID: "gp-msg", Title: "Mail", Type: "task", Labels: []string{"gt:message"}, ClosedAt: closed(time.Hour)},
+	}
+
+	got := standupCompleted(issues, since)
+	if len(got) != 2 || got[0].ID != "gp-1" || got[1].ID != "gp-2" {
+		t.Fatalf("standupCompleted = %+v, want gp-1 then gp-2", got)
+	}
+	if got[0].Detail != "greenplace/polecats/toast" {
+		t.Errorf("Detail = %q, want the assignee", got[0].Detail)
+	}
+}
+
+func TestAssignStandupEscalations(t *testing.T) {
+	out := StandupOutput{Rigs: []StandupRig{{Name: "greenplace"}}}
+	escalations := []*beads.Issue{
+		{ID: "hq-1", Title: "Build broken", Description: beads.FormatEscalationDescription("Build broken",
+			&beads.EscalationFields{Severity: "high", EscalatedBy: "greenplace/witness"})},
+		{ID: "hq-2", Title: "Dolt down", Description: beads.FormatEscalationDescription("Dolt down",
+			&beads.EscalationFields{Severity: "critical", EscalatedBy: "deacon"})},
+	}
+
+	assignStandupEscalations(&out, escalations)
+	if got := out.Rigs[0].Escalations; len(got) != 1 || got[0].ID != "hq-1" || got[0].Detail != "high, from greenplace/witness" {
+		t.Errorf("rig escalations = %+v, want hq-1", got)
+	}
+	if len(out.Escalations) != 1 || out.Escalations[0].ID != "hq-2" {
+		t.Errorf("town escalations = %+v, want hq-2", out.Escalations)
+	}
+}
+
+func TestFormatStandup(t *testing.T) {
+	until := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	out := StandupOutput{
+		Since: until.Add(-24 * time.Hour),
+		Until: until,
+		Rigs: []StandupRig{{
+			Name:         "greenplace",
+			Completed:    []StandupItem{{ID: "gp-1", Title: "Fix login", Detail: "toast"}},
+			IdlePolecats: []string{"nux"},
+			Queue:        StandupQueueTrend{Start: 2, Now: 5, Peak: 6, Submitted: 4},
+		}},
+		Escalations: []StandupItem{{ID: "hq-2", Title: "Dolt down", Detail: "critical"}},
+	}
+
+	md := formatStandup(out)
+	for _, want := range []string{
+		"# Standup: Mon Mar 2 09:00 (last 24h)",
+		"## greenplace",
+		"**Completed (1):**\n- `gp-1` Fix login (toast)\n",
+		"**Merged (0):** none",
+		"**Idle polecats (1):** nux",
+		"**Queue depth:** ↑ 2 → 5 (peak 6, 4 submitted)",
+		"## Town\n\n**Open escalations (1):**\n- `hq-2` Dolt down (critical)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("standup missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestFormatStandupWindow(t *testing.T) {
+	tests := map[time.Duration]string{
+		24 * time.Hour:   "24h",
+		72 * time.Hour:   "3d",
+		90 * time.Minute: "90m",
+		36 * time.Hour:   "36h",
+	}
+	for d, want := range tests {
+		if got := formatStandupWindow(d); got != want {
+			t.Errorf("formatStandupWindow(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestStandupRows(t *testing.T) {
+	until := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	out := StandupOutput{
+		Since: until.Add(-24 * time.Hour),
+		Until: until,
+		Rigs: []StandupRig{{
+			Name:         "greenplace",
+			Merged:       []StandupItem{{ID: "gp-mr-1"}, {ID: "gp-mr-2"}},
+			IdlePolecats: []string{"nux"},
+			Queue:        StandupQueueTrend{Start: 2, Now: 5, Peak: 6, Submitted: 4},
+		}},
+	}
+	rows := standupRows(out)
+	want := StandupRow{Rig: "greenplace", Since: "2026-03-01T09:00:00Z", Until: "2026-03-02T09:00:00Z", Merged: 2, IdlePolecats: 1, QueueStart: 2, QueueNow: 5, QueuePeak: 6, QueueSubmitted: 4}
+	if len(rows) != 1 || rows[0] != want {
+		t.Errorf("standupRows = %+v, want %+v", rows, want)
+	}
+}
@@ -484,7 +484,7 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 
 	// Confirmation prompt
 	if !shutdownYes && !shutdownForce {
-		fmt.Printf("Proceed with shutdown? [y/N] ")
+		promptf("Proceed with shutdown? [y/N] ")
 		reader := bufio.NewReader(os.Stdin)
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
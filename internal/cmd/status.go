@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -15,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crash"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -62,6 +62,8 @@ type TownStatus struct {
 	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
 	Rigs     []RigStatus    `json:"rigs"`
 	Summary  StatusSum      `json:"summary"`
+
+	PendingCrashes int `json:"pending_crashes"` // Unreviewed crash report bundles
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -138,8 +140,8 @@ func runStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runStatusWatch(cmd *cobra.Command, args []string) error {
-	if statusJSON {
-		return fmt.Errorf("--json and --watch cannot be used together")
+	if structuredOutput(statusJSON) {
+		return fmt.Errorf("--json/--output and --watch cannot be used together")
 	}
 	if statusInterval <= 0 {
 		return fmt.Errorf("interval must be positive, got %d", statusInterval)
@@ -184,7 +186,7 @@ func runStatusWatch(cmd *cobra.Command, args []string) error {
 
 func runStatusOnce(_ *cobra.Command, _ []string) error {
 	// Find town root
-	townRoot, err := workspace.FindFromCwdOrError()
+	townRoot, err := workspace.FindFromCwdOrContext()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
@@ -423,19 +425,17 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	}
 	status.Summary.RigCount = len(rigs)
 
+	if pending, err := crash.Pending(townRoot); err == nil {
+		status.PendingCrashes = len(pending)
+	}
+
 	// Output
-	if statusJSON {
-		return outputStatusJSON(status)
+	if structuredOutput(statusJSON) {
+		return renderStructured(status)
 	}
 	return outputStatusText(status)
 }
 
-func outputStatusJSON(status TownStatus) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(status)
-}
-
 func outputStatusText(status TownStatus) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), status.Name)
@@ -456,6 +456,11 @@ func outputStatusText(status TownStatus) error {
 		fmt.Println()
 	}
 
+	if status.PendingCrashes > 0 {
+		fmt.Printf("💥 %s %d pending crash report(s) %s\n\n", style.Warning.Render("Crashes:"),
+			status.PendingCrashes, style.Dim.Render("(gt crashes list)"))
+	}
+
 	// Role icons - uses centralized emojis from constants package
 	roleIcons := map[string]string{
 		constants.RoleMayor:    constants.EmojiMayor,
@@ -1163,14 +1168,14 @@ func getMQSummary(r *rig.Rig) *MQSummary {
 		Status:   "open",
 		Priority: -1, // No priority filter
 	}
-	openMRs, err := b.List(opts)
+	openMRs, err := b.ListIndexed(opts)
 	if err != nil {
 		return nil
 	}
 
 	// Query for in-progress merge-requests
 	opts.Status = "in_progress"
-	inProgressMRs, err := b.List(opts)
+	inProgressMRs, err := b.ListIndexed(opts)
 	if err != nil {
 		return nil
 	}
@@ -558,13 +558,16 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no rigs found")
 	}
 
-	// Find which rig has this swarm
-	var foundRig *rig.Rig
-	for _, r := range rigs {
+	// Find which rig has this swarm, checking all rigs in parallel
+	found := rig.ForEach(rigs, func(r *rig.Rig) bool {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		return checkCmd.Run() == nil
+	})
+	var foundRig *rig.Rig
+	for i, r := range rigs {
+		if found[i] {
 			foundRig = r
 			break
 		}
@@ -629,13 +632,21 @@ func runSwarmList(cmd *cobra.Command, args []string) error {
 	}
 	var allSwarms []swarmListEntry
 
-	for _, r := range rigs {
+	outputs := rig.ForEach(rigs, func(r *rig.Rig) *bytes.Buffer {
 		bdCmd := exec.Command("bd", bdArgs...)
 		bdCmd.Dir = r.BeadsPath() // Use BeadsPath() for git-synced beads
 		var stdout bytes.Buffer
 		bdCmd.Stdout = &stdout
 
 		if err := bdCmd.Run(); err != nil {
+			return nil
+		}
+		return &stdout
+	})
+
+	for i, r := range rigs {
+		stdout := outputs[i]
+		if stdout == nil {
 			continue
 		}
 
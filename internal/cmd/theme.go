@@ -367,29 +367,14 @@ func saveRigTheme(rigName, themeName string) error {
 
 	settingsPath := filepath.Join(townRoot, rigName, "settings", "config.json")
 
-	// Load existing settings or create new
-	var settings *config.RigSettings
-	settings, err = config.LoadRigSettings(settingsPath)
-	if err != nil {
-		// Create new settings if not found
-		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
-			settings = config.NewRigSettings()
-		} else {
-			return fmt.Errorf("loading settings: %w", err)
+	// Set theme, creating the settings if needed
+	_, err = config.RigSettingsStore(settingsPath).Update(func(settings *config.RigSettings) error {
+		settings.Theme = &config.ThemeConfig{
+			Name: themeName,
 		}
-	}
-
-	// Set theme
-	settings.Theme = &config.ThemeConfig{
-		Name: themeName,
-	}
-
-	// Save
-	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
-		return fmt.Errorf("saving settings: %w", err)
-	}
-
-	return nil
+		return nil
+	})
+	return err
 }
 
 func runThemeCLI(cmd *cobra.Command, args []string) error {
@@ -448,17 +433,12 @@ func runThemeCLI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid CLI theme '%s' (valid: auto, dark, light)", mode)
 	}
 
-	// Load existing settings
-	settings, err := config.LoadOrCreateTownSettings(settingsPath)
-	if err != nil {
-		return fmt.Errorf("loading settings: %w", err)
-	}
-
 	// Update CLITheme
-	settings.CLITheme = mode
-
-	// Save
-	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+	_, err = config.TownSettingsStore(settingsPath).Update(func(settings *config.TownSettings) error {
+		settings.CLITheme = mode
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("saving settings: %w", err)
 	}
 
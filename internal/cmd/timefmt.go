@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Time formats accepted by the global --time-format flag.
+const (
+	TimeRelative = "relative" // "3h", "2 days ago" (default)
+	TimeISO      = "iso"      // RFC 3339 in UTC
+	TimeLocal    = "local"    // "2006-01-02 15:04 MST" in the local zone
+)
+
+// EnvTimeFormat sets the time format when --time-format isn't given,
+// overriding the town's time_format setting.
+const EnvTimeFormat = "GT_TIME_FORMAT"
+
+// localTimeLayout is how TimeLocal renders a timestamp.
+const localTimeLayout = "2006-01-02 15:04 MST"
+
+var (
+	// timeFormatFlag is the value of the global --time-format flag.
+	timeFormatFlag string
+
+	// timeFormat is the effective time format for human-readable output,
+	// resolved by applyTimeFormat.
+	timeFormat = TimeRelative
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&timeFormatFlag, "time-format", "",
+		"How to show times: relative, iso, local (env: GT_TIME_FORMAT; default: town setting, else relative)")
+}
+
+// applyTimeFormat resolves the time format from, in order, --time-format,
+// GT_TIME_FORMAT and the town's time_format setting.
+func applyTimeFormat() error {
+	format, source := timeFormatFlag, "--time-format"
+	if format == "" {
+		format, source = os.Getenv(EnvTimeFormat), EnvTimeFormat
+	}
+	if format == "" {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+				format, source = settings.TimeFormat, "time_format setting"
+			}
+		}
+	}
+	switch strings.ToLower(format) {
+	case "":
+		timeFormat = TimeRelative
+	case TimeRelative, TimeISO, TimeLocal:
+		timeFormat = strings.ToLower(format)
+	default:
+		return fmt.Errorf("invalid %s %q (valid: relative, iso, local)", source, format)
+	}
+	return nil
+}
+
+// parseTimestamp parses a timestamp as beads and gt write them. Timestamps
+// without a zone are taken to be UTC.
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// formatAbsoluteTime renders t in the current absolute time format: local
+// for TimeLocal, RFC 3339 UTC otherwise.
+func formatAbsoluteTime(t time.Time) string {
+	if timeFormat == TimeLocal {
+		return t.Local().Format(localTimeLayout)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// utcTimestamp rewrites a timestamp as RFC 3339 in UTC, so structured
+// output uses one zone whatever the writer's was. Unparseable values are
+// returned unchanged.
+func utcTimestamp(s string) string {
+	t, ok := parseTimestamp(s)
+	if !ok {
+		return s
+	}
+	return t.UTC().Format(time.RFC3339)
+}
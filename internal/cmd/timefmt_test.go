@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyTimeFormat(t *testing.T) {
+	defer func() { timeFormatFlag, timeFormat = "", TimeRelative }()
+	t.Setenv(EnvTimeFormat, "local")
+
+	timeFormatFlag = "ISO"
+	if err := applyTimeFormat(); err != nil || timeFormat != TimeISO {
+		t.Errorf("flag: timeFormat = %q, %v; want iso", timeFormat, err)
+	}
+
+	timeFormatFlag = ""
+	if err := applyTimeFormat(); err != nil || timeFormat != TimeLocal {
+		t.Errorf("env: timeFormat = %q, %v; want local", timeFormat, err)
+	}
+
+	timeFormatFlag = "epoch"
+	if err := applyTimeFormat(); err == nil {
+		t.Error("invalid format should be an error")
+	}
+}
+
+func TestUTCTimestamp(t *testing.T) {
+	for in, want := range map[string]string{
+		"2025-01-01T12:00:00-08:00": "2025-01-01T20:00:00Z",
+		"2025-01-01T12:00:00Z":      "2025-01-01T12:00:00Z",
+		"2025-01-01 12:00:00":       "2025-01-01T12:00:00Z",
+		"":                          "",
+		"not-a-date":                "not-a-date",
+	} {
+		if got := utcTimestamp(in); got != want {
+			t.Errorf("utcTimestamp(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatMRAgeAbsolute(t *testing.T) {
+	defer func() { timeFormat = TimeRelative }()
+	created := "2025-01-01T12:00:00-08:00"
+
+	timeFormat = TimeISO
+	if got := formatMRAge(created); got != "2025-01-01T20:00:00Z" {
+		t.Errorf("iso: formatMRAge = %q", got)
+	}
+
+	timeFormat = TimeLocal
+	want := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC).Local().Format(localTimeLayout)
+	if got := formatMRAge(created); got != want {
+		t.Errorf("local: formatMRAge = %q, want %q", got, want)
+	}
+
+	timeFormat = TimeRelative
+	if got := formatTimestamp(created); !strings.HasPrefix(got, created+" ") {
+		t.Errorf("relative: formatTimestamp = %q, want the timestamp and its age", got)
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// townContextFlag is the value of the global --town flag.
+var townContextFlag string
+
+var townListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered towns",
+	Long: `List the towns registered on this machine. The current context is
+marked with *.
+
+Examples:
+  gt town list
+  gt town list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runTownList,
+}
+
+var townUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the current town context",
+	Long: `Make a registered town the current context. Run outside any town,
+gt status, gt rig list and commands that take a rig name act on the current
+context. Other commands (hooks, agent commands) still need a town directory
+or --town.
+
+Examples:
+  gt town use personal
+  gt status              # Status of the personal town, from any directory`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownUse,
+}
+
+var townAddCmd = &cobra.Command{
+	Use:   "add <name> [path]",
+	Short: "Register a town under a name",
+	Long: `Register a town root under a name. Without a path, registers the town
+containing the current directory. The first town registered becomes the
+current context.
+
+Examples:
+  gt town add work ~/gt
+  gt town add personal ~/src/home-town`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTownAdd,
+}
+
+var townRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a town",
+	Long: `Remove a town from the registry. The town itself is not touched.
+
+Examples:
+  gt town remove personal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownRemove,
+}
+
+var townCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show which town commands act on",
+	Long: `Show the town commands resolve to from here, and why: --town or
+GT_TOWN_CONTEXT, the current directory, or the current context.
+
+Examples:
+  gt town current`,
+	Args: cobra.NoArgs,
+	RunE: runTownCurrent,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&townContextFlag, "town", "",
+		"Act on a registered town by name, regardless of the current directory (env: GT_TOWN_CONTEXT)")
+
+	townCmd.AddCommand(townListCmd)
+	townCmd.AddCommand(townUseCmd)
+	townCmd.AddCommand(townAddCmd)
+	townCmd.AddCommand(townRemoveCmd)
+	townCmd.AddCommand(townCurrentCmd)
+}
+
+// applyTownContextFlag exports --town so every town lookup sees it.
+func applyTownContextFlag() error {
+	if townContextFlag == "" {
+		return nil
+	}
+	return os.Setenv(workspace.EnvTownContext, townContextFlag)
+}
+
+// TownListItem is one row of gt town list structured output.
+type TownListItem struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Current bool   `json:"current"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+func runTownList(cmd *cobra.Command, args []string) error {
+	reg, err := workspace.LoadRegistry()
+	if err != nil {
+		return err
+	}
+
+	items := []TownListItem{}
+	for _, name := range reg.Names() {
+		path := reg.Towns[name].Path
+		ok, _ := workspace.IsWorkspace(path)
+		items = append(items, TownListItem{Name: name, Path: path, Current: name == reg.Current, Missing: !ok})
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println(style.Dim.Render("No towns registered. Register one with: gt town add <name> [path]"))
+		return nil
+	}
+	for _, t := range items {
+		marker := " "
+		if t.Current {
+			marker = style.Success.Render("*")
+		}
+		line := fmt.Sprintf("%s %-16s %s", marker, t.Name, style.Dim.Render(t.Path))
+		if t.Missing {
+			line += " " + style.Warning.Render("(missing)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runTownUse(cmd *cobra.Command, args []string) error {
+	reg, err := workspace.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Use(args[0]); err != nil {
+		return fmt.Errorf("%w (see 'gt town list')", err)
+	}
+	if err := reg.Save(); err != nil {
+		return fmt.Errorf("saving town registry: %w", err)
+	}
+	fmt.Printf("%s Current town: %s %s\n", style.Success.Render("✓"), args[0],
+		style.Dim.Render(reg.Towns[args[0]].Path))
+	return nil
+}
+
+func runTownAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	var path string
+	if len(args) > 1 {
+		path = args[1]
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		path, err = workspace.Find(cwd)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return fmt.Errorf("not in a Gas Town workspace; pass the town path")
+		}
+	}
+
+	reg, err := workspace.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Add(name, path); err != nil {
+		return err
+	}
+	if reg.Current == "" {
+		reg.Current = name
+	}
+	if err := reg.Save(); err != nil {
+		return fmt.Errorf("saving town registry: %w", err)
+	}
+
+	fmt.Printf("%s Registered town %s %s\n", style.Success.Render("✓"), name, style.Dim.Render(reg.Towns[name].Path))
+	if reg.Current == name {
+		fmt.Printf("  Current town: %s\n", name)
+	}
+	return nil
+}
+
+func runTownRemove(cmd *cobra.Command, args []string) error {
+	reg, err := workspace.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Remove(args[0]); err != nil {
+		return err
+	}
+	if err := reg.Save(); err != nil {
+		return fmt.Errorf("saving town registry: %w", err)
+	}
+	fmt.Printf("%s Removed town %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runTownCurrent(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrContext()
+	if errors.Is(err, workspace.ErrNotFound) {
+		return fmt.Errorf("no town selected (use 'gt town use <name>' or cd into a town): %w", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	reg, err := workspace.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	name := reg.NameFor(townRoot)
+
+	source := "current context"
+	if os.Getenv(workspace.EnvTownContext) != "" {
+		source = "--town / " + workspace.EnvTownContext
+	} else if cwd, err := os.Getwd(); err == nil {
+		if root, _ := workspace.Find(cwd); root == townRoot {
+			source = "current directory"
+		}
+	}
+
+	if name == "" {
+		name = "(unregistered)"
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(name), style.Dim.Render(townRoot))
+	fmt.Printf("  via %s\n", source)
+	return nil
+}
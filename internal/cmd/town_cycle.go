@@ -43,7 +43,13 @@ func init() {
 var townCmd = &cobra.Command{
 	Use:   "town",
 	Short: "Town-level operations",
-	Long:  `Commands for town-level operations including session cycling.`,
+	Long: `Commands for town-level operations: session cycling and town contexts.
+
+Town contexts let one machine run several towns. Register each with
+'gt town add', pick one with 'gt town use', and gt status, gt rig list and
+commands that take a rig name act on it when run outside any town. Inside a
+town directory that town wins; --town <name> (or GT_TOWN_CONTEXT) overrides
+both for any command.`,
 }
 
 var townNextCmd = &cobra.Command{
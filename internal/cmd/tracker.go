@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracker"
+)
+
+// Tracker command flags
+var (
+	trackerSyncRig    string
+	trackerSyncDryRun bool
+)
+
+var trackerCmd = &cobra.Command{
+	Use:     "tracker",
+	GroupID: GroupWork,
+	Short:   "Mirror rigs' work to external issue trackers",
+	Long: `Mirror a rig's work beads to an external issue tracker (GitHub Issues,
+Jira or Linear), so people who never use gt still see progress.
+
+A rig opts in with a tracker block in its settings/config.json:
+
+  "tracker": {"kind": "github", "repo": "acme/widgets"}
+  "tracker": {"kind": "jira", "url": "https://acme.atlassian.net",
+              "project": "WID", "user": "bot@acme.com"}
+  "tracker": {"kind": "linear", "project": "WID"}
+
+Jira and Linear take an API token from "token", usually "secret:<name>"
+(see gt secret), or else from JIRA_API_TOKEN or LINEAR_API_KEY (or the
+variable named by token_env); GitHub uses gh's login. "events"
+limits what is mirrored (created, in_progress, closed, merged) and
+"labels" limits it to beads with one of those labels.
+
+The daemon's tracker-sync task keeps trackers current; 'gt tracker sync'
+runs it now.
+
+Subcommands:
+  sync    Mirror changes since the last sync`,
+	RunE: requireSubcommand,
+}
+
+var trackerSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror changes since the last sync",
+	Long: `Mirror each rig's work to its tracker: open an item for each new work
+bead, move it to in progress and closed as the bead moves, and comment with
+the commit when an MR for it merges. Beads already closed when a rig first
+syncs aren't mirrored. A change that fails is retried on the next sync.
+
+Examples:
+  gt tracker sync
+  gt tracker sync --rig greenplace --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runTrackerSync,
+}
+
+func init() {
+	trackerSyncCmd.Flags().StringVar(&trackerSyncRig, "rig", "", "Sync one rig only")
+	trackerSyncCmd.Flags().BoolVarP(&trackerSyncDryRun, "dry-run", "n", false, "Show what would be mirrored without changing the tracker")
+
+	trackerCmd.AddCommand(trackerSyncCmd)
+	rootCmd.AddCommand(trackerCmd)
+}
+
+func runTrackerSync(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	if trackerSyncRig != "" {
+		_, r, err := getRig(trackerSyncRig)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	} else {
+		var err error
+		if rigs, _, err = getAllRigs(); err != nil {
+			return err
+		}
+	}
+
+	results := []*tracker.Result{}
+	failed := 0
+	for _, r := range rigs {
+		res, err := tracker.SyncRig(r.Path, trackerSyncDryRun)
+		if err != nil {
+			return fmt.Errorf("syncing %s: %w", r.Name, err)
+		}
+		if res != nil {
+			results = append(results, res)
+			failed += res.Failed
+		}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(results)
+	}
+	if len(results) == 0 {
+		fmt.Printf("%s No rig has a tracker configured\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+	for _, res := range results {
+		printTrackerSync(res)
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printTrackerSync(res *tracker.Result) {
+	verb := "Mirrored"
+	if res.DryRun {
+		verb = "Would mirror"
+	}
+	fmt.Printf("%s %s %d change(s) to its %s tracker\n", style.Bold.Render(res.Rig), verb, len(res.Actions)-res.Failed, res.Kind)
+	for _, a := range res.Actions {
+		line := fmt.Sprintf("  %-11s %s", a.Event, a.Bead)
+		if a.Ref != "" {
+			line += " → " + a.Ref
+		}
+		if a.Detail != "" {
+			line += "  " + style.Dim.Render(a.Detail)
+		}
+		if a.Error != "" {
+			line += "  " + style.Error.Render(a.Error)
+		}
+		fmt.Println(line)
+	}
+}
@@ -72,7 +72,7 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Println()
-		fmt.Print("Continue? [y/N] ")
+		promptf("Continue? [y/N] ")
 
 		reader := bufio.NewReader(os.Stdin)
 		response, _ := reader.ReadString('\n')
@@ -65,12 +65,10 @@ aren't already running.`,
 }
 
 var (
-	upQuiet   bool
 	upRestore bool
 )
 
 func init() {
-	upCmd.Flags().BoolVarP(&upQuiet, "quiet", "q", false, "Only show errors")
 	upCmd.Flags().BoolVar(&upRestore, "restore", false, "Also restore crew (from settings) and polecats (from hooks)")
 	rootCmd.AddCommand(upCmd)
 }
@@ -228,14 +226,13 @@ func runUp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printStatus reports a service's startup. Failures go to stderr, so
+// --quiet still shows them.
 func printStatus(name string, ok bool, detail string) {
-	if upQuiet && ok {
-		return
-	}
 	if ok {
 		fmt.Printf("%s %s: %s\n", style.SuccessPrefix, name, style.Dim.Render(detail))
 	} else {
-		fmt.Printf("%s %s: %s\n", style.ErrorPrefix, name, detail)
+		fmt.Fprintf(os.Stderr, "%s %s: %s\n", style.ErrorPrefix, name, detail)
 	}
 }
 
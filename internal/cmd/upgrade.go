@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/archive"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/upgrade"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Upgrade command flags
+var (
+	upgradeCheck    bool
+	upgradeNoSelf   bool
+	upgradeNoBackup bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:     "upgrade",
+	GroupID: GroupDiag,
+	Short:   "Update gt and migrate the town's data to the current format",
+	Long: `Update the gt binary to the latest release, then run the data migrations
+the town hasn't had yet.
+
+gt updates itself the way it was installed: 'brew upgrade gastown' for
+Homebrew, 'npm install -g @gastown/gt@latest' for npm, and
+'go install github.com/steveyegge/gastown/cmd/gt@latest' otherwise. The
+new binary then runs the migrations, so they're the ones it ships with.
+
+Migrations bring data written by older versions forward: config schema
+bumps, renamed bead fields, restructured merge request fields. Each runs
+once per town (mayor/migrations.json records them) and changes nothing
+where there's nothing to migrate.
+
+Before migrating, the town's state is backed up as by 'gt export' to
+~/.local/state/gastown/backups/ (restore it with
+'gt import <backup> <town> --force --no-clone'). The backup needs the
+Dolt server stopped ('gt dolt stop'); --no-backup skips it.
+
+With --check, nothing changes: the command reports the latest release and
+the pending migrations, and exits with code 8 if there are either.
+
+Examples:
+  gt upgrade                 # Update gt, then migrate the town
+  gt upgrade --check         # Report what an upgrade would do
+  gt upgrade --no-self       # Only run pending migrations`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeCheck, "check", false, "Report the latest release and pending migrations without changing anything")
+	upgradeCmd.Flags().BoolVar(&upgradeNoSelf, "no-self", false, "Don't update the gt binary; only run migrations")
+	upgradeCmd.Flags().BoolVar(&upgradeNoBackup, "no-backup", false, "Don't back up the town before migrating")
+
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+// UpgradeOutput is the structured output for gt upgrade.
+type UpgradeOutput struct {
+	Version         string            `json:"version"`
+	Latest          string            `json:"latest,omitempty"` // "" if not checked, or the check failed
+	InstallMethod   string            `json:"install_method"`
+	UpdateAvailable bool              `json:"update_available"`
+	Backup          string            `json:"backup,omitempty"`
+	Migrations      []MigrationResult `json:"migrations"`
+}
+
+// MigrationResult is one migration in gt upgrade's output.
+type MigrationResult struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"` // "pending", "applied", or "failed"
+	Changes     []string `json:"changes,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	exe, _ := os.Executable()
+	out := UpgradeOutput{Version: Version, InstallMethod: upgrade.DetectInstallMethod(exe), Migrations: []MigrationResult{}}
+
+	if !upgradeNoSelf {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		latest, err := upgrade.LatestVersion(ctx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Couldn't check for a newer gt: %v\n", style.Warning.Render("⚠"), err)
+		} else {
+			out.Latest = latest
+			out.UpdateAvailable = upgrade.CompareVersions(latest, Version) > 0
+		}
+	}
+
+	// Migrations need a town; updating the binary doesn't
+	townRoot, _ := workspace.FindFromCwd()
+	var pending []upgrade.Migration
+	if townRoot != "" {
+		var err error
+		if pending, err = upgrade.Pending(townRoot); err != nil {
+			return err
+		}
+	}
+	for _, m := range pending {
+		out.Migrations = append(out.Migrations, MigrationResult{ID: m.ID, Description: m.Description, Status: "pending"})
+	}
+
+	if upgradeCheck {
+		if structuredOutput(false) {
+			if err := renderStructured(out); err != nil {
+				return err
+			}
+		} else {
+			printUpgradeCheck(out, townRoot)
+		}
+		if out.UpdateAvailable || len(pending) > 0 {
+			return NewSilentExit(ExitCheckFailed)
+		}
+		return nil
+	}
+
+	if out.UpdateAvailable {
+		if err := selfUpdate(out); err != nil {
+			return err
+		}
+		// The new binary runs the migrations it ships with
+		return rerunUpgrade()
+	}
+
+	if townRoot != "" && len(pending) > 0 {
+		if !upgradeNoBackup {
+			backup, err := backupTown(townRoot)
+			if err != nil {
+				return err
+			}
+			out.Backup = backup
+			if !structuredOutput(false) {
+				fmt.Printf("%s Backed up the town to %s\n", style.Success.Render("✓"), backup)
+			}
+		}
+		if err := runMigrations(townRoot, pending, &out); err != nil {
+			if structuredOutput(false) {
+				_ = renderStructured(out)
+			}
+			return err
+		}
+	}
+
+	if structuredOutput(false) {
+		return renderStructured(out)
+	}
+	switch {
+	case townRoot == "" && out.Latest != "":
+		fmt.Printf("%s gt %s is the latest release\n", style.Success.Render("✓"), Version)
+	case len(pending) == 0 && townRoot != "":
+		fmt.Printf("%s gt %s; town data is current\n", style.Success.Render("✓"), Version)
+	case len(pending) > 0:
+		fmt.Printf("\n%s Applied %d migration(s)\n", style.Success.Render("✓"), len(pending))
+	}
+	return nil
+}
+
+// selfUpdate replaces the gt binary with the latest release, the way it
+// was installed.
+func selfUpdate(out UpgradeOutput) error {
+	argv := upgrade.UpdateCommand(out.InstallMethod)
+	if !structuredOutput(false) {
+		fmt.Printf("Updating gt %s → %s: %s\n", out.Version, out.Latest, strings.Join(argv, " "))
+	}
+	c := exec.Command(argv[0], argv[1:]...) //nolint:gosec // G204: argv is one of UpdateCommand's fixed commands
+	// Structured output owns stdout
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	if structuredOutput(false) {
+		c.Stdout = os.Stderr
+	}
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("updating gt (%s): %w", strings.Join(argv, " "), err)
+	}
+	return nil
+}
+
+// rerunUpgrade runs 'gt upgrade --no-self' with the newly installed gt,
+// passing on this run's flags and exit code.
+func rerunUpgrade() error {
+	gt, err := exec.LookPath("gt")
+	if err != nil {
+		if gt, err = os.Executable(); err != nil {
+			return fmt.Errorf("finding the updated gt: %w", err)
+		}
+	}
+	argv := []string{"upgrade", "--no-self"}
+	if upgradeNoBackup {
+		argv = append(argv, "--no-backup")
+	}
+	if structuredOutput(false) {
+		argv = append(argv, "--output", outputFormat)
+	}
+	c := exec.Command(gt, argv...) //nolint:gosec // G204: gt is the installed binary
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return NewSilentExit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("running the updated gt: %w", err)
+	}
+	return nil
+}
+
+// backupTown exports the town's state to the backups directory and
+// returns the archive's path.
+func backupTown(townRoot string) (string, error) {
+	if running, _, _ := doltserver.IsRunning(townRoot); running {
+		return "", fmt.Errorf("the Dolt server is running; stop it with 'gt dolt stop' so the town can be backed up, or pass --no-backup")
+	}
+	name := filepath.Base(townRoot)
+	if townConfig, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json")); err == nil {
+		name = townConfig.Name
+	}
+	dir := filepath.Join(state.StateDir(), "backups")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("creating backups dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-pre-upgrade-%s.tar.gz", name, time.Now().Format("20060102-150405")))
+	if _, err := archive.Export(townRoot, path, Version); err != nil {
+		return "", fmt.Errorf("backing up the town: %w", err)
+	}
+	return path, nil
+}
+
+// runMigrations applies the pending migrations in order, stopping at the
+// first that fails, and records each one's result in out.
+func runMigrations(townRoot string, pending []upgrade.Migration, out *UpgradeOutput) error {
+	for i, m := range pending {
+		r := &out.Migrations[i]
+		if !structuredOutput(false) {
+			fmt.Printf("\n%s %s\n", style.Bold.Render(r.ID), style.Dim.Render(r.Description))
+		}
+		changes, err := upgrade.Apply(townRoot, m, Version)
+		r.Changes = changes
+		if !structuredOutput(false) {
+			for _, c := range changes {
+				fmt.Printf("  %s\n", c)
+			}
+		}
+		if err != nil {
+			r.Status, r.Error = "failed", err.Error()
+			if out.Backup != "" {
+				return fmt.Errorf("%w (the town was backed up to %s)", err, out.Backup)
+			}
+			return err
+		}
+		r.Status = "applied"
+		if !structuredOutput(false) && len(changes) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("(nothing to migrate)"))
+		}
+	}
+	return nil
+}
+
+func printUpgradeCheck(out UpgradeOutput, townRoot string) {
+	switch {
+	case out.Latest == "":
+		fmt.Printf("gt %s %s\n", out.Version, style.Dim.Render("(latest release unknown)"))
+	case out.UpdateAvailable:
+		fmt.Printf("%s gt %s → %s available (%s)\n", style.Warning.Render("↑"), out.Version, out.Latest,
+			strings.Join(upgrade.UpdateCommand(out.InstallMethod), " "))
+	default:
+		fmt.Printf("%s gt %s is the latest release\n", style.Success.Render("✓"), out.Version)
+	}
+
+	if townRoot == "" {
+		return
+	}
+	if len(out.Migrations) == 0 {
+		fmt.Printf("%s Town data is current\n", style.Success.Render("✓"))
+		return
+	}
+	fmt.Printf("%s %d pending migration(s):\n", style.Warning.Render("⚠"), len(out.Migrations))
+	for _, m := range out.Migrations {
+		fmt.Printf("  %s %s\n", m.ID, style.Dim.Render(m.Description))
+	}
+}
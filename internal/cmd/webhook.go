@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/webhook"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Webhook command flags
+var webhookTestDryRun bool
+
+var webhookCmd = &cobra.Command{
+	Use:     "webhook",
+	GroupID: GroupConfig,
+	Short:   "Post town events to outbound webhooks",
+	Long: `Post town events to outbound webhooks, rendering each endpoint's payload
+from a Go template over the event JSON. One event stream can feed PagerDuty,
+Teams, or an internal API without a dedicated integration for each.
+
+Endpoints live in settings/webhooks.json:
+
+  {
+    "type": "webhooks", "version": 1,
+    "endpoints": [{
+      "name": "pagerduty",
+      "url": "https://events.pagerduty.com/v2/enqueue",
+      "filter": ["type=merge_failed,mr_sla_breached"],
+      "template": "{\"routing_key\": {{secret \"pagerduty\" | json}}, \"event_action\": \"trigger\", \"payload\": {\"summary\": {{printf \"%s by %s\" .type .actor | json}}, \"source\": \"gastown\", \"severity\": \"error\"}}"
+    }]
+  }
+
+"filter" takes 'gt events tail --filter' expressions. Templates see the
+event's fields as .ts, .type, .actor, .source and .payload, plus json,
+default, upper, lower and secret functions; "template_file" reads the
+template from a file under settings/. Without a template the event JSON is
+sent as is. The url and header values may be "secret:<name>" references.
+
+The daemon's webhooks task delivers new events every 30 seconds, retrying
+failed deliveries.
+
+Subcommands:
+  list    List endpoints and their delivery state
+  test    Send an endpoint its latest matching event`,
+	RunE: requireSubcommand,
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List endpoints and their delivery state",
+	Long: `List the endpoints in settings/webhooks.json with when each last delivered
+an event and its last error.
+
+Examples:
+  gt webhook list
+  gt webhook list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runWebhookList,
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Send an endpoint its latest matching event",
+	Long: `Render the latest logged event that an endpoint's filter matches (or a
+sample webhook_test event if none does) and send it, to check the template
+and the receiving end.
+
+Examples:
+  gt webhook test pagerduty --dry-run    # Print the payload only
+  gt webhook test teams`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWebhookTest,
+}
+
+func init() {
+	webhookTestCmd.Flags().BoolVarP(&webhookTestDryRun, "dry-run", "n", false, "Print the payload without sending it")
+
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookTestCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+// WebhookListItem is an endpoint as shown by gt webhook list.
+type WebhookListItem struct {
+	Name      string     `json:"name"`
+	Host      string     `json:"host"` // The URL's host, or its secret reference
+	Filter    []string   `json:"filter,omitempty"`
+	Disabled  bool       `json:"disabled,omitempty"`
+	LastSent  *time.Time `json:"last_sent,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+func runWebhookList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateWebhooksConfig(config.WebhooksConfigPath(townRoot))
+	if err != nil {
+		return err
+	}
+	cursors, err := webhook.LoadCursors(townRoot)
+	if err != nil {
+		return err
+	}
+
+	items := []WebhookListItem{}
+	for _, ep := range cfg.Endpoints {
+		item := WebhookListItem{Name: ep.Name, Host: webhookHost(ep.URL), Filter: ep.Filter, Disabled: ep.Disabled}
+		if cur := cursors[ep.Name]; cur != nil {
+			if !cur.LastSent.IsZero() {
+				item.LastSent = &cur.LastSent
+			}
+			if cur.Failures > 0 {
+				item.LastError = cur.LastError
+			}
+		}
+		items = append(items, item)
+	}
+	if structuredOutput(false) {
+		return renderStructured(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println(style.Dim.Render("No webhooks configured. Add endpoints to settings/webhooks.json"))
+		return nil
+	}
+	for _, item := range items {
+		filter := "all events"
+		if len(item.Filter) > 0 {
+			filter = strings.Join(item.Filter, " ")
+		}
+		fmt.Printf("  %-16s %-32s %s\n", style.Bold.Render(item.Name), item.Host, style.Dim.Render(filter))
+		switch {
+		case item.Disabled:
+			fmt.Printf("  %-16s %s\n", "", style.Dim.Render("disabled"))
+		case item.LastError != "":
+			fmt.Printf("  %-16s %s\n", "", style.Error.Render("failing: "+item.LastError))
+		case item.LastSent != nil:
+			fmt.Printf("  %-16s %s\n", "", style.Dim.Render("last sent "+item.LastSent.Local().Format("2006-01-02 15:04")))
+		}
+	}
+	return nil
+}
+
+// webhookHost returns what gt webhook list shows of a URL: its host, since
+// webhook URLs often carry tokens in their path.
+func webhookHost(raw string) string {
+	if secrets.IsRef(raw) {
+		return raw
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "(invalid url)"
+}
+
+func runWebhookTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateWebhooksConfig(config.WebhooksConfigPath(townRoot))
+	if err != nil {
+		return err
+	}
+	var epCfg *config.WebhookEndpoint
+	for i := range cfg.Endpoints {
+		if cfg.Endpoints[i].Name == args[0] {
+			epCfg = &cfg.Endpoints[i]
+		}
+	}
+	if epCfg == nil {
+		return fmt.Errorf("no webhook named %q in %s", args[0], config.WebhooksConfigPath(townRoot))
+	}
+	ep, err := webhook.Compile(townRoot, *epCfg)
+	if err != nil {
+		return err
+	}
+
+	ev := events.Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       "webhook_test",
+		Actor:      detectSender(),
+		Payload:    map[string]interface{}{"endpoint": ep.Name},
+		Visibility: events.VisibilityAudit,
+	}
+	logged, _, err := events.ReadAll(events.Path(townRoot), nil)
+	if err != nil {
+		return err
+	}
+	for i := len(logged) - 1; i >= 0; i-- {
+		if ep.Matches(logged[i]) {
+			ev = logged[i]
+			break
+		}
+	}
+
+	body, err := ep.Render(ev)
+	if err != nil {
+		return err
+	}
+	if webhookTestDryRun {
+		fmt.Println(string(body))
+		return nil
+	}
+	if err := ep.Post(body); err != nil {
+		return err
+	}
+	fmt.Printf("%s Sent %s event to %s\n", style.Success.Render("✓"), ev.Type, ep.Name)
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -52,24 +54,7 @@ func LoadTownConfig(path string) (*TownConfig, error) {
 
 // SaveTownConfig saves a town configuration to a file.
 func SaveTownConfig(path string, config *TownConfig) error {
-	if err := validateTownConfig(config); err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding config: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing config: %w", err)
-	}
-
-	return nil
+	return TownStore(path).Save(config)
 }
 
 // LoadRigsConfig loads and validates a rigs registry file.
@@ -96,24 +81,7 @@ func LoadRigsConfig(path string) (*RigsConfig, error) {
 
 // SaveRigsConfig saves a rigs registry to a file.
 func SaveRigsConfig(path string, config *RigsConfig) error {
-	if err := validateRigsConfig(config); err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding config: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("writing config: %w", err)
-	}
-
-	return nil
+	return RigsStore(path).Save(config)
 }
 
 // validateTownConfig validates a TownConfig.
@@ -169,20 +137,12 @@ func SaveRigConfig(path string, config *RigConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
-		return fmt.Errorf("writing config: %w", err)
-	}
-
-	return nil
+	return writeConfigFile(path, data, 0644)
 }
 
 // validateRigConfig validates a RigConfig (identity only).
@@ -212,6 +172,46 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Tracker != nil {
+		if err := validateTrackerConfig(c.Tracker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTrackerConfig checks a tracker has what its kind needs.
+func validateTrackerConfig(c *TrackerConfig) error {
+	var missing string
+	switch c.Kind {
+	case TrackerGitHub:
+		if c.Repo == "" {
+			missing = "repo"
+		}
+	case TrackerJira:
+		switch {
+		case c.URL == "":
+			missing = "url"
+		case c.Project == "":
+			missing = "project"
+		case c.User == "":
+			missing = "user"
+		}
+	case TrackerLinear:
+		if c.Project == "" {
+			missing = "project"
+		}
+	default:
+		return fmt.Errorf("tracker.kind %q: want %s, %s or %s", c.Kind, TrackerGitHub, TrackerJira, TrackerLinear)
+	}
+	if missing != "" {
+		return fmt.Errorf("tracker: a %s tracker needs %s", c.Kind, missing)
+	}
+	for _, e := range c.Events {
+		if !slices.Contains(TrackerEvents, e) {
+			return fmt.Errorf("tracker.events: unknown event %q (events: %s)", e, strings.Join(TrackerEvents, ", "))
+		}
+	}
 	return nil
 }
 
@@ -288,24 +288,7 @@ func LoadRigSettings(path string) (*RigSettings, error) {
 
 // SaveRigSettings saves rig settings to a file.
 func SaveRigSettings(path string, settings *RigSettings) error {
-	if err := validateRigSettings(settings); err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding settings: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
-		return fmt.Errorf("writing settings: %w", err)
-	}
-
-	return nil
+	return RigSettingsStore(path).Save(settings)
 }
 
 // LoadMayorConfig loads and validates a mayor config file.
@@ -336,20 +319,12 @@ func SaveMayorConfig(path string, config *MayorConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
-		return fmt.Errorf("writing config: %w", err)
-	}
-
-	return nil
+	return writeConfigFile(path, data, 0644)
 }
 
 // validateMayorConfig validates a MayorConfig.
@@ -404,20 +379,12 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding daemon patrol config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
-		return fmt.Errorf("writing daemon patrol config: %w", err)
-	}
-
-	return nil
+	return writeConfigFile(path, data, 0644)
 }
 
 func validateDaemonPatrolConfig(c *DaemonPatrolConfig) error {
@@ -466,24 +433,7 @@ func LoadAccountsConfig(path string) (*AccountsConfig, error) {
 
 // SaveAccountsConfig saves an accounts configuration to a file.
 func SaveAccountsConfig(path string, config *AccountsConfig) error {
-	if err := validateAccountsConfig(config); err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding accounts config: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
-		return fmt.Errorf("writing accounts config: %w", err)
-	}
-
-	return nil
+	return AccountsStore(path).Save(config)
 }
 
 // validateAccountsConfig validates an AccountsConfig.
@@ -617,20 +567,12 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding messaging config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
-		return fmt.Errorf("writing messaging config: %w", err)
-	}
-
-	return nil
+	return writeConfigFile(path, data, 0644)
 }
 
 // validateMessagingConfig validates a MessagingConfig.
@@ -693,6 +635,42 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	if c.Email != nil {
+		if err := validateEmailConfig(c.Email); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateEmailConfig checks email has a server and sender, and every
+// route somewhere to send.
+func validateEmailConfig(c *EmailConfig) error {
+	if c.SMTP.Host == "" {
+		return fmt.Errorf("%w: email.smtp.host", ErrMissingField)
+	}
+	if c.From == "" {
+		return fmt.Errorf("%w: email.from", ErrMissingField)
+	}
+	switch c.SMTP.Security {
+	case "", SMTPStartTLS, SMTPTLS, SMTPNone:
+	default:
+		return fmt.Errorf("email.smtp.security %q: want %s, %s or %s", c.SMTP.Security, SMTPStartTLS, SMTPTLS, SMTPNone)
+	}
+	if c.SMTP.Port < 0 || c.SMTP.Port > 65535 {
+		return fmt.Errorf("email.smtp.port %d: out of range", c.SMTP.Port)
+	}
+	for i, route := range c.Routes {
+		if len(route.To) == 0 {
+			return fmt.Errorf("%w: email.routes[%d].to", ErrMissingField, i)
+		}
+		for _, addr := range route.To {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return fmt.Errorf("email.routes[%d].to %q: not an email address", i, addr)
+			}
+		}
+	}
 	return nil
 }
 
@@ -759,26 +737,17 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
+	return TownSettingsStore(path).Save(settings)
+}
+
+// validateTownSettings validates TownSettings.
+func validateTownSettings(settings *TownSettings) error {
 	if settings.Type != "town-settings" && settings.Type != "" {
 		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, settings.Type)
 	}
 	if settings.Version > CurrentTownSettingsVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
 	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("encoding settings: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
-		return fmt.Errorf("writing settings: %w", err)
-	}
-
 	return nil
 }
 
@@ -1335,6 +1304,11 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		}
 	}
 
+	// Polecats launch as their profile says
+	if role == "polecat" {
+		rc = applyPolecatProfile(rc, townRoot, envVars["GT_POLECAT"])
+	}
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -1445,6 +1419,11 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		}
 	}
 
+	// Polecats launch as their profile says
+	if extractSimpleRole(role) == "polecat" {
+		rc = applyPolecatProfile(rc, townRoot, envVars["GT_POLECAT"])
+	}
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -1673,20 +1652,12 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
-		return fmt.Errorf("writing escalation config: %w", err)
-	}
-
-	return nil
+	return writeConfigFile(path, data, 0644)
 }
 
 // validateEscalationConfig validates an EscalationConfig.
@@ -1725,6 +1696,136 @@ func validateEscalationConfig(c *EscalationConfig) error {
 	return nil
 }
 
+// WebhooksConfigPath returns the standard path for webhooks config in a town.
+func WebhooksConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "webhooks.json")
+}
+
+// LoadWebhooksConfig loads and validates a webhooks configuration file.
+func LoadWebhooksConfig(path string) (*WebhooksConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading webhooks config: %w", err)
+	}
+
+	var config WebhooksConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing webhooks config: %w", err)
+	}
+
+	if err := validateWebhooksConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateWebhooksConfig loads the webhooks config, returning an empty
+// one if not found.
+func LoadOrCreateWebhooksConfig(path string) (*WebhooksConfig, error) {
+	config, err := LoadWebhooksConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewWebhooksConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateWebhooksConfig validates a WebhooksConfig. Filters and templates
+// are checked when the webhook package compiles them.
+func validateWebhooksConfig(c *WebhooksConfig) error {
+	if c.Type != "webhooks" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'webhooks', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentWebhooksVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentWebhooksVersion)
+	}
+
+	seen := make(map[string]bool)
+	for i, ep := range c.Endpoints {
+		if ep.Name == "" {
+			return fmt.Errorf("%w: endpoints[%d].name", ErrMissingField, i)
+		}
+		if seen[ep.Name] {
+			return fmt.Errorf("duplicate webhook endpoint '%s'", ep.Name)
+		}
+		seen[ep.Name] = true
+		if ep.URL == "" {
+			return fmt.Errorf("%w: webhook '%s' has no url", ErrMissingField, ep.Name)
+		}
+		if ep.Template != "" && ep.TemplateFile != "" {
+			return fmt.Errorf("webhook '%s': set template or template_file, not both", ep.Name)
+		}
+	}
+	return nil
+}
+
+// ChatOpsConfigPath returns the standard path for chatops config in a town.
+func ChatOpsConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "chatops.json")
+}
+
+// LoadChatOpsConfig loads and validates a chatops configuration file.
+func LoadChatOpsConfig(path string) (*ChatOpsConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading chatops config: %w", err)
+	}
+
+	var config ChatOpsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing chatops config: %w", err)
+	}
+
+	if err := validateChatOpsConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// validateChatOpsConfig validates a ChatOpsConfig. Tokens must be secret
+// references so the shared tokens never sit in plaintext config.
+func validateChatOpsConfig(c *ChatOpsConfig) error {
+	if c.Type != "chatops" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'chatops', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentChatOpsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentChatOpsVersion)
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range c.Tokens {
+		if t.Name == "" {
+			return fmt.Errorf("%w: tokens[%d].name", ErrMissingField, i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate chatops token '%s'", t.Name)
+		}
+		seen[t.Name] = true
+		if !strings.HasPrefix(t.Token, "secret:") || t.Token == "secret:" {
+			return fmt.Errorf("chatops token '%s': token must be a secret reference (secret:<name>)", t.Name)
+		}
+		if len(t.Scopes) == 0 {
+			return fmt.Errorf("%w: chatops token '%s' has no scopes", ErrMissingField, t.Name)
+		}
+		for _, scope := range t.Scopes {
+			if !slices.Contains(ChatOpsScopes, scope) {
+				return fmt.Errorf("chatops token '%s': unknown scope '%s' (valid: %s)", t.Name, scope, strings.Join(ChatOpsScopes, ", "))
+			}
+		}
+	}
+	return nil
+}
+
 // GetStaleThreshold returns the stale threshold as a time.Duration.
 // Returns 4 hours if not configured or invalid.
 func (c *EscalationConfig) GetStaleThreshold() time.Duration {
@@ -344,6 +344,33 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid tracker",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Kind: TrackerGitHub, Repo: "acme/widgets", Events: []string{TrackerEventClosed}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "jira tracker without project",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Kind: TrackerJira, URL: "https://acme.atlassian.net", User: "bot@acme.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown tracker event",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Kind: TrackerLinear, Project: "WID", Events: []string{"reopened"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -747,6 +774,46 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid email",
+			config: &MessagingConfig{
+				Version: 1,
+				Email: &EmailConfig{
+					SMTP:   SMTPConfig{Host: "smtp.acme.com", Password: "secret:smtp"},
+					From:   "Gas Town <gt@acme.com>",
+					Routes: []EmailRoute{{To: []string{"lead@acme.com"}, Recipients: []string{"mayor/"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "email without host",
+			config: &MessagingConfig{
+				Version: 1,
+				Email:   &EmailConfig{From: "gt@acme.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "email with unknown security",
+			config: &MessagingConfig{
+				Version: 1,
+				Email:   &EmailConfig{SMTP: SMTPConfig{Host: "smtp.acme.com", Security: "ssl"}, From: "gt@acme.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "email route to a town address",
+			config: &MessagingConfig{
+				Version: 1,
+				Email: &EmailConfig{
+					SMTP:   SMTPConfig{Host: "smtp.acme.com"},
+					From:   "gt@acme.com",
+					Routes: []EmailRoute{{To: []string{"mayor/"}}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -759,6 +826,55 @@ func TestMessagingConfigValidation(t *testing.T) {
 	}
 }
 
+func TestWebhooksConfigValidation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		config  *WebhooksConfig
+		wantErr bool
+	}{
+		{"empty", NewWebhooksConfig(), false},
+		{"valid", &WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ops", URL: "secret:ops-hook", Template: "{}"}}}, false},
+		{"wrong type", &WebhooksConfig{Type: "hooks"}, true},
+		{"no name", &WebhooksConfig{Endpoints: []WebhookEndpoint{{URL: "https://x"}}}, true},
+		{"no url", &WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ops"}}}, true},
+		{"duplicate", &WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ops", URL: "https://x"}, {Name: "ops", URL: "https://y"}}}, true},
+		{"two templates", &WebhooksConfig{Endpoints: []WebhookEndpoint{{Name: "ops", URL: "https://x", Template: "{}", TemplateFile: "ops.tmpl"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWebhooksConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWebhooksConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChatOpsConfigValidation(t *testing.T) {
+	t.Parallel()
+	valid := ChatOpsToken{Name: "slack", Token: "secret:chatops-slack", Scopes: []string{ChatOpsScopeStatus}}
+	tests := []struct {
+		name    string
+		tokens  []ChatOpsToken
+		wantErr bool
+	}{
+		{"valid", []ChatOpsToken{valid}, false},
+		{"plaintext token", []ChatOpsToken{{Name: "slack", Token: "abc123", Scopes: []string{ChatOpsScopeStatus}}}, true},
+		{"no scopes", []ChatOpsToken{{Name: "slack", Token: "secret:x"}}, true},
+		{"unknown scope", []ChatOpsToken{{Name: "slack", Token: "secret:x", Scopes: []string{"sling"}}}, true},
+		{"duplicate", []ChatOpsToken{valid, valid}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateChatOpsConfig(&ChatOpsConfig{Tokens: tt.tokens})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChatOpsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadMessagingConfigNotFound(t *testing.T) {
 	t.Parallel()
 	_, err := LoadMessagingConfig("/nonexistent/path.json")
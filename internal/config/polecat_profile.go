@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPolecatProfile names the profile of polecats without their own.
+const DefaultPolecatProfile = "default"
+
+// Polecat context strategies (PolecatProfile.ContextStrategy).
+const (
+	ContextStrategyCompact = "compact"
+	ContextStrategyHandoff = "handoff"
+)
+
+// PolecatProfileFor returns the profile a polecat's sessions launch with,
+// and its name: the polecat's own, else the default one. Returns nil if
+// there is neither.
+func (s *TownSettings) PolecatProfileFor(polecat string) (string, *PolecatProfile) {
+	if p := s.PolecatProfiles[polecat]; p != nil {
+		return polecat, p
+	}
+	if p := s.PolecatProfiles[DefaultPolecatProfile]; p != nil {
+		return DefaultPolecatProfile, p
+	}
+	return "", nil
+}
+
+// Validate checks a profile against the town it belongs to: its context
+// strategy is known and its system prompt file exists.
+func (p *PolecatProfile) Validate(townRoot string) error {
+	switch p.ContextStrategy {
+	case "", ContextStrategyCompact, ContextStrategyHandoff:
+	default:
+		return fmt.Errorf("invalid context_strategy %q (want %s or %s)", p.ContextStrategy, ContextStrategyCompact, ContextStrategyHandoff)
+	}
+	for _, tool := range append(append([]string(nil), p.AllowedTools...), p.DisallowedTools...) {
+		if tool == "" {
+			return fmt.Errorf("empty tool name")
+		}
+	}
+	if p.SystemPromptFile != "" {
+		if _, err := os.Stat(p.SystemPromptPath(townRoot)); err != nil {
+			return fmt.Errorf("system_prompt_file: %w", err)
+		}
+	}
+	return nil
+}
+
+// SystemPromptPath returns the profile's system prompt file as an absolute
+// path, or "" if it has none.
+func (p *PolecatProfile) SystemPromptPath(townRoot string) string {
+	if p.SystemPromptFile == "" || filepath.IsAbs(p.SystemPromptFile) {
+		return p.SystemPromptFile
+	}
+	return filepath.Join(townRoot, p.SystemPromptFile)
+}
+
+// Apply returns a copy of rc launching the agent as the named profile
+// says. Its flags go ahead of rc's own args, tool lists first, so the
+// prompt that follows is never taken for a tool. The system prompt is read
+// when the session starts, so edits to it apply to the next session.
+func (p *PolecatProfile) Apply(rc *RuntimeConfig, name, townRoot string) *RuntimeConfig {
+	profiled := *rc
+	normalizeRuntimeConfig(&profiled)
+
+	var args []string
+	if profiled.Provider == "claude" {
+		if len(p.AllowedTools) > 0 {
+			args = append(args, "--allowedTools")
+			for _, tool := range p.AllowedTools {
+				args = append(args, ShellQuote(tool))
+			}
+		}
+		if len(p.DisallowedTools) > 0 {
+			args = append(args, "--disallowedTools")
+			for _, tool := range p.DisallowedTools {
+				args = append(args, ShellQuote(tool))
+			}
+		}
+		if path := p.SystemPromptPath(townRoot); path != "" {
+			args = append(args, "--append-system-prompt", `"$(cat `+ShellQuote(path)+`)"`)
+		}
+	}
+	if p.Model != "" {
+		args = append(args, "--model", ShellQuote(p.Model))
+	}
+	profiled.Args = append(args, profiled.Args...)
+
+	profiled.Env = make(map[string]string, len(rc.Env)+2)
+	for k, v := range rc.Env {
+		profiled.Env[k] = v
+	}
+	profiled.Env["GT_POLECAT_PROFILE"] = name
+	if p.ContextStrategy != "" {
+		profiled.Env["GT_CONTEXT_STRATEGY"] = p.ContextStrategy
+	}
+	return &profiled
+}
+
+// applyPolecatProfile applies the town's profile for polecat, if any, to
+// the runtime config its session launches with.
+func applyPolecatProfile(rc *RuntimeConfig, townRoot, polecat string) *RuntimeConfig {
+	if townRoot == "" || polecat == "" {
+		return rc
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return rc
+	}
+	name, profile := settings.PolecatProfileFor(polecat)
+	if profile == nil {
+		return rc
+	}
+	return profile.Apply(rc, name, townRoot)
+}
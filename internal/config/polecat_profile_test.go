@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolecatProfileFor(t *testing.T) {
+	t.Parallel()
+	settings := NewTownSettings()
+	if name, p := settings.PolecatProfileFor("toast"); p != nil {
+		t.Errorf("no profiles: got %q", name)
+	}
+
+	settings.PolecatProfiles = map[string]*PolecatProfile{
+		"default": {Model: "sonnet"},
+		"nux":     {Model: "opus"},
+	}
+	if name, p := settings.PolecatProfileFor("nux"); name != "nux" || p.Model != "opus" {
+		t.Errorf("nux: got %q %+v", name, p)
+	}
+	if name, p := settings.PolecatProfileFor("toast"); name != "default" || p.Model != "sonnet" {
+		t.Errorf("toast: got %q %+v, want the default profile", name, p)
+	}
+}
+
+func TestPolecatProfileValidate(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(townRoot, "careful.md"), []byte("Be careful."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []*PolecatProfile{
+		{},
+		{SystemPromptFile: "careful.md", ContextStrategy: ContextStrategyHandoff},
+		{SystemPromptFile: filepath.Join(townRoot, "careful.md")},
+	} {
+		if err := p.Validate(townRoot); err != nil {
+			t.Errorf("Validate(%+v) = %v", p, err)
+		}
+	}
+	for _, p := range []*PolecatProfile{
+		{ContextStrategy: "summarize"},
+		{SystemPromptFile: "missing.md"},
+		{AllowedTools: []string{""}},
+	} {
+		if err := p.Validate(townRoot); err == nil {
+			t.Errorf("Validate(%+v) should fail", p)
+		}
+	}
+}
+
+func TestPolecatProfileApply(t *testing.T) {
+	t.Parallel()
+	p := &PolecatProfile{
+		Model:            "opus",
+		SystemPromptFile: "prompts/careful.md",
+		AllowedTools:     []string{"Bash(git log:*)", "Read"},
+		ContextStrategy:  ContextStrategyHandoff,
+	}
+	rc := &RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}, Env: map[string]string{"A": "1"}}
+
+	got := p.Apply(rc, "nux", "/town")
+	want := `claude --allowedTools 'Bash(git log:*)' Read --append-system-prompt "$(cat /town/prompts/careful.md)" --model opus --dangerously-skip-permissions`
+	if cmd := got.BuildCommand(); cmd != want {
+		t.Errorf("command = %s\nwant %s", cmd, want)
+	}
+	if got.Env["GT_POLECAT_PROFILE"] != "nux" || got.Env["GT_CONTEXT_STRATEGY"] != "handoff" || got.Env["A"] != "1" {
+		t.Errorf("env = %v", got.Env)
+	}
+	if len(rc.Args) != 1 || len(rc.Env) != 1 {
+		t.Errorf("Apply modified rc: %+v", rc)
+	}
+
+	// Tool permissions and system prompts are claude flags
+	codex := p.Apply(&RuntimeConfig{Provider: "codex", Command: "codex", Args: []string{}}, "nux", "/town")
+	if cmd := codex.BuildCommand(); cmd != "codex --model opus" {
+		t.Errorf("codex command = %s", cmd)
+	}
+}
+
+func TestBuildPolecatStartupCommandWithProfile(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	settings := NewTownSettings()
+	settings.PolecatProfiles = map[string]*PolecatProfile{"toast": {Model: "haiku"}}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), NewRigSettings()); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd := BuildPolecatStartupCommand("testrig", "toast", rigPath, "")
+	if !strings.Contains(cmd, "--model haiku") || !strings.Contains(cmd, "GT_POLECAT_PROFILE=toast") {
+		t.Errorf("toast's command should use its profile: %s", cmd)
+	}
+	cmd = BuildPolecatStartupCommand("testrig", "nux", rigPath, "")
+	if strings.Contains(cmd, "--model") {
+		t.Errorf("nux has no profile: %s", cmd)
+	}
+	cmd = BuildCrewStartupCommand("testrig", "toast", rigPath, "")
+	if strings.Contains(cmd, "--model") {
+		t.Errorf("crew members don't use polecat profiles: %s", cmd)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// SessionTemplateVars are the variables available to session templates.
+type SessionTemplateVars struct {
+	Role     string // mayor, polecat, ...
+	TownRoot string
+	Rig      string // empty for town-level roles
+	RigPath  string // empty for town-level roles
+	Worker   string // polecat name (empty for singletons)
+	Issue    string // assigned issue, if any
+	Command  string // the default startup command
+}
+
+// ApplySessionTemplate applies the town's session template for vars.Role to
+// a startup command. It returns the command to run (the template's command
+// with its env exported first) and the rendered env, for callers that also
+// set it on the tmux session. Without a template for the role, the default
+// command is returned unchanged.
+func ApplySessionTemplate(townRoot string, vars SessionTemplateVars) (string, map[string]string, error) {
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	tmpl := settings.Sessions[vars.Role]
+	if tmpl == nil {
+		return vars.Command, nil, nil
+	}
+
+	command, env, err := RenderSessionTemplate(tmpl, vars)
+	if err != nil {
+		return "", nil, fmt.Errorf("rendering %s session template: %w", vars.Role, err)
+	}
+	return PrependEnv(command, env), env, nil
+}
+
+// RenderSessionTemplate renders a session template's env values and command.
+// The command is vars.Command when the template does not set one.
+func RenderSessionTemplate(tmpl *SessionTemplateConfig, vars SessionTemplateVars) (string, map[string]string, error) {
+	var env map[string]string
+	if len(tmpl.Env) > 0 {
+		keys := make([]string, 0, len(tmpl.Env))
+		for k := range tmpl.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		env = make(map[string]string, len(tmpl.Env))
+		for _, k := range keys {
+			v, err := renderSessionValue("env."+k, tmpl.Env[k], vars)
+			if err != nil {
+				return "", nil, err
+			}
+			env[k] = v
+		}
+	}
+
+	command := vars.Command
+	if tmpl.Command != "" {
+		var err error
+		if command, err = renderSessionValue("command", tmpl.Command, vars); err != nil {
+			return "", nil, err
+		}
+	}
+	return command, env, nil
+}
+
+// AgentWindow names the window a templated session's agent runs in.
+const AgentWindow = "agent"
+
+// SessionTemplateWindows returns the extra windows of the town's session
+// template for vars.Role, with their commands rendered. Returns nil if the
+// role has no template or it has no windows.
+func SessionTemplateWindows(townRoot string, vars SessionTemplateVars) ([]SessionWindowConfig, error) {
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	tmpl := settings.Sessions[vars.Role]
+	if tmpl == nil {
+		return nil, nil
+	}
+	windows, err := RenderSessionWindows(tmpl, vars)
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s session template: %w", vars.Role, err)
+	}
+	return windows, nil
+}
+
+// RenderSessionWindows checks a session template's windows and renders
+// their commands. Window names must be unique, and not the agent's.
+func RenderSessionWindows(tmpl *SessionTemplateConfig, vars SessionTemplateVars) ([]SessionWindowConfig, error) {
+	var windows []SessionWindowConfig
+	seen := map[string]bool{AgentWindow: true}
+	for _, w := range tmpl.Windows {
+		switch {
+		case w.Name == "":
+			return nil, fmt.Errorf("window with no name")
+		case strings.ContainsAny(w.Name, ":. "):
+			return nil, fmt.Errorf("window %q: name may not contain ':', '.' or spaces", w.Name)
+		case seen[w.Name]:
+			return nil, fmt.Errorf("duplicate window %q", w.Name)
+		}
+		seen[w.Name] = true
+
+		rendered := SessionWindowConfig{Name: w.Name, Layout: w.Layout}
+		var err error
+		if rendered.Command, err = renderSessionValue("windows."+w.Name, w.Command, vars); err != nil {
+			return nil, err
+		}
+		for i, pane := range w.Panes {
+			command, err := renderSessionValue(fmt.Sprintf("windows.%s.panes[%d]", w.Name, i), pane, vars)
+			if err != nil {
+				return nil, err
+			}
+			rendered.Panes = append(rendered.Panes, command)
+		}
+		windows = append(windows, rendered)
+	}
+	return windows, nil
+}
+
+func renderSessionValue(name, text string, vars SessionTemplateVars) (string, error) {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("rendering %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
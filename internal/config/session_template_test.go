@@ -0,0 +1,118 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSessionTemplate(t *testing.T) {
+	vars := SessionTemplateVars{
+		Role:     "polecat",
+		TownRoot: "/gt",
+		Rig:      "greenplace",
+		RigPath:  "/gt/greenplace",
+		Worker:   "Toast",
+		Issue:    "gp-42",
+		Command:  "exec env GT_ROLE=greenplace/polecats/Toast claude",
+	}
+
+	cmd, env, err := RenderSessionTemplate(&SessionTemplateConfig{
+		Env: map[string]string{
+			"WORK_ISSUE": "{{.Issue}}",
+			"CACHE_DIR":  "{{.RigPath}}/.cache/{{.Worker}}",
+		},
+		Command: "ulimit -n 4096; {{.Command}}",
+	}, vars)
+	if err != nil {
+		t.Fatalf("RenderSessionTemplate: %v", err)
+	}
+	if cmd != "ulimit -n 4096; "+vars.Command {
+		t.Errorf("command = %q", cmd)
+	}
+	if env["WORK_ISSUE"] != "gp-42" || env["CACHE_DIR"] != "/gt/greenplace/.cache/Toast" {
+		t.Errorf("env = %v", env)
+	}
+
+	// No command template keeps the default command
+	cmd, _, err = RenderSessionTemplate(&SessionTemplateConfig{Env: map[string]string{"A": "b"}}, vars)
+	if err != nil || cmd != vars.Command {
+		t.Errorf("default command = %q, %v", cmd, err)
+	}
+
+	for _, bad := range []string{"{{.Nope}}", "{{.Issue"} {
+		if _, _, err := RenderSessionTemplate(&SessionTemplateConfig{Command: bad}, vars); err == nil {
+			t.Errorf("RenderSessionTemplate(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRenderSessionWindows(t *testing.T) {
+	vars := SessionTemplateVars{Role: "polecat", RigPath: "/gt/greenplace", Worker: "Toast"}
+
+	windows, err := RenderSessionWindows(&SessionTemplateConfig{
+		Windows: []SessionWindowConfig{
+			{Name: "tests", Command: "cd {{.RigPath}} && make test-watch"},
+			{Name: "logs", Panes: []string{"tail -F /tmp/{{.Worker}}.log", "htop"}, Layout: "even-vertical"},
+		},
+	}, vars)
+	if err != nil {
+		t.Fatalf("RenderSessionWindows: %v", err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("got %d windows, want 2", len(windows))
+	}
+	if windows[0].Command != "cd /gt/greenplace && make test-watch" {
+		t.Errorf("tests command = %q", windows[0].Command)
+	}
+	if windows[1].Command != "" || len(windows[1].Panes) != 2 || windows[1].Panes[0] != "tail -F /tmp/Toast.log" || windows[1].Layout != "even-vertical" {
+		t.Errorf("logs window = %+v", windows[1])
+	}
+
+	for _, bad := range [][]SessionWindowConfig{
+		{{Name: ""}},
+		{{Name: "a:b"}},
+		{{Name: AgentWindow}},
+		{{Name: "tests"}, {Name: "tests"}},
+		{{Name: "tests", Panes: []string{"{{.Nope}}"}}},
+	} {
+		if _, err := RenderSessionWindows(&SessionTemplateConfig{Windows: bad}, vars); err == nil {
+			t.Errorf("RenderSessionWindows(%+v) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestApplySessionTemplate(t *testing.T) {
+	townRoot := t.TempDir()
+	vars := SessionTemplateVars{Role: "mayor", TownRoot: townRoot, Command: "exec claude"}
+
+	// No settings file: command unchanged
+	cmd, env, err := ApplySessionTemplate(townRoot, vars)
+	if err != nil || cmd != "exec claude" || env != nil {
+		t.Fatalf("ApplySessionTemplate without settings = %q, %v, %v", cmd, env, err)
+	}
+
+	settings := NewTownSettings()
+	settings.Sessions = map[string]*SessionTemplateConfig{
+		"mayor": {Env: map[string]string{"GT_HOME": "{{.TownRoot}}"}, Command: "cd {{.TownRoot}} && {{.Command}}"},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	cmd, env, err = ApplySessionTemplate(townRoot, vars)
+	if err != nil {
+		t.Fatalf("ApplySessionTemplate: %v", err)
+	}
+	if env["GT_HOME"] != townRoot {
+		t.Errorf("env = %v", env)
+	}
+	if !strings.HasPrefix(cmd, "export GT_HOME=") || !strings.HasSuffix(cmd, "cd "+townRoot+" && exec claude") {
+		t.Errorf("command = %q", cmd)
+	}
+
+	// Roles without a template are untouched
+	vars.Role = "polecat"
+	if cmd, _, _ := ApplySessionTemplate(townRoot, vars); cmd != "exec claude" {
+		t.Errorf("polecat command = %q, want unchanged", cmd)
+	}
+}
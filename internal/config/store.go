@@ -0,0 +1,193 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// configLockTimeout bounds the wait for another gt process changing the
+// same config file.
+const configLockTimeout = 10 * time.Second
+
+// ErrConfigLocked indicates another process held a config file's lock past
+// configLockTimeout.
+var ErrConfigLocked = errors.New("config file locked")
+
+// Store is a JSON config file that any gt process in the town may change,
+// such as mayor/rigs.json or settings/config.json. Writes hold an exclusive
+// lock on <file>.lock and replace the file atomically, so readers never see
+// half a file. Update holds the lock from its read to its write, so two
+// processes changing the file at once don't lose each other's changes.
+type Store[T any] struct {
+	path     string
+	perm     os.FileMode
+	load     func(path string) (*T, error)
+	validate func(*T) error
+}
+
+// RigsStore returns the store for a rigs registry (mayor/rigs.json). A
+// missing registry loads as an empty one.
+func RigsStore(path string) *Store[RigsConfig] {
+	return &Store[RigsConfig]{
+		path: path,
+		perm: 0600,
+		load: func(path string) (*RigsConfig, error) {
+			c, err := LoadRigsConfig(path)
+			if errors.Is(err, ErrNotFound) {
+				return &RigsConfig{Version: CurrentRigsVersion, Rigs: make(map[string]RigEntry)}, nil
+			}
+			return c, err
+		},
+		validate: validateRigsConfig,
+	}
+}
+
+// TownStore returns the store for a town's identity (mayor/town.json).
+func TownStore(path string) *Store[TownConfig] {
+	return &Store[TownConfig]{path: path, perm: 0600, load: LoadTownConfig, validate: validateTownConfig}
+}
+
+// TownSettingsStore returns the store for a town's settings
+// (settings/config.json). Missing settings load as the defaults.
+func TownSettingsStore(path string) *Store[TownSettings] {
+	return &Store[TownSettings]{path: path, perm: 0644, load: LoadOrCreateTownSettings, validate: validateTownSettings}
+}
+
+// RigSettingsStore returns the store for a rig's settings
+// (<rig>/settings/config.json). Missing settings load as the defaults.
+func RigSettingsStore(path string) *Store[RigSettings] {
+	return &Store[RigSettings]{
+		path: path,
+		perm: 0644,
+		load: func(path string) (*RigSettings, error) {
+			s, err := LoadRigSettings(path)
+			if errors.Is(err, ErrNotFound) {
+				return NewRigSettings(), nil
+			}
+			return s, err
+		},
+		validate: validateRigSettings,
+	}
+}
+
+// AccountsStore returns the store for a town's Claude accounts
+// (mayor/accounts.json). A missing file loads as no accounts.
+func AccountsStore(path string) *Store[AccountsConfig] {
+	return &Store[AccountsConfig]{
+		path: path,
+		perm: 0644,
+		load: func(path string) (*AccountsConfig, error) {
+			c, err := LoadAccountsConfig(path)
+			if errors.Is(err, ErrNotFound) {
+				return NewAccountsConfig(), nil
+			}
+			return c, err
+		},
+		validate: validateAccountsConfig,
+	}
+}
+
+// Path returns the config file's path.
+func (s *Store[T]) Path() string {
+	return s.path
+}
+
+// Load reads the config. Reads take no lock: writes are atomic.
+func (s *Store[T]) Load() (*T, error) {
+	return s.load(s.path)
+}
+
+// Save validates and writes v, replacing the file under its lock.
+func (s *Store[T]) Save(v *T) error {
+	data, err := s.encode(v)
+	if err != nil {
+		return err
+	}
+	return writeConfigFile(s.path, data, s.perm)
+}
+
+// Update changes the config under its lock: it loads the file, lets fn
+// change it, and saves the result. Nothing is written if fn fails. Returns
+// the config as saved.
+func (s *Store[T]) Update(fn func(*T) error) (*T, error) {
+	unlock, err := lockConfigFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	v, err := s.load(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(v); err != nil {
+		return nil, err
+	}
+	data, err := s.encode(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := replaceConfigFile(s.path, data, s.perm); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *Store[T]) encode(v *T) ([]byte, error) {
+	if s.validate != nil {
+		if err := s.validate(v); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", filepath.Base(s.path), err)
+	}
+	return data, nil
+}
+
+// lockConfigFile takes the exclusive lock on a config file, waiting up to
+// configLockTimeout for another process to release it. The returned func
+// releases it.
+func lockConfigFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), configLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("%w: %s (held by another gt for over %v)", ErrConfigLocked, path, configLockTimeout)
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// writeConfigFile replaces a config file with data under its lock.
+func writeConfigFile(path string, data []byte, perm os.FileMode) error {
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return replaceConfigFile(path, data, perm)
+}
+
+// replaceConfigFile atomically replaces a config file whose lock is held.
+func replaceConfigFile(path string, data []byte, perm os.FileMode) error {
+	if err := util.AtomicWriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
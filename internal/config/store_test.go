@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "mayor", "rigs.json")
+	store := RigsStore(path)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("rig%d", i)
+			_, err := store.Update(func(c *RigsConfig) error {
+				c.Rigs[name] = RigEntry{GitURL: "https://example.com/" + name + ".git"}
+				return nil
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	got, err := LoadRigsConfig(path)
+	if err != nil {
+		t.Fatalf("LoadRigsConfig: %v", err)
+	}
+	if len(got.Rigs) != n {
+		t.Errorf("got %d rigs, want %d: concurrent updates were lost", len(got.Rigs), n)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestStoreUpdateFailure(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "settings", "config.json")
+	store := TownSettingsStore(path)
+	if _, err := store.Update(func(s *TownSettings) error {
+		s.DefaultAgent = "gemini"
+		return nil
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	errNope := errors.New("nope")
+	if _, err := store.Update(func(s *TownSettings) error {
+		s.DefaultAgent = "codex"
+		return errNope
+	}); !errors.Is(err, errNope) {
+		t.Fatalf("Update = %v, want fn's error", err)
+	}
+	if _, err := store.Update(func(s *TownSettings) error {
+		s.Version = CurrentTownSettingsVersion + 1
+		return nil
+	}); !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("Update of an invalid config = %v, want ErrInvalidVersion", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.DefaultAgent != "gemini" || got.Version > CurrentTownSettingsVersion {
+		t.Errorf("failed updates were written: %+v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -45,6 +46,11 @@ type TownSettings struct {
 	// Can be overridden by GT_THEME environment variable.
 	CLITheme string `json:"cli_theme,omitempty"`
 
+	// TimeFormat controls how human-readable output shows times.
+	// Values: "relative" (default, "3h ago"), "iso" (RFC 3339 UTC),
+	// "local" (local zone). Overridden by GT_TIME_FORMAT and --time-format.
+	TimeFormat string `json:"time_format,omitempty"`
+
 	// DefaultAgent is the name of the agent preset to use by default.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
 	// or a custom agent name defined in settings/agents.json.
@@ -68,6 +74,123 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// SessionBackend selects the program that hosts agent sessions:
+	// "tmux" (default), "screen", or "process" (detached subprocesses for
+	// headless CI and containers). GT_SESSION_BACKEND overrides it.
+	SessionBackend string `json:"session_backend,omitempty"`
+
+	// Sessions maps role names ("mayor", "polecat") to session templates
+	// that add environment variables to, or wrap, the startup command.
+	// Example: {"polecat": {"env": {"WORK_ISSUE": "{{.Issue}}"}}}
+	Sessions map[string]*SessionTemplateConfig `json:"sessions,omitempty"`
+
+	// PolecatProfiles tailor polecat sessions: keys are polecat names, or
+	// "default" for polecats without a profile of their own. Edit them with
+	// 'gt polecat profile edit'.
+	// Example: {"default": {"model": "sonnet"}, "nux": {"model": "opus"}}
+	PolecatProfiles map[string]*PolecatProfile `json:"polecat_profiles,omitempty"`
+
+	// Permissions overrides the permission level ("mayor", "operator",
+	// "polecat", "readonly") of callers of destructive commands. Keys are
+	// actor globs such as "greenplace/crew/*" or "*/polecats/*", or "human"
+	// for callers without GT_ROLE.
+	// Example: {"greenplace/crew/intern": "readonly"}
+	Permissions map[string]string `json:"permissions,omitempty"`
+
+	// Budgets caps agent session time per role ("polecat", "witness",
+	// "refinery", "deacon"). The daemon will not start or restart a role's
+	// sessions once its budget is spent. Rig settings override per rig.
+	// Example: {"polecat": {"max_session_hours_per_day": 40, "max_respawns_per_hour": 10}}
+	Budgets map[string]*SessionBudget `json:"budgets,omitempty"`
+
+	// MayorDigest batches routine mail to the Mayor into a periodic summary
+	// instead of one mail per event (nil = deliver each mail).
+	MayorDigest *MayorDigestConfig `json:"mayor_digest,omitempty"`
+}
+
+// SessionBudget limits the agent time a role consumes, summed over all of
+// the role's agents in a rig (or the town, for town-level roles). Zero
+// means unlimited.
+type SessionBudget struct {
+	// MaxSessionHoursPerDay caps session hours since local midnight.
+	MaxSessionHoursPerDay float64 `json:"max_session_hours_per_day,omitempty"`
+
+	// MaxRespawnsPerHour caps session starts in the last hour.
+	MaxRespawnsPerHour int `json:"max_respawns_per_hour,omitempty"`
+}
+
+// MayorDigestConfig configures the Mayor's notification digest. Mail to the
+// Mayor in the chosen categories is held and delivered by the daemon as one
+// summary per interval. Urgent mail is always delivered at once.
+type MayorDigestConfig struct {
+	// Interval is how long mail is held before the digest goes out (e.g.,
+	// "30m"; default "1h").
+	Interval string `json:"interval,omitempty"`
+
+	// Categories are the kinds of mail to batch: "mr" (merge outcomes),
+	// "escalation", and "stuck" (stuck or killed workers). Default: all.
+	Categories []string `json:"categories,omitempty"`
+
+	// Inject also types a one-line briefing into the Mayor's session when
+	// the digest is delivered.
+	Inject bool `json:"inject,omitempty"`
+}
+
+// SessionTemplateConfig templates an agent session's startup. Values are Go
+// text/template strings rendered with SessionTemplateVars.
+type SessionTemplateConfig struct {
+	// Env holds extra environment variables for the session.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Command replaces the startup command; {{.Command}} is the command Gas
+	// Town would otherwise run. That command starts with exec, so setup steps
+	// go before it. Example: "ulimit -n 4096; {{.Command}}".
+	// If empty, the default command is used.
+	Command string `json:"command,omitempty"`
+
+	// Windows are extra tmux windows opened alongside the agent's, which
+	// is named "agent" and stays selected (e.g., a test watcher and logs).
+	Windows []SessionWindowConfig `json:"windows,omitempty"`
+}
+
+// SessionWindowConfig is a tmux window in a templated session, addressable
+// by name (e.g., 'gt polecat send <name> --window tests'). Commands are
+// templates like the session's own; an empty one opens a shell.
+type SessionWindowConfig struct {
+	// Name identifies the window (e.g., "tests").
+	Name string `json:"name"`
+
+	// Command runs in the window's first pane.
+	Command string `json:"command,omitempty"`
+
+	// Panes are commands for more panes, split off the window in order.
+	Panes []string `json:"panes,omitempty"`
+
+	// Layout is a tmux layout for the window's panes (e.g., "even-horizontal",
+	// "main-vertical", "tiled"; default: tmux's).
+	Layout string `json:"layout,omitempty"`
+}
+
+// PolecatProfile is a polecat's persona: how its agent sessions are
+// launched, on top of the agent chosen for the polecat role.
+type PolecatProfile struct {
+	// Model selects the agent's model, passed as --model (e.g., "opus").
+	Model string `json:"model,omitempty"`
+
+	// SystemPromptFile is a file, relative to the town root unless
+	// absolute, appended to the agent's system prompt (claude only).
+	SystemPromptFile string `json:"system_prompt_file,omitempty"`
+
+	// AllowedTools and DisallowedTools are the agent's tool permissions,
+	// in claude's --allowedTools syntax (e.g., "Bash(git:*)", "WebFetch").
+	AllowedTools    []string `json:"allowed_tools,omitempty"`
+	DisallowedTools []string `json:"disallowed_tools,omitempty"`
+
+	// ContextStrategy is what the polecat does as its context fills:
+	// "compact" (default) lets the agent compact it, "handoff" has the
+	// polecat hand off to a fresh session instead.
+	ContextStrategy string `json:"context_strategy,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -215,6 +338,7 @@ type RigSettings struct {
 	Theme      *ThemeConfig      `json:"theme,omitempty"`       // tmux theme settings
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
+	Polecat    *PolecatConfig    `json:"polecat,omitempty"`     // polecat session settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
@@ -236,6 +360,122 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Budgets overrides TownSettings.Budgets for this rig's roles, field by
+	// field. Example: {"polecat": {"max_session_hours_per_day": 8}}
+	Budgets map[string]*SessionBudget `json:"budgets,omitempty"`
+
+	// WIP caps each worker's unfinished work in this rig (nil = no limits).
+	WIP *WIPLimitConfig `json:"wip,omitempty"`
+
+	// IssueTemplates are the templates gt issue new fills in, by issue type
+	// (e.g., "bug"). A type listed here replaces the built-in template.
+	IssueTemplates map[string]*IssueTemplateConfig `json:"issue_templates,omitempty"`
+
+	// Tracker mirrors this rig's work to an external issue tracker (nil =
+	// none).
+	Tracker *TrackerConfig `json:"tracker,omitempty"`
+}
+
+// IssueTemplateConfig is the structure gt issue new gives every issue of a
+// type, so workers get the same context on each. The description is
+// "<Field>: <value>" lines, then one "## <section>" heading per section.
+type IssueTemplateConfig struct {
+	// Fields are one-line values (e.g., "Component", "Version").
+	Fields []string `json:"fields,omitempty"`
+
+	// Sections are the description's headings, in order (e.g., "Steps to
+	// Reproduce", "Expected", "Actual").
+	Sections []string `json:"sections,omitempty"`
+
+	// Required fields and sections must be filled in; gt issue new refuses
+	// an issue that leaves one empty.
+	Required []string `json:"required,omitempty"`
+
+	// Priority is the default priority, 0-4 (nil = 2).
+	Priority *int `json:"priority,omitempty"`
+
+	// Labels are added to every issue created from the template.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// WIPLimitConfig caps how much unfinished work one worker may have in a rig,
+// so agents finish what they started before fanning out. gt mq submit and
+// gt sling refuse work past a limit unless run with --ignore-wip.
+type WIPLimitConfig struct {
+	// MaxOpenMRsPerWorker caps a worker's MRs that are submitted but not yet
+	// merged or otherwise closed (0 = no limit).
+	MaxOpenMRsPerWorker int `json:"max_open_mrs_per_worker,omitempty"`
+
+	// MaxInProgressPerWorker caps the issues hooked to or in progress with
+	// one worker (0 = no limit).
+	MaxInProgressPerWorker int `json:"max_in_progress_per_worker,omitempty"`
+}
+
+// Tracker kinds.
+const (
+	TrackerGitHub = "github"
+	TrackerJira   = "jira"
+	TrackerLinear = "linear"
+)
+
+// Tracker events: what gets mirrored.
+const (
+	TrackerEventCreated    = "created"     // A work bead was created: open an item for it
+	TrackerEventInProgress = "in_progress" // Work started on it
+	TrackerEventClosed     = "closed"      // It was closed
+	TrackerEventMerged     = "merged"      // An MR for it merged: comment with the commit
+)
+
+// TrackerEvents lists every tracker event.
+var TrackerEvents = []string{TrackerEventCreated, TrackerEventInProgress, TrackerEventClosed, TrackerEventMerged}
+
+// TrackerConfig mirrors a rig's work beads to an external tracker (GitHub
+// Issues, Jira or Linear), so people who never use gt see progress. The
+// daemon's tracker-sync task (and gt tracker sync) opens an item for each
+// new work bead and updates it as the bead starts, closes and merges.
+type TrackerConfig struct {
+	Kind string `json:"kind"` // "github", "jira" or "linear"
+
+	// Repo is the GitHub repository, as owner/name (github).
+	Repo string `json:"repo,omitempty"`
+
+	// URL is the Jira site, e.g. "https://acme.atlassian.net" (jira).
+	URL string `json:"url,omitempty"`
+
+	// Project is the Jira project key (jira) or Linear team key (linear)
+	// items are created in.
+	Project string `json:"project,omitempty"`
+
+	// User is the account the Jira API token belongs to (jira).
+	User string `json:"user,omitempty"`
+
+	// Token is the API token, usually "secret:<name>" (jira, linear).
+	// Without it the token comes from the environment.
+	Token string `json:"token,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token
+	// (default: JIRA_API_TOKEN, LINEAR_API_KEY). GitHub uses gh's login.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Events are what to mirror (default: all of TrackerEvents).
+	Events []string `json:"events,omitempty"`
+
+	// Labels, if set, limits mirroring to beads with one of these labels.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// MirrorsEvent reports whether the tracker mirrors an event.
+func (c *TrackerConfig) MirrorsEvent(event string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -252,6 +492,13 @@ type CrewConfig struct {
 	Startup string `json:"startup,omitempty"`
 }
 
+// PolecatConfig represents polecat session settings for a rig.
+type PolecatConfig struct {
+	// AutoStartOnAttach starts a stopped polecat's session when someone runs
+	// gt polecat attach, instead of failing. Default false.
+	AutoStartOnAttach bool `json:"auto_start_on_attach,omitempty"`
+}
+
 // RuntimeConfig represents LLM runtime configuration for agent sessions.
 // This allows switching between different LLM backends (claude, aider, etc.)
 // without modifying startup code.
@@ -716,6 +963,474 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// Schedule restricts when the refinery may merge (nil = any time).
+	Schedule *MergeScheduleConfig `json:"schedule,omitempty"`
+
+	// Fairness adjusts the order MRs are merged in beyond priority score
+	// (nil = score order).
+	Fairness *MergeFairnessConfig `json:"fairness,omitempty"`
+
+	// SLA sets how long MRs may wait to merge, by priority (nil = no targets).
+	SLA *MergeSLAConfig `json:"sla,omitempty"`
+
+	// Transactional lands merges through a staging ref that must pass
+	// verification before the target moves (nil = push merges directly).
+	Transactional *TransactionalMergeConfig `json:"transactional,omitempty"`
+
+	// Protection holds branch protection rules every MR must satisfy (nil = none).
+	Protection *BranchProtectionConfig `json:"protection,omitempty"`
+
+	// Risk tunes MR risk scoring (nil = default scoring, no approval gate).
+	Risk *MergeRiskConfig `json:"risk,omitempty"`
+
+	// Description is the template for MR descriptions written at submit
+	// time (nil = none).
+	Description *MRDescriptionConfig `json:"description,omitempty"`
+
+	// Flaky tunes flaky check detection and retries (nil = defaults).
+	Flaky *FlakyChecksConfig `json:"flaky,omitempty"`
+
+	// Checks sets where and with what limits each check runs, by check
+	// name ("tests", "verify"). Checks not listed run in the town host's
+	// shell.
+	Checks map[string]*CheckRunnerConfig `json:"checks,omitempty"`
+
+	// Artifacts sets which files the refinery keeps from each check run,
+	// besides its log, and for how long (nil = logs only, default
+	// retention).
+	Artifacts *MergeArtifactsConfig `json:"artifacts,omitempty"`
+
+	// Coverage gates merges on test coverage: an MR whose merged result
+	// covers less of the code than its target, by more than MaxDrop, is
+	// failed (nil = no gate).
+	Coverage *MergeCoverageConfig `json:"coverage,omitempty"`
+
+	// Lint lists linters the refinery runs on each MR's merged result.
+	// Their findings are recorded on the MR, and any send it back to its
+	// worker with a task to fix exactly those.
+	Lint []*LinterConfig `json:"lint,omitempty"`
+
+	// Owners maps paths to the owners who must sign off on MRs touching
+	// them, CODEOWNERS-style (nil = no owners).
+	Owners []OwnershipRule `json:"owners,omitempty"`
+
+	// Changelog makes the refinery add an entry to CHANGELOG.md for each
+	// merged MR (via 'gt changelog <rig> --append <mr-id>').
+	Changelog bool `json:"changelog,omitempty"`
+
+	// DispatchConflicts makes the refinery sling each conflict-resolution
+	// task it creates to a fresh polecat, instead of leaving it in bd ready
+	// for someone to notice.
+	DispatchConflicts bool `json:"dispatch_conflicts,omitempty"`
+
+	// ArchiveAfter is how long closed MRs stay in the beads database before
+	// the daemon moves them to the rig's MR archive: a Go duration or whole
+	// days (e.g., "30d"). Empty means only 'gt mq archive' archives them.
+	ArchiveAfter string `json:"archive_after,omitempty"`
+
+	// ReleaseHook is a shell command 'gt release' runs in the refinery's
+	// clone after tagging (e.g., "make publish"). It gets GT_RIG,
+	// GT_RELEASE_VERSION, and GT_RELEASE_NOTES (path to the changelog).
+	ReleaseHook string `json:"release_hook,omitempty"`
+
+	// Targets are branches besides the rig's default branch that MRs may
+	// be submitted against (e.g., release branches). Each target has its
+	// own sub-queue.
+	Targets []MergeTargetConfig `json:"targets,omitempty"`
+
+	// Projects are a monorepo rig's sub-projects, by path. An MR whose
+	// changes all fall in one project is tested and timed by the project's
+	// settings, and can wait in a sub-queue of its own.
+	Projects []MergeProjectConfig `json:"projects,omitempty"`
+
+	// Locks are the rig's hot paths. MRs touching the same lock's paths, or
+	// declaring the same lock at submit, are merged one at a time even
+	// when the refinery could otherwise take them in parallel.
+	Locks []MergeLockConfig `json:"locks,omitempty"`
+
+	// Signing makes the refinery sign every merge commit it lands, and
+	// refuse to land one it can't sign (nil = commits land as they are).
+	Signing *MergeSigningConfig `json:"signing,omitempty"`
+
+	// Attestation makes the refinery record an attestation for every merge
+	// it lands (nil = none).
+	Attestation *MergeAttestationConfig `json:"attestation,omitempty"`
+
+	// Policies are merge rules over MR attributes (size, paths, time of day,
+	// risk, approvals, author). An MR is held while any policy denies it.
+	Policies []MergePolicyRule `json:"policies,omitempty"`
+
+	// CommitMessages is the convention commit messages on MR branches must
+	// follow (nil = any message).
+	CommitMessages *CommitMessageConfig `json:"commit_messages,omitempty"`
+
+	// Views are named 'gt mq list' views, selected with --view (e.g.,
+	// "stuck"). A view named "mine" replaces the built-in one.
+	Views map[string]*MQListViewConfig `json:"views,omitempty"`
+}
+
+// MQListViewConfig is a saved 'gt mq list' view: filters, columns and
+// order. Flags given alongside --view override its settings.
+type MQListViewConfig struct {
+	// Ready shows only MRs with no blockers, as with --ready.
+	Ready bool `json:"ready,omitempty"`
+
+	// Status shows only MRs with this status (open, in_progress, closed,
+	// all; default: the unmerged ones).
+	Status string `json:"status,omitempty"`
+
+	// Worker shows only this worker's MRs; "@me" is whoever runs the view.
+	Worker string `json:"worker,omitempty"`
+
+	// Epic shows only MRs targeting integration/<epic>.
+	Epic string `json:"epic,omitempty"`
+
+	// Target shows only the sub-queue for this target branch.
+	Target string `json:"target,omitempty"`
+
+	// Labels shows only MRs with all of these labels.
+	Labels []string `json:"labels,omitempty"`
+
+	// OlderThan shows only MRs submitted longer ago than this: a Go
+	// duration or whole days (e.g., "4h", "2d").
+	OlderThan string `json:"older_than,omitempty"`
+
+	// Columns are the table's columns, in order (e.g., ["id", "branch",
+	// "age", "risk"]; default: the standard ones).
+	Columns []string `json:"columns,omitempty"`
+
+	// Sort orders each sub-queue: "queue" (the order the refinery works
+	// in, the default), "score", "priority", "age" (oldest first),
+	// "worker" or "risk" (highest first).
+	Sort string `json:"sort,omitempty"`
+
+	// Reverse reverses the order.
+	Reverse bool `json:"reverse,omitempty"`
+}
+
+// CommitMessageConfig is a rig's commit message convention. gt check
+// validates the commits on a branch against it, gt mq submit and gt done
+// refuse branches with commits that break it, and gt commit writes
+// messages that follow it.
+type CommitMessageConfig struct {
+	// Template is the subject line format. Variables: {type}, {issue} (the
+	// issue ID) and {summary}, which is required. Default:
+	// "{summary} ({issue})".
+	Template string `json:"template,omitempty"`
+
+	// Types are the allowed {type} prefixes (e.g., "feat", "fix", "docs").
+	// Empty allows any lowercase word.
+	Types []string `json:"types,omitempty"`
+
+	// MaxSubjectLength caps the subject line's length (0 = no limit).
+	MaxSubjectLength int `json:"max_subject_length,omitempty"`
+}
+
+// MergePolicyRule is one merge policy. Deny is an expression over the MR's
+// attributes, e.g. `lines > 500 && approvals < 2`; while it is true the MR
+// may not merge.
+type MergePolicyRule struct {
+	// Name identifies the policy in explanations (e.g., "big-diffs").
+	Name string `json:"name"`
+
+	// Deny is the expression that blocks the MR when true.
+	Deny string `json:"deny"`
+
+	// Message explains the policy to the MR's author (default: Deny).
+	Message string `json:"message,omitempty"`
+}
+
+// MergeSigningConfig configures merge commit signing. The key must be
+// usable by git in the refinery's clone (e.g., in its gpg keyring or
+// ssh-agent).
+type MergeSigningConfig struct {
+	// Format is the signature format: "gpg" (default) or "ssh".
+	Format string `json:"format,omitempty"`
+
+	// Key is the signing key: a gpg key ID, or for ssh a public key file
+	// or literal key (default: git's user.signingkey).
+	Key string `json:"key,omitempty"`
+}
+
+// MergeAttestationConfig configures merge attestations: a record of what
+// was merged, where, and on whose say-so (MR, checks, approvers, commit),
+// stored on the MR bead in its attestation field.
+type MergeAttestationConfig struct {
+	// GitNotes also attaches each attestation to its merge commit as a git
+	// note, pushed to origin.
+	GitNotes bool `json:"git_notes,omitempty"`
+
+	// NotesRef is the notes ref attestations are written to (default
+	// "refs/notes/gastown/attestations").
+	NotesRef string `json:"notes_ref,omitempty"`
+}
+
+// MergeTargetConfig is an extra branch the merge queue merges into.
+type MergeTargetConfig struct {
+	// Branch is the target branch (e.g., "release/1.2").
+	Branch string `json:"branch"`
+
+	// Backport makes the refinery submit a cherry-pick MR onto Branch for
+	// each MR merged into the rig's default branch.
+	Backport bool `json:"backport,omitempty"`
+}
+
+// MergeLockConfig is a path lock: a hot path only one MR at a time may be
+// merging changes to.
+type MergeLockConfig struct {
+	// Name identifies the lock (e.g., "schema").
+	Name string `json:"name"`
+
+	// Paths are the lock's files, as globs in forbidden_paths syntax
+	// ("db/migrations/**").
+	Paths []string `json:"paths"`
+}
+
+// MergeProjectConfig is a sub-project of a monorepo rig. An MR belongs to
+// the first project whose paths cover every file it changes; MRs spanning
+// projects, or touching files outside them, are the rig's.
+type MergeProjectConfig struct {
+	// Name identifies the project (e.g., "docs").
+	Name string `json:"name"`
+
+	// Paths are the project's files, as globs in forbidden_paths syntax
+	// ("docs/**", "*.md").
+	Paths []string `json:"paths"`
+
+	// TestCommand tests the project's MRs instead of the merge queue's
+	// test_command (default: the merge queue's).
+	TestCommand string `json:"test_command,omitempty"`
+
+	// SkipTests runs no tests on the project's MRs.
+	SkipTests bool `json:"skip_tests,omitempty"`
+
+	// SLA replaces merge_queue.sla for the project's MRs (nil = the merge
+	// queue's).
+	SLA *MergeSLAConfig `json:"sla,omitempty"`
+
+	// Queue gives the project's MRs a sub-queue of their own in each
+	// target, so they don't wait behind the rest of the rig's.
+	Queue bool `json:"queue,omitempty"`
+}
+
+// MergeScheduleConfig restricts when the refinery may merge.
+// Window specs take the form "[days] HH:MM-HH:MM", where days is a comma
+// list of mon..sun, a range like "mon-fri", or "weekdays"/"weekends"/"daily".
+// A window whose end is before its start wraps past midnight.
+type MergeScheduleConfig struct {
+	// Windows are the times merges are allowed (e.g., "weekdays 09:00-17:00").
+	// Empty means merges are allowed any time outside quiet hours.
+	Windows []string `json:"windows,omitempty"`
+
+	// QuietHours are times merges are never allowed (e.g., "22:00-06:00").
+	QuietHours []string `json:"quiet_hours,omitempty"`
+
+	// Timezone is the IANA zone windows are evaluated in (default: local time).
+	Timezone string `json:"timezone,omitempty"`
+
+	// P0Override lets P0 (urgent) MRs merge outside the schedule.
+	P0Override bool `json:"p0_override,omitempty"`
+}
+
+// MergeFairnessConfig keeps one worker from monopolizing the merge queue.
+type MergeFairnessConfig struct {
+	// Policy orders MRs: "priority" (default) merges by score; "round_robin"
+	// takes one MR from each worker in turn within each priority level.
+	Policy string `json:"policy,omitempty"`
+
+	// AgingHours raises an MR one priority level for each this many hours it
+	// has waited, up to P0 (0 = no aging).
+	AgingHours float64 `json:"aging_hours,omitempty"`
+
+	// MaxInFlightPerWorker caps how many of one worker's MRs the refinery
+	// may have claimed at once; the rest wait (0 = no limit).
+	MaxInFlightPerWorker int `json:"max_in_flight_per_worker,omitempty"`
+}
+
+// MergeSLAConfig sets merge time targets for the merge queue. An open MR
+// older than its priority's target has breached its SLA: gt mq list flags
+// it, and the daemon logs an mr_sla_breached event and mails Notify.
+type MergeSLAConfig struct {
+	// Targets maps priorities to the longest an MR may wait from submission
+	// to merge, e.g. {"P0": "1h", "P2": "1d"}. Durations are Go durations
+	// or whole days ("2d"). Priorities without a target have no SLA.
+	Targets map[string]string `json:"targets,omitempty"`
+
+	// Notify is the mail address told about breaches (default "mayor/").
+	Notify string `json:"notify,omitempty"`
+}
+
+// TransactionalMergeConfig configures transactional merges. 'gt mq land'
+// stages the merged result on a temporary ref, runs VerifyCommand against
+// it, and fast-forwards the target only if it passes. If verification fails
+// nothing lands and the MR is marked failed.
+type TransactionalMergeConfig struct {
+	// VerifyCommand is run in a checkout of the merged result (default: the
+	// merge queue's test_command).
+	VerifyCommand string `json:"verify_command,omitempty"`
+
+	// Timeout bounds a verification run (e.g., "20m"; default: no limit).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// BranchProtectionConfig holds rules an MR must satisfy before the refinery
+// merges it. gt mq submit checks the rules it can (paths, diff size) up front;
+// the refinery checks all of them before merging.
+type BranchProtectionConfig struct {
+	// RequiredChecks are check names that must have passed (recorded in the
+	// MR's checks_passed field, e.g. via 'gt mq verify --check tests').
+	RequiredChecks []string `json:"required_checks,omitempty"`
+
+	// MinApprovals is the number of distinct approvals the MR needs before
+	// the refinery will process it.
+	MinApprovals int `json:"min_approvals,omitempty"`
+
+	// ApprovalRoles limits whose approvals count toward MinApprovals. Entries
+	// are roles ("mayor", "human", "crew", "witness", "deacon", "chatops") or
+	// specific addresses ("greenplace/crew/max"). Empty means any approver
+	// counts except approvals made through chatops, which count only when
+	// listed.
+	ApprovalRoles []string `json:"approval_roles,omitempty"`
+
+	// Reviewers is the pool of addresses gt mq submit assigns reviews to
+	// when MinApprovals > 0 (e.g., "mayor", "greenplace/crew/max").
+	Reviewers []string `json:"reviewers,omitempty"`
+
+	// ReviewAssignment picks reviewers from the pool: "round_robin" (default)
+	// or "load" (fewest open assigned reviews first).
+	ReviewAssignment string `json:"review_assignment,omitempty"`
+
+	// ForbiddenPaths are glob patterns no MR may touch (e.g., ".github/**", "*.pem").
+	ForbiddenPaths []string `json:"forbidden_paths,omitempty"`
+
+	// MaxDiffLines caps added plus deleted lines (0 = no limit).
+	MaxDiffLines int `json:"max_diff_lines,omitempty"`
+}
+
+// MergeRiskConfig tunes the heuristic risk score shown for queued MRs.
+type MergeRiskConfig struct {
+	// HotPaths are globs for fragile or critical code (e.g., "internal/auth/**",
+	// "*.sql"). Touching them raises an MR's risk score.
+	HotPaths []string `json:"hot_paths,omitempty"`
+
+	// HighThreshold is the score (0-100) at which an MR counts as high risk
+	// (default 60).
+	HighThreshold int `json:"high_threshold,omitempty"`
+
+	// RequireApproval holds high-risk MRs until they have at least one
+	// approval, even when all checks pass.
+	RequireApproval bool `json:"require_approval,omitempty"`
+}
+
+// MRDescriptionConfig is the template gt mq submit fills in for each MR's
+// description, so reviewers get the same context on every MR. The
+// description is markdown with one "## <section>" heading per section.
+type MRDescriptionConfig struct {
+	// Sections are the description's headings, in order (e.g., "Summary",
+	// "Testing", "Risk").
+	Sections []string `json:"sections,omitempty"`
+
+	// Required sections must be filled in; gt mq submit refuses an MR that
+	// leaves one empty. Each must also be listed in Sections.
+	Required []string `json:"required,omitempty"`
+}
+
+// FlakyChecksConfig tunes flaky check detection. The refinery keeps each
+// check's recent runs; a run that failed and then passed on retry is a
+// flake. When a check fails, it is retried if its flake rate is at least
+// Threshold, or once if it has too little history to judge.
+type FlakyChecksConfig struct {
+	// Threshold is the flake rate (0-1) at which a check's failures are
+	// retried (default 0.05).
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// MaxRetries is how many times a flaky check is retried (default 2;
+	// -1 never retries, but runs are still tracked).
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// MinRuns is the history a check needs before its flake rate is
+	// trusted; until then a failure is retried once (default 10).
+	MinRuns int `json:"min_runs,omitempty"`
+
+	// Window is how many recent runs are kept per check (default 200).
+	Window int `json:"window,omitempty"`
+}
+
+// CheckRunnerConfig sets how the refinery runs a check command.
+type CheckRunnerConfig struct {
+	// Runner is where the command runs: "shell" (the default: the town
+	// host's shell), "docker:<image>" (a container of image, with the
+	// checkout mounted at the same path) or "nix" (the checkout's flake dev
+	// shell; "nix:<flake>" names another).
+	Runner string `json:"runner,omitempty"`
+
+	// CPUs and Memory limit a docker run (e.g., 2 and "4g"; default: no
+	// limit). Other runners can't enforce them.
+	CPUs   float64 `json:"cpus,omitempty"`
+	Memory string  `json:"memory,omitempty"`
+
+	// Timeout bounds a run (e.g., "20m"; default: no limit, or the
+	// transactional timeout for verify).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// MergeArtifactsConfig sets what the refinery keeps from the check runs
+// on each MR, under <rig>/.runtime/artifacts/<mr-id>/.
+type MergeArtifactsConfig struct {
+	// Paths are files in the checkout to keep after each check run (junit
+	// XML, coverage reports, build outputs), in forbidden_paths syntax
+	// ("junit.xml", "coverage/**", "dist/*.tar.gz").
+	Paths []string `json:"paths,omitempty"`
+
+	// Retention is how long an MR's artifacts are kept after its last
+	// check run: a Go duration or whole days (default "14d").
+	Retention string `json:"retention,omitempty"`
+}
+
+// MergeCoverageConfig sets the refinery's coverage gate.
+type MergeCoverageConfig struct {
+	// Command writes a coverage report to File (e.g., "go test
+	// -coverprofile=coverage.out ./..."). It runs on the target and on each
+	// MR's merged result, under merge_queue.checks.coverage.
+	Command string `json:"command"`
+
+	// File is the report, relative to the checkout: a Go cover profile,
+	// an lcov tracefile, a Cobertura XML report or a bare percentage.
+	File string `json:"file"`
+
+	// MaxDrop is how many percentage points coverage may drop before a
+	// merge is blocked (default 0: any drop).
+	MaxDrop float64 `json:"max_drop,omitempty"`
+}
+
+// LinterConfig is a linter the refinery runs on MRs.
+type LinterConfig struct {
+	// Name identifies the linter in findings (e.g., "golangci-lint").
+	Name string `json:"name"`
+
+	// Command runs the linter on the checkout. It runs under
+	// merge_queue.checks.lint.
+	Command string `json:"command"`
+
+	// Format is how Command reports findings: "line" (the default:
+	// "path:line[:col]: message" lines, as go vet, golangci-lint, eslint -f
+	// unix, shellcheck -f gcc and ruff print them) or "sarif".
+	Format string `json:"format,omitempty"`
+}
+
+// OwnershipRule assigns owners to the files matching a path glob. As in
+// CODEOWNERS, the last rule matching a file decides its owners, and an
+// approval from any one of them signs off for those files.
+type OwnershipRule struct {
+	// Path is a glob in forbidden_paths syntax ("internal/auth/**",
+	// "*.sql").
+	Path string `json:"path"`
+
+	// Owners are addresses (e.g., "greenplace/crew/max") or approval
+	// roles ("mayor", "human", "crew", ...). An empty list leaves matching
+	// files unowned.
+	Owners []string `json:"owners"`
 }
 
 // OnConflict strategy constants.
@@ -812,6 +1527,59 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Email delivers mail to people outside the town over SMTP: mail sent
+	// to an email address, and copies of town mail matching Email.Routes
+	// (nil = no email).
+	Email *EmailConfig `json:"email,omitempty"`
+}
+
+// EmailConfig configures SMTP delivery for humans who don't live in the
+// town.
+type EmailConfig struct {
+	SMTP SMTPConfig `json:"smtp"`
+
+	// From is the sender address, e.g. "Gas Town <gastown@acme.com>".
+	From string `json:"from"`
+
+	// Routes copy town mail to email addresses. A message is copied to the
+	// addresses of every route it matches.
+	Routes []EmailRoute `json:"routes,omitempty"`
+}
+
+// SMTP security modes.
+const (
+	SMTPStartTLS = "starttls" // Plain connection upgraded with STARTTLS (default)
+	SMTPTLS      = "tls"      // TLS from the start (usually port 465)
+	SMTPNone     = "none"     // No encryption (local relays only)
+)
+
+// SMTPConfig is the server email is sent through.
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`     // Default 587, or 465 with "tls"
+	Username string `json:"username,omitempty"` // No authentication if empty
+	Password string `json:"password,omitempty"` // Or "secret:<name>"
+	Security string `json:"security,omitempty"` // "starttls" (default), "tls" or "none"
+}
+
+// EmailRoute copies town mail matching all its conditions to email
+// addresses. Empty conditions match everything.
+type EmailRoute struct {
+	// To are the email addresses copied.
+	To []string `json:"to"`
+
+	// Recipients are the town addresses whose mail is copied, e.g.
+	// "mayor/", "overseer" or "gastown/polecats/*".
+	Recipients []string `json:"recipients,omitempty"`
+
+	// Subjects are subject prefixes copied, e.g. "MR_SLA_BREACH" or
+	// "Digest".
+	Subjects []string `json:"subjects,omitempty"`
+
+	// Priorities are the mail priorities copied: "urgent", "high",
+	// "normal" or "low".
+	Priorities []string `json:"priorities,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
@@ -884,7 +1652,7 @@ type EscalationConfig struct {
 type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
-	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action, or "secret:<name>"
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
@@ -944,3 +1712,109 @@ func NewEscalationConfig() *EscalationConfig {
 		MaxReescalations: 2,
 	}
 }
+
+// WebhooksConfig defines outbound webhooks fed by the town's event log
+// (settings/webhooks.json).
+type WebhooksConfig struct {
+	Type    string `json:"type"`    // "webhooks"
+	Version int    `json:"version"` // schema version
+
+	Endpoints []WebhookEndpoint `json:"endpoints"`
+}
+
+// CurrentWebhooksVersion is the current schema version for WebhooksConfig.
+const CurrentWebhooksVersion = 1
+
+// WebhookEndpoint posts events to one URL, with a payload rendered from a
+// Go template over the event JSON.
+type WebhookEndpoint struct {
+	// Name identifies the endpoint (in gt webhook and its delivery state).
+	Name string `json:"name"`
+
+	// URL receives the events, or "secret:<name>".
+	URL string `json:"url"`
+
+	// Method is the HTTP method (default POST).
+	Method string `json:"method,omitempty"`
+
+	// Headers are sent with each request; values may be "secret:<name>".
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ContentType of the rendered payload (default application/json).
+	ContentType string `json:"content_type,omitempty"`
+
+	// Filter selects the events sent, as key=value expressions like
+	// gt events --filter (e.g. "type=merge_failed,mr_sla_breached").
+	// Empty sends every event.
+	Filter []string `json:"filter,omitempty"`
+
+	// Template renders the payload from the event, e.g.
+	// {"text": {{printf "%s: %s" .type .actor | json}}}. Empty sends the
+	// event JSON as is.
+	Template string `json:"template,omitempty"`
+
+	// TemplateFile reads the template from a file instead, relative to
+	// the town's settings/ directory.
+	TemplateFile string `json:"template_file,omitempty"`
+
+	// Disabled stops deliveries without removing the endpoint.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// NewWebhooksConfig creates an empty WebhooksConfig.
+func NewWebhooksConfig() *WebhooksConfig {
+	return &WebhooksConfig{
+		Type:    "webhooks",
+		Version: CurrentWebhooksVersion,
+	}
+}
+
+// ChatOpsConfig authorizes chat bots and slash commands to operate the town
+// through the dashboard's /api/chatops endpoint (settings/chatops.json).
+type ChatOpsConfig struct {
+	Type    string `json:"type"`    // "chatops"
+	Version int    `json:"version"` // schema version
+
+	Tokens []ChatOpsToken `json:"tokens"`
+}
+
+// CurrentChatOpsVersion is the current schema version for ChatOpsConfig.
+const CurrentChatOpsVersion = 1
+
+// ChatOps scopes: what a token may do.
+const (
+	ChatOpsScopeStatus  = "status"      // status [rig]
+	ChatOpsScopeRetry   = "mr:retry"    // retry <rig> <mr-id>
+	ChatOpsScopeApprove = "mr:approve"  // approve <mr-id>
+	ChatOpsScopePause   = "queue:pause" // pause <rig>, resume <rig>
+)
+
+// ChatOpsScopes lists the valid scopes.
+var ChatOpsScopes = []string{ChatOpsScopeStatus, ChatOpsScopeRetry, ChatOpsScopeApprove, ChatOpsScopePause}
+
+// ChatOpsToken is a credential for the chatops endpoint.
+type ChatOpsToken struct {
+	// Name identifies the token in the audit log, e.g. "slack".
+	Name string `json:"name"`
+
+	// Token is a "secret:<name>" reference to the shared token, sent as
+	// "Authorization: Bearer <token>" or a form "token" field (as Slack
+	// slash commands do).
+	Token string `json:"token"`
+
+	// Scopes are the commands the token may run.
+	Scopes []string `json:"scopes"`
+}
+
+// Allows reports whether the token has a scope.
+func (t *ChatOpsToken) Allows(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// NewChatOpsConfig creates an empty ChatOpsConfig.
+func NewChatOpsConfig() *ChatOpsConfig {
+	return &ChatOpsConfig{
+		Type:    "chatops",
+		Version: CurrentChatOpsVersion,
+	}
+}
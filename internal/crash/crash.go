@@ -0,0 +1,268 @@
+// Package crash captures forensic bundles for agent sessions that die
+// unexpectedly.
+//
+// A bundle is a directory under <town>/.runtime/crashes/ holding the last
+// lines of the session's output, the git status of the session's worktree,
+// and the most recent town events, alongside a report.json describing the
+// crash.
+// Bundles stay pending until someone reviews and acknowledges them.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/terminal"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Bundle file names.
+const (
+	ReportFile    = "report.json"
+	PaneFile      = "pane.txt"
+	GitStatusFile = "git-status.txt"
+	EventsFile    = "events.jsonl"
+)
+
+// Capture defaults.
+const (
+	DefaultPaneLines  = 200
+	DefaultEventCount = 50
+)
+
+// Report describes one captured crash.
+type Report struct {
+	ID         string     `json:"id"`
+	Agent      string     `json:"agent"`
+	Session    string     `json:"session,omitempty"`
+	ExitCode   int        `json:"exit_code"`
+	Reason     string     `json:"reason,omitempty"`
+	WorkDir    string     `json:"work_dir,omitempty"`
+	CapturedAt time.Time  `json:"captured_at"`
+	Reviewed   bool       `json:"reviewed"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+
+	// Files lists the bundle files that were captured; Errors records what
+	// could not be (e.g. the pane was already gone).
+	Files  []string `json:"files"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Options controls what Capture collects.
+type Options struct {
+	Agent    string // Gas Town agent identity (e.g., "greenplace/polecats/Toast")
+	Session  string // session name; the pane is skipped when empty
+	ExitCode int
+	Reason   string
+
+	// WorkDir is the worktree to record git status for. When empty, the
+	// pane's current directory is used.
+	WorkDir string
+
+	PaneLines  int // default DefaultPaneLines
+	EventCount int // default DefaultEventCount
+}
+
+// Dir returns the directory holding a town's crash bundles.
+func Dir(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "crashes")
+}
+
+// Capture writes a crash bundle for a dead session. It collects whatever it
+// can: a pane that has already closed or a workdir that is not a git repo is
+// noted in Report.Errors rather than failing the capture.
+func Capture(townRoot string, opts Options) (*Report, error) {
+	if opts.PaneLines <= 0 {
+		opts.PaneLines = DefaultPaneLines
+	}
+	if opts.EventCount <= 0 {
+		opts.EventCount = DefaultEventCount
+	}
+
+	now := time.Now().UTC()
+	report := &Report{
+		ID:         reportID(now, opts.Agent),
+		Agent:      opts.Agent,
+		Session:    opts.Session,
+		ExitCode:   opts.ExitCode,
+		Reason:     opts.Reason,
+		WorkDir:    opts.WorkDir,
+		CapturedAt: now,
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating crash directory: %w", err)
+	}
+	// Two crashes of one agent within a second get distinct bundles
+	dir := filepath.Join(Dir(townRoot), report.ID)
+	for n := 2; ; n++ {
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating crash bundle: %w", err)
+		}
+		report.ID = fmt.Sprintf("%s-%d", reportID(now, opts.Agent), n)
+		dir = filepath.Join(Dir(townRoot), report.ID)
+	}
+
+	addFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		report.Files = append(report.Files, name)
+	}
+
+	if opts.Session != "" {
+		backend, err := terminal.ForTown(townRoot)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("pane: %v", err))
+		} else if pane, err := backend.Capture(opts.Session, opts.PaneLines); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("pane: %v", err))
+		} else {
+			addFile(PaneFile, pane+"\n")
+		}
+		if tb, ok := backend.(*terminal.TmuxBackend); ok && report.WorkDir == "" {
+			report.WorkDir, _ = tb.Tmux().GetPaneWorkDir(opts.Session)
+		}
+	}
+
+	if report.WorkDir != "" {
+		if status, err := git.NewGit(report.WorkDir).StatusShort(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("git status: %v", err))
+		} else {
+			addFile(GitStatusFile, status+"\n")
+		}
+	}
+
+	if recent, _, err := events.ReadAll(events.Path(townRoot), nil); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("events: %v", err))
+	} else {
+		if len(recent) > opts.EventCount {
+			recent = recent[len(recent)-opts.EventCount:]
+		}
+		var sb strings.Builder
+		for _, e := range recent {
+			data, _ := json.Marshal(e)
+			sb.Write(data)
+			sb.WriteByte('\n')
+		}
+		addFile(EventsFile, sb.String())
+	}
+
+	if err := save(townRoot, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// List returns all crash reports in the town, newest first. Bundles with a
+// missing or unreadable report.json are skipped.
+func List(townRoot string) ([]*Report, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading crash reports: %w", err)
+	}
+
+	var reports []*Report
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if r, err := Load(townRoot, entry.Name()); err == nil {
+			reports = append(reports, r)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].CapturedAt.After(reports[j].CapturedAt)
+	})
+	return reports, nil
+}
+
+// Pending returns the crash reports that have not been acknowledged, newest
+// first.
+func Pending(townRoot string) ([]*Report, error) {
+	all, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Report
+	for _, r := range all {
+		if !r.Reviewed {
+			pending = append(pending, r)
+		}
+	}
+	return pending, nil
+}
+
+// Load reads one crash report by ID.
+func Load(townRoot, id string) (*Report, error) {
+	if id == "" || id != filepath.Base(id) {
+		return nil, fmt.Errorf("invalid crash report ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(Dir(townRoot), id, ReportFile)) //nolint:gosec // G304: path is within the town's crash directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("crash report %s not found", id)
+		}
+		return nil, fmt.Errorf("reading crash report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing crash report %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Acknowledge marks a crash report as reviewed so it is no longer pending.
+// The bundle itself is kept.
+func Acknowledge(townRoot, id string) (*Report, error) {
+	r, err := Load(townRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Reviewed {
+		return r, nil
+	}
+	now := time.Now().UTC()
+	r.Reviewed = true
+	r.ReviewedAt = &now
+	return r, save(townRoot, r)
+}
+
+// BundlePath returns the directory of a crash report's bundle.
+func BundlePath(townRoot, id string) string {
+	return filepath.Join(Dir(townRoot), id)
+}
+
+func save(townRoot string, r *Report) error {
+	if err := util.AtomicWriteJSON(filepath.Join(Dir(townRoot), r.ID, ReportFile), r); err != nil {
+		return fmt.Errorf("writing crash report: %w", err)
+	}
+	return nil
+}
+
+var unsafeIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// reportID builds a sortable, filesystem-safe ID such as
+// "20261015T120304-greenplace-polecats-Toast".
+func reportID(at time.Time, agent string) string {
+	name := strings.Trim(unsafeIDChars.ReplaceAllString(agent, "-"), "-")
+	if name == "" {
+		name = "unknown"
+	}
+	return at.Format("20060102T150405") + "-" + name
+}
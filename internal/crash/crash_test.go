@@ -0,0 +1,106 @@
+package crash
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestCaptureAndAcknowledge(t *testing.T) {
+	townRoot := t.TempDir()
+
+	workDir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", workDir).Run(); err != nil {
+		t.Skipf("git init: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "wip.go"), []byte("package wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(events.Path(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []string{events.TypeSpawn, events.TypeSling, events.TypeHook} {
+		data, _ := json.Marshal(events.Event{Type: typ})
+		_, _ = f.Write(append(data, '\n'))
+	}
+	f.Close()
+
+	r, err := Capture(townRoot, Options{
+		Agent:      "greenplace/polecats/Toast",
+		ExitCode:   1,
+		WorkDir:    workDir,
+		EventCount: 2,
+	})
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if !strings.HasSuffix(r.ID, "-greenplace-polecats-Toast") {
+		t.Errorf("ID = %q", r.ID)
+	}
+	if want := []string{GitStatusFile, EventsFile}; strings.Join(r.Files, ",") != strings.Join(want, ",") {
+		t.Errorf("Files = %v, want %v", r.Files, want)
+	}
+
+	dir := BundlePath(townRoot, r.ID)
+	status, _ := os.ReadFile(filepath.Join(dir, GitStatusFile))
+	if !strings.Contains(string(status), "?? wip.go") {
+		t.Errorf("git status = %q, want the untracked file", status)
+	}
+	evts, _, _ := events.ReadAll(filepath.Join(dir, EventsFile), nil)
+	if len(evts) != 2 || evts[0].Type != events.TypeSling {
+		t.Errorf("events = %+v, want the last two", evts)
+	}
+
+	// A second crash in the same second gets its own bundle
+	r2, err := Capture(townRoot, Options{Agent: "greenplace/polecats/Toast", ExitCode: 2})
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if r2.ID == r.ID {
+		t.Errorf("second capture reused ID %q", r.ID)
+	}
+
+	pending, err := Pending(townRoot)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Pending = %d reports, %v; want 2", len(pending), err)
+	}
+
+	if _, err := Acknowledge(townRoot, r.ID); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	pending, _ = Pending(townRoot)
+	if len(pending) != 1 || pending[0].ID != r2.ID {
+		t.Errorf("Pending after ack = %+v, want just %s", pending, r2.ID)
+	}
+	all, _ := List(townRoot)
+	if len(all) != 2 {
+		t.Errorf("List = %d reports, want 2 (ack keeps the bundle)", len(all))
+	}
+
+	if _, err := Load(townRoot, "../escape"); err == nil {
+		t.Error("Load accepted a path outside the crash directory")
+	}
+}
+
+func TestReportID(t *testing.T) {
+	at := time.Date(2026, 10, 15, 12, 3, 4, 0, time.UTC)
+	tests := map[string]string{
+		"greenplace/polecats/Toast": "20261015T120304-greenplace-polecats-Toast",
+		"mayor":                     "20261015T120304-mayor",
+		"":                          "20261015T120304-unknown",
+		"a b/../c":                  "20261015T120304-a-b-..-c",
+	}
+	for agent, want := range tests {
+		if got := reportID(at, agent); got != want {
+			t.Errorf("reportID(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/terminal"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -448,15 +449,28 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		return fmt.Errorf("getting crew worker: %w", err)
 	}
 
-	t := tmux.NewTmux()
+	backend, err := terminal.ForTown(filepath.Dir(m.rig.Path))
+	if err != nil {
+		return err
+	}
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists
-	running, err := t.HasSession(sessionID)
+	running, err := backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
-	if running {
+	tb, inTmux := backend.(*terminal.TmuxBackend)
+	if running && !inTmux {
+		// No way to tell a live agent from a zombie outside tmux
+		if !opts.KillExisting {
+			return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
+		}
+		if err := backend.KillSession(sessionID); err != nil {
+			return fmt.Errorf("killing existing session: %w", err)
+		}
+	} else if running {
+		t := tb.Tmux()
 		if opts.KillExisting {
 			// Restart mode - kill existing session.
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
@@ -511,13 +525,6 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		claudeCmd = strings.Replace(claudeCmd, " --dangerously-skip-permissions", "", 1)
 	}
 
-	// Create session with command directly to avoid send-keys race condition.
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
-
-	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "crew",
@@ -527,6 +534,29 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		RuntimeConfigDir: opts.ClaudeConfigDir,
 		BeadsNoDaemon:    true,
 	})
+
+	if !inTmux {
+		// Other backends only pass variables to sessions started afterwards,
+		// and have no terminal to theme.
+		for k, v := range envVars {
+			if err := backend.SetEnvironment(sessionID, k, v); err != nil {
+				return fmt.Errorf("setting session environment: %w", err)
+			}
+		}
+		if err := backend.NewSession(sessionID, worker.ClonePath, claudeCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+	t := tb.Tmux()
+
+	// Create session with command directly to avoid send-keys race condition.
+	// See: https://github.com/anthropics/gastown/issues/280
+	if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+
+	// Set environment variables (non-fatal: session works without these)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
@@ -552,11 +582,14 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	backend, err := terminal.ForTown(filepath.Dir(m.rig.Path))
+	if err != nil {
+		return err
+	}
 	sessionID := m.SessionName(name)
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -565,9 +598,9 @@ func (m *Manager) Stop(name string) error {
 	}
 
 	// Kill the session.
-	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := t.KillSessionWithProcesses(sessionID); err != nil {
+	// The backend kills all descendant processes too. This prevents orphan
+	// bash processes from Claude's Bash tool surviving session termination.
+	if err := backend.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -576,8 +609,10 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
-	sessionID := m.SessionName(name)
-	return t.HasSession(sessionID)
+	backend, err := terminal.ForTown(filepath.Dir(m.rig.Path))
+	if err != nil {
+		return false, err
+	}
+	return backend.HasSession(m.SessionName(name))
 }
 
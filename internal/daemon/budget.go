@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// budgetWarnInterval is how often a blocked budget is re-reported to the feed.
+const budgetWarnInterval = time.Hour
+
+// budgetAllows reports whether a session for role in rigName ("" for
+// town-level roles) may be started. Budgets come from town settings,
+// overridden by rig settings; usage is estimated from the event log. Blocked
+// starts are logged each time and reported to the feed once per
+// budgetWarnInterval. Unreadable settings or events never block a start.
+func (d *Daemon) budgetAllows(rigName, role string) bool {
+	townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil {
+		d.logger.Printf("Warning: loading town settings for budgets: %v", err)
+		return true
+	}
+	var rigSettings *config.RigSettings
+	if rigName != "" {
+		// Missing rig settings just means no rig overrides
+		rigSettings, _ = config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName)))
+	}
+	limits := budget.For(townSettings, rigSettings, role)
+	if limits == (config.SessionBudget{}) {
+		return true
+	}
+
+	evts, _, err := events.ReadAll(filepath.Join(d.config.TownRoot, events.EventsFile), nil)
+	if err != nil {
+		d.logger.Printf("Warning: reading events for budgets: %v", err)
+		return true
+	}
+	now := time.Now()
+	usage := budget.UsageFor(budget.Spans(evts, now), rigName, role, now)
+
+	err = budget.Check(limits, usage, rigName, role)
+	var exceeded *budget.ExceededError
+	if !errors.As(err, &exceeded) {
+		return true
+	}
+
+	d.logger.Printf("Not starting %s session: %v", role, err)
+	if d.budgetWarned == nil {
+		d.budgetWarned = map[string]time.Time{}
+	}
+	key := rigName + "/" + role + "/" + exceeded.Limit
+	if last, ok := d.budgetWarned[key]; !ok || now.Sub(last) >= budgetWarnInterval {
+		d.budgetWarned[key] = now
+		_ = events.LogFeed(events.TypeBudgetExceeded, "daemon",
+			events.BudgetExceededPayload(rigName, role, exceeded.Limit, exceeded.Used, exceeded.Max))
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func budgetTestDaemon(t *testing.T, budgets map[string]*config.SessionBudget, starts int) *Daemon {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Budgets = budgets
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for i := 0; i < starts; i++ {
+		data, _ := json.Marshal(events.Event{
+			Timestamp: time.Now().Add(-time.Duration(i+1) * time.Minute).UTC().Format(time.RFC3339),
+			Type:      events.TypeSessionStart,
+			Actor:     "greenplace/witness",
+		})
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalWd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalWd) })
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+	}
+}
+
+func TestBudgetAllowsWithoutBudget(t *testing.T) {
+	d := budgetTestDaemon(t, nil, 5)
+	if !d.budgetAllows("greenplace", "witness") {
+		t.Error("no budget configured should allow starts")
+	}
+}
+
+func TestBudgetAllowsBlocksRespawns(t *testing.T) {
+	d := budgetTestDaemon(t, map[string]*config.SessionBudget{
+		"witness": {MaxRespawnsPerHour: 3},
+	}, 3)
+
+	if d.budgetAllows("greenplace", "witness") {
+		t.Fatal("fourth witness start in an hour should be blocked")
+	}
+	if !d.budgetAllows("otherrig", "witness") {
+		t.Error("budgets apply per rig")
+	}
+
+	// Reported to the feed once per interval
+	_ = d.budgetAllows("greenplace", "witness")
+	evts, _, err := events.ReadAll(filepath.Join(d.config.TownRoot, events.EventsFile),
+		events.Filter{"type": {events.TypeBudgetExceeded}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evts) != 1 {
+		t.Errorf("budget_exceeded events = %d, want 1", len(evts))
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -58,6 +59,14 @@ type Daemon struct {
 	// See: https://github.com/steveyegge/gastown/issues/567
 	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
 	deaconLastStarted time.Time
+
+	// budgetWarned records when each blocked budget was last reported to the
+	// feed. Only accessed from heartbeat loop goroutine - no sync needed.
+	budgetWarned map[string]time.Time
+
+	// slaAlerted holds, per rig, the MRs already reported as past their
+	// merge SLA. Only accessed from heartbeat loop goroutine - no sync needed.
+	slaAlerted map[string]map[string]bool
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -156,12 +165,14 @@ func (d *Daemon) Run() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
-	// Normal wake is handled by feed subscription (bd activity --follow)
-	timer := time.NewTimer(recoveryHeartbeatInterval)
-	defer timer.Stop()
+	// Each task runs on its own interval; the heartbeat ticks often enough
+	// to run them when due. Normal wake is handled by feed subscription
+	// (bd activity --follow).
+	d.scheduleTasks(state)
+	ticker := time.NewTicker(taskTickInterval)
+	defer ticker.Stop()
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	d.logger.Printf("Daemon running, checking for due tasks every %v", taskTickInterval)
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
@@ -205,28 +216,27 @@ func (d *Daemon) Run() error {
 			if isLifecycleSignal(sig) {
 				// Lifecycle signal: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received lifecycle signal, processing lifecycle requests immediately")
-				d.processLifecycleRequests()
+				if t, ok := FindTask("lifecycle"); ok {
+					d.recordTaskRun(state, d.runTask(t, TriggerSignal))
+				}
 			} else {
 				d.logger.Printf("Received signal %v, shutting down", sig)
 				return d.shutdown(state)
 			}
 
-		case <-timer.C:
+		case <-ticker.C:
 			d.heartbeat(state)
-
-			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
 		}
 	}
 }
 
-// recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
+// recoveryHeartbeatInterval is the default interval of the daemon's tasks.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
 const recoveryHeartbeatInterval = 3 * time.Minute
 
-// heartbeat performs one heartbeat cycle.
+// heartbeat runs the tasks that are due (see tasks).
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is the safety net for edge cases:
@@ -242,75 +252,10 @@ func (d *Daemon) heartbeat(state *State) {
 		return
 	}
 
-	d.logger.Println("Heartbeat starting (recovery-focused)")
-
-	// 0. Ensure Dolt server is running (if configured)
-	// This must happen before beads operations that depend on Dolt.
-	d.ensureDoltServerRunning()
-
-	// 1. Ensure Deacon is running (restart if dead)
-	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
-		d.ensureDeaconRunning()
-	} else {
-		d.logger.Printf("Deacon patrol disabled in config, skipping")
-	}
-
-	// 2. Poke Boot for intelligent triage (stuck/nudge/interrupt)
-	// Boot handles nuanced "is Deacon responsive" decisions
-	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
-		d.ensureBootRunning()
-	}
-
-	// 3. Direct Deacon heartbeat check (belt-and-suspenders)
-	// Boot may not detect all stuck states; this provides a fallback
-	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
-		d.checkDeaconHeartbeat()
-	}
-
-	// 4. Ensure Witnesses are running for all rigs (restart if dead)
-	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "witness") {
-		d.ensureWitnessesRunning()
-	} else {
-		d.logger.Printf("Witness patrol disabled in config, skipping")
-	}
-
-	// 5. Ensure Refineries are running for all rigs (restart if dead)
-	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "refinery") {
-		d.ensureRefineriesRunning()
-	} else {
-		d.logger.Printf("Refinery patrol disabled in config, skipping")
+	if d.runDueTasks(state, time.Now()) == 0 {
+		return
 	}
 
-	// 6. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
-	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
-	// Uses regex-based WaitForRuntimeReady, which is acceptable for daemon bootstrap.
-	d.triggerPendingSpawns()
-
-	// 7. Process lifecycle requests
-	d.processLifecycleRequests()
-
-	// 8. (Removed) Stale agent check - violated "discover, don't track"
-
-	// 9. Check for GUPP violations (agents with work-on-hook not progressing)
-	d.checkGUPPViolations()
-
-	// 10. Check for orphaned work (assigned to dead agents)
-	d.checkOrphanedWork()
-
-	// 11. Check polecat session health (proactive crash detection)
-	// This validates tmux sessions are still alive for polecats with work-on-hook
-	d.checkPolecatSessionHealth()
-
-	// 12. Clean up orphaned claude subagent processes (memory leak prevention)
-	// These are Task tool subagents that didn't clean up after completion.
-	// This is a safety net - Deacon patrol also does this more frequently.
-	d.cleanupOrphanedProcesses()
-
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -411,6 +356,9 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 // Uses deacon.Manager for consistent startup behavior (WaitForShellReady, GUPP, etc.).
 func (d *Daemon) ensureDeaconRunning() {
 	mgr := deacon.NewManager(d.config.TownRoot)
+	if running, _ := mgr.IsRunning(); !running && !d.budgetAllows("", budget.RoleDeacon) {
+		return
+	}
 
 	if err := mgr.Start(""); err != nil {
 		if err == deacon.ErrAlreadyRunning {
@@ -523,6 +471,9 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		Path: filepath.Join(d.config.TownRoot, rigName),
 	}
 	mgr := witness.NewManager(r)
+	if running, _ := mgr.IsRunning(); !running && !d.budgetAllows(rigName, budget.RoleWitness) {
+		return
+	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
 		if err == witness.ErrAlreadyRunning {
@@ -563,6 +514,9 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		Path: filepath.Join(d.config.TownRoot, rigName),
 	}
 	mgr := refinery.NewManager(r)
+	if running, _ := mgr.IsRunning(); !running && !d.budgetAllows(rigName, budget.RoleRefinery) {
+		return
+	}
 
 	if err := mgr.Start(false, ""); err != nil {
 		if err == refinery.ErrAlreadyRunning {
@@ -1096,6 +1050,9 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		return fmt.Errorf("cannot restart polecat: %s", reason)
 	}
+	if !d.budgetAllows(rigName, budget.RolePolecat) {
+		return fmt.Errorf("cannot restart polecat: %s/polecat session budget exceeded", rigName)
+	}
 
 	// Calculate rig path for agent config resolution
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
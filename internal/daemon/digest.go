@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// sendMayorDigest delivers the mail held for the Mayor once the oldest has
+// waited the town's digest interval. Mail left over from a digest that has
+// since been turned off goes out at once.
+func (d *Daemon) sendMayorDigest() {
+	digest, err := mail.LoadDigest(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: loading mayor_digest settings: %v", err)
+		return
+	}
+	due, err := mail.DigestDue(d.config.TownRoot, digest.Interval(), time.Now())
+	if err != nil {
+		d.logger.Printf("Warning: reading held Mayor mail: %v", err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	cmd := exec.Command("gt", "mail", "digest", "--send") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Warning: failed to send Mayor digest: %v: %s", err, out)
+	} else {
+		d.logger.Println("Sent Mayor digest")
+	}
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// archiveClosedMRs applies each rig's merge_queue.archive_after policy,
+// moving old closed MRs out of the beads database.
+func (d *Daemon) archiveClosedMRs() {
+	for _, rigName := range d.getKnownRigs() {
+		d.archiveRigClosedMRs(rigName)
+	}
+}
+
+// archiveRigClosedMRs archives a rig's MRs closed longer ago than its policy.
+func (d *Daemon) archiveRigClosedMRs(rigName string) {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	after, err := refinery.LoadArchiveAfter(rigPath)
+	if err != nil {
+		d.logger.Printf("Warning: loading MR archive policy for %s: %v", rigName, err)
+		return
+	}
+	if after == 0 {
+		return
+	}
+
+	bd := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath))
+	closed, err := bd.ListIndexed(beads.ListOptions{Type: "merge-request", Status: "closed", Priority: -1})
+	if err != nil {
+		d.logger.Printf("Warning: listing closed MRs for %s archive: %v", rigName, err)
+		return
+	}
+	old := refinery.ArchivableMRs(closed, time.Now().Add(-after))
+	if len(old) == 0 {
+		return
+	}
+	archived, err := bd.ArchiveMRs(old)
+	if err != nil {
+		d.logger.Printf("Warning: archiving closed MRs for %s: %v", rigName, err)
+	}
+	if len(archived) > 0 {
+		d.logger.Printf("Archived %d closed MR(s) in %s older than %v", len(archived), rigName, after)
+	}
+}
+
+// pruneMRArtifacts applies each rig's merge_queue.artifacts.retention,
+// removing the check logs and artifacts of MRs not checked since.
+func (d *Daemon) pruneMRArtifacts() {
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		store, err := refinery.LoadArtifactStore(rigPath)
+		if err != nil {
+			d.logger.Printf("Warning: loading MR artifact policy for %s: %v", rigName, err)
+			continue
+		}
+		pruned, err := store.Prune(time.Now())
+		if err != nil {
+			d.logger.Printf("Warning: pruning MR artifacts for %s: %v", rigName, err)
+		}
+		if len(pruned) > 0 {
+			d.logger.Printf("Pruned artifacts of %d MR(s) in %s older than %v", len(pruned), rigName, store.Retention())
+		}
+	}
+}
@@ -51,3 +51,18 @@ func TestIsPatrolEnabled_NilConfig(t *testing.T) {
 		t.Error("expected default to be enabled")
 	}
 }
+
+func TestIsPatrolEnabled_RigSyncOptIn(t *testing.T) {
+	// Rig sync changes clones, so it stays off unless enabled
+	if IsPatrolEnabled(nil, "rig_sync") {
+		t.Error("expected rig_sync to be disabled without config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "rig_sync") {
+		t.Error("expected rig_sync to be disabled by default")
+	}
+	config.Patrols.RigSync = &PatrolConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "rig_sync") {
+		t.Error("expected rig_sync to be enabled")
+	}
+}
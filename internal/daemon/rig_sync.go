@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// syncRigClones fetches into each rig's clones and fast-forwards their
+// default branch, as gt rig sync does, logging rigs that need a human.
+func (d *Daemon) syncRigClones() {
+	for _, rigName := range d.getKnownRigs() {
+		result := rig.Sync(filepath.Join(d.config.TownRoot, rigName))
+		for _, repo := range result.Repos {
+			switch {
+			case repo.Error != "":
+				d.logger.Printf("Warning: syncing %s %s: %s", rigName, repo.Path, repo.Error)
+			case repo.Diverged():
+				d.logger.Printf("Warning: %s %s has diverged from %s/%s (%d ahead, %d behind)",
+					rigName, repo.Path, result.Remote, result.Branch, repo.Ahead, repo.Behind)
+			case repo.Skipped != "":
+				d.logger.Printf("Warning: %s %s is %d behind %s/%s: %s",
+					rigName, repo.Path, repo.Behind, result.Remote, result.Branch, repo.Skipped)
+			case repo.Updated:
+				d.logger.Printf("Fast-forwarded %s %s to %s/%s", rigName, repo.Path, result.Remote, result.Branch)
+			}
+		}
+		if len(result.Detached) > 0 {
+			d.logger.Printf("Warning: %s has detached checkouts: %s", rigName, strings.Join(result.Detached, ", "))
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// checkMergeQueueSLAs reports MRs that have waited past their rig's merge SLA.
+func (d *Daemon) checkMergeQueueSLAs() {
+	for _, rigName := range d.getKnownRigs() {
+		d.checkRigMergeQueueSLA(rigName)
+	}
+}
+
+// checkRigMergeQueueSLA checks a rig's unmerged MRs against its SLA targets.
+func (d *Daemon) checkRigMergeQueueSLA(rigName string) {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	sla, err := refinery.LoadSLA(rigPath)
+	if err != nil {
+		d.logger.Printf("Warning: loading merge SLA for %s: %v", rigName, err)
+		return
+	}
+	if sla == nil {
+		return
+	}
+
+	bd := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath))
+	var mrs []*beads.Issue
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := bd.ListIndexed(beads.ListOptions{Type: "merge-request", Status: status, Priority: -1})
+		if err != nil {
+			d.logger.Printf("Warning: listing MRs for %s SLA check: %v", rigName, err)
+			return
+		}
+		mrs = append(mrs, issues...)
+	}
+
+	d.alertSLABreaches(rigName, sla, sla.Breaches(mrs, time.Now()))
+}
+
+// alertSLABreaches logs an mr_sla_breached event and mails the SLA contact
+// (the MR's project's, or the rig's) for each breach not already reported. An MR is reported again only
+// if it leaves breach (e.g., its priority changes) and re-enters it.
+func (d *Daemon) alertSLABreaches(rigName string, sla *refinery.SLA, breaches []refinery.SLABreach) {
+	if d.slaAlerted == nil {
+		d.slaAlerted = map[string]map[string]bool{}
+	}
+	alerted := d.slaAlerted[rigName]
+	current := make(map[string]bool, len(breaches))
+	for _, b := range breaches {
+		current[b.ID] = true
+		if alerted[b.ID] {
+			continue
+		}
+
+		overdue := b.Overdue.Round(time.Minute)
+		d.logger.Printf("SLA breach: %s (P%d) is %v past its %v merge target", b.ID, b.Priority, overdue, b.Target)
+		_ = events.LogFeed(events.TypeMRSLABreached, "daemon",
+			events.MRSLABreachPayload(rigName, b.ID, b.Worker, b.Priority, b.Target.String(), overdue.String()))
+		addr := b.Notify
+		if addr == "" {
+			addr = sla.Notify()
+		}
+		d.notifyOfSLABreach(rigName, addr, b)
+	}
+	d.slaAlerted[rigName] = current
+}
+
+// notifyOfSLABreach mails addr about an MR that has blown its merge SLA.
+func (d *Daemon) notifyOfSLABreach(rigName, addr string, b refinery.SLABreach) {
+	overdue := b.Overdue.Round(time.Minute)
+	subject := fmt.Sprintf("MR_SLA_BREACH: %s P%d overdue by %v", b.ID, b.Priority, overdue)
+	body := fmt.Sprintf(`Merge request %s in %s has waited past its merge SLA.
+
+priority: P%d
+target: %v
+overdue_by: %v
+worker: %s
+
+Action needed: Check the refinery is running and the MR isn't stuck (gt mq list %s).`,
+		b.ID, rigName, b.Priority, b.Target, overdue, b.Worker, rigName)
+
+	cmd := exec.Command("gt", "mail", "send", addr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify %s of SLA breach: %v", addr, err)
+	} else {
+		d.logger.Printf("Notified %s of SLA breach for %s", addr, b.ID)
+	}
+}
@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestAlertSLABreachesOncePerBreach(t *testing.T) {
+	d := budgetTestDaemon(t, nil, 0)
+	t.Setenv("PATH", t.TempDir()) // no gt: mail fails and is only logged
+
+	sla, err := refinery.NewSLA(&config.MergeSLAConfig{Targets: map[string]string{"P0": "1h"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breach := refinery.SLABreach{ID: "gt-1", Worker: "toast", Target: time.Hour, Overdue: 10 * time.Minute}
+	countEvents := func() int {
+		evts, _, err := events.ReadAll(filepath.Join(d.config.TownRoot, events.EventsFile),
+			events.Filter{"type": {events.TypeMRSLABreached}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(evts)
+	}
+
+	d.alertSLABreaches("greenplace", sla, []refinery.SLABreach{breach})
+	d.alertSLABreaches("greenplace", sla, []refinery.SLABreach{breach})
+	if n := countEvents(); n != 1 {
+		t.Fatalf("mr_sla_breached events = %d, want 1 while the breach persists", n)
+	}
+
+	// Cleared, then breached again
+	d.alertSLABreaches("greenplace", sla, nil)
+	d.alertSLABreaches("greenplace", sla, []refinery.SLABreach{breach})
+	if n := countEvents(); n != 2 {
+		t.Errorf("mr_sla_breached events = %d, want 2 after a new breach", n)
+	}
+}
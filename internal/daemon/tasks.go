@@ -0,0 +1,302 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// taskTickInterval is how often the daemon checks for due tasks. Task
+// intervals shorter than this run every tick.
+const taskTickInterval = 30 * time.Second
+
+// maxTaskLogSize is the size past which the task log is rotated to
+// tasks.jsonl.1 when the daemon starts.
+const maxTaskLogSize = 10 << 20
+
+// What started a task run.
+const (
+	TriggerSchedule = "schedule" // Its interval elapsed
+	TriggerSignal   = "signal"   // A lifecycle signal (lifecycle only)
+	TriggerRunOnce  = "run-once" // gt daemon run-once
+)
+
+// Outcomes of a task run.
+const (
+	TaskOK    = "ok"
+	TaskPanic = "panic"
+)
+
+// Task is one of the daemon's periodic behaviors. Each runs on its own
+// interval, which mayor/daemon.json's tasks.<name>.interval overrides.
+type Task struct {
+	Name     string
+	Summary  string
+	Interval time.Duration // Default interval
+
+	// Patrol, if set, is the patrol in mayor/daemon.json the task belongs
+	// to; it runs only while the patrol is enabled (see IsPatrolEnabled),
+	// unless tasks.<name>.enabled says otherwise.
+	Patrol string
+
+	run func(d *Daemon)
+}
+
+// tasks lists the daemon's tasks, in the order a tick runs those due.
+// The Dolt server comes first: the tasks after it use beads.
+var tasks = []Task{
+	{Name: "dolt", Summary: "Keep the Dolt SQL server running (if configured)", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).ensureDoltServerRunning},
+	{Name: "deacon", Summary: "Keep the Deacon running, with Boot to triage it", Interval: recoveryHeartbeatInterval, Patrol: "deacon",
+		run: func(d *Daemon) {
+			d.ensureDeaconRunning()
+			d.ensureBootRunning()
+			d.checkDeaconHeartbeat()
+		}},
+	{Name: "witnesses", Summary: "Keep every rig's Witness running", Interval: recoveryHeartbeatInterval, Patrol: "witness",
+		run: (*Daemon).ensureWitnessesRunning},
+	{Name: "refineries", Summary: "Keep every rig's Refinery running", Interval: recoveryHeartbeatInterval, Patrol: "refinery",
+		run: (*Daemon).ensureRefineriesRunning},
+	{Name: "spawns", Summary: "Nudge newly spawned polecats", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).triggerPendingSpawns},
+	{Name: "lifecycle", Summary: "Process cycle, restart and shutdown requests", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).processLifecycleRequests},
+	{Name: "stuck-workers", Summary: "Report stuck hooks and orphaned work; restart crashed polecats", Interval: recoveryHeartbeatInterval,
+		run: func(d *Daemon) {
+			d.checkGUPPViolations()
+			d.checkOrphanedWork()
+			d.checkPolecatSessionHealth()
+		}},
+	{Name: "orphan-processes", Summary: "Kill orphaned claude subagent processes", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).cleanupOrphanedProcesses},
+	{Name: "sla", Summary: "Report MRs waiting past their rig's merge SLA", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).checkMergeQueueSLAs},
+	{Name: "digest", Summary: "Send the Mayor's notification digest when due", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).sendMayorDigest},
+	{Name: "mr-archive", Summary: "Archive closed MRs and prune their check artifacts", Interval: recoveryHeartbeatInterval,
+		run: func(d *Daemon) {
+			d.archiveClosedMRs()
+			d.pruneMRArtifacts()
+		}},
+	{Name: "webhooks", Summary: "Post new events to outbound webhooks", Interval: taskTickInterval,
+		run: (*Daemon).dispatchWebhooks},
+	{Name: "tracker-sync", Summary: "Mirror rigs' work to their external issue trackers", Interval: recoveryHeartbeatInterval,
+		run: (*Daemon).syncTrackers},
+	{Name: "rig-sync", Summary: "Fetch and fast-forward rig clones", Interval: recoveryHeartbeatInterval, Patrol: "rig_sync",
+		run: (*Daemon).syncRigClones},
+}
+
+// Tasks returns the daemon's tasks, in the order they run.
+func Tasks() []Task {
+	return append([]Task(nil), tasks...)
+}
+
+// FindTask returns the task named name.
+func FindTask(name string) (Task, bool) {
+	for _, t := range tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Task{}, false
+}
+
+// TaskEnabled reports whether a task runs: as tasks.<name>.enabled says
+// if set, otherwise as its patrol is enabled. Tasks without a patrol run
+// by default.
+func TaskEnabled(config *DaemonPatrolConfig, t Task) bool {
+	if tc := config.task(t.Name); tc != nil && tc.Enabled != nil {
+		return *tc.Enabled
+	}
+	if t.Patrol != "" {
+		return IsPatrolEnabled(config, t.Patrol)
+	}
+	return true
+}
+
+// TaskInterval returns how often a task runs: tasks.<name>.interval if
+// set, otherwise its default.
+func TaskInterval(config *DaemonPatrolConfig, t Task) (time.Duration, error) {
+	tc := config.task(t.Name)
+	if tc == nil || tc.Interval == "" {
+		return t.Interval, nil
+	}
+	d, err := time.ParseDuration(tc.Interval)
+	if err != nil {
+		return t.Interval, fmt.Errorf("tasks.%s.interval: %w", t.Name, err)
+	}
+	if d <= 0 {
+		return t.Interval, fmt.Errorf("tasks.%s.interval %q: must be positive", t.Name, tc.Interval)
+	}
+	return d, nil
+}
+
+// TaskState is a task's schedule and latest run, kept in the daemon's state.
+type TaskState struct {
+	Enabled      bool          `json:"enabled"`
+	Interval     time.Duration `json:"interval"`
+	Runs         int64         `json:"runs"`
+	LastRun      time.Time     `json:"last_run,omitempty"`
+	LastDuration time.Duration `json:"last_duration,omitempty"`
+	LastStatus   string        `json:"last_status,omitempty"` // TaskOK or TaskPanic
+}
+
+// Due reports whether the task should run at now.
+func (s *TaskState) Due(now time.Time) bool {
+	return s.Enabled && (s.LastRun.IsZero() || !now.Before(s.LastRun.Add(s.Interval)))
+}
+
+// TaskRun is one run of a task, as logged to the task log.
+type TaskRun struct {
+	Task     string        `json:"task"`
+	Trigger  string        `json:"trigger"` // TriggerSchedule, TriggerSignal, TriggerRunOnce
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Status   string        `json:"status"` // TaskOK or TaskPanic
+	Error    string        `json:"error,omitempty"`
+}
+
+// TaskLogFile returns the path to the task log, one JSON TaskRun per line.
+func TaskLogFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "tasks.jsonl")
+}
+
+// ReadTaskLog returns the last n runs in the task log (all if n <= 0),
+// only task's if task is set, oldest first.
+func ReadTaskLog(townRoot, task string, n int) ([]TaskRun, error) {
+	f, err := os.Open(TaskLogFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []TaskRun
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var run TaskRun
+		if json.Unmarshal(scanner.Bytes(), &run) != nil {
+			continue // Torn or foreign line
+		}
+		if task != "" && run.Task != task {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if n > 0 && len(runs) > n {
+		runs = runs[len(runs)-n:]
+	}
+	return runs, scanner.Err()
+}
+
+// scheduleTasks works out each task's schedule from the patrol config,
+// logging the schedule and any config it can't use.
+func (d *Daemon) scheduleTasks(state *State) {
+	state.Tasks = make(map[string]*TaskState, len(tasks))
+	for _, t := range tasks {
+		interval, err := TaskInterval(d.patrolConfig, t)
+		if err != nil {
+			d.logger.Printf("Warning: %v; using %v", err, interval)
+		}
+		s := &TaskState{Enabled: TaskEnabled(d.patrolConfig, t), Interval: interval}
+		state.Tasks[t.Name] = s
+		if s.Enabled {
+			d.logger.Printf("Task %s: every %v", t.Name, interval)
+		} else {
+			d.logger.Printf("Task %s: disabled in config", t.Name)
+		}
+	}
+	if d.patrolConfig != nil {
+		for name := range d.patrolConfig.Tasks {
+			if _, ok := FindTask(name); !ok {
+				d.logger.Printf("Warning: %s configures unknown task %q", PatrolConfigFile(d.config.TownRoot), name)
+			}
+		}
+	}
+	d.rotateTaskLog()
+}
+
+// runDueTasks runs, in order, every task whose interval has elapsed.
+// Returns how many ran.
+func (d *Daemon) runDueTasks(state *State, now time.Time) int {
+	ran := 0
+	for _, t := range tasks {
+		s := state.Tasks[t.Name]
+		if s == nil || !s.Due(now) {
+			continue
+		}
+		d.recordTaskRun(state, d.runTask(t, TriggerSchedule))
+		ran++
+	}
+	return ran
+}
+
+// recordTaskRun updates a task's state with a run.
+func (d *Daemon) recordTaskRun(state *State, run TaskRun) {
+	s := state.Tasks[run.Task]
+	if s == nil {
+		return
+	}
+	s.Runs++
+	s.LastRun = run.Started
+	s.LastDuration = run.Duration
+	s.LastStatus = run.Status
+}
+
+// runTask runs a task, recovering if it panics so one broken task can't
+// take the daemon down, and logs the run to the task log.
+func (d *Daemon) runTask(t Task, trigger string) (run TaskRun) {
+	run = TaskRun{Task: t.Name, Trigger: trigger, Started: time.Now(), Status: TaskOK}
+	defer func() {
+		if r := recover(); r != nil {
+			run.Status = TaskPanic
+			run.Error = fmt.Sprint(r)
+			d.logger.Printf("Task %s panicked: %v\n%s", t.Name, r, debug.Stack())
+		}
+		run.Duration = time.Since(run.Started)
+		d.appendTaskLog(run)
+	}()
+	t.run(d)
+	return run
+}
+
+// RunOnce runs a task now, whether or not it is enabled or due, and
+// returns the run. It is logged to the task log like a scheduled run.
+func (d *Daemon) RunOnce(name string) (TaskRun, error) {
+	t, ok := FindTask(name)
+	if !ok {
+		return TaskRun{}, fmt.Errorf("unknown task %q", name)
+	}
+	d.logger.Printf("Running task %s once", name)
+	return d.runTask(t, TriggerRunOnce), nil
+}
+
+func (d *Daemon) appendTaskLog(run TaskRun) {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(TaskLogFile(d.config.TownRoot), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		d.logger.Printf("Warning: writing task log: %v", err)
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// rotateTaskLog moves a task log past maxTaskLogSize aside.
+func (d *Daemon) rotateTaskLog() {
+	path := TaskLogFile(d.config.TownRoot)
+	if info, err := os.Stat(path); err == nil && info.Size() > maxTaskLogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			d.logger.Printf("Warning: rotating task log: %v", err)
+		}
+	}
+}
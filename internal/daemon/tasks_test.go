@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTaskConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configJSON := `{
+		"type": "daemon-patrol-config",
+		"version": 1,
+		"patrols": {"witness": {"enabled": false}},
+		"tasks": {
+			"sla": {"interval": "1m"},
+			"digest": {"interval": "soon"},
+			"orphan-processes": {"enabled": false},
+			"rig-sync": {"enabled": true}
+		}
+	}`
+	if err := os.WriteFile(PatrolConfigFile(tmpDir), []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := LoadPatrolConfig(tmpDir)
+
+	for name, want := range map[string]bool{
+		"sla":              true,
+		"orphan-processes": false,
+		"witnesses":        false, // Its patrol is disabled
+		"rig-sync":         true,  // Its patrol is off by default, but the task is enabled
+		"refineries":       true,
+	} {
+		task, _ := FindTask(name)
+		if got := TaskEnabled(config, task); got != want {
+			t.Errorf("TaskEnabled(%s) = %v, want %v", name, got, want)
+		}
+	}
+	if task, _ := FindTask("rig-sync"); TaskEnabled(nil, task) {
+		t.Error("rig-sync should be off without config")
+	}
+
+	sla, _ := FindTask("sla")
+	if got, err := TaskInterval(config, sla); err != nil || got != time.Minute {
+		t.Errorf("TaskInterval(sla) = %v, %v; want 1m", got, err)
+	}
+	digest, _ := FindTask("digest")
+	if got, err := TaskInterval(config, digest); err == nil || got != digest.Interval {
+		t.Errorf("TaskInterval(digest) = %v, %v; want an error and the default", got, err)
+	}
+}
+
+func TestTaskStateDue(t *testing.T) {
+	now := time.Now()
+	s := &TaskState{Enabled: true, Interval: time.Minute}
+	if !s.Due(now) {
+		t.Error("a task that never ran should be due")
+	}
+	s.LastRun = now.Add(-30 * time.Second)
+	if s.Due(now) {
+		t.Error("a task run 30s ago shouldn't be due every minute")
+	}
+	s.LastRun = now.Add(-time.Minute)
+	if !s.Due(now) {
+		t.Error("a task run a minute ago should be due")
+	}
+	s.Enabled = false
+	if s.Due(now) {
+		t.Error("a disabled task is never due")
+	}
+}
+
+func TestRunTaskRecoversAndLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := &Daemon{config: &Config{TownRoot: tmpDir}, logger: log.New(io.Discard, "", 0)}
+	state := &State{Tasks: map[string]*TaskState{"boom": {Enabled: true, Interval: time.Minute}}}
+
+	ran := false
+	d.recordTaskRun(state, d.runTask(Task{Name: "fine", run: func(*Daemon) { ran = true }}, TriggerRunOnce))
+	d.recordTaskRun(state, d.runTask(Task{Name: "boom", run: func(*Daemon) { panic("kaboom") }}, TriggerSchedule))
+	if !ran {
+		t.Error("task didn't run")
+	}
+	if s := state.Tasks["boom"]; s.Runs != 1 || s.LastStatus != TaskPanic || s.LastRun.IsZero() {
+		t.Errorf("boom state = %+v", s)
+	}
+
+	runs, err := ReadTaskLog(tmpDir, "", 0)
+	if err != nil {
+		t.Fatalf("ReadTaskLog: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Task != "fine" || runs[0].Status != TaskOK || runs[0].Trigger != TriggerRunOnce {
+		t.Fatalf("runs = %+v", runs)
+	}
+	if runs[1].Status != TaskPanic || runs[1].Error != "kaboom" {
+		t.Errorf("boom run = %+v", runs[1])
+	}
+	if runs, _ := ReadTaskLog(tmpDir, "boom", 0); len(runs) != 1 {
+		t.Errorf("ReadTaskLog(boom) = %+v", runs)
+	}
+}
@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/tracker"
+)
+
+// syncTrackers mirrors each rig's work to the external tracker in its
+// settings, if any.
+func (d *Daemon) syncTrackers() {
+	for _, rigName := range d.getKnownRigs() {
+		res, err := tracker.SyncRig(filepath.Join(d.config.TownRoot, rigName), false)
+		if err != nil {
+			d.logger.Printf("Warning: syncing %s with its tracker: %v", rigName, err)
+			continue
+		}
+		if res == nil {
+			continue
+		}
+		for _, a := range res.Actions {
+			if a.Error != "" {
+				d.logger.Printf("Warning: mirroring %s %s to %s tracker: %s", a.Bead, a.Event, res.Kind, a.Error)
+			}
+		}
+		if n := len(res.Actions) - res.Failed; n > 0 {
+			d.logger.Printf("Mirrored %d change(s) in %s to its %s tracker", n, rigName, res.Kind)
+		}
+	}
+}
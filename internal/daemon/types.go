@@ -59,6 +59,9 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// Tasks is each periodic task's schedule and latest run, by name.
+	Tasks map[string]*TaskState `json:"tasks,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -114,6 +117,19 @@ type PatrolsConfig struct {
 	Witness    *PatrolConfig     `json:"witness,omitempty"`
 	Deacon     *PatrolConfig     `json:"deacon,omitempty"`
 	DoltServer *DoltServerConfig `json:"dolt_server,omitempty"`
+
+	// RigSync fetches and fast-forwards rig clones each heartbeat (gt rig
+	// sync). Unlike the agent patrols it is off unless enabled.
+	RigSync *PatrolConfig `json:"rig_sync,omitempty"`
+}
+
+// TaskConfig tunes one of the daemon's periodic tasks.
+type TaskConfig struct {
+	// Enabled turns the task on or off; unset follows its patrol, if any.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Interval is how often the task runs (e.g., "10m"); unset uses its default.
+	Interval string `json:"interval,omitempty"`
 }
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
@@ -122,6 +138,17 @@ type DaemonPatrolConfig struct {
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+
+	// Tasks tunes the daemon's periodic tasks, by name (see gt daemon status).
+	Tasks map[string]*TaskConfig `json:"tasks,omitempty"`
+}
+
+// task returns a task's config, or nil.
+func (c *DaemonPatrolConfig) task(name string) *TaskConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Tasks[name]
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -146,8 +173,13 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 }
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
-// Returns true if the config doesn't exist (default enabled for backwards compatibility).
+// Returns true if the config doesn't exist (default enabled for backwards compatibility),
+// except for rig_sync, which must be enabled explicitly.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
+	if patrol == "rig_sync" {
+		return config != nil && config.Patrols != nil &&
+			config.Patrols.RigSync != nil && config.Patrols.RigSync.Enabled
+	}
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
 	}
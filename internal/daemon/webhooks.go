@@ -0,0 +1,25 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/webhook"
+)
+
+// dispatchWebhooks posts new events to the endpoints in
+// settings/webhooks.json.
+func (d *Daemon) dispatchWebhooks() {
+	deliveries, err := webhook.Dispatch(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: dispatching webhooks: %v", err)
+	}
+	for _, dl := range deliveries {
+		if dl.Error != "" {
+			d.logger.Printf("Warning: webhook %s: %s", dl.Endpoint, dl.Error)
+		}
+		if dl.Dropped > 0 {
+			d.logger.Printf("Warning: webhook %s dropped %d event(s)", dl.Endpoint, dl.Dropped)
+		}
+		if dl.Sent > 0 {
+			d.logger.Printf("Webhook %s: sent %d event(s)", dl.Endpoint, dl.Sent)
+		}
+	}
+}
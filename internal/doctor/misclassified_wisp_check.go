@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // CheckMisclassifiedWisps detects issues that should be marked as wisps but aren't.
@@ -67,9 +68,11 @@ func (c *CheckMisclassifiedWisps) Run(ctx *CheckContext) *CheckResult {
 
 	var details []string
 
-	for _, rigName := range rigs {
-		rigPath := filepath.Join(ctx.TownRoot, rigName)
-		found := c.findMisclassifiedWisps(rigPath, rigName)
+	perRig := rig.ForEach(rigs, func(rigName string) []misclassifiedWisp {
+		return c.findMisclassifiedWisps(filepath.Join(ctx.TownRoot, rigName), rigName)
+	})
+	for i, rigName := range rigs {
+		found := perRig[i]
 		if len(found) > 0 {
 			c.misclassified = append(c.misclassified, found...)
 			c.misclassifiedRigs[rigName] = len(found)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/templates"
 )
 
@@ -64,9 +65,11 @@ func (c *PatrolMoleculesExistCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	var details []string
-	for _, rigName := range rigs {
-		rigPath := filepath.Join(ctx.TownRoot, rigName)
-		missing := c.checkPatrolFormulas(rigPath)
+	perRig := rig.ForEach(rigs, func(rigName string) []string {
+		return c.checkPatrolFormulas(filepath.Join(ctx.TownRoot, rigName))
+	})
+	for i, rigName := range rigs {
+		missing := perRig[i]
 		if len(missing) > 0 {
 			c.missingFormulas[rigName] = missing
 			details = append(details, fmt.Sprintf("%s: missing %v", rigName, missing))
@@ -243,14 +246,16 @@ func (c *PatrolNotStuckCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	var stuckWisps []string
-	for _, rigName := range rigs {
+	perRig := rig.ForEach(rigs, func(rigName string) []string {
 		// Check main beads database for wisps (issues with Wisp=true)
 		// Follows redirect if present (rig root may redirect to mayor/rig/.beads)
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 		beadsDir := beads.ResolveBeadsDir(rigPath)
 		beadsPath := filepath.Join(beadsDir, "issues.jsonl")
-		stuck := c.checkStuckWisps(beadsPath, rigName)
+		return c.checkStuckWisps(beadsPath, rigName)
+	})
+	var stuckWisps []string
+	for _, stuck := range perRig {
 		stuckWisps = append(stuckWisps, stuck...)
 	}
 
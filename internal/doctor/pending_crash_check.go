@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/crash"
+)
+
+// PendingCrashCheck reports crash report bundles captured for dead agent
+// sessions that nobody has reviewed yet.
+type PendingCrashCheck struct {
+	BaseCheck
+}
+
+// NewPendingCrashCheck creates a new pending crash report check.
+func NewPendingCrashCheck() *PendingCrashCheck {
+	return &PendingCrashCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "pending-crashes",
+			CheckDescription: "Check for unreviewed agent crash reports",
+			CheckCategory:    CategoryCleanup,
+		},
+	}
+}
+
+// Run lists crash reports under .runtime/crashes/ that are not acknowledged.
+func (c *PendingCrashCheck) Run(ctx *CheckContext) *CheckResult {
+	pending, err := crash.Pending(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read crash reports",
+			Details: []string{err.Error()},
+		}
+	}
+	if len(pending) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No pending crash reports",
+		}
+	}
+
+	var details []string
+	for _, r := range pending {
+		age := time.Since(r.CapturedAt).Round(time.Minute)
+		details = append(details, fmt.Sprintf("%s: %s exit %d (%s ago)", r.ID, r.Agent, r.ExitCode, age))
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d pending crash report(s)", len(pending)),
+		Details: details,
+		FixHint: "Review with 'gt crashes show <id>', then 'gt crashes ack <id>'",
+	}
+}
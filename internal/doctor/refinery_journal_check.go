@@ -0,0 +1,119 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// RefineryJournalCheck finds merges a refinery was in the middle of when it
+// died. The refinery recovers them when it next starts; the fix recovers
+// them now.
+type RefineryJournalCheck struct {
+	FixableCheck
+	interrupted []string // Rigs with an interrupted merge
+}
+
+// NewRefineryJournalCheck creates a new refinery journal check.
+func NewRefineryJournalCheck() *RefineryJournalCheck {
+	return &RefineryJournalCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "refinery-journal",
+				CheckDescription: "Detect refinery merges interrupted by a crash",
+				CheckCategory:    CategoryRig,
+			},
+		},
+	}
+}
+
+// Run reads every rig's refinery journal.
+func (c *RefineryJournalCheck) Run(ctx *CheckContext) *CheckResult {
+	c.interrupted = nil
+	var details, inProgress []string
+	for _, rigName := range c.rigNames(ctx) {
+		entry, err := refinery.OpenJournal(filepath.Join(ctx.TownRoot, rigName)).Pending()
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		if entry == nil {
+			continue
+		}
+		if !entry.Interrupted() {
+			inProgress = append(inProgress, fmt.Sprintf("%s: merging %s into %s", rigName, entry.MR, entry.Target))
+			continue
+		}
+		c.interrupted = append(c.interrupted, rigName)
+		details = append(details, fmt.Sprintf("%s: merge of %s into %s interrupted at step %q (%s)",
+			rigName, entry.MR, entry.Target, entry.Step, entry.Updated.Local().Format("2006-01-02 15:04")))
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d refinery merge(s) interrupted", len(c.interrupted)),
+			Details: append(details, inProgress...),
+			FixHint: "Run 'gt doctor --fix' to complete or roll them back, or restart the refinery",
+		}
+	}
+	msg := "No interrupted refinery merges"
+	if len(inProgress) > 0 {
+		msg = fmt.Sprintf("%d refinery merge(s) in progress", len(inProgress))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: msg,
+		Details: inProgress,
+	}
+}
+
+// Fix recovers each interrupted merge as the refinery would on startup.
+func (c *RefineryJournalCheck) Fix(ctx *CheckContext) error {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	mgr := rig.NewManager(ctx.TownRoot, rigsConfig, git.NewGit(ctx.TownRoot))
+
+	var errs []string
+	for _, rigName := range c.interrupted {
+		r, err := mgr.GetRig(rigName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		if _, err := refinery.NewEngineer(r).Recover(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rigName, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("recovering interrupted merges: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// rigNames returns the rig being checked, or every registered rig.
+func (c *RefineryJournalCheck) rigNames(ctx *CheckContext) []string {
+	if ctx.RigName != "" {
+		return []string{ctx.RigName}
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // RigIsGitRepoCheck verifies the rig has a valid mayor/rig git clone.
@@ -710,6 +711,16 @@ func (c *PolecatClonesValidCheck) Run(ctx *CheckContext) *CheckResult {
 	// Get rig name for new structure path detection
 	rigName := ctx.RigName
 
+	// Work branches should be named by the rig's branch policy
+	r := &rig.Rig{Name: rigName, Path: rigPath}
+	if cfg, err := rig.LoadRigConfig(rigPath); err == nil && cfg.Beads != nil {
+		r.Config = &config.BeadsConfig{Prefix: cfg.Beads.Prefix}
+	}
+	policy, err := r.BranchPolicy()
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
@@ -749,8 +760,8 @@ func (c *PolecatClonesValidCheck) Run(ctx *CheckContext) *CheckResult {
 		branchOutput, err := cmd.Output()
 		if err == nil {
 			branch := strings.TrimSpace(string(branchOutput))
-			if !strings.HasPrefix(branch, constants.BranchPolecatPrefix) {
-				warnings = append(warnings, fmt.Sprintf("%s: on branch '%s' (expected %s*)", polecatName, branch, constants.BranchPolecatPrefix))
+			if _, ok := policy.Parse(branch); !ok {
+				warnings = append(warnings, fmt.Sprintf("%s: on branch '%s' (expected %s)", polecatName, branch, policy.Template()))
 			}
 		}
 
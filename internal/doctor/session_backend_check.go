@@ -0,0 +1,51 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/terminal"
+)
+
+// SessionBackendCheck verifies that the configured session backend is
+// known and installed on this machine.
+type SessionBackendCheck struct {
+	BaseCheck
+}
+
+// NewSessionBackendCheck creates a new session backend check.
+func NewSessionBackendCheck() *SessionBackendCheck {
+	return &SessionBackendCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "session-backend",
+			CheckDescription: "Check that the configured session backend is available",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run resolves the town's session backend and checks it is usable.
+func (c *SessionBackendCheck) Run(ctx *CheckContext) *CheckResult {
+	backend, err := terminal.ForTown(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Invalid session backend",
+			Details: []string{err.Error()},
+			FixHint: "Set session_backend in settings/config.json to tmux, screen, or process",
+		}
+	}
+	if !backend.IsAvailable() {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Session backend %s is not installed", backend.Name()),
+			FixHint: fmt.Sprintf("Install %s, or choose another session_backend", backend.Name()),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("Session backend: %s", backend.Name()),
+	}
+}
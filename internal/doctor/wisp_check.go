@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // WispGCCheck detects and cleans orphaned wisps that are older than a threshold.
@@ -61,9 +62,11 @@ func (c *WispGCCheck) Run(ctx *CheckContext) *CheckResult {
 	var details []string
 	totalAbandoned := 0
 
-	for _, rigName := range rigs {
-		rigPath := filepath.Join(ctx.TownRoot, rigName)
-		count := c.countAbandonedWisps(rigPath)
+	counts := rig.ForEach(rigs, func(rigName string) int {
+		return c.countAbandonedWisps(filepath.Join(ctx.TownRoot, rigName))
+	})
+	for i, rigName := range rigs {
+		count := counts[i]
 		if count > 0 {
 			c.abandonedRigs[rigName] = count
 			totalAbandoned += count
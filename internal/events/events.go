@@ -1,7 +1,8 @@
-// Package events provides event logging for the gt activity feed.
+// Package events provides the town event bus behind the gt activity feed.
 //
-// Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// Events are appended to ~/gt/.events.jsonl (raw audit log) and later
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). Consumers
+// read and follow the log with ReadAll, Follow, and Subscribe.
 package events
 
 import (
@@ -53,6 +54,7 @@ const (
 	// Session death events (for crash investigation)
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
+	TypeCrashReport  = "crash_report"  // Crash report bundle captured for a dead session
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -68,6 +70,25 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Merge request lifecycle events (emitted by gt mq)
+	TypeMRSubmitted        = "mr_submitted"
+	TypeMRApproved         = "mr_approved"
+	TypeMRChangesRequested = "mr_changes_requested"
+	TypeMRRejected         = "mr_rejected"
+	TypeMRReverted         = "mr_reverted"
+	TypeMRAssigned         = "mr_assigned"
+	TypeMRBackported       = "mr_backported"
+	TypeMRCommented        = "mr_commented"
+
+	// Merge queue SLA events (emitted by the daemon)
+	TypeMRSLABreached = "mr_sla_breached"
+
+	// Access control events
+	TypePermissionDenied = "permission_denied"
+
+	// Session budget events (emitted by the daemon)
+	TypeBudgetExceeded = "budget_exceeded"
 )
 
 // EventsFile is the name of the raw events log.
@@ -213,6 +234,21 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// MRPayload creates a payload for merge request lifecycle events.
+// reason: rejection reason or review comment (optional)
+func MRPayload(rig, mrID, issue, branch, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"mr":     mrID,
+		"issue":  issue,
+		"branch": branch,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -311,6 +347,53 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// CrashReportPayload creates a payload for crash report events.
+// report: crash report ID (directory name under .runtime/crashes/)
+func CrashReportPayload(session, agent, report string, exitCode int) map[string]interface{} {
+	return map[string]interface{}{
+		"session":   session,
+		"agent":     agent,
+		"report":    report,
+		"exit_code": exitCode,
+	}
+}
+
+// PermissionDeniedPayload creates a payload for blocked destructive commands.
+// action: the gated action (e.g., "mq.reject"); level: the caller's
+// permission level; target: what the action would have touched.
+func PermissionDeniedPayload(action, level, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"action": action,
+		"level":  level,
+		"target": target,
+	}
+}
+
+// BudgetExceededPayload creates a payload for session starts blocked by a
+// budget. rig is empty for town-level roles; limit names the budget field.
+func BudgetExceededPayload(rig, role, limit string, used, max float64) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":   rig,
+		"role":  role,
+		"limit": limit,
+		"used":  used,
+		"max":   max,
+	}
+}
+
+// MRSLABreachPayload creates a payload for an MR that has waited past its
+// priority's merge SLA. target and overdue are durations (e.g., "1h0m0s").
+func MRSLABreachPayload(rig, mrID, worker string, priority int, target, overdue string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":      rig,
+		"mr":       mrID,
+		"worker":   worker,
+		"priority": priority,
+		"target":   target,
+		"overdue":  overdue,
+	}
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
@@ -0,0 +1,250 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often Follow checks the events log for new
+// events.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// Path returns the events log for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, EventsFile)
+}
+
+// Filter selects events by field. Keys are "type", "actor", "source",
+// "visibility", or a payload key (optionally written "payload.<key>").
+// An event matches when every key matches one of its values; values may
+// use * globs (e.g. type=merge_*).
+type Filter map[string][]string
+
+// ParseFilter parses "key=value" expressions into a Filter. A value may
+// list alternatives separated by commas; repeating a key adds alternatives.
+func ParseFilter(exprs []string) (Filter, error) {
+	f := Filter{}
+	for _, expr := range exprs {
+		key, value, ok := strings.Cut(expr, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid filter %q: want key=value", expr)
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				if _, err := path.Match(v, ""); err != nil {
+					return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+				}
+				f[key] = append(f[key], v)
+			}
+		}
+	}
+	return f, nil
+}
+
+// Match reports whether e satisfies every key in the filter. A nil or empty
+// filter matches everything.
+func (f Filter) Match(e Event) bool {
+	for key, values := range f {
+		got, ok := e.field(key)
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, v := range values {
+			if m, _ := path.Match(v, got); m {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// field returns the value of a filterable field as a string.
+func (e Event) field(key string) (string, bool) {
+	switch key {
+	case "type":
+		return e.Type, true
+	case "actor":
+		return e.Actor, true
+	case "source":
+		return e.Source, true
+	case "visibility":
+		return e.Visibility, true
+	}
+	v, ok := e.Payload[strings.TrimPrefix(key, "payload.")]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// ReadAll returns the events in the log at path that match f, oldest first,
+// and the offset just past the last complete line (to Follow from). A
+// missing log has no events. Malformed lines are skipped.
+func ReadAll(path string, f Filter) ([]Event, int64, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is the town's events log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("opening events log: %w", err)
+	}
+	defer file.Close()
+
+	var matched []Event
+	offset, err := readLines(file, 0, func(e Event, _ int64) error {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+		return nil
+	})
+	return matched, offset, err
+}
+
+// Follow calls fn for each event matching f appended to the log at path
+// after offset, until ctx is done or fn returns an error. If the log
+// shrinks below offset (it was pruned), Follow starts again from the top.
+func Follow(ctx context.Context, path string, offset int64, f Filter, interval time.Duration, fn func(Event) error) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case err == nil:
+			if info.Size() < offset {
+				offset = 0
+			}
+			if info.Size() > offset {
+				if offset, err = followOnce(path, offset, f, fn); err != nil {
+					return err
+				}
+			}
+		case !os.IsNotExist(err):
+			return fmt.Errorf("checking events log: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Subscribe streams events matching f that are logged in the town from now
+// on. The channel is closed when ctx is done.
+func Subscribe(ctx context.Context, townRoot string, f Filter) <-chan Event {
+	ch := make(chan Event, 100)
+	go func() {
+		defer close(ch)
+		offset := int64(0)
+		if info, err := os.Stat(Path(townRoot)); err == nil {
+			offset = info.Size()
+		}
+		_ = Follow(ctx, Path(townRoot), offset, f, DefaultPollInterval, func(e Event) error {
+			select {
+			case ch <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return ch
+}
+
+// Since calls fn for each event matching f logged after offset in the log
+// at path, with the offset just past the event, so a consumer can resume
+// after the last event it handled. Returns the offset past the last line
+// read; reading stops at the first error from fn. If the log shrank below
+// offset (it was pruned), reading starts again from the top.
+func Since(path string, offset int64, f Filter, fn func(e Event, next int64) error) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return offset, fmt.Errorf("checking events log: %w", err)
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	file, err := os.Open(path) //nolint:gosec // G304: path is the town's events log
+	if err != nil {
+		return offset, fmt.Errorf("opening events log: %w", err)
+	}
+	defer file.Close()
+
+	return readLines(file, offset, func(e Event, next int64) error {
+		if f.Match(e) {
+			return fn(e, next)
+		}
+		return nil
+	})
+}
+
+func followOnce(path string, offset int64, f Filter, fn func(Event) error) (int64, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is the town's events log
+	if err != nil {
+		return offset, fmt.Errorf("opening events log: %w", err)
+	}
+	defer file.Close()
+
+	return readLines(file, offset, func(e Event, _ int64) error {
+		if f.Match(e) {
+			return fn(e)
+		}
+		return nil
+	})
+}
+
+// readLines parses complete JSONL lines from r starting at offset, calling
+// fn for each event with the offset just past its line. A trailing partial
+// line (still being written) is left for the next read. Returns the offset
+// after the last complete line.
+func readLines(r io.ReadSeeker, offset int64, fn func(Event, int64) error) (int64, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("seeking events log: %w", err)
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, fmt.Errorf("reading events log: %w", err)
+		}
+		offset += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		if err := fn(e, offset); err != nil {
+			return offset, err
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendEvents(t *testing.T, path string, evts ...Event) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	merged := Event{Type: TypeMerged, Actor: "greenplace/refinery", Payload: MergePayload("gp-mr-1", "Nux", "polecat/Nux", "")}
+	spawn := Event{Type: TypeSpawn, Actor: "gt", Payload: SpawnPayload("greenplace", "Toast")}
+
+	tests := []struct {
+		exprs []string
+		event Event
+		want  bool
+	}{
+		{nil, merged, true},
+		{[]string{"type=merged"}, merged, true},
+		{[]string{"type=merge_*"}, merged, false},
+		{[]string{"type=merge*"}, merged, true},
+		{[]string{"type=spawn,merged"}, merged, true},
+		{[]string{"type=spawn", "type=merged"}, merged, true},
+		{[]string{"type=merged", "actor=greenplace/*"}, merged, true},
+		{[]string{"worker=Nux"}, merged, true},
+		{[]string{"payload.worker=Nux"}, merged, true},
+		{[]string{"rig=greenplace"}, spawn, true},
+		{[]string{"rig=greenplace"}, merged, false}, // no rig in payload
+		{[]string{"type=spawn", "polecat=Nux"}, spawn, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.exprs)
+		if err != nil {
+			t.Fatalf("ParseFilter(%v): %v", tt.exprs, err)
+		}
+		if got := f.Match(tt.event); got != tt.want {
+			t.Errorf("ParseFilter(%v).Match(%s) = %v, want %v", tt.exprs, tt.event.Type, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"type", "=merged", "type=", "type=[x"} {
+		if _, err := ParseFilter([]string{bad}); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+
+	if evts, offset, err := ReadAll(path, nil); err != nil || len(evts) != 0 || offset != 0 {
+		t.Fatalf("ReadAll(missing) = %v, %d, %v", evts, offset, err)
+	}
+
+	appendEvents(t, path, Event{Type: TypeSpawn}, Event{Type: TypeMerged})
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("not json\n{\"type\":\"kill\"") // malformed line, then a partial one
+	f.Close()
+
+	filter, _ := ParseFilter([]string{"type=merged,kill"})
+	evts, offset, err := ReadAll(path, filter)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(evts) != 1 || evts[0].Type != TypeMerged {
+		t.Errorf("ReadAll = %+v, want just the merged event", evts)
+	}
+	info, _ := os.Stat(path)
+	if want := info.Size() - int64(len(`{"type":"kill"`)); offset != want {
+		t.Errorf("offset = %d, want %d (before the partial line)", offset, want)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	appendEvents(t, path, Event{Type: TypeSpawn})
+	_, offset, _ := ReadAll(path, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got := make(chan Event, 10)
+	done := make(chan error, 1)
+	filter, _ := ParseFilter([]string{"type=merged"})
+	go func() {
+		done <- Follow(ctx, path, offset, filter, 10*time.Millisecond, func(e Event) error {
+			got <- e
+			return nil
+		})
+	}()
+
+	appendEvents(t, path, Event{Type: TypeMail}, Event{Type: TypeMerged, Actor: "first"})
+	if e := <-got; e.Actor != "first" {
+		t.Errorf("first followed event = %+v", e)
+	}
+
+	// A pruned (shorter) log is read again from the top
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	appendEvents(t, path, Event{Type: TypeMerged, Actor: "after-prune"})
+	select {
+	case e := <-got:
+		if e.Actor != "after-prune" {
+			t.Errorf("event after prune = %+v", e)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for event after prune")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow returned %v", err)
+	}
+}
+
+func TestSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	if offset, err := Since(path, 0, nil, nil); err != nil || offset != 0 {
+		t.Fatalf("Since(missing) = %d, %v", offset, err)
+	}
+
+	appendEvents(t, path, Event{Type: TypeSpawn}, Event{Type: TypeMerged, Actor: "a"}, Event{Type: TypeMerged, Actor: "b"})
+	filter, _ := ParseFilter([]string{"type=merged"})
+	var nexts []int64
+	end, err := Since(path, 0, filter, func(e Event, next int64) error {
+		nexts = append(nexts, next)
+		return nil
+	})
+	if err != nil || len(nexts) != 2 {
+		t.Fatalf("Since = %v, %v", nexts, err)
+	}
+	if info, _ := os.Stat(path); end != info.Size() || nexts[1] != end {
+		t.Errorf("Since ended at %d (events at %v), want %d", end, nexts, info.Size())
+	}
+
+	// Resuming after the first merged event sees only the second
+	var actors []string
+	if _, err := Since(path, nexts[0], filter, func(e Event, _ int64) error {
+		actors = append(actors, e.Actor)
+		return nil
+	}); err != nil || len(actors) != 1 || actors[0] != "b" {
+		t.Errorf("resumed Since = %v, %v", actors, err)
+	}
+}
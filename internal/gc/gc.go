@@ -0,0 +1,469 @@
+// Package gc finds and removes the cruft a town accumulates: polecat
+// branches whose work has merged, worktrees whose checkouts are gone, tmux
+// sessions of polecats that no longer exist, expired check artifacts,
+// rotated logs and reviewed crash reports, and superseded copies in MR
+// archives.
+package gc
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crash"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Kinds of cruft.
+const (
+	KindWorktrees      = "worktrees"       // Worktree entries whose checkouts are gone
+	KindBranches       = "branches"        // Merged polecat branches in the rig's shared repo
+	KindRemoteBranches = "remote-branches" // Merged polecat branches on the rig's push remote
+	KindSessions       = "sessions"        // tmux sessions of polecats that no longer exist
+	KindArtifacts      = "artifacts"       // MR check artifacts past the rig's retention
+	KindLogs           = "logs"            // Rotated logs past MaxAge
+	KindCrashes        = "crashes"         // Reviewed crash reports past MaxAge
+	KindArchives       = "archives"        // Superseded copies in MR archives
+)
+
+// Kinds lists every kind, in the order Run collects them. Worktrees come
+// before branches: a branch checked out in a dead worktree can't be
+// deleted until the worktree is pruned.
+var Kinds = []string{
+	KindWorktrees, KindBranches, KindRemoteBranches, KindSessions,
+	KindArtifacts, KindLogs, KindCrashes, KindArchives,
+}
+
+// DefaultKinds are the kinds collected unless others are asked for.
+// Deleting branches on the push remote is opt-in.
+var DefaultKinds = slices.DeleteFunc(slices.Clone(Kinds), func(k string) bool { return k == KindRemoteBranches })
+
+// DefaultMaxAge is how long rotated logs and reviewed crash reports are
+// kept.
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+// Options controls a collection.
+type Options struct {
+	Kinds  []string      // What to collect (default: DefaultKinds)
+	MaxAge time.Duration // Age past which logs and crash reports expire (default: DefaultMaxAge)
+	DryRun bool          // Report what would be removed without removing it
+}
+
+// Item is one piece of cruft, removed or (on a dry run) found.
+type Item struct {
+	Kind   string `json:"kind"`
+	Rig    string `json:"rig,omitempty"`
+	Target string `json:"target"` // Branch, path or session
+	Reason string `json:"reason,omitempty"`
+	Bytes  int64  `json:"bytes"` // Space reclaimed (0 if none, or unknown)
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is what a collection removed, or would remove.
+type Report struct {
+	DryRun bool   `json:"dry_run"`
+	Items  []Item `json:"items"`
+	Bytes  int64  `json:"bytes"`  // Reclaimed, or reclaimable on a dry run
+	Failed int    `json:"failed"` // Items that couldn't be removed
+}
+
+// CheckKinds returns an error naming any kind that isn't one.
+func CheckKinds(kinds []string) error {
+	for _, k := range kinds {
+		if !slices.Contains(Kinds, k) {
+			return fmt.Errorf("unknown kind %q (kinds: %v)", k, Kinds)
+		}
+	}
+	return nil
+}
+
+// run is a collection in progress.
+type run struct {
+	townRoot string
+	rigs     []*rig.Rig
+	opts     Options
+	now      time.Time
+	report   *Report
+}
+
+// Run collects the town's cruft of each kind in opts across rigs.
+func Run(townRoot string, rigs []*rig.Rig, opts Options) (*Report, error) {
+	if len(opts.Kinds) == 0 {
+		opts.Kinds = DefaultKinds
+	}
+	if err := CheckKinds(opts.Kinds); err != nil {
+		return nil, err
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	r := &run{townRoot: townRoot, rigs: rigs, opts: opts, now: time.Now(), report: &Report{DryRun: opts.DryRun, Items: []Item{}}}
+	collectors := map[string]func(){
+		KindWorktrees:      r.worktrees,
+		KindBranches:       func() { r.branches(false) },
+		KindRemoteBranches: func() { r.branches(true) },
+		KindSessions:       r.sessions,
+		KindArtifacts:      r.artifacts,
+		KindLogs:           r.logs,
+		KindCrashes:        r.crashes,
+		KindArchives:       r.archives,
+	}
+	for _, kind := range Kinds {
+		if slices.Contains(opts.Kinds, kind) {
+			collectors[kind]()
+		}
+	}
+	return r.report, nil
+}
+
+// remove records an item and, unless this is a dry run, removes it.
+func (r *run) remove(item Item, removeFn func() error) {
+	if !r.opts.DryRun {
+		if err := removeFn(); err != nil {
+			item.Error = err.Error()
+		}
+	}
+	r.record(item)
+}
+
+// record adds an item to the report.
+func (r *run) record(item Item) {
+	if item.Error != "" {
+		r.report.Failed++
+	} else {
+		r.report.Bytes += item.Bytes
+	}
+	r.report.Items = append(r.report.Items, item)
+}
+
+// fail records that cruft of a kind couldn't be looked for.
+func (r *run) fail(kind, rigName, target string, err error) {
+	r.record(Item{Kind: kind, Rig: rigName, Target: target, Error: err.Error()})
+}
+
+// repos returns a rig's repositories that have worktrees: the shared bare
+// repo polecats and the refinery check out, and the mayor's clone.
+func repos(rg *rig.Rig) []*git.Git {
+	var repos []*git.Git
+	if bare := filepath.Join(rg.Path, ".repo.git"); isDir(bare) {
+		repos = append(repos, git.NewGitWithDir(bare, ""))
+	}
+	if clone := filepath.Join(rg.Path, "mayor", "rig"); isDir(filepath.Join(clone, ".git")) {
+		repos = append(repos, git.NewGit(clone))
+	}
+	return repos
+}
+
+func (r *run) worktrees() {
+	for _, rg := range r.rigs {
+		for _, g := range repos(rg) {
+			prunable, err := g.WorktreePrunable()
+			if err != nil {
+				r.fail(KindWorktrees, rg.Name, "", err)
+				continue
+			}
+			if len(prunable) == 0 {
+				continue
+			}
+			var pruneErr error
+			if !r.opts.DryRun {
+				pruneErr = g.WorktreePrune()
+			}
+			for _, path := range prunable {
+				item := Item{Kind: KindWorktrees, Rig: rg.Name, Target: path, Reason: "checkout is gone"}
+				if pruneErr != nil {
+					item.Error = pruneErr.Error()
+				}
+				r.record(item)
+			}
+		}
+	}
+}
+
+// branches deletes the polecat branches (as the rig's branch policy names
+// them) whose work has merged: the refinery merged an MR from the branch's
+// current tip, or the tip is on the rig's default branch. A branch pushed to
+// after its MR merged is kept, as are branches checked out in a worktree or
+// with an MR still open. Each branch is deleted only if it still points at
+// the tip that was checked. With remote, the push remote's branches are
+// deleted, otherwise those in the rig's shared repo.
+func (r *run) branches(remote bool) {
+	kind := KindBranches
+	if remote {
+		kind = KindRemoteBranches
+	}
+	for _, rg := range r.rigs {
+		bare := filepath.Join(rg.Path, ".repo.git")
+		if !isDir(bare) {
+			continue
+		}
+		g := git.NewGitWithDir(bare, "")
+		policy, err := rg.BranchPolicy()
+		if err != nil {
+			r.fail(kind, rg.Name, "", err)
+			continue
+		}
+		merged, open, err := mrBranches(rg)
+		if err != nil {
+			r.fail(kind, rg.Name, "", fmt.Errorf("listing MRs: %w", err))
+			continue
+		}
+		checkedOut, err := checkedOutBranches(g, r.opts.DryRun)
+		if err != nil {
+			r.fail(kind, rg.Name, "", err)
+			continue
+		}
+
+		pushRemote := rg.PushRemote()
+		target := pushRemote + "/" + rg.DefaultBranch()
+		var branches []string
+		if remote {
+			branches, err = g.ListRemoteBranches(pushRemote)
+		} else {
+			branches, err = g.ListBranches("")
+		}
+		if err != nil {
+			r.fail(kind, rg.Name, "", err)
+			continue
+		}
+
+		for _, branch := range branches {
+			if _, ok := policy.Parse(branch); !ok || checkedOut[branch] || open[branch] {
+				continue
+			}
+			ref, name := branch, branch
+			if remote {
+				ref = pushRemote + "/" + branch
+				name = ref
+			}
+			tip, err := g.Rev(ref)
+			if err != nil {
+				r.fail(kind, rg.Name, name, err)
+				continue
+			}
+			var reason string
+			if mr := merged[branch][tip]; mr != "" {
+				reason = mr + " merged"
+			} else if onTarget, err := g.IsAncestor(tip, target); err == nil && onTarget {
+				reason = "on " + target
+			} else {
+				continue
+			}
+			r.remove(Item{Kind: kind, Rig: rg.Name, Target: name, Reason: reason}, func() error {
+				if remote {
+					return g.DeleteRemoteBranchAt(pushRemote, branch, tip)
+				}
+				return g.DeleteBranchAt(branch, tip)
+			})
+		}
+	}
+}
+
+// mrBranches returns, for a rig's merged MRs (including archived ones), the
+// MR's ID by branch and the branch tip it merged; and the branches of its
+// unmerged MRs. MRs merged before the refinery recorded the tip are left
+// out, so their branches are deleted only once on the default branch.
+func mrBranches(rg *rig.Rig) (merged map[string]map[string]string, open map[string]bool, err error) {
+	bd := beads.NewWithBeadsDir(rg.Path, beads.ResolveBeadsDir(rg.Path))
+	merged, open = make(map[string]map[string]string), make(map[string]bool)
+	var mrs []*beads.Issue
+	for _, status := range []string{"open", "in_progress", "closed"} {
+		listed, err := bd.ListIndexed(beads.ListOptions{Type: "merge-request", Status: status, Priority: -1})
+		if err != nil {
+			return nil, nil, err
+		}
+		mrs = append(mrs, listed...)
+	}
+	archived, err := bd.ListArchivedMRs()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, mr := range append(archived, mrs...) {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.Branch == "" {
+			continue
+		}
+		switch state := refinery.StateOf(mr); {
+		case state == refinery.StateMerged:
+			if fields.MergeHead == "" {
+				continue
+			}
+			if merged[fields.Branch] == nil {
+				merged[fields.Branch] = make(map[string]string)
+			}
+			merged[fields.Branch][fields.MergeHead] = mr.ID
+		case !state.Terminal():
+			open[fields.Branch] = true
+		}
+	}
+	return merged, open, nil
+}
+
+// checkedOutBranches returns the branches checked out in a repo's
+// worktrees. Unless dryRun, worktrees that are gone were pruned first and
+// don't hold theirs; on a dry run they are treated as if pruned.
+func checkedOutBranches(g *git.Git, dryRun bool) (map[string]bool, error) {
+	worktrees, err := g.WorktreeList()
+	if err != nil {
+		return nil, err
+	}
+	var gone []string
+	if dryRun {
+		gone, _ = g.WorktreePrunable()
+	}
+	branches := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.Branch != "" && !slices.Contains(gone, wt.Path) {
+			branches[wt.Branch] = true
+		}
+	}
+	return branches, nil
+}
+
+// sessions kills the tmux sessions of polecats whose directories are gone.
+// Crew sessions are never touched.
+func (r *run) sessions() {
+	sessions, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return // No tmux server, so no sessions
+	}
+	rigs := make(map[string]*rig.Rig, len(r.rigs))
+	for _, rg := range r.rigs {
+		rigs[rg.Name] = rg
+	}
+	t := tmux.NewTmux()
+	for _, sess := range sessions {
+		id, err := session.ParseSessionName(sess)
+		if err != nil || id.Role != session.RolePolecat || rigs[id.Rig] == nil {
+			continue
+		}
+		if isDir(filepath.Join(rigs[id.Rig].Path, "polecats", id.Name)) {
+			continue
+		}
+		r.remove(Item{Kind: KindSessions, Rig: id.Rig, Target: sess, Reason: "polecat " + id.Name + " is gone"}, func() error {
+			_ = events.LogFeed(events.TypeSessionDeath, sess,
+				events.SessionDeathPayload(sess, id.Rig+"/polecats/"+id.Name, "orphan cleanup", "gt gc"))
+			return t.KillSessionWithProcesses(sess)
+		})
+	}
+}
+
+func (r *run) artifacts() {
+	for _, rg := range r.rigs {
+		store, err := refinery.LoadArtifactStore(rg.Path)
+		if err != nil {
+			r.fail(KindArtifacts, rg.Name, "", err)
+			continue
+		}
+		expired, err := store.Expired(r.now)
+		if err != nil {
+			r.fail(KindArtifacts, rg.Name, "", err)
+			continue
+		}
+		for _, dir := range expired {
+			r.removeAll(Item{Kind: KindArtifacts, Rig: rg.Name, Target: dir,
+				Reason: fmt.Sprintf("older than %v", store.Retention())}, dir)
+		}
+	}
+}
+
+// rotatedLog matches a log moved aside by rotation, e.g. tasks.jsonl.1 or
+// daemon.log.2026-01-02.gz.
+var rotatedLog = regexp.MustCompile(`\.(log|jsonl)\.[^/]+$`)
+
+func (r *run) logs() {
+	for _, dir := range []string{filepath.Join(r.townRoot, "daemon"), filepath.Join(r.townRoot, "logs")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || entry.IsDir() || !rotatedLog.MatchString(entry.Name()) || r.now.Sub(info.ModTime()) < r.opts.MaxAge {
+				continue
+			}
+			r.removeAll(Item{Kind: KindLogs, Target: filepath.Join(dir, entry.Name()),
+				Reason: fmt.Sprintf("rotated %s ago", formatAge(r.now.Sub(info.ModTime())))}, filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+func (r *run) crashes() {
+	reports, err := crash.List(r.townRoot)
+	if err != nil {
+		r.fail(KindCrashes, "", crash.Dir(r.townRoot), err)
+		return
+	}
+	for _, rep := range reports {
+		if !rep.Reviewed || r.now.Sub(rep.CapturedAt) < r.opts.MaxAge {
+			continue
+		}
+		r.removeAll(Item{Kind: KindCrashes, Target: crash.BundlePath(r.townRoot, rep.ID),
+			Reason: fmt.Sprintf("%s crash, reviewed", rep.Agent)}, crash.BundlePath(r.townRoot, rep.ID))
+	}
+}
+
+func (r *run) archives() {
+	for _, rg := range r.rigs {
+		bd := beads.NewWithBeadsDir(rg.Path, beads.ResolveBeadsDir(rg.Path))
+		dropped, reclaimed, err := bd.CompactMRArchive(r.opts.DryRun)
+		item := Item{Kind: KindArchives, Rig: rg.Name, Target: bd.MRArchivePath(), Bytes: reclaimed,
+			Reason: fmt.Sprintf("%d superseded entr%s", dropped, plural(dropped, "y", "ies"))}
+		if err != nil {
+			item.Error = err.Error()
+		} else if dropped == 0 {
+			continue
+		}
+		r.record(item)
+	}
+}
+
+// removeAll records and removes a file or directory, counting its size.
+func (r *run) removeAll(item Item, path string) {
+	item.Bytes = diskUsage(path)
+	r.remove(item, func() error { return os.RemoveAll(path) })
+}
+
+// diskUsage returns the size of the files under path.
+func diskUsage(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return d.Round(time.Hour).String()
+}
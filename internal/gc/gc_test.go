@@ -0,0 +1,166 @@
+package gc
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crash"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func writeFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeCrash(t *testing.T, townRoot string, r crash.Report) {
+	t.Helper()
+	data, _ := json.Marshal(r)
+	writeFile(t, filepath.Join(crash.BundlePath(townRoot, r.ID), crash.ReportFile), string(data), time.Now())
+	writeFile(t, filepath.Join(crash.BundlePath(townRoot, r.ID), crash.PaneFile), "pane output", time.Now())
+}
+
+func TestRunLogsAndCrashes(t *testing.T) {
+	townRoot := t.TempDir()
+	old := time.Now().Add(-2 * DefaultMaxAge)
+	writeFile(t, filepath.Join(townRoot, "daemon", "daemon.log"), "live", old)
+	writeFile(t, filepath.Join(townRoot, "daemon", "tasks.jsonl.1"), "rotated", old)
+	writeFile(t, filepath.Join(townRoot, "logs", "town.log.1"), "recent", time.Now())
+	writeCrash(t, townRoot, crash.Report{ID: "old-reviewed", Agent: "gp/polecats/Toast", CapturedAt: old, Reviewed: true})
+	writeCrash(t, townRoot, crash.Report{ID: "old-pending", Agent: "gp/polecats/Nux", CapturedAt: old})
+	writeCrash(t, townRoot, crash.Report{ID: "new-reviewed", Agent: "gp/witness", CapturedAt: time.Now(), Reviewed: true})
+
+	opts := Options{Kinds: []string{KindLogs, KindCrashes}, DryRun: true}
+	report, err := Run(townRoot, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(townRoot, "daemon", "tasks.jsonl.1"),
+		crash.BundlePath(townRoot, "old-reviewed"),
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("items = %+v, want %v", report.Items, want)
+	}
+	for i, item := range report.Items {
+		if item.Target != want[i] {
+			t.Errorf("item %d = %s, want %s", i, item.Target, want[i])
+		}
+	}
+	if report.Bytes <= 0 {
+		t.Errorf("reclaimable = %d, want the removed files' size", report.Bytes)
+	}
+	for _, path := range want {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("dry run removed %s", path)
+		}
+	}
+
+	opts.DryRun = false
+	if report, err = Run(townRoot, nil, opts); err != nil || len(report.Items) != 2 || report.Failed != 0 {
+		t.Fatalf("Run = %+v, %v", report, err)
+	}
+	for _, path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "daemon", "daemon.log")); err != nil {
+		t.Error("live log removed")
+	}
+	if report, _ = Run(townRoot, nil, opts); len(report.Items) != 0 {
+		t.Errorf("second run found %+v", report.Items)
+	}
+}
+
+func TestRunUnknownKind(t *testing.T) {
+	if _, err := Run(t.TempDir(), nil, Options{Kinds: []string{"tmp"}}); err == nil {
+		t.Error("Run accepted an unknown kind")
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestRunBranches(t *testing.T) {
+	// No beads database: the only MRs are the archived ones
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte("#!/bin/sh\necho '[]'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	src := t.TempDir()
+	runGit(t, src, "init", "-q", "-b", "main")
+	commit := func(msg string) string {
+		runGit(t, src, "commit", "-q", "--allow-empty", "-m", msg)
+		return runGit(t, src, "rev-parse", "HEAD")
+	}
+	base := commit("base")
+	runGit(t, src, "checkout", "-q", "-b", "polecat/nux/gp-1@1")
+	merged := commit("gp-1 work")
+	runGit(t, src, "checkout", "-q", "-b", "polecat/nux/gp-2@2")
+	pushedAfter := commit("gp-2 work")
+	commit("gp-2 work pushed after the merge")
+	runGit(t, src, "checkout", "-q", "-b", "polecat/nux/gp-3@3", base)
+	runGit(t, src, "checkout", "-q", "-b", "polecat/nux/gp-4@4")
+	commit("gp-4 unmerged work")
+	runGit(t, src, "checkout", "-q", "main")
+
+	rigPath := t.TempDir()
+	bare := filepath.Join(rigPath, ".repo.git")
+	runGit(t, rigPath, "clone", "-q", "--bare", src, bare)
+	runGit(t, bare, "fetch", "-q", "origin", "+refs/heads/*:refs/remotes/origin/*")
+
+	var archive []string
+	for _, mr := range []struct{ id, branch, head string }{
+		{"gp-mr-1", "polecat/nux/gp-1@1", merged},
+		{"gp-mr-2", "polecat/nux/gp-2@2", pushedAfter},
+	} {
+		data, _ := json.Marshal(beads.Issue{ID: mr.id, Status: "closed", Type: "merge-request",
+			Description: "branch: " + mr.branch + "\ntarget: main\nstate: merged\nmerge_head: " + mr.head})
+		archive = append(archive, string(data))
+	}
+	writeFile(t, filepath.Join(rigPath, ".beads", beads.MRArchiveFile), strings.Join(archive, "\n")+"\n", time.Now())
+
+	report, err := Run(t.TempDir(), []*rig.Rig{{Name: "gp", Path: rigPath}}, Options{Kinds: []string{KindBranches}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var removed []string
+	for _, item := range report.Items {
+		if item.Error != "" {
+			t.Errorf("%s: %s", item.Target, item.Error)
+		}
+		removed = append(removed, item.Target+" ("+item.Reason+")")
+	}
+	want := "polecat/nux/gp-1@1 (gp-mr-1 merged), polecat/nux/gp-3@3 (on origin/main)"
+	if got := strings.Join(removed, ", "); got != want {
+		t.Errorf("removed %s\nwant %s", got, want)
+	}
+	if got := runGit(t, bare, "branch", "--list", "--format=%(refname:short)", "polecat/*"); got != "polecat/nux/gp-2@2\npolecat/nux/gp-4@4" {
+		t.Errorf("branches left:\n%s", got)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/helper"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -97,7 +100,15 @@ func (g *Git) run(args ...string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	// Read-only queries go to the helper when one is running
+	var err error
+	if res, ok := helper.Run("git", args, g.workDir, os.Environ()); ok {
+		stdout.Write(res.Stdout)
+		stderr.Write(res.Stderr)
+		err = res.Err
+	} else {
+		err = cmd.Run()
+	}
 	if err != nil {
 		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
 	}
@@ -295,6 +306,66 @@ func configureRefspec(repoPath string) error {
 	return nil
 }
 
+// ClonePartial clones a repository as a partial clone: only the objects
+// filter (a git --filter spec such as blob:none or tree:0) lets through are
+// fetched now, the rest on demand from origin. reference, if set, is used as
+// an object reference like CloneWithReference.
+func (g *Git) ClonePartial(url, dest, reference, filter string) error {
+	if err := g.clonePartial(url, dest, reference, filter); err != nil {
+		return err
+	}
+	// Configure hooks path for Gas Town clones
+	if err := configureHooksPath(dest); err != nil {
+		return err
+	}
+	// Configure sparse checkout to exclude .claude/ from source repo
+	return ConfigureSparseCheckout(dest)
+}
+
+// CloneBarePartial is ClonePartial for the shared bare repo. Worktrees of it
+// fetch the objects they check out on demand.
+func (g *Git) CloneBarePartial(url, dest, reference, filter string) error {
+	if err := g.clonePartial(url, dest, reference, filter, "--bare"); err != nil {
+		return err
+	}
+	// Configure refspec so worktrees can fetch and see origin/* refs
+	return configureRefspec(dest)
+}
+
+// clonePartial runs a filtered clone in a temporary directory, isolated from
+// any git repo at the process cwd, and moves it to dest.
+func (g *Git) clonePartial(url, dest, reference, filter string, extra ...string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("creating destination parent: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp("", "gt-clone-*")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	args := append([]string{"clone", "--filter=" + filter}, extra...)
+	if reference != "" {
+		args = append(args, "--reference-if-able", reference)
+	}
+	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
+	cmd := exec.Command("git", append(args, url, tmpDest)...)
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return g.wrapError(err, stdout.String(), stderr.String(), append(args, url))
+	}
+
+	// Move to final destination (handles cross-filesystem moves)
+	if err := moveDir(tmpDest, dest); err != nil {
+		return fmt.Errorf("moving clone to destination: %w", err)
+	}
+	return nil
+}
+
 // CloneBareWithReference clones a bare repository using a local repo as an object reference.
 func (g *Git) CloneBareWithReference(url, dest, reference string) error {
 	// Ensure destination directory's parent exists
@@ -342,6 +413,13 @@ func (g *Git) Fetch(remote string) error {
 	return err
 }
 
+// FetchPrune fetches from the remote, deleting remote-tracking branches
+// whose branch was deleted on the remote.
+func (g *Git) FetchPrune(remote string) error {
+	_, err := g.run("fetch", "--prune", remote)
+	return err
+}
+
 // FetchBranch fetches a specific branch from the remote.
 func (g *Git) FetchBranch(remote, branch string) error {
 	_, err := g.run("fetch", remote, branch)
@@ -427,6 +505,12 @@ func (g *Git) Status() (*GitStatus, error) {
 	return status, nil
 }
 
+// StatusShort returns the short-format status with the branch header line,
+// as a human would read it (git status --short --branch).
+func (g *Git) StatusShort() (string, error) {
+	return g.run("status", "--short", "--branch")
+}
+
 // CurrentBranch returns the current branch name.
 func (g *Git) CurrentBranch() (string, error) {
 	return g.run("rev-parse", "--abbrev-ref", "HEAD")
@@ -501,6 +585,24 @@ func (g *Git) Remotes() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// AddRemote adds a remote with the standard fetch refspec.
+func (g *Git) AddRemote(name, url string) error {
+	_, err := g.run("remote", "add", name, url)
+	return err
+}
+
+// SetRemoteURL changes the URL of an existing remote.
+func (g *Git) SetRemoteURL(name, url string) error {
+	_, err := g.run("remote", "set-url", name, url)
+	return err
+}
+
+// RemoveRemote removes a remote and its remote-tracking branches.
+func (g *Git) RemoveRemote(name string) error {
+	_, err := g.run("remote", "remove", name)
+	return err
+}
+
 // ConfigGet returns the value of a git config key.
 // Returns empty string if the key is not set.
 func (g *Git) ConfigGet(key string) (string, error) {
@@ -518,6 +620,12 @@ func (g *Git) Merge(branch string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to ref, failing if it has diverged.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
 // MergeNoFF merges the given branch with --no-ff flag and a custom message.
 func (g *Git) MergeNoFF(branch, message string) error {
 	_, err := g.run("merge", "--no-ff", "-m", message, branch)
@@ -551,6 +659,13 @@ func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	return err
 }
 
+// DeleteRemoteBranchAt deletes a branch on the remote only if it still
+// points at commit there.
+func (g *Git) DeleteRemoteBranchAt(remote, branch, commit string) error {
+	_, err := g.run("push", "--force-with-lease=refs/heads/"+branch+":"+commit, remote, "--delete", branch)
+	return err
+}
+
 // Rebase rebases the current branch onto the given ref.
 func (g *Git) Rebase(onto string) error {
 	_, err := g.run("rebase", onto)
@@ -570,9 +685,16 @@ func (g *Git) AbortMerge() error {
 // The caller must ensure the working directory is clean before calling this.
 // After return, the working directory is restored to the target branch.
 func (g *Git) CheckConflicts(source, target string) ([]string, error) {
+	conflicts, _, err := g.CheckConflictsDiff(source, target, 0)
+	return conflicts, err
+}
+
+// CheckConflictsDiff is CheckConflicts, also returning the conflicting hunks
+// as ConflictDiff reports them, cut to maxLines lines.
+func (g *Git) CheckConflictsDiff(source, target string, maxLines int) ([]string, string, error) {
 	// Checkout the target branch
 	if err := g.Checkout(target); err != nil {
-		return nil, fmt.Errorf("checkout target %s: %w", target, err)
+		return nil, "", fmt.Errorf("checkout target %s: %w", target, err)
 	}
 
 	// Attempt test merge with --no-commit --no-ff
@@ -584,20 +706,21 @@ func (g *Git) CheckConflicts(source, target string) ([]string, error) {
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is the proper way.
 		conflicts, err := g.GetConflictingFiles()
 		if err == nil && len(conflicts) > 0 {
+			diff, _ := g.ConflictDiff(maxLines)
 			// Abort the test merge (best-effort cleanup)
 			_ = g.AbortMerge()
-			return conflicts, nil
+			return conflicts, diff, nil
 		}
 
 		// No unmerged files detected - this is some other merge error
 		_ = g.AbortMerge()
-		return nil, mergeErr
+		return nil, "", mergeErr
 	}
 
 	// Merge succeeded (no conflicts) - abort the test merge
 	// Use reset since --abort won't work on successful merge (best-effort cleanup)
 	_, _ = g.run("reset", "--hard", "HEAD")
-	return nil, nil
+	return nil, "", nil
 }
 
 // runMergeCheck runs a git merge command and returns error info from both stdout and stderr.
@@ -644,6 +767,21 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// ConflictDiff returns the diff of the files a merge in progress left
+// unmerged, with their conflict markers, cut to maxLines lines (0 for all
+// of it). Call it before aborting the merge.
+func (g *Git) ConflictDiff(maxLines int) (string, error) {
+	out, err := g.run("diff", "--diff-filter=U")
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(out, "\n")
+	if maxLines > 0 && len(lines) > maxLines {
+		out = strings.Join(lines[:maxLines], "\n") + fmt.Sprintf("\n... (%d more lines)", len(lines)-maxLines)
+	}
+	return out, nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -699,6 +837,13 @@ func (g *Git) DeleteBranch(name string, force bool) error {
 	return err
 }
 
+// DeleteBranchAt deletes a local branch only if it still points at commit,
+// so commits added to it since it was checked are never lost.
+func (g *Git) DeleteBranchAt(name, commit string) error {
+	_, err := g.run("update-ref", "-d", "refs/heads/"+name, commit)
+	return err
+}
+
 // ListBranches returns all local branches matching a pattern.
 // Pattern uses git's pattern matching (e.g., "polecat/*" matches all polecat branches).
 // Returns branch names without the refs/heads/ prefix.
@@ -717,6 +862,23 @@ func (g *Git) ListBranches(pattern string) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ListRemoteBranches returns the remote-tracking branches of a remote,
+// without the refs/remotes/<remote>/ prefix. The remote's HEAD is skipped.
+func (g *Git) ListRemoteBranches(remote string) ([]string, error) {
+	prefix := "refs/remotes/" + remote + "/"
+	out, err := g.run("for-each-ref", "--format=%(refname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, ref := range strings.Split(out, "\n") {
+		if name := strings.TrimPrefix(ref, prefix); ref != "" && name != "HEAD" {
+			branches = append(branches, name)
+		}
+	}
+	return branches, nil
+}
+
 // ResetBranch force-updates a branch to point to a ref.
 // This is useful for resetting stale polecat branches to main.
 func (g *Git) ResetBranch(name, ref string) error {
@@ -724,6 +886,25 @@ func (g *Git) ResetBranch(name, ref string) error {
 	return err
 }
 
+// ResetHard moves the current branch to ref, discarding local changes.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
+// UpdateRef points a ref (e.g., refs/gastown/staging/x) at commit, creating
+// it if needed.
+func (g *Git) UpdateRef(ref, commit string) error {
+	_, err := g.run("update-ref", ref, commit)
+	return err
+}
+
+// DeleteRef deletes a ref. Deleting a ref that doesn't exist is not an error.
+func (g *Git) DeleteRef(ref string) error {
+	_, err := g.run("update-ref", "-d", ref)
+	return err
+}
+
 // Rev returns the commit hash for the given ref.
 func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
@@ -762,6 +943,20 @@ func (g *Git) WorktreeAddFromRef(path, branch, startPoint string) error {
 	return ConfigureSparseCheckout(path)
 }
 
+// WorktreeAddFromRefSparse is WorktreeAddFromRef that only checks out paths
+// (see ConfigureSparseCheckoutPaths). The worktree is created without a
+// checkout and populated once the sparse patterns are in place, so the rest of
+// the tree is never written to disk.
+func (g *Git) WorktreeAddFromRefSparse(path, branch, startPoint string, paths []string) error {
+	if len(paths) == 0 {
+		return g.WorktreeAddFromRef(path, branch, startPoint)
+	}
+	if _, err := g.run("worktree", "add", "--no-checkout", "-b", branch, path, startPoint); err != nil {
+		return err
+	}
+	return ConfigureSparseCheckoutPaths(path, paths)
+}
+
 // WorktreeAddDetached creates a new worktree at the given path with a detached HEAD.
 // Sparse checkout is enabled to exclude .claude/ from source repos.
 func (g *Git) WorktreeAddDetached(path, ref string) error {
@@ -794,6 +989,13 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 // This ensures source repo settings don't override Gas Town agent settings.
 // Exported for use by doctor checks.
 func ConfigureSparseCheckout(repoPath string) error {
+	return ConfigureSparseCheckoutPaths(repoPath, nil)
+}
+
+// ConfigureSparseCheckoutPaths is ConfigureSparseCheckout that, when paths is
+// non-empty, also narrows the checkout to those directories plus the files at
+// the repo root, so workers on huge monorepos only materialize what they need.
+func ConfigureSparseCheckoutPaths(repoPath string, paths []string) error {
 	// Enable sparse checkout
 	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout", "true")
 	var stderr bytes.Buffer
@@ -829,8 +1031,7 @@ func ConfigureSparseCheckout(repoPath string) error {
 		return fmt.Errorf("creating info dir: %w", err)
 	}
 	sparseFile := filepath.Join(infoDir, "sparse-checkout")
-	sparsePatterns := "/*\n!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n"
-	if err := os.WriteFile(sparseFile, []byte(sparsePatterns), 0644); err != nil {
+	if err := os.WriteFile(sparseFile, []byte(sparseCheckoutPatterns(paths)), 0644); err != nil {
 		return fmt.Errorf("writing sparse-checkout: %w", err)
 	}
 
@@ -852,6 +1053,26 @@ func ConfigureSparseCheckout(repoPath string) error {
 	return nil
 }
 
+// sparseCheckoutPatterns returns the sparse-checkout patterns for a checkout
+// of paths, or of everything when paths is empty. The root's files are always
+// included and Claude context files always excluded.
+func sparseCheckoutPatterns(paths []string) string {
+	var sb strings.Builder
+	sb.WriteString("/*\n")
+	if len(paths) > 0 {
+		// Leave out the root's directories except the ones asked for
+		sb.WriteString("!/*/\n")
+		for _, p := range paths {
+			p = strings.Trim(filepath.ToSlash(p), "/")
+			if p != "" {
+				sb.WriteString("/" + p + "/\n")
+			}
+		}
+	}
+	sb.WriteString("!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n")
+	return sb.String()
+}
+
 // ExcludedContextFiles lists all Claude context files that should be excluded by sparse checkout.
 // Note: .mcp.json is NOT excluded so worktrees can inherit MCP server config (e.g., Puppeteer).
 var ExcludedContextFiles = []string{
@@ -931,17 +1152,50 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
+// CommonDir returns the absolute path of the repository shared by all
+// worktrees: for a worktree of a bare repo, the bare repo itself.
+func (g *Git) CommonDir() (string, error) {
+	dir, err := g.run("rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(g.workDir, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
 	return err
 }
 
+// WorktreePrunable returns the paths of the worktrees WorktreePrune would
+// forget: those whose checkouts are gone.
+func (g *Git) WorktreePrunable() ([]string, error) {
+	out, err := g.run("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	var current string
+	for _, line := range strings.Split(out, "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			current = path
+		} else if strings.HasPrefix(line, "prunable") && current != "" {
+			paths = append(paths, current)
+		}
+	}
+	return paths, nil
+}
+
 // Worktree represents a git worktree.
 type Worktree struct {
-	Path   string
-	Branch string
-	Commit string
+	Path     string
+	Branch   string
+	Commit   string
+	Detached bool // HEAD is a commit, not a branch
 }
 
 // WorktreeList returns all worktrees for this repository.
@@ -970,6 +1224,8 @@ func (g *Git) WorktreeList() ([]Worktree, error) {
 			current.Commit = strings.TrimPrefix(line, "HEAD ")
 		case strings.HasPrefix(line, "branch "):
 			current.Branch = strings.TrimPrefix(line, "branch refs/heads/")
+		case line == "detached":
+			current.Detached = true
 		}
 	}
 
@@ -1036,6 +1292,225 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FileDiffStat is one file's line counts from git diff --numstat.
+// Binary files report zero added and deleted lines.
+type FileDiffStat struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// DiffStat returns per-file line counts for the changes branch introduces
+// relative to its merge base with base (git diff --numstat base...branch).
+func (g *Git) DiffStat(base, branch string) ([]FileDiffStat, error) {
+	out, err := g.run("diff", "--numstat", "--no-renames", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []FileDiffStat
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		stat := FileDiffStat{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.Binary = true
+		} else {
+			_, _ = fmt.Sscanf(parts[0], "%d", &stat.Added)
+			_, _ = fmt.Sscanf(parts[1], "%d", &stat.Deleted)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// DiffRange returns git diff output for the changes branch introduces
+// relative to its merge base with base, with extra diff flags (e.g. "--stat").
+func (g *Git) DiffRange(base, branch string, flags ...string) (string, error) {
+	args := append([]string{"diff"}, flags...)
+	return g.run(append(args, base+"..."+branch)...)
+}
+
+// CommitTime returns the committer date of ref (a branch, tag, or SHA).
+func (g *Git) CommitTime(ref string) (time.Time, error) {
+	out, err := g.run("log", "-1", "--format=%cI", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
+// TagExists reports whether a tag exists locally.
+func (g *Git) TagExists(name string) (bool, error) {
+	_, err := g.run("rev-parse", "-q", "--verify", "refs/tags/"+name)
+	if err != nil {
+		if gitErr, ok := err.(*GitError); ok && gitErr.Stderr == "" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateTag creates an annotated tag at ref.
+func (g *Git) CreateTag(name, ref, message string) error {
+	_, err := g.run("tag", "-a", name, "-m", message, ref)
+	return err
+}
+
+// LatestTag returns the most recent tag reachable from ref, or "" if there
+// is none.
+func (g *Git) LatestTag(ref string) (string, error) {
+	out, err := g.run("describe", "--tags", "--abbrev=0", ref)
+	if err != nil {
+		if gitErr, ok := err.(*GitError); ok && (strings.Contains(gitErr.Stderr, "No names found") ||
+			strings.Contains(gitErr.Stderr, "No tags can describe")) {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// Revert commits a revert of commit on the current branch. Merge commits are
+// reverted against their first parent (the branch they were merged into).
+func (g *Git) Revert(commit string) error {
+	out, err := g.run("rev-list", "--parents", "-n", "1", commit)
+	if err != nil {
+		return err
+	}
+	args := []string{"revert", "--no-edit"}
+	if len(strings.Fields(out)) > 2 {
+		args = append(args, "-m", "1")
+	}
+	if _, err := g.run(append(args, commit)...); err != nil {
+		_, _ = g.run("revert", "--abort")
+		return err
+	}
+	return nil
+}
+
+// CherryPick commits a copy of commit on the current branch, noting the
+// original in the message (-x). Merge commits are picked against their first
+// parent, bringing over everything the merge brought in.
+func (g *Git) CherryPick(commit string) error {
+	out, err := g.run("rev-list", "--parents", "-n", "1", commit)
+	if err != nil {
+		return err
+	}
+	args := []string{"cherry-pick", "-x"}
+	if len(strings.Fields(out)) > 2 {
+		args = append(args, "-m", "1")
+	}
+	if _, err := g.run(append(args, commit)...); err != nil {
+		_, _ = g.run("cherry-pick", "--abort")
+		return err
+	}
+	return nil
+}
+
+// AmendSigned re-commits HEAD with a signature, keeping its tree, message
+// and author. format is git's gpg.format ("openpgp"/"gpg" or "ssh"); key
+// is the signing key, or "" for user.signingkey.
+func (g *Git) AmendSigned(format, key string) error {
+	if format == "gpg" {
+		format = "openpgp"
+	}
+	_, err := g.run("-c", "gpg.format="+format, "commit", "--amend", "--no-edit", "--allow-empty", "--no-verify", "-S"+key)
+	return err
+}
+
+// AddNote attaches message to commit as a note under ref (e.g.,
+// "refs/notes/gastown/attestations"), replacing any existing note there.
+func (g *Git) AddNote(ref, commit, message string) error {
+	_, err := g.run("notes", "--ref", ref, "add", "-f", "-m", message, commit)
+	return err
+}
+
+// Note returns commit's note under ref.
+func (g *Git) Note(ref, commit string) (string, error) {
+	return g.run("notes", "--ref", ref, "show", commit)
+}
+
+// Bisect runs git bisect between good and bad, judging each commit with
+// command (run via sh -c; exit 0 is good, 125 skips, other codes are bad).
+// Returns the first bad commit. The bisect is always reset afterwards.
+func (g *Git) Bisect(good, bad, command string) (string, error) {
+	if _, err := g.run("bisect", "start", bad, good); err != nil {
+		return "", err
+	}
+	defer func() { _, _ = g.run("bisect", "reset") }()
+
+	out, err := g.run("bisect", "run", "sh", "-c", command)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if sha, ok := strings.CutSuffix(strings.TrimSpace(line), " is the first bad commit"); ok {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("git bisect did not identify a first bad commit:\n%s", out)
+}
+
+// FirstParentCommits returns the commits in base..head following only first
+// parents (the merges and direct commits on head's branch), oldest first.
+func (g *Git) FirstParentCommits(base, head string) ([]string, error) {
+	out, err := g.run("rev-list", "--first-parent", "--reverse", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CommitSummary is a commit and its subject line.
+type CommitSummary struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+// CommitsMentioning returns the latest commits on ref whose message
+// contains text, newest first, at most limit of them.
+func (g *Git) CommitsMentioning(ref, text string, limit int) ([]CommitSummary, error) {
+	out, err := g.run("log", "--fixed-strings", "--grep="+text, fmt.Sprintf("--max-count=%d", limit), "--format=%H%x09%s", ref)
+	if err != nil {
+		return nil, err
+	}
+	var commits []CommitSummary
+	for _, line := range strings.Split(out, "\n") {
+		if sha, subject, ok := strings.Cut(line, "\t"); ok {
+			commits = append(commits, CommitSummary{SHA: sha, Subject: subject})
+		}
+	}
+	return commits, nil
+}
+
+// CommitSummaries returns the non-merge commits in base..head, oldest first.
+func (g *Git) CommitSummaries(base, head string) ([]CommitSummary, error) {
+	out, err := g.run("log", "--no-merges", "--reverse", "--format=%H%x09%s", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+	var commits []CommitSummary
+	for _, line := range strings.Split(out, "\n") {
+		if sha, subject, ok := strings.Cut(line, "\t"); ok {
+			commits = append(commits, CommitSummary{SHA: sha, Subject: subject})
+		}
+	}
+	return commits, nil
+}
+
+// CommitSubject returns the subject line of ref's commit message.
+func (g *Git) CommitSubject(ref string) (string, error) {
+	return g.run("log", "-1", "--format=%s", ref)
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
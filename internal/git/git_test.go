@@ -1,11 +1,13 @@
 package git
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -382,6 +384,216 @@ func TestCheckConflicts_NoConflict(t *testing.T) {
 	}
 }
 
+func TestDiffStat(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\nmore\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("feature changes"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	stats, err := g.DiffStat(mainBranch, "feature")
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	want := map[string][2]int{"README.md": {2, 1}, "new.txt": {3, 0}}
+	if len(stats) != len(want) {
+		t.Fatalf("DiffStat returned %d files, want %d: %+v", len(stats), len(want), stats)
+	}
+	for _, s := range stats {
+		w, ok := want[s.Path]
+		if !ok || s.Added != w[0] || s.Deleted != w[1] {
+			t.Errorf("unexpected stat %+v", s)
+		}
+	}
+
+	names, err := g.DiffRange(mainBranch, "feature", "--name-only")
+	if err != nil {
+		t.Fatalf("DiffRange: %v", err)
+	}
+	if names != "README.md\nnew.txt" {
+		t.Errorf("DiffRange --name-only = %q", names)
+	}
+}
+
+func TestCommitTime(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	got, err := g.CommitTime("HEAD")
+	if err != nil {
+		t.Fatalf("CommitTime: %v", err)
+	}
+	if d := time.Since(got); d < 0 || d > time.Hour {
+		t.Errorf("CommitTime(HEAD) = %v, want about now", got)
+	}
+	if _, err := g.CommitTime("no-such-tag"); err == nil {
+		t.Error("CommitTime of unknown ref should fail")
+	}
+}
+
+func TestTags(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	if tag, err := g.LatestTag("HEAD"); err != nil || tag != "" {
+		t.Fatalf("LatestTag with no tags = %q, %v; want \"\", nil", tag, err)
+	}
+	if exists, err := g.TagExists("v1.0.0"); err != nil || exists {
+		t.Fatalf("TagExists before tagging = %v, %v", exists, err)
+	}
+	if err := g.CreateTag("v1.0.0", "HEAD", "Release v1.0.0"); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if exists, err := g.TagExists("v1.0.0"); err != nil || !exists {
+		t.Errorf("TagExists after tagging = %v, %v", exists, err)
+	}
+	if tag, err := g.LatestTag("HEAD"); err != nil || tag != "v1.0.0" {
+		t.Errorf("LatestTag = %q, %v; want v1.0.0", tag, err)
+	}
+}
+
+func TestRevert_MergeCommit(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("feature.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add feature"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	if err := g.MergeNoFF("feature", "Merge feature"); err != nil {
+		t.Fatalf("MergeNoFF: %v", err)
+	}
+	merge, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+
+	if err := g.Revert(merge); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+		t.Errorf("feature.txt still present after revert (stat err = %v)", err)
+	}
+}
+
+func TestCherryPick_MergeCommit(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("release"); err != nil {
+		t.Fatalf("CreateBranch release: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch feature: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("feature.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add feature"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	if err := g.MergeNoFF("feature", "Merge feature"); err != nil {
+		t.Fatalf("MergeNoFF: %v", err)
+	}
+	merge, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+
+	if err := g.Checkout("release"); err != nil {
+		t.Fatalf("Checkout release: %v", err)
+	}
+	if err := g.CherryPick(merge); err != nil {
+		t.Fatalf("CherryPick: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+		t.Errorf("feature.txt missing after cherry-pick: %v", err)
+	}
+}
+
+func TestBisect(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	good, _ := g.Rev("HEAD")
+
+	// Three commits; the second introduces "bug"
+	var commits []string
+	for i, content := range []string{"ok\n", "bug\n", "bug, still\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "state.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := g.Add("state.txt"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit(fmt.Sprintf("commit %d", i)); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		sha, _ := g.Rev("HEAD")
+		commits = append(commits, sha)
+	}
+
+	culprit, err := g.Bisect(good, "HEAD", "! grep -q bug state.txt")
+	if err != nil {
+		t.Fatalf("Bisect: %v", err)
+	}
+	if culprit != commits[1] {
+		t.Errorf("Bisect = %s, want %s", culprit, commits[1])
+	}
+	if subject, _ := g.CommitSubject(culprit); subject != "commit 1" {
+		t.Errorf("CommitSubject = %q, want %q", subject, "commit 1")
+	}
+
+	line, err := g.FirstParentCommits(good, "HEAD")
+	if err != nil {
+		t.Fatalf("FirstParentCommits: %v", err)
+	}
+	if len(line) != 3 || line[0] != commits[0] || line[2] != commits[2] {
+		t.Errorf("FirstParentCommits = %v, want %v", line, commits)
+	}
+}
+
 func TestCheckConflicts_WithConflict(t *testing.T) {
 	dir := initTestRepo(t)
 	g := NewGit(dir)
@@ -452,6 +664,81 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 	}
 }
 
+func TestCheckConflictsDiff_WithConflict(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	// Create feature branch
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+
+	// Modify README.md on feature branch
+	readmeFile := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readmeFile, []byte("# Feature changes\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("README.md"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("modify readme on feature"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	// Go back to main and make conflicting change
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	if err := os.WriteFile(readmeFile, []byte("# Main changes\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("README.md"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("modify readme on main"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	// Check for conflicts - should find README.md and show both sides
+	conflicts, diff, err := g.CheckConflictsDiff("feature", mainBranch, 0)
+	if err != nil {
+		t.Fatalf("CheckConflictsDiff: %v", err)
+	}
+	if len(conflicts) == 0 {
+		t.Error("expected conflicts, got none")
+	}
+	for _, want := range []string{"README.md", "<<<<<<<", "Main changes", "Feature changes", ">>>>>>>"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("conflict diff missing %q:\n%s", want, diff)
+		}
+	}
+
+	foundReadme := false
+	for _, f := range conflicts {
+		if f == "README.md" {
+			foundReadme = true
+			break
+		}
+	}
+	if !foundReadme {
+		t.Errorf("expected README.md in conflicts, got %v", conflicts)
+	}
+
+	// Verify we're still on main and clean
+	branch, _ := g.CurrentBranch()
+	if branch != mainBranch {
+		t.Errorf("branch = %q, want %q", branch, mainBranch)
+	}
+	status, _ := g.Status()
+	if !status.Clean {
+		t.Error("expected clean working directory after CheckConflicts")
+	}
+}
+
 // TestCloneBareHasOriginRefs verifies that after CloneBare, origin/* refs
 // are available for worktree creation. This was broken before the fix:
 // bare clones had refspec configured but no fetch was run, so origin/main
@@ -543,3 +830,146 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestNotes(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	head, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+
+	const ref = "refs/notes/test"
+	if _, err := g.Note(ref, head); err == nil {
+		t.Error("Note should fail before a note is added")
+	}
+	if err := g.AddNote(ref, head, "first"); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	// Adding again replaces the note
+	if err := g.AddNote(ref, head, "second"); err != nil {
+		t.Fatalf("AddNote again: %v", err)
+	}
+	if note, err := g.Note(ref, head); err != nil || note != "second" {
+		t.Errorf("Note = %q, %v; want %q", note, err, "second")
+	}
+}
+
+func TestCommitsMentioning(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	for _, msg := range []string{"Fix retry (gp-42)", "Unrelated", "Test retry\n\nRefs gp-42"} {
+		if out, err := exec.Command("git", "-C", dir, "commit", "--allow-empty", "-m", msg).CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+
+	commits, err := g.CommitsMentioning("HEAD", "gp-42", 10)
+	if err != nil {
+		t.Fatalf("CommitsMentioning: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "Test retry" || commits[1].Subject != "Fix retry (gp-42)" {
+		t.Errorf("commits = %+v", commits)
+	}
+	if commits, _ := g.CommitsMentioning("HEAD", "gp-42", 1); len(commits) != 1 {
+		t.Errorf("limit 1: got %d commits", len(commits))
+	}
+	if commits, _ := g.CommitsMentioning("HEAD", "gp-4[2]", 10); len(commits) != 0 {
+		t.Errorf("text should match literally: %+v", commits)
+	}
+}
+
+func TestCloneBarePartialSparseWorktree(t *testing.T) {
+	tmp := t.TempDir()
+	remoteDir := initTestRepo(t)
+	for _, f := range []string{"services/api/main.go", "services/web/main.go", "docs/guide.md", ".claude/settings.json"} {
+		path := filepath.Join(remoteDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(f+"\n"), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	for _, args := range [][]string{
+		{"add", "."},
+		{"commit", "-m", "tree"},
+		{"config", "uploadpack.allowFilter", "true"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = remoteDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	branch, err := NewGit(remoteDir).CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+
+	bareDir := filepath.Join(tmp, "bare.git")
+	if err := NewGit(tmp).CloneBarePartial("file://"+remoteDir, bareDir, "", "blob:none"); err != nil {
+		t.Fatalf("CloneBarePartial: %v", err)
+	}
+	bareGit := NewGitWithDir(bareDir, "")
+	if filter, err := bareGit.run("config", "remote.origin.partialclonefilter"); err != nil || filter != "blob:none" {
+		t.Errorf("partialclonefilter = %q, %v; want blob:none", filter, err)
+	}
+
+	worktreePath := filepath.Join(tmp, "worktree")
+	if err := bareGit.WorktreeAddFromRefSparse(worktreePath, "polecat/test", "origin/"+branch, []string{"services/api/"}); err != nil {
+		t.Fatalf("WorktreeAddFromRefSparse: %v", err)
+	}
+	for f, want := range map[string]bool{
+		"README.md":             true,
+		"services/api/main.go":  true,
+		"services/web/main.go":  false,
+		"docs/guide.md":         false,
+		".claude/settings.json": false,
+	} {
+		_, err := os.Stat(filepath.Join(worktreePath, f))
+		if got := err == nil; got != want {
+			t.Errorf("%s checked out = %v, want %v", f, got, want)
+		}
+	}
+	if !IsSparseCheckoutConfigured(worktreePath) {
+		t.Error("IsSparseCheckoutConfigured = false, want true")
+	}
+	status, err := NewGit(worktreePath).Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.Clean {
+		t.Errorf("sparse worktree not clean: %+v", status)
+	}
+}
+
+func TestWorktreePrunable(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	live := filepath.Join(t.TempDir(), "live")
+	gone := filepath.Join(t.TempDir(), "gone")
+	if err := g.WorktreeAdd(live, "live"); err != nil {
+		t.Fatalf("WorktreeAdd: %v", err)
+	}
+	if err := g.WorktreeAdd(gone, "gone"); err != nil {
+		t.Fatalf("WorktreeAdd: %v", err)
+	}
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	prunable, err := g.WorktreePrunable()
+	if err != nil {
+		t.Fatalf("WorktreePrunable: %v", err)
+	}
+	if len(prunable) != 1 || filepath.Base(prunable[0]) != "gone" {
+		t.Fatalf("WorktreePrunable = %v, want only the removed checkout", prunable)
+	}
+	if err := g.WorktreePrune(); err != nil {
+		t.Fatalf("WorktreePrune: %v", err)
+	}
+	if prunable, _ := g.WorktreePrunable(); len(prunable) != 0 {
+		t.Errorf("after pruning, WorktreePrunable = %v", prunable)
+	}
+}
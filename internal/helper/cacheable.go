@@ -0,0 +1,69 @@
+package helper
+
+import "strings"
+
+// cacheableBd are bd subcommands that only read the local database. bd show
+// is left out: it follows routes.jsonl to other rigs' databases, whose
+// changes a cached result would not notice.
+var cacheableBd = map[string]bool{
+	"list":    true,
+	"ready":   true,
+	"blocked": true,
+	"search":  true,
+	"count":   true,
+}
+
+// cacheableGit are git subcommands whose output depends only on refs,
+// config, and immutable objects, never on the working tree.
+var cacheableGit = map[string]bool{
+	"rev-parse":    true,
+	"rev-list":     true,
+	"merge-base":   true,
+	"show-ref":     true,
+	"for-each-ref": true,
+}
+
+// Cacheable reports whether a bd or git invocation is a read-only query the
+// helper may answer. Anything else is run directly by the caller.
+func Cacheable(tool string, args []string) bool {
+	switch tool {
+	case "bd":
+		return cacheableBd[subcommand(args, "--db")]
+	case "git":
+		// Only --git-dir= globals are understood when fingerprinting; with
+		// -C or -c the subcommand reads as their value and is not cached.
+		return cacheableGit[subcommand(args)]
+	}
+	return false
+}
+
+// subcommand returns the first argument that isn't a global flag or the
+// value of one of valueFlags.
+func subcommand(args []string, valueFlags ...string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+		for _, f := range valueFlags {
+			if arg == f {
+				i++
+				break
+			}
+		}
+	}
+	return ""
+}
+
+// flagValue returns the value of a "--name value" or "--name=value" flag.
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+// Package helper implements an optional long-lived process that answers the
+// CLI's read-only bd and git queries over a unix socket.
+//
+// Most gt latency is spawning bd and git, often with the same arguments
+// several times per command and across commands. The helper keeps the
+// results of read-only queries and serves them again until the beads
+// database or the repo's refs change, so a repeated query costs a socket
+// round trip instead of a process. Writes never go through the helper.
+//
+// The helper is optional: when it is not running, or anything goes wrong
+// talking to it, Run reports ok=false and the caller execs directly.
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// SocketEnv overrides the helper socket path.
+const SocketEnv = "GT_HELPER_SOCKET"
+
+// DisableEnv turns the helper off for a process when set to "0", so every
+// query execs directly even if a helper is listening.
+const DisableEnv = "GT_HELPER"
+
+// dialTimeout bounds how long the CLI waits to reach a helper before
+// running the command itself.
+const dialTimeout = 100 * time.Millisecond
+
+// SocketPath returns the helper socket. There is one helper per user; it
+// serves every town, keyed by directory.
+func SocketPath() string {
+	if p := os.Getenv(SocketEnv); p != "" {
+		return p
+	}
+	return filepath.Join(state.StateDir(), "helper.sock")
+}
+
+// request is one message from the CLI to the helper, as a JSON line.
+type request struct {
+	Op   string   `json:"op"` // "exec", "stats", or "shutdown"
+	Tool string   `json:"tool,omitempty"`
+	Args []string `json:"args,omitempty"`
+	Dir  string   `json:"dir,omitempty"`
+	Env  []string `json:"env,omitempty"`
+}
+
+// response is the helper's reply to a request.
+type response struct {
+	Stdout   []byte `json:"stdout,omitempty"`
+	Stderr   []byte `json:"stderr,omitempty"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"` // request could not be served
+	Cached   bool   `json:"cached,omitempty"`
+	Stats    *Stats `json:"stats,omitempty"`
+}
+
+// Stats describes a running helper.
+type Stats struct {
+	PID       int       `json:"pid"`
+	Socket    string    `json:"socket"`
+	StartedAt time.Time `json:"started_at"`
+	Requests  int64     `json:"requests"`
+	Hits      int64     `json:"hits"`
+	Entries   int       `json:"entries"`
+}
+
+// ExitError reports a command the helper ran that exited non-zero. It
+// formats like *exec.ExitError so wrapped error messages don't change.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// Result is the outcome of a command run by the helper.
+type Result struct {
+	Stdout []byte
+	Stderr []byte
+	// Err is nil on success or *ExitError for a non-zero exit.
+	Err    error
+	Cached bool
+}
+
+// Run asks the helper to run tool with args in dir, with env as the
+// command's environment. Only read-only queries (see Cacheable) are sent;
+// ok is false for anything else, when the helper is disabled or not
+// running, or if the exchange fails, and the caller should exec the
+// command itself. Falling back is always safe because the queries are
+// read-only.
+func Run(tool string, args []string, dir string, env []string) (*Result, bool) {
+	if os.Getenv(DisableEnv) == "0" || !Cacheable(tool, args) {
+		return nil, false
+	}
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return nil, false
+		}
+	}
+	resp, err := call(SocketPath(), request{Op: "exec", Tool: tool, Args: args, Dir: dir, Env: env})
+	if err != nil || resp.Error != "" {
+		return nil, false
+	}
+
+	res := &Result{Stdout: resp.Stdout, Stderr: resp.Stderr, Cached: resp.Cached}
+	if resp.ExitCode != 0 {
+		res.Err = &ExitError{Code: resp.ExitCode}
+	}
+	return res, true
+}
+
+// GetStats returns the stats of the helper listening on socket.
+func GetStats(socket string) (*Stats, error) {
+	resp, err := call(socket, request{Op: "stats"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stats == nil {
+		return nil, fmt.Errorf("helper returned no stats")
+	}
+	return resp.Stats, nil
+}
+
+// Shutdown asks the helper listening on socket to exit.
+func Shutdown(socket string) error {
+	_, err := call(socket, request{Op: "shutdown"})
+	return err
+}
+
+// call sends one request and reads the response.
+func call(socket string, req request) (*response, error) {
+	// Cheap check first: most processes run without a helper.
+	if _, err := os.Stat(socket); err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,173 @@
+//go:build !windows
+
+package helper
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer runs a helper on a temp socket and points the client at it.
+func startServer(t *testing.T) *Server {
+	t.Helper()
+	// Short path: unix socket paths are limited to ~100 bytes
+	dir, err := os.MkdirTemp("", "gt-helper-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "h.sock")
+	t.Setenv(SocketEnv, socket)
+	t.Setenv(DisableEnv, "")
+
+	ln, err := Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(socket)
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		s.Close()
+		<-done
+	})
+	return s
+}
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		tool string
+		args []string
+		want bool
+	}{
+		{"bd", []string{"--no-daemon", "--allow-stale", "list", "--json"}, true},
+		{"bd", []string{"--db", "/x/beads.db", "--no-daemon", "ready", "--json"}, true},
+		{"bd", []string{"--no-daemon", "show", "gt-1", "--json"}, false},
+		{"bd", []string{"--no-daemon", "update", "gt-1", "--status=closed"}, false},
+		{"git", []string{"rev-parse", "HEAD"}, true},
+		{"git", []string{"--git-dir=/r/.repo.git", "rev-list", "--count", "a..b"}, true},
+		{"git", []string{"-C", "/r", "merge-base", "a", "b"}, false},
+		{"git", []string{"status", "--porcelain"}, false},
+		{"git", []string{"fetch", "origin"}, false},
+		{"tmux", []string{"list-sessions"}, false},
+	}
+	for _, tt := range tests {
+		if got := Cacheable(tt.tool, tt.args); got != tt.want {
+			t.Errorf("Cacheable(%s %v) = %v, want %v", tt.tool, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithoutHelper(t *testing.T) {
+	t.Setenv(SocketEnv, filepath.Join(t.TempDir(), "none.sock"))
+	if _, ok := Run("git", []string{"rev-parse", "HEAD"}, t.TempDir(), os.Environ()); ok {
+		t.Error("Run should report ok=false when no helper is listening")
+	}
+}
+
+func TestRunGitInvalidatesOnRefChange(t *testing.T) {
+	s := startServer(t)
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	repo := t.TempDir()
+	gitOut(t, repo, "init", "-b", "main")
+	gitOut(t, repo, "commit", "--allow-empty", "-m", "one")
+	first := gitOut(t, repo, "rev-parse", "HEAD")
+
+	for i := 0; i < 2; i++ {
+		res, ok := Run("git", []string{"rev-parse", "HEAD"}, repo, os.Environ())
+		if !ok || res.Err != nil {
+			t.Fatalf("Run = %+v, %v", res, ok)
+		}
+		if got := strings.TrimSpace(string(res.Stdout)); got != first {
+			t.Errorf("rev-parse HEAD = %s, want %s", got, first)
+		}
+		if res.Cached != (i == 1) {
+			t.Errorf("query %d cached = %v", i, res.Cached)
+		}
+	}
+
+	gitOut(t, repo, "commit", "--allow-empty", "-m", "two")
+	second := gitOut(t, repo, "rev-parse", "HEAD")
+	res, ok := Run("git", []string{"rev-parse", "HEAD"}, repo, os.Environ())
+	if !ok || res.Cached || strings.TrimSpace(string(res.Stdout)) != second {
+		t.Errorf("after commit: %+v, want fresh %s", res, second)
+	}
+
+	// Failures are passed through with their exit code, not cached
+	res, ok = Run("git", []string{"rev-parse", "--verify", "nope"}, repo, os.Environ())
+	if !ok {
+		t.Fatal("expected the helper to answer")
+	}
+	if exitErr, isExit := res.Err.(*ExitError); !isExit || exitErr.Code == 0 {
+		t.Errorf("err = %v, want non-zero *ExitError", res.Err)
+	}
+
+	if stats := s.Stats(); stats.Hits != 1 {
+		t.Errorf("hits = %d, want 1", stats.Hits)
+	}
+}
+
+func TestRunBdInvalidatesOnDatabaseWrite(t *testing.T) {
+	startServer(t)
+
+	// Fake bd on the caller's PATH (not the helper's) that counts runs
+	binDir := t.TempDir()
+	countFile := filepath.Join(t.TempDir(), "count")
+	script := "#!/bin/sh\necho x >> " + countFile + "\necho '[]'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	beadsDir := t.TempDir()
+	db := filepath.Join(beadsDir, "beads.db")
+	if err := os.WriteFile(db, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := append(os.Environ(), "PATH="+binDir, "BEADS_DIR="+beadsDir)
+	args := []string{"--no-daemon", "list", "--json"}
+	runs := func() int {
+		data, _ := os.ReadFile(countFile)
+		return strings.Count(string(data), "x")
+	}
+
+	for i := 0; i < 3; i++ {
+		if res, ok := Run("bd", args, beadsDir, env); !ok || string(res.Stdout) != "[]\n" {
+			t.Fatalf("Run = %+v, %v", res, ok)
+		}
+	}
+	if n := runs(); n != 1 {
+		t.Errorf("bd ran %d times for 3 identical queries, want 1", n)
+	}
+
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(db, future, future); err != nil {
+		t.Fatal(err)
+	}
+	Run("bd", args, beadsDir, env)
+	if n := runs(); n != 2 {
+		t.Errorf("bd ran %d times after a database write, want 2", n)
+	}
+
+	// Writes are never sent to the helper
+	if _, ok := Run("bd", []string{"--no-daemon", "close", "gt-1"}, beadsDir, env); ok {
+		t.Error("bd close should not go through the helper")
+	}
+}
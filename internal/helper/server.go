@@ -0,0 +1,421 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxAge bounds how long a cached result is served even while its
+// fingerprint is unchanged, as a backstop for changes the fingerprint
+// can't see (e.g. a database on a Dolt server).
+const DefaultMaxAge = 30 * time.Second
+
+// maxEntries caps the cache. Past it the cache is simply dropped; entries
+// are cheap to recompute.
+const maxEntries = 4096
+
+// keyEnv are the environment variables that change what a query returns,
+// and so are part of the cache key.
+var keyEnv = []string{"BEADS_DIR", "BEADS_DB", "GIT_DIR", "GIT_WORK_TREE", "GIT_COMMON_DIR", "GIT_INDEX_FILE"}
+
+// Server answers helper requests. Create one with NewServer and run it with
+// Serve.
+type Server struct {
+	// MaxAge is the longest a cached result is served. Zero disables
+	// caching.
+	MaxAge time.Duration
+	// IdleTimeout makes Serve return after this long without a request.
+	// Zero means run until Close.
+	IdleTimeout time.Duration
+
+	socket    string
+	startedAt time.Time
+
+	mu      sync.Mutex
+	cache   map[string]*entry
+	gitDirs map[string][2]string // dir+flags -> git dir, common dir
+
+	requests   atomic.Int64
+	hits       atomic.Int64
+	lastActive atomic.Int64
+
+	ln        net.Listener
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// entry is a cached successful query.
+type entry struct {
+	fingerprint string
+	at          time.Time
+	stdout      []byte
+	stderr      []byte
+}
+
+// NewServer returns a server that reports socket in its stats.
+func NewServer(socket string) *Server {
+	return &Server{
+		MaxAge:    DefaultMaxAge,
+		socket:    socket,
+		startedAt: time.Now(),
+		cache:     make(map[string]*entry),
+		gitDirs:   make(map[string][2]string),
+		done:      make(chan struct{}),
+	}
+}
+
+// Listen returns the listener to serve on. Under systemd socket activation
+// (LISTEN_FDS set for this process) it uses the inherited socket;
+// otherwise it creates socket, replacing a stale one left by a helper that
+// died. It fails if another helper is already answering on socket.
+func Listen(socket string) (net.Listener, error) {
+	if pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID")); pid == os.Getpid() {
+		if n, _ := strconv.Atoi(os.Getenv("LISTEN_FDS")); n >= 1 {
+			// systemd passes sockets starting at fd 3
+			f := os.NewFile(3, "helper.sock")
+			defer f.Close()
+			return net.FileListener(f)
+		}
+	}
+
+	if _, err := GetStats(socket); err == nil {
+		return nil, fmt.Errorf("a helper is already running on %s", socket)
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return nil, fmt.Errorf("creating socket dir: %w", err)
+	}
+	_ = os.Remove(socket)
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	// Only the owner may talk to the helper: it runs commands as them.
+	if err := os.Chmod(socket, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve accepts requests on ln until Close, a shutdown request, or the
+// idle timeout.
+func (s *Server) Serve(ln net.Listener) error {
+	s.ln = ln
+	s.lastActive.Store(time.Now().UnixNano())
+	if s.IdleTimeout > 0 {
+		go s.watchIdle()
+	}
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		s.lastActive.Store(time.Now().UnixNano())
+		go s.handle(conn)
+	}
+}
+
+// Close stops Serve.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		if s.ln != nil {
+			s.ln.Close()
+		}
+	})
+}
+
+func (s *Server) watchIdle() {
+	ticker := time.NewTicker(s.IdleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, s.lastActive.Load())) >= s.IdleTimeout {
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+// Stats returns the server's current stats.
+func (s *Server) Stats() *Stats {
+	s.mu.Lock()
+	entries := len(s.cache)
+	s.mu.Unlock()
+	return &Stats{
+		PID:       os.Getpid(),
+		Socket:    s.socket,
+		StartedAt: s.startedAt,
+		Requests:  s.requests.Load(),
+		Hits:      s.hits.Load(),
+		Entries:   entries,
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	var resp *response
+	switch req.Op {
+	case "exec":
+		resp = s.exec(req)
+	case "stats":
+		resp = &response{Stats: s.Stats()}
+	case "shutdown":
+		resp = &response{}
+		defer s.Close()
+	default:
+		resp = &response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// exec answers a query from the cache, or runs it and caches a success.
+func (s *Server) exec(req request) *response {
+	s.requests.Add(1)
+	if !Cacheable(req.Tool, req.Args) {
+		return &response{Error: "not a read-only query"}
+	}
+
+	// Not found: the caller's own exec reports it the usual way
+	bin, err := lookPath(req.Tool, envValue(req.Env, "PATH"))
+	if err != nil {
+		return &response{Error: err.Error()}
+	}
+
+	// Fingerprint before running: a write that lands mid-query changes the
+	// fingerprint, so the result is not served again.
+	key := cacheKey(bin, req)
+	fingerprint := ""
+	if s.MaxAge > 0 {
+		fingerprint = s.fingerprint(req)
+	}
+	if fingerprint != "" {
+		if e := s.lookup(key, fingerprint); e != nil {
+			s.hits.Add(1)
+			return &response{Stdout: e.stdout, Stderr: e.stderr, Cached: true}
+		}
+	}
+
+	cmd := exec.Command(bin, req.Args...) //nolint:gosec // G204: bd and git on the caller's own PATH
+	cmd.Dir = req.Dir
+	cmd.Env = req.Env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &response{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), ExitCode: exitErr.ExitCode()}
+		}
+		// Couldn't start (e.g. dir gone): let the caller run it and see why
+		return &response{Error: err.Error()}
+	}
+
+	if fingerprint != "" {
+		s.store(key, &entry{
+			fingerprint: fingerprint,
+			at:          time.Now(),
+			stdout:      stdout.Bytes(),
+			stderr:      stderr.Bytes(),
+		})
+	}
+	return &response{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+}
+
+func (s *Server) lookup(key, fingerprint string) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.cache[key]
+	if e == nil || e.fingerprint != fingerprint || time.Since(e.at) > s.MaxAge {
+		return nil
+	}
+	return e
+}
+
+func (s *Server) store(key string, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxEntries {
+		s.cache = make(map[string]*entry)
+	}
+	s.cache[key] = e
+}
+
+// fingerprint identifies the state a query reads, or returns "" if the
+// query can't be cached safely.
+func (s *Server) fingerprint(req request) string {
+	switch req.Tool {
+	case "bd":
+		return beadsFingerprint(req)
+	case "git":
+		return s.gitFingerprint(req)
+	}
+	return ""
+}
+
+// beadsFingerprint covers the SQLite database the query reads. Databases
+// without a local beads.db (e.g. on a Dolt server) are not cached.
+func beadsFingerprint(req request) string {
+	dir := envValue(req.Env, "BEADS_DIR")
+	if db := flagValue(req.Args, "--db"); db != "" {
+		dir = filepath.Dir(db)
+	}
+	if dir == "" {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(dir, "beads.db")); err != nil {
+		return ""
+	}
+	return statFingerprint(dir, "beads.db", "beads.db-wal", "issues.jsonl")
+}
+
+// gitFingerprint covers the refs, HEADs, and config of the repo the query
+// runs in.
+func (s *Server) gitFingerprint(req request) string {
+	gitDir, commonDir, ok := s.resolveGitDirs(req)
+	if !ok {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write([]byte(statFingerprint(gitDir, "HEAD", "FETCH_HEAD", "ORIG_HEAD", "MERGE_HEAD")))
+	h.Write([]byte(statFingerprint(commonDir, "packed-refs", "config")))
+	for _, refs := range []string{filepath.Join(commonDir, "refs"), filepath.Join(gitDir, "refs")} {
+		_ = filepath.WalkDir(refs, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				fmt.Fprintf(h, "%s:%d:%d,", path, info.Size(), info.ModTime().UnixNano())
+			}
+			return nil
+		})
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// resolveGitDirs returns the git dir and common dir for a query, asking git
+// the first time a directory is seen.
+func (s *Server) resolveGitDirs(req request) (string, string, bool) {
+	var globals []string
+	for _, arg := range req.Args {
+		if !strings.HasPrefix(arg, "--git-dir=") {
+			break
+		}
+		globals = append(globals, arg)
+	}
+	key := req.Dir + "\x00" + strings.Join(globals, "\x00") + "\x00" + envValue(req.Env, "GIT_DIR")
+
+	s.mu.Lock()
+	dirs, ok := s.gitDirs[key]
+	s.mu.Unlock()
+	if ok {
+		return dirs[0], dirs[1], true
+	}
+
+	cmd := exec.Command("git", append(globals, "rev-parse", "--absolute-git-dir", "--git-common-dir")...)
+	cmd.Dir = req.Dir
+	cmd.Env = req.Env
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", false
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		return "", "", false
+	}
+	gitDir, commonDir := lines[0], lines[1]
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(req.Dir, commonDir)
+	}
+	dirs = [2]string{gitDir, filepath.Clean(commonDir)}
+
+	s.mu.Lock()
+	s.gitDirs[key] = dirs
+	s.mu.Unlock()
+	return dirs[0], dirs[1], true
+}
+
+// statFingerprint joins the size and modification time of the named files
+// in dir. Missing files are skipped.
+func statFingerprint(dir string, names ...string) string {
+	var parts []string
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", name, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, ",")
+}
+
+func cacheKey(bin string, req request) string {
+	var b strings.Builder
+	b.WriteString(bin)
+	b.WriteByte(0)
+	b.WriteString(req.Dir)
+	for _, arg := range req.Args {
+		b.WriteByte(0)
+		b.WriteString(arg)
+	}
+	for _, name := range keyEnv {
+		b.WriteByte(0)
+		b.WriteString(envValue(req.Env, name))
+	}
+	return b.String()
+}
+
+// envValue returns the last value of name in env, as exec does.
+func envValue(env []string, name string) string {
+	value := ""
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, name+"="); ok {
+			value = v
+		}
+	}
+	return value
+}
+
+// lookPath finds tool on the caller's PATH rather than the helper's, so a
+// caller with a different bd (e.g. a test's fake) gets that one.
+func lookPath(tool, path string) (string, error) {
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+		candidate := filepath.Join(dir, tool)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return candidate, nil
+		}
+	}
+	return "", exec.ErrNotFound
+}
@@ -0,0 +1,326 @@
+// Package issuetmpl renders and parses per-rig issue templates: the fields
+// and sections an issue of each type is written in, so issues carry the
+// same structure instead of free text. Templates come from the
+// "issue_templates" section of a rig's settings/config.json, over built-in
+// ones for bugs, features and chores.
+package issuetmpl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrInvalid indicates a malformed issue template.
+var ErrInvalid = errors.New("invalid issue template")
+
+// Title is the field every template has, filled in as the issue's title.
+const Title = "Title"
+
+// DefaultPriority is the priority of issues from a template that sets none.
+const DefaultPriority = 2
+
+// DefaultSection heads the description of a template without sections.
+const DefaultSection = "Description"
+
+const heading = "## "
+
+var htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// builtin are the templates every rig has unless it replaces them.
+var builtin = map[string]*config.IssueTemplateConfig{
+	"bug": {
+		Sections: []string{"Steps to Reproduce", "Expected", "Actual"},
+		Required: []string{"Steps to Reproduce"},
+	},
+	"feature": {
+		Sections: []string{"Summary", "Acceptance Criteria"},
+		Required: []string{"Summary", "Acceptance Criteria"},
+	},
+	"chore": {
+		Sections: []string{"Summary"},
+		Required: []string{"Summary"},
+	},
+}
+
+// Template is the structure of one issue type's issues.
+type Template struct {
+	issueType string
+	fields    []string
+	sections  []string
+	required  map[string]bool // Lowercased names; always has Title
+	priority  int
+	labels    []string
+}
+
+// New builds a Template for issueType from config. A nil cfg is a template
+// with just a title and a description.
+func New(issueType string, cfg *config.IssueTemplateConfig) (*Template, error) {
+	if cfg == nil {
+		cfg = &config.IssueTemplateConfig{}
+	}
+	t := &Template{
+		issueType: issueType,
+		required:  map[string]bool{strings.ToLower(Title): true},
+		priority:  DefaultPriority,
+	}
+	seen := map[string]bool{strings.ToLower(Title): true}
+	add := func(kind, name string, badChars string) (string, error) {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" || strings.ContainsAny(trimmed, badChars) {
+			return "", fmt.Errorf("%w: %s: bad %s name %q", ErrInvalid, issueType, kind, name)
+		}
+		if seen[strings.ToLower(trimmed)] {
+			return "", fmt.Errorf("%w: %s: %q listed twice (or is the title)", ErrInvalid, issueType, trimmed)
+		}
+		seen[strings.ToLower(trimmed)] = true
+		return trimmed, nil
+	}
+	for _, field := range cfg.Fields {
+		name, err := add("field", field, "\n#:")
+		if err != nil {
+			return nil, err
+		}
+		t.fields = append(t.fields, name)
+	}
+	for _, section := range cfg.Sections {
+		name, err := add("section", section, "\n#")
+		if err != nil {
+			return nil, err
+		}
+		t.sections = append(t.sections, name)
+	}
+	if len(t.sections) == 0 {
+		t.sections = []string{DefaultSection}
+	}
+	for _, name := range cfg.Required {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if !seen[key] {
+			return nil, fmt.Errorf("%w: %s: required %q is not a field or section", ErrInvalid, issueType, name)
+		}
+		t.required[key] = true
+	}
+	if cfg.Priority != nil {
+		if *cfg.Priority < 0 || *cfg.Priority > 4 {
+			return nil, fmt.Errorf("%w: %s: priority %d is not 0-4", ErrInvalid, issueType, *cfg.Priority)
+		}
+		t.priority = *cfg.Priority
+	}
+	for _, label := range cfg.Labels {
+		if label == "" || strings.ContainsAny(label, ", \n") {
+			return nil, fmt.Errorf("%w: %s: bad label %q", ErrInvalid, issueType, label)
+		}
+		t.labels = append(t.labels, label)
+	}
+	return t, nil
+}
+
+// Templates are a rig's issue templates, by type.
+type Templates map[string]*Template
+
+// Load reads a rig's issue templates: the built-in ones, replaced or added
+// to by its settings/config.json's issue_templates.
+func Load(rigPath string) (Templates, error) {
+	configs := make(map[string]*config.IssueTemplateConfig, len(builtin))
+	for issueType, cfg := range builtin {
+		configs[issueType] = cfg
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+	if settings != nil {
+		for issueType, cfg := range settings.IssueTemplates {
+			configs[issueType] = cfg
+		}
+	}
+
+	templates := make(Templates, len(configs))
+	for issueType, cfg := range configs {
+		if issueType == "" || strings.ContainsAny(issueType, ", \n:") {
+			return nil, fmt.Errorf("%w: bad issue type %q", ErrInvalid, issueType)
+		}
+		t, err := New(issueType, cfg)
+		if err != nil {
+			return nil, err
+		}
+		templates[issueType] = t
+	}
+	return templates, nil
+}
+
+// Types returns the issue types with templates, sorted.
+func (ts Templates) Types() []string {
+	types := make([]string, 0, len(ts))
+	for issueType := range ts {
+		types = append(types, issueType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Get returns the template for issueType.
+func (ts Templates) Get(issueType string) (*Template, error) {
+	if t, ok := ts[issueType]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("no issue template for type %q (have: %s)", issueType, strings.Join(ts.Types(), ", "))
+}
+
+// Type returns the issue type the template is for.
+func (t *Template) Type() string { return t.issueType }
+
+// Priority returns the default priority of the template's issues.
+func (t *Template) Priority() int { return t.priority }
+
+// Labels returns the labels added to the template's issues.
+func (t *Template) Labels() []string { return t.labels }
+
+// Names returns the template's fields, Title first, then its sections.
+func (t *Template) Names() []string {
+	names := append([]string{Title}, t.fields...)
+	return append(names, t.sections...)
+}
+
+// Required returns the fields and sections that must be filled in, in
+// template order.
+func (t *Template) Required() []string {
+	var required []string
+	for _, name := range t.Names() {
+		if t.required[strings.ToLower(name)] {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+// Canonical returns the field or section name matches (case-insensitive),
+// as the template spells it.
+func (t *Template) Canonical(name string) (string, bool) {
+	for _, n := range t.Names() {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+func (t *Template) isField(name string) bool {
+	if strings.EqualFold(name, Title) {
+		return true
+	}
+	for _, f := range t.fields {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns the template for editing: a comment saying what to fill
+// in, a line per field, then a heading per section. values supplies text by
+// canonical name.
+func (t *Template) Render(values map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!--\nNew %s. Fill in the fields and write under each heading;\n", t.issueType)
+	fmt.Fprintf(&b, "required: %s. ", strings.Join(t.Required(), ", "))
+	b.WriteString("Empty sections and comments like this are dropped.\n-->\n")
+	for _, field := range append([]string{Title}, t.fields...) {
+		fmt.Fprintf(&b, "%s: %s\n", field, strings.TrimSpace(values[field]))
+	}
+	for _, section := range t.sections {
+		fmt.Fprintf(&b, "\n%s%s\n\n", heading, section)
+		if value := strings.TrimSpace(values[section]); value != "" {
+			b.WriteString(value + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Parse reads edited template text back into values by canonical name.
+// Comments are dropped. Before the first heading, "<Field>: value" lines
+// fill fields and other text goes in the first section. Headings that
+// aren't the template's are kept, as written, in the section before them.
+func (t *Template) Parse(text string) map[string]string {
+	text = htmlCommentRe.ReplaceAllString(text, "")
+	values := make(map[string]string)
+	section := t.sections[0]
+	var lines []string
+	flush := func() {
+		if body := strings.TrimSpace(strings.Join(lines, "\n")); body != "" {
+			if values[section] != "" {
+				body = values[section] + "\n\n" + body
+			}
+			values[section] = body
+		}
+		lines = nil
+	}
+	inFields := true
+	for _, line := range strings.Split(text, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), heading); ok {
+			if canonical, ok := t.Canonical(name); ok && !t.isField(canonical) {
+				flush()
+				section = canonical
+				inFields = false
+				continue
+			}
+		}
+		if inFields {
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				if canonical, ok := t.Canonical(name); ok && t.isField(canonical) {
+					values[canonical] = strings.TrimSpace(value)
+					continue
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return values
+}
+
+// MissingError reports required fields or sections left empty.
+type MissingError struct {
+	Names []string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("issue is missing required %s", strings.Join(e.Names, ", "))
+}
+
+// Description returns the issue description for values: the fields as
+// "<Field>: value" lines, then the filled-in sections under their headings.
+// Empty fields and sections are left out. Returns a *MissingError if a
+// required field or section (including the title) is empty.
+func (t *Template) Description(values map[string]string) (string, error) {
+	var missing []string
+	for _, name := range t.Required() {
+		if strings.TrimSpace(values[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", &MissingError{Names: missing}
+	}
+
+	var parts []string
+	var fields []string
+	for _, field := range t.fields {
+		if value := strings.TrimSpace(values[field]); value != "" {
+			fields = append(fields, field+": "+value)
+		}
+	}
+	if len(fields) > 0 {
+		parts = append(parts, strings.Join(fields, "\n"))
+	}
+	for _, section := range t.sections {
+		if value := strings.TrimSpace(values[section]); value != "" {
+			parts = append(parts, heading+section+"\n\n"+value)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
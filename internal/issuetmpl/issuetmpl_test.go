@@ -0,0 +1,128 @@
+package issuetmpl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	priority := 1
+	tmpl, err := New("bug", &config.IssueTemplateConfig{
+		Fields:   []string{"Component"},
+		Sections: []string{"Steps", "Expected"},
+		Required: []string{"component", "Steps"},
+		Priority: &priority,
+		Labels:   []string{"triage"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tmpl.Required(); !slices.Equal(got, []string{"Title", "Component", "Steps"}) {
+		t.Errorf("Required() = %q", got)
+	}
+	if tmpl.Priority() != 1 || !slices.Equal(tmpl.Labels(), []string{"triage"}) {
+		t.Errorf("Priority() = %d, Labels() = %q", tmpl.Priority(), tmpl.Labels())
+	}
+
+	outOfRange := 5
+	bad := []*config.IssueTemplateConfig{
+		{Fields: []string{"Title"}},
+		{Fields: []string{"A: B"}},
+		{Sections: []string{"Steps", "steps"}},
+		{Sections: []string{"# Steps"}},
+		{Required: []string{"Steps"}},
+		{Priority: &outOfRange},
+		{Labels: []string{"a,b"}},
+	}
+	for _, cfg := range bad {
+		if _, err := New("bug", cfg); !errors.Is(err, ErrInvalid) {
+			t.Errorf("New(%+v) = %v, want ErrInvalid", cfg, err)
+		}
+	}
+}
+
+func TestRenderParseDescription(t *testing.T) {
+	tmpl, err := New("bug", &config.IssueTemplateConfig{
+		Fields:   []string{"Component", "Version"},
+		Sections: []string{"Steps", "Expected"},
+		Required: []string{"Steps"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := tmpl.Render(map[string]string{"Title": "Login fails", "Component": "auth"})
+	for _, want := range []string{"Title: Login fails\n", "Component: auth\n", "Version: \n", "## Steps\n", "## Expected\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Render() missing %q:\n%s", want, text)
+		}
+	}
+
+	// Untouched, the required section is missing
+	values := tmpl.Parse(text)
+	var missing *MissingError
+	if _, err := tmpl.Description(values); !errors.As(err, &missing) || !slices.Equal(missing.Names, []string{"Steps"}) {
+		t.Fatalf("Description() of the untouched template = %v, want Steps missing", err)
+	}
+
+	edited := strings.Replace(text, "## Steps\n", "## Steps\n\n1. Open /login\n2. Submit\n\n### Notes\n\nOnly on Safari\n", 1)
+	values = tmpl.Parse(edited)
+	if values["Title"] != "Login fails" || values["Component"] != "auth" {
+		t.Errorf("Parse() fields = %q", values)
+	}
+	got, err := tmpl.Description(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Component: auth\n\n## Steps\n\n1. Open /login\n2. Submit\n\n### Notes\n\nOnly on Safari"
+	if got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+
+	// No title
+	if _, err := tmpl.Description(map[string]string{"Steps": "x"}); !errors.As(err, &missing) || missing.Names[0] != "Title" {
+		t.Errorf("Description() without a title = %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	templates, err := Load(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := templates.Types(); !slices.Equal(got, []string{"bug", "chore", "feature"}) {
+		t.Errorf("built-in types = %q", got)
+	}
+
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"rig-settings","version":1,"issue_templates":{"bug":{"fields":["Severity"],"labels":["triage"]},"spike":{}}}`
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if templates, err = Load(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	bug, err := templates.Get("bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(bug.Names(), []string{"Title", "Severity", DefaultSection}) || !slices.Equal(bug.Labels(), []string{"triage"}) {
+		t.Errorf("rig bug template = %q, labels %q", bug.Names(), bug.Labels())
+	}
+	if _, err := templates.Get("spike"); err != nil {
+		t.Error(err)
+	}
+	if _, err := templates.Get("epic"); err == nil || !strings.Contains(err.Error(), "bug, chore, feature, spike") {
+		t.Errorf("Get(epic) = %v", err)
+	}
+}
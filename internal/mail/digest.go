@@ -0,0 +1,383 @@
+package mail
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// DigestCategory is a kind of Mayor mail the digest can batch.
+type DigestCategory string
+
+const (
+	// DigestMR is merge queue outcomes: merges, failures, rejections, SLA breaches.
+	DigestMR DigestCategory = "mr"
+
+	// DigestEscalation is escalations and requests for the Mayor's help.
+	DigestEscalation DigestCategory = "escalation"
+
+	// DigestStuck is alerts about stuck, unresponsive, or killed workers.
+	DigestStuck DigestCategory = "stuck"
+)
+
+// DigestCategories lists the categories in the order the digest shows them.
+var DigestCategories = []DigestCategory{DigestMR, DigestEscalation, DigestStuck}
+
+// DefaultDigestInterval is how long mail is held if the town sets no interval.
+const DefaultDigestInterval = time.Hour
+
+// digestBodyLines is how much of each held message's body the digest shows.
+const digestBodyLines = 6
+
+// digestPrefixes maps lower-cased subject prefixes of mail the town's agents
+// send the Mayor to digest categories. The first match wins.
+var digestPrefixes = []struct {
+	prefix   string
+	category DigestCategory
+}{
+	{"mr_", DigestMR},   // MR_SLA_BREACH
+	{"merge", DigestMR}, // MERGED, MERGE_FAILED, "Merge request rejected"
+	{"work merged", DigestMR},
+	{"regression from", DigestMR},
+	{"escalation", DigestEscalation}, // "Escalation: <polecat> needs help"
+	{"[escalation]", DigestEscalation},
+	{"[critical", DigestEscalation}, // gt escalate: "[HIGH] ...", "[HIGH→CRITICAL] Re-escalated: ..."
+	{"[high", DigestEscalation},
+	{"[medium", DigestEscalation},
+	{"[low", DigestEscalation},
+	{"recovery_needed", DigestEscalation},
+	{"idle_dirty", DigestEscalation},
+	{"orphan", DigestEscalation},
+	{"agent killed", DigestStuck},
+	{"force_kill", DigestStuck},
+	{"health:", DigestStuck},
+	{"alert:", DigestStuck},
+	{"stuck", DigestStuck},
+}
+
+// Digest holds a town's mayor_digest settings. A nil *Digest holds nothing:
+// every mail is delivered as it is sent.
+type Digest struct {
+	interval   time.Duration
+	categories map[DigestCategory]bool
+	inject     bool
+}
+
+// NewDigest builds digest settings from config. Returns nil if cfg is nil.
+func NewDigest(cfg *config.MayorDigestConfig) (*Digest, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	d := &Digest{interval: DefaultDigestInterval, categories: make(map[DigestCategory]bool), inject: cfg.Inject}
+	if cfg.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid digest interval %q", cfg.Interval)
+		}
+		d.interval = interval
+	}
+	if len(cfg.Categories) == 0 {
+		for _, c := range DigestCategories {
+			d.categories[c] = true
+		}
+	}
+	for _, name := range cfg.Categories {
+		c := DigestCategory(strings.ToLower(strings.TrimSpace(name)))
+		if !isDigestCategory(c) {
+			return nil, fmt.Errorf("unknown digest category %q (want mr, escalation, or stuck)", name)
+		}
+		d.categories[c] = true
+	}
+	return d, nil
+}
+
+// LoadDigest reads the digest settings from the town's settings/config.json.
+// Returns nil if the town doesn't batch the Mayor's mail.
+func LoadDigest(townRoot string) (*Digest, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	return NewDigest(settings.MayorDigest)
+}
+
+func isDigestCategory(c DigestCategory) bool {
+	for _, known := range DigestCategories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Interval returns how long mail is held before the digest goes out.
+func (d *Digest) Interval() time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.interval
+}
+
+// Inject reports whether the digest is also typed into the Mayor's session.
+func (d *Digest) Inject() bool {
+	return d != nil && d.inject
+}
+
+// Holds reports whether msg waits for the digest, and under which category.
+// Only mail to the Mayor is held, and never urgent mail.
+func (d *Digest) Holds(msg *Message) (DigestCategory, bool) {
+	if d == nil || AddressToIdentity(msg.To) != "mayor/" || msg.Priority == PriorityUrgent {
+		return "", false
+	}
+	subject := strings.ToLower(strings.TrimSpace(msg.Subject))
+	for _, p := range digestPrefixes {
+		if strings.HasPrefix(subject, p.prefix) {
+			return p.category, d.categories[p.category]
+		}
+	}
+	return "", false
+}
+
+// DigestEntry is a mail held for the Mayor's next digest.
+type DigestEntry struct {
+	Category DigestCategory `json:"category"`
+	From     string         `json:"from"`
+	Subject  string         `json:"subject"`
+	Body     string         `json:"body,omitempty"`
+	Priority Priority       `json:"priority,omitempty"`
+	HeldAt   time.Time      `json:"held_at"`
+}
+
+// DigestPath returns the file held mail is spooled to until the digest goes out.
+func DigestPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "mayor-digest.jsonl")
+}
+
+// appendDigest adds entries to the digest spool. Each entry is one write
+// to a file opened for appending, so concurrent senders don't interleave.
+func appendDigest(townRoot string, entries ...DigestEntry) error {
+	path := DigestPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PendingDigest returns the mail held for the next digest, oldest first.
+func PendingDigest(townRoot string) ([]DigestEntry, error) {
+	return readDigest(DigestPath(townRoot))
+}
+
+func readDigest(path string) ([]DigestEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []DigestEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e DigestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip a torn or corrupt line rather than lose the rest
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// DigestDue reports whether the oldest held mail has waited an interval.
+func DigestDue(townRoot string, interval time.Duration, now time.Time) (bool, error) {
+	entries, err := PendingDigest(townRoot)
+	if err != nil || len(entries) == 0 {
+		return false, err
+	}
+	return now.Sub(entries[0].HeldAt) >= interval, nil
+}
+
+// takeDigest empties the spool and returns what it held. Mail held while
+// the digest is being sent starts a new spool.
+func takeDigest(townRoot string) ([]DigestEntry, error) {
+	path := DigestPath(townRoot)
+	taking := fmt.Sprintf("%s.%d", path, os.Getpid())
+	if err := os.Rename(path, taking); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries, err := readDigest(taking)
+	if err != nil {
+		// Put the file back for the next attempt
+		_ = os.Rename(taking, path)
+		return nil, err
+	}
+	_ = os.Remove(taking)
+	return entries, nil
+}
+
+// holdForDigest spools msg for the Mayor's digest if the town batches it.
+// Returns false if msg should be delivered now, including when the digest
+// settings or spool can't be read: a broken digest never loses mail.
+func (r *Router) holdForDigest(msg *Message) bool {
+	if r.townRoot == "" {
+		return false
+	}
+	digest, err := LoadDigest(r.townRoot)
+	if err != nil {
+		return false
+	}
+	category, ok := digest.Holds(msg)
+	if !ok {
+		return false
+	}
+	entry := DigestEntry{
+		Category: category,
+		From:     msg.From,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Priority: msg.Priority,
+		HeldAt:   time.Now(),
+	}
+	return appendDigest(r.townRoot, entry) == nil
+}
+
+// SendDigest delivers the held mail to the Mayor as one message from
+// "daemon" and, if inject is set, types a briefing into the Mayor's session
+// in place of the usual new-mail notice. Returns how many mails the digest
+// covered (0 if none were held). If delivery fails the mail stays held.
+func (r *Router) SendDigest(inject bool) (int, error) {
+	if r.townRoot == "" {
+		return 0, errors.New("no town root for the Mayor's digest")
+	}
+	entries, err := takeDigest(r.townRoot)
+	if err != nil {
+		return 0, fmt.Errorf("reading held mail: %w", err)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	subject, body := FormatDigest(entries)
+	msg := &Message{From: "daemon", To: "mayor/", Subject: subject, Body: body, Priority: PriorityNormal}
+	if err := r.createMessage(msg, AddressToIdentity(msg.To)); err != nil {
+		if putBackErr := appendDigest(r.townRoot, entries...); putBackErr != nil {
+			return 0, fmt.Errorf("%w (and could not re-hold mail: %v)", err, putBackErr)
+		}
+		return 0, err
+	}
+
+	if inject {
+		if has, err := r.tmux.HasSession(session.MayorSessionName()); err == nil && has {
+			_ = r.tmux.NudgeSession(session.MayorSessionName(), FormatBriefing(entries))
+		}
+	} else {
+		_ = r.notifyRecipient(msg)
+	}
+	_ = r.emailRouted(msg)
+	return len(entries), nil
+}
+
+// digestCategoryTitle names a category's section in the digest.
+func digestCategoryTitle(c DigestCategory) string {
+	switch c {
+	case DigestMR:
+		return "MR outcomes"
+	case DigestEscalation:
+		return "Escalations"
+	case DigestStuck:
+		return "Stuck workers"
+	default:
+		return string(c)
+	}
+}
+
+// groupDigest splits entries by category, keeping each category's order.
+func groupDigest(entries []DigestEntry) map[DigestCategory][]DigestEntry {
+	groups := make(map[DigestCategory][]DigestEntry)
+	for _, e := range entries {
+		groups[e.Category] = append(groups[e.Category], e)
+	}
+	return groups
+}
+
+// FormatDigest renders held mail as the digest's subject and body: one
+// section per category, each mail with its sender, time, and the start of
+// its body.
+func FormatDigest(entries []DigestEntry) (subject, body string) {
+	groups := groupDigest(entries)
+	var counts []string
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d notifications since %s.\n", len(entries), entries[0].HeldAt.Local().Format("Jan 2 15:04"))
+	for _, c := range DigestCategories {
+		group := groups[c]
+		if len(group) == 0 {
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%s %d", digestCategoryTitle(c), len(group)))
+		fmt.Fprintf(&b, "\n## %s (%d)\n", digestCategoryTitle(c), len(group))
+		for _, e := range group {
+			fmt.Fprintf(&b, "\n- %s %s (from %s)\n", e.HeldAt.Local().Format("15:04"), e.Subject, e.From)
+			lines := strings.Split(strings.TrimSpace(e.Body), "\n")
+			if len(lines) > digestBodyLines {
+				lines = append(lines[:digestBodyLines], "…")
+			}
+			for _, line := range lines {
+				if line = strings.TrimRight(line, " \t"); line != "" {
+					fmt.Fprintf(&b, "    %s\n", line)
+				}
+			}
+		}
+	}
+	subject = fmt.Sprintf("📋 Digest: %d notifications (%s)", len(entries), strings.Join(counts, ", "))
+	return subject, b.String()
+}
+
+// FormatBriefing renders held mail as a one-line briefing for the Mayor's
+// session.
+func FormatBriefing(entries []DigestEntry) string {
+	groups := groupDigest(entries)
+	var parts []string
+	for _, c := range DigestCategories {
+		group := groups[c]
+		if len(group) == 0 {
+			continue
+		}
+		subjects := make([]string, len(group))
+		for i, e := range group {
+			subjects[i] = e.Subject
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d): %s", digestCategoryTitle(c), len(group), strings.Join(subjects, "; ")))
+	}
+	return fmt.Sprintf("📋 BRIEFING: %d notifications. %s. Run 'gt mail inbox' for the digest.",
+		len(entries), strings.Join(parts, ". "))
+}
@@ -0,0 +1,169 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewDigest(t *testing.T) {
+	if d, err := NewDigest(nil); err != nil || d != nil {
+		t.Errorf("NewDigest(nil) = %v, %v; want nil (no digest)", d, err)
+	}
+
+	d, err := NewDigest(&config.MayorDigestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Interval() != DefaultDigestInterval {
+		t.Errorf("Interval = %v, want default %v", d.Interval(), DefaultDigestInterval)
+	}
+	for _, c := range DigestCategories {
+		if !d.categories[c] {
+			t.Errorf("category %s not batched by default", c)
+		}
+	}
+
+	if _, err := NewDigest(&config.MayorDigestConfig{Interval: "often"}); err == nil {
+		t.Error("NewDigest with a bad interval should fail")
+	}
+	if _, err := NewDigest(&config.MayorDigestConfig{Categories: []string{"gossip"}}); err == nil {
+		t.Error("NewDigest with an unknown category should fail")
+	}
+}
+
+func TestDigestHolds(t *testing.T) {
+	d, err := NewDigest(&config.MayorDigestConfig{Categories: []string{"mr", "escalation"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		to, subject string
+		priority    Priority
+		want        DigestCategory
+		held        bool
+	}{
+		{"mayor/", "MR_SLA_BREACH: gp-mr-1 P0 overdue by 10m", PriorityNormal, DigestMR, true},
+		{"mayor", "Merge request rejected", PriorityNormal, DigestMR, true},
+		{"mayor/", "[HIGH] Build broken", PriorityHigh, DigestEscalation, true},
+		{"mayor/", "Escalation: toast needs help", PriorityHigh, DigestEscalation, true},
+		{"mayor/", "[CRITICAL] Prod down", PriorityUrgent, "", false},                      // urgent mail is never held
+		{"mayor/", "Agent killed: greenplace/witness", PriorityNormal, DigestStuck, false}, // stuck not batched here
+		{"mayor/", "Convoy complete: auth", PriorityNormal, "", false},
+		{"greenplace/witness", "MR_SLA_BREACH: gp-mr-1", PriorityNormal, "", false},
+	}
+	for _, tt := range tests {
+		got, held := d.Holds(&Message{To: tt.to, Subject: tt.subject, Priority: tt.priority})
+		if held != tt.held || (held && got != tt.want) {
+			t.Errorf("Holds(%s, %q) = %q, %v; want %q, %v", tt.to, tt.subject, got, held, tt.want, tt.held)
+		}
+	}
+
+	var none *Digest
+	if _, held := none.Holds(&Message{To: "mayor/", Subject: "MR_SLA_BREACH"}); held {
+		t.Error("nil digest should hold nothing")
+	}
+}
+
+func TestDigestSpool(t *testing.T) {
+	town := t.TempDir()
+	start := time.Now().Add(-90 * time.Minute)
+
+	if due, err := DigestDue(town, time.Hour, time.Now()); err != nil || due {
+		t.Errorf("DigestDue with nothing held = %v, %v; want false", due, err)
+	}
+
+	if err := appendDigest(town,
+		DigestEntry{Category: DigestMR, From: "daemon", Subject: "MR_SLA_BREACH: gp-mr-1", HeldAt: start},
+		DigestEntry{Category: DigestStuck, From: "deacon", Subject: "Agent killed: greenplace/toast", HeldAt: start.Add(time.Minute)},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if due, _ := DigestDue(town, 2*time.Hour, time.Now()); due {
+		t.Error("digest due before its interval")
+	}
+	if due, _ := DigestDue(town, time.Hour, time.Now()); !due {
+		t.Error("digest not due after its interval")
+	}
+
+	entries, err := takeDigest(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Subject != "MR_SLA_BREACH: gp-mr-1" {
+		t.Fatalf("takeDigest = %+v, want both entries oldest first", entries)
+	}
+	if pending, _ := PendingDigest(town); len(pending) != 0 {
+		t.Errorf("spool not emptied: %d entries left", len(pending))
+	}
+	if matches, _ := filepath.Glob(DigestPath(town) + ".*"); len(matches) != 0 {
+		t.Errorf("leftover spool files: %v", matches)
+	}
+}
+
+func TestHoldForDigest(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+	msg := &Message{From: "daemon", To: "mayor/", Subject: "MR_SLA_BREACH: gp-mr-1", Body: "priority: P0"}
+
+	if r.holdForDigest(msg) {
+		t.Fatal("mail held without mayor_digest configured")
+	}
+
+	settings := filepath.Join(town, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settings, []byte(`{"type":"town-settings","version":1,"mayor_digest":{"interval":"30m"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !r.holdForDigest(msg) {
+		t.Fatal("mail not held with mayor_digest configured")
+	}
+	pending, err := PendingDigest(town)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("PendingDigest = %v, %v; want the held mail", pending, err)
+	}
+	if pending[0].Category != DigestMR || pending[0].Body != "priority: P0" {
+		t.Errorf("held entry = %+v", pending[0])
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	at := time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local)
+	entries := []DigestEntry{
+		{Category: DigestStuck, From: "deacon", Subject: "Agent killed: greenplace/toast", HeldAt: at},
+		{Category: DigestMR, From: "daemon", Subject: "MR_SLA_BREACH: gp-mr-1", Body: "priority: P0\ntarget: 1h", HeldAt: at},
+		{Category: DigestMR, From: "greenplace/refinery", Subject: "Merge request rejected", HeldAt: at},
+	}
+
+	subject, body := FormatDigest(entries)
+	if subject != "📋 Digest: 3 notifications (MR outcomes 2, Stuck workers 1)" {
+		t.Errorf("subject = %q", subject)
+	}
+	if _, held := (&Digest{categories: map[DigestCategory]bool{DigestMR: true}}).Holds(&Message{To: "mayor/", Subject: subject}); held {
+		t.Error("the digest itself must not be held for the next digest")
+	}
+	mr := strings.Index(body, "## MR outcomes (2)")
+	stuck := strings.Index(body, "## Stuck workers (1)")
+	if mr < 0 || stuck < 0 || stuck < mr {
+		t.Errorf("body sections missing or out of order:\n%s", body)
+	}
+	if !strings.Contains(body, "14:05 MR_SLA_BREACH: gp-mr-1 (from daemon)\n    priority: P0\n    target: 1h") {
+		t.Errorf("body missing entry details:\n%s", body)
+	}
+
+	briefing := FormatBriefing(entries)
+	if strings.Contains(briefing, "\n") {
+		t.Errorf("briefing should be one line: %q", briefing)
+	}
+	if !strings.Contains(briefing, "MR outcomes (2): MR_SLA_BREACH: gp-mr-1; Merge request rejected") {
+		t.Errorf("briefing = %q", briefing)
+	}
+}
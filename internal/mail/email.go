@@ -0,0 +1,228 @@
+package mail
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// ErrNoEmail is returned when mail is sent to an email address but the town
+// has no email configured in config/messaging.json.
+var ErrNoEmail = errors.New("email is not configured (add an \"email\" block to config/messaging.json)")
+
+// IsEmailAddress reports whether address is an email address rather than a
+// town address. Town addresses never contain "@" outside an @group prefix.
+func IsEmailAddress(address string) bool {
+	if strings.HasPrefix(address, "@") || strings.Contains(address, "/") || !strings.Contains(address, "@") {
+		return false
+	}
+	_, err := netmail.ParseAddress(address)
+	return err == nil
+}
+
+// smtpSend delivers a raw message; tests replace it.
+var smtpSend = sendSMTP
+
+// Limits on an SMTP exchange. A copy sent because a route matched holds up
+// the sender's gt mail send, so it gets far less time than mail addressed
+// to an email address.
+const (
+	emailTimeout       = 2 * time.Minute
+	routedEmailTimeout = 15 * time.Second
+)
+
+// sendEmail emails msg to the given addresses through the town's SMTP
+// server.
+func (r *Router) sendEmail(msg *Message, to []string) error {
+	cfg, err := r.emailConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return ErrNoEmail
+	}
+	return r.deliverEmail(cfg, msg, to, emailTimeout)
+}
+
+// emailRouted copies msg to the email addresses of every route it matches.
+// Best-effort, like session notification: town delivery already succeeded.
+func (r *Router) emailRouted(msg *Message) error {
+	cfg, err := r.emailConfig()
+	if err != nil || cfg == nil {
+		return err
+	}
+	var to []string
+	for _, route := range cfg.Routes {
+		if routeMatches(route, msg) {
+			for _, addr := range route.To {
+				if !slices.Contains(to, addr) {
+					to = append(to, addr)
+				}
+			}
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	return r.deliverEmail(cfg, msg, to, routedEmailTimeout)
+}
+
+// emailConfig returns the town's email settings, or nil if it has none.
+func (r *Router) emailConfig() (*config.EmailConfig, error) {
+	if r.townRoot == "" {
+		return nil, nil
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	return cfg.Email, nil
+}
+
+// routeMatches reports whether msg meets all of a route's conditions.
+func routeMatches(route config.EmailRoute, msg *Message) bool {
+	if len(route.Recipients) > 0 && !slices.ContainsFunc(route.Recipients, func(p string) bool {
+		return matchPattern(p, msg.To) || AddressToIdentity(p) == AddressToIdentity(msg.To)
+	}) {
+		return false
+	}
+	if len(route.Subjects) > 0 && !slices.ContainsFunc(route.Subjects, func(p string) bool {
+		return strings.HasPrefix(strings.ToLower(msg.Subject), strings.ToLower(p))
+	}) {
+		return false
+	}
+	if len(route.Priorities) > 0 && !slices.Contains(route.Priorities, string(msg.Priority)) {
+		return false
+	}
+	return true
+}
+
+// deliverEmail formats msg and sends it to the given addresses, giving up
+// after timeout.
+func (r *Router) deliverEmail(cfg *config.EmailConfig, msg *Message, to []string, timeout time.Duration) error {
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return fmt.Errorf("email.from %q: %w", cfg.From, err)
+	}
+	password, err := secrets.Resolve(r.townRoot, cfg.SMTP.Password)
+	if err != nil {
+		return fmt.Errorf("resolving SMTP password: %w", err)
+	}
+	server := cfg.SMTP
+	server.Password = password
+	if err := smtpSend(server, from.Address, to, formatEmail(cfg.From, msg, to), timeout); err != nil {
+		return fmt.Errorf("emailing %s: %w", strings.Join(to, ", "), err)
+	}
+	return nil
+}
+
+// formatEmail renders msg as a plain-text email.
+func formatEmail(from string, msg *Message, to []string) []byte {
+	var b strings.Builder
+	subject := msg.Subject
+	if msg.Priority == PriorityUrgent || msg.Priority == PriorityHigh {
+		subject = "[" + strings.ToUpper(string(msg.Priority)) + "] " + subject
+	}
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mimeHeader(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	fmt.Fprintf(&b, "\r\n\r\n-- \r\nSent by %s to %s from Gas Town.\r\n", msg.From, msg.To)
+	return []byte(b.String())
+}
+
+// mimeHeader encodes a header value if it isn't plain ASCII. Line breaks
+// are folded to spaces first: the subject comes from whoever sent the mail,
+// and a CR or LF in it would start a header of their choosing.
+func mimeHeader(s string) string {
+	s = strings.Join(strings.FieldsFunc(s, func(c rune) bool { return c == '\r' || c == '\n' }), " ")
+	for _, c := range s {
+		if c > 127 {
+			return mime.QEncoding.Encode("utf-8", s)
+		}
+	}
+	return s
+}
+
+// sendSMTP sends a raw message through server, giving up after timeout.
+func sendSMTP(server config.SMTPConfig, from string, to []string, data []byte, timeout time.Duration) error {
+	port := server.Port
+	if port == 0 {
+		port = 587
+		if server.Security == config.SMTPTLS {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: server.Host}
+
+	var conn net.Conn
+	var err error
+	deadline := time.Now().Add(timeout)
+	dialer := &net.Dialer{Deadline: deadline}
+	if server.Security == config.SMTPTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if server.Security == "" || server.Security == config.SMTPStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if server.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host)); err != nil {
+			return fmt.Errorf("authenticating: %w", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,145 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// sentEmail is a message captured by fakeSMTP.
+type sentEmail struct {
+	server config.SMTPConfig
+	from   string
+	to     []string
+	data   string
+}
+
+// fakeSMTP replaces smtpSend for the test, returning what was sent.
+func fakeSMTP(t *testing.T) *[]sentEmail {
+	t.Helper()
+	var sent []sentEmail
+	orig := smtpSend
+	smtpSend = func(server config.SMTPConfig, from string, to []string, data []byte, _ time.Duration) error {
+		sent = append(sent, sentEmail{server, from, to, string(data)})
+		return nil
+	}
+	t.Cleanup(func() { smtpSend = orig })
+	return &sent
+}
+
+// emailTown writes a town whose messaging.json has the given email block.
+func emailTown(t *testing.T, email string) *Router {
+	t.Helper()
+	town := t.TempDir()
+	configDir := filepath.Join(town, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"type": "messaging", "version": 1, "email": ` + email + `}`
+	if err := os.WriteFile(filepath.Join(configDir, "messaging.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewRouterWithTownRoot(town, town)
+}
+
+func TestIsEmailAddress(t *testing.T) {
+	tests := map[string]bool{
+		"alice@example.com":   true,
+		"mayor/":              false,
+		"overseer":            false,
+		"@town":               false,
+		"@crew/gastown":       false,
+		"gastown/crew/a@b.io": false,
+		"list:oncall":         false,
+		"not an address@":     false,
+	}
+	for addr, want := range tests {
+		if got := IsEmailAddress(addr); got != want {
+			t.Errorf("IsEmailAddress(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestSendToEmailAddress(t *testing.T) {
+	sent := fakeSMTP(t)
+	r := emailTown(t, `{"smtp": {"host": "smtp.acme.com", "username": "bot", "password": "hunter2"}, "from": "Gas Town <gt@acme.com>"}`)
+
+	msg := &Message{From: "gastown/witness", To: "alice@acme.com", Subject: "MR_SLA_BREACH: gt-mr-1", Body: "Overdue.", Priority: PriorityUrgent}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(*sent))
+	}
+	got := (*sent)[0]
+	if got.from != "gt@acme.com" || strings.Join(got.to, ",") != "alice@acme.com" || got.server.Password != "hunter2" {
+		t.Errorf("sent from %s to %v via %+v", got.from, got.to, got.server)
+	}
+	for _, want := range []string{"Subject: [URGENT] MR_SLA_BREACH: gt-mr-1\r\n", "\r\n\r\nOverdue.", "Sent by gastown/witness"} {
+		if !strings.Contains(got.data, want) {
+			t.Errorf("email missing %q:\n%s", want, got.data)
+		}
+	}
+}
+
+func TestFormatEmailSubjectLineBreaks(t *testing.T) {
+	msg := &Message{From: "mayor/", To: "alice@acme.com", Subject: "hi\r\nBcc: eve@evil.example\nX-Evil: 1", Body: "body"}
+	data := string(formatEmail("gt@acme.com", msg, []string{"alice@acme.com"}))
+	header, _, _ := strings.Cut(data, "\r\n\r\n")
+	if strings.Contains(header, "\nBcc:") || strings.Contains(header, "\nX-Evil:") {
+		t.Errorf("subject injected headers:\n%s", header)
+	}
+	if !strings.Contains(header, "Subject: hi Bcc: eve@evil.example X-Evil: 1\r\n") {
+		t.Errorf("subject not folded onto one line:\n%s", header)
+	}
+}
+
+func TestSendToEmailAddressUnconfigured(t *testing.T) {
+	fakeSMTP(t)
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	if err := r.Send(&Message{From: "mayor/", To: "alice@acme.com", Subject: "hi"}); !errors.Is(err, ErrNoEmail) {
+		t.Errorf("Send = %v, want ErrNoEmail", err)
+	}
+}
+
+func TestEmailRouted(t *testing.T) {
+	sent := fakeSMTP(t)
+	r := emailTown(t, `{
+		"smtp": {"host": "localhost", "security": "none"},
+		"from": "gt@acme.com",
+		"routes": [
+			{"to": ["lead@acme.com"], "recipients": ["mayor/"], "subjects": ["Digest"]},
+			{"to": ["oncall@acme.com", "lead@acme.com"], "priorities": ["urgent"]},
+			{"to": ["crew@acme.com"], "recipients": ["gastown/crew/*"]}
+		]
+	}`)
+
+	tests := []struct {
+		msg  Message
+		want string
+	}{
+		{Message{To: "mayor/", Subject: "Digest: 3 updates", Priority: PriorityNormal}, "lead@acme.com"},
+		{Message{To: "mayor/", Subject: "Digest: 3 updates", Priority: PriorityUrgent}, "lead@acme.com,oncall@acme.com"},
+		{Message{To: "mayor/", Subject: "MERGED gt-1", Priority: PriorityNormal}, ""},
+		{Message{To: "gastown/crew/max", Subject: "hello", Priority: PriorityLow}, "crew@acme.com"},
+		{Message{To: "gastown/witness", Subject: "hello", Priority: PriorityLow}, ""},
+	}
+	for _, tt := range tests {
+		*sent = nil
+		if err := r.emailRouted(&tt.msg); err != nil {
+			t.Fatalf("emailRouted(%s %q): %v", tt.msg.To, tt.msg.Subject, err)
+		}
+		var got []string
+		for _, e := range *sent {
+			got = append(got, e.to...)
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("emailRouted(%s %q %s) emailed %v, want %q", tt.msg.To, tt.msg.Subject, tt.msg.Priority, got, tt.want)
+		}
+	}
+}
@@ -49,7 +49,8 @@ func NewResolver(b *beads.Beads, townRoot string) *Resolver {
 // 1. Contains '/' → agent address or pattern (direct delivery)
 // 2. Starts with '@' → special pattern (@town, @crew, etc.)
 // 3. Starts with explicit prefix → use that type (group:, queue:, channel:)
-// 4. Otherwise → lookup by name: group → queue → channel
+// 4. Email address → a human outside the town (sent over SMTP)
+// 5. Otherwise → lookup by name: group → queue → channel
 func (r *Resolver) Resolve(address string) ([]Recipient, error) {
 	// 1. Explicit prefix takes precedence
 	if strings.HasPrefix(address, "group:") {
@@ -81,7 +82,12 @@ func (r *Resolver) Resolve(address string) ([]Recipient, error) {
 		return r.resolveAtPattern(address)
 	}
 
-	// 4. Name lookup: group → queue → channel
+	// 4. Email address → a human outside the town, emailed by the router
+	if IsEmailAddress(address) {
+		return []Recipient{{Address: address, Type: RecipientAgent}}, nil
+	}
+
+	// 5. Name lookup: group → queue → channel
 	return r.resolveByName(address)
 }
 
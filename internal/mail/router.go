@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -623,9 +624,15 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Mailing lists (list:name) - fans out to all list members
 // - @group addresses - resolves and fans out to matching agents
 // Supports single-copy delivery for:
+// - Email addresses (alice@example.com) - sent over SMTP, see email.go
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	// Check for email address - a human outside the town
+	if IsEmailAddress(msg.To) {
+		return r.sendEmail(msg, []string{msg.To})
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -748,6 +755,32 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
+	// Routine mail to the Mayor waits for the next digest if the town batches it
+	if r.holdForDigest(msg) {
+		return nil
+	}
+
+	if err := r.createMessage(msg, toIdentity); err != nil {
+		return err
+	}
+
+	// Notify recipient if they have an active session (best-effort notification)
+	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
+	if !isSelfMail(msg.From, msg.To) {
+		_ = r.notifyRecipient(msg)
+	}
+
+	// Copy to email if a route asks for it. The message is already
+	// delivered, so a failure only warns.
+	if err := r.emailRouted(msg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not email a copy of %q: %v\n", msg.Subject, err)
+	}
+
+	return nil
+}
+
+// createMessage stores msg as a message bead assigned to toIdentity.
+func (r *Router) createMessage(msg *Message, toIdentity string) error {
 	// Build labels for from/thread/reply-to/cc
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
@@ -795,13 +828,6 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
-
-	// Notify recipient if they have an active session (best-effort notification)
-	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
-	if !isSelfMail(msg.From, msg.To) {
-		_ = r.notifyRecipient(msg)
-	}
-
 	return nil
 }
 
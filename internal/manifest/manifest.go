@@ -0,0 +1,179 @@
+// Package manifest declares a whole town in one file (its rigs, their
+// refinery settings and polecat counts, and escalation routing) and plans
+// the changes that bring a live town in line with it, for gt apply.
+//
+// A manifest is YAML (or JSON). Setting sections use the same keys as the
+// files they end up in, so merge_queue matches a rig's
+// settings/config.json and escalation matches settings/escalation.json:
+//
+//	templates:
+//	  go-service:
+//	    polecats: 4
+//	    merge_queue:
+//	      test_command: go test ./...
+//	rigs:
+//	  greenplace:
+//	    git_url: https://github.com/acme/greenplace
+//	    prefix: gp
+//	    template: go-service
+//	    merge_queue:
+//	      protection:
+//	        min_approvals: 1
+//	escalation:
+//	  contacts:
+//	    slack_webhook: secret:slack
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a parsed town manifest.
+type Manifest struct {
+	Rigs []RigSpec // Sorted by name
+
+	// Escalation is the desired settings/escalation.json, with defaults
+	// for anything the manifest leaves out (nil = leave as is).
+	Escalation *config.EscalationConfig
+}
+
+// RigSpec is the desired state of one rig.
+type RigSpec struct {
+	Name   string
+	GitURL string
+	Prefix string // Beads prefix ("" = derived from the name)
+	Branch string // Default branch ("" = detected from the remote)
+
+	// Polecats is the rig's max_polecats (nil = leave as is).
+	Polecats *int
+
+	// MergeQueue is the desired merge_queue section of the rig's
+	// settings, with defaults for anything the manifest leaves out
+	// (nil = leave as is).
+	MergeQueue *config.MergeQueueConfig
+}
+
+// rawManifest is a manifest as written, before templates are applied.
+type rawManifest struct {
+	Templates  map[string]map[string]interface{} `yaml:"templates"`
+	Rigs       map[string]map[string]interface{} `yaml:"rigs"`
+	Escalation map[string]interface{}            `yaml:"escalation"`
+}
+
+// rawRig is one rig's section, template applied.
+type rawRig struct {
+	GitURL     string          `json:"git_url"`
+	Prefix     string          `json:"prefix"`
+	Branch     string          `json:"branch"`
+	Template   string          `json:"template"`
+	Polecats   *int            `json:"polecats"`
+	MergeQueue json.RawMessage `json:"merge_queue"`
+}
+
+// Load reads and parses a manifest file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the user
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse parses a manifest. Each rig starts from its template's settings,
+// and its own override them key by key.
+func Parse(data []byte) (*Manifest, error) {
+	var raw rawManifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	m := &Manifest{}
+	for name, section := range raw.Rigs {
+		var r rawRig
+		if err := decodeStrict(section, &r); err != nil {
+			return nil, fmt.Errorf("rig %s: %w", name, err)
+		}
+		if template := r.Template; template != "" {
+			tmpl, ok := raw.Templates[template]
+			if !ok {
+				return nil, fmt.Errorf("rig %s: unknown template %q", name, template)
+			}
+			r = rawRig{}
+			if err := decodeStrict(mergeSections(tmpl, section), &r); err != nil {
+				return nil, fmt.Errorf("rig %s (template %s): %w", name, template, err)
+			}
+		}
+
+		if r.GitURL == "" {
+			return nil, fmt.Errorf("rig %s: git_url is required", name)
+		}
+		if r.Polecats != nil && *r.Polecats < 0 {
+			return nil, fmt.Errorf("rig %s: polecats can't be negative", name)
+		}
+		spec := RigSpec{Name: name, GitURL: r.GitURL, Prefix: r.Prefix, Branch: r.Branch, Polecats: r.Polecats}
+		if len(r.MergeQueue) > 0 {
+			spec.MergeQueue = config.DefaultMergeQueueConfig()
+			if err := decodeStrict(r.MergeQueue, spec.MergeQueue); err != nil {
+				return nil, fmt.Errorf("rig %s merge_queue: %w", name, err)
+			}
+		}
+		m.Rigs = append(m.Rigs, spec)
+	}
+	sort.Slice(m.Rigs, func(i, j int) bool { return m.Rigs[i].Name < m.Rigs[j].Name })
+
+	if raw.Escalation != nil {
+		m.Escalation = config.NewEscalationConfig()
+		if err := decodeStrict(raw.Escalation, m.Escalation); err != nil {
+			return nil, fmt.Errorf("escalation: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// decodeStrict decodes a YAML section (or raw JSON) into v by way of
+// JSON, so config types are filled in by their json tags. Unknown keys
+// are an error.
+func decodeStrict(section interface{}, v interface{}) error {
+	data, ok := section.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(section); err != nil {
+			return err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// mergeSections returns base overlaid with override: nested maps merge,
+// anything else in override replaces base's.
+func mergeSections(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if b, ok := merged[k].(map[string]interface{}); ok {
+			if o, ok := v.(map[string]interface{}); ok {
+				merged[k] = mergeSections(b, o)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
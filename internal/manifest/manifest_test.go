@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const testManifest = `
+templates:
+  go-service:
+    polecats: 4
+    merge_queue:
+      test_command: go test ./...
+      protection:
+        min_approvals: 2
+        required_checks: [tests]
+rigs:
+  greenplace:
+    git_url: https://example.com/greenplace.git
+    prefix: gp
+    template: go-service
+    merge_queue:
+      protection:
+        min_approvals: 1
+  bluefield:
+    git_url: https://example.com/bluefield.git
+escalation:
+  contacts:
+    human_email: ops@example.com
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(m.Rigs) != 2 || m.Rigs[0].Name != "bluefield" || m.Rigs[1].Name != "greenplace" {
+		t.Fatalf("rigs = %+v, want bluefield, greenplace", m.Rigs)
+	}
+
+	blue := m.Rigs[0]
+	if blue.Polecats != nil || blue.MergeQueue != nil {
+		t.Errorf("bluefield sets nothing, got polecats %v, merge_queue %+v", blue.Polecats, blue.MergeQueue)
+	}
+
+	// The rig's settings override its template's, key by key
+	green := m.Rigs[1]
+	if green.Prefix != "gp" || green.Polecats == nil || *green.Polecats != 4 {
+		t.Errorf("greenplace = %+v", green)
+	}
+	mq := green.MergeQueue
+	if mq == nil || mq.TestCommand != "go test ./..." || mq.Protection == nil {
+		t.Fatalf("merge_queue = %+v", mq)
+	}
+	if mq.Protection.MinApprovals != 1 || strings.Join(mq.Protection.RequiredChecks, ",") != "tests" {
+		t.Errorf("protection = %+v, want min_approvals 1 from the rig, required_checks from the template", mq.Protection)
+	}
+	if !mq.Enabled || mq.PollInterval != "30s" {
+		t.Errorf("unset merge_queue keys should get defaults, got %+v", mq)
+	}
+
+	if m.Escalation == nil || m.Escalation.Contacts.HumanEmail != "ops@example.com" || m.Escalation.StaleThreshold != "4h" {
+		t.Errorf("escalation = %+v", m.Escalation)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, manifest, want string
+	}{
+		{"unknown section", "rigz: {}", "field rigz not found"},
+		{"missing git_url", "rigs: {a: {prefix: a}}", "git_url is required"},
+		{"unknown template", "rigs: {a: {git_url: u, template: nope}}", `unknown template "nope"`},
+		{"unknown rig key", "rigs: {a: {git_url: u, polecat: 3}}", `unknown field "polecat"`},
+		{"unknown merge_queue key", "rigs: {a: {git_url: u, merge_queue: {test_cmd: x}}}", `unknown field "test_cmd"`},
+		{"negative polecats", "rigs: {a: {git_url: u, polecats: -1}}", "can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	townRoot := t.TempDir()
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"greenplace": {GitURL: "https://example.com/greenplace.git", AddedAt: time.Now()},
+		"oldrig":     {GitURL: "https://example.com/old.git", AddedAt: time.Now()},
+	}}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := m.Plan(townRoot)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Action+" "+c.Rig+" "+c.Subject)
+	}
+	want := []string{
+		"create bluefield rig",
+		"update greenplace merge_queue",
+		"update greenplace max_polecats",
+		"drift oldrig rig",
+		"update  escalation",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("plan =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+
+	for _, c := range changes {
+		if c.Action == ActionUpdate {
+			if err := m.Apply(townRoot, c); err != nil {
+				t.Fatalf("Apply %+v: %v", c, err)
+			}
+		}
+	}
+	if err := m.Apply(townRoot, changes[0]); err == nil {
+		t.Error("Apply should refuse a create")
+	}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "greenplace")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.MergeQueue.Protection == nil || settings.MergeQueue.Protection.MinApprovals != 1 {
+		t.Errorf("merge_queue not written: %+v", settings.MergeQueue)
+	}
+
+	// Applied updates don't come back
+	changes, err = m.Plan(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range changes {
+		if c.Action == ActionUpdate {
+			t.Errorf("still planned after apply: %+v", c)
+		}
+	}
+
+	// Hand edits show up as drift to correct
+	settings.MergeQueue.TestCommand = "make test"
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "greenplace")), settings); err != nil {
+		t.Fatal(err)
+	}
+	changes, _ = m.Plan(townRoot)
+	found := false
+	for _, c := range changes {
+		if c.Subject == SubjectMergeQueue && c.Detail == "test_command" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("edited test_command not planned: %+v", changes)
+	}
+}
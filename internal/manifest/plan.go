@@ -0,0 +1,216 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Change actions.
+const (
+	ActionCreate = "create" // Clone a missing rig
+	ActionUpdate = "update" // Rewrite config to match the manifest
+	ActionDrift  = "drift"  // Differs from the manifest; reported, not changed
+)
+
+// Change subjects.
+const (
+	SubjectRig        = "rig"
+	SubjectMergeQueue = "merge_queue"
+	SubjectPolecats   = "max_polecats"
+	SubjectEscalation = "escalation"
+)
+
+// Change is one difference between the manifest and the live town.
+type Change struct {
+	Action  string `json:"action"`
+	Rig     string `json:"rig,omitempty"` // "" for town-wide settings
+	Subject string `json:"subject"`
+	Detail  string `json:"detail"`
+}
+
+// Plan compares the manifest with the town at townRoot and returns the
+// changes that would bring the town in line: rigs to clone, settings to
+// rewrite, and drift that apply won't touch (a rig cloned from another
+// URL, rigs the manifest doesn't list). Creates come first, so a new rig
+// exists before its settings are written.
+func (m *Manifest) Plan(townRoot string) ([]Change, error) {
+	rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		rigs = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+
+	var creates, changes []Change
+	listed := make(map[string]bool, len(m.Rigs))
+	for _, spec := range m.Rigs {
+		listed[spec.Name] = true
+		entry, exists := rigs.Rigs[spec.Name]
+		switch {
+		case !exists:
+			creates = append(creates, Change{Action: ActionCreate, Rig: spec.Name, Subject: SubjectRig,
+				Detail: "clone " + spec.GitURL})
+		case entry.GitURL != spec.GitURL:
+			changes = append(changes, Change{Action: ActionDrift, Rig: spec.Name, Subject: SubjectRig,
+				Detail: fmt.Sprintf("cloned from %s, manifest says %s", entry.GitURL, spec.GitURL)})
+		}
+
+		rigPath := filepath.Join(townRoot, spec.Name)
+		if spec.MergeQueue != nil {
+			current, err := currentMergeQueue(rigPath)
+			if err != nil {
+				return nil, fmt.Errorf("rig %s: %w", spec.Name, err)
+			}
+			if keys := differingKeys(current, spec.MergeQueue); len(keys) > 0 {
+				changes = append(changes, Change{Action: ActionUpdate, Rig: spec.Name, Subject: SubjectMergeQueue,
+					Detail: strings.Join(keys, ", ")})
+			}
+		}
+		if spec.Polecats != nil {
+			current, set := currentPolecats(townRoot, spec.Name)
+			if !set || current != *spec.Polecats {
+				detail := fmt.Sprintf("%d → %d", current, *spec.Polecats)
+				if !set {
+					detail = fmt.Sprintf("unset → %d", *spec.Polecats)
+				}
+				changes = append(changes, Change{Action: ActionUpdate, Rig: spec.Name, Subject: SubjectPolecats,
+					Detail: detail})
+			}
+		}
+	}
+
+	var unlisted []string
+	for name := range rigs.Rigs {
+		if !listed[name] {
+			unlisted = append(unlisted, name)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, name := range unlisted {
+		changes = append(changes, Change{Action: ActionDrift, Rig: name, Subject: SubjectRig,
+			Detail: "not in the manifest (left in place)"})
+	}
+
+	if m.Escalation != nil {
+		current, err := config.LoadEscalationConfig(config.EscalationConfigPath(townRoot))
+		if errors.Is(err, config.ErrNotFound) {
+			current, err = nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading escalation config: %w", err)
+		}
+		if keys := differingKeys(current, m.Escalation); len(keys) > 0 {
+			changes = append(changes, Change{Action: ActionUpdate, Subject: SubjectEscalation,
+				Detail: strings.Join(keys, ", ")})
+		}
+	}
+	return append(creates, changes...), nil
+}
+
+// Apply makes an update change to the town at townRoot. Creates are the
+// caller's (cloning a rig takes the full gt rig add), and drift is never
+// changed.
+func (m *Manifest) Apply(townRoot string, c Change) error {
+	if c.Action != ActionUpdate {
+		return fmt.Errorf("can't apply %s %s", c.Action, c.Subject)
+	}
+	switch c.Subject {
+	case SubjectMergeQueue:
+		spec := m.Rig(c.Rig)
+		path := config.RigSettingsPath(filepath.Join(townRoot, c.Rig))
+		_, err := config.RigSettingsStore(path).Update(func(settings *config.RigSettings) error {
+			settings.MergeQueue = spec.MergeQueue
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("saving %s settings: %w", c.Rig, err)
+		}
+	case SubjectPolecats:
+		spec := m.Rig(c.Rig)
+		if err := wisp.NewConfig(townRoot, c.Rig).Set(SubjectPolecats, *spec.Polecats); err != nil {
+			return fmt.Errorf("setting %s max_polecats: %w", c.Rig, err)
+		}
+	case SubjectEscalation:
+		if err := config.SaveEscalationConfig(config.EscalationConfigPath(townRoot), m.Escalation); err != nil {
+			return fmt.Errorf("saving escalation config: %w", err)
+		}
+	default:
+		return fmt.Errorf("can't apply %s %s", c.Action, c.Subject)
+	}
+	return nil
+}
+
+// Rig returns the spec for a rig, or nil if the manifest doesn't list it.
+func (m *Manifest) Rig(name string) *RigSpec {
+	for i := range m.Rigs {
+		if m.Rigs[i].Name == name {
+			return &m.Rigs[i]
+		}
+	}
+	return nil
+}
+
+// currentMergeQueue returns a rig's merge_queue settings, or nil if it
+// has none yet.
+func currentMergeQueue(rigPath string) (*config.MergeQueueConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading settings: %w", err)
+	}
+	return settings.MergeQueue, nil
+}
+
+// currentPolecats returns a rig's max_polecats from the wisp layer, where
+// gt rig config set (and so gt apply) keeps it.
+func currentPolecats(townRoot, rigName string) (int, bool) {
+	switch v := wisp.NewConfig(townRoot, rigName).Get(SubjectPolecats).(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// differingKeys returns the top-level JSON keys whose values differ
+// between current and desired. A nil current differs in every key.
+func differingKeys(current, desired interface{}) []string {
+	have, want := jsonFields(current), jsonFields(desired)
+	var keys []string
+	for k := range want {
+		if !reflect.DeepEqual(have[k], want[k]) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range have {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonFields returns v's JSON object fields, or nil for a nil v.
+func jsonFields(v interface{}) map[string]interface{} {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/terminal"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -51,12 +52,20 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	backend, err := terminal.ForTown(m.townRoot)
+	if err != nil {
+		return err
+	}
 	sessionID := m.SessionName()
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
-	if running {
+	running, _ := backend.HasSession(sessionID)
+	tb, inTmux := backend.(*terminal.TmuxBackend)
+	if running && !inTmux {
+		// No way to tell a live agent from a zombie outside tmux
+		return ErrAlreadyRunning
+	} else if running {
+		t := tb.Tmux()
 		// Session exists - check if agent is actually running (healthy vs zombie)
 		if t.IsAgentAlive(sessionID) {
 			return ErrAlreadyRunning
@@ -93,20 +102,51 @@ func (m *Manager) Start(agentOverride string) error {
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
 	}
+	templateVars := config.SessionTemplateVars{
+		Role:     "mayor",
+		TownRoot: m.townRoot,
+		Command:  startupCmd,
+	}
+	windows, err := config.SessionTemplateWindows(m.townRoot, templateVars)
+	if err != nil {
+		return err
+	}
+	startupCmd, templateEnv, err := config.ApplySessionTemplate(m.townRoot, templateVars)
+	if err != nil {
+		return err
+	}
+
+	// Use centralized AgentEnv for consistency across all role startup paths
+	envVars := config.MergeEnv(config.AgentEnv(config.AgentEnvConfig{
+		Role:     "mayor",
+		TownRoot: m.townRoot,
+	}), templateEnv)
 
 	// Create session in mayorDir - Mayor's home directory within the town.
 	// Tools like gt prime use workspace.FindFromCwd() which walks UP to find
 	// town root, so running from ~/gt/mayor/ still finds ~/gt/ correctly.
+	if !inTmux {
+		// Other backends only pass variables to sessions started afterwards,
+		// and have no windows or theme.
+		for k, v := range envVars {
+			if err := backend.SetEnvironment(sessionID, k, v); err != nil {
+				return fmt.Errorf("setting session environment: %w", err)
+			}
+		}
+		if err := backend.NewSession(sessionID, mayorDir, startupCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+	t := tb.Tmux()
 	if err := t.NewSessionWithCommand(sessionID, mayorDir, startupCmd); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Open the session template's extra windows (non-fatal)
+	_ = t.OpenSessionWindows(sessionID, mayorDir, windows)
+
 	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "mayor",
-		TownRoot: m.townRoot,
-	})
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
@@ -115,6 +155,9 @@ func (m *Manager) Start(agentOverride string) error {
 	theme := tmux.MayorTheme()
 	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Mayor", "coordinator")
 
+	// Set pane-died hook for crash detection (non-fatal)
+	_ = t.SetPaneDiedHook(sessionID, "mayor")
+
 	// Wait for Claude to start - fatal if Claude fails to launch
 	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
@@ -135,11 +178,14 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	backend, err := terminal.ForTown(m.townRoot)
+	if err != nil {
+		return err
+	}
 	sessionID := m.SessionName()
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -148,11 +194,13 @@ func (m *Manager) Stop() error {
 	}
 
 	// Try graceful shutdown first (best-effort interrupt)
-	_ = t.SendKeysRaw(sessionID, "C-c")
-	time.Sleep(100 * time.Millisecond)
+	if tb, ok := backend.(*terminal.TmuxBackend); ok {
+		_ = tb.Tmux().SendKeysRaw(sessionID, "C-c")
+		time.Sleep(100 * time.Millisecond)
+	}
 
 	// Kill the session
-	if err := t.KillSession(sessionID); err != nil {
+	if err := backend.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -161,16 +209,22 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the mayor session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	backend, err := terminal.ForTown(m.townRoot)
+	if err != nil {
+		return false, err
+	}
+	return backend.HasSession(m.SessionName())
 }
 
 // Status returns information about the mayor session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	backend, err := terminal.ForTown(m.townRoot)
+	if err != nil {
+		return nil, err
+	}
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := backend.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -178,5 +232,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if tb, ok := backend.(*terminal.TmuxBackend); ok {
+		return tb.Tmux().GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
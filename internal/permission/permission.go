@@ -0,0 +1,131 @@
+// Package permission gates destructive commands by the caller's role.
+//
+// Every caller has a Level derived from GT_ROLE: the Mayor is LevelMayor,
+// polecats are LevelPolecat, other agents and humans (no GT_ROLE) are
+// LevelOperator. Town settings can override the level per actor. Mayors and
+// operators may run every gated action; polecats only those that touch their
+// own work; readonly callers none.
+package permission
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Level is a caller's permission level.
+type Level string
+
+// Permission levels.
+const (
+	LevelMayor    Level = "mayor"
+	LevelOperator Level = "operator"
+	LevelPolecat  Level = "polecat"
+	LevelReadonly Level = "readonly"
+)
+
+// HumanActor is the override key for callers without GT_ROLE.
+const HumanActor = "human"
+
+// Action is a gated destructive operation.
+type Action string
+
+// Gated actions.
+const (
+	ActionRigRemove     Action = "rig.remove"
+	ActionMQReject      Action = "mq.reject"
+	ActionMQRevert      Action = "mq.revert"
+	ActionMQAssign      Action = "mq.assign"
+	ActionPolecatRemove Action = "polecat.remove"
+	ActionPolecatNuke   Action = "polecat.nuke"
+	ActionCrewRemove    Action = "crew.remove"
+	ActionSecretGet     Action = "secret.get"
+	ActionSecretSet     Action = "secret.set"
+	ActionSecretRemove  Action = "secret.remove"
+	ActionGC            Action = "gc"
+)
+
+// ownWork lists the actions a polecat may take on its own work, such as
+// withdrawing its own MR.
+var ownWork = map[Action]bool{
+	ActionMQReject: true,
+}
+
+// ParseLevel validates a level name.
+func ParseLevel(s string) (Level, error) {
+	switch l := Level(strings.ToLower(strings.TrimSpace(s))); l {
+	case LevelMayor, LevelOperator, LevelPolecat, LevelReadonly:
+		return l, nil
+	}
+	return "", fmt.Errorf("unknown permission level %q (valid: mayor, operator, polecat, readonly)", s)
+}
+
+// Allowed reports whether a caller at level may perform action. own is true
+// when the target belongs to the caller (e.g., the polecat's own MR).
+func Allowed(level Level, action Action, own bool) bool {
+	switch level {
+	case LevelMayor, LevelOperator:
+		return true
+	case LevelPolecat:
+		return own && ownWork[action]
+	}
+	return false
+}
+
+// DefaultLevel maps a role ("mayor", "polecat", "witness", ...) to its
+// level. An empty role is a human at the terminal.
+func DefaultLevel(role string) Level {
+	switch role {
+	case "mayor":
+		return LevelMayor
+	case "polecat":
+		return LevelPolecat
+	case "", "deacon", "boot", "witness", "refinery", "crew":
+		return LevelOperator
+	}
+	return LevelReadonly
+}
+
+// Resolve returns the level for an actor (e.g., "greenplace/polecats/Toast",
+// or HumanActor), applying overrides before the role default. An exact
+// actor key wins over globs; among globs, the longest pattern wins. Invalid
+// override levels are reported as errors so a typo cannot widen access.
+func Resolve(actor, role string, overrides map[string]string) (Level, error) {
+	if value, ok := overrides[actor]; ok {
+		return ParseLevel(value)
+	}
+
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, actor); ok {
+			return ParseLevel(overrides[pattern])
+		}
+	}
+	return DefaultLevel(role), nil
+}
+
+// DeniedError reports a blocked action.
+type DeniedError struct {
+	Actor  string
+	Level  Level
+	Action Action
+	Target string
+}
+
+func (e *DeniedError) Error() string {
+	msg := fmt.Sprintf("permission denied: %s (%s) may not %s", e.Actor, e.Level, e.Action)
+	if e.Target != "" {
+		msg += " " + e.Target
+	}
+	return msg
+}
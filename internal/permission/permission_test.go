@@ -0,0 +1,94 @@
+package permission
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		level  Level
+		action Action
+		own    bool
+		want   bool
+	}{
+		{LevelMayor, ActionRigRemove, false, true},
+		{LevelOperator, ActionMQReject, false, true},
+		{LevelPolecat, ActionMQReject, true, true},
+		{LevelPolecat, ActionMQReject, false, false},
+		{LevelPolecat, ActionRigRemove, true, false},
+		{LevelPolecat, ActionPolecatNuke, true, false},
+		{LevelPolecat, ActionGC, false, false},
+		{LevelReadonly, ActionMQReject, true, false},
+		{Level("bogus"), ActionMQReject, true, false},
+	}
+	for _, tt := range tests {
+		if got := Allowed(tt.level, tt.action, tt.own); got != tt.want {
+			t.Errorf("Allowed(%s, %s, %v) = %v, want %v", tt.level, tt.action, tt.own, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultLevel(t *testing.T) {
+	tests := map[string]Level{
+		"mayor":    LevelMayor,
+		"polecat":  LevelPolecat,
+		"witness":  LevelOperator,
+		"crew":     LevelOperator,
+		"":         LevelOperator,
+		"stranger": LevelReadonly,
+	}
+	for role, want := range tests {
+		if got := DefaultLevel(role); got != want {
+			t.Errorf("DefaultLevel(%q) = %s, want %s", role, got, want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	overrides := map[string]string{
+		"greenplace/polecats/*":     "readonly",
+		"greenplace/polecats/Toast": "operator",
+		"*/crew/*":                  "polecat",
+		"*":                         "Readonly",
+	}
+
+	tests := []struct {
+		actor, role string
+		want        Level
+	}{
+		{"greenplace/polecats/Toast", "polecat", LevelOperator}, // exact key
+		{"greenplace/polecats/Nux", "polecat", LevelReadonly},   // longest glob
+		{"greenplace/crew/max", "crew", LevelPolecat},           // crew glob
+		{HumanActor, "", LevelReadonly},                         // catch-all, case-insensitive
+		{"mayor", "mayor", LevelReadonly},                       // overrides apply to the mayor too
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.actor, tt.role, overrides)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %v", tt.actor, err)
+		}
+		if got != tt.want {
+			t.Errorf("Resolve(%q) = %s, want %s", tt.actor, got, tt.want)
+		}
+	}
+
+	if got, _ := Resolve("greenplace/polecats/Nux", "polecat", nil); got != LevelPolecat {
+		t.Errorf("Resolve without overrides = %s, want %s", got, LevelPolecat)
+	}
+	if _, err := Resolve(HumanActor, "", map[string]string{"human": "admin"}); err == nil {
+		t.Error("Resolve with invalid level should fail")
+	}
+}
+
+func TestDeniedError(t *testing.T) {
+	var err error = &DeniedError{Actor: "greenplace/polecats/Nux", Level: LevelPolecat, Action: ActionRigRemove, Target: "greenplace"}
+	want := "permission denied: greenplace/polecats/Nux (polecat) may not rig.remove greenplace"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	var denied *DeniedError
+	if !errors.As(err, &denied) {
+		t.Error("errors.As should match *DeniedError")
+	}
+}
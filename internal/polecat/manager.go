@@ -8,7 +8,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
@@ -228,109 +227,41 @@ type AddOptions struct {
 // Add creates a new polecat as a git worktree from the repo base.
 // Uses the shared bare repo (.repo.git) if available, otherwise mayor/rig.
 // This is much faster than a full clone and shares objects with all worktrees.
-// buildBranchName creates a branch name using the configured template or default format.
-// Supported template variables:
+// buildBranchName creates a branch name from the rig's branch policy (the
+// polecat_branch_template config, see rig.BranchPolicy). Supported template
+// variables:
 // - {user}: git config user.name
 // - {year}: current year (YY format)
 // - {month}: current month (MM format)
 // - {name}: polecat name
+// - {id}: full issue ID
 // - {issue}: issue ID (without prefix)
 // - {description}: sanitized issue title
 // - {timestamp}: unique timestamp
 //
 // If no template is configured or template is empty, uses default format:
-// - polecat/{name}/{issue}@{timestamp} when issue is available
+// - polecat/{name}/{id}@{timestamp} when issue is available
 // - polecat/{name}-{timestamp} otherwise
 func (m *Manager) buildBranchName(name, issue string) string {
-	template := m.rig.GetStringConfig("polecat_branch_template")
-
-	// No template configured - use default behavior for backward compatibility
-	if template == "" {
-		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 36)
-		if issue != "" {
-			return fmt.Sprintf("polecat/%s/%s@%s", name, issue, timestamp)
-		}
-		return fmt.Sprintf("polecat/%s-%s", name, timestamp)
-	}
-
-	// Build template variables
-	vars := make(map[string]string)
-
-	// {user} - from git config user.name
-	if userName, err := m.git.ConfigGet("user.name"); err == nil && userName != "" {
-		vars["{user}"] = userName
-	} else {
-		vars["{user}"] = "unknown"
-	}
-
-	// {year} and {month}
-	now := time.Now()
-	vars["{year}"] = now.Format("06")  // YY format
-	vars["{month}"] = now.Format("01") // MM format
-
-	// {name}
-	vars["{name}"] = name
-
-	// {timestamp}
-	vars["{timestamp}"] = strconv.FormatInt(now.UnixMilli(), 36)
-
-	// {issue} - issue ID without prefix
-	if issue != "" {
-		// Strip prefix (e.g., "gt-123" -> "123")
-		if idx := strings.Index(issue, "-"); idx >= 0 {
-			vars["{issue}"] = issue[idx+1:]
-		} else {
-			vars["{issue}"] = issue
-		}
-	} else {
-		vars["{issue}"] = ""
+	policy, err := m.rig.BranchPolicy()
+	if err != nil {
+		// A bad template shouldn't block spawning; fall back to the default
+		fmt.Fprintf(os.Stderr, "Warning: %v; using default branch names\n", err)
+		policy = nil
 	}
 
-	// {description} - try to get from beads if issue is set
-	if issue != "" {
-		if issueData, err := m.beads.Show(issue); err == nil && issueData.Title != "" {
-			// Sanitize title for branch name: lowercase, replace spaces/special chars with hyphens
-			desc := strings.ToLower(issueData.Title)
-			desc = strings.Map(func(r rune) rune {
-				if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-					return r
-				}
-				return '-'
-			}, desc)
-			// Remove consecutive hyphens and trim
-			desc = strings.Trim(desc, "-")
-			for strings.Contains(desc, "--") {
-				desc = strings.ReplaceAll(desc, "--", "-")
-			}
-			// Limit length to keep branch names reasonable
-			if len(desc) > 40 {
-				desc = desc[:40]
-			}
-			vars["{description}"] = desc
-		} else {
-			vars["{description}"] = ""
+	vars := rig.BranchVars{Name: name, Issue: issue, Now: time.Now()}
+	if policy.Uses("user") {
+		if userName, err := m.git.ConfigGet("user.name"); err == nil {
+			vars.User = userName
 		}
-	} else {
-		vars["{description}"] = ""
-	}
-
-	// Replace all variables in template
-	result := template
-	for key, value := range vars {
-		result = strings.ReplaceAll(result, key, value)
 	}
-
-	// Clean up any remaining empty segments (e.g., "adam///" -> "adam")
-	parts := strings.Split(result, "/")
-	cleanParts := make([]string, 0, len(parts))
-	for _, part := range parts {
-		if part != "" {
-			cleanParts = append(cleanParts, part)
+	if policy.Uses("description") && issue != "" {
+		if issueData, err := m.beads.Show(issue); err == nil {
+			vars.Title = issueData.Title
 		}
 	}
-	result = strings.Join(cleanParts, "/")
-
-	return result
+	return policy.Name(vars)
 }
 
 // Polecat state is derived from beads assignee field, not state.json.
@@ -386,7 +317,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+	if err := repoGit.WorktreeAddFromRefSparse(clonePath, branchName, startPoint, m.rig.SparsePaths()); err != nil {
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 
@@ -786,7 +717,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	// Old branches are left behind - they're ephemeral (never pushed to origin)
 	// and will be cleaned up by garbage collection
 	branchName := m.buildBranchName(name, opts.HookBead)
-	if err := repoGit.WorktreeAddFromRef(newClonePath, branchName, startPoint); err != nil {
+	if err := repoGit.WorktreeAddFromRefSparse(newClonePath, branchName, startPoint, m.rig.SparsePaths()); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/terminal"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -37,14 +38,48 @@ var (
 type SessionManager struct {
 	tmux *tmux.Tmux
 	rig  *rig.Rig
+
+	// backend hosts the sessions, as the town's session_backend says. tmux
+	// sessions go through the tmux wrapper above.
+	backend    terminal.Backend
+	backendErr error
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
 func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
-	return &SessionManager{
+	m := &SessionManager{
 		tmux: t,
 		rig:  r,
 	}
+	m.backend, m.backendErr = sessionBackend(t, filepath.Dir(r.Path))
+	return m
+}
+
+// sessionBackend returns the town's session backend, hosting tmux sessions
+// through t.
+func sessionBackend(t *tmux.Tmux, townRoot string) (terminal.Backend, error) {
+	b, err := terminal.ForTown(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if b.Name() == terminal.BackendTmux {
+		return terminal.NewTmuxBackendFor(t), nil
+	}
+	return b, nil
+}
+
+// inTmux reports whether sessions run in tmux, which alone supports
+// theming, crash hooks, and typing into the session.
+func (m *SessionManager) inTmux() bool {
+	return m.backend != nil && m.backend.Name() == terminal.BackendTmux
+}
+
+// hasSession checks the session through the backend.
+func (m *SessionManager) hasSession(sessionID string) (bool, error) {
+	if m.backendErr != nil {
+		return false, m.backendErr
+	}
+	return m.backend.HasSession(sessionID)
 }
 
 // SessionStartOptions configures polecat session startup.
@@ -149,7 +184,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Check if session already exists
 	// Note: Orphan sessions are cleaned up by ReconcilePool during AllocateName,
 	// so by this point, any existing session should be legitimately in use.
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -203,40 +238,63 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if command == "" {
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
 	}
+	townRoot := filepath.Dir(m.rig.Path)
+	templateVars := config.SessionTemplateVars{
+		Role:     "polecat",
+		TownRoot: townRoot,
+		Rig:      m.rig.Name,
+		RigPath:  m.rig.Path,
+		Worker:   polecat,
+		Issue:    opts.Issue,
+		Command:  command,
+	}
+	windows, err := config.SessionTemplateWindows(townRoot, templateVars)
+	if err != nil {
+		return err
+	}
+	command, templateEnv, err := config.ApplySessionTemplate(townRoot, templateVars)
+	if err != nil {
+		return err
+	}
 	// Prepend runtime config dir env if needed
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
 
-	// Create session with command directly to avoid send-keys race condition.
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
-
-	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	env := config.MergeEnv(config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
 		AgentName:        polecat,
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		BeadsNoDaemon:    true,
-	})
-	for k, v := range envVars {
-		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
-	}
+	}), templateEnv)
 
-	// Hook the issue to the polecat if provided via --issue flag
-	if opts.Issue != "" {
-		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
-		if err := m.hookIssue(opts.Issue, agentID, workDir); err != nil {
-			fmt.Printf("Warning: could not hook issue %s: %v\n", opts.Issue, err)
+	if !m.inTmux() {
+		if err := m.startDetached(sessionID, workDir, command, env); err != nil {
+			return err
 		}
+		m.hookStartIssue(polecat, opts.Issue, workDir)
+		return nil
+	}
+
+	// Create session with command directly to avoid send-keys race condition.
+	// See: https://github.com/anthropics/gastown/issues/280
+	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Open the session template's extra windows (non-fatal)
+	debugSession("OpenSessionWindows", m.tmux.OpenSessionWindows(sessionID, workDir, windows))
+
+	// Set environment (non-fatal: session works without these)
+	for k, v := range env {
+		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+	}
+
+	m.hookStartIssue(polecat, opts.Issue, workDir)
+
 	// Apply theme (non-fatal)
 	theme := tmux.AssignTheme(m.rig.Name)
 	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
@@ -282,7 +340,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Verify session survived startup - if the command crashed, the session may have died.
 	// Without this check, Start() would return success even if the pane died during initialization.
-	running, err = m.tmux.HasSession(sessionID)
+	running, err = m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("verifying session: %w", err)
 	}
@@ -293,11 +351,38 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	return nil
 }
 
+// startDetached starts a session on a backend other than tmux. Variables
+// are recorded before the session starts, since these backends pass them
+// only to processes started afterwards. There is no terminal to theme or
+// nudge, so the agent gets its instructions from the startup beacon alone.
+func (m *SessionManager) startDetached(sessionID, workDir, command string, env map[string]string) error {
+	for k, v := range env {
+		if err := m.backend.SetEnvironment(sessionID, k, v); err != nil {
+			return fmt.Errorf("setting session environment: %w", err)
+		}
+	}
+	if err := m.backend.NewSession(sessionID, workDir, command); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	return nil
+}
+
+// hookStartIssue hooks the issue to the polecat if provided via --issue flag.
+func (m *SessionManager) hookStartIssue(polecat, issue, workDir string) {
+	if issue == "" {
+		return
+	}
+	agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+	if err := m.hookIssue(issue, agentID, workDir); err != nil {
+		fmt.Printf("Warning: could not hook issue %s: %v\n", issue, err)
+	}
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -306,14 +391,14 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	}
 
 	// Try graceful shutdown first
-	if !force {
+	if !force && m.inTmux() {
 		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	// The backend kills all descendant processes too. This prevents orphan
+	// bash processes from Claude's Bash tool surviving session termination.
+	if err := m.backend.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -323,14 +408,14 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // IsRunning checks if a polecat session is active.
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	return m.tmux.HasSession(sessionID)
+	return m.hasSession(sessionID)
 }
 
 // Status returns detailed status for a polecat session.
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -342,7 +427,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		RigName:   m.rig.Name,
 	}
 
-	if !running {
+	if !running || !m.inTmux() {
 		return info, nil
 	}
 
@@ -381,7 +466,10 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 
 // List returns information about all polecat sessions for this rig.
 func (m *SessionManager) List() ([]SessionInfo, error) {
-	sessions, err := m.tmux.ListSessions()
+	if m.backendErr != nil {
+		return nil, m.backendErr
+	}
+	sessions, err := m.backend.ListSessions()
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +498,7 @@ func (m *SessionManager) List() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -418,6 +506,9 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
+	if !m.inTmux() {
+		return m.backend.Attach(sessionID, false)
+	}
 	return m.tmux.AttachSession(sessionID)
 }
 
@@ -425,7 +516,7 @@ func (m *SessionManager) Attach(polecat string) error {
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -433,12 +524,12 @@ func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.Capture(sessionID, lines)
 }
 
 // CaptureSession returns the recent output from a session by raw session ID.
 func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, error) {
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -446,20 +537,23 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.Capture(sessionID, lines)
 }
 
 // Inject sends a message to a polecat session.
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if !running {
 		return ErrSessionNotFound
 	}
+	if !m.inTmux() {
+		return m.backend.SendText(sessionID, message)
+	}
 
 	debounceMs := 200 + (len(message)/1024)*100
 	if debounceMs > 1500 {
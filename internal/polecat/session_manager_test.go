@@ -1,14 +1,18 @@
 package polecat
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/terminal"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -172,6 +176,59 @@ func TestInjectNotFound(t *testing.T) {
 	}
 }
 
+// TestProcessBackendSession starts and stops a polecat session in a town
+// whose settings select the process backend.
+func TestProcessBackendSession(t *testing.T) {
+	t.Setenv(terminal.EnvBackend, "")
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.SessionBackend = terminal.BackendProcess
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	r := &rig.Rig{Name: "gastown", Path: filepath.Join(townRoot, "gastown")}
+	workDir := filepath.Join(r.Path, "polecats", "Toast")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := NewSessionManager(tmux.NewTmux(), r)
+
+	command := "echo started $GT_POLECAT; exec sleep 30"
+	if _, err := exec.LookPath("sh"); err != nil && runtime.GOOS == "windows" {
+		command = "echo started %GT_POLECAT% & ping -n 30 127.0.0.1 >NUL"
+	}
+	if err := m.Start("Toast", SessionStartOptions{WorkDir: workDir, Command: command}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop("Toast", true) })
+
+	if running, err := m.IsRunning("Toast"); err != nil || !running {
+		t.Fatalf("IsRunning = %v, %v; want true", running, err)
+	}
+	if err := m.Start("Toast", SessionStartOptions{WorkDir: workDir, Command: command}); !errors.Is(err, ErrSessionRunning) {
+		t.Errorf("second Start = %v, want ErrSessionRunning", err)
+	}
+	var out string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if out, _ = m.Capture("Toast", 10); strings.Contains(out, "started Toast") {
+			break
+		}
+	}
+	if !strings.Contains(out, "started Toast") {
+		t.Errorf("Capture = %q, want the session's output with its environment", out)
+	}
+
+	if err := m.Stop("Toast", false); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if running, _ := m.IsRunning("Toast"); running {
+		t.Error("session still running after Stop")
+	}
+	if err := m.Stop("Toast", false); err != ErrSessionNotFound {
+		t.Errorf("second Stop = %v, want ErrSessionNotFound", err)
+	}
+}
+
 // TestPolecatCommandFormat verifies the polecat session command exports
 // GT_ROLE, GT_RIG, GT_POLECAT, and BD_ACTOR inline before starting Claude.
 // This is a regression test for gt-y41ep - env vars must be exported inline
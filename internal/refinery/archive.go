@@ -0,0 +1,55 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// LoadArchiveAfter reads how long closed MRs stay in a rig's beads database
+// before they are archived, from merge_queue.archive_after in its
+// settings/config.json. Returns 0 if the rig has no archival policy.
+func LoadArchiveAfter(rigPath string) (time.Duration, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if settings.MergeQueue == nil || settings.MergeQueue.ArchiveAfter == "" {
+		return 0, nil
+	}
+	return ParseArchiveAge(settings.MergeQueue.ArchiveAfter)
+}
+
+// ParseArchiveAge parses an archive age: a Go duration or whole days ("30d").
+func ParseArchiveAge(s string) (time.Duration, error) {
+	d, err := parseSLADuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid archive age %q (want e.g. 30d or 720h)", s)
+	}
+	return d, nil
+}
+
+// ArchivableMRs returns the closed MRs among issues that closed before
+// cutoff. An MR without a close time is judged by when it was last updated.
+func ArchivableMRs(issues []*beads.Issue, cutoff time.Time) []*beads.Issue {
+	var old []*beads.Issue
+	for _, issue := range issues {
+		if issue.Status != "closed" {
+			continue
+		}
+		closed := parseTime(issue.ClosedAt)
+		if closed.IsZero() {
+			closed = parseTime(issue.UpdatedAt)
+		}
+		if !closed.IsZero() && closed.Before(cutoff) {
+			old = append(old, issue)
+		}
+	}
+	return old
+}
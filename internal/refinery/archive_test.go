@@ -0,0 +1,48 @@
+package refinery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseArchiveAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"72h", 72 * time.Hour, false},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseArchiveAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseArchiveAge(%q) = %v, %v; want %v, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestArchivableMRs(t *testing.T) {
+	cutoff := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+	issues := []*beads.Issue{
+		{ID: "old", Status: "closed", ClosedAt: "2026-08-01T10:00:00Z"},
+		{ID: "recent", Status: "closed", ClosedAt: "2026-10-01T10:00:00Z"},
+		{ID: "open", Status: "open", UpdatedAt: "2026-08-01T10:00:00Z"},
+		{ID: "no-close-time", Status: "closed", UpdatedAt: "2026-08-01T10:00:00Z"},
+		{ID: "no-times", Status: "closed"},
+	}
+
+	got := ArchivableMRs(issues, cutoff)
+	if len(got) != 2 || got[0].ID != "old" || got[1].ID != "no-close-time" {
+		var ids []string
+		for _, i := range got {
+			ids = append(ids, i.ID)
+		}
+		t.Errorf("ArchivableMRs = %v, want [old no-close-time]", ids)
+	}
+}
@@ -0,0 +1,238 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultArtifactRetention is how long an MR's artifacts are kept after
+// its last check run, for rigs that don't set merge_queue.artifacts.retention.
+const DefaultArtifactRetention = 14 * 24 * time.Hour
+
+// ArtifactStore keeps what the refinery's check runs on each MR leave
+// behind, under <rig>/.runtime/artifacts/<mr-id>/: each check's log (as
+// <check>.log) and the files matching the rig's artifact paths (under
+// <check>/), from the MR's latest run of that check.
+type ArtifactStore struct {
+	root      string
+	paths     []string
+	retention time.Duration
+}
+
+// NewArtifactStore builds a rig's artifact store from config. A nil cfg
+// keeps logs only, for the default retention.
+func NewArtifactStore(rigPath string, cfg *config.MergeArtifactsConfig) (*ArtifactStore, error) {
+	s := &ArtifactStore{
+		root:      filepath.Join(rigPath, ".runtime", "artifacts"),
+		retention: DefaultArtifactRetention,
+	}
+	if cfg == nil {
+		return s, nil
+	}
+	for _, pattern := range cfg.Paths {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("invalid artifact path %q: %v", pattern, err)
+		}
+	}
+	s.paths = cfg.Paths
+	if cfg.Retention != "" {
+		d, err := parseSLADuration(cfg.Retention)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid artifact retention %q (want e.g. 14d or 72h)", cfg.Retention)
+		}
+		s.retention = d
+	}
+	return s, nil
+}
+
+// LoadArtifactStore reads a rig's artifact settings from its
+// settings/config.json. A rig without them keeps logs only.
+func LoadArtifactStore(rigPath string) (*ArtifactStore, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+	var cfg *config.MergeArtifactsConfig
+	if settings != nil && settings.MergeQueue != nil {
+		cfg = settings.MergeQueue.Artifacts
+	}
+	return NewArtifactStore(rigPath, cfg)
+}
+
+// Retention returns how long an MR's artifacts are kept after its last
+// check run.
+func (s *ArtifactStore) Retention() time.Duration {
+	return s.retention
+}
+
+// Dir returns the directory holding an MR's artifacts.
+func (s *ArtifactStore) Dir(mrID string) string {
+	return filepath.Join(s.root, mrID)
+}
+
+// Save keeps a check run on an MR: its output as <check>.log, and the
+// files in checkout matching the artifact paths under <check>/, replacing
+// those from the MR's earlier runs of the check. Returns the MR's artifact
+// directory.
+func (s *ArtifactStore) Save(mrID, check, checkout string, run CheckRun) (string, error) {
+	if mrID == "" || filepath.Base(mrID) != mrID || mrID == "." || mrID == ".." {
+		return "", fmt.Errorf("invalid MR ID %q", mrID)
+	}
+	dir := s.Dir(mrID)
+	files := filepath.Join(dir, check)
+	if err := os.RemoveAll(files); err != nil {
+		return "", fmt.Errorf("clearing %s's artifacts: %w", check, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating artifact dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, check+".log"), []byte(run.Output), 0644); err != nil {
+		return "", fmt.Errorf("saving %s log: %w", check, err)
+	}
+
+	if len(s.paths) > 0 {
+		err := filepath.WalkDir(checkout, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(checkout, p)
+			rel = filepath.ToSlash(rel)
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || !s.matches(rel) {
+				return nil
+			}
+			return copyArtifact(p, filepath.Join(files, filepath.FromSlash(rel)))
+		})
+		if err != nil {
+			return "", fmt.Errorf("collecting %s artifacts: %w", check, err)
+		}
+	}
+
+	// Retention counts from the latest run
+	now := time.Now()
+	_ = os.Chtimes(dir, now, now)
+	return dir, nil
+}
+
+// matches reports whether a file in the checkout is an artifact.
+func (s *ArtifactStore) matches(file string) bool {
+	for _, pattern := range s.paths {
+		if matchProtectedPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyArtifact(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Expired returns the artifact directories of MRs whose last check run
+// was longer ago than the retention.
+func (s *ArtifactStore) Expired(now time.Time) ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading artifacts: %w", err)
+	}
+	cutoff := now.Add(-s.retention)
+	var expired []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !entry.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		expired = append(expired, filepath.Join(s.root, entry.Name()))
+	}
+	return expired, nil
+}
+
+// Prune removes the artifacts of MRs whose last check run was longer ago
+// than the retention, and returns their IDs.
+func (s *ArtifactStore) Prune(now time.Time) ([]string, error) {
+	expired, err := s.Expired(now)
+	if err != nil {
+		return nil, err
+	}
+	var pruned []string
+	var errs []error
+	for _, dir := range expired {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pruned = append(pruned, filepath.Base(dir))
+	}
+	return pruned, errors.Join(errs...)
+}
+
+// Artifact is a file kept from an MR's check runs.
+type Artifact struct {
+	Name     string    `json:"name"` // Relative to the MR's artifact directory
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// ListArtifacts lists the files in an MR's artifact directory, by name.
+// A directory that doesn't exist (e.g., pruned) has none.
+func ListArtifacts(dir string) ([]Artifact, error) {
+	var artifacts []Artifact
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		artifacts = append(artifacts, Artifact{Name: filepath.ToSlash(rel), Path: p, Size: info.Size(), Modified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing artifacts: %w", err)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
@@ -0,0 +1,162 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewArtifactStore(t *testing.T) {
+	s, err := NewArtifactStore("/rig", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Retention() != DefaultArtifactRetention {
+		t.Errorf("default retention = %v", s.Retention())
+	}
+	if got := s.Dir("gp-mr-1"); got != filepath.Join("/rig", ".runtime", "artifacts", "gp-mr-1") {
+		t.Errorf("Dir = %s", got)
+	}
+
+	s, err = NewArtifactStore("/rig", &config.MergeArtifactsConfig{Retention: "3d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Retention() != 72*time.Hour {
+		t.Errorf("retention = %v, want 72h", s.Retention())
+	}
+
+	for _, cfg := range []*config.MergeArtifactsConfig{
+		{Retention: "soon"},
+		{Retention: "0h"},
+		{Paths: []string{"coverage[.out"}},
+	} {
+		if _, err := NewArtifactStore("/rig", cfg); err == nil {
+			t.Errorf("NewArtifactStore(%+v) succeeded", *cfg)
+		}
+	}
+}
+
+func TestArtifactStoreSave(t *testing.T) {
+	rigPath := t.TempDir()
+	checkout := t.TempDir()
+	for name, content := range map[string]string{
+		"junit.xml":             "<testsuites/>",
+		"build/bin/app":         "binary",
+		"main.go":               "package main",
+		".git/junit.xml":        "not an artifact",
+		"pkg/report/junit.xml":  "<testsuites/>",
+		"build-notes/README.md": "no",
+	} {
+		path := filepath.Join(checkout, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := NewArtifactStore(rigPath, &config.MergeArtifactsConfig{Paths: []string{"*.xml", "build/**"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := s.Save("gp-mr-1", CheckTests, checkout, CheckRun{Output: "ok all\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != s.Dir("gp-mr-1") {
+		t.Errorf("Save returned %s, want %s", dir, s.Dir("gp-mr-1"))
+	}
+
+	artifacts, err := ListArtifacts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range artifacts {
+		names = append(names, a.Name)
+	}
+	want := []string{"tests.log", "tests/build/bin/app", "tests/junit.xml", "tests/pkg/report/junit.xml"}
+	if len(names) != len(want) {
+		t.Fatalf("artifacts = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("artifacts = %v, want %v", names, want)
+			break
+		}
+	}
+	if log, _ := os.ReadFile(filepath.Join(dir, "tests.log")); string(log) != "ok all\n" {
+		t.Errorf("tests.log = %q", log)
+	}
+
+	// A later run replaces the earlier one's artifacts, but not other checks'
+	if _, err := s.Save("gp-mr-1", CheckVerify, checkout, CheckRun{Output: "verified\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(checkout, "junit.xml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save("gp-mr-1", CheckTests, checkout, CheckRun{Output: "FAIL\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tests", "junit.xml")); !os.IsNotExist(err) {
+		t.Errorf("earlier run's junit.xml kept: %v", err)
+	}
+	if log, _ := os.ReadFile(filepath.Join(dir, "tests.log")); string(log) != "FAIL\n" {
+		t.Errorf("tests.log = %q, want the latest run's", log)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "verify", "junit.xml")); err != nil {
+		t.Errorf("verify's artifacts lost: %v", err)
+	}
+
+	if _, err := s.Save("../escape", CheckTests, checkout, CheckRun{}); err == nil {
+		t.Error("Save accepted an MR ID outside the store")
+	}
+}
+
+func TestArtifactStorePrune(t *testing.T) {
+	rigPath := t.TempDir()
+	s, err := NewArtifactStore(rigPath, &config.MergeArtifactsConfig{Retention: "1d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing kept yet
+	if pruned, err := s.Prune(time.Now()); err != nil || len(pruned) != 0 {
+		t.Fatalf("Prune on an empty store = %v, %v", pruned, err)
+	}
+
+	for _, id := range []string{"gp-mr-old", "gp-mr-new"} {
+		if _, err := s.Save(id, CheckTests, t.TempDir(), CheckRun{Output: "ok\n"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(s.Dir("gp-mr-old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := s.Prune(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 1 || pruned[0] != "gp-mr-old" {
+		t.Errorf("pruned %v, want [gp-mr-old]", pruned)
+	}
+	if _, err := os.Stat(s.Dir("gp-mr-old")); !os.IsNotExist(err) {
+		t.Errorf("old MR's artifacts kept: %v", err)
+	}
+	if _, err := os.Stat(s.Dir("gp-mr-new")); err != nil {
+		t.Errorf("recent MR's artifacts pruned: %v", err)
+	}
+
+	// A pruned MR lists no artifacts
+	if artifacts, err := ListArtifacts(s.Dir("gp-mr-old")); err != nil || len(artifacts) != 0 {
+		t.Errorf("ListArtifacts after prune = %v, %v", artifacts, err)
+	}
+}
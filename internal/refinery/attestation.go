@@ -0,0 +1,153 @@
+package refinery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultAttestationNotesRef is where attestation git notes are written.
+const DefaultAttestationNotesRef = "refs/notes/gastown/attestations"
+
+// Attestation records what the refinery merged, where, and on whose
+// say-so. It is stored on the MR bead's attestation field as JSON, and
+// optionally as a git note on the merge commit.
+type Attestation struct {
+	MR          string    `json:"mr"`
+	Rig         string    `json:"rig"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	Target      string    `json:"target"`
+	Commit      string    `json:"commit"`
+	Signed      string    `json:"signed,omitempty"` // Signature format, if the refinery signed Commit
+	Checks      []string  `json:"checks,omitempty"` // Checks that passed
+	Tests       string    `json:"tests,omitempty"`  // Latest test results summary
+	ApprovedBy  []string  `json:"approved_by,omitempty"`
+	MergedBy    string    `json:"merged_by"`
+	MergedAt    time.Time `json:"merged_at"`
+}
+
+// NewAttestation builds the attestation for merged MR mrID from its MR
+// fields, which must already carry the merge commit.
+func NewAttestation(mrID string, fields *beads.MRFields, signed, mergedBy string, now time.Time) Attestation {
+	return Attestation{
+		MR:          mrID,
+		Rig:         fields.Rig,
+		SourceIssue: fields.SourceIssue,
+		Branch:      fields.Branch,
+		Target:      fields.Target,
+		Commit:      fields.MergeCommit,
+		Signed:      signed,
+		Checks:      SplitMRList(fields.ChecksPassed),
+		Tests:       fields.TestResults,
+		ApprovedBy:  SplitMRList(fields.ApprovedBy),
+		MergedBy:    mergedBy,
+		MergedAt:    now.UTC().Truncate(time.Second),
+	}
+}
+
+// ParseAttestation parses an MR's attestation field.
+func ParseAttestation(s string) (*Attestation, error) {
+	var a Attestation
+	if err := json.Unmarshal([]byte(s), &a); err != nil {
+		return nil, fmt.Errorf("parsing attestation: %w", err)
+	}
+	return &a, nil
+}
+
+// String returns the attestation as single-line JSON, as stored on the
+// MR bead.
+func (a Attestation) String() string {
+	data, _ := json.Marshal(a)
+	return string(data)
+}
+
+// Attestations holds a rig's merge_queue.attestation settings. A nil
+// *Attestations records no attestations.
+type Attestations struct {
+	notesRef string // "" = bead only
+}
+
+// NewAttestations builds attestation settings from config. Returns nil if
+// cfg is nil.
+func NewAttestations(cfg *config.MergeAttestationConfig) *Attestations {
+	if cfg == nil {
+		return nil
+	}
+	a := &Attestations{}
+	if cfg.GitNotes {
+		a.notesRef = cfg.NotesRef
+		if a.notesRef == "" {
+			a.notesRef = DefaultAttestationNotesRef
+		}
+	}
+	return a
+}
+
+// LoadAttestations reads attestation settings from a rig's
+// settings/config.json. A missing settings file or attestation section
+// yields nil (no attestations).
+func LoadAttestations(rigPath string) (*Attestations, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewAttestations(settings.MergeQueue.Attestation), nil
+}
+
+// NotesRef returns the notes ref attestations are attached under, or "" if
+// they aren't written as git notes.
+func (a *Attestations) NotesRef() string {
+	if a == nil {
+		return ""
+	}
+	return a.notesRef
+}
+
+// Attest records the attestation for merged MR mrID, whose merge commit was
+// signed in format signed ("" if unsigned): on the MR bead, and as a git
+// note pushed to origin if the rig asks for notes. Returns nil if the rig
+// records no attestations.
+func (e *Engineer) Attest(a *Attestations, mrID, signed string) (*Attestation, error) {
+	if a == nil {
+		return nil, nil
+	}
+	mr, fields, err := e.mergedMR(mrID)
+	if err != nil {
+		return nil, err
+	}
+	att := NewAttestation(mr.ID, fields, signed, e.actor(), time.Now())
+	if att.Target == "" {
+		att.Target = e.rig.DefaultBranch()
+	}
+	if att.Rig == "" {
+		att.Rig = e.rig.Name
+	}
+
+	fields.Attestation = att.String()
+	desc := beads.SetMRFields(mr, fields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return &att, fmt.Errorf("recording attestation on %s: %w", mr.ID, err)
+	}
+
+	if ref := a.NotesRef(); ref != "" {
+		note, _ := json.MarshalIndent(att, "", "  ")
+		if err := e.git.AddNote(ref, att.Commit, string(note)); err != nil {
+			return &att, fmt.Errorf("writing attestation note on %s: %w", shortCommit(att.Commit), err)
+		}
+		if err := e.git.Push(e.remote, ref, false); err != nil {
+			return &att, fmt.Errorf("pushing %s: %w", ref, err)
+		}
+	}
+	return &att, nil
+}
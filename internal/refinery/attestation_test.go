@@ -0,0 +1,54 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewAttestations(t *testing.T) {
+	if a := NewAttestations(nil); a != nil || a.NotesRef() != "" {
+		t.Errorf("NewAttestations(nil) = %v, want nil", a)
+	}
+	if ref := NewAttestations(&config.MergeAttestationConfig{}).NotesRef(); ref != "" {
+		t.Errorf("bead-only attestations NotesRef = %q, want none", ref)
+	}
+	if ref := NewAttestations(&config.MergeAttestationConfig{GitNotes: true}).NotesRef(); ref != DefaultAttestationNotesRef {
+		t.Errorf("NotesRef = %q, want %q", ref, DefaultAttestationNotesRef)
+	}
+	if ref := NewAttestations(&config.MergeAttestationConfig{GitNotes: true, NotesRef: "refs/notes/audit"}).NotesRef(); ref != "refs/notes/audit" {
+		t.Errorf("NotesRef = %q, want refs/notes/audit", ref)
+	}
+}
+
+func TestAttestationRoundTrip(t *testing.T) {
+	fields := &beads.MRFields{
+		Branch:       "polecat/toast",
+		Target:       "main",
+		SourceIssue:  "gp-abc",
+		Rig:          "greenplace",
+		MergeCommit:  "1a2b3c4d5e6f",
+		ChecksPassed: "lint,unit",
+		ApprovedBy:   "alice,bob",
+		TestResults:  "42 passed",
+	}
+	now := time.Date(2026, 10, 15, 12, 0, 0, 500, time.UTC)
+	a := NewAttestation("gp-mr-1", fields, SigningSSH, "greenplace/refinery", now)
+	if !reflect.DeepEqual(a.Checks, []string{"lint", "unit"}) || !reflect.DeepEqual(a.ApprovedBy, []string{"alice", "bob"}) {
+		t.Errorf("attestation lists = %v / %v", a.Checks, a.ApprovedBy)
+	}
+
+	// Survives storage in an MR bead's description
+	fields.Attestation = a.String()
+	issue := &beads.Issue{Description: beads.FormatMRFields(fields)}
+	parsed, err := ParseAttestation(beads.ParseMRFields(issue).Attestation)
+	if err != nil {
+		t.Fatalf("ParseAttestation: %v", err)
+	}
+	if !reflect.DeepEqual(*parsed, a) {
+		t.Errorf("round trip = %+v, want %+v", *parsed, a)
+	}
+}
@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// ErrNotMerged is returned when backporting an MR that hasn't merged.
+var ErrNotMerged = errors.New("merge request has not been merged")
+
+// Backport is the outcome of backporting a merged MR onto one target.
+type Backport struct {
+	Target string `json:"target"`
+	Branch string `json:"branch"`
+	MRID   string `json:"mr_id,omitempty"` // Cherry-pick MR, if submitted
+	Error  string `json:"error,omitempty"`
+}
+
+// SubmitBackport cherry-picks merged MR mrID onto target and submits the
+// result as a backport MR (backport_of: <mr-id>), recording it in the
+// original's backports field. target must be one of the rig's merge
+// targets other than the one the MR merged into.
+func (e *Engineer) SubmitBackport(mrID, target string) (Backport, error) {
+	mr, fields, err := e.mergedMR(mrID)
+	if err != nil {
+		return Backport{}, err
+	}
+	targets, err := LoadTargets(e.rig.Path, e.rig.DefaultBranch())
+	if err != nil {
+		return Backport{}, fmt.Errorf("loading merge targets: %w", err)
+	}
+	if err := targets.Check(target); err != nil {
+		return Backport{}, err
+	}
+	if target == fields.Target || (fields.Target == "" && target == targets.Default()) {
+		return Backport{}, fmt.Errorf("%s already merged into %s", mr.ID, target)
+	}
+
+	bp, err := e.backport(mr, fields, target)
+	if err != nil {
+		return bp, err
+	}
+	return bp, e.recordBackports(mr, fields, []string{bp.MRID})
+}
+
+// SubmitBackports submits a cherry-pick MR of merged MR mrID onto each
+// target configured to backport merges into its target (see
+// Targets.BackportTargets). Revert MRs are not backported. A target whose
+// cherry-pick fails (e.g., conflicts) is reported with its error and
+// skipped; the rest are still submitted.
+func (e *Engineer) SubmitBackports(mrID string) ([]Backport, error) {
+	mr, fields, err := e.mergedMR(mrID)
+	if errors.Is(err, ErrNotMerged) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fields.Reverts != "" {
+		return nil, nil
+	}
+
+	targets, err := LoadTargets(e.rig.Path, e.rig.DefaultBranch())
+	if err != nil {
+		return nil, fmt.Errorf("loading merge targets: %w", err)
+	}
+	target := fields.Target
+	if target == "" {
+		target = targets.Default()
+	}
+
+	var backports []Backport
+	var submitted []string
+	for _, onto := range targets.BackportTargets(target) {
+		bp, _ := e.backport(mr, fields, onto)
+		if bp.MRID != "" {
+			submitted = append(submitted, bp.MRID)
+		}
+		backports = append(backports, bp)
+	}
+	if len(submitted) > 0 {
+		return backports, e.recordBackports(mr, fields, submitted)
+	}
+	return backports, nil
+}
+
+// mergedMR fetches a merge request and its fields, requiring that it has
+// merged.
+func (e *Engineer) mergedMR(mrID string) (*beads.Issue, *beads.MRFields, error) {
+	mr, err := e.beads.Show(mrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching merge request %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		return nil, nil, fmt.Errorf("%s is not a merge request (no MR fields)", mr.ID)
+	}
+	if fields.MergeCommit == "" {
+		return nil, nil, fmt.Errorf("%w: %s has no merge_commit", ErrNotMerged, mr.ID)
+	}
+	return mr, fields, nil
+}
+
+// backport cherry-picks mr's merge commit onto a backport branch from
+// target and submits it as an MR at the original's priority. A failure is
+// both returned and recorded in the Backport's Error.
+func (e *Engineer) backport(mr *beads.Issue, fields *beads.MRFields, target string) (Backport, error) {
+	bp := Backport{Target: target, Branch: BackportBranch(target, mr.ID)}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Backporting %s onto %s...\n", mr.ID, target)
+	if err := e.CreateBackportBranch(bp.Branch, fields.MergeCommit, target); err != nil {
+		bp.Error = err.Error()
+		return bp, err
+	}
+
+	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s\nstate: %s\nbackport_of: %s",
+		bp.Branch, target, fields.SourceIssue, e.rig.Name, StateQueued, mr.ID)
+	issue, err := e.beads.Create(beads.CreateOptions{
+		Title:       backportTitle(mr, fields, target),
+		Type:        "merge-request",
+		Priority:    mr.Priority,
+		Description: description,
+		Actor:       e.actor(),
+		Ephemeral:   true,
+	})
+	if err != nil {
+		err = fmt.Errorf("creating merge request bead: %w", err)
+		bp.Error = err.Error()
+		return bp, err
+	}
+	bp.MRID = issue.ID
+
+	payload := events.MRPayload(e.rig.Name, mr.ID, fields.SourceIssue, bp.Branch, "")
+	payload["backport_mr"] = issue.ID
+	payload["target"] = target
+	_ = events.LogFeed(events.TypeMRBackported, e.actor(), payload)
+	return bp, nil
+}
+
+// recordBackports adds backport MRs to the original MR's backports field.
+func (e *Engineer) recordBackports(mr *beads.Issue, fields *beads.MRFields, mrIDs []string) error {
+	fields.Backports = strings.Join(append(SplitMRList(fields.Backports), mrIDs...), ",")
+	desc := beads.SetMRFields(mr, fields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording backports on %s: %w", mr.ID, err)
+	}
+	return nil
+}
+
+// backportTitle names a backport MR after what it backports and where,
+// e.g. "Backport: gt-abc → release/1.2".
+func backportTitle(mr *beads.Issue, fields *beads.MRFields, target string) string {
+	what := strings.TrimPrefix(mr.Title, "Merge: ")
+	if fields.SourceIssue != "" {
+		what = fields.SourceIssue
+	}
+	return fmt.Sprintf("Backport: %s → %s", what, target)
+}
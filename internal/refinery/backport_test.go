@@ -0,0 +1,33 @@
+package refinery
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBackportTitle(t *testing.T) {
+	mr := &beads.Issue{ID: "gp-mr-1", Title: "Merge: gp-abc"}
+	if got := backportTitle(mr, &beads.MRFields{SourceIssue: "gp-xyz"}, "release/1.2"); got != "Backport: gp-xyz → release/1.2" {
+		t.Errorf("backportTitle with source issue = %q", got)
+	}
+	if got := backportTitle(mr, &beads.MRFields{}, "release/1.2"); got != "Backport: gp-abc → release/1.2" {
+		t.Errorf("backportTitle without source issue = %q", got)
+	}
+}
+
+func TestBackportFieldsRoundTrip(t *testing.T) {
+	issue := &beads.Issue{Description: "branch: backport/release/1.2/gp-mr-1\ntarget: release/1.2\nbackport_of: gp-mr-1"}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.BackportOf != "gp-mr-1" {
+		t.Fatalf("ParseMRFields = %+v, want backport_of gp-mr-1", fields)
+	}
+
+	original := &beads.Issue{Description: "branch: polecat/nux/gp-abc\ntarget: main\nbackports: gp-mr-2"}
+	fields = beads.ParseMRFields(original)
+	fields.Backports = "gp-mr-2,gp-mr-3"
+	original.Description = beads.SetMRFields(original, fields)
+	if got := beads.ParseMRFields(original).Backports; got != "gp-mr-2,gp-mr-3" {
+		t.Errorf("backports after SetMRFields = %q", got)
+	}
+}
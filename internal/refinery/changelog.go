@@ -0,0 +1,209 @@
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangelogFile is the file the refinery appends merged MRs to.
+const ChangelogFile = "CHANGELOG.md"
+
+// changelogUnreleased is the section new entries are added under.
+const changelogUnreleased = "## Unreleased"
+
+// ChangelogEntry is one merged MR in a changelog.
+type ChangelogEntry struct {
+	MR          string `json:"mr"`
+	Title       string `json:"title"`
+	SourceIssue string `json:"source_issue,omitempty"`
+	Worker      string `json:"worker,omitempty"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Epic        string `json:"epic,omitempty"`
+	Type        string `json:"type,omitempty"` // source issue type (feature, bug, task, ...)
+	MergedAt    string `json:"merged_at,omitempty"`
+}
+
+// ChangelogGroup is the entries for one epic and issue type.
+type ChangelogGroup struct {
+	Epic      string           `json:"epic,omitempty"`
+	EpicTitle string           `json:"epic_title,omitempty"`
+	Type      string           `json:"type"`
+	Entries   []ChangelogEntry `json:"entries"`
+}
+
+// changelogTypeOrder ranks issue types within an epic; unknown types sort last.
+var changelogTypeOrder = map[string]int{"feature": 0, "bug": 1, "task": 2, "chore": 3}
+
+// changelogTypeHeadings are the section names for known issue types.
+var changelogTypeHeadings = map[string]string{
+	"feature": "Features",
+	"bug":     "Fixes",
+	"task":    "Tasks",
+	"chore":   "Chores",
+}
+
+// GroupChangelog groups entries by epic (entries without one last), then by
+// issue type. epicTitles maps epic IDs to titles for headings. Entries keep
+// their input order within a group.
+func GroupChangelog(entries []ChangelogEntry, epicTitles map[string]string) []ChangelogGroup {
+	index := make(map[[2]string]int)
+	var groups []ChangelogGroup
+	for _, e := range entries {
+		typ := e.Type
+		if typ == "" {
+			typ = "other"
+		}
+		key := [2]string{e.Epic, typ}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ChangelogGroup{Epic: e.Epic, EpicTitle: epicTitles[e.Epic], Type: typ})
+		}
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Epic != b.Epic {
+			if a.Epic == "" || b.Epic == "" {
+				return b.Epic == ""
+			}
+			return a.Epic < b.Epic
+		}
+		return typeRank(a.Type) < typeRank(b.Type)
+	})
+	return groups
+}
+
+func typeRank(typ string) int {
+	if rank, ok := changelogTypeOrder[typ]; ok {
+		return rank
+	}
+	return len(changelogTypeOrder)
+}
+
+// FormatChangelogMarkdown renders grouped entries under a "## heading".
+func FormatChangelogMarkdown(heading string, groups []ChangelogGroup) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n", heading)
+	if len(groups) == 0 {
+		sb.WriteString("\nNo merged changes.\n")
+		return sb.String()
+	}
+
+	epic := "\x00" // never a real epic, so the first group prints its heading
+	for _, g := range groups {
+		if g.Epic != epic {
+			epic = g.Epic
+			switch {
+			case epic == "":
+				sb.WriteString("\n### Other changes\n")
+			case g.EpicTitle != "":
+				fmt.Fprintf(&sb, "\n### %s (%s)\n", g.EpicTitle, epic)
+			default:
+				fmt.Fprintf(&sb, "\n### %s\n", epic)
+			}
+		}
+		fmt.Fprintf(&sb, "\n#### %s\n\n", changelogTypeHeading(g.Type))
+		for _, e := range g.Entries {
+			sb.WriteString(ChangelogLine(e) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func changelogTypeHeading(typ string) string {
+	if h, ok := changelogTypeHeadings[typ]; ok {
+		return h
+	}
+	return "Other"
+}
+
+// ChangelogLine formats one entry as a markdown list item, e.g.
+// "- Add retry backoff (gt-abc, greenplace/Nux, 1a2b3c4d)".
+func ChangelogLine(e ChangelogEntry) string {
+	var refs []string
+	for _, ref := range []string{e.SourceIssue, e.Worker} {
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	if commit := e.MergeCommit; commit != "" {
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		refs = append(refs, commit)
+	}
+	if len(refs) == 0 {
+		return "- " + e.Title
+	}
+	return fmt.Sprintf("- %s (%s)", e.Title, strings.Join(refs, ", "))
+}
+
+// AppendChangelogEntry adds an entry at the top of the "## Unreleased"
+// section of the changelog at path, creating the file or section if needed.
+func AppendChangelogEntry(path string, e ChangelogEntry) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	content := string(data)
+	if content == "" {
+		content = "# Changelog\n\n" + changelogUnreleased + "\n"
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	entry := ChangelogLine(e)
+	var out []string
+	if i := indexLine(lines, changelogUnreleased); i >= 0 {
+		// New entry goes first in the existing section
+		rest := i + 1
+		for rest < len(lines) && strings.TrimSpace(lines[rest]) == "" {
+			rest++
+		}
+		out = append(out, lines[:i+1]...)
+		out = append(out, "", entry)
+		if rest < len(lines) && !strings.HasPrefix(lines[rest], "- ") {
+			out = append(out, "")
+		}
+		out = append(out, lines[rest:]...)
+	} else {
+		// Add the section before the first release heading (or at the end)
+		k := len(lines)
+		for j, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				k = j
+				break
+			}
+		}
+		out = append(out, lines[:k]...)
+		if k > 0 && strings.TrimSpace(lines[k-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, changelogUnreleased, "", entry)
+		if k < len(lines) {
+			out = append(out, "")
+			out = append(out, lines[k:]...)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(out, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// indexLine returns the index of the first line equal to want (ignoring
+// surrounding whitespace), or -1.
+func indexLine(lines []string, want string) int {
+	for i, line := range lines {
+		if strings.TrimSpace(line) == want {
+			return i
+		}
+	}
+	return -1
+}
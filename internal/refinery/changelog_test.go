@@ -0,0 +1,88 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGroupChangelog(t *testing.T) {
+	entries := []ChangelogEntry{
+		{MR: "mr-1", Title: "Loose fix", Type: "bug"},
+		{MR: "mr-2", Title: "Retry backoff", Type: "task", Epic: "gt-epic"},
+		{MR: "mr-3", Title: "Login page", Type: "feature", Epic: "gt-epic"},
+		{MR: "mr-4", Title: "Another task", Type: "task", Epic: "gt-epic"},
+	}
+	groups := GroupChangelog(entries, map[string]string{"gt-epic": "Auth"})
+
+	var got []string
+	for _, g := range groups {
+		got = append(got, g.Epic+"/"+g.Type)
+	}
+	if want := "gt-epic/feature gt-epic/task /bug"; strings.Join(got, " ") != want {
+		t.Fatalf("groups = %v, want %s", got, want)
+	}
+	if len(groups[1].Entries) != 2 || groups[1].Entries[0].MR != "mr-2" {
+		t.Errorf("task group entries = %+v, want mr-2 then mr-4", groups[1].Entries)
+	}
+
+	md := FormatChangelogMarkdown("Unreleased", groups)
+	for _, want := range []string{"### Auth (gt-epic)", "#### Features", "### Other changes", "#### Fixes", "- Loose fix"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestChangelogLine(t *testing.T) {
+	e := ChangelogEntry{Title: "Add retry", SourceIssue: "gt-abc", Worker: "greenplace/Nux", MergeCommit: "1a2b3c4d5e6f"}
+	if got, want := ChangelogLine(e), "- Add retry (gt-abc, greenplace/Nux, 1a2b3c4d)"; got != want {
+		t.Errorf("ChangelogLine = %q, want %q", got, want)
+	}
+}
+
+func TestAppendChangelogEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name: "new file",
+			want: "# Changelog\n\n## Unreleased\n\n- New\n",
+		},
+		{
+			name:     "existing unreleased entries",
+			existing: "# Changelog\n\n## Unreleased\n\n- Old\n\n## v1.0\n\n- First\n",
+			want:     "# Changelog\n\n## Unreleased\n\n- New\n- Old\n\n## v1.0\n\n- First\n",
+		},
+		{
+			name:     "no unreleased section",
+			existing: "# Changelog\n\n## v1.0\n\n- First\n",
+			want:     "# Changelog\n\n## Unreleased\n\n- New\n\n## v1.0\n\n- First\n",
+		},
+		{
+			name:     "empty unreleased before release",
+			existing: "# Changelog\n\n## Unreleased\n\n## v1.0\n",
+			want:     "# Changelog\n\n## Unreleased\n\n- New\n\n## v1.0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ChangelogFile)
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := AppendChangelogEntry(path, ChangelogEntry{Title: "New"}); err != nil {
+				t.Fatalf("AppendChangelogEntry: %v", err)
+			}
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("changelog =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
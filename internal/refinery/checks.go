@@ -0,0 +1,114 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RigCheck is a check command the refinery runs on a rig's MRs.
+type RigCheck struct {
+	Name    string        `json:"name"` // CheckTests or CheckVerify
+	Command string        `json:"command"`
+	Timeout time.Duration `json:"timeout,omitempty"` // 0 = no limit
+	Runner  *CheckRunner  `json:"runner,omitempty"`  // nil = the host's shell
+}
+
+// LoadChecks returns the checks the refinery runs on a rig's MRs, in the
+// order it runs them: the merge queue's test_command ("tests", via gt mq
+// test) and, with transactional merges, the verify command ("verify", run
+// on the merged result before it lands). A verify command that is just the
+// test command is listed once. Each runs as merge_queue.checks says. A rig
+// without settings has no checks.
+func LoadChecks(rigPath string) ([]RigCheck, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+
+	var checks []RigCheck
+	testCommand := settings.MergeQueue.TestCommand
+	if testCommand != "" {
+		checks = append(checks, RigCheck{Name: CheckTests, Command: testCommand})
+	}
+	tx, err := NewTransaction(settings.MergeQueue.Transactional, testCommand)
+	if err != nil {
+		return nil, err
+	}
+	if cmd := tx.VerifyCommand(); cmd != "" && cmd != testCommand {
+		checks = append(checks, RigCheck{Name: CheckVerify, Command: cmd, Timeout: tx.timeout})
+	}
+	for i := range checks {
+		runner, err := NewCheckRunner(settings.MergeQueue.Checks[checks[i].Name])
+		if err != nil {
+			return nil, fmt.Errorf("merge_queue.checks.%s: %w", checks[i].Name, err)
+		}
+		checks[i].Runner = runner
+		checks[i].Timeout = runner.TimeoutOr(checks[i].Timeout)
+	}
+	return checks, nil
+}
+
+// CheckCommand returns the command to run c in dir with, retrying failures
+// as the refinery would given the rig's flake history.
+func (c RigCheck) CheckCommand(dir string, history *FlakeHistory) CheckCommand {
+	return CheckCommand{
+		Command:  c.Command,
+		Dir:      dir,
+		Attempts: 1 + history.Retries(c.Name),
+		Runner:   c.Runner,
+	}
+}
+
+// RefineryEnv returns the environment variables a rig's refinery session
+// runs with, and so the environment its checks see.
+func RefineryEnv(rigName, townRoot string) map[string]string {
+	env := config.AgentEnv(config.AgentEnvConfig{
+		Role:          "refinery",
+		Rig:           rigName,
+		TownRoot:      townRoot,
+		BeadsNoDaemon: true,
+	})
+	env["GT_REFINERY"] = "1"
+	return env
+}
+
+// workerEnv are variables identifying a worker's session, which the
+// refinery's lacks.
+var workerEnv = []string{"GT_POLECAT", "GT_CREW", "BEADS_AGENT_NAME"}
+
+// CheckEnv returns the current process environment with the refinery
+// session's variables in place of the caller's agent identity, for running
+// checks as the refinery would.
+func CheckEnv(rigName, townRoot string) []string {
+	refineryEnv := RefineryEnv(rigName, townRoot)
+	var env []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, overridden := refineryEnv[key]; overridden || slices.Contains(workerEnv, key) {
+			continue
+		}
+		env = append(env, kv)
+	}
+	keys := make([]string, 0, len(refineryEnv))
+	for k := range refineryEnv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+refineryEnv[k])
+	}
+	return env
+}
@@ -0,0 +1,102 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeCheckSettings(t *testing.T, rigPath, mergeQueue string) {
+	t.Helper()
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"rig-settings","version":1,"merge_queue":` + mergeQueue + `}`
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadChecks(t *testing.T) {
+	rigPath := t.TempDir()
+	if checks, err := LoadChecks(rigPath); err != nil || checks != nil {
+		t.Errorf("LoadChecks without settings = %v, %v; want none", checks, err)
+	}
+
+	writeCheckSettings(t, rigPath, `{"test_command":"go test ./...","transactional":{}}`)
+	checks, err := LoadChecks(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || checks[0].Name != CheckTests || checks[0].Command != "go test ./..." {
+		t.Errorf("verify defaulting to the test command: got %+v, want just tests", checks)
+	}
+
+	writeCheckSettings(t, rigPath, `{"test_command":"go test ./...","transactional":{"verify_command":"make e2e","timeout":"20m"}}`)
+	checks, err = LoadChecks(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RigCheck{
+		{Name: CheckTests, Command: "go test ./..."},
+		{Name: CheckVerify, Command: "make e2e", Timeout: 20 * time.Minute},
+	}
+	if !slices.Equal(checks, want) {
+		t.Errorf("LoadChecks = %+v, want %+v", checks, want)
+	}
+
+	writeCheckSettings(t, rigPath, `{"transactional":{"timeout":"soon"}}`)
+	if _, err := LoadChecks(rigPath); err == nil {
+		t.Error("LoadChecks with a bad timeout should fail")
+	}
+
+	// A runner's timeout replaces the transactional one
+	writeCheckSettings(t, rigPath, `{"test_command":"go test ./...","transactional":{"verify_command":"make e2e","timeout":"20m"},`+
+		`"checks":{"tests":{"runner":"docker:golang:1.23","memory":"4g"},"verify":{"runner":"nix","timeout":"5m"}}}`)
+	if checks, err = LoadChecks(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 || checks[0].Runner.String() != "docker:golang:1.23" || checks[0].Runner.Memory != "4g" || checks[0].Timeout != 0 {
+		t.Errorf("tests check = %+v", checks[0])
+	}
+	if len(checks) == 2 && (checks[1].Runner.String() != "nix" || checks[1].Timeout != 5*time.Minute) {
+		t.Errorf("verify check = %+v", checks[1])
+	}
+
+	writeCheckSettings(t, rigPath, `{"test_command":"go test ./...","checks":{"tests":{"runner":"podman"}}}`)
+	if _, err := LoadChecks(rigPath); err == nil || !strings.Contains(err.Error(), "merge_queue.checks.tests") {
+		t.Errorf("LoadChecks with a bad runner = %v", err)
+	}
+}
+
+func TestCheckEnv(t *testing.T) {
+	t.Setenv("GT_ROLE", "greenplace/polecats/toast")
+	t.Setenv("GT_POLECAT", "toast")
+	t.Setenv("BD_ACTOR", "greenplace/polecats/toast")
+	t.Setenv("CHECK_ENV_KEPT", "yes")
+
+	env := CheckEnv("greenplace", "/town")
+	for _, want := range []string{"GT_ROLE=greenplace/refinery", "BD_ACTOR=greenplace/refinery", "GT_REFINERY=1", "GT_ROOT=/town", "CHECK_ENV_KEPT=yes"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("CheckEnv missing %s", want)
+		}
+	}
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "GT_POLECAT=") || kv == "GT_ROLE=greenplace/polecats/toast" {
+			t.Errorf("CheckEnv kept the worker's %s", kv)
+		}
+	}
+
+	// Checks see it
+	run := CheckCommand{Command: `test "$GT_ROLE" = greenplace/refinery && test -z "$GT_POLECAT"`, Env: env}.Run(context.Background())
+	if run.Err != nil {
+		t.Errorf("check in CheckEnv failed: %v", run.Err)
+	}
+}
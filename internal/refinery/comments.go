@@ -0,0 +1,185 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MRComment is one comment in a merge request's discussion thread. An MR's
+// comments are stored on its bead's comments field, as a JSON list.
+type MRComment struct {
+	ID      int       `json:"id"`
+	Author  string    `json:"author"`
+	Role    string    `json:"role"` // ApproverRole of Author
+	At      time.Time `json:"at"`
+	Body    string    `json:"body"`
+	ReplyTo int       `json:"reply_to,omitempty"` // ID of the comment this answers
+
+	// Inline comments are anchored to a file, and a line of the MR's
+	// version of it (0 for the whole file)
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// ParseMRComments parses an MR's comments field, oldest first.
+func ParseMRComments(s string) ([]MRComment, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var comments []MRComment
+	if err := json.Unmarshal([]byte(s), &comments); err != nil {
+		return nil, fmt.Errorf("parsing comments: %w", err)
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].At.Before(comments[j].At)
+	})
+	return comments, nil
+}
+
+// AddMRComment appends comment c to the comments field s, numbering it
+// and stamping it with its author's role and the time. A reply with no
+// anchor of its own takes that of the comment it answers. It returns the
+// new field and the comment as added.
+func AddMRComment(s string, c MRComment, now time.Time) (string, MRComment, error) {
+	c.Body = strings.TrimSpace(c.Body)
+	if c.Body == "" {
+		return s, MRComment{}, fmt.Errorf("comment is empty")
+	}
+	c.File = path.Clean(filepath.ToSlash(c.File))
+	switch {
+	case c.File == ".":
+		c.File = ""
+		if c.Line != 0 {
+			return s, MRComment{}, fmt.Errorf("a line needs a file")
+		}
+	case path.IsAbs(c.File) || c.File == ".." || strings.HasPrefix(c.File, "../"):
+		return s, MRComment{}, fmt.Errorf("file %q must be relative to the repository root", c.File)
+	}
+	if c.Line < 0 {
+		return s, MRComment{}, fmt.Errorf("invalid line %d", c.Line)
+	}
+
+	comments, err := ParseMRComments(s)
+	if err != nil {
+		return s, MRComment{}, err
+	}
+	next := 1
+	var parent *MRComment
+	for i := range comments {
+		next = max(next, comments[i].ID+1)
+		if comments[i].ID == c.ReplyTo {
+			parent = &comments[i]
+		}
+	}
+	if c.ReplyTo != 0 {
+		if parent == nil {
+			return s, MRComment{}, fmt.Errorf("no comment #%d to reply to", c.ReplyTo)
+		}
+		if c.File == "" {
+			c.File, c.Line = parent.File, parent.Line
+		}
+	}
+
+	c.ID = next
+	c.Role = ApproverRole(c.Author)
+	c.At = now.UTC().Truncate(time.Second)
+	data, err := json.Marshal(append(comments, c))
+	if err != nil {
+		return s, MRComment{}, fmt.Errorf("encoding comments: %w", err)
+	}
+	return string(data), c, nil
+}
+
+// Location is where an inline comment is anchored: "path:line", or just
+// the path for a comment on a whole file. Empty for general comments.
+func (c MRComment) Location() string {
+	if c.Line == 0 {
+		return c.File
+	}
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// AnnotatePatch interleaves inline comments with a unified diff. A comment
+// on a line follows the diff line that shows that line of the MR's version
+// of its file; a comment on a whole file follows the file's header. format
+// renders each comment as lines. AnnotatePatch returns the annotated patch
+// and the inline comments the diff had no place for, oldest first.
+func AnnotatePatch(patch string, comments []MRComment, format func(MRComment) []string) (string, []MRComment) {
+	var inline []MRComment
+	for _, c := range comments {
+		if c.File != "" {
+			inline = append(inline, c)
+		}
+	}
+	placed := make(map[int]bool)
+	var out []string
+	emit := func(file string, line int) {
+		for _, c := range inline {
+			if !placed[c.ID] && c.File == file && c.Line == line {
+				placed[c.ID] = true
+				out = append(out, format(c)...)
+			}
+		}
+	}
+
+	file, line, inHunk := "", 0, false
+	for _, l := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		out = append(out, l)
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			file, inHunk = "", false
+		case !inHunk && strings.HasPrefix(l, "--- "):
+			file = patchPath(l[4:])
+		case !inHunk && strings.HasPrefix(l, "+++ "):
+			if p := patchPath(l[4:]); p != "" {
+				file = p
+			}
+			emit(file, 0)
+		case strings.HasPrefix(l, "@@ "):
+			inHunk, line = true, hunkStart(l)
+		case inHunk && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, " ")):
+			emit(file, line)
+			line++
+		}
+	}
+
+	var unplaced []MRComment
+	for _, c := range inline {
+		if !placed[c.ID] {
+			unplaced = append(unplaced, c)
+		}
+	}
+	return strings.Join(out, "\n"), unplaced
+}
+
+// patchPath returns the path in a diff's ---/+++ header, without its a/
+// or b/ prefix. Empty for /dev/null.
+func patchPath(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if len(s) > 2 && (s[:2] == "a/" || s[:2] == "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// hunkStart returns the first new-file line of the hunk headed h
+// ("@@ -12,5 +14,7 @@ ...").
+func hunkStart(h string) int {
+	for _, f := range strings.Fields(h) {
+		if strings.HasPrefix(f, "+") {
+			start, _, _ := strings.Cut(f[1:], ",")
+			n, _ := strconv.Atoi(start)
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,150 @@
+package refinery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestAddMRComment(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 500, time.UTC)
+	thread, first, err := AddMRComment("", MRComment{Author: "mayor", Body: "  Why retry here?\nNot in the client?  "}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || first.Role != "mayor" || first.Body != "Why retry here?\nNot in the client?" || !first.At.Equal(now.Truncate(time.Second)) {
+		t.Errorf("first comment = %+v", first)
+	}
+
+	thread, reply, err := AddMRComment(thread, MRComment{Author: "greenplace/polecats/Nux", Body: "The client has no backoff", ReplyTo: 1}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.ID != 2 || reply.ReplyTo != 1 || reply.Role != "polecat" {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	bad := []MRComment{
+		{Author: "mayor", Body: "   "},
+		{Author: "mayor", Body: "hm", ReplyTo: 7},
+		{Author: "mayor", Body: "hm", Line: 3},
+		{Author: "mayor", Body: "hm", File: "a.go", Line: -1},
+		{Author: "mayor", Body: "hm", File: "/etc/passwd"},
+		{Author: "mayor", Body: "hm", File: "../a.go"},
+	}
+	for _, c := range bad {
+		if _, _, err := AddMRComment(thread, c, now); err == nil {
+			t.Errorf("AddMRComment(%+v) should fail", c)
+		}
+	}
+
+	// The thread survives a trip through the MR bead's description
+	issue := &beads.Issue{Description: "branch: polecat/Nux/gp-1\ntarget: main\n\n## Summary\nRetry pushes"}
+	issue.Description = beads.SetMRFields(issue, &beads.MRFields{Branch: "polecat/Nux/gp-1", Target: "main", Comments: thread})
+	comments, err := ParseMRComments(beads.ParseMRFields(issue).Comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0] != first || comments[1] != reply {
+		t.Errorf("comments after round trip = %+v", comments)
+	}
+	if body := beads.MRBody(issue); body != "## Summary\nRetry pushes" {
+		t.Errorf("MRBody = %q", body)
+	}
+}
+
+func TestParseMRCommentsChronological(t *testing.T) {
+	comments, err := ParseMRComments(`[{"id":2,"author":"overseer","role":"human","at":"2026-10-15T12:05:00Z","body":"later"},` +
+		`{"id":1,"author":"mayor","role":"mayor","at":"2026-10-15T12:00:00Z","body":"earlier"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].ID != 1 || comments[1].ID != 2 {
+		t.Errorf("comments = %+v, want oldest first", comments)
+	}
+	if c, err := ParseMRComments(""); c != nil || err != nil {
+		t.Errorf("ParseMRComments(\"\") = %v, %v", c, err)
+	}
+	if _, err := ParseMRComments("not json"); err == nil {
+		t.Error("bad comments field should fail to parse")
+	}
+}
+
+func TestAddMRCommentInline(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	thread, c, err := AddMRComment("", MRComment{Author: "overseer", Body: "Cap retries", File: "./internal/retry.go", Line: 42}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.File != "internal/retry.go" || c.Location() != "internal/retry.go:42" || c.Role != "human" {
+		t.Errorf("inline comment = %+v", c)
+	}
+	_, reply, err := AddMRComment(thread, MRComment{Author: "greenplace/polecats/Nux", Body: "Done", ReplyTo: 1}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Location() != "internal/retry.go:42" {
+		t.Errorf("reply anchored at %q, want its parent's", reply.Location())
+	}
+	if loc := (MRComment{File: "go.mod"}).Location(); loc != "go.mod" {
+		t.Errorf("file comment Location = %q", loc)
+	}
+}
+
+func TestAnnotatePatch(t *testing.T) {
+	patch := `diff --git a/retry.go b/retry.go
+index 1111111..2222222 100644
+--- a/retry.go
++++ b/retry.go
+@@ -10,3 +10,4 @@ func retry() {
+ 	for {
+-		try()
++		if try() {
++			return
+ 	}
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+	comments := []MRComment{
+		{ID: 1, File: "retry.go", Line: 11, Body: "bound this"},
+		{ID: 2, Body: "general"},
+		{ID: 3, File: "retry.go", Body: "whole file"},
+		{ID: 4, File: "retry.go", Line: 12, Body: "return what?"},
+		{ID: 5, File: "old.go", Body: "why delete?"},
+		{ID: 6, File: "retry.go", Line: 99, Body: "not in the diff"},
+	}
+	got, unplaced := AnnotatePatch(patch, comments, func(c MRComment) []string {
+		return []string{"> " + c.Body}
+	})
+	want := `diff --git a/retry.go b/retry.go
+index 1111111..2222222 100644
+--- a/retry.go
++++ b/retry.go
+> whole file
+@@ -10,3 +10,4 @@ func retry() {
+ 	for {
+-		try()
++		if try() {
+> bound this
++			return
+> return what?
+ 	}
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+> why delete?
+@@ -1 +0,0 @@
+-package old`
+	if got != want {
+		t.Errorf("AnnotatePatch =\n%s\nwant\n%s", got, want)
+	}
+	if len(unplaced) != 1 || unplaced[0].ID != 6 {
+		t.Errorf("unplaced = %+v, want #6", unplaced)
+	}
+}
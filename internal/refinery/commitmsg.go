@@ -0,0 +1,283 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// ErrInvalidCommitPolicy indicates a malformed commit message convention.
+var ErrInvalidCommitPolicy = errors.New("invalid commit message policy")
+
+// DefaultCommitTemplate is the subject line format of a convention that
+// sets none.
+const DefaultCommitTemplate = "{summary} ({issue})"
+
+var (
+	commitVarRe  = regexp.MustCompile(`\{([a-z_]+)\}`)
+	commitTypeRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// commitVarPatterns maps each template variable to what it matches in a
+// subject line.
+var commitVarPatterns = map[string]string{
+	"type":    `[a-z][a-z0-9-]*`,
+	"issue":   `[a-z0-9]+-[a-z0-9]+(?:\.[0-9]+)*`,
+	"summary": `\S.*?`,
+}
+
+// issueCommitTypes maps beads issue types to the commit types that suit
+// them, most conventional first.
+var issueCommitTypes = map[string][]string{
+	"bug":     {"fix"},
+	"feature": {"feat", "feature"},
+	"task":    {"chore", "task"},
+	"chore":   {"chore"},
+}
+
+// CommitPolicy is a rig's merge_queue.commit_messages convention: the
+// format commit subject lines follow. Subjects are checked against the
+// template with each {variable} as a named capture, and composed by
+// filling it in. A nil *CommitPolicy accepts any message.
+type CommitPolicy struct {
+	template   string
+	types      []string
+	maxSubject int
+	vars       map[string]bool
+	re         *regexp.Regexp // The template, {type} limited to types
+	loose      *regexp.Regexp // The template, any {type}
+}
+
+// NewCommitPolicy builds a CommitPolicy from config. Returns nil (any
+// message) if cfg is nil.
+func NewCommitPolicy(cfg *config.CommitMessageConfig) (*CommitPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.MaxSubjectLength < 0 {
+		return nil, fmt.Errorf("%w: max_subject_length can't be negative", ErrInvalidCommitPolicy)
+	}
+	p := &CommitPolicy{template: cfg.Template, maxSubject: cfg.MaxSubjectLength, vars: make(map[string]bool)}
+	if p.template == "" {
+		p.template = DefaultCommitTemplate
+	}
+	if strings.Contains(p.template, "\n") {
+		return nil, fmt.Errorf("%w: template is a single subject line", ErrInvalidCommitPolicy)
+	}
+	for _, t := range cfg.Types {
+		if !commitTypeRe.MatchString(t) {
+			return nil, fmt.Errorf("%w: bad type %q (lowercase letters, digits and -)", ErrInvalidCommitPolicy, t)
+		}
+		p.types = append(p.types, t)
+	}
+
+	for _, m := range commitVarRe.FindAllStringSubmatch(p.template, -1) {
+		name := m[1]
+		if _, ok := commitVarPatterns[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown variable {%s} in template %q", ErrInvalidCommitPolicy, name, p.template)
+		}
+		if p.vars[name] {
+			return nil, fmt.Errorf("%w: {%s} appears twice in template %q", ErrInvalidCommitPolicy, name, p.template)
+		}
+		p.vars[name] = true
+	}
+	if !p.vars["summary"] {
+		return nil, fmt.Errorf("%w: template %q has no {summary}", ErrInvalidCommitPolicy, p.template)
+	}
+	if len(p.types) > 0 && !p.vars["type"] {
+		return nil, fmt.Errorf("%w: types are set but template %q has no {type}", ErrInvalidCommitPolicy, p.template)
+	}
+
+	typeExpr := commitVarPatterns["type"]
+	if len(p.types) > 0 {
+		quoted := make([]string, len(p.types))
+		for i, t := range p.types {
+			quoted[i] = regexp.QuoteMeta(t)
+		}
+		typeExpr = strings.Join(quoted, "|")
+	}
+	var err error
+	if p.re, err = compileCommitTemplate(p.template, typeExpr); err != nil {
+		return nil, err
+	}
+	if p.loose, err = compileCommitTemplate(p.template, commitVarPatterns["type"]); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// compileCommitTemplate turns a template into an anchored pattern, with
+// {type} matching typeExpr.
+func compileCommitTemplate(template, typeExpr string) (*regexp.Regexp, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, m := range commitVarRe.FindAllStringSubmatchIndex(template, -1) {
+		name := template[m[2]:m[3]]
+		expr := commitVarPatterns[name]
+		if name == "type" {
+			expr = typeExpr
+		}
+		pattern.WriteString(regexp.QuoteMeta(template[last:m[0]]))
+		pattern.WriteString("(?P<" + name + ">" + expr + ")")
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: template %q: %v", ErrInvalidCommitPolicy, template, err)
+	}
+	return re, nil
+}
+
+// LoadCommitPolicy reads the commit message convention from a rig's
+// settings/config.json. A missing settings file or commit_messages section
+// yields nil (any message).
+func LoadCommitPolicy(rigPath string) (*CommitPolicy, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewCommitPolicy(settings.MergeQueue.CommitMessages)
+}
+
+// Template returns the subject line format, or "" for a nil policy.
+func (p *CommitPolicy) Template() string {
+	if p == nil {
+		return ""
+	}
+	return p.template
+}
+
+// Types returns the allowed {type} prefixes (empty: any).
+func (p *CommitPolicy) Types() []string {
+	if p == nil {
+		return nil
+	}
+	return p.types
+}
+
+// Uses reports whether the template contains {name}.
+func (p *CommitPolicy) Uses(name string) bool {
+	return p != nil && p.vars[name]
+}
+
+// Check returns why a commit subject line breaks the convention, or nil.
+func (p *CommitPolicy) Check(subject string) error {
+	if p == nil {
+		return nil
+	}
+	if n := utf8.RuneCountInString(subject); p.maxSubject > 0 && n > p.maxSubject {
+		return fmt.Errorf("subject is %d characters, over the limit of %d", n, p.maxSubject)
+	}
+	if p.re.MatchString(subject) {
+		return nil
+	}
+	if m := p.loose.FindStringSubmatch(subject); m != nil {
+		return fmt.Errorf("type %q is not one of %s", m[p.loose.SubexpIndex("type")], strings.Join(p.types, ", "))
+	}
+	return fmt.Errorf("doesn't follow %q", p.template)
+}
+
+// TypeFor returns the allowed commit type that suits a beads issue type
+// (e.g., "fix" for a bug), or "" if none does.
+func (p *CommitPolicy) TypeFor(issueType string) string {
+	for _, t := range append(issueCommitTypes[issueType], issueType) {
+		if t != "" && commitTypeRe.MatchString(t) && (len(p.Types()) == 0 || slices.Contains(p.types, t)) {
+			return t
+		}
+	}
+	return ""
+}
+
+// Compose writes a subject line following the convention and checks it.
+// A nil policy writes "summary (issue)", or just the summary without an
+// issue.
+func (p *CommitPolicy) Compose(commitType, issue, summary string) (string, error) {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("commit message needs a summary")
+	}
+	template := DefaultCommitTemplate
+	if p != nil {
+		template = p.template
+	} else if issue == "" {
+		return summary, nil
+	}
+	if p.Uses("type") && commitType == "" {
+		if len(p.types) > 0 {
+			return "", fmt.Errorf("commit message needs a type: one of %s", strings.Join(p.types, ", "))
+		}
+		return "", fmt.Errorf("commit message needs a type")
+	}
+	if (p == nil || p.Uses("issue")) && issue == "" {
+		return "", fmt.Errorf("commit message needs an issue ID")
+	}
+
+	subject := commitVarRe.ReplaceAllStringFunc(template, func(v string) string {
+		switch v {
+		case "{type}":
+			return commitType
+		case "{issue}":
+			return issue
+		default:
+			return summary
+		}
+	})
+	if err := p.Check(subject); err != nil {
+		return "", fmt.Errorf("%q: %w", subject, err)
+	}
+	return subject, nil
+}
+
+// CommitViolation is a commit whose subject breaks the convention.
+type CommitViolation struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Reason  string `json:"reason"`
+}
+
+// CheckCommits checks the subjects of the non-merge commits in base..head.
+func (p *CommitPolicy) CheckCommits(g *git.Git, base, head string) ([]CommitViolation, error) {
+	if p == nil {
+		return nil, nil
+	}
+	commits, err := g.CommitSummaries(base, head)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits in %s..%s: %w", base, head, err)
+	}
+	var violations []CommitViolation
+	for _, c := range commits {
+		if err := p.Check(c.Subject); err != nil {
+			violations = append(violations, CommitViolation{SHA: c.SHA, Subject: c.Subject, Reason: err.Error()})
+		}
+	}
+	return violations, nil
+}
+
+// FormatCommitViolations describes violations of p for people.
+func (p *CommitPolicy) FormatCommitViolations(violations []CommitViolation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d commit message(s) don't follow the rig's convention %q", len(violations), p.Template())
+	if len(p.Types()) > 0 {
+		fmt.Fprintf(&b, " (types: %s)", strings.Join(p.types, ", "))
+	}
+	b.WriteString(":")
+	for _, v := range violations {
+		fmt.Fprintf(&b, "\n  %s %s: %s", shortCommit(v.SHA), v.Subject, v.Reason)
+	}
+	return b.String()
+}
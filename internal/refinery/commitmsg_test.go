@@ -0,0 +1,176 @@
+package refinery
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestNewCommitPolicy(t *testing.T) {
+	if p, err := NewCommitPolicy(nil); err != nil || p != nil {
+		t.Errorf("NewCommitPolicy(nil) = %v, %v; want nil", p, err)
+	}
+	p, err := NewCommitPolicy(&config.CommitMessageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Template() != DefaultCommitTemplate {
+		t.Errorf("Template() = %q, want the default", p.Template())
+	}
+
+	bad := []*config.CommitMessageConfig{
+		{Template: "{type}: {title}"},
+		{Template: "{type}: {issue}"},
+		{Template: "{summary} {summary}"},
+		{Template: "{summary}\n\n{issue}"},
+		{Template: "{summary}", Types: []string{"fix"}},
+		{Template: "{type}: {summary}", Types: []string{"Fix"}},
+		{MaxSubjectLength: -1},
+	}
+	for _, cfg := range bad {
+		if _, err := NewCommitPolicy(cfg); !errors.Is(err, ErrInvalidCommitPolicy) {
+			t.Errorf("NewCommitPolicy(%+v) = %v, want ErrInvalidCommitPolicy", cfg, err)
+		}
+	}
+}
+
+func TestCommitPolicyCheck(t *testing.T) {
+	p, err := NewCommitPolicy(&config.CommitMessageConfig{
+		Template:         "{type}({issue}): {summary}",
+		Types:            []string{"feat", "fix", "docs"},
+		MaxSubjectLength: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		subject string
+		reason  string // "" if it follows the convention
+	}{
+		{"fix(gt-abc): handle expired tokens", ""},
+		{"feat(gt-abc.2): add gt commit", ""},
+		{"chore(gt-abc): bump deps", `type "chore" is not one of feat, fix, docs`},
+		{"fix: handle expired tokens", "doesn't follow"},
+		{"fix(gt-abc):", "doesn't follow"},
+		{"Handle expired tokens", "doesn't follow"},
+		{"fix(gt-abc): " + strings.Repeat("x", 40), "over the limit of 50"},
+	}
+	for _, tt := range tests {
+		err := p.Check(tt.subject)
+		switch {
+		case tt.reason == "" && err != nil:
+			t.Errorf("Check(%q) = %v, want nil", tt.subject, err)
+		case tt.reason != "" && (err == nil || !strings.Contains(err.Error(), tt.reason)):
+			t.Errorf("Check(%q) = %v, want %q", tt.subject, err, tt.reason)
+		}
+	}
+
+	var none *CommitPolicy
+	if err := none.Check("anything goes"); err != nil {
+		t.Errorf("nil policy Check = %v, want nil", err)
+	}
+}
+
+func TestCommitPolicyCompose(t *testing.T) {
+	p, err := NewCommitPolicy(&config.CommitMessageConfig{
+		Template: "{type}: {summary} [{issue}]",
+		Types:    []string{"feat", "fix"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := p.Compose("fix", "gt-abc", " handle expired tokens "); err != nil || got != "fix: handle expired tokens [gt-abc]" {
+		t.Errorf("Compose = %q, %v", got, err)
+	}
+	for _, args := range [][3]string{
+		{"", "gt-abc", "summary"},      // Needs a type
+		{"chore", "gt-abc", "summary"}, // Not an allowed type
+		{"fix", "", "summary"},         // Needs an issue
+		{"fix", "gt-abc", " "},         // Needs a summary
+	} {
+		if got, err := p.Compose(args[0], args[1], args[2]); err == nil {
+			t.Errorf("Compose%q = %q, want an error", args, got)
+		}
+	}
+
+	var none *CommitPolicy
+	if got, _ := none.Compose("", "gt-abc", "Fix login"); got != "Fix login (gt-abc)" {
+		t.Errorf("nil policy Compose = %q", got)
+	}
+	if got, _ := none.Compose("", "", "Fix login"); got != "Fix login" {
+		t.Errorf("nil policy Compose without issue = %q", got)
+	}
+}
+
+func TestCommitPolicyTypeFor(t *testing.T) {
+	p, _ := NewCommitPolicy(&config.CommitMessageConfig{Template: "{type}: {summary}", Types: []string{"feat", "fix", "task"}})
+	for issueType, want := range map[string]string{"bug": "fix", "feature": "feat", "task": "task", "epic": ""} {
+		if got := p.TypeFor(issueType); got != want {
+			t.Errorf("TypeFor(%q) = %q, want %q", issueType, got, want)
+		}
+	}
+	var none *CommitPolicy
+	if got := none.TypeFor("task"); got != "chore" {
+		t.Errorf("nil policy TypeFor(task) = %q, want chore", got)
+	}
+}
+
+func TestCommitPolicyCheckCommits(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(name, message string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", name)
+		run("commit", "-m", message)
+	}
+	run("init", "-b", "main")
+	commit("a", "Initial commit")
+	run("checkout", "-b", "work")
+	commit("b", "Add b (gt-abc)")
+	commit("c", "wip")
+
+	p, _ := NewCommitPolicy(&config.CommitMessageConfig{})
+	violations, err := p.CheckCommits(git.NewGit(dir), "main", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Subject != "wip" {
+		t.Fatalf("CheckCommits = %+v, want just wip", violations)
+	}
+	if msg := p.FormatCommitViolations(violations); !strings.Contains(msg, "wip: doesn't follow") {
+		t.Errorf("FormatCommitViolations = %q", msg)
+	}
+}
+
+func TestLoadCommitPolicy(t *testing.T) {
+	rigPath := t.TempDir()
+	if p, err := LoadCommitPolicy(rigPath); err != nil || p != nil {
+		t.Errorf("LoadCommitPolicy without settings = %v, %v; want nil", p, err)
+	}
+	writeCheckSettings(t, rigPath, `{"commit_messages":{"template":"{type}: {summary}","types":["fix"]}}`)
+	p, err := LoadCommitPolicy(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Template() != "{type}: {summary}" || !p.Uses("type") || p.Uses("issue") {
+		t.Errorf("LoadCommitPolicy = %+v", p)
+	}
+}
@@ -0,0 +1,49 @@
+package refinery
+
+import "sort"
+
+// MRFileSet is the set of files a queued MR modifies relative to its target.
+type MRFileSet struct {
+	ID     string
+	Target string
+	Files  []string
+}
+
+// ConflictPair is two queued MRs that modify the same files and are likely
+// to conflict when the second of them is merged.
+type ConflictPair struct {
+	A     string   `json:"a"`
+	B     string   `json:"b"`
+	Files []string `json:"files"`
+}
+
+// FindOverlaps pairwise-compares MR file sets and returns every pair that
+// targets the same branch and modifies at least one common file. Pairs
+// follow the order of sets; shared files are sorted.
+func FindOverlaps(sets []MRFileSet) []ConflictPair {
+	var pairs []ConflictPair
+	for i := 0; i < len(sets); i++ {
+		files := make(map[string]bool, len(sets[i].Files))
+		for _, f := range sets[i].Files {
+			files[f] = true
+		}
+		for j := i + 1; j < len(sets); j++ {
+			if sets[i].Target != sets[j].Target {
+				continue
+			}
+			var shared []string
+			seen := make(map[string]bool)
+			for _, f := range sets[j].Files {
+				if files[f] && !seen[f] {
+					shared = append(shared, f)
+					seen[f] = true
+				}
+			}
+			if len(shared) > 0 {
+				sort.Strings(shared)
+				pairs = append(pairs, ConflictPair{A: sets[i].ID, B: sets[j].ID, Files: shared})
+			}
+		}
+	}
+	return pairs
+}
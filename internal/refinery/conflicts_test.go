@@ -0,0 +1,21 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	sets := []MRFileSet{
+		{ID: "mr-1", Target: "main", Files: []string{"a.go", "b.go", "c.go"}},
+		{ID: "mr-2", Target: "main", Files: []string{"c.go", "a.go"}},
+		{ID: "mr-3", Target: "main", Files: []string{"d.go"}},
+		{ID: "mr-4", Target: "integration/gt-epic", Files: []string{"a.go"}},
+	}
+
+	got := FindOverlaps(sets)
+	want := []ConflictPair{{A: "mr-1", B: "mr-2", Files: []string{"a.go", "c.go"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindOverlaps = %+v, want %+v", got, want)
+	}
+}
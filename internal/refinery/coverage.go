@@ -0,0 +1,440 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// coverageCacheSize is how many target commits' coverage is remembered.
+const coverageCacheSize = 50
+
+// CoverageGate blocks merges that drop test coverage: it measures the
+// coverage of each MR's merged result and of the target it was merged
+// onto, from the rig's merge_queue.coverage settings. A nil *CoverageGate
+// lets everything through.
+type CoverageGate struct {
+	command string
+	file    string
+	maxDrop float64
+	cache   string // under .runtime/
+}
+
+// NewCoverageGate builds a rig's coverage gate from config. Returns nil
+// if cfg is nil.
+func NewCoverageGate(rigPath string, cfg *config.MergeCoverageConfig) (*CoverageGate, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if strings.TrimSpace(cfg.Command) == "" || cfg.File == "" {
+		return nil, fmt.Errorf("coverage gate needs a command and the file it writes")
+	}
+	if filepath.IsAbs(cfg.File) || !filepath.IsLocal(cfg.File) {
+		return nil, fmt.Errorf("invalid coverage file %q: must be relative to the checkout", cfg.File)
+	}
+	if cfg.MaxDrop < 0 {
+		return nil, fmt.Errorf("invalid coverage max_drop %v", cfg.MaxDrop)
+	}
+	return &CoverageGate{
+		command: cfg.Command,
+		file:    cfg.File,
+		maxDrop: cfg.MaxDrop,
+		cache:   filepath.Join(rigPath, ".runtime", "coverage.json"),
+	}, nil
+}
+
+// LoadCoverageGate reads a rig's coverage gate from its
+// settings/config.json. A rig without one yields nil.
+func LoadCoverageGate(rigPath string) (*CoverageGate, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	g, err := NewCoverageGate(rigPath, settings.MergeQueue.Coverage)
+	if err != nil {
+		return nil, fmt.Errorf("merge_queue.coverage: %w", err)
+	}
+	return g, nil
+}
+
+// Command returns the shell command that writes the coverage report.
+func (g *CoverageGate) Command() string {
+	return g.command
+}
+
+// Report returns the coverage report's path in a checkout.
+func (g *CoverageGate) Report(dir string) string {
+	return filepath.Join(dir, g.file)
+}
+
+// Compare returns the delta from the target's coverage to an MR's.
+func (g *CoverageGate) Compare(target, candidate float64) *CoverageDelta {
+	return &CoverageDelta{Target: target, Candidate: candidate, MaxDrop: g.maxDrop}
+}
+
+// coverageRecord is a target commit's measured coverage.
+type coverageRecord struct {
+	Commit  string  `json:"commit"`
+	Percent float64 `json:"percent"`
+}
+
+// Cached returns the coverage measured earlier for a target commit.
+func (g *CoverageGate) Cached(commit string) (float64, bool) {
+	for _, r := range g.load() {
+		if r.Commit == commit {
+			return r.Percent, true
+		}
+	}
+	return 0, false
+}
+
+// Remember caches a target commit's coverage, so MRs onto it don't
+// measure it again, keeping the most recent commits'.
+func (g *CoverageGate) Remember(commit string, percent float64) error {
+	records := append(g.load(), coverageRecord{Commit: commit, Percent: percent})
+	if len(records) > coverageCacheSize {
+		records = records[len(records)-coverageCacheSize:]
+	}
+	if err := os.MkdirAll(filepath.Dir(g.cache), 0755); err != nil {
+		return fmt.Errorf("saving target coverage: %w", err)
+	}
+	if err := util.AtomicWriteJSON(g.cache, records); err != nil {
+		return fmt.Errorf("saving target coverage: %w", err)
+	}
+	return nil
+}
+
+// load reads the cached target coverage; an unreadable cache is empty.
+func (g *CoverageGate) load() []coverageRecord {
+	data, err := os.ReadFile(g.cache)
+	if err != nil {
+		return nil
+	}
+	var records []coverageRecord
+	if json.Unmarshal(data, &records) != nil {
+		return nil
+	}
+	return records
+}
+
+// CoverageDelta is how an MR changes its target's test coverage.
+type CoverageDelta struct {
+	Target    float64 `json:"target"`             // Percent of the target covered
+	Candidate float64 `json:"candidate"`          // Percent of the merged result covered
+	MaxDrop   float64 `json:"max_drop,omitempty"` // Percentage points it may drop
+}
+
+// Delta returns the change in coverage, in percentage points.
+func (d *CoverageDelta) Delta() float64 {
+	return d.Candidate - d.Target
+}
+
+// Blocked reports whether coverage dropped by more than the gate allows.
+func (d *CoverageDelta) Blocked() bool {
+	// Round to the precision reported, so an unchanged 66.67% isn't a drop
+	return d != nil && roundCoverage(d.Target-d.Candidate) > d.MaxDrop
+}
+
+// String summarizes the delta, as recorded on the MR ("81.62% -> 81.20%
+// (-0.42)").
+func (d *CoverageDelta) String() string {
+	return fmt.Sprintf("%.2f%% -> %.2f%% (%+.2f)", d.Target, d.Candidate, roundCoverage(d.Delta()))
+}
+
+func roundCoverage(p float64) float64 {
+	return math.Round(p*100) / 100
+}
+
+// ParseCoverageDelta reads a delta recorded by String, or nil if s isn't
+// one.
+func ParseCoverageDelta(s string) *CoverageDelta {
+	var d CoverageDelta
+	if _, err := fmt.Sscanf(s, "%f%% -> %f%%", &d.Target, &d.Candidate); err != nil {
+		return nil
+	}
+	return &d
+}
+
+// ParseCoverageReport reads the percent of code a coverage report covers.
+// It understands Go cover profiles, lcov tracefiles, Cobertura XML and
+// bare percentages ("81.2%").
+func ParseCoverageReport(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading coverage report: %w", err)
+	}
+	trimmed := bytes.TrimSpace(data)
+	var percent float64
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		percent, err = parseGoCoverProfile(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		percent, err = parseCoberturaReport(trimmed)
+	case bytes.Contains(trimmed, []byte("\nLF:")) || bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		percent, err = parseLcovReport(trimmed)
+	default:
+		percent, err = strconv.ParseFloat(strings.TrimSuffix(string(trimmed), "%"), 64)
+		if err != nil {
+			err = errors.New("unrecognized format")
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("parsing coverage report %s: %w", filepath.Base(path), err)
+	}
+	return percent, nil
+}
+
+// parseGoCoverProfile totals a Go cover profile's statements. Blocks
+// profiled by several packages' tests count once, covered if any covered
+// them.
+func parseGoCoverProfile(data []byte) (float64, error) {
+	type block struct {
+		stmts   int
+		covered bool
+	}
+	blocks := make(map[string]*block)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // mode: line
+	for scanner.Scan() {
+		// file.go:12.34,15.2 3 1
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			return 0, fmt.Errorf("malformed line %q", scanner.Text())
+		}
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{stmts: stmts}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	var total, covered int
+	for _, b := range blocks {
+		total += b.stmts
+		if b.covered {
+			covered += b.stmts
+		}
+	}
+	return percentOf(covered, total), scanner.Err()
+}
+
+// parseLcovReport totals an lcov tracefile's lines found (LF) and hit (LH).
+func parseLcovReport(data []byte) (float64, error) {
+	var found, hit int
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || (key != "LF" && key != "LH") {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("malformed line %q", line)
+		}
+		if key == "LF" {
+			found += n
+		} else {
+			hit += n
+		}
+	}
+	return percentOf(hit, found), nil
+}
+
+// parseCoberturaReport reads a Cobertura report's overall line rate.
+func parseCoberturaReport(data []byte) (float64, error) {
+	var report struct {
+		XMLName  xml.Name `xml:"coverage"`
+		LineRate string   `xml:"line-rate,attr"`
+	}
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return 0, err
+	}
+	rate, err := strconv.ParseFloat(report.LineRate, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid line-rate %q", report.LineRate)
+	}
+	return rate * 100, nil
+}
+
+// percentOf returns n as a percent of total; nothing to cover is fully
+// covered.
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// checkCoverage runs the rig's coverage gate on the merged result checked
+// out in dir, against base, the target commit it was merged onto, whose
+// coverage is measured in a checkout of its own once per commit. A rig
+// without a gate passes.
+func (e *Engineer) checkCoverage(ctx context.Context, mrID, dir, base string) ProcessResult {
+	gate, err := LoadCoverageGate(e.rig.Path)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	if gate == nil {
+		return ProcessResult{Success: true}
+	}
+	runner, err := LoadCheckRunner(e.rig.Path, CheckCoverage)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Measuring coverage: %s\n", gate.Command())
+	candidate, run, err := measureCoverage(ctx, gate, runner, dir)
+	if ctx.Err() != nil {
+		return ProcessResult{Error: "coverage run canceled"}
+	}
+	artifacts := e.saveArtifacts(mrID, CheckCoverage, dir, run)
+	if err != nil {
+		reason := fmt.Sprintf("coverage: %v", err)
+		if tail := lastLines(run.Output, verifyOutputLines); tail != "" {
+			reason += "\n" + tail
+		}
+		return ProcessResult{TestsFailed: true, Error: reason, Artifacts: artifacts}
+	}
+
+	target, ok := gate.Cached(base)
+	if !ok {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Measuring coverage of target %s...\n", shortCommit(base))
+		if target, err = e.measureTargetCoverage(ctx, gate, runner, base); err != nil {
+			// Nothing to compare to, and the MR isn't to blame
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: skipping coverage gate: target %s: %v\n", shortCommit(base), err)
+			return ProcessResult{Success: true, Artifacts: artifacts}
+		}
+		if err := gate.Remember(base, target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+		}
+	}
+
+	delta := gate.Compare(target, candidate)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Coverage: %s\n", delta)
+	if delta.Blocked() {
+		return ProcessResult{
+			TestsFailed: true,
+			Error:       fmt.Sprintf("coverage dropped more than %.2f points: %s", delta.MaxDrop, delta),
+			Coverage:    delta,
+			Artifacts:   artifacts,
+		}
+	}
+	return ProcessResult{Success: true, Coverage: delta, Artifacts: artifacts}
+}
+
+// checkMerged runs the gates on a merged result checked out in dir, onto
+// base: the rig's linters, then its coverage gate.
+func (e *Engineer) checkMerged(ctx context.Context, mrID, dir, base string) ProcessResult {
+	lint := e.runLinters(ctx, mrID, dir)
+	if !lint.Success {
+		return lint
+	}
+	result := e.checkCoverage(ctx, mrID, dir, base)
+	result.Lint = lint.Lint
+	if result.Artifacts == "" {
+		result.Artifacts = lint.Artifacts
+	}
+	return result
+}
+
+// checkMergedCommit is checkMerged for a merge commit that isn't checked
+// out: it's checked out only if the rig has gates.
+func (e *Engineer) checkMergedCommit(ctx context.Context, mrID, commit, base string) ProcessResult {
+	gate, err := LoadCoverageGate(e.rig.Path)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	linters, err := LoadLinters(e.rig.Path)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	if gate == nil && len(linters) == 0 {
+		return ProcessResult{Success: true}
+	}
+	path, remove, err := e.checkoutAt(commit)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	defer remove()
+	return e.checkMerged(ctx, mrID, path, base)
+}
+
+// measureTargetCoverage measures a target commit's coverage, in a
+// throwaway checkout of it.
+func (e *Engineer) measureTargetCoverage(ctx context.Context, gate *CoverageGate, runner *CheckRunner, commit string) (float64, error) {
+	path, remove, err := e.checkoutAt(commit)
+	if err != nil {
+		return 0, err
+	}
+	defer remove()
+	percent, _, err := measureCoverage(ctx, gate, runner, path)
+	return percent, err
+}
+
+// checkoutAt checks commit out in a throwaway worktree, of the rig's
+// mirror if it has one, otherwise of the refinery's clone.
+func (e *Engineer) checkoutAt(commit string) (string, func(), error) {
+	if e.mirror != nil {
+		path, err := e.mirror.AddWorktree(commit)
+		if err != nil {
+			return "", nil, err
+		}
+		return path, func() { _ = e.mirror.RemoveWorktree(path) }, nil
+	}
+	tmp, err := os.MkdirTemp("", mergeWorktreePrefix)
+	if err != nil {
+		return "", nil, fmt.Errorf("creating worktree dir: %w", err)
+	}
+	path := filepath.Join(tmp, "wt")
+	if err := e.git.WorktreeAddDetached(path, commit); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("checking out %s: %w", shortCommit(commit), err)
+	}
+	return path, func() {
+		_ = e.git.WorktreeRemove(path, true)
+		_ = os.RemoveAll(tmp)
+		_ = e.git.WorktreePrune()
+	}, nil
+}
+
+// measureCoverage runs the gate's command in dir and reads the report it
+// writes.
+func measureCoverage(ctx context.Context, gate *CoverageGate, runner *CheckRunner, dir string) (float64, CheckRun, error) {
+	timeout := runner.TimeoutOr(0)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	run := CheckCommand{Command: gate.Command(), Dir: dir, Attempts: 1, Runner: runner}.Run(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return 0, run, fmt.Errorf("timed out after %v", timeout)
+	}
+	if run.Err != nil {
+		return 0, run, fmt.Errorf("%s failed: %v", gate.Command(), run.Err)
+	}
+	percent, err := ParseCoverageReport(gate.Report(dir))
+	return percent, run, err
+}
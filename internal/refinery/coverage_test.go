@@ -0,0 +1,195 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseCoverageReport(t *testing.T) {
+	tests := []struct {
+		name, report string
+		want         float64
+	}{
+		{"go", `mode: set
+example.com/a/a.go:3.14,5.2 2 1
+example.com/a/a.go:7.14,9.2 1 0
+example.com/a/b.go:3.14,5.2 1 0
+`, 50},
+		// A block profiled by two packages' tests counts once
+		{"go repeated block", `mode: atomic
+example.com/a/a.go:3.14,5.2 3 0
+example.com/a/a.go:3.14,5.2 3 4
+example.com/a/a.go:7.14,9.2 1 0
+`, 75},
+		{"lcov", `TN:
+SF:src/a.js
+LF:10
+LH:9
+end_of_record
+SF:src/b.js
+LF:10
+LH:6
+end_of_record
+`, 75},
+		{"cobertura", `<?xml version="1.0" ?>
+<coverage line-rate="0.8125" branch-rate="0.5" version="7.4">
+  <packages/>
+</coverage>
+`, 81.25},
+		{"percentage", "66.5%\n", 66.5},
+		{"empty go profile", "mode: set\n", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "coverage")
+			if err := os.WriteFile(path, []byte(tt.report), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseCoverageReport(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCoverageReport = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "coverage")
+	if err := os.WriteFile(path, []byte("PASS\nok  example.com/a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseCoverageReport(path); err == nil {
+		t.Error("ParseCoverageReport accepted test output")
+	}
+	if _, err := ParseCoverageReport(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ParseCoverageReport accepted a missing report")
+	}
+}
+
+func TestCoverageDelta(t *testing.T) {
+	d := &CoverageDelta{Target: 81.62, Candidate: 81.2, MaxDrop: 0.5}
+	if d.Blocked() {
+		t.Error("a 0.42 point drop blocked under max_drop 0.5")
+	}
+	if got := d.String(); got != "81.62% -> 81.20% (-0.42)" {
+		t.Errorf("String = %q", got)
+	}
+	parsed := ParseCoverageDelta(d.String())
+	if parsed == nil || parsed.Target != 81.62 || parsed.Candidate != 81.2 {
+		t.Errorf("ParseCoverageDelta(%q) = %+v", d.String(), parsed)
+	}
+	if ParseCoverageDelta("") != nil {
+		t.Error("ParseCoverageDelta parsed an empty field")
+	}
+
+	d.MaxDrop = 0
+	if !d.Blocked() {
+		t.Error("a drop wasn't blocked under max_drop 0")
+	}
+	// Float noise isn't a drop
+	if (&CoverageDelta{Target: 200.0 / 3, Candidate: 66.666666666}).Blocked() {
+		t.Error("unchanged coverage blocked")
+	}
+	if (&CoverageDelta{Target: 70, Candidate: 75}).Blocked() {
+		t.Error("a rise blocked")
+	}
+	var none *CoverageDelta
+	if none.Blocked() {
+		t.Error("no gate blocked")
+	}
+}
+
+func TestNewCoverageGate(t *testing.T) {
+	if g, err := NewCoverageGate("/rig", nil); g != nil || err != nil {
+		t.Errorf("NewCoverageGate(nil) = %v, %v", g, err)
+	}
+	for _, cfg := range []*config.MergeCoverageConfig{
+		{File: "coverage.out"},
+		{Command: "make cover"},
+		{Command: "make cover", File: "/tmp/coverage.out"},
+		{Command: "make cover", File: "../coverage.out"},
+		{Command: "make cover", File: "coverage.out", MaxDrop: -1},
+	} {
+		if _, err := NewCoverageGate("/rig", cfg); err == nil {
+			t.Errorf("NewCoverageGate(%+v) succeeded", *cfg)
+		}
+	}
+
+	rigPath := t.TempDir()
+	g, err := NewCoverageGate(rigPath, &config.MergeCoverageConfig{Command: "make cover", File: "coverage.out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Cached("abc123"); ok {
+		t.Error("empty cache had a target")
+	}
+	for i := 0; i < coverageCacheSize+1; i++ {
+		commit := "c" + strings.Repeat("0", i)
+		if err := g.Remember(commit, float64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := g.Cached("c"); ok {
+		t.Error("oldest target kept past the cache size")
+	}
+	if got, ok := g.Cached("c0"); !ok || got != 1 {
+		t.Errorf("Cached(c0) = %v, %v; want 1", got, ok)
+	}
+}
+
+func TestMergeCoverageGate(t *testing.T) {
+	// The polecat's feature.txt drops coverage from 80% to 70%
+	const cover = `if [ -f feature.txt ]; then echo 70 > cov.txt; else echo 80 > cov.txt; fi`
+
+	t.Run("blocked", func(t *testing.T) {
+		rigPath, origin, _ := setupMirrorRig(t)
+		writeCheckSettings(t, rigPath, `{"coverage":{"command":"`+cover+`","file":"cov.txt","max_drop":5}}`)
+		before := runGit(t, origin, "rev-parse", "main")
+
+		result := mirrorTestEngineer(rigPath).doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+		if result.Success || !result.TestsFailed {
+			t.Fatalf("doMerge = %+v, want blocked on coverage", result)
+		}
+		if !result.Coverage.Blocked() || result.Coverage.String() != "80.00% -> 70.00% (-10.00)" {
+			t.Errorf("coverage = %v", result.Coverage)
+		}
+		if after := runGit(t, origin, "rev-parse", "main"); after != before {
+			t.Error("origin main moved on a blocked merge")
+		}
+		if result.Artifacts == "" {
+			t.Error("coverage run's artifacts not kept")
+		}
+	})
+
+	t.Run("within threshold", func(t *testing.T) {
+		rigPath, origin, _ := setupMirrorRig(t)
+		writeCheckSettings(t, rigPath, `{"coverage":{"command":"`+cover+`","file":"cov.txt","max_drop":10}}`)
+
+		result := mirrorTestEngineer(rigPath).doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+		if !result.Success {
+			t.Fatalf("doMerge failed: %s", result.Error)
+		}
+		if result.Coverage == nil || result.Coverage.Delta() != -10 {
+			t.Errorf("coverage = %v, want the -10 point delta recorded", result.Coverage)
+		}
+		if got := runGit(t, origin, "rev-parse", "main"); got != result.MergeCommit {
+			t.Errorf("origin main = %s, want merge commit %s", got, result.MergeCommit)
+		}
+
+		// The target's coverage is remembered for later MRs
+		g, err := LoadCoverageGate(rigPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := runGit(t, origin, "rev-parse", "main~1")
+		if got, ok := g.Cached(base); !ok || got != 80 {
+			t.Errorf("Cached(target) = %v, %v; want 80", got, ok)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrInvalidMRDescription indicates a malformed MR description template.
+var ErrInvalidMRDescription = errors.New("invalid MR description template")
+
+// DefaultDescriptionSection heads a body written without a template or
+// without headings.
+const DefaultDescriptionSection = "Description"
+
+var htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// MRDescription is a rig's merge_queue.description template: the sections
+// an MR description is written in, and which of them must be filled in.
+// A nil *MRDescription has no sections and requires nothing.
+type MRDescription struct {
+	sections []string
+	required map[string]bool // lowercased section names
+}
+
+// NewMRDescription builds an MRDescription from config. Returns nil (no
+// template) if cfg is nil or has no sections.
+func NewMRDescription(cfg *config.MRDescriptionConfig) (*MRDescription, error) {
+	if cfg == nil || (len(cfg.Sections) == 0 && len(cfg.Required) == 0) {
+		return nil, nil
+	}
+	d := &MRDescription{required: make(map[string]bool)}
+	seen := make(map[string]bool)
+	for _, section := range cfg.Sections {
+		name := strings.TrimSpace(section)
+		if name == "" || strings.ContainsAny(name, "\n#") {
+			return nil, fmt.Errorf("%w: bad section name %q", ErrInvalidMRDescription, section)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("%w: section %q listed twice", ErrInvalidMRDescription, name)
+		}
+		seen[strings.ToLower(name)] = true
+		d.sections = append(d.sections, name)
+	}
+	for _, section := range cfg.Required {
+		key := strings.ToLower(strings.TrimSpace(section))
+		if !seen[key] {
+			return nil, fmt.Errorf("%w: required section %q is not in sections", ErrInvalidMRDescription, section)
+		}
+		d.required[key] = true
+	}
+	return d, nil
+}
+
+// LoadMRDescription reads the MR description template from a rig's
+// settings/config.json. A missing settings file or description section
+// yields nil (no template).
+func LoadMRDescription(rigPath string) (*MRDescription, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewMRDescription(settings.MergeQueue.Description)
+}
+
+// Sections returns the template's sections, in order.
+func (d *MRDescription) Sections() []string {
+	if d == nil {
+		return nil
+	}
+	return d.sections
+}
+
+// Required returns the sections that must be filled in, in order.
+func (d *MRDescription) Required() []string {
+	if d == nil {
+		return nil
+	}
+	var required []string
+	for _, section := range d.sections {
+		if d.required[strings.ToLower(section)] {
+			required = append(required, section)
+		}
+	}
+	return required
+}
+
+// Render returns the template for editing: a comment saying what to fill
+// in, then a heading per section. prefill supplies text for sections, by
+// name (case-insensitive).
+func (d *MRDescription) Render(prefill map[string]string) string {
+	sections := d.Sections()
+	if len(sections) == 0 {
+		sections = []string{DefaultDescriptionSection}
+	}
+	text := make(map[string]string, len(prefill))
+	for name, value := range prefill {
+		text[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+
+	var b strings.Builder
+	b.WriteString("<!--\nDescribe this merge request for its reviewers. Write under each heading;\n")
+	if required := d.Required(); len(required) > 0 {
+		fmt.Fprintf(&b, "required: %s. ", strings.Join(required, ", "))
+	}
+	b.WriteString("Empty sections and comments like this are dropped.\n-->\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n%s%s\n\n", beads.MRBodyHeading, section)
+		if value := text[strings.ToLower(section)]; value != "" {
+			b.WriteString(value + "\n")
+		}
+	}
+	return b.String()
+}
+
+// MissingSectionsError reports required sections left empty.
+type MissingSectionsError struct {
+	Sections []string
+}
+
+func (e *MissingSectionsError) Error() string {
+	return fmt.Sprintf("MR description is missing required sections: %s", strings.Join(e.Sections, ", "))
+}
+
+// Parse reads an edited description into the body stored on the MR bead.
+// Comments are dropped, sections are put in template order under their
+// template names (sections not in the template follow, as written), and
+// empty sections are left out. Text before the first heading goes in the
+// first section. Returns a *MissingSectionsError (with the body) if a
+// required section is empty.
+func (d *MRDescription) Parse(text string) (string, error) {
+	text = htmlCommentRe.ReplaceAllString(text, "")
+
+	type section struct {
+		name string
+		text []string
+	}
+	first := DefaultDescriptionSection
+	if sections := d.Sections(); len(sections) > 0 {
+		first = sections[0]
+	}
+	written := []*section{{name: first}}
+	for _, line := range strings.Split(text, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), beads.MRBodyHeading); ok {
+			written = append(written, &section{name: strings.TrimSpace(name)})
+			continue
+		}
+		current := written[len(written)-1]
+		current.text = append(current.text, line)
+	}
+
+	// Gather text by section name, merging repeats
+	content := make(map[string]string)
+	var order []string
+	for _, s := range written {
+		key := strings.ToLower(s.name)
+		body := strings.TrimSpace(strings.Join(s.text, "\n"))
+		if body == "" {
+			continue
+		}
+		if _, ok := content[key]; !ok {
+			order = append(order, s.name)
+			content[key] = body
+		} else {
+			content[key] += "\n\n" + body
+		}
+	}
+
+	var parts []string
+	var missing []string
+	used := make(map[string]bool)
+	for _, name := range d.Sections() {
+		key := strings.ToLower(name)
+		used[key] = true
+		if body := content[key]; body != "" {
+			parts = append(parts, beads.MRBodyHeading+name+"\n\n"+body)
+		} else if d.required[key] {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range order {
+		if key := strings.ToLower(name); !used[key] {
+			used[key] = true
+			parts = append(parts, beads.MRBodyHeading+name+"\n\n"+content[key])
+		}
+	}
+
+	body := strings.Join(parts, "\n\n")
+	if len(missing) > 0 {
+		return body, &MissingSectionsError{Sections: missing}
+	}
+	return body, nil
+}
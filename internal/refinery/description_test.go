@@ -0,0 +1,112 @@
+package refinery
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewMRDescription(t *testing.T) {
+	if d, err := NewMRDescription(nil); err != nil || d != nil {
+		t.Errorf("NewMRDescription(nil) = %v, %v; want nil", d, err)
+	}
+	bad := []*config.MRDescriptionConfig{
+		{Sections: []string{"Summary", "summary"}},
+		{Sections: []string{"Summary", ""}},
+		{Sections: []string{"## Summary"}},
+		{Sections: []string{"Summary"}, Required: []string{"Testing"}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewMRDescription(cfg); !errors.Is(err, ErrInvalidMRDescription) {
+			t.Errorf("NewMRDescription(%+v) = %v, want ErrInvalidMRDescription", cfg, err)
+		}
+	}
+}
+
+func TestLoadMRDescription(t *testing.T) {
+	rigPath := t.TempDir()
+	if d, err := LoadMRDescription(rigPath); err != nil || d != nil {
+		t.Errorf("LoadMRDescription without settings = %v, %v; want nil", d, err)
+	}
+
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"rig-settings","version":1,"merge_queue":{"description":{"sections":["Summary","Testing","Risk"],"required":["summary","Testing"]}}}`
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadMRDescription(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(d.Required(), ",") != "Summary,Testing" {
+		t.Errorf("Required() = %v, want Summary and Testing in template order", d.Required())
+	}
+}
+
+func TestMRDescriptionRenderParse(t *testing.T) {
+	d, err := NewMRDescription(&config.MRDescriptionConfig{
+		Sections: []string{"Summary", "Testing", "Risk"},
+		Required: []string{"Summary", "Testing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rendered := d.Render(map[string]string{"summary": "Fix login redirect (gt-abc)"})
+	for _, want := range []string{"required: Summary, Testing.", "## Summary\n\nFix login redirect (gt-abc)\n", "## Testing\n", "## Risk\n"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Render missing %q:\n%s", want, rendered)
+		}
+	}
+
+	// Unedited, Testing is missing
+	body, err := d.Parse(rendered)
+	var missing *MissingSectionsError
+	if !errors.As(err, &missing) || strings.Join(missing.Sections, ",") != "Testing" {
+		t.Fatalf("Parse(unedited) = %v, want Testing missing", err)
+	}
+	if body != "## Summary\n\nFix login redirect (gt-abc)" {
+		t.Errorf("body = %q", body)
+	}
+
+	// Headings are matched case-insensitively and put in template order;
+	// extra sections follow, and empty ones are dropped
+	edited := `Fixes the redirect loop.
+## risk
+Low.
+## Notes
+Target: staging only
+## testing
+go test ./internal/auth/...
+## Screenshots
+`
+	body, err = d.Parse(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Summary\n\nFixes the redirect loop.\n\n## Testing\n\ngo test ./internal/auth/...\n\n## Risk\n\nLow.\n\n## Notes\n\nTarget: staging only"
+	if body != want {
+		t.Errorf("body =\n%s\nwant\n%s", body, want)
+	}
+}
+
+func TestMRDescriptionNil(t *testing.T) {
+	var d *MRDescription
+	body, err := d.Parse("Just some notes.\n<!-- dropped -->\n")
+	if err != nil || body != "## Description\n\nJust some notes." {
+		t.Errorf("Parse = %q, %v", body, err)
+	}
+	if body, _ := d.Parse("<!-- only a comment -->\n## Summary\n\n"); body != "" {
+		t.Errorf("Parse of an empty description = %q, want empty", body)
+	}
+	if !strings.Contains(d.Render(nil), "## Description") {
+		t.Error("nil template should render a Description section")
+	}
+}
@@ -2,7 +2,6 @@
 package refinery
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,7 +13,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
@@ -73,20 +74,26 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 // MRInfo holds merge request information for display and processing.
 // This replaces mrqueue.MR after the mrqueue package removal.
 type MRInfo struct {
-	ID              string     // Bead ID (e.g., "gt-abc123")
-	Branch          string     // Source branch (e.g., "polecat/nux")
-	Target          string     // Target branch (e.g., "main")
-	SourceIssue     string     // The work item being merged
-	Worker          string     // Who did the work
-	Rig             string     // Which rig
-	Title           string     // MR title
-	Priority        int        // Priority (lower = higher priority)
-	AgentBead       string     // Agent bead ID that created this MR
-	RetryCount      int        // Conflict retry count
-	ConvoyID        string     // Parent convoy ID if part of a convoy
-	ConvoyCreatedAt *time.Time // Convoy creation time
-	CreatedAt       time.Time  // MR creation time
-	BlockedBy       string     // Task ID blocking this MR
+	ID                 string     // Bead ID (e.g., "gt-abc123")
+	Branch             string     // Source branch (e.g., "polecat/nux")
+	Target             string     // Target branch (e.g., "main")
+	SourceIssue        string     // The work item being merged
+	Worker             string     // Who did the work
+	Rig                string     // Which rig
+	Project            string     // Monorepo sub-project, if its changes fall in one
+	Locks              []string   // Path locks it merges under (see PathLocks)
+	Title              string     // MR title
+	Priority           int        // Priority (lower = higher priority)
+	AgentBead          string     // Agent bead ID that created this MR
+	RetryCount         int        // Conflict retry count
+	ConvoyID           string     // Parent convoy ID if part of a convoy
+	ConvoyCreatedAt    *time.Time // Convoy creation time
+	CreatedAt          time.Time  // MR creation time
+	BlockedBy          string     // Task ID blocking this MR
+	ChecksPassed       []string   // Checks recorded as passed
+	ApprovedBy         []string   // Approvers recorded on the MR
+	ChangesRequestedBy []string   // Reviewers who requested changes
+	Reverts            string     // MR this MR reverts (its source issue stays open)
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -95,6 +102,9 @@ type Engineer struct {
 	rig     *rig.Rig
 	beads   *beads.Beads
 	git     *git.Git
+	mirror  *Mirror // nil for legacy rigs without a shared bare repo
+	remote  string  // where merges land: the rig's push remote, usually origin
+	mirrors []string
 	config  *MergeQueueConfig
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
@@ -117,11 +127,15 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
 		gitDir = filepath.Join(r.Path, "mayor", "rig")
 	}
+	mirror, _ := OpenMirror(r.Path)
 
 	return &Engineer{
 		rig:     r,
 		beads:   beads.New(r.Path),
 		git:     git.NewGit(gitDir),
+		mirror:  mirror,
+		remote:  r.PushRemote(),
+		mirrors: r.MirrorRemotes(),
 		config:  cfg,
 		workDir: gitDir,
 		output:  os.Stdout,
@@ -228,11 +242,43 @@ func (e *Engineer) Config() *MergeQueueConfig {
 type ProcessResult struct {
 	Success     bool
 	MergeCommit string
+	Head        string // Tip of the source branch that was merged
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+	Rejected    bool           // Violates branch protection; closed rather than retried
+	Tests       *TestResults   // Results of the test run, if tests ran
+	Artifacts   string         // Where the test run's log and artifacts were kept
+	Coverage    *CoverageDelta // Coverage change, if the rig gates on it
+	Lint        *LintReport    // What the rig's linters found, if it has any
+	Signed      string         // Signature format, if the refinery signed MergeCommit
+
+	ConflictFiles []string // Files that conflicted with the target
+	ConflictDiff  string   // Their conflicting hunks, with conflict markers
 }
 
+// Apply records the checks a merge attempt ran on an MR's fields: the
+// results of its run of check, where its artifacts were kept, its
+// coverage delta and its lint findings.
+func (r ProcessResult) Apply(fields *beads.MRFields, check string) {
+	if r.Tests != nil {
+		r.Tests.Apply(fields, check)
+	}
+	if r.Artifacts != "" {
+		fields.Artifacts = r.Artifacts
+	}
+	if r.Coverage != nil {
+		fields.Coverage = r.Coverage.String()
+	}
+	if r.Lint != nil {
+		fields.LintFindings = r.Lint.Field()
+	}
+}
+
+// MaxConflictDiffLines caps the conflicting hunks kept for a
+// conflict-resolution task.
+const MaxConflictDiffLines = 200
+
 // ProcessMR processes a single merge request from a beads issue.
 func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult {
 	// Parse MR fields from description
@@ -250,12 +296,362 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
-	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+	if result := e.enforceProtection(mrFields.Branch, mrFields.Target, ReviewFromMR(mr, mrFields)); result != nil {
+		return *result
+	}
+	_ = events.LogFeed(events.TypeMergeStarted, e.actor(), events.MergePayload(mr.ID, mrFields.Worker, mrFields.Branch, ""))
+	return e.doMerge(ctx, mr.ID, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+}
+
+// CheckProtection evaluates the rig's branch protection rules against the
+// change branch would introduce into target, holds high-risk MRs for
+// approval if the rig requires it, requires sign-off from the owners of
+// the paths changed, and applies the rig's merge policies. Refs missing
+// locally are resolved against origin. Returns no violations if the rig
+// has no rules.
+func (e *Engineer) CheckProtection(branch, target string, review MRReview) ([]ProtectionViolation, error) {
+	protection, err := LoadBranchProtection(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading branch protection: %w", err)
+	}
+	scorer, err := LoadRiskScorer(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading risk settings: %w", err)
+	}
+	ownership, err := LoadOwnership(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading owners: %w", err)
+	}
+	policies, err := LoadPolicies(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading merge policies: %w", err)
+	}
+	if protection == nil && !scorer.RequiresApproval() && ownership == nil && policies == nil {
+		return protection.CheckReview(review), nil
+	}
+	stats, err := e.DiffStatMR(branch, target)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against %s: %w", branch, target, err)
+	}
+	change := changeFromStats(stats)
+	violations := protection.Check(change, review)
+	risk := scorer.Score(stats)
+	if v := scorer.CheckApproval(risk, protection, review); v != nil {
+		violations = append(violations, *v)
+	}
+	if v := ownership.CheckApproval(change.Files, review); v != nil {
+		violations = append(violations, *v)
+	}
+	violations = append(violations, policies.Check(PolicyInput{
+		Stats:  stats,
+		Review: review,
+		Risk:   risk,
+		Branch: branch,
+		Target: target,
+		Now:    time.Now(),
+	})...)
+	return violations, nil
+}
+
+// CheckPolicies evaluates only the rig's merge policies against the MR
+// branch would introduce into target. Returns nil without diffing if the
+// rig has no policies.
+func (e *Engineer) CheckPolicies(branch, target string, review MRReview) ([]ProtectionViolation, error) {
+	policies, err := LoadPolicies(e.rig.Path)
+	if err != nil || policies == nil {
+		return nil, err
+	}
+	scorer, err := LoadRiskScorer(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading risk settings: %w", err)
+	}
+	stats, err := e.DiffStatMR(branch, target)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against %s: %w", branch, target, err)
+	}
+	return policies.Check(PolicyInput{
+		Stats:  stats,
+		Review: review,
+		Risk:   scorer.Score(stats),
+		Branch: branch,
+		Target: target,
+		Now:    time.Now(),
+	}), nil
+}
+
+// OwnerApprovals returns the owner sign-offs the change branch would
+// introduce into target needs, marked with the approvals in approvedBy.
+// Returns nil if the rig has no owners.
+func (e *Engineer) OwnerApprovals(branch, target string, approvedBy []string) ([]OwnerApproval, error) {
+	ownership, err := LoadOwnership(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading owners: %w", err)
+	}
+	if ownership == nil {
+		return nil, nil
+	}
+	stats, err := e.DiffStatMR(branch, target)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against %s: %w", branch, target, err)
+	}
+	return ownership.Approvals(changeFromStats(stats).Files, approvedBy), nil
+}
+
+// AssessRisk scores the change branch would introduce into target using the
+// rig's risk settings.
+func (e *Engineer) AssessRisk(branch, target string) (RiskAssessment, error) {
+	scorer, err := LoadRiskScorer(e.rig.Path)
+	if err != nil {
+		return RiskAssessment{}, fmt.Errorf("loading risk settings: %w", err)
+	}
+	stats, err := e.DiffStatMR(branch, target)
+	if err != nil {
+		return RiskAssessment{}, fmt.Errorf("diffing %s against %s: %w", branch, target, err)
+	}
+	return scorer.Score(stats), nil
+}
+
+// DiffMR returns git diff output (with extra flags) for the change branch
+// would introduce into target, using the refinery's clone of the rig repo.
+func (e *Engineer) DiffMR(branch, target string, flags ...string) (string, error) {
+	return e.git.DiffRange(e.resolveRef(target), e.resolveRef(branch), flags...)
+}
+
+// DiffStatMR returns per-file line counts for the change branch would
+// introduce into target.
+func (e *Engineer) DiffStatMR(branch, target string) ([]git.FileDiffStat, error) {
+	return e.git.DiffStat(e.resolveRef(target), e.resolveRef(branch))
+}
+
+// AppendChangelog adds entry to CHANGELOG.md on the branch checked out in
+// the refinery's clone and commits it. An entry without a merge commit is
+// attributed to the current HEAD (the just-merged work).
+func (e *Engineer) AppendChangelog(entry ChangelogEntry) error {
+	if entry.MergeCommit == "" {
+		head, err := e.git.Rev("HEAD")
+		if err != nil {
+			return fmt.Errorf("resolving HEAD: %w", err)
+		}
+		entry.MergeCommit = head
+	}
+	if err := AppendChangelogEntry(filepath.Join(e.workDir, ChangelogFile), entry); err != nil {
+		return err
+	}
+	if err := e.git.Add(ChangelogFile); err != nil {
+		return fmt.Errorf("staging %s: %w", ChangelogFile, err)
+	}
+	msg := fmt.Sprintf("docs(changelog): %s", entry.Title)
+	if entry.SourceIssue != "" {
+		msg += fmt.Sprintf(" (%s)", entry.SourceIssue)
+	}
+	if err := e.git.Commit(msg); err != nil {
+		return fmt.Errorf("committing %s: %w", ChangelogFile, err)
+	}
+	return nil
+}
+
+// TagRelease tags the push remote's target branch with an annotated
+// release tag and pushes it. Returns the tagged commit and the previous tag
+// reachable from it ("" for a first release).
+func (e *Engineer) TagRelease(tag, target string) (commit, previous string, err error) {
+	if err := e.git.Fetch(e.remote); err != nil {
+		return "", "", fmt.Errorf("fetching %s: %w", e.remote, err)
+	}
+	if exists, err := e.git.TagExists(tag); err != nil {
+		return "", "", err
+	} else if exists {
+		return "", "", fmt.Errorf("%w: %s", ErrTagExists, tag)
+	}
+
+	ref := e.remote + "/" + target
+	if commit, err = e.git.Rev(ref); err != nil {
+		return "", "", fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	if previous, err = e.git.LatestTag(ref); err != nil {
+		return "", "", fmt.Errorf("finding previous tag: %w", err)
+	}
+	if err := e.git.CreateTag(tag, commit, "Release "+tag); err != nil {
+		return "", "", fmt.Errorf("creating tag %s: %w", tag, err)
+	}
+	if err := e.git.Push(e.remote, "refs/tags/"+tag, false); err != nil {
+		return "", "", fmt.Errorf("pushing tag %s: %w", tag, err)
+	}
+	return commit, previous, nil
+}
+
+// CreateRevertBranch creates branch from the push remote's target, reverts
+// commit on it, and pushes it to origin. The revert is made in a temporary worktree so
+// the refinery's checkout is left untouched.
+func (e *Engineer) CreateRevertBranch(branch, commit, target string) error {
+	return e.createBranchFrom(branch, target, "gt-revert-", func(wt *git.Git) error {
+		if err := wt.Revert(commit); err != nil {
+			return fmt.Errorf("reverting %s onto %s: %w", commit, target, err)
+		}
+		return nil
+	})
+}
+
+// CreateBackportBranch creates branch from the push remote's target,
+// cherry-picks commit onto it, and pushes it to origin, like
+// CreateRevertBranch.
+func (e *Engineer) CreateBackportBranch(branch, commit, target string) error {
+	return e.createBranchFrom(branch, target, "gt-backport-", func(wt *git.Git) error {
+		if err := wt.CherryPick(commit); err != nil {
+			return fmt.Errorf("cherry-picking %s onto %s: %w", commit, target, err)
+		}
+		return nil
+	})
+}
+
+// createBranchFrom creates branch from the push remote's target in a
+// temporary worktree, applies change there, and pushes the branch to origin
+// as workers push theirs.
+func (e *Engineer) createBranchFrom(branch, target, tmpPrefix string, change func(wt *git.Git) error) error {
+	if err := e.git.Fetch(e.remote); err != nil {
+		return fmt.Errorf("fetching %s: %w", e.remote, err)
+	}
+	if exists, err := e.git.RemoteBranchExists("origin", branch); err != nil {
+		return fmt.Errorf("checking for %s: %w", branch, err)
+	} else if exists {
+		return fmt.Errorf("%w: %s", ErrBranchExists, branch)
+	}
+
+	tmp, err := os.MkdirTemp("", tmpPrefix)
+	if err != nil {
+		return fmt.Errorf("creating worktree dir: %w", err)
+	}
+	path := filepath.Join(tmp, "wt")
+	defer os.RemoveAll(tmp)
+
+	if err := e.git.WorktreeAddFromRef(path, branch, e.remote+"/"+target); err != nil {
+		return fmt.Errorf("creating worktree: %w", err)
+	}
+	defer func() {
+		_ = e.git.WorktreeRemove(path, true)
+		_ = e.git.DeleteBranch(branch, true)
+	}()
+
+	wt := git.NewGit(path)
+	if err := change(wt); err != nil {
+		return err
+	}
+	if err := wt.Push("origin", branch, false); err != nil {
+		return fmt.Errorf("pushing %s: %w", branch, err)
+	}
+	return nil
+}
+
+// BisectResult is the first bad commit found by Engineer.Bisect.
+type BisectResult struct {
+	Culprit string `json:"culprit"`
+	Subject string `json:"subject"`
+	// MergeCommit is the commit on bad's first-parent line that brought
+	// Culprit in: the MR's merge commit, or Culprit itself for squash and
+	// fast-forward merges.
+	MergeCommit string `json:"merge_commit"`
+}
+
+// Bisect finds the first commit between good and bad for which command
+// fails. The bisect runs in a temporary worktree so the refinery's checkout
+// is left untouched.
+func (e *Engineer) Bisect(good, bad, command string) (*BisectResult, error) {
+	if err := e.git.Fetch(e.remote); err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", e.remote, err)
+	}
+	if ok, err := e.git.IsAncestor(good, bad); err != nil {
+		return nil, fmt.Errorf("comparing %s and %s: %w", good, bad, err)
+	} else if !ok {
+		return nil, fmt.Errorf("good commit %s is not an ancestor of bad commit %s", good, bad)
+	}
+
+	tmp, err := os.MkdirTemp("", "gt-bisect-")
+	if err != nil {
+		return nil, fmt.Errorf("creating worktree dir: %w", err)
+	}
+	path := filepath.Join(tmp, "wt")
+	defer os.RemoveAll(tmp)
+
+	if err := e.git.WorktreeAddDetached(path, bad); err != nil {
+		return nil, fmt.Errorf("creating worktree: %w", err)
+	}
+	defer func() { _ = e.git.WorktreeRemove(path, true) }()
+
+	culprit, err := git.NewGit(path).Bisect(good, bad, command)
+	if err != nil {
+		return nil, fmt.Errorf("bisecting: %w", err)
+	}
+	result := &BisectResult{Culprit: culprit}
+	result.Subject, _ = e.git.CommitSubject(culprit)
+
+	line, err := e.git.FirstParentCommits(good, bad)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s..%s: %w", good, bad, err)
+	}
+	for _, commit := range line {
+		if ok, err := e.git.IsAncestor(culprit, commit); err == nil && ok {
+			result.MergeCommit = commit
+			break
+		}
+	}
+	return result, nil
+}
+
+// actor is the refinery's address, used as the actor for events.
+func (e *Engineer) actor() string {
+	return e.rig.Name + "/refinery"
+}
+
+// WorkDir returns the refinery's clone of the rig repo.
+func (e *Engineer) WorkDir() string {
+	return e.workDir
+}
+
+// CommitTime returns the commit date of ref (e.g., a release tag) in the
+// refinery's clone.
+func (e *Engineer) CommitTime(ref string) (time.Time, error) {
+	return e.git.CommitTime(ref)
+}
+
+// resolveRef returns name if it is a local branch, otherwise origin/name.
+func (e *Engineer) resolveRef(name string) string {
+	if exists, err := e.git.BranchExists(name); err == nil && exists {
+		return name
+	}
+	return "origin/" + name
+}
+
+// enforceProtection returns a failed result if the MR violates branch
+// protection, or nil if it may proceed. Diff violations (forbidden paths,
+// size) reject the MR; missing checks or approvals leave it queued until
+// they are recorded. The engineer's own test run satisfies a required
+// "tests" check, since doMerge will not merge unless the tests pass.
+func (e *Engineer) enforceProtection(branch, target string, review MRReview) *ProcessResult {
+	if e.config.RunTests && e.config.TestCommand != "" {
+		review.ChecksPassed = append(review.ChecksPassed, "tests")
+	}
+	violations, err := e.CheckProtection(branch, target, review)
+	if err != nil {
+		return &ProcessResult{Success: false, Error: err.Error()}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %s\n", FormatViolations(violations))
+	return &ProcessResult{
+		Success:  false,
+		Error:    FormatViolations(violations),
+		Rejected: HasChangeViolation(violations),
+	}
 }
 
 // doMerge performs the actual git merge operation.
 // This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+//
+// Each step is written ahead to the rig's refinery journal, so a merge
+// interrupted by a crash can be recovered (see Recover). The journal is
+// cleared here if the merge fails, and by the success handlers once a
+// landed merge is recorded.
+func (e *Engineer) doMerge(ctx context.Context, mrID, branch, target, sourceIssue string) (result ProcessResult) {
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -272,6 +668,28 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Recorded on the MR so gt gc only deletes the branch if nothing was
+	// pushed to it since. Read before merging: if the branch moves in
+	// between, the recorded tip is older and the branch is kept.
+	head, _ := e.git.Rev(branch)
+
+	if err := OpenJournal(e.rig.Path).Begin(JournalEntry{MR: mrID, Branch: branch, Target: target, SourceIssue: sourceIssue}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	defer func() {
+		if !result.Success {
+			e.finishJournal(mrID)
+			return
+		}
+		result.Head = head
+	}()
+
+	testCommand := e.testCommandFor(branch, target)
+	if e.mirror != nil {
+		return e.mergeInWorktree(ctx, mrID, branch, target, sourceIssue, testCommand)
+	}
+
+	// Legacy rigs (no bare mirror) merge in the refinery's own clone.
 	// Step 2: Checkout the target branch
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
 	if err := e.git.Checkout(target); err != nil {
@@ -281,15 +699,18 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Make sure target is up to date with origin
-	if err := e.git.Pull("origin", target); err != nil {
+	// Make sure target is up to date with the push remote
+	if err := e.git.Pull(e.remote, target); err != nil {
 		// Pull might fail if nothing to pull, that's ok
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from %s/%s: %v (continuing)\n", e.remote, target, err)
+	}
+	if targetWas, err := e.git.Rev(target); err == nil {
+		e.journal(mrID, StepStarted, func(j *JournalEntry) { j.TargetWas = targetWas })
 	}
 
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
-	conflicts, err := e.git.CheckConflicts(branch, target)
+	conflicts, conflictDiff, err := e.git.CheckConflictsDiff(branch, target, MaxConflictDiffLines)
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -299,49 +720,49 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 	if len(conflicts) > 0 {
 		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+			Success:       false,
+			Conflict:      true,
+			Error:         fmt.Sprintf("merge conflicts in: %v", conflicts),
+			ConflictFiles: conflicts,
+			ConflictDiff:  conflictDiff,
 		}
 	}
 
 	// Step 4: Run tests if configured
-	if e.config.RunTests && e.config.TestCommand != "" {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
+	var tests *TestResults
+	var artifacts string
+	if e.config.RunTests && testCommand != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", testCommand)
+		result := e.runTests(ctx, mrID, e.workDir, branch, testCommand)
 		if !result.Success {
 			return ProcessResult{
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				Tests:       result.Tests,
+				Artifacts:   result.Artifacts,
 			}
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		tests, artifacts = result.Tests, result.Artifacts
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Tests passed: %s\n", tests)
 	}
 
 	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
+	originalMsg := e.squashMessage(branch, target, sourceIssue)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
 	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
 		conflicts, conflictErr := e.git.GetConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
+			conflictDiff, _ := e.git.ConflictDiff(MaxConflictDiffLines)
 			_ = e.git.AbortMerge()
 			return ProcessResult{
-				Success:  false,
-				Conflict: true,
-				Error:    "merge conflict during actual merge",
+				Success:       false,
+				Conflict:      true,
+				Error:         "merge conflict during actual merge",
+				ConflictFiles: conflicts,
+				ConflictDiff:  conflictDiff,
 			}
 		}
 		return ProcessResult{
@@ -358,71 +779,327 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
 		}
 	}
+	e.journal(mrID, StepMerged, func(j *JournalEntry) { j.MergeCommit = mergeCommit })
+
+	// Step 7: Lint and gate on coverage, now the merged result is checked out
+	base, err := e.git.Rev(mergeCommit + "~1")
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get target commit SHA: %v", err),
+		}
+	}
+	gates := e.checkMerged(ctx, mrID, e.workDir, base)
+	if artifacts == "" {
+		artifacts = gates.Artifacts
+	}
+	if !gates.Success {
+		e.rollbackTarget(target, mergeCommit)
+		return ProcessResult{
+			Success:     false,
+			TestsFailed: gates.TestsFailed,
+			Error:       gates.Error,
+			Tests:       tests,
+			Artifacts:   artifacts,
+			Coverage:    gates.Coverage,
+			Lint:        gates.Lint,
+		}
+	}
 
-	// Step 7: Push to origin
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
+	// Step 8: Push to the push remote, then any mirrors
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to %s/%s...\n", e.remote, target)
+	if err := e.git.Push(e.remote, target, false); err != nil {
 		return ProcessResult{
 			Success: false,
-			Error:   fmt.Sprintf("failed to push to origin: %v", err),
+			Error:   fmt.Sprintf("failed to push to %s: %v", e.remote, err),
 		}
 	}
+	e.journal(mrID, StepPushed, nil)
+	e.mirrorTarget(target, mergeCommit)
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		Tests:       tests,
+		Artifacts:   artifacts,
+		Coverage:    gates.Coverage,
+		Lint:        gates.Lint,
 	}
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
-	if e.config.TestCommand == "" {
-		return ProcessResult{Success: true}
+// mergeInWorktree squash-merges branch onto a fresh checkout of the push
+// remote's target in a temporary worktree of the rig's mirror, tests the
+// merged result there, and pushes it. Only target is fetched, and the
+// refinery's checkout is fast-forwarded afterwards rather than used for the
+// merge, so a failed or conflicting merge leaves nothing to clean up.
+func (e *Engineer) mergeInWorktree(ctx context.Context, mrID, branch, target, sourceIssue, testCommand string) ProcessResult {
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Syncing mirror with %s/%s...\n", e.remote, target)
+	if err := e.mirror.Sync(e.remote, target); err != nil {
+		// Merge onto what we have; a stale target makes the push fail
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (continuing)\n", err)
 	}
 
-	// Run the test command with retries for flaky tests
-	maxRetries := e.config.RetryFlakyTests
-	if maxRetries < 1 {
-		maxRetries = 1
+	path, err := e.mirror.AddWorktree(e.remote + "/" + target)
+	if err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
 	}
+	defer func() { _ = e.mirror.RemoveWorktree(path) }()
+	wt := git.NewGit(path)
+	e.journal(mrID, StepStarted, func(j *JournalEntry) { j.Worktree = path })
 
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if attempt > 1 {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
+	originalMsg := e.squashMessage(branch, target, sourceIssue)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
+	if err := wt.MergeSquash(branch, originalMsg); err != nil {
+		if conflicts, conflictErr := wt.GetConflictingFiles(); conflictErr == nil && len(conflicts) > 0 {
+			conflictDiff, _ := wt.ConflictDiff(MaxConflictDiffLines)
+			return ProcessResult{
+				Success:       false,
+				Conflict:      true,
+				Error:         fmt.Sprintf("merge conflicts in: %v", conflicts),
+				ConflictFiles: conflicts,
+				ConflictDiff:  conflictDiff,
+			}
 		}
-
-		// Note: TestCommand comes from rig's config.json (trusted infrastructure config),
-		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-		if err == nil {
-			return ProcessResult{Success: true}
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("merge failed: %v", err),
 		}
-		lastErr = err
+	}
 
-		// Check if context was canceled
-		if ctx.Err() != nil {
+	var tests *TestResults
+	var artifacts string
+	if e.config.RunTests && testCommand != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", testCommand)
+		result := e.runTests(ctx, mrID, path, branch, testCommand)
+		if !result.Success {
 			return ProcessResult{
-				Success: false,
-				Error:   "test run canceled",
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+				Tests:       result.Tests,
+				Artifacts:   result.Artifacts,
 			}
 		}
+		tests, artifacts = result.Tests, result.Artifacts
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Tests passed: %s\n", tests)
+	}
+
+	base, err := wt.Rev("HEAD~1")
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get target commit SHA: %v", err),
+		}
+	}
+	gates := e.checkMerged(ctx, mrID, path, base)
+	if artifacts == "" {
+		artifacts = gates.Artifacts
+	}
+	if !gates.Success {
+		return ProcessResult{
+			Success:     false,
+			TestsFailed: gates.TestsFailed,
+			Error:       gates.Error,
+			Tests:       tests,
+			Artifacts:   artifacts,
+			Coverage:    gates.Coverage,
+			Lint:        gates.Lint,
+		}
+	}
+
+	mergeCommit, err := wt.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
+		}
+	}
+	e.journal(mrID, StepMerged, func(j *JournalEntry) { j.MergeCommit = mergeCommit })
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to %s/%s...\n", e.remote, target)
+	if err := wt.Push(e.remote, "HEAD:"+target, false); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to push to %s: %v", e.remote, err),
+		}
+	}
+	e.journal(mrID, StepPushed, nil)
+	e.fastForwardTarget(target, mergeCommit)
+	e.mirrorTarget(target, mergeCommit)
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+	return ProcessResult{
+		Success:     true,
+		MergeCommit: mergeCommit,
+		Tests:       tests,
+		Artifacts:   artifacts,
+		Coverage:    gates.Coverage,
+		Lint:        gates.Lint,
+	}
+}
+
+// fastForwardTarget moves the local target branch to commit after a merge
+// pushed from a worktree, so local refs (and the refinery's checkout, if it
+// is on target) stay current. Best-effort: a dirty or diverged checkout is
+// left alone and catches up on its next pull.
+func (e *Engineer) fastForwardTarget(target, commit string) {
+	var err error
+	if current, _ := e.git.CurrentBranch(); current == target {
+		err = e.git.MergeFFOnly(commit)
+	} else {
+		err = e.git.ResetBranch(target, commit)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not update local %s: %v\n", target, err)
+	}
+}
+
+// mirrorTarget pushes a landed merge to the rig's mirror remotes.
+// Best-effort: the merge has landed, so a mirror that can't take it (down,
+// or diverged) is only a warning.
+func (e *Engineer) mirrorTarget(target, commit string) {
+	for _, remote := range e.mirrors {
+		if err := e.git.Push(remote, commit+":refs/heads/"+target, false); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not mirror %s to %s: %v\n", target, remote, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Mirrored %s to %s/%s\n", shortCommit(commit), remote, target)
+	}
+}
+
+// squashMessage returns the message for squash-merging branch: its head
+// commit's message, which keeps the conventional commit format (feat:/fix:),
+// or a descriptive fallback.
+func (e *Engineer) squashMessage(branch, target, sourceIssue string) string {
+	msg, err := e.git.GetBranchCommitMessage(branch)
+	if err == nil {
+		return msg
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+	if sourceIssue != "" {
+		return fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+	}
+	return fmt.Sprintf("Squash merge %s into %s", branch, target)
+}
+
+// testCommandFor returns the command that tests branch's merge into
+// target: its project's, when all its changes fall in one of the rig's
+// merge_queue.projects, else the merge queue's.
+func (e *Engineer) testCommandFor(branch, target string) string {
+	projects, err := LoadProjects(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: merge projects: %v\n", err)
+		return e.config.TestCommand
+	}
+	if projects == nil {
+		return e.config.TestCommand
+	}
+	stats, err := e.DiffStatMR(branch, target)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not match %s to a project: %v\n", branch, err)
+		return e.config.TestCommand
+	}
+	project := projects.Match(DiffStatFiles(stats))
+	if project != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s is in project %s\n", branch, project)
+	}
+	return projects.TestCommand(project, e.config.TestCommand)
+}
+
+// runTests runs command, the MR's test command, in dir and returns the result.
+// A failure is retried as the rig's flake history allows (and at least
+// RetryFlakyTests times in all); the run is recorded in that history
+// against source, the branch under test. The tests run where, and within
+// the limits, merge_queue.checks.tests sets. Their log and artifacts are
+// kept in the rig's artifact store under mrID.
+func (e *Engineer) runTests(ctx context.Context, mrID, dir, source, command string) ProcessResult {
+	if command == "" {
+		return ProcessResult{Success: true}
+	}
+
+	history, err := LoadFlakeHistory(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: flaky check settings: %v\n", err)
+	}
+	attempts := 1 + history.Retries(CheckTests)
+	if e.config.RetryFlakyTests > attempts {
+		attempts = e.config.RetryFlakyTests
+	}
+	runner, err := LoadCheckRunner(e.rig.Path, CheckTests)
+	if err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
+	testCtx := ctx
+	timeout := runner.TimeoutOr(0)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		testCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	run := CheckCommand{
+		Command:  command,
+		Dir:      dir,
+		Attempts: attempts,
+		Runner:   runner,
+		Retrying: func(attempt, attempts int) {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, attempts)
+		},
+	}.Run(testCtx)
+	if ctx.Err() != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   "test run canceled",
+		}
+	}
+	artifacts := e.saveArtifacts(mrID, CheckTests, dir, run)
+	if testCtx.Err() != nil {
+		return ProcessResult{
+			Success:     false,
+			TestsFailed: true,
+			Error:       fmt.Sprintf("tests timed out after %v", timeout),
+			Tests:       run.Tests,
+			Artifacts:   artifacts,
+		}
+	}
+	if err := history.Record(CheckTests, source, run); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	if run.Err == nil {
+		return ProcessResult{Success: true, Tests: run.Tests, Artifacts: artifacts}
 	}
 
+	reason := fmt.Sprintf("tests failed after %d attempts: %v", run.Tests.Attempts, run.Err)
+	if len(run.Tests.Failing) > 0 {
+		reason += "; failing: " + strings.Join(run.Tests.Failing, ", ")
+	}
 	return ProcessResult{
 		Success:     false,
 		TestsFailed: true,
-		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		Error:       reason,
+		Tests:       run.Tests,
+		Artifacts:   artifacts,
 	}
 }
 
+// saveArtifacts keeps a check run's log and artifacts under mrID, and
+// returns where, or "" (with a warning) if they can't be kept.
+func (e *Engineer) saveArtifacts(mrID, check, dir string, run CheckRun) string {
+	if mrID == "" {
+		return ""
+	}
+	store, err := LoadArtifactStore(e.rig.Path)
+	if err == nil {
+		var saved string
+		if saved, err = store.Save(mrID, check, dir, run); err == nil {
+			return saved
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: keeping %s artifacts: %v\n", check, err)
+	return ""
+}
+
 // handleSuccess handles a successful merge completion.
 // Steps:
 // 1. Update MR with merge_commit SHA
@@ -437,9 +1114,15 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		mrFields = &beads.MRFields{}
 	}
 
+	_ = events.LogFeed(events.TypeMerged, e.actor(), events.MergePayload(mr.ID, mrFields.Worker, mrFields.Branch, ""))
+	e.resolveFailures(mr.ID, mrFields.Branch, result.MergeCommit)
+
 	// 1. Update MR with merge_commit SHA
 	mrFields.MergeCommit = result.MergeCommit
+	mrFields.MergeHead = result.Head
 	mrFields.CloseReason = "merged"
+	mrFields.State = string(StateMerged)
+	result.Apply(mrFields, CheckTests)
 	newDesc := beads.SetMRFields(mr, mrFields)
 	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -450,8 +1133,9 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close MR %s: %v\n", mr.ID, err)
 	}
 
-	// 3. Close source issue with reference to MR
-	if mrFields.SourceIssue != "" {
+	// 3. Close source issue with reference to MR. A revert MR's source
+	// issue was reopened for rework, so it stays open.
+	if mrFields.SourceIssue != "" && mrFields.Reverts == "" {
 		closeReason := fmt.Sprintf("Merged in %s", mr.ID)
 		if err := e.beads.CloseWithReason(closeReason, mrFields.SourceIssue); err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mrFields.SourceIssue, err)
@@ -491,15 +1175,36 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 	}
 
 	// 5. Log success
+	e.finishJournal(mr.ID)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
 // handleFailure handles a failed merge request.
 // Reopens the MR for rework and logs the failure.
 func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
-	// Reopen the MR (back to open status for rework)
-	open := "open"
-	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Status: &open}); err != nil {
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	_ = events.LogFeed(events.TypeMergeFailed, e.actor(), events.MergePayload(mr.ID, fields.Worker, fields.Branch, result.Error))
+	e.recordTestResults(mr, result)
+	e.recordFailure(FailureRecord{MR: mr.ID, Issue: fields.SourceIssue, Branch: fields.Branch, Target: fields.Target, Worker: fields.Worker}, result)
+
+	if result.Rejected {
+		e.rejectMR(mr, result)
+		return
+	}
+	if result.Lint.Failed() {
+		if taskID, err := e.SendBackForLint(mr, result.Lint); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: sending %s back for lint: %v\n", mr.ID, err)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed lint: %s sent back to its worker with fix-it task %s\n", mr.ID, taskID)
+			return
+		}
+	}
+
+	// Requeue the MR (back to open status for rework)
+	if _, err := RecordState(e.beads, mr, StateQueued, ""); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reopen MR %s: %v\n", mr.ID, err)
 	}
 
@@ -507,6 +1212,57 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 }
 
+// recordTestResults stores a test run's results, and where its artifacts
+// were kept, on the MR bead, warning if it can't.
+func (e *Engineer) recordTestResults(mr *beads.Issue, result ProcessResult) {
+	if err := RecordCheckRun(e.beads, mr, CheckTests, result); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+}
+
+// recordFailure adds a failed merge to the rig's knowledge base of merge
+// failures, warning if it can't.
+func (e *Engineer) recordFailure(f FailureRecord, result ProcessResult) {
+	f.Kind = FailureKind(result)
+	f.Error = result.Error
+	f.Files = result.ConflictFiles
+	if result.Tests != nil {
+		f.Tests = result.Tests.Failing
+	}
+	if f.Branch != "" {
+		f.Head, _ = e.git.Rev(f.Branch)
+	}
+	if _, err := NewFailureKB(e.rig.Path).Record(f); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+}
+
+// resolveFailures marks a merged MR's earlier failures resolved in the
+// rig's knowledge base, warning if it can't. It runs before the branch is
+// deleted, to tell retries from fixes by the branch's head.
+func (e *Engineer) resolveFailures(mrID, branch, mergeCommit string) {
+	var head string
+	if branch != "" {
+		head, _ = e.git.Rev(branch)
+	}
+	resolved, err := NewFailureKB(e.rig.Path).ResolveMerged(mrID, head, mergeCommit)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	for _, f := range resolved {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Resolved merge failure %s (%s): %s\n", f.ID, f.Kind, f.Resolution.How)
+	}
+}
+
+// rejectMR closes an MR that violates branch protection.
+func (e *Engineer) rejectMR(mr *beads.Issue, result ProcessResult) {
+	if _, err := RecordState(e.beads, mr, StateRejected, result.Error); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reject MR %s: %v\n", mr.ID, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Rejected: %s - %s\n", mr.ID, result.Error)
+}
+
 // ProcessMRInfo processes a merge request from MRInfo.
 func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
 	// MR fields are directly on the struct
@@ -516,12 +1272,27 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	review := MRReview{
+		ChecksPassed:       mr.ChecksPassed,
+		ApprovedBy:         mr.ApprovedBy,
+		ChangesRequestedBy: mr.ChangesRequestedBy,
+		Author:             mr.Worker,
+		Priority:           mr.Priority,
+	}
+	if result := e.enforceProtection(mr.Branch, mr.Target, review); result != nil {
+		return *result
+	}
+
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	_ = events.LogFeed(events.TypeMergeStarted, e.actor(), events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+	return e.doMerge(ctx, mr.ID, mr.Branch, mr.Target, mr.SourceIssue)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
+	_ = events.LogFeed(events.TypeMerged, e.actor(), events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+	e.resolveFailures(mr.ID, mr.Branch, result.MergeCommit)
+
 	// Release merge slot if this was a conflict resolution
 	// The slot is held while conflict resolution is in progress
 	holder := e.rig.Name + "/refinery"
@@ -549,7 +1320,10 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 				mrFields = &beads.MRFields{}
 			}
 			mrFields.MergeCommit = result.MergeCommit
+			mrFields.MergeHead = result.Head
 			mrFields.CloseReason = "merged"
+			mrFields.State = string(StateMerged)
+			result.Apply(mrFields, CheckTests)
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -564,8 +1338,13 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 1. Close source issue with reference to MR
-	if mr.SourceIssue != "" {
+	// 0.5. Submit cherry-pick MRs onto targets that backport this one's
+	if mr.ID != "" && result.MergeCommit != "" {
+		e.submitBackports(mr.ID)
+	}
+
+	// 1. Close source issue with reference to MR (unless this is a revert)
+	if mr.SourceIssue != "" && mr.Reverts == "" {
 		closeReason := fmt.Sprintf("Merged in %s", mr.ID)
 		if err := e.beads.CloseWithReason(closeReason, mr.SourceIssue); err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mr.SourceIssue, err)
@@ -597,21 +1376,42 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	}
 
 	// 3. Log success
+	e.finishJournal(mr.ID)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// submitBackports submits an MR's backports, logging the outcome.
+func (e *Engineer) submitBackports(mrID string) {
+	backports, err := e.SubmitBackports(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	for _, bp := range backports {
+		if bp.Error != "" {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not backport %s onto %s: %s\n", mrID, bp.Target, bp.Error)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Submitted backport %s onto %s\n", bp.MRID, bp.Target)
+	}
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
+	_ = events.LogFeed(events.TypeMergeFailed, e.actor(), events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error))
+	e.recordFailure(FailureRecord{MR: mr.ID, Issue: mr.SourceIssue, Branch: mr.Branch, Target: mr.Target, Worker: mr.Worker}, result)
+	var mrBead *beads.Issue
+	if result.Tests != nil || result.Artifacts != "" || result.Coverage != nil || result.Lint != nil {
+		if bead, err := e.beads.Show(mr.ID); err == nil {
+			mrBead = bead
+			e.recordTestResults(mrBead, result)
+		}
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
-	failureType := "build"
-	if result.Conflict {
-		failureType = "conflict"
-	} else if result.TestsFailed {
-		failureType = "tests"
-	}
+	failureType := FailureKind(result)
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
@@ -632,10 +1432,37 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on task: %v\n", err)
 			} else {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on conflict task %s (non-blocking delegation)\n", mr.ID, taskID)
+				mr.BlockedBy = taskID
+			}
+			if e.dispatchConflicts() {
+				if err := e.dispatchConflictTask(taskID); err != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to dispatch conflict task %s: %v\n", taskID, err)
+				} else {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Dispatched conflict task %s to a polecat\n", taskID)
+				}
 			}
 		}
 	}
 
+	if result.Lint.Failed() && mrBead != nil {
+		if taskID, err := e.SendBackForLint(mrBead, result.Lint); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: sending %s back for lint: %v\n", mr.ID, err)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed lint: %s sent back to its worker with fix-it task %s\n", mr.ID, taskID)
+			return
+		}
+	}
+
+	if result.Rejected {
+		mrBead, err := e.beads.Show(mr.ID)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+			return
+		}
+		e.rejectMR(mrBead, result)
+		return
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
@@ -652,17 +1479,20 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 // Task format:
 //
 //	Title: Resolve merge conflicts: <original-issue-title>
-//	Type: task
+//	Type: conflict-resolution
 //	Priority: inherit from original + boost (P2 -> P1)
-//	Parent: original MR bead
-//	Description: metadata including branch, conflict SHA, etc.
+//	Description: metadata including branch, conflict SHA, etc., and the
+//	             conflicting files and hunks
+//
+// The MR records the task and the target SHA it conflicted with
+// (conflict_task_id, last_conflict_sha), and counts the cycle in retry_count.
 //
 // Merge Slot Integration:
 // Before creating a conflict resolution task, we acquire the merge-slot for this rig.
 // This serializes conflict resolution - only one polecat can resolve conflicts at a time.
 // If the slot is already held, we skip creating the task and let the MR stay in queue.
 // When the current resolution completes and merges, the slot is released.
-func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult) (string, error) { // result unused but kept for future merge diagnostics
+func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, result ProcessResult) (string, error) {
 	// === MERGE SLOT GATE: Serialize conflict resolution ===
 	// Ensure merge slot exists (idempotent)
 	slotID, err := e.beads.MergeSlotEnsureExists()
@@ -688,7 +1518,7 @@ func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult
 	}
 
 	// Get the current main SHA for conflict tracking
-	mainSHA, err := e.git.Rev("origin/" + mr.Target)
+	mainSHA, err := e.git.Rev(e.remote + "/" + mr.Target)
 	if err != nil {
 		mainSHA = "unknown-sha"
 	}
@@ -711,8 +1541,41 @@ func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult
 	// Increment retry count for tracking
 	retryCount := mr.RetryCount + 1
 
-	// Build the task description with metadata
-	description := fmt.Sprintf(`Resolve merge conflicts for branch %s
+	// Create the conflict resolution task
+	taskTitle := fmt.Sprintf("Resolve merge conflicts: %s", originalTitle)
+	task, err := e.beads.Create(beads.CreateOptions{
+		Title:       taskTitle,
+		Type:        "conflict-resolution",
+		Priority:    boostedPriority,
+		Description: conflictTaskDescription(mr, mainSHA, retryCount, result),
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating conflict resolution task: %w", err)
+	}
+
+	// The conflict task's ID is returned so the MR can be blocked on it.
+	// When the task closes, the MR unblocks and re-enters the ready queue.
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Created conflict resolution task: %s (P%d)\n", task.ID, task.Priority)
+
+	if err := e.recordConflict(mr.ID, task.ID, mainSHA, retryCount); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+
+	return task.ID, nil
+}
+
+// conflictTaskDescription builds the description of a conflict-resolution
+// task: where the conflict is, how to resolve it, and the conflicting files
+// and hunks from the failed merge.
+func conflictTaskDescription(mr *MRInfo, mainSHA string, retryCount int, result ProcessResult) string {
+	if len(mainSHA) > 8 {
+		mainSHA = mainSHA[:8]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Resolve merge conflicts for branch %s
 
 ## Metadata
 - Original MR: %s
@@ -733,32 +1596,69 @@ The Refinery will automatically retry the merge after you force-push.`,
 		mr.Branch,
 		mr.ID,
 		mr.Branch,
-		mr.Target, mainSHA[:8],
+		mr.Target, mainSHA,
 		mr.SourceIssue,
 		retryCount,
 		mr.Branch,
 		mr.Target,
 	)
 
-	// Create the conflict resolution task
-	taskTitle := fmt.Sprintf("Resolve merge conflicts: %s", originalTitle)
-	task, err := e.beads.Create(beads.CreateOptions{
-		Title:       taskTitle,
-		Type:        "task",
-		Priority:    boostedPriority,
-		Description: description,
-		Actor:       e.rig.Name + "/refinery",
-	})
-	if err != nil {
-		return "", fmt.Errorf("creating conflict resolution task: %w", err)
+	if len(result.ConflictFiles) > 0 {
+		b.WriteString("\n\n## Conflicting Files\n")
+		for _, file := range result.ConflictFiles {
+			fmt.Fprintf(&b, "- %s\n", file)
+		}
+	}
+	if result.ConflictDiff != "" {
+		// Fence with more backticks than the hunks contain
+		fence := "```"
+		for strings.Contains(result.ConflictDiff, fence) {
+			fence += "`"
+		}
+		fmt.Fprintf(&b, "\n## Conflicts\nAs they stood when the merge onto %s failed:\n\n%sdiff\n%s\n%s\n",
+			mr.Target, fence, result.ConflictDiff, fence)
 	}
+	return strings.TrimRight(b.String(), "\n")
+}
 
-	// The conflict task's ID is returned so the MR can be blocked on it.
-	// When the task closes, the MR unblocks and re-enters the ready queue.
+// recordConflict links an MR to the conflict-resolution task created for
+// it, with the target SHA it conflicted with and its conflict cycle count.
+func (e *Engineer) recordConflict(mrID, taskID, mainSHA string, retryCount int) error {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		return fmt.Errorf("recording conflict on MR %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	fields.ConflictTaskID = taskID
+	fields.RetryCount = retryCount
+	if mainSHA != "unknown-sha" {
+		fields.LastConflictSHA = mainSHA
+	}
+	desc := beads.SetMRFields(issue, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording conflict on MR %s: %w", mrID, err)
+	}
+	return nil
+}
 
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Created conflict resolution task: %s (P%d)\n", task.ID, task.Priority)
+// dispatchConflicts reports whether the rig has merge_queue.dispatch_conflicts
+// enabled.
+func (e *Engineer) dispatchConflicts() bool {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	return err == nil && settings.MergeQueue != nil && settings.MergeQueue.DispatchConflicts
+}
 
-	return task.ID, nil
+// dispatchConflictTask slings a conflict-resolution task to a fresh polecat
+// in the rig.
+func (e *Engineer) dispatchConflictTask(taskID string) error {
+	cmd := exec.Command("gt", "sling", taskID, e.rig.Name) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = e.workDir
+	cmd.Stdout = e.output
+	cmd.Stderr = e.output
+	return cmd.Run()
 }
 
 // IsBeadOpen checks if a bead is still open (not closed).
@@ -776,6 +1676,7 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (handled by bd ready)
+// - Not sent back to the worker for changes
 // Sorted by priority (highest first).
 //
 // This queries beads for merge-request wisps.
@@ -786,6 +1687,12 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
+	// MRs still awaiting sign-off are not ready for the refinery
+	protection, err := LoadBranchProtection(e.rig.Path)
+	if err != nil {
+		return nil, fmt.Errorf("loading branch protection: %w", err)
+	}
+
 	// Convert beads issues to MRInfo
 	var mrs []*MRInfo
 	for _, issue := range issues {
@@ -805,6 +1712,11 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue
 		}
 
+		// Skip MRs waiting for the worker to resubmit
+		if StateOf(issue) == StateChangesRequested {
+			continue
+		}
+
 		// Parse convoy created_at if present
 		var convoyCreatedAt *time.Time
 		if fields.ConvoyCreatedAt != "" {
@@ -822,19 +1734,28 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		}
 
 		mr := &MRInfo{
-			ID:              issue.ID,
-			Branch:          fields.Branch,
-			Target:          fields.Target,
-			SourceIssue:     fields.SourceIssue,
-			Worker:          fields.Worker,
-			Rig:             fields.Rig,
-			Title:           issue.Title,
-			Priority:        issue.Priority,
-			AgentBead:       fields.AgentBead,
-			RetryCount:      fields.RetryCount,
-			ConvoyID:        fields.ConvoyID,
-			ConvoyCreatedAt: convoyCreatedAt,
-			CreatedAt:       createdAt,
+			ID:                 issue.ID,
+			Branch:             fields.Branch,
+			Target:             fields.Target,
+			SourceIssue:        fields.SourceIssue,
+			Worker:             fields.Worker,
+			Rig:                fields.Rig,
+			Project:            fields.Project,
+			Locks:              SplitMRList(fields.Locks),
+			Title:              issue.Title,
+			Priority:           issue.Priority,
+			AgentBead:          fields.AgentBead,
+			RetryCount:         fields.RetryCount,
+			ConvoyID:           fields.ConvoyID,
+			ConvoyCreatedAt:    convoyCreatedAt,
+			CreatedAt:          createdAt,
+			ChecksPassed:       SplitMRList(fields.ChecksPassed),
+			ApprovedBy:         SplitMRList(fields.ApprovedBy),
+			ChangesRequestedBy: SplitMRList(fields.ChangesRequestedBy),
+			Reverts:            fields.Reverts,
+		}
+		if protection.AwaitingReview(ReviewFromFields(fields)) != "" {
+			continue
 		}
 		mrs = append(mrs, mr)
 	}
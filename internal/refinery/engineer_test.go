@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -215,3 +216,66 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 		t.Error("expected DeleteMergedBranches to be true by default")
 	}
 }
+
+func TestConflictTaskDescription(t *testing.T) {
+	mr := &MRInfo{ID: "gp-mr-1", Branch: "polecat/Nux/gp-1", Target: "main", SourceIssue: "gp-1"}
+	result := ProcessResult{
+		Conflict:      true,
+		ConflictFiles: []string{"README.md", "auth/login.go"},
+		ConflictDiff:  "diff --cc README.md\n++<<<<<<< HEAD\n + # Main\n++=======\n+ # Feature\n++>>>>>>> polecat/Nux/gp-1",
+	}
+
+	desc := conflictTaskDescription(mr, "1a2b3c4d5e6f7a8b", 2, result)
+	for _, want := range []string{
+		"- Original MR: gp-mr-1",
+		"- Conflict with: main@1a2b3c4d",
+		"- Retry count: 2",
+		"git rebase origin/main",
+		"## Conflicting Files\n- README.md\n- auth/login.go",
+		"```diff\ndiff --cc README.md",
+		"++>>>>>>> polecat/Nux/gp-1\n```",
+	} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description missing %q:\n%s", want, desc)
+		}
+	}
+
+	// Without diagnostics, only the metadata and instructions
+	desc = conflictTaskDescription(mr, "unknown-sha", 1, ProcessResult{Conflict: true})
+	if strings.Contains(desc, "## Conflict") {
+		t.Errorf("description without files or hunks has a conflicts section:\n%s", desc)
+	}
+	if !strings.Contains(desc, "main@unknown-") {
+		t.Errorf("description = %q, want the short SHA", desc)
+	}
+}
+
+func TestConflictTaskDescription_FencesBackticks(t *testing.T) {
+	mr := &MRInfo{ID: "gp-mr-1", Branch: "polecat/Nux/gp-1", Target: "main"}
+	result := ProcessResult{Conflict: true, ConflictDiff: "++<<<<<<< HEAD\n ```go\n++======="}
+
+	desc := conflictTaskDescription(mr, "1a2b3c4d", 1, result)
+	if !strings.Contains(desc, "````diff\n") || !strings.HasSuffix(desc, "\n````") {
+		t.Errorf("hunks containing ``` not fenced with more backticks:\n%s", desc)
+	}
+}
+
+func TestEngineer_DispatchConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+
+	if e.dispatchConflicts() {
+		t.Error("dispatchConflicts() = true without rig settings")
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type": "rig-settings", "version": 1, "merge_queue": {"dispatch_conflicts": true}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !e.dispatchConflicts() {
+		t.Error("dispatchConflicts() = false with merge_queue.dispatch_conflicts set")
+	}
+}
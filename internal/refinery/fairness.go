@@ -0,0 +1,165 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Merge queue fairness policies.
+const (
+	FairnessPriority   = "priority"
+	FairnessRoundRobin = "round_robin"
+)
+
+// Fairness orders the merge queue so one prolific worker can't starve the
+// rest, based on the rig's merge_queue.fairness settings. A nil *Fairness
+// orders by score alone.
+type Fairness struct {
+	roundRobin  bool
+	agingHours  float64
+	maxInFlight int
+}
+
+// NewFairness builds a Fairness from config. Returns nil (score order) if
+// cfg is nil.
+func NewFairness(cfg *config.MergeFairnessConfig) (*Fairness, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	f := &Fairness{agingHours: cfg.AgingHours, maxInFlight: cfg.MaxInFlightPerWorker}
+	switch cfg.Policy {
+	case "", FairnessPriority:
+	case FairnessRoundRobin:
+		f.roundRobin = true
+	default:
+		return nil, fmt.Errorf("invalid fairness policy %q (want %q or %q)", cfg.Policy, FairnessPriority, FairnessRoundRobin)
+	}
+	if f.agingHours < 0 {
+		return nil, fmt.Errorf("invalid fairness aging_hours %v", f.agingHours)
+	}
+	if f.maxInFlight < 0 {
+		return nil, fmt.Errorf("invalid fairness max_in_flight_per_worker %d", f.maxInFlight)
+	}
+	return f, nil
+}
+
+// LoadFairness reads the fairness policy from a rig's settings/config.json.
+// A missing settings file or fairness section yields nil (score order).
+func LoadFairness(rigPath string) (*Fairness, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewFairness(settings.MergeQueue.Fairness)
+}
+
+// QueueEntry is what fairness needs to know about a queued MR.
+type QueueEntry struct {
+	Worker    string
+	Priority  int
+	CreatedAt time.Time
+	Score     float64 // from ScoreMR
+}
+
+// EffectivePriority returns priority raised one level for each AgingHours
+// the MR has waited, never above P0.
+func (f *Fairness) EffectivePriority(priority int, createdAt, now time.Time) int {
+	if f == nil || f.agingHours == 0 || createdAt.IsZero() {
+		return priority
+	}
+	boost := int(now.Sub(createdAt).Hours() / f.agingHours)
+	if boost <= 0 {
+		return priority
+	}
+	if priority-boost < 0 {
+		return 0
+	}
+	return priority - boost
+}
+
+// Order returns the indexes of entries in merge order, and separately the
+// indexes held back because their worker already has MaxInFlightPerWorker
+// MRs claimed. inFlight counts claimed MRs by worker (see InFlightByWorker).
+//
+// With the priority policy, entries are ordered by score, with aged MRs
+// scored at their effective priority. With round_robin, each priority
+// level is served one MR per worker at a time, best score first; workers
+// with MRs already in flight take their turn after the others.
+func (f *Fairness) Order(entries []QueueEntry, inFlight map[string]int, now time.Time) (order, held []int) {
+	type ranked struct {
+		index    int
+		priority int
+		score    float64
+		round    int
+	}
+	items := make([]ranked, len(entries))
+	for i, e := range entries {
+		priority := f.EffectivePriority(e.Priority, e.CreatedAt, now)
+		score := e.Score
+		if priority < e.Priority {
+			score += DefaultScoreConfig().PriorityWeight * float64(e.Priority-priority)
+		}
+		items[i] = ranked{index: i, priority: priority, score: score}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].score > items[j].score
+	})
+
+	if f != nil && f.roundRobin {
+		// Each worker's n-th best MR in a priority level plays in round n,
+		// after any MRs it already has in flight
+		taken := make(map[string]int)
+		for i := range items {
+			e := entries[items[i].index]
+			key := fmt.Sprintf("%d/%s", items[i].priority, e.Worker)
+			items[i].round = inFlight[e.Worker] + taken[key]
+			taken[key]++
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].priority != items[j].priority {
+				return items[i].priority < items[j].priority
+			}
+			return items[i].round < items[j].round
+		})
+	}
+
+	claimable := make(map[string]int)
+	for _, item := range items {
+		worker := entries[item.index].Worker
+		if f != nil && f.maxInFlight > 0 && worker != "" {
+			if inFlight[worker]+claimable[worker] >= f.maxInFlight {
+				held = append(held, item.index)
+				continue
+			}
+			claimable[worker]++
+		}
+		order = append(order, item.index)
+	}
+	return order, held
+}
+
+// InFlightByWorker counts the MRs among issues that the refinery has
+// claimed (in progress, or open with an assignee), by worker.
+func InFlightByWorker(issues []*beads.Issue) map[string]int {
+	inFlight := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Status != "in_progress" && (issue.Status != "open" || issue.Assignee == "") {
+			continue
+		}
+		if fields := beads.ParseMRFields(issue); fields != nil && fields.Worker != "" {
+			inFlight[fields.Worker]++
+		}
+	}
+	return inFlight
+}
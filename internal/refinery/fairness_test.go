@@ -0,0 +1,138 @@
+package refinery
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// queueOrder renders the entries at indexes as "worker:score" for comparison.
+func queueOrder(entries []QueueEntry, indexes []int) string {
+	var out []string
+	for _, i := range indexes {
+		out = append(out, fmt.Sprintf("%s:%.0f", entries[i].Worker, entries[i].Score))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestNewFairness(t *testing.T) {
+	if f, err := NewFairness(nil); f != nil || err != nil {
+		t.Errorf("NewFairness(nil) = %v, %v; want nil, nil", f, err)
+	}
+	for _, cfg := range []config.MergeFairnessConfig{
+		{Policy: "lottery"},
+		{AgingHours: -1},
+		{MaxInFlightPerWorker: -2},
+	} {
+		if _, err := NewFairness(&cfg); err == nil {
+			t.Errorf("NewFairness(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestFairnessOrder(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	// A prolific worker with the top three scores at equal priority
+	entries := []QueueEntry{
+		{Worker: "toast", Priority: 2, Score: 1209},
+		{Worker: "toast", Priority: 2, Score: 1208},
+		{Worker: "toast", Priority: 2, Score: 1207},
+		{Worker: "nux", Priority: 2, Score: 1203},
+		{Worker: "furiosa", Priority: 2, Score: 1202},
+		{Worker: "nux", Priority: 1, Score: 1301},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       *config.MergeFairnessConfig
+		inFlight  map[string]int
+		wantOrder string
+		wantHeld  string
+	}{
+		{
+			name:      "no policy keeps score order",
+			wantOrder: "nux:1301,toast:1209,toast:1208,toast:1207,nux:1203,furiosa:1202",
+		},
+		{
+			name:      "round robin within each priority",
+			cfg:       &config.MergeFairnessConfig{Policy: FairnessRoundRobin},
+			wantOrder: "nux:1301,toast:1209,nux:1203,furiosa:1202,toast:1208,toast:1207",
+		},
+		{
+			name:      "workers with MRs in flight go last",
+			cfg:       &config.MergeFairnessConfig{Policy: FairnessRoundRobin},
+			inFlight:  map[string]int{"toast": 1},
+			wantOrder: "nux:1301,nux:1203,furiosa:1202,toast:1209,toast:1208,toast:1207",
+		},
+		{
+			name:      "in-flight limit holds the rest",
+			cfg:       &config.MergeFairnessConfig{MaxInFlightPerWorker: 1},
+			inFlight:  map[string]int{"furiosa": 1},
+			wantOrder: "nux:1301,toast:1209",
+			wantHeld:  "toast:1208,toast:1207,nux:1203,furiosa:1202",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFairness(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			order, held := f.Order(entries, tt.inFlight, now)
+			if got := queueOrder(entries, order); got != tt.wantOrder {
+				t.Errorf("order = %s\n want %s", got, tt.wantOrder)
+			}
+			if got := queueOrder(entries, held); got != tt.wantHeld {
+				t.Errorf("held = %s\n want %s", got, tt.wantHeld)
+			}
+		})
+	}
+}
+
+func TestFairnessAging(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	f, err := NewFairness(&config.MergeFairnessConfig{Policy: FairnessRoundRobin, AgingHours: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.EffectivePriority(3, now.Add(-9*time.Hour), now); got != 1 {
+		t.Errorf("P3 after 9h with 4h aging = P%d, want P1", got)
+	}
+	if got := f.EffectivePriority(1, now.Add(-24*time.Hour), now); got != 0 {
+		t.Errorf("aging should stop at P0, got P%d", got)
+	}
+	if got := (*Fairness)(nil).EffectivePriority(3, now.Add(-24*time.Hour), now); got != 3 {
+		t.Errorf("nil fairness should not age, got P%d", got)
+	}
+
+	// An old P3 overtakes a fresh P2
+	entries := []QueueEntry{
+		{Worker: "nux", Priority: 2, Score: 1200, CreatedAt: now.Add(-time.Hour)},
+		{Worker: "toast", Priority: 3, Score: 1105, CreatedAt: now.Add(-5 * time.Hour)},
+	}
+	order, _ := f.Order(entries, nil, now)
+	if order[0] != 1 {
+		t.Errorf("order = %v, want the aged P3 first", order)
+	}
+}
+
+func TestInFlightByWorker(t *testing.T) {
+	mr := func(status, assignee, worker string) *beads.Issue {
+		return &beads.Issue{Status: status, Assignee: assignee, Description: "branch: polecat/x\nworker: " + worker}
+	}
+	got := InFlightByWorker([]*beads.Issue{
+		mr("in_progress", "", "toast"),
+		mr("open", "greenplace/refinery", "toast"),
+		mr("open", "", "toast"),
+		mr("closed", "greenplace/refinery", "nux"),
+		mr("in_progress", "", "nux"),
+	})
+	if got["toast"] != 2 || got["nux"] != 1 {
+		t.Errorf("InFlightByWorker = %v, want toast:2 nux:1", got)
+	}
+}
@@ -0,0 +1,374 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Check names the refinery runs and tracks flakes for.
+const (
+	CheckTests    = "tests"    // merge_queue.test_command
+	CheckVerify   = "verify"   // transactional verify_command
+	CheckCoverage = "coverage" // merge_queue.coverage.command
+	CheckLint     = "lint"     // merge_queue.lint commands
+)
+
+// Flaky check defaults, for settings that leave them unset.
+const (
+	DefaultFlakeThreshold  = 0.05
+	DefaultFlakeMaxRetries = 2
+	DefaultFlakeMinRuns    = 10
+	DefaultFlakeWindow     = 200
+)
+
+// Outcomes of a check run.
+const (
+	RunPassed = "passed"
+	RunFailed = "failed"
+	RunFlaky  = "flaky" // Failed, then passed on retry
+)
+
+// CheckCommand runs a check's shell command, retrying failures.
+type CheckCommand struct {
+	Command  string
+	Dir      string
+	Attempts int          // Total tries (at least 1)
+	Output   io.Writer    // Passes the output through, if set
+	Env      []string     // The command's environment (nil = this process's)
+	Runner   *CheckRunner // Where the command runs (nil = the host's shell)
+
+	// Retrying, if set, is called before each retry.
+	Retrying func(attempt, attempts int)
+}
+
+// CheckRun is the outcome of running a check.
+type CheckRun struct {
+	Err        error         // The last attempt's error; nil if it passed
+	Output     string        // The last attempt's output
+	Tests      *TestResults  // Parsed from the last attempt's output
+	FlakyTests []string      // Tests that failed before a retry passed
+	Duration   time.Duration // How long every attempt took together
+}
+
+// Outcome returns RunPassed, RunFailed or RunFlaky.
+func (r CheckRun) Outcome() string {
+	switch {
+	case r.Err != nil:
+		return RunFailed
+	case r.Tests.Flaky:
+		return RunFlaky
+	default:
+		return RunPassed
+	}
+}
+
+// Run runs the check until it passes or its attempts are used up. A
+// command that can't be started, or a canceled ctx, isn't retried.
+func (c CheckCommand) Run(ctx context.Context) CheckRun {
+	attempts := c.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var run CheckRun
+	var failing []string
+	began := time.Now()
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 && c.Retrying != nil {
+			c.Retrying(attempt, attempts)
+		}
+
+		cmd, err := c.Runner.command(ctx, c.Command, c.Dir, c.Env)
+		if err != nil {
+			run = CheckRun{Err: err, Tests: ParseTestOutput("", false, 0)}
+			run.Tests.Attempts = attempt
+			break
+		}
+		var out bytes.Buffer
+		var w io.Writer = &out
+		if c.Output != nil {
+			w = io.MultiWriter(c.Output, &out)
+		}
+		cmd.Stdout = w
+		cmd.Stderr = w
+
+		start := time.Now()
+		err = cmd.Run()
+		run = CheckRun{Err: err, Output: out.String(),
+			Tests: ParseTestOutput(out.String(), err == nil, time.Since(start))}
+		run.Tests.Attempts = attempt
+		if err == nil {
+			break
+		}
+		failing = append(failing, run.Tests.Failing...)
+		var exitErr *exec.ExitError
+		if ctx.Err() != nil || !errors.As(err, &exitErr) {
+			break
+		}
+	}
+
+	run.Duration = time.Since(began)
+
+	if run.Err == nil && run.Tests.Attempts > 1 {
+		run.Tests.Flaky = true
+		seen := make(map[string]bool)
+		for _, name := range failing {
+			if !seen[name] {
+				seen[name] = true
+				run.FlakyTests = append(run.FlakyTests, name)
+			}
+		}
+	}
+	return run
+}
+
+// CheckRecord is one run of a check in a rig's check history.
+type CheckRecord struct {
+	Check      string        `json:"check"`
+	Source     string        `json:"source,omitempty"` // MR ID or branch checked
+	Outcome    string        `json:"outcome"`          // RunPassed, RunFailed, RunFlaky
+	Attempts   int           `json:"attempts"`
+	FlakyTests []string      `json:"flaky_tests,omitempty"`
+	Duration   time.Duration `json:"duration,omitempty"` // Every attempt together
+	At         time.Time     `json:"at"`
+}
+
+// FlakeHistory tracks a rig's check runs, to tell flaky checks from
+// failing ones. A nil *FlakeHistory never retries and records nothing.
+type FlakeHistory struct {
+	threshold  float64
+	maxRetries int
+	minRuns    int
+	window     int
+	path       string // under .runtime/
+}
+
+// NewFlakeHistory builds a rig's FlakeHistory from config, filling in
+// defaults for unset settings.
+func NewFlakeHistory(rigPath string, cfg *config.FlakyChecksConfig) (*FlakeHistory, error) {
+	h := &FlakeHistory{
+		threshold:  DefaultFlakeThreshold,
+		maxRetries: DefaultFlakeMaxRetries,
+		minRuns:    DefaultFlakeMinRuns,
+		window:     DefaultFlakeWindow,
+		path:       filepath.Join(rigPath, ".runtime", "check-history.json"),
+	}
+	if cfg == nil {
+		return h, nil
+	}
+	if cfg.Threshold < 0 || cfg.Threshold > 1 {
+		return nil, fmt.Errorf("merge_queue.flaky.threshold %v: must be between 0 and 1", cfg.Threshold)
+	}
+	if cfg.MinRuns < 0 || cfg.Window < 0 {
+		return nil, fmt.Errorf("merge_queue.flaky: min_runs and window can't be negative")
+	}
+	if cfg.Threshold > 0 {
+		h.threshold = cfg.Threshold
+	}
+	switch {
+	case cfg.MaxRetries < 0:
+		h.maxRetries = 0
+	case cfg.MaxRetries > 0:
+		h.maxRetries = cfg.MaxRetries
+	}
+	if cfg.MinRuns > 0 {
+		h.minRuns = cfg.MinRuns
+	}
+	if cfg.Window > 0 {
+		h.window = cfg.Window
+	}
+	return h, nil
+}
+
+// LoadFlakeHistory reads a rig's flaky check settings from its
+// settings/config.json; the history itself is read as needed.
+func LoadFlakeHistory(rigPath string) (*FlakeHistory, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+	var cfg *config.FlakyChecksConfig
+	if settings != nil && settings.MergeQueue != nil {
+		cfg = settings.MergeQueue.Flaky
+	}
+	return NewFlakeHistory(rigPath, cfg)
+}
+
+// Retries returns how many times a failing run of check should be
+// retried: MaxRetries if its flake rate is at least the threshold, once
+// if it has fewer than MinRuns runs, otherwise none.
+func (h *FlakeHistory) Retries(check string) int {
+	if h == nil || h.maxRetries == 0 {
+		return 0
+	}
+	stats := h.statsFor(check)
+	switch {
+	case stats.Runs >= h.minRuns && stats.FlakeRate >= h.threshold:
+		return h.maxRetries
+	case stats.Runs < h.minRuns:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Record adds a run of check to the history, keeping the most recent
+// Window runs per check.
+func (h *FlakeHistory) Record(check, source string, run CheckRun) error {
+	if h == nil {
+		return nil
+	}
+	records := h.load()
+	records = append(records, CheckRecord{
+		Check:      check,
+		Source:     source,
+		Outcome:    run.Outcome(),
+		Attempts:   run.Tests.Attempts,
+		FlakyTests: run.FlakyTests,
+		Duration:   run.Duration,
+		At:         time.Now().UTC(),
+	})
+
+	// Drop the oldest runs of this check past the window
+	kept := 0
+	for _, r := range records {
+		if r.Check == check {
+			kept++
+		}
+	}
+	trimmed := records[:0]
+	for _, r := range records {
+		if r.Check == check && kept > h.window {
+			kept--
+			continue
+		}
+		trimmed = append(trimmed, r)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("saving check history: %w", err)
+	}
+	if err := util.AtomicWriteJSON(h.path, trimmed); err != nil {
+		return fmt.Errorf("saving check history: %w", err)
+	}
+	return nil
+}
+
+// FlakyTest counts a test's flakes.
+type FlakyTest struct {
+	Name   string `json:"name"`
+	Flakes int    `json:"flakes"`
+}
+
+// CheckStats summarizes a check's recent runs.
+type CheckStats struct {
+	Check     string      `json:"check"`
+	Runs      int         `json:"runs"`
+	Failed    int         `json:"failed"`
+	Flaky     int         `json:"flaky"`
+	FlakeRate float64     `json:"flake_rate"` // Flaky / Runs
+	LastFlake *time.Time  `json:"last_flake,omitempty"`
+	Tests     []FlakyTest `json:"tests,omitempty"` // Flakiest tests first
+}
+
+// Stats summarizes every check in the history, flakiest first.
+func (h *FlakeHistory) Stats() []CheckStats {
+	if h == nil {
+		return nil
+	}
+	byCheck := make(map[string]*CheckStats)
+	testFlakes := make(map[string]map[string]int)
+	for _, r := range h.load() {
+		s := byCheck[r.Check]
+		if s == nil {
+			s = &CheckStats{Check: r.Check}
+			byCheck[r.Check] = s
+			testFlakes[r.Check] = make(map[string]int)
+		}
+		s.Runs++
+		switch r.Outcome {
+		case RunFailed:
+			s.Failed++
+		case RunFlaky:
+			s.Flaky++
+			at := r.At
+			s.LastFlake = &at
+			for _, name := range r.FlakyTests {
+				testFlakes[r.Check][name]++
+			}
+		}
+	}
+
+	stats := make([]CheckStats, 0, len(byCheck))
+	for check, s := range byCheck {
+		s.FlakeRate = float64(s.Flaky) / float64(s.Runs)
+		for name, n := range testFlakes[check] {
+			s.Tests = append(s.Tests, FlakyTest{Name: name, Flakes: n})
+		}
+		sort.Slice(s.Tests, func(i, j int) bool {
+			if s.Tests[i].Flakes != s.Tests[j].Flakes {
+				return s.Tests[i].Flakes > s.Tests[j].Flakes
+			}
+			return s.Tests[i].Name < s.Tests[j].Name
+		})
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].FlakeRate != stats[j].FlakeRate {
+			return stats[i].FlakeRate > stats[j].FlakeRate
+		}
+		return stats[i].Check < stats[j].Check
+	})
+	return stats
+}
+
+// Durations returns how long each check in the history typically takes:
+// the median of its recorded runs, retries included. Checks recorded
+// before durations were kept are left out.
+func (h *FlakeHistory) Durations() map[string]time.Duration {
+	if h == nil {
+		return nil
+	}
+	byCheck := make(map[string][]time.Duration)
+	for _, r := range h.load() {
+		if r.Duration > 0 {
+			byCheck[r.Check] = append(byCheck[r.Check], r.Duration)
+		}
+	}
+	durations := make(map[string]time.Duration, len(byCheck))
+	for check, ds := range byCheck {
+		durations[check] = MedianDuration(ds)
+	}
+	return durations
+}
+
+func (h *FlakeHistory) statsFor(check string) CheckStats {
+	for _, s := range h.Stats() {
+		if s.Check == check {
+			return s
+		}
+	}
+	return CheckStats{Check: check}
+}
+
+func (h *FlakeHistory) load() []CheckRecord {
+	var records []CheckRecord
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(data, &records) // corrupt history starts over
+	return records
+}
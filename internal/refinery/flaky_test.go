@@ -0,0 +1,200 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCheckCommandRetriesFlakyFailure(t *testing.T) {
+	dir := t.TempDir()
+	// Fails the first time it runs, then passes
+	script := `if [ -f ran ]; then echo "--- PASS: TestRace (0.00s)"; echo "ok  	example.com/app	0.01s"; exit 0; fi
+touch ran
+echo "--- FAIL: TestRace (0.00s)"
+echo "FAIL	example.com/app	0.01s"
+exit 1`
+
+	var retries []int
+	run := CheckCommand{
+		Command:  script,
+		Dir:      dir,
+		Attempts: 3,
+		Retrying: func(attempt, attempts int) { retries = append(retries, attempt) },
+	}.Run(context.Background())
+
+	if run.Err != nil {
+		t.Fatalf("run failed: %v", run.Err)
+	}
+	if run.Outcome() != RunFlaky || !run.Tests.Flaky || run.Tests.Attempts != 2 {
+		t.Errorf("outcome %s, flaky %v, attempts %d; want flaky after 2", run.Outcome(), run.Tests.Flaky, run.Tests.Attempts)
+	}
+	if len(retries) != 1 || retries[0] != 2 {
+		t.Errorf("retries = %v, want [2]", retries)
+	}
+	if strings.Join(run.FlakyTests, "|") != "example.com/app.TestRace" {
+		t.Errorf("flaky tests = %q", run.FlakyTests)
+	}
+	if !strings.HasSuffix(run.Tests.String(), "passed after retry (flaky)") {
+		t.Errorf("String() = %q", run.Tests.String())
+	}
+}
+
+func TestCheckCommandGivesUp(t *testing.T) {
+	run := CheckCommand{Command: "exit 1", Dir: t.TempDir(), Attempts: 2}.Run(context.Background())
+	if run.Outcome() != RunFailed || run.Tests.Attempts != 2 || run.Tests.Flaky {
+		t.Errorf("outcome %s, attempts %d, flaky %v; want failed after 2", run.Outcome(), run.Tests.Attempts, run.Tests.Flaky)
+	}
+
+	run = CheckCommand{Command: "true", Dir: t.TempDir(), Attempts: 0}.Run(context.Background())
+	if run.Outcome() != RunPassed || run.Tests.Attempts != 1 {
+		t.Errorf("outcome %s, attempts %d; want passed after 1", run.Outcome(), run.Tests.Attempts)
+	}
+}
+
+func recordRuns(t *testing.T, h *FlakeHistory, check, outcome string, n int) {
+	t.Helper()
+	run := CheckRun{Tests: &TestResults{OK: true, Attempts: 1}}
+	switch outcome {
+	case RunFailed:
+		run.Err = context.Canceled
+	case RunFlaky:
+		run.Tests.Attempts, run.Tests.Flaky = 2, true
+		run.FlakyTests = []string{"TestRace"}
+	}
+	for i := 0; i < n; i++ {
+		if err := h.Record(check, "gp-mr-1", run); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFlakeHistoryRetries(t *testing.T) {
+	var none *FlakeHistory
+	if none.Retries(CheckTests) != 0 {
+		t.Error("nil history should never retry")
+	}
+
+	h, err := NewFlakeHistory(t.TempDir(), &config.FlakyChecksConfig{Threshold: 0.2, MaxRetries: 3, MinRuns: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Too few runs to judge: one probe retry
+	if got := h.Retries(CheckTests); got != 1 {
+		t.Errorf("new check: Retries = %d, want 1", got)
+	}
+
+	recordRuns(t, h, CheckTests, RunPassed, 9)
+	if got := h.Retries(CheckTests); got != 0 {
+		t.Errorf("steady check: Retries = %d, want 0", got)
+	}
+
+	recordRuns(t, h, CheckTests, RunFlaky, 3) // 3/12 flaky
+	if got := h.Retries(CheckTests); got != 3 {
+		t.Errorf("flaky check: Retries = %d, want 3", got)
+	}
+	if got := h.Retries(CheckVerify); got != 1 {
+		t.Errorf("other check: Retries = %d, want 1", got)
+	}
+
+	never, err := NewFlakeHistory(t.TempDir(), &config.FlakyChecksConfig{MaxRetries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := never.Retries(CheckTests); got != 0 {
+		t.Errorf("max_retries -1: Retries = %d, want 0", got)
+	}
+}
+
+func TestNewFlakeHistoryRejectsBadSettings(t *testing.T) {
+	for _, cfg := range []*config.FlakyChecksConfig{{Threshold: 1.5}, {Threshold: -0.1}, {Window: -1}} {
+		if _, err := NewFlakeHistory(t.TempDir(), cfg); err == nil {
+			t.Errorf("%+v: expected error", *cfg)
+		}
+	}
+}
+
+func TestFlakeHistoryStats(t *testing.T) {
+	rigPath := t.TempDir()
+	h, err := NewFlakeHistory(rigPath, &config.FlakyChecksConfig{Window: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordRuns(t, h, CheckVerify, RunFailed, 2)
+	recordRuns(t, h, CheckTests, RunFlaky, 2)
+	recordRuns(t, h, CheckTests, RunPassed, 3) // Pushes one flaky run out of the window
+
+	stats := h.Stats()
+	if len(stats) != 2 || stats[0].Check != CheckTests || stats[1].Check != CheckVerify {
+		t.Fatalf("stats = %+v, want tests then verify", stats)
+	}
+	tests := stats[0]
+	if tests.Runs != 4 || tests.Flaky != 1 || tests.FlakeRate != 0.25 || tests.LastFlake == nil {
+		t.Errorf("tests stats = %+v", tests)
+	}
+	if len(tests.Tests) != 1 || tests.Tests[0] != (FlakyTest{Name: "TestRace", Flakes: 1}) {
+		t.Errorf("flaky tests = %+v", tests.Tests)
+	}
+	if stats[1].Runs != 2 || stats[1].Failed != 2 || stats[1].FlakeRate != 0 {
+		t.Errorf("verify stats = %+v", stats[1])
+	}
+
+	// The history persists under the rig's .runtime/
+	loaded, err := LoadFlakeHistory(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Stats(); len(got) != 2 {
+		t.Errorf("reloaded %d checks, want 2", len(got))
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, ".runtime", "check-history.json")); err != nil {
+		t.Errorf("history file: %v", err)
+	}
+}
+
+func TestFlakeHistoryDurations(t *testing.T) {
+	h, err := NewFlakeHistory(t.TempDir(), &config.FlakyChecksConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordRuns(t, h, CheckVerify, RunPassed, 1) // No duration recorded
+	for _, d := range []time.Duration{3, 1, 2} {
+		run := CheckRun{Tests: &TestResults{OK: true, Attempts: 1}, Duration: d * time.Minute}
+		if err := h.Record(CheckTests, "gp-mr-1", run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := h.Durations()
+	if len(got) != 1 || got[CheckTests] != 2*time.Minute {
+		t.Errorf("Durations() = %v, want tests: 2m", got)
+	}
+}
+
+func TestApplyNotesFlakyChecks(t *testing.T) {
+	fields := &beads.MRFields{}
+	flaky := &TestResults{OK: true, Attempts: 2, Flaky: true}
+	flaky.Apply(fields, CheckTests)
+	flaky.Apply(fields, CheckVerify)
+	flaky.Apply(fields, CheckTests)
+	if fields.FlakyChecks != "tests,verify" {
+		t.Errorf("flaky_checks = %q, want tests,verify", fields.FlakyChecks)
+	}
+	if fields.TestResults != "ok duration=0s attempts=2 flaky" {
+		t.Errorf("test_results = %q", fields.TestResults)
+	}
+
+	out := TestResultsFromFields(beads.ParseMRFields(&beads.Issue{Description: beads.FormatMRFields(fields)}))
+	if !out.Flaky || out.Attempts != 2 {
+		t.Errorf("round trip = %+v", out)
+	}
+	if got := beads.ParseMRFields(&beads.Issue{Description: beads.FormatMRFields(fields)}).FlakyChecks; got != "tests,verify" {
+		t.Errorf("flaky_checks round trip = %q", got)
+	}
+}
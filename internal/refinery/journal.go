@@ -0,0 +1,277 @@
+package refinery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Steps of a merge, in the order the refinery journals them.
+const (
+	StepStarted = "started" // Merging; nothing has landed
+	StepMerged  = "merged"  // The merge commit is made, maybe not yet pushed
+	StepPushed  = "pushed"  // Landed; the MR bead may not be updated yet
+)
+
+// What recovering an interrupted merge did.
+const (
+	RecoveryCompleted  = "completed"   // It had landed: the MR was recorded as merged
+	RecoveryRolledBack = "rolled back" // It hadn't: the MR was requeued
+)
+
+// JournalEntry is the merge the refinery has in flight. It is written
+// ahead of each step, so a merge interrupted by a crash can be completed
+// or rolled back.
+type JournalEntry struct {
+	MR          string    `json:"mr"`
+	Branch      string    `json:"branch"`
+	Target      string    `json:"target"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Step        string    `json:"step"`                   // StepStarted, StepMerged, StepPushed
+	Worktree    string    `json:"worktree,omitempty"`     // The temporary merge worktree (mirror rigs)
+	TargetWas   string    `json:"target_was,omitempty"`   // The local target before merging in the refinery's clone
+	MergeCommit string    `json:"merge_commit,omitempty"` // Once merged
+	PID         int       `json:"pid"`                    // The process merging
+	Session     string    `json:"session,omitempty"`      // The refinery session merging, for merges made across gt commands
+	Started     time.Time `json:"started"`
+	Updated     time.Time `json:"updated"`
+}
+
+// Interrupted reports whether the process merging has died without
+// finishing. A merge the refinery agent makes with gt mq state and gt mq
+// land outlives each gt process, so it is interrupted once the refinery's
+// session is gone.
+func (j *JournalEntry) Interrupted() bool {
+	if j.Session != "" {
+		running, _ := tmux.NewTmux().HasSession(j.Session)
+		return !running
+	}
+	return j.PID != os.Getpid() && !processExists(j.PID)
+}
+
+// Journal is a rig refinery's write-ahead journal of its in-flight merge.
+// A nil *Journal records nothing.
+type Journal struct {
+	path string // under .runtime/
+}
+
+// OpenJournal returns a rig's refinery journal.
+func OpenJournal(rigPath string) *Journal {
+	return &Journal{path: filepath.Join(rigPath, ".runtime", "refinery-journal.json")}
+}
+
+// Begin journals the start of a merge, replacing any earlier entry.
+func (j *Journal) Begin(entry JournalEntry) error {
+	if j == nil {
+		return nil
+	}
+	entry.Step = StepStarted
+	entry.PID = os.Getpid()
+	entry.Started = time.Now().UTC()
+	return j.write(&entry)
+}
+
+// Record journals a step of the merge of mrID, after update fills in
+// what the step learned (nil if nothing). An entry for another MR is
+// left alone.
+func (j *Journal) Record(mrID, step string, update func(*JournalEntry)) error {
+	entry, err := j.Pending()
+	if err != nil || entry == nil || entry.MR != mrID {
+		return err
+	}
+	entry.Step = step
+	if update != nil {
+		update(entry)
+	}
+	return j.write(entry)
+}
+
+// Finish clears the journal once the merge of mrID is over, landed and
+// recorded or failed and cleaned up.
+func (j *Journal) Finish(mrID string) error {
+	entry, err := j.Pending()
+	if err != nil || entry == nil || entry.MR != mrID {
+		return err
+	}
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing refinery journal: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the journaled merge, or nil if there is none.
+func (j *Journal) Pending() (*JournalEntry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading refinery journal: %w", err)
+	}
+	var entry JournalEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("reading refinery journal %s: %w", j.path, err)
+	}
+	return &entry, nil
+}
+
+func (j *Journal) write(entry *JournalEntry) error {
+	entry.Updated = time.Now().UTC()
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("writing refinery journal: %w", err)
+	}
+	if err := util.AtomicWriteJSON(j.path, entry); err != nil {
+		return fmt.Errorf("writing refinery journal: %w", err)
+	}
+	return nil
+}
+
+// BeginLanding journals the start of a merge the refinery agent makes itself
+// and lands with Land (the patrol's merging step, gt mq state ... merging).
+// session is the refinery's session, which outlives the gt commands making
+// the merge; the local target is recorded so a merge that never landed can
+// be rolled back.
+func (e *Engineer) BeginLanding(mrID, branch, target, sourceIssue, session string) error {
+	entry := JournalEntry{MR: mrID, Branch: branch, Target: target, SourceIssue: sourceIssue, Session: session}
+	if targetWas, err := e.git.Rev(target); err == nil {
+		entry.TargetWas = targetWas
+	}
+	return OpenJournal(e.rig.Path).Begin(entry)
+}
+
+// journal records a step of a merge, warning if it can't.
+func (e *Engineer) journal(mrID, step string, update func(*JournalEntry)) {
+	if err := OpenJournal(e.rig.Path).Record(mrID, step, update); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+}
+
+// finishJournal clears the journal once a merge is over, warning if it can't.
+func (e *Engineer) finishJournal(mrID string) {
+	if err := OpenJournal(e.rig.Path).Finish(mrID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+}
+
+// Recovery describes an interrupted merge the refinery recovered.
+type Recovery struct {
+	Entry  JournalEntry `json:"entry"`
+	Action string       `json:"action"` // RecoveryCompleted or RecoveryRolledBack
+}
+
+// ErrMergeInProgress is returned by Recover while the journaled merge's
+// process is still running.
+var ErrMergeInProgress = errors.New("a merge is in progress")
+
+// Recover finishes a merge that was interrupted, e.g. by the refinery
+// crashing. A merge that landed (pushed, or found on the push remote's
+// target) is completed: the MR is recorded as merged and its source issue
+// closed. One that didn't is rolled back: a leftover merge worktree is
+// removed, the refinery's local target reset, and the MR requeued.
+// Returns nil if there was nothing to recover.
+func (e *Engineer) Recover() (*Recovery, error) {
+	j := OpenJournal(e.rig.Path)
+	entry, err := j.Pending()
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if !entry.Interrupted() {
+		return nil, fmt.Errorf("%w: %s (PID %d)", ErrMergeInProgress, entry.MR, entry.PID)
+	}
+
+	landed, err := e.mergeLanded(entry)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Recovery{Entry: *entry, Action: RecoveryRolledBack}
+	if landed {
+		rec.Action = RecoveryCompleted
+		err = e.completeInterrupted(entry)
+	} else {
+		err = e.rollBackInterrupted(entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := j.Finish(entry.MR); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// mergeLanded reports whether a journaled merge reached the push remote.
+func (e *Engineer) mergeLanded(entry *JournalEntry) (bool, error) {
+	if entry.Step == StepPushed {
+		return true, nil
+	}
+	if entry.MergeCommit == "" {
+		return false, nil
+	}
+	if err := e.git.FetchBranch(e.remote, entry.Target); err != nil {
+		return false, fmt.Errorf("can't tell whether %s landed: fetching %s/%s: %w", entry.MR, e.remote, entry.Target, err)
+	}
+	// A merge commit that never left its worktree isn't in the clone at all
+	landed, err := e.git.IsAncestor(entry.MergeCommit, e.remote+"/"+entry.Target)
+	return err == nil && landed, nil
+}
+
+// completeInterrupted records a landed merge as handleSuccess would have.
+func (e *Engineer) completeInterrupted(entry *JournalEntry) error {
+	mr, err := e.beads.Show(entry.MR)
+	if err != nil {
+		return fmt.Errorf("fetching MR %s: %w", entry.MR, err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Completing interrupted merge of %s (%s landed on %s)\n",
+		entry.MR, shortCommit(entry.MergeCommit), entry.Target)
+	e.fastForwardTarget(entry.Target, entry.MergeCommit)
+	e.mirrorTarget(entry.Target, entry.MergeCommit)
+	if mr.Status != string(MRClosed) {
+		e.handleSuccess(mr, ProcessResult{Success: true, MergeCommit: entry.MergeCommit})
+	}
+	e.submitBackports(entry.MR)
+	return nil
+}
+
+// rollBackInterrupted undoes what a merge that didn't land left behind.
+func (e *Engineer) rollBackInterrupted(entry *JournalEntry) error {
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rolling back interrupted merge of %s (%s)\n", entry.MR, entry.Step)
+	if entry.Worktree != "" && e.mirror != nil {
+		if _, err := os.Stat(entry.Worktree); err == nil {
+			if err := e.mirror.RemoveWorktree(entry.Worktree); err != nil {
+				return fmt.Errorf("removing merge worktree: %w", err)
+			}
+		}
+	}
+	if entry.TargetWas != "" {
+		var err error
+		if current, _ := e.git.CurrentBranch(); current == entry.Target {
+			err = e.git.ResetHard(entry.TargetWas) // Also drops a half-done squash merge
+		} else {
+			err = e.git.ResetBranch(entry.Target, entry.TargetWas)
+		}
+		if err != nil {
+			return fmt.Errorf("resetting local %s: %w", entry.Target, err)
+		}
+	}
+
+	mr, err := e.beads.Show(entry.MR)
+	if err != nil {
+		return fmt.Errorf("fetching MR %s: %w", entry.MR, err)
+	}
+	// Release the MR if the refinery had claimed it
+	if state := StateOf(mr); state.Active() || (state == StateQueued && mr.Assignee != "") {
+		if _, err := RecordState(e.beads, mr, StateQueued, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+	j := OpenJournal(t.TempDir())
+
+	if entry, err := j.Pending(); err != nil || entry != nil {
+		t.Fatalf("Pending on an empty journal = %+v, %v", entry, err)
+	}
+	if err := j.Begin(JournalEntry{MR: "gp-mr-1", Branch: "polecat/nux", Target: "main"}); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := j.Record("gp-mr-1", StepMerged, func(e *JournalEntry) { e.MergeCommit = "cafef00d" }); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// Steps of another MR's merge don't touch this one's entry
+	if err := j.Record("gp-mr-2", StepPushed, nil); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := j.Finish("gp-mr-2"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	entry, err := j.Pending()
+	if err != nil || entry == nil {
+		t.Fatalf("Pending = %+v, %v", entry, err)
+	}
+	if entry.MR != "gp-mr-1" || entry.Step != StepMerged || entry.MergeCommit != "cafef00d" || entry.Branch != "polecat/nux" {
+		t.Errorf("Pending = %+v", entry)
+	}
+	if entry.PID != os.Getpid() || entry.Interrupted() {
+		t.Errorf("our own merge shouldn't count as interrupted: %+v", entry)
+	}
+
+	if err := j.Finish("gp-mr-1"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if entry, err := j.Pending(); err != nil || entry != nil {
+		t.Errorf("Pending after Finish = %+v, %v", entry, err)
+	}
+}
+
+func TestJournalEntryInterrupted(t *testing.T) {
+	t.Parallel()
+	// PIDs this high aren't handed out
+	if entry := (JournalEntry{PID: 1 << 30}); !entry.Interrupted() {
+		t.Error("a merge whose process is gone should count as interrupted")
+	}
+}
+
+func TestJournalNil(t *testing.T) {
+	t.Parallel()
+	var j *Journal
+	if err := j.Begin(JournalEntry{MR: "gp-mr-1"}); err != nil {
+		t.Errorf("Begin on nil journal: %v", err)
+	}
+	if entry, err := j.Pending(); err != nil || entry != nil {
+		t.Errorf("Pending on nil journal = %+v, %v", entry, err)
+	}
+}
+
+func TestJournalLanding(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	before := runGit(t, origin, "rev-parse", "main")
+	// No such session: the merge counts as interrupted as soon as it's journaled
+	const session = "gt-greenplace-refinery-journal-test"
+
+	// gt mq state ... merging, then the patrol's local merge, then gt mq land
+	if err := e.BeginLanding("gp-mr-1", "polecat/toast", "main", "gp-1", session); err != nil {
+		t.Fatalf("BeginLanding: %v", err)
+	}
+	stageLocalMerge(t, rigPath)
+	result := e.Land(context.Background(), nil, nil, "gp-mr-1", "main", "main")
+	if !result.Success {
+		t.Fatalf("Land failed: %s", result.Error)
+	}
+
+	entry, err := OpenJournal(rigPath).Pending()
+	if err != nil || entry == nil {
+		t.Fatalf("Pending after land = %+v, %v", entry, err)
+	}
+	if entry.Step != StepPushed || entry.MergeCommit != result.MergeCommit || entry.TargetWas != before || entry.Session != session {
+		t.Errorf("journal after land = %+v", entry)
+	}
+	if !entry.Interrupted() {
+		t.Error("a landing whose refinery session is gone should count as interrupted")
+	}
+	if landed, err := e.mergeLanded(entry); err != nil || !landed {
+		t.Errorf("mergeLanded = %v, %v; want landed", landed, err)
+	}
+}
+
+func TestJournalLandingNotLanded(t *testing.T) {
+	rigPath, _, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	if err := e.BeginLanding("gp-mr-1", "polecat/toast", "main", "gp-1", "gt-greenplace-refinery-journal-test"); err != nil {
+		t.Fatalf("BeginLanding: %v", err)
+	}
+	refineryRig := stageLocalMerge(t, rigPath)
+	merged := runGit(t, refineryRig, "rev-parse", "HEAD")
+
+	// The refinery dies after merging locally, before gt mq land pushes
+	entry, err := OpenJournal(rigPath).Pending()
+	if err != nil || entry == nil || entry.Step != StepStarted {
+		t.Fatalf("Pending = %+v, %v", entry, err)
+	}
+	entry.MergeCommit = merged
+	if landed, err := e.mergeLanded(entry); err != nil || landed {
+		t.Errorf("mergeLanded = %v, %v; want not landed", landed, err)
+	}
+}
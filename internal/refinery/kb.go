@@ -0,0 +1,287 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// How a merge failure was resolved.
+const (
+	ResolvedRetried  = "retried"  // Merged unchanged: a flake, or fixed on the target
+	ResolvedRebased  = "rebased"  // Merged once rebased past a conflict
+	ResolvedFixed    = "fixed"    // Merged after the branch was changed
+	ResolvedReverted = "reverted" // A change on the target was reverted
+	ResolvedConfig   = "config"   // The rig's config or environment was fixed
+	ResolvedOther    = "other"
+)
+
+// Resolutions lists every way a failure can be resolved.
+var Resolutions = []string{ResolvedRetried, ResolvedRebased, ResolvedFixed, ResolvedReverted, ResolvedConfig, ResolvedOther}
+
+// Knowledge base defaults.
+const (
+	DefaultKBSize     = 1000 // Failures kept per rig, oldest dropped first
+	DefaultKBMinScore = 0.5  // Share of a query's words a failure must contain
+	maxKBErrorLen     = 2000
+)
+
+// FailureResolution is how a merge failure was resolved.
+type FailureResolution struct {
+	How         string    `json:"how"` // ResolvedRetried, ResolvedRebased, ...
+	Note        string    `json:"note,omitempty"`
+	By          string    `json:"by,omitempty"`
+	MergeCommit string    `json:"merge_commit,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// FailureRecord is one refinery failure in a rig's knowledge base.
+type FailureRecord struct {
+	ID         string             `json:"id"` // kb-<n>, unique within the rig
+	MR         string             `json:"mr"`
+	Issue      string             `json:"issue,omitempty"`
+	Branch     string             `json:"branch,omitempty"`
+	Target     string             `json:"target,omitempty"`
+	Worker     string             `json:"worker,omitempty"`
+	Kind       string             `json:"kind"` // conflict, lint, coverage, tests, protection, build
+	Error      string             `json:"error"`
+	Tests      []string           `json:"tests,omitempty"` // Failing tests
+	Files      []string           `json:"files,omitempty"` // Conflicting files
+	Head       string             `json:"head,omitempty"`  // The branch's head when it failed
+	At         time.Time          `json:"at"`
+	Resolution *FailureResolution `json:"resolution,omitempty"`
+}
+
+// Resolved reports whether the failure has a known resolution.
+func (f FailureRecord) Resolved() bool {
+	return f.Resolution != nil
+}
+
+// FailureKind names the kind of failure a result is.
+func FailureKind(result ProcessResult) string {
+	switch {
+	case result.Conflict:
+		return "conflict"
+	case result.Lint.Failed():
+		return "lint"
+	case result.Coverage.Blocked():
+		return "coverage"
+	case result.TestsFailed:
+		return "tests"
+	case result.Rejected:
+		return "protection"
+	default:
+		return "build"
+	}
+}
+
+// FailureKB is a rig's knowledge base of merge failures and how each was
+// resolved, so a failure can be checked for a known fix. A nil
+// *FailureKB records nothing.
+type FailureKB struct {
+	size int
+	path string // under .runtime/
+}
+
+// NewFailureKB returns a rig's knowledge base of merge failures.
+func NewFailureKB(rigPath string) *FailureKB {
+	return &FailureKB{
+		size: DefaultKBSize,
+		path: filepath.Join(rigPath, ".runtime", "merge-failures.json"),
+	}
+}
+
+// Record adds a failure, giving it the next ID, and returns it.
+func (kb *FailureKB) Record(f FailureRecord) (FailureRecord, error) {
+	if kb == nil {
+		return f, nil
+	}
+	records := kb.load()
+	next := 1
+	for _, r := range records {
+		if n, err := strconv.Atoi(strings.TrimPrefix(r.ID, "kb-")); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	f.ID = fmt.Sprintf("kb-%d", next)
+	if f.At.IsZero() {
+		f.At = time.Now().UTC()
+	}
+	if len(f.Error) > maxKBErrorLen {
+		f.Error = f.Error[:maxKBErrorLen]
+	}
+	records = append(records, f)
+	if len(records) > kb.size {
+		records = records[len(records)-kb.size:]
+	}
+	return f, kb.save(records)
+}
+
+// Resolve records how a failure was resolved, replacing any resolution
+// it already had.
+func (kb *FailureKB) Resolve(id string, res FailureResolution) (FailureRecord, error) {
+	if kb == nil {
+		return FailureRecord{}, fmt.Errorf("failure %s not found", id)
+	}
+	if res.At.IsZero() {
+		res.At = time.Now().UTC()
+	}
+	records := kb.load()
+	for i := range records {
+		if records[i].ID == id {
+			records[i].Resolution = &res
+			return records[i], kb.save(records)
+		}
+	}
+	return FailureRecord{}, fmt.Errorf("failure %s not found", id)
+}
+
+// ResolveMerged resolves an MR's unresolved failures now that it has
+// merged with the branch at head: conflicts were rebased past, failures
+// with the same head were retried, and the rest were fixed on the branch.
+// Returns the failures resolved.
+func (kb *FailureKB) ResolveMerged(mrID, head, mergeCommit string) ([]FailureRecord, error) {
+	if kb == nil || mrID == "" {
+		return nil, nil
+	}
+	records := kb.load()
+	var resolved []FailureRecord
+	now := time.Now().UTC()
+	for i, r := range records {
+		if r.MR != mrID || r.Resolved() {
+			continue
+		}
+		how := ResolvedFixed
+		switch {
+		case r.Kind == "conflict":
+			how = ResolvedRebased
+		case r.Head != "" && r.Head == head:
+			how = ResolvedRetried
+		}
+		records[i].Resolution = &FailureResolution{How: how, By: "refinery", MergeCommit: mergeCommit, At: now}
+		resolved = append(resolved, records[i])
+	}
+	if len(resolved) == 0 {
+		return nil, nil
+	}
+	return resolved, kb.save(records)
+}
+
+// List returns the knowledge base, newest failure first.
+func (kb *FailureKB) List() []FailureRecord {
+	if kb == nil {
+		return nil
+	}
+	records := kb.load()
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records
+}
+
+// KBMatch is a failure that matches a search, scored by the share of the
+// query's words it contains.
+type KBMatch struct {
+	FailureRecord
+	Rig   string  `json:"rig,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// Search returns the failures whose error, failing tests or conflicting
+// files contain at least minScore of query's words, ranked by RankMatches.
+// SHAs, numbers and other words that differ between runs are ignored.
+func (kb *FailureKB) Search(query string, minScore float64) []KBMatch {
+	if kb == nil {
+		return nil
+	}
+	words := kbWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+	var matches []KBMatch
+	for _, r := range kb.load() {
+		have := make(map[string]bool)
+		for _, text := range slices.Concat([]string{r.Kind, r.Error}, r.Tests, r.Files) {
+			for w := range kbWords(text) {
+				have[w] = true
+			}
+		}
+		found := 0
+		for w := range words {
+			if have[w] {
+				found++
+			}
+		}
+		score := float64(found) / float64(len(words))
+		if found > 0 && score >= minScore {
+			matches = append(matches, KBMatch{FailureRecord: r, Score: score})
+		}
+	}
+	RankMatches(matches)
+	return matches
+}
+
+// RankMatches sorts matches best first: by score, then resolved failures
+// before unresolved ones, then newest first.
+func RankMatches(matches []KBMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Resolved() != b.Resolved() {
+			return a.Resolved()
+		}
+		return a.At.After(b.At)
+	})
+}
+
+// kbWords splits text into the lowercased words a search matches on,
+// leaving out short words and those that change from one failure to the
+// next: numbers, durations and sizes, and hex strings like SHAs.
+func kbWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		if len(w) < 3 || unicode.IsDigit(rune(w[0])) || isHexID(w) {
+			continue
+		}
+		words[w] = true
+	}
+	return words
+}
+
+// isHexID reports whether a word is a hex string at least seven long.
+func isHexID(w string) bool {
+	return len(w) >= 7 && strings.Trim(w, "0123456789abcdef") == ""
+}
+
+func (kb *FailureKB) load() []FailureRecord {
+	var records []FailureRecord
+	data, err := os.ReadFile(kb.path)
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(data, &records) // a corrupt knowledge base starts over
+	return records
+}
+
+func (kb *FailureKB) save(records []FailureRecord) error {
+	if err := os.MkdirAll(filepath.Dir(kb.path), 0755); err != nil {
+		return fmt.Errorf("saving merge failures: %w", err)
+	}
+	if err := util.AtomicWriteJSON(kb.path, records); err != nil {
+		return fmt.Errorf("saving merge failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package refinery
+
+import (
+	"testing"
+)
+
+func TestFailureKBResolveMerged(t *testing.T) {
+	t.Parallel()
+	kb := NewFailureKB(t.TempDir())
+
+	conflict, err := kb.Record(FailureRecord{MR: "gp-mr-1", Kind: "conflict", Error: "merge conflicts in: [go.mod]", Head: "aaa"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	flake, _ := kb.Record(FailureRecord{MR: "gp-mr-1", Kind: "tests", Error: "tests failed", Head: "aaa"})
+	fixed, _ := kb.Record(FailureRecord{MR: "gp-mr-1", Kind: "build", Error: "build failed", Head: "bbb"})
+	other, _ := kb.Record(FailureRecord{MR: "gp-mr-2", Kind: "tests", Error: "tests failed"})
+	if conflict.ID != "kb-1" || other.ID != "kb-4" {
+		t.Errorf("IDs = %s, %s; want kb-1, kb-4", conflict.ID, other.ID)
+	}
+
+	resolved, err := kb.ResolveMerged("gp-mr-1", "aaa", "cafef00d")
+	if err != nil {
+		t.Fatalf("ResolveMerged: %v", err)
+	}
+	want := map[string]string{conflict.ID: ResolvedRebased, flake.ID: ResolvedRetried, fixed.ID: ResolvedFixed}
+	if len(resolved) != len(want) {
+		t.Fatalf("resolved %d failures, want %d", len(resolved), len(want))
+	}
+	for _, f := range resolved {
+		if f.Resolution.How != want[f.ID] || f.Resolution.MergeCommit != "cafef00d" {
+			t.Errorf("%s resolved as %+v, want %s", f.ID, f.Resolution, want[f.ID])
+		}
+	}
+
+	// Manual resolutions replace inferred ones
+	if _, err := kb.Resolve(flake.ID, FailureResolution{How: ResolvedConfig, Note: "raised the timeout"}); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if _, err := kb.Resolve("kb-99", FailureResolution{How: ResolvedOther}); err == nil {
+		t.Error("Resolve of an unknown failure should fail")
+	}
+	list := kb.List()
+	if len(list) != 4 || list[0].ID != other.ID {
+		t.Fatalf("List = %+v, want newest first", list)
+	}
+	if list[0].Resolved() {
+		t.Error("gp-mr-2 hasn't merged; its failure should be unresolved")
+	}
+	if list[2].Resolution.How != ResolvedConfig {
+		t.Errorf("%s resolution = %+v", list[2].ID, list[2].Resolution)
+	}
+}
+
+func TestFailureKBSearch(t *testing.T) {
+	t.Parallel()
+	kb := NewFailureKB(t.TempDir())
+	old, _ := kb.Record(FailureRecord{MR: "gp-mr-1", Kind: "build", Error: "internal/cmd/kb.go:12: undefined: config.AgentWindow"})
+	_, _ = kb.ResolveMerged("gp-mr-1", "", "")
+	_, _ = kb.Record(FailureRecord{MR: "gp-mr-2", Kind: "build", Error: "internal/cmd/kb.go:40: undefined: config.AgentWindow"})
+	_, _ = kb.Record(FailureRecord{MR: "gp-mr-3", Kind: "tests", Error: "tests failed", Tests: []string{"TestParallelReadySteps"}})
+
+	// Line numbers differ; the resolved failure ranks first
+	matches := kb.Search("internal/cmd/kb.go:97: undefined: config.AgentWindow", DefaultKBMinScore)
+	if len(matches) != 2 || matches[0].ID != old.ID || matches[0].Score != 1 {
+		t.Fatalf("Search = %+v, want both build failures, %s first", matches, old.ID)
+	}
+	if matches := kb.Search("--- FAIL: TestParallelReadySteps (0.01s)", DefaultKBMinScore); len(matches) != 1 || matches[0].MR != "gp-mr-3" {
+		t.Errorf("Search by test = %+v", matches)
+	}
+	if matches := kb.Search("deadbeef1 42", DefaultKBMinScore); matches != nil {
+		t.Errorf("SHAs and numbers alone shouldn't match: %+v", matches)
+	}
+}
+
+func TestKBWords(t *testing.T) {
+	t.Parallel()
+	got := kbWords("FAIL at 3f2a9c1d: TestFoo/bar_baz in go.mod after 2s, 1234 ms")
+	for _, w := range []string{"fail", "testfoo", "bar_baz", "mod"} {
+		if !got[w] {
+			t.Errorf("missing %q in %v", w, got)
+		}
+	}
+	for _, w := range []string{"3f2a9c1d", "1234", "2s", "at", "go", "ms"} {
+		if got[w] {
+			t.Errorf("%q should be left out: %v", w, got)
+		}
+	}
+}
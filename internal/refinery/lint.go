@@ -0,0 +1,397 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Linter output formats.
+const (
+	LintFormatLine  = "line"  // path:line[:col]: message
+	LintFormatSARIF = "sarif" // SARIF 2.1 JSON
+)
+
+// MaxLintFindings caps the findings kept on an MR and in its fix-it task.
+const MaxLintFindings = 100
+
+// lintLineRe matches a "path:line[:col]: message" finding.
+var lintLineRe = regexp.MustCompile(`^([^\s:][^:]*):(\d+)(?::(\d+))?:\s*(.+)$`)
+
+// lintRuleRe matches a trailing "(rule)", as golangci-lint names the linter
+// that reported a finding.
+var lintRuleRe = regexp.MustCompile(`\s+\(([\w./-]+)\)$`)
+
+// Linter is a linter the refinery runs on a rig's MRs.
+type Linter struct {
+	Name    string       `json:"name"`
+	Command string       `json:"command"`
+	Format  string       `json:"format"`           // LintFormatLine or LintFormatSARIF
+	Runner  *CheckRunner `json:"runner,omitempty"` // nil = the host's shell
+}
+
+// LoadLinters returns the linters a rig's settings configure, in order,
+// each running as merge_queue.checks.lint says. A rig without settings
+// has none.
+func LoadLinters(rigPath string) ([]Linter, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil || len(settings.MergeQueue.Lint) == 0 {
+		return nil, nil
+	}
+	runner, err := NewCheckRunner(settings.MergeQueue.Checks[CheckLint])
+	if err != nil {
+		return nil, fmt.Errorf("merge_queue.checks.%s: %w", CheckLint, err)
+	}
+
+	var linters []Linter
+	seen := make(map[string]bool)
+	for i, cfg := range settings.MergeQueue.Lint {
+		if cfg == nil || cfg.Name == "" || strings.TrimSpace(cfg.Command) == "" {
+			return nil, fmt.Errorf("merge_queue.lint[%d]: a linter needs a name and a command", i)
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("merge_queue.lint: linter %q listed twice", cfg.Name)
+		}
+		seen[cfg.Name] = true
+		format := cfg.Format
+		switch format {
+		case "":
+			format = LintFormatLine
+		case LintFormatLine, LintFormatSARIF:
+		default:
+			return nil, fmt.Errorf("merge_queue.lint.%s: invalid format %q (want %s or %s)", cfg.Name, cfg.Format, LintFormatLine, LintFormatSARIF)
+		}
+		linters = append(linters, Linter{Name: cfg.Name, Command: cfg.Command, Format: format, Runner: runner})
+	}
+	return linters, nil
+}
+
+// Run runs the linter in dir, within its runner's timeout.
+func (l Linter) Run(ctx context.Context, dir string) CheckRun {
+	if timeout := l.Runner.TimeoutOr(0); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return CheckCommand{Command: l.Command, Dir: dir, Attempts: 1, Runner: l.Runner}.Run(ctx)
+}
+
+// LintFinding is one problem a linter found, anchored to a file (relative
+// to the repository root) and line.
+type LintFinding struct {
+	Linter   string `json:"linter"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity,omitempty"` // As the linter reports it ("error", "warning", ...)
+	Message  string `json:"message"`
+}
+
+// Location is where the finding is: "path:line[:col]".
+func (f LintFinding) Location() string {
+	if f.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// String formats the finding as a line-format linter would.
+func (f LintFinding) String() string {
+	msg := f.Message
+	if f.Rule != "" {
+		msg += " (" + f.Rule + ")"
+	}
+	return fmt.Sprintf("%s: %s", f.Location(), msg)
+}
+
+// LintReport is what a merge attempt's linters found.
+type LintReport struct {
+	Findings []LintFinding `json:"findings"` // At most MaxLintFindings
+	Total    int           `json:"total"`    // Before the cap
+}
+
+// Failed reports whether the linters found anything.
+func (r *LintReport) Failed() bool {
+	return r != nil && r.Total > 0
+}
+
+// Field encodes the findings for an MR's lint_findings field; "" if there
+// are none.
+func (r *LintReport) Field() string {
+	if r == nil || len(r.Findings) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(r.Findings)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseLintFindings parses an MR's lint_findings field.
+func ParseLintFindings(s string) ([]LintFinding, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var findings []LintFinding
+	if err := json.Unmarshal([]byte(s), &findings); err != nil {
+		return nil, fmt.Errorf("parsing lint findings: %w", err)
+	}
+	return findings, nil
+}
+
+// ParseLintOutput reads the findings in a linter's output. Paths are made
+// relative to dir, the checkout the linter ran in; findings outside it
+// are dropped.
+func ParseLintOutput(linter, format, output, dir string) ([]LintFinding, error) {
+	var findings []LintFinding
+	if format == LintFormatSARIF {
+		var err error
+		if findings, err = parseSARIF(output); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, line := range strings.Split(output, "\n") {
+			m := lintLineRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+			if m == nil {
+				continue
+			}
+			f := LintFinding{File: m[1], Message: strings.TrimSpace(m[4])}
+			f.Line, _ = strconv.Atoi(m[2])
+			f.Column, _ = strconv.Atoi(m[3])
+			for _, severity := range []string{"error", "warning", "note", "info"} {
+				if rest, ok := strings.CutPrefix(f.Message, severity+": "); ok {
+					f.Severity, f.Message = severity, rest
+					break
+				}
+			}
+			if rule := lintRuleRe.FindStringSubmatch(f.Message); rule != nil {
+				f.Rule = rule[1]
+				f.Message = strings.TrimSuffix(f.Message, rule[0])
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	kept := findings[:0]
+	for _, f := range findings {
+		file, ok := repoRelative(f.File, dir)
+		if !ok || f.Line <= 0 {
+			continue
+		}
+		f.File, f.Linter = file, linter
+		kept = append(kept, f)
+	}
+	return kept, nil
+}
+
+// repoRelative returns file relative to the checkout dir, as a slash path.
+func repoRelative(file, dir string) (string, bool) {
+	if filepath.IsAbs(file) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", false
+		}
+		if file, err = filepath.Rel(abs, file); err != nil {
+			return "", false
+		}
+	}
+	file = path.Clean(filepath.ToSlash(file))
+	if file == "." || file == ".." || strings.HasPrefix(file, "../") || path.IsAbs(file) {
+		return "", false
+	}
+	return file, true
+}
+
+// parseSARIF reads the results of a SARIF log.
+func parseSARIF(output string) ([]LintFinding, error) {
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	// The log may follow other output (e.g., on stderr)
+	start := strings.Index(output, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("no SARIF log in the output")
+	}
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&log); err != nil {
+		return nil, fmt.Errorf("parsing SARIF: %w", err)
+	}
+	var findings []LintFinding
+	for _, run := range log.Runs {
+		for _, result := range run.Results {
+			if len(result.Locations) == 0 {
+				continue
+			}
+			loc := result.Locations[0].PhysicalLocation
+			file := loc.ArtifactLocation.URI
+			if u, err := url.Parse(file); err == nil && u.Scheme == "file" {
+				file = u.Path
+			}
+			findings = append(findings, LintFinding{
+				File:     file,
+				Line:     loc.Region.StartLine,
+				Column:   loc.Region.StartColumn,
+				Rule:     result.RuleID,
+				Severity: result.Level,
+				Message:  strings.TrimSpace(result.Message.Text),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// runLinters runs the rig's linters on the merged result checked out in
+// dir. It fails if any finds anything, or fails without saying what; a rig
+// without linters passes.
+func (e *Engineer) runLinters(ctx context.Context, mrID, dir string) ProcessResult {
+	linters, err := LoadLinters(e.rig.Path)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	if len(linters) == 0 {
+		return ProcessResult{Success: true}
+	}
+
+	report := &LintReport{}
+	var outputs []string
+	for _, l := range linters {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Linting with %s: %s\n", l.Name, l.Command)
+		run := l.Run(ctx, dir)
+		if ctx.Err() != nil {
+			return ProcessResult{Error: "lint run canceled"}
+		}
+		outputs = append(outputs, fmt.Sprintf("== %s ==\n%s", l.Name, run.Output))
+
+		findings, err := ParseLintOutput(l.Name, l.Format, run.Output, dir)
+		if err == nil && len(findings) == 0 && run.Err != nil {
+			err = fmt.Errorf("failed without findings: %v", run.Err)
+		}
+		if err != nil {
+			artifacts := e.saveArtifacts(mrID, CheckLint, dir, CheckRun{Output: strings.Join(outputs, "\n")})
+			reason := fmt.Sprintf("lint: %s %v", l.Name, err)
+			if tail := lastLines(run.Output, verifyOutputLines); tail != "" {
+				reason += "\n" + tail
+			}
+			return ProcessResult{TestsFailed: true, Error: reason, Artifacts: artifacts}
+		}
+		report.Total += len(findings)
+		for _, f := range findings {
+			if len(report.Findings) < MaxLintFindings {
+				report.Findings = append(report.Findings, f)
+			}
+		}
+	}
+
+	artifacts := e.saveArtifacts(mrID, CheckLint, dir, CheckRun{Output: strings.Join(outputs, "\n")})
+	if report.Failed() {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Lint found %d problem(s)\n", report.Total)
+		return ProcessResult{
+			TestsFailed: true,
+			Error:       fmt.Sprintf("lint found %d problem(s), first: %s", report.Total, report.Findings[0]),
+			Lint:        report,
+			Artifacts:   artifacts,
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Lint passed\n")
+	return ProcessResult{Success: true, Lint: report, Artifacts: artifacts}
+}
+
+// SendBackForLint sends an MR whose merge failed lint back to its worker:
+// a fix-it task holding exactly the findings, assigned to the worker, and
+// the MR moved to changes_requested until the worker resubmits (which
+// closes the task). Returns the task's ID.
+func (e *Engineer) SendBackForLint(mr *beads.Issue, report *LintReport) (string, error) {
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	task, err := e.beads.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Fix lint findings on %s (%d)", mr.ID, report.Total),
+		Type:        "task",
+		Priority:    mr.Priority,
+		Description: lintTaskDescription(mr.ID, fields, report),
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating lint task: %w", err)
+	}
+	if fields.Worker != "" {
+		assignee := fmt.Sprintf("%s/polecats/%s", e.rig.Name, fields.Worker)
+		if err := e.beads.Update(task.ID, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: assigning %s to %s: %v\n", task.ID, assignee, err)
+		}
+	}
+
+	fields.ChangesTasks = strings.Join(append(SplitMRList(fields.ChangesTasks), task.ID), ",")
+	desc := beads.SetMRFields(mr, fields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return task.ID, fmt.Errorf("recording lint task on MR %s: %w", mr.ID, err)
+	}
+	mr.Description = desc
+	if _, err := RecordState(e.beads, mr, StateChangesRequested, ""); err != nil {
+		return task.ID, err
+	}
+	return task.ID, nil
+}
+
+// lintTaskDescription builds a fix-it task's description: the MR, and the
+// findings as JSON, for the worker to work through.
+func lintTaskDescription(mrID string, fields *beads.MRFields, report *LintReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fix the lint findings on %s\n\n", mrID)
+	b.WriteString("## Metadata\n")
+	fmt.Fprintf(&b, "- MR: %s\n", mrID)
+	fmt.Fprintf(&b, "- Branch: %s\n", fields.Branch)
+	fmt.Fprintf(&b, "- Original issue: %s\n", fields.SourceIssue)
+	fmt.Fprintf(&b, "- Findings: %d\n", report.Total)
+
+	data, _ := json.MarshalIndent(report.Findings, "", "  ")
+	fmt.Fprintf(&b, "\n## Findings\n```json\n%s\n```\n", data)
+	if more := report.Total - len(report.Findings); more > 0 {
+		fmt.Fprintf(&b, "\n%d more not listed; run the linters locally to see them.\n", more)
+	}
+	fmt.Fprintf(&b, `
+## Instructions
+1. Check out the branch: git checkout %s
+2. Fix each finding at its file and line
+3. Push to the same branch
+4. Resubmit: gt mq submit (this closes this task and queues the MR again)
+`, fields.Branch)
+	return b.String()
+}
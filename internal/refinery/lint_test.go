@@ -0,0 +1,163 @@
+package refinery
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseLintOutput(t *testing.T) {
+	dir := t.TempDir()
+	output := strings.Join([]string{
+		"level=warning msg=\"[runner] deprecated\"",
+		"# example.com/a",
+		"./a.go:12:5: unused variable x (unused)",
+		"pkg/b.go:3: error: missing return",
+		filepath.Join(dir, "c.go") + ":7:1: exported func C should have comment (revive)",
+		"/elsewhere/d.go:1:1: outside the checkout",
+		"../e.go:1:1: outside too",
+		"",
+	}, "\n")
+
+	got, err := ParseLintOutput("golangci-lint", LintFormatLine, output, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LintFinding{
+		{Linter: "golangci-lint", File: "a.go", Line: 12, Column: 5, Rule: "unused", Message: "unused variable x"},
+		{Linter: "golangci-lint", File: "pkg/b.go", Line: 3, Severity: "error", Message: "missing return"},
+		{Linter: "golangci-lint", File: "c.go", Line: 7, Column: 1, Rule: "revive", Message: "exported func C should have comment"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if s := got[0].String(); s != "a.go:12:5: unused variable x (unused)" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestParseLintOutputSARIF(t *testing.T) {
+	output := `warning: some stderr noise
+{"version":"2.1.0","runs":[{"results":[
+  {"ruleId":"S1000","level":"warning","message":{"text":"use plain channel send"},
+   "locations":[{"physicalLocation":{"artifactLocation":{"uri":"file:///src/repo/x/y.go"},"region":{"startLine":9,"startColumn":2}}}]},
+  {"ruleId":"E501","level":"error","message":{"text":"line too long"},
+   "locations":[{"physicalLocation":{"artifactLocation":{"uri":"lib/z.py"},"region":{"startLine":40}}}]},
+  {"ruleId":"nowhere","message":{"text":"no location"}}
+]}]}`
+
+	got, err := ParseLintOutput("staticcheck", LintFormatSARIF, output, "/src/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LintFinding{
+		{Linter: "staticcheck", File: "x/y.go", Line: 9, Column: 2, Rule: "S1000", Severity: "warning", Message: "use plain channel send"},
+		{Linter: "staticcheck", File: "lib/z.py", Line: 40, Rule: "E501", Severity: "error", Message: "line too long"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := ParseLintOutput("staticcheck", LintFormatSARIF, "panic: oops", "/src/repo"); err == nil {
+		t.Error("ParseLintOutput accepted output with no SARIF log")
+	}
+}
+
+func TestLintReportField(t *testing.T) {
+	var none *LintReport
+	if none.Failed() || none.Field() != "" {
+		t.Error("a nil report failed or has findings")
+	}
+	r := &LintReport{Findings: []LintFinding{{Linter: "vet", File: "a.go", Line: 1, Message: "bad"}}, Total: 3}
+	if !r.Failed() {
+		t.Error("report with findings didn't fail")
+	}
+	parsed, err := ParseLintFindings(r.Field())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 1 || parsed[0] != r.Findings[0] {
+		t.Errorf("ParseLintFindings(Field()) = %+v", parsed)
+	}
+	if desc := lintTaskDescription("gp-mr-1", &beads.MRFields{Branch: "polecat/toast"}, r); !strings.Contains(desc, `"file": "a.go"`) || !strings.Contains(desc, "2 more not listed") {
+		t.Errorf("task description lacks the findings:\n%s", desc)
+	}
+}
+
+func TestLoadLinters(t *testing.T) {
+	rigPath := t.TempDir()
+	if linters, err := LoadLinters(rigPath); err != nil || linters != nil {
+		t.Errorf("LoadLinters without settings = %v, %v; want none", linters, err)
+	}
+
+	writeCheckSettings(t, rigPath, `{
+		"lint": [
+			{"name": "vet", "command": "go vet ./..."},
+			{"name": "staticcheck", "command": "staticcheck -f sarif ./...", "format": "sarif"}
+		],
+		"checks": {"lint": {"timeout": "5m"}}
+	}`)
+	linters, err := LoadLinters(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linters) != 2 || linters[0].Format != LintFormatLine || linters[1].Format != LintFormatSARIF {
+		t.Fatalf("linters = %+v", linters)
+	}
+	if linters[0].Runner.TimeoutOr(0) == 0 {
+		t.Error("merge_queue.checks.lint not applied")
+	}
+
+	for _, lint := range []string{
+		`[{"name": "vet"}]`,
+		`[{"command": "go vet ./..."}]`,
+		`[{"name": "vet", "command": "go vet ./...", "format": "xml"}]`,
+		`[{"name": "vet", "command": "a"}, {"name": "vet", "command": "b"}]`,
+	} {
+		writeCheckSettings(t, rigPath, `{"lint": `+lint+`}`)
+		if _, err := LoadLinters(rigPath); err == nil {
+			t.Errorf("LoadLinters accepted %s", lint)
+		}
+	}
+}
+
+func TestMergeLintGate(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	// Finds a problem only in the polecat's change
+	writeCheckSettings(t, rigPath, `{"lint": [
+		{"name": "vet", "command": "true"},
+		{"name": "nofeature", "command": "if [ -f feature.txt ]; then echo 'feature.txt:1:1: features are frozen (freeze)'; fi"}
+	]}`)
+	before := runGit(t, origin, "rev-parse", "main")
+
+	result := mirrorTestEngineer(rigPath).doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("doMerge = %+v, want failed lint", result)
+	}
+	if !result.Lint.Failed() || result.Lint.Total != 1 {
+		t.Fatalf("lint = %+v, want one finding", result.Lint)
+	}
+	want := LintFinding{Linter: "nofeature", File: "feature.txt", Line: 1, Column: 1, Rule: "freeze", Message: "features are frozen"}
+	if got := result.Lint.Findings[0]; got != want {
+		t.Errorf("finding = %+v, want %+v", got, want)
+	}
+	if after := runGit(t, origin, "rev-parse", "main"); after != before {
+		t.Error("origin main moved on a merge that failed lint")
+	}
+	if result.Artifacts == "" {
+		t.Error("lint output not kept")
+	}
+}
@@ -0,0 +1,165 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// PathLocks are a rig's hot paths, from merge_queue.locks. An MR holds the
+// locks whose paths it touches, plus any it declared at submit, while the
+// refinery has it claimed; queued MRs wanting a held lock wait their turn.
+// A nil *PathLocks registers none, though MRs may still declare locks.
+type PathLocks struct {
+	locks []config.MergeLockConfig
+}
+
+// NewPathLocks builds PathLocks from config. Returns nil (no locks) if
+// there are none.
+func NewPathLocks(cfg []config.MergeLockConfig) (*PathLocks, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool)
+	for _, lock := range cfg {
+		if lock.Name == "" {
+			return nil, fmt.Errorf("merge lock with no name")
+		}
+		if strings.Contains(lock.Name, ",") {
+			return nil, fmt.Errorf("merge lock %q: name contains a comma", lock.Name)
+		}
+		if seen[lock.Name] {
+			return nil, fmt.Errorf("duplicate merge lock %q", lock.Name)
+		}
+		seen[lock.Name] = true
+		if len(lock.Paths) == 0 {
+			return nil, fmt.Errorf("merge lock %s has no paths", lock.Name)
+		}
+		for _, p := range lock.Paths {
+			if _, err := path.Match(strings.TrimSuffix(p, "/**"), ""); err != nil {
+				return nil, fmt.Errorf("merge lock %s: path %q: %v", lock.Name, p, err)
+			}
+		}
+	}
+	return &PathLocks{locks: cfg}, nil
+}
+
+// LoadPathLocks reads a rig's path locks from its settings/config.json. A
+// missing settings file or locks section yields nil (no locks).
+func LoadPathLocks(rigPath string) (*PathLocks, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewPathLocks(settings.MergeQueue.Locks)
+}
+
+// Detect returns the locks an MR changing files touches, in config order.
+func (l *PathLocks) Detect(files []string) []string {
+	if l == nil {
+		return nil
+	}
+	var locks []string
+	for _, lock := range l.locks {
+		for _, file := range files {
+			if lockCovers(lock, file) {
+				locks = append(locks, lock.Name)
+				break
+			}
+		}
+	}
+	return locks
+}
+
+// MergeLocks joins declared and detected locks, without duplicates, for
+// an MR's locks field.
+func MergeLocks(declared, detected []string) []string {
+	var locks []string
+	seen := make(map[string]bool)
+	for _, lock := range append(append([]string(nil), declared...), detected...) {
+		if lock = strings.TrimSpace(lock); lock != "" && !seen[lock] {
+			seen[lock] = true
+			locks = append(locks, lock)
+		}
+	}
+	return locks
+}
+
+// LockHolders returns, for each lock held among issues, the MR holding
+// it: one the refinery has claimed (in progress, or open with an
+// assignee).
+func LockHolders(issues []*beads.Issue) map[string]string {
+	holders := make(map[string]string)
+	for _, issue := range issues {
+		if issue.Status != "in_progress" && (issue.Status != "open" || issue.Assignee == "") {
+			continue
+		}
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			continue
+		}
+		for _, lock := range SplitMRList(fields.Locks) {
+			if _, ok := holders[lock]; !ok {
+				holders[lock] = issue.ID
+			}
+		}
+	}
+	return holders
+}
+
+// LockWait is a queued MR waiting on a path lock.
+type LockWait struct {
+	Lock   string `json:"lock"`
+	Holder string `json:"holder"` // MR ahead of it that has the lock
+}
+
+// SerializeLocks walks queued items in queue order, handing each its
+// locks unless one is held (in holders, or by an item earlier in the
+// queue). Returns the items free to merge now, and why each other one
+// waits, by ID. holders is not modified.
+func SerializeLocks[T any](holders map[string]string, items []T, id func(T) string, locks func(T) []string) (free []T, waits map[string]LockWait) {
+	taken := make(map[string]string, len(holders))
+	for lock, holder := range holders {
+		taken[lock] = holder
+	}
+	waits = make(map[string]LockWait)
+	for _, item := range items {
+		itemLocks := locks(item)
+		waiting := false
+		for _, lock := range itemLocks {
+			if holder, ok := taken[lock]; ok && holder != id(item) {
+				waits[id(item)] = LockWait{Lock: lock, Holder: holder}
+				waiting = true
+				break
+			}
+		}
+		if waiting {
+			continue
+		}
+		for _, lock := range itemLocks {
+			taken[lock] = id(item)
+		}
+		free = append(free, item)
+	}
+	return free, waits
+}
+
+// lockCovers reports whether file is one of lock's.
+func lockCovers(lock config.MergeLockConfig, file string) bool {
+	for _, pattern := range lock.Paths {
+		if matchProtectedPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
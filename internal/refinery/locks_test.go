@@ -0,0 +1,86 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewPathLocks(t *testing.T) {
+	for _, cfg := range [][]config.MergeLockConfig{
+		{{Paths: []string{"db/**"}}},
+		{{Name: "schema"}},
+		{{Name: "a,b", Paths: []string{"db/**"}}},
+		{{Name: "schema", Paths: []string{"db/**"}}, {Name: "schema", Paths: []string{"go.sum"}}},
+		{{Name: "schema", Paths: []string{"db/[**"}}},
+	} {
+		if _, err := NewPathLocks(cfg); err == nil {
+			t.Errorf("NewPathLocks(%+v) should fail", cfg)
+		}
+	}
+	if l, err := NewPathLocks(nil); err != nil || l != nil {
+		t.Errorf("NewPathLocks(nil) = %v, %v; want none", l, err)
+	}
+}
+
+func TestPathLocksDetect(t *testing.T) {
+	locks, err := NewPathLocks([]config.MergeLockConfig{
+		{Name: "schema", Paths: []string{"db/migrations/**", "db/schema.sql"}},
+		{Name: "lockfile", Paths: []string{"go.sum"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := locks.Detect([]string{"go.sum", "db/migrations/0042_users.sql", "cmd/main.go"})
+	if want := []string{"schema", "lockfile"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Detect = %v, want %v", got, want)
+	}
+	if got := locks.Detect([]string{"db/seed.sql"}); got != nil {
+		t.Errorf("Detect(db/seed.sql) = %v, want none", got)
+	}
+	if got := MergeLocks([]string{"deploy", "schema"}, []string{"schema", "lockfile"}); !reflect.DeepEqual(got, []string{"deploy", "schema", "lockfile"}) {
+		t.Errorf("MergeLocks = %v", got)
+	}
+}
+
+func TestSerializeLocks(t *testing.T) {
+	holders := LockHolders([]*beads.Issue{
+		{ID: "gt-1", Status: "in_progress", Description: "branch: polecat/a\nlocks: schema"},
+		{ID: "gt-2", Status: "open", Assignee: "refinery-1", Description: "branch: polecat/b\nlocks: deploy"},
+		{ID: "gt-3", Status: "open", Description: "branch: polecat/c\nlocks: lockfile"},
+	})
+	if want := map[string]string{"schema": "gt-1", "deploy": "gt-2"}; !reflect.DeepEqual(holders, want) {
+		t.Fatalf("LockHolders = %v, want %v", holders, want)
+	}
+
+	queued := []*MRInfo{
+		{ID: "gt-4", Locks: []string{"schema"}},
+		{ID: "gt-5", Locks: []string{"lockfile"}},
+		{ID: "gt-6"},
+		{ID: "gt-7", Locks: []string{"api", "lockfile"}},
+		{ID: "gt-8", Locks: []string{"api"}},
+	}
+	free, waits := SerializeLocks(holders, queued,
+		func(mr *MRInfo) string { return mr.ID },
+		func(mr *MRInfo) []string { return mr.Locks })
+
+	var ids []string
+	for _, mr := range free {
+		ids = append(ids, mr.ID)
+	}
+	if want := []string{"gt-5", "gt-6", "gt-8"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("free = %v, want %v", ids, want)
+	}
+	want := map[string]LockWait{
+		"gt-4": {Lock: "schema", Holder: "gt-1"},
+		"gt-7": {Lock: "lockfile", Holder: "gt-5"},
+	}
+	if !reflect.DeepEqual(waits, want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+	if _, ok := holders["lockfile"]; ok {
+		t.Error("SerializeLocks modified holders")
+	}
+}
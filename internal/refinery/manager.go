@@ -110,6 +110,9 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Note: No PID check per ZFC - tmux session is the source of truth
 
+	// Finish any merge the last refinery left half done before it merges more
+	m.recoverInterruptedMerge()
+
 	// Background mode: spawn a Claude agent in a tmux session
 	// The Claude agent handles MR processing using git commands and beads
 
@@ -155,15 +158,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:          "refinery",
-		Rig:           m.rig.Name,
-		TownRoot:      townRoot,
-		BeadsNoDaemon: true,
-	})
-
-	// Add refinery-specific flag
-	envVars["GT_REFINERY"] = "1"
+	envVars := RefineryEnv(m.rig.Name, townRoot)
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
@@ -193,6 +188,22 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	return nil
 }
 
+// recoverInterruptedMerge completes or rolls back a merge the refinery
+// journaled but didn't finish. Failures are reported, not fatal: gt doctor
+// reports the journal until it is recovered.
+func (m *Manager) recoverInterruptedMerge() {
+	eng := NewEngineer(m.rig)
+	eng.SetOutput(m.output)
+	rec, err := eng.Recover()
+	switch {
+	case errors.Is(err, ErrMergeInProgress):
+	case err != nil:
+		_, _ = fmt.Fprintf(m.output, "⚠ Could not recover interrupted merge: %v\n", err)
+	case rec != nil:
+		_, _ = fmt.Fprintf(m.output, "✓ Recovered interrupted merge of %s: %s\n", rec.Entry.MR, rec.Action)
+	}
+}
+
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
@@ -325,7 +336,7 @@ func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 		Worker:       fields.Worker,
 		IssueID:      fields.SourceIssue,
 		TargetBranch: target,
-		Status:       MROpen,
+		Status:       StateOf(issue).Status(),
 		CreatedAt:    parseTime(issue.CreatedAt),
 	}
 }
@@ -512,8 +523,10 @@ Thank you for your contribution!`,
 
 // Common errors for MR operations
 var (
-	ErrMRNotFound  = errors.New("merge request not found")
-	ErrMRNotFailed = errors.New("merge request has not failed")
+	ErrMRNotFound   = errors.New("merge request not found")
+	ErrMRNotFailed  = errors.New("merge request has not failed")
+	ErrTagExists    = errors.New("tag already exists")
+	ErrBranchExists = errors.New("branch already exists")
 )
 
 // GetMR returns a merge request by ID.
@@ -548,6 +561,12 @@ func (m *Manager) FindMR(idOrBranch string) (*MergeRequest, error) {
 		}
 	}
 
+	// MRs the refinery is working on are in progress, not in the queue
+	b := beads.New(m.rig.BeadsPath())
+	if issue, err := b.Show(idOrBranch); err == nil && StateOf(issue).Active() {
+		return m.issueToMR(issue), nil
+	}
+
 	return nil, ErrMRNotFound
 }
 
@@ -580,9 +599,13 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 		return nil, fmt.Errorf("%w: MR is already closed with reason: %s", ErrClosedImmutable, mr.CloseReason)
 	}
 
-	// Close the bead in storage with the rejection reason
+	// Record the rejection, closing the bead with the reason
 	b := beads.New(m.rig.BeadsPath())
-	if err := b.CloseWithReason("rejected: "+reason, mr.ID); err != nil {
+	issue, err := b.Show(mr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MR bead: %w", err)
+	}
+	if _, err := RecordState(b, issue, StateRejected, reason); err != nil {
 		return nil, fmt.Errorf("failed to close MR bead: %w", err)
 	}
 
@@ -592,6 +615,8 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 		_, _ = fmt.Fprintf(m.output, "Warning: failed to update MR state: %v\n", err)
 	}
 	mr.Error = reason
+	_ = events.LogFeed(events.TypeMRRejected, fmt.Sprintf("%s/refinery", m.rig.Name),
+		events.MRPayload(m.rig.Name, mr.ID, mr.IssueID, mr.Branch, reason))
 
 	// Optionally notify worker
 	if notify {
@@ -0,0 +1,105 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// MirrorDir is the rig's shared bare repo, relative to the rig root.
+const MirrorDir = ".repo.git"
+
+// mergeWorktreePrefix names the temporary directories holding merge
+// worktrees, so RemoveWorktree only ever deletes directories it created.
+const mergeWorktreePrefix = "gt-merge-"
+
+// ErrNoMirror is returned for legacy rigs that have no shared bare repo.
+var ErrNoMirror = errors.New("rig has no bare mirror (" + MirrorDir + ")")
+
+// Mirror is a rig's shared bare repo. The refinery and polecat worktrees
+// hang off it, so polecat branches are already local and a merge or check
+// run can check out any ref in a temporary worktree: no clone, no full
+// fetch, and the refinery's own checkout is never switched or dirtied.
+type Mirror struct {
+	path string
+	git  *git.Git
+}
+
+// OpenMirror returns the mirror of the rig at rigPath, or ErrNoMirror.
+func OpenMirror(rigPath string) (*Mirror, error) {
+	path := filepath.Join(rigPath, MirrorDir)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil, ErrNoMirror
+	}
+	return &Mirror{path: path, git: git.NewGitWithDir(path, "")}, nil
+}
+
+// MirrorOf returns the mirror a merge worktree was created from.
+func MirrorOf(worktree string) (*Mirror, error) {
+	dir, err := git.NewGit(worktree).CommonDir()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git worktree: %w", worktree, err)
+	}
+	return &Mirror{path: dir, git: git.NewGitWithDir(dir, "")}, nil
+}
+
+// Path returns the bare repo's directory.
+func (m *Mirror) Path() string {
+	return m.path
+}
+
+// Sync brings the mirror up to date for a merge into target: it fetches
+// just that branch from remote (the rig's push remote) and drops worktree
+// entries whose directories are gone (e.g. temp dirs reaped after a crash).
+func (m *Mirror) Sync(remote, target string) error {
+	_ = m.git.WorktreePrune()
+	if err := m.git.FetchBranch(remote, target); err != nil {
+		return fmt.Errorf("fetching %s/%s: %w", remote, target, err)
+	}
+	return nil
+}
+
+// ResolveRef returns name if it is a branch in the mirror (polecat branches
+// usually are) or any other ref the mirror knows, otherwise remote/name.
+func (m *Mirror) ResolveRef(remote, name string) string {
+	if exists, err := m.git.BranchExists(name); err == nil && exists {
+		return name
+	}
+	if _, err := m.git.Rev(name); err == nil {
+		return name
+	}
+	return remote + "/" + name
+}
+
+// AddWorktree checks out ref with a detached HEAD in a new temporary
+// worktree and returns its path. Remove it with RemoveWorktree.
+func (m *Mirror) AddWorktree(ref string) (string, error) {
+	tmp, err := os.MkdirTemp("", mergeWorktreePrefix)
+	if err != nil {
+		return "", fmt.Errorf("creating worktree dir: %w", err)
+	}
+	path := filepath.Join(tmp, "wt")
+	if err := m.git.WorktreeAddDetached(path, ref); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", fmt.Errorf("creating worktree at %s: %w", ref, err)
+	}
+	return path, nil
+}
+
+// RemoveWorktree removes a worktree created by AddWorktree, discarding any
+// changes in it.
+func (m *Mirror) RemoveWorktree(path string) error {
+	parent := filepath.Dir(path)
+	if filepath.Base(path) != "wt" || !strings.HasPrefix(filepath.Base(parent), mergeWorktreePrefix) {
+		return fmt.Errorf("%s is not a merge worktree", path)
+	}
+	_ = m.git.WorktreeRemove(path, true)
+	if err := os.RemoveAll(parent); err != nil {
+		return fmt.Errorf("removing worktree dir: %w", err)
+	}
+	return m.git.WorktreePrune()
+}
@@ -0,0 +1,178 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitFile(t *testing.T, dir, name, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", msg)
+}
+
+// setupMirrorRig creates an origin repo and a rig laid out like gt rig add
+// makes it: a bare mirror, the refinery worktree on main, and a polecat
+// worktree on polecat/toast. Returns the rig path, the origin path, and the
+// polecat worktree.
+func setupMirrorRig(t *testing.T) (string, string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	tmp := t.TempDir()
+	origin := filepath.Join(tmp, "origin.git")
+	runGit(t, tmp, "init", "--bare", "-b", "main", origin)
+	seed := filepath.Join(tmp, "seed")
+	runGit(t, tmp, "clone", origin, seed)
+	runGit(t, seed, "checkout", "-b", "main")
+	commitFile(t, seed, "README.md", "# Test\n", "initial")
+	runGit(t, seed, "push", "origin", "main")
+
+	rigPath := filepath.Join(tmp, "greenplace")
+	if err := git.NewGit(tmp).CloneBare(origin, filepath.Join(rigPath, MirrorDir)); err != nil {
+		t.Fatal(err)
+	}
+	mirror := filepath.Join(rigPath, MirrorDir)
+	runGit(t, tmp, "--git-dir="+mirror, "worktree", "add", filepath.Join(rigPath, "refinery", "rig"), "main")
+	polecat := filepath.Join(rigPath, "polecats", "toast")
+	runGit(t, tmp, "--git-dir="+mirror, "worktree", "add", "-b", "polecat/toast", polecat, "main")
+	commitFile(t, polecat, "feature.txt", "feature\n", "feat: add feature")
+
+	return rigPath, origin, polecat
+}
+
+func mirrorTestEngineer(rigPath string) *Engineer {
+	e := NewEngineer(&rig.Rig{Name: "greenplace", Path: rigPath})
+	e.SetOutput(io.Discard)
+	// Passes only if tests run against the merged tree
+	e.config.RunTests = true
+	e.config.TestCommand = "test -f feature.txt"
+	return e
+}
+
+func TestOpenMirrorLegacyRig(t *testing.T) {
+	if _, err := OpenMirror(t.TempDir()); err != ErrNoMirror {
+		t.Errorf("OpenMirror without %s = %v, want ErrNoMirror", MirrorDir, err)
+	}
+}
+
+func TestMergeInWorktree(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	if e.mirror == nil {
+		t.Fatal("expected engineer to use the rig mirror")
+	}
+
+	result := e.doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+	if !result.Success {
+		t.Fatalf("doMerge failed: %s", result.Error)
+	}
+
+	if got := runGit(t, origin, "log", "-1", "--format=%s", "main"); got != "feat: add feature" {
+		t.Errorf("origin main head = %q, want the squashed polecat commit", got)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("origin main = %s, want merge commit %s", got, result.MergeCommit)
+	}
+	// The refinery's checkout was fast-forwarded, not used for the merge
+	refineryRig := filepath.Join(rigPath, "refinery", "rig")
+	if _, err := os.Stat(filepath.Join(refineryRig, "feature.txt")); err != nil {
+		t.Errorf("refinery checkout not fast-forwarded: %v", err)
+	}
+	if list := runGit(t, refineryRig, "worktree", "list"); strings.Contains(list, mergeWorktreePrefix) {
+		t.Errorf("merge worktree left behind:\n%s", list)
+	}
+}
+
+func TestMergeInWorktreeConflict(t *testing.T) {
+	rigPath, origin, polecat := setupMirrorRig(t)
+	commitFile(t, polecat, "README.md", "# Toast\n", "docs: retitle")
+
+	// Main moves on with a conflicting change
+	other := filepath.Join(t.TempDir(), "other")
+	runGit(t, filepath.Dir(other), "clone", origin, other)
+	commitFile(t, other, "README.md", "# Nux\n", "docs: other retitle")
+	runGit(t, other, "push", "origin", "main")
+
+	e := mirrorTestEngineer(rigPath)
+	refineryRig := filepath.Join(rigPath, "refinery", "rig")
+	before := runGit(t, refineryRig, "rev-parse", "HEAD")
+
+	result := e.doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+	if result.Success || !result.Conflict {
+		t.Fatalf("doMerge = %+v, want conflict", result)
+	}
+	if !strings.Contains(result.Error, "README.md") {
+		t.Errorf("error %q should name the conflicting file", result.Error)
+	}
+	if after := runGit(t, refineryRig, "rev-parse", "HEAD"); after != before {
+		t.Error("refinery checkout moved on a failed merge")
+	}
+	if status := runGit(t, refineryRig, "status", "--porcelain"); status != "" {
+		t.Errorf("refinery checkout dirtied:\n%s", status)
+	}
+}
+
+func TestMergeInWorktreePushRemote(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	tmp := filepath.Dir(origin)
+	fork := filepath.Join(tmp, "fork.git")
+	internal := filepath.Join(tmp, "internal.git")
+	runGit(t, tmp, "clone", "--bare", origin, fork)
+	runGit(t, tmp, "clone", "--bare", origin, internal)
+	if err := rig.SaveRigConfig(rigPath, &rig.RigConfig{
+		Type:   "rig",
+		Name:   "greenplace",
+		GitURL: origin,
+		Remotes: []rig.RemoteConfig{
+			{Name: "fork", URL: fork},
+			{Name: "internal", URL: internal, Mirror: true},
+		},
+		PushRemote: "fork",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rig.ConfigureRemotes(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	before := runGit(t, origin, "rev-parse", "main")
+
+	result := mirrorTestEngineer(rigPath).doMerge(context.Background(), "gp-mr-1", "polecat/toast", "main", "gp-1")
+	if !result.Success {
+		t.Fatalf("doMerge failed: %s", result.Error)
+	}
+	if got := runGit(t, fork, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("fork main = %s, want merge commit %s", got, result.MergeCommit)
+	}
+	if got := runGit(t, internal, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("mirror main = %s, want merge commit %s", got, result.MergeCommit)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != before {
+		t.Error("merge pushed to origin instead of the push remote")
+	}
+}
@@ -0,0 +1,143 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RuleOwnerApproval labels the violation for an MR touching owned paths
+// without sign-off from their owners.
+const RuleOwnerApproval = "owner_approval"
+
+// OwnerApproval is the sign-off one ownership rule needs on an MR: the
+// files it owns there, and who approved for them.
+type OwnerApproval struct {
+	Path       string   `json:"path"`
+	Owners     []string `json:"owners"`
+	Files      []string `json:"files"`
+	ApprovedBy string   `json:"approved_by,omitempty"` // "" = outstanding
+}
+
+// Ownership maps a rig's files to their owners, from merge_queue.owners.
+// A nil *Ownership owns nothing.
+type Ownership struct {
+	rules []config.OwnershipRule
+}
+
+// NewOwnership builds an Ownership from config. Returns nil (no owners) if
+// there are no rules.
+func NewOwnership(rules []config.OwnershipRule) (*Ownership, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	for _, rule := range rules {
+		if rule.Path == "" {
+			return nil, fmt.Errorf("%w: owners rule with no path", ErrInvalidProtectionRule)
+		}
+		if _, err := path.Match(strings.TrimSuffix(rule.Path, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("%w: owned path %q: %v", ErrInvalidProtectionRule, rule.Path, err)
+		}
+	}
+	return &Ownership{rules: rules}, nil
+}
+
+// LoadOwnership reads ownership rules from a rig's settings/config.json. A
+// missing settings file or owners section yields nil (no owners).
+func LoadOwnership(rigPath string) (*Ownership, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewOwnership(settings.MergeQueue.Owners)
+}
+
+// Approvals returns the sign-offs an MR changing files needs, one per
+// ownership rule that owns any of them, in rule order. Each is marked
+// approved by the first of approvedBy who is one of its owners.
+func (o *Ownership) Approvals(files, approvedBy []string) []OwnerApproval {
+	if o == nil {
+		return nil
+	}
+	byRule := make(map[int]*OwnerApproval)
+	for _, file := range files {
+		i := o.ruleFor(file)
+		if i < 0 {
+			continue
+		}
+		if byRule[i] == nil {
+			byRule[i] = &OwnerApproval{Path: o.rules[i].Path, Owners: o.rules[i].Owners}
+		}
+		byRule[i].Files = append(byRule[i].Files, file)
+	}
+
+	var approvals []OwnerApproval
+	for i := range o.rules {
+		a := byRule[i]
+		if a == nil {
+			continue
+		}
+	find:
+		for _, approver := range approvedBy {
+			for _, owner := range a.Owners {
+				if approverMatches(owner, approver) {
+					a.ApprovedBy = approver
+					break find
+				}
+			}
+		}
+		approvals = append(approvals, *a)
+	}
+	return approvals
+}
+
+// CheckApproval returns a violation if an MR changing files lacks sign-off
+// from the owners of any of them. Returns nil otherwise.
+func (o *Ownership) CheckApproval(files []string, review MRReview) *ProtectionViolation {
+	outstanding := OutstandingOwners(o.Approvals(files, review.ApprovedBy))
+	if len(outstanding) == 0 {
+		return nil
+	}
+	needs := make([]string, len(outstanding))
+	for i, a := range outstanding {
+		needs[i] = fmt.Sprintf("%s (%s)", a.Path, strings.Join(a.Owners, " or "))
+	}
+	return &ProtectionViolation{
+		Rule:   RuleOwnerApproval,
+		Reason: "needs owner approval for " + strings.Join(needs, ", "),
+	}
+}
+
+// OutstandingOwners returns the approvals still waiting on an owner.
+func OutstandingOwners(approvals []OwnerApproval) []OwnerApproval {
+	var outstanding []OwnerApproval
+	for _, a := range approvals {
+		if a.ApprovedBy == "" {
+			outstanding = append(outstanding, a)
+		}
+	}
+	return outstanding
+}
+
+// ruleFor returns the index of the rule owning file (the last that matches),
+// or -1 if the file is unowned. A rule with no owners disowns its files.
+func (o *Ownership) ruleFor(file string) int {
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if matchProtectedPath(o.rules[i].Path, file) {
+			if len(o.rules[i].Owners) == 0 {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,88 @@
+package refinery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestOwnershipApprovals(t *testing.T) {
+	o, err := NewOwnership([]config.OwnershipRule{
+		{Path: "internal/**", Owners: []string{"mayor"}},
+		{Path: "internal/auth/**", Owners: []string{"greenplace/crew/max", "human"}},
+		{Path: "internal/auth/testdata/**"}, // Unowned
+		{Path: "*.sql", Owners: []string{"greenplace/crew/dba"}},
+	})
+	if err != nil {
+		t.Fatalf("NewOwnership: %v", err)
+	}
+
+	files := []string{
+		"internal/auth/token.go",
+		"internal/auth/testdata/fixture.json",
+		"internal/store/db.go",
+		"migrations/001.sql",
+		"README.md",
+	}
+	approvals := o.Approvals(files, []string{"greenplace/crew/max/"})
+	if len(approvals) != 3 {
+		t.Fatalf("got %d approvals, want 3: %+v", len(approvals), approvals)
+	}
+	want := []struct {
+		path, files, approvedBy string
+	}{
+		{"internal/**", "internal/store/db.go", ""},
+		{"internal/auth/**", "internal/auth/token.go", "greenplace/crew/max/"},
+		{"*.sql", "migrations/001.sql", ""},
+	}
+	for i, w := range want {
+		a := approvals[i]
+		if a.Path != w.path || strings.Join(a.Files, ",") != w.files || a.ApprovedBy != w.approvedBy {
+			t.Errorf("approval %d = %+v, want %s %s approved by %q", i, a, w.path, w.files, w.approvedBy)
+		}
+	}
+	if got := len(OutstandingOwners(approvals)); got != 2 {
+		t.Errorf("outstanding = %d, want 2", got)
+	}
+
+	// A role approves for its members
+	v := o.CheckApproval(files, MRReview{ApprovedBy: []string{"mayor", "overseer", "greenplace/crew/dba"}})
+	if v != nil {
+		t.Errorf("all owners approved, got violation %+v", v)
+	}
+
+	v = o.CheckApproval(files, MRReview{ApprovedBy: []string{"mayor"}})
+	if v == nil || v.Rule != RuleOwnerApproval {
+		t.Fatalf("expected owner_approval violation, got %+v", v)
+	}
+	if !strings.Contains(v.Reason, "internal/auth/** (greenplace/crew/max or human)") || strings.Contains(v.Reason, "internal/**") {
+		t.Errorf("reason = %q", v.Reason)
+	}
+
+	if o.CheckApproval([]string{"README.md"}, MRReview{}) != nil {
+		t.Error("unowned files need no owner approval")
+	}
+}
+
+func TestOwnershipNil(t *testing.T) {
+	o, err := NewOwnership(nil)
+	if err != nil || o != nil {
+		t.Fatalf("NewOwnership(nil) = %v, %v; want nil, nil", o, err)
+	}
+	if o.Approvals([]string{"main.go"}, nil) != nil || o.CheckApproval([]string{"main.go"}, MRReview{}) != nil {
+		t.Error("nil ownership should own nothing")
+	}
+}
+
+func TestNewOwnershipRejectsBadRules(t *testing.T) {
+	for _, rule := range []config.OwnershipRule{
+		{Owners: []string{"mayor"}},
+		{Path: "[bad", Owners: []string{"mayor"}},
+	} {
+		if _, err := NewOwnership([]config.OwnershipRule{rule}); !errors.Is(err, ErrInvalidProtectionRule) {
+			t.Errorf("%+v: err = %v, want ErrInvalidProtectionRule", rule, err)
+		}
+	}
+}
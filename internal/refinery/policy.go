@@ -0,0 +1,655 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// RulePolicy labels violations of a rig's merge policies; the violation's
+// Policy field names the policy.
+const RulePolicy = "policy"
+
+// PolicyInput is what merge policies are evaluated against: an MR's diff,
+// review state and risk, and the time it would merge.
+type PolicyInput struct {
+	Stats  []git.FileDiffStat
+	Review MRReview
+	Risk   RiskAssessment
+	Branch string
+	Target string
+	Now    time.Time
+}
+
+// Policies evaluates a rig's merge_queue.policies. A nil *Policies permits
+// every MR.
+type Policies struct {
+	rules []policy
+}
+
+type policy struct {
+	name    string
+	message string
+	deny    policyExpr
+}
+
+// NewPolicies compiles merge policies from config. Returns nil (no
+// policies) if there are none.
+func NewPolicies(rules []config.MergePolicyRule) (*Policies, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	p := &Policies{}
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("%w: policy with no name", ErrInvalidProtectionRule)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("%w: duplicate policy %q", ErrInvalidProtectionRule, rule.Name)
+		}
+		seen[rule.Name] = true
+		deny, err := parsePolicy(rule.Deny)
+		if err != nil {
+			return nil, fmt.Errorf("%w: policy %q: %v", ErrInvalidProtectionRule, rule.Name, err)
+		}
+		p.rules = append(p.rules, policy{name: rule.Name, message: rule.Message, deny: deny})
+	}
+	return p, nil
+}
+
+// LoadPolicies reads merge policies from a rig's settings/config.json. A
+// missing settings file or policies section yields nil (no policies).
+func LoadPolicies(rigPath string) (*Policies, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewPolicies(settings.MergeQueue.Policies)
+}
+
+// Check returns a violation for each policy that denies the MR, in config
+// order. Each reason names the policy and shows the values of the
+// attributes its rule refers to, e.g.
+// `big-diffs: lines > 500 && approvals < 2 (lines=812, approvals=1)`.
+func (p *Policies) Check(in PolicyInput) []ProtectionViolation {
+	if p == nil {
+		return nil
+	}
+	env := newPolicyEnv(in)
+	var violations []ProtectionViolation
+	for _, rule := range p.rules {
+		if !rule.deny.eval(env).(bool) {
+			continue
+		}
+		reason := rule.message
+		if reason == "" {
+			reason = rule.deny.String()
+		}
+		if facts := explainPolicy(rule.deny, env); facts != "" {
+			reason += " (" + facts + ")"
+		}
+		violations = append(violations, ProtectionViolation{
+			Rule:   RulePolicy,
+			Policy: rule.name,
+			Reason: rule.name + ": " + reason,
+		})
+	}
+	return violations
+}
+
+// Policy expression language
+//
+// A rule is a boolean expression over the MR's attributes:
+//
+//	lines > 500 && approvals < 2
+//	touches("migrations/**") && !approved_by("human")
+//	author_role == "polecat" && (hour >= 18 || weekday in ["sat", "sun"])
+//	risk_level == "high" && !("security" in checks)
+//
+// Operators, loosest first: ||, &&, !, then comparisons (== != < <= > >=)
+// and "in" (membership in a list). Literals are integers, double-quoted
+// strings, true/false, and lists of strings in brackets.
+
+type policyType int
+
+const (
+	policyBool policyType = iota
+	policyInt
+	policyString
+	policyList
+)
+
+func (t policyType) String() string {
+	return [...]string{"bool", "int", "string", "list"}[t]
+}
+
+// policyAttributes are the MR attributes rules can refer to.
+var policyAttributes = map[string]policyType{
+	"lines":       policyInt,    // Lines added plus deleted
+	"files":       policyInt,    // Files changed
+	"paths":       policyList,   // Paths changed
+	"risk":        policyInt,    // Risk score, 0-100
+	"risk_level":  policyString, // "low", "medium", or "high"
+	"approvals":   policyInt,    // Distinct approvers
+	"approvers":   policyList,   // Approver addresses
+	"checks":      policyList,   // Checks passed
+	"author":      policyString, // Who submitted the MR
+	"author_role": policyString, // Their role (see ApproverRole)
+	"branch":      policyString, // Source branch
+	"target":      policyString, // Target branch
+	"priority":    policyInt,    // 0 (urgent) to 4
+	"hour":        policyInt,    // Hour of day, 0-23, local time
+	"weekday":     policyString, // "mon" ... "sun"
+}
+
+// policyFunctions are the functions rules can call. Each takes one string.
+var policyFunctions = map[string]func(env *policyEnv, arg string) bool{
+	// touches(pattern): the MR changes a path matching pattern, matched
+	// like forbidden_paths ("dir/**", "*.pem")
+	"touches": func(env *policyEnv, pattern string) bool {
+		for _, file := range env.attrs["paths"].([]string) {
+			if matchProtectedPath(pattern, file) {
+				return true
+			}
+		}
+		return false
+	},
+	// approved_by(who): approved by a role (see ApproverRole) or address
+	"approved_by": func(env *policyEnv, who string) bool {
+		for _, a := range env.attrs["approvers"].([]string) {
+			if approverMatches(who, a) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+type policyEnv struct {
+	attrs map[string]any
+}
+
+func newPolicyEnv(in PolicyInput) *policyEnv {
+	change := changeFromStats(in.Stats)
+	approvers := dedupe(in.Review.ApprovedBy)
+	now := in.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return &policyEnv{attrs: map[string]any{
+		"lines":       change.Lines,
+		"files":       len(change.Files),
+		"paths":       nonNil(change.Files),
+		"risk":        in.Risk.Score,
+		"risk_level":  in.Risk.Level,
+		"approvals":   len(approvers),
+		"approvers":   approvers,
+		"checks":      nonNil(in.Review.ChecksPassed),
+		"author":      in.Review.Author,
+		"author_role": authorRole(in.Review.Author),
+		"branch":      in.Branch,
+		"target":      in.Target,
+		"priority":    in.Review.Priority,
+		"hour":        now.Hour(),
+		"weekday":     strings.ToLower(now.Weekday().String()[:3]),
+	}}
+}
+
+// authorRole classifies an MR's author like an approver. An unknown author
+// has no role.
+func authorRole(author string) string {
+	if author == "" {
+		return ""
+	}
+	return ApproverRole(author)
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	out := []string{}
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+// explainPolicy lists the attributes and function calls in expr with
+// their values in env, in the order they appear.
+func explainPolicy(expr policyExpr, env *policyEnv) string {
+	var facts []string
+	seen := make(map[string]bool)
+	var walk func(policyExpr)
+	walk = func(x policyExpr) {
+		switch x := x.(type) {
+		case *policyAttr, *policyCall:
+			if s := x.String(); !seen[s] {
+				seen[s] = true
+				facts = append(facts, s+"="+formatPolicyValue(x.eval(env)))
+			}
+		case *policyNot:
+			walk(x.x)
+		case *policyBinary:
+			walk(x.left)
+			walk(x.right)
+		}
+	}
+	walk(expr)
+	return strings.Join(facts, ", ")
+}
+
+func formatPolicyValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// policyExpr is a type-checked policy expression.
+type policyExpr interface {
+	typ() policyType
+	eval(env *policyEnv) any
+	String() string
+}
+
+type policyLiteral struct {
+	t   policyType
+	val any
+	src string
+}
+
+func (x *policyLiteral) typ() policyType     { return x.t }
+func (x *policyLiteral) eval(*policyEnv) any { return x.val }
+func (x *policyLiteral) String() string      { return x.src }
+
+type policyAttr struct{ name string }
+
+func (x *policyAttr) typ() policyType         { return policyAttributes[x.name] }
+func (x *policyAttr) eval(env *policyEnv) any { return env.attrs[x.name] }
+func (x *policyAttr) String() string          { return x.name }
+
+type policyCall struct {
+	name string
+	arg  string
+}
+
+func (x *policyCall) typ() policyType { return policyBool }
+func (x *policyCall) eval(env *policyEnv) any {
+	return policyFunctions[x.name](env, x.arg)
+}
+func (x *policyCall) String() string { return x.name + "(" + strconv.Quote(x.arg) + ")" }
+
+type policyNot struct{ x policyExpr }
+
+func (x *policyNot) typ() policyType         { return policyBool }
+func (x *policyNot) eval(env *policyEnv) any { return !x.x.eval(env).(bool) }
+func (x *policyNot) String() string          { return "!" + x.x.String() }
+
+type policyBinary struct {
+	op          string
+	left, right policyExpr
+	paren       bool // written in parentheses
+}
+
+func (x *policyBinary) typ() policyType { return policyBool }
+
+func (x *policyBinary) eval(env *policyEnv) any {
+	switch x.op {
+	case "&&":
+		return x.left.eval(env).(bool) && x.right.eval(env).(bool)
+	case "||":
+		return x.left.eval(env).(bool) || x.right.eval(env).(bool)
+	case "in":
+		needle := x.left.eval(env).(string)
+		for _, item := range x.right.eval(env).([]string) {
+			if item == needle {
+				return true
+			}
+		}
+		return false
+	}
+
+	l, r := x.left.eval(env), x.right.eval(env)
+	if li, ok := l.(int); ok {
+		ri := r.(int)
+		switch x.op {
+		case "<":
+			return li < ri
+		case "<=":
+			return li <= ri
+		case ">":
+			return li > ri
+		case ">=":
+			return li >= ri
+		}
+	}
+	switch x.op {
+	case "==":
+		return l == r
+	default: // "!="
+		return l != r
+	}
+}
+
+func (x *policyBinary) String() string {
+	s := x.left.String() + " " + x.op + " " + x.right.String()
+	if x.paren {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// parsePolicy parses and type-checks a policy rule, which must be boolean.
+func parsePolicy(src string) (policyExpr, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("empty deny expression")
+	}
+	p := &policyParser{src: src}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	if expr.typ() != policyBool {
+		return nil, fmt.Errorf("deny expression %q is a %s, not a condition", src, expr.typ())
+	}
+	return expr, nil
+}
+
+type policyTokenKind int
+
+const (
+	tokEOF policyTokenKind = iota
+	tokIdent
+	tokInt
+	tokString
+	tokOp
+)
+
+type policyToken struct {
+	kind policyTokenKind
+	text string // operator, identifier, or literal source
+	val  string // unquoted string value
+	pos  int
+}
+
+type policyParser struct {
+	src    string
+	tokens []policyToken
+	next   int
+}
+
+func (p *policyParser) errorf(tok policyToken, format string, args ...any) error {
+	return fmt.Errorf("column %d: %s", tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *policyParser) lex() error {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+				j++
+			}
+			p.tokens = append(p.tokens, policyToken{kind: tokInt, text: src[i:j], pos: i})
+			i = j
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			j := i
+			for j < len(src) && (src[j] == '_' || (src[j]|0x20 >= 'a' && src[j]|0x20 <= 'z') || (src[j] >= '0' && src[j] <= '9')) {
+				j++
+			}
+			p.tokens = append(p.tokens, policyToken{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return fmt.Errorf("column %d: unterminated string", i+1)
+			}
+			val, err := strconv.Unquote(src[i : j+1])
+			if err != nil {
+				return fmt.Errorf("column %d: invalid string %s", i+1, src[i:j+1])
+			}
+			p.tokens = append(p.tokens, policyToken{kind: tokString, text: src[i : j+1], val: val, pos: i})
+			i = j + 1
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("column %d: unexpected %q", i+1, c)
+			}
+			p.tokens = append(p.tokens, policyToken{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, policyToken{kind: tokEOF, text: "end of rule", pos: len(src)})
+	return nil
+}
+
+func (p *policyParser) peek() policyToken { return p.tokens[p.next] }
+
+func (p *policyParser) take() policyToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *policyParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *policyParser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return p.errorf(tok, "expected %q, found %q", op, tok.text)
+	}
+	return nil
+}
+
+func (p *policyParser) parseOr() (policyExpr, error) {
+	return p.parseLogic("||", p.parseAnd)
+}
+
+func (p *policyParser) parseAnd() (policyExpr, error) {
+	return p.parseLogic("&&", p.parseUnary)
+}
+
+func (p *policyParser) parseLogic(op string, operand func() (policyExpr, error)) (policyExpr, error) {
+	start := p.peek()
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == op {
+		if left.typ() != policyBool {
+			return nil, p.errorf(start, "%s is a %s; %s needs conditions", left, left.typ(), op)
+		}
+		p.take()
+		start = p.peek()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if right.typ() != policyBool {
+			return nil, p.errorf(start, "%s is a %s; %s needs conditions", right, right.typ(), op)
+		}
+		left = &policyBinary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *policyParser) parseUnary() (policyExpr, error) {
+	tok := p.peek()
+	if p.accept("!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.typ() != policyBool {
+			return nil, p.errorf(tok, "cannot negate %s (a %s)", x, x.typ())
+		}
+		return &policyNot{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *policyParser) parseComparison() (policyExpr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	op := tok.text
+	switch {
+	case tok.kind == tokOp && (op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="):
+	case tok.kind == tokIdent && op == "in":
+	default:
+		return left, nil
+	}
+	p.take()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case op == "in":
+		if left.typ() != policyString || right.typ() != policyList {
+			return nil, p.errorf(tok, "\"in\" needs a string and a list, not %s and %s", left.typ(), right.typ())
+		}
+	case left.typ() != right.typ():
+		return nil, p.errorf(tok, "cannot compare %s (%s) with %s (%s)", left, left.typ(), right, right.typ())
+	case left.typ() == policyList:
+		return nil, p.errorf(tok, "cannot compare lists; use \"in\"")
+	case op != "==" && op != "!=" && left.typ() != policyInt:
+		return nil, p.errorf(tok, "%s needs integers, not %s", op, left.typ())
+	}
+	return &policyBinary{op: op, left: left, right: right}, nil
+}
+
+func (p *policyParser) parsePrimary() (policyExpr, error) {
+	tok := p.take()
+	switch tok.kind {
+	case tokInt:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %s", tok.text)
+		}
+		return &policyLiteral{t: policyInt, val: n, src: tok.text}, nil
+	case tokString:
+		return &policyLiteral{t: policyString, val: tok.val, src: tok.text}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return &policyLiteral{t: policyBool, val: tok.text == "true", src: tok.text}, nil
+		}
+		if _, ok := policyFunctions[tok.text]; ok {
+			return p.parseCall(tok)
+		}
+		if _, ok := policyAttributes[tok.text]; !ok {
+			return nil, p.errorf(tok, "unknown attribute %q", tok.text)
+		}
+		return &policyAttr{name: tok.text}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if b, ok := x.(*policyBinary); ok {
+				b.paren = true
+			}
+			return x, nil
+		case "[":
+			return p.parseList()
+		}
+	}
+	return nil, p.errorf(tok, "unexpected %q", tok.text)
+}
+
+func (p *policyParser) parseCall(name policyToken) (policyExpr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arg := p.take()
+	if arg.kind != tokString {
+		return nil, p.errorf(arg, "%s takes a string, found %q", name.text, arg.text)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &policyCall{name: name.text, arg: arg.val}, nil
+}
+
+func (p *policyParser) parseList() (policyExpr, error) {
+	items := []string{}
+	var src []string
+	for !p.accept("]") {
+		if len(items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		tok := p.take()
+		if tok.kind != tokString {
+			return nil, p.errorf(tok, "lists hold strings, found %q", tok.text)
+		}
+		items = append(items, tok.val)
+		src = append(src, tok.text)
+	}
+	return &policyLiteral{t: policyList, val: items, src: "[" + strings.Join(src, ", ") + "]"}, nil
+}
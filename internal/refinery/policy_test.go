@@ -0,0 +1,131 @@
+package refinery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestNewPoliciesRejectsBadRules(t *testing.T) {
+	for _, deny := range []string{
+		"",
+		"lines",                  // not a condition
+		"lines > ",               // incomplete
+		"lines > \"big\"",        // type mismatch
+		"author < \"m\"",         // ordering on strings
+		"sizes > 5",              // unknown attribute
+		"touches(5)",             // wrong argument
+		"lines > 5 && risk",      // non-boolean operand
+		"\"x\" in author",        // in needs a list
+		"(lines > 5",             // unbalanced
+		"lines > 5 lines",        // trailing tokens
+		"weekday in [\"sat\" 1]", // malformed list
+		"lines % 2 == 0",         // unknown operator
+	} {
+		_, err := NewPolicies([]config.MergePolicyRule{{Name: "p", Deny: deny}})
+		if !errors.Is(err, ErrInvalidProtectionRule) {
+			t.Errorf("deny %q: err = %v, want ErrInvalidProtectionRule", deny, err)
+		}
+	}
+
+	if _, err := NewPolicies([]config.MergePolicyRule{{Deny: "true"}}); err == nil {
+		t.Error("a policy without a name should be rejected")
+	}
+	if _, err := NewPolicies([]config.MergePolicyRule{{Name: "p", Deny: "true"}, {Name: "p", Deny: "false"}}); err == nil {
+		t.Error("duplicate policy names should be rejected")
+	}
+	if p, err := NewPolicies(nil); p != nil || err != nil {
+		t.Errorf("NewPolicies(nil) = %v, %v; want nil, nil", p, err)
+	}
+}
+
+func TestPoliciesCheck(t *testing.T) {
+	saturday := time.Date(2026, 10, 17, 20, 30, 0, 0, time.UTC)
+	in := PolicyInput{
+		Stats: []git.FileDiffStat{
+			{Path: "db/migrations/001.sql", Added: 400},
+			{Path: "main.go", Added: 300, Deleted: 112},
+		},
+		Review: MRReview{
+			ChecksPassed: []string{"tests"},
+			ApprovedBy:   []string{"greenplace/crew/max", "greenplace/crew/max"},
+			Author:       "greenplace/polecats/toast",
+			Priority:     2,
+		},
+		Risk:   RiskAssessment{Score: 70, Level: RiskHigh},
+		Branch: "polecat/toast",
+		Target: "main",
+		Now:    saturday,
+	}
+
+	tests := []struct {
+		deny string
+		want bool
+	}{
+		{"lines > 500 && approvals < 2", true},
+		{"lines > 1000 || files > 2", false},
+		{"touches(\"db/migrations/**\") && !approved_by(\"human\")", true},
+		{"touches(\"*.pem\")", false},
+		{"approved_by(\"crew\")", true},
+		{"author_role == \"polecat\" && (hour >= 18 || weekday in [\"sat\", \"sun\"])", true},
+		{"weekday in [\"mon\", \"tue\"]", false},
+		{"risk_level == \"high\" && !(\"security\" in checks)", true},
+		{"risk >= 80", false},
+		{"priority <= 1", false},
+		{"target != \"main\" || branch == \"polecat/toast\"", true},
+		{"\"main.go\" in paths", true},
+		{"!true", false},
+	}
+	for _, tt := range tests {
+		p, err := NewPolicies([]config.MergePolicyRule{{Name: "p", Deny: tt.deny}})
+		if err != nil {
+			t.Errorf("deny %q: %v", tt.deny, err)
+			continue
+		}
+		if got := len(p.Check(in)) > 0; got != tt.want {
+			t.Errorf("deny %q = %v, want %v", tt.deny, got, tt.want)
+		}
+	}
+}
+
+func TestPoliciesExplain(t *testing.T) {
+	p, err := NewPolicies([]config.MergePolicyRule{
+		{Name: "big-diffs", Deny: "lines > 500 && approvals < 2"},
+		{Name: "no-weekend-migrations", Deny: "touches(\"migrations/**\") && weekday in [\"sat\", \"sun\"]", Message: "no migrations on weekends"},
+		{Name: "never", Deny: "false"},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicies: %v", err)
+	}
+	violations := p.Check(PolicyInput{
+		Stats:  []git.FileDiffStat{{Path: "migrations/001.sql", Added: 812}},
+		Review: MRReview{ApprovedBy: []string{"mayor/"}},
+		Now:    time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	})
+	if len(violations) != 2 {
+		t.Fatalf("violations = %+v, want 2", violations)
+	}
+
+	want := []string{
+		"big-diffs: lines > 500 && approvals < 2 (lines=812, approvals=1)",
+		"no-weekend-migrations: no migrations on weekends (touches(\"migrations/**\")=true, weekday=\"sun\")",
+	}
+	for i, v := range violations {
+		if v.Rule != RulePolicy || v.Reason != want[i] {
+			t.Errorf("violation %d = %+v, want reason %q", i, v, want[i])
+		}
+	}
+	if violations[0].Policy != "big-diffs" {
+		t.Errorf("Policy = %q, want big-diffs", violations[0].Policy)
+	}
+	if HasChangeViolation(violations) {
+		t.Error("policy violations should hold the MR, not reject it")
+	}
+	if !strings.Contains(FormatViolations(violations), "big-diffs") {
+		t.Error("FormatViolations should name the policy")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package refinery
+
+import (
+	"os"
+	"syscall"
+)
+
+// processExists checks if a process with the given PID exists and is alive.
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	// On Unix, sending signal 0 checks if process exists without affecting it.
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
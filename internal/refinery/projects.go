@@ -0,0 +1,166 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Projects are a monorepo rig's sub-projects, from merge_queue.projects.
+// An MR changing only a project's files gets the project's test command
+// and SLA, and its own sub-queue if the project has one. A nil *Projects
+// has none: every MR is the rig's.
+type Projects struct {
+	projects []config.MergeProjectConfig
+}
+
+// NewProjects builds Projects from config. Returns nil (no projects) if
+// there are none.
+func NewProjects(cfg []config.MergeProjectConfig) (*Projects, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool)
+	for _, project := range cfg {
+		if project.Name == "" {
+			return nil, fmt.Errorf("merge project with no name")
+		}
+		if seen[project.Name] {
+			return nil, fmt.Errorf("duplicate merge project %q", project.Name)
+		}
+		seen[project.Name] = true
+		if len(project.Paths) == 0 {
+			return nil, fmt.Errorf("merge project %s has no paths", project.Name)
+		}
+		for _, p := range project.Paths {
+			if _, err := path.Match(strings.TrimSuffix(p, "/**"), ""); err != nil {
+				return nil, fmt.Errorf("merge project %s: path %q: %v", project.Name, p, err)
+			}
+		}
+		if project.SkipTests && project.TestCommand != "" {
+			return nil, fmt.Errorf("merge project %s sets both test_command and skip_tests", project.Name)
+		}
+	}
+	return &Projects{projects: cfg}, nil
+}
+
+// LoadProjects reads a rig's sub-projects from its settings/config.json. A
+// missing settings file or projects section yields nil (no projects).
+func LoadProjects(rigPath string) (*Projects, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewProjects(settings.MergeQueue.Projects)
+}
+
+// Match returns the project an MR changing files belongs to: the first
+// whose paths cover every one of them. Returns "" if none does, or files
+// is empty.
+func (p *Projects) Match(files []string) string {
+	if p == nil || len(files) == 0 {
+		return ""
+	}
+	for _, project := range p.projects {
+		covered := true
+		for _, file := range files {
+			if !projectCovers(project, file) {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return project.Name
+		}
+	}
+	return ""
+}
+
+// get returns the named project, or nil if there is none.
+func (p *Projects) get(name string) *config.MergeProjectConfig {
+	if p == nil || name == "" {
+		return nil
+	}
+	for i := range p.projects {
+		if p.projects[i].Name == name {
+			return &p.projects[i]
+		}
+	}
+	return nil
+}
+
+// TestCommand returns the command that tests MRs in project, given the
+// merge queue's: the project's own, "" if it skips tests, or the merge
+// queue's.
+func (p *Projects) TestCommand(project, fallback string) string {
+	cfg := p.get(project)
+	switch {
+	case cfg == nil:
+		return fallback
+	case cfg.SkipTests:
+		return ""
+	case cfg.TestCommand != "":
+		return cfg.TestCommand
+	}
+	return fallback
+}
+
+// SkipsTests reports whether MRs in project run no tests.
+func (p *Projects) SkipsTests(project string) bool {
+	cfg := p.get(project)
+	return cfg != nil && cfg.SkipTests
+}
+
+// Queue returns the sub-queue MRs in project wait in within their target:
+// the project's name if it has its own, else "" (the target's main one).
+func (p *Projects) Queue(project string) string {
+	if cfg := p.get(project); cfg != nil && cfg.Queue {
+		return cfg.Name
+	}
+	return ""
+}
+
+// SplitByProject splits each of the target sub-queues further, giving
+// each project with its own queue a sub-queue after its target's main
+// one, in project order. Items keep their order within each.
+func SplitByProject[T any](p *Projects, queues []SubQueue[T], project func(T) string) []SubQueue[T] {
+	if p == nil {
+		return queues
+	}
+	var split []SubQueue[T]
+	for _, q := range queues {
+		byQueue := make(map[string][]T)
+		for _, item := range q.Items {
+			name := p.Queue(project(item))
+			byQueue[name] = append(byQueue[name], item)
+		}
+		if items, ok := byQueue[""]; ok {
+			split = append(split, SubQueue[T]{Target: q.Target, Items: items})
+		}
+		for _, cfg := range p.projects {
+			if items, ok := byQueue[cfg.Name]; ok && cfg.Queue {
+				split = append(split, SubQueue[T]{Target: q.Target, Project: cfg.Name, Items: items})
+			}
+		}
+	}
+	return split
+}
+
+// projectCovers reports whether file is one of project's.
+func projectCovers(project config.MergeProjectConfig, file string) bool {
+	for _, pattern := range project.Paths {
+		if matchProtectedPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewProjects(t *testing.T) {
+	for _, cfg := range [][]config.MergeProjectConfig{
+		{{Paths: []string{"docs/**"}}},
+		{{Name: "docs"}},
+		{{Name: "docs", Paths: []string{"docs/**"}}, {Name: "docs", Paths: []string{"*.md"}}},
+		{{Name: "docs", Paths: []string{"docs/[**"}}},
+		{{Name: "docs", Paths: []string{"docs/**"}, TestCommand: "make docs", SkipTests: true}},
+	} {
+		if _, err := NewProjects(cfg); err == nil {
+			t.Errorf("NewProjects(%+v) should fail", cfg)
+		}
+	}
+	if p, err := NewProjects(nil); err != nil || p != nil {
+		t.Errorf("NewProjects(nil) = %v, %v; want none", p, err)
+	}
+}
+
+func TestProjectsMatch(t *testing.T) {
+	projects, err := NewProjects([]config.MergeProjectConfig{
+		{Name: "docs", Paths: []string{"docs/**", "*.md"}, SkipTests: true, Queue: true},
+		{Name: "web", Paths: []string{"web/**"}, TestCommand: "npm test"},
+		{Name: "compiler", Paths: []string{"compiler/**"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"docs/guide.md", "README.md", "web/README.md"}, "docs"},
+		{[]string{"web/app.ts", "web/README.md"}, "web"},
+		{[]string{"web/app.ts", "compiler/lex.go"}, ""},
+		{[]string{"Makefile"}, ""},
+		{nil, ""},
+	} {
+		if got := projects.Match(tt.files); got != tt.want {
+			t.Errorf("Match(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+
+	for project, want := range map[string]string{"docs": "", "web": "npm test", "compiler": "make test", "": "make test"} {
+		if got := projects.TestCommand(project, "make test"); got != want {
+			t.Errorf("TestCommand(%q) = %q, want %q", project, got, want)
+		}
+	}
+	if !projects.SkipsTests("docs") || projects.SkipsTests("web") {
+		t.Error("SkipsTests: want only docs")
+	}
+	if got := (*Projects)(nil).TestCommand("docs", "make test"); got != "make test" {
+		t.Errorf("nil Projects TestCommand = %q, want the merge queue's", got)
+	}
+}
+
+func TestSplitByProject(t *testing.T) {
+	targets, err := NewTargets("main", []config.MergeTargetConfig{{Branch: "release/1.2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects, err := NewProjects([]config.MergeProjectConfig{
+		{Name: "web", Paths: []string{"web/**"}},
+		{Name: "docs", Paths: []string{"docs/**"}, Queue: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mrs := []*MRInfo{
+		{ID: "a", Project: "docs"},
+		{ID: "b"},
+		{ID: "c", Project: "web"},
+		{ID: "d", Target: "release/1.2", Project: "docs"},
+		{ID: "e", Project: "docs"},
+	}
+
+	queues := SplitByTarget(targets, mrs, func(mr *MRInfo) string { return mr.Target })
+	var got []string
+	for _, q := range SplitByProject(projects, queues, func(mr *MRInfo) string { return mr.Project }) {
+		ids := q.Name() + ":"
+		for _, mr := range q.Items {
+			ids += mr.ID
+		}
+		got = append(got, ids)
+	}
+	want := []string{"main:bc", "main (docs):ae", "release/1.2 (docs):d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sub-queues = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSLAProjects(t *testing.T) {
+	rigPath := t.TempDir()
+	writeCheckSettings(t, rigPath, `{
+		"sla": {"targets": {"P2": "1d"}, "notify": "greenplace/witness"},
+		"projects": [
+			{"name": "docs", "paths": ["docs/**"], "sla": {"targets": {"P2": "2h"}}},
+			{"name": "web", "paths": ["web/**"]}
+		]
+	}`)
+	s, err := LoadSLA(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, _ := s.For("docs").Target(2); target != 2*time.Hour {
+		t.Errorf("docs P2 target = %v, want 2h", target)
+	}
+	if target, _ := s.For("web").Target(2); target != 24*time.Hour {
+		t.Errorf("web P2 target = %v, want the rig's 1d", target)
+	}
+	if got := s.For("docs").Notify(); got != "greenplace/witness" {
+		t.Errorf("docs notify = %q, want the rig's", got)
+	}
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	breaches := s.Breaches([]*beads.Issue{
+		{ID: "gt-1", Status: "open", Priority: 2, CreatedAt: now.Add(-3 * time.Hour).Format(time.RFC3339), Description: "branch: polecat/a\nproject: docs"},
+		{ID: "gt-2", Status: "open", Priority: 2, CreatedAt: now.Add(-3 * time.Hour).Format(time.RFC3339), Description: "branch: polecat/b\nproject: web"},
+	}, now)
+	if len(breaches) != 1 || breaches[0].ID != "gt-1" || breaches[0].Project != "docs" || breaches[0].Overdue != time.Hour {
+		t.Errorf("breaches = %+v, want gt-1 an hour past the docs target", breaches)
+	}
+
+	// A project SLA alone still gives the rig an SLA
+	writeCheckSettings(t, rigPath, `{"projects": [{"name": "docs", "paths": ["docs/**"], "sla": {"targets": {"P0": "1h"}}}]}`)
+	if s, err = LoadSLA(rigPath); err != nil || s == nil {
+		t.Fatalf("LoadSLA = %v, %v; want the docs SLA", s, err)
+	}
+	if _, ok := s.For("").Target(0); ok {
+		t.Error("rig MRs should have no target")
+	}
+	if _, ok := s.For("docs").Target(0); !ok {
+		t.Error("docs MRs should have a P0 target")
+	}
+}
@@ -0,0 +1,340 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// ErrInvalidProtectionRule indicates a malformed branch protection rule.
+var ErrInvalidProtectionRule = errors.New("invalid branch protection rule")
+
+// Branch protection rule names, used to label violations.
+const (
+	RuleRequiredChecks = "required_checks"
+	RuleMinApprovals   = "min_approvals"
+	RuleForbiddenPaths = "forbidden_paths"
+	RuleMaxDiffLines   = "max_diff_lines"
+
+	RuleChangesRequested = "changes_requested"
+)
+
+// ProtectionViolation is a branch protection rule an MR does not satisfy.
+type ProtectionViolation struct {
+	Rule   string `json:"rule"`
+	Policy string `json:"policy,omitempty"` // Merge policy name, for RulePolicy
+	Reason string `json:"reason"`
+}
+
+// MRChange summarizes what an MR changes relative to its target branch.
+type MRChange struct {
+	Files []string
+	Lines int // added plus deleted lines
+}
+
+// MRReview holds the review state recorded on an MR bead.
+type MRReview struct {
+	ChecksPassed       []string
+	ApprovedBy         []string
+	ChangesRequestedBy []string
+
+	// Who submitted the MR and its priority, for merge policies
+	Author   string
+	Priority int
+}
+
+// BranchProtection evaluates a rig's merge_queue.protection rules.
+// A nil *BranchProtection permits every MR.
+type BranchProtection struct {
+	cfg config.BranchProtectionConfig
+}
+
+// NewBranchProtection builds a BranchProtection from config. Returns nil
+// (no rules) if cfg is nil or empty.
+func NewBranchProtection(cfg *config.BranchProtectionConfig) (*BranchProtection, error) {
+	if cfg == nil || (len(cfg.RequiredChecks) == 0 && cfg.MinApprovals == 0 &&
+		len(cfg.ForbiddenPaths) == 0 && cfg.MaxDiffLines == 0) {
+		return nil, nil
+	}
+	if cfg.MinApprovals < 0 {
+		return nil, fmt.Errorf("%w: min_approvals must not be negative", ErrInvalidProtectionRule)
+	}
+	if cfg.MaxDiffLines < 0 {
+		return nil, fmt.Errorf("%w: max_diff_lines must not be negative", ErrInvalidProtectionRule)
+	}
+	for _, pattern := range cfg.ForbiddenPaths {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("%w: forbidden path %q: %v", ErrInvalidProtectionRule, pattern, err)
+		}
+	}
+	return &BranchProtection{cfg: *cfg}, nil
+}
+
+// LoadBranchProtection reads branch protection rules from a rig's
+// settings/config.json. A missing settings file or protection section yields
+// nil (no rules).
+func LoadBranchProtection(rigPath string) (*BranchProtection, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewBranchProtection(settings.MergeQueue.Protection)
+}
+
+// CheckChange evaluates the rules that depend only on the diff (forbidden
+// paths and diff size). These can be checked before an MR is submitted.
+func (p *BranchProtection) CheckChange(change MRChange) []ProtectionViolation {
+	if p == nil {
+		return nil
+	}
+
+	var violations []ProtectionViolation
+	for _, pattern := range p.cfg.ForbiddenPaths {
+		var hits []string
+		for _, file := range change.Files {
+			if matchProtectedPath(pattern, file) {
+				hits = append(hits, file)
+			}
+		}
+		if len(hits) > 0 {
+			violations = append(violations, ProtectionViolation{
+				Rule:   RuleForbiddenPaths,
+				Reason: fmt.Sprintf("touches forbidden path %q: %s", pattern, strings.Join(hits, ", ")),
+			})
+		}
+	}
+	if p.cfg.MaxDiffLines > 0 && change.Lines > p.cfg.MaxDiffLines {
+		violations = append(violations, ProtectionViolation{
+			Rule:   RuleMaxDiffLines,
+			Reason: fmt.Sprintf("diff is %d lines, limit is %d", change.Lines, p.cfg.MaxDiffLines),
+		})
+	}
+	return violations
+}
+
+// CheckReview evaluates the rules that depend on review state (required
+// checks and approvals). These are only satisfiable once the MR is queued.
+// An outstanding request for changes blocks merging even without rules.
+func (p *BranchProtection) CheckReview(review MRReview) []ProtectionViolation {
+	var violations []ProtectionViolation
+	if len(review.ChangesRequestedBy) > 0 {
+		violations = append(violations, ProtectionViolation{
+			Rule:   RuleChangesRequested,
+			Reason: fmt.Sprintf("changes requested by %s", strings.Join(review.ChangesRequestedBy, ", ")),
+		})
+	}
+	if p == nil {
+		return violations
+	}
+
+	passed := make(map[string]bool, len(review.ChecksPassed))
+	for _, c := range review.ChecksPassed {
+		passed[c] = true
+	}
+	var missing []string
+	for _, c := range p.cfg.RequiredChecks {
+		if !passed[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		violations = append(violations, ProtectionViolation{
+			Rule:   RuleRequiredChecks,
+			Reason: fmt.Sprintf("required checks not passed: %s", strings.Join(missing, ", ")),
+		})
+	}
+
+	if n := p.countApprovals(review.ApprovedBy); n < p.cfg.MinApprovals {
+		reason := fmt.Sprintf("has %d approval(s), needs %d", n, p.cfg.MinApprovals)
+		if len(p.cfg.ApprovalRoles) > 0 {
+			reason += " from " + strings.Join(p.cfg.ApprovalRoles, "/")
+		}
+		violations = append(violations, ProtectionViolation{Rule: RuleMinApprovals, Reason: reason})
+	}
+	return violations
+}
+
+// AwaitingReview returns why the MR is not yet ready for the refinery
+// (missing approvals or changes requested), or "" if it is. Required checks
+// are not considered, since the refinery runs those while processing.
+func (p *BranchProtection) AwaitingReview(review MRReview) string {
+	var reasons []string
+	for _, v := range p.CheckReview(review) {
+		if v.Rule == RuleMinApprovals || v.Rule == RuleChangesRequested {
+			reasons = append(reasons, v.Reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// countApprovals counts distinct approvers whose approval counts under the
+// rig's approval_roles. With no rules (nil), every approver counts except
+// chatops ones, which only count when approval_roles names them: their chat
+// user is whatever the chat client claims.
+func (p *BranchProtection) countApprovals(approvers []string) int {
+	seen := make(map[string]bool, len(approvers))
+	for _, a := range approvers {
+		if seen[a] {
+			continue
+		}
+		if ApproverRole(a) == "chatops" {
+			if p != nil && p.approverAllowed(a) {
+				seen[a] = true
+			}
+			continue
+		}
+		if p == nil || len(p.cfg.ApprovalRoles) == 0 || p.approverAllowed(a) {
+			seen[a] = true
+		}
+	}
+	return len(seen)
+}
+
+func (p *BranchProtection) approverAllowed(address string) bool {
+	for _, allowed := range p.cfg.ApprovalRoles {
+		if approverMatches(allowed, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// approverMatches reports whether address is allowed, given as a role (see
+// ApproverRole) or an address.
+func approverMatches(allowed, address string) bool {
+	return allowed == ApproverRole(address) || strings.TrimSuffix(allowed, "/") == strings.TrimSuffix(address, "/")
+}
+
+// ApproverRole classifies a mail address into an approval role: "mayor",
+// "deacon", "human" (the overseer), "witness", "refinery", "crew",
+// "chatops" (an approval made through the dashboard's chatops endpoint), or
+// "polecat".
+func ApproverRole(address string) string {
+	parts := strings.Split(strings.TrimSuffix(address, "/"), "/")
+	switch {
+	case parts[0] == "overseer":
+		return "human"
+	case strings.HasPrefix(parts[0], "chatops:"):
+		return "chatops"
+	case parts[0] == "mayor" || parts[0] == "deacon":
+		return parts[0]
+	case len(parts) >= 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return parts[1]
+	case len(parts) >= 3 && parts[1] == "crew":
+		return "crew"
+	default:
+		return "polecat"
+	}
+}
+
+// Check evaluates every rule.
+func (p *BranchProtection) Check(change MRChange, review MRReview) []ProtectionViolation {
+	return append(p.CheckChange(change), p.CheckReview(review)...)
+}
+
+// ReviewFromFields extracts review state from an MR's description fields.
+func ReviewFromFields(fields *beads.MRFields) MRReview {
+	if fields == nil {
+		return MRReview{}
+	}
+	return MRReview{
+		ChecksPassed:       SplitMRList(fields.ChecksPassed),
+		ApprovedBy:         SplitMRList(fields.ApprovedBy),
+		ChangesRequestedBy: SplitMRList(fields.ChangesRequestedBy),
+	}
+}
+
+// ReviewFromMR extracts review state from an MR bead, along with its
+// author (the worker, or whoever created the bead) and priority.
+func ReviewFromMR(mr *beads.Issue, fields *beads.MRFields) MRReview {
+	review := ReviewFromFields(fields)
+	review.Priority = mr.Priority
+	if fields != nil {
+		review.Author = fields.Worker
+	}
+	if review.Author == "" {
+		review.Author = mr.CreatedBy
+	}
+	return review
+}
+
+// DiffMRChange computes the change branch would introduce into target.
+func DiffMRChange(g *git.Git, target, branch string) (MRChange, error) {
+	stats, err := g.DiffStat(target, branch)
+	if err != nil {
+		return MRChange{}, fmt.Errorf("diffing %s against %s: %w", branch, target, err)
+	}
+	return changeFromStats(stats), nil
+}
+
+// changeFromStats summarizes per-file diff stats as an MRChange.
+func changeFromStats(stats []git.FileDiffStat) MRChange {
+	var change MRChange
+	for _, s := range stats {
+		change.Files = append(change.Files, s.Path)
+		change.Lines += s.Added + s.Deleted
+	}
+	return change
+}
+
+// DiffStatFiles returns the paths of per-file diff stats.
+func DiffStatFiles(stats []git.FileDiffStat) []string {
+	return changeFromStats(stats).Files
+}
+
+// HasChangeViolation reports whether any violation comes from the diff
+// itself (forbidden paths or size) rather than missing checks or approvals.
+func HasChangeViolation(violations []ProtectionViolation) bool {
+	for _, v := range violations {
+		if v.Rule == RuleForbiddenPaths || v.Rule == RuleMaxDiffLines {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatViolations joins violations into a single rejection reason.
+func FormatViolations(violations []ProtectionViolation) string {
+	reasons := make([]string, len(violations))
+	for i, v := range violations {
+		reasons[i] = v.Reason
+	}
+	return "branch protection: " + strings.Join(reasons, "; ")
+}
+
+// SplitMRList parses a comma-separated MR field into its non-empty items.
+func SplitMRList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// matchProtectedPath reports whether file matches a forbidden path pattern.
+// A trailing "/**" matches everything under a directory, and a pattern with
+// no slash matches a file name at any depth (like .gitignore).
+func matchProtectedPath(pattern, file string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return file == dir || strings.HasPrefix(file, dir+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
@@ -0,0 +1,189 @@
+package refinery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchProtectedPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{".github/**", ".github/workflows/ci.yml", true},
+		{".github/**", "src/.github/x", false},
+		{"*.pem", "certs/server.pem", true},
+		{"*.pem", "server.pem.txt", false},
+		{"internal/secrets/*.go", "internal/secrets/keys.go", true},
+		{"internal/secrets/*.go", "internal/secrets/sub/keys.go", false},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "tools/go.mod", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchProtectedPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchProtectedPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestBranchProtectionCheck(t *testing.T) {
+	p, err := NewBranchProtection(&config.BranchProtectionConfig{
+		RequiredChecks: []string{"tests", "lint"},
+		MinApprovals:   2,
+		ForbiddenPaths: []string{".github/**"},
+		MaxDiffLines:   100,
+	})
+	if err != nil {
+		t.Fatalf("NewBranchProtection: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		change    MRChange
+		review    MRReview
+		wantRules []string
+	}{
+		{
+			name:   "all satisfied",
+			change: MRChange{Files: []string{"main.go"}, Lines: 40},
+			review: MRReview{ChecksPassed: []string{"lint", "tests"}, ApprovedBy: []string{"mayor", "gastown/crew/max"}},
+		},
+		{
+			name:      "forbidden path and too large",
+			change:    MRChange{Files: []string{".github/workflows/ci.yml"}, Lines: 500},
+			review:    MRReview{ChecksPassed: []string{"lint", "tests"}, ApprovedBy: []string{"mayor", "gastown/crew/max"}},
+			wantRules: []string{RuleForbiddenPaths, RuleMaxDiffLines},
+		},
+		{
+			name:      "missing check and duplicate approvals",
+			change:    MRChange{Files: []string{"main.go"}, Lines: 1},
+			review:    MRReview{ChecksPassed: []string{"tests"}, ApprovedBy: []string{"mayor", "mayor"}},
+			wantRules: []string{RuleRequiredChecks, RuleMinApprovals},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := p.Check(tt.change, tt.review)
+			if len(violations) != len(tt.wantRules) {
+				t.Fatalf("got %d violations %+v, want rules %v", len(violations), violations, tt.wantRules)
+			}
+			for i, v := range violations {
+				if v.Rule != tt.wantRules[i] {
+					t.Errorf("violation %d rule = %q, want %q", i, v.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHasChangeViolation(t *testing.T) {
+	review := []ProtectionViolation{{Rule: RuleMinApprovals}, {Rule: RuleRequiredChecks}}
+	if HasChangeViolation(review) {
+		t.Error("review-only violations should not reject the MR")
+	}
+	if !HasChangeViolation(append(review, ProtectionViolation{Rule: RuleMaxDiffLines})) {
+		t.Error("diff size violation should reject the MR")
+	}
+}
+
+func TestNewBranchProtectionValidation(t *testing.T) {
+	if p, err := NewBranchProtection(&config.BranchProtectionConfig{}); err != nil || p != nil {
+		t.Errorf("empty config = %v, %v; want nil, nil", p, err)
+	}
+	if violations := (*BranchProtection)(nil).Check(MRChange{Lines: 1e6}, MRReview{}); len(violations) != 0 {
+		t.Errorf("nil protection should permit everything, got %+v", violations)
+	}
+
+	bad := []*config.BranchProtectionConfig{
+		{MinApprovals: -1},
+		{MaxDiffLines: -5},
+		{ForbiddenPaths: []string{"[unclosed"}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewBranchProtection(cfg); !errors.Is(err, ErrInvalidProtectionRule) {
+			t.Errorf("NewBranchProtection(%+v) error = %v, want ErrInvalidProtectionRule", cfg, err)
+		}
+	}
+}
+
+func TestSplitMRList(t *testing.T) {
+	got := SplitMRList(" tests, ,lint ,")
+	if len(got) != 2 || got[0] != "tests" || got[1] != "lint" {
+		t.Errorf("SplitMRList = %q, want [tests lint]", got)
+	}
+}
+
+func TestApproverRole(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":                  "mayor",
+		"overseer":                "human",
+		"deacon":                  "deacon",
+		"greenplace/witness":      "witness",
+		"greenplace/crew/max":     "crew",
+		"greenplace/Nux":          "polecat",
+		"greenplace/polecats/Nux": "polecat",
+		"chatops:slack/alice":     "chatops",
+	}
+	for addr, want := range tests {
+		if got := ApproverRole(addr); got != want {
+			t.Errorf("ApproverRole(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestApprovalRoles(t *testing.T) {
+	p, err := NewBranchProtection(&config.BranchProtectionConfig{
+		MinApprovals:  2,
+		ApprovalRoles: []string{"mayor", "greenplace/crew/max"},
+	})
+	if err != nil {
+		t.Fatalf("NewBranchProtection: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		review   MRReview
+		awaiting bool
+	}{
+		{"allowed role and address", MRReview{ApprovedBy: []string{"mayor", "greenplace/crew/max"}}, false},
+		{"other roles do not count", MRReview{ApprovedBy: []string{"mayor", "greenplace/crew/joe", "overseer"}}, true},
+		{"changes requested", MRReview{ApprovedBy: []string{"mayor", "greenplace/crew/max"}, ChangesRequestedBy: []string{"overseer"}}, true},
+		{"missing checks do not hold the queue", MRReview{ApprovedBy: []string{"mayor", "greenplace/crew/max"}, ChecksPassed: nil}, false},
+		{"chatops does not count unless listed", MRReview{ApprovedBy: []string{"mayor", "chatops:slack/max"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.AwaitingReview(tt.review) != ""; got != tt.awaiting {
+				t.Errorf("AwaitingReview = %v, want %v", got, tt.awaiting)
+			}
+		})
+	}
+
+	// Any approver counts without approval_roles, except through chatops,
+	// which must be listed
+	open, err := NewBranchProtection(&config.BranchProtectionConfig{MinApprovals: 1})
+	if err != nil {
+		t.Fatalf("NewBranchProtection: %v", err)
+	}
+	if open.AwaitingReview(MRReview{ApprovedBy: []string{"chatops:slack/alice"}}) == "" {
+		t.Error("chatops approval counted without approval_roles listing it")
+	}
+	listed, err := NewBranchProtection(&config.BranchProtectionConfig{MinApprovals: 1, ApprovalRoles: []string{"chatops"}})
+	if err != nil {
+		t.Fatalf("NewBranchProtection: %v", err)
+	}
+	if listed.AwaitingReview(MRReview{ApprovedBy: []string{"chatops:slack/alice"}}) != "" {
+		t.Error("chatops approval not counted with approval_roles listing it")
+	}
+
+	// Changes requested blocks even when the rig has no rules
+	var none *BranchProtection
+	if none.AwaitingReview(MRReview{ChangesRequestedBy: []string{"mayor"}}) == "" {
+		t.Error("changes requested should block without protection rules")
+	}
+}
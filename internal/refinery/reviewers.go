@@ -0,0 +1,181 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Review assignment strategies for merge_queue.protection.review_assignment.
+const (
+	ReviewAssignRoundRobin = "round_robin"
+	ReviewAssignLoad       = "load"
+)
+
+// rotationLockTimeout bounds the wait for another submit advancing the
+// round-robin rotation.
+const rotationLockTimeout = 10 * time.Second
+
+// ReviewerPool assigns reviewers to new MRs from a rig's reviewer pool.
+// A nil *ReviewerPool assigns no one.
+type ReviewerPool struct {
+	reviewers []string
+	strategy  string
+	count     int    // reviewers per MR (min_approvals, capped to the pool)
+	stateFile string // round-robin position, under .runtime/
+}
+
+// reviewerRotationState is persisted between submits for round-robin.
+type reviewerRotationState struct {
+	Next int `json:"next"`
+}
+
+// NewReviewerPool builds a ReviewerPool from protection config. Returns nil
+// if the rig requires no approvals or has no reviewer pool.
+func NewReviewerPool(rigPath string, cfg *config.BranchProtectionConfig) (*ReviewerPool, error) {
+	if cfg == nil || cfg.MinApprovals <= 0 || len(cfg.Reviewers) == 0 {
+		return nil, nil
+	}
+
+	strategy := cfg.ReviewAssignment
+	if strategy == "" {
+		strategy = ReviewAssignRoundRobin
+	}
+	if strategy != ReviewAssignRoundRobin && strategy != ReviewAssignLoad {
+		return nil, fmt.Errorf("%w: review_assignment %q (want %q or %q)",
+			ErrInvalidProtectionRule, strategy, ReviewAssignRoundRobin, ReviewAssignLoad)
+	}
+
+	return &ReviewerPool{
+		reviewers: cfg.Reviewers,
+		strategy:  strategy,
+		count:     cfg.MinApprovals,
+		stateFile: filepath.Join(rigPath, ".runtime", "reviewer-rotation.json"),
+	}, nil
+}
+
+// LoadReviewerPool reads the reviewer pool from a rig's settings/config.json.
+func LoadReviewerPool(rigPath string) (*ReviewerPool, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewReviewerPool(rigPath, settings.MergeQueue.Protection)
+}
+
+// Strategy returns the pool's assignment strategy.
+func (p *ReviewerPool) Strategy() string {
+	if p == nil {
+		return ""
+	}
+	return p.strategy
+}
+
+// Assign picks reviewers for an MR submitted by author, who is never
+// assigned their own MR. load maps reviewer address to open assigned
+// reviews and is only consulted by the load strategy. Round-robin
+// advances the rig's rotation.
+func (p *ReviewerPool) Assign(author string, load map[string]int) ([]string, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var eligible []string
+	for _, r := range p.reviewers {
+		if strings.TrimSuffix(r, "/") != strings.TrimSuffix(author, "/") {
+			eligible = append(eligible, r)
+		}
+	}
+	n := p.count
+	if n > len(eligible) {
+		n = len(eligible)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	if p.strategy == ReviewAssignLoad {
+		sorted := append([]string(nil), eligible...)
+		sort.SliceStable(sorted, func(i, j int) bool { return load[sorted[i]] < load[sorted[j]] })
+		return sorted[:n], nil
+	}
+
+	// Hold the rotation from read to write, so concurrent submits each
+	// advance it rather than picking the same reviewers
+	unlock, err := p.lockRotation()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state := p.loadRotation()
+	start := state.Next % len(p.reviewers)
+	var picked []string
+	last := start
+	for i := 0; i < len(p.reviewers) && len(picked) < n; i++ {
+		idx := (start + i) % len(p.reviewers)
+		r := p.reviewers[idx]
+		if strings.TrimSuffix(r, "/") == strings.TrimSuffix(author, "/") {
+			continue
+		}
+		picked = append(picked, r)
+		last = idx
+	}
+	state.Next = (last + 1) % len(p.reviewers)
+	if err := p.saveRotation(state); err != nil {
+		return picked, fmt.Errorf("saving reviewer rotation: %w", err)
+	}
+	return picked, nil
+}
+
+// lockRotation takes the exclusive lock on the rotation state, waiting up
+// to rotationLockTimeout. The returned func releases it.
+func (p *ReviewerPool) lockRotation() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(p.stateFile), 0755); err != nil {
+		return nil, err
+	}
+	lock := flock.New(p.stateFile + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), rotationLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("locking reviewer rotation: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("locking reviewer rotation: held by another gt for over %v", rotationLockTimeout)
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+func (p *ReviewerPool) loadRotation() reviewerRotationState {
+	var state reviewerRotationState
+	data, err := os.ReadFile(p.stateFile)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state) // corrupt state restarts the rotation
+	return state
+}
+
+func (p *ReviewerPool) saveRotation(state reviewerRotationState) error {
+	if err := os.MkdirAll(filepath.Dir(p.stateFile), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(p.stateFile, state)
+}
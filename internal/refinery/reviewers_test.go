@@ -0,0 +1,110 @@
+package refinery
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestReviewerPoolRoundRobin(t *testing.T) {
+	pool, err := NewReviewerPool(t.TempDir(), &config.BranchProtectionConfig{
+		MinApprovals: 1,
+		Reviewers:    []string{"mayor", "greenplace/crew/max", "overseer"},
+	})
+	if err != nil {
+		t.Fatalf("NewReviewerPool: %v", err)
+	}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		picked, err := pool.Assign("greenplace/Nux", nil)
+		if err != nil {
+			t.Fatalf("Assign: %v", err)
+		}
+		got = append(got, picked...)
+	}
+	want := []string{"mayor", "greenplace/crew/max", "overseer", "mayor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rotation = %v, want %v", got, want)
+	}
+
+	// The author is skipped without stalling the rotation
+	picked, _ := pool.Assign("greenplace/crew/max", nil)
+	if !reflect.DeepEqual(picked, []string{"overseer"}) {
+		t.Errorf("Assign skipping author = %v, want [overseer]", picked)
+	}
+}
+
+func TestReviewerPoolRoundRobinConcurrent(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg := &config.BranchProtectionConfig{MinApprovals: 1, Reviewers: []string{"a", "b", "c", "d"}}
+
+	// Concurrent submits (separate pools, as separate gt processes have)
+	// each advance the rotation, so a full round picks everyone once
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool, err := NewReviewerPool(rigPath, cfg)
+			if err != nil {
+				t.Errorf("NewReviewerPool: %v", err)
+				return
+			}
+			picked, err := pool.Assign("greenplace/Nux", nil)
+			if err != nil {
+				t.Errorf("Assign: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, r := range picked {
+				counts[r]++
+			}
+		}()
+	}
+	wg.Wait()
+	for _, r := range cfg.Reviewers {
+		if counts[r] != 2 {
+			t.Errorf("reviewers picked %v, want each twice", counts)
+			break
+		}
+	}
+}
+
+func TestReviewerPoolLoad(t *testing.T) {
+	pool, err := NewReviewerPool(t.TempDir(), &config.BranchProtectionConfig{
+		MinApprovals:     2,
+		Reviewers:        []string{"mayor", "greenplace/crew/max", "overseer"},
+		ReviewAssignment: ReviewAssignLoad,
+	})
+	if err != nil {
+		t.Fatalf("NewReviewerPool: %v", err)
+	}
+
+	load := map[string]int{"mayor": 3, "greenplace/crew/max": 0, "overseer": 1}
+	picked, err := pool.Assign("greenplace/Nux", load)
+	if err != nil {
+		t.Fatalf("Assign: %v", err)
+	}
+	if want := []string{"greenplace/crew/max", "overseer"}; !reflect.DeepEqual(picked, want) {
+		t.Errorf("Assign = %v, want %v", picked, want)
+	}
+}
+
+func TestNewReviewerPool(t *testing.T) {
+	if pool, err := NewReviewerPool(t.TempDir(), &config.BranchProtectionConfig{Reviewers: []string{"mayor"}}); pool != nil || err != nil {
+		t.Errorf("pool without min_approvals = %v, %v; want nil, nil", pool, err)
+	}
+	_, err := NewReviewerPool(t.TempDir(), &config.BranchProtectionConfig{
+		MinApprovals: 1, Reviewers: []string{"mayor"}, ReviewAssignment: "random",
+	})
+	if !errors.Is(err, ErrInvalidProtectionRule) {
+		t.Errorf("unknown strategy error = %v, want ErrInvalidProtectionRule", err)
+	}
+}
@@ -0,0 +1,170 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Risk levels for a scored MR.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// RuleRiskApproval labels the violation for a high-risk MR that has not
+// been approved when merge_queue.risk.require_approval is set.
+const RuleRiskApproval = "risk_approval"
+
+// DefaultRiskHighThreshold is the score at which an MR counts as high risk.
+const DefaultRiskHighThreshold = 60
+
+// RiskAssessment is the heuristic risk score for an MR's diff.
+type RiskAssessment struct {
+	Score   int      `json:"score"` // 0-100
+	Level   string   `json:"level"`
+	Factors []string `json:"factors,omitempty"`
+}
+
+// RiskScorer scores MR diffs using a rig's merge_queue.risk settings.
+type RiskScorer struct {
+	hotPaths        []string
+	highThreshold   int
+	requireApproval bool
+}
+
+// NewRiskScorer builds a RiskScorer from config. A nil config yields the
+// default scorer (no hot paths, no approval gate).
+func NewRiskScorer(cfg *config.MergeRiskConfig) (*RiskScorer, error) {
+	s := &RiskScorer{highThreshold: DefaultRiskHighThreshold}
+	if cfg == nil {
+		return s, nil
+	}
+	if cfg.HighThreshold < 0 || cfg.HighThreshold > 100 {
+		return nil, fmt.Errorf("%w: risk high_threshold must be 0-100", ErrInvalidProtectionRule)
+	}
+	for _, pattern := range cfg.HotPaths {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("%w: hot path %q: %v", ErrInvalidProtectionRule, pattern, err)
+		}
+	}
+	if cfg.HighThreshold > 0 {
+		s.highThreshold = cfg.HighThreshold
+	}
+	s.hotPaths = cfg.HotPaths
+	s.requireApproval = cfg.RequireApproval
+	return s, nil
+}
+
+// LoadRiskScorer reads risk settings from a rig's settings/config.json,
+// falling back to the default scorer if none are configured.
+func LoadRiskScorer(rigPath string) (*RiskScorer, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return NewRiskScorer(nil)
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return NewRiskScorer(nil)
+	}
+	return NewRiskScorer(settings.MergeQueue.Risk)
+}
+
+// RequiresApproval reports whether high-risk MRs are held for approval.
+func (s *RiskScorer) RequiresApproval() bool {
+	return s.requireApproval
+}
+
+// Score computes a 0-100 risk score from per-file diff stats. Points come
+// from diff size (up to 40), files touched (up to 20), code changed without
+// matching test changes (up to 20), and hot paths touched (up to 30).
+func (s *RiskScorer) Score(stats []git.FileDiffStat) RiskAssessment {
+	var a RiskAssessment
+	var lines, codeLines, testLines int
+	var hot []string
+	for _, st := range stats {
+		n := st.Added + st.Deleted
+		lines += n
+		if isTestFile(st.Path) {
+			testLines += n
+		} else {
+			codeLines += n
+		}
+		for _, pattern := range s.hotPaths {
+			if matchProtectedPath(pattern, st.Path) {
+				hot = append(hot, st.Path)
+				break
+			}
+		}
+	}
+
+	if size := min(40, lines/25); size > 0 {
+		a.Score += size
+		a.Factors = append(a.Factors, fmt.Sprintf("%d lines changed", lines))
+	}
+	if files := min(20, 2*(len(stats)-1)); files > 0 {
+		a.Score += files
+		a.Factors = append(a.Factors, fmt.Sprintf("%d files touched", len(stats)))
+	}
+	switch {
+	case codeLines >= 20 && testLines == 0:
+		a.Score += 20
+		a.Factors = append(a.Factors, "no test changes")
+	case codeLines >= 20 && testLines*4 < codeLines:
+		a.Score += 10
+		a.Factors = append(a.Factors, "few test changes")
+	}
+	if len(hot) > 0 {
+		a.Score += min(30, 15*len(hot))
+		a.Factors = append(a.Factors, "hot paths: "+strings.Join(hot, ", "))
+	}
+
+	a.Score = min(100, a.Score)
+	switch {
+	case a.Score >= s.highThreshold:
+		a.Level = RiskHigh
+	case a.Score >= s.highThreshold/2:
+		a.Level = RiskMedium
+	default:
+		a.Level = RiskLow
+	}
+	return a
+}
+
+// CheckApproval returns a violation if the rig holds high-risk MRs for
+// approval and this one has none that count under protection's
+// approval_roles. Returns nil otherwise.
+func (s *RiskScorer) CheckApproval(a RiskAssessment, protection *BranchProtection, review MRReview) *ProtectionViolation {
+	if !s.requireApproval || a.Level != RiskHigh || protection.countApprovals(review.ApprovedBy) > 0 {
+		return nil
+	}
+	return &ProtectionViolation{
+		Rule:   RuleRiskApproval,
+		Reason: fmt.Sprintf("high-risk MR (score %d) needs an approval", a.Score),
+	}
+}
+
+// isTestFile reports whether a path looks like a test file in common
+// language conventions.
+func isTestFile(file string) bool {
+	base := path.Base(file)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if dir == "test" || dir == "tests" || dir == "testdata" || dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
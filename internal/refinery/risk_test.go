@@ -0,0 +1,94 @@
+package refinery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestRiskScore(t *testing.T) {
+	s, err := NewRiskScorer(&config.MergeRiskConfig{HotPaths: []string{"internal/auth/**"}})
+	if err != nil {
+		t.Fatalf("NewRiskScorer: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		stats     []git.FileDiffStat
+		wantScore int
+		wantLevel string
+	}{
+		{
+			name:      "small change with tests",
+			stats:     []git.FileDiffStat{{Path: "main.go", Added: 10}, {Path: "main_test.go", Added: 10}},
+			wantScore: 2,
+			wantLevel: RiskLow,
+		},
+		{
+			name:      "code without tests",
+			stats:     []git.FileDiffStat{{Path: "main.go", Added: 40, Deleted: 10}},
+			wantScore: 22,
+			wantLevel: RiskLow,
+		},
+		{
+			name: "large untested change to hot path",
+			stats: []git.FileDiffStat{
+				{Path: "internal/auth/token.go", Added: 500},
+				{Path: "internal/auth/session.go", Added: 300, Deleted: 200},
+			},
+			wantScore: 92,
+			wantLevel: RiskHigh,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := s.Score(tt.stats)
+			if a.Score != tt.wantScore || a.Level != tt.wantLevel {
+				t.Errorf("Score = %d %s (%v), want %d %s", a.Score, a.Level, a.Factors, tt.wantScore, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestRiskCheckApproval(t *testing.T) {
+	s, err := NewRiskScorer(&config.MergeRiskConfig{RequireApproval: true, HighThreshold: 20})
+	if err != nil {
+		t.Fatalf("NewRiskScorer: %v", err)
+	}
+	high := RiskAssessment{Score: 50, Level: RiskHigh}
+
+	if v := s.CheckApproval(high, nil, MRReview{}); v == nil || v.Rule != RuleRiskApproval {
+		t.Errorf("unapproved high-risk MR: violation = %+v, want %s", v, RuleRiskApproval)
+	}
+	if v := s.CheckApproval(high, nil, MRReview{ApprovedBy: []string{"mayor"}}); v != nil {
+		t.Errorf("approved high-risk MR should pass, got %+v", v)
+	}
+	if v := s.CheckApproval(RiskAssessment{Score: 5, Level: RiskLow}, nil, MRReview{}); v != nil {
+		t.Errorf("low-risk MR should pass, got %+v", v)
+	}
+}
+
+func TestNewRiskScorerValidation(t *testing.T) {
+	for _, cfg := range []*config.MergeRiskConfig{{HighThreshold: 101}, {HotPaths: []string{"[bad"}}} {
+		if _, err := NewRiskScorer(cfg); !errors.Is(err, ErrInvalidProtectionRule) {
+			t.Errorf("NewRiskScorer(%+v) error = %v, want ErrInvalidProtectionRule", cfg, err)
+		}
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	for file, want := range map[string]bool{
+		"pkg/foo_test.go":     true,
+		"web/app.spec.ts":     true,
+		"tests/test_api.py":   true,
+		"pkg/testdata/in.txt": true,
+		"pkg/foo.go":          false,
+		"latest/notes.md":     false,
+	} {
+		if got := isTestFile(file); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Check runners.
+const (
+	RunnerShell  = "shell"  // The town host's shell
+	RunnerDocker = "docker" // A container, with the checkout mounted
+	RunnerNix    = "nix"    // A nix flake's dev shell
+)
+
+// dockerMemoryRe matches docker's memory sizes ("512m", "4g").
+var dockerMemoryRe = regexp.MustCompile(`(?i)^[0-9]+[bkmg]?$`)
+
+// dockerEnvPrefixes are the variables passed into a docker check: the
+// refinery's identity, but nothing else of the host's environment.
+var dockerEnvPrefixes = []string{"GT_", "BD_", "BEADS_"}
+
+// dockerStopGrace is how long a docker check has to stop after it is
+// interrupted (e.g., by its timeout) before the docker client is killed.
+const dockerStopGrace = 10 * time.Second
+
+// CheckRunner is where and with what limits a check command runs, from a
+// rig's merge_queue.checks settings. A nil *CheckRunner runs the command in
+// the host's shell with no limits.
+type CheckRunner struct {
+	Kind    string        `json:"kind"`              // RunnerShell, RunnerDocker or RunnerNix
+	Image   string        `json:"image,omitempty"`   // The docker image, or the nix flake ("" = the checkout's)
+	CPUs    float64       `json:"cpus,omitempty"`    // 0 = no limit
+	Memory  string        `json:"memory,omitempty"`  // In docker's format; "" = no limit
+	Timeout time.Duration `json:"timeout,omitempty"` // 0 = no limit
+}
+
+// NewCheckRunner builds a check runner from config. Returns nil if cfg is
+// nil.
+func NewCheckRunner(cfg *config.CheckRunnerConfig) (*CheckRunner, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	r := &CheckRunner{CPUs: cfg.CPUs, Memory: cfg.Memory}
+	kind, arg, _ := strings.Cut(cfg.Runner, ":")
+	switch kind {
+	case "", RunnerShell:
+		r.Kind = RunnerShell
+		if arg != "" {
+			return nil, fmt.Errorf("invalid runner %q: shell takes no argument", cfg.Runner)
+		}
+	case RunnerDocker:
+		r.Kind, r.Image = RunnerDocker, arg
+		if r.Image == "" {
+			return nil, fmt.Errorf("invalid runner %q: want docker:<image>", cfg.Runner)
+		}
+	case RunnerNix:
+		r.Kind, r.Image = RunnerNix, arg
+	default:
+		return nil, fmt.Errorf("invalid runner %q (want %s, %s:<image> or %s)", cfg.Runner, RunnerShell, RunnerDocker, RunnerNix)
+	}
+
+	if r.CPUs < 0 {
+		return nil, fmt.Errorf("invalid cpus %v", r.CPUs)
+	}
+	if r.Memory != "" && !dockerMemoryRe.MatchString(r.Memory) {
+		return nil, fmt.Errorf("invalid memory %q (e.g., 512m or 4g)", r.Memory)
+	}
+	if (r.CPUs > 0 || r.Memory != "") && r.Kind != RunnerDocker {
+		return nil, fmt.Errorf("cpus and memory limits need a docker runner")
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+		r.Timeout = timeout
+	}
+	return r, nil
+}
+
+// LoadCheckRunner reads how a check runs from a rig's settings/config.json.
+// A missing settings file or a check that isn't configured yields nil (the
+// host's shell, no limits).
+func LoadCheckRunner(rigPath, check string) (*CheckRunner, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	r, err := NewCheckRunner(settings.MergeQueue.Checks[check])
+	if err != nil {
+		return nil, fmt.Errorf("merge_queue.checks.%s: %w", check, err)
+	}
+	return r, nil
+}
+
+// String describes the runner as it is configured ("docker:golang:1.23").
+func (r *CheckRunner) String() string {
+	if r == nil {
+		return RunnerShell
+	}
+	if r.Image != "" {
+		return r.Kind + ":" + r.Image
+	}
+	return r.Kind
+}
+
+// TimeoutOr returns the runner's timeout, or fallback if it sets none.
+func (r *CheckRunner) TimeoutOr(fallback time.Duration) time.Duration {
+	if r == nil || r.Timeout == 0 {
+		return fallback
+	}
+	return r.Timeout
+}
+
+// command returns the command that runs the shell command line in dir
+// with env (nil = this process's environment).
+func (r *CheckRunner) command(ctx context.Context, line, dir string, env []string) (*exec.Cmd, error) {
+	// Note: check commands and runners come from rig settings (trusted
+	// infrastructure config), not from the branch being checked.
+	var cmd *exec.Cmd
+	if r == nil || r.Kind == RunnerShell {
+		cmd = exec.CommandContext(ctx, "sh", "-c", line) //nolint:gosec // G204: command is from trusted rig config
+	} else {
+		name, args, err := r.args(line, dir, env)
+		if err != nil {
+			return nil, err
+		}
+		cmd = exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: command is from trusted rig config
+		if r.Kind == RunnerDocker {
+			// Interrupt rather than kill the client, which stops the
+			// container too
+			cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+			cmd.WaitDelay = dockerStopGrace
+		}
+	}
+	cmd.Dir = dir
+	cmd.Env = env
+	return cmd, nil
+}
+
+// args returns the program and arguments that run line under a docker or
+// nix runner.
+func (r *CheckRunner) args(line, dir string, env []string) (string, []string, error) {
+	if r.Kind == RunnerNix {
+		flake := r.Image
+		if flake == "" {
+			flake = "."
+		}
+		return "nix", []string{"develop", flake, "--command", "sh", "-c", line}, nil
+	}
+
+	// The checkout is mounted at the same path, so paths in the output
+	// match the host's
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	args := []string{"run", "--rm", "--init",
+		"--volume", abs + ":" + abs, "--workdir", abs,
+		// Tool caches need a writable home, whoever the user is
+		"--env", "HOME=/tmp"}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		// Files the check writes in the checkout stay removable
+		args = append(args, "--user", strconv.Itoa(uid)+":"+strconv.Itoa(gid))
+	}
+	if r.CPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(r.CPUs, 'f', -1, 64))
+	}
+	if r.Memory != "" {
+		args = append(args, "--memory", r.Memory)
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	for _, kv := range env {
+		for _, prefix := range dockerEnvPrefixes {
+			if strings.HasPrefix(kv, prefix) {
+				args = append(args, "--env", kv)
+				break
+			}
+		}
+	}
+	return "docker", append(args, r.Image, "sh", "-c", line), nil
+}
@@ -0,0 +1,86 @@
+package refinery
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewCheckRunner(t *testing.T) {
+	if r, err := NewCheckRunner(nil); r != nil || err != nil {
+		t.Errorf("NewCheckRunner(nil) = %v, %v", r, err)
+	}
+
+	tests := []struct {
+		cfg  config.CheckRunnerConfig
+		want CheckRunner
+	}{
+		{config.CheckRunnerConfig{}, CheckRunner{Kind: RunnerShell}},
+		{config.CheckRunnerConfig{Runner: "shell", Timeout: "90s"}, CheckRunner{Kind: RunnerShell, Timeout: 90 * time.Second}},
+		{config.CheckRunnerConfig{Runner: "docker:golang:1.23", CPUs: 1.5, Memory: "4G"}, CheckRunner{Kind: RunnerDocker, Image: "golang:1.23", CPUs: 1.5, Memory: "4G"}},
+		{config.CheckRunnerConfig{Runner: "nix"}, CheckRunner{Kind: RunnerNix}},
+		{config.CheckRunnerConfig{Runner: "nix:.#ci"}, CheckRunner{Kind: RunnerNix, Image: ".#ci"}},
+	}
+	for _, tt := range tests {
+		r, err := NewCheckRunner(&tt.cfg)
+		if err != nil {
+			t.Errorf("NewCheckRunner(%+v): %v", tt.cfg, err)
+			continue
+		}
+		if *r != tt.want {
+			t.Errorf("NewCheckRunner(%+v) = %+v, want %+v", tt.cfg, *r, tt.want)
+		}
+	}
+
+	bad := []config.CheckRunnerConfig{
+		{Runner: "podman:golang"},
+		{Runner: "docker"},
+		{Runner: "shell:bash"},
+		{Runner: "docker:golang", Memory: "lots"},
+		{Runner: "docker:golang", CPUs: -1},
+		{Runner: "shell", Memory: "4g"},
+		{Runner: "nix", CPUs: 2},
+		{Timeout: "0s"},
+	}
+	for _, cfg := range bad {
+		if _, err := NewCheckRunner(&cfg); err == nil {
+			t.Errorf("NewCheckRunner(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestCheckRunnerArgs(t *testing.T) {
+	r := &CheckRunner{Kind: RunnerDocker, Image: "golang:1.23", CPUs: 2, Memory: "4g"}
+	name, args, err := r.args("go test ./...", "/work/repo", []string{"PATH=/usr/bin", "GT_ROLE=greenplace/refinery", "SECRET=x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Join(args, " ")
+	for _, want := range []string{"run --rm", "--volume /work/repo:/work/repo --workdir /work/repo", "--cpus 2", "--memory 4g",
+		"--env GT_ROLE=greenplace/refinery", "golang:1.23 sh -c go test ./..."} {
+		if !strings.Contains(line, want) {
+			t.Errorf("docker %s: missing %q", line, want)
+		}
+	}
+	if name != "docker" || strings.Contains(line, "PATH=") || strings.Contains(line, "SECRET") {
+		t.Errorf("%s %s: should pass only the refinery's variables", name, line)
+	}
+
+	name, args, _ = (&CheckRunner{Kind: RunnerNix}).args("make test", "/work/repo", nil)
+	if want := []string{"develop", ".", "--command", "sh", "-c", "make test"}; name != "nix" || !slices.Equal(args, want) {
+		t.Errorf("nix runner = %s %q, want nix %q", name, args, want)
+	}
+}
+
+func TestCheckCommandRunner(t *testing.T) {
+	// Shell runners (and no runner) run the command as before
+	for _, r := range []*CheckRunner{nil, {Kind: RunnerShell}} {
+		if run := (CheckCommand{Command: "true", Runner: r}).Run(context.Background()); run.Err != nil {
+			t.Errorf("runner %v: %v", r, run.Err)
+		}
+	}
+}
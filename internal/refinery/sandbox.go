@@ -0,0 +1,155 @@
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// sandboxPrefix names the temporary directories sandboxes are cloned into.
+const sandboxPrefix = "gt-sandbox-"
+
+// Sandbox branches. The scratch target stands in for the MR's real target,
+// which the sandbox never touches.
+const (
+	SandboxTarget = "gt-sandbox/target"
+	SandboxBranch = "gt-sandbox/mr"
+)
+
+// Files added by a sandbox's fabricated MR and by the commit its scratch
+// target moves on with.
+const (
+	sandboxMRFile     = ".gt-sandbox-mr"
+	sandboxTargetFile = ".gt-sandbox-target"
+)
+
+// Sandbox is a throwaway clone of a rig's repo holding a fabricated MR, for
+// running the refinery's pipeline on it without touching origin, the
+// refinery's checkout or the merge queue. Nothing in a sandbox is pushed.
+type Sandbox struct {
+	Dir  string // The clone
+	Base string // The target commit the MR branched from
+
+	tmp string
+	git *git.Git
+}
+
+// NewSandbox clones the refinery's repo into a temporary directory and
+// fabricates an MR there: SandboxBranch adds a file to target as the
+// refinery has it, in a commit titled message, and SandboxTarget, a scratch
+// branch from the same commit, moves on with a commit of its own so the MR
+// needs rebasing as a real one would. The clone uses the repo's hooks, so
+// they run on these commits. Remove the sandbox with Remove.
+func (e *Engineer) NewSandbox(target, message string) (*Sandbox, error) {
+	tmp, err := os.MkdirTemp("", sandboxPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("creating sandbox dir: %w", err)
+	}
+	s := &Sandbox{Dir: filepath.Join(tmp, "repo"), tmp: tmp}
+	if err := s.fabricate(e, target, message); err != nil {
+		_ = s.Remove()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Sandbox) fabricate(e *Engineer, target, message string) error {
+	if err := git.NewGit(s.tmp).Clone(e.workDir, s.Dir); err != nil {
+		return fmt.Errorf("cloning %s: %w", e.workDir, err)
+	}
+	s.git = git.NewGit(s.Dir)
+
+	// The refinery's view of target is a local branch or origin/target in
+	// its clone, so fetch it from there by that name
+	ref := e.resolveRef(target)
+	if err := s.git.FetchBranch(e.workDir, ref); err != nil {
+		return fmt.Errorf("fetching %s: %w", ref, err)
+	}
+	base, err := s.git.Rev("FETCH_HEAD")
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	s.Base = base
+
+	if err := s.git.CreateBranchFrom(SandboxBranch, base); err != nil {
+		return fmt.Errorf("creating %s: %w", SandboxBranch, err)
+	}
+	if err := s.git.CreateBranchFrom(SandboxTarget, base); err != nil {
+		return fmt.Errorf("creating %s: %w", SandboxTarget, err)
+	}
+	if err := s.commitFile(SandboxBranch, sandboxMRFile, message); err != nil {
+		return err
+	}
+	return s.commitFile(SandboxTarget, sandboxTargetFile, message)
+}
+
+// commitFile commits a new file to branch.
+func (s *Sandbox) commitFile(branch, name, message string) error {
+	if err := s.git.Checkout(branch); err != nil {
+		return fmt.Errorf("checking out %s: %w", branch, err)
+	}
+	content := fmt.Sprintf("Written by the refinery's sandbox on %s; safe to delete.\n", branch)
+	if err := os.WriteFile(filepath.Join(s.Dir, name), []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := s.git.Add(name); err != nil {
+		return fmt.Errorf("staging %s: %w", name, err)
+	}
+	if err := s.git.Commit(message); err != nil {
+		return fmt.Errorf("committing to %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Git returns the sandbox clone's git.
+func (s *Sandbox) Git() *git.Git {
+	return s.git
+}
+
+// Rebase rebases the MR onto the scratch target, as the refinery rebases
+// MRs whose target has moved on.
+func (s *Sandbox) Rebase() error {
+	if err := s.git.Checkout(SandboxBranch); err != nil {
+		return fmt.Errorf("checking out %s: %w", SandboxBranch, err)
+	}
+	if err := s.git.Rebase(SandboxTarget); err != nil {
+		_ = s.git.AbortRebase()
+		return fmt.Errorf("rebasing onto %s: %w", SandboxTarget, err)
+	}
+	return nil
+}
+
+// Merge squash-merges the MR onto the scratch target, with its head
+// commit's message as the refinery does, and returns the merge commit. The
+// merged result is left checked out, for checks to run on.
+func (s *Sandbox) Merge() (string, error) {
+	msg, err := s.git.GetBranchCommitMessage(SandboxBranch)
+	if err != nil {
+		return "", fmt.Errorf("reading %s's message: %w", SandboxBranch, err)
+	}
+	if err := s.git.Checkout(SandboxTarget); err != nil {
+		return "", fmt.Errorf("checking out %s: %w", SandboxTarget, err)
+	}
+	if err := s.git.MergeSquash(SandboxBranch, msg); err != nil {
+		_ = s.git.AbortMerge()
+		return "", fmt.Errorf("squash merging %s: %w", SandboxBranch, err)
+	}
+	return s.git.Rev("HEAD")
+}
+
+// Sign replaces the merge commit checked out on the scratch target with a
+// signed copy, as the refinery signs merges before they land, and returns
+// it.
+func (s *Sandbox) Sign(sig *Signing) (string, error) {
+	if err := s.git.AmendSigned(sig.format, sig.key); err != nil {
+		return "", fmt.Errorf("signing: %w", err)
+	}
+	return s.git.Rev("HEAD")
+}
+
+// Remove deletes the sandbox.
+func (s *Sandbox) Remove() error {
+	return os.RemoveAll(s.tmp)
+}
@@ -0,0 +1,61 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSandbox(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	originMain := runGit(t, origin, "rev-parse", "main")
+
+	s, err := e.NewSandbox("main", "chore: try the refinery (gp-sandbox)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Base != originMain {
+		t.Errorf("sandbox base = %s, want origin's main %s", s.Base, originMain)
+	}
+
+	if err := s.Rebase(); err != nil {
+		t.Fatalf("Rebase: %v", err)
+	}
+	if got := runGit(t, s.Dir, "rev-list", "--count", SandboxTarget+".."+SandboxBranch); got != "1" {
+		t.Errorf("%s commits on the MR after rebasing, want 1", got)
+	}
+	commit, err := s.Merge()
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if got := runGit(t, s.Dir, "log", "-1", "--format=%s", SandboxTarget); got != "chore: try the refinery (gp-sandbox)" {
+		t.Errorf("merge commit subject = %q", got)
+	}
+	if got := runGit(t, s.Dir, "rev-parse", "HEAD"); got != commit {
+		t.Errorf("HEAD = %s, want the merge %s checked out", got, commit)
+	}
+	for _, f := range []string{sandboxMRFile, sandboxTargetFile} {
+		if _, err := os.Stat(filepath.Join(s.Dir, f)); err != nil {
+			t.Errorf("merged result lacks %s: %v", f, err)
+		}
+	}
+
+	// Nothing reached origin or the refinery's clone
+	if got := runGit(t, origin, "rev-parse", "main"); got != originMain {
+		t.Errorf("origin main moved to %s", got)
+	}
+	if got := runGit(t, origin, "branch", "--list", "gt-sandbox/*"); got != "" {
+		t.Errorf("sandbox branches pushed to origin: %s", got)
+	}
+	if got := runGit(t, filepath.Join(rigPath, "refinery", "rig"), "branch", "--list", "gt-sandbox/*"); got != "" {
+		t.Errorf("sandbox branches in the refinery's clone: %s", got)
+	}
+
+	if err := s.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Dir); !os.IsNotExist(err) {
+		t.Errorf("sandbox left behind: %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrInvalidScheduleWindow indicates a malformed merge window spec.
+var ErrInvalidScheduleWindow = errors.New("invalid schedule window")
+
+// Schedule decides when the refinery may merge, based on the rig's
+// merge_queue.schedule settings. A nil *Schedule is always open.
+type Schedule struct {
+	windows    []scheduleWindow
+	quietHours []scheduleWindow
+	loc        *time.Location
+	p0Override bool
+}
+
+// scheduleWindow is a parsed "[days] HH:MM-HH:MM" spec.
+type scheduleWindow struct {
+	spec  string
+	days  [7]bool // indexed by time.Weekday
+	start int     // minutes after midnight
+	end   int     // minutes after midnight; <= start wraps past midnight
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// NewSchedule builds a Schedule from config. Returns nil (always open) if
+// cfg is nil or defines no windows.
+func NewSchedule(cfg *config.MergeScheduleConfig) (*Schedule, error) {
+	if cfg == nil || (len(cfg.Windows) == 0 && len(cfg.QuietHours) == 0) {
+		return nil, nil
+	}
+
+	s := &Schedule{loc: time.Local, p0Override: cfg.P0Override}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule timezone %q: %w", cfg.Timezone, err)
+		}
+		s.loc = loc
+	}
+	for _, spec := range cfg.Windows {
+		w, err := parseScheduleWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, w)
+	}
+	for _, spec := range cfg.QuietHours {
+		w, err := parseScheduleWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		s.quietHours = append(s.quietHours, w)
+	}
+	return s, nil
+}
+
+// LoadSchedule reads the merge schedule from a rig's settings/config.json.
+// A missing settings file or schedule section yields a nil (always open) schedule.
+func LoadSchedule(rigPath string) (*Schedule, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewSchedule(settings.MergeQueue.Schedule)
+}
+
+// Check reports whether merges are allowed at now. When closed, reason
+// explains which rule applies.
+func (s *Schedule) Check(now time.Time) (open bool, reason string) {
+	if s == nil {
+		return true, ""
+	}
+	now = now.In(s.loc)
+	for _, w := range s.quietHours {
+		if w.contains(now) {
+			return false, fmt.Sprintf("quiet hours %s", w.spec)
+		}
+	}
+	if len(s.windows) == 0 {
+		return true, ""
+	}
+	for _, w := range s.windows {
+		if w.contains(now) {
+			return true, ""
+		}
+	}
+	return false, "outside merge windows"
+}
+
+// Permits reports whether an MR with the given priority may merge at now.
+// P0 MRs bypass a closed schedule when p0_override is enabled.
+func (s *Schedule) Permits(now time.Time, priority int) bool {
+	if open, _ := s.Check(now); open {
+		return true
+	}
+	return s.p0Override && priority == 0
+}
+
+// P0Override reports whether P0 MRs may merge outside the schedule.
+func (s *Schedule) P0Override() bool {
+	return s != nil && s.p0Override
+}
+
+// NextOpen returns the next time at or after now when the schedule opens,
+// searching up to a week ahead at minute granularity. Returns the zero time
+// if the schedule never opens.
+func (s *Schedule) NextOpen(now time.Time) time.Time {
+	t := now.Truncate(time.Minute)
+	for i := 0; i <= 7*24*60; i++ {
+		if open, _ := s.Check(t); open {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}
+}
+
+// parseScheduleWindow parses "[days] HH:MM-HH:MM" or a bare days spec
+// (which covers the whole day).
+func parseScheduleWindow(spec string) (scheduleWindow, error) {
+	w := scheduleWindow{spec: strings.TrimSpace(spec), end: 24 * 60}
+	fields := strings.Fields(strings.ToLower(w.spec))
+
+	var daySpec, timeSpec string
+	switch len(fields) {
+	case 1:
+		if strings.Contains(fields[0], ":") {
+			timeSpec = fields[0]
+		} else {
+			daySpec = fields[0]
+		}
+	case 2:
+		daySpec, timeSpec = fields[0], fields[1]
+	default:
+		return w, fmt.Errorf("%w: %q (want \"[days] HH:MM-HH:MM\")", ErrInvalidScheduleWindow, spec)
+	}
+
+	if daySpec == "" || daySpec == "daily" {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	} else if err := parseScheduleDays(daySpec, &w.days); err != nil {
+		return w, fmt.Errorf("%w: %q: %v", ErrInvalidScheduleWindow, spec, err)
+	}
+
+	if timeSpec != "" {
+		startStr, endStr, ok := strings.Cut(timeSpec, "-")
+		if !ok {
+			return w, fmt.Errorf("%w: %q: time range must be HH:MM-HH:MM", ErrInvalidScheduleWindow, spec)
+		}
+		var err error
+		if w.start, err = parseClock(startStr); err != nil {
+			return w, fmt.Errorf("%w: %q: %v", ErrInvalidScheduleWindow, spec, err)
+		}
+		if w.end, err = parseClock(endStr); err != nil {
+			return w, fmt.Errorf("%w: %q: %v", ErrInvalidScheduleWindow, spec, err)
+		}
+		if w.start == w.end {
+			return w, fmt.Errorf("%w: %q: empty time range", ErrInvalidScheduleWindow, spec)
+		}
+	}
+	return w, nil
+}
+
+// parseScheduleDays parses "weekdays", "weekends", "mon-fri", or "mon,wed,fri".
+func parseScheduleDays(spec string, days *[7]bool) error {
+	switch spec {
+	case "weekdays":
+		spec = "mon-fri"
+	case "weekends":
+		spec = "sat,sun"
+	}
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := weekdayNames[from]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		if !isRange {
+			days[start] = true
+			continue
+		}
+		end, ok := weekdayNames[to]
+		if !ok {
+			return fmt.Errorf("unknown day %q", to)
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" (00:00-24:00) into minutes after midnight.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether t falls inside the window. For windows that wrap
+// past midnight, the day list applies to the day the window starts.
+func (w scheduleWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if w.start < w.end {
+		return w.days[day] && minute >= w.start && minute < w.end
+	}
+	prev := (day + 6) % 7
+	return (w.days[day] && minute >= w.start) || (w.days[prev] && minute < w.end)
+}
@@ -0,0 +1,117 @@
+package refinery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseScheduleWindow(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"22:00-06:00", false},
+		{"weekdays 09:00-17:00", false},
+		{"mon,wed,fri 10:00-12:00", false},
+		{"fri-mon 00:00-24:00", false},
+		{"weekends", false},
+		{"daily 08:00-20:00", false},
+		{"funday 09:00-17:00", true},
+		{"09:00", true},
+		{"25:00-26:00", true},
+		{"09:00-09:00", true},
+		{"mon 09:00-17:00 extra", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := parseScheduleWindow(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseScheduleWindow(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidScheduleWindow) {
+				t.Errorf("error should wrap ErrInvalidScheduleWindow, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScheduleCheck(t *testing.T) {
+	s, err := NewSchedule(&config.MergeScheduleConfig{
+		Windows:    []string{"weekdays 06:00-23:00"},
+		QuietHours: []string{"22:00-06:00"},
+		Timezone:   "UTC",
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+
+	// 2026-10-14 is a Wednesday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"weekday midday", at(14, 12, 0), true},
+		{"weekday quiet evening", at(14, 22, 30), false},
+		{"weekday early morning wraps", at(14, 5, 59), false},
+		{"window opens", at(14, 6, 0), true},
+		{"saturday outside windows", at(17, 12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := s.Check(tt.now); got != tt.want {
+				t.Errorf("Check(%v) = %v (%s), want %v", tt.now, got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePermitsP0Override(t *testing.T) {
+	cfg := &config.MergeScheduleConfig{QuietHours: []string{"daily 00:00-24:00"}, Timezone: "UTC"}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	s, err := NewSchedule(cfg)
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	if s.Permits(now, 0) {
+		t.Error("P0 should not bypass schedule without p0_override")
+	}
+
+	cfg.P0Override = true
+	s, _ = NewSchedule(cfg)
+	if !s.Permits(now, 0) {
+		t.Error("P0 should bypass schedule with p0_override")
+	}
+	if s.Permits(now, 1) {
+		t.Error("P1 should never bypass schedule")
+	}
+}
+
+func TestNilScheduleAlwaysOpen(t *testing.T) {
+	s, err := NewSchedule(nil)
+	if err != nil || s != nil {
+		t.Fatalf("NewSchedule(nil) = %v, %v; want nil, nil", s, err)
+	}
+	if open, _ := s.Check(time.Now()); !open {
+		t.Error("nil schedule should always be open")
+	}
+}
+
+func TestScheduleNextOpen(t *testing.T) {
+	s, _ := NewSchedule(&config.MergeScheduleConfig{QuietHours: []string{"22:00-06:00"}, Timezone: "UTC"})
+	now := time.Date(2026, 10, 14, 23, 15, 0, 0, time.UTC)
+	want := time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC)
+	if got := s.NextOpen(now); !got.Equal(want) {
+		t.Errorf("NextOpen = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Merge commit signature formats.
+const (
+	SigningGPG = "gpg"
+	SigningSSH = "ssh"
+)
+
+// Signing holds a rig's merge_queue.signing settings: the refinery signs
+// each merge commit before it lands, and nothing lands unsigned. A nil
+// *Signing lands commits as they are.
+type Signing struct {
+	format string
+	key    string
+}
+
+// NewSigning builds signing settings from config. Returns nil if cfg is nil.
+func NewSigning(cfg *config.MergeSigningConfig) (*Signing, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	s := &Signing{format: cfg.Format, key: cfg.Key}
+	switch s.format {
+	case "":
+		s.format = SigningGPG
+	case SigningGPG, SigningSSH:
+	default:
+		return nil, fmt.Errorf("invalid signing format %q (want %q or %q)", cfg.Format, SigningGPG, SigningSSH)
+	}
+	return s, nil
+}
+
+// LoadSigning reads signing settings from a rig's settings/config.json.
+// A missing settings file or signing section yields nil (unsigned).
+func LoadSigning(rigPath string) (*Signing, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewSigning(settings.MergeQueue.Signing)
+}
+
+// Format returns the signature format, or "" if merges aren't signed.
+func (s *Signing) Format() string {
+	if s == nil {
+		return ""
+	}
+	return s.format
+}
+
+// signCommit returns a signed copy of commit: the same tree, parents,
+// message and author, committed by the refinery with its signature. The
+// commit is made in a temporary worktree so the refinery's checkout is left
+// untouched.
+func (e *Engineer) signCommit(s *Signing, commit string) (string, error) {
+	tmp, err := os.MkdirTemp("", mergeWorktreePrefix)
+	if err != nil {
+		return "", fmt.Errorf("creating worktree dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "wt")
+	if err := e.git.WorktreeAddDetached(path, commit); err != nil {
+		return "", fmt.Errorf("checking out %s: %w", shortCommit(commit), err)
+	}
+	defer func() {
+		_ = e.git.WorktreeRemove(path, true)
+		_ = e.git.WorktreePrune()
+	}()
+
+	wt := git.NewGit(path)
+	if err := wt.AmendSigned(s.format, s.key); err != nil {
+		return "", fmt.Errorf("signing %s: %w", shortCommit(commit), err)
+	}
+	return wt.Rev("HEAD")
+}
+
+// moveTarget points the refinery's local target branch at to, if it is at
+// from. Best-effort, like fastForwardTarget.
+func (e *Engineer) moveTarget(target, from, to string) {
+	if local, err := e.git.Rev(target); err != nil || local != from {
+		return
+	}
+	var err error
+	if current, _ := e.git.CurrentBranch(); current == target {
+		err = e.git.ResetHard(to)
+	} else {
+		err = e.git.ResetBranch(target, to)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not update local %s: %v\n", target, err)
+	}
+}
@@ -0,0 +1,85 @@
+package refinery
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewSigning(t *testing.T) {
+	if s, err := NewSigning(nil); s != nil || err != nil {
+		t.Errorf("NewSigning(nil) = %v, %v; want nil, nil", s, err)
+	}
+	s, err := NewSigning(&config.MergeSigningConfig{})
+	if err != nil {
+		t.Fatalf("NewSigning: %v", err)
+	}
+	if s.Format() != SigningGPG {
+		t.Errorf("default format = %q, want %q", s.Format(), SigningGPG)
+	}
+	if _, err := NewSigning(&config.MergeSigningConfig{Format: "x509"}); err == nil {
+		t.Error("NewSigning should reject unknown formats")
+	}
+	if (*Signing)(nil).Format() != "" {
+		t.Error("nil Signing should have no format")
+	}
+}
+
+func TestLandSigned(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	refineryRig := stageLocalMerge(t, rigPath)
+	unsigned := runGit(t, refineryRig, "rev-parse", "HEAD")
+
+	key := filepath.Join(t.TempDir(), "refinery")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, out)
+	}
+	s := &Signing{format: SigningSSH, key: key}
+
+	result := e.Land(context.Background(), &Transaction{verifyCommand: "test -f feature.txt"}, s, "gp-mr-1", "main", "main")
+	if !result.Success {
+		t.Fatalf("Land failed: %s", result.Error)
+	}
+	if result.Signed != SigningSSH {
+		t.Errorf("Signed = %q, want %q", result.Signed, SigningSSH)
+	}
+	if result.MergeCommit == unsigned {
+		t.Fatal("landed the unsigned commit")
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("origin main = %s, want %s", got, result.MergeCommit)
+	}
+	if got := runGit(t, refineryRig, "rev-parse", "HEAD"); got != result.MergeCommit {
+		t.Errorf("refinery main = %s, want the signed commit", got)
+	}
+	if sig := runGit(t, origin, "cat-file", "-p", "main"); !strings.Contains(sig, "gpgsig -----BEGIN SSH SIGNATURE-----") {
+		t.Errorf("landed commit is not signed:\n%s", sig)
+	}
+	if got, want := runGit(t, origin, "rev-parse", "main^{tree}"), runGit(t, refineryRig, "rev-parse", unsigned+"^{tree}"); got != want {
+		t.Error("signing changed the tree")
+	}
+}
+
+func TestLandSigningFailure(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	before := runGit(t, origin, "rev-parse", "main")
+	stageLocalMerge(t, rigPath)
+	s := &Signing{format: SigningSSH, key: filepath.Join(t.TempDir(), "missing")}
+
+	result := e.Land(context.Background(), nil, s, "gp-mr-1", "main", "main")
+	if result.Success {
+		t.Fatal("Land succeeded without a usable signing key")
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != before {
+		t.Error("origin main moved although the commit couldn't be signed")
+	}
+}
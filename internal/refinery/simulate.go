@@ -0,0 +1,142 @@
+package refinery
+
+import (
+	"sort"
+	"time"
+)
+
+// Simulated outcomes of an MR.
+const (
+	SimMerge    = "merge"    // Merges cleanly onto the MRs before it
+	SimConflict = "conflict" // Touches files an earlier MR in the run changed
+)
+
+// SimMR is a merge request to simulate, given in the order the refinery
+// would take it.
+type SimMR struct {
+	ID       string
+	Branch   string
+	Target   string
+	Worker   string
+	Priority int
+	Files    []string // Files it changes relative to its target
+}
+
+// SimStep is the projected outcome of one MR in a simulation.
+type SimStep struct {
+	Position      int        `json:"position"`
+	ID            string     `json:"id"`
+	Branch        string     `json:"branch"`
+	Target        string     `json:"target"`
+	Worker        string     `json:"worker,omitempty"`
+	Priority      int        `json:"priority"`
+	Outcome       string     `json:"outcome"`                  // SimMerge or SimConflict
+	ConflictsWith []string   `json:"conflicts_with,omitempty"` // Earlier MRs that change the same files
+	ConflictFiles []string   `json:"conflict_files,omitempty"`
+	Start         *time.Time `json:"start,omitempty"` // When the refinery takes it, if it can be estimated
+	Finish        *time.Time `json:"finish,omitempty"`
+}
+
+// Simulation projects how the refinery would work through a queue without
+// touching it.
+type Simulation struct {
+	// Start is when the refinery takes the first MR.
+	Start time.Time
+
+	// Duration is how long checking and merging one MR takes; 0 if
+	// unknown, which leaves steps without times. MRs that conflict fail
+	// before their checks run and take no time.
+	Duration time.Duration
+
+	// Schedule holds MRs until merges are allowed (nil = any time).
+	Schedule *Schedule
+}
+
+// Run processes mrs one at a time, in order. An MR conflicts if it changes
+// a file that an earlier MR with the same target merged in the run; it
+// then fails and its own changes don't land, so later MRs are compared
+// only with what merged.
+func (s Simulation) Run(mrs []SimMR) []SimStep {
+	merged := make(map[string]map[string]string) // target -> file -> MR that changed it
+	at := s.Start
+	timed := s.Duration > 0
+
+	steps := make([]SimStep, 0, len(mrs))
+	for i, mr := range mrs {
+		step := SimStep{
+			Position: i + 1,
+			ID:       mr.ID,
+			Branch:   mr.Branch,
+			Target:   mr.Target,
+			Worker:   mr.Worker,
+			Priority: mr.Priority,
+			Outcome:  SimMerge,
+		}
+
+		files := merged[mr.Target]
+		if files == nil {
+			files = make(map[string]string)
+			merged[mr.Target] = files
+		}
+		with := make(map[string]bool)
+		for _, f := range mr.Files {
+			if by, ok := files[f]; ok {
+				with[by] = true
+				step.ConflictFiles = append(step.ConflictFiles, f)
+			}
+		}
+		if len(step.ConflictFiles) > 0 {
+			step.Outcome = SimConflict
+			for id := range with {
+				step.ConflictsWith = append(step.ConflictsWith, id)
+			}
+			sort.Strings(step.ConflictsWith)
+			step.ConflictFiles = uniqueSorted(step.ConflictFiles)
+		} else {
+			for _, f := range mr.Files {
+				files[f] = mr.ID
+			}
+		}
+
+		if timed && !s.Schedule.Permits(at, mr.Priority) {
+			if at = s.Schedule.NextOpen(at); at.IsZero() {
+				timed = false // The schedule never opens
+			}
+		}
+		if timed {
+			start := at
+			if step.Outcome == SimMerge {
+				at = at.Add(s.Duration)
+			}
+			finish := at
+			step.Start, step.Finish = &start, &finish
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// MedianDuration returns the median of ds, or 0 if ds is empty.
+func MedianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func uniqueSorted(ss []string) []string {
+	sort.Strings(ss)
+	out := ss[:0]
+	for i, s := range ss {
+		if i == 0 || s != ss[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestSimulationRun(t *testing.T) {
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	sim := Simulation{Start: start, Duration: 10 * time.Minute}
+
+	steps := sim.Run([]SimMR{
+		{ID: "mr-1", Target: "main", Files: []string{"a.go", "b.go"}},
+		{ID: "mr-2", Target: "main", Files: []string{"b.go", "c.go"}},
+		{ID: "mr-3", Target: "main", Files: []string{"c.go"}},         // mr-2 didn't merge, so no conflict
+		{ID: "mr-4", Target: "release", Files: []string{"a.go"}},      // different target
+		{ID: "mr-5", Target: "main", Files: []string{"a.go", "c.go"}}, // conflicts with mr-1 and mr-3
+	})
+
+	wantOutcomes := []string{SimMerge, SimConflict, SimMerge, SimMerge, SimConflict}
+	for i, want := range wantOutcomes {
+		if steps[i].Outcome != want {
+			t.Errorf("%s outcome = %s, want %s", steps[i].ID, steps[i].Outcome, want)
+		}
+		if steps[i].Position != i+1 {
+			t.Errorf("%s position = %d, want %d", steps[i].ID, steps[i].Position, i+1)
+		}
+	}
+	if got := steps[1].ConflictsWith; !reflect.DeepEqual(got, []string{"mr-1"}) {
+		t.Errorf("mr-2 conflicts with %v, want [mr-1]", got)
+	}
+	if got := steps[4].ConflictsWith; !reflect.DeepEqual(got, []string{"mr-1", "mr-3"}) {
+		t.Errorf("mr-5 conflicts with %v, want [mr-1 mr-3]", got)
+	}
+	if got := steps[4].ConflictFiles; !reflect.DeepEqual(got, []string{"a.go", "c.go"}) {
+		t.Errorf("mr-5 conflict files = %v, want [a.go c.go]", got)
+	}
+
+	// Merges take Duration each; conflicts fail without taking any
+	wantFinish := []time.Duration{10, 10, 20, 30, 30}
+	for i, want := range wantFinish {
+		if got := steps[i].Finish.Sub(start); got != want*time.Minute {
+			t.Errorf("%s finishes after %v, want %v", steps[i].ID, got, want*time.Minute)
+		}
+	}
+}
+
+func TestSimulationRun_NoDuration(t *testing.T) {
+	steps := Simulation{Start: time.Now()}.Run([]SimMR{{ID: "mr-1", Target: "main"}})
+	if steps[0].Start != nil || steps[0].Finish != nil {
+		t.Errorf("step has times without a duration: %+v", steps[0])
+	}
+}
+
+func TestSimulationRun_Schedule(t *testing.T) {
+	s, err := NewSchedule(&config.MergeScheduleConfig{QuietHours: []string{"22:00-06:00"}, Timezone: "UTC", P0Override: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 14, 21, 50, 0, 0, time.UTC)
+	steps := Simulation{Start: start, Duration: 20 * time.Minute, Schedule: s}.Run([]SimMR{
+		{ID: "mr-1", Target: "main", Priority: 2},
+		{ID: "mr-2", Target: "main", Priority: 0}, // P0 bypasses quiet hours
+		{ID: "mr-3", Target: "main", Priority: 2}, // waits for 06:00
+	})
+
+	want := []time.Time{
+		start,
+		start.Add(20 * time.Minute),
+		time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC),
+	}
+	for i, w := range want {
+		if !steps[i].Start.Equal(w) {
+			t.Errorf("%s starts at %v, want %v", steps[i].ID, steps[i].Start, w)
+		}
+	}
+}
+
+func TestMedianDuration(t *testing.T) {
+	tests := []struct {
+		ds   []time.Duration
+		want time.Duration
+	}{
+		{nil, 0},
+		{[]time.Duration{3}, 3},
+		{[]time.Duration{5, 1, 3}, 3},
+		{[]time.Duration{4, 1, 3, 2}, 2},
+	}
+	for _, tt := range tests {
+		if got := MedianDuration(tt.ds); got != tt.want {
+			t.Errorf("MedianDuration(%v) = %v, want %v", tt.ds, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultSLANotify is where SLA breaches are mailed if the rig names no one.
+const DefaultSLANotify = "mayor/"
+
+// SLA holds merge time targets by priority, based on the rig's
+// merge_queue.sla settings, and those of its projects that replace them. A
+// nil *SLA has no targets.
+type SLA struct {
+	targets  map[int]time.Duration
+	notify   string
+	projects map[string]*SLA
+}
+
+// NewSLA builds an SLA from config. Returns nil (no targets) if cfg is nil
+// or sets no targets.
+func NewSLA(cfg *config.MergeSLAConfig) (*SLA, error) {
+	if cfg == nil || len(cfg.Targets) == 0 {
+		return nil, nil
+	}
+
+	s := &SLA{targets: make(map[int]time.Duration), notify: cfg.Notify}
+	if s.notify == "" {
+		s.notify = DefaultSLANotify
+	}
+	for key, spec := range cfg.Targets {
+		priority, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(key), "P"))
+		if err != nil || priority < 0 || priority > 4 {
+			return nil, fmt.Errorf("invalid SLA priority %q (want P0-P4)", key)
+		}
+		target, err := parseSLADuration(spec)
+		if err != nil || target <= 0 {
+			return nil, fmt.Errorf("invalid SLA target %q for %s", spec, key)
+		}
+		s.targets[priority] = target
+	}
+	return s, nil
+}
+
+// LoadSLA reads the merge SLA from a rig's settings/config.json, with its
+// projects' (merge_queue.projects). A missing settings file, or no sla
+// section anywhere, yields nil (no targets). A project's SLA without a
+// notify address notifies the rig's.
+func LoadSLA(rigPath string) (*SLA, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	s, err := NewSLA(settings.MergeQueue.SLA)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range settings.MergeQueue.Projects {
+		if project.SLA == nil {
+			continue
+		}
+		ps, err := NewSLA(project.SLA)
+		if err != nil {
+			return nil, fmt.Errorf("merge project %s: %w", project.Name, err)
+		}
+		if s == nil {
+			s = &SLA{notify: DefaultSLANotify}
+			if settings.MergeQueue.SLA != nil && settings.MergeQueue.SLA.Notify != "" {
+				s.notify = settings.MergeQueue.SLA.Notify
+			}
+		}
+		if ps != nil && project.SLA.Notify == "" {
+			ps.notify = s.notify
+		}
+		if s.projects == nil {
+			s.projects = make(map[string]*SLA)
+		}
+		s.projects[project.Name] = ps
+	}
+	return s, nil
+}
+
+// For returns the SLA for MRs in project: the project's own, if it sets
+// one, else s.
+func (s *SLA) For(project string) *SLA {
+	if s == nil {
+		return nil
+	}
+	if ps, ok := s.projects[project]; ok {
+		return ps
+	}
+	return s
+}
+
+// parseSLADuration parses a Go duration or a whole number of days ("2d").
+func parseSLADuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid days: %s", days)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Notify returns the mail address told about breaches.
+func (s *SLA) Notify() string {
+	if s == nil {
+		return DefaultSLANotify
+	}
+	return s.notify
+}
+
+// Target returns the merge time target for a priority, if it has one.
+func (s *SLA) Target(priority int) (time.Duration, bool) {
+	if s == nil {
+		return 0, false
+	}
+	target, ok := s.targets[priority]
+	return target, ok
+}
+
+// Overdue returns how far past its target an MR of the given priority,
+// submitted at createdAt, is at now. Zero means within target or no target.
+func (s *SLA) Overdue(priority int, createdAt, now time.Time) time.Duration {
+	target, ok := s.Target(priority)
+	if !ok || createdAt.IsZero() {
+		return 0
+	}
+	if over := now.Sub(createdAt) - target; over > 0 {
+		return over
+	}
+	return 0
+}
+
+// SLABreach is an unmerged MR past its priority's target.
+type SLABreach struct {
+	ID       string
+	Worker   string
+	Project  string
+	Priority int
+	Target   time.Duration
+	Overdue  time.Duration
+	Notify   string // who to tell: the MR's project's SLA notify address
+}
+
+// Breaches returns the MRs among issues that are still unmerged (open or in
+// progress) and past their target, most overdue first.
+func (s *SLA) Breaches(issues []*beads.Issue, now time.Time) []SLABreach {
+	var breaches []SLABreach
+	for _, issue := range issues {
+		if issue.Status != "open" && issue.Status != "in_progress" {
+			continue
+		}
+		createdAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
+		if err != nil {
+			continue
+		}
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			fields = &beads.MRFields{}
+		}
+		sla := s.For(fields.Project)
+		over := sla.Overdue(issue.Priority, createdAt, now)
+		if over == 0 {
+			continue
+		}
+		b := SLABreach{ID: issue.ID, Worker: fields.Worker, Project: fields.Project, Priority: issue.Priority, Overdue: over, Notify: sla.Notify()}
+		b.Target, _ = sla.Target(issue.Priority)
+		breaches = append(breaches, b)
+	}
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].Overdue > breaches[j].Overdue
+	})
+	return breaches
+}
@@ -0,0 +1,78 @@
+package refinery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewSLA(t *testing.T) {
+	if s, err := NewSLA(nil); s != nil || err != nil {
+		t.Errorf("NewSLA(nil) = %v, %v; want nil, nil", s, err)
+	}
+	for _, targets := range []map[string]string{
+		{"P5": "1h"},
+		{"urgent": "1h"},
+		{"P0": "soon"},
+		{"P0": "-1h"},
+	} {
+		if _, err := NewSLA(&config.MergeSLAConfig{Targets: targets}); err == nil {
+			t.Errorf("NewSLA(%v) should fail", targets)
+		}
+	}
+
+	s, err := NewSLA(&config.MergeSLAConfig{Targets: map[string]string{"P0": "1h", "p2": "2d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, ok := s.Target(2); !ok || target != 48*time.Hour {
+		t.Errorf("P2 target = %v, %v; want 48h", target, ok)
+	}
+	if _, ok := s.Target(1); ok {
+		t.Error("P1 should have no target")
+	}
+	if s.Notify() != DefaultSLANotify {
+		t.Errorf("Notify() = %q, want %q", s.Notify(), DefaultSLANotify)
+	}
+}
+
+func TestSLABreaches(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	s, err := NewSLA(&config.MergeSLAConfig{Targets: map[string]string{"P0": "1h", "P2": "1d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := func(id, status string, priority int, age time.Duration) *beads.Issue {
+		return &beads.Issue{
+			ID:          id,
+			Status:      status,
+			Priority:    priority,
+			CreatedAt:   now.Add(-age).Format(time.RFC3339),
+			Description: "branch: polecat/" + id + "\nworker: toast",
+		}
+	}
+
+	breaches := s.Breaches([]*beads.Issue{
+		mr("gt-1", "open", 0, 90*time.Minute),
+		mr("gt-2", "open", 0, 30*time.Minute),
+		mr("gt-3", "in_progress", 2, 30*time.Hour),
+		mr("gt-4", "closed", 0, 5*time.Hour),
+		mr("gt-5", "open", 3, 72*time.Hour),
+	}, now)
+
+	if len(breaches) != 2 {
+		t.Fatalf("breaches = %+v, want gt-3 and gt-1", breaches)
+	}
+	if b := breaches[0]; b.ID != "gt-3" || b.Overdue != 6*time.Hour || b.Target != 24*time.Hour || b.Worker != "toast" {
+		t.Errorf("breaches[0] = %+v", b)
+	}
+	if b := breaches[1]; b.ID != "gt-1" || b.Overdue != 30*time.Minute {
+		t.Errorf("breaches[1] = %+v", b)
+	}
+
+	if got := (*SLA)(nil).Breaches([]*beads.Issue{mr("gt-1", "open", 0, 90*time.Hour)}, now); len(got) != 0 {
+		t.Errorf("nil SLA breaches = %+v, want none", got)
+	}
+}
@@ -0,0 +1,224 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// snapshotTimeFormat names snapshot files; it sorts chronologically.
+const snapshotTimeFormat = "2006-01-02T150405Z"
+
+// QueueSnapshot is a rig's merge queue at one point in time: every MR bead
+// in the rig's beads database (archived MRs are not included).
+type QueueSnapshot struct {
+	Rig     string       `json:"rig"`
+	TakenAt time.Time    `json:"taken_at"`
+	MRs     []SnapshotMR `json:"mrs"`
+}
+
+// SnapshotMR is one MR as it stood when a snapshot was taken.
+type SnapshotMR struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	State       MRState `json:"state"`
+	Priority    int     `json:"priority"`
+	Branch      string  `json:"branch,omitempty"`
+	Target      string  `json:"target,omitempty"`
+	Worker      string  `json:"worker,omitempty"`
+	SourceIssue string  `json:"source_issue,omitempty"`
+	RetryCount  int     `json:"retry_count,omitempty"`
+	MergeCommit string  `json:"merge_commit,omitempty"`
+	CloseReason string  `json:"close_reason,omitempty"`
+	UpdatedAt   string  `json:"updated_at,omitempty"`
+}
+
+// NewQueueSnapshot captures the MRs among issues, ordered by ID.
+func NewQueueSnapshot(rigName string, issues []*beads.Issue, now time.Time) *QueueSnapshot {
+	s := &QueueSnapshot{Rig: rigName, TakenAt: now.UTC().Truncate(time.Second), MRs: []SnapshotMR{}}
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			continue
+		}
+		s.MRs = append(s.MRs, SnapshotMR{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			State:       StateOf(issue),
+			Priority:    issue.Priority,
+			Branch:      fields.Branch,
+			Target:      fields.Target,
+			Worker:      fields.Worker,
+			SourceIssue: fields.SourceIssue,
+			RetryCount:  fields.RetryCount,
+			MergeCommit: fields.MergeCommit,
+			CloseReason: fields.CloseReason,
+			UpdatedAt:   issue.UpdatedAt,
+		})
+	}
+	sort.Slice(s.MRs, func(i, j int) bool { return s.MRs[i].ID < s.MRs[j].ID })
+	return s
+}
+
+// SnapshotDir is where a rig's queue snapshots are kept.
+func SnapshotDir(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "mq-snapshots")
+}
+
+// Save writes the snapshot into dir, named for the time it was taken, and
+// returns its path.
+func (s *QueueSnapshot) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating snapshot dir: %w", err)
+	}
+	path := filepath.Join(dir, s.TakenAt.UTC().Format(snapshotTimeFormat)+".json")
+	if err := util.AtomicWriteJSON(path, s); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// LoadQueueSnapshot reads a snapshot file.
+func LoadQueueSnapshot(path string) (*QueueSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s QueueSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing snapshot %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// ListSnapshots returns the snapshot files in dir, oldest first. A missing
+// dir has none.
+func ListSnapshots(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// FindSnapshot resolves a snapshot name in dir: "latest", "previous" (the
+// one before latest), or a prefix of a snapshot's name such as
+// "2026-10-14", which picks the latest snapshot that day.
+func FindSnapshot(dir, name string) (string, error) {
+	paths, err := ListSnapshots(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no snapshots in %s; take one with gt mq snapshot", dir)
+	}
+	switch name {
+	case "latest":
+		return paths[len(paths)-1], nil
+	case "previous":
+		if len(paths) < 2 {
+			return "", fmt.Errorf("only one snapshot in %s", dir)
+		}
+		return paths[len(paths)-2], nil
+	}
+	for i := len(paths) - 1; i >= 0; i-- {
+		if strings.HasPrefix(filepath.Base(paths[i]), name) {
+			return paths[i], nil
+		}
+	}
+	return "", fmt.Errorf("no snapshot matching %q in %s", name, dir)
+}
+
+// SnapshotChange is an MR whose standing differs between two snapshots.
+// From is "" for an MR the first snapshot didn't have, and To is "" for
+// one the second doesn't.
+type SnapshotChange struct {
+	ID     string  `json:"id"`
+	Title  string  `json:"title"`
+	Worker string  `json:"worker,omitempty"`
+	Target string  `json:"target,omitempty"`
+	From   MRState `json:"from,omitempty"`
+	To     MRState `json:"to,omitempty"`
+	Reason string  `json:"reason,omitempty"` // Close reason, for failures
+}
+
+// SnapshotDiff is how a rig's merge queue changed between two snapshots.
+type SnapshotDiff struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Merged  []SnapshotChange `json:"merged"`  // Merged since the first snapshot
+	Failed  []SnapshotChange `json:"failed"`  // Regressed to failed or rejected
+	Closed  []SnapshotChange `json:"closed"`  // Cancelled or gone stale
+	Stuck   []SnapshotChange `json:"stuck"`   // Open in both, in the same state
+	Moved   []SnapshotChange `json:"moved"`   // Open in both, in a different state
+	Added   []SnapshotChange `json:"added"`   // Submitted since the first snapshot
+	Removed []SnapshotChange `json:"removed"` // Open in the first, gone from the second (deleted)
+}
+
+// DiffSnapshots compares snapshot a with a later snapshot b.
+func DiffSnapshots(a, b *QueueSnapshot) SnapshotDiff {
+	d := SnapshotDiff{
+		From: a.TakenAt, To: b.TakenAt,
+		Merged: []SnapshotChange{}, Failed: []SnapshotChange{}, Closed: []SnapshotChange{},
+		Stuck: []SnapshotChange{}, Moved: []SnapshotChange{}, Added: []SnapshotChange{},
+		Removed: []SnapshotChange{},
+	}
+	before := make(map[string]SnapshotMR, len(a.MRs))
+	for _, mr := range a.MRs {
+		before[mr.ID] = mr
+	}
+
+	for _, mr := range b.MRs {
+		old, existed := before[mr.ID]
+		delete(before, mr.ID)
+		change := SnapshotChange{ID: mr.ID, Title: mr.Title, Worker: mr.Worker, Target: mr.Target, To: mr.State}
+		if existed {
+			change.From = old.State
+		}
+
+		switch {
+		case existed && old.State == mr.State && mr.State.Terminal():
+			// Closed before the first snapshot; nothing new
+		case mr.State == StateMerged:
+			d.Merged = append(d.Merged, change)
+		case mr.State == StateFailed || mr.State == StateRejected:
+			change.Reason = mr.CloseReason
+			d.Failed = append(d.Failed, change)
+		case mr.State.Terminal():
+			d.Closed = append(d.Closed, change)
+		case !existed:
+			d.Added = append(d.Added, change)
+		case old.State != mr.State: // Includes MRs reopened since
+			d.Moved = append(d.Moved, change)
+		default:
+			d.Stuck = append(d.Stuck, change)
+		}
+	}
+
+	// Open MRs the second snapshot no longer has. Closed ones that are gone
+	// were archived, which is routine.
+	for _, mr := range a.MRs {
+		if _, gone := before[mr.ID]; gone && !mr.State.Terminal() {
+			d.Removed = append(d.Removed, SnapshotChange{ID: mr.ID, Title: mr.Title, Worker: mr.Worker, Target: mr.Target, From: mr.State})
+		}
+	}
+	return d
+}
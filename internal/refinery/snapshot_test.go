@@ -0,0 +1,124 @@
+package refinery
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func snapshotMR(id string, state MRState) *beads.Issue {
+	status := string(state.Status())
+	return &beads.Issue{
+		ID:          id,
+		Title:       "Merge: " + id,
+		Status:      status,
+		Description: fmt.Sprintf("branch: polecat/%s\ntarget: main\nworker: toast\nstate: %s", id, state),
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	day := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+	a := NewQueueSnapshot("greenplace", []*beads.Issue{
+		snapshotMR("gp-1", StateQueued),   // merges
+		snapshotMR("gp-2", StateChecking), // fails
+		snapshotMR("gp-3", StateQueued),   // stuck
+		snapshotMR("gp-4", StateQueued),   // starts checking
+		snapshotMR("gp-5", StateMerged),   // merged long ago
+		snapshotMR("gp-6", StateQueued),   // deleted
+		snapshotMR("gp-7", StateMerged),   // archived
+		snapshotMR("gp-8", StateFailed),   // retried
+		snapshotMR("gp-9", StateQueued),   // cancelled
+		{ID: "gp-x", Title: "not an MR"},
+	}, day)
+	b := NewQueueSnapshot("greenplace", []*beads.Issue{
+		snapshotMR("gp-1", StateMerged),
+		snapshotMR("gp-2", StateFailed),
+		snapshotMR("gp-3", StateQueued),
+		snapshotMR("gp-4", StateChecking),
+		snapshotMR("gp-5", StateMerged),
+		snapshotMR("gp-8", StateQueued),
+		snapshotMR("gp-9", StateCancelled),
+		snapshotMR("gp-10", StateQueued),
+		snapshotMR("gp-11", StateMerged), // submitted and merged in between
+	}, day.Add(24*time.Hour))
+
+	if len(a.MRs) != 9 {
+		t.Fatalf("snapshot a has %d MRs, want 9 (non-MR beads skipped)", len(a.MRs))
+	}
+
+	d := DiffSnapshots(a, b)
+	ids := func(changes []SnapshotChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+	for name, tt := range map[string]struct {
+		got, want []string
+	}{
+		"merged":  {ids(d.Merged), []string{"gp-1", "gp-11"}},
+		"failed":  {ids(d.Failed), []string{"gp-2"}},
+		"closed":  {ids(d.Closed), []string{"gp-9"}},
+		"stuck":   {ids(d.Stuck), []string{"gp-3"}},
+		"moved":   {ids(d.Moved), []string{"gp-4", "gp-8"}},
+		"added":   {ids(d.Added), []string{"gp-10"}},
+		"removed": {ids(d.Removed), []string{"gp-6"}},
+	} {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", name, tt.got, tt.want)
+		}
+	}
+	if c := d.Merged[0]; c.From != StateQueued || c.To != StateMerged || c.Worker != "toast" {
+		t.Errorf("merged change = %+v", c)
+	}
+	if c := d.Merged[1]; c.From != "" {
+		t.Errorf("MR new in b should have no From state, got %q", c.From)
+	}
+}
+
+func TestSnapshotSaveAndFind(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mq-snapshots")
+	if _, err := FindSnapshot(dir, "latest"); err == nil {
+		t.Error("FindSnapshot with no snapshots should fail")
+	}
+
+	var paths []string
+	for _, at := range []time.Time{
+		time.Date(2026, 10, 13, 2, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 14, 14, 0, 0, 0, time.UTC),
+	} {
+		path, err := NewQueueSnapshot("greenplace", []*beads.Issue{snapshotMR("gp-1", StateQueued)}, at).Save(dir)
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	for name, want := range map[string]string{
+		"latest":     paths[2],
+		"previous":   paths[1],
+		"2026-10-13": paths[0],
+		"2026-10-14": paths[2], // latest that day
+	} {
+		if got, err := FindSnapshot(dir, name); err != nil || got != want {
+			t.Errorf("FindSnapshot(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := FindSnapshot(dir, "2026-10-15"); err == nil {
+		t.Error("FindSnapshot with no match should fail")
+	}
+
+	s, err := LoadQueueSnapshot(paths[0])
+	if err != nil {
+		t.Fatalf("LoadQueueSnapshot: %v", err)
+	}
+	if s.Rig != "greenplace" || len(s.MRs) != 1 || s.MRs[0].State != StateQueued || s.MRs[0].Branch != "polecat/gp-1" {
+		t.Errorf("loaded snapshot = %+v", s)
+	}
+}
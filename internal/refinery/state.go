@@ -0,0 +1,199 @@
+package refinery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// MRState is where a merge request is in its lifecycle. It is stored in the
+// MR bead's "state" field; the bead's status (open, in_progress, closed)
+// follows from it.
+type MRState string
+
+const (
+	// StateQueued means the MR is waiting for the refinery.
+	StateQueued MRState = "queued"
+
+	// StateChangesRequested means a reviewer sent the MR back to its worker
+	// with a follow-up task. The refinery skips it until the worker
+	// resubmits, which queues it again.
+	StateChangesRequested MRState = "changes_requested"
+
+	// StateRebasing means the refinery is rebasing the branch onto its target.
+	StateRebasing MRState = "rebasing"
+
+	// StateChecking means the refinery is running tests and other checks.
+	StateChecking MRState = "checking"
+
+	// StateMerging means checks passed and the refinery is merging and pushing.
+	StateMerging MRState = "merging"
+
+	// StateMerged means the MR landed on its target. Terminal.
+	StateMerged MRState = "merged"
+
+	// StateFailed means the MR can't be merged and won't be retried, e.g.
+	// conflicts nobody will resolve. Terminal.
+	StateFailed MRState = "failed"
+
+	// StateRejected means the MR was refused, by a person or by branch
+	// protection. Terminal.
+	StateRejected MRState = "rejected"
+
+	// StateCancelled means the MR was withdrawn or superseded. Terminal.
+	StateCancelled MRState = "cancelled"
+
+	// StateStale means the MR's branch is gone or it was abandoned. Terminal.
+	StateStale MRState = "stale"
+)
+
+// MRStates lists every state in lifecycle order.
+var MRStates = []MRState{
+	StateQueued, StateChangesRequested, StateRebasing, StateChecking, StateMerging,
+	StateMerged, StateFailed, StateRejected, StateCancelled, StateStale,
+}
+
+// ParseMRState parses a state name.
+func ParseMRState(s string) (MRState, error) {
+	for _, state := range MRStates {
+		if strings.EqualFold(s, string(state)) {
+			return state, nil
+		}
+	}
+	return "", fmt.Errorf("unknown MR state %q", s)
+}
+
+// Terminal reports whether the state closes the MR for good.
+func (s MRState) Terminal() bool {
+	switch s {
+	case StateMerged, StateFailed, StateRejected, StateCancelled, StateStale:
+		return true
+	}
+	return false
+}
+
+// Active reports whether the refinery is working on the MR.
+func (s MRState) Active() bool {
+	return s == StateRebasing || s == StateChecking || s == StateMerging
+}
+
+// Status returns the bead status an MR in this state has.
+func (s MRState) Status() MRStatus {
+	switch {
+	case s.Terminal():
+		return MRClosed
+	case s.Active():
+		return MRInProgress
+	default:
+		return MROpen
+	}
+}
+
+// ValidateStateTransition checks if an MR may move from one state to another.
+//
+// Valid transitions:
+//   - queued → rebasing, checking, merging (refinery claims the MR)
+//   - rebasing, checking, merging → each other (work progresses or restarts)
+//   - rebasing, checking, merging → queued (released, or sent back for rework)
+//   - rebasing, checking, merging → merged
+//   - queued, rebasing, checking, merging → changes_requested (sent back to the worker)
+//   - changes_requested → queued (the worker resubmitted)
+//   - any non-terminal state → failed, rejected, cancelled, stale
+//
+// Invalid:
+//   - queued → merged (the refinery must claim an MR to merge it)
+//   - changes_requested → rebasing, checking, merging (the worker must resubmit first)
+//   - terminal → anything (immutable once closed)
+func ValidateStateTransition(from, to MRState) error {
+	if from == to {
+		return nil
+	}
+	if from.Terminal() {
+		return fmt.Errorf("%w: MR is already %s", ErrClosedImmutable, from)
+	}
+	if from == StateChangesRequested && to != StateQueued && !to.Terminal() {
+		return fmt.Errorf("%w: %s → %s is not allowed (the worker must resubmit first)", ErrInvalidTransition, from, to)
+	}
+	if to == StateMerged && !from.Active() {
+		return fmt.Errorf("%w: %s → %s is not allowed (claim the MR first)", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// StateOf returns an MR bead's state. MRs from before states were recorded
+// have theirs derived from the bead's status and close_reason.
+func StateOf(issue *beads.Issue) MRState {
+	fields := beads.ParseMRFields(issue)
+	if fields != nil && fields.State != "" {
+		if state, err := ParseMRState(fields.State); err == nil {
+			return state
+		}
+	}
+
+	switch issue.Status {
+	case string(MRInProgress):
+		return StateMerging
+	case string(MRClosed):
+		if fields == nil {
+			return StateCancelled
+		}
+		switch reason := CloseReason(fields.CloseReason); {
+		case reason == CloseReasonMerged || fields.MergeCommit != "":
+			return StateMerged
+		case reason == CloseReasonConflict:
+			return StateFailed
+		case strings.HasPrefix(string(reason), string(CloseReasonRejected)):
+			return StateRejected
+		default:
+			// Superseded, or closed with no recorded reason
+			return StateCancelled
+		}
+	}
+	return StateQueued
+}
+
+// RecordState moves an MR bead to a new state after validating the
+// transition. The state is written to the MR's fields and its status updated
+// to match: queued MRs, and those sent back for changes, are released
+// (unassigned) and terminal states close the bead with reason as
+// "<state>: <reason>". Returns the previous state.
+func RecordState(b *beads.Beads, issue *beads.Issue, to MRState, reason string) (MRState, error) {
+	from := StateOf(issue)
+	if err := ValidateStateTransition(from, to); err != nil {
+		return from, err
+	}
+
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	fields.State = string(to)
+	if to.Terminal() {
+		fields.CloseReason = string(to)
+	}
+	desc := beads.SetMRFields(issue, fields)
+	opts := beads.UpdateOptions{Description: &desc}
+	if !to.Terminal() {
+		status := string(to.Status())
+		opts.Status = &status
+	}
+	if to == StateQueued || to == StateChangesRequested {
+		empty := ""
+		opts.Assignee = &empty
+	}
+	if err := b.Update(issue.ID, opts); err != nil {
+		return from, fmt.Errorf("updating MR %s: %w", issue.ID, err)
+	}
+
+	if to.Terminal() && issue.Status != string(MRClosed) {
+		closeReason := string(to)
+		if reason != "" {
+			closeReason += ": " + reason
+		}
+		if err := b.CloseWithReason(closeReason, issue.ID); err != nil {
+			return from, fmt.Errorf("closing MR %s: %w", issue.ID, err)
+		}
+	}
+	return from, nil
+}
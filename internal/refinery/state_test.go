@@ -0,0 +1,105 @@
+package refinery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseMRState(t *testing.T) {
+	for _, s := range MRStates {
+		got, err := ParseMRState(string(s))
+		if err != nil || got != s {
+			t.Errorf("ParseMRState(%q) = %q, %v", s, got, err)
+		}
+	}
+	if got, _ := ParseMRState("Merged"); got != StateMerged {
+		t.Errorf("ParseMRState should ignore case, got %q", got)
+	}
+	if _, err := ParseMRState("closed"); err == nil {
+		t.Error("ParseMRState(closed) should fail: closed is a status, not a state")
+	}
+}
+
+func TestMRStateStatus(t *testing.T) {
+	tests := map[MRState]MRStatus{
+		StateQueued:           MROpen,
+		StateChangesRequested: MROpen,
+		StateRebasing:         MRInProgress,
+		StateChecking:         MRInProgress,
+		StateMerging:          MRInProgress,
+		StateMerged:           MRClosed,
+		StateFailed:           MRClosed,
+		StateRejected:         MRClosed,
+		StateCancelled:        MRClosed,
+		StateStale:            MRClosed,
+	}
+	for state, want := range tests {
+		if got := state.Status(); got != want {
+			t.Errorf("%s.Status() = %s, want %s", state, got, want)
+		}
+	}
+}
+
+func TestValidateStateTransition(t *testing.T) {
+	tests := []struct {
+		from, to MRState
+		wantErr  error
+	}{
+		{StateQueued, StateRebasing, nil},
+		{StateRebasing, StateChecking, nil},
+		{StateChecking, StateMerging, nil},
+		{StateMerging, StateMerged, nil},
+		{StateChecking, StateQueued, nil},
+		{StateQueued, StateStale, nil},
+		{StateMerging, StateFailed, nil},
+		{StateMerged, StateMerged, nil},
+		{StateQueued, StateChangesRequested, nil},
+		{StateChecking, StateChangesRequested, nil},
+		{StateChangesRequested, StateQueued, nil},
+		{StateChangesRequested, StateRejected, nil},
+		{StateQueued, StateMerged, ErrInvalidTransition},
+		{StateChangesRequested, StateRebasing, ErrInvalidTransition},
+		{StateChangesRequested, StateMerged, ErrInvalidTransition},
+		{StateMerged, StateQueued, ErrClosedImmutable},
+		{StateRejected, StateMerging, ErrClosedImmutable},
+		{StateStale, StateCancelled, ErrClosedImmutable},
+	}
+	for _, tt := range tests {
+		err := ValidateStateTransition(tt.from, tt.to)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("%s → %s: unexpected error %v", tt.from, tt.to, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s → %s: err = %v, want %v", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStateOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		desc   string
+		want   MRState
+	}{
+		{"recorded state", "in_progress", "branch: b\nstate: checking", StateChecking},
+		{"open legacy MR", "open", "branch: b", StateQueued},
+		{"in progress legacy MR", "in_progress", "branch: b", StateMerging},
+		{"merged legacy MR", "closed", "branch: b\nclose_reason: merged", StateMerged},
+		{"merge commit without reason", "closed", "branch: b\nmerge_commit: abc123", StateMerged},
+		{"conflict legacy MR", "closed", "branch: b\nclose_reason: conflict", StateFailed},
+		{"rejected legacy MR", "closed", "branch: b\nclose_reason: rejected: too big", StateRejected},
+		{"superseded legacy MR", "closed", "branch: b\nclose_reason: superseded", StateCancelled},
+		{"unknown state falls back", "open", "branch: b\nstate: pondering", StateQueued},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &beads.Issue{Status: tt.status, Description: tt.desc}
+			if got := StateOf(issue); got != tt.want {
+				t.Errorf("StateOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Targets are the branches a rig's merge queue merges into: the rig's
+// default branch, plus any extra targets under merge_queue.targets (e.g.,
+// release branches). Each target has its own sub-queue.
+type Targets struct {
+	defaultBranch string
+	extra         []string
+	backport      map[string]bool
+}
+
+// NewTargets builds Targets from the rig's default branch and its
+// configured extra targets.
+func NewTargets(defaultBranch string, cfg []config.MergeTargetConfig) (*Targets, error) {
+	t := &Targets{defaultBranch: defaultBranch, backport: make(map[string]bool)}
+	seen := map[string]bool{defaultBranch: true}
+	for _, target := range cfg {
+		branch := strings.TrimSpace(target.Branch)
+		if branch == "" {
+			return nil, fmt.Errorf("merge target with no branch")
+		}
+		if seen[branch] {
+			return nil, fmt.Errorf("duplicate merge target %q", branch)
+		}
+		seen[branch] = true
+		t.extra = append(t.extra, branch)
+		if target.Backport {
+			t.backport[branch] = true
+		}
+	}
+	return t, nil
+}
+
+// LoadTargets reads a rig's merge targets from its settings/config.json.
+// A missing settings file or targets section yields just the default branch.
+func LoadTargets(rigPath, defaultBranch string) (*Targets, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return NewTargets(defaultBranch, nil)
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return NewTargets(defaultBranch, nil)
+	}
+	return NewTargets(defaultBranch, settings.MergeQueue.Targets)
+}
+
+// Default returns the rig's default branch.
+func (t *Targets) Default() string {
+	return t.defaultBranch
+}
+
+// Branches returns the targets, default branch first.
+func (t *Targets) Branches() []string {
+	return append([]string{t.defaultBranch}, t.extra...)
+}
+
+// Check returns an error unless branch is one of the targets.
+func (t *Targets) Check(branch string) error {
+	for _, b := range t.Branches() {
+		if b == branch {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a merge target (targets: %s)", branch, strings.Join(t.Branches(), ", "))
+}
+
+// BackportTargets returns the targets an MR merged into target should be
+// cherry-picked onto. Only merges into the default branch are backported.
+func (t *Targets) BackportTargets(target string) []string {
+	if target != t.defaultBranch {
+		return nil
+	}
+	var targets []string
+	for _, b := range t.extra {
+		if t.backport[b] {
+			targets = append(targets, b)
+		}
+	}
+	return targets
+}
+
+// BackportBranch names the branch carrying the backport of MR mrID onto
+// target, e.g. "backport/release/1.2/gt-mr-abc".
+func BackportBranch(target, mrID string) string {
+	return "backport/" + target + "/" + mrID
+}
+
+// SubQueue is the part of the merge queue waiting to merge into one target:
+// all of it, or a project's part when the project has its own queue.
+type SubQueue[T any] struct {
+	Target  string
+	Project string // "" = the target's main sub-queue
+	Items   []T
+}
+
+// Name labels the sub-queue, e.g. "main" or "main (docs)".
+func (q SubQueue[T]) Name() string {
+	if q.Project == "" {
+		return q.Target
+	}
+	return q.Target + " (" + q.Project + ")"
+}
+
+// SplitByTarget splits items into per-target sub-queues, keeping their
+// order within each. Sub-queues come in target order (default branch
+// first), then other branches (e.g., integration branches) by name. Items
+// with no target belong to the default branch. Empty sub-queues are omitted.
+func SplitByTarget[T any](t *Targets, items []T, target func(T) string) []SubQueue[T] {
+	byTarget := make(map[string][]T)
+	for _, item := range items {
+		branch := target(item)
+		if branch == "" {
+			branch = t.defaultBranch
+		}
+		byTarget[branch] = append(byTarget[branch], item)
+	}
+
+	var queues []SubQueue[T]
+	for _, branch := range t.Branches() {
+		if items, ok := byTarget[branch]; ok {
+			queues = append(queues, SubQueue[T]{Target: branch, Items: items})
+			delete(byTarget, branch)
+		}
+	}
+	others := make([]string, 0, len(byTarget))
+	for branch := range byTarget {
+		others = append(others, branch)
+	}
+	sort.Strings(others)
+	for _, branch := range others {
+		queues = append(queues, SubQueue[T]{Target: branch, Items: byTarget[branch]})
+	}
+	return queues
+}
@@ -0,0 +1,77 @@
+package refinery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewTargets(t *testing.T) {
+	for _, cfg := range [][]config.MergeTargetConfig{
+		{{Branch: ""}},
+		{{Branch: "main"}},
+		{{Branch: "release/1.2"}, {Branch: "release/1.2"}},
+	} {
+		if _, err := NewTargets("main", cfg); err == nil {
+			t.Errorf("NewTargets(%+v) should fail", cfg)
+		}
+	}
+
+	targets, err := NewTargets("main", []config.MergeTargetConfig{
+		{Branch: "release/1.2", Backport: true},
+		{Branch: "release/1.1"},
+	})
+	if err != nil {
+		t.Fatalf("NewTargets: %v", err)
+	}
+	if got, want := targets.Branches(), []string{"main", "release/1.2", "release/1.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Branches() = %v, want %v", got, want)
+	}
+	if err := targets.Check("release/1.1"); err != nil {
+		t.Errorf("Check(release/1.1) = %v", err)
+	}
+	if err := targets.Check("release/0.9"); err == nil || !strings.Contains(err.Error(), "main, release/1.2, release/1.1") {
+		t.Errorf("Check(release/0.9) = %v, want error listing targets", err)
+	}
+	if got, want := targets.BackportTargets("main"), []string{"release/1.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BackportTargets(main) = %v, want %v", got, want)
+	}
+	if got := targets.BackportTargets("release/1.2"); got != nil {
+		t.Errorf("BackportTargets(release/1.2) = %v, want none", got)
+	}
+}
+
+func TestSplitByTarget(t *testing.T) {
+	targets, err := NewTargets("main", []config.MergeTargetConfig{{Branch: "release/1.2"}})
+	if err != nil {
+		t.Fatalf("NewTargets: %v", err)
+	}
+	mrs := []*MRInfo{
+		{ID: "a", Target: "integration/gt-epic"},
+		{ID: "b", Target: "release/1.2"},
+		{ID: "c", Target: "main"},
+		{ID: "d"},
+		{ID: "e", Target: "release/1.2"},
+	}
+
+	var got []string
+	for _, q := range SplitByTarget(targets, mrs, func(mr *MRInfo) string { return mr.Target }) {
+		var ids []string
+		for _, mr := range q.Items {
+			ids = append(ids, mr.ID)
+		}
+		got = append(got, q.Target+"="+strings.Join(ids, ","))
+	}
+	want := []string{"main=c,d", "release/1.2=b,e", "integration/gt-epic=a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitByTarget = %v, want %v", got, want)
+	}
+}
+
+func TestBackportBranch(t *testing.T) {
+	if got := BackportBranch("release/1.2", "gt-mr-abc"); got != "backport/release/1.2/gt-mr-abc" {
+		t.Errorf("BackportBranch = %q", got)
+	}
+}
@@ -0,0 +1,314 @@
+package refinery
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// MaxFailingTests caps the failing test names kept on an MR bead; the
+// failed count still covers them all.
+const MaxFailingTests = 20
+
+// TestResults summarizes a test run, parsed from its output. Counts are
+// only as complete as the output: go test reports passing tests with -v.
+type TestResults struct {
+	Suite    string        `json:"suite,omitempty"` // Recognized runner: go, pytest, cargo, jest
+	OK       bool          `json:"ok"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Duration time.Duration `json:"duration"`
+	Failing  []string      `json:"failing,omitempty"`
+	Attempts int           `json:"attempts,omitempty"` // Runs it took, with retries
+	Flaky    bool          `json:"flaky,omitempty"`    // Passed after a retry
+}
+
+var (
+	goTestRe    = regexp.MustCompile(`^(\s*)--- (PASS|FAIL|SKIP): (\S+)`)
+	goPackageRe = regexp.MustCompile(`^(ok|FAIL) *\t(\S+)(.*)$`) // Tab-separated, unlike jest's "FAIL file"
+
+	pytestSummaryRe = regexp.MustCompile(`^=+ (.*\d+ (?:passed|failed|skipped|errors?).*) in [\d.]+s`)
+	pytestCountRe   = regexp.MustCompile(`(\d+) (passed|failed|skipped|errors?)`)
+	pytestFailRe    = regexp.MustCompile(`^(?:FAILED|ERROR) (\S+)`)
+
+	cargoResultRe = regexp.MustCompile(`^test result: \w+\. (\d+) passed; (\d+) failed; (\d+) ignored`)
+	cargoFailRe   = regexp.MustCompile(`^test (\S+) \.\.\. FAILED`)
+
+	jestSummaryRe = regexp.MustCompile(`^Tests:\s+(.*\d+ total)`)
+	jestCountRe   = regexp.MustCompile(`(\d+) (passed|failed|skipped)`)
+	jestFailRe    = regexp.MustCompile(`^\s+● (.+)$`)
+)
+
+// ParseTestOutput reads counts and failing test names out of a test run's
+// output, recognizing go test, pytest, cargo test and jest. ok is whether
+// the command succeeded; duration is how long it took. Output from other
+// runners yields just those, with no suite.
+func ParseTestOutput(output string, ok bool, duration time.Duration) *TestResults {
+	lines := strings.Split(output, "\n")
+	r := &TestResults{OK: ok, Duration: duration}
+	for _, parse := range []func([]string, *TestResults) bool{parseGoTest, parsePytest, parseCargoTest, parseJest} {
+		if parse(lines, r) {
+			break
+		}
+		*r = TestResults{OK: ok, Duration: duration}
+	}
+	if len(r.Failing) > MaxFailingTests {
+		r.Failing = r.Failing[:MaxFailingTests]
+	}
+	return r
+}
+
+// parseGoTest counts top-level tests; failing tests are named
+// <package>.<test> once their package's result line is seen.
+func parseGoTest(lines []string, r *TestResults) bool {
+	seen := false
+	var pending []string
+	for _, line := range lines {
+		if m := goTestRe.FindStringSubmatch(line); m != nil {
+			seen = true
+			if m[1] != "" {
+				continue // Subtest; its parent is counted
+			}
+			switch m[2] {
+			case "PASS":
+				r.Passed++
+			case "FAIL":
+				r.Failed++
+				pending = append(pending, m[3])
+			case "SKIP":
+				r.Skipped++
+			}
+			continue
+		}
+		if m := goPackageRe.FindStringSubmatch(line); m != nil {
+			seen = true
+			pkg := m[2]
+			if m[1] == "FAIL" && len(pending) == 0 {
+				// Build failure or panic: no test result lines
+				name := pkg
+				if i := strings.Index(m[3], "["); i >= 0 {
+					name += " " + strings.TrimSpace(m[3][i:]) // e.g. "[build failed]"
+				}
+				r.Failed++
+				r.Failing = append(r.Failing, name)
+				continue
+			}
+			for _, name := range pending {
+				r.Failing = append(r.Failing, pkg+"."+name)
+			}
+			pending = nil
+		}
+	}
+	r.Failing = append(r.Failing, pending...)
+	if seen {
+		r.Suite = "go"
+	}
+	return seen
+}
+
+func parsePytest(lines []string, r *TestResults) bool {
+	seen := false
+	for _, line := range lines {
+		if m := pytestSummaryRe.FindStringSubmatch(line); m != nil {
+			seen = true
+			for _, c := range pytestCountRe.FindAllStringSubmatch(m[1], -1) {
+				n, _ := strconv.Atoi(c[1])
+				switch c[2] {
+				case "passed":
+					r.Passed += n
+				case "skipped":
+					r.Skipped += n
+				default:
+					r.Failed += n
+				}
+			}
+		} else if m := pytestFailRe.FindStringSubmatch(line); m != nil {
+			r.Failing = append(r.Failing, m[1])
+		}
+	}
+	if seen {
+		r.Suite = "pytest"
+	}
+	return seen
+}
+
+func parseCargoTest(lines []string, r *TestResults) bool {
+	seen := false
+	for _, line := range lines {
+		if m := cargoResultRe.FindStringSubmatch(line); m != nil {
+			seen = true
+			passed, _ := strconv.Atoi(m[1])
+			failed, _ := strconv.Atoi(m[2])
+			ignored, _ := strconv.Atoi(m[3])
+			r.Passed += passed
+			r.Failed += failed
+			r.Skipped += ignored
+		} else if m := cargoFailRe.FindStringSubmatch(line); m != nil {
+			r.Failing = append(r.Failing, m[1])
+		}
+	}
+	if seen {
+		r.Suite = "cargo"
+	}
+	return seen
+}
+
+func parseJest(lines []string, r *TestResults) bool {
+	seen := false
+	for _, line := range lines {
+		if m := jestSummaryRe.FindStringSubmatch(line); m != nil {
+			seen = true
+			for _, c := range jestCountRe.FindAllStringSubmatch(m[1], -1) {
+				n, _ := strconv.Atoi(c[1])
+				switch c[2] {
+				case "passed":
+					r.Passed += n
+				case "failed":
+					r.Failed += n
+				case "skipped":
+					r.Skipped += n
+				}
+			}
+		} else if m := jestFailRe.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[1], "Test suite failed") {
+			r.Failing = append(r.Failing, strings.TrimSpace(m[1]))
+		}
+	}
+	if seen {
+		r.Suite = "jest"
+	}
+	return seen
+}
+
+// Summary formats the results for an MR's test_results field, e.g.
+// "failed suite=go passed=41 failed=2 skipped=1 duration=12.5s".
+func (r *TestResults) Summary() string {
+	parts := []string{"ok"}
+	if !r.OK {
+		parts[0] = "failed"
+	}
+	if r.Suite != "" {
+		parts = append(parts, "suite="+r.Suite,
+			fmt.Sprintf("passed=%d", r.Passed),
+			fmt.Sprintf("failed=%d", r.Failed),
+			fmt.Sprintf("skipped=%d", r.Skipped))
+	}
+	parts = append(parts, "duration="+r.Duration.Round(100*time.Millisecond).String())
+	if r.Attempts > 1 {
+		parts = append(parts, fmt.Sprintf("attempts=%d", r.Attempts))
+	}
+	if r.Flaky {
+		parts = append(parts, "flaky")
+	}
+	return strings.Join(parts, " ")
+}
+
+// String describes the results for people, e.g.
+// "41 passed, 2 failed, 1 skipped (go, 12.5s)".
+func (r *TestResults) String() string {
+	took := r.Duration.Round(100 * time.Millisecond).String()
+	var desc string
+	switch {
+	case r.Suite != "":
+		desc = fmt.Sprintf("%d passed, %d failed", r.Passed, r.Failed)
+		if r.Skipped > 0 {
+			desc += fmt.Sprintf(", %d skipped", r.Skipped)
+		}
+		desc += fmt.Sprintf(" (%s, %s)", r.Suite, took)
+	case r.OK:
+		desc = "passed (" + took + ")"
+	default:
+		desc = "failed (" + took + ")"
+	}
+	if r.Flaky {
+		desc += ", passed after retry (flaky)"
+	}
+	return desc
+}
+
+// Apply records the results of a run of check in MR fields, replacing any
+// earlier run's. A flaky pass is noted in the MR's flaky_checks. The check
+// is added to checks_passed if the run passed and removed if it failed:
+// only runs like this one satisfy branch protection's required_checks. An
+// empty check records the results alone.
+func (r *TestResults) Apply(fields *beads.MRFields, check string) {
+	if check != "" {
+		if flaky := SplitMRList(fields.FlakyChecks); r.Flaky && !slices.Contains(flaky, check) {
+			fields.FlakyChecks = strings.Join(append(flaky, check), ",")
+		}
+		passed := slices.DeleteFunc(SplitMRList(fields.ChecksPassed), func(c string) bool { return c == check })
+		if r.OK {
+			passed = append(passed, check)
+		}
+		fields.ChecksPassed = strings.Join(passed, ",")
+	}
+	fields.TestResults = r.Summary()
+	names := make([]string, len(r.Failing))
+	for i, name := range r.Failing {
+		names[i] = strings.ReplaceAll(name, ",", ";") // Keep the list splittable
+	}
+	fields.FailingTests = strings.Join(names, ",")
+}
+
+// RecordTestResults stores the results of a run of check on an MR bead,
+// for gt mq status, and updates issue's description to match. A nil run
+// is a no-op.
+func RecordTestResults(b *beads.Beads, issue *beads.Issue, check string, tests *TestResults) error {
+	return RecordCheckRun(b, issue, check, ProcessResult{Tests: tests})
+}
+
+// RecordCheckRun is RecordTestResults for a merge attempt's run of check:
+// it also stores where the run's artifacts were kept, the coverage delta
+// and the lint findings. A result with none of these is a no-op.
+func RecordCheckRun(b *beads.Beads, issue *beads.Issue, check string, result ProcessResult) error {
+	if result.Tests == nil && result.Artifacts == "" && result.Coverage == nil && result.Lint == nil {
+		return nil
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	result.Apply(fields, check)
+	desc := beads.SetMRFields(issue, fields)
+	if err := b.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording test results on MR %s: %w", issue.ID, err)
+	}
+	issue.Description = desc
+	return nil
+}
+
+// TestResultsFromFields reads the results recorded on an MR, or nil if
+// none are.
+func TestResultsFromFields(fields *beads.MRFields) *TestResults {
+	if fields == nil || fields.TestResults == "" {
+		return nil
+	}
+	words := strings.Fields(fields.TestResults)
+	r := &TestResults{OK: words[0] == "ok", Failing: SplitMRList(fields.FailingTests)}
+	for _, word := range words[1:] {
+		key, value, _ := strings.Cut(word, "=")
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "suite":
+			r.Suite = value
+		case "passed":
+			r.Passed = n
+		case "failed":
+			r.Failed = n
+		case "skipped":
+			r.Skipped = n
+		case "duration":
+			r.Duration, _ = time.ParseDuration(value)
+		case "attempts":
+			r.Attempts = n
+		case "flaky":
+			r.Flaky = true
+		}
+	}
+	return r
+}
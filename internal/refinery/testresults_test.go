@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseTestOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    TestResults
+		failing string
+	}{
+		{
+			name: "go test -v",
+			output: `=== RUN   TestLogin
+--- PASS: TestLogin (0.01s)
+=== RUN   TestRedirect
+=== RUN   TestRedirect/loop
+    redirect_test.go:12: too many redirects
+    --- FAIL: TestRedirect/loop (0.00s)
+--- FAIL: TestRedirect (0.00s)
+--- SKIP: TestSSO (0.00s)
+FAIL
+FAIL	example.com/app/auth	0.120s
+ok  	example.com/app/store	0.051s
+FAIL	example.com/app/web [build failed]
+FAIL
+`,
+			want:    TestResults{Suite: "go", Passed: 1, Failed: 2, Skipped: 1},
+			failing: "example.com/app/auth.TestRedirect|example.com/app/web [build failed]",
+		},
+		{
+			name: "pytest",
+			output: `tests/test_auth.py ..F.s
+=========================== short test summary info ============================
+FAILED tests/test_auth.py::test_redirect - AssertionError: loop
+ERROR tests/test_db.py::test_conn - ConnectionError
+============= 1 failed, 3 passed, 1 skipped, 1 error in 2.31s ==============
+`,
+			want:    TestResults{Suite: "pytest", Passed: 3, Failed: 2, Skipped: 1},
+			failing: "tests/test_auth.py::test_redirect|tests/test_db.py::test_conn",
+		},
+		{
+			name: "cargo test",
+			output: `running 3 tests
+test auth::login ... ok
+test auth::redirect ... FAILED
+test auth::sso ... ignored
+
+test result: FAILED. 1 passed; 1 failed; 1 ignored; 0 measured; 0 filtered out
+
+running 2 tests
+test result: ok. 2 passed; 0 failed; 0 ignored; 0 measured; 0 filtered out
+`,
+			want:    TestResults{Suite: "cargo", Passed: 3, Failed: 1, Skipped: 1},
+			failing: "auth::redirect",
+		},
+		{
+			name: "jest",
+			output: `FAIL src/auth.test.js
+  ● auth › redirects once
+
+    expect(received).toBe(expected)
+
+Test Suites: 1 failed, 1 total
+Tests:       1 failed, 1 skipped, 4 passed, 6 total
+`,
+			want:    TestResults{Suite: "jest", Passed: 4, Failed: 1, Skipped: 1},
+			failing: "auth › redirects once",
+		},
+		{
+			name:   "unrecognized",
+			output: "make: *** [check] Error 2\n",
+			want:   TestResults{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTestOutput(tt.output, false, time.Second)
+			if got.Suite != tt.want.Suite || got.Passed != tt.want.Passed || got.Failed != tt.want.Failed || got.Skipped != tt.want.Skipped {
+				t.Errorf("counts = %s %d/%d/%d, want %s %d/%d/%d", got.Suite, got.Passed, got.Failed, got.Skipped,
+					tt.want.Suite, tt.want.Passed, tt.want.Failed, tt.want.Skipped)
+			}
+			if strings.Join(got.Failing, "|") != tt.failing {
+				t.Errorf("failing = %q, want %q", strings.Join(got.Failing, "|"), tt.failing)
+			}
+			if got.OK || got.Duration != time.Second {
+				t.Errorf("ok, duration = %v, %v; want false, 1s", got.OK, got.Duration)
+			}
+		})
+	}
+}
+
+func TestParseTestOutputCapsFailing(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < MaxFailingTests+5; i++ {
+		b.WriteString("--- FAIL: TestX" + strings.Repeat("x", i) + " (0.00s)\n")
+	}
+	got := ParseTestOutput(b.String(), false, 0)
+	if got.Failed != MaxFailingTests+5 || len(got.Failing) != MaxFailingTests {
+		t.Errorf("failed %d, %d names; want %d, %d", got.Failed, len(got.Failing), MaxFailingTests+5, MaxFailingTests)
+	}
+}
+
+func TestTestResultsFields(t *testing.T) {
+	if TestResultsFromFields(&beads.MRFields{}) != nil {
+		t.Error("no test_results field should read as nil")
+	}
+
+	in := &TestResults{Suite: "pytest", Passed: 3, Failed: 2, Duration: 2310 * time.Millisecond,
+		Failing: []string{"tests/test_auth.py::test_redirect[a,b]", "tests/test_db.py::test_conn"}}
+	fields := &beads.MRFields{FailingTests: "stale"}
+	in.Apply(fields, CheckTests)
+	if fields.TestResults != "failed suite=pytest passed=3 failed=2 skipped=0 duration=2.3s" {
+		t.Errorf("test_results = %q", fields.TestResults)
+	}
+
+	// Round trip through the bead description
+	desc := beads.FormatMRFields(fields)
+	out := TestResultsFromFields(beads.ParseMRFields(&beads.Issue{Description: desc}))
+	if out.OK || out.Suite != "pytest" || out.Passed != 3 || out.Failed != 2 || out.Duration != 2300*time.Millisecond {
+		t.Errorf("round trip = %+v", out)
+	}
+	if strings.Join(out.Failing, "|") != "tests/test_auth.py::test_redirect[a;b]|tests/test_db.py::test_conn" {
+		t.Errorf("failing = %q", out.Failing)
+	}
+	if out.String() != "3 passed, 2 failed (pytest, 2.3s)" {
+		t.Errorf("String() = %q", out.String())
+	}
+
+	// A passing run clears the failing list
+	(&TestResults{OK: true, Duration: time.Second}).Apply(fields, CheckTests)
+	if fields.TestResults != "ok duration=1s" || fields.FailingTests != "" {
+		t.Errorf("after passing run: %q, %q", fields.TestResults, fields.FailingTests)
+	}
+}
+
+func TestTestResultsApplyChecksPassed(t *testing.T) {
+	fields := &beads.MRFields{ChecksPassed: "lint"}
+	(&TestResults{OK: true}).Apply(fields, CheckTests)
+	if fields.ChecksPassed != "lint,tests" {
+		t.Errorf("after passing run, checks_passed = %q", fields.ChecksPassed)
+	}
+	(&TestResults{OK: true}).Apply(fields, CheckTests)
+	if fields.ChecksPassed != "lint,tests" {
+		t.Errorf("after second passing run, checks_passed = %q", fields.ChecksPassed)
+	}
+	(&TestResults{}).Apply(fields, CheckTests)
+	if fields.ChecksPassed != "lint" {
+		t.Errorf("after failing run, checks_passed = %q", fields.ChecksPassed)
+	}
+	(&TestResults{OK: true}).Apply(fields, "")
+	if fields.ChecksPassed != "lint" {
+		t.Errorf("a run without a check name changed checks_passed to %q", fields.ChecksPassed)
+	}
+}
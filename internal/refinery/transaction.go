@@ -0,0 +1,282 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// StagingRefPrefix is where transactional merges are staged while they are
+// verified. Staging refs are never pushed.
+const StagingRefPrefix = "refs/gastown/staging/"
+
+// verifyOutputLines is how much of a failed verification's output is kept
+// in the failure reason.
+const verifyOutputLines = 20
+
+// Transaction holds a rig's transactional merge settings, based on its
+// merge_queue.transactional settings. A nil *Transaction lands merges
+// without verifying them first.
+type Transaction struct {
+	verifyCommand string
+	timeout       time.Duration
+}
+
+// NewTransaction builds transactional merge settings from config.
+// testCommand is the merge queue's test command, used when cfg names no
+// verify command. Returns nil if cfg is nil.
+func NewTransaction(cfg *config.TransactionalMergeConfig, testCommand string) (*Transaction, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	t := &Transaction{verifyCommand: cfg.VerifyCommand}
+	if t.verifyCommand == "" {
+		t.verifyCommand = testCommand
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid transactional merge timeout %q", cfg.Timeout)
+		}
+		t.timeout = timeout
+	}
+	return t, nil
+}
+
+// LoadTransaction reads transactional merge settings from a rig's
+// settings/config.json. A missing settings file or transactional section
+// yields nil (merges land directly).
+func LoadTransaction(rigPath string) (*Transaction, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.MergeQueue == nil {
+		return nil, nil
+	}
+	return NewTransaction(settings.MergeQueue.Transactional, settings.MergeQueue.TestCommand)
+}
+
+// VerifyCommand returns the command merged results must pass before they
+// land, or "" if they land unverified.
+func (t *Transaction) VerifyCommand() string {
+	if t == nil {
+		return ""
+	}
+	return t.verifyCommand
+}
+
+// StagingRef returns the ref an MR's merged result is staged on.
+func StagingRef(mrID string) string {
+	return StagingRefPrefix + mrID
+}
+
+// Land pushes rev, the merged result of an MR, to origin's target branch.
+// With transactional merges the merge is staged on StagingRef(mrID) and
+// verified in a temporary worktree first; origin only moves if verification
+// passes, and only by fast-forward. With signing, the merge commit is
+// replaced by a signed copy first (and the local target moved to it); if it
+// can't be signed, nothing lands. The rig's linters and coverage gate must
+// pass too.
+//
+// If anything fails nothing lands: origin is untouched, and if the
+// refinery's local target was advanced to rev (e.g., by git merge --ff-only)
+// it is reset to origin's, discarding the merge. The result says why:
+// TestsFailed for a failed verification, lint or coverage gate, otherwise Error
+// alone (e.g., the target moved and the MR needs rebasing again).
+//
+// A merge begun with BeginLanding has its steps journaled; the caller
+// clears the journal once the outcome is recorded on the MR.
+func (e *Engineer) Land(ctx context.Context, t *Transaction, s *Signing, mrID, rev, target string) ProcessResult {
+	if err := e.git.FetchBranch(e.remote, target); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("fetching %s/%s: %v", e.remote, target, err)}
+	}
+	commit, err := e.git.Rev(rev)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("resolving %s: %v", rev, err)}
+	}
+	e.journal(mrID, StepMerged, func(j *JournalEntry) { j.MergeCommit = commit })
+
+	base := e.remote + "/" + target
+	if ok, err := e.git.IsAncestor(base, commit); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("comparing %s with %s: %v", rev, base, err)}
+	} else if !ok {
+		e.rollbackTarget(target, commit)
+		return ProcessResult{Error: fmt.Sprintf("%s has moved since the merge; rebase and merge again", base)}
+	}
+
+	if s != nil {
+		signed, err := e.signCommit(s, commit)
+		if err != nil {
+			e.rollbackTarget(target, commit)
+			return ProcessResult{Error: fmt.Sprintf("merge commit must be signed: %v", err)}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Signed %s as %s (%s)\n", shortCommit(commit), shortCommit(signed), s.Format())
+		e.moveTarget(target, commit, signed)
+		commit = signed
+		e.journal(mrID, StepMerged, func(j *JournalEntry) { j.MergeCommit = signed })
+	}
+
+	var tests *TestResults
+	var artifacts string
+	if cmd := t.VerifyCommand(); cmd != "" {
+		ref := StagingRef(mrID)
+		if err := e.git.UpdateRef(ref, commit); err != nil {
+			return ProcessResult{Error: fmt.Sprintf("staging merge on %s: %v", ref, err)}
+		}
+		defer func() { _ = e.git.DeleteRef(ref) }()
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Verifying %s on %s: %s\n", shortCommit(commit), ref, cmd)
+		result := e.verifyStaged(ctx, t, mrID, ref)
+		if !result.Success {
+			e.rollbackTarget(target, commit)
+			return result
+		}
+		tests, artifacts = result.Tests, result.Artifacts
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Verification passed: %s\n", tests)
+	}
+
+	baseCommit, err := e.git.Rev(base)
+	if err != nil {
+		e.rollbackTarget(target, commit)
+		return ProcessResult{Error: fmt.Sprintf("resolving %s: %v", base, err)}
+	}
+	gates := e.checkMergedCommit(ctx, mrID, commit, baseCommit)
+	if artifacts == "" {
+		artifacts = gates.Artifacts
+	}
+	if !gates.Success {
+		e.rollbackTarget(target, commit)
+		gates.Tests, gates.Artifacts = tests, artifacts
+		return gates
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Fast-forwarding %s/%s to %s...\n", e.remote, target, shortCommit(commit))
+	if err := e.git.Push(e.remote, commit+":refs/heads/"+target, false); err != nil {
+		e.rollbackTarget(target, commit)
+		return ProcessResult{Error: fmt.Sprintf("pushing to %s/%s (did it move?): %v", e.remote, target, err)}
+	}
+	e.journal(mrID, StepPushed, nil)
+	e.fastForwardTarget(target, commit)
+	e.mirrorTarget(target, commit)
+
+	return ProcessResult{
+		Success:     true,
+		MergeCommit: commit,
+		Tests:       tests,
+		Artifacts:   artifacts,
+		Coverage:    gates.Coverage,
+		Lint:        gates.Lint,
+		Signed:      s.Format(),
+	}
+}
+
+// verifyStaged runs the verify command in a temporary worktree of the
+// staging ref, retrying a failure as the rig's flake history allows.
+func (e *Engineer) verifyStaged(ctx context.Context, t *Transaction, mrID, ref string) ProcessResult {
+	tmp, err := os.MkdirTemp("", mergeWorktreePrefix)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("creating worktree dir: %v", err)}
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "wt")
+	if err := e.git.WorktreeAddDetached(path, ref); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("checking out %s: %v", ref, err)}
+	}
+	defer func() {
+		_ = e.git.WorktreeRemove(path, true)
+		_ = e.git.WorktreePrune()
+	}()
+
+	runner, err := LoadCheckRunner(e.rig.Path, CheckVerify)
+	if err != nil {
+		return ProcessResult{Error: err.Error()}
+	}
+	timeout := runner.TimeoutOr(t.timeout)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	history, err := LoadFlakeHistory(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: flaky check settings: %v\n", err)
+	}
+	run := CheckCommand{
+		Command:  t.verifyCommand,
+		Dir:      path,
+		Attempts: 1 + history.Retries(CheckVerify),
+		Runner:   runner,
+		Retrying: func(attempt, attempts int) {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying verification (attempt %d/%d)...\n", attempt, attempts)
+		},
+	}.Run(ctx)
+	var artifacts string
+	if ctx.Err() == nil {
+		if err := history.Record(CheckVerify, mrID, run); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+		}
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		artifacts = e.saveArtifacts(mrID, CheckVerify, path, run)
+	}
+	if run.Err != nil {
+		reason := fmt.Sprintf("post-merge verification failed: %v", run.Err)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = fmt.Sprintf("post-merge verification timed out after %v", timeout)
+		}
+		if tail := lastLines(run.Output, verifyOutputLines); tail != "" {
+			reason += "\n" + tail
+		}
+		return ProcessResult{TestsFailed: true, Error: reason, Tests: run.Tests, Artifacts: artifacts}
+	}
+	return ProcessResult{Success: true, Tests: run.Tests, Artifacts: artifacts}
+}
+
+// rollbackTarget undoes a local merge that didn't land: if the refinery's
+// target branch is at commit, it goes back to the push remote's. Best-effort, like
+// fastForwardTarget.
+func (e *Engineer) rollbackTarget(target, commit string) {
+	if local, err := e.git.Rev(target); err != nil || local != commit {
+		return
+	}
+	var err error
+	if current, _ := e.git.CurrentBranch(); current == target {
+		err = e.git.ResetHard(e.remote + "/" + target)
+	} else {
+		err = e.git.ResetBranch(target, e.remote+"/"+target)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not roll back local %s: %v\n", target, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rolled back local %s to %s/%s\n", target, e.remote, target)
+}
+
+// shortCommit abbreviates a commit SHA for messages.
+func shortCommit(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,132 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNewTransaction(t *testing.T) {
+	if tx, err := NewTransaction(nil, "go test ./..."); err != nil || tx != nil {
+		t.Errorf("NewTransaction(nil) = %v, %v; want nil (merges land directly)", tx, err)
+	}
+
+	tx, err := NewTransaction(&config.TransactionalMergeConfig{}, "go test ./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tx.VerifyCommand(); got != "go test ./..." {
+		t.Errorf("VerifyCommand = %q, want the test command by default", got)
+	}
+
+	tx, err = NewTransaction(&config.TransactionalMergeConfig{VerifyCommand: "make check", Timeout: "20m"}, "go test ./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tx.VerifyCommand(); got != "make check" {
+		t.Errorf("VerifyCommand = %q, want make check", got)
+	}
+
+	if _, err := NewTransaction(&config.TransactionalMergeConfig{Timeout: "soon"}, ""); err == nil {
+		t.Error("NewTransaction with a bad timeout should fail")
+	}
+}
+
+// stageLocalMerge fast-forwards the refinery's main to the polecat branch,
+// as the patrol does before landing, and returns the refinery checkout.
+func stageLocalMerge(t *testing.T, rigPath string) string {
+	t.Helper()
+	refineryRig := filepath.Join(rigPath, "refinery", "rig")
+	runGit(t, refineryRig, "merge", "--ff-only", "polecat/toast")
+	return refineryRig
+}
+
+func TestLandVerifiesBeforePushing(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	refineryRig := stageLocalMerge(t, rigPath)
+	tx := &Transaction{verifyCommand: "test -f feature.txt"}
+
+	result := e.Land(context.Background(), tx, nil, "gp-mr-1", "main", "main")
+	if !result.Success {
+		t.Fatalf("Land failed: %s", result.Error)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("origin main = %s, want %s", got, result.MergeCommit)
+	}
+	if refs := runGit(t, refineryRig, "for-each-ref", StagingRefPrefix); refs != "" {
+		t.Errorf("staging ref left behind:\n%s", refs)
+	}
+	if list := runGit(t, refineryRig, "worktree", "list"); strings.Contains(list, mergeWorktreePrefix) {
+		t.Errorf("verify worktree left behind:\n%s", list)
+	}
+}
+
+func TestLandRollsBackFailedVerification(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	before := runGit(t, origin, "rev-parse", "main")
+	refineryRig := stageLocalMerge(t, rigPath)
+	tx := &Transaction{verifyCommand: "echo broken main; exit 1"}
+
+	result := e.Land(context.Background(), tx, nil, "gp-mr-1", "main", "main")
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("Land = %+v, want failed verification", result)
+	}
+	if !strings.Contains(result.Error, "broken main") {
+		t.Errorf("error %q should include the verify output", result.Error)
+	}
+
+	// Nothing landed, locally or on origin
+	if got := runGit(t, origin, "rev-parse", "main"); got != before {
+		t.Error("origin main moved on a failed verification")
+	}
+	if got := runGit(t, refineryRig, "rev-parse", "HEAD"); got != before {
+		t.Error("refinery main not rolled back to origin's")
+	}
+	if _, err := os.Stat(filepath.Join(refineryRig, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("refinery checkout still has the failed merge")
+	}
+	if refs := runGit(t, refineryRig, "for-each-ref", StagingRefPrefix); refs != "" {
+		t.Errorf("staging ref left behind:\n%s", refs)
+	}
+}
+
+func TestLandTargetMoved(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	stageLocalMerge(t, rigPath)
+
+	// Main moves on after the refinery merged
+	other := filepath.Join(t.TempDir(), "other")
+	runGit(t, filepath.Dir(other), "clone", origin, other)
+	commitFile(t, other, "other.txt", "other\n", "feat: other")
+	runGit(t, other, "push", "origin", "main")
+	moved := runGit(t, origin, "rev-parse", "main")
+
+	result := e.Land(context.Background(), &Transaction{verifyCommand: "true"}, nil, "gp-mr-1", "main", "main")
+	if result.Success || result.TestsFailed {
+		t.Fatalf("Land = %+v, want failure because the target moved", result)
+	}
+	if got := runGit(t, origin, "rev-parse", "main"); got != moved {
+		t.Error("origin main changed when the land should have been refused")
+	}
+}
+
+func TestLandWithoutTransaction(t *testing.T) {
+	rigPath, origin, _ := setupMirrorRig(t)
+	e := mirrorTestEngineer(rigPath)
+	stageLocalMerge(t, rigPath)
+
+	result := e.Land(context.Background(), nil, nil, "gp-mr-1", "main", "main")
+	if !result.Success {
+		t.Fatalf("Land failed: %s", result.Error)
+	}
+	if got := runGit(t, origin, "log", "-1", "--format=%s", "main"); got != "feat: add feature" {
+		t.Errorf("origin main head = %q, want the polecat commit", got)
+	}
+}
@@ -0,0 +1,237 @@
+package rig
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// BranchTemplateKey is the rig config key holding the work branch template.
+const BranchTemplateKey = "polecat_branch_template"
+
+// DefaultBranchTemplate describes the scheme used when no template is set.
+// Branches without an issue are named polecat/{name}-{timestamp}.
+const DefaultBranchTemplate = "polecat/{name}/{id}@{timestamp}"
+
+// branchVarPatterns maps each template variable to what it matches when a
+// branch name is parsed back.
+var branchVarPatterns = map[string]string{
+	"user":        `[^/]+?`,
+	"year":        `[0-9]{2}`,
+	"month":       `[0-9]{2}`,
+	"name":        `[^/]+?`,
+	"id":          `[^/@]+?`,
+	"issue":       `[^/@]+?`,
+	"description": `[a-z0-9-]*?`,
+	"timestamp":   `[0-9a-z]+`,
+}
+
+var branchVarRe = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// BranchPolicy names work branches from a rig's branch template and parses
+// branch names back into the issue and worker they were made for. Both
+// directions come from the same template, so they can't disagree: each
+// {variable} in the template is a named capture when parsing.
+//
+// A nil or empty policy uses the default scheme.
+type BranchPolicy struct {
+	template string
+	prefix   string         // beads prefix, restored to {issue} when parsing
+	re       *regexp.Regexp // the template with each variable as a named capture
+	vars     map[string]bool
+}
+
+// NewBranchPolicy builds a policy from a branch template (empty for the
+// default scheme). prefix is the rig's beads prefix: {issue} drops it when
+// naming, so parsing puts it back.
+func NewBranchPolicy(template, prefix string) (*BranchPolicy, error) {
+	p := &BranchPolicy{template: template, prefix: prefix, vars: make(map[string]bool)}
+	if template == "" {
+		return p, nil
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, m := range branchVarRe.FindAllStringSubmatchIndex(template, -1) {
+		name := template[m[2]:m[3]]
+		expr, ok := branchVarPatterns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable {%s} in branch template %q", name, template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(template[last:m[0]]))
+		if p.vars[name] {
+			fmt.Fprintf(&pattern, "(?:%s)", expr) // Only the first occurrence is captured
+		} else {
+			fmt.Fprintf(&pattern, "(?P<%s>%s)", name, expr)
+		}
+		p.vars[name] = true
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("branch template %q: %w", template, err)
+	}
+	p.re = re
+	return p, nil
+}
+
+// BranchPolicy returns the rig's work branch policy, from the layered
+// polecat_branch_template config.
+func (r *Rig) BranchPolicy() (*BranchPolicy, error) {
+	prefix := ""
+	if r.Config != nil {
+		prefix = r.Config.Prefix
+	}
+	return NewBranchPolicy(r.GetStringConfig(BranchTemplateKey), prefix)
+}
+
+// Template returns the policy's template, or DefaultBranchTemplate.
+func (p *BranchPolicy) Template() string {
+	if p == nil || p.template == "" {
+		return DefaultBranchTemplate
+	}
+	return p.template
+}
+
+// Uses reports whether the template contains the variable name (e.g.,
+// "description"), so callers can skip looking up values it doesn't need.
+func (p *BranchPolicy) Uses(name string) bool {
+	if p == nil || p.template == "" {
+		return name == "name" || name == "id" || name == "timestamp"
+	}
+	return p.vars[name]
+}
+
+// BranchVars are the values a branch name is made from.
+type BranchVars struct {
+	Name  string    // Worker (polecat or crew) name
+	Issue string    // Full issue ID, e.g. "gt-123" (may be empty)
+	Title string    // Issue title, for {description}
+	User  string    // git user.name, for {user}
+	Now   time.Time // For {year}, {month}, and {timestamp}
+}
+
+// Name returns the branch name for v. Empty path segments (e.g., from a
+// blank {description}) are dropped.
+func (p *BranchPolicy) Name(v BranchVars) string {
+	timestamp := strconv.FormatInt(v.Now.UnixMilli(), 36)
+	if p == nil || p.template == "" {
+		if v.Issue != "" {
+			return fmt.Sprintf("%s%s/%s@%s", constants.BranchPolecatPrefix, v.Name, v.Issue, timestamp)
+		}
+		return fmt.Sprintf("%s%s-%s", constants.BranchPolecatPrefix, v.Name, timestamp)
+	}
+
+	user := v.User
+	if user == "" {
+		user = "unknown"
+	}
+	// {issue} is the ID without its prefix (e.g., "gt-123" -> "123")
+	issue := v.Issue
+	if idx := strings.Index(issue, "-"); idx >= 0 {
+		issue = issue[idx+1:]
+	}
+	result := strings.NewReplacer(
+		"{user}", user,
+		"{year}", v.Now.Format("06"),
+		"{month}", v.Now.Format("01"),
+		"{name}", v.Name,
+		"{id}", v.Issue,
+		"{issue}", issue,
+		"{description}", BranchDescription(v.Title),
+		"{timestamp}", timestamp,
+	).Replace(p.template)
+
+	// Clean up any empty segments (e.g., "adam///" -> "adam")
+	parts := strings.Split(result, "/")
+	cleanParts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			cleanParts = append(cleanParts, part)
+		}
+	}
+	return strings.Join(cleanParts, "/")
+}
+
+// BranchDescription sanitizes an issue title for a branch name: lowercase
+// letters, digits, and single hyphens, at most 40 characters.
+func BranchDescription(title string) string {
+	desc := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(title))
+	for strings.Contains(desc, "--") {
+		desc = strings.ReplaceAll(desc, "--", "-")
+	}
+	desc = strings.Trim(desc, "-")
+	if len(desc) > 40 {
+		desc = strings.TrimRight(desc[:40], "-")
+	}
+	return desc
+}
+
+// BranchInfo is what a work branch's name says about its work.
+type BranchInfo struct {
+	Issue  string // Full issue ID, or "" if the name doesn't carry one
+	Worker string // Worker name, or "" if the name doesn't carry one
+}
+
+// Parse reads the issue and worker from a branch named by this policy. The
+// default scheme's forms are always recognized too, so branches made before
+// a template was set still parse. ok is false if the name matches neither.
+func (p *BranchPolicy) Parse(branch string) (info BranchInfo, ok bool) {
+	if p != nil && p.re != nil {
+		if m := p.re.FindStringSubmatch(branch); m != nil {
+			group := func(name string) string {
+				if i := p.re.SubexpIndex(name); i >= 0 {
+					return m[i]
+				}
+				return ""
+			}
+			info.Worker = group("name")
+			info.Issue = group("id")
+			if info.Issue == "" {
+				if issue := group("issue"); issue != "" {
+					info.Issue = issue
+					if p.prefix != "" {
+						info.Issue = p.prefix + "-" + issue
+					}
+				}
+			}
+			return info, true
+		}
+	}
+	return parseDefaultBranch(branch)
+}
+
+// parseDefaultBranch parses the default scheme:
+//   - polecat/<worker>/<issue>[@<timestamp>]
+//   - polecat/<worker>-<timestamp> (no issue; gt done uses the hook instead)
+func parseDefaultBranch(branch string) (BranchInfo, bool) {
+	rest, found := strings.CutPrefix(branch, constants.BranchPolecatPrefix)
+	if !found || rest == "" {
+		return BranchInfo{}, false
+	}
+	if worker, issue, found := strings.Cut(rest, "/"); found {
+		// Strip @timestamp suffix if present (e.g., "gt-abc@mk123" -> "gt-abc")
+		if at := strings.Index(issue, "@"); at > 0 {
+			issue = issue[:at]
+		}
+		return BranchInfo{Issue: issue, Worker: worker}, true
+	}
+	// The part after polecat/ is "worker-timestamp", not an issue ID
+	if dash := strings.LastIndex(rest, "-"); dash > 0 {
+		return BranchInfo{Worker: rest[:dash]}, true
+	}
+	return BranchInfo{Worker: rest}, true
+}
@@ -0,0 +1,113 @@
+package rig
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBranchPolicyRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	vars := BranchVars{Name: "toast", Issue: "gt-abc.2", Title: "Fix: login redirect", User: "ann", Now: now}
+
+	tests := []struct {
+		template string
+		want     string // prefix of the generated name
+	}{
+		{"", "polecat/toast/gt-abc.2@"},
+		{"polecat/{name}/{id}", "polecat/toast/gt-abc.2"},
+		{"{user}/{year}/{month}/{name}/{issue}", "ann/26/03/toast/abc.2"},
+		{"work/{name}/{id}-{description}", "work/toast/gt-abc.2-fix-login-redirect"},
+		{"{name}/{issue}-{timestamp}", "toast/abc.2-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			p, err := NewBranchPolicy(tt.template, "gt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := p.Name(vars)
+			if !strings.HasPrefix(name, tt.want) {
+				t.Errorf("Name = %q, want prefix %q", name, tt.want)
+			}
+			info, ok := p.Parse(name)
+			if !ok || info.Issue != "gt-abc.2" || info.Worker != "toast" {
+				t.Errorf("Parse(%q) = %+v, %v; want gt-abc.2 by toast", name, info, ok)
+			}
+		})
+	}
+}
+
+func TestBranchPolicyParseDefault(t *testing.T) {
+	// Default-scheme branches parse under any template, so branches made
+	// before a template was set still work.
+	p, err := NewBranchPolicy("{user}/{year}/{issue}", "gt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]BranchInfo{
+		"polecat/nux/gt-xyz":       {Issue: "gt-xyz", Worker: "nux"},
+		"polecat/nux/gt-xyz@mk123": {Issue: "gt-xyz", Worker: "nux"},
+		"polecat/furiosa-mkb0vq9f": {Worker: "furiosa"},
+		"polecat/my-cat-mkb0vq9f":  {Worker: "my-cat"},
+		"ann/26/xyz":               {Issue: "gt-xyz"},
+	}
+	for branch, want := range tests {
+		if got, ok := p.Parse(branch); !ok || got != want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", branch, got, ok, want)
+		}
+	}
+
+	for _, branch := range []string{"main", "feature/gt-abc", "polecat/"} {
+		if info, ok := p.Parse(branch); ok {
+			t.Errorf("Parse(%q) = %+v, want no match", branch, info)
+		}
+	}
+}
+
+func TestBranchPolicyNil(t *testing.T) {
+	var p *BranchPolicy
+	if p.Template() != DefaultBranchTemplate {
+		t.Errorf("Template() = %q, want the default", p.Template())
+	}
+	if !p.Uses("name") || p.Uses("user") {
+		t.Error("nil policy should use the default scheme's variables")
+	}
+	now := time.UnixMilli(1000)
+	if got := p.Name(BranchVars{Name: "toast", Now: now}); got != "polecat/toast-rs" {
+		t.Errorf("Name without issue = %q", got)
+	}
+	if info, ok := p.Parse("polecat/toast/gt-1@rs"); !ok || info.Issue != "gt-1" {
+		t.Errorf("Parse = %+v, %v", info, ok)
+	}
+}
+
+func TestNewBranchPolicyUnknownVariable(t *testing.T) {
+	if _, err := NewBranchPolicy("polecat/{worker}/{id}", "gt"); err == nil {
+		t.Error("NewBranchPolicy accepted an unknown variable")
+	}
+}
+
+func TestBranchPolicyUses(t *testing.T) {
+	p, err := NewBranchPolicy("{user}/{description}/{user}", "gt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Uses("user") || !p.Uses("description") || p.Uses("name") {
+		t.Errorf("Uses wrong for %q", p.Template())
+	}
+}
+
+func TestBranchDescription(t *testing.T) {
+	tests := map[string]string{
+		"Fix: login redirect!":      "fix-login-redirect",
+		"  Already--hyphenated":     "already-hyphenated",
+		"":                          "",
+		strings.Repeat("abcd ", 12): "abcd-abcd-abcd-abcd-abcd-abcd-abcd-abcd",
+	}
+	for title, want := range tests {
+		if got := BranchDescription(title); got != want {
+			t.Errorf("BranchDescription(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package rig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CloneConfig keeps huge repositories from being fully materialized in every
+// clone and polecat worktree.
+type CloneConfig struct {
+	// Filter makes the rig's clones partial clones: "blobless" fetches file
+	// contents on demand, "treeless" directories too. A raw git --filter
+	// spec (e.g. blob:limit=1m) is used as given.
+	Filter string `json:"filter,omitempty"`
+
+	// SparsePaths limits polecat worktrees to these directories (plus the
+	// files at the repo root). Empty checks out everything.
+	SparsePaths []string `json:"sparse_paths,omitempty"`
+}
+
+// PartialCloneFilter returns the git --filter spec for a clone filter
+// setting, or "" for a full clone.
+func PartialCloneFilter(filter string) (string, error) {
+	switch filter {
+	case "", "none":
+		return "", nil
+	case "blobless":
+		return "blob:none", nil
+	case "treeless":
+		return "tree:0", nil
+	}
+	if !strings.Contains(filter, ":") {
+		return "", fmt.Errorf("invalid clone filter %q (want blobless, treeless or a git filter spec)", filter)
+	}
+	return filter, nil
+}
+
+// CloneFilter returns the git --filter spec the rig's clones are made with,
+// or "" for full clones. Falls back to "" if config cannot be loaded.
+func (r *Rig) CloneFilter() string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.Clone == nil {
+		return ""
+	}
+	filter, err := PartialCloneFilter(cfg.Clone.Filter)
+	if err != nil {
+		return ""
+	}
+	return filter
+}
+
+// SparsePaths returns the directories polecat worktrees check out, or nil
+// for everything.
+func (r *Rig) SparsePaths() []string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.Clone == nil {
+		return nil
+	}
+	return cfg.Clone.SparsePaths
+}
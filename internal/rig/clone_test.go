@@ -0,0 +1,24 @@
+package rig
+
+import "testing"
+
+func TestPartialCloneFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"none", "", false},
+		{"blobless", "blob:none", false},
+		{"treeless", "tree:0", false},
+		{"blob:limit=1m", "blob:limit=1m", false},
+		{"shallow", "", true},
+	}
+	for _, tt := range tests {
+		got, err := PartialCloneFilter(tt.filter)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("PartialCloneFilter(%q) = %q, %v; want %q, err %v", tt.filter, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -72,14 +72,17 @@ func convertToSSH(httpsURL string) string {
 
 // RigConfig represents the rig-level configuration (config.json at rig root).
 type RigConfig struct {
-	Type          string       `json:"type"`                     // "rig"
-	Version       int          `json:"version"`                  // schema version
-	Name          string       `json:"name"`                     // rig name
-	GitURL        string       `json:"git_url"`                  // repository URL
-	LocalRepo     string       `json:"local_repo,omitempty"`     // optional local reference repo
-	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
-	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
-	Beads         *BeadsConfig `json:"beads,omitempty"`
+	Type          string         `json:"type"`                     // "rig"
+	Version       int            `json:"version"`                  // schema version
+	Name          string         `json:"name"`                     // rig name
+	GitURL        string         `json:"git_url"`                  // repository URL
+	LocalRepo     string         `json:"local_repo,omitempty"`     // optional local reference repo
+	DefaultBranch string         `json:"default_branch,omitempty"` // main, master, etc.
+	CreatedAt     time.Time      `json:"created_at"`               // when rig was created
+	Beads         *BeadsConfig   `json:"beads,omitempty"`
+	Remotes       []RemoteConfig `json:"remotes,omitempty"`     // remotes besides origin (git_url)
+	PushRemote    string         `json:"push_remote,omitempty"` // remote the refinery lands merges on (default origin)
+	Clone         *CloneConfig   `json:"clone,omitempty"`       // partial clone and sparse checkout settings
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -209,11 +212,12 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 
 // AddRigOptions configures rig creation.
 type AddRigOptions struct {
-	Name          string // Rig name (directory name)
-	GitURL        string // Repository URL
-	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
-	LocalRepo     string // Optional local repo for reference clones
-	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	Name          string       // Rig name (directory name)
+	GitURL        string       // Repository URL
+	BeadsPrefix   string       // Beads issue prefix (defaults to derived from name)
+	LocalRepo     string       // Optional local repo for reference clones
+	DefaultBranch string       // Default branch (defaults to auto-detected from remote)
+	Clone         *CloneConfig // Optional partial clone and sparse checkout settings
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -286,6 +290,14 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
 	}
 
+	var filter string
+	if opts.Clone != nil {
+		var err error
+		if filter, err = PartialCloneFilter(opts.Clone.Filter); err != nil {
+			return nil, err
+		}
+	}
+
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
@@ -316,6 +328,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
+		Clone: opts.Clone,
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
@@ -326,7 +339,12 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
-	if localRepo != "" {
+	if filter != "" {
+		// Partial clone: objects are fetched on demand from origin
+		if err := m.git.CloneBarePartial(opts.GitURL, bareRepoPath, localRepo, filter); err != nil {
+			return nil, wrapCloneError(err, opts.GitURL)
+		}
+	} else if localRepo != "" {
 		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(bareRepoPath)
@@ -367,7 +385,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := os.MkdirAll(filepath.Dir(mayorRigPath), 0755); err != nil {
 		return nil, fmt.Errorf("creating mayor dir: %w", err)
 	}
-	if localRepo != "" {
+	if filter != "" {
+		if err := m.git.ClonePartial(opts.GitURL, mayorRigPath, localRepo, filter); err != nil {
+			return nil, fmt.Errorf("cloning for mayor: %w", err)
+		}
+	} else if localRepo != "" {
 		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(mayorRigPath)
@@ -591,6 +613,11 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 
 // saveRigConfig writes the rig configuration to config.json.
 func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
+	return SaveRigConfig(rigPath, cfg)
+}
+
+// SaveRigConfig writes the rig configuration to config.json.
+func SaveRigConfig(rigPath string, cfg *RigConfig) error {
 	configPath := filepath.Join(rigPath, "config.json")
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
@@ -0,0 +1,45 @@
+package rig
+
+import (
+	"os"
+	"strconv"
+	"sync"
+)
+
+// DefaultParallelism bounds how many rigs ForEach works on at once. Per-rig
+// work is mostly waiting on git and bd subprocesses, so this is set above
+// the CPU count but low enough not to swamp a Dolt server with many rigs.
+const DefaultParallelism = 8
+
+// ParallelismEnv overrides DefaultParallelism. Set it to 1 to work on rigs
+// one at a time.
+const ParallelismEnv = "GT_RIG_PARALLELISM"
+
+// Parallelism returns how many rigs ForEach works on at once.
+func Parallelism() int {
+	if n, err := strconv.Atoi(os.Getenv(ParallelismEnv)); err == nil && n > 0 {
+		return n
+	}
+	return DefaultParallelism
+}
+
+// ForEach calls fn for every item, running up to Parallelism() calls at
+// once, and returns the results in the order of items. Items are usually
+// rigs or rig names; fn must be safe to run concurrently and should report
+// per-rig failures in its result rather than aborting the others.
+func ForEach[T, R any](items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+	sem := make(chan struct{}, Parallelism())
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = fn(item)
+		}(i, item)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,53 @@
+package rig
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	t.Setenv(ParallelismEnv, "3")
+
+	var running, peak atomic.Int32
+	items := []int{5, 4, 3, 2, 1, 0, 6, 7}
+	got := ForEach(items, func(n int) int {
+		now := running.Add(1)
+		for {
+			p := peak.Load()
+			if now <= p || peak.CompareAndSwap(p, now) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		running.Add(-1)
+		return n * 10
+	})
+
+	for i, n := range items {
+		if got[i] != n*10 {
+			t.Errorf("result %d = %d, want %d (results must keep input order)", i, got[i], n*10)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	for _, tt := range []struct {
+		env  string
+		want int
+	}{
+		{"", DefaultParallelism},
+		{"1", 1},
+		{"16", 16},
+		{"0", DefaultParallelism},
+		{"many", DefaultParallelism},
+	} {
+		t.Setenv(ParallelismEnv, tt.env)
+		if got := Parallelism(); got != tt.want {
+			t.Errorf("Parallelism() with %s=%q = %d, want %d", ParallelismEnv, tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// OriginRemote is the remote every rig clone has, at the rig's git_url.
+const OriginRemote = "origin"
+
+// RemoteConfig is a git remote a rig's clones carry besides origin, e.g. a
+// fork the refinery pushes to or an internal mirror.
+type RemoteConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+
+	// Mirror makes the refinery push each merge's target branch here too,
+	// after landing it on the push remote.
+	Mirror bool `json:"mirror,omitempty"`
+}
+
+var remoteNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// PushRemote returns the remote the refinery merges into and pushes to.
+// Falls back to origin if not configured or if config cannot be loaded.
+func (r *Rig) PushRemote() string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.PushRemote == "" {
+		return OriginRemote
+	}
+	return cfg.PushRemote
+}
+
+// MirrorRemotes returns the remotes the refinery mirrors merges to.
+func (r *Rig) MirrorRemotes() []string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil {
+		return nil
+	}
+	var mirrors []string
+	for _, remote := range cfg.Remotes {
+		if remote.Mirror && remote.Name != cfg.PushRemote {
+			mirrors = append(mirrors, remote.Name)
+		}
+	}
+	return mirrors
+}
+
+// SetRemote adds a remote to the rig's config, or changes its URL and
+// mirror flag, and configures it in the rig's clones.
+func SetRemote(rigPath string, remote RemoteConfig) error {
+	if remote.Name == OriginRemote {
+		return fmt.Errorf("origin is the rig's git_url and can't be changed here")
+	}
+	if !remoteNameRe.MatchString(remote.Name) {
+		return fmt.Errorf("invalid remote name %q", remote.Name)
+	}
+	if remote.URL == "" {
+		return fmt.Errorf("remote %s needs a URL", remote.Name)
+	}
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+
+	replaced := false
+	for i := range cfg.Remotes {
+		if cfg.Remotes[i].Name == remote.Name {
+			cfg.Remotes[i] = remote
+			replaced = true
+		}
+	}
+	if !replaced {
+		cfg.Remotes = append(cfg.Remotes, remote)
+	}
+
+	for _, g := range rigClones(rigPath) {
+		if err := configureRemote(g, remote.Name, remote.URL); err != nil {
+			return fmt.Errorf("configuring remote %s: %w", remote.Name, err)
+		}
+	}
+	return SaveRigConfig(rigPath, cfg)
+}
+
+// RemoveRemote removes a remote from the rig's config and clones. The push
+// remote can't be removed until another is chosen.
+func RemoveRemote(rigPath, name string) error {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+	if name == cfg.PushRemote {
+		return fmt.Errorf("%s is the push remote; set another first", name)
+	}
+
+	kept := cfg.Remotes[:0]
+	for _, remote := range cfg.Remotes {
+		if remote.Name != name {
+			kept = append(kept, remote)
+		}
+	}
+	if len(kept) == len(cfg.Remotes) {
+		return fmt.Errorf("rig has no remote %q", name)
+	}
+	cfg.Remotes = kept
+
+	for _, g := range rigClones(rigPath) {
+		if remotes, err := g.Remotes(); err == nil && slices.Contains(remotes, name) {
+			if err := g.RemoveRemote(name); err != nil {
+				return fmt.Errorf("removing remote %s: %w", name, err)
+			}
+		}
+	}
+	return SaveRigConfig(rigPath, cfg)
+}
+
+// SetPushRemote makes name, origin or a configured remote, the remote the
+// refinery merges into and pushes to.
+func SetPushRemote(rigPath, name string) error {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+	if name == OriginRemote {
+		cfg.PushRemote = ""
+		return SaveRigConfig(rigPath, cfg)
+	}
+	for _, remote := range cfg.Remotes {
+		if remote.Name == name {
+			cfg.PushRemote = name
+			return SaveRigConfig(rigPath, cfg)
+		}
+	}
+	return fmt.Errorf("rig has no remote %q (add it with gt rig remotes set)", name)
+}
+
+// ConfigureRemotes adds the rig's configured remotes to its clones, or
+// updates their URLs, e.g. after the clones were re-created.
+func ConfigureRemotes(rigPath string) error {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+	for _, g := range rigClones(rigPath) {
+		for _, remote := range cfg.Remotes {
+			if err := configureRemote(g, remote.Name, remote.URL); err != nil {
+				return fmt.Errorf("configuring remote %s: %w", remote.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rigClones returns the rig's repositories that carry its remotes: the
+// shared bare repo (and so the refinery and polecat worktrees) and the
+// mayor clone. Missing ones are skipped.
+func rigClones(rigPath string) []*git.Git {
+	var clones []*git.Git
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); err == nil {
+		clones = append(clones, git.NewGitWithDir(bareRepoPath, ""))
+	}
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(filepath.Join(mayorRigPath, ".git")); err == nil {
+		clones = append(clones, git.NewGit(mayorRigPath))
+	}
+	return clones
+}
+
+// configureRemote adds remote name at url to a repository, or points an
+// existing one at url.
+func configureRemote(g *git.Git, name, url string) error {
+	remotes, err := g.Remotes()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(remotes, name) {
+		return g.SetRemoteURL(name, url)
+	}
+	return g.AddRemote(name, url)
+}
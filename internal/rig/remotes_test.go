@@ -0,0 +1,89 @@
+package rig
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRemotes(t *testing.T) {
+	rigPath, origin := setupSyncRig(t)
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Name: "gastown", GitURL: origin}); err != nil {
+		t.Fatal(err)
+	}
+	r := &Rig{Name: "gastown", Path: rigPath}
+	if got := r.PushRemote(); got != OriginRemote {
+		t.Errorf("PushRemote = %q, want origin by default", got)
+	}
+
+	fork := filepath.Join(filepath.Dir(origin), "fork.git")
+	syncGit(t, filepath.Dir(origin), "clone", "-q", "--bare", origin, fork)
+	if err := SetRemote(rigPath, RemoteConfig{Name: "fork", URL: fork}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRemote(rigPath, RemoteConfig{Name: "internal", URL: "/srv/internal.git", Mirror: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{filepath.Join(rigPath, "refinery", "rig"), filepath.Join(rigPath, "mayor", "rig")} {
+		if got := syncGit(t, dir, "remote", "get-url", "fork"); got != fork {
+			t.Errorf("%s: fork URL = %q, want %q", dir, got, fork)
+		}
+	}
+
+	if err := SetPushRemote(rigPath, "nope"); err == nil {
+		t.Error("SetPushRemote accepted an unknown remote")
+	}
+	if err := SetPushRemote(rigPath, "fork"); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.PushRemote(); got != "fork" {
+		t.Errorf("PushRemote = %q, want fork", got)
+	}
+	if got := r.MirrorRemotes(); len(got) != 1 || got[0] != "internal" {
+		t.Errorf("MirrorRemotes = %v, want [internal]", got)
+	}
+	if err := RemoveRemote(rigPath, "fork"); err == nil {
+		t.Error("RemoveRemote removed the push remote")
+	}
+
+	// Syncing fast-forwards to the push remote
+	result := Sync(rigPath)
+	if result.Remote != "fork" {
+		t.Errorf("Sync remote = %q, want fork", result.Remote)
+	}
+	for _, repo := range result.Repos {
+		if repo.Error != "" {
+			t.Errorf("%s: %s", repo.Path, repo.Error)
+		}
+	}
+
+	for _, bad := range []RemoteConfig{
+		{Name: "origin", URL: fork},
+		{Name: "-x", URL: fork},
+		{Name: "fork"},
+	} {
+		if err := SetRemote(rigPath, bad); err == nil {
+			t.Errorf("SetRemote accepted %+v", bad)
+		}
+	}
+
+	if err := SetPushRemote(rigPath, OriginRemote); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveRemote(rigPath, "fork"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveRemote(rigPath, "fork"); err == nil {
+		t.Error("RemoveRemote removed a missing remote")
+	}
+	remotes := syncGit(t, filepath.Join(rigPath, "mayor", "rig"), "remote")
+	if remotes != "internal\norigin" {
+		t.Errorf("mayor remotes = %q, want internal and origin", remotes)
+	}
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PushRemote != "" || len(cfg.Remotes) != 1 || cfg.Remotes[0].Name != "internal" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
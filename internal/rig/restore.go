@@ -0,0 +1,150 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// RestoreClones re-creates the repositories of a rig whose state was
+// restored without them (gt import): the shared bare repo, the mayor clone
+// and the refinery worktree. Anything restored inside mayor/rig or
+// refinery/rig (the mayor clone's beads database) is laid back over the
+// fresh checkout. Repositories that already exist are left alone.
+//
+// Crew workspaces and polecat worktrees are not re-created; gt crew add
+// and gt sling make them as before.
+func (m *Manager) RestoreClones(name string) error {
+	rigPath := filepath.Join(m.townRoot, name)
+	rigConfig, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return fmt.Errorf("loading rig config: %w", err)
+	}
+	if rigConfig.GitURL == "" {
+		return fmt.Errorf("rig config has no git_url")
+	}
+
+	filter := (&Rig{Path: rigPath}).CloneFilter()
+
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); os.IsNotExist(err) {
+		fmt.Printf("  Cloning repository (this may take a moment)...\n")
+		clone := m.git.CloneBare
+		if filter != "" {
+			clone = func(url, dest string) error { return m.git.CloneBarePartial(url, dest, "", filter) }
+		}
+		if err := clone(rigConfig.GitURL, bareRepoPath); err != nil {
+			return wrapCloneError(err, rigConfig.GitURL)
+		}
+		fmt.Printf("   ✓ Created shared bare repo\n")
+	}
+	bareGit := git.NewGitWithDir(bareRepoPath, "")
+
+	defaultBranch := rigConfig.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = bareGit.RemoteDefaultBranch()
+		if defaultBranch == "" {
+			defaultBranch = bareGit.DefaultBranch()
+		}
+	}
+
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	restored, err := restoreCheckout(mayorRigPath, func() error {
+		clone := m.git.Clone
+		if filter != "" {
+			clone = func(url, dest string) error { return m.git.ClonePartial(url, dest, "", filter) }
+		}
+		if err := clone(rigConfig.GitURL, mayorRigPath); err != nil {
+			return fmt.Errorf("cloning for mayor: %w", err)
+		}
+		return git.NewGitWithDir("", mayorRigPath).Checkout(defaultBranch)
+	})
+	if err != nil {
+		return err
+	}
+	if restored {
+		if err := m.createRoleCLAUDEmd(mayorRigPath, "mayor", name, ""); err != nil {
+			return fmt.Errorf("creating mayor CLAUDE.md: %w", err)
+		}
+		fmt.Printf("   ✓ Created mayor clone\n")
+	}
+
+	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
+	restored, err = restoreCheckout(refineryRigPath, func() error {
+		if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
+			return fmt.Errorf("creating refinery worktree: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if restored {
+		if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
+			fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
+		}
+		if err := m.createRoleCLAUDEmd(refineryRigPath, "refinery", name, ""); err != nil {
+			return fmt.Errorf("creating refinery CLAUDE.md: %w", err)
+		}
+		if err := CopyOverlay(rigPath, refineryRigPath); err != nil {
+			fmt.Printf("  Warning: Could not copy overlay files to refinery: %v\n", err)
+		}
+		fmt.Printf("   ✓ Created refinery worktree\n")
+	}
+
+	// Fresh clones only have origin
+	if len(rigConfig.Remotes) > 0 {
+		if err := ConfigureRemotes(rigPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreCheckout runs create to make the git checkout at path, unless one
+// is already there. Whatever was at path beforehand is moved aside first
+// and put back over the checkout afterwards, entry by entry. Reports
+// whether it created the checkout.
+func restoreCheckout(path string, create func() error) (bool, error) {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+
+	aside := path + ".restore"
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, aside); err != nil {
+			return false, fmt.Errorf("moving restored %s aside: %w", path, err)
+		}
+	}
+	if err := create(); err != nil {
+		if _, statErr := os.Stat(aside); statErr == nil {
+			_ = os.RemoveAll(path)
+			_ = os.Rename(aside, path)
+		}
+		return false, err
+	}
+
+	entries, err := os.ReadDir(aside)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	for _, entry := range entries {
+		dst := filepath.Join(path, entry.Name())
+		if err := os.RemoveAll(dst); err != nil {
+			return true, err
+		}
+		if err := os.Rename(filepath.Join(aside, entry.Name()), dst); err != nil {
+			return true, fmt.Errorf("restoring %s: %w", dst, err)
+		}
+	}
+	return true, os.Remove(aside)
+}
@@ -0,0 +1,70 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreCheckout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mayor", "rig")
+	restoredDB := filepath.Join(path, ".beads", "beads.db")
+	if err := os.MkdirAll(filepath.Dir(restoredDB), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(restoredDB, []byte("restored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := func() error {
+		for rel, content := range map[string]string{".git/HEAD": "ref", ".beads/beads.db": "tracked", "main.go": "package main"} {
+			p := filepath.Join(path, rel)
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	created, err := restoreCheckout(path, clone)
+	if err != nil || !created {
+		t.Fatalf("restoreCheckout = %v, %v; want created", created, err)
+	}
+	if data, _ := os.ReadFile(restoredDB); string(data) != "restored" {
+		t.Errorf("beads.db = %q, want the restored database laid over the clone", data)
+	}
+	if _, err := os.Stat(filepath.Join(path, "main.go")); err != nil {
+		t.Errorf("clone contents missing: %v", err)
+	}
+	if _, err := os.Stat(path + ".restore"); !os.IsNotExist(err) {
+		t.Errorf("aside directory left behind: %v", err)
+	}
+
+	// An existing checkout is left alone
+	created, err = restoreCheckout(path, func() error { t.Fatal("should not clone again"); return nil })
+	if err != nil || created {
+		t.Errorf("second restoreCheckout = %v, %v; want untouched", created, err)
+	}
+}
+
+func TestRestoreCheckoutFailurePutsStateBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rig")
+	restoredDB := filepath.Join(path, ".beads", "beads.db")
+	if err := os.MkdirAll(filepath.Dir(restoredDB), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(restoredDB, []byte("restored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cloneErr := errors.New("remote unreachable")
+	if _, err := restoreCheckout(path, func() error { return cloneErr }); !errors.Is(err, cloneErr) {
+		t.Fatalf("err = %v, want the clone error", err)
+	}
+	if data, _ := os.ReadFile(restoredDB); string(data) != "restored" {
+		t.Errorf("restored state lost after a failed clone: %q", data)
+	}
+}
@@ -0,0 +1,215 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// RepoSync reports how a sync left one of a rig's repositories.
+type RepoSync struct {
+	// Path is the repository, relative to the rig (.repo.git, mayor/rig).
+	Path string `json:"path"`
+
+	// Pruned lists the remote's tracking branches removed because their
+	// branch was deleted there.
+	Pruned []string `json:"pruned,omitempty"`
+
+	// Updated is true when the default branch was fast-forwarded.
+	Updated bool `json:"updated,omitempty"`
+
+	// Ahead and Behind count the commits the default branch has that the
+	// remote's doesn't, and the reverse, after the sync.
+	Ahead  int `json:"ahead,omitempty"`
+	Behind int `json:"behind,omitempty"`
+
+	// Skipped says why a default branch behind the remote wasn't
+	// fast-forwarded.
+	Skipped string `json:"skipped,omitempty"`
+
+	// Error is why the repository couldn't be synced at all.
+	Error string `json:"error,omitempty"`
+}
+
+// Diverged reports whether the default branch and the remote's each have
+// commits the other lacks, so it can't be fast-forwarded.
+func (s RepoSync) Diverged() bool {
+	return s.Ahead > 0 && s.Behind > 0
+}
+
+// SyncResult reports a rig's sync.
+type SyncResult struct {
+	Rig    string     `json:"rig"`
+	Remote string     `json:"remote"` // the push remote, usually origin
+	Branch string     `json:"branch"`
+	Repos  []RepoSync `json:"repos"`
+
+	// Detached lists the rig's checkouts whose HEAD is a commit rather
+	// than a branch, relative to the rig.
+	Detached []string `json:"detached,omitempty"`
+}
+
+// NeedsAttention reports whether the sync left anything for a human: a
+// repository that failed to sync, diverged from the remote or couldn't be
+// fast-forwarded, or a detached checkout.
+func (r *SyncResult) NeedsAttention() bool {
+	if len(r.Detached) > 0 {
+		return true
+	}
+	for _, repo := range r.Repos {
+		if repo.Error != "" || repo.Skipped != "" || repo.Diverged() {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync brings a rig's clones up to date: it fetches into the shared bare
+// repo and the mayor clone, pruning branches deleted upstream, and
+// fast-forwards the default branch in each to the rig's push remote (the
+// remote the refinery lands merges on, usually origin). A default branch
+// checked out with uncommitted changes, or one that has diverged from the
+// remote, is left alone and reported. Polecat and crew checkouts are not
+// touched, but worktrees of the shared repo left on a detached HEAD are
+// reported.
+//
+// Stale clones are a common source of spurious refinery conflicts.
+func Sync(rigPath string) *SyncResult {
+	r := &Rig{Path: rigPath}
+	remote, branch := r.PushRemote(), r.DefaultBranch()
+	result := &SyncResult{Rig: filepath.Base(rigPath), Remote: remote, Branch: branch}
+
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); err == nil {
+		bare := git.NewGitWithDir(bareRepoPath, "")
+		worktrees, err := bare.WorktreeList()
+		repo := RepoSync{Path: ".repo.git"}
+		if err != nil {
+			repo.Error = fmt.Sprintf("listing worktrees: %v", err)
+		} else {
+			// The default branch is usually checked out in refinery/rig
+			checkout := ""
+			for _, wt := range worktrees {
+				if wt.Branch == branch {
+					checkout = wt.Path
+				}
+				if wt.Detached {
+					if rel, err := filepath.Rel(rigPath, wt.Path); err == nil && !strings.HasPrefix(rel, "..") {
+						result.Detached = append(result.Detached, filepath.ToSlash(rel))
+					}
+				}
+			}
+			repo = syncRepo(bare, ".repo.git", remote, branch, checkout)
+		}
+		result.Repos = append(result.Repos, repo)
+	}
+
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(filepath.Join(mayorRigPath, ".git")); err == nil {
+		mayor := git.NewGit(mayorRigPath)
+		current, err := mayor.CurrentBranch()
+		if err != nil {
+			result.Repos = append(result.Repos, RepoSync{Path: "mayor/rig", Error: fmt.Sprintf("reading HEAD: %v", err)})
+		} else {
+			if current == "HEAD" {
+				result.Detached = append(result.Detached, "mayor/rig")
+			}
+			checkout := ""
+			if current == branch {
+				checkout = mayorRigPath
+			}
+			result.Repos = append(result.Repos, syncRepo(mayor, "mayor/rig", remote, branch, checkout))
+		}
+	}
+
+	return result
+}
+
+// syncRepo fetches into a repository and fast-forwards its branch to the
+// remote's. checkout is the worktree with branch checked out, if any.
+func syncRepo(g *git.Git, path, remote, branch, checkout string) RepoSync {
+	repo := RepoSync{Path: path}
+
+	if remote != OriginRemote {
+		// Workers still start from origin
+		if err := g.FetchPrune(OriginRemote); err != nil {
+			repo.Error = fmt.Sprintf("fetching origin: %v", err)
+			return repo
+		}
+	}
+	before, err := g.ListRemoteBranches(remote)
+	if err != nil {
+		repo.Error = fmt.Sprintf("listing remote branches: %v", err)
+		return repo
+	}
+	if err := g.FetchPrune(remote); err != nil {
+		repo.Error = fmt.Sprintf("fetching %s: %v", remote, err)
+		return repo
+	}
+	after, err := g.ListRemoteBranches(remote)
+	if err != nil {
+		repo.Error = fmt.Sprintf("listing remote branches: %v", err)
+		return repo
+	}
+	kept := make(map[string]bool, len(after))
+	for _, b := range after {
+		kept[b] = true
+	}
+	for _, b := range before {
+		if !kept[b] {
+			repo.Pruned = append(repo.Pruned, b)
+		}
+	}
+
+	local, upstream := "refs/heads/"+branch, "refs/remotes/"+remote+"/"+branch
+	if !kept[branch] {
+		repo.Error = fmt.Sprintf("%s has no %s branch", remote, branch)
+		return repo
+	}
+	if _, err := g.Rev(local); err != nil {
+		// Nothing to fast-forward
+		return repo
+	}
+	if repo.Ahead, err = g.CommitsAhead(upstream, local); err == nil {
+		repo.Behind, err = g.CommitsAhead(local, upstream)
+	}
+	if err != nil {
+		repo.Error = fmt.Sprintf("comparing %s with %s: %v", branch, remote, err)
+		return repo
+	}
+	if repo.Behind == 0 || repo.Ahead > 0 {
+		return repo
+	}
+
+	if checkout == "" {
+		target, err := g.Rev(upstream)
+		if err == nil {
+			err = g.UpdateRef(local, target)
+		}
+		if err != nil {
+			repo.Error = fmt.Sprintf("fast-forwarding %s: %v", branch, err)
+			return repo
+		}
+	} else {
+		wt := git.NewGit(checkout)
+		status, err := wt.Status()
+		if err != nil {
+			repo.Error = fmt.Sprintf("reading status of %s: %v", checkout, err)
+			return repo
+		}
+		if len(status.Modified)+len(status.Added)+len(status.Deleted) > 0 {
+			repo.Skipped = "uncommitted changes in " + checkout
+			return repo
+		}
+		if err := wt.MergeFFOnly(remote + "/" + branch); err != nil {
+			repo.Skipped = fmt.Sprintf("fast-forward failed in %s: %v", checkout, err)
+			return repo
+		}
+	}
+	repo.Updated = true
+	repo.Behind = 0
+	return repo
+}
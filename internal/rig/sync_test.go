@@ -0,0 +1,112 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func syncGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupSyncRig makes a rig laid out as gt rig add does, cloned from a
+// working repo standing in for origin.
+func setupSyncRig(t *testing.T) (rigPath, origin string) {
+	t.Helper()
+	root := t.TempDir()
+	origin = filepath.Join(root, "origin")
+	rigPath = filepath.Join(root, "gastown")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	syncGit(t, origin, "init", "-q", "-b", "main")
+	syncGit(t, origin, "commit", "-q", "--allow-empty", "-m", "initial")
+	syncGit(t, origin, "branch", "polecat/gone")
+
+	g := git.NewGit(root)
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if err := g.CloneBare(origin, bareRepoPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := git.NewGitWithDir(bareRepoPath, "").WorktreeAddExisting(filepath.Join(rigPath, "refinery", "rig"), "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Clone(origin, filepath.Join(rigPath, "mayor", "rig")); err != nil {
+		t.Fatal(err)
+	}
+	return rigPath, origin
+}
+
+func TestSync(t *testing.T) {
+	rigPath, origin := setupSyncRig(t)
+	refineryRig := filepath.Join(rigPath, "refinery", "rig")
+	mayorRig := filepath.Join(rigPath, "mayor", "rig")
+
+	syncGit(t, origin, "commit", "-q", "--allow-empty", "-m", "upstream")
+	syncGit(t, origin, "branch", "-D", "polecat/gone")
+	want := syncGit(t, origin, "rev-parse", "main")
+
+	result := Sync(rigPath)
+	if result.Branch != "main" || len(result.Repos) != 2 {
+		t.Fatalf("Sync = %+v", result)
+	}
+	for _, repo := range result.Repos {
+		if repo.Error != "" || !repo.Updated || repo.Behind != 0 {
+			t.Errorf("%s: %+v, want fast-forwarded", repo.Path, repo)
+		}
+		if len(repo.Pruned) != 1 || repo.Pruned[0] != "polecat/gone" {
+			t.Errorf("%s pruned %v, want [polecat/gone]", repo.Path, repo.Pruned)
+		}
+	}
+	if result.NeedsAttention() {
+		t.Errorf("clean sync needs attention: %+v", result)
+	}
+	for _, dir := range []string{refineryRig, mayorRig} {
+		if got := syncGit(t, dir, "rev-parse", "HEAD"); got != want {
+			t.Errorf("%s HEAD = %s, want origin's %s", dir, got, want)
+		}
+	}
+
+	// A diverged mayor, a dirty refinery and a detached worktree are reported
+	syncGit(t, mayorRig, "commit", "-q", "--allow-empty", "-m", "local only")
+	if err := os.WriteFile(filepath.Join(origin, "a.txt"), []byte("upstream"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	syncGit(t, origin, "add", "a.txt")
+	syncGit(t, origin, "commit", "-q", "-m", "add a")
+	if err := os.WriteFile(filepath.Join(refineryRig, "b.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	syncGit(t, refineryRig, "add", "b.txt")
+	syncGit(t, refineryRig, "worktree", "add", "-q", "--detach", filepath.Join(rigPath, "polecats", "toast"), "HEAD")
+
+	result = Sync(rigPath)
+	if !result.NeedsAttention() {
+		t.Fatalf("Sync = %+v, want attention needed", result)
+	}
+	bare, mayor := result.Repos[0], result.Repos[1]
+	if bare.Updated || bare.Behind != 1 || !strings.Contains(bare.Skipped, "uncommitted changes") {
+		t.Errorf(".repo.git = %+v, want skipped for uncommitted changes", bare)
+	}
+	if !mayor.Diverged() || mayor.Ahead != 1 || mayor.Behind != 1 {
+		t.Errorf("mayor/rig = %+v, want diverged", mayor)
+	}
+	if len(result.Detached) != 1 || result.Detached[0] != "polecats/toast" {
+		t.Errorf("detached = %v, want [polecats/toast]", result.Detached)
+	}
+}
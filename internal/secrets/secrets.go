@@ -0,0 +1,339 @@
+// Package secrets stores integration credentials (forge tokens, webhook
+// URLs, notifier passwords) encrypted at rest under the town.
+//
+// Values are sealed with AES-256-GCM into <town>/settings/secrets.json. The
+// key lives outside the town, in ~/.config/gastown/secret.key (or the
+// GT_SECRET_KEY environment variable), so copying or committing the town
+// never exposes plaintext. Config files refer to a secret as "secret:<name>"
+// and callers resolve the reference when the value is needed.
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// RefPrefix marks a config value as a reference to a stored secret.
+const RefPrefix = "secret:"
+
+// EnvKey holds a base64-encoded 32-byte key, overriding the key file.
+const EnvKey = "GT_SECRET_KEY"
+
+// CurrentVersion is the secrets file schema version.
+const CurrentVersion = 1
+
+// keySize is the AES-256 key length.
+const keySize = 32
+
+// lockTimeout bounds the wait for another gt process changing the store.
+const lockTimeout = 10 * time.Second
+
+// ErrNotFound is returned for a secret name that is not stored.
+var ErrNotFound = errors.New("secret not found")
+
+// ErrNoKey is returned when secrets exist but no key is available to open them.
+var ErrNoKey = errors.New("no secret key")
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Entry is one sealed secret.
+type Entry struct {
+	Nonce      string    `json:"nonce"`
+	Ciphertext string    `json:"ciphertext"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// File is the on-disk secrets store.
+type File struct {
+	Version int              `json:"version"`
+	Secrets map[string]Entry `json:"secrets"`
+}
+
+// Info describes a stored secret without its value.
+type Info struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Path returns the secrets file for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "secrets.json")
+}
+
+// KeyPath returns the machine key file.
+func KeyPath() string {
+	return filepath.Join(state.ConfigDir(), "secret.key")
+}
+
+// ValidateName checks that name is usable as a secret name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q (letters, digits, '.', '_', '-')", name)
+	}
+	return nil
+}
+
+// IsRef reports whether a config value refers to a stored secret.
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// Set encrypts and stores value under name, creating the machine key when
+// the store is empty and no key exists yet.
+func Set(townRoot, name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return update(townRoot, func(f *File) error {
+		// Only an empty store may get a fresh key; otherwise existing
+		// secrets would silently become unreadable.
+		key, err := loadKey(len(f.Secrets) == 0)
+		if err != nil {
+			return err
+		}
+		entry, err := seal(key, name, value)
+		if err != nil {
+			return err
+		}
+		f.Secrets[name] = entry
+		return nil
+	})
+}
+
+// Get decrypts the secret stored under name.
+func Get(townRoot, name string) (string, error) {
+	f, err := load(townRoot)
+	if err != nil {
+		return "", err
+	}
+	entry, ok := f.Secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	key, err := loadKey(false)
+	if err != nil {
+		return "", err
+	}
+	return open(key, name, entry)
+}
+
+// Remove deletes the secret stored under name.
+func Remove(townRoot, name string) error {
+	return update(townRoot, func(f *File) error {
+		if _, ok := f.Secrets[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		delete(f.Secrets, name)
+		return nil
+	})
+}
+
+// List returns the stored secret names, sorted.
+func List(townRoot string) ([]Info, error) {
+	f, err := load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(f.Secrets))
+	for name, entry := range f.Secrets {
+		infos = append(infos, Info{Name: name, UpdatedAt: entry.UpdatedAt})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Resolve returns value unchanged unless it is a "secret:<name>" reference,
+// in which case the stored secret is returned.
+func Resolve(townRoot, value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	return Get(townRoot, strings.TrimPrefix(value, RefPrefix))
+}
+
+// seal encrypts value, binding it to name so sealed values cannot be
+// swapped between names.
+func seal(key []byte, name, value string) (Entry, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Entry{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Entry{}, fmt.Errorf("generating nonce: %w", err)
+	}
+	ciphertext := gcm.Seal(nil, nonce, []byte(value), []byte(name))
+	return Entry{
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+		UpdatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func open(key []byte, name string, entry Entry) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(entry.Nonce)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: malformed nonce", name)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(entry.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: malformed ciphertext", name)
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return "", fmt.Errorf("secret %s: malformed nonce", name)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("secret %s: cannot decrypt (wrong key or tampered value)", name)
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadKey returns the key from GT_SECRET_KEY or the key file. With create,
+// a missing key file is generated.
+func loadKey(create bool) ([]byte, error) {
+	if encoded := os.Getenv(EnvKey); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%s must be a base64-encoded %d-byte key", EnvKey, keySize)
+		}
+		return key, nil
+	}
+
+	path := KeyPath()
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%s is not a valid secret key", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading secret key: %w", err)
+	}
+	if !create {
+		return nil, fmt.Errorf("%w: %s does not exist and %s is not set", ErrNoKey, path, EnvKey)
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secret key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating config directory: %w", err)
+	}
+	// Never replace a key another gt created meanwhile: secrets sealed with
+	// it would become unreadable. Whoever loses the race uses the winner's.
+	kf, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrExist) {
+		return loadKey(false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing secret key: %w", err)
+	}
+	if _, err := kf.Write([]byte(base64.StdEncoding.EncodeToString(key) + "\n")); err != nil {
+		_ = kf.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("writing secret key: %w", err)
+	}
+	if err := kf.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("writing secret key: %w", err)
+	}
+	return key, nil
+}
+
+// update changes the store under its lock, from its read to its write, so
+// two processes setting secrets at once don't lose each other's. Nothing is
+// written if fn fails.
+func update(townRoot string, fn func(*File) error) error {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("locking secrets: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("locking secrets: %s held by another gt for over %v", path, lockTimeout)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := load(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		return err
+	}
+	return save(townRoot, f)
+}
+
+func load(townRoot string) (*File, error) {
+	f := &File{Version: CurrentVersion, Secrets: map[string]Entry{}}
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parsing secrets: %w", err)
+	}
+	if f.Version > CurrentVersion {
+		return nil, fmt.Errorf("secrets file version %d is newer than supported (%d)", f.Version, CurrentVersion)
+	}
+	if f.Secrets == nil {
+		f.Secrets = map[string]Entry{}
+	}
+	return f, nil
+}
+
+func save(townRoot string, f *File) error {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding secrets: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing secrets: %w", err)
+	}
+	return nil
+}
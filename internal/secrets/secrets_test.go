@@ -0,0 +1,176 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func setupSecrets(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvKey, "")
+	return t.TempDir()
+}
+
+func TestSetGetRoundTrip(t *testing.T) {
+	townRoot := setupSecrets(t)
+
+	if err := Set(townRoot, "slack-webhook", "https://hooks.example/T0/B0/xyz"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := Get(townRoot, "slack-webhook")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "https://hooks.example/T0/B0/xyz" {
+		t.Errorf("Get = %q", got)
+	}
+
+	// Plaintext never reaches the town
+	data, _ := os.ReadFile(Path(townRoot))
+	if strings.Contains(string(data), "hooks.example") {
+		t.Error("secrets file contains plaintext")
+	}
+	if info, err := os.Stat(KeyPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file = %v, %v; want mode 0600", info, err)
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	townRoot := setupSecrets(t)
+	if _, err := Get(townRoot, "nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetWithoutKey(t *testing.T) {
+	townRoot := setupSecrets(t)
+	if err := Set(townRoot, "token", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(KeyPath()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Get(townRoot, "token"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Get without key = %v, want ErrNoKey", err)
+	}
+	// A lost key must not be silently replaced while secrets exist
+	if err := Set(townRoot, "other", "xyz"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Set without key = %v, want ErrNoKey", err)
+	}
+}
+
+func TestWrongKeyAndSwappedValues(t *testing.T) {
+	townRoot := setupSecrets(t)
+	if err := Set(townRoot, "a", "alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(townRoot, "b", "bravo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swapping sealed values between names is detected
+	f, _ := load(townRoot)
+	f.Secrets["a"], f.Secrets["b"] = f.Secrets["b"], f.Secrets["a"]
+	if err := save(townRoot, f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(townRoot, "a"); err == nil {
+		t.Error("Get of swapped value should fail")
+	}
+
+	// A different key cannot decrypt
+	t.Setenv(EnvKey, base64.StdEncoding.EncodeToString(make([]byte, keySize)))
+	if _, err := Get(townRoot, "b"); err == nil {
+		t.Error("Get with wrong key should fail")
+	}
+
+	t.Setenv(EnvKey, "short")
+	if _, err := Get(townRoot, "b"); err == nil || !strings.Contains(err.Error(), EnvKey) {
+		t.Errorf("invalid %s = %v", EnvKey, err)
+	}
+}
+
+func TestSetConcurrent(t *testing.T) {
+	townRoot := setupSecrets(t)
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	// Racing first Sets must agree on one key and keep every secret
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := Set(townRoot, name, "v-"+name); err != nil {
+				t.Errorf("Set(%s): %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	for _, name := range names {
+		if got, err := Get(townRoot, name); err != nil || got != "v-"+name {
+			t.Errorf("Get(%s) = %q, %v", name, got, err)
+		}
+	}
+}
+
+func TestListAndRemove(t *testing.T) {
+	townRoot := setupSecrets(t)
+	for _, name := range []string{"github-token", "slack-webhook"} {
+		if err := Set(townRoot, name, "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := List(townRoot)
+	if err != nil || len(infos) != 2 || infos[0].Name != "github-token" {
+		t.Fatalf("List = %v, %v", infos, err)
+	}
+
+	if err := Remove(townRoot, "github-token"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := Remove(townRoot, "github-token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Remove = %v, want ErrNotFound", err)
+	}
+	infos, _ = List(townRoot)
+	if len(infos) != 1 {
+		t.Errorf("List after Remove = %v", infos)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	townRoot := setupSecrets(t)
+	if err := Set(townRoot, "slack-webhook", "https://hooks.example/x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := Resolve(townRoot, "secret:slack-webhook"); err != nil || got != "https://hooks.example/x" {
+		t.Errorf("Resolve ref = %q, %v", got, err)
+	}
+	if got, err := Resolve(townRoot, "https://plain.example"); err != nil || got != "https://plain.example" {
+		t.Errorf("Resolve plain = %q, %v", got, err)
+	}
+	if _, err := Resolve(townRoot, "secret:missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve missing = %v, want ErrNotFound", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"github-token", "slack.webhook", "A_1"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-lead", "has space", "a/b"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+}
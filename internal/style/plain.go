@@ -0,0 +1,208 @@
+package style
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// plain is whether plain output is on (see SetPlain).
+var plain bool
+
+// Plain reports whether plain output is on.
+func Plain() bool {
+	return plain
+}
+
+// SetPlain turns plain output on: styles render unstyled from then on.
+// Text written through a PlainWriter also loses its icons and emoji.
+func SetPlain() {
+	plain = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// PlainFromEnv reports whether the environment asks for plain output:
+// GT_PLAIN is set, or CI is, as GitHub Actions, GitLab CI and most other
+// CI systems do. NO_COLOR alone only turns off color (see ui.ShouldUseColor).
+func PlainFromEnv() bool {
+	if _, ok := os.LookupEnv("GT_PLAIN"); ok {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("CI")) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// plainReplacements are the ASCII stand-ins for the symbols gt prints.
+// Icons keep their width so tables stay aligned; other symbols and emoji
+// are dropped.
+var plainReplacements = map[rune]string{
+	'✓': "+", '✔': "+", '✅': "+",
+	'✗': "x", '✖': "x", '❌': "x", '×': "x",
+	'⚠': "!", 'ℹ': "i",
+	'○': "o", '◌': "o", '●': "*", '•': "*", '·': ".",
+	'▶': ">", '▸': ">", '❯': ">", '▼': "v",
+	'→': "->", '←': "<-", '↔': "<->", '↑': "^", '↓': "v",
+	'─': "-", '━': "-", '═': "=", '—': "-",
+	'│': "|", '║': "|",
+	'┌': "+", '┐': "+", '└': "+", '┘': "+", '├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'█': "#", '░': ".",
+	'…': "...",
+}
+
+// PlainText rewrites s for plain output: ANSI escape sequences are
+// removed, the symbols gt uses for status are replaced by ASCII (✓ → +,
+// ✗ → x, ⚠ → !, → → ->), and other emoji are dropped along with the space
+// that followed them.
+func PlainText(s string) string {
+	var sb strings.Builder
+	state := plainState{prev: ' '}
+	state.write(&sb, s)
+	return sb.String()
+}
+
+// plainState carries PlainText's context from one piece of text to the
+// next, so an emoji that starts a word can take its space with it.
+type plainState struct {
+	prev      byte // last byte written
+	skipSpace bool // a word-starting emoji was just dropped
+}
+
+// write writes s as plain text to sb.
+func (st *plainState) write(sb *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		if st.skipSpace {
+			st.skipSpace = false
+			if s[i] == ' ' {
+				i++
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r < utf8.RuneSelf {
+			sb.WriteRune(r)
+			st.prev = byte(r)
+			continue
+		}
+		if repl, ok := plainReplacements[r]; ok {
+			sb.WriteString(repl)
+			st.prev = repl[len(repl)-1]
+			continue
+		}
+		if !isEmoji(r) {
+			sb.WriteRune(r)
+			st.prev = 0
+			continue
+		}
+		// Drop the emoji, and the space after it if it began a word
+		st.skipSpace = st.prev == ' ' || st.prev == '\n' || st.prev == '\t'
+	}
+}
+
+// isEmoji reports whether r is an emoji or another pictographic symbol,
+// or one of the joiners and selectors that build emoji sequences.
+func isEmoji(r rune) bool {
+	return unicode.Is(unicode.So, r) || r == '\u200d' || r == '\ufe0f' || r == '\ufe0e'
+}
+
+// escapeLen returns the length of the ANSI escape sequence s starts with,
+// or 0 if it doesn't start with a complete one.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	switch s[1] {
+	case '[': // CSI: parameters, then a final byte in @-~
+		for i := 2; i < len(s); i++ {
+			if s[i] >= '@' && s[i] <= '~' {
+				return i + 1
+			}
+		}
+	case ']': // OSC: up to BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return 0
+}
+
+// PlainWriter writes everything written to it to an underlying writer as
+// PlainText. A rune or escape sequence split across writes is held back
+// until it's complete; Flush writes whatever is held.
+type PlainWriter struct {
+	w       io.Writer
+	pending []byte
+	state   plainState
+}
+
+// NewPlainWriter returns a PlainWriter that writes to w.
+func NewPlainWriter(w io.Writer) *PlainWriter {
+	return &PlainWriter{w: w, state: plainState{prev: '\n'}}
+}
+
+// Write converts p to plain text and writes it, holding back an incomplete
+// tail.
+func (pw *PlainWriter) Write(p []byte) (int, error) {
+	buf := append(pw.pending, p...)
+	cut := completePrefix(buf)
+	pw.pending = append([]byte(nil), buf[cut:]...)
+	if err := pw.write(string(buf[:cut])); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush converts and writes whatever is held back.
+func (pw *PlainWriter) Flush() error {
+	s := string(pw.pending)
+	pw.pending = nil
+	return pw.write(s)
+}
+
+func (pw *PlainWriter) write(s string) error {
+	if s == "" {
+		return nil
+	}
+	var sb strings.Builder
+	pw.state.write(&sb, s)
+	_, err := io.WriteString(pw.w, sb.String())
+	return err
+}
+
+// completePrefix returns how much of buf can be converted now: all of it
+// unless it ends partway through a rune or an escape sequence.
+func completePrefix(buf []byte) int {
+	// An unterminated escape sequence near the end
+	if i := strings.LastIndexByte(string(buf), '\x1b'); i >= 0 && len(buf)-i < 64 && escapeLen(string(buf[i:])) == 0 {
+		return i
+	}
+	// A rune cut short
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if !utf8.FullRune(buf[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(buf)
+}
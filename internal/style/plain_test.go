@@ -0,0 +1,59 @@
+package style
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"\x1b[1;32m✓\x1b[0m Backed up", "+ Backed up"},
+		{"📋 Merge queue for 'greenplace':", "Merge queue for 'greenplace':"},
+		{"  🚚 Slung gp-1 → nux", "  Slung gp-1 -> nux"},
+		{"⚠️ Warning: stale", "! Warning: stale"},
+		{"\x1b[1m📋\x1b[0m Queue", "Queue"},
+		{"──────", "------"},
+		{"café 👷‍♀️", "café "},
+		{"\x1b]8;;https://example.com\x07link\x1b]8;;\x07", "link"},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.in); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlainWriterSplitWrites(t *testing.T) {
+	var buf bytes.Buffer
+	pw := NewPlainWriter(&buf)
+	in := []byte("\x1b[32m✓\x1b[0m done\n📋 queue\n")
+	// One byte at a time splits every rune and escape sequence
+	for i := range in {
+		if _, err := pw.Write(in[i : i+1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "+ done\nqueue\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPlainFromEnv(t *testing.T) {
+	t.Setenv("CI", "")
+	if PlainFromEnv() {
+		t.Error("plain without CI or GT_PLAIN")
+	}
+	t.Setenv("CI", "false")
+	if PlainFromEnv() {
+		t.Error("CI=false should not turn on plain output")
+	}
+	t.Setenv("CI", "true")
+	if !PlainFromEnv() {
+		t.Error("CI=true should turn on plain output")
+	}
+}
@@ -73,8 +73,17 @@ func (t *Table) Render() string {
 	}
 
 	var sb strings.Builder
+	sb.WriteString(t.RenderHeader())
+	for _, row := range t.rows {
+		sb.WriteString(t.RenderRow(row...))
+	}
+	return sb.String()
+}
 
-	// Render header
+// RenderHeader returns the table's header, with its separator line. With
+// RenderRow, it lets a table be printed a row at a time, as rows are made.
+func (t *Table) RenderHeader() string {
+	var sb strings.Builder
 	sb.WriteString(t.indent)
 	for i, col := range t.columns {
 		text := t.headerStyle.Render(col.Name)
@@ -98,32 +107,33 @@ func (t *Table) Render() string {
 		sb.WriteString(Dim.Render(strings.Repeat("─", totalWidth)))
 		sb.WriteString("\n")
 	}
+	return sb.String()
+}
 
-	// Render rows
-	for _, row := range t.rows {
-		sb.WriteString(t.indent)
-		for i, col := range t.columns {
-			val := ""
-			if i < len(row) {
-				val = row[i]
-			}
-			// Truncate if too long
-			plainVal := stripAnsi(val)
-			if len(plainVal) > col.Width {
-				val = plainVal[:col.Width-3] + "..."
-			}
-			// Apply column style if set
-			if col.Style.Value() != "" {
-				val = col.Style.Render(val)
-			}
-			sb.WriteString(t.pad(val, plainVal, col.Width, col.Align))
-			if i < len(t.columns)-1 {
-				sb.WriteString(" ")
-			}
+// RenderRow returns one formatted row, without adding it to the table.
+func (t *Table) RenderRow(row ...string) string {
+	var sb strings.Builder
+	sb.WriteString(t.indent)
+	for i, col := range t.columns {
+		val := ""
+		if i < len(row) {
+			val = row[i]
+		}
+		// Truncate if too long
+		plainVal := stripAnsi(val)
+		if len(plainVal) > col.Width {
+			val = plainVal[:col.Width-3] + "..."
+		}
+		// Apply column style if set
+		if col.Style.Value() != "" {
+			val = col.Style.Render(val)
+		}
+		sb.WriteString(t.pad(val, plainVal, col.Width, col.Align))
+		if i < len(t.columns)-1 {
+			sb.WriteString(" ")
 		}
-		sb.WriteString("\n")
 	}
-
+	sb.WriteString("\n")
 	return sb.String()
 }
 
@@ -0,0 +1,206 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ProcessBackend runs each session as a plain detached subprocess with its
+// output sent to a log file. It needs nothing but the system shell (sh, or
+// cmd on Windows without one), which makes it the backend for headless CI,
+// containers, and Windows. Sessions have no terminal, so SendText and Attach are
+// unsupported.
+//
+// State lives under <town>/.runtime/sessions/<name>/: pid, output.log, and
+// env (variables recorded by SetEnvironment, one KEY=value per line).
+type ProcessBackend struct {
+	dir string
+}
+
+// NewProcessBackend returns the subprocess backend for a town.
+func NewProcessBackend(townRoot string) *ProcessBackend {
+	return &ProcessBackend{dir: filepath.Join(townRoot, constants.DirRuntime, "sessions")}
+}
+
+// Name returns "process".
+func (b *ProcessBackend) Name() string { return BackendProcess }
+
+// IsAvailable reports whether the system shell is installed.
+func (b *ProcessBackend) IsAvailable() bool {
+	_, err := exec.LookPath(shell)
+	return err == nil
+}
+
+func (b *ProcessBackend) sessionDir(name string) string {
+	return filepath.Join(b.dir, name)
+}
+
+// LogPath returns the file a session's output is written to.
+func (b *ProcessBackend) LogPath(name string) string {
+	return filepath.Join(b.sessionDir(name), "output.log")
+}
+
+// NewSession starts command via the system shell in its own process group,
+// detached from the caller, with stdout and stderr appended to the session
+// log.
+// Variables recorded with SetEnvironment before the session starts are
+// passed to it.
+func (b *ProcessBackend) NewSession(name, workDir, command string) error {
+	if name == "" || name != filepath.Base(name) {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	if ok, _ := b.HasSession(name); ok {
+		return fmt.Errorf("session already exists: %s", name)
+	}
+
+	dir := b.sessionDir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating session dir: %w", err)
+	}
+	logFile, err := os.OpenFile(b.LogPath(name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening session log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := shellCommand(command)
+	cmd.Dir = workDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = append(os.Environ(), b.readEnv(name)...)
+	setDetached(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting session %s: %w", name, err)
+	}
+
+	pid := cmd.Process.Pid
+	if err := os.WriteFile(filepath.Join(dir, "pid"), []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		_ = killGroup(pid)
+		return fmt.Errorf("writing session pid: %w", err)
+	}
+	// Reap the child in the background so it does not linger as a zombie
+	// while this process is alive.
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// readPID returns the session's recorded pid, or 0 if there is none.
+func (b *ProcessBackend) readPID(name string) int {
+	data, err := os.ReadFile(filepath.Join(b.sessionDir(name), "pid"))
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// HasSession reports whether the session's process is still running.
+func (b *ProcessBackend) HasSession(name string) (bool, error) {
+	pid := b.readPID(name)
+	return pid > 0 && processAlive(pid), nil
+}
+
+// KillSession kills the session's process group and removes its pid file.
+// The log is kept for post-mortems.
+func (b *ProcessBackend) KillSession(name string) error {
+	pid := b.readPID(name)
+	if pid <= 0 || !processAlive(pid) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	if err := killGroup(pid); err != nil {
+		return fmt.Errorf("killing session %s: %w", name, err)
+	}
+	_ = os.Remove(filepath.Join(b.sessionDir(name), "pid"))
+	return nil
+}
+
+// ListSessions returns the sessions whose processes are running.
+func (b *ProcessBackend) ListSessions() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if ok, _ := b.HasSession(e.Name()); ok {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetEnvironment records a variable for the session. Like tmux, it affects
+// only processes started afterwards, which here means the next NewSession
+// with this name.
+func (b *ProcessBackend) SetEnvironment(name, key, value string) error {
+	if strings.ContainsAny(key, "=\n") || strings.Contains(value, "\n") {
+		return fmt.Errorf("invalid environment variable %q", key)
+	}
+	dir := b.sessionDir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var env []string
+	for _, kv := range b.readEnv(name) {
+		if !strings.HasPrefix(kv, key+"=") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, key+"="+value)
+	return os.WriteFile(filepath.Join(dir, "env"), []byte(strings.Join(env, "\n")+"\n"), 0600)
+}
+
+// readEnv returns the KEY=value pairs recorded for the session.
+func (b *ProcessBackend) readEnv(name string) []string {
+	data, err := os.ReadFile(filepath.Join(b.sessionDir(name), "env"))
+	if err != nil {
+		return nil
+	}
+	var env []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "=") {
+			env = append(env, line)
+		}
+	}
+	return env
+}
+
+// SendText is unsupported: subprocess sessions have no terminal to type into.
+func (b *ProcessBackend) SendText(name, text string) error {
+	return fmt.Errorf("sending text to %s: %w", name, ErrUnsupported)
+}
+
+// Capture returns the last lines of the session log.
+func (b *ProcessBackend) Capture(name string, lines int) (string, error) {
+	data, err := os.ReadFile(b.LogPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+		}
+		return "", err
+	}
+	return lastLines(string(data), lines), nil
+}
+
+// Attach is unsupported; follow the session with tail -f on LogPath instead.
+func (b *ProcessBackend) Attach(name string, readOnly bool) error {
+	return fmt.Errorf("attaching to %s (see %s): %w", name, b.LogPath(name), ErrUnsupported)
+}
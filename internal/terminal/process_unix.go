@@ -0,0 +1,32 @@
+//go:build unix
+
+package terminal
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shell runs session commands.
+const shell = "sh"
+
+// shellCommand returns a command that runs command through the shell.
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command(shell, "-c", command)
+}
+
+// setDetached starts the command in a new session so it survives the
+// caller and has no controlling terminal.
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive checks if a process is still running.
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, syscall.Signal(0)) == nil
+}
+
+// killGroup sends SIGKILL to the process group led by pid.
+func killGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
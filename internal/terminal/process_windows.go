@@ -0,0 +1,59 @@
+//go:build windows
+
+package terminal
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// shell runs session commands when no POSIX shell is installed.
+const shell = "cmd"
+
+// shellCommand returns a command that runs command through sh when one is
+// on PATH (Git for Windows, MSYS2), since agent startup commands are written
+// for a POSIX shell, and through cmd.exe otherwise. The cmd command line is
+// passed verbatim, since cmd does its own unquoting.
+func shellCommand(command string) *exec.Cmd {
+	if sh, err := exec.LookPath("sh"); err == nil {
+		return exec.Command(sh, "-c", command)
+	}
+	cmd := exec.Command(shell)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: "/C " + command}
+	return cmd
+}
+
+// setDetached starts the command in a new process group with no console
+// window, so it survives the caller closing its console.
+func setDetached(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP | windows.CREATE_NO_WINDOW
+}
+
+const processStillActive = 259
+
+// processAlive checks if a process is still running.
+func processAlive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return false
+	}
+	return exitCode == processStillActive
+}
+
+// killGroup kills the process and its descendants. Windows has no process
+// groups to signal, so this walks the tree with taskkill.
+func killGroup(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,147 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ScreenBackend hosts sessions in GNU screen, for machines with screen but
+// no tmux.
+type ScreenBackend struct{}
+
+// NewScreenBackend returns the GNU screen backend.
+func NewScreenBackend() *ScreenBackend {
+	return &ScreenBackend{}
+}
+
+// Name returns "screen".
+func (b *ScreenBackend) Name() string { return BackendScreen }
+
+// IsAvailable reports whether screen is installed.
+func (b *ScreenBackend) IsAvailable() bool {
+	_, err := exec.LookPath("screen")
+	return err == nil
+}
+
+// NewSession starts a detached screen session running command via sh -c.
+func (b *ScreenBackend) NewSession(name, workDir, command string) error {
+	if ok, _ := b.HasSession(name); ok {
+		return fmt.Errorf("session already exists: %s", name)
+	}
+	cmd := exec.Command("screen", "-dmS", name, "sh", "-c", command)
+	cmd.Dir = workDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("screen -dmS %s: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// HasSession reports whether a screen session with exactly this name runs.
+func (b *ScreenBackend) HasSession(name string) (bool, error) {
+	names, err := b.ListSessions()
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// KillSession quits the screen session.
+func (b *ScreenBackend) KillSession(name string) error {
+	if ok, err := b.HasSession(name); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	return b.command(name, "quit")
+}
+
+// screenListLine matches "\t12345.name\t(Detached)" in screen -ls output.
+var screenListLine = regexp.MustCompile(`^\s+\d+\.(\S+)\s`)
+
+// ListSessions returns the names of the user's screen sessions.
+func (b *ScreenBackend) ListSessions() ([]string, error) {
+	// screen -ls exits non-zero when there are no sessions (and on some
+	// versions even when there are), so parse the output regardless.
+	out, _ := exec.Command("screen", "-ls").CombinedOutput()
+	return parseScreenList(string(out)), nil
+}
+
+func parseScreenList(out string) []string {
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		if m := screenListLine.FindStringSubmatch(line); m != nil {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// SetEnvironment sets a variable for windows the session opens afterwards.
+func (b *ScreenBackend) SetEnvironment(name, key, value string) error {
+	return b.command(name, "setenv", key, value)
+}
+
+// SendText types text into the session and presses Enter.
+func (b *ScreenBackend) SendText(name, text string) error {
+	return b.command(name, "stuff", screenEscape(text)+"\\015")
+}
+
+// screenEscape escapes the characters screen's command parser interprets.
+func screenEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `^`, `\^`, `$`, `\$`).Replace(s)
+}
+
+// Capture returns the last lines of the session's scrollback.
+func (b *ScreenBackend) Capture(name string, lines int) (string, error) {
+	f, err := os.CreateTemp("", "gt-screen-*.txt")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	if err := b.command(name, "hardcopy", "-h", path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	return lastLines(string(data), lines), nil
+}
+
+// Attach reattaches the terminal to the session. screen has no read-only
+// client mode, so readOnly is unsupported.
+func (b *ScreenBackend) Attach(name string, readOnly bool) error {
+	if readOnly {
+		return fmt.Errorf("read-only attach: %w", ErrUnsupported)
+	}
+	cmd := exec.Command("screen", "-x", name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// command runs a screen command in the named session (screen -S name -X ...).
+func (b *ScreenBackend) command(name string, args ...string) error {
+	cmdArgs := append([]string{"-S", name, "-X"}, args...)
+	out, err := exec.Command("screen", cmdArgs...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("screen -X %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package terminal abstracts the program that hosts agent sessions.
+//
+// Gas Town runs every agent in a named, detached session that outlives the
+// command that started it. tmux is the default host; GNU screen and plain
+// detached subprocesses (for headless CI and containers, where no terminal
+// multiplexer is installed) implement the same Backend interface. The town
+// picks one with session_backend in settings/config.json, or GT_SESSION_BACKEND;
+// Windows defaults to plain subprocesses.
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Backend names.
+const (
+	BackendTmux    = "tmux"
+	BackendScreen  = "screen"
+	BackendProcess = "process"
+)
+
+// EnvBackend overrides the town's session_backend setting.
+const EnvBackend = "GT_SESSION_BACKEND"
+
+var (
+	// ErrUnsupported is returned for operations a backend cannot perform,
+	// such as typing into a session that has no terminal.
+	ErrUnsupported = errors.New("not supported by this session backend")
+
+	// ErrSessionNotFound is returned when the named session does not exist.
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// Backend hosts named, detached agent sessions.
+type Backend interface {
+	// Name returns the backend name (tmux, screen, process).
+	Name() string
+
+	// IsAvailable reports whether the backend can run on this machine.
+	IsAvailable() bool
+
+	// NewSession starts a detached session running command in workDir.
+	NewSession(name, workDir, command string) error
+
+	// HasSession reports whether the named session is running.
+	HasSession(name string) (bool, error)
+
+	// KillSession stops the session and the processes it started.
+	KillSession(name string) error
+
+	// ListSessions returns the names of running sessions.
+	ListSessions() ([]string, error)
+
+	// SetEnvironment records an environment variable on the session. As
+	// with tmux, it applies to processes the session starts afterwards.
+	SetEnvironment(name, key, value string) error
+
+	// SendText types text into the session and presses Enter.
+	SendText(name, text string) error
+
+	// Capture returns the last lines of the session's output.
+	Capture(name string, lines int) (string, error)
+
+	// Attach connects the current terminal to the session until detach.
+	Attach(name string, readOnly bool) error
+}
+
+// Names returns the known backend names.
+func Names() []string {
+	names := []string{BackendTmux, BackendScreen, BackendProcess}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultName returns the backend used when none is configured: tmux, or
+// process on Windows, where tmux only exists under WSL or Cygwin.
+func DefaultName() string {
+	if runtime.GOOS == "windows" {
+		return BackendProcess
+	}
+	return BackendTmux
+}
+
+// New returns the named backend. townRoot locates state for backends that
+// keep it on disk (process). An empty name selects DefaultName.
+func New(name, townRoot string) (Backend, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultName()
+	}
+	switch name {
+	case BackendTmux:
+		return NewTmuxBackend(), nil
+	case BackendScreen:
+		return NewScreenBackend(), nil
+	case BackendProcess:
+		if townRoot == "" {
+			return nil, fmt.Errorf("process session backend needs a town root")
+		}
+		return NewProcessBackend(townRoot), nil
+	}
+	return nil, fmt.Errorf("unknown session backend %q (valid: %s)", name, strings.Join(Names(), ", "))
+}
+
+// ForTown returns the backend configured for a town: GT_SESSION_BACKEND if
+// set, else session_backend from the town settings, else DefaultName.
+func ForTown(townRoot string) (Backend, error) {
+	return New(ConfiguredName(townRoot), townRoot)
+}
+
+// ConfiguredName returns the backend name configured for a town, without
+// validating it. Empty means DefaultName.
+func ConfiguredName(townRoot string) string {
+	if name := os.Getenv(EnvBackend); name != "" {
+		return name
+	}
+	if townRoot == "" {
+		return ""
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return ""
+	}
+	return settings.SessionBackend
+}
+
+// lastLines returns the last n lines of s, without a trailing newline.
+func lastLines(s string, n int) string {
+	s = strings.TrimRight(s, "\n")
+	if n <= 0 || s == "" {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,158 @@
+package terminal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	townRoot := t.TempDir()
+	for name, want := range map[string]string{
+		"":        DefaultName(),
+		"tmux":    BackendTmux,
+		"Screen":  BackendScreen,
+		"process": BackendProcess,
+	} {
+		b, err := New(name, townRoot)
+		if err != nil {
+			t.Fatalf("New(%q): %v", name, err)
+		}
+		if b.Name() != want {
+			t.Errorf("New(%q).Name() = %q, want %q", name, b.Name(), want)
+		}
+	}
+	if _, err := New("docker", townRoot); err == nil {
+		t.Error("New(docker) succeeded, want error")
+	}
+	if _, err := New("process", ""); err == nil {
+		t.Error("New(process) without town root succeeded, want error")
+	}
+}
+
+func TestForTown(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv(EnvBackend, "")
+
+	if name := ConfiguredName(townRoot); name != "" {
+		t.Errorf("ConfiguredName without settings = %q, want empty", name)
+	}
+
+	settings := config.NewTownSettings()
+	settings.SessionBackend = "process"
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	b, err := ForTown(townRoot)
+	if err != nil || b.Name() != BackendProcess {
+		t.Fatalf("ForTown = %v, %v; want process backend", b, err)
+	}
+
+	t.Setenv(EnvBackend, "screen")
+	if b, _ := ForTown(townRoot); b.Name() != BackendScreen {
+		t.Errorf("ForTown with %s=screen = %q", EnvBackend, b.Name())
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	in := "a\nb\nc\n"
+	if got := lastLines(in, 2); got != "b\nc" {
+		t.Errorf("lastLines(2) = %q", got)
+	}
+	if got := lastLines(in, 0); got != "a\nb\nc" {
+		t.Errorf("lastLines(0) = %q", got)
+	}
+	if got := lastLines(in, 10); got != "a\nb\nc" {
+		t.Errorf("lastLines(10) = %q", got)
+	}
+}
+
+func TestParseScreenList(t *testing.T) {
+	out := "There are screens on:\n" +
+		"\t4242.gt-mayor\t(10/15/2026 09:12:01 AM)\t(Detached)\n" +
+		"\t4250.gp-Toast\t(Attached)\n" +
+		"2 Sockets in /run/screen/S-gt.\n"
+	got := parseScreenList(out)
+	if strings.Join(got, ",") != "gt-mayor,gp-Toast" {
+		t.Errorf("parseScreenList = %v", got)
+	}
+	if got := parseScreenList("No Sockets found in /run/screen/S-gt.\n"); len(got) != 0 {
+		t.Errorf("parseScreenList(none) = %v", got)
+	}
+}
+
+func TestProcessBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("session commands below use sh syntax")
+	}
+	townRoot := t.TempDir()
+	b := NewProcessBackend(townRoot)
+
+	if err := b.SetEnvironment("gt-test", "GT_ROLE", "mayor"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	if err := b.NewSession("gt-test", townRoot, `echo "role=$GT_ROLE"; pwd; sleep 30`); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	t.Cleanup(func() { _ = b.KillSession("gt-test") })
+
+	if ok, _ := b.HasSession("gt-test"); !ok {
+		t.Fatal("HasSession = false after NewSession")
+	}
+	if err := b.NewSession("gt-test", townRoot, "true"); err == nil {
+		t.Error("duplicate NewSession succeeded, want error")
+	}
+	if names, _ := b.ListSessions(); strings.Join(names, ",") != "gt-test" {
+		t.Errorf("ListSessions = %v", names)
+	}
+
+	var out string
+	for i := 0; i < 50; i++ {
+		out, _ = b.Capture("gt-test", 10)
+		if strings.Contains(out, townRoot) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !strings.Contains(out, "role=mayor") || !strings.Contains(out, townRoot) {
+		t.Errorf("Capture = %q, want role and workdir", out)
+	}
+
+	if err := b.SendText("gt-test", "hi"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SendText err = %v, want ErrUnsupported", err)
+	}
+	if err := b.Attach("gt-test", true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Attach err = %v, want ErrUnsupported", err)
+	}
+
+	if err := b.KillSession("gt-test"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if ok, _ := b.HasSession("gt-test"); !ok {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if ok, _ := b.HasSession("gt-test"); ok {
+		t.Error("HasSession = true after KillSession")
+	}
+	if err := b.KillSession("gt-test"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("second KillSession err = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := os.Stat(b.LogPath("gt-test")); err != nil {
+		t.Errorf("session log removed on kill: %v", err)
+	}
+	if err := b.NewSession("../escape", townRoot, "true"); err == nil {
+		t.Error("NewSession with path name succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".runtime", "escape")); err == nil {
+		t.Error("session dir created outside sessions/")
+	}
+}
@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// TmuxBackend hosts sessions in tmux. It is a thin adapter over
+// internal/tmux, which keeps the tmux-only features (theming, hooks,
+// pane inspection) that other backends do not have.
+type TmuxBackend struct {
+	t *tmux.Tmux
+}
+
+// NewTmuxBackend returns the tmux backend.
+func NewTmuxBackend() *TmuxBackend {
+	return &TmuxBackend{t: tmux.NewTmux()}
+}
+
+// NewTmuxBackendFor returns the tmux backend over an existing wrapper.
+func NewTmuxBackendFor(t *tmux.Tmux) *TmuxBackend {
+	return &TmuxBackend{t: t}
+}
+
+// Tmux returns the underlying tmux wrapper for tmux-only operations.
+func (b *TmuxBackend) Tmux() *tmux.Tmux {
+	return b.t
+}
+
+// Name returns "tmux".
+func (b *TmuxBackend) Name() string { return BackendTmux }
+
+// IsAvailable reports whether tmux is installed.
+func (b *TmuxBackend) IsAvailable() bool { return b.t.IsAvailable() }
+
+// NewSession starts a detached tmux session running command.
+func (b *TmuxBackend) NewSession(name, workDir, command string) error {
+	return b.t.NewSessionWithCommand(name, workDir, command)
+}
+
+// HasSession reports whether the tmux session exists.
+func (b *TmuxBackend) HasSession(name string) (bool, error) {
+	return b.t.HasSession(name)
+}
+
+// KillSession kills the tmux session and its processes.
+func (b *TmuxBackend) KillSession(name string) error {
+	err := b.t.KillSessionWithProcesses(name)
+	if errors.Is(err, tmux.ErrSessionNotFound) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	return err
+}
+
+// ListSessions returns the tmux session names.
+func (b *TmuxBackend) ListSessions() ([]string, error) {
+	return b.t.ListSessions()
+}
+
+// SetEnvironment sets a variable in the tmux session environment.
+func (b *TmuxBackend) SetEnvironment(name, key, value string) error {
+	return b.t.SetEnvironment(name, key, value)
+}
+
+// SendText nudges the session: literal text, debounce, then Enter.
+func (b *TmuxBackend) SendText(name, text string) error {
+	return b.t.NudgeSession(name, text)
+}
+
+// Capture returns the last lines of the session's pane.
+func (b *TmuxBackend) Capture(name string, lines int) (string, error) {
+	return b.t.CapturePane(name, lines)
+}
+
+// Attach attaches the terminal to the session (tmux attach-session).
+func (b *TmuxBackend) Attach(name string, readOnly bool) error {
+	args := []string{"attach-session", "-t", name}
+	if readOnly {
+		args = append(args, "-r")
+	}
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
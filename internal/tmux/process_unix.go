@@ -0,0 +1,21 @@
+//go:build !windows
+
+package tmux
+
+import (
+	"syscall"
+	"time"
+)
+
+// killProcessGroup sends SIGTERM to the process group, then SIGKILL after a
+// short grace period.
+//
+// It uses syscall.Kill directly rather than shelling out to /usr/bin/kill,
+// which has parsing ambiguity with negative PGIDs: procps-ng kill (v4.0.4+)
+// misparses "-PGID" and can kill ALL processes. syscall.Kill with a negative
+// PID targets the process group (POSIX).
+func killProcessGroup(pgid int) {
+	_ = syscall.Kill(-pgid, syscall.SIGTERM)
+	time.Sleep(100 * time.Millisecond)
+	_ = syscall.Kill(-pgid, syscall.SIGKILL)
+}
@@ -0,0 +1,7 @@
+//go:build windows
+
+package tmux
+
+// killProcessGroup is a no-op on Windows, which has no POSIX process
+// groups. tmux itself only runs under WSL or Cygwin there.
+func killProcessGroup(pgid int) {}
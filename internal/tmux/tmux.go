@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
@@ -184,14 +183,8 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 		// Note: Processes that called setsid() will have a new PGID and won't be killed here
 		pgid := getProcessGroupID(pid)
 		if pgid != "" && pgid != "0" && pgid != "1" {
-			// Kill process group using syscall.Kill() directly, rather than shelling
-			// out to /usr/bin/kill which has parsing ambiguity with negative PGIDs.
-			// procps-ng kill (v4.0.4+) misparses "-PGID" and can kill ALL processes.
-			// syscall.Kill with negative PID targets the process group (POSIX).
 			pgidInt, _ := strconv.Atoi(pgid)
-			_ = syscall.Kill(-pgidInt, syscall.SIGTERM)
-			time.Sleep(100 * time.Millisecond)
-			_ = syscall.Kill(-pgidInt, syscall.SIGKILL)
+			killProcessGroup(pgidInt)
 		}
 
 		// Also walk the process tree for any descendants that might have called setsid()
@@ -388,12 +381,8 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 	// - Are not direct children but stayed in the same process group
 	pgid := getProcessGroupID(pid)
 	if pgid != "" && pgid != "0" && pgid != "1" {
-		// Kill process group using syscall.Kill() directly.
-		// See comment in KillSessionWithProcesses for why we avoid exec.Command("kill").
 		pgidInt, _ := strconv.Atoi(pgid)
-		_ = syscall.Kill(-pgidInt, syscall.SIGTERM)
-		time.Sleep(100 * time.Millisecond)
-		_ = syscall.Kill(-pgidInt, syscall.SIGKILL)
+		killProcessGroup(pgidInt)
 	}
 
 	// Also walk the process tree for any descendants that might have called setsid()
@@ -1027,6 +1016,93 @@ func (t *Tmux) SelectWindow(session string, index int) error {
 	return err
 }
 
+// NewWindow opens a named window in a session without selecting it. The
+// window runs command, or a shell if command is empty.
+func (t *Tmux) NewWindow(session, name, workDir, command string) error {
+	args := []string{"new-window", "-d", "-t", session + ":", "-n", name}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	_, err := t.run(args...)
+	return err
+}
+
+// SplitWindow splits a new pane off target without selecting it. The pane
+// runs command, or a shell if command is empty.
+func (t *Tmux) SplitWindow(target, workDir, command string) error {
+	args := []string{"split-window", "-d", "-t", target}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	_, err := t.run(args...)
+	return err
+}
+
+// SelectLayout arranges a window's panes in a tmux layout.
+func (t *Tmux) SelectLayout(target, layout string) error {
+	_, err := t.run("select-layout", "-t", target, layout)
+	return err
+}
+
+// RenameWindow renames a window.
+func (t *Tmux) RenameWindow(target, name string) error {
+	_, err := t.run("rename-window", "-t", target, name)
+	return err
+}
+
+// ListWindows returns the names of a session's windows.
+func (t *Tmux) ListWindows(session string) ([]string, error) {
+	out, err := t.run("list-windows", "-t", session, "-F", "#{window_name}")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// WindowTarget returns the tmux target for a session's named window.
+func WindowTarget(session, window string) string {
+	return session + ":" + window
+}
+
+// OpenSessionWindows lays out a session template's extra windows in a new
+// session: the agent's window, still selected, is named
+// config.AgentWindow, and each window is opened with its panes. Session
+// targets keep reaching the agent.
+func (t *Tmux) OpenSessionWindows(session, workDir string, windows []config.SessionWindowConfig) error {
+	if len(windows) == 0 {
+		return nil
+	}
+	if err := t.RenameWindow(session+":", config.AgentWindow); err != nil {
+		return fmt.Errorf("naming agent window: %w", err)
+	}
+	for _, w := range windows {
+		if err := t.NewWindow(session, w.Name, workDir, w.Command); err != nil {
+			return fmt.Errorf("opening window %s: %w", w.Name, err)
+		}
+		target := WindowTarget(session, w.Name)
+		for _, pane := range w.Panes {
+			if err := t.SplitWindow(target, workDir, pane); err != nil {
+				return fmt.Errorf("splitting window %s: %w", w.Name, err)
+			}
+		}
+		if w.Layout != "" {
+			if err := t.SelectLayout(target, w.Layout); err != nil {
+				return fmt.Errorf("laying out window %s: %w", w.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // SetEnvironment sets an environment variable in the session.
 func (t *Tmux) SetEnvironment(session, key, value string) error {
 	_, err := t.run("set-environment", "-t", session, key, value)
@@ -1629,7 +1705,7 @@ func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	// Hook command logs the crash with exit status
 	// #{pane_dead_status} is the exit code of the process that died
 	// We run gt log crash which records to the town log
-	hookCmd := fmt.Sprintf(`run-shell "gt log crash --agent '%s' --session '%s' --exit-code #{pane_dead_status}"`,
+	hookCmd := fmt.Sprintf(`run-shell "gt log crash --agent '%s' --session '%s' --exit-code #{pane_dead_status} --workdir '#{pane_current_path}'"`,
 		agentID, session)
 
 	// Set the hook on this specific session
@@ -0,0 +1,68 @@
+package tracker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func init() {
+	Register(config.TrackerGitHub, newGitHub)
+}
+
+// gitHub mirrors to GitHub Issues with the GitHub CLI, so it uses gh's
+// login. GitHub has no in-progress state; starting work is a comment.
+type gitHub struct {
+	repo string
+}
+
+func newGitHub(_ string, cfg *config.TrackerConfig) (Tracker, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("GitHub CLI (gh) not found. Install it with: brew install gh")
+	}
+	return &gitHub{repo: cfg.Repo}, nil
+}
+
+func (g *gitHub) Create(item Item) (string, string, error) {
+	out, err := g.gh("issue", "create", "--title", item.Title, "--body", item.Description+footer(item.BeadID))
+	if err != nil {
+		return "", "", err
+	}
+	// gh prints the new issue's URL, ending in its number
+	url := strings.TrimSpace(out)
+	if i := strings.LastIndex(url, "\n"); i >= 0 {
+		url = url[i+1:]
+	}
+	ref := url[strings.LastIndex(url, "/")+1:]
+	if ref == "" {
+		return "", "", fmt.Errorf("gh issue create: unexpected output %q", out)
+	}
+	return ref, url, nil
+}
+
+func (g *gitHub) SetStatus(ref, status string) error {
+	if status == StatusClosed {
+		_, err := g.gh("issue", "close", ref)
+		return err
+	}
+	return g.Comment(ref, "Work has started.")
+}
+
+func (g *gitHub) Comment(ref, body string) error {
+	_, err := g.gh("issue", "comment", ref, "--body", body)
+	return err
+}
+
+func (g *gitHub) gh(args ...string) (string, error) {
+	cmd := exec.Command("gh", append(args, "--repo", g.repo)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("gh %s: %s", args[0]+" "+args[1], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("gh %s: %w", args[0]+" "+args[1], err)
+	}
+	return string(out), nil
+}
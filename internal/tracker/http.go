@@ -0,0 +1,79 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// token returns a tracker's API token: the configured token, resolving a
+// "secret:<name>" reference, or else the environment's.
+func token(townRoot string, cfg *config.TrackerConfig, defaultEnv string) (string, error) {
+	if cfg.Token != "" {
+		tok, err := secrets.Resolve(townRoot, cfg.Token)
+		if err != nil {
+			return "", fmt.Errorf("%s tracker: token: %w", cfg.Kind, err)
+		}
+		return tok, nil
+	}
+	env := cfg.TokenEnv
+	if env == "" {
+		env = defaultEnv
+	}
+	tok := os.Getenv(env)
+	if tok == "" {
+		return "", fmt.Errorf("%s tracker: %s is not set", cfg.Kind, env)
+	}
+	return tok, nil
+}
+
+// doJSON sends a JSON request and decodes the JSON response into out (if
+// not nil). A non-2xx response is an error carrying the response body.
+func doJSON(method, url string, header http.Header, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: parsing response: %w", method, url, err)
+	}
+	return nil
+}
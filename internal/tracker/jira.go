@@ -0,0 +1,89 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func init() {
+	Register(config.TrackerJira, newJira)
+}
+
+// jira mirrors to a Jira project through its REST API, authenticating as
+// cfg.User with an API token. Status changes take the first workflow
+// transition into the matching status category.
+type jira struct {
+	base    string
+	project string
+	header  http.Header
+}
+
+func newJira(townRoot string, cfg *config.TrackerConfig) (Tracker, error) {
+	tok, err := token(townRoot, cfg, "JIRA_API_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(cfg.User, tok)
+	return &jira{base: strings.TrimSuffix(cfg.URL, "/"), project: cfg.Project, header: req.Header}, nil
+}
+
+// jiraIssueTypes maps bead types to Jira's default issue types.
+var jiraIssueTypes = map[string]string{"bug": "Bug", "feature": "Story", "epic": "Epic"}
+
+func (j *jira) Create(item Item) (string, string, error) {
+	issueType := jiraIssueTypes[item.Type]
+	if issueType == "" {
+		issueType = "Task"
+	}
+	in := map[string]interface{}{"fields": map[string]interface{}{
+		"project":     map[string]string{"key": j.project},
+		"summary":     item.Title,
+		"description": item.Description + footer(item.BeadID),
+		"issuetype":   map[string]string{"name": issueType},
+		"labels":      []string{"gastown"},
+	}}
+	var out struct {
+		Key string `json:"key"`
+	}
+	if err := doJSON(http.MethodPost, j.base+"/rest/api/2/issue", j.header, in, &out); err != nil {
+		return "", "", err
+	}
+	return out.Key, j.base + "/browse/" + out.Key, nil
+}
+
+func (j *jira) SetStatus(ref, status string) error {
+	category := "done"
+	if status == StatusInProgress {
+		category = "indeterminate"
+	}
+	endpoint := j.base + "/rest/api/2/issue/" + url.PathEscape(ref) + "/transitions"
+	var out struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := doJSON(http.MethodGet, endpoint, j.header, nil, &out); err != nil {
+		return err
+	}
+	for _, t := range out.Transitions {
+		if t.To.StatusCategory.Key == category {
+			return doJSON(http.MethodPost, endpoint, j.header, map[string]interface{}{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("jira %s: no transition to a %q status", ref, category)
+}
+
+func (j *jira) Comment(ref, body string) error {
+	return doJSON(http.MethodPost, j.base+"/rest/api/2/issue/"+url.PathEscape(ref)+"/comment", j.header,
+		map[string]string{"body": body}, nil)
+}
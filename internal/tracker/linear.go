@@ -0,0 +1,139 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func init() {
+	Register(config.TrackerLinear, newLinear)
+}
+
+// linearURL is Linear's GraphQL endpoint.
+var linearURL = "https://api.linear.app/graphql"
+
+// linear mirrors to a Linear team through its GraphQL API. Status changes
+// move an issue to the team's first workflow state of the matching type.
+type linear struct {
+	team   string
+	header http.Header
+
+	teamID string
+	states map[string]string // State type ("started", "completed") to ID
+}
+
+func newLinear(townRoot string, cfg *config.TrackerConfig) (Tracker, error) {
+	tok, err := token(townRoot, cfg, "LINEAR_API_KEY")
+	if err != nil {
+		return nil, err
+	}
+	return &linear{team: cfg.Project, header: http.Header{"Authorization": {tok}}}, nil
+}
+
+func (l *linear) Create(item Item) (string, string, error) {
+	if err := l.loadTeam(); err != nil {
+		return "", "", err
+	}
+	var out struct {
+		IssueCreate struct {
+			Success bool `json:"success"`
+			Issue   struct {
+				Identifier string `json:"identifier"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	err := l.query(`mutation($input: IssueCreateInput!) { issueCreate(input: $input) { success issue { identifier url } } }`,
+		map[string]interface{}{"input": map[string]string{
+			"teamId":      l.teamID,
+			"title":       item.Title,
+			"description": item.Description + footer(item.BeadID),
+		}}, &out)
+	if err != nil {
+		return "", "", err
+	}
+	if !out.IssueCreate.Success {
+		return "", "", fmt.Errorf("linear: creating issue for %s failed", item.BeadID)
+	}
+	return out.IssueCreate.Issue.Identifier, out.IssueCreate.Issue.URL, nil
+}
+
+func (l *linear) SetStatus(ref, status string) error {
+	if err := l.loadTeam(); err != nil {
+		return err
+	}
+	stateType := "completed"
+	if status == StatusInProgress {
+		stateType = "started"
+	}
+	stateID := l.states[stateType]
+	if stateID == "" {
+		return fmt.Errorf("linear team %s has no %q workflow state", l.team, stateType)
+	}
+	return l.query(`mutation($id: String!, $stateId: String!) { issueUpdate(id: $id, input: {stateId: $stateId}) { success } }`,
+		map[string]interface{}{"id": ref, "stateId": stateID}, nil)
+}
+
+func (l *linear) Comment(ref, body string) error {
+	return l.query(`mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`,
+		map[string]interface{}{"input": map[string]string{"issueId": ref, "body": body}}, nil)
+}
+
+// loadTeam looks up the team's ID and workflow states, once.
+func (l *linear) loadTeam() error {
+	if l.teamID != "" {
+		return nil
+	}
+	var out struct {
+		Teams struct {
+			Nodes []struct {
+				ID     string `json:"id"`
+				States struct {
+					Nodes []struct {
+						ID       string  `json:"id"`
+						Type     string  `json:"type"`
+						Position float64 `json:"position"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	err := l.query(`query($key: String!) { teams(filter: {key: {eq: $key}}) { nodes { id states { nodes { id type position } } } } }`,
+		map[string]interface{}{"key": l.team}, &out)
+	if err != nil {
+		return err
+	}
+	if len(out.Teams.Nodes) == 0 {
+		return fmt.Errorf("linear: no team with key %q", l.team)
+	}
+	team := out.Teams.Nodes[0]
+	l.states = make(map[string]string)
+	positions := make(map[string]float64)
+	for _, s := range team.States.Nodes {
+		if _, ok := l.states[s.Type]; !ok || s.Position < positions[s.Type] {
+			l.states[s.Type], positions[s.Type] = s.ID, s.Position
+		}
+	}
+	l.teamID = team.ID
+	return nil
+}
+
+// query runs a GraphQL query, decoding its data into out (if not nil).
+func (l *linear) query(q string, vars map[string]interface{}, out interface{}) error {
+	var resp struct {
+		Data   interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	if err := doJSON(http.MethodPost, linearURL, l.header, map[string]interface{}{"query": q, "variables": vars}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("linear: %s", resp.Errors[0].Message)
+	}
+	return nil
+}
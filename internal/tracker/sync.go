@@ -0,0 +1,305 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// workTypes are the bead types mirrored; agents, MRs, mail and the rest of
+// gt's bookkeeping beads aren't work.
+var workTypes = []string{"task", "bug", "feature", "epic", "chore"}
+
+// Mirrored is a bead as last mirrored to the tracker.
+type Mirrored struct {
+	Ref    string   `json:"ref,omitempty"` // Empty for a bead seen but not mirrored
+	URL    string   `json:"url,omitempty"`
+	Status string   `json:"status"` // The bead's status when last synced
+	Merged []string `json:"merged,omitempty"`
+}
+
+// syncState is what a rig has mirrored, kept in <rig>/.runtime/.
+type syncState struct {
+	Kind  string               `json:"kind"`
+	Items map[string]*Mirrored `json:"items"` // By bead ID
+}
+
+// Action is one change mirrored to the tracker, or (on a dry run) due.
+type Action struct {
+	Bead   string `json:"bead"`
+	Event  string `json:"event"` // A config.TrackerEvent*
+	Ref    string `json:"ref,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Result is what a sync of one rig did.
+type Result struct {
+	Rig     string   `json:"rig"`
+	Kind    string   `json:"kind"`
+	DryRun  bool     `json:"dry_run"`
+	Actions []Action `json:"actions"`
+	Failed  int      `json:"failed"`
+}
+
+// statePath returns where a rig's sync state is kept.
+func statePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "tracker-sync.json")
+}
+
+// SyncRig mirrors a rig's work to the tracker in its settings. Returns nil
+// if the rig has no tracker.
+func SyncRig(rigPath string, dryRun bool) (*Result, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if settings.Tracker == nil {
+		return nil, nil
+	}
+	var t Tracker
+	if !dryRun {
+		if t, err = New(filepath.Dir(rigPath), settings.Tracker); err != nil {
+			return nil, err
+		}
+	}
+	bd := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath))
+	return Sync(rigPath, bd, t, settings.Tracker, dryRun)
+}
+
+// Sync mirrors a rig's work beads to t: it opens an item for each new one,
+// moves the item along as the bead starts and closes, and comments when an
+// MR for the bead merges. Beads already closed when first seen aren't
+// mirrored, so turning a tracker on doesn't replay the rig's history. A
+// change that fails is retried on the next sync. On a dry run, t may be
+// nil: the changes due are reported and nothing is recorded.
+func Sync(rigPath string, bd *beads.Beads, t Tracker, cfg *config.TrackerConfig, dryRun bool) (*Result, error) {
+	st, err := loadState(rigPath, cfg.Kind)
+	if err != nil {
+		return nil, err
+	}
+	all, err := bd.ListIndexed(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing beads: %w", err)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt < all[j].CreatedAt })
+
+	s := &syncer{cfg: cfg, t: t, dryRun: dryRun, state: st,
+		result: &Result{Rig: filepath.Base(rigPath), Kind: cfg.Kind, DryRun: dryRun, Actions: []Action{}}}
+	for _, issue := range all {
+		if workType, ok := WorkType(issue); ok && s.selected(issue) {
+			s.syncBead(issue, workType)
+		}
+	}
+	for _, mr := range all {
+		if beads.HasLabel(mr, "gt:merge-request") && refinery.StateOf(mr) == refinery.StateMerged {
+			s.syncMerge(mr)
+		}
+	}
+
+	if !dryRun {
+		if err := saveState(rigPath, st); err != nil {
+			return s.result, err
+		}
+	}
+	return s.result, nil
+}
+
+// WorkType returns a bead's type if it is work to mirror: its type is one
+// of workTypes, and no gt: label marks it as anything else.
+func WorkType(issue *beads.Issue) (string, bool) {
+	typ := issue.Type
+	for _, label := range issue.Labels {
+		if t, ok := strings.CutPrefix(label, "gt:"); ok {
+			if !slices.Contains(workTypes, t) {
+				return t, false
+			}
+			typ = t
+		}
+	}
+	if typ == "" {
+		typ = "task"
+	}
+	return typ, slices.Contains(workTypes, typ)
+}
+
+type syncer struct {
+	cfg    *config.TrackerConfig
+	t      Tracker
+	dryRun bool
+	state  *syncState
+	result *Result
+}
+
+// selected reports whether a bead carries one of the tracker's labels.
+func (s *syncer) selected(issue *beads.Issue) bool {
+	if len(s.cfg.Labels) == 0 {
+		return true
+	}
+	for _, label := range s.cfg.Labels {
+		if beads.HasLabel(issue, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *syncer) syncBead(issue *beads.Issue, workType string) {
+	m := s.state.Items[issue.ID]
+	creating := false // On a dry run, an item would be created
+	if m == nil {
+		m = &Mirrored{Status: issue.Status}
+		switch ref, url := s.importedFrom(issue); {
+		case ref != "":
+			m.Ref, m.URL = ref, url
+			m.Status = "open" // Catch the original up on the bead's status
+		case issue.Status == "closed" || !s.cfg.MirrorsEvent(config.TrackerEventCreated):
+		default:
+			m.Status = "open"
+			ok := s.apply(Action{Bead: issue.ID, Event: config.TrackerEventCreated, Detail: issue.Title}, func(a *Action) error {
+				ref, url, err := s.t.Create(Item{BeadID: issue.ID, Title: issue.Title, Description: issue.Description, Type: workType})
+				a.Ref, m.Ref, m.URL = ref, ref, url
+				return err
+			})
+			if !ok {
+				return
+			}
+			creating = s.dryRun
+		}
+		if !s.dryRun {
+			s.state.Items[issue.ID] = m
+		}
+	}
+	if m.Ref == "" && !creating {
+		if !s.dryRun {
+			m.Status = issue.Status
+		}
+		return
+	}
+
+	switch {
+	case issue.Status == m.Status:
+	case issue.Status == "closed":
+		s.moveTo(issue, m, config.TrackerEventClosed, StatusClosed)
+	case started(issue.Status) && !started(m.Status):
+		s.moveTo(issue, m, config.TrackerEventInProgress, StatusInProgress)
+	default:
+		if !s.dryRun {
+			m.Status = issue.Status
+		}
+	}
+}
+
+// started reports whether a bead status means work is under way.
+func started(status string) bool {
+	return status == "in_progress" || status == "hooked"
+}
+
+// moveTo mirrors a bead's new status.
+func (s *syncer) moveTo(issue *beads.Issue, m *Mirrored, event, status string) {
+	if !s.cfg.MirrorsEvent(event) {
+		if !s.dryRun {
+			m.Status = issue.Status
+		}
+		return
+	}
+	if s.apply(Action{Bead: issue.ID, Event: event, Ref: m.Ref}, func(*Action) error { return s.t.SetStatus(m.Ref, status) }) && !s.dryRun {
+		m.Status = issue.Status
+	}
+}
+
+// syncMerge comments on a merged MR's source issue.
+func (s *syncer) syncMerge(mr *beads.Issue) {
+	fields := beads.ParseMRFields(mr)
+	if fields == nil || fields.SourceIssue == "" || !s.cfg.MirrorsEvent(config.TrackerEventMerged) {
+		return
+	}
+	m := s.state.Items[fields.SourceIssue]
+	if m == nil || m.Ref == "" || slices.Contains(m.Merged, mr.ID) {
+		return
+	}
+	body := fmt.Sprintf("Merged to %s", fields.Target)
+	if fields.MergeCommit != "" {
+		body += " as " + fields.MergeCommit
+	}
+	body += fmt.Sprintf(" (%s).", mr.ID)
+	if s.apply(Action{Bead: fields.SourceIssue, Event: config.TrackerEventMerged, Ref: m.Ref, Detail: mr.ID}, func(*Action) error {
+		return s.t.Comment(m.Ref, body)
+	}) && !s.dryRun {
+		m.Merged = append(m.Merged, mr.ID)
+	}
+}
+
+// importedFrom returns the GitHub issue a bead was imported from, if the
+// tracker is that repository's.
+func (s *syncer) importedFrom(issue *beads.Issue) (ref, url string) {
+	if s.cfg.Kind != config.TrackerGitHub {
+		return "", ""
+	}
+	prefix := GitHubSourceLabelPrefix + s.cfg.Repo + "#"
+	for _, label := range issue.Labels {
+		if n, ok := strings.CutPrefix(label, prefix); ok && n != "" {
+			return n, "https://github.com/" + s.cfg.Repo + "/issues/" + n
+		}
+	}
+	return "", ""
+}
+
+// apply records an action and, unless this is a dry run, makes it.
+// Returns whether it was made.
+func (s *syncer) apply(a Action, fn func(*Action) error) bool {
+	ok := true
+	if !s.dryRun {
+		if err := fn(&a); err != nil {
+			a.Error = err.Error()
+			s.result.Failed++
+			ok = false
+		}
+	}
+	s.result.Actions = append(s.result.Actions, a)
+	return ok
+}
+
+// loadState reads a rig's sync state. State from another kind of tracker
+// is dropped: its references mean nothing to this one.
+func loadState(rigPath, kind string) (*syncState, error) {
+	st := &syncState{Kind: kind, Items: map[string]*Mirrored{}}
+	data, err := os.ReadFile(statePath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("reading tracker sync state: %w", err)
+	}
+	var saved syncState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("reading tracker sync state %s: %w", statePath(rigPath), err)
+	}
+	if saved.Kind == kind && saved.Items != nil {
+		st.Items = saved.Items
+	}
+	return st, nil
+}
+
+func saveState(rigPath string, st *syncState) error {
+	if err := os.MkdirAll(filepath.Dir(statePath(rigPath)), 0755); err != nil {
+		return fmt.Errorf("writing tracker sync state: %w", err)
+	}
+	if err := util.AtomicWriteJSON(statePath(rigPath), st); err != nil {
+		return fmt.Errorf("writing tracker sync state: %w", err)
+	}
+	return nil
+}
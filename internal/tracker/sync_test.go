@@ -0,0 +1,156 @@
+package tracker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// fakeTracker records the calls Sync makes.
+type fakeTracker struct {
+	calls []string
+	fail  bool
+}
+
+func (f *fakeTracker) Create(item Item) (string, string, error) {
+	if f.fail {
+		return "", "", fmt.Errorf("tracker down")
+	}
+	ref := fmt.Sprintf("%d", len(f.calls)+1)
+	f.calls = append(f.calls, "create "+item.BeadID+" "+item.Type)
+	return ref, "https://tracker/" + ref, nil
+}
+
+func (f *fakeTracker) SetStatus(ref, status string) error {
+	f.calls = append(f.calls, "status "+ref+" "+status)
+	return nil
+}
+
+func (f *fakeTracker) Comment(ref, body string) error {
+	f.calls = append(f.calls, "comment "+ref+" "+body)
+	return nil
+}
+
+// fakeBeads returns a Beads whose bd lists the beads written with write.
+func fakeBeads(t *testing.T) (*beads.Beads, func(json string)) {
+	t.Helper()
+	binDir := t.TempDir()
+	data := filepath.Join(t.TempDir(), "beads.json")
+	script := "#!/bin/sh\ncat \"" + data + "\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return beads.New(t.TempDir()), func(json string) {
+		t.Helper()
+		if err := os.WriteFile(data, []byte(json), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	rigPath := t.TempDir()
+	bd, write := fakeBeads(t)
+	cfg := &config.TrackerConfig{Kind: config.TrackerGitHub, Repo: "acme/widgets"}
+	ft := &fakeTracker{}
+	sync := func(dryRun bool) *Result {
+		t.Helper()
+		res, err := Sync(rigPath, bd, ft, cfg, dryRun)
+		if err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+		return res
+	}
+
+	write(`[
+		{"id":"gp-1","title":"Fix login","status":"open","created_at":"1","labels":["gt:bug"]},
+		{"id":"gp-2","title":"Old work","status":"closed","created_at":"2"},
+		{"id":"gp-3","title":"From GitHub","status":"open","created_at":"3","labels":["github:acme/widgets#42"]},
+		{"id":"gp-agent","title":"Nux","status":"open","created_at":"4","labels":["gt:agent"]}
+	]`)
+	if res := sync(true); len(res.Actions) != 1 || res.Actions[0].Bead != "gp-1" || len(ft.calls) != 0 {
+		t.Fatalf("dry run = %+v, calls %v", res.Actions, ft.calls)
+	}
+	sync(false)
+	if got := strings.Join(ft.calls, "; "); got != "create gp-1 bug" {
+		t.Fatalf("first sync calls = %s", got)
+	}
+
+	ft.calls = nil
+	write(`[
+		{"id":"gp-1","title":"Fix login","status":"closed","created_at":"1","labels":["gt:bug"]},
+		{"id":"gp-2","title":"Old work","status":"closed","created_at":"2"},
+		{"id":"gp-3","title":"From GitHub","status":"in_progress","created_at":"3","labels":["github:acme/widgets#42"]},
+		{"id":"gp-mr-1","status":"closed","created_at":"5","labels":["gt:merge-request"],
+		 "description":"branch: polecat/nux/gp-1\ntarget: main\nsource_issue: gp-1\nmerge_commit: abc123\nclose_reason: merged"}
+	]`)
+	sync(false)
+	want := "status 1 closed; status 42 in_progress; comment 1 Merged to main as abc123 (gp-mr-1)."
+	if got := strings.Join(ft.calls, "; "); got != want {
+		t.Errorf("second sync calls = %s\nwant %s", got, want)
+	}
+
+	ft.calls = nil
+	if res := sync(false); len(res.Actions) != 0 || len(ft.calls) != 0 {
+		t.Errorf("unchanged sync = %+v", res.Actions)
+	}
+}
+
+func TestSyncRetriesFailedCreate(t *testing.T) {
+	rigPath := t.TempDir()
+	bd, write := fakeBeads(t)
+	cfg := &config.TrackerConfig{Kind: config.TrackerJira, URL: "https://jira", Project: "GP", User: "me"}
+	write(`[{"id":"gp-1","title":"Fix login","status":"in_progress","created_at":"1"}]`)
+
+	ft := &fakeTracker{fail: true}
+	res, err := Sync(rigPath, bd, ft, cfg, false)
+	if err != nil || res.Failed != 1 {
+		t.Fatalf("Sync = %+v, %v; want 1 failure", res, err)
+	}
+	ft.fail = false
+	if _, err := Sync(rigPath, bd, ft, cfg, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ft.calls, "; "); got != "create gp-1 task; status 1 in_progress" {
+		t.Errorf("retry calls = %s", got)
+	}
+}
+
+func TestNewUnknownKind(t *testing.T) {
+	if _, err := New(t.TempDir(), &config.TrackerConfig{Kind: "trello"}); err == nil {
+		t.Error("New accepted an unknown kind")
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(secrets.EnvKey, "")
+	t.Setenv("JIRA_API_TOKEN", "from-env")
+	townRoot := t.TempDir()
+	if err := secrets.Set(townRoot, "jira", "from-secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		cfg  config.TrackerConfig
+		want string
+	}{
+		{config.TrackerConfig{Kind: "jira"}, "from-env"},
+		{config.TrackerConfig{Kind: "jira", Token: "secret:jira"}, "from-secret"},
+		{config.TrackerConfig{Kind: "jira", Token: "literal"}, "literal"},
+	} {
+		if got, err := token(townRoot, &tt.cfg, "JIRA_API_TOKEN"); err != nil || got != tt.want {
+			t.Errorf("token(%+v) = %q, %v; want %q", tt.cfg, got, err, tt.want)
+		}
+	}
+	if _, err := token(townRoot, &config.TrackerConfig{Kind: "jira", Token: "secret:nope"}, "JIRA_API_TOKEN"); err == nil {
+		t.Error("token with a missing secret succeeded")
+	}
+}
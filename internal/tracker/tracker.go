@@ -0,0 +1,87 @@
+// Package tracker mirrors a rig's work to an external issue tracker, so
+// people who follow GitHub Issues, Jira or Linear rather than gt still see
+// progress. Each tracker kind is an adapter behind the Tracker interface;
+// Sync opens an item for each new work bead and updates it as the bead
+// starts, closes, and its MRs merge.
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Statuses an item can be moved to.
+const (
+	StatusInProgress = "in_progress"
+	StatusClosed     = "closed"
+)
+
+// GitHubSourceLabelPrefix starts the label recording which GitHub issue a
+// bead was imported from, e.g. "github:acme/widgets#42". A GitHub tracker
+// for that repository mirrors to the original issue instead of opening
+// another.
+const GitHubSourceLabelPrefix = "github:"
+
+// requestTimeout bounds each call to a tracker's API.
+const requestTimeout = 30 * time.Second
+
+// Item is a bead as a tracker receives it.
+type Item struct {
+	BeadID      string
+	Title       string
+	Description string
+	Type        string // task, bug, feature, epic, chore
+}
+
+// Tracker is an external issue tracker.
+type Tracker interface {
+	// Create opens an item for a bead, returning its reference (an issue
+	// number or key) and URL.
+	Create(item Item) (ref, url string, err error)
+
+	// SetStatus moves an item to StatusInProgress or StatusClosed.
+	SetStatus(ref, status string) error
+
+	// Comment adds a comment to an item.
+	Comment(ref, body string) error
+}
+
+// Factory makes a tracker from a rig's tracker config. townRoot resolves
+// secret references in it.
+type Factory func(townRoot string, cfg *config.TrackerConfig) (Tracker, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a tracker kind available to New. Adapters register
+// themselves in init.
+func Register(kind string, f Factory) {
+	factories[kind] = f
+}
+
+// Kinds returns the registered tracker kinds, sorted.
+func Kinds() []string {
+	kinds := make([]string, 0, len(factories))
+	for kind := range factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// New returns the tracker a rig's config describes.
+func New(townRoot string, cfg *config.TrackerConfig) (Tracker, error) {
+	f, ok := factories[cfg.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown tracker kind %q (kinds: %s)", cfg.Kind, strings.Join(Kinds(), ", "))
+	}
+	return f(townRoot, cfg)
+}
+
+// footer is appended to a mirrored item's description.
+func footer(beadID string) string {
+	return fmt.Sprintf("\n\n---\nMirrored from bead %s by Gas Town.", beadID)
+}
@@ -478,6 +478,14 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "merge failed"
 
+	case "mr_sla_breached":
+		mr := getPayloadString(payload, "mr")
+		overdue := getPayloadString(payload, "overdue")
+		if mr != "" && overdue != "" {
+			return fmt.Sprintf("%s past merge SLA by %s", mr, overdue)
+		}
+		return "merge SLA breached"
+
 	default:
 		if msg := getPayloadString(payload, "message"); msg != "" {
 			return msg
@@ -0,0 +1,203 @@
+// Package picker is a fuzzy-filterable list for choosing one item, used
+// when a human runs an ID-taking command without the ID.
+package picker
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCancelled is returned by Run when the user leaves without choosing.
+var ErrCancelled = errors.New("cancelled")
+
+// maxVisible caps how many items are listed at once.
+const maxVisible = 15
+
+// Item is one choice: an ID, and a description to show and match beside it.
+type Item struct {
+	ID    string
+	Title string
+}
+
+// Model is the bubbletea model for the picker.
+type Model struct {
+	title   string
+	items   []Item
+	filter  []rune
+	matches []int // indexes into items, best match first
+	cursor  int   // index into matches
+	offset  int   // first visible match
+	height  int   // terminal height; 0 until known
+
+	chosen    *Item
+	cancelled bool
+
+	keys KeyMap
+	help help.Model
+}
+
+// New creates a picker over items, titled with a prompt such as
+// "Failed merge requests in greenplace".
+func New(title string, items []Item) Model {
+	m := Model{title: title, items: items, keys: DefaultKeyMap(), help: help.New()}
+	m.refilter()
+	return m
+}
+
+// Run shows the picker on the terminal (drawn on stderr, so stdout stays
+// clean) and returns the chosen item, or ErrCancelled.
+func Run(title string, items []Item) (Item, error) {
+	p := tea.NewProgram(New(title, items), tea.WithOutput(os.Stderr))
+	final, err := p.Run()
+	if err != nil {
+		return Item{}, err
+	}
+	m := final.(Model)
+	if m.chosen == nil {
+		return Item{}, ErrCancelled
+	}
+	return *m.chosen, nil
+}
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key presses and resizes.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.scroll()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.cancelled = true
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Choose):
+			if len(m.matches) > 0 {
+				item := m.items[m.matches[m.cursor]]
+				m.chosen = &item
+				return m, tea.Quit
+			}
+		case key.Matches(msg, m.keys.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keys.Down):
+			m.move(1)
+		case key.Matches(msg, m.keys.PageUp):
+			m.move(-m.visible())
+		case key.Matches(msg, m.keys.PageDown):
+			m.move(m.visible())
+		case key.Matches(msg, m.keys.Clear):
+			m.filter = nil
+			m.refilter()
+		case msg.Type == tea.KeyBackspace:
+			if len(m.filter) > 0 {
+				m.filter = m.filter[:len(m.filter)-1]
+				m.refilter()
+			}
+		case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+			m.filter = append(m.filter, msg.Runes...)
+			m.refilter()
+		}
+	}
+	return m, nil
+}
+
+// move moves the cursor by delta, keeping it on the list and in view.
+func (m *Model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.matches) {
+		m.cursor = len(m.matches) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scroll()
+}
+
+// scroll keeps the cursor within the visible window.
+func (m *Model) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if n := m.visible(); m.cursor >= m.offset+n {
+		m.offset = m.cursor - n + 1
+	}
+}
+
+// visible returns how many items fit: maxVisible, less on a short
+// terminal (the title, filter and footer lines take three, plus a spare).
+func (m Model) visible() int {
+	n := maxVisible
+	if m.height > 0 && m.height-4 < n {
+		n = m.height - 4
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// refilter recomputes the matches for the current filter and moves the
+// cursor back to the best one.
+func (m *Model) refilter() {
+	type scored struct {
+		index int
+		score int
+	}
+	var found []scored
+	for i, item := range m.items {
+		if score, ok := Match(string(m.filter), item.ID+" "+item.Title); ok {
+			found = append(found, scored{i, score})
+		}
+	}
+	sort.SliceStable(found, func(a, b int) bool { return found[a].score < found[b].score })
+
+	m.matches = m.matches[:0]
+	for _, f := range found {
+		m.matches = append(m.matches, f.index)
+	}
+	m.cursor, m.offset = 0, 0
+}
+
+// Match reports whether pattern fuzzy-matches s: its characters appear in
+// s in order, ignoring case. The score ranks matches, lower first: a
+// contiguous match scores lowest, then matches whose characters lie
+// closer together. Spaces in the pattern are ignored.
+func Match(pattern, s string) (int, bool) {
+	pattern = strings.ToLower(strings.ReplaceAll(pattern, " ", ""))
+	s = strings.ToLower(s)
+	if pattern == "" {
+		return 0, true
+	}
+	if i := strings.Index(s, pattern); i >= 0 {
+		return i, true
+	}
+
+	// Subsequence match: score by the span it covers
+	pr := []rune(pattern)
+	start, j := -1, 0
+	for i, r := range []rune(s) {
+		if r != pr[j] {
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		j++
+		if j == len(pr) {
+			return len(s) + (i - start), true
+		}
+	}
+	return 0, false
+}
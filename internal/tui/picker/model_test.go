@@ -0,0 +1,40 @@
+package picker
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMatch(t *testing.T) {
+	if _, ok := Match("gpmr", "gp-mr-abc polecat/nux"); !ok {
+		t.Error("subsequence should match")
+	}
+	if _, ok := Match("zz", "gp-mr-abc"); ok {
+		t.Error("absent characters should not match")
+	}
+	contiguous, _ := Match("nux", "gp-mr-1 polecat/nux/gp-1")
+	scattered, _ := Match("nux", "gp-mr-2 polecat/n-u-x/gp-2")
+	if contiguous >= scattered {
+		t.Errorf("contiguous score %d should rank before scattered %d", contiguous, scattered)
+	}
+}
+
+func TestFilterAndChoose(t *testing.T) {
+	m := New("Pick", []Item{
+		{ID: "gp-mr-1", Title: "polecat/nux/gp-1"},
+		{ID: "gp-mr-2", Title: "polecat/toast/gp-2"},
+	})
+	for _, r := range "toast" {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(Model)
+	}
+	if len(m.matches) != 1 {
+		t.Fatalf("matches = %v, want one", m.matches)
+	}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.chosen == nil || m.chosen.ID != "gp-mr-2" || cmd == nil {
+		t.Errorf("chosen = %+v, want gp-mr-2 and quit", m.chosen)
+	}
+}
@@ -0,0 +1,69 @@
+package picker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the picker
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	filterStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+)
+
+// View renders the picker. Once it's done it renders nothing, so the
+// command's own output follows the prompt line.
+func (m Model) View() string {
+	if m.chosen != nil || m.cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n")
+	b.WriteString(filterStyle.Render("> " + string(m.filter)))
+	b.WriteString("\n")
+
+	if len(m.matches) == 0 {
+		b.WriteString(dimStyle.Render("  (no matches)"))
+		b.WriteString("\n")
+	}
+	end := m.offset + m.visible()
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+	for i := m.offset; i < end; i++ {
+		item := m.items[m.matches[i]]
+		line := item.ID
+		if item.Title != "" {
+			line += "  " + dimStyle.Render(item.Title)
+		}
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("▸ " + item.ID))
+			if item.Title != "" {
+				b.WriteString("  " + item.Title)
+			}
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(dimStyle.Render(fmt.Sprintf("%d/%d", len(m.matches), len(m.items))))
+	b.WriteString("  ")
+	b.WriteString(m.help.View(m.keys))
+	return b.String()
+}
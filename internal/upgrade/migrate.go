@@ -0,0 +1,134 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Migration brings one kind of town data forward to the format this gt
+// expects: a config schema bump, a bead field rename, and the like.
+type Migration struct {
+	// ID names the migration in the town's record. Never reuse or
+	// rename one; a town that recorded it won't run it again.
+	ID          string
+	Description string
+
+	// Run migrates the town at townRoot and describes each change it
+	// made. It must be safe to run on a town that doesn't need it (a new
+	// town, or one that stopped halfway), changing nothing there.
+	Run func(townRoot string) ([]string, error)
+}
+
+// Applied records a migration run on a town.
+type Applied struct {
+	ID        string    `json:"id"`
+	AppliedAt time.Time `json:"applied_at"`
+	GTVersion string    `json:"gt_version"`
+}
+
+// State is the town's record of the migrations run on it
+// (mayor/migrations.json).
+type State struct {
+	Applied []Applied `json:"applied"`
+}
+
+// StatePath returns the path to a town's migration record.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "migrations.json")
+}
+
+// LoadState reads a town's migration record. A town without one has run
+// none.
+func LoadState(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading migration record: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing migration record: %w", err)
+	}
+	return &s, nil
+}
+
+// IsApplied reports whether the town has run the migration.
+func (s *State) IsApplied(id string) bool {
+	for _, a := range s.Applied {
+		if a.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the registered migrations the town at townRoot hasn't
+// run, in order.
+func Pending(townRoot string) ([]Migration, error) {
+	return pending(townRoot, Migrations)
+}
+
+func pending(townRoot string, migrations []Migration) ([]Migration, error) {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var out []Migration
+	for _, m := range migrations {
+		if !state.IsApplied(m.ID) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// MarkApplied records every registered migration as applied to the town
+// at townRoot, for a new town that starts out in the current format.
+func MarkApplied(townRoot, gtVersion string) error {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, m := range Migrations {
+		if !state.IsApplied(m.ID) {
+			state.Applied = append(state.Applied, Applied{ID: m.ID, AppliedAt: now, GTVersion: gtVersion})
+		}
+	}
+	return saveState(townRoot, state)
+}
+
+// Apply runs a migration on the town at townRoot and records it as
+// applied, unless it fails. Returns the changes it made.
+func Apply(townRoot string, m Migration, gtVersion string) ([]string, error) {
+	changes, err := m.Run(townRoot)
+	if err != nil {
+		return changes, fmt.Errorf("migration %s: %w", m.ID, err)
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return changes, err
+	}
+	if !state.IsApplied(m.ID) {
+		state.Applied = append(state.Applied, Applied{ID: m.ID, AppliedAt: time.Now().UTC(), GTVersion: gtVersion})
+	}
+	if err := saveState(townRoot, state); err != nil {
+		return changes, fmt.Errorf("recording migration %s: %w", m.ID, err)
+	}
+	return changes, nil
+}
+
+func saveState(townRoot string, state *State) error {
+	if err := os.MkdirAll(filepath.Dir(StatePath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(StatePath(townRoot), state)
+}
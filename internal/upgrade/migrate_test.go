@@ -0,0 +1,122 @@
+package upgrade
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestApplyRecordsMigrations(t *testing.T) {
+	townRoot := t.TempDir()
+	runs := 0
+	migrations := []Migration{
+		{ID: "first", Run: func(string) ([]string, error) { runs++; return []string{"did it"}, nil }},
+		{ID: "second", Run: func(string) ([]string, error) { return nil, errors.New("broken") }},
+	}
+
+	pend, err := pending(townRoot, migrations)
+	if err != nil || len(pend) != 2 {
+		t.Fatalf("pending = %v, %v; want both", pend, err)
+	}
+	changes, err := Apply(townRoot, pend[0], "0.5.0")
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Apply = %v, %v", changes, err)
+	}
+	if _, err := Apply(townRoot, pend[1], "0.5.0"); err == nil {
+		t.Fatal("a failing migration should return its error")
+	}
+
+	// Only the one that succeeded is recorded
+	pend, err = pending(townRoot, migrations)
+	if err != nil || len(pend) != 1 || pend[0].ID != "second" {
+		t.Fatalf("pending after apply = %v, %v; want [second]", pend, err)
+	}
+	state, err := LoadState(townRoot)
+	if err != nil || len(state.Applied) != 1 || state.Applied[0].GTVersion != "0.5.0" {
+		t.Errorf("state = %+v, %v", state, err)
+	}
+	if runs != 1 {
+		t.Errorf("first ran %d times, want 1", runs)
+	}
+}
+
+func TestRegisteredMigrationIDsAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, m := range Migrations {
+		if m.ID == "" || m.Run == nil || seen[m.ID] {
+			t.Errorf("bad or duplicate migration %q", m.ID)
+		}
+		seen[m.ID] = true
+	}
+}
+
+func TestMigrateTownConfigV2(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "mayor", "town.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	v1 := `{"type": "town", "version": 1, "name": "hq", "created_at": "2025-01-01T00:00:00Z"}`
+	if err := os.WriteFile(path, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := migrateTownConfigV2(townRoot)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("migrate = %v, %v", changes, err)
+	}
+	townConfig, err := config.LoadTownConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if townConfig.Version != 2 || townConfig.PublicName != "hq" || !townConfig.CreatedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("town config = %+v", townConfig)
+	}
+
+	// Already migrated: nothing to do
+	if changes, err := migrateTownConfigV2(townRoot); err != nil || len(changes) != 0 {
+		t.Errorf("second run = %v, %v; want no changes", changes, err)
+	}
+}
+
+func TestCanonicalMRDescription(t *testing.T) {
+	aliased := &beads.Issue{Description: "branch: polecat/nux\nsource-issue: gt-42\nconvoy: hq-cv-1\nretry_count: 2\n\n## Summary\nsource-issue: stays in the body"}
+	desc, ok := canonicalMRDescription(aliased)
+	if !ok {
+		t.Fatal("aliased keys should be rewritten")
+	}
+	for _, want := range []string{"source_issue: gt-42", "convoy_id: hq-cv-1", "retry_count: 2", "## Summary\nsource-issue: stays in the body"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description missing %q:\n%s", want, desc)
+		}
+	}
+	if strings.Contains(strings.Split(desc, "## ")[0], "source-issue") {
+		t.Errorf("alias left in the fields:\n%s", desc)
+	}
+
+	// The rewritten description, and one gt wrote, need nothing
+	if _, ok := canonicalMRDescription(&beads.Issue{Description: desc}); ok {
+		t.Error("canonical description rewritten again")
+	}
+	written := &beads.Issue{Description: beads.FormatMRFields(&beads.MRFields{Branch: "b", Target: "main", SourceIssue: "gt-1"})}
+	if _, ok := canonicalMRDescription(written); ok {
+		t.Error("description gt wrote should not need migrating")
+	}
+}
+
+func TestMarkApplied(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := MarkApplied(townRoot, "0.5.0"); err != nil {
+		t.Fatalf("MarkApplied: %v", err)
+	}
+	pend, err := Pending(townRoot)
+	if err != nil || len(pend) != 0 {
+		t.Errorf("Pending after MarkApplied = %v, %v; want none", pend, err)
+	}
+}
@@ -0,0 +1,135 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Migrations are the registered data migrations, in the order they run.
+// Append new ones at the end.
+var Migrations = []Migration{
+	{
+		ID:          "town-config-v2",
+		Description: "Add owner and public_name to mayor/town.json (town config version 2)",
+		Run:         migrateTownConfigV2,
+	},
+	{
+		ID:          "mr-canonical-fields",
+		Description: "Rewrite merge request fields under their canonical names (source-issue → source_issue, convoy → convoy_id)",
+		Run:         migrateMRFields,
+	},
+}
+
+// migrateTownConfigV2 fills in the identity fields town config version 2
+// added, as gt install does for a new town: the owner from git's
+// user.email, and the town name as its public name.
+func migrateTownConfigV2(townRoot string) ([]string, error) {
+	path := filepath.Join(townRoot, "mayor", "town.json")
+	townConfig, err := config.LoadTownConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if townConfig.Version >= 2 {
+		return nil, nil
+	}
+
+	if townConfig.Owner == "" {
+		if out, err := exec.Command("git", "config", "user.email").Output(); err == nil {
+			townConfig.Owner = strings.TrimSpace(string(out))
+		}
+	}
+	if townConfig.PublicName == "" {
+		townConfig.PublicName = townConfig.Name
+	}
+	from := townConfig.Version
+	townConfig.Version = 2
+	if err := config.SaveTownConfig(path, townConfig); err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("mayor/town.json: version %d → 2 (owner %q, public_name %q)",
+		from, townConfig.Owner, townConfig.PublicName)}, nil
+}
+
+// migrateMRFields rewrites each rig's merge request beads whose fields
+// use an alias the parser still accepts (source-issue, sourceissue,
+// convoy) under the canonical key gt writes today, so tools reading the
+// descriptions see one spelling.
+func migrateMRFields(townRoot string) ([]string, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, name := range names {
+		b := beads.New(filepath.Join(townRoot, name))
+		issues, err := b.List(beads.ListOptions{Status: "all", Label: "gt:merge-request", Priority: -1})
+		if err != nil {
+			return changes, fmt.Errorf("rig %s: listing merge requests: %w", name, err)
+		}
+		for _, issue := range issues {
+			desc, ok := canonicalMRDescription(issue)
+			if !ok {
+				continue
+			}
+			if err := b.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+				return changes, fmt.Errorf("rig %s: updating %s: %w", name, issue.ID, err)
+			}
+			changes = append(changes, fmt.Sprintf("%s: %s fields renamed", name, issue.ID))
+		}
+	}
+	return changes, nil
+}
+
+// canonicalMRDescription returns an MR's description with its fields
+// written under their canonical keys, and whether that differs from the
+// one it has: only descriptions with an aliased key are rewritten.
+func canonicalMRDescription(issue *beads.Issue) (string, bool) {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return "", false
+	}
+	canonical := make(map[string]bool)
+	for _, line := range strings.Split(beads.FormatMRFields(fields), "\n") {
+		if i := strings.Index(line, ":"); i > 0 {
+			canonical[line[:i]] = true
+		}
+	}
+
+	aliased := false
+	for _, line := range strings.Split(issue.Description, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, beads.MRBodyHeading) {
+			break
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			continue
+		}
+		// A key the parser knows that gt wouldn't write is an alias
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		if !canonical[key] && beads.ParseMRFields(&beads.Issue{Description: key + ": 1"}) != nil {
+			aliased = true
+			break
+		}
+	}
+	if !aliased {
+		return "", false
+	}
+	return beads.SetMRFields(issue, fields), true
+}
@@ -0,0 +1,112 @@
+// Package upgrade keeps gt and the town's data current: it finds the
+// latest gt release and how this binary was installed (so it can update
+// itself the same way), and runs the registered data migrations that
+// bring an older town's files and beads forward to what this gt expects.
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReleaseURL is where the latest gt release is looked up.
+var ReleaseURL = "https://api.github.com/repos/steveyegge/gastown/releases/latest"
+
+// LatestVersion returns the version of the latest gt release, without
+// its leading "v".
+func LatestVersion(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ReleaseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("checking latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checking latest release: %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("parsing latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("latest release has no tag")
+	}
+	return strings.TrimPrefix(release.TagName, "v"), nil
+}
+
+// CompareVersions compares two "X.Y.Z" versions (a leading "v" and any
+// pre-release suffix are ignored). Returns -1 if a < b, 0 if equal, 1 if
+// a > b.
+func CompareVersions(a, b string) int {
+	pa, pb := parseVersion(a), parseVersion(b)
+	for i := range pa {
+		if pa[i] < pb[i] {
+			return -1
+		}
+		if pa[i] > pb[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(v string) [3]int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts [3]int
+	for i, s := range strings.SplitN(v, ".", 3) {
+		parts[i], _ = strconv.Atoi(s)
+	}
+	return parts
+}
+
+// Install methods, as the README lists them.
+const (
+	InstallHomebrew = "homebrew"
+	InstallNPM      = "npm"
+	InstallGo       = "go"
+)
+
+// DetectInstallMethod works out how the gt binary at exe was installed
+// from where it lives. Anything not under Homebrew or npm is taken to be
+// a go install.
+func DetectInstallMethod(exe string) string {
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	exe = filepath.ToSlash(exe)
+	switch {
+	case strings.Contains(exe, "/Cellar/") || strings.Contains(exe, "/homebrew/"):
+		return InstallHomebrew
+	case strings.Contains(exe, "/node_modules/"):
+		return InstallNPM
+	default:
+		return InstallGo
+	}
+}
+
+// UpdateCommand returns the command that updates a gt installed by method
+// to the latest release.
+func UpdateCommand(method string) []string {
+	switch method {
+	case InstallHomebrew:
+		return []string{"brew", "upgrade", "gastown"}
+	case InstallNPM:
+		return []string{"npm", "install", "-g", "@gastown/gt@latest"}
+	default:
+		return []string{"go", "install", "github.com/steveyegge/gastown/cmd/gt@latest"}
+	}
+}
@@ -0,0 +1,65 @@
+package upgrade
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.5.0", "0.5.0", 0},
+		{"v0.6.0", "0.5.9", 1},
+		{"0.5.0", "0.10.0", -1},
+		{"1.0.0-rc1", "1.0.0", 0},
+		{"1.2", "1.2.0", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDetectInstallMethod(t *testing.T) {
+	tests := map[string]string{
+		"/opt/homebrew/bin/gt":                     InstallHomebrew,
+		"/usr/local/Cellar/gastown/0.5.0/bin/gt":   InstallHomebrew,
+		"/usr/lib/node_modules/@gastown/gt/bin/gt": InstallNPM,
+		"/home/me/go/bin/gt":                       InstallGo,
+	}
+	for exe, want := range tests {
+		if got := DetectInstallMethod(exe); got != want {
+			t.Errorf("DetectInstallMethod(%q) = %q, want %q", exe, got, want)
+		}
+		if argv := UpdateCommand(want); len(argv) < 2 {
+			t.Errorf("UpdateCommand(%q) = %v", want, argv)
+		}
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name": "v0.7.1"}`))
+	}))
+	defer srv.Close()
+	old := ReleaseURL
+	ReleaseURL = srv.URL
+	defer func() { ReleaseURL = old }()
+
+	got, err := LatestVersion(context.Background())
+	if err != nil || got != "0.7.1" {
+		t.Errorf("LatestVersion = %q, %v; want 0.7.1", got, err)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	})
+	if _, err := LatestVersion(context.Background()); err == nil {
+		t.Error("a failed lookup should be an error")
+	}
+}
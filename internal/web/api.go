@@ -96,6 +96,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleIssueShow(w, r)
 	case path == "/pr/show" && r.Method == http.MethodGet:
 		h.handlePRShow(w, r)
+	case path == "/chatops" && r.Method == http.MethodPost:
+		h.handleChatOps(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -175,6 +177,12 @@ func (h *APIHandler) handleCommands(w http.ResponseWriter, _ *http.Request) {
 
 // runGtCommand executes a gt command with the given args.
 func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
+	return h.runGtCommandAs(ctx, timeout, nil, args)
+}
+
+// runGtCommandAs is runGtCommand with env added to the dashboard's
+// environment, e.g. to run as another identity.
+func (h *APIHandler) runGtCommandAs(ctx context.Context, timeout time.Duration, env, args []string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -182,6 +190,9 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	if h.workDir != "" {
 		cmd.Dir = h.workDir
 	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	// Ensure the command doesn't wait for stdin
 	cmd.Stdin = nil
 
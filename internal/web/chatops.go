@@ -0,0 +1,257 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// maxChatOpsBody bounds a chatops request body.
+const maxChatOpsBody = 64 << 10
+
+// chatOpsUsage lists the chatops commands.
+const chatOpsUsage = `Commands:
+  status [rig]              Town status, or a rig's merge queue
+  retry <rig> <mr-id>       Retry a failed merge request
+  approve <mr-id>           Approve a merge request
+  pause <rig> [reason]      Hold merges in a rig's queue
+  resume <rig>              Resume merges`
+
+// chatOpsArg is what a rig name or MR ID may look like; anything else
+// (flags, shell syntax) is refused before reaching gt.
+var chatOpsArg = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// chatOpsUserChars are the characters kept from a chat user name in an
+// identity.
+var chatOpsUserChars = regexp.MustCompile(`[^A-Za-z0-9._@-]+`)
+
+// chatOpsIdentity is who a chatops command runs as: the token that
+// authenticated it and the user its chat client named, e.g.
+// "chatops:slack/alice". Only the token is authenticated, so approvals
+// recorded under it don't count toward a rig's min_approvals unless the rig
+// allows the chatops role (see refinery.ApproverRole).
+func chatOpsIdentity(token, user string) string {
+	user = strings.Trim(chatOpsUserChars.ReplaceAllString(user, "_"), "_")
+	if user == "" {
+		user = "unknown"
+	}
+	return "chatops:" + token + "/" + user
+}
+
+// ChatOpsRequest is the JSON request body for /api/chatops. Slack-style
+// form posts (text, user_name, token) are accepted too.
+type ChatOpsRequest struct {
+	Command string `json:"command"` // e.g. "retry greenplace gp-mr-abc"
+	User    string `json:"user"`    // Who asked, as the chat client says; only the token vouches for it
+}
+
+// ChatOpsResponse is the response from /api/chatops. Text is what a chat
+// client shows; response_type keeps Slack replies private to the caller.
+type ChatOpsResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+	OK           bool   `json:"ok"`
+	Output       string `json:"output,omitempty"`
+}
+
+// chatOp is a parsed chatops command.
+type chatOp struct {
+	verb  string
+	scope string
+	args  []string // gt arguments
+}
+
+// parseChatOp maps a chatops command onto the gt command it runs.
+func parseChatOp(text, user string) (*chatOp, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, errors.New("no command")
+	}
+	verb, rest := strings.ToLower(fields[0]), fields[1:]
+	want := func(min, max int) error {
+		if len(rest) < min || len(rest) > max {
+			return fmt.Errorf("wrong number of arguments to %s", verb)
+		}
+		for _, a := range rest[:min] {
+			if !chatOpsArg.MatchString(a) {
+				return fmt.Errorf("invalid argument %q", a)
+			}
+		}
+		return nil
+	}
+
+	op := &chatOp{verb: verb}
+	switch verb {
+	case "status":
+		if err := want(0, 1); err != nil {
+			return nil, err
+		}
+		op.scope, op.args = config.ChatOpsScopeStatus, []string{"status"}
+		if len(rest) == 1 {
+			if !chatOpsArg.MatchString(rest[0]) {
+				return nil, fmt.Errorf("invalid argument %q", rest[0])
+			}
+			op.args = []string{"mq", "list", rest[0]}
+		}
+	case "retry":
+		if err := want(2, 2); err != nil {
+			return nil, err
+		}
+		op.scope, op.args = config.ChatOpsScopeRetry, []string{"mq", "retry", rest[0], rest[1]}
+	case "approve":
+		if err := want(1, 1); err != nil {
+			return nil, err
+		}
+		op.scope, op.args = config.ChatOpsScopeApprove, []string{"mq", "approve", rest[0], "--comment=Approved by " + user + " via chat"}
+	case "pause":
+		if err := want(1, len(rest)); err != nil {
+			return nil, err
+		}
+		reason := "by " + user + " via chat"
+		if len(rest) > 1 {
+			reason = strings.Join(rest[1:], " ") + " (" + reason + ")"
+		}
+		op.scope, op.args = config.ChatOpsScopePause, []string{"mq", "pause", rest[0], "--reason=" + reason}
+	case "resume":
+		if err := want(1, 1); err != nil {
+			return nil, err
+		}
+		op.scope, op.args = config.ChatOpsScopePause, []string{"mq", "resume", rest[0]}
+	default:
+		return nil, fmt.Errorf("unknown command %q", verb)
+	}
+	return op, nil
+}
+
+// handleChatOps runs a constrained command for an authenticated chat bot or
+// slash command. Every request, allowed or not, is recorded in the town's
+// audit log.
+func (h *APIHandler) handleChatOps(w http.ResponseWriter, r *http.Request) {
+	started := time.Now()
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		h.sendChatOps(w, http.StatusNotFound, false, "Chatops is not configured", "")
+		return
+	}
+	cfg, err := config.LoadChatOpsConfig(config.ChatOpsConfigPath(townRoot))
+	if err != nil {
+		h.sendChatOps(w, http.StatusNotFound, false, "Chatops is not configured", "")
+		return
+	}
+
+	var req ChatOpsRequest
+	var token string
+	r.Body = http.MaxBytesReader(w, r.Body, maxChatOpsBody)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			h.sendChatOps(w, http.StatusBadRequest, false, "Invalid request body", "")
+			return
+		}
+		req = ChatOpsRequest{Command: r.PostForm.Get("text"), User: r.PostForm.Get("user_name")}
+		token = r.PostForm.Get("token")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendChatOps(w, http.StatusBadRequest, false, "Invalid request body", "")
+		return
+	}
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+	if req.User == "" {
+		req.User = "unknown"
+	}
+
+	rec := auditlog.Record{
+		Timestamp: started,
+		Actor:     "chatops",
+		User:      req.User,
+		Command:   "chatops",
+		Args:      strings.Fields(req.Command),
+		ExitCode:  1,
+	}
+	audit := func(status int, text, output string) {
+		switch {
+		case status != http.StatusOK && rec.Error == "":
+			rec.Error = text
+		case status == http.StatusOK && rec.Error == "":
+			rec.ExitCode = 0
+		}
+		rec.DurationMS = time.Since(started).Milliseconds()
+		if _, err := auditlog.Append(townRoot, rec); err != nil {
+			// An unaudited command must not look like it went through quietly
+			text += fmt.Sprintf(" (warning: audit log: %v)", err)
+		}
+		h.sendChatOps(w, status, rec.ExitCode == 0, text, output)
+	}
+
+	tok := authenticateChatOps(townRoot, cfg, token)
+	if tok == nil {
+		audit(http.StatusUnauthorized, "Unauthorized", "")
+		return
+	}
+	rec.Actor = "chatops:" + tok.Name
+	rec.User = chatOpsIdentity(tok.Name, req.User)
+
+	op, err := parseChatOp(req.Command, req.User)
+	if err != nil {
+		rec.Error = err.Error()
+		audit(http.StatusBadRequest, err.Error()+"\n"+chatOpsUsage, "")
+		return
+	}
+	rec.Command = "chatops " + op.verb
+	if !tok.Allows(op.scope) {
+		audit(http.StatusForbidden, fmt.Sprintf("Token %s may not %s (needs scope %s)", tok.Name, op.verb, op.scope), "")
+		return
+	}
+
+	// Review commands record who ran them, so they run as the chatops
+	// identity rather than the dashboard's
+	var env []string
+	if op.scope == config.ChatOpsScopeApprove {
+		env = []string{"GT_ROLE=" + rec.User, "BD_ACTOR=" + rec.User}
+	}
+	output, err := h.runGtCommandAs(r.Context(), DefaultCommandTimeout, env, op.args)
+	if err != nil {
+		rec.Error = err.Error()
+		audit(http.StatusOK, fmt.Sprintf("`gt %s` failed: %v", strings.Join(op.args, " "), err), output)
+		return
+	}
+	audit(http.StatusOK, fmt.Sprintf("`gt %s` done", strings.Join(op.args, " ")), output)
+}
+
+// authenticateChatOps returns the configured token matching token, or nil.
+func authenticateChatOps(townRoot string, cfg *config.ChatOpsConfig, token string) *config.ChatOpsToken {
+	if token == "" {
+		return nil
+	}
+	for i := range cfg.Tokens {
+		want, err := secrets.Resolve(townRoot, cfg.Tokens[i].Token)
+		if err != nil || want == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return &cfg.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// sendChatOps sends a chatops response.
+func (h *APIHandler) sendChatOps(w http.ResponseWriter, status int, ok bool, text, output string) {
+	resp := ChatOpsResponse{ResponseType: "ephemeral", Text: text, OK: ok, Output: output}
+	if output != "" {
+		resp.Text += "\n```\n" + strings.TrimSpace(output) + "\n```"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,146 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// chatOpsTown sets up a town with a chatops token "slack" (value
+// "tok-slack") scoped to status and retry, and a gt that echoes its args.
+func chatOpsTown(t *testing.T) (*APIHandler, string) {
+	t.Helper()
+	town := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(secrets.EnvKey, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+
+	for path, content := range map[string]string{
+		"mayor/town.json": `{"type": "town", "version": 1, "name": "test"}`,
+		"settings/chatops.json": `{"type": "chatops", "version": 1, "tokens": [
+			{"name": "slack", "token": "secret:chatops-slack", "scopes": ["status", "mr:retry"]}]}`,
+	} {
+		full := filepath.Join(town, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := secrets.Set(town, "chatops-slack", "tok-slack"); err != nil {
+		t.Fatal(err)
+	}
+
+	gt := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(gt, []byte("#!/bin/sh\necho \"ran: $*\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &APIHandler{gtPath: gt, workDir: town}, town
+}
+
+func postChatOps(t *testing.T, h *APIHandler, token, command string) (int, ChatOpsResponse) {
+	t.Helper()
+	body, _ := json.Marshal(ChatOpsRequest{Command: command, User: "alice"})
+	req := httptest.NewRequest(http.MethodPost, "/api/chatops", strings.NewReader(string(body)))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp ChatOpsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestChatOps(t *testing.T) {
+	h, town := chatOpsTown(t)
+
+	tests := []struct {
+		name       string
+		token      string
+		command    string
+		wantStatus int
+		wantText   string
+	}{
+		{"no token", "", "status", http.StatusUnauthorized, "Unauthorized"},
+		{"wrong token", "nope", "status", http.StatusUnauthorized, "Unauthorized"},
+		{"status", "tok-slack", "status", http.StatusOK, "ran: status"},
+		{"rig status", "tok-slack", "status greenplace", http.StatusOK, "ran: mq list greenplace"},
+		{"retry", "tok-slack", "retry greenplace gp-mr-1", http.StatusOK, "ran: mq retry greenplace gp-mr-1"},
+		{"out of scope", "tok-slack", "approve gp-mr-1", http.StatusForbidden, "needs scope mr:approve"},
+		{"unknown command", "tok-slack", "sling gp-1", http.StatusBadRequest, "unknown command"},
+		{"flag injection", "tok-slack", "retry --now gp-mr-1", http.StatusBadRequest, "invalid argument"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := postChatOps(t, h, tt.token, tt.command)
+			if status != tt.wantStatus || !strings.Contains(resp.Text, tt.wantText) {
+				t.Errorf("chatops %q = %d %q, want %d containing %q", tt.command, status, resp.Text, tt.wantStatus, tt.wantText)
+			}
+		})
+	}
+
+	records, err := auditlog.ReadAll(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(tests) {
+		t.Fatalf("audit log has %d records, want %d", len(records), len(tests))
+	}
+	if r := records[0]; r.Actor != "chatops" || r.User != "alice" || r.ExitCode == 0 {
+		t.Errorf("unauthorized request audited as %+v", r)
+	}
+	if r := records[4]; r.Actor != "chatops:slack" || r.User != "chatops:slack/alice" || r.Command != "chatops retry" || r.ExitCode != 0 {
+		t.Errorf("retry audited as %+v", r)
+	}
+}
+
+func TestChatOpsApproveIdentity(t *testing.T) {
+	h, town := chatOpsTown(t)
+	if err := os.WriteFile(filepath.Join(town, "settings", "chatops.json"), []byte(`{"type": "chatops", "version": 1, "tokens": [
+		{"name": "slack", "token": "secret:chatops-slack", "scopes": ["mr:approve"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(h.gtPath, []byte("#!/bin/sh\necho \"as $GT_ROLE: $1 $2\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(ChatOpsRequest{Command: "approve gp-mr-1", User: "mallory\nmayor/"})
+	req := httptest.NewRequest(http.MethodPost, "/api/chatops", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer tok-slack")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), "as chatops:slack/mallory_mayor: mq approve") {
+		t.Errorf("approve ran as %s", w.Body.String())
+	}
+}
+
+func TestChatOpsSlackForm(t *testing.T) {
+	h, _ := chatOpsTown(t)
+	form := url.Values{"token": {"tok-slack"}, "text": {"status"}, "user_name": {"bob"}, "command": {"/gt"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/chatops", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "ran: status") {
+		t.Errorf("slash command = %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestChatOpsNotConfigured(t *testing.T) {
+	h := &APIHandler{gtPath: "false", workDir: t.TempDir()}
+	if status, _ := postChatOps(t, h, "tok", "status"); status != http.StatusNotFound {
+		t.Errorf("unconfigured chatops = %d, want 404", status)
+	}
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxAttempts is how many dispatches an event is retried on before it is
+// dropped, so a dead endpoint doesn't hold its queue forever.
+const maxAttempts = 10
+
+// Cursor is how far through the events log an endpoint has got.
+type Cursor struct {
+	Offset    int64     `json:"offset"`
+	LastEvent string    `json:"last_event,omitempty"` // Timestamp of the last event handled
+	Failures  int       `json:"failures,omitempty"`   // Attempts at the event after Offset
+	LastError string    `json:"last_error,omitempty"`
+	LastSent  time.Time `json:"last_sent,omitempty"`
+}
+
+// Delivery is what one dispatch did for an endpoint.
+type Delivery struct {
+	Endpoint string `json:"endpoint"`
+	Sent     int    `json:"sent"`
+	Dropped  int    `json:"dropped"`
+	Error    string `json:"error,omitempty"`
+}
+
+// statePath returns where endpoints' cursors are kept.
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "webhooks.json")
+}
+
+// Dispatch delivers the events logged since the last dispatch to each
+// enabled endpoint, in order. A new endpoint starts at the end of the log
+// rather than replaying history. An event an endpoint rejects, or that
+// fails maxAttempts times, is dropped; other failures stop that endpoint
+// until the next dispatch, which retries from the failed event.
+func Dispatch(townRoot string) ([]Delivery, error) {
+	cfg, err := config.LoadOrCreateWebhooksConfig(config.WebhooksConfigPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Endpoints) == 0 {
+		return nil, nil
+	}
+	cursors, err := LoadCursors(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	logPath := events.Path(townRoot)
+	var end int64
+	if info, err := os.Stat(logPath); err == nil {
+		end = info.Size()
+	}
+
+	var deliveries []Delivery
+	for _, epCfg := range cfg.Endpoints {
+		if epCfg.Disabled {
+			continue
+		}
+		d := Delivery{Endpoint: epCfg.Name}
+		cur := cursors[epCfg.Name]
+		if cur == nil {
+			cursors[epCfg.Name] = &Cursor{Offset: end}
+			continue
+		}
+		ep, err := Compile(townRoot, epCfg)
+		if err != nil {
+			d.Error = err.Error()
+			deliveries = append(deliveries, d)
+			continue
+		}
+		// A pruned log is read again from the top: skip what was handled
+		pruned := end < cur.Offset
+		offset, err := events.Since(logPath, cur.Offset, ep.filter, func(ev events.Event, next int64) error {
+			if pruned && ev.Timestamp <= cur.LastEvent {
+				return nil
+			}
+			if sendErr := ep.Send(ev); sendErr == nil {
+				d.Sent++
+				cur.LastSent = time.Now().UTC()
+			} else {
+				cur.Failures++
+				cur.LastError = sendErr.Error()
+				d.Error = sendErr.Error()
+				if !errors.Is(sendErr, ErrRejected) && cur.Failures < maxAttempts {
+					return sendErr
+				}
+				d.Dropped++
+			}
+			cur.Offset, cur.Failures, cur.LastEvent = next, 0, ev.Timestamp
+			return nil
+		})
+		if err == nil {
+			cur.Offset = offset // Past events the filter skipped too
+		} else if d.Error == "" {
+			d.Error = err.Error()
+		}
+		if d.Sent > 0 || d.Dropped > 0 || d.Error != "" {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	if err := saveCursors(townRoot, cursors); err != nil {
+		return deliveries, err
+	}
+	return deliveries, nil
+}
+
+// LoadCursors reads the endpoints' cursors, by endpoint name.
+func LoadCursors(townRoot string) (map[string]*Cursor, error) {
+	cursors := map[string]*Cursor{}
+	data, err := os.ReadFile(statePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cursors, nil
+		}
+		return nil, fmt.Errorf("reading webhook state: %w", err)
+	}
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, fmt.Errorf("reading webhook state %s: %w", statePath(townRoot), err)
+	}
+	return cursors, nil
+}
+
+func saveCursors(townRoot string, cursors map[string]*Cursor) error {
+	if err := os.MkdirAll(filepath.Dir(statePath(townRoot)), 0755); err != nil {
+		return fmt.Errorf("writing webhook state: %w", err)
+	}
+	if err := util.AtomicWriteJSON(statePath(townRoot), cursors); err != nil {
+		return fmt.Errorf("writing webhook state: %w", err)
+	}
+	return nil
+}
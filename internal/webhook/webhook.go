@@ -0,0 +1,205 @@
+// Package webhook posts town events to outbound webhooks.
+//
+// Endpoints are defined in settings/webhooks.json. Each selects events with
+// an events.Filter and renders its payload with a Go template over the
+// event JSON, so one event stream can feed PagerDuty, Teams, or an internal
+// API without a dedicated integration for each. The daemon's webhooks task
+// delivers new events (see Dispatch).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// requestTimeout bounds each delivery.
+const requestTimeout = 15 * time.Second
+
+// ErrRejected wraps a delivery the endpoint refused outright (a 4xx other
+// than 408 or 429): retrying the same payload won't help.
+var ErrRejected = errors.New("rejected")
+
+// Endpoint is a configured webhook, ready to render and send events.
+type Endpoint struct {
+	config.WebhookEndpoint
+
+	townRoot string
+	filter   events.Filter
+	tmpl     *template.Template
+}
+
+// Compile parses an endpoint's filter and template.
+func Compile(townRoot string, cfg config.WebhookEndpoint) (*Endpoint, error) {
+	filter, err := events.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %s: %w", cfg.Name, err)
+	}
+	e := &Endpoint{WebhookEndpoint: cfg, townRoot: townRoot, filter: filter}
+
+	text := cfg.Template
+	if cfg.TemplateFile != "" {
+		path := cfg.TemplateFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, "settings", path)
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the town's own config
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: reading template: %w", cfg.Name, err)
+		}
+		text = string(data)
+	}
+	if text != "" {
+		e.tmpl, err = template.New(cfg.Name).Funcs(e.funcs()).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", cfg.Name, err)
+		}
+	}
+	return e, nil
+}
+
+// Matches reports whether the endpoint takes ev.
+func (e *Endpoint) Matches(ev events.Event) bool {
+	return e.filter.Match(ev)
+}
+
+// funcs are the functions available to templates.
+func (e *Endpoint) funcs() template.FuncMap {
+	return template.FuncMap{
+		// json encodes a value, quoting strings: {"text": {{.actor | json}}}
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		// default returns def if v is empty: {{.payload.rig | default "town"}}
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		// secret reads a town secret: {{secret "pagerduty-key" | json}}
+		"secret": func(name string) (string, error) {
+			return secrets.Get(e.townRoot, name)
+		},
+	}
+}
+
+// contentType returns the payload's content type.
+func (e *Endpoint) contentType() string {
+	if e.ContentType == "" {
+		return "application/json"
+	}
+	return e.ContentType
+}
+
+// Render returns the payload for ev: the template executed over the event
+// JSON, or the event JSON itself if there is no template. A JSON payload
+// that doesn't parse is an error, so template mistakes surface here rather
+// than as a rejected request.
+func (e *Endpoint) Render(ev events.Event) ([]byte, error) {
+	raw, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	if e.tmpl == nil {
+		return raw, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := e.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("webhook %s: %w", e.Name, err)
+	}
+	if strings.Contains(e.contentType(), "json") && !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook %s: template produced invalid JSON: %s", e.Name, strings.TrimSpace(buf.String()))
+	}
+	return buf.Bytes(), nil
+}
+
+// Send renders ev and delivers it. A refused delivery wraps ErrRejected.
+func (e *Endpoint) Send(ev events.Event) error {
+	body, err := e.Render(ev)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return e.Post(body)
+}
+
+// Post delivers a rendered payload.
+func (e *Endpoint) Post(body []byte) error {
+	url, err := secrets.Resolve(e.townRoot, e.URL)
+	if err != nil {
+		return fmt.Errorf("webhook %s: url: %w", e.Name, err)
+	}
+	method := e.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", e.Name, redactURL(err, url))
+	}
+	req.Header.Set("Content-Type", e.contentType())
+	req.Header.Set("User-Agent", "gastown-webhook")
+	for key, value := range e.Headers {
+		if value, err = secrets.Resolve(e.townRoot, value); err != nil {
+			return fmt.Errorf("webhook %s: header %s: %w", e.Name, key, err)
+		}
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", e.Name, redactURL(err, url))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("webhook %s: %s: %s", e.Name, resp.Status, strings.TrimSpace(string(msg)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return err
+}
+
+// redactURL strips the endpoint URL from a request error. Webhook URLs
+// often carry their credential (a Slack or Teams hook, a PagerDuty key), and
+// the error ends up in the daemon log and .runtime/webhooks.json, so only
+// the endpoint's name may identify it there.
+func redactURL(err error, url string) error {
+	var ue *neturl.Error
+	if errors.As(err, &ue) {
+		err = fmt.Errorf("%s: %w", ue.Op, ue.Err)
+	}
+	if url == "" || !strings.Contains(err.Error(), url) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), url, "<url>"))
+}
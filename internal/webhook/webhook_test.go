@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestRender(t *testing.T) {
+	ev := events.Event{Timestamp: "2026-01-02T03:04:05Z", Type: "merge_failed", Actor: "greenplace/refinery",
+		Payload: map[string]interface{}{"mr": "gp-mr-1", "reason": `conflict in "main.go"`}}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{"no template", "", `{"ts":"2026-01-02T03:04:05Z","source":"","type":"merge_failed","actor":"greenplace/refinery","payload":{"mr":"gp-mr-1","reason":"conflict in \"main.go\""},"visibility":""}`, false},
+		{"fields", `{"text": {{printf "%s: %s" .type .payload.reason | json}}}`, `{"text": "merge_failed: conflict in \"main.go\""}`, false},
+		{"default", `{"rig": {{.payload.rig | default "town" | json}}, "who": {{.actor | upper | json}}}`, `{"rig": "town", "who": "GREENPLACE/REFINERY"}`, false},
+		{"invalid JSON", `{"text": {{.type}}}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, err := Compile(t.TempDir(), config.WebhookEndpoint{Name: "test", URL: "http://x", Template: tt.template})
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			got, err := ep.Render(ev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Render = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileTemplateFile(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "settings", "teams.tmpl"), []byte(`{"title": {{.type | json}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ep, err := Compile(town, config.WebhookEndpoint{Name: "teams", URL: "http://x", TemplateFile: "teams.tmpl"})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if got, err := ep.Render(events.Event{Type: "merged"}); err != nil || string(got) != `{"title": "merged"}` {
+		t.Errorf("Render = %s, %v", got, err)
+	}
+
+	if _, err := Compile(town, config.WebhookEndpoint{Name: "bad", URL: "http://x", Template: "{{.type"}); err == nil {
+		t.Error("Compile accepted a malformed template")
+	}
+}
+
+// receiver is a test endpoint answering with the queued statuses (then 200),
+// recording the bodies it accepts.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusOK {
+		r.bodies = append(r.bodies, string(body))
+	}
+	w.WriteHeader(status)
+}
+
+func TestDispatch(t *testing.T) {
+	town := t.TempDir()
+	recv := &receiver{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	writeJSON(t, config.WebhooksConfigPath(town), config.WebhooksConfig{Type: "webhooks", Version: 1,
+		Endpoints: []config.WebhookEndpoint{{Name: "ops", URL: srv.URL, Filter: []string{"type=merge_failed"},
+			Template: `{"mr": {{.payload.mr | json}}}`}}})
+	logEvents(t, town, events.Event{Type: "merge_failed", Payload: map[string]interface{}{"mr": "old"}})
+
+	dispatch := func() []Delivery {
+		t.Helper()
+		d, err := Dispatch(town)
+		if err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+		return d
+	}
+
+	// A new endpoint starts at the end of the log
+	if d := dispatch(); len(d) != 0 || len(recv.bodies) != 0 {
+		t.Fatalf("first dispatch = %+v, sent %v", d, recv.bodies)
+	}
+
+	logEvents(t, town,
+		events.Event{Type: "merged", Payload: map[string]interface{}{"mr": "skip"}},
+		events.Event{Type: "merge_failed", Payload: map[string]interface{}{"mr": "gp-mr-1"}},
+		events.Event{Type: "merge_failed", Payload: map[string]interface{}{"mr": "gp-mr-2"}})
+	recv.statuses = []int{http.StatusServiceUnavailable}
+	if d := dispatch(); len(d) != 1 || d[0].Sent != 0 || d[0].Error == "" {
+		t.Fatalf("failing dispatch = %+v", d)
+	}
+	if d := dispatch(); len(d) != 1 || d[0].Sent != 2 {
+		t.Fatalf("retry dispatch = %+v", d)
+	}
+	if got := strings.Join(recv.bodies, " "); got != `{"mr": "gp-mr-1"} {"mr": "gp-mr-2"}` {
+		t.Errorf("sent %s", got)
+	}
+
+	// A rejected event is dropped, not retried
+	logEvents(t, town, events.Event{Type: "merge_failed", Payload: map[string]interface{}{"mr": "gp-mr-3"}})
+	recv.statuses = []int{http.StatusBadRequest}
+	if d := dispatch(); len(d) != 1 || d[0].Dropped != 1 {
+		t.Fatalf("rejected dispatch = %+v", d)
+	}
+	if d := dispatch(); len(d) != 0 {
+		t.Errorf("dispatch after drop = %+v", d)
+	}
+}
+
+func TestPostRedactsURL(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL + "/services/T000/B000/s3cr3t"
+	srv.Close() // Nothing listening: the request itself fails
+
+	ep, err := Compile(t.TempDir(), config.WebhookEndpoint{Name: "ops", URL: url})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	err = ep.Post([]byte(`{}`))
+	if err == nil {
+		t.Fatal("Post to a closed server succeeded")
+	}
+	if strings.Contains(err.Error(), "s3cr3t") || !strings.HasPrefix(err.Error(), "webhook ops: ") {
+		t.Errorf("Post error = %q, want the endpoint name and no URL", err)
+	}
+}
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func logEvents(t *testing.T, town string, evts ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(events.Path(town), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package wip enforces per-rig work-in-progress limits: how many open merge
+// requests, and how many hooked or in-progress issues, one worker may have
+// at once. Limits come from the "wip" section of a rig's
+// settings/config.json.
+package wip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Limits are a rig's WIP limits. A nil *Limits allows anything.
+type Limits struct {
+	maxOpenMRs    int
+	maxInProgress int
+}
+
+// New builds Limits from config. Returns nil (no limits) if cfg is nil.
+func New(cfg *config.WIPLimitConfig) (*Limits, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.MaxOpenMRsPerWorker < 0 {
+		return nil, fmt.Errorf("invalid wip max_open_mrs_per_worker %d", cfg.MaxOpenMRsPerWorker)
+	}
+	if cfg.MaxInProgressPerWorker < 0 {
+		return nil, fmt.Errorf("invalid wip max_in_progress_per_worker %d", cfg.MaxInProgressPerWorker)
+	}
+	return &Limits{maxOpenMRs: cfg.MaxOpenMRsPerWorker, maxInProgress: cfg.MaxInProgressPerWorker}, nil
+}
+
+// Load reads a rig's WIP limits from its settings/config.json. A missing
+// settings file or wip section yields nil (no limits).
+func Load(rigPath string) (*Limits, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return New(settings.WIP)
+}
+
+// MaxOpenMRs returns the per-worker open MR limit (0 = none).
+func (l *Limits) MaxOpenMRs() int {
+	if l == nil {
+		return 0
+	}
+	return l.maxOpenMRs
+}
+
+// MaxInProgress returns the per-worker in-progress issue limit (0 = none).
+func (l *Limits) MaxInProgress() int {
+	if l == nil {
+		return 0
+	}
+	return l.maxInProgress
+}
+
+// ExceededError reports a worker at one of its WIP limits.
+type ExceededError struct {
+	Worker string
+	Kind   string // "open MRs" or "issues in progress"
+	Count  int
+	Limit  int
+	Items  []string // IDs of the work counted against the limit
+}
+
+func (e *ExceededError) Error() string {
+	msg := fmt.Sprintf("WIP limit reached: %s has %d %s (limit %d)", e.Worker, e.Count, e.Kind, e.Limit)
+	if len(e.Items) > 0 {
+		msg += ": " + strings.Join(e.Items, ", ")
+	}
+	return msg
+}
+
+// CheckOpenMRs returns an *ExceededError if worker may not open another MR
+// given the rig's MRs. Closed MRs don't count, nor do repeats.
+func (l *Limits) CheckOpenMRs(worker string, mrs []*beads.Issue) error {
+	if l.MaxOpenMRs() == 0 {
+		return nil
+	}
+	var open []string
+	seen := make(map[string]bool)
+	for _, mr := range mrs {
+		if mr.Status == "closed" || seen[mr.ID] || MROwner(mr) != worker {
+			continue
+		}
+		seen[mr.ID] = true
+		open = append(open, mr.ID)
+	}
+	if len(open) < l.maxOpenMRs {
+		return nil
+	}
+	return &ExceededError{Worker: worker, Kind: "open MRs", Count: len(open), Limit: l.maxOpenMRs, Items: open}
+}
+
+// CheckInProgress returns an *ExceededError if worker may not take another
+// issue given the issues assigned to it. Only hooked and in-progress issues
+// count, each once; skip (the issue being assigned, if it is already one of
+// them) is left out.
+func (l *Limits) CheckInProgress(worker string, assigned []*beads.Issue, skip string) error {
+	if l.MaxInProgress() == 0 {
+		return nil
+	}
+	var active []string
+	seen := make(map[string]bool)
+	for _, issue := range assigned {
+		if issue.ID == skip || seen[issue.ID] {
+			continue
+		}
+		if issue.Status == beads.StatusHooked || issue.Status == "in_progress" {
+			seen[issue.ID] = true
+			active = append(active, issue.ID)
+		}
+	}
+	if len(active) < l.maxInProgress {
+		return nil
+	}
+	return &ExceededError{Worker: worker, Kind: "issues in progress", Count: len(active), Limit: l.maxInProgress, Items: active}
+}
+
+// MROwner returns who an MR counts against: its worker (a polecat name) if
+// recorded, otherwise whoever created the bead (e.g., "greenplace/crew/max").
+func MROwner(mr *beads.Issue) string {
+	if fields := beads.ParseMRFields(mr); fields != nil && fields.Worker != "" {
+		return fields.Worker
+	}
+	return mr.CreatedBy
+}
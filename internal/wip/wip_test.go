@@ -0,0 +1,117 @@
+package wip
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	if l, err := New(nil); err != nil || l != nil {
+		t.Errorf("New(nil) = %v, %v; want nil (no limits)", l, err)
+	}
+	if _, err := New(&config.WIPLimitConfig{MaxOpenMRsPerWorker: -1}); err == nil {
+		t.Error("New with a negative MR limit should fail")
+	}
+	if _, err := New(&config.WIPLimitConfig{MaxInProgressPerWorker: -1}); err == nil {
+		t.Error("New with a negative in-progress limit should fail")
+	}
+
+	var none *Limits
+	if none.MaxOpenMRs() != 0 || none.MaxInProgress() != 0 {
+		t.Error("nil limits should be unlimited")
+	}
+	if err := none.CheckOpenMRs("toast", []*beads.Issue{{ID: "gp-mr-1", Status: "open"}}); err != nil {
+		t.Errorf("nil limits refused an MR: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	if l, err := Load(rigPath); err != nil || l != nil {
+		t.Errorf("Load without settings = %v, %v; want nil", l, err)
+	}
+
+	settings := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settings, []byte(`{"type":"rig-settings","version":1,"wip":{"max_open_mrs_per_worker":2,"max_in_progress_per_worker":1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Load(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.MaxOpenMRs() != 2 || l.MaxInProgress() != 1 {
+		t.Errorf("limits = %d MRs, %d in progress; want 2, 1", l.MaxOpenMRs(), l.MaxInProgress())
+	}
+}
+
+func mr(id, status, worker, createdBy string) *beads.Issue {
+	issue := &beads.Issue{ID: id, Status: status, Type: "merge-request", CreatedBy: createdBy}
+	if worker != "" {
+		issue.Description = beads.FormatMRFields(&beads.MRFields{Branch: "polecat/" + worker, Worker: worker})
+	}
+	return issue
+}
+
+func TestCheckOpenMRs(t *testing.T) {
+	l := &Limits{maxOpenMRs: 2}
+	mrs := []*beads.Issue{
+		mr("gp-mr-1", "open", "toast", "greenplace/polecats/toast"),
+		mr("gp-mr-2", "in_progress", "toast", "greenplace/polecats/toast"),
+		mr("gp-mr-3", "closed", "toast", "greenplace/polecats/toast"),
+		mr("gp-mr-4", "open", "nux", "greenplace/polecats/nux"),
+		mr("gp-mr-5", "open", "", "greenplace/crew/max"),
+	}
+
+	err := l.CheckOpenMRs("toast", mrs)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("CheckOpenMRs(toast) = %v, want ExceededError", err)
+	}
+	if exceeded.Count != 2 || exceeded.Limit != 2 || strings.Join(exceeded.Items, ",") != "gp-mr-1,gp-mr-2" {
+		t.Errorf("exceeded = %+v, want gp-mr-1 and gp-mr-2 counted", exceeded)
+	}
+	if !strings.Contains(err.Error(), "toast has 2 open MRs (limit 2)") {
+		t.Errorf("error = %q", err)
+	}
+
+	if err := l.CheckOpenMRs("nux", mrs); err != nil {
+		t.Errorf("nux is under the limit: %v", err)
+	}
+	// MRs without a worker count against their creator
+	if err := (&Limits{maxOpenMRs: 1}).CheckOpenMRs("greenplace/crew/max", mrs); err == nil {
+		t.Error("crew MR not counted against its creator")
+	}
+	// An MR listed twice (open and in_progress queries overlap) counts once
+	if err := l.CheckOpenMRs("nux", append(mrs, mrs[3])); err != nil {
+		t.Errorf("repeated MR counted twice: %v", err)
+	}
+}
+
+func TestCheckInProgress(t *testing.T) {
+	l := &Limits{maxInProgress: 2}
+	assigned := []*beads.Issue{
+		{ID: "gp-1", Status: beads.StatusHooked},
+		{ID: "gp-2", Status: "in_progress"},
+		{ID: "gp-3", Status: "open"},
+	}
+
+	if err := l.CheckInProgress("greenplace/crew/max", assigned, "gp-9"); err == nil {
+		t.Error("CheckInProgress allowed a third issue")
+	}
+	// Re-slinging work the agent already has doesn't add to it
+	if err := l.CheckInProgress("greenplace/crew/max", assigned, "gp-2"); err != nil {
+		t.Errorf("re-sling counted the slung issue: %v", err)
+	}
+	if err := (&Limits{}).CheckInProgress("greenplace/crew/max", assigned, "gp-9"); err != nil {
+		t.Errorf("zero limit should be unlimited: %v", err)
+	}
+}
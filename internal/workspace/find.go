@@ -84,16 +84,52 @@ func FindOrError(startDir string) (string, error) {
 	return root, nil
 }
 
-// FindFromCwd locates the town root from the current working directory.
+// FindFromCwd locates the town root for the current command: the town named
+// by GT_TOWN_CONTEXT, else the one containing the current working directory.
+// Returns "" if neither applies. The registry's current context is not
+// consulted; see FindFromCwdOrContext.
 func FindFromCwd() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("getting current directory: %w", err)
 	}
-	return Find(cwd)
+	return findWithContext(cwd, false)
 }
 
-// FindFromCwdOrError is like FindFromCwd but returns an error if not found.
+// findWithContext resolves the town for cwd: GT_TOWN_CONTEXT, then the town
+// containing cwd, then (only if fallback is set) the current context.
+func findWithContext(cwd string, fallback bool) (string, error) {
+	if root, explicit, err := contextTown(false); explicit {
+		return root, err
+	}
+	root, err := Find(cwd)
+	if err != nil || root != "" || !fallback {
+		return root, err
+	}
+	root, _, err = contextTown(true)
+	return root, err
+}
+
+// FindFromCwdOrContext is like FindFromCwdOrError but falls back to the
+// registry's current context when the current directory is not in a town.
+// Use it only for commands a person runs by hand: hooks and agent commands
+// rely on "not in a workspace" to do nothing outside a town.
+func FindFromCwdOrContext() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+	root, err := findWithContext(cwd, true)
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", ErrNotFound
+	}
+	return root, nil
+}
+
+// FindFromCwdOrError is like FindFromCwd but returns ErrNotFound if no town applies.
 // If getcwd fails (e.g., worktree deleted), falls back to GT_TOWN_ROOT env var.
 func FindFromCwdOrError() (string, error) {
 	cwd, err := os.Getwd()
@@ -107,7 +143,14 @@ func FindFromCwdOrError() (string, error) {
 		}
 		return "", fmt.Errorf("getting current directory: %w", err)
 	}
-	return FindOrError(cwd)
+	root, err := findWithContext(cwd, false)
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", ErrNotFound
+	}
+	return root, nil
 }
 
 // FindFromCwdWithFallback is like FindFromCwdOrError but returns (townRoot, cwd, error).
@@ -127,10 +170,13 @@ func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
 		return "", "", fmt.Errorf("getting current directory: %w", err)
 	}
 
-	townRoot, err = FindOrError(cwd)
+	townRoot, err = findWithContext(cwd, false)
 	if err != nil {
 		return "", "", err
 	}
+	if townRoot == "" {
+		return "", "", ErrNotFound
+	}
 	return townRoot, cwd, nil
 }
 
@@ -0,0 +1,53 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// RelParts returns the components of path relative to townRoot, e.g.
+// ["gastown", "crew", "max"]. It is the portable way to ask "where in the
+// town am I": separators are normalized (so it works on Windows), and when
+// one side reaches the town through a symlink (macOS /var → /private/var, a
+// symlinked ~/gt) both are resolved before comparing. Returns an empty
+// slice for the town root itself, and an error if path is outside the town.
+func RelParts(townRoot, path string) ([]string, error) {
+	rel, err := relInside(townRoot, path)
+	if err != nil {
+		realRoot, rootErr := filepath.EvalSymlinks(townRoot)
+		realPath, pathErr := filepath.EvalSymlinks(path)
+		if rootErr != nil || pathErr != nil {
+			return nil, err
+		}
+		if rel, err = relInside(realRoot, realPath); err != nil {
+			return nil, err
+		}
+	}
+	if rel == "." {
+		return []string{}, nil
+	}
+	return strings.Split(filepath.ToSlash(rel), "/"), nil
+}
+
+// relInside returns path relative to root, or an error if it escapes root.
+// filepath.Rel already compares case-insensitively on Windows, and fails
+// for paths on different volumes.
+func relInside(root, path string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside %s: %w", path, root, err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not inside %s", path, root)
+	}
+	return rel, nil
+}
@@ -0,0 +1,50 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRelParts(t *testing.T) {
+	root := realPath(t, t.TempDir())
+	crewDir := filepath.Join(root, "gastown", "crew", "max")
+	if err := os.MkdirAll(crewDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := RelParts(root, crewDir)
+	if err != nil || strings.Join(parts, "/") != "gastown/crew/max" {
+		t.Errorf("RelParts(crew) = %v, %v", parts, err)
+	}
+	if parts, err := RelParts(root, root); err != nil || len(parts) != 0 {
+		t.Errorf("RelParts(root) = %v, %v; want empty", parts, err)
+	}
+	if _, err := RelParts(crewDir, root); err == nil {
+		t.Error("RelParts outside the town succeeded, want error")
+	}
+	// "..foo" is a valid directory name, not an escape
+	dotted := filepath.Join(root, "..rig")
+	if parts, err := RelParts(root, dotted); err != nil || parts[0] != "..rig" {
+		t.Errorf("RelParts(..rig) = %v, %v", parts, err)
+	}
+}
+
+func TestRelPartsThroughSymlink(t *testing.T) {
+	root := realPath(t, t.TempDir())
+	town := filepath.Join(root, "town")
+	if err := os.MkdirAll(filepath.Join(town, "gastown", "polecats", "Toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "gt")
+	if err := os.Symlink(town, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	// Town root known by its real path, cwd reached through the symlink
+	parts, err := RelParts(town, filepath.Join(link, "gastown", "polecats", "Toast"))
+	if err != nil || strings.Join(parts, "/") != "gastown/polecats/Toast" {
+		t.Errorf("RelParts via symlink = %v, %v", parts, err)
+	}
+}
@@ -0,0 +1,182 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// EnvTownContext selects a registered town by name for one command,
+// overriding both the current directory and the current context.
+const EnvTownContext = "GT_TOWN_CONTEXT"
+
+// ErrUnknownTown indicates a town name that is not in the registry.
+var ErrUnknownTown = errors.New("unknown town")
+
+// Registry is the per-machine list of towns and the current context, so
+// commands run outside any town still know which one to act on (like
+// kubeconfig contexts). It lives in ~/.config/gastown/towns.json.
+type Registry struct {
+	Current string               `json:"current,omitempty"`
+	Towns   map[string]TownEntry `json:"towns"`
+}
+
+// TownEntry is one registered town.
+type TownEntry struct {
+	Path    string    `json:"path"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// RegistryPath returns the path of the town registry file.
+func RegistryPath() string {
+	return filepath.Join(state.ConfigDir(), "towns.json")
+}
+
+// LoadRegistry reads the town registry. A missing file is an empty registry.
+func LoadRegistry() (*Registry, error) {
+	reg := &Registry{Towns: make(map[string]TownEntry)}
+	data, err := os.ReadFile(RegistryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", RegistryPath(), err)
+	}
+	if reg.Towns == nil {
+		reg.Towns = make(map[string]TownEntry)
+	}
+	return reg, nil
+}
+
+// Save writes the registry atomically.
+func (r *Registry) Save() error {
+	path := RegistryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Names returns the registered town names, sorted.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.Towns))
+	for name := range r.Towns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Add registers a town root under name. The path must be a workspace.
+func (r *Registry) Add(name, path string) error {
+	if name == "" {
+		return fmt.Errorf("town name is required")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	if ok, _ := IsWorkspace(absPath); !ok {
+		return fmt.Errorf("%s is not a Gas Town workspace", absPath)
+	}
+	if existing, ok := r.Towns[name]; ok && existing.Path != absPath {
+		return fmt.Errorf("town %q is already registered at %s", name, existing.Path)
+	}
+	if other := r.NameFor(absPath); other != "" && other != name {
+		return fmt.Errorf("%s is already registered as %q", absPath, other)
+	}
+	if _, ok := r.Towns[name]; !ok {
+		r.Towns[name] = TownEntry{Path: absPath, AddedAt: time.Now().UTC()}
+	}
+	return nil
+}
+
+// Remove unregisters a town, clearing the current context if it was current.
+func (r *Registry) Remove(name string) error {
+	if _, ok := r.Towns[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTown, name)
+	}
+	delete(r.Towns, name)
+	if r.Current == name {
+		r.Current = ""
+	}
+	return nil
+}
+
+// Use makes name the current context.
+func (r *Registry) Use(name string) error {
+	if _, ok := r.Towns[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTown, name)
+	}
+	r.Current = name
+	return nil
+}
+
+// Resolve returns the root of the named town.
+func (r *Registry) Resolve(name string) (string, error) {
+	entry, ok := r.Towns[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s (see 'gt town list')", ErrUnknownTown, name)
+	}
+	return entry.Path, nil
+}
+
+// NameFor returns the name a town root is registered under, or "".
+func (r *Registry) NameFor(townRoot string) string {
+	for name, entry := range r.Towns {
+		if entry.Path == townRoot {
+			return name
+		}
+	}
+	return ""
+}
+
+// contextTown returns the town root selected by GT_TOWN_CONTEXT, or by the
+// registry's current context when fallback is set. explicit reports whether
+// GT_TOWN_CONTEXT chose it, in which case it overrides the current directory.
+func contextTown(fallback bool) (root string, explicit bool, err error) {
+	name := os.Getenv(EnvTownContext)
+	explicit = name != ""
+	if !explicit && !fallback {
+		return "", false, nil
+	}
+
+	reg, err := LoadRegistry()
+	if err != nil {
+		if explicit {
+			return "", true, err
+		}
+		return "", false, nil // a broken registry must not break cwd-based commands
+	}
+	if !explicit {
+		name = reg.Current
+		if name == "" {
+			return "", false, nil
+		}
+	}
+	root, err = reg.Resolve(name)
+	if err != nil {
+		return "", explicit, err
+	}
+	if ok, _ := IsWorkspace(root); !ok {
+		return "", explicit, fmt.Errorf("town %q at %s is no longer a Gas Town workspace", name, root)
+	}
+	return root, explicit, nil
+}
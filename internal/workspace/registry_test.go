@@ -0,0 +1,114 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeTown creates a minimal town root under dir.
+func makeTown(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PrimaryMarker), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return dir
+}
+
+func TestRegistry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := realPath(t, t.TempDir())
+	work := makeTown(t, filepath.Join(root, "work"))
+	personal := makeTown(t, filepath.Join(root, "personal"))
+
+	reg, err := LoadRegistry()
+	if err != nil || len(reg.Towns) != 0 {
+		t.Fatalf("LoadRegistry without file = %v, %v", reg, err)
+	}
+	if err := reg.Add("work", work); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := reg.Add("personal", personal); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := reg.Add("again", work); err == nil {
+		t.Error("Add of an already registered path succeeded, want error")
+	}
+	if err := reg.Add("bogus", root); err == nil {
+		t.Error("Add of a non-workspace succeeded, want error")
+	}
+	if err := reg.Use("nope"); !errors.Is(err, ErrUnknownTown) {
+		t.Errorf("Use(nope) = %v, want ErrUnknownTown", err)
+	}
+	if err := reg.Use("personal"); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+	if err := reg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reg, err = LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+	if reg.Current != "personal" || len(reg.Names()) != 2 || reg.NameFor(work) != "work" {
+		t.Errorf("reloaded registry = %+v", reg)
+	}
+	if err := reg.Remove("personal"); err != nil || reg.Current != "" {
+		t.Errorf("Remove current = %v, current %q", err, reg.Current)
+	}
+}
+
+func TestFindFromCwdUsesTownContext(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvTownContext, "")
+	root := realPath(t, t.TempDir())
+	work := makeTown(t, filepath.Join(root, "work"))
+	personal := makeTown(t, filepath.Join(root, "personal"))
+	outside := filepath.Join(root, "elsewhere")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, _ := LoadRegistry()
+	if err := reg.Add("work", work); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add("personal", personal); err != nil {
+		t.Fatal(err)
+	}
+
+	// No current context: outside a town finds nothing
+	if got, err := findWithContext(outside, true); err != nil || got != "" {
+		t.Errorf("without context = %q, %v; want empty", got, err)
+	}
+
+	reg.Current = "personal"
+	if err := reg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := findWithContext(outside, true); got != personal {
+		t.Errorf("outside a town = %q, want current context %q", got, personal)
+	}
+	// Without fallback (FindFromCwd) the current context is ignored
+	if got, err := findWithContext(outside, false); err != nil || got != "" {
+		t.Errorf("outside a town without fallback = %q, %v; want empty", got, err)
+	}
+	// Inside a town, the directory wins over the current context
+	if got, _ := findWithContext(filepath.Join(work, "mayor"), true); got != work {
+		t.Errorf("inside work = %q, want %q", got, work)
+	}
+	// GT_TOWN_CONTEXT overrides the directory
+	t.Setenv(EnvTownContext, "personal")
+	if got, _ := findWithContext(filepath.Join(work, "mayor"), false); got != personal {
+		t.Errorf("with %s = %q, want %q", EnvTownContext, got, personal)
+	}
+	t.Setenv(EnvTownContext, "missing")
+	if _, err := findWithContext(work, false); !errors.Is(err, ErrUnknownTown) {
+		t.Errorf("unknown %s err = %v, want ErrUnknownTown", EnvTownContext, err)
+	}
+}